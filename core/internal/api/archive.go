@@ -0,0 +1,176 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// handleArchive handles GET /api/v1/archive, listing archived prompt versions
+// filtered by ?id=, ?since= and ?until=
+func (s *APIServer) handleArchive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	filter, err := archiveFilter(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	filter.ID = r.URL.Query().Get("id")
+	s.writeArchive(w, r, filter)
+}
+
+// handleArchiveWithID handles /api/v1/archive/{id}: GET lists the prompt's
+// archived versions, or returns one with ?version=, and POST
+// /api/v1/archive/{id}/restore makes a version current again
+func (s *APIServer) handleArchiveWithID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/archive/")
+	id, action, _ := strings.Cut(path, "/")
+	if id == "" {
+		s.writeError(w, errors.ValidationError("Prompt ID is required"))
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == "GET":
+		if version := r.URL.Query().Get("version"); version != "" {
+			s.handleGetArchivedVersion(w, r, id, version)
+			return
+		}
+		filter, err := archiveFilter(r)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		filter.ID = id
+		s.writeArchive(w, r, filter)
+	case action == "restore" && r.Method == "POST":
+		s.handleRestoreArchivedVersion(w, r, id)
+	case action == "" || action == "restore":
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+	default:
+		s.writeError(w, errors.NotFoundError("Endpoint "+r.URL.Path))
+	}
+}
+
+// writeArchive responds with the archived versions matching filter
+func (s *APIServer) writeArchive(w http.ResponseWriter, r *http.Request, filter service.ArchiveFilter) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	versions, err := s.service.ListArchive(filter)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeResponse(w, redactResult(redactor, versions), fmt.Sprintf("%d archived versions", len(versions)), http.StatusOK)
+}
+
+// handleGetArchivedVersion handles GET /api/v1/archive/{id}?version=
+func (s *APIServer) handleGetArchivedVersion(w http.ResponseWriter, r *http.Request, id, version string) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	archived, err := s.service.GetArchivedVersion(id, version)
+	if err != nil {
+		s.writeArchiveError(w, err, id, version)
+		return
+	}
+	s.writeResponse(w, redactResult(redactor, archived), "", http.StatusOK)
+}
+
+// handleRestoreArchivedVersion handles POST /api/v1/archive/{id}/restore. The
+// version comes from a JSON body {"version": "1.0.2"} or from ?version=.
+func (s *APIServer) handleRestoreArchivedVersion(w http.ResponseWriter, r *http.Request, id string) {
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		var body struct {
+			Version string `json:"version"`
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.writeError(w, errors.ValidationError("Failed to read request body"))
+			return
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &body); err != nil {
+				s.writeError(w, errors.ValidationError("Invalid JSON in request body"))
+				return
+			}
+		}
+		version = body.Version
+	}
+	if version == "" {
+		s.writeError(w, errors.ValidationError("version is required"))
+		return
+	}
+
+	restored, err := s.service.RestoreArchivedVersion(id, version)
+	if err != nil {
+		s.writeArchiveError(w, err, id, version)
+		return
+	}
+	s.writeResponse(w, restored, fmt.Sprintf("Restored %s v%s as v%s", id, version, restored.Version), http.StatusOK)
+}
+
+// writeArchiveError reports a missing version as not found and anything else
+// as an internal error
+func (s *APIServer) writeArchiveError(w http.ResponseWriter, err error, id, version string) {
+	if strings.Contains(err.Error(), "not found") {
+		s.writeError(w, errors.NotFoundError(fmt.Sprintf("Archived version %s v%s", id, version)))
+	} else {
+		s.writeError(w, err)
+	}
+}
+
+// archiveFilter reads the ?since= and ?until= bounds of an archive listing.
+// Each takes a date or an RFC 3339 timestamp; a date for until includes that
+// whole day.
+func archiveFilter(r *http.Request) (service.ArchiveFilter, error) {
+	var filter service.ArchiveFilter
+	if value := r.URL.Query().Get("since"); value != "" {
+		since, _, err := parseArchiveTime(value)
+		if err != nil {
+			return filter, errors.ValidationError("Invalid since: " + err.Error())
+		}
+		filter.Since = since
+	}
+	if value := r.URL.Query().Get("until"); value != "" {
+		until, dateOnly, err := parseArchiveTime(value)
+		if err != nil {
+			return filter, errors.ValidationError("Invalid until: " + err.Error())
+		}
+		if dateOnly {
+			until = until.AddDate(0, 0, 1)
+		}
+		filter.Until = until
+	}
+	return filter, nil
+}
+
+// parseArchiveTime accepts a date (2006-01-02) or a full RFC 3339 timestamp,
+// reporting which it was given
+func parseArchiveTime(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("%q is not a date (YYYY-MM-DD) or RFC 3339 timestamp", value)
+}
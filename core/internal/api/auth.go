@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/errors"
+)
+
+// AuditFileName is the JSON-lines audit log kept in .pocket-prompt/
+const AuditFileName = "audit.log"
+
+// requiredScope maps a request to the scope it needs: admin for the audit log,
+// read for safe methods and read-only commands, write for anything that
+// changes the library
+func requiredScope(r *http.Request) string {
+	if r.URL.Path == "/api/v1/audit" {
+		return config.ScopeAdmin
+	}
+	// Installing a pack clones and copies files on the host
+	if strings.HasPrefix(r.URL.Path, "/api/v1/packs/") && r.Method != "GET" && r.Method != "HEAD" && r.Method != "OPTIONS" {
+		return config.ScopeAdmin
+	}
+	if name, ok := strings.CutPrefix(r.URL.Path, "/api/v1/commands/"); ok && commands.IsReadOnly(name) {
+		return config.ScopeRead
+	}
+	// Rendering with posted context changes nothing
+	if strings.HasPrefix(r.URL.Path, "/api/v1/prompts/") && strings.HasSuffix(r.URL.Path, "/render") {
+		return config.ScopeRead
+	}
+	switch r.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return config.ScopeRead
+	default:
+		return config.ScopeWrite
+	}
+}
+
+// requestKey extracts the API key from the Authorization or X-API-Key header.
+// Feed readers cannot send headers, so the feed also takes ?key=.
+func requestKey(r *http.Request) string {
+	if r.URL.Path == "/feed.xml" {
+		if key := r.URL.Query().Get("key"); key != "" {
+			return strings.TrimSpace(key)
+		}
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+// authMiddleware enforces API keys once any are configured, rejects changes
+// to a read-only library and records changes and rejected requests in the
+// audit log
+func (s *APIServer) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cfg := s.keys.Current()
+		keyName := ""
+
+		if len(cfg.Server.APIKeys) > 0 {
+			plaintext := requestKey(r)
+			if plaintext == "" {
+				s.audit.record(r, "", http.StatusUnauthorized)
+				s.writeError(w, errors.NewAppError(errors.ErrCodeUnauthorized, "API key required"))
+				return
+			}
+			key := cfg.LookupAPIKey(plaintext)
+			if key == nil {
+				s.audit.record(r, "", http.StatusUnauthorized)
+				s.writeError(w, errors.NewAppError(errors.ErrCodeUnauthorized, "Invalid API key"))
+				return
+			}
+			if required := requiredScope(r); !key.Allows(required) {
+				s.audit.record(r, key.Name, http.StatusForbidden)
+				s.writeError(w, errors.NewAppError(errors.ErrCodePermissionDenied, "API key "+key.Name+" lacks the "+required+" scope"))
+				return
+			}
+			keyName = key.Name
+		}
+
+		if s.service.ReadOnly() && requiredScope(r) == config.ScopeWrite {
+			s.writeError(w, errors.NewAppError(errors.ErrCodePermissionDenied, "Library is read-only"))
+			return
+		}
+
+		if r.Method == "GET" || r.Method == "HEAD" {
+			next(w, r)
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r)
+		s.audit.record(r, keyName, recorder.status)
+	}
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// auditLog appends one JSON object per line to .pocket-prompt/audit.log
+type auditLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	Key    string    `json:"key,omitempty"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	Status int       `json:"status"`
+	Remote string    `json:"remote"`
+}
+
+func newAuditLog(baseDir string) *auditLog {
+	return &auditLog{path: filepath.Join(baseDir, ".pocket-prompt", AuditFileName)}
+}
+
+func (a *auditLog) record(r *http.Request, key string, status int) {
+	data, err := json.Marshal(auditEntry{
+		Time:   time.Now(),
+		Key:    key,
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Status: status,
+		Remote: r.RemoteAddr,
+	})
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		log.Printf("Warning: failed to write audit log: %v", err)
+		return
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Printf("Warning: failed to write audit log: %v", err)
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// recent returns up to limit of the newest audit entries, newest first
+func (a *auditLog) recent(limit int) ([]auditEntry, error) {
+	a.mu.Lock()
+	data, err := os.ReadFile(a.path)
+	a.mu.Unlock()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []auditEntry{}, nil
+		}
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	entries := make([]auditEntry, 0, limit)
+	for i := len(lines) - 1; i >= 0 && len(entries) < limit; i-- {
+		var entry auditEntry
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// handleAudit handles GET /api/v1/audit
+func (s *APIServer) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	limit := 100
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	entries, err := s.audit.recent(limit)
+	if err != nil {
+		s.writeError(w, errors.InternalError("Failed to read audit log"))
+		return
+	}
+	s.writeResponse(w, entries, "", http.StatusOK)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/api/v1/prompts", config.ScopeRead},
+		{"HEAD", "/api/v1/prompts/review", config.ScopeRead},
+		{"POST", "/api/v1/prompts", config.ScopeWrite},
+		{"PUT", "/api/v1/prompts/review", config.ScopeWrite},
+		{"DELETE", "/api/v1/prompts/review", config.ScopeWrite},
+		{"POST", "/api/v1/prompts/review/render", config.ScopeRead},
+		{"POST", "/api/v1/commands/list", config.ScopeRead},
+		{"POST", "/api/v1/commands/create", config.ScopeWrite},
+		{"GET", "/api/v1/packs/team", config.ScopeRead},
+		{"POST", "/api/v1/packs/team", config.ScopeAdmin},
+		{"GET", "/api/v1/audit", config.ScopeAdmin},
+	}
+	for _, tt := range tests {
+		if got := requiredScope(httptest.NewRequest(tt.method, tt.path, nil)); got != tt.want {
+			t.Errorf("requiredScope(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAuthMiddlewareScopes(t *testing.T) {
+	s, _ := newTestServer(t, &models.Prompt{ID: "review", Name: "Review", Content: "Review this"})
+
+	// Without keys the API is open
+	if rec := serve(s, "GET", "/api/v1/prompts/review", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("open GET = %d: %s", rec.Code, rec.Body)
+	}
+
+	cfg := s.keys.Current()
+	keys := map[string]string{}
+	for _, scope := range []string{config.ScopeRead, config.ScopeWrite, config.ScopeAdmin} {
+		key, err := cfg.AddAPIKey(scope+"-key", scope)
+		if err != nil {
+			t.Fatalf("AddAPIKey: %v", err)
+		}
+		keys[scope] = key
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	bearer := func(key string) http.Header {
+		return http.Header{"Authorization": {"Bearer " + key}}
+	}
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		header       http.Header
+		want         int
+	}{
+		{"no key", "GET", "/api/v1/prompts/review", "", nil, http.StatusUnauthorized},
+		{"unknown key", "GET", "/api/v1/prompts/review", "", bearer("pkt_unknown"), http.StatusUnauthorized},
+		{"read reads", "GET", "/api/v1/prompts/review", "", bearer(keys[config.ScopeRead]), http.StatusOK},
+		{"read key header", "GET", "/api/v1/prompts/review", "", http.Header{"X-Api-Key": {keys[config.ScopeRead]}}, http.StatusOK},
+		{"read renders", "POST", "/api/v1/prompts/review/render", `{"context":"code"}`, bearer(keys[config.ScopeRead]), http.StatusOK},
+		{"read cannot write", "POST", "/api/v1/prompts", `{"id":"new","name":"New","content":"x"}`, bearer(keys[config.ScopeRead]), http.StatusForbidden},
+		{"write writes", "POST", "/api/v1/prompts", `{"id":"new","name":"New","content":"x"}`, bearer(keys[config.ScopeWrite]), http.StatusCreated},
+		{"write cannot audit", "GET", "/api/v1/audit", "", bearer(keys[config.ScopeWrite]), http.StatusForbidden},
+		{"admin audits", "GET", "/api/v1/audit", "", bearer(keys[config.ScopeAdmin]), http.StatusOK},
+	}
+	for _, tt := range tests {
+		if rec := serve(s, tt.method, tt.path, tt.body, tt.header); rec.Code != tt.want {
+			t.Errorf("%s: %s %s = %d, want %d: %s", tt.name, tt.method, tt.path, rec.Code, tt.want, rec.Body)
+		}
+	}
+}
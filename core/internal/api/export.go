@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// handleExport handles GET /export, which streams the prompts in ?scope= as a
+// zip of their files or, with ?format=ndjson, as one JSON prompt per line. It
+// lets a library be backed up or moved without a shell on the host.
+func (s *APIServer) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "ndjson" {
+		s.writeError(w, errors.ValidationError(fmt.Sprintf("invalid format %q (use zip or ndjson)", format)))
+		return
+	}
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	prompts, templates, err := s.service.ExportSelection(r.URL.Query().Get("scope"))
+	if err != nil {
+		s.writeError(w, errors.ValidationError(err.Error()))
+		return
+	}
+	if redactor != nil {
+		prompts = redactor.Prompts(prompts)
+		for i, template := range templates {
+			templates[i] = redactor.Template(template)
+		}
+	}
+
+	name := "pocket-prompt-" + time.Now().Format("2006-01-02")
+	if format == "ndjson" {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.ndjson"`, name))
+		if r.Method == "HEAD" {
+			return
+		}
+		if err := service.WriteExportNDJSON(w, prompts); err != nil {
+			log.Printf("Failed to write export: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, name))
+	if r.Method == "HEAD" {
+		return
+	}
+	if err := service.WriteExportZip(w, prompts, templates); err != nil {
+		log.Printf("Failed to write export: %v", err)
+	}
+}
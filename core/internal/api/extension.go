@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// Quick search sizes: results returned by default and at most
+const (
+	defaultQuickSearchResults = 10
+	maxQuickSearchResults     = 50
+)
+
+// quickResult is a search result small enough to send on every keystroke
+type quickResult struct {
+	ID    string   `json:"id"`
+	Title string   `json:"title"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// handleQuickSearch handles GET /api/v1/quick-search, a search for browser
+// extension popups that returns only IDs, titles and tags. ?q= is the query
+// (empty lists the first prompts) and ?limit= caps the results.
+func (s *APIServer) handleQuickSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	limit := defaultQuickSearchResults
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			s.writeError(w, errors.ValidationError("limit must be a positive number"))
+			return
+		}
+		limit = min(n, maxQuickSearchResults)
+	}
+
+	prompts, err := s.service.SearchPrompts(r.URL.Query().Get("q"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if len(prompts) > limit {
+		prompts = prompts[:limit]
+	}
+
+	results := make([]quickResult, 0, len(prompts))
+	for _, p := range prompts {
+		results = append(results, quickResult{ID: p.ID, Title: p.Title(), Tags: p.Tags})
+	}
+	s.writeResponse(w, results, "", http.StatusOK)
+}
+
+// handleCapture handles POST /capture, saving text selected in a browser as a
+// new prompt. The JSON body holds the text and, optionally, a title, tags and
+// the URL and title of the page it came from, which are kept as metadata.
+func (s *APIServer) handleCapture(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	var req struct {
+		Text      string   `json:"text"`
+		Title     string   `json:"title"`
+		Tags      []string `json:"tags"`
+		URL       string   `json:"url"`
+		PageTitle string   `json:"page_title"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		s.writeError(w, errors.ValidationError("Invalid JSON in request body"))
+		return
+	}
+	if req.Text == "" {
+		s.writeError(w, errors.ValidationError("Prompt text is required"))
+		return
+	}
+
+	prompt, err := s.service.CapturePrompt(service.Capture{
+		Text:      req.Text,
+		Title:     req.Title,
+		Tags:      req.Tags,
+		SourceURL: req.URL,
+		PageTitle: req.PageTitle,
+	})
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeResponse(w, map[string]interface{}{
+		"id":    prompt.ID,
+		"title": prompt.Name,
+		"tags":  prompt.Tags,
+	}, fmt.Sprintf("Created prompt %s", prompt.ID), http.StatusCreated)
+}
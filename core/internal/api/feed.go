@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Feed sizes: entries returned by default and at most
+const (
+	defaultFeedEntries = 50
+	maxFeedEntries     = 200
+)
+
+// atomFeed is an Atom 1.0 document (RFC 4287)
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Link       atomLink       `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// handleFeed handles GET /feed.xml, an Atom feed of the most recently created
+// or updated prompts. ?tag= narrows it to one tag and ?limit= sets its length.
+func (s *APIServer) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	limit := defaultFeedEntries
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = min(l, maxFeedEntries)
+	}
+
+	prompts, err := s.service.ListPrompts()
+	if err != nil {
+		s.writeError(w, errors.InternalError("Failed to list prompts"))
+		return
+	}
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		var tagged []*models.Prompt
+		for _, p := range prompts {
+			if slices.Contains(p.Tags, tag) {
+				tagged = append(tagged, p)
+			}
+		}
+		prompts = tagged
+	}
+	sort.SliceStable(prompts, func(i, j int) bool {
+		return lastChanged(prompts[i]).After(lastChanged(prompts[j]))
+	})
+	if len(prompts) > limit {
+		prompts = prompts[:limit]
+	}
+
+	baseURL := shortcutBaseURL(r, s.port)
+	feed := atomFeed{
+		Title:   "Pocket Prompt: recent changes",
+		ID:      baseURL + "/feed.xml",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Link:    []atomLink{{Rel: "self", Href: baseURL + "/feed.xml"}},
+		Author:  atomAuthor{Name: "Pocket Prompt"},
+	}
+	if len(prompts) > 0 {
+		feed.Updated = lastChanged(prompts[0]).UTC().Format(time.RFC3339)
+	}
+
+	webURL := strings.TrimRight(s.keys.Current().Server.WebURL, "/")
+	for _, p := range prompts {
+		link := baseURL + "/api/v1/prompts/" + url.PathEscape(p.ID)
+		if webURL != "" {
+			link = webURL + "/prompts/" + url.PathEscape(p.ID)
+		}
+		entry := atomEntry{
+			Title:   p.Title(),
+			ID:      "urn:pocket-prompt:" + p.ID,
+			Updated: lastChanged(p).UTC().Format(time.RFC3339),
+			Link:    atomLink{Rel: "alternate", Href: link},
+			Summary: feedSummary(p),
+		}
+		if !p.CreatedAt.IsZero() {
+			entry.Published = p.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		for _, tag := range p.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		s.writeError(w, errors.InternalError("Failed to build feed"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	w.Write(data)
+}
+
+// lastChanged is when a prompt was last updated, or created if it never was
+func lastChanged(p *models.Prompt) time.Time {
+	if p.UpdatedAt.IsZero() {
+		return p.CreatedAt
+	}
+	return p.UpdatedAt
+}
+
+// feedSummary says whether the prompt is new or changed, followed by its
+// description or, failing that, its first line
+func feedSummary(p *models.Prompt) string {
+	change := "New prompt"
+	if p.UpdatedAt.After(p.CreatedAt.Add(time.Second)) {
+		change = "Updated to v" + p.Version
+	}
+	detail := p.Summary
+	if detail == "" {
+		detail, _, _ = strings.Cut(strings.TrimSpace(p.Content), "\n")
+	}
+	if detail == "" {
+		return change
+	}
+	return change + ": " + detail
+}
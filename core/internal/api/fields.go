@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// List content modes for ?include=: each prompt's whole content, an excerpt
+// of it instead, or neither
+const (
+	includeContent = "content"
+	includeSummary = "summary"
+	includeNone    = "none"
+)
+
+// excerptKey is the key of the excerpt ?include=summary adds to each prompt,
+// styled like the prompt's own keys
+const excerptKey = "Excerpt"
+
+// listInclude reads ?include=, which is "" when the request leaves it out
+func listInclude(r *http.Request) (string, error) {
+	include := r.URL.Query().Get("include")
+	switch include {
+	case "", includeContent, includeSummary, includeNone:
+		return include, nil
+	}
+	return "", errors.ValidationError(fmt.Sprintf("invalid include %q (use summary, content or none)", include))
+}
+
+// loadListContent loads the content of a page of prompts for ?include=content
+// and ?include=summary. Prompts listed from the metadata cache otherwise
+// come without it.
+func (s *APIServer) loadListContent(r *http.Request, data interface{}) (interface{}, error) {
+	include, err := listInclude(r)
+	if err != nil {
+		return nil, err
+	}
+	prompts, ok := data.([]*models.Prompt)
+	if !ok || (include != includeContent && include != includeSummary) {
+		return data, nil
+	}
+	full, err := s.service.WithContent(prompts)
+	if err != nil {
+		return nil, errors.InternalError("Failed to load prompt content")
+	}
+	return full, nil
+}
+
+// selectFields shapes each prompt in a list as the request asks. With
+// ?include=summary the content is replaced by an Excerpt of its first
+// ?sentences= sentences (2 by default), and ?include=none leaves both out.
+// ?fields=, such as "id,name,tags", then narrows each prompt to the fields
+// named, so clients on slow connections skip content they never show. Names
+// match the prompt's keys ignoring case and underscores, so created_at
+// selects CreatedAt. Data other than prompts, and every list without these
+// parameters, is returned as it is.
+func selectFields(r *http.Request, data interface{}) (interface{}, error) {
+	query := r.URL.Query()
+	value := query.Get("fields")
+	include, err := listInclude(r)
+	if err != nil {
+		return nil, err
+	}
+	prompts, ok := data.([]*models.Prompt)
+	if (value == "" && (include == "" || include == includeContent)) || !ok {
+		return data, nil
+	}
+
+	sentences := service.DefaultExcerptSentences
+	if n := query.Get("sentences"); n != "" {
+		sentences, err = strconv.Atoi(n)
+		if err != nil || sentences < 1 || sentences > service.MaxExcerptSentences {
+			return nil, errors.ValidationError(fmt.Sprintf("sentences must be a number from 1 to %d", service.MaxExcerptSentences))
+		}
+	}
+
+	known := promptFieldKeys()
+	if include == includeSummary {
+		known[fieldName(excerptKey)] = excerptKey
+	}
+	var keys []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		key, ok := known[fieldName(name)]
+		if !ok {
+			return nil, errors.ValidationError(fmt.Sprintf("unknown field %q in fields (use %s)", name, strings.Join(promptFieldNames(known), ", ")))
+		}
+		keys = append(keys, key)
+	}
+
+	selected := make([]map[string]json.RawMessage, len(prompts))
+	for i, prompt := range prompts {
+		encoded, err := json.Marshal(prompt)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &all); err != nil {
+			return nil, err
+		}
+		if include == includeSummary {
+			excerpt, err := json.Marshal(service.Excerpt(prompt.Content, sentences))
+			if err != nil {
+				return nil, err
+			}
+			all[excerptKey] = excerpt
+		}
+		if include == includeSummary || include == includeNone {
+			delete(all, "Content")
+		}
+		if len(keys) == 0 {
+			selected[i] = all
+			continue
+		}
+		fields := make(map[string]json.RawMessage, len(keys))
+		for _, key := range keys {
+			if v, ok := all[key]; ok {
+				fields[key] = v
+			}
+		}
+		selected[i] = fields
+	}
+	return selected, nil
+}
+
+// promptFieldKeys maps the normalized name of each key a prompt has in JSON
+// to the key itself
+func promptFieldKeys() map[string]string {
+	keys := make(map[string]string)
+	t := reflect.TypeOf(models.Prompt{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+		keys[fieldName(key)] = key
+	}
+	return keys
+}
+
+// promptFieldNames lists the keys ?fields= can select, for error messages
+func promptFieldNames(known map[string]string) []string {
+	names := make([]string, 0, len(known))
+	for _, key := range known {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fieldName normalizes a field name for matching
+func fieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
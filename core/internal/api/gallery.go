@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+)
+
+// handleGallery handles GET /gallery, a standalone HTML page showing every
+// template with its slots, an example rendered from sample values and how
+// many prompts are built from it. ?redact=true applies the redaction rules.
+func (s *APIServer) handleGallery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	page, err := s.service.TemplateGalleryHTML(redactor)
+	if err != nil {
+		s.writeError(w, errors.InternalError("Failed to build template gallery"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(page))
+}
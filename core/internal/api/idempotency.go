@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+)
+
+// IdempotencyHeader names a create request, so that a client retrying it
+// after a dropped connection gets the first response back instead of a
+// second prompt
+const IdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyTTL is how long a response is replayed for its key
+const IdempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKey bounds the length of an Idempotency-Key header
+const maxIdempotencyKey = 255
+
+// maxIdempotentBody bounds the body read to hash a request, the same 1 MiB
+// the API reads at most of other request bodies
+const maxIdempotentBody = 1 << 20
+
+// maxIdempotencyEntries bounds how many responses are kept. When it is
+// reached the response closest to expiring is forgotten first.
+const maxIdempotencyEntries = 10000
+
+// idempotencyStore remembers responses to POST requests sent with an
+// Idempotency-Key. Entries live in memory, so a restarted server forgets them.
+type idempotencyStore struct {
+	mu      sync.Mutex
+	entries map[[32]byte]*idempotentResponse
+}
+
+// idempotentResponse is a stored response, or a request still running when
+// done is false
+type idempotentResponse struct {
+	request     [32]byte // Hash of the method, path and body
+	done        bool
+	status      int
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+func newIdempotencyStore() *idempotencyStore {
+	return &idempotencyStore{entries: make(map[[32]byte]*idempotentResponse)}
+}
+
+// begin returns the entry for key, or reserves it for a new request and
+// returns nil. Expired entries are dropped first, and the oldest response
+// when the store is full.
+func (st *idempotencyStore) begin(key, request [32]byte, now time.Time) *idempotentResponse {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for k, entry := range st.entries {
+		if entry.done && now.After(entry.expires) {
+			delete(st.entries, k)
+		}
+	}
+	if entry, ok := st.entries[key]; ok {
+		copied := *entry
+		return &copied
+	}
+	if len(st.entries) >= maxIdempotencyEntries {
+		st.evictOldest()
+	}
+	st.entries[key] = &idempotentResponse{request: request}
+	return nil
+}
+
+// evictOldest forgets the stored response closest to expiring. Requests
+// still running are kept, so their keys are never reused while they run.
+func (st *idempotencyStore) evictOldest() {
+	var oldest [32]byte
+	var found *idempotentResponse
+	for k, entry := range st.entries {
+		if entry.done && (found == nil || entry.expires.Before(found.expires)) {
+			oldest, found = k, entry
+		}
+	}
+	if found != nil {
+		delete(st.entries, oldest)
+	}
+}
+
+// finish stores the response for key, or forgets key when the response
+// should not be replayed
+func (st *idempotencyStore) finish(key [32]byte, response *idempotentResponse) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if response == nil {
+		delete(st.entries, key)
+		return
+	}
+	st.entries[key] = response
+}
+
+// idempotent makes POST requests to next that carry an Idempotency-Key
+// header safe to retry. The first response with a status below 500 is kept
+// for IdempotencyTTL and replayed, with an Idempotent-Replayed header, to
+// later requests with the same key and API key. A key reused for a different
+// request, or sent again while the first is still running, is refused.
+func (s *APIServer) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(IdempotencyHeader)
+		if r.Method != "POST" || idempotencyKey == "" {
+			next(w, r)
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKey {
+			s.writeError(w, errors.ValidationError("Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+		if err != nil {
+			s.writeError(w, errors.ValidationError("Failed to read request body"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the caller's API key, which is hashed rather than kept
+		key := sha256.Sum256([]byte(requestKey(r) + "\x00" + idempotencyKey))
+		request := sha256.Sum256(append([]byte(r.Method+" "+r.URL.Path+"\x00"), body...))
+
+		if entry := s.idempotency.begin(key, request, time.Now()); entry != nil {
+			switch {
+			case entry.request != request:
+				s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidInput, "Idempotency-Key was already used for a different request"))
+			case !entry.done:
+				s.writeError(w, errors.NewAppError(errors.ErrCodeAlreadyExists, "A request with this Idempotency-Key is still in progress"))
+			default:
+				w.Header().Set("Content-Type", entry.contentType)
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(entry.status)
+				w.Write(entry.body)
+			}
+			return
+		}
+
+		capture := &responseCapture{ResponseWriter: w, status: http.StatusOK}
+		var stored *idempotentResponse
+		// A panicking handler releases the key so the request can be retried
+		defer func() { s.idempotency.finish(key, stored) }()
+		next(capture, r)
+
+		// Server errors may succeed when retried, so they are not kept
+		if capture.status < 500 {
+			stored = &idempotentResponse{
+				request:     request,
+				done:        true,
+				status:      capture.status,
+				contentType: w.Header().Get("Content-Type"),
+				body:        capture.body.Bytes(),
+				expires:     time.Now().Add(IdempotencyTTL),
+			}
+		}
+	}
+}
+
+// responseCapture copies the status and body written by a handler
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(data []byte) (int, error) {
+	c.body.Write(data)
+	return c.ResponseWriter.Write(data)
+}
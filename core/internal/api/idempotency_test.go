@@ -0,0 +1,111 @@
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotentCreateReplays(t *testing.T) {
+	s, svc := newTestServer(t)
+	header := http.Header{IdempotencyHeader: {"create-review"}}
+	body := `{"id": "review", "name": "Review", "content": "Review this"}`
+
+	first := serve(s, "POST", "/api/v1/prompts", body, header)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("create: status %d: %s", first.Code, first.Body)
+	}
+	retry := serve(s, "POST", "/api/v1/prompts", body, header)
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry: status %d, replayed %q, body %s; want the first response replayed",
+			retry.Code, retry.Header().Get("Idempotent-Replayed"), retry.Body)
+	}
+	if prompts, err := svc.ListPrompts(); err != nil || len(prompts) != 1 {
+		t.Errorf("prompts after a retry = %d, %v; want one", len(prompts), err)
+	}
+
+	// The same key for another request is refused rather than replayed
+	other := serve(s, "POST", "/api/v1/prompts", `{"id": "other", "name": "Other", "content": "Other"}`, header)
+	if other.Code != http.StatusBadRequest || other.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("reused key: status %d: %s; want 400", other.Code, other.Body)
+	}
+	if _, err := svc.GetPrompt("other"); err == nil {
+		t.Error("a reused key created a second prompt")
+	}
+}
+
+func TestIdempotentInProgress(t *testing.T) {
+	s, _ := newTestServer(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/prompts", strings.NewReader(`{"id": "slow"}`))
+		req.Header.Set(IdempotencyHeader, "slow")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- request() }()
+	<-started
+	if rec := request(); rec.Code != http.StatusConflict {
+		t.Errorf("second request while the first runs: status %d: %s; want 409", rec.Code, rec.Body)
+	}
+	close(release)
+	if rec := <-done; rec.Code != http.StatusCreated {
+		t.Errorf("first request: status %d", rec.Code)
+	}
+	if rec := request(); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("request after the first finished: status %d, replayed %q", rec.Code, rec.Header().Get("Idempotent-Replayed"))
+	}
+}
+
+func TestIdempotentBodyLimit(t *testing.T) {
+	s, _ := newTestServer(t)
+	called := false
+	handler := s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	req := httptest.NewRequest("POST", "/api/v1/prompts", strings.NewReader(strings.Repeat("x", maxIdempotentBody+1)))
+	req.Header.Set(IdempotencyHeader, "huge")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusBadRequest || called {
+		t.Errorf("oversized body: status %d, handler called %v; want 400 before the handler", rec.Code, called)
+	}
+	if len(s.idempotency.entries) != 0 {
+		t.Errorf("an oversized body reserved its key")
+	}
+}
+
+func TestIdempotencyStoreBounded(t *testing.T) {
+	st := newIdempotencyStore()
+	now := time.Now()
+	key := func(i int) [32]byte { return sha256.Sum256([]byte(fmt.Sprint(i))) }
+	for i := 0; i < maxIdempotencyEntries; i++ {
+		st.begin(key(i), key(i), now)
+		st.finish(key(i), &idempotentResponse{request: key(i), done: true, expires: now.Add(time.Duration(i) * time.Second)})
+	}
+
+	if entry := st.begin(key(-1), key(-1), now); entry != nil {
+		t.Fatalf("begin for a new key = %+v, want it reserved", entry)
+	}
+	if len(st.entries) != maxIdempotencyEntries {
+		t.Errorf("entries = %d, want at most %d", len(st.entries), maxIdempotencyEntries)
+	}
+	if _, ok := st.entries[key(0)]; ok {
+		t.Error("the response closest to expiring was kept")
+	}
+	if _, ok := st.entries[key(1)]; !ok {
+		t.Error("a newer response was forgotten")
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/platform"
+)
+
+// UnixPrefix marks a listen address as a Unix domain socket path
+const UnixPrefix = "unix:"
+
+// portFallbackAttempts is how many ports after a taken one SetPortFallback
+// tries before giving up
+const portFallbackAttempts = 20
+
+// SetListen overrides the TCP port with an explicit listen address: either
+// "unix:/path/to/socket", a TCP "host:port", or TailscaleListen for the
+// tailnet address on the TCP port
+func (s *APIServer) SetListen(addr string) {
+	s.listen = addr
+}
+
+// Address returns where the server listens: the address given to SetListen,
+// or the TCP port
+func (s *APIServer) Address() string {
+	if s.listen != "" {
+		return s.listen
+	}
+	return fmt.Sprintf(":%d", s.port)
+}
+
+// SetPortFallback makes Start listen on the next free port when the
+// configured one is taken, rather than failing
+func (s *APIServer) SetPortFallback(enabled bool) {
+	s.portFallback = enabled
+}
+
+// OnListen registers fn to be called with the server's address once it is
+// listening, which can differ from Address after a port fallback
+func (s *APIServer) OnListen(fn func(addr string)) {
+	s.onListen = fn
+}
+
+// listener opens the configured address and returns it with a display form
+// for startup logs
+func (s *APIServer) listener() (net.Listener, string, error) {
+	if path, ok := strings.CutPrefix(s.listen, UnixPrefix); ok {
+		if path == "" {
+			return nil, "", fmt.Errorf("unix listen address requires a socket path")
+		}
+		if err := removeStaleSocket(path); err != nil {
+			return nil, "", err
+		}
+		lis, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, "", err
+		}
+		// The socket is the only access control when no API keys are set
+		if err := os.Chmod(path, 0600); err != nil {
+			lis.Close()
+			return nil, "", fmt.Errorf("failed to restrict socket permissions: %w", err)
+		}
+		return lis, UnixPrefix + path, nil
+	}
+
+	if s.listen == TailscaleListen {
+		ip, dnsName, err := tailnetAddress()
+		if err != nil {
+			return nil, "", err
+		}
+		s.listen = net.JoinHostPort(ip, strconv.Itoa(s.port))
+		s.tailnetName = dnsName
+	}
+
+	lis, err := net.Listen("tcp", s.Address())
+	if platform.IsAddrInUse(err) {
+		lis, err = s.listenAfterConflict()
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	host, port, _ := net.SplitHostPort(lis.Addr().String())
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	// Phones on the tailnet reach it by its MagicDNS name
+	if s.tailnetName != "" {
+		host = s.tailnetName
+	}
+	return lis, "http://" + net.JoinHostPort(host, port), nil
+}
+
+// listenAfterConflict handles a taken TCP port: it says what holds it and,
+// with port fallback, listens on the next free port instead
+func (s *APIServer) listenAfterConflict() (net.Listener, error) {
+	host, portStr, err := net.SplitHostPort(s.Address())
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in listen address %q", s.Address())
+	}
+	owner := portOwner(port)
+	if !s.portFallback {
+		return nil, fmt.Errorf("port %d is already in use%s; stop it, choose another port with --port, or pass --port-fallback to use the next free one", port, owner)
+	}
+
+	for next := port + 1; next <= port+portFallbackAttempts && next <= 65535; next++ {
+		lis, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(next)))
+		if err != nil {
+			continue
+		}
+		log.Printf("Port %d is already in use%s; listening on port %d instead", port, owner, next)
+		// Later addresses, such as those in the Shortcuts gallery, use the new port
+		if s.listen != "" {
+			s.listen = net.JoinHostPort(host, strconv.Itoa(next))
+		} else {
+			s.port = next
+		}
+		return lis, nil
+	}
+	return nil, fmt.Errorf("port %d is already in use%s, and so are the %d ports after it", port, owner, portFallbackAttempts)
+}
+
+// portOwner describes the process listening on port, such as " by pkt
+// (PID 4242)", or returns "" when lsof is unavailable or cannot tell
+func portOwner(port int) string {
+	out, err := exec.Command("lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-Fpc").Output()
+	if err != nil {
+		return ""
+	}
+	// Fields come one per line, each prefixed with its letter
+	var pid, command string
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case strings.HasPrefix(line, "p") && pid == "":
+			pid = line[1:]
+		case strings.HasPrefix(line, "c") && command == "":
+			command = line[1:]
+		}
+	}
+	if pid == "" {
+		return ""
+	}
+	if command == "" {
+		return fmt.Sprintf(" by PID %s", pid)
+	}
+	return fmt.Sprintf(" by %s (PID %s)", command, pid)
+}
+
+// removeStaleSocket deletes a socket left behind by a server that did not shut
+// down cleanly, refusing to touch anything that is not a socket or is still in use
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("another server is already listening on %s", path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// lockRequest is the body of POST and DELETE /api/v1/prompts/{id}/lock.
+// Owner defaults to the server's git user, which is rarely who is editing, so
+// clients should send it.
+type lockRequest struct {
+	Owner string `json:"owner"`
+	Note  string `json:"note"`
+	TTL   string `json:"ttl"` // Go duration such as "30m" or "2h"
+	Force bool   `json:"force"`
+}
+
+// handleLocks handles GET /api/v1/locks, listing the prompts locked for editing
+func (s *APIServer) handleLocks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	locks, err := s.service.ListLocks()
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeResponse(w, locks, fmt.Sprintf("%d locked prompts", len(locks)), http.StatusOK)
+}
+
+// handlePromptLock handles /api/v1/prompts/{id}/lock: GET returns the lock or
+// null, POST takes it and DELETE releases it
+func (s *APIServer) handlePromptLock(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method == "GET" {
+		lock, err := s.service.PromptLock(id)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeResponse(w, lock, "", http.StatusOK)
+		return
+	}
+	if r.Method != "POST" && r.Method != "DELETE" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	var req lockRequest
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, errors.ValidationError("Failed to read request body"))
+		return
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &req); err != nil {
+			s.writeError(w, errors.ValidationError("Invalid JSON in request body"))
+			return
+		}
+	}
+	if owner := r.URL.Query().Get("owner"); owner != "" {
+		req.Owner = owner
+	}
+	if r.URL.Query().Get("force") == "true" {
+		req.Force = true
+	}
+
+	if r.Method == "DELETE" {
+		if err := s.service.UnlockPrompt(id, req.Owner, req.Force); err != nil {
+			s.writeLockError(w, err, id)
+			return
+		}
+		s.writeResponse(w, nil, "Unlocked prompt: "+id, http.StatusOK)
+		return
+	}
+
+	opts := service.LockOptions{Owner: req.Owner, Note: req.Note, Force: req.Force}
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			s.writeError(w, errors.ValidationError("Invalid ttl: "+req.TTL))
+			return
+		}
+		opts.TTL = ttl
+	}
+	lock, err := s.service.LockPrompt(id, opts)
+	if err != nil {
+		s.writeLockError(w, err, id)
+		return
+	}
+	s.writeResponse(w, lock, fmt.Sprintf("Locked prompt: %s (by %s)", id, lock.Owner), http.StatusOK)
+}
+
+// writeLockError reports a lock held by someone else as a conflict and a
+// missing prompt or lock as not found
+func (s *APIServer) writeLockError(w http.ResponseWriter, err error, id string) {
+	var locked *service.LockedError
+	switch {
+	case stderrors.As(err, &locked):
+		s.writeError(w, errors.NewAppError(errors.ErrCodeAlreadyExists, err.Error()))
+	case strings.Contains(err.Error(), "not found"), strings.Contains(err.Error(), "not locked"):
+		s.writeError(w, errors.NewAppError(errors.ErrCodeNotFound, err.Error()))
+	default:
+		s.writeError(w, err)
+	}
+}
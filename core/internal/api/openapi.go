@@ -51,12 +51,14 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
 )
 
 // handleOpenAPI serves the OpenAPI documentation interface
 func (s *APIServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		s.writeError(w, nil)
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -103,7 +105,7 @@ func (s *APIServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
 // handleOpenAPISpec serves the OpenAPI JSON specification
 func (s *APIServer) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		s.writeError(w, nil)
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -155,6 +157,15 @@ func getOpenAPISpec() map[string]interface{} {
 								"type": "string",
 							},
 						},
+						{
+							"name":        "lang",
+							"in":          "query",
+							"description": "Filter prompts by language, such as de or pt-BR. Prompts without a language field match the language detected from their content.",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
 						{
 							"name":        "archived",
 							"in":          "query",
@@ -174,30 +185,879 @@ func getOpenAPISpec() map[string]interface{} {
 								"enum": []string{"json", "text"},
 							},
 						},
+						{
+							"name":        "redact",
+							"in":          "query",
+							"description": "Apply the library's redaction rules, removing email addresses, API keys and configured patterns",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Prompts per page; defaults to server.page_size, or every prompt when that is not set",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"maximum": 1000,
+							},
+						},
+						{
+							"name":        "offset",
+							"in":          "query",
+							"description": "Prompts to skip; page.next holds the request for the following page",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"default": 0,
+							},
+						},
+						{
+							"name":        "fields",
+							"in":          "query",
+							"description": "Comma-separated prompt fields to return, such as id,name,tags; case and underscores are ignored",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "include",
+							"in":          "query",
+							"description": "Prompt content to return: all of it, an Excerpt field in its place, or neither",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"summary", "content", "none"},
+							},
+						},
+						{
+							"name":        "sentences",
+							"in":          "query",
+							"description": "Sentences in each excerpt with include=summary",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"minimum": 1,
+								"maximum": 10,
+								"default": 2,
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "List of prompts",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/PromptsResponse",
+									},
+								},
+							},
+						},
+						"400": map[string]interface{}{
+							"description": "Bad request",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+						"500": map[string]interface{}{
+							"description": "Internal server error",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+				"post": map[string]interface{}{
+					"summary":     "Create prompt",
+					"description": "Create a prompt from its fields",
+					"parameters": []map[string]interface{}{
+						{"$ref": "#/components/parameters/IdempotencyKey"},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"$ref": "#/components/schemas/Prompt",
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"201": map[string]interface{}{
+							"description": "The created prompt",
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid prompt, or an Idempotency-Key reused for a different request",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/prompts/{id}": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Get prompt by ID",
+					"description": "Retrieve a specific prompt by its ID",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Prompt ID, or a prefix of it no other prompt ID starts with (409 AMBIGUOUS_ID lists the candidates otherwise)",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "with_content",
+							"in":          "query",
+							"description": "Include prompt content",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "boolean",
+								"default": true,
+							},
+						},
+						{
+							"name":        "redact",
+							"in":          "query",
+							"description": "Apply the library's redaction rules, removing email addresses, API keys and configured patterns",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Prompt details",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/PromptResponse",
+									},
+								},
+							},
+						},
+						"404": map[string]interface{}{
+							"description": "Prompt not found",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+				"put": map[string]interface{}{
+					"summary":     "Update prompt",
+					"description": "Save changes to a prompt as its next version. Fields left out are kept. Send the ETag from GET as If-Match, or the version as expected_version, to refuse the update when someone else changed the prompt since it was read.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Prompt ID. Changes need the full ID, not a prefix",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "If-Match",
+							"in":          "header",
+							"description": "ETag or version the update is based on. Ignored when expected_version is set.",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"name":             map[string]interface{}{"type": "string"},
+										"summary":          map[string]interface{}{"type": "string"},
+										"content":          map[string]interface{}{"type": "string"},
+										"tags":             map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+										"template":         map[string]interface{}{"type": "string"},
+										"expected_version": map[string]interface{}{"type": "string", "description": "Version or ETag the update is based on"},
+									},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "The updated prompt, with its new ETag",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/PromptResponse",
+									},
+								},
+							},
+						},
+						"404": map[string]interface{}{
+							"description": "Prompt not found",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+						"412": map[string]interface{}{
+							"description": "VERSION_CONFLICT: the prompt changed since the expected version. The error context has current_version and etag.",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/prompts/{id}/preview": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Preview prompt",
+					"description": "A standalone, styled HTML page showing the prompt's title, description and tags above its content formatted from Markdown, with images embedded. Variables are filled in as for render. Raw HTML in the prompt is left out.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Prompt ID, or a prefix of it no other prompt ID starts with (409 AMBIGUOUS_ID lists the candidates otherwise)",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "profile",
+							"in":          "query",
+							"description": "Variable profile (profiles/<name>.yaml) that fills {{name}} placeholders. Individual variables can be set with var.<name>=<value> parameters.",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "redact",
+							"in":          "query",
+							"description": "Apply the library's redaction rules, removing email addresses, API keys and configured patterns",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+						{
+							"name":        "download",
+							"in":          "query",
+							"description": "Serve the page as a file download",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "HTML page",
+							"content": map[string]interface{}{
+								"text/html": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"type": "string",
+									},
+								},
+							},
+						},
+						"404": map[string]interface{}{
+							"description": "Prompt not found",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/templates/{id}/preview": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Preview template",
+					"description": "A standalone, styled HTML page showing the template's name, description and slots above its content.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Template ID",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "redact",
+							"in":          "query",
+							"description": "Apply the library's redaction rules, removing email addresses, API keys and configured patterns",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+						{
+							"name":        "download",
+							"in":          "query",
+							"description": "Serve the page as a file download",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "HTML page",
+							"content": map[string]interface{}{
+								"text/html": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"type": "string",
+									},
+								},
+							},
+						},
+						"404": map[string]interface{}{
+							"description": "Template not found",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/prompts/{id}/render": map[string]interface{}{
+				"post": map[string]interface{}{
+					"summary":     "Render prompt with context",
+					"description": "Render a prompt with text sent along, such as code selected in a browser. The text fills the variable named by the prompt's context_slot (default: context), or is appended after the prompt when nothing uses that variable. Query parameters work as for GET; fields in a JSON body take precedence. Requires only the read scope.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Prompt ID, or a prefix of it no other prompt ID starts with (409 AMBIGUOUS_ID lists the candidates otherwise)",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"context":   map[string]interface{}{"type": "string", "description": "Text to render the prompt with"},
+										"variables": map[string]interface{}{"type": "object", "description": "Variable values, overriding the profile"},
+										"profile":   map[string]interface{}{"type": "string"},
+										"format":    map[string]interface{}{"type": "string", "description": "text, json or a plugin formatter"},
+										"images":    map[string]interface{}{"type": "string", "enum": []string{"base64", "path"}},
+									},
+								},
+							},
+							"text/plain": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type":        "string",
+									"description": "The context text itself",
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Rendered prompt",
+						},
+						"404": map[string]interface{}{
+							"description": "Prompt not found",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+				"get": map[string]interface{}{
+					"summary":     "Render prompt",
+					"description": "Render a prompt as text, or as a chat request with format=json, filling in variables from a profile and var.<name> parameters. JSON output includes the prompt's images and, when it has an output schema, a response_format block.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Prompt ID, or a prefix of it no other prompt ID starts with (409 AMBIGUOUS_ID lists the candidates otherwise)",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "format",
+							"in":          "query",
+							"description": "Output format: text, json, or a formatter installed as a plugin on the server",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "string",
+								"default": "text",
+							},
+						},
+						{
+							"name":        "images",
+							"in":          "query",
+							"description": "How JSON output includes images",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "string",
+								"enum":    []string{"base64", "path"},
+								"default": "base64",
+							},
+						},
+						{
+							"name":        "profile",
+							"in":          "query",
+							"description": "Variable profile (profiles/<name>.yaml) that fills {{name}} placeholders. Individual variables can be set with var.<name>=<value> parameters.",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "redact",
+							"in":          "query",
+							"description": "Apply the library's redaction rules, removing email addresses, API keys and configured patterns",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Rendered prompt",
+						},
+						"404": map[string]interface{}{
+							"description": "Prompt not found",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/stats": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Library statistics",
+					"description": "Per-prompt metrics for reporting: versions, estimated tokens, words, tags, review state, last edit and usage count",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "format",
+							"in":          "query",
+							"description": "Response format",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "string",
+								"enum":    []string{"json", "csv"},
+								"default": "json",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Prompt statistics, as a JSON response or a CSV file",
+						},
+					},
+				},
+			},
+			"/search": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Search prompts",
+					"description": "Search prompts using fuzzy text matching",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "q",
+							"in":          "query",
+							"description": "Search query",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "packs",
+							"in":          "query",
+							"description": "Comma-separated list of packs to search",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "redact",
+							"in":          "query",
+							"description": "Apply the library's redaction rules, removing email addresses, API keys and configured patterns",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Prompts per page; defaults to server.page_size, or every prompt when that is not set",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"maximum": 1000,
+							},
+						},
+						{
+							"name":        "offset",
+							"in":          "query",
+							"description": "Prompts to skip; page.next holds the request for the following page",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"default": 0,
+							},
+						},
+						{
+							"name":        "fields",
+							"in":          "query",
+							"description": "Comma-separated prompt fields to return, such as id,name,tags; case and underscores are ignored",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "include",
+							"in":          "query",
+							"description": "Prompt content to return: all of it, an Excerpt field in its place, or neither",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"summary", "content", "none"},
+							},
+						},
+						{
+							"name":        "sentences",
+							"in":          "query",
+							"description": "Sentences in each excerpt with include=summary",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"minimum": 1,
+								"maximum": 10,
+								"default": 2,
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Search results",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/PromptsResponse",
+									},
+								},
+							},
+						},
+						"400": map[string]interface{}{
+							"description": "Missing search query",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/quick-search": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Quick search",
+					"description": "Search for browser extension popups: returns only each prompt's id, title and tags",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "q",
+							"in":          "query",
+							"description": "Search query; empty lists the first prompts",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Maximum number of results",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"default": 10,
+								"maximum": 50,
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Matching prompts' ids, titles and tags",
+						},
+					},
+				},
+			},
+			"/boolean-search": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Boolean search prompts",
+					"description": "Search prompts using boolean expressions with AND, OR, NOT operators",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "expr",
+							"in":          "query",
+							"description": "Boolean expression (e.g., 'ai AND analysis OR writing')",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "packs",
+							"in":          "query",
+							"description": "Comma-separated list of packs to search",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "redact",
+							"in":          "query",
+							"description": "Apply the library's redaction rules, removing email addresses, API keys and configured patterns",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "boolean",
+							},
+						},
+						{
+							"name":        "limit",
+							"in":          "query",
+							"description": "Prompts per page; defaults to server.page_size, or every prompt when that is not set",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"maximum": 1000,
+							},
+						},
+						{
+							"name":        "offset",
+							"in":          "query",
+							"description": "Prompts to skip; page.next holds the request for the following page",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"default": 0,
+							},
+						},
+						{
+							"name":        "fields",
+							"in":          "query",
+							"description": "Comma-separated prompt fields to return, such as id,name,tags; case and underscores are ignored",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "include",
+							"in":          "query",
+							"description": "Prompt content to return: all of it, an Excerpt field in its place, or neither",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"summary", "content", "none"},
+							},
+						},
+						{
+							"name":        "sentences",
+							"in":          "query",
+							"description": "Sentences in each excerpt with include=summary",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type":    "integer",
+								"minimum": 1,
+								"maximum": 10,
+								"default": 2,
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Search results",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/PromptsResponse",
+									},
+								},
+							},
+						},
+						"400": map[string]interface{}{
+							"description": "Invalid boolean expression",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/boolean/validate": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Validate and explain a boolean expression",
+					"description": "Parse a boolean expression and return its normalized form, the parsed tree with the number of prompts each node matches, per-tag prompt counts, and any syntax error with its 1-based column. An invalid expression still returns 200 with valid set to false.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "expr",
+							"in":          "query",
+							"description": "Boolean expression (e.g., '(ai OR ml) AND NOT draft')",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Explanation of the expression",
+						},
+						"400": map[string]interface{}{
+							"description": "Missing expression",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
 					},
+				},
+			},
+			"/tags": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "List tags",
+					"description": "Retrieve all available tags",
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
-							"description": "List of prompts",
+							"description": "List of tags",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/PromptsResponse",
+										"$ref": "#/components/schemas/TagsResponse",
 									},
 								},
 							},
 						},
-						"400": map[string]interface{}{
-							"description": "Bad request",
-							"content": map[string]interface{}{
-								"application/json": map[string]interface{}{
-									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/ErrorResponse",
+					},
+				},
+			},
+			"/templates": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "List templates",
+					"description": "Retrieve all templates",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "List of templates",
+						},
+					},
+				},
+				"post": map[string]interface{}{
+					"summary":     "Create template",
+					"description": "Create a template; fails if the ID is taken",
+					"parameters": []map[string]interface{}{
+						{"$ref": "#/components/parameters/IdempotencyKey"},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"required": []string{"id"},
+									"properties": map[string]interface{}{
+										"id":          map[string]interface{}{"type": "string"},
+										"name":        map[string]interface{}{"type": "string"},
+										"description": map[string]interface{}{"type": "string"},
+										"content":     map[string]interface{}{"type": "string"},
+										"version":     map[string]interface{}{"type": "string"},
+										"slots": map[string]interface{}{
+											"type":        "array",
+											"description": "Slot names, or objects with name, description, required and default",
+										},
 									},
 								},
 							},
 						},
-						"500": map[string]interface{}{
-							"description": "Internal server error",
+					},
+					"responses": map[string]interface{}{
+						"201": map[string]interface{}{
+							"description": "The created template",
+						},
+						"409": map[string]interface{}{
+							"description": "Template already exists",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
@@ -209,44 +1069,77 @@ func getOpenAPISpec() map[string]interface{} {
 					},
 				},
 			},
-			"/prompts/{id}": map[string]interface{}{
+			"/templates/{id}": map[string]interface{}{
 				"get": map[string]interface{}{
-					"summary":     "Get prompt by ID",
-					"description": "Retrieve a specific prompt by its ID",
+					"summary":     "Get template",
 					"parameters": []map[string]interface{}{
 						{
 							"name":        "id",
 							"in":          "path",
-							"description": "Prompt ID",
+							"description": "Template ID",
 							"required":    true,
 							"schema": map[string]interface{}{
 								"type": "string",
 							},
 						},
-						{
-							"name":        "with_content",
-							"in":          "query",
-							"description": "Include prompt content",
-							"required":    false,
-							"schema": map[string]interface{}{
-								"type":    "boolean",
-								"default": true,
-							},
-						},
 					},
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
-							"description": "Prompt details",
+							"description": "The template",
+						},
+						"404": map[string]interface{}{
+							"description": "Template not found",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/PromptResponse",
+										"$ref": "#/components/schemas/ErrorResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+				"put": map[string]interface{}{
+					"summary":     "Update template",
+					"description": "Change the fields given. The replaced version is archived in archive/templates/ and the version bumped unless a new one is given.",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Template ID",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"id":          map[string]interface{}{"type": "string"},
+										"name":        map[string]interface{}{"type": "string"},
+										"description": map[string]interface{}{"type": "string"},
+										"content":     map[string]interface{}{"type": "string"},
+										"version":     map[string]interface{}{"type": "string"},
+										"slots": map[string]interface{}{
+											"type":        "array",
+											"description": "Slot names, or objects with name, description, required and default",
+										},
 									},
 								},
 							},
 						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "The updated template",
+						},
 						"404": map[string]interface{}{
-							"description": "Prompt not found",
+							"description": "Template not found",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
@@ -257,25 +1150,53 @@ func getOpenAPISpec() map[string]interface{} {
 						},
 					},
 				},
+				"delete": map[string]interface{}{
+					"summary":     "Delete template",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Template ID",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Template deleted",
+						},
+					},
+				},
 			},
-			"/search": map[string]interface{}{
+			"/archive": map[string]interface{}{
 				"get": map[string]interface{}{
-					"summary":     "Search prompts",
-					"description": "Search prompts using fuzzy text matching",
+					"summary":     "List archived versions",
+					"description": "Archived prompt versions, kept each time a prompt is updated, sorted by prompt ID and newest first",
 					"parameters": []map[string]interface{}{
 						{
-							"name":        "q",
+							"name":        "id",
 							"in":          "query",
-							"description": "Search query",
-							"required":    true,
+							"description": "Only versions of this prompt",
+							"required":    false,
 							"schema": map[string]interface{}{
 								"type": "string",
 							},
 						},
 						{
-							"name":        "packs",
+							"name":        "since",
 							"in":          "query",
-							"description": "Comma-separated list of packs to search",
+							"description": "Only versions last edited on or after this date (YYYY-MM-DD or RFC 3339)",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "until",
+							"in":          "query",
+							"description": "Only versions last edited before this time, or on this date (YYYY-MM-DD or RFC 3339)",
 							"required":    false,
 							"schema": map[string]interface{}{
 								"type": "string",
@@ -284,17 +1205,59 @@ func getOpenAPISpec() map[string]interface{} {
 					},
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
-							"description": "Search results",
-							"content": map[string]interface{}{
-								"application/json": map[string]interface{}{
-									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/PromptsResponse",
-									},
-								},
+							"description": "Archived prompt versions",
+						},
+					},
+				},
+			},
+			"/archive/{id}": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":     "Archived versions of a prompt",
+					"description": "List the archived versions of a prompt, or return one with its content when version is given",
+					"parameters": []map[string]interface{}{
+						{
+							"name":        "id",
+							"in":          "path",
+							"description": "Original prompt ID",
+							"required":    true,
+							"schema": map[string]interface{}{
+								"type": "string",
 							},
 						},
-						"400": map[string]interface{}{
-							"description": "Missing search query",
+						{
+							"name":        "version",
+							"in":          "query",
+							"description": "Return only this version, with content",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "since",
+							"in":          "query",
+							"description": "Only versions last edited on or after this date (YYYY-MM-DD or RFC 3339)",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+						{
+							"name":        "until",
+							"in":          "query",
+							"description": "Only versions last edited before this time, or on this date (YYYY-MM-DD or RFC 3339)",
+							"required":    false,
+							"schema": map[string]interface{}{
+								"type": "string",
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Archived versions, or the requested version",
+						},
+						"404": map[string]interface{}{
+							"description": "Archived version not found",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
@@ -306,43 +1269,52 @@ func getOpenAPISpec() map[string]interface{} {
 					},
 				},
 			},
-			"/boolean-search": map[string]interface{}{
-				"get": map[string]interface{}{
-					"summary":     "Boolean search prompts",
-					"description": "Search prompts using boolean expressions with AND, OR, NOT operators",
+			"/archive/{id}/restore": map[string]interface{}{
+				"post": map[string]interface{}{
+					"summary":     "Restore an archived version",
+					"description": "Save an archived version as the prompt's next version. The current version is archived first; a deleted prompt is recreated.",
 					"parameters": []map[string]interface{}{
 						{
-							"name":        "expr",
-							"in":          "query",
-							"description": "Boolean expression (e.g., 'ai AND analysis OR writing')",
+							"name":        "id",
+							"in":          "path",
+							"description": "Original prompt ID",
 							"required":    true,
 							"schema": map[string]interface{}{
 								"type": "string",
 							},
 						},
 						{
-							"name":        "packs",
+							"name":        "version",
 							"in":          "query",
-							"description": "Comma-separated list of packs to search",
+							"description": "Version to restore, if not given in the body",
 							"required":    false,
 							"schema": map[string]interface{}{
 								"type": "string",
 							},
 						},
 					},
-					"responses": map[string]interface{}{
-						"200": map[string]interface{}{
-							"description": "Search results",
-							"content": map[string]interface{}{
-								"application/json": map[string]interface{}{
-									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/PromptsResponse",
+					"requestBody": map[string]interface{}{
+						"required": false,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"version": map[string]interface{}{
+											"type":    "string",
+											"example": "1.0.2",
+										},
 									},
 								},
 							},
 						},
-						"400": map[string]interface{}{
-							"description": "Invalid boolean expression",
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "The restored prompt",
+						},
+						"404": map[string]interface{}{
+							"description": "Archived version not found",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
@@ -354,23 +1326,103 @@ func getOpenAPISpec() map[string]interface{} {
 					},
 				},
 			},
-			"/tags": map[string]interface{}{
+			"/locks": map[string]interface{}{
 				"get": map[string]interface{}{
-					"summary":     "List tags",
-					"description": "Retrieve all available tags",
+					"summary":     "List prompt locks",
+					"description": "List the prompts someone has locked for editing. Expired locks are left out.",
 					"responses": map[string]interface{}{
 						"200": map[string]interface{}{
-							"description": "List of tags",
+							"description": "Active locks, sorted by prompt ID",
+						},
+					},
+				},
+			},
+			"/prompts/{id}/lock": map[string]interface{}{
+				"parameters": []map[string]interface{}{
+					{
+						"name":        "id",
+						"in":          "path",
+						"description": "Prompt ID, or for GET a prefix of it no other prompt ID starts with. Locking and unlocking need the full ID",
+						"required":    true,
+						"schema": map[string]interface{}{
+							"type": "string",
+						},
+					},
+				},
+				"get": map[string]interface{}{
+					"summary":     "Get a prompt's lock",
+					"description": "Return who has locked the prompt for editing, or null",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "The lock, or null when the prompt is not locked",
+						},
+					},
+				},
+				"post": map[string]interface{}{
+					"summary":     "Lock a prompt",
+					"description": "Mark a prompt as being edited. Locks are advisory: they never block a save, but clients warn before editing a locked prompt. Taking your own lock again renews it.",
+					"requestBody": map[string]interface{}{
+						"required": false,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"owner": map[string]interface{}{
+											"type":        "string",
+											"description": "Who is editing (default: the server's git user)",
+										},
+										"note": map[string]interface{}{
+											"type": "string",
+										},
+										"ttl": map[string]interface{}{
+											"type":        "string",
+											"description": "How long the lock lasts (default: 8h)",
+											"example":     "2h",
+										},
+										"force": map[string]interface{}{
+											"type":        "boolean",
+											"description": "Take over someone else's lock",
+										},
+									},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "The lock",
+						},
+						"404": map[string]interface{}{
+							"description": "Prompt not found",
+						},
+						"409": map[string]interface{}{
+							"description": "Locked by someone else",
 							"content": map[string]interface{}{
 								"application/json": map[string]interface{}{
 									"schema": map[string]interface{}{
-										"$ref": "#/components/schemas/TagsResponse",
+										"$ref": "#/components/schemas/ErrorResponse",
 									},
 								},
 							},
 						},
 					},
 				},
+				"delete": map[string]interface{}{
+					"summary":     "Unlock a prompt",
+					"description": "Release a prompt's lock. Releasing someone else's lock needs force, in the body or as ?force=true.",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Lock released",
+						},
+						"404": map[string]interface{}{
+							"description": "Prompt not locked",
+						},
+						"409": map[string]interface{}{
+							"description": "Locked by someone else",
+						},
+					},
+				},
 			},
 			"/health": map[string]interface{}{
 				"get": map[string]interface{}{
@@ -402,6 +1454,18 @@ func getOpenAPISpec() map[string]interface{} {
 			},
 		},
 		"components": map[string]interface{}{
+			"parameters": map[string]interface{}{
+				"IdempotencyKey": map[string]interface{}{
+					"name":        "Idempotency-Key",
+					"in":          "header",
+					"description": "Any unique value, such as a UUID. A retry with the same key within 24 hours gets the first response, marked with an Idempotent-Replayed header, instead of creating again.",
+					"required":    false,
+					"schema": map[string]interface{}{
+						"type":      "string",
+						"maxLength": 255,
+					},
+				},
+			},
 			"schemas": map[string]interface{}{
 				"Prompt": map[string]interface{}{
 					"type": "object",
@@ -464,6 +1528,20 @@ func getOpenAPISpec() map[string]interface{} {
 							"type":        "string",
 							"description": "Human-readable message",
 						},
+						"similar_titles": map[string]interface{}{
+							"type":        "array",
+							"description": "Set when a created prompt's title is nearly identical to existing prompts in the library or a pack, so clients can suggest extending one of those instead",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"id":    map[string]interface{}{"type": "string"},
+									"title": map[string]interface{}{"type": "string"},
+									"pack":  map[string]interface{}{"type": "string", "description": "Empty for the personal library"},
+									"path":  map[string]interface{}{"type": "string", "description": "The prompt's file in the library"},
+									"url":   map[string]interface{}{"type": "string", "description": "API path of the existing prompt"},
+								},
+							},
+						},
 						"error": map[string]interface{}{
 							"description": "Error information if request failed",
 						},
@@ -475,6 +1553,26 @@ func getOpenAPISpec() map[string]interface{} {
 					},
 					"required": []string{"success", "timestamp"},
 				},
+				"Page": map[string]interface{}{
+					"type":        "object",
+					"description": "Which part of a list a response holds; present only when the list is paged",
+					"properties": map[string]interface{}{
+						"offset": map[string]interface{}{
+							"type": "integer",
+						},
+						"limit": map[string]interface{}{
+							"type": "integer",
+						},
+						"total": map[string]interface{}{
+							"type":        "integer",
+							"description": "Prompts in the whole list",
+						},
+						"next": map[string]interface{}{
+							"type":        "string",
+							"description": "Request for the following page, also sent as a Link header; absent on the last page",
+						},
+					},
+				},
 				"PromptsResponse": map[string]interface{}{
 					"allOf": []map[string]interface{}{
 						{"$ref": "#/components/schemas/APIResponse"},
@@ -487,6 +1585,9 @@ func getOpenAPISpec() map[string]interface{} {
 										"$ref": "#/components/schemas/Prompt",
 									},
 								},
+								"page": map[string]interface{}{
+									"$ref": "#/components/schemas/Page",
+								},
 							},
 						},
 					},
@@ -547,12 +1648,16 @@ func getOpenAPISpec() map[string]interface{} {
 				"ErrorResponse": map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
+						"success": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Always false for errors",
+						},
 						"error": map[string]interface{}{
 							"type": "object",
 							"properties": map[string]interface{}{
 								"code": map[string]interface{}{
 									"type":        "string",
-									"description": "Error code",
+									"description": "Error code to branch on, such as NOT_FOUND, VALIDATION_ERROR, PERMISSION_DENIED, ALREADY_EXISTS, VERSION_CONFLICT, AMBIGUOUS_ID, UNAUTHORIZED, METHOD_NOT_ALLOWED or INTERNAL_ERROR",
 								},
 								"message": map[string]interface{}{
 									"type":        "string",
@@ -565,10 +1670,20 @@ func getOpenAPISpec() map[string]interface{} {
 								"category": map[string]interface{}{
 									"type":        "string",
 									"description": "Error category",
+									"enum":        []string{"validation", "service", "storage", "network", "authentication", "authorization", "command", "git", "system"},
 								},
 								"severity": map[string]interface{}{
 									"type":        "string",
 									"description": "Error severity level",
+									"enum":        []string{"info", "warning", "error", "critical"},
+								},
+								"retryable": map[string]interface{}{
+									"type":        "boolean",
+									"description": "Whether the same request may succeed if retried later",
+								},
+								"context": map[string]interface{}{
+									"type":        "object",
+									"description": "Values that describe the failure",
 								},
 								"timestamp": map[string]interface{}{
 									"type":        "string",
@@ -576,10 +1691,14 @@ func getOpenAPISpec() map[string]interface{} {
 									"description": "Error timestamp",
 								},
 							},
-							"required": []string{"code", "message", "timestamp"},
+							"required": []string{"code", "category", "severity", "message", "retryable", "timestamp"},
+						},
+						"timestamp": map[string]interface{}{
+							"type":   "string",
+							"format": "date-time",
 						},
 					},
-					"required": []string{"error"},
+					"required": []string{"success", "error", "timestamp"},
 				},
 			},
 		},
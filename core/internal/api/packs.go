@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/errors"
+)
+
+// handlePacksWithName handles pack administration, mirroring 'pkt packs':
+// POST /api/v1/packs/install installs a pack, GET /api/v1/packs/{name} shows
+// one, DELETE /api/v1/packs/{name} uninstalls it and POST
+// /api/v1/packs/{name}/update pulls its latest version
+func (s *APIServer) handlePacksWithName(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/packs/")
+	name, action, _ := strings.Cut(path, "/")
+	if name == "" {
+		s.writeError(w, errors.ValidationError("Pack name is required"))
+		return
+	}
+
+	switch {
+	case name == "install" && action == "" && r.Method == "POST":
+		s.handleInstallPack(w, r)
+	case action == "" && r.Method == "GET":
+		pack, err := s.service.GetPack(name)
+		if err != nil {
+			s.writeError(w, errors.NotFoundError("Pack "+name))
+			return
+		}
+		s.writeResponse(w, pack, "", http.StatusOK)
+	case action == "" && r.Method == "DELETE":
+		if _, err := s.service.GetPack(name); err != nil {
+			s.writeError(w, errors.NotFoundError("Pack "+name))
+			return
+		}
+		if err := s.service.UninstallPack(name); err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeResponse(w, map[string]string{"name": name}, fmt.Sprintf("Pack '%s' uninstalled", name), http.StatusOK)
+	case action == "update" && r.Method == "POST":
+		if _, err := s.service.GetPack(name); err != nil {
+			s.writeError(w, errors.NotFoundError("Pack "+name))
+			return
+		}
+		pack, err := s.service.UpdatePack(name)
+		if err != nil {
+			s.writeError(w, errors.ValidationError(err.Error()))
+			return
+		}
+		s.writeResponse(w, pack, fmt.Sprintf("Pack '%s' updated to version %s", pack.Name, pack.Version), http.StatusOK)
+	case action == "" || action == "update":
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+	default:
+		s.writeError(w, errors.NotFoundError("Endpoint "+r.URL.Path))
+	}
+}
+
+// handleInstallPack handles POST /api/v1/packs/install. The body names the
+// source, a Git URL or a directory on the server, and the options 'pkt packs
+// install' takes as flags.
+func (s *APIServer) handleInstallPack(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Source string `json:"source"`
+		Name   string `json:"name"`
+		Branch string `json:"branch"`
+		Force  bool   `json:"force"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&body); err != nil {
+		s.writeError(w, errors.ValidationError("Invalid JSON in request body"))
+		return
+	}
+	if body.Source == "" {
+		s.writeError(w, errors.ValidationError("source is required"))
+		return
+	}
+
+	pack, err := s.service.InstallPack(body.Source, config.PackInstallOptions{
+		Name:   body.Name,
+		Branch: body.Branch,
+		Force:  body.Force,
+	})
+	if err != nil {
+		s.writeError(w, errors.ValidationError("Failed to install pack: "+err.Error()))
+		return
+	}
+	s.writeResponse(w, pack, fmt.Sprintf("Pack '%s' installed from %s", pack.Name, body.Source), http.StatusCreated)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/redact"
+)
+
+// Page says which part of a list of prompts a response holds. Next is the
+// request for the following page, empty on the last one.
+type Page struct {
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+	Total  int    `json:"total"`
+	Next   string `json:"next,omitempty"`
+}
+
+// paginate cuts the prompts in a command result to the page asked for with
+// ?limit= and ?offset=, the limit defaulting to server.page_size. Other data,
+// and every prompt when neither a limit nor a page size is set, is returned
+// whole with a nil page.
+func (s *APIServer) paginate(r *http.Request, data interface{}) (interface{}, *Page, error) {
+	prompts, ok := data.([]*models.Prompt)
+	if !ok {
+		return data, nil, nil
+	}
+	query := r.URL.Query()
+
+	limit := s.keys.Current().Server.PageSize
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, nil, errors.ValidationError("limit must be a positive number")
+		}
+		limit = min(n, config.MaxPageSize)
+	}
+	offset := 0
+	if value := query.Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, nil, errors.ValidationError("offset must be zero or a positive number")
+		}
+		offset = n
+	}
+	if limit == 0 && offset == 0 {
+		return prompts, nil, nil
+	}
+	if limit == 0 {
+		limit = config.MaxPageSize
+	}
+
+	page := &Page{Offset: offset, Limit: limit, Total: len(prompts)}
+	start := min(offset, len(prompts))
+	end := min(start+limit, len(prompts))
+	if end < len(prompts) {
+		next := *r.URL
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(end))
+		next.RawQuery = query.Encode()
+		page.Next = next.RequestURI()
+	}
+	return prompts[start:end], page, nil
+}
+
+// writeList writes the page of data the request asks for, with a Link header
+// to the next page when there is one, redacted by redactor when it is not nil
+// and narrowed to the content and fields it asks for. Content is loaded for
+// the page alone and redacted before any excerpt is taken from it.
+func (s *APIServer) writeList(w http.ResponseWriter, r *http.Request, redactor *redact.Redactor, data interface{}, message string) {
+	data, page, err := s.paginate(r, data)
+	if err == nil {
+		data, err = s.loadListContent(r, data)
+	}
+	if err == nil {
+		data, err = selectFields(r, redactResult(redactor, data))
+	}
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if page != nil && page.Next != "" {
+		w.Header().Set("Link", "<"+page.Next+`>; rel="next"`)
+	}
+	s.writeJSON(w, APIResponse{
+		Success:   true,
+		Data:      data,
+		Page:      page,
+		Message:   message,
+		Timestamp: time.Now(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// handlePreview handles GET /api/v1/prompts/{id}/preview and
+// /api/v1/templates/{id}/preview, serving a standalone HTML page to share.
+// Prompt variables are filled in from profile and var.<name> parameters, as
+// for render, and images are always embedded.
+func (s *APIServer) handlePreview(w http.ResponseWriter, r *http.Request, id string, isTemplate bool) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	variables := map[string]interface{}{}
+	for param, values := range query {
+		if name, ok := strings.CutPrefix(param, "var."); ok && name != "" {
+			variables[name] = values[0]
+		}
+	}
+	opts := service.RenderOptions{
+		Profile:   query.Get("profile"),
+		Variables: variables,
+		Redactor:  redactor,
+	}
+
+	var page string
+	kind := "Prompt"
+	if isTemplate {
+		kind = "Template"
+		page, err = s.service.PreviewTemplate(id, opts)
+	} else {
+		page, err = s.service.PreviewPrompt(id, opts)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.writeError(w, errors.NotFoundError(fmt.Sprintf("%s %s", kind, id)))
+		} else {
+			s.writeError(w, errors.ValidationError(err.Error()))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if query.Get("download") == "true" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+".html"))
+	}
+	w.Write([]byte(page))
+}
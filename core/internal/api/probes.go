@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+)
+
+// probeStatus is the body of the liveness and readiness probes
+type probeStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// handleLiveness handles GET /healthz, which answers as long as the process
+// is serving. Like /readyz it needs no API key and is not logged, so container
+// orchestrators can poll it.
+func (s *APIServer) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, http.StatusOK, probeStatus{Status: "ok"})
+}
+
+// handleReadiness handles GET /readyz: ready once startup (including the
+// initial git pull) has finished and the library is reachable, and no longer
+// ready once shutdown begins
+func (s *APIServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeProbe(w, http.StatusServiceUnavailable, probeStatus{Status: "unavailable", Reason: "starting or shutting down"})
+		return
+	}
+	if _, err := os.Stat(s.service.GetBaseDir()); err != nil {
+		writeProbe(w, http.StatusServiceUnavailable, probeStatus{Status: "unavailable", Reason: "library not accessible"})
+		return
+	}
+	writeProbe(w, http.StatusOK, probeStatus{Status: "ready"})
+}
+
+func writeProbe(w http.ResponseWriter, code int, status probeStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
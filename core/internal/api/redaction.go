@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/redact"
+)
+
+// requestRedactor returns a redactor built from the library's current rules
+// when the request asks for ?redact=true, and nil otherwise
+func (s *APIServer) requestRedactor(r *http.Request) (*redact.Redactor, error) {
+	if r.URL.Query().Get("redact") != "true" {
+		return nil, nil
+	}
+	redactor, err := redact.New(s.keys.Current().Redaction)
+	if err != nil {
+		return nil, errors.ValidationError(err.Error())
+	}
+	return redactor, nil
+}
+
+// redactResult applies redactor to the prompts in a command result. Other
+// data, and any data when redactor is nil, is returned unchanged.
+func redactResult(redactor *redact.Redactor, data interface{}) interface{} {
+	if redactor == nil {
+		return data
+	}
+	switch d := data.(type) {
+	case *models.Prompt:
+		return redactor.Prompt(d)
+	case []*models.Prompt:
+		return redactor.Prompts(d)
+	}
+	return data
+}
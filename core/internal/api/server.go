@@ -25,6 +25,7 @@
 // - Logging: Request/response logging with timing information
 // - CORS: Cross-origin resource sharing for web application integration
 // - Content-Type: Automatic JSON content type setting
+// - Auth: Scoped API keys from config (when any are configured) and an audit log of changes
 // - Error Handling: Panic recovery and standardized error responses
 // - Validation: Request parameter validation (when implemented)
 //
@@ -39,18 +40,31 @@
 // - /api/v1/prompts: Prompt CRUD operations
 // - /api/v1/search: Fuzzy search functionality
 // - /api/v1/boolean-search: Boolean expression search
+// - /api/v1/boolean/validate: Parse a boolean expression and explain what it matches
 // - /api/v1/tags: Tag management and listing
+// - /api/v1/templates: Template CRUD through the unified template commands
+// - /api/v1/archive: Archived prompt versions, and restoring one as the current version
+// - /api/v1/locks, /api/v1/prompts/{id}/lock: Advisory locks marking prompts as being edited
+// - /api/v1/prompts/{id}/preview, /api/v1/templates/{id}/preview: Shareable HTML previews
 // - /api/v1/health: System health monitoring
+// - /healthz, /readyz: Liveness and readiness probes for containers (no API key)
+// - /api/v1/audit: Recent changes with the API key that made them (admin keys)
+// - /api/v1/commands/{name}: Run any unified command by name (used by pkt --remote)
 // - /api/docs: Interactive API documentation
+// - /shortcuts: iOS Shortcuts definitions pointing at this server
+// - /quick-add: Create a prompt from plain text (share sheet, bookmarklet)
+// - /api/v1/quick-search, /capture: Minimal search and selection capture for browser extensions
+// - /feed.xml: Atom feed of recently created and updated prompts
+// - /gallery: HTML gallery of templates with slots, examples and usage
 //
 // USAGE PATTERNS:
 // - Start server: Use Start() method with desired port
 // - Add endpoints: Implement handler methods following established patterns
 // - Handle errors: Use writeError() for consistent error responses
+// - Creating endpoints: Wrap in idempotent() so retries with an Idempotency-Key don't create twice
 // - Document APIs: Update OpenAPI specification in openapi.go
 //
 // FUTURE DEVELOPMENT:
-// - Authentication: Add JWT or API key authentication middleware
 // - Rate limiting: Implement rate limiting for API protection
 // - Caching: Add response caching for improved performance
 // - Webhooks: Add webhook support for event notifications
@@ -61,15 +75,20 @@ package api
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/config"
 	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
 	"github.com/dpshade/pocket-prompt/internal/service"
 )
 
@@ -79,7 +98,15 @@ type APIServer struct {
 	executor     *commands.CommandExecutor
 	errorHandler *errors.HTTPErrorHandler
 	port         int
+	listen       string
+	portFallback bool   // Listen on the next free port when port is taken
+	tailnetName  string // MagicDNS name when listening on the tailnet
+	onListen     func(addr string)
 	server       *http.Server
+	keys         *config.Watcher
+	audit        *auditLog
+	idempotency  *idempotencyStore // Responses replayed for Idempotency-Key retries
+	ready        atomic.Bool // Reported by /readyz
 	ctx          context.Context
 	cancel       context.CancelFunc
 }
@@ -93,6 +120,9 @@ func NewAPIServer(svc *service.Service, port int) *APIServer {
 		executor:     commands.NewCommandExecutor(svc),
 		errorHandler: errors.NewHTTPErrorHandler(true), // Include details in responses
 		port:         port,
+		keys:         config.NewWatcher(svc.GetBaseDir(), svc.Settings()),
+		audit:        newAuditLog(svc.GetBaseDir()),
+		idempotency:  newIdempotencyStore(),
 		ctx:          ctx,
 		cancel:       cancel,
 	}
@@ -107,37 +137,87 @@ func (s *APIServer) SetGitSync(enabled bool) {
 	}
 }
 
-// Start begins serving HTTP requests with middleware
-func (s *APIServer) Start() error {
+// Handler returns the server's routes with their middleware, for serving
+// from another listener such as a test or benchmark server
+func (s *APIServer) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// API routes
-	mux.HandleFunc("/api/v1/prompts", s.withMiddleware(s.handlePrompts))
+	mux.HandleFunc("/api/v1/prompts", s.withMiddleware(s.idempotent(s.handlePrompts)))
 	mux.HandleFunc("/api/v1/prompts/", s.withMiddleware(s.handlePromptsWithID))
 	mux.HandleFunc("/api/v1/search", s.withMiddleware(s.handleSearch))
 	mux.HandleFunc("/api/v1/boolean-search", s.withMiddleware(s.handleBooleanSearch))
+	mux.HandleFunc("/api/v1/boolean/validate", s.withMiddleware(s.handleBooleanValidate))
 	mux.HandleFunc("/api/v1/tags", s.withMiddleware(s.handleTags))
 	mux.HandleFunc("/api/v1/tags/", s.withMiddleware(s.handleTagsWithName))
-	mux.HandleFunc("/api/v1/templates", s.withMiddleware(s.handleTemplates))
+	mux.HandleFunc("/api/v1/templates", s.withMiddleware(s.idempotent(s.handleTemplates)))
 	mux.HandleFunc("/api/v1/templates/", s.withMiddleware(s.handleTemplatesWithID))
 	mux.HandleFunc("/api/v1/saved-searches", s.withMiddleware(s.handleSavedSearches))
 	mux.HandleFunc("/api/v1/saved-searches/", s.withMiddleware(s.handleSavedSearchesWithName))
 	mux.HandleFunc("/api/v1/saved-search/", s.withMiddleware(s.handleExecuteSavedSearch))
 	mux.HandleFunc("/api/v1/packs", s.withMiddleware(s.handlePacks))
+	mux.HandleFunc("/api/v1/packs/", s.withMiddleware(s.handlePacksWithName))
+	mux.HandleFunc("/api/v1/archive", s.withMiddleware(s.handleArchive))
+	mux.HandleFunc("/api/v1/archive/", s.withMiddleware(s.handleArchiveWithID))
+	mux.HandleFunc("/api/v1/locks", s.withMiddleware(s.handleLocks))
 	mux.HandleFunc("/api/v1/health", s.withMiddleware(s.handleHealth))
+	mux.HandleFunc("/api/v1/audit", s.withMiddleware(s.handleAudit))
+	mux.HandleFunc("/api/v1/stats", s.withMiddleware(s.handleStats))
+	mux.HandleFunc("/api/v1/commands/", s.withMiddleware(s.handleCommand))
+
+	// Share sheet and bookmarklet target
+	mux.HandleFunc("/quick-add", s.withMiddleware(s.idempotent(s.handleQuickAdd)))
+
+	// Browser extension companions: small search results and selection capture
+	mux.HandleFunc("/api/v1/quick-search", s.withMiddleware(s.handleQuickSearch))
+	mux.HandleFunc("/capture", s.withMiddleware(s.idempotent(s.handleCapture)))
+
+	// Webhook target for no-code tools; fields are mapped by the inbox config
+	mux.HandleFunc("/inbox", s.withMiddleware(s.idempotent(s.handleInbox)))
+
+	// Atom feed of recent prompt changes for feed readers
+	mux.HandleFunc("/feed.xml", s.withMiddleware(s.handleFeed))
+
+	// Browsable page of every template with a rendered example
+	mux.HandleFunc("/gallery", s.withMiddleware(s.handleGallery))
+
+	// Whole-library or per-tag export for backups and migration
+	mux.HandleFunc("/export", s.withMiddleware(s.handleExport))
+
+	// Slack slash commands authenticate with the app's signing secret rather than an API key
+	mux.HandleFunc("/slack/command", s.loggingMiddleware(s.contentTypeMiddleware(s.errorMiddleware(s.handleSlackCommand))))
+
+	// iOS Shortcuts gallery
+	mux.HandleFunc("/shortcuts", s.withMiddleware(s.handleShortcuts))
+	mux.HandleFunc("/shortcuts/", s.withMiddleware(s.handleShortcuts))
 
 	// OpenAPI documentation
 	mux.HandleFunc("/api/docs", s.withMiddleware(s.handleOpenAPI))
 	mux.HandleFunc("/api/openapi.json", s.withMiddleware(s.handleOpenAPISpec))
 
+	// Probes for container orchestrators, outside the middleware
+	mux.HandleFunc("/healthz", s.handleLiveness)
+	mux.HandleFunc("/readyz", s.handleReadiness)
+
+	return mux
+}
+
+// Start begins serving HTTP requests with middleware
+func (s *APIServer) Start() error {
 	s.server = &http.Server{
-		Addr:         fmt.Sprintf(":%d", s.port),
-		Handler:      mux,
+		Handler:      s.Handler(),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// The port is taken first, so a server that cannot listen starts no
+	// background work
+	lis, addr, err := s.listener()
+	if err != nil {
+		return err
+	}
+
 	// Git sync is managed by the service layer - check if it's enabled
 	if s.service.IsGitSyncEnabled() {
 		log.Printf("Git sync enabled")
@@ -147,30 +227,85 @@ func (s *APIServer) Start() error {
 			log.Printf("Warning: Auto-pull failed: %v", err)
 		}
 		
-		// Start background sync with smart 30-second interval
-		go s.service.StartBackgroundSync(s.ctx, 30*time.Second)
+		// Start background sync, every 30 seconds unless configured otherwise
+		s.service.OnPromptChanges(logPulledPrompts)
+		go s.service.StartBackgroundSync(s.ctx, s.service.Settings().Git.Interval(30*time.Second))
+	}
+
+	// A read-only library is only served; nothing in the background changes it
+	readOnly := s.service.ReadOnly()
+	if readOnly {
+		log.Printf("Library is read-only: changes, the email gateway, maintenance and digests are off")
 	}
 
-	log.Printf("API server starting on http://localhost:%d", s.port)
-	log.Printf("OpenAPI documentation: http://localhost:%d/api/docs", s.port)
-	log.Printf("API specification: http://localhost:%d/api/openapi.json", s.port)
+	// Email gateway polls its mailbox in the background when configured
+	if email := s.service.Settings().Email; email.Enabled() && !readOnly {
+		log.Printf("Email gateway enabled for %s (subject prefix %q)", email.MailboxName(), email.Prefix())
+		go s.pollEmail(email)
+	}
+
+	// Maintenance runs on a schedule when an interval is configured
+	if maintenance := s.service.Settings().Maintenance; maintenance.Scheduled() && !readOnly {
+		if interval, err := maintenance.RunInterval(); err != nil {
+			log.Printf("Warning: scheduled maintenance disabled: %v", err)
+		} else {
+			log.Printf("Maintenance scheduled every %v", interval)
+			go s.service.PollMaintenance(s.ctx, interval, logMaintenance)
+		}
+	}
+
+	// Digests of library changes go out on their cron schedule
+	if digest := s.service.Settings().Digest; digest.Scheduled() && !readOnly {
+		if schedule, err := digest.Cron(); err != nil {
+			log.Printf("Warning: scheduled digests disabled: %v", err)
+		} else if !digest.HasDestination() {
+			log.Printf("Warning: scheduled digests disabled: set digest.webhook or digest.smtp")
+		} else {
+			log.Printf("Digests scheduled (%s), next at %s", schedule, schedule.Next(time.Now()).Format(time.RFC1123))
+			go s.pollDigests(digest)
+		}
+	}
+
+	log.Printf("API server starting on %s", addr)
+	if tcp, ok := lis.Addr().(*net.TCPAddr); ok {
+		log.Printf("OpenAPI documentation: %s/api/docs", addr)
+		log.Printf("API specification: %s/api/openapi.json", addr)
+		log.Printf("iOS Shortcuts gallery: %s/shortcuts", addr)
+		log.Printf("Template gallery: %s/gallery", addr)
+		// 'pkt server qr' reads the address back, for setting up Shortcuts
+		if err := s.service.RecordServer(addr, tcp.Port); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if s.onListen != nil {
+		s.onListen(addr)
+	}
 
-	return s.server.ListenAndServe()
+	s.ready.Store(true)
+	return s.server.Serve(lis)
 }
 
 // Stop gracefully shuts down the server
 func (s *APIServer) Stop(ctx context.Context) error {
+	s.ready.Store(false)
 	// Cancel background git sync
 	s.cancel()
+	if err := s.service.ForgetServer(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 	return s.server.Shutdown(ctx)
 }
 
 // withMiddleware applies middleware to HTTP handlers
 func (s *APIServer) withMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 	return s.loggingMiddleware(
-		s.corsMiddleware(
-			s.contentTypeMiddleware(
-				s.errorMiddleware(handler),
+		s.compressionMiddleware(
+			s.corsMiddleware(
+				s.contentTypeMiddleware(
+					s.authMiddleware(
+						s.errorMiddleware(handler),
+					),
+				),
 			),
 		),
 	)
@@ -186,12 +321,31 @@ func (s *APIServer) loggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// corsMiddleware handles CORS headers
+// corsMiddleware handles CORS headers. Without server.cors_origins any origin
+// may call the API. With it, only the listed origins get CORS headers and
+// browser requests from any other page are refused, so a web page cannot use
+// a server on localhost behind the user's back.
 func (s *APIServer) corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		server := s.keys.Current().Server
+		origin := r.Header.Get("Origin")
+		if len(server.CORSOrigins) == 0 {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else if origin != "" {
+			if !server.AllowsOrigin(origin, r.Host) {
+				w.Header().Set("Content-Type", "application/json")
+				s.writeError(w, errors.NewAppError(errors.ErrCodePermissionDenied, "Origin "+origin+" is not allowed"))
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+			// Chrome asks before letting a page reach a private address
+			if r.Header.Get("Access-Control-Request-Private-Network") == "true" {
+				w.Header().Set("Access-Control-Allow-Private-Network", "true")
+			}
+		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, Idempotency-Key")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
 		if r.Method == "OPTIONS" {
@@ -227,11 +381,13 @@ func (s *APIServer) errorMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 // APIResponse represents a standardized API response
 type APIResponse struct {
-	Success   bool        `json:"success"`
-	Data      interface{} `json:"data,omitempty"`
-	Message   string      `json:"message,omitempty"`
-	Error     interface{} `json:"error,omitempty"`
-	Timestamp time.Time   `json:"timestamp"`
+	Success       bool           `json:"success"`
+	Data          interface{}    `json:"data,omitempty"`
+	Page          *Page          `json:"page,omitempty"`           // Set when Data is one page of a longer list
+	SimilarTitles []similarTitle `json:"similar_titles,omitempty"` // Set when a created prompt's title is nearly identical to others
+	Message       string         `json:"message,omitempty"`
+	Error         interface{}    `json:"error,omitempty"`
+	Timestamp     time.Time      `json:"timestamp"`
 }
 
 // writeResponse writes a standardized JSON response
@@ -242,7 +398,11 @@ func (s *APIServer) writeResponse(w http.ResponseWriter, data interface{}, messa
 		Message:   message,
 		Timestamp: time.Now(),
 	}
+	s.writeJSON(w, response, statusCode)
+}
 
+// writeJSON writes a response with the given status
+func (s *APIServer) writeJSON(w http.ResponseWriter, response APIResponse, statusCode int) {
 	w.WriteHeader(statusCode)
 	
 	// Use pretty-printed JSON for better readability
@@ -256,9 +416,35 @@ func (s *APIServer) writeResponse(w http.ResponseWriter, data interface{}, messa
 	w.Write(jsonData)
 }
 
-// writeError writes an error response using the error handler
+// writeError writes an error response using the error handler. Every error
+// is sent as an errors.ErrorResponse; see classifyError for errors that are
+// not AppErrors.
 func (s *APIServer) writeError(w http.ResponseWriter, err error) {
-	s.errorHandler.WriteHTTPError(w, err)
+	s.errorHandler.WriteHTTPError(w, classifyError(err))
+}
+
+// classifyError gives a code to an error a handler passed on from the
+// service, so clients can branch on it rather than on the message
+func classifyError(err error) *errors.AppError {
+	var appErr *errors.AppError
+	var conflict *service.VersionConflictError
+	var ambiguous *service.AmbiguousIDError
+	switch {
+	case err == nil:
+		return errors.InternalError("Internal error occurred")
+	case stderrors.As(err, &appErr):
+		return appErr
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.Wrap(err, errors.ErrCodeTimeout, "Request timed out")
+	case stderrors.As(err, &conflict):
+		return errors.Wrap(err, errors.ErrCodeVersionConflict, err.Error()).
+			WithContext("current_version", conflict.Current).
+			WithContext("etag", conflict.Revision)
+	case stderrors.As(err, &ambiguous):
+		return errors.Wrap(err, errors.ErrCodeAmbiguousID, err.Error()).
+			WithContext("candidates", ambiguous.Candidates)
+	}
+	return errors.Wrap(err, commands.FailureCode(err, errors.ErrCodeInternalError), err.Error())
 }
 
 // handlePrompts handles /api/v1/prompts
@@ -269,7 +455,7 @@ func (s *APIServer) handlePrompts(w http.ResponseWriter, r *http.Request) {
 	case "POST":
 		s.handleCreatePrompt(w, r)
 	default:
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 	}
 }
 
@@ -281,21 +467,62 @@ func (s *APIServer) handlePromptsWithID(w http.ResponseWriter, r *http.Request)
 		s.writeError(w, errors.ValidationError("Prompt ID is required"))
 		return
 	}
+	id, action := path, ""
+	for _, suffix := range []string{"/lock", "/preview", "/render"} {
+		if trimmed := strings.TrimSuffix(path, suffix); trimmed != path {
+			id, action = trimmed, suffix
+			break
+		}
+	}
+
+	// A unique prefix of an ID names the prompt to read, as in the CLI. Changes
+	// need the exact ID: a retried DELETE of "code" must not go on to delete
+	// "code-review" once "code" is gone.
+	if r.Method == "GET" || action == "/render" || action == "/preview" {
+		resolved, err := s.service.ResolvePromptID(id)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		id = resolved
+	}
+
+	switch action {
+	case "/lock":
+		s.handlePromptLock(w, r, id)
+		return
+	case "/preview":
+		s.handlePreview(w, r, id, false)
+		return
+	case "/render":
+		if r.Method != "GET" && r.Method != "POST" {
+			s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+			return
+		}
+		s.handleRenderPrompt(w, r, id)
+		return
+	}
 
 	switch r.Method {
 	case "GET":
-		s.handleGetPrompt(w, r, path)
+		s.handleGetPrompt(w, r, id)
 	case "PUT":
-		s.handleUpdatePrompt(w, r, path)
+		s.handleUpdatePrompt(w, r, id)
 	case "DELETE":
-		s.handleDeletePrompt(w, r, path)
+		s.handleDeletePrompt(w, r, id)
 	default:
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 	}
 }
 
 // handleListPrompts handles GET /api/v1/prompts
 func (s *APIServer) handleListPrompts(w http.ResponseWriter, r *http.Request) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
 	params := make(map[string]interface{})
 
 	// Parse query parameters
@@ -305,6 +532,9 @@ func (s *APIServer) handleListPrompts(w http.ResponseWriter, r *http.Request) {
 	if pack := r.URL.Query().Get("pack"); pack != "" {
 		params["pack"] = pack
 	}
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		params["lang"] = lang
+	}
 	if archived := r.URL.Query().Get("archived"); archived == "true" {
 		params["archived"] = true
 	}
@@ -336,11 +566,17 @@ func (s *APIServer) handleListPrompts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
+	s.writeList(w, r, redactor, result.Data, result.Message)
 }
 
 // handleGetPrompt handles GET /api/v1/prompts/{id}
 func (s *APIServer) handleGetPrompt(w http.ResponseWriter, r *http.Request, id string) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
 	params := map[string]interface{}{
 		"id": id,
 	}
@@ -372,11 +608,145 @@ func (s *APIServer) handleGetPrompt(w http.ResponseWriter, r *http.Request, id s
 		return
 	}
 
-	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
+	if prompt, ok := result.Data.(*models.Prompt); ok {
+		w.Header().Set("ETag", `"`+s.service.PromptRevision(prompt)+`"`)
+	}
+	s.writeResponse(w, redactResult(redactor, result.Data), result.Message, http.StatusOK)
+}
+
+// handleRenderPrompt handles GET and POST /api/v1/prompts/{id}/render. With
+// format=json the rendered chat request, including any response_format block
+// for the prompt's output schema, is returned as JSON rather than a string.
+// profile=<name> fills placeholders from a variable profile, and var.<name>=
+// parameters set individual variables. section=<heading> renders only the
+// part under that Markdown heading. redact=true applies the library's
+// redaction rules to the result.
+//
+// A POST can also send text to render with, such as code selected in a
+// browser: as a JSON body with a "context" field, alongside optional
+// "variables", "profile", "format" and "images", or as a plain text body. The
+// text fills the prompt's context slot, or is appended after the prompt.
+func (s *APIServer) handleRenderPrompt(w http.ResponseWriter, r *http.Request, id string) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	images := query.Get("images")
+	profile := query.Get("profile")
+	variables := map[string]interface{}{}
+	for param, values := range query {
+		if name, ok := strings.CutPrefix(param, "var."); ok && name != "" {
+			variables[name] = values[0]
+		}
+	}
+
+	var selection string
+	if r.Method == "POST" {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			s.writeError(w, errors.ValidationError("Failed to read request body"))
+			return
+		}
+		mediaType := strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0])
+		if mediaType == "application/json" {
+			var req struct {
+				Context   string                 `json:"context"`
+				Variables map[string]interface{} `json:"variables"`
+				Profile   string                 `json:"profile"`
+				Format    string                 `json:"format"`
+				Images    string                 `json:"images"`
+			}
+			if err := json.Unmarshal(body, &req); err != nil {
+				s.writeError(w, errors.ValidationError("Invalid JSON in request body"))
+				return
+			}
+			selection = req.Context
+			for name, value := range req.Variables {
+				variables[name] = value
+			}
+			if req.Profile != "" {
+				profile = req.Profile
+			}
+			if req.Format != "" {
+				format = req.Format
+			}
+			if req.Images != "" {
+				images = req.Images
+			}
+		} else {
+			selection = string(body)
+		}
+	}
+	if format == "" {
+		format = "text"
+	}
+
+	rendered, err := s.service.RenderPrompt(id, service.RenderOptions{
+		Format:    format,
+		Images:    images,
+		Profile:   profile,
+		Variables: variables,
+		Redactor:  redactor,
+		Selection: selection,
+		Section:   query.Get("section"),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.writeError(w, errors.NotFoundError(fmt.Sprintf("Prompt %s", id)))
+		} else {
+			s.writeError(w, errors.ValidationError(err.Error()))
+		}
+		return
+	}
+
+	var content interface{} = rendered
+	if format == "json" {
+		content = json.RawMessage(rendered)
+	}
+	s.writeResponse(w, map[string]interface{}{
+		"id":      id,
+		"format":  format,
+		"content": content,
+	}, "", http.StatusOK)
+}
+
+// handleStats handles GET /api/v1/stats, returning per-prompt metrics as JSON
+// or, with format=csv, as a CSV file for reporting tools
+func (s *APIServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	stats, err := s.service.LibraryStats()
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="prompt-stats.csv"`)
+		if err := service.WriteStatsCSV(w, stats); err != nil {
+			log.Printf("Failed to write stats CSV: %v", err)
+		}
+		return
+	}
+	s.writeResponse(w, stats, fmt.Sprintf("%d prompts", len(stats)), http.StatusOK)
 }
 
 // handleSearch handles GET /api/v1/search
 func (s *APIServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
 	query := r.URL.Query().Get("q")
 	if query == "" {
 		s.writeError(w, errors.ValidationError("Search query 'q' parameter is required"))
@@ -415,11 +785,17 @@ func (s *APIServer) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
+	s.writeList(w, r, redactor, result.Data, result.Message)
 }
 
 // handleBooleanSearch handles GET /api/v1/boolean-search
 func (s *APIServer) handleBooleanSearch(w http.ResponseWriter, r *http.Request) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
 	expression := r.URL.Query().Get("expr")
 	if expression == "" {
 		s.writeError(w, errors.ValidationError("Boolean expression 'expr' parameter is required"))
@@ -458,13 +834,40 @@ func (s *APIServer) handleBooleanSearch(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
+	s.writeList(w, r, redactor, result.Data, result.Message)
+}
+
+// handleBooleanValidate handles GET /api/v1/boolean/validate. An invalid
+// expression is still a successful request; its syntax errors are in the body.
+func (s *APIServer) handleBooleanValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	expression := r.URL.Query().Get("expr")
+	if expression == "" {
+		s.writeError(w, errors.ValidationError("Boolean expression 'expr' parameter is required"))
+		return
+	}
+
+	explanation, err := s.service.ExplainBooleanExpression(expression)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	message := fmt.Sprintf("Expression matches %d of %d prompts", explanation.Matches, explanation.TotalPrompts)
+	if !explanation.Valid {
+		message = "Expression has a syntax error"
+	}
+	s.writeResponse(w, explanation, message, http.StatusOK)
 }
 
 // handleTags handles GET /api/v1/tags
 func (s *APIServer) handleTags(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -498,39 +901,99 @@ func (s *APIServer) handleTags(w http.ResponseWriter, r *http.Request) {
 func (s *APIServer) handleTemplates(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case "GET":
-		s.writeError(w, errors.NewAppError(errors.ErrCodeNotImplemented, "Template listing via API is planned for a future release"))
+		result, err := s.executor.Execute(r.Context(), "list-templates", nil)
+		s.writeCommandResult(w, result, err, http.StatusOK)
 	case "POST":
-		s.writeError(w, errors.NewAppError(errors.ErrCodeNotImplemented, "Template creation via API is planned for a future release"))
+		params, err := readJSONParams(r)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		result, err := s.executor.Execute(r.Context(), "create-template", params)
+		s.writeCommandResult(w, result, err, http.StatusCreated)
 	default:
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 	}
 }
 
-// handleTemplatesWithID handles /api/v1/templates/{id}
+// handleTemplatesWithID handles /api/v1/templates/{id}. PUT changes only the
+// fields in the body and archives the version it replaces.
 func (s *APIServer) handleTemplatesWithID(w http.ResponseWriter, r *http.Request) {
 	// Extract ID from path
-	path := strings.TrimPrefix(r.URL.Path, "/api/v1/templates/")
-	if path == "" {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/templates/")
+	if id == "" {
 		s.writeError(w, errors.ValidationError("Template ID is required"))
 		return
 	}
+	if previewID := strings.TrimSuffix(id, "/preview"); previewID != id {
+		s.handlePreview(w, r, previewID, true)
+		return
+	}
 
 	switch r.Method {
 	case "GET":
-		s.writeError(w, errors.NewAppError(errors.ErrCodeNotImplemented, "Template retrieval via API is planned for a future release"))
+		result, err := s.executor.Execute(r.Context(), "get-template", map[string]interface{}{"id": id})
+		s.writeCommandResult(w, result, err, http.StatusOK)
 	case "PUT":
-		s.writeError(w, errors.NewAppError(errors.ErrCodeNotImplemented, "Template updates via API are planned for a future release"))
+		params, err := readJSONParams(r)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		params["id"] = id
+		result, err := s.executor.Execute(r.Context(), "update-template", params)
+		s.writeCommandResult(w, result, err, http.StatusOK)
 	case "DELETE":
-		s.writeError(w, errors.NewAppError(errors.ErrCodeNotImplemented, "Template deletion via API is planned for a future release"))
+		result, err := s.executor.Execute(r.Context(), "delete-template", map[string]interface{}{"id": id})
+		s.writeCommandResult(w, result, err, http.StatusOK)
 	default:
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+	}
+}
+
+// writeCommandResult writes a command's data on success and its error otherwise
+func (s *APIServer) writeCommandResult(w http.ResponseWriter, result *commands.CommandResult, err error, statusCode int) {
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if !result.Success {
+		if result.Error != nil {
+			s.writeError(w, &errors.AppError{
+				Code:     errors.ErrorCode(result.Error.Code),
+				Message:  result.Error.Message,
+				Details:  result.Error.Details,
+				Category: errors.ErrorCategory(result.Error.Category),
+				Severity: errors.ErrorSeverity(result.Error.Severity),
+			})
+		} else {
+			s.writeError(w, errors.InternalError("Command failed"))
+		}
+		return
+	}
+	s.writeResponse(w, result.Data, result.Message, statusCode)
+}
+
+// readJSONParams reads a JSON object request body as command parameters
+func readJSONParams(r *http.Request) (map[string]interface{}, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.ValidationError("Failed to read request body")
+	}
+	if len(body) == 0 {
+		return nil, errors.ValidationError("Request body is required")
 	}
+	params := map[string]interface{}{}
+	if err := json.Unmarshal(body, &params); err != nil {
+		return nil, errors.ValidationError("Invalid JSON in request body")
+	}
+	return params, nil
 }
 
 // handleHealth handles GET /api/v1/health
 func (s *APIServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -560,6 +1023,59 @@ func (s *APIServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
 }
 
+// handleCommand handles POST /api/v1/commands/{name}, running a unified command
+// with the JSON body as its parameters
+func (s *APIServer) handleCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/commands/")
+	if name == "" {
+		s.writeError(w, errors.ValidationError("Command name is required"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, errors.ValidationError("Failed to read request body"))
+		return
+	}
+
+	var params map[string]interface{}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &params); err != nil {
+			s.writeError(w, errors.ValidationError("Invalid JSON in request body"))
+			return
+		}
+	}
+
+	result, err := s.executor.Execute(r.Context(), name, params)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	if !result.Success {
+		if result.Error != nil {
+			appErr := &errors.AppError{
+				Code:     errors.ErrorCode(result.Error.Code),
+				Message:  result.Error.Message,
+				Details:  result.Error.Details,
+				Category: errors.ErrorCategory(result.Error.Category),
+				Severity: errors.ErrorSeverity(result.Error.Severity),
+			}
+			s.writeError(w, appErr)
+		} else {
+			s.writeError(w, errors.InternalError("Command failed"))
+		}
+		return
+	}
+
+	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
+}
+
 // handleCreatePrompt handles POST /api/v1/prompts
 func (s *APIServer) handleCreatePrompt(w http.ResponseWriter, r *http.Request) {
 	// Parse JSON request body
@@ -608,11 +1124,238 @@ func (s *APIServer) handleCreatePrompt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if prompt, ok := result.Data.(*models.Prompt); ok {
+		s.writeCreated(w, prompt, prompt, result.Message)
+		return
+	}
 	s.writeResponse(w, result.Data, result.Message, http.StatusCreated)
 }
 
+// handleQuickAdd handles POST /quick-add. The body is the prompt text, sent
+// either raw (text/plain, as from a share sheet) or as a form with a "text"
+// field (as from a bookmarklet); title and tags come from query or form values.
+func (s *APIServer) handleQuickAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
+	var content string
+	mediaType := strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0])
+	switch mediaType {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		if err := r.ParseMultipartForm(1 << 20); err != nil && err != http.ErrNotMultipart {
+			s.writeError(w, errors.ValidationError("Failed to parse form data"))
+			return
+		}
+		content = r.FormValue("text")
+		if content == "" {
+			content = r.FormValue("content")
+		}
+		// Bookmarklets send the page URL alongside the selection
+		if pageURL := r.FormValue("url"); pageURL != "" {
+			content = strings.TrimSpace(content + "\n\nSource: " + pageURL)
+		}
+	default:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.writeError(w, errors.ValidationError("Failed to read request body"))
+			return
+		}
+		content = string(body)
+	}
+
+	if strings.TrimSpace(content) == "" {
+		s.writeError(w, errors.ValidationError("Prompt text is required"))
+		return
+	}
+
+	// FormValue also covers query parameters
+	var tags []string
+	if tagList := r.FormValue("tags"); tagList != "" {
+		tags = strings.Split(tagList, ",")
+	}
+
+	prompt, err := s.service.QuickAdd(content, r.FormValue("title"), tags)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeCreated(w, prompt, map[string]interface{}{
+		"id":    prompt.ID,
+		"title": prompt.Name,
+		"tags":  prompt.Tags,
+	}, fmt.Sprintf("Created prompt %s", prompt.ID))
+}
+
+// handleInbox handles POST /inbox, creating a prompt from arbitrary JSON using
+// the field mapping in the library's inbox config
+func (s *APIServer) handleInbox(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+
+	var payload interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		s.writeError(w, errors.ValidationError("Request body must be JSON"))
+		return
+	}
+
+	prompt, err := s.service.InboxPrompt(payload, s.keys.Current().Inbox)
+	if err != nil {
+		s.writeError(w, errors.ValidationError(err.Error()))
+		return
+	}
+	if err := s.service.CreatePrompt(prompt); err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeResponse(w, map[string]interface{}{
+		"id":    prompt.ID,
+		"title": prompt.Name,
+		"tags":  prompt.Tags,
+	}, fmt.Sprintf("Created prompt %s", prompt.ID), http.StatusCreated)
+}
+
+// logMaintenance logs the outcome of a scheduled maintenance run
+func logMaintenance(report *service.MaintenanceReport, err error) {
+	if err != nil {
+		log.Printf("Maintenance failed: %v", err)
+		return
+	}
+	log.Printf("Maintenance: archived %d expired prompts, removed %d archived versions and %d empty directories, indexed %d prompts",
+		len(report.ArchivedPrompts), len(report.RemovedVersions), len(report.RemovedDirs), report.IndexedPrompts)
+	for _, p := range report.ExpiringPrompts {
+		log.Printf("Maintenance: %s expires %s", p.ID, p.Expires)
+	}
+	for _, problem := range report.GitProblems {
+		log.Printf("Maintenance: git fsck: %s", problem)
+	}
+	for _, warning := range report.Warnings {
+		log.Printf("Maintenance: %s", warning)
+	}
+}
+
+// logPulledPrompts logs the prompts a background git pull changed
+func logPulledPrompts(events []service.PromptEvent) {
+	for _, event := range events {
+		log.Printf("Git sync: %s %s (%s)", event.Kind, event.ID, event.Path)
+	}
+}
+
+// pollDigests sends scheduled digests until the server stops, logging each run
+func (s *APIServer) pollDigests(cfg config.DigestConfig) {
+	err := s.service.PollDigests(s.ctx, cfg, func(digest *service.Digest, sent bool, err error) {
+		switch {
+		case err != nil:
+			log.Printf("Digest failed: %v", err)
+		case sent:
+			log.Printf("Digest sent: %s", digest.Summary())
+		default:
+			log.Printf("Digest skipped: no changes")
+		}
+	})
+	if err != nil {
+		log.Printf("Digests stopped: %v", err)
+	}
+}
+
+// pollEmail runs the email gateway until the server stops, logging what each check imports
+func (s *APIServer) pollEmail(cfg config.EmailConfig) {
+	err := s.service.PollEmail(s.ctx, cfg, func(result *service.EmailCheckResult, err error) {
+		if err != nil {
+			log.Printf("Email check failed: %v", err)
+			return
+		}
+		for _, prompt := range result.Prompts {
+			log.Printf("Created prompt %s from email", prompt.ID)
+		}
+		for _, err := range result.Errors {
+			log.Printf("Skipped email: %v", err)
+		}
+	})
+	if err != nil {
+		log.Printf("Email gateway stopped: %v", err)
+	}
+}
+
+// promptUpdate is the body of PUT /api/v1/prompts/{id}. Fields left out keep
+// their current values.
+type promptUpdate struct {
+	Name            *string   `json:"name"`
+	Summary         *string   `json:"summary"`
+	Content         *string   `json:"content"`
+	Tags            *[]string `json:"tags"`
+	Template        *string   `json:"template"`
+	ExpectedVersion string    `json:"expected_version"` // Refuse the update unless the prompt is still at this version
+}
+
+// handleUpdatePrompt handles PUT /api/v1/prompts/{id}, saving the changed
+// fields as the prompt's next version. With expected_version, or an If-Match
+// header holding the ETag from GET, the update is refused with 412 when the
+// prompt changed since, so concurrent editors don't overwrite each other.
 func (s *APIServer) handleUpdatePrompt(w http.ResponseWriter, r *http.Request, id string) {
-	s.writeError(w, errors.NewAppError(errors.ErrCodeNotImplemented, "Prompt updates via API are planned for a future release"))
+	var update promptUpdate
+	if err := json.NewDecoder(io.LimitReader(r.Body, 10<<20)).Decode(&update); err != nil {
+		s.writeError(w, errors.ValidationError("Invalid JSON in request body"))
+		return
+	}
+	expected := update.ExpectedVersion
+	if expected == "" {
+		expected = ifMatch(r)
+	}
+
+	prompt, err := s.service.GetPrompt(id)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	updated := *prompt
+	if update.Name != nil {
+		updated.Name = *update.Name
+	}
+	if update.Summary != nil {
+		updated.Summary = *update.Summary
+	}
+	if update.Content != nil {
+		updated.Content = *update.Content
+	}
+	if update.Tags != nil {
+		updated.Tags = *update.Tags
+	}
+	if update.Template != nil {
+		updated.TemplateRef = *update.Template
+	}
+
+	if expected != "" {
+		err = s.service.UpdatePromptIfVersion(&updated, expected)
+	} else {
+		err = s.service.UpdatePrompt(&updated)
+	}
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", `"`+s.service.PromptRevision(&updated)+`"`)
+	s.writeResponse(w, &updated, fmt.Sprintf("Updated prompt: %s (v%s)", id, updated.Version), http.StatusOK)
+}
+
+// ifMatch returns the ETag or version in an If-Match header, or "" for none
+// or "*"
+func ifMatch(r *http.Request) string {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "*" {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
 }
 
 func (s *APIServer) handleDeletePrompt(w http.ResponseWriter, r *http.Request, id string) {
@@ -623,7 +1366,7 @@ func (s *APIServer) handleDeletePrompt(w http.ResponseWriter, r *http.Request, i
 // handleTagsWithName handles GET /api/v1/tags/{name}
 func (s *APIServer) handleTagsWithName(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -661,7 +1404,7 @@ func (s *APIServer) handleTagsWithName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
+	s.writeList(w, r, nil, result.Data, result.Message)
 }
 
 // handleSavedSearches handles /api/v1/saved-searches
@@ -672,7 +1415,7 @@ func (s *APIServer) handleSavedSearches(w http.ResponseWriter, r *http.Request)
 	case "POST":
 		s.handleCreateSavedSearch(w, r)
 	default:
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 	}
 }
 
@@ -726,7 +1469,7 @@ func (s *APIServer) handleSavedSearchesWithName(w http.ResponseWriter, r *http.R
 	case "DELETE":
 		s.handleDeleteSavedSearch(w, r, name)
 	default:
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 	}
 }
 
@@ -737,8 +1480,14 @@ func (s *APIServer) handleDeleteSavedSearch(w http.ResponseWriter, r *http.Reque
 
 // handleExecuteSavedSearch handles GET /api/v1/saved-search/{name}
 func (s *APIServer) handleExecuteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	redactor, err := s.requestRedactor(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
 	if r.Method != "GET" {
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -774,13 +1523,13 @@ func (s *APIServer) handleExecuteSavedSearch(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	s.writeResponse(w, result.Data, result.Message, http.StatusOK)
+	s.writeList(w, r, redactor, result.Data, result.Message)
 }
 
 // handlePacks handles GET /api/v1/packs
 func (s *APIServer) handlePacks(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		s.writeError(w, errors.NewAppError(errors.ErrCodeInvalidCommand, "Method not allowed"))
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
 		return
 	}
 
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// newTestServer serves a new library holding prompts
+func newTestServer(t *testing.T, prompts ...*models.Prompt) (*APIServer, *service.Service) {
+	t.Helper()
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range prompts {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create %s: %v", p.ID, err)
+		}
+	}
+	s := NewAPIServer(svc, 0)
+	t.Cleanup(s.cancel)
+	return s, svc
+}
+
+// serve sends a request with an optional JSON body to the server's routes
+func serve(s *APIServer, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRenderDoesNotReadFilesFromRequests(t *testing.T) {
+	s, svc := newTestServer(t,
+		&models.Prompt{ID: "greet", Name: "Greet", Content: "Hello {{name}}"},
+		&models.Prompt{ID: "chart", Name: "Chart", Content: "Describe {{image:assets/chart/chart.png}}"},
+	)
+	secret := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(secret, []byte("top secret"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	asset := filepath.Join(svc.GetBaseDir(), "assets", "chart", "chart.png")
+	if err := os.MkdirAll(filepath.Dir(asset), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(asset, []byte("png"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	placeholder := "{{image:" + secret + "}}"
+	leaked := "dG9wIHNlY3JldA" // "top secret" in base64
+
+	for name, body := range map[string]map[string]interface{}{
+		"variables": {"variables": map[string]string{"name": placeholder}, "format": "json"},
+		"context":   {"context": placeholder, "format": "json"},
+	} {
+		data, _ := json.Marshal(body)
+		rec := serve(s, "POST", "/api/v1/prompts/greet/render", string(data), nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", name, rec.Code, rec.Body)
+			continue
+		}
+		if strings.Contains(rec.Body.String(), leaked) || strings.Contains(rec.Body.String(), "image_url") {
+			t.Errorf("%s: render read a file named in the request: %s", name, rec.Body)
+		}
+	}
+
+	// Images written in the prompt are still embedded
+	rec := serve(s, "POST", "/api/v1/prompts/chart/render", `{"format":"json"}`, nil)
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte("data:image/png;base64,cG5n")) {
+		t.Errorf("chart render = %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestPromptPrefixesOnlyResolveForReads(t *testing.T) {
+	s, svc := newTestServer(t,
+		&models.Prompt{ID: "code", Name: "Code", Content: "Write code"},
+		&models.Prompt{ID: "code-review", Name: "Code Review", Content: "Review this"},
+	)
+
+	if rec := serve(s, "GET", "/api/v1/prompts/code-r", "", nil); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"code-review"`) {
+		t.Errorf("GET by prefix = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(s, "PUT", "/api/v1/prompts/code-r", `{"content":"Changed"}`, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT by prefix = %d, want 404: %s", rec.Code, rec.Body)
+	}
+
+	// Once "code" is gone, a retried change to it must not reach the next
+	// prompt whose ID starts with "code"
+	if err := svc.DeletePrompt("code"); err != nil {
+		t.Fatalf("DeletePrompt: %v", err)
+	}
+	if rec := serve(s, "PUT", "/api/v1/prompts/code", `{"content":"Changed"}`, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("retried PUT = %d, want 404: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(s, "DELETE", "/api/v1/prompts/code", "", nil); rec.Code < 400 {
+		t.Errorf("retried DELETE = %d, want an error: %s", rec.Code, rec.Body)
+	}
+	prompt, err := svc.GetPrompt("code-review")
+	if err != nil {
+		t.Fatalf("code-review was removed by the retry: %v", err)
+	}
+	if prompt.Content != "Review this" {
+		t.Errorf("code-review content = %q, want it unchanged", prompt.Content)
+	}
+}
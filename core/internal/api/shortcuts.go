@@ -0,0 +1,197 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+)
+
+// Shortcut is a ready-made iOS Shortcuts flow described as the ordered list of
+// actions to add in the Shortcuts app, with URLs already pointing at this server
+type Shortcut struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Input       string         `json:"input,omitempty"` // What the shortcut receives, e.g. from the share sheet
+	Steps       []ShortcutStep `json:"steps"`
+}
+
+// ShortcutStep is a single action in a shortcut. Identifier is the Shortcuts
+// action identifier; bracketed values in Params such as [Provided Input] are
+// the magic variables produced by earlier steps.
+type ShortcutStep struct {
+	Action     string            `json:"action"`
+	Identifier string            `json:"identifier"`
+	Params     map[string]string `json:"params,omitempty"`
+	Note       string            `json:"note,omitempty"`
+}
+
+// ShortcutGallery lists the available shortcuts for a server base URL
+type ShortcutGallery struct {
+	BaseURL   string     `json:"base_url"`
+	Warning   string     `json:"warning,omitempty"`
+	Shortcuts []Shortcut `json:"shortcuts"`
+}
+
+// handleShortcuts handles GET /shortcuts and GET /shortcuts/{id}
+func (s *APIServer) handleShortcuts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	baseURL := shortcutBaseURL(r, s.port)
+	gallery := ShortcutGallery{
+		BaseURL:   baseURL,
+		Shortcuts: buildShortcuts(baseURL, r.URL.Query().Get("prompt")),
+	}
+	if isLoopbackURL(baseURL) {
+		gallery.Warning = "Base URL points at this machine's loopback address; pass ?host=<LAN address> so the URLs work from your phone"
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/shortcuts"), "/")
+	if id == "" {
+		s.writeResponse(w, gallery, fmt.Sprintf("Found %d shortcuts", len(gallery.Shortcuts)), http.StatusOK)
+		return
+	}
+
+	for _, shortcut := range gallery.Shortcuts {
+		if shortcut.ID == id {
+			s.writeResponse(w, shortcut, "", http.StatusOK)
+			return
+		}
+	}
+	s.writeError(w, errors.NotFoundError(fmt.Sprintf("Shortcut %q", id)))
+}
+
+// shortcutBaseURL resolves the address a phone should call: ?host= and ?port=
+// override the Host header the request arrived with
+func shortcutBaseURL(r *http.Request, defaultPort int) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	host, port, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host, port = r.Host, ""
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" && r.Header.Get("X-Forwarded-Proto") == "" {
+		port = fmt.Sprintf("%d", defaultPort)
+	}
+
+	query := r.URL.Query()
+	if h := query.Get("host"); h != "" {
+		host = h
+	}
+	if p := query.Get("port"); p != "" {
+		port = p
+	}
+
+	if port == "" {
+		return fmt.Sprintf("%s://%s", scheme, host)
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port))
+}
+
+func isLoopbackURL(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// buildShortcuts generates the gallery for baseURL. When promptID is set the
+// copy and render flows are bound to that prompt instead of asking for one.
+func buildShortcuts(baseURL, promptID string) []Shortcut {
+	promptURL := baseURL + "/api/v1/prompts/"
+	if promptID != "" {
+		promptURL += url.PathEscape(promptID)
+	}
+
+	// Steps that end with the selected prompt's JSON, either fixed or picked via search
+	pickPrompt := []ShortcutStep{
+		{Action: "Ask for Input", Identifier: "is.workflow.actions.ask", Params: map[string]string{"Prompt": "Search prompts", "Input Type": "Text"}},
+		{Action: "URL", Identifier: "is.workflow.actions.url", Params: map[string]string{"URL": baseURL + "/api/v1/search?q=[Provided Input]"}},
+		{Action: "Get Contents of URL", Identifier: "is.workflow.actions.downloadurl", Params: map[string]string{"Method": "GET"}},
+		{Action: "Get Dictionary Value", Identifier: "is.workflow.actions.getvalueforkey", Params: map[string]string{"Key": "data"}},
+		{Action: "Choose from List", Identifier: "is.workflow.actions.choosefromlist", Params: map[string]string{"Prompt": "Choose a prompt"}, Note: "Shows each result; pick one"},
+		{Action: "Get Dictionary Value", Identifier: "is.workflow.actions.getvalueforkey", Params: map[string]string{"Key": "ID"}},
+		{Action: "URL", Identifier: "is.workflow.actions.url", Params: map[string]string{"URL": promptURL + "[Dictionary Value]"}},
+		{Action: "Get Contents of URL", Identifier: "is.workflow.actions.downloadurl", Params: map[string]string{"Method": "GET"}},
+	}
+	if promptID != "" {
+		pickPrompt = []ShortcutStep{
+			{Action: "URL", Identifier: "is.workflow.actions.url", Params: map[string]string{"URL": promptURL}},
+			{Action: "Get Contents of URL", Identifier: "is.workflow.actions.downloadurl", Params: map[string]string{"Method": "GET"}},
+		}
+	}
+	getContent := ShortcutStep{Action: "Get Dictionary Value", Identifier: "is.workflow.actions.getvalueforkey", Params: map[string]string{"Key": "data.Content"}}
+
+	searchCopy := Shortcut{
+		ID:          "search-copy",
+		Name:        "Search & Copy Prompt",
+		Description: "Search the library, pick a prompt and copy its content to the clipboard",
+	}
+	if promptID != "" {
+		searchCopy.ID = "copy"
+		searchCopy.Name = "Copy " + promptID
+		searchCopy.Description = fmt.Sprintf("Copy the content of %s to the clipboard", promptID)
+	}
+	searchCopy.Steps = append(append([]ShortcutStep{}, pickPrompt...),
+		getContent,
+		ShortcutStep{Action: "Copy to Clipboard", Identifier: "is.workflow.actions.setclipboard"},
+		ShortcutStep{Action: "Show Notification", Identifier: "is.workflow.actions.notification", Params: map[string]string{"Body": "Prompt copied"}},
+	)
+
+	render := Shortcut{
+		ID:          "render",
+		Name:        "Render Prompt with Variables",
+		Description: "Fill in each {{variable}} in a prompt, then copy the result",
+	}
+	render.Steps = append(append([]ShortcutStep{}, pickPrompt...),
+		getContent,
+		ShortcutStep{Action: "Set Variable", Identifier: "is.workflow.actions.setvariable", Params: map[string]string{"Variable Name": "Rendered"}},
+		ShortcutStep{Action: "Match Text", Identifier: "is.workflow.actions.text.match", Params: map[string]string{"Pattern": `\{\{\s*(\w+)\s*\}\}`, "Text": "[Rendered]"}},
+		ShortcutStep{Action: "Repeat with Each", Identifier: "is.workflow.actions.repeat.each", Note: "Loops over every placeholder found"},
+		ShortcutStep{Action: "Get Group from Matched Text", Identifier: "is.workflow.actions.text.match.getgroup", Params: map[string]string{"Group Index": "1"}},
+		ShortcutStep{Action: "Ask for Input", Identifier: "is.workflow.actions.ask", Params: map[string]string{"Prompt": "[Text from Group]", "Input Type": "Text"}},
+		ShortcutStep{Action: "Replace Text", Identifier: "is.workflow.actions.text.replace", Params: map[string]string{"Find": "[Repeat Item]", "Replace With": "[Provided Input]", "In": "[Rendered]"}},
+		ShortcutStep{Action: "Set Variable", Identifier: "is.workflow.actions.setvariable", Params: map[string]string{"Variable Name": "Rendered"}},
+		ShortcutStep{Action: "End Repeat", Identifier: "is.workflow.actions.repeat.each", Note: "Closes the loop"},
+		ShortcutStep{Action: "Copy to Clipboard", Identifier: "is.workflow.actions.setclipboard", Params: map[string]string{"Content": "[Rendered]"}},
+	)
+
+	create := Shortcut{
+		ID:          "create-from-share",
+		Name:        "Save as Prompt",
+		Description: "Save text shared from any app as a new prompt",
+		Input:       "Share Sheet: Text",
+		Steps: []ShortcutStep{
+			{Action: "Ask for Input", Identifier: "is.workflow.actions.ask", Params: map[string]string{"Prompt": "Title (leave empty to use the first line)", "Input Type": "Text"}},
+			{Action: "URL", Identifier: "is.workflow.actions.url", Params: map[string]string{"URL": baseURL + "/quick-add?tags=mobile&title=[Provided Input]"}},
+			{Action: "Get Contents of URL", Identifier: "is.workflow.actions.downloadurl", Params: map[string]string{
+				"Method":       "POST",
+				"Request Body": "File",
+				"File":         "[Shortcut Input]",
+			}},
+			{Action: "Get Dictionary Value", Identifier: "is.workflow.actions.getvalueforkey", Params: map[string]string{"Key": "message"}},
+			{Action: "Show Notification", Identifier: "is.workflow.actions.notification", Params: map[string]string{"Body": "[Dictionary Value]"}},
+		},
+	}
+
+	return []Shortcut{searchCopy, render, create}
+}
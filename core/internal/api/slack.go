@@ -0,0 +1,191 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Limits for slash command replies. Slack accepts longer messages, but long
+// prompts are easier to read with `pkt get` than in a chat bubble.
+const (
+	slackMaxResults  = 10
+	slackMaxContent  = 3000
+	slackMaxClockAge = 5 * time.Minute
+)
+
+// SlackMessage is a slash command reply. Ephemeral replies are shown only to
+// the user who ran the command.
+type SlackMessage struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// handleSlackCommand handles POST /slack/command, answering Slack slash
+// commands such as `/pkt search review` and `/pkt get code-review`. Requests
+// are authenticated with the Slack app's signing secret, since Slack cannot
+// send an API key.
+func (s *APIServer) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		s.writeError(w, errors.NewAppError(errors.ErrCodeMethodNotAllowed, "Method not allowed"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		s.writeError(w, errors.ValidationError("Failed to read request body"))
+		return
+	}
+
+	cfg := s.keys.Current()
+	if secret := slackSigningSecret(cfg); secret != "" {
+		if !verifySlackSignature(secret, r.Header, body, time.Now()) {
+			s.writeError(w, errors.NewAppError(errors.ErrCodeUnauthorized, "Invalid Slack signature"))
+			return
+		}
+	} else if len(cfg.Server.APIKeys) > 0 {
+		// Without a signing secret there is no way to tell Slack from anyone else
+		s.writeError(w, errors.NewAppError(errors.ErrCodeUnauthorized, "Slack commands require server.slack_secret_env when API keys are configured"))
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		s.writeError(w, errors.ValidationError("Failed to parse form data"))
+		return
+	}
+
+	reply := s.slackReply(form.Get("command"), form.Get("text"))
+	json.NewEncoder(w).Encode(SlackMessage{ResponseType: "ephemeral", Text: reply})
+}
+
+// slackSigningSecret reads the signing secret from the configured environment variable
+func slackSigningSecret(cfg *config.Config) string {
+	if cfg.Server.SlackSecretEnv == "" {
+		return ""
+	}
+	return os.Getenv(cfg.Server.SlackSecretEnv)
+}
+
+// verifySlackSignature checks the X-Slack-Signature header, an HMAC-SHA256 of
+// "v0:<timestamp>:<body>", and rejects requests older than a few minutes so
+// captured requests cannot be replayed
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) bool {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackMaxClockAge || age < -slackMaxClockAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature")))
+}
+
+// slackReply runs a slash command's text and formats the answer as Slack mrkdwn
+func (s *APIServer) slackReply(command, text string) string {
+	if command == "" {
+		command = "/pkt"
+	}
+	action, arg, _ := strings.Cut(strings.TrimSpace(text), " ")
+	arg = strings.TrimSpace(arg)
+
+	switch action {
+	case "search":
+		return s.slackSearch(command, arg)
+	case "get":
+		if arg == "" {
+			return slackEscape(fmt.Sprintf("Usage: `%s get <prompt-id>`", command))
+		}
+		return s.slackGet(arg)
+	default:
+		return slackEscape(fmt.Sprintf("*Pocket Prompt*\n`%s search <query>` find prompts\n`%s get <prompt-id>` show a prompt", command, command))
+	}
+}
+
+func (s *APIServer) slackSearch(command, query string) string {
+	prompts, err := s.service.SearchPrompts(query)
+	if err != nil {
+		return "Search failed: " + slackEscape(err.Error())
+	}
+	if len(prompts) == 0 {
+		return fmt.Sprintf("No prompts match _%s_", slackEscape(query))
+	}
+
+	var b strings.Builder
+	noun := "prompts"
+	if len(prompts) == 1 {
+		noun = "prompt"
+	}
+	if query == "" {
+		fmt.Fprintf(&b, "%d %s", len(prompts), noun)
+	} else {
+		fmt.Fprintf(&b, "%d %s match _%s_", len(prompts), noun, slackEscape(query))
+	}
+	for i, p := range prompts {
+		if i == slackMaxResults {
+			fmt.Fprintf(&b, "\n…and %d more; narrow the search to see them", len(prompts)-slackMaxResults)
+			break
+		}
+		fmt.Fprintf(&b, "\n• *%s* `%s`", slackEscape(p.Title()), p.ID)
+		if p.Summary != "" {
+			fmt.Fprintf(&b, " — %s", slackEscape(p.Summary))
+		}
+	}
+	b.WriteString(slackEscape(fmt.Sprintf("\nUse `%s get <prompt-id>` to see one", command)))
+	return b.String()
+}
+
+func (s *APIServer) slackGet(id string) string {
+	prompt, err := s.service.GetPrompt(id)
+	if err != nil {
+		return fmt.Sprintf("Prompt `%s` not found", slackEscape(id))
+	}
+	return formatSlackPrompt(prompt)
+}
+
+// formatSlackPrompt shows a prompt's title, description and tags above its
+// content in a code block
+func formatSlackPrompt(p *models.Prompt) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s* `%s` v%s", slackEscape(p.Title()), p.ID, p.Version)
+	if p.Summary != "" {
+		fmt.Fprintf(&b, "\n%s", slackEscape(p.Summary))
+	}
+	if len(p.Tags) > 0 {
+		fmt.Fprintf(&b, "\nTags: %s", slackEscape(strings.Join(p.Tags, ", ")))
+	}
+
+	content := []rune(p.Content)
+	truncated := len(content) > slackMaxContent
+	if truncated {
+		content = content[:slackMaxContent]
+	}
+	fmt.Fprintf(&b, "\n```\n%s\n```", slackEscape(string(content)))
+	if truncated {
+		fmt.Fprintf(&b, "\n_Truncated; run `pkt get %s` for the full prompt_", p.ID)
+	}
+	return b.String()
+}
+
+// slackEscape escapes the characters Slack treats as markup in message text
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// TailscaleListen is the listen address that binds the server to this
+// machine's tailnet address only, so other devices on the tailnet can reach
+// it and nothing else can. It works with Headscale too, which uses the same
+// client.
+const TailscaleListen = "tailscale"
+
+// tailnetRange holds the addresses Tailscale and Headscale hand out by default
+var tailnetRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// tailnetAddress returns this machine's tailnet IPv4 address and its
+// MagicDNS name, which is "" when MagicDNS is off. Without the tailscale
+// command the address is looked for among the network interfaces.
+func tailnetAddress() (ip, dnsName string, err error) {
+	if status, err := readTailscaleStatus(); err == nil {
+		for _, addr := range status.Self.TailscaleIPs {
+			if parsed := net.ParseIP(addr); parsed != nil && parsed.To4() != nil {
+				return addr, strings.TrimSuffix(status.Self.DNSName, "."), nil
+			}
+		}
+	}
+
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil && tailnetRange.Contains(ipNet.IP) {
+				return ipNet.IP.String(), "", nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("no tailnet address found; is Tailscale running and logged in? (try 'tailscale up')")
+}
+
+// tailscaleStatus is the part of 'tailscale status --json' about this machine
+type tailscaleStatus struct {
+	Self struct {
+		DNSName      string   `json:"DNSName"`
+		TailscaleIPs []string `json:"TailscaleIPs"`
+	} `json:"Self"`
+}
+
+// readTailscaleStatus asks the tailscale command about this machine
+func readTailscaleStatus() (*tailscaleStatus, error) {
+	out, err := exec.Command(tailscaleCommand(), "status", "--json").Output()
+	if err != nil {
+		return nil, err
+	}
+	var status tailscaleStatus
+	if err := json.Unmarshal(out, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// tailscaleCommand returns the tailscale command, which the macOS app keeps
+// inside its bundle rather than on the PATH
+func tailscaleCommand() string {
+	if path, err := exec.LookPath("tailscale"); err == nil {
+		return path
+	}
+	const macApp = "/Applications/Tailscale.app/Contents/MacOS/Tailscale"
+	if _, err := os.Stat(macApp); err == nil {
+		return macApp
+	}
+	return "tailscale"
+}
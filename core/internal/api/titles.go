@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// similarTitle is an existing prompt with a title nearly identical to a
+// created one's, with where to fetch it
+type similarTitle struct {
+	service.SimilarTitle
+	URL string `json:"url"`
+}
+
+// writeCreated writes the response to a request that created prompt, naming
+// in similar_titles any prompts in the library or a pack whose title is
+// nearly identical, so the client can suggest extending one of those instead
+func (s *APIServer) writeCreated(w http.ResponseWriter, prompt *models.Prompt, data interface{}, message string) {
+	response := APIResponse{
+		Success:   true,
+		Data:      data,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+	for _, other := range s.service.SimilarTitles(prompt.Title(), prompt.ID) {
+		response.SimilarTitles = append(response.SimilarTitles, similarTitle{
+			SimilarTitle: other,
+			URL:          "/api/v1/prompts/" + url.PathEscape(other.ID),
+		})
+	}
+	s.writeJSON(w, response, http.StatusCreated)
+}
@@ -0,0 +1,265 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/api"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// RunOptions controls how long each benchmark runs
+type RunOptions struct {
+	Iterations  int           // Repetitions of each library benchmark
+	Duration    time.Duration // How long the server benchmark sends requests
+	Concurrency int           // Clients sending requests to the server at once
+}
+
+// Result is the measurement of one benchmark
+type Result struct {
+	Name      string        `json:"name"`
+	Ops       int           `json:"ops"`
+	Errors    int           `json:"errors,omitempty"`
+	Total     time.Duration `json:"total_ns"`
+	PerOp     time.Duration `json:"per_op_ns"`
+	OpsPerSec float64       `json:"ops_per_sec"`
+}
+
+func newResult(name string, ops, errors int, total time.Duration) Result {
+	r := Result{Name: name, Ops: ops, Errors: errors, Total: total}
+	if ops > 0 {
+		r.PerOp = total / time.Duration(ops)
+	}
+	if total > 0 {
+		r.OpsPerSec = float64(ops) / total.Seconds()
+	}
+	return r
+}
+
+// Run measures the library at dir: opening and listing it, parsing every
+// prompt file as a cold cache does, searching, rendering, and serving
+// requests over the URL server. The library is opened read-only, so
+// renders are not counted as uses.
+func Run(dir string, opts RunOptions) ([]Result, error) {
+	if opts.Iterations <= 0 {
+		opts.Iterations = 20
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = 3 * time.Second
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+
+	var results []Result
+
+	open, err := timeOps("open + list", opts.Iterations, func(int) error {
+		svc, err := service.OpenLibrary(dir)
+		if err != nil {
+			return err
+		}
+		_, err = svc.ListPrompts()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, open)
+
+	files, err := promptFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no prompts in %s; create some with 'pkt bench generate'", dir)
+	}
+	parse, err := timeOps("parse files", len(files)*opts.Iterations, func(i int) error {
+		content, err := os.ReadFile(files[i%len(files)])
+		if err != nil {
+			return err
+		}
+		_, err = storage.ParsePrompt(content)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, parse)
+
+	svc, err := service.OpenLibrary(dir)
+	if err != nil {
+		return nil, err
+	}
+	svc.SetReadOnly(true)
+	prompts, err := svc.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no approved prompts in %s", dir)
+	}
+	queries := searchQueries(prompts)
+	expression, err := models.ParseBooleanExpression(fmt.Sprintf("%s OR (%s AND NOT %s)", tags[0], tags[1], tags[2]))
+	if err != nil {
+		return nil, err
+	}
+
+	benchmarks := []struct {
+		name string
+		ops  int
+		fn   func(i int) error
+	}{
+		{"list", opts.Iterations, func(int) error {
+			_, err := svc.ListPrompts()
+			return err
+		}},
+		{"fuzzy search", opts.Iterations, func(i int) error {
+			_, err := svc.SearchPrompts(queries[i%len(queries)])
+			return err
+		}},
+		{"filtered search", opts.Iterations, func(i int) error {
+			_, err := svc.SearchPrompts("tag:" + tags[i%len(tags)] + " " + queries[i%len(queries)])
+			return err
+		}},
+		{"boolean search", opts.Iterations, func(int) error {
+			_, err := svc.SearchPromptsByBooleanExpression(expression)
+			return err
+		}},
+		{"get", len(prompts), func(i int) error {
+			_, err := svc.GetPrompt(prompts[i].ID)
+			return err
+		}},
+		{"render", len(prompts), func(i int) error {
+			_, err := svc.RenderPrompt(prompts[i].ID, service.RenderOptions{
+				Variables: map[string]interface{}{"topic": "benchmarks", "audience": "maintainers"},
+			})
+			return err
+		}},
+	}
+	for _, b := range benchmarks {
+		result, err := timeOps(b.name, b.ops, b.fn)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return append(results, runServer(svc, prompts, queries, opts)), nil
+}
+
+// timeOps runs fn ops times and times it, stopping at the first error
+func timeOps(name string, ops int, fn func(i int) error) (Result, error) {
+	start := time.Now()
+	for i := 0; i < ops; i++ {
+		if err := fn(i); err != nil {
+			return Result{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return newResult(name, ops, 0, time.Since(start)), nil
+}
+
+// runServer sends list, get and search requests to the URL server from
+// opts.Concurrency clients for opts.Duration. Requests that fail or get an
+// error status, such as 401 from a library with API keys, are counted as
+// errors.
+func runServer(svc *service.Service, prompts []*models.Prompt, queries []string, opts RunOptions) Result {
+	// The server logs every request, which would measure the terminal
+	logOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(logOutput)
+
+	srv := httptest.NewServer(api.NewAPIServer(svc, 0).Handler())
+	defer srv.Close()
+	client := srv.Client()
+	client.Transport.(*http.Transport).MaxIdleConnsPerHost = opts.Concurrency
+
+	paths := make([]string, 0, 3*len(queries))
+	for i, query := range queries {
+		paths = append(paths,
+			"/api/v1/prompts",
+			"/api/v1/prompts/"+url.PathEscape(prompts[i%len(prompts)].ID),
+			"/api/v1/search?q="+url.QueryEscape(query),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Duration)
+	defer cancel()
+	var ops, errors atomic.Int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	for c := 0; c < opts.Concurrency; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for i := c; ctx.Err() == nil; i += opts.Concurrency {
+				req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+paths[i%len(paths)], nil)
+				resp, err := client.Do(req)
+				if ctx.Err() != nil {
+					return // Cut off by the deadline, not a failure
+				}
+				ops.Add(1)
+				if err != nil {
+					errors.Add(1)
+					continue
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode >= 400 {
+					errors.Add(1)
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	return newResult(fmt.Sprintf("server (%d clients)", opts.Concurrency), int(ops.Load()), int(errors.Load()), time.Since(start))
+}
+
+// promptFiles lists the prompt files of the library at dir
+func promptFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(filepath.Join(dir, "prompts"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".md") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return files, err
+}
+
+// searchQueries picks words from prompt names to search for, so searches
+// match something in any library
+func searchQueries(prompts []*models.Prompt) []string {
+	var queries []string
+	for _, p := range prompts {
+		if fields := strings.Fields(p.Name); len(fields) > 0 {
+			queries = append(queries, strings.ToLower(fields[len(fields)/2]))
+		}
+		if len(queries) == 50 {
+			break
+		}
+	}
+	if len(queries) == 0 {
+		queries = append(queries, prompts[0].ID)
+	}
+	return queries
+}
@@ -0,0 +1,81 @@
+package bench
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestGenerateIsReproducible(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "bench-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	first, second := filepath.Join(tmpDir, "a"), filepath.Join(tmpDir, "b")
+	for _, dir := range []string{first, second} {
+		if err := Generate(dir, GenerateOptions{Count: 40, Seed: 7}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Generate(first, GenerateOptions{Count: 40, Seed: 7}); err == nil {
+		t.Error("Generate wrote into a library that is not empty")
+	}
+
+	svc, err := service.OpenLibrary(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompts, err := svc.ListPrompts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 40 {
+		t.Fatalf("listed %d prompts, want 40", len(prompts))
+	}
+
+	files, err := promptFiles(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		rel, _ := filepath.Rel(first, file)
+		a, _ := os.ReadFile(file)
+		b, err := os.ReadFile(filepath.Join(second, rel))
+		if err != nil || string(a) != string(b) {
+			t.Errorf("%s differs between libraries generated with the same seed", rel)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "bench-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := Generate(tmpDir, GenerateOptions{Count: 30, Seed: 1}); err != nil {
+		t.Fatal(err)
+	}
+	results, err := Run(tmpDir, RunOptions{Iterations: 2, Duration: 200 * time.Millisecond, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range results {
+		if r.Ops == 0 {
+			t.Errorf("%s ran no operations", r.Name)
+		}
+		if r.Errors > 0 {
+			t.Errorf("%s had %d errors", r.Name, r.Errors)
+		}
+	}
+	if last := results[len(results)-1]; last.Name != "server (2 clients)" {
+		t.Errorf("last benchmark = %q, want the server", last.Name)
+	}
+}
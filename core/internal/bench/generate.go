@@ -0,0 +1,129 @@
+// Package bench generates synthetic prompt libraries and measures how fast
+// the library, search, rendering and the URL server are on them, for
+// 'pkt bench'. Generated libraries depend only on the count and seed, so
+// runs on different machines or commits compare like with like.
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// GenerateOptions sizes a synthetic library
+type GenerateOptions struct {
+	Count int   // Number of prompts
+	Seed  int64 // Seed for the random content; the same seed gives the same library
+}
+
+// words are the vocabulary of generated titles and content
+var words = strings.Fields(`
+	analyze review summarize draft explain translate classify extract compare
+	rewrite outline critique brainstorm plan estimate debug refactor document
+	customer report email meeting proposal contract invoice research paper
+	article essay code function query schema dataset model pipeline service
+	release roadmap incident postmortem interview feedback survey campaign
+	budget forecast strategy policy onboarding tutorial guide checklist
+	concise formal friendly detailed technical persuasive neutral structured
+	security performance accessibility privacy compliance quality testing
+	product market sales support legal finance design engineering operations
+	résumé café naïve façade jalapeño`)
+
+// tags are the pool generated prompts draw their tags from
+var tags = strings.Fields(`
+	ai writing code review email research marketing sales support legal
+	finance design engineering ops data analytics planning meetings hiring
+	onboarding docs testing security performance product strategy draft
+	archived client-acme client-globex personal team shared urgent`)
+
+// Generate writes a synthetic library of opts.Count prompts to dir, which
+// must not exist or be empty. About a fifth of the prompts sit in nested
+// folders and a third declare variables.
+func Generate(dir string, opts GenerateOptions) error {
+	if opts.Count <= 0 {
+		return fmt.Errorf("count must be positive")
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s is not empty; generate into a new directory", dir)
+	}
+
+	store, err := storage.NewStorage(dir)
+	if err != nil {
+		return err
+	}
+	if err := store.InitLibrary(); err != nil {
+		return err
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	created := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < opts.Count; i++ {
+		prompt := generatePrompt(rng, i, created.Add(time.Duration(i)*time.Minute))
+		if err := store.SavePrompt(prompt); err != nil {
+			return fmt.Errorf("failed to write %s: %w", prompt.FilePath, err)
+		}
+	}
+	return nil
+}
+
+// generatePrompt makes the i-th prompt of a synthetic library
+func generatePrompt(rng *rand.Rand, i int, created time.Time) *models.Prompt {
+	id := fmt.Sprintf("bench-%05d", i)
+	prompt := &models.Prompt{
+		ID:        id,
+		Version:   "1.0.0",
+		Name:      capitalize(sentence(rng, 3+rng.Intn(4))),
+		Summary:   capitalize(sentence(rng, 8+rng.Intn(8))) + ".",
+		CreatedAt: created,
+		UpdatedAt: created,
+		FilePath:  filepath.Join("prompts", id+".md"),
+	}
+	if rng.Intn(5) == 0 {
+		prompt.FilePath = filepath.Join("prompts", fmt.Sprintf("group-%02d", rng.Intn(20)), id+".md")
+	}
+
+	seen := map[string]bool{}
+	for n := 1 + rng.Intn(4); len(prompt.Tags) < n; {
+		if tag := tags[rng.Intn(len(tags))]; !seen[tag] {
+			seen[tag] = true
+			prompt.Tags = append(prompt.Tags, tag)
+		}
+	}
+
+	var content strings.Builder
+	for p := 2 + rng.Intn(6); p > 0; p-- {
+		content.WriteString(capitalize(sentence(rng, 20+rng.Intn(40))))
+		content.WriteString(".\n\n")
+	}
+	if rng.Intn(3) == 0 {
+		prompt.Variables = []models.Variable{
+			{Name: "topic", Required: true},
+			{Name: "audience", Default: "a general audience"},
+		}
+		content.WriteString("Write about {{topic}} for {{audience}}.\n")
+	}
+	prompt.Content = content.String()
+	return prompt
+}
+
+// sentence returns n random words
+func sentence(rng *rand.Rand, n int) string {
+	picked := make([]string, n)
+	for i := range picked {
+		picked[i] = words[rng.Intn(len(words))]
+	}
+	return strings.Join(picked, " ")
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,206 @@
+// Package bot serves prompt search and retrieval in team chat. Discord and
+// Telegram adapters turn chat messages into unified commands and send the
+// formatted results back.
+package bot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// Supported chat platforms
+const (
+	PlatformDiscord  = "discord"
+	PlatformTelegram = "telegram"
+)
+
+// maxResults caps how many search results a reply lists
+const maxResults = 10
+
+// Run starts the bot for platform and serves commands until ctx is done. The
+// token is read from the environment variable named in the library's bot config.
+func Run(ctx context.Context, platform string, svc *service.Service) error {
+	cfg := svc.Settings().Bot
+	executor := commands.NewCommandExecutor(svc)
+
+	switch platform {
+	case PlatformDiscord:
+		token, err := botToken(cfg.DiscordTokenVar(), "bot.discord_token_env")
+		if err != nil {
+			return err
+		}
+		return NewDiscord(token, cfg.Prefix(), executor).Run(ctx)
+	case PlatformTelegram:
+		token, err := botToken(cfg.TelegramTokenVar(), "bot.telegram_token_env")
+		if err != nil {
+			return err
+		}
+		return NewTelegram(token, executor).Run(ctx)
+	default:
+		return fmt.Errorf("unknown bot platform %q (expected %s or %s)", platform, PlatformDiscord, PlatformTelegram)
+	}
+}
+
+func botToken(envVar, setting string) (string, error) {
+	if token := strings.TrimSpace(os.Getenv(envVar)); token != "" {
+		return token, nil
+	}
+	return "", fmt.Errorf("no bot token: set $%s, or name another variable with %s in .pocket-prompt/config.json", envVar, setting)
+}
+
+// markup formats replies for a platform's message syntax. bold wraps text that
+// is already escaped; code and block take raw text.
+type markup interface {
+	bold(s string) string
+	code(s string) string  // Inline code
+	block(s string) string // Preformatted block
+	escape(s string) string
+	maxLength() int // Longest message the platform accepts
+}
+
+// Handler answers chat commands by running them through the unified command layer
+type Handler struct {
+	executor *commands.CommandExecutor
+	markup   markup
+	prefix   string // How commands are typed on the platform, e.g. "/" or "!pkt "
+}
+
+// Reply runs action ("search", "get", "copy" or "help") with its argument and
+// returns the formatted answer
+func (h *Handler) Reply(ctx context.Context, action, arg string) string {
+	arg = strings.TrimSpace(arg)
+	switch action {
+	case "search":
+		if arg == "" {
+			return h.usage("search <query>")
+		}
+		return h.search(ctx, arg)
+	case "get":
+		if arg == "" {
+			return h.usage("get <prompt-id>")
+		}
+		return h.get(ctx, arg)
+	case "copy":
+		if arg == "" {
+			return h.usage("copy <prompt-id>")
+		}
+		return h.copy(ctx, arg)
+	default:
+		return h.help()
+	}
+}
+
+func (h *Handler) help() string {
+	m := h.markup
+	return strings.Join([]string{
+		m.bold("Pocket Prompt"),
+		m.code(h.prefix+"search <query>") + m.escape(" find prompts"),
+		m.code(h.prefix+"get <prompt-id>") + m.escape(" show a prompt with its details"),
+		m.code(h.prefix+"copy <prompt-id>") + m.escape(" show just the rendered prompt, ready to paste"),
+	}, "\n")
+}
+
+func (h *Handler) usage(command string) string {
+	return h.markup.escape("Usage: ") + h.markup.code(h.prefix+command)
+}
+
+// run executes a unified command, returning its data or a message explaining the failure
+func (h *Handler) run(ctx context.Context, name string, params map[string]interface{}) (interface{}, string) {
+	result, err := h.executor.Execute(ctx, name, params)
+	if err != nil {
+		return nil, h.markup.escape(err.Error())
+	}
+	if !result.Success {
+		message := "Command failed"
+		if result.Error != nil {
+			message = result.Error.Message
+		}
+		return nil, h.markup.escape(message)
+	}
+	return result.Data, ""
+}
+
+func (h *Handler) search(ctx context.Context, query string) string {
+	data, failure := h.run(ctx, "search", map[string]interface{}{"query": query})
+	if failure != "" {
+		return failure
+	}
+	prompts, _ := data.([]*models.Prompt)
+
+	m := h.markup
+	if len(prompts) == 0 {
+		return m.escape(fmt.Sprintf("No prompts match %q", query))
+	}
+
+	noun := "prompts"
+	if len(prompts) == 1 {
+		noun = "prompt"
+	}
+	reply := m.escape(fmt.Sprintf("%d %s match %q", len(prompts), noun, query))
+	for i, p := range prompts {
+		line := "• " + m.bold(m.escape(p.Title())) + " " + m.code(p.ID)
+		if p.Summary != "" {
+			line += m.escape(" — " + p.Summary)
+		}
+		// Stop at whole lines so markup is never cut in half
+		if i == maxResults || len(reply)+len(line)+100 > m.maxLength() {
+			reply += "\n" + m.escape(fmt.Sprintf("…and %d more; narrow the search to see them", len(prompts)-i))
+			break
+		}
+		reply += "\n" + line
+	}
+	return reply
+}
+
+func (h *Handler) get(ctx context.Context, id string) string {
+	data, failure := h.run(ctx, "get", map[string]interface{}{"id": id})
+	if failure != "" {
+		return failure
+	}
+	p, ok := data.(*models.Prompt)
+	if !ok {
+		return h.markup.escape("Prompt " + id + " not found")
+	}
+
+	m := h.markup
+	header := m.bold(m.escape(p.Title())) + " " + m.code(p.ID) + m.escape(" v"+p.Version)
+	if p.Summary != "" {
+		header += "\n" + m.escape(p.Summary)
+	}
+	if len(p.Tags) > 0 {
+		header += "\n" + m.escape("Tags: "+strings.Join(p.Tags, ", "))
+	}
+	return header + "\n" + h.contentBlock(p.Content, len(header)+1)
+}
+
+func (h *Handler) copy(ctx context.Context, id string) string {
+	data, failure := h.run(ctx, "render", map[string]interface{}{"id": id})
+	if failure != "" {
+		return failure
+	}
+	rendered, _ := data.(string)
+	return h.contentBlock(rendered, 0)
+}
+
+// contentBlock wraps content in a preformatted block, cutting it short when the
+// message would otherwise exceed the platform limit after used characters
+func (h *Handler) contentBlock(content string, used int) string {
+	const note = "\n(truncated; use pkt get for the full prompt)"
+	budget := h.markup.maxLength() - used
+
+	if block := h.markup.block(content); len(block) <= budget {
+		return block
+	}
+	budget -= len(note)
+	runes := []rune(content)
+	for len(runes) > 0 && len(h.markup.block(string(runes))) > budget {
+		runes = runes[:len(runes)*9/10]
+	}
+	return h.markup.block(string(runes)) + h.markup.escape(note)
+}
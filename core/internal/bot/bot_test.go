@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestTelegramAnswersCommands(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := service.OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "code-review", Name: "Code review", Content: "Review <this> diff"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A fake Bot API that delivers two commands, then records the replies
+	var replies []string
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			w.Write([]byte(`{"ok": true, "result": {"username": "pocket_bot"}}`))
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			polls++
+			if polls > 1 {
+				cancel()
+				w.Write([]byte(`{"ok": true, "result": []}`))
+				return
+			}
+			w.Write([]byte(`{"ok": true, "result": [
+				{"update_id": 1, "message": {"chat": {"id": 7}, "text": "/search@pocket_bot review"}},
+				{"update_id": 2, "message": {"chat": {"id": 7}, "text": "/get code-review"}}
+			]}`))
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			var body struct {
+				Text string `json:"text"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			replies = append(replies, body.Text)
+			w.Write([]byte(`{"ok": true, "result": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	bot := NewTelegram("token", commands.NewCommandExecutor(svc))
+	bot.apiURL = server.URL
+	if err := bot.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(replies) != 2 {
+		t.Fatalf("got %d replies, want 2: %q", len(replies), replies)
+	}
+	if !strings.Contains(replies[0], "<code>code-review</code>") {
+		t.Errorf("search reply = %q, want the matching prompt ID", replies[0])
+	}
+	if !strings.Contains(replies[1], "<pre>Review &lt;this&gt; diff</pre>") {
+		t.Errorf("get reply = %q, want escaped content in a pre block", replies[1])
+	}
+}
+
+func TestParsePrefixedCommand(t *testing.T) {
+	tests := []struct {
+		text, action, arg string
+		ok                bool
+	}{
+		{"!pkt search code review", "search", "code review", true},
+		{"!pkt", "", "", true},
+		{"!pktsearch x", "", "", false},
+		{"hello !pkt get x", "", "", false},
+	}
+	for _, tt := range tests {
+		action, arg, ok := parsePrefixedCommand(tt.text, "!pkt")
+		if action != tt.action || arg != tt.arg || ok != tt.ok {
+			t.Errorf("parsePrefixedCommand(%q) = %q, %q, %v", tt.text, action, arg, ok)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+)
+
+// Discord endpoints: the REST API and the gateway that streams messages
+const (
+	DiscordAPI     = "https://discord.com/api/v10"
+	DiscordGateway = "wss://gateway.discord.gg/?v=10&encoding=json"
+)
+
+// Gateway opcodes used by the bot
+const (
+	discordDispatch       = 0
+	discordHeartbeat      = 1
+	discordIdentify       = 2
+	discordReconnect      = 7
+	discordInvalidSession = 9
+	discordHello          = 10
+)
+
+// discordIntents subscribes to guild and direct messages along with their
+// text. Message content is a privileged intent that must also be enabled for
+// the bot in the Discord developer portal.
+const discordIntents = 1<<9 | 1<<12 | 1<<15
+
+// errDiscordAuth means Discord rejected the token, so reconnecting is pointless
+var errDiscordAuth = errors.New("discord rejected the bot token")
+
+// Discord serves "!pkt search", "!pkt get" and "!pkt copy" in channels the bot
+// can read, over a gateway connection that needs no public address
+type Discord struct {
+	token      string
+	prefix     string
+	apiURL     string
+	gatewayURL string
+	client     *http.Client
+	handler    *Handler
+}
+
+// NewDiscord creates a Discord bot for token that answers messages starting with prefix
+func NewDiscord(token, prefix string, executor *commands.CommandExecutor) *Discord {
+	return &Discord{
+		token:      token,
+		prefix:     prefix,
+		apiURL:     DiscordAPI,
+		gatewayURL: DiscordGateway,
+		client:     &http.Client{Timeout: 15 * time.Second},
+		handler:    &Handler{executor: executor, markup: discordMarkup{}, prefix: prefix + " "},
+	}
+}
+
+type discordPayload struct {
+	Op       int             `json:"op"`
+	Data     json.RawMessage `json:"d,omitempty"`
+	Sequence *int64          `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+}
+
+type discordMessage struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	Content   string `json:"content"`
+	Author    struct {
+		Bot bool `json:"bot"`
+	} `json:"author"`
+}
+
+// Run connects to the gateway and answers commands until ctx is done,
+// reconnecting whenever the connection drops
+func (d *Discord) Run(ctx context.Context) error {
+	var me struct {
+		Username string `json:"username"`
+	}
+	if err := d.rest(ctx, "GET", "/users/@me", nil, &me); err != nil {
+		return fmt.Errorf("failed to connect to Discord: %w", err)
+	}
+	log.Printf("Discord bot %s is listening for %q", me.Username, d.prefix)
+
+	for {
+		err := d.session(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, errDiscordAuth) {
+			return err
+		}
+		log.Printf("Discord connection lost: %v; reconnecting", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// session runs one gateway connection: identify, keep the heartbeat going and
+// handle messages until the connection ends
+func (d *Discord) session(ctx context.Context) error {
+	ws, err := websocket.Dial(d.gatewayURL, "", "https://discord.com")
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		ws.Close()
+	}()
+
+	var sendMu sync.Mutex
+	send := func(op int, data interface{}) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return websocket.JSON.Send(ws, map[string]interface{}{"op": op, "d": data})
+	}
+
+	var hello discordPayload
+	if err := websocket.JSON.Receive(ws, &hello); err != nil {
+		return err
+	}
+	var helloData struct {
+		HeartbeatInterval int `json:"heartbeat_interval"`
+	}
+	if hello.Op != discordHello || json.Unmarshal(hello.Data, &helloData) != nil || helloData.HeartbeatInterval <= 0 {
+		return fmt.Errorf("unexpected gateway greeting (op %d)", hello.Op)
+	}
+
+	// The last sequence number is echoed in every heartbeat
+	var seqMu sync.Mutex
+	var seq *int64
+	heartbeat := func() error {
+		seqMu.Lock()
+		last := seq
+		seqMu.Unlock()
+		return send(discordHeartbeat, last)
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(helloData.HeartbeatInterval) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := heartbeat(); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	identify := map[string]interface{}{
+		"token":   d.token,
+		"intents": discordIntents,
+		"properties": map[string]string{
+			"os":      runtime.GOOS,
+			"browser": "pocket-prompt",
+			"device":  "pocket-prompt",
+		},
+	}
+	if err := send(discordIdentify, identify); err != nil {
+		return err
+	}
+
+	for {
+		var payload discordPayload
+		if err := websocket.JSON.Receive(ws, &payload); err != nil {
+			return err
+		}
+		if payload.Sequence != nil {
+			seqMu.Lock()
+			seq = payload.Sequence
+			seqMu.Unlock()
+		}
+
+		switch payload.Op {
+		case discordHeartbeat:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		case discordReconnect:
+			return errors.New("gateway asked to reconnect")
+		case discordInvalidSession:
+			return errors.New("gateway session invalidated")
+		case discordDispatch:
+			if payload.Type != "MESSAGE_CREATE" {
+				continue
+			}
+			var message discordMessage
+			if err := json.Unmarshal(payload.Data, &message); err != nil || message.Author.Bot {
+				continue
+			}
+			action, arg, ok := parsePrefixedCommand(message.Content, d.prefix)
+			if !ok {
+				continue
+			}
+			if err := d.reply(ctx, message, d.handler.Reply(ctx, action, arg)); err != nil {
+				log.Printf("Discord reply failed: %v", err)
+			}
+		}
+	}
+}
+
+// parsePrefixedCommand splits "!pkt get my-prompt" into its action and argument.
+// A bare prefix asks for help.
+func parsePrefixedCommand(text, prefix string) (action, arg string, ok bool) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(text), prefix)
+	if !found || (rest != "" && rest[0] != ' ') {
+		return "", "", false
+	}
+	action, arg, _ = strings.Cut(strings.TrimSpace(rest), " ")
+	return strings.ToLower(action), arg, true
+}
+
+// reply answers message in its channel without pinging anyone mentioned in prompt text
+func (d *Discord) reply(ctx context.Context, message discordMessage, text string) error {
+	body := map[string]interface{}{
+		"content":           text,
+		"message_reference": map[string]string{"message_id": message.ID},
+		"allowed_mentions":  map[string]interface{}{"parse": []string{}},
+	}
+	return d.rest(ctx, "POST", "/channels/"+message.ChannelID+"/messages", body, nil)
+}
+
+// rest calls the Discord REST API, decoding the response into out when given
+func (d *Discord) rest(ctx context.Context, method, path string, body, out interface{}) error {
+	reader := bytes.NewReader(nil)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, d.apiURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bot "+d.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DiscordBot (https://github.com/dpshade/pocket-prompt, 1.0)")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errDiscordAuth
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: HTTP %d %s", method, path, resp.StatusCode, apiErr.Message)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// discordMarkup formats replies with Discord's Markdown
+type discordMarkup struct{}
+
+func (discordMarkup) bold(s string) string { return "**" + s + "**" }
+func (discordMarkup) code(s string) string { return "`" + strings.ReplaceAll(s, "`", "'") + "`" }
+
+// block keeps triple backticks in the content from closing the block early
+func (discordMarkup) block(s string) string {
+	return "```\n" + strings.ReplaceAll(s, "```", "`\u200b``") + "\n```"
+}
+
+func (discordMarkup) escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`, ">", `\>`).Replace(s)
+}
+
+func (discordMarkup) maxLength() int { return 2000 }
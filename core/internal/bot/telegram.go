@@ -0,0 +1,184 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+)
+
+// TelegramAPI is the Bot API endpoint
+const TelegramAPI = "https://api.telegram.org"
+
+// telegramPollTimeout is how long each getUpdates call waits for new messages
+const telegramPollTimeout = 30 * time.Second
+
+// Telegram serves commands such as /search and /get over the Bot API using
+// long polling, so it needs no public address
+type Telegram struct {
+	token   string
+	apiURL  string
+	client  *http.Client
+	handler *Handler
+}
+
+// NewTelegram creates a Telegram bot for token
+func NewTelegram(token string, executor *commands.CommandExecutor) *Telegram {
+	return &Telegram{
+		token:   token,
+		apiURL:  TelegramAPI,
+		client:  &http.Client{Timeout: telegramPollTimeout + 10*time.Second},
+		handler: &Handler{executor: executor, markup: telegramMarkup{}, prefix: "/"},
+	}
+}
+
+type telegramUpdate struct {
+	UpdateID int `json:"update_id"`
+	Message  *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	ErrorCode   int             `json:"error_code"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// Run polls for messages and answers commands until ctx is done
+func (t *Telegram) Run(ctx context.Context) error {
+	var me struct {
+		Username string `json:"username"`
+	}
+	if err := t.call(ctx, "getMe", nil, &me); err != nil {
+		return fmt.Errorf("failed to connect to Telegram: %w", err)
+	}
+	log.Printf("Telegram bot @%s is listening", me.Username)
+
+	offset := 0
+	for {
+		var updates []telegramUpdate
+		params := url.Values{
+			"offset":  {fmt.Sprint(offset)},
+			"timeout": {fmt.Sprint(int(telegramPollTimeout.Seconds()))},
+		}
+		if err := t.call(ctx, "getUpdates", params, &updates); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Telegram polling failed: %v", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil {
+				continue
+			}
+			action, arg, ok := parseTelegramCommand(update.Message.Text)
+			if !ok {
+				continue
+			}
+			reply := t.handler.Reply(ctx, action, arg)
+			if err := t.send(ctx, update.Message.Chat.ID, reply); err != nil {
+				log.Printf("Telegram reply failed: %v", err)
+			}
+		}
+	}
+}
+
+// parseTelegramCommand splits "/get my-prompt" into its action and argument.
+// Commands in groups may be addressed to the bot, as in "/get@pocket_bot id".
+func parseTelegramCommand(text string) (action, arg string, ok bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+	command, arg, _ := strings.Cut(text[1:], " ")
+	command, _, _ = strings.Cut(command, "@")
+	return strings.ToLower(command), arg, true
+}
+
+func (t *Telegram) send(ctx context.Context, chatID int64, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":    chatID,
+		"text":       text,
+		"parse_mode": "HTML",
+	})
+	if err != nil {
+		return err
+	}
+	return t.post(ctx, "sendMessage", body)
+}
+
+// call invokes a Bot API method with query parameters and decodes its result into out
+func (t *Telegram) call(ctx context.Context, method string, params url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", t.methodURL(method)+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	return t.do(req, out)
+}
+
+func (t *Telegram) post(ctx context.Context, method string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", t.methodURL(method), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return t.do(req, nil)
+}
+
+func (t *Telegram) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", t.apiURL, t.token, method)
+}
+
+func (t *Telegram) do(req *http.Request, out interface{}) error {
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// The request URL contains the token, so report only the method
+		if ue, ok := err.(*url.Error); ok {
+			err = ue.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("unexpected response (HTTP %d)", resp.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("%s (error %d)", result.Description, result.ErrorCode)
+	}
+	if out != nil {
+		return json.Unmarshal(result.Result, out)
+	}
+	return nil
+}
+
+// telegramMarkup formats replies with Telegram's HTML parse mode
+type telegramMarkup struct{}
+
+func (telegramMarkup) bold(s string) string   { return "<b>" + s + "</b>" }
+func (telegramMarkup) code(s string) string   { return "<code>" + html.EscapeString(s) + "</code>" }
+func (telegramMarkup) block(s string) string  { return "<pre>" + html.EscapeString(s) + "</pre>" }
+func (telegramMarkup) escape(s string) string { return html.EscapeString(s) }
+func (telegramMarkup) maxLength() int         { return 4096 }
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// analyticsReportLimit is how many prompts, searches and commands the text
+// report lists in each section
+const analyticsReportLimit = 10
+
+// handleAnalytics reports the usage analytics recorded on this device, and
+// opts in and out of recording them or purges them
+func (c *CLI) handleAnalytics(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return c.analyticsReport(args)
+	}
+	switch args[0] {
+	case "report":
+		return c.analyticsReport(args[1:])
+	case "status":
+		return c.analyticsStatus()
+	case "enable":
+		scopes, err := service.AnalyticsScopes(args[1:])
+		if err != nil {
+			return err
+		}
+		if err := c.service.EnableAnalytics(scopes); err != nil {
+			return fmt.Errorf("failed to enable analytics: %w", err)
+		}
+		fmt.Printf("Recording %s in %s\n", strings.Join(scopes, ", "), c.service.AnalyticsDir())
+		return nil
+	case "disable":
+		scopes, err := service.AnalyticsScopes(args[1:])
+		if err != nil {
+			return err
+		}
+		if err := c.service.DisableAnalytics(scopes); err != nil {
+			return fmt.Errorf("failed to disable analytics: %w", err)
+		}
+		fmt.Printf("Stopped recording %s; 'pkt analytics purge' deletes what was recorded\n", strings.Join(scopes, ", "))
+		return nil
+	case "purge":
+		purged, err := c.service.PurgeAnalytics()
+		if err != nil {
+			return fmt.Errorf("failed to purge analytics: %w", err)
+		}
+		fmt.Printf("Deleted %d recorded events\n", purged)
+		return nil
+	default:
+		return fmt.Errorf("unknown analytics subcommand %q (expected report, status, enable, disable or purge)", args[0])
+	}
+}
+
+// analyticsStatus prints which scopes are recorded, where and how much
+func (c *CLI) analyticsStatus() error {
+	status, err := c.service.AnalyticsStatus()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Data: %s\n", status.Dir)
+	for _, scope := range storage.AnalyticsScopes {
+		if since, ok := status.Enabled[scope]; ok {
+			fmt.Printf("  %-9s on since %s\n", scope, i18n.FormatDate(since))
+		} else {
+			fmt.Printf("  %-9s %s\n", scope, c.out.muted("off"))
+		}
+	}
+	if status.Events == 0 {
+		fmt.Println("No events recorded")
+	} else {
+		fmt.Printf("%d events recorded since %s\n", status.Events, i18n.FormatDate(status.Oldest))
+	}
+	return nil
+}
+
+// analyticsReport prints the prompts, searches and commands used over a
+// period, the last 30 days unless --since says otherwise
+func (c *CLI) analyticsReport(args []string) error {
+	var format string
+	now := time.Now()
+	since := now.Add(-service.DefaultAnalyticsPeriod)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case "--since":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--since requires a date")
+			}
+			t, err := parseChangelogDate(args[i+1])
+			if err != nil {
+				return err
+			}
+			since = t
+			i++
+		default:
+			return fmt.Errorf("unknown analytics option: %s", args[i])
+		}
+	}
+	format = c.outputFormat(format, "json")
+
+	report, err := c.service.AnalyticsReport(since, now)
+	if err != nil {
+		return fmt.Errorf("failed to build analytics report: %w", err)
+	}
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "", "text":
+	default:
+		return fmt.Errorf("unsupported analytics format %q (expected text or json)", format)
+	}
+
+	if len(report.Enabled) == 0 {
+		fmt.Println("Analytics are off. Nothing is recorded until you opt in, for example:")
+		fmt.Println("  pkt analytics enable uses searches")
+		return nil
+	}
+	fmt.Println(c.out.header(fmt.Sprintf("Since %s (recording %s)", i18n.FormatDate(since), strings.Join(report.Enabled, ", "))))
+
+	if len(report.Prompts) > 0 {
+		fmt.Printf("\nMost used prompts (%d uses)\n", report.Uses)
+		for _, p := range report.Prompts[:min(len(report.Prompts), analyticsReportLimit)] {
+			title := p.Title
+			if title == "" {
+				title = c.out.muted("(removed)")
+			}
+			fmt.Printf("  %4d  %s  %s  %s\n", p.Uses, c.out.id(p.ID), title, c.out.muted("last "+i18n.FormatDate(p.LastUsed)))
+		}
+	}
+	if len(report.Searches) > 0 {
+		fmt.Println("\nSearches")
+		for _, search := range report.Searches[:min(len(report.Searches), analyticsReportLimit)] {
+			line := fmt.Sprintf("  %4d  %s", search.Count, search.Query)
+			if search.Empty > 0 {
+				line += c.out.muted(fmt.Sprintf("  (%d found nothing)", search.Empty))
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(report.Commands) > 0 {
+		fmt.Println("\nCommands")
+		for _, command := range report.Commands[:min(len(report.Commands), analyticsReportLimit)] {
+			fmt.Printf("  %4d  %s\n", command.Count, command.Name)
+		}
+	}
+	if len(report.Prompts)+len(report.Searches)+len(report.Commands) == 0 {
+		fmt.Println("Nothing recorded in this period")
+	}
+	return nil
+}
@@ -24,17 +24,21 @@
 //
 // COMMAND CATEGORIES:
 // - Core Prompts: list, search, get, create, edit, delete, copy
+// - Review: propose, approve, reject, review queue
 // - Search Operations: search, boolean-search, saved searches
 // - Templates: template management and operations
 // - System: tags, packs, health, configuration
 // - Import/Export: File and Git repository import/export
 // - Git Integration: Repository setup, sync, status
+// - Registry Sync: remote pull, push, sync, status
 //
 // OUTPUT FORMATS:
 // - Default: Human-readable format with descriptions and metadata
 // - JSON: Machine-readable format for scripting and integration
 // - Table: Tabular format for structured data display
 // - IDs: Simple ID list for scripting and piping
+// - Color: IDs, tags, headers and severities are colored on a terminal only
+//   (color.go); piped output, --no-color and NO_COLOR stay plain
 //
 // ERROR HANDLING:
 // - Unified errors: Uses CLI error handler for consistent formatting
@@ -54,32 +58,94 @@
 // - Configuration: User configuration file support for defaults
 // - Scripting: Enhanced scripting support with machine-readable outputs
 // - Progress indicators: Progress bars for long-running operations
-// - Colored output: Configurable color support for better terminal experience
 package cli
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dpshade/pocket-prompt/internal/bench"
 	"github.com/dpshade/pocket-prompt/internal/clipboard"
 	"github.com/dpshade/pocket-prompt/internal/commands"
 	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/detect"
 	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/federation"
+	"github.com/dpshade/pocket-prompt/internal/filemanager"
+	"github.com/dpshade/pocket-prompt/internal/git"
+	"github.com/dpshade/pocket-prompt/internal/help"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
 	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/language"
+	"github.com/dpshade/pocket-prompt/internal/lint"
 	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/platform"
+	"github.com/dpshade/pocket-prompt/internal/plugin"
+	"github.com/dpshade/pocket-prompt/internal/qr"
+	"github.com/dpshade/pocket-prompt/internal/redact"
+	"github.com/dpshade/pocket-prompt/internal/remote"
 	"github.com/dpshade/pocket-prompt/internal/renderer"
 	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/speech"
+	"github.com/dpshade/pocket-prompt/internal/ui"
+	"github.com/dpshade/pocket-prompt/internal/urlscheme"
+	"golang.org/x/term"
 )
 
 // CLI provides headless command-line interface functionality
 type CLI struct {
 	service      *service.Service
-	executor     *commands.CommandExecutor
+	executor     Executor
 	errorHandler *errors.CLIErrorHandler
+	table        tableOptions // Columns for --format table prompt lists
+	out          palette      // Colors for stdout
+	inShell      bool         // Running commands for 'pkt shell'
+}
+
+// Executor runs unified commands, either locally or against a running server
+type Executor interface {
+	Execute(ctx context.Context, commandName string, params map[string]interface{}) (*commands.CommandResult, error)
+}
+
+// ExitError is an error that sets the process exit status
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit status for an error returned by ExecuteCommand: the
+// code of an ExitError, or 1
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	if stderrors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
 }
 
 // NewCLI creates a new CLI instance
@@ -92,6 +158,16 @@ func NewCLI(svc *service.Service) *CLI {
 	}
 }
 
+// NewRemoteCLI creates a CLI that sends commands to a running server through
+// exec instead of reading the library from disk
+func NewRemoteCLI(exec Executor) *CLI {
+	verbose := os.Getenv("DEBUG") == "true" || os.Getenv("VERBOSE") == "true"
+	return &CLI{
+		executor:     exec,
+		errorHandler: errors.NewCLIErrorHandler(verbose),
+	}
+}
+
 // parseBooleanExpression delegates to the shared parser in models package
 func parseBooleanExpression(expr string) (*models.BooleanExpression, error) {
 	return models.ParseBooleanExpression(expr)
@@ -121,10 +197,13 @@ func (c *CLI) executeUnifiedCommand(commandName string, params map[string]interf
 	}
 	
 	// Handle output based on data type
+	format, _ := params["format"].(string)
 	if result.Data != nil {
 		switch data := result.Data.(type) {
 		case []*models.Prompt:
-			c.printPrompts(data, "")
+			c.printPrompts(data, format)
+		case *models.Prompt:
+			return c.formatSinglePrompt(data, format, nil)
 		case []string:
 			for _, item := range data {
 				fmt.Println(item)
@@ -140,25 +219,118 @@ func (c *CLI) executeUnifiedCommand(commandName string, params map[string]interf
 	}
 	
 	if result.Message != "" {
-		fmt.Printf("# %s\n", result.Message)
+		fmt.Println(c.out.muted("# " + result.Message))
 	}
 	
 	return nil
-}// ExecuteCommand processes a CLI command and returns the result
+}
+
+// runCommand executes a unified command for a handler that formats the result
+// itself, turning a failed command into an error
+func (c *CLI) runCommand(commandName string, params map[string]interface{}) (*commands.CommandResult, error) {
+	result, err := c.executor.Execute(context.Background(), commandName, params)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		if result.Error != nil {
+			return nil, stderrors.New(result.Error.Message)
+		}
+		return nil, fmt.Errorf("%s failed", commandName)
+	}
+	return result, nil
+}
+
+// getTemplate fetches a template through the get-template command
+func (c *CLI) getTemplate(id string) (*models.Template, error) {
+	result, err := c.runCommand("get-template", map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data.(*models.Template), nil
+}
+
+// ExecuteCommand processes a CLI command and returns the result, recording
+// it in the command history
 func (c *CLI) ExecuteCommand(args []string) error {
 	if len(args) == 0 {
 		return c.printUsage()
 	}
+	err := c.executeCommand(args)
+	c.recordHistory(args, err)
+	if c.service != nil && !unrecordedCommands[args[0]] && args[0] != "analytics" {
+		c.service.RecordCommand(args[0])
+	}
+	return err
+}
+
+// executeCommand runs the command for ExecuteCommand, which records it
+func (c *CLI) executeCommand(args []string) error {
+
+	// Alias definitions keep their flags, so they are handled before any
+	// flag is taken out
+	if args[0] == "alias" && c.service != nil {
+		return c.handleAlias(args[1:])
+	}
+	args, err := c.expandAlias(args)
+	if err != nil {
+		return err
+	}
 
 	command := args[0]
 	commandArgs := args[1:]
 
+	// Any command that changes prompts can be told to touch protected ones
+	if hasFlag(commandArgs, "--force-protected") {
+		commandArgs = slices.DeleteFunc(commandArgs, func(arg string) bool { return arg == "--force-protected" })
+		if c.service != nil {
+			c.service.SetForceProtected(true)
+		}
+	}
+
+	// Any command takes --no-color; color is also off when stdout is not a terminal
+	if hasFlag(commandArgs, "--no-color") {
+		commandArgs = slices.DeleteFunc(commandArgs, func(arg string) bool { return arg == "--no-color" })
+		DisableColor()
+	}
+	c.out = newPalette(os.Stdout)
+
+	// Any command that lists prompts takes --columns and --no-truncate
+	commandArgs, table, err := extractTableFlags(commandArgs)
+	if err != nil {
+		return err
+	}
+	c.table = table
+
+	// The history is the user's own, so it is read locally in remote mode too
+	switch command {
+	case "history":
+		return c.handleHistory(commandArgs)
+	case "repeat", "!!":
+		return c.handleRepeat(commandArgs)
+	}
+
+	if c.service == nil {
+		return c.executeRemoteCommand(command, commandArgs)
+	}
+	if err := c.resolvePromptArgs(command, commandArgs); err != nil {
+		return err
+	}
+
 	switch command {
 	case "list", "ls":
+		if len(commandArgs) == 0 {
+			if handled, err := c.listPinnedSearch(); handled {
+				return err
+			}
+		}
 		// Use unified command system for list
 		params := c.parseListArgs(commandArgs)
 		return c.executeUnifiedCommand("list", params)
 	case "search":
+		if hasFlag(commandArgs, "--all-sources") || hasFlag(commandArgs, "--source") {
+			return c.federatedSearch(commandArgs)
+		}
 		// Use unified command system for search
 		if len(commandArgs) == 0 {
 			return fmt.Errorf("search query is required")
@@ -167,8 +339,28 @@ func (c *CLI) ExecuteCommand(args []string) error {
 			"query": commandArgs[0],
 		}
 		return c.executeUnifiedCommand("search", params)
+	case "sources", "source":
+		return c.handleSources(commandArgs)
+	case "project":
+		return c.handleProject(commandArgs)
+	case "suggest":
+		return c.handleSuggest(commandArgs)
+	case "summarize":
+		return c.handleSummarize(commandArgs)
+	case "autotag":
+		return c.handleAutotag(commandArgs)
+	case "translate":
+		return c.handleTranslate(commandArgs)
+	case "check-links":
+		return c.handleCheckLinks(commandArgs)
+	case "keys":
+		return c.handleKeys(commandArgs)
+	case "move":
+		return c.handleMove(commandArgs)
 	case "get", "show":
 		return c.showPrompt(commandArgs)
+	case "path":
+		return c.promptPath(commandArgs)
 	case "create", "new":
 		return c.createPrompt(commandArgs)
 	case "edit":
@@ -177,6 +369,20 @@ func (c *CLI) ExecuteCommand(args []string) error {
 		return c.deletePrompt(commandArgs)
 	case "copy":
 		return c.copyPrompt(commandArgs)
+	case "render":
+		return c.renderPrompt(commandArgs)
+	case "preview":
+		return c.previewPrompt(commandArgs)
+	case "share":
+		return c.sharePrompt(commandArgs)
+	case "cat":
+		return c.catPrompts(commandArgs)
+	case "speak":
+		return c.speakPrompt(commandArgs)
+	case "profiles", "profile":
+		return c.handleProfiles(commandArgs)
+	case "eval":
+		return c.evalOutputs(commandArgs)
 	case "templates":
 		return c.handleTemplates(commandArgs)
 	case "template":
@@ -188,7 +394,10 @@ func (c *CLI) ExecuteCommand(args []string) error {
 	case "search-saved":
 		return c.handleSavedSearches(commandArgs)
 	case "boolean-search":
-		// Use unified command system for boolean search
+		if len(commandArgs) > 0 && slices.Contains(booleanSearchSubcommands, commandArgs[0]) {
+			return c.handleBooleanSearch(commandArgs)
+		}
+		// Anything else is an expression, run through the unified command system
 		if len(commandArgs) == 0 {
 			return fmt.Errorf("boolean expression is required")
 		}
@@ -202,8 +411,72 @@ func (c *CLI) ExecuteCommand(args []string) error {
 		return c.handleImport(commandArgs)
 	case "git":
 		return c.handleGit(commandArgs)
+	case "migrate":
+		return c.handleMigrate(commandArgs)
+	case "attach":
+		return c.attachFiles(commandArgs)
+	case "detach":
+		return c.detachFile(commandArgs)
+	case "propose", "approve", "reject":
+		return c.handleReviewTransition(command, commandArgs)
+	case "review":
+		return c.handleReviewQueue(commandArgs)
+	case "lock", "unlock":
+		return c.handleLock(command, commandArgs)
+	case "protect", "unprotect":
+		return c.handleProtect(command, commandArgs)
+	case "locks":
+		return c.handleLocks(commandArgs)
+	case "log":
+		return c.handleOutcomeLog(commandArgs)
+	case "variants":
+		return c.handleVariants(commandArgs)
+	case "changelog":
+		return c.handleChangelog(commandArgs)
+	case "diff":
+		return c.handleDiff(commandArgs)
+	case "merge":
+		return c.handleMerge(commandArgs)
+	case "analytics":
+		return c.handleAnalytics(commandArgs)
+	case "digest":
+		return c.handleDigest(commandArgs)
+	case "stats":
+		return c.handleStats(commandArgs)
+	case "lint":
+		return c.handleLint(commandArgs)
+	case "hooks", "hook":
+		return c.handleHooks(commandArgs)
+	case "ci":
+		return c.handleCI(commandArgs)
+	case "maintenance":
+		return c.handleMaintenance(commandArgs)
+	case "bench":
+		return c.handleBench(commandArgs)
+	case "doctor":
+		return c.handleDoctor(commandArgs)
+	case "remote":
+		return c.handleRemote(commandArgs)
+	case "url-scheme":
+		return c.handleURLScheme(commandArgs)
+	case "qr":
+		return c.handleQR(commandArgs)
+	case "server":
+		return c.handleServer(commandArgs)
 	case "packs", "pack":
 		return c.handlePacks(commandArgs)
+	case "email":
+		return c.handleEmail(commandArgs)
+	case "config":
+		return c.handleConfig(commandArgs)
+	case "plugins", "plugin":
+		return c.handlePlugins(commandArgs)
+	case "shell":
+		return c.handleShell(commandArgs)
+	case "gh":
+		return c.handleGH(commandArgs)
+	case "watch-clipboard":
+		return c.handleWatchClipboard(commandArgs)
 	case "help":
 		return c.printHelp(commandArgs)
 	default:
@@ -211,6 +484,40 @@ func (c *CLI) ExecuteCommand(args []string) error {
 	}
 }
 
+// executeRemoteCommand handles the subset of commands that map onto unified
+// commands, which is all a remote CLI can run
+func (c *CLI) executeRemoteCommand(command string, args []string) error {
+	switch command {
+	case "list", "ls":
+		return c.executeUnifiedCommand("list", c.parseListArgs(args))
+	case "search":
+		if len(args) == 0 {
+			return fmt.Errorf("search query is required")
+		}
+		return c.executeUnifiedCommand("search", map[string]interface{}{"query": args[0]})
+	case "boolean-search":
+		if len(args) == 0 {
+			return fmt.Errorf("boolean expression is required")
+		}
+		return c.executeUnifiedCommand("boolean-search", map[string]interface{}{"expression": strings.Join(args, " ")})
+	case "get", "show":
+		if len(args) == 0 {
+			return fmt.Errorf("get requires a prompt ID")
+		}
+		return c.executeUnifiedCommand("get", map[string]interface{}{"id": args[0]})
+	case "tags":
+		return c.executeUnifiedCommand("list-tags", nil)
+	case "packs", "pack":
+		return c.executeUnifiedCommand("list-packs", nil)
+	case "health":
+		return c.executeUnifiedCommand("health", nil)
+	case "help":
+		return c.printHelp(args)
+	default:
+		return fmt.Errorf("%s is not available with --remote (supported: list, search, boolean-search, get, tags, packs, health)", command)
+	}
+}
+
 // listPrompts lists all prompts
 func (c *CLI) listPrompts(args []string) error {
 	var format string
@@ -311,6 +618,7 @@ func (c *CLI) showPrompt(args []string) error {
 
 	id := args[0]
 	var format string
+	var withStats bool
 
 	// Parse flags
 	for i := 1; i < len(args); i++ {
@@ -321,15 +629,74 @@ func (c *CLI) showPrompt(args []string) error {
 				format = args[i+1]
 				i++
 			}
+		case "--stats":
+			withStats = true
 		}
 	}
 
+	id, err := c.resolvePromptID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get prompt: %w", err)
+	}
 	prompt, err := c.service.GetPrompt(id)
 	if err != nil {
 		return fmt.Errorf("failed to get prompt: %w", err)
 	}
 
-	return c.formatSinglePrompt(prompt, format)
+	var stats *service.TextStats
+	if withStats {
+		measured := service.MeasureText(prompt.Content)
+		stats = &measured
+	}
+	return c.formatSinglePrompt(prompt, format, stats)
+}
+
+// resolvePromptID returns id if a prompt has it. Otherwise the error names
+// the closest IDs, and in a terminal the user may pick one of them instead.
+func (c *CLI) resolvePromptID(id string) (string, error) {
+	_, err := c.service.GetPrompt(id)
+	if !stderrors.Is(err, service.ErrPromptNotFound) {
+		return id, err
+	}
+	similar := c.service.SimilarPromptIDs(id)
+	if len(similar) == 0 {
+		return "", err
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) || c.defaults().SkipConfirm() {
+		return "", fmt.Errorf("%w (did you mean %s?)", err, strings.Join(similar, ", "))
+	}
+
+	// The question goes to stderr so output piped elsewhere stays clean
+	fmt.Fprintf(os.Stderr, "No prompt %s. Did you mean:\n", id)
+	colors := newPalette(os.Stderr)
+	for i, similarID := range similar {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, colors.id(similarID))
+	}
+	fmt.Fprintf(os.Stderr, "Pick one [1-%d], or press Enter to cancel: ", len(similar))
+	var response string
+	fmt.Scanln(&response)
+	choice, convErr := strconv.Atoi(strings.TrimSpace(response))
+	if convErr != nil || choice < 1 || choice > len(similar) {
+		return "", err
+	}
+	return similar[choice-1], nil
+}
+
+// promptPath prints the absolute path of a prompt's file, and with --reveal
+// also shows it in the file manager
+func (c *CLI) promptPath(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("path requires a prompt ID")
+	}
+	path, err := c.service.PromptPath(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get prompt: %w", err)
+	}
+	fmt.Println(path)
+	if hasFlag(args[1:], "--reveal") {
+		return filemanager.Reveal(path)
+	}
+	return nil
 }
 
 // createPrompt creates a new prompt
@@ -339,9 +706,10 @@ func (c *CLI) createPrompt(args []string) error {
 	}
 
 	id := args[0]
-	var title, description, content, template, pack string
+	var title, description, content, template, pack, dir, variantGroup, lang string
 	var tags []string
-	pack = "personal" // Default to personal library
+	var project bool
+	pack = c.defaults().DefaultPack()
 
 	// Parse flags
 	for i := 1; i < len(args); i++ {
@@ -380,6 +748,25 @@ func (c *CLI) createPrompt(args []string) error {
 				pack = args[i+1]
 				i++
 			}
+		case "--dir":
+			if i+1 < len(args) {
+				dir = args[i+1]
+				i++
+			}
+		case "--variant-group":
+			if i+1 < len(args) {
+				variantGroup = args[i+1]
+				i++
+			}
+		case "--lang":
+			if i+1 < len(args) {
+				if lang = language.Normalize(args[i+1]); lang == "" {
+					return fmt.Errorf("invalid language %q (use a language code such as de or pt-BR)", args[i+1])
+				}
+				i++
+			}
+		case "--project":
+			project = true
 		case "--stdin":
 			// Read content from stdin
 			var buf strings.Builder
@@ -402,31 +789,100 @@ func (c *CLI) createPrompt(args []string) error {
 	}
 
 	prompt := &models.Prompt{
-		ID:          id,
-		Version:     "1.0.0",
-		Name:        title,
-		Summary:     description,
-		Content:     content,
-		Tags:        tags,
-		TemplateRef: template,
-		Pack:        pack,
+		ID:           id,
+		Version:      "1.0.0",
+		Name:         title,
+		Summary:      description,
+		Content:      content,
+		Tags:         tags,
+		TemplateRef:  template,
+		Pack:         pack,
+		VariantGroup: variantGroup,
+		Language:     lang,
+	}
+
+	if dir != "" {
+		path, err := c.service.PromptFilePath(pack, dir, id)
+		if err != nil {
+			return err
+		}
+		prompt.FilePath = path
 	}
 
-	if err := c.service.CreatePrompt(prompt); err != nil {
+	create := c.service.CreatePrompt
+	if project {
+		create = c.service.CreateProjectPrompt
+	}
+	if err := create(prompt); err != nil {
 		return fmt.Errorf("failed to create prompt: %w", err)
 	}
 
 	fmt.Printf("Created prompt: %s\n", id)
+	c.noteSimilarTitles(prompt)
 	return nil
 }
 
+// noteSimilarTitles points out, on stderr, existing prompts in any pack whose
+// title is nearly identical to that of a prompt just created, so the user
+// can extend one of them instead of keeping a fork without knowing
+func (c *CLI) noteSimilarTitles(prompt *models.Prompt) {
+	similar := c.service.SimilarTitles(prompt.Title(), prompt.ID)
+	if len(similar) == 0 {
+		return
+	}
+	colors := newPalette(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Note: these prompts have a nearly identical title:")
+	for _, other := range similar {
+		where := ""
+		if other.Pack != "" {
+			where = " (pack " + other.Pack + ")"
+		}
+		link := other.Path
+		if path, err := c.service.PromptPath(other.ID); err == nil {
+			link = (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+		}
+		fmt.Fprintf(os.Stderr, "  %s  %s%s  %s\n", colors.id(other.ID), other.Title, where, link)
+	}
+	fmt.Fprintf(os.Stderr, "Consider extending %s with 'pkt edit %s' instead of keeping both.\n", similar[0].ID, similar[0].ID)
+}
+
 // editPrompt edits an existing prompt
 func (c *CLI) editPrompt(args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("edit requires a prompt ID")
 	}
 
-	id := args[0]
+	id, err := c.resolvePromptID(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get prompt: %w", err)
+	}
+	force := hasFlag(args, "--force")
+	args = slices.DeleteFunc(args, func(arg string) bool { return arg == "--force" })
+	// --if-version refuses the edit when the prompt has changed since then
+	var ifVersion string
+	if i := slices.Index(args, "--if-version"); i > 0 {
+		if i+1 >= len(args) {
+			return fmt.Errorf("--if-version requires a version")
+		}
+		ifVersion = args[i+1]
+		args = slices.Delete(args, i, i+2)
+	}
+	if err := c.checkLock(id, force); err != nil {
+		return err
+	}
+	if prompt, err := c.service.GetPrompt(id); err == nil {
+		if err := c.service.CheckProtected(prompt); err != nil {
+			return err
+		}
+	}
+	if ifVersion != "" {
+		if err := c.service.CheckPromptVersion(id, ifVersion); err != nil {
+			return err
+		}
+	}
+	if len(args) == 1 {
+		return c.editPromptInEditor(id, ifVersion)
+	}
 	prompt, err := c.service.GetPrompt(id)
 	if err != nil {
 		return fmt.Errorf("failed to get prompt: %w", err)
@@ -456,6 +912,18 @@ func (c *CLI) editPrompt(args []string) error {
 				prompt.TemplateRef = args[i+1]
 				i++
 			}
+		case "--variant-group":
+			if i+1 < len(args) {
+				prompt.VariantGroup = args[i+1]
+				i++
+			}
+		case "--lang":
+			if i+1 < len(args) {
+				if prompt.Language = language.Normalize(args[i+1]); prompt.Language == "" {
+					return fmt.Errorf("invalid language %q (use a language code such as de or pt-BR)", args[i+1])
+				}
+				i++
+			}
 		case "--tags":
 			if i+1 < len(args) {
 				tags := strings.Split(args[i+1], ",")
@@ -506,7 +974,68 @@ func (c *CLI) editPrompt(args []string) error {
 		}
 	}
 
-	if err := c.service.UpdatePrompt(prompt); err != nil {
+	if ifVersion != "" {
+		err = c.service.UpdatePromptIfVersion(prompt, ifVersion)
+	} else {
+		err = c.service.UpdatePrompt(prompt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update prompt: %w", err)
+	}
+
+	fmt.Printf("Updated prompt: %s\n", id)
+	return nil
+}
+
+// checkLock warns when someone else has locked a prompt for editing and asks
+// whether to edit it anyway. Locks are advisory, so force skips the question.
+func (c *CLI) checkLock(id string, force bool) error {
+	lock := c.service.LockedByOther(id, c.service.LockOwner())
+	if lock == nil {
+		return nil
+	}
+	warnf("%v", &service.LockedError{Lock: lock})
+	if lock.Note != "" {
+		fmt.Fprintf(os.Stderr, "  %s\n", lock.Note)
+	}
+	if force || c.confirm("Edit anyway?") {
+		return nil
+	}
+	return fmt.Errorf("edit cancelled")
+}
+
+// editPromptInEditor opens a copy of a prompt's file in the configured editor
+// and saves the result as the prompt's next version. With ifVersion, the
+// result is refused if the prompt changed while the editor was open.
+func (c *CLI) editPromptInEditor(id, ifVersion string) error {
+	source, err := c.service.PromptSource(id)
+	if err != nil {
+		return fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	edited, err := c.openInEditor(source, "pkt-edit-*.md")
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(edited, source) {
+		fmt.Println("No changes")
+		return nil
+	}
+	if ifVersion != "" {
+		if err := c.service.CheckPromptVersion(id, ifVersion); err != nil {
+			// Keep the edit so it is not lost with the temporary file
+			kept, keepErr := os.CreateTemp("", "pkt-edit-*.md")
+			if keepErr != nil {
+				return err
+			}
+			defer kept.Close()
+			if _, keepErr := kept.Write(edited); keepErr != nil {
+				return err
+			}
+			return fmt.Errorf("%w; your edit was saved to %s", err, kept.Name())
+		}
+	}
+	if _, err := c.service.UpdatePromptSource(id, edited); err != nil {
 		return fmt.Errorf("failed to update prompt: %w", err)
 	}
 
@@ -514,6 +1043,36 @@ func (c *CLI) editPrompt(args []string) error {
 	return nil
 }
 
+// openInEditor opens content in the configured editor in a temporary file
+// named after pattern, and returns the file's contents once the editor exits
+func (c *CLI) openInEditor(content []byte, pattern string) ([]byte, error) {
+	tmp, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	editor := strings.Fields(c.defaults().EditorCommand())
+	cmd := exec.Command(editor[0], append(editor[1:], tmp.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return edited, nil
+}
+
 // deletePrompt deletes a prompt
 func (c *CLI) deletePrompt(args []string) error {
 	if len(args) == 0 {
@@ -530,16 +1089,17 @@ func (c *CLI) deletePrompt(args []string) error {
 		}
 	}
 
-	if !force {
-		fmt.Printf("Are you sure you want to delete prompt '%s'? (y/N): ", id)
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-			fmt.Println("Cancelled")
-			return nil
+	if prompt, err := c.service.GetPrompt(id); err == nil {
+		if err := c.service.CheckProtected(prompt); err != nil {
+			return err
 		}
 	}
 
+	if !force && !c.confirm(fmt.Sprintf("Are you sure you want to delete prompt '%s'?", id)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
 	if err := c.service.DeletePrompt(id); err != nil {
 		return fmt.Errorf("failed to delete prompt: %w", err)
 	}
@@ -548,54 +1108,61 @@ func (c *CLI) deletePrompt(args []string) error {
 	return nil
 }
 
-// copyPrompt copies a prompt to clipboard
-func (c *CLI) copyPrompt(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("copy requires a prompt ID")
+// attachFiles copies files into the library and attaches them to a prompt
+func (c *CLI) attachFiles(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("attach requires a prompt ID and at least one file")
 	}
 
-	id := args[0]
-	var format string
+	prompt, err := c.service.AttachFiles(args[0], args[1:])
+	if err != nil {
+		return fmt.Errorf("failed to attach files: %w", err)
+	}
 
-	// Parse flags
-	for i := 1; i < len(args); i++ {
-		arg := args[i]
-		switch arg {
-		case "--format", "-f":
-			if i+1 < len(args) {
-				format = args[i+1]
-				i++
-			}
-		}
+	fmt.Printf("Attached to %s:\n", prompt.ID)
+	for _, attachment := range prompt.Attachments {
+		fmt.Printf("  %s\n", attachment)
 	}
+	return nil
+}
 
-	prompt, err := c.service.GetPrompt(id)
-	if err != nil {
-		return fmt.Errorf("failed to get prompt: %w", err)
+// detachFile removes an attachment from a prompt
+func (c *CLI) detachFile(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("detach requires a prompt ID and an attachment path")
 	}
 
-	var template *models.Template
-	if prompt.TemplateRef != "" {
-		template, _ = c.service.GetTemplate(prompt.TemplateRef)
+	id, attachment := args[0], args[1]
+	deleteFile := hasFlag(args[2:], "--delete")
+
+	if err := c.service.DetachFile(id, attachment, deleteFile); err != nil {
+		return fmt.Errorf("failed to detach file: %w", err)
 	}
 
-	r := renderer.NewRenderer(prompt, template)
-	
-	var content string
-	switch format {
-	case "json":
-		content, err = r.RenderJSON(nil)
-	default:
-		content, err = r.RenderText(nil)
+	fmt.Printf("Detached %s from %s\n", attachment, id)
+	return nil
+}
+
+// copyPrompt copies a prompt to clipboard
+func (c *CLI) copyPrompt(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("copy requires a prompt ID")
+	}
+
+	id, opts, err := c.parseRenderArgs(args[0], args[1:])
+	if err != nil {
+		return err
 	}
 
+	// JSON output includes the prompt's images and output schema
+	content, err := c.service.RenderPrompt(id, opts)
 	if err != nil {
 		return fmt.Errorf("failed to render prompt: %w", err)
 	}
 
 	if statusMsg, err := clipboard.CopyWithFallback(content); err != nil {
 		// Print the helpful error message and continue without failing
-		fmt.Printf("Warning: %v\n", err)
+		warnf("%v", err)
 		fmt.Printf("Content saved but not copied to clipboard.\n")
 	} else {
 		fmt.Printf("%s\n", statusMsg)
@@ -603,752 +1170,5275 @@ func (c *CLI) copyPrompt(args []string) error {
 	return nil
 }
 
+// renderPrompt prints a rendered prompt, for piping into other tools
+func (c *CLI) renderPrompt(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("render requires a prompt ID")
+	}
 
-// formatOutput formats prompts for output
-func (c *CLI) formatOutput(prompts []*models.Prompt, format string) error {
-	switch format {
-	case "json":
-		return json.NewEncoder(os.Stdout).Encode(prompts)
-	case "ids":
-		for _, p := range prompts {
-			fmt.Println(p.ID)
-		}
-	case "table":
-		fmt.Printf("%-20s %-30s %-15s %s\n", "ID", "Title", "Version", "Updated")
-		fmt.Println(strings.Repeat("-", 80))
-		for _, p := range prompts {
-			title := p.Name
-			if len(title) > 30 {
-				title = title[:27] + "..."
-			}
-			fmt.Printf("%-20s %-30s %-15s %s\n", 
-				p.ID, title, p.Version, p.UpdatedAt.Format("2006-01-02"))
-		}
-	default:
-		for _, p := range prompts {
-			fmt.Printf("%s - %s\n", p.ID, p.Name)
-			if p.Summary != "" {
-				fmt.Printf("  %s\n", p.Summary)
-			}
-			if len(p.Tags) > 0 {
-				fmt.Printf("  Tags: %s\n", strings.Join(p.Tags, ", "))
-			}
-			fmt.Println()
-		}
+	id, opts, err := c.parseRenderArgs(args[0], args[1:])
+	if err != nil {
+		return err
 	}
+	content, err := c.service.RenderPrompt(id, opts)
+	if err != nil {
+		return fmt.Errorf("failed to render prompt: %w", err)
+	}
+	fmt.Println(content)
 	return nil
 }
 
-// formatSinglePrompt formats a single prompt for output
-func (c *CLI) formatSinglePrompt(prompt *models.Prompt, format string) error {
-	switch format {
-	case "json":
-		return json.NewEncoder(os.Stdout).Encode(prompt)
-	default:
-		fmt.Printf("ID: %s\n", prompt.ID)
-		fmt.Printf("Title: %s\n", prompt.Name)
-		fmt.Printf("Version: %s\n", prompt.Version)
-		if prompt.Summary != "" {
-			fmt.Printf("Description: %s\n", prompt.Summary)
-		}
-		if len(prompt.Tags) > 0 {
-			fmt.Printf("Tags: %s\n", strings.Join(prompt.Tags, ", "))
-		}
-		if prompt.TemplateRef != "" {
-			fmt.Printf("Template: %s\n", prompt.TemplateRef)
-		}
-		fmt.Printf("Created: %s\n", prompt.CreatedAt.Format("2006-01-02 15:04"))
-		fmt.Printf("Updated: %s\n", prompt.UpdatedAt.Format("2006-01-02 15:04"))
-		fmt.Printf("\nContent:\n%s\n", prompt.Content)
-	}
-	return nil
-}
-
-// Additional command handlers would go here...
-// This is a simplified implementation focusing on core functionality
-
-func (c *CLI) handleTemplates(args []string) error {
+// previewPrompt writes a prompt, or with --template a template, as a styled
+// HTML page for sharing
+func (c *CLI) previewPrompt(args []string) error {
 	if len(args) == 0 {
-		// List templates
-		templates, err := c.service.ListTemplates()
-		if err != nil {
-			return fmt.Errorf("failed to list templates: %w", err)
-		}
+		return fmt.Errorf("preview requires a prompt ID")
+	}
 
-		for _, t := range templates {
-			fmt.Printf("%s - %s\n", t.ID, t.Name)
-			if t.Description != "" {
-				fmt.Printf("  %s\n", t.Description)
+	var out string
+	var isTemplate bool
+	var renderArgs []string
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--out", "-o":
+			if i+1 < len(args) {
+				out = args[i+1]
+				i++
 			}
-			fmt.Println()
+		case "--template", "-t":
+			isTemplate = true
+		default:
+			renderArgs = append(renderArgs, args[i])
 		}
-		return nil
 	}
 
-	subcommand := args[0]
-	switch subcommand {
-	case "show":
-		if len(args) < 2 {
-			return fmt.Errorf("templates show requires a template ID")
-		}
-		template, err := c.service.GetTemplate(args[1])
-		if err != nil {
-			return fmt.Errorf("failed to get template: %w", err)
-		}
-		
-		fmt.Printf("ID: %s\n", template.ID)
-		fmt.Printf("Name: %s\n", template.Name)
-		if template.Description != "" {
-			fmt.Printf("Description: %s\n", template.Description)
-		}
-		fmt.Printf("Created: %s\n", template.CreatedAt.Format("2006-01-02 15:04"))
-		fmt.Printf("Updated: %s\n", template.UpdatedAt.Format("2006-01-02 15:04"))
-		fmt.Printf("\nContent:\n%s\n", template.Content)
-		
-		if len(template.Slots) > 0 {
-			fmt.Println("\nSlots:")
-			for _, slot := range template.Slots {
-				fmt.Printf("  %s", slot.Name)
-				if slot.Required {
-					fmt.Print(" [required]")
-				}
-				if slot.Default != "" {
-					fmt.Printf(" [default: %s]", slot.Default)
-				}
-				if slot.Description != "" {
-					fmt.Printf(" - %s", slot.Description)
-				}
-				fmt.Println()
-			}
-		}
-		return nil
-	default:
-		return fmt.Errorf("unknown templates subcommand: %s", subcommand)
+	id, opts, err := c.parseRenderArgs(args[0], renderArgs)
+	if err != nil {
+		return err
+	}
+	if opts.Format != "" {
+		return fmt.Errorf("preview is always HTML; use render --format for other formats")
+	}
+	if opts.Section != "" {
+		return fmt.Errorf("preview shows the whole prompt; use render or copy with --section")
 	}
-}
 
-func (c *CLI) handleTags(args []string) error {
-	tags, err := c.service.GetAllTags()
+	var page string
+	if isTemplate {
+		page, err = c.service.PreviewTemplate(id, opts)
+	} else {
+		page, err = c.service.PreviewPrompt(id, opts)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get tags: %w", err)
+		return fmt.Errorf("failed to preview: %w", err)
 	}
 
-	for _, tag := range tags {
-		fmt.Println(tag)
+	if out == "" {
+		fmt.Print(page)
+		return nil
 	}
+	if err := os.WriteFile(out, []byte(page), 0644); err != nil {
+		return fmt.Errorf("failed to write preview: %w", err)
+	}
+	fmt.Printf("Wrote preview of %s to %s\n", id, out)
 	return nil
 }
 
-func (c *CLI) handleArchive(args []string) error {
+// sharePrompt writes a prompt as a self-contained Markdown document for
+// pasting into wikis, docs or emails
+func (c *CLI) sharePrompt(args []string) error {
 	if len(args) == 0 {
-		// List archived prompts
-		prompts, err := c.service.ListArchivedPrompts()
-		if err != nil {
-			return fmt.Errorf("failed to list archived prompts: %w", err)
-		}
-		return c.formatOutput(prompts, "")
+		return fmt.Errorf("share requires a prompt ID")
 	}
-	return fmt.Errorf("archive subcommands not implemented")
-}
 
-func (c *CLI) handleSavedSearches(args []string) error {
-	if len(args) == 0 {
-		// List saved searches
-		searches, err := c.service.ListSavedSearches()
-		if err != nil {
-			return fmt.Errorf("failed to list saved searches: %w", err)
+	id := args[0]
+	var out string
+	var toClipboard bool
+	var redactor *redact.Redactor
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--out", "-o":
+			if i+1 < len(args) {
+				out = args[i+1]
+				i++
+			}
+		case "--clipboard", "-c":
+			toClipboard = true
+		case "--redact":
+			var err error
+			if redactor, err = c.service.Redactor(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown option: %s", args[i])
 		}
+	}
+	if out != "" && toClipboard {
+		return fmt.Errorf("--out and --clipboard cannot be combined")
+	}
 
-		for _, search := range searches {
-			fmt.Printf("%s: %s\n", search.Name, search.Expression.String())
-		}
-		return nil
+	doc, err := c.service.SharePrompt(id, redactor)
+	if err != nil {
+		return fmt.Errorf("failed to share prompt: %w", err)
 	}
 
-	subcommand := args[0]
-	switch subcommand {
-	case "run":
-		if len(args) < 2 {
-			return fmt.Errorf("search-saved run requires a search name")
-		}
-		
-		searchName := args[1]
-		var textQuery string
-		var format string
-		
-		// Parse flags
-		for i := 2; i < len(args); i++ {
-			arg := args[i]
-			switch arg {
-			case "--text", "-t":
-				if i+1 < len(args) {
-					textQuery = args[i+1]
-					i++
-				}
-			case "--format", "-f":
-				if i+1 < len(args) {
-					format = args[i+1]
-					i++
-				}
-			}
-		}
-		
-		prompts, err := c.service.ExecuteSavedSearchWithText(searchName, textQuery)
+	switch {
+	case toClipboard:
+		statusMsg, err := clipboard.CopyWithFallback(doc)
 		if err != nil {
-			return fmt.Errorf("failed to execute saved search: %w", err)
+			// Print the document instead, so it can still be copied by hand
+			warnf("%v", err)
+			fmt.Print(doc)
+			return nil
 		}
-		return c.formatOutput(prompts, format)
+		fmt.Println(statusMsg)
+	case out != "":
+		if err := os.WriteFile(out, []byte(doc), 0644); err != nil {
+			return fmt.Errorf("failed to write shared prompt: %w", err)
+		}
+		fmt.Printf("Wrote %s to %s\n", id, out)
 	default:
-		return fmt.Errorf("unknown search-saved subcommand: %s", subcommand)
+		fmt.Print(doc)
 	}
+	return nil
 }
 
-func (c *CLI) handleGit(args []string) error {
-	if len(args) == 0 {
-		// Show git status
-		status, err := c.service.GetGitSyncStatus()
-		if err != nil {
-			return fmt.Errorf("failed to get git status: %w", err)
+// catPrompts renders several prompts in order and prints them as one, or
+// copies it with --copy. The IDs come first, then the options: --vars
+// <id>=<file> fills one prompt's variables from a YAML or JSON file, over the
+// values every prompt gets, and render's options apply to every prompt. The
+// values given are not remembered, since most only suit some of the prompts.
+func (c *CLI) catPrompts(args []string) error {
+	var ids []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		ids = append(ids, args[0])
+		args = args[1:]
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("cat requires at least one prompt ID")
+	}
+
+	var separator string
+	var toClipboard bool
+	varsFiles := map[string]string{}
+	var renderArgs []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--separator", "-s":
+			if i+1 < len(args) {
+				separator = args[i+1]
+				i++
+			}
+		case "--copy", "-c":
+			toClipboard = true
+		case "--vars":
+			if i+1 < len(args) {
+				id, file, ok := strings.Cut(args[i+1], "=")
+				if !ok || id == "" || file == "" {
+					return fmt.Errorf("--vars expects <id>=<file>, got %q", args[i+1])
+				}
+				varsFiles[id] = file
+				i++
+			}
+		case "--variant":
+			return fmt.Errorf("cat does not take --variant; name the prompts to concatenate")
+		default:
+			renderArgs = append(renderArgs, args[i])
 		}
-		fmt.Println("Git sync status:", status)
-		return nil
 	}
 
-	subcommand := args[0]
-	switch subcommand {
-	case "setup":
-		if len(args) < 2 {
-			return fmt.Errorf("git setup requires a repository URL\n\nUsage: pkt git setup <repository-url>\n\nExamples:\n  pkt git setup https://github.com/username/my-prompts.git\n  pkt git setup git@github.com:username/my-prompts.git")
-		}
-		repoURL := args[1]
-		if err := c.service.SetupGitRepository(repoURL); err != nil {
-			return fmt.Errorf("failed to setup git repository: %w", err)
-		}
-		fmt.Println("Git repository successfully configured!")
-		return nil
-	case "enable":
-		c.service.EnableGitSync()
-		fmt.Println("Git sync enabled")
-		return nil
-	case "disable":
-		c.service.DisableGitSync()
-		fmt.Println("Git sync disabled")
-		return nil
-	case "status":
-		status, err := c.service.GetGitSyncStatus()
+	parts := make([]service.ConcatPart, len(ids))
+	for i, given := range ids {
+		id, err := c.resolvePromptID(given)
 		if err != nil {
-			return fmt.Errorf("failed to get git status: %w", err)
+			return fmt.Errorf("failed to get prompt: %w", err)
 		}
-		fmt.Println(status)
-		return nil
-	case "sync":
-		if err := c.service.SyncChanges("Manual sync from CLI"); err != nil {
-			return fmt.Errorf("failed to sync: %w", err)
+		parts[i].ID = id
+		file, ok := varsFiles[given]
+		if !ok {
+			file, ok = varsFiles[id]
 		}
-		fmt.Println("Successfully synced with remote repository")
-		return nil
-	case "pull":
-		if err := c.service.PullGitChanges(); err != nil {
-			return fmt.Errorf("failed to pull changes: %w", err)
+		if ok {
+			if parts[i].Variables, err = c.service.LoadVariablesFile(file); err != nil {
+				return err
+			}
+		}
+	}
+	for given := range varsFiles {
+		if !slices.Contains(ids, given) && !slices.ContainsFunc(parts, func(part service.ConcatPart) bool { return part.ID == given }) {
+			return fmt.Errorf("--vars names %s, which is not one of the prompts", given)
 		}
-		fmt.Println("Successfully pulled changes from remote repository")
-		return nil
-	default:
-		return fmt.Errorf("unknown git subcommand: %s", subcommand)
 	}
-}
-
-func (c *CLI) printUsage() error {
-	fmt.Println(`pkt - Headless CLI mode
-
-Usage: pkt <command> [options]
-
-Commands:
-  list, ls              List all prompts
-  search <query>        Search prompts  
-  get, show <id>        Show a specific prompt
-  create, new <id>      Create a new prompt
-  edit <id>             Edit an existing prompt
-  delete, rm <id>       Delete a prompt
-  copy <id>             Copy prompt to clipboard
-  templates             List templates
-  template              Template management (create, edit, delete, show)
-  tags                  List all tags
-  archive               Manage archived prompts
-  search-saved          Manage saved searches
-  boolean-search        Boolean search operations (create, edit, delete, list, run)
-  export                Export prompts and templates
-  import                Import prompts and templates
-  git                   Git synchronization
-  help                  Show help
-
-Use 'pkt help <command>' for detailed help on a specific command.`)
-	return nil
-}
 
-// handleTemplate handles individual template operations  
-func (c *CLI) handleTemplate(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("template command requires a subcommand (create, edit, delete, show)")
+	// The IDs are resolved, so this only reads the options
+	_, opts, err := c.parseRenderArgs(parts[0].ID, renderArgs)
+	if err != nil {
+		return err
 	}
+	opts.RememberAnswers = false
 
-	subcommand := args[0]
-	switch subcommand {
-	case "create":
-		return c.createTemplate(args[1:])
-	case "edit":
-		return c.editTemplate(args[1:])  
-	case "delete":
-		return c.deleteTemplate(args[1:])
-	case "show":
-		if len(args) < 2 {
-			return fmt.Errorf("template show requires a template ID")
-		}
-		template, err := c.service.GetTemplate(args[1])
-		if err != nil {
-			return fmt.Errorf("failed to get template: %w", err)
-		}
-		return c.formatSingleTemplate(template, "")
-	default:
-		return fmt.Errorf("unknown template subcommand: %s", subcommand)
+	content, err := c.service.ConcatPrompts(parts, separator, opts)
+	if err != nil {
+		return fmt.Errorf("failed to render prompts: %w", err)
+	}
+	if !toClipboard {
+		fmt.Println(content)
+		return nil
+	}
+	if statusMsg, err := clipboard.CopyWithFallback(content); err != nil {
+		// Print the result instead, so it can still be copied by hand
+		warnf("%v", err)
+		fmt.Println(content)
+	} else {
+		fmt.Println(statusMsg)
 	}
+	return nil
 }
 
-// createTemplate creates a new template
-func (c *CLI) createTemplate(args []string) error {
+// speakPrompt reads a rendered prompt aloud with the system's text-to-speech
+// engine, or writes the speech to an audio file with --out
+func (c *CLI) speakPrompt(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("create template requires a template ID")
+		return fmt.Errorf("speak requires a prompt ID")
 	}
 
-	id := args[0]
-	var name, description, content string
-	var slots []string
-
-	// Parse flags
+	var speechOpts speech.Options
+	var renderArgs []string
 	for i := 1; i < len(args); i++ {
-		arg := args[i]
-		switch arg {
-		case "--name":
+		switch args[i] {
+		case "--voice":
 			if i+1 < len(args) {
-				name = args[i+1]
-				i++
-			}
-		case "--description":
-			if i+1 < len(args) {
-				description = args[i+1]
+				speechOpts.Voice = args[i+1]
 				i++
 			}
-		case "--content":
+		case "--rate":
 			if i+1 < len(args) {
-				content = args[i+1]
+				rate, err := strconv.Atoi(args[i+1])
+				if err != nil || rate <= 0 {
+					return fmt.Errorf("--rate expects words per minute, got %q", args[i+1])
+				}
+				speechOpts.Rate = rate
 				i++
 			}
-		case "--slots":
+		case "--out", "-o":
 			if i+1 < len(args) {
-				slots = strings.Split(args[i+1], ",")
-				for j := range slots {
-					slots[j] = strings.TrimSpace(slots[j])
-				}
+				speechOpts.Out = args[i+1]
 				i++
 			}
-		case "--stdin":
-			// Read content from stdin
-			var buf strings.Builder
-			for {
-				var line string
-				n, err := fmt.Scanln(&line)
-				if n == 0 || err != nil {
-					break
+		default:
+			renderArgs = append(renderArgs, args[i])
+		}
+	}
+
+	id, opts, err := c.parseRenderArgs(args[0], renderArgs)
+	if err != nil {
+		return err
+	}
+	if opts.Format != "" {
+		return fmt.Errorf("speak reads the prompt's text; use render --format for other formats")
+	}
+	content, err := c.service.RenderPrompt(id, opts)
+	if err != nil {
+		return fmt.Errorf("failed to render prompt: %w", err)
+	}
+	if err := speech.Speak(speech.Text(content), speechOpts); err != nil {
+		return fmt.Errorf("failed to speak prompt: %w", err)
+	}
+	if speechOpts.Out != "" {
+		fmt.Printf("Wrote %s\n", speechOpts.Out)
+	}
+	return nil
+}
+
+// parseRenderArgs reads the flags shared by render and copy and returns the
+// ID of the prompt to render. Renders from the CLI read secrets from the
+// environment and keychain, ask for any other sensitive values when run in a
+// terminal, and fill context placeholders from the working directory.
+// {{cmd:...}} placeholders only run with --allow-cmd. Variables not given
+// with --var or the profile take the values last given for the prompt,
+// unless --fresh is passed, and the values given are remembered. --section
+// renders only the part under one Markdown heading.
+//
+// With --variant, id names a variant group and one of its prompts is picked.
+// The pick is printed to stderr so the rendered text stays blind.
+func (c *CLI) parseRenderArgs(id string, args []string) (string, service.RenderOptions, error) {
+	opts := service.RenderOptions{Variables: map[string]interface{}{}, ReadSecrets: true, Context: &renderer.Context{}, UseAnswers: true, RememberAnswers: true}
+	var variant string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		opts.AskSecret = askSecret
+	}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				opts.Format = args[i+1]
+				i++
+			}
+		case "--images":
+			if i+1 < len(args) {
+				opts.Images = args[i+1]
+				i++
+			}
+		case "--profile", "-p":
+			if i+1 < len(args) {
+				opts.Profile = args[i+1]
+				i++
+			}
+		case "--var":
+			if i+1 < len(args) {
+				name, value, ok := strings.Cut(args[i+1], "=")
+				if !ok || name == "" {
+					return "", opts, fmt.Errorf("--var expects name=value, got %q", args[i+1])
 				}
-				buf.WriteString(line + "\n")
+				opts.Variables[name] = value
+				i++
 			}
-			content = buf.String()
+		case "--variant":
+			if i+1 < len(args) {
+				variant = args[i+1]
+				i++
+			}
+		case "--redact":
+			redactor, err := c.service.Redactor()
+			if err != nil {
+				return "", opts, err
+			}
+			opts.Redactor = redactor
+		case "--allow-cmd":
+			opts.Context.AllowCommands = true
+		case "--fresh":
+			opts.UseAnswers = false
+		case "--section":
+			if i+1 < len(args) {
+				opts.Section = args[i+1]
+				i++
+			}
+		default:
+			return "", opts, fmt.Errorf("unknown option: %s", args[i])
 		}
 	}
 
-	template := &models.Template{
-		ID:          id,
-		Version:     "1.0.0",
-		Name:        name,
-		Description: description,
-		Content:     content,
+	if variant != "" {
+		prompt, err := c.service.PickVariant(id, variant)
+		if err != nil {
+			return "", opts, err
+		}
+		fmt.Fprintf(os.Stderr, "Variant: %s\n", prompt.ID)
+		return prompt.ID, opts, nil
+	}
+	id, err := c.resolvePromptID(id)
+	if err != nil {
+		return "", opts, fmt.Errorf("failed to get prompt: %w", err)
 	}
+	return id, opts, nil
+}
 
-	// Convert slot strings to template slots
-	for _, slot := range slots {
-		template.Slots = append(template.Slots, models.Slot{
-			Name:        slot,
-			Required:    false,
-			Description: "",
-			Default:     "",
-		})
+// askSecret reads a sensitive variable from the terminal without echoing it
+func askSecret(v models.Variable) (string, error) {
+	label := v.Name
+	if v.Description != "" {
+		label = v.Description + " (" + v.Name + ")"
+	}
+	fmt.Fprintf(os.Stderr, "%s: ", label)
+	value, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
 	}
+	return string(value), nil
+}
 
-	if err := c.service.SaveTemplate(template); err != nil {
-		return fmt.Errorf("failed to create template: %w", err)
+// handleProfiles lists variable profiles and the variables each one sets
+func (c *CLI) handleProfiles(args []string) error {
+	names, err := c.service.ListProfiles()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No profiles. Add YAML files of variable values to profiles/ in your library.")
+		return nil
 	}
 
-	fmt.Printf("Created template: %s\n", id)
+	for _, name := range names {
+		variables, err := c.service.LoadProfile(name)
+		if err != nil {
+			fmt.Printf("%s (error: %v)\n", name, err)
+			continue
+		}
+		keys := make([]string, 0, len(variables))
+		for key := range variables {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Printf("%s: %s\n", name, strings.Join(keys, ", "))
+	}
 	return nil
 }
 
-// editTemplate edits an existing template
-func (c *CLI) editTemplate(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("edit template requires a template ID")
+// evalOutputs validates sample model outputs against a prompt's output schema
+func (c *CLI) evalOutputs(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("eval requires a prompt ID and at least one output file")
 	}
 
 	id := args[0]
-	template, err := c.service.GetTemplate(id)
-	if err != nil {
-		return fmt.Errorf("failed to get template: %w", err)
+	failed := 0
+	for _, file := range args[1:] {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		problems, err := c.service.ValidateOutput(id, data)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("PASS  %s\n", file)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL  %s\n", file)
+		for _, problem := range problems {
+			fmt.Printf("      %s\n", problem)
+		}
 	}
 
-	// Parse flags to update fields
+	if failed > 0 {
+		return fmt.Errorf("%d of %d outputs do not match the schema for %s", failed, len(args)-1, id)
+	}
+	return nil
+}
+
+// handleQR prints a QR code holding a prompt's rendered text, or with --url
+// the server address that serves it
+func (c *CLI) handleQR(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("qr requires a prompt ID")
+	}
+
+	id := args[0]
+	useURL := false
+	host, port := c.serverAddress()
 	for i := 1; i < len(args); i++ {
-		arg := args[i]
-		switch arg {
-		case "--name":
+		switch args[i] {
+		case "--url":
+			useURL = true
+		case "--host":
 			if i+1 < len(args) {
-				template.Name = args[i+1]
+				host = args[i+1]
 				i++
 			}
-		case "--description":
+		case "--port":
 			if i+1 < len(args) {
-				template.Description = args[i+1]
+				p, err := strconv.Atoi(args[i+1])
+				if err != nil {
+					return fmt.Errorf("invalid port: %s", args[i+1])
+				}
+				port = p
 				i++
 			}
-		case "--content":
+		}
+	}
+
+	prompt, err := c.service.GetPrompt(id)
+	if err != nil {
+		return fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	var content string
+	if useURL {
+		content = fmt.Sprintf("%s/api/v1/prompts/%s", serverURL(host, port), url.PathEscape(prompt.ID))
+	} else {
+		var template *models.Template
+		if prompt.TemplateRef != "" {
+			template, _ = c.service.GetTemplate(prompt.TemplateRef)
+		}
+		content, err = renderer.NewRenderer(prompt, template).RenderText(nil)
+		if err != nil {
+			return fmt.Errorf("failed to render prompt: %w", err)
+		}
+	}
+
+	code, err := qr.Render(content)
+	if err != nil {
+		if !useURL {
+			return fmt.Errorf("%w; use --url to encode the server link instead", err)
+		}
+		return err
+	}
+
+	fmt.Print(code)
+	if useURL {
+		fmt.Println(content)
+	} else {
+		fmt.Printf("%s (%d characters)\n", prompt.Title(), len([]rune(content)))
+	}
+	return nil
+}
+
+// handleServer handles helpers for the HTTP API server
+func (c *CLI) handleServer(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("server subcommand required (qr, keys)")
+	}
+
+	switch args[0] {
+	case "qr":
+		host, port := c.serverAddress()
+		for i := 1; i < len(args); i++ {
+			switch args[i] {
+			case "--host":
+				if i+1 < len(args) {
+					host = args[i+1]
+					i++
+				}
+			case "--port":
+				if i+1 < len(args) {
+					p, err := strconv.Atoi(args[i+1])
+					if err != nil {
+						return fmt.Errorf("invalid port: %s", args[i+1])
+					}
+					port = p
+					i++
+				}
+			}
+		}
+
+		address := serverURL(host, port)
+		code, err := qr.Render(address)
+		if err != nil {
+			return err
+		}
+		fmt.Print(code)
+		fmt.Println(address)
+		return nil
+	case "keys":
+		return c.handleServerKeys(args[1:])
+	default:
+		return fmt.Errorf("unknown server subcommand: %s", args[0])
+	}
+}
+
+// handleServerKeys manages the API keys accepted by the HTTP server
+func (c *CLI) handleServerKeys(args []string) error {
+	settings := c.service.Settings()
+	if len(args) == 0 || args[0] == "list" {
+		keys := settings.Server.APIKeys
+		if len(keys) == 0 {
+			fmt.Println("No API keys configured; the server accepts unauthenticated requests.")
+			return nil
+		}
+		fmt.Printf("%-20s %-8s %-14s %s\n", "Name", "Scope", "Key", "Created")
+		for _, key := range keys {
+			fmt.Printf("%-20s %-8s %-14s %s\n", key.Name, key.Scope, key.Prefix+"...", i18n.FormatDate(key.CreatedAt))
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+			return fmt.Errorf("keys add requires a key name")
+		}
+		name, scope := args[1], config.ScopeRead
+		for i := 2; i < len(args); i++ {
+			if args[i] == "--scope" && i+1 < len(args) {
+				scope = args[i+1]
+				i++
+			}
+		}
+
+		plaintext, err := settings.AddAPIKey(name, scope)
+		if err != nil {
+			return err
+		}
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("failed to save API key: %w", err)
+		}
+		fmt.Printf("Created %s key %q:\n\n  %s\n\n", scope, name, plaintext)
+		fmt.Println("Store it now; only a hash is kept. Send it as 'Authorization: Bearer <key>' or 'X-API-Key: <key>'.")
+		if len(settings.Server.APIKeys) == 1 {
+			fmt.Println("The server now requires an API key for every request.")
+		}
+		return nil
+	case "revoke", "rm":
+		if len(args) < 2 {
+			return fmt.Errorf("keys revoke requires a key name")
+		}
+		if err := settings.RevokeAPIKey(args[1]); err != nil {
+			return err
+		}
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		fmt.Printf("Revoked API key %q\n", args[1])
+		if len(settings.Server.APIKeys) == 0 {
+			fmt.Println("No keys remain; the server accepts unauthenticated requests again.")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown keys subcommand: %s", args[0])
+	}
+}
+
+// handleSources manages the libraries registered for federated search
+func (c *CLI) handleSources(args []string) error {
+	settings := c.service.Settings()
+	if len(args) == 0 || args[0] == "list" || args[0] == "ls" {
+		fmt.Printf("%-16s %-10s %s\n", "Name", "Type", "Location")
+		fmt.Printf("%-16s %-10s %s\n", config.LocalSourceName, "library", c.service.GetBaseDir())
+		for _, source := range settings.Sources {
+			location := source.Location()
+			if source.Mirror != "" {
+				if refreshedAt, ok := git.MirrorRefreshedAt(mirrorDir(source.Name)); ok {
+					location += fmt.Sprintf(" (refreshed %s)", i18n.FormatDateTime(refreshedAt))
+				} else {
+					location += " (not cloned yet)"
+				}
+			}
+			fmt.Printf("%-16s %-10s %s\n", source.Name, source.Kind(), location)
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			return fmt.Errorf("sources add requires a name and a directory, server URL or git repository")
+		}
+		var apiKeyEnv, branch, refresh string
+		var mirror bool
+		for i := 3; i < len(args); i++ {
+			switch args[i] {
+			case "--api-key-env":
+				if i+1 < len(args) {
+					apiKeyEnv = args[i+1]
+					i++
+				}
+			case "--mirror":
+				mirror = true
+			case "--branch":
+				if i+1 < len(args) {
+					branch = args[i+1]
+					i++
+				}
+			case "--refresh":
+				if i+1 < len(args) {
+					refresh = args[i+1]
+					i++
+				}
+			}
+		}
+		var err error
+		if mirror {
+			err = settings.AddMirror(args[1], args[2], branch, refresh)
+		} else {
+			err = settings.AddSource(args[1], args[2], apiKeyEnv)
+		}
+		if err != nil {
+			return err
+		}
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		source := settings.FindSource(args[1])
+		fmt.Printf("Added %s source %q (%s)\n", source.Kind(), source.Name, source.Location())
+		if source.IsServer() {
+			fmt.Println(c.out.muted("Saved in " + settings.UserPath() + ", outside the library"))
+		}
+		if mirror {
+			fmt.Println("Cloning...")
+			if err := git.RefreshMirror(mirrorDir(source.Name), source.Mirror, source.Branch); err != nil {
+				return fmt.Errorf("failed to clone mirror (it will be retried on next use): %w", err)
+			}
+			fmt.Printf("Mirror ready; it refreshes from upstream every %s\n", source.RefreshInterval())
+		}
+		return nil
+	case "refresh":
+		refreshed := 0
+		for _, source := range settings.Sources {
+			if source.Mirror == "" || (len(args) > 1 && source.Name != args[1]) {
+				continue
+			}
+			if err := git.RefreshMirror(mirrorDir(source.Name), source.Mirror, source.Branch); err != nil {
+				return fmt.Errorf("failed to refresh %s: %w", source.Name, err)
+			}
+			fmt.Printf("Refreshed %s from %s\n", source.Name, source.Mirror)
+			refreshed++
+		}
+		if refreshed == 0 {
+			if len(args) > 1 {
+				return fmt.Errorf("no mirror source named %q", args[1])
+			}
+			fmt.Println("No mirror sources registered")
+		}
+		return nil
+	case "remove", "rm":
+		if len(args) < 2 {
+			return fmt.Errorf("sources remove requires a source name")
+		}
+		source := settings.FindSource(args[1])
+		if source == nil {
+			return fmt.Errorf("source %q not found", args[1])
+		}
+		if source.Mirror != "" {
+			if err := os.RemoveAll(mirrorDir(source.Name)); err != nil {
+				return fmt.Errorf("failed to remove mirror clone: %w", err)
+			}
+		}
+		settings.RemoveSource(args[1])
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		fmt.Printf("Removed source %q\n", args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown sources subcommand: %s", args[0])
+	}
+}
+
+// mirrorDir returns the clone location of a mirror source, or "" when the
+// cache directory is unavailable
+func mirrorDir(name string) string {
+	dir, _ := git.MirrorDir(name)
+	return dir
+}
+
+// federatedSearch searches the local library and registered sources together
+func (c *CLI) federatedSearch(args []string) error {
+	var query, format string
+	var selected []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--all-sources":
+		case "--source":
 			if i+1 < len(args) {
-				template.Content = args[i+1]
+				selected = strings.Split(args[i+1], ",")
 				i++
 			}
-		case "--slots":
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if query == "" {
+				query = args[i]
+			}
+		}
+	}
+	if query == "" {
+		return fmt.Errorf("search query is required")
+	}
+
+	fed := federation.New(c.service, c.service.Settings().Sources)
+	names, err := fed.Resolve(selected)
+	if err != nil {
+		return err
+	}
+
+	prompts, errs := fed.Search(context.Background(), names, query)
+	for _, err := range errs {
+		warnf("%v", err)
+	}
+	if len(errs) == len(names) {
+		return fmt.Errorf("no source could be searched")
+	}
+	return c.formatOutput(prompts, format)
+}
+
+// hasFlag reports whether flag appears in args
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// defaults returns the CLI defaults from the library configuration. A remote
+// CLI has none.
+func (c *CLI) defaults() config.CLIConfig {
+	if c.service == nil {
+		return config.CLIConfig{}
+	}
+	return c.service.Settings().CLI
+}
+
+// outputFormat returns the --format value given, or else the configured
+// default format when the command supports it
+func (c *CLI) outputFormat(given string, supported ...string) string {
+	if given != "" {
+		return given
+	}
+	if format := c.defaults().Format; slices.Contains(supported, format) {
+		return format
+	}
+	return ""
+}
+
+// confirm asks a yes/no question, defaulting to no, unless the configuration
+// turns confirmations off
+func (c *CLI) confirm(question string) bool {
+	if c.defaults().SkipConfirm() {
+		return true
+	}
+	fmt.Printf("%s (y/N): ", question)
+	var response string
+	fmt.Scanln(&response)
+	response = strings.ToLower(response)
+	return response == "y" || response == "yes"
+}
+
+// serverAddress returns the host and port of the API server. A running
+// server gives its own: the port can differ from server.port after a port
+// fallback, and the host is its MagicDNS name on a tailnet. Otherwise the
+// host is "" and the port the configured one.
+func (c *CLI) serverAddress() (host string, port int) {
+	if running := c.service.RunningServer(); running != nil {
+		if u, err := url.Parse(running.URL); err == nil && u.Hostname() != "localhost" {
+			host = u.Hostname()
+		}
+		return host, running.Port
+	}
+	return "", c.service.Settings().Server.ListenPort()
+}
+
+// serverURL returns the API server address as reachable from other devices,
+// preferring the first private LAN address when no host is given
+func serverURL(host string, port int) string {
+	if host == "" {
+		host = "localhost"
+		if addrs, err := net.InterfaceAddrs(); err == nil {
+			for _, addr := range addrs {
+				if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil && ipNet.IP.IsPrivate() {
+					host = ipNet.IP.String()
+					break
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("http://%s", net.JoinHostPort(host, strconv.Itoa(port)))
+}
+
+// formatOutput formats prompts for output. --columns and --no-truncate imply
+// the table format.
+func (c *CLI) formatOutput(prompts []*models.Prompt, format string) error {
+	format = c.outputFormat(format, "json", "ids", "table")
+	if format == "" && (len(c.table.columns) > 0 || c.table.noTruncate) {
+		format = "table"
+	}
+	switch format {
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(prompts)
+	case "ids":
+		for _, p := range prompts {
+			fmt.Println(p.ID)
+		}
+	case "table":
+		c.printTable(os.Stdout, prompts)
+	default:
+		for _, p := range prompts {
+			if p.Source != "" {
+				fmt.Printf("%s ", c.out.muted("["+p.Source+"]"))
+			}
+			fmt.Printf("%s - %s\n", c.out.id(p.ID), p.Name)
+			if p.Summary != "" {
+				fmt.Printf("  %s\n", c.out.muted(p.Summary))
+			}
+			if len(p.Tags) > 0 {
+				fmt.Printf("  %s %s\n", c.out.header("Tags:"), c.out.tags(p.Tags, ", "))
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+// formatSinglePrompt formats a single prompt for output, with the length of
+// its content when stats is set
+func (c *CLI) formatSinglePrompt(prompt *models.Prompt, format string, stats *service.TextStats) error {
+	switch c.outputFormat(format, "json") {
+	case "json":
+		if stats != nil {
+			return json.NewEncoder(os.Stdout).Encode(struct {
+				*models.Prompt
+				Stats *service.TextStats
+			}{prompt, stats})
+		}
+		return json.NewEncoder(os.Stdout).Encode(prompt)
+	default:
+		field := func(label, value string) {
+			fmt.Printf("%s %s\n", c.out.header(label+":"), value)
+		}
+		field("ID", c.out.id(prompt.ID))
+		field("Title", prompt.Name)
+		field("Version", prompt.Version)
+		if prompt.Summary != "" {
+			field("Description", prompt.Summary)
+		}
+		if len(prompt.Tags) > 0 {
+			field("Tags", c.out.tags(prompt.Tags, ", "))
+		}
+		if prompt.TemplateRef != "" {
+			field("Template", prompt.TemplateRef)
+		}
+		if prompt.Language != "" {
+			field("Language", prompt.Language)
+		}
+		if prompt.TranslationOf != "" {
+			field("Translation of", c.out.id(prompt.TranslationOf))
+		}
+		if c.service != nil {
+			if translations, err := c.service.Translations(prompt.ID); err == nil && len(translations) > 0 {
+				var ids []string
+				for _, t := range translations {
+					ids = append(ids, c.out.id(t.ID)+" ("+t.Language+")")
+				}
+				field("Translations", strings.Join(ids, ", "))
+			}
+		}
+		field("Created", i18n.FormatDateTime(prompt.CreatedAt))
+		field("Updated", i18n.FormatDateTime(prompt.UpdatedAt))
+		if len(prompt.Attachments) > 0 {
+			fmt.Println(c.out.header("Attachments:"))
+			for _, attachment := range prompt.Attachments {
+				fmt.Printf("  %s\n", c.attachmentLink(attachment))
+			}
+		}
+		if stats != nil {
+			field("Length", fmt.Sprintf("%d words, %d characters, %d lines", stats.Words, stats.Characters, stats.Lines))
+			field("Tokens", fmt.Sprintf("about %d", stats.Tokens))
+			field("Reading time", fmt.Sprintf("about %d min", stats.ReadingMinutes))
+		}
+		fmt.Printf("\n%s\n%s\n", c.out.header("Content:"), prompt.Content)
+	}
+	return nil
+}
+
+// attachmentLink returns a file:// link to an attachment, or the bare
+// reference when there is no local library to resolve it against
+func (c *CLI) attachmentLink(attachment string) string {
+	if c.service == nil {
+		return attachment
+	}
+	path, err := c.service.AttachmentPath(attachment)
+	if err != nil {
+		return attachment
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// Additional command handlers would go here...
+// This is a simplified implementation focusing on core functionality
+
+func (c *CLI) handleTemplates(args []string) error {
+	if len(args) > 0 && args[0] == "--stats" {
+		return c.templateStats(args[1:])
+	}
+	if len(args) == 0 {
+		// List templates
+		result, err := c.runCommand("list-templates", nil)
+		if err != nil {
+			return fmt.Errorf("failed to list templates: %w", err)
+		}
+
+		for _, t := range result.Data.([]*models.Template) {
+			fmt.Printf("%s - %s\n", t.ID, t.Name)
+			if t.Description != "" {
+				fmt.Printf("  %s\n", t.Description)
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+
+	subcommand := args[0]
+	switch subcommand {
+	case "show":
+		if len(args) < 2 {
+			return fmt.Errorf("templates show requires a template ID")
+		}
+		template, err := c.getTemplate(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to get template: %w", err)
+		}
+		
+		fmt.Printf("ID: %s\n", template.ID)
+		fmt.Printf("Name: %s\n", template.Name)
+		if template.Description != "" {
+			fmt.Printf("Description: %s\n", template.Description)
+		}
+		fmt.Printf("Created: %s\n", i18n.FormatDateTime(template.CreatedAt))
+		fmt.Printf("Updated: %s\n", i18n.FormatDateTime(template.UpdatedAt))
+		if usage, err := c.service.TemplateUsage(template.ID); err == nil {
+			fmt.Printf("Usage: %s\n", usage.Summary())
+		}
+		fmt.Printf("\nContent:\n%s\n", template.Content)
+		
+		if len(template.Slots) > 0 {
+			fmt.Println("\nSlots:")
+			for _, slot := range template.Slots {
+				fmt.Printf("  %s", slot.Name)
+				if slot.Required {
+					fmt.Print(" [required]")
+				}
+				if slot.Default != "" {
+					fmt.Printf(" [default: %s]", slot.Default)
+				}
+				if slot.Description != "" {
+					fmt.Printf(" - %s", slot.Description)
+				}
+				fmt.Println()
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown templates subcommand: %s", subcommand)
+	}
+}
+
+// templateStats prints how many prompts use each template and how often
+// they are rendered, most used first
+func (c *CLI) templateStats(args []string) error {
+	var format string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			return fmt.Errorf("unknown option: %s", args[i])
+		}
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "table" && format != "json" {
+		return fmt.Errorf("unsupported template stats format %q (expected table or json)", format)
+	}
+
+	byID, err := c.service.TemplateStats()
+	if err != nil {
+		return fmt.Errorf("failed to count template usage: %w", err)
+	}
+	stats := make([]service.TemplateStats, 0, len(byID))
+	for _, st := range byID {
+		stats = append(stats, st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if len(stats[i].Prompts) != len(stats[j].Prompts) {
+			return len(stats[i].Prompts) > len(stats[j].Prompts)
+		}
+		if stats[i].Renders != stats[j].Renders {
+			return stats[i].Renders > stats[j].Renders
+		}
+		return stats[i].ID < stats[j].ID
+	})
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+	if len(stats) == 0 {
+		fmt.Println("No templates")
+		return nil
+	}
+	fmt.Printf("%-24s %-30s %8s %8s  %s\n", "ID", "Name", "Prompts", "Renders", "Last used")
+	fmt.Println(strings.Repeat("-", 86))
+	for _, st := range stats {
+		name := st.Name
+		if len(name) > 30 {
+			name = name[:27] + "..."
+		}
+		lastUsed := "never"
+		if !st.LastUsed.IsZero() {
+			lastUsed = i18n.FormatDate(st.LastUsed)
+		}
+		fmt.Printf("%-24s %-30s %8d %8d  %s\n", st.ID, name, len(st.Prompts), st.Renders, lastUsed)
+	}
+	return nil
+}
+
+func (c *CLI) handleTags(args []string) error {
+	tags, err := c.service.GetAllTags()
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	for _, tag := range tags {
+		fmt.Println(tag)
+	}
+	return nil
+}
+
+func (c *CLI) handleArchive(args []string) error {
+	if len(args) == 0 {
+		// List archived prompts
+		prompts, err := c.service.ListArchivedPrompts()
+		if err != nil {
+			return fmt.Errorf("failed to list archived prompts: %w", err)
+		}
+		return c.formatOutput(prompts, "")
+	}
+	return fmt.Errorf("archive subcommands not implemented")
+}
+
+// handleReviewTransition moves a prompt through the review workflow
+func (c *CLI) handleReviewTransition(action string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s requires a prompt ID", action)
+	}
+
+	id := args[0]
+	var note string
+	commit := true
+
+	// Parse flags
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--note", "-m":
+			if i+1 < len(args) {
+				note = args[i+1]
+				i++
+			}
+		case "--no-commit":
+			commit = false
+		}
+	}
+
+	var result *service.ReviewResult
+	var err error
+	var verb string
+	switch action {
+	case "propose":
+		result, err = c.service.ProposePrompt(id, note, commit)
+		verb = "Proposed"
+	case "approve":
+		result, err = c.service.ApprovePrompt(id, note, commit)
+		verb = "Approved"
+	default:
+		result, err = c.service.RejectPrompt(id, note, commit)
+		verb = "Rejected"
+	}
+	if err != nil {
+		if result == nil {
+			return fmt.Errorf("failed to %s prompt: %w", action, err)
+		}
+		warnf("%v", err)
+	}
+
+	fmt.Printf("%s prompt: %s\n", verb, id)
+	if result.Merged {
+		fmt.Printf("Merged %s\n", git.ReviewBranch(id))
+	}
+	if result.Committed {
+		fmt.Printf("Committed to branch %s\n", result.Branch)
+	}
+	if action == "propose" && result.Branch != "" {
+		fmt.Printf("Share it for review with: git push -u origin %s\n", result.Branch)
+	}
+	return nil
+}
+
+// handleReviewQueue lists prompts that are not approved and unmerged proposals
+func (c *CLI) handleReviewQueue(args []string) error {
+	var format string
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--format" || args[i] == "-f") && i+1 < len(args) {
+			format = args[i+1]
+			i++
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	queue, err := c.service.ReviewQueue()
+	if err != nil {
+		return fmt.Errorf("failed to load review queue: %w", err)
+	}
+	branches := c.service.UnmergedReviewBranches()
+
+	if format == "json" {
+		return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"prompts":  queue,
+			"branches": branches,
+		})
+	}
+
+	if len(queue) == 0 && len(branches) == 0 {
+		fmt.Println("Nothing awaiting review")
+		return nil
+	}
+
+	if len(queue) > 0 {
+		fmt.Printf("%-24s %-10s %-16s %s\n", "ID", "State", "By", "Note")
+		for _, p := range queue {
+			by := p.Review.ProposedBy
+			if p.ReviewState() == models.ReviewRejected {
+				by = p.Review.ReviewedBy
+			}
+			fmt.Printf("%-24s %-10s %-16s %s\n", p.ID, p.ReviewState(), by, p.Review.Note)
+		}
+	}
+
+	if len(branches) > 0 {
+		if len(queue) > 0 {
+			fmt.Println()
+		}
+		fmt.Println("Unmerged review branches:")
+		for _, branch := range branches {
+			fmt.Printf("  %s\n", branch)
+		}
+	}
+	return nil
+}
+
+// handleLock takes or releases the advisory lock telling teammates a prompt
+// is being edited
+func (c *CLI) handleLock(action string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s requires a prompt ID", action)
+	}
+
+	id := args[0]
+	opts := service.LockOptions{Force: hasFlag(args, "--force")}
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--note", "-m":
+			if i+1 < len(args) {
+				opts.Note = args[i+1]
+				i++
+			}
+		case "--for":
+			if i+1 < len(args) {
+				ttl, err := time.ParseDuration(args[i+1])
+				if err != nil || ttl <= 0 {
+					return fmt.Errorf("invalid --for duration: %s", args[i+1])
+				}
+				opts.TTL = ttl
+				i++
+			}
+		}
+	}
+
+	if action == "unlock" {
+		if err := c.service.UnlockPrompt(id, "", opts.Force); err != nil {
+			return fmt.Errorf("failed to unlock prompt: %w", err)
+		}
+		fmt.Printf("Unlocked prompt: %s\n", id)
+		return nil
+	}
+
+	lock, err := c.service.LockPrompt(id, opts)
+	if err != nil {
+		return fmt.Errorf("failed to lock prompt: %w", err)
+	}
+	fmt.Printf("Locked prompt: %s (by %s until %s)\n", id, lock.Owner, i18n.FormatDateTime(lock.Expires))
+	return nil
+}
+
+// handleProtect protects a prompt against deletes and overwrites, or removes
+// the protection. With no ID, protect lists the protected prompts.
+func (c *CLI) handleProtect(action string, args []string) error {
+	if len(args) == 0 {
+		if action == "unprotect" {
+			return fmt.Errorf("unprotect requires a prompt ID")
+		}
+		prompts, err := c.service.ListProtected()
+		if err != nil {
+			return err
+		}
+		if len(prompts) == 0 {
+			fmt.Println("No protected prompts")
+			return nil
+		}
+		for _, p := range prompts {
+			source := "frontmatter"
+			if !p.Protected {
+				source = "config"
+			}
+			fmt.Printf("%-30s %-12s %s\n", p.ID, source, p.Title())
+		}
+		return nil
+	}
+
+	done := "Protected"
+	if action == "unprotect" {
+		done = "Unprotected"
+	}
+	for _, id := range args {
+		if _, err := c.service.ProtectPrompt(id, action == "protect"); err != nil {
+			return fmt.Errorf("failed to %s %s: %w", action, id, err)
+		}
+		fmt.Printf("%s prompt: %s\n", done, id)
+	}
+	return nil
+}
+
+// handleProject shows the project library found above the working
+// directory, or creates one there with init
+func (c *CLI) handleProject(args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	if len(args) > 0 {
+		if args[0] != "init" {
+			return fmt.Errorf("unknown project subcommand: %s", args[0])
+		}
+		dir, err := service.InitProjectLibrary(wd)
+		if err != nil {
+			return fmt.Errorf("failed to create project library: %w", err)
+		}
+		fmt.Printf("Initialized project library in %s\n", dir)
+		fmt.Println("Add prompts with 'pkt create <id> --project' and commit them with the project")
+		return nil
+	}
+
+	dir, mode := c.service.ProjectLibrary()
+	if dir == "" {
+		if found := service.FindProjectLibrary(wd); found != "" && c.service.Settings().Project.LibraryMode() == config.ProjectOff {
+			fmt.Printf("Ignoring project library %s (project.mode is off)\n", found)
+			return nil
+		}
+		fmt.Println("No project library (create one with 'pkt project init')")
+		return nil
+	}
+	fmt.Printf("Project library: %s (%s)\n", dir, mode)
+	if mode != config.ProjectMerge {
+		return nil
+	}
+
+	prompts, err := c.service.ListPrompts()
+	if err != nil {
+		return err
+	}
+	for _, p := range prompts {
+		if c.service.InProjectLibrary(p.ID) {
+			fmt.Printf("  %-30s %s\n", p.ID, p.Title())
+		}
+	}
+	return nil
+}
+
+// handleSuggest ranks prompts against the project in the working directory
+func (c *CLI) handleSuggest(args []string) error {
+	dir := "."
+	limit := 5
+	var format string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--dir":
+			if i+1 < len(args) {
+				dir = args[i+1]
+				i++
+			}
+		case "--limit", "-n":
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 0 {
+					return fmt.Errorf("invalid --limit %q (expected a number, 0 for all)", args[i+1])
+				}
+				limit = n
+				i++
+			}
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	project, err := detect.Detect(dir)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", dir, err)
+	}
+	suggestions, err := c.service.SuggestPrompts(project, limit)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"project":     project,
+			"suggestions": suggestions,
+		})
+	}
+
+	fmt.Printf("Detected %s in %s\n", strings.Join(project.Signals(), ", "), project.Root)
+	if len(suggestions) == 0 {
+		fmt.Println("No prompts match; tag prompts with the languages, frameworks and tools they suit")
+		return nil
+	}
+	fmt.Println()
+	for _, s := range suggestions {
+		fmt.Printf("%-30s %-36s %s\n", s.ID, s.Title, strings.Join(s.Matches, ", "))
+	}
+	return nil
+}
+
+// handleSummarize writes summaries for the prompts given, or for every
+// prompt, and saves the ones accepted; --missing-only skips prompts that have
+// one. In a terminal each is shown for review; elsewhere they are only listed
+// unless --yes accepts them all.
+func (c *CLI) handleSummarize(args []string) error {
+	var ids []string
+	var missingOnly, yes, dryRun bool
+	var format string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--missing-only", "-m":
+			missingOnly = true
+		case "--yes", "-y":
+			yes = true
+		case "--dry-run", "-n":
+			dryRun = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown summarize option: %s", args[i])
+			}
+			ids = append(ids, args[i])
+		}
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported summarize format %q (expected text or json)", format)
+	}
+	if c.defaults().SkipConfirm() && !dryRun {
+		yes = true
+	}
+
+	candidates, err := c.service.SummaryCandidates(ids, missingOnly)
+	if err != nil {
+		return err
+	}
+	interactive := !yes && !dryRun && format != "json" && term.IsTerminal(int(os.Stdin.Fd()))
+	if len(candidates) == 0 {
+		if format != "json" {
+			fmt.Println("No prompts need a summary")
+			return nil
+		}
+	} else if c.service.Settings().Summarize.Command != "" {
+		fmt.Fprintf(os.Stderr, "Summarizing %d prompts with %s\n", len(candidates), c.service.Settings().Summarize.Command)
+	}
+
+	proposals := []*service.SummaryProposal{}
+	reader := bufio.NewReader(os.Stdin)
+	failed := 0
+	for _, p := range candidates {
+		proposal, err := c.service.ProposeSummary(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipped: %v\n", err)
+			failed++
+			continue
+		}
+		proposals = append(proposals, proposal)
+		if format == "json" && !yes {
+			continue
+		}
+
+		if format != "json" {
+			fmt.Printf("%s  %s\n", proposal.ID, proposal.Title)
+			if proposal.Current != "" {
+				fmt.Printf("  was: %s\n", proposal.Current)
+			}
+			fmt.Printf("  new: %s\n", proposal.Summary)
+		}
+		if !yes && !interactive {
+			continue
+		}
+
+		summary := proposal.Summary
+		if interactive {
+			fmt.Print("Save it? [y]es, [n]o, [e]dit, [q]uit: ")
+			answer, _ := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+			case "e", "edit":
+				fmt.Print("Summary: ")
+				edited, _ := reader.ReadString('\n')
+				if summary = strings.TrimSpace(edited); summary == "" {
+					continue
+				}
+			case "q", "quit":
+				return nil
+			default:
+				continue
+			}
+		}
+		saved, err := c.service.ApplySummary(proposal.ID, summary)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save summary for %s: %v\n", proposal.ID, err)
+			failed++
+			continue
+		}
+		proposal.Summary = saved.Summary
+		proposal.Version = saved.Version
+		if format != "json" {
+			fmt.Printf("  saved as v%s\n", saved.Version)
+		}
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(proposals); err != nil {
+			return err
+		}
+	} else if dryRun && len(proposals) > 0 {
+		fmt.Println("Run again without --dry-run to save them.")
+	} else if !yes && !interactive && len(proposals) > 0 {
+		fmt.Println("Run again with --yes to save them.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d prompts could not be summarized", failed, len(candidates))
+	}
+	return nil
+}
+
+// handleAutotag proposes tags for the prompts given, or for every prompt
+// with fewer than autotag.min_tags tags, shows them as a diff and adds them
+// once confirmed. --dry-run only shows the diff; elsewhere than a terminal
+// nothing is added unless --yes is given.
+func (c *CLI) handleAutotag(args []string) error {
+	var ids []string
+	var yes, dryRun bool
+	var format string
+	max := 0
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--yes", "-y":
+			yes = true
+		case "--dry-run", "-n":
+			dryRun = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case "--max":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--max requires a number")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid --max %q (expected a positive number)", args[i+1])
+			}
+			max = n
+			i++
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown autotag option: %s", args[i])
+			}
+			ids = append(ids, args[i])
+		}
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported autotag format %q (expected text or json)", format)
+	}
+	if c.defaults().SkipConfirm() && !dryRun {
+		yes = true
+	}
+
+	candidates, err := c.service.AutotagCandidates(ids)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 && format != "json" {
+		fmt.Println("No prompts need tags")
+		return nil
+	}
+	if command := c.service.Settings().Autotag.Command; command != "" && len(candidates) > 0 {
+		fmt.Fprintf(os.Stderr, "Tagging %d prompts with %s\n", len(candidates), command)
+	}
+
+	proposals, skipped, err := c.service.ProposeTags(candidates, max)
+	if err != nil {
+		return err
+	}
+	if proposals == nil {
+		proposals = []*service.AutotagProposal{}
+	}
+	for _, skip := range skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s: %s\n", skip.ID, skip.Reason)
+	}
+
+	if format != "json" {
+		for _, proposal := range proposals {
+			fmt.Printf("%s  %s\n", proposal.ID, proposal.Title)
+			if len(proposal.Tags) > 0 {
+				fmt.Printf("  tags: %s\n", strings.Join(proposal.Tags, ", "))
+			}
+			fmt.Printf("  + %s\n", strings.Join(proposal.Added, ", "))
+		}
+	}
+
+	apply := yes && !dryRun && len(proposals) > 0
+	if !yes && !dryRun && format != "json" && len(proposals) > 0 && term.IsTerminal(int(os.Stdin.Fd())) {
+		apply = c.confirm(fmt.Sprintf("Add these tags to %d prompts?", len(proposals)))
+	}
+	if apply {
+		skipped, err := c.service.ApplyTags(proposals)
+		if err != nil {
+			return fmt.Errorf("autotag failed: %w", err)
+		}
+		for _, skip := range skipped {
+			fmt.Fprintf(os.Stderr, "Skipped %s: %s\n", skip.ID, skip.Reason)
+		}
+		if format != "json" {
+			fmt.Printf("Tagged %d prompts\n", len(proposals)-len(skipped))
+		}
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(proposals)
+	}
+	if !apply && len(proposals) > 0 {
+		if dryRun {
+			fmt.Println("Run again without --dry-run to add them.")
+		} else if !term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Println("Run again with --yes to add them.")
+		}
+	}
+	return nil
+}
+
+// handleTranslate translates a prompt into another language with the
+// translate command and saves the result as a new prompt linked to the
+// original, or with --dry-run only shows it
+func (c *CLI) handleTranslate(args []string) error {
+	var id, to, newID, format string
+	var dryRun bool
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--to":
+			if i+1 < len(args) {
+				to = args[i+1]
+				i++
+			}
+		case "--id":
+			if i+1 < len(args) {
+				newID = args[i+1]
+				i++
+			}
+		case "--dry-run", "-n":
+			dryRun = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown translate option: %s", args[i])
+			}
+			if id != "" {
+				return fmt.Errorf("translate takes one prompt ID")
+			}
+			id = args[i]
+		}
+	}
+	if id == "" || to == "" {
+		return fmt.Errorf("usage: pkt translate <id> --to <language> [--id new-id] [--dry-run]")
+	}
+	id, err := c.resolvePromptID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	if command := c.service.Settings().Translate.Command; command != "" {
+		fmt.Fprintf(os.Stderr, "Translating %s into %s with %s\n", id, language.Name(to), command)
+	}
+	translated, err := c.service.TranslatePrompt(id, to, newID)
+	if err != nil {
+		return fmt.Errorf("translate failed: %w", err)
+	}
+	if dryRun {
+		return c.formatSinglePrompt(translated, format, nil)
+	}
+	if err := c.service.CreatePrompt(translated); err != nil {
+		return fmt.Errorf("failed to save translation: %w", err)
+	}
+	if c.outputFormat(format, "json") == "json" {
+		return c.formatSinglePrompt(translated, format, nil)
+	}
+	fmt.Printf("Created prompt: %s (%s translation of %s)\n", translated.ID, language.Name(translated.Language), id)
+	return nil
+}
+
+// handleCheckLinks checks the URLs and library docs that prompts reference,
+// exiting with an error when any of them is broken
+func (c *CLI) handleCheckLinks(args []string) error {
+	var ids []string
+	var format string
+	var all bool
+	timeout := service.DefaultLinkTimeout
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--all", "-a":
+			all = true
+		case "--timeout":
+			if i+1 < len(args) {
+				d, err := time.ParseDuration(args[i+1])
+				if err != nil || d <= 0 {
+					return fmt.Errorf("invalid timeout %q (expected a duration such as 10s)", args[i+1])
+				}
+				timeout = d
+				i++
+			}
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown check-links option: %s", args[i])
+			}
+			ids = append(ids, args[i])
+		}
+	}
+
+	prompts, err := c.service.LinkCheckCandidates(ids)
+	if err != nil {
+		return err
+	}
+	report := c.service.CheckLinks(prompts, service.LinkCheckOptions{Timeout: timeout})
+
+	if c.outputFormat(format, "json") == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else if len(report.Links) == 0 {
+		fmt.Println("No prompts declare references")
+	} else {
+		for _, link := range report.Links {
+			if !link.Failed() && !all {
+				continue
+			}
+			detail := link.Error
+			if link.Status != 0 {
+				detail = fmt.Sprintf("%d %s", link.Status, http.StatusText(link.Status))
+			}
+			fmt.Printf("%-12s %s", link.Result, link.Reference)
+			if detail != "" {
+				fmt.Printf(" (%s)", detail)
+			}
+			fmt.Printf("\n%-12s used by %s\n", "", strings.Join(link.Prompts, ", "))
+		}
+		fmt.Printf("Checked %d references in %d prompts: %d need attention\n", len(report.Links), report.Prompts, report.Failed)
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d referenced resources need attention", report.Failed, len(report.Links))
+	}
+	return nil
+}
+
+// handleKeys prints the TUI keybindings, with any set in ui.keys in place of
+// the defaults, as a Markdown cheat sheet or JSON
+func (c *CLI) handleKeys(args []string) error {
+	var format string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			return fmt.Errorf("unknown keys option: %s", args[i])
+		}
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "md" && format != "markdown" && format != "json" {
+		return fmt.Errorf("unsupported keys format %q (expected md or json)", format)
+	}
+
+	groups, err := ui.KeyGroups(c.service.Settings().UI.Keys)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(groups)
+	}
+	fmt.Print(ui.KeysMarkdown(groups))
+	return nil
+}
+
+// handleMove moves prompts between packs: those named by ID, or those of
+// one pack, or of the personal library, that have the tags or match the
+// boolean search given
+func (c *CLI) handleMove(args []string) error {
+	var ids, tags []string
+	var search, fromPack, toPack, format string
+	var dryRun bool
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--tag", "-t":
+			if i+1 < len(args) {
+				tags = append(tags, args[i+1])
+				i++
+			}
+		case "--search", "-s":
+			if i+1 < len(args) {
+				search = args[i+1]
+				i++
+			}
+		case "--from-pack":
+			if i+1 < len(args) {
+				fromPack = args[i+1]
+				i++
+			}
+		case "--to-pack":
+			if i+1 < len(args) {
+				toPack = args[i+1]
+				i++
+			}
+		case "--dry-run", "-n":
+			dryRun = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown move option: %s", args[i])
+			}
+			ids = append(ids, args[i])
+		}
+	}
+	if toPack == "" {
+		return fmt.Errorf("move requires --to-pack (a pack name, or personal)")
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported move format %q (expected text or json)", format)
+	}
+
+	var prompts []*models.Prompt
+	switch {
+	case len(ids) > 0:
+		if len(tags) > 0 || search != "" || fromPack != "" {
+			return fmt.Errorf("give prompt IDs or --tag/--search, not both")
+		}
+		for _, id := range ids {
+			prompt, err := c.service.GetPrompt(id)
+			if err != nil {
+				return err
+			}
+			prompts = append(prompts, prompt)
+		}
+	case len(tags) > 0 || search != "":
+		var parts []*models.BooleanExpression
+		for _, tag := range tags {
+			parts = append(parts, models.NewTagExpression(tag))
+		}
+		if search != "" {
+			expr, err := parseBooleanExpression(search)
+			if err != nil {
+				return fmt.Errorf("invalid boolean expression: %w", err)
+			}
+			parts = append(parts, expr)
+		}
+		expr := parts[0]
+		if len(parts) > 1 {
+			expr = models.NewAndExpression(parts...)
+		}
+		var err error
+		if prompts, err = c.service.MoveCandidates(fromPack, expr); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("move needs prompt IDs, --tag or --search")
+	}
+
+	report, err := c.service.MovePrompts(prompts, toPack, dryRun)
+	if err != nil {
+		return fmt.Errorf("move failed: %w", err)
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	verb := "Moved"
+	if dryRun {
+		verb = "Would move"
+	}
+	fmt.Printf("%s %d of %d prompts to %s\n", verb, len(report.Moved), len(prompts), toPack)
+	for _, change := range report.Moved {
+		version := ""
+		if change.Version != "" {
+			version = " (v" + change.Version + ")"
+		}
+		fmt.Printf("  %-30s %s -> %s%s\n", change.ID, change.From, change.Path, version)
+	}
+	if len(report.Unchanged) > 0 {
+		fmt.Printf("Unchanged: %d already in %s\n", len(report.Unchanged), toPack)
+	}
+	for _, skip := range report.Skipped {
+		fmt.Printf("Skipped %s: %s\n", skip.ID, skip.Reason)
+	}
+	if dryRun && len(report.Moved) > 0 {
+		fmt.Println("Run again without --dry-run to apply.")
+	}
+	return nil
+}
+
+// handleLocks lists the prompts locked for editing
+func (c *CLI) handleLocks(args []string) error {
+	var format string
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--format" || args[i] == "-f") && i+1 < len(args) {
+			format = args[i+1]
+			i++
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	locks, err := c.service.ListLocks()
+	if err != nil {
+		return fmt.Errorf("failed to list locks: %w", err)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(locks)
+	}
+
+	if len(locks) == 0 {
+		fmt.Println("No prompts are locked")
+		return nil
+	}
+	fmt.Printf("%-24s %-16s %-18s %-18s %s\n", "ID", "Owner", "Since", "Expires", "Note")
+	for _, lock := range locks {
+		fmt.Printf("%-24s %-16s %-18s %-18s %s\n", lock.ID, lock.Owner, i18n.FormatDateTime(lock.Since), i18n.FormatDateTime(lock.Expires), lock.Note)
+	}
+	return nil
+}
+
+// handleOutcomeLog logs how a run of a prompt went with --outcome, or lists
+// the prompt's logged outcomes without it
+func (c *CLI) handleOutcomeLog(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("log requires a prompt ID")
+	}
+
+	id := args[0]
+	var outcome, note, format string
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--outcome", "-o":
+			if i+1 < len(args) {
+				outcome = args[i+1]
+				i++
+			}
+		case "--note", "-m":
+			if i+1 < len(args) {
+				note = args[i+1]
+				i++
+			}
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		}
+	}
+
+	if outcome != "" {
+		parsed, err := models.ParseOutcome(outcome)
+		if err != nil {
+			return err
+		}
+		entry, err := c.service.LogOutcome(id, parsed, note)
+		if err != nil {
+			return fmt.Errorf("failed to log outcome: %w", err)
+		}
+		fmt.Printf("Logged %s outcome for %s (v%s)\n", entry.Outcome, id, entry.Version)
+		return nil
+	}
+	if note != "" {
+		return fmt.Errorf("--note needs --outcome good|bad")
+	}
+
+	format = c.outputFormat(format, "json")
+
+	entries, err := c.service.Outcomes(id)
+	if err != nil {
+		return fmt.Errorf("failed to read outcomes: %w", err)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"outcomes": entries,
+			"summary":  models.SummarizeOutcomes(entries),
+		})
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No outcomes logged for %s\n", id)
+		return nil
+	}
+	fmt.Printf("%-18s %-10s %-8s %-16s %s\n", "Time", "Version", "Outcome", "By", "Note")
+	for _, entry := range entries {
+		fmt.Printf("%-18s %-10s %-8s %-16s %s\n", i18n.FormatDateTime(entry.Time.Local()), entry.Version, entry.Outcome, entry.Author, entry.Note)
+	}
+	fmt.Printf("\n%s\n", models.SummarizeOutcomes(entries))
+	return nil
+}
+
+// handleVariants lists the variants of an experiment side by side with how
+// often each was used and how its logged outcomes went
+func (c *CLI) handleVariants(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("variants requires a variant group")
+	}
+
+	group := args[0]
+	var format string
+	for i := 1; i < len(args); i++ {
+		if (args[i] == "--format" || args[i] == "-f") && i+1 < len(args) {
+			format = args[i+1]
+			i++
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	stats, err := c.service.CompareVariants(group)
+	if err != nil {
+		return fmt.Errorf("failed to compare variants: %w", err)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+
+	fmt.Printf("%-24s %-10s %6s %6s %6s %8s  %s\n", "ID", "Version", "Uses", "Good", "Bad", "Success", "Title")
+	for _, st := range stats {
+		success := "-"
+		if st.Outcomes.Total() > 0 {
+			success = fmt.Sprintf("%.0f%%", st.Outcomes.SuccessRate()*100)
+		}
+		fmt.Printf("%-24s %-10s %6d %6d %6d %8s  %s\n", st.ID, st.Version, st.Uses, st.Outcomes.Good, st.Outcomes.Bad, success, st.Title)
+	}
+	return nil
+}
+
+// handleStats prints per-prompt metrics as a table, or as JSON or CSV for
+// loading into reporting tools
+func (c *CLI) handleStats(args []string) error {
+	var format string
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--format" || args[i] == "-f") && i+1 < len(args) {
+			format = args[i+1]
+			i++
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	stats, err := c.service.LibraryStats()
+	if err != nil {
+		return fmt.Errorf("failed to collect stats: %w", err)
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	case "csv":
+		return service.WriteStatsCSV(os.Stdout, stats)
+	case "", "table":
+	default:
+		return fmt.Errorf("unsupported stats format %q (expected table, json or csv)", format)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("No prompts found")
+		return nil
+	}
+
+	totalTokens, totalUses := 0, 0
+	fmt.Printf("%-24s %-8s %-9s %-7s %-5s %s\n", "ID", "Version", "Versions", "Tokens", "Uses", "Last edit")
+	for _, st := range stats {
+		fmt.Printf("%-24s %-8s %-9d %-7d %-5d %s\n",
+			st.ID, st.Version, st.Versions, st.Tokens, st.UsageCount, i18n.FormatDate(st.UpdatedAt))
+		totalTokens += st.Tokens
+		totalUses += st.UsageCount
+	}
+	fmt.Printf("\n%d prompts, ~%d tokens, %d uses\n", len(stats), totalTokens, totalUses)
+	return nil
+}
+
+// handleLint checks prompts against the library's style rules, failing when
+// any rule at error severity is broken, or any rule at all with --strict
+func (c *CLI) handleLint(args []string) error {
+	var opts service.LintOptions
+	var format string
+	strict := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case "--strict":
+			strict = true
+		case "--staged-only":
+			opts.StagedOnly = true
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown option: %s", args[i])
+			}
+			opts.IDs = append(opts.IDs, args[i])
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	results, err := c.service.LintPrompts(opts)
+	if err != nil {
+		return err
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			if issue.Severity == lint.Error {
+				errorCount++
+			} else {
+				warningCount++
+			}
+		}
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	case "", "text":
+		for _, result := range results {
+			for _, issue := range result.Issues {
+				location := result.FilePath
+				if issue.Line > 0 {
+					location = fmt.Sprintf("%s:%d", location, issue.Line)
+				}
+				fmt.Printf("%s: %s: %s (%s)\n", location, c.out.severity(string(issue.Severity), string(issue.Severity)), issue.Message, issue.Rule)
+			}
+		}
+		fmt.Printf("%d prompts checked: %d errors, %d warnings\n", len(results), errorCount, warningCount)
+	default:
+		return fmt.Errorf("unsupported lint format %q (expected text or json)", format)
+	}
+
+	if errorCount > 0 || (strict && warningCount > 0) {
+		return fmt.Errorf("lint failed with %d errors and %d warnings", errorCount, warningCount)
+	}
+	return nil
+}
+
+// handleHooks installs git hooks in the library repository
+func (c *CLI) handleHooks(args []string) error {
+	if len(args) == 0 || args[0] != "install" {
+		return fmt.Errorf("usage: pkt hooks install [--force]")
+	}
+	force := hasFlag(args[1:], "--force")
+
+	path, err := c.service.InstallPreCommitHook(force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installed pre-commit hook at %s\n", path)
+	fmt.Println("Commits now run 'pkt lint --staged-only'; skip it once with 'git commit --no-verify'.")
+	return nil
+}
+
+// CI exit codes: checks passed, checks found problems, checks could not run
+const (
+	ciPassed = 0
+	ciFailed = 1
+	ciBroken = 2
+)
+
+// handleCI runs every validation check and reports the findings as text, JSON
+// or GitHub Actions annotations, exiting with ciFailed when any error is found
+func (c *CLI) handleCI(args []string) error {
+	format := "text"
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		format = "github"
+	}
+	var reportFile string
+	strict := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case "--report":
+			if i+1 < len(args) {
+				reportFile = args[i+1]
+				i++
+			}
+		case "--strict":
+			strict = true
+		default:
+			return &ExitError{Code: ciBroken, Err: fmt.Errorf("unknown option: %s", args[i])}
+		}
+	}
+	if format != "text" && format != "json" && format != "github" {
+		return &ExitError{Code: ciBroken, Err: fmt.Errorf("unsupported ci format %q (expected text, json or github)", format)}
+	}
+
+	report, err := c.service.RunCI()
+	if err != nil {
+		return &ExitError{Code: ciBroken, Err: err}
+	}
+
+	if reportFile != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err == nil {
+			err = os.WriteFile(reportFile, append(data, '\n'), 0644)
+		}
+		if err != nil {
+			return &ExitError{Code: ciBroken, Err: fmt.Errorf("failed to write %s: %w", reportFile, err)}
+		}
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return &ExitError{Code: ciBroken, Err: err}
+		}
+	case "github":
+		prefix := c.service.RepoPrefix()
+		for _, f := range report.Findings {
+			fmt.Println(githubAnnotation(f, prefix))
+		}
+	default:
+		for _, f := range report.Findings {
+			location := f.File
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, f.Line)
+			}
+			name := f.Check
+			if f.Rule != "" && f.Rule != f.Check {
+				name += "/" + f.Rule
+			}
+			fmt.Printf("%s: %s: %s (%s)\n", location, c.out.severity(string(f.Severity), string(f.Severity)), f.Message, name)
+		}
+	}
+	if format != "json" {
+		fmt.Printf("%d prompts checked: %d errors, %d warnings\n", report.Prompts, report.Errors, report.Warnings)
+	}
+
+	if report.Errors > 0 || (strict && report.Warnings > 0) {
+		return &ExitError{Code: ciFailed, Err: fmt.Errorf("ci failed with %d errors and %d warnings", report.Errors, report.Warnings)}
+	}
+	return nil
+}
+
+// githubAnnotation formats a finding as a GitHub Actions workflow command,
+// which GitHub shows on the pull request's changed files. prefix is the
+// library's directory in the repository.
+func githubAnnotation(f service.CIFinding, prefix string) string {
+	level := "warning"
+	if f.Severity == lint.Error {
+		level = "error"
+	}
+	title := f.Check
+	if f.Rule != "" && f.Rule != f.Check {
+		title += "/" + f.Rule
+	}
+
+	props := []string{}
+	if f.File != "" {
+		props = append(props, "file="+escapeAnnotationProperty(prefix+filepath.ToSlash(f.File)))
+		if f.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", f.Line))
+		}
+	}
+	props = append(props, "title="+escapeAnnotationProperty(title))
+	return fmt.Sprintf("::%s %s::%s", level, strings.Join(props, ","), escapeAnnotationData(f.Message))
+}
+
+// escapeAnnotationData escapes a workflow command's message
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a workflow command's property value
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// handleMaintenance tidies the library and reports what it removed, the
+// state of the git repository and disk usage by category
+func (c *CLI) handleMaintenance(args []string) error {
+	dryRun := false
+	var format string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--dry-run", "-n":
+			dryRun = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			return fmt.Errorf("unknown option: %s", args[i])
+		}
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported maintenance format %q (expected text or json)", format)
+	}
+
+	report, err := c.service.RunMaintenance(dryRun)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	removed, archived := "Removed", "Archived"
+	if dryRun {
+		removed, archived = "Would remove", "Would archive"
+	}
+	if len(report.ArchivedPrompts) > 0 {
+		fmt.Printf("%s %d expired prompts\n", archived, len(report.ArchivedPrompts))
+		c.printExpiring(report.ArchivedPrompts)
+	}
+	if len(report.ExpiredPrompts) > 0 {
+		fmt.Printf("Kept %d expired prompts\n", len(report.ExpiredPrompts))
+		c.printExpiring(report.ExpiredPrompts)
+	}
+	if len(report.ExpiringPrompts) > 0 {
+		fmt.Printf("%d prompts expire soon\n", len(report.ExpiringPrompts))
+		c.printExpiring(report.ExpiringPrompts)
+	}
+	fmt.Printf("%s %d archived versions\n", removed, len(report.RemovedVersions))
+	for _, path := range report.RemovedVersions {
+		fmt.Printf("  %s\n", path)
+	}
+	fmt.Printf("%s %d empty directories\n", removed, len(report.RemovedDirs))
+	for _, dir := range report.RemovedDirs {
+		fmt.Printf("  %s\n", dir)
+	}
+	if !dryRun {
+		fmt.Printf("Rebuilt index of %d prompts\n", report.IndexedPrompts)
+	}
+	switch {
+	case !report.GitChecked:
+		fmt.Println("Git: not checked")
+	case len(report.GitProblems) == 0:
+		fmt.Println("Git: repository OK")
+	default:
+		fmt.Printf("Git: fsck reported %d problems\n", len(report.GitProblems))
+		for _, problem := range report.GitProblems {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+	for _, warning := range report.Warnings {
+		warnf("%s", warning)
+	}
+
+	fmt.Println("\nDisk usage:")
+	var total int64
+	for _, u := range report.DiskUsage {
+		fmt.Printf("  %-20s %6d files  %10s\n", u.Category, u.Files, config.FormatSize(u.Bytes))
+		total += u.Bytes
+	}
+	fmt.Printf("  %-20s %6s        %10s\n", "total", "", config.FormatSize(total))
+
+	if len(report.GitProblems) > 0 {
+		return fmt.Errorf("git repository has problems; see above")
+	}
+	return nil
+}
+
+// printExpiring lists prompts with their expiry dates under a maintenance heading
+func (c *CLI) printExpiring(prompts []service.ExpiringPrompt) {
+	for _, p := range prompts {
+		fmt.Printf("  %s  %s  %s\n", c.out.id(p.ID), p.Title, c.out.muted("expires "+p.Expires))
+	}
+}
+
+// handleBench handles 'pkt bench generate' and 'pkt bench run'
+func (c *CLI) handleBench(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("bench subcommand required (generate or run)")
+	}
+
+	switch args[0] {
+	case "generate":
+		opts := bench.GenerateOptions{Count: 1000, Seed: 1}
+		dir := "pocket-prompt-bench"
+		for i := 1; i < len(args); i++ {
+			flag := args[i]
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", flag)
+			}
+			i++
+			value := args[i]
+			switch flag {
+			case "--count", "-n":
+				n, err := strconv.Atoi(value)
+				if err != nil || n <= 0 {
+					return fmt.Errorf("invalid --count %q (expected a positive number)", value)
+				}
+				opts.Count = n
+			case "--seed":
+				seed, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid --seed %q", value)
+				}
+				opts.Seed = seed
+			case "--dir":
+				dir = value
+			default:
+				return fmt.Errorf("unknown option: %s", flag)
+			}
+		}
+
+		start := time.Now()
+		if err := bench.Generate(dir, opts); err != nil {
+			return err
+		}
+		fmt.Printf("Generated %d prompts in %s (%v)\n", opts.Count, dir, time.Since(start).Round(time.Millisecond))
+		fmt.Printf("Measure it with: pkt bench run --dir %s\n", dir)
+		return nil
+
+	case "run":
+		var opts bench.RunOptions
+		var format string
+		dir := c.service.GetBaseDir()
+		for i := 1; i < len(args); i++ {
+			flag := args[i]
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", flag)
+			}
+			i++
+			value := args[i]
+			switch flag {
+			case "--dir":
+				dir = value
+			case "--iterations":
+				n, err := strconv.Atoi(value)
+				if err != nil || n <= 0 {
+					return fmt.Errorf("invalid --iterations %q (expected a positive number)", value)
+				}
+				opts.Iterations = n
+			case "--duration":
+				d, err := time.ParseDuration(value)
+				if err != nil || d <= 0 {
+					return fmt.Errorf("invalid --duration %q (expected a duration such as 3s)", value)
+				}
+				opts.Duration = d
+			case "--concurrency":
+				n, err := strconv.Atoi(value)
+				if err != nil || n <= 0 {
+					return fmt.Errorf("invalid --concurrency %q (expected a positive number)", value)
+				}
+				opts.Concurrency = n
+			case "--format", "-f":
+				format = value
+			default:
+				return fmt.Errorf("unknown option: %s", flag)
+			}
+		}
+		format = c.outputFormat(format, "json")
+		if format != "" && format != "text" && format != "json" {
+			return fmt.Errorf("unsupported bench format %q (expected text or json)", format)
+		}
+
+		results, err := bench.Run(dir, opts)
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(results)
+		}
+
+		fmt.Printf("%-20s %8s %12s %12s %8s\n", "BENCHMARK", "OPS", "PER OP", "OPS/SEC", "ERRORS")
+		for _, r := range results {
+			fmt.Printf("%-20s %8d %12v %12.1f %8d\n", r.Name, r.Ops, roundDuration(r.PerOp), r.OpsPerSec, r.Errors)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown bench subcommand: %s", args[0])
+	}
+}
+
+// roundDuration keeps the three or four most significant digits of d
+func roundDuration(d time.Duration) time.Duration {
+	step := time.Duration(1)
+	for step*1000 < d {
+		step *= 10
+	}
+	return d.Round(step)
+}
+
+// maxSlowQueryGroups is how many slow queries 'pkt doctor --perf' lists as text
+const maxSlowQueryGroups = 20
+
+// handleDoctor handles 'pkt doctor --perf', which shows how long saved
+// searches take and which searches were logged as slow
+func (c *CLI) handleDoctor(args []string) error {
+	perf, size, clear, system := false, false, false, false
+	var format string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--perf":
+			perf = true
+		case "--size":
+			size = true
+		case "--platform":
+			system = true
+		case "--clear":
+			clear = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			return fmt.Errorf("unknown option: %s", args[i])
+		}
+	}
+	if (perf && size) || (system && (perf || size)) {
+		return fmt.Errorf("doctor runs one check at a time: --perf, --size or --platform")
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported doctor format %q (expected text or json)", format)
+	}
+	if !perf {
+		if clear {
+			return fmt.Errorf("--clear only applies to --perf")
+		}
+		if system {
+			return c.doctorPlatform(format)
+		}
+		return c.doctorSize(format)
+	}
+
+	report, err := c.service.PerfReport()
+	if err != nil {
+		return err
+	}
+	if clear {
+		if err := c.service.ClearSlowQueries(); err != nil {
+			return err
+		}
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	threshold := "off"
+	if report.Threshold > 0 {
+		threshold = report.Threshold.String()
+	}
+	fmt.Printf("Library: %d prompts, slow-query threshold %s\n", report.Prompts, threshold)
+
+	fmt.Println()
+	if len(report.SavedSearches) == 0 {
+		fmt.Println("No saved searches")
+	} else {
+		fmt.Println(c.out.header("Saved searches, slowest first:"))
+		fmt.Printf("  %-24s %10s %8s %10s  %s\n", "NAME", "TIME", "RESULTS", "COMPLEXITY", "EXPRESSION")
+		for _, p := range report.SavedSearches {
+			name := fmt.Sprintf("%-24s", p.Name)
+			if p.Slow {
+				name = c.out.severity("warning", name)
+			}
+			fmt.Printf("  %s %10v %8d %10d  %s\n", name, roundDuration(p.Duration), p.Results, p.Complexity, p.Query)
+		}
+	}
+
+	fmt.Println()
+	if len(report.SlowQueries) == 0 {
+		fmt.Println("No slow queries logged")
+		return nil
+	}
+	fmt.Println(c.out.header("Slow queries logged, most total time first:"))
+	fmt.Printf("  %-12s %6s %10s %10s %8s %10s  %s\n", "KIND", "COUNT", "MEAN", "MAX", "PROMPTS", "COMPLEXITY", "QUERY")
+	for i, g := range report.SlowQueries {
+		if i == maxSlowQueryGroups {
+			fmt.Printf("  ... and %d more (--format json lists all)\n", len(report.SlowQueries)-i)
+			break
+		}
+		query := g.Query
+		if g.SavedSearch != "" {
+			query = fmt.Sprintf("%s (saved search %s)", query, g.SavedSearch)
+		}
+		fmt.Printf("  %-12s %6d %10v %10v %8d %10d  %s\n", g.Kind, g.Count, roundDuration(g.Mean), roundDuration(g.Max), g.Prompts, g.Complexity, query)
+	}
+	if clear {
+		fmt.Println("\nCleared the slow-query log")
+	}
+	return nil
+}
+
+// doctorSize reports the library's size against its soft limits and what
+// takes up the most room
+func (c *CLI) doctorSize(format string) error {
+	report, err := c.service.QuotaReport()
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	limit := "no limit"
+	if report.MaxTotalBytes > 0 {
+		limit = "limit " + config.FormatSize(report.MaxTotalBytes)
+	}
+	fmt.Printf("Library: %s without .git and .pocket-prompt, %s\n", config.FormatSize(report.TotalBytes), limit)
+
+	fmt.Println()
+	header := "Prompts per pack, most first:"
+	if report.MaxPromptsPerPack > 0 {
+		header = fmt.Sprintf("Prompts per pack, most first (limit %d):", report.MaxPromptsPerPack)
+	}
+	fmt.Println(c.out.header(header))
+	fmt.Printf("  %-24s %8s %10s\n", "PACK", "PROMPTS", "SIZE")
+	for _, pack := range report.Packs {
+		name := fmt.Sprintf("%-24s", pack.Name)
+		if report.MaxPromptsPerPack > 0 && pack.Prompts > report.MaxPromptsPerPack {
+			name = c.out.severity("warning", name)
+		}
+		fmt.Printf("  %s %8d %10s\n", name, pack.Prompts, config.FormatSize(pack.Bytes))
+	}
+
+	if len(report.LargestFiles) > 0 {
+		fmt.Println()
+		fmt.Println(c.out.header("Largest files:"))
+		for _, f := range report.LargestFiles {
+			fmt.Printf("  %10s  %s\n", config.FormatSize(f.Bytes), f.Path)
+		}
+	}
+
+	fmt.Println()
+	if len(report.Warnings) == 0 {
+		fmt.Println("Within all limits")
+		return nil
+	}
+	for _, warning := range report.Warnings {
+		fmt.Println(c.out.severity("warning", "Warning: "+warning))
+	}
+	return nil
+}
+
+// handleChangelog prints prompt changes grouped by day, as Markdown suitable
+// for release notes
+func (c *CLI) handleChangelog(args []string) error {
+	var opts service.ChangelogOptions
+	var format string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--since":
+			if i+1 < len(args) {
+				since, err := parseChangelogDate(args[i+1])
+				if err != nil {
+					return err
+				}
+				opts.Since = since
+				i++
+			}
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown changelog option: %s", args[i])
+			}
+			opts.ID = args[i]
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	changes, err := c.service.Changelog(opts)
+	if err != nil {
+		return fmt.Errorf("failed to build changelog: %w", err)
+	}
+
+	if format == "json" {
+		return json.NewEncoder(os.Stdout).Encode(changes)
+	}
+	if len(changes) == 0 {
+		fmt.Println("No prompt changes")
+		return nil
+	}
+
+	day := ""
+	for _, change := range changes {
+		if d := i18n.FormatDate(change.Date.Local()); d != day {
+			if day != "" {
+				fmt.Println()
+			}
+			day = d
+			fmt.Printf("## %s\n\n", day)
+		}
+		fmt.Printf("- %s\n", formatChange(change))
+	}
+	return nil
+}
+
+// formatChange renders a changelog entry as one Markdown list item
+func formatChange(change service.PromptChange) string {
+	var b strings.Builder
+	switch change.Kind {
+	case service.ChangeAdded:
+		b.WriteString("**Added** ")
+	case service.ChangeRemoved:
+		b.WriteString("**Removed** ")
+	default:
+		b.WriteString("**Changed** ")
+	}
+	fmt.Fprintf(&b, "`%s` v%s — %s", change.ID, change.Version, change.Title)
+
+	var details []string
+	switch change.Kind {
+	case service.ChangeAdded:
+		details = append(details, fmt.Sprintf("%d lines", change.LinesAdded))
+	case service.ChangeModified:
+		if change.LinesAdded > 0 || change.LinesRemoved > 0 {
+			details = append(details, fmt.Sprintf("+%d/-%d lines", change.LinesAdded, change.LinesRemoved))
+		}
+		details = append(details, change.Notes...)
+	}
+	if len(details) > 0 {
+		b.WriteString(": " + strings.Join(details, "; "))
+	}
+	if change.Author != "" {
+		fmt.Fprintf(&b, " (%s)", change.Author)
+	}
+	return b.String()
+}
+
+// handleDigest previews the next scheduled digest, or sends it now
+func (c *CLI) handleDigest(args []string) error {
+	var since time.Time
+	send := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--send":
+			send = true
+		case "--since":
+			if i+1 < len(args) {
+				date, err := parseChangelogDate(args[i+1])
+				if err != nil {
+					return err
+				}
+				since = date
+				i++
+			}
+		default:
+			return fmt.Errorf("unknown digest option: %s", args[i])
+		}
+	}
+	if send && !since.IsZero() {
+		return fmt.Errorf("--since only applies to previews; a sent digest starts where the last one ended")
+	}
+
+	cfg := c.service.Settings().Digest
+	if send {
+		digest, sent, err := c.service.RunDigest(context.Background(), cfg, time.Now())
+		if err != nil {
+			return err
+		}
+		if !sent {
+			fmt.Println("No changes since the last digest; nothing sent (set digest.send_empty to send anyway)")
+			return nil
+		}
+		fmt.Printf("Sent digest: %s\n", digest.Summary())
+		return nil
+	}
+
+	var digest *service.Digest
+	var err error
+	if since.IsZero() {
+		digest, err = c.service.BuildDigest(cfg, time.Now())
+	} else {
+		digest, err = c.service.BuildDigestSince(cfg, since, time.Now())
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Print(digest.Markdown())
+	return nil
+}
+
+// parseChangelogDate accepts a date (2006-01-02) or a full RFC 3339 timestamp
+func parseChangelogDate(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since date %q (expected YYYY-MM-DD)", value)
+}
+
+// handleMigrate upgrades library files to the current prompt schema
+func (c *CLI) handleMigrate(args []string) error {
+	var dryRun bool
+	commit := true
+	var format string
+
+	// Parse flags
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--dry-run", "--preview":
+			dryRun = true
+		case "--no-commit":
+			commit = false
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	report, err := c.service.MigrateLibrary(dryRun, commit)
+	if err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	if format == "json" {
+		return json.NewEncoder(os.Stdout).Encode(report)
+	}
+
+	if dryRun {
+		fmt.Println("Migration Preview:")
+		fmt.Println("==================")
+	} else {
+		fmt.Println("Migration Complete:")
+		fmt.Println("===================")
+	}
+	fmt.Printf("Scanned %d prompt files, %d need changes\n", report.Scanned, len(report.Changes))
+
+	for _, change := range report.Changes {
+		fmt.Printf("\n%s\n", change.FilePath)
+		for _, action := range change.Actions {
+			fmt.Printf("  - %s\n", action)
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("\nErrors encountered: %d\n", len(report.Errors))
+		for _, err := range report.Errors {
+			fmt.Printf("  - %v\n", err)
+		}
+	}
+
+	if dryRun && len(report.Changes) > 0 {
+		fmt.Printf("\nTo apply these changes, run the same command without --dry-run\n")
+	}
+
+	return nil
+}
+
+// handleRemote syncs the library with the configured hosted prompt registry
+func (c *CLI) handleRemote(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("remote subcommand required (pull, push, sync, status)")
+	}
+
+	direction := remote.DirectionBoth
+	dryRun := false
+	switch args[0] {
+	case "pull":
+		direction = remote.DirectionPull
+	case "push":
+		direction = remote.DirectionPush
+	case "sync":
+	case "status":
+		dryRun = true
+	default:
+		return fmt.Errorf("unknown remote subcommand: %s", args[0])
+	}
+
+	var prefer, format string
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--dry-run", "--preview":
+			dryRun = true
+		case "--prefer":
+			if i+1 < len(args) {
+				prefer = args[i+1]
+				i++
+			}
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		}
+	}
+
+	format = c.outputFormat(format, "json")
+
+	report, err := c.service.SyncRemote(direction, dryRun, prefer)
+	if err != nil {
+		return fmt.Errorf("remote sync failed: %w", err)
+	}
+
+	if format == "json" {
+		return json.NewEncoder(os.Stdout).Encode(report.Items)
+	}
+
+	if dryRun {
+		fmt.Printf("Remote Sync Preview (%s):\n", report.Adapter)
+		fmt.Println("=========================")
+	} else {
+		fmt.Printf("Remote Sync Complete (%s):\n", report.Adapter)
+		fmt.Println("==========================")
+	}
+	fmt.Printf("Push: %d  Pull: %d  Conflicts: %d  In sync: %d\n",
+		report.Count(remote.ActionPush), report.Count(remote.ActionPull),
+		report.Count(remote.ActionConflict), report.Count(remote.ActionInSync))
+
+	for _, item := range report.Items {
+		if item.Action == remote.ActionInSync {
+			continue
+		}
+		fmt.Printf("  %-9s %s (%s)\n", item.Action, item.Name, item.Reason)
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("\nErrors encountered: %d\n", len(report.Errors))
+		for _, err := range report.Errors {
+			fmt.Printf("  - %v\n", err)
+		}
+	}
+
+	if report.Count(remote.ActionConflict) > 0 {
+		fmt.Printf("\nResolve conflicts with --prefer local or --prefer remote\n")
+	}
+
+	return nil
+}
+
+// handleURLScheme registers or removes the pocket-prompt:// link handler
+func (c *CLI) handleURLScheme(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("url-scheme subcommand required (install, uninstall)")
+	}
+
+	switch args[0] {
+	case "install":
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate pocket-prompt binary: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+			executable = resolved
+		}
+		result, err := urlscheme.Register(executable)
+		if err != nil {
+			return fmt.Errorf("failed to register URL scheme: %w", err)
+		}
+		fmt.Println(result)
+		fmt.Printf("Try it: open %s\n", urlscheme.Link{Action: urlscheme.ActionPrompt, ID: "my-prompt"})
+		return nil
+	case "uninstall":
+		if err := urlscheme.Unregister(); err != nil {
+			return fmt.Errorf("failed to remove URL scheme handler: %w", err)
+		}
+		fmt.Printf("Removed %s:// handler\n", urlscheme.Scheme)
+		return nil
+	default:
+		return fmt.Errorf("unknown url-scheme subcommand: %s", args[0])
+	}
+}
+
+func (c *CLI) handleSavedSearches(args []string) error {
+	if len(args) == 0 {
+		// List saved searches
+		searches, err := c.service.ListSavedSearches()
+		if err != nil {
+			return fmt.Errorf("failed to list saved searches: %w", err)
+		}
+
+		c.printSavedSearches(searches)
+		return nil
+	}
+
+	subcommand := args[0]
+	switch subcommand {
+	case "pin":
+		if len(args) < 2 {
+			return fmt.Errorf("search-saved pin requires a search name")
+		}
+		if err := c.service.SetPinnedSearch(args[1]); err != nil {
+			return fmt.Errorf("failed to pin saved search: %w", err)
+		}
+		fmt.Printf("Pinned saved search: %s (applied when the TUI opens and by 'pkt list')\n", args[1])
+		return nil
+	case "unpin":
+		if err := c.service.SetPinnedSearch(""); err != nil {
+			return fmt.Errorf("failed to unpin saved search: %w", err)
+		}
+		fmt.Println("No saved search is pinned")
+		return nil
+	case "subscribe":
+		if len(args) < 2 {
+			return fmt.Errorf("search-saved subscribe requires a search name")
+		}
+		if err := c.service.Subscribe(args[1]); err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+		fmt.Printf("Subscribed to %s; prompts that sync and imports bring in are listed by 'pkt search-saved new %s'\n", args[1], args[1])
+		return nil
+	case "unsubscribe":
+		if len(args) < 2 {
+			return fmt.Errorf("search-saved unsubscribe requires a search name")
+		}
+		if err := c.service.Unsubscribe(args[1]); err != nil {
+			return fmt.Errorf("failed to unsubscribe: %w", err)
+		}
+		fmt.Printf("Unsubscribed from %s\n", args[1])
+		return nil
+	case "new":
+		return c.newMatches(args[1:])
+	case "ack":
+		if len(args) < 2 {
+			return fmt.Errorf("search-saved ack requires a search name")
+		}
+		if err := c.service.AcknowledgeMatches(args[1]); err != nil {
+			return fmt.Errorf("failed to acknowledge new matches: %w", err)
+		}
+		fmt.Printf("Acknowledged the new matches of %s\n", args[1])
+		return nil
+	case "run":
+		if len(args) < 2 {
+			return fmt.Errorf("search-saved run requires a search name")
+		}
+		
+		searchName := args[1]
+		var textQuery string
+		var format string
+		
+		// Parse flags
+		for i := 2; i < len(args); i++ {
+			arg := args[i]
+			switch arg {
+			case "--text", "-t":
+				if i+1 < len(args) {
+					textQuery = args[i+1]
+					i++
+				}
+			case "--format", "-f":
+				if i+1 < len(args) {
+					format = args[i+1]
+					i++
+				}
+			}
+		}
+		
+		prompts, err := c.service.ExecuteSavedSearchWithText(searchName, textQuery)
+		if err != nil {
+			return fmt.Errorf("failed to execute saved search: %w", err)
+		}
+		c.service.RecordSearch("saved-search", searchName, len(prompts))
+		return c.formatOutput(prompts, format)
+	default:
+		return fmt.Errorf("unknown search-saved subcommand: %s", subcommand)
+	}
+}
+
+func (c *CLI) handleGit(args []string) error {
+	if len(args) == 0 {
+		// Show git status
+		status, err := c.service.GetGitSyncStatus()
+		if err != nil {
+			return fmt.Errorf("failed to get git status: %w", err)
+		}
+		fmt.Println("Git sync status:", status)
+		return nil
+	}
+
+	subcommand := args[0]
+	switch subcommand {
+	case "setup":
+		if len(args) < 2 {
+			return fmt.Errorf("git setup requires a repository URL\n\nUsage: pkt git setup <repository-url>\n\nExamples:\n  pkt git setup https://github.com/username/my-prompts.git\n  pkt git setup git@github.com:username/my-prompts.git")
+		}
+		repoURL := args[1]
+		var opts git.SetupOptions
+		for i := 2; i < len(args); i++ {
+			switch args[i] {
+			case "--lfs":
+				opts.LFS = true
+			case "--depth":
+				if i+1 >= len(args) {
+					return fmt.Errorf("--depth requires a number of commits")
+				}
+				depth, err := strconv.Atoi(args[i+1])
+				if err != nil || depth < 1 {
+					return fmt.Errorf("invalid --depth %q: must be a positive number", args[i+1])
+				}
+				opts.Depth = depth
+				i++
+			case "--sparse":
+				if i+1 >= len(args) {
+					return fmt.Errorf("--sparse requires directories, e.g. --sparse packs/team-a,prompts/team-a")
+				}
+				opts.Sparse = append(opts.Sparse, strings.Split(args[i+1], ",")...)
+				i++
+			default:
+				return fmt.Errorf("unknown option: %s", args[i])
+			}
+		}
+		if err := c.service.SetupGitRepository(repoURL, opts); err != nil {
+			return fmt.Errorf("failed to setup git repository: %w", err)
+		}
+		fmt.Println("Git repository successfully configured!")
+		return nil
+	case "lfs":
+		if err := c.service.EnableGitLFS(); err != nil {
+			return fmt.Errorf("failed to enable git-lfs: %w", err)
+		}
+		fmt.Println("Attachments under assets/ are now tracked with Git LFS")
+		return nil
+	case "enable":
+		c.service.EnableGitSync()
+		fmt.Println("Git sync enabled")
+		return nil
+	case "disable":
+		c.service.DisableGitSync()
+		fmt.Println("Git sync disabled")
+		return nil
+	case "status":
+		status, err := c.service.GetGitSyncStatus()
+		if err != nil {
+			return fmt.Errorf("failed to get git status: %w", err)
+		}
+		fmt.Println(status)
+		return nil
+	case "sync":
+		if err := c.service.SyncChanges("Manual sync from CLI"); err != nil {
+			return fmt.Errorf("failed to sync: %w", err)
+		}
+		fmt.Println("Successfully synced with remote repository")
+		return nil
+	case "pull":
+		if err := c.service.PullGitChanges(); err != nil {
+			return fmt.Errorf("failed to pull changes: %w", err)
+		}
+		fmt.Println("Successfully pulled changes from remote repository")
+		return nil
+	case "branch":
+		return c.handleGitBranch(args[1:])
+	case "pr":
+		return c.handleGitPullRequest(args[1:])
+	case "auth":
+		return c.handleGitAuth(args[1:])
+	case "sparse":
+		return c.handleGitSparse(args[1:])
+	case "unshallow":
+		if err := c.service.UnshallowGit(); err != nil {
+			return err
+		}
+		fmt.Println("Fetched the library's full history")
+		return nil
+	default:
+		return fmt.Errorf("unknown git subcommand: %s", subcommand)
+	}
+}
+
+// handleGitBranch shows or changes the working and main branches
+func (c *CLI) handleGitBranch(args []string) error {
+	current, working, mainBranch := c.service.GitBranches()
+	if len(args) == 0 {
+		fmt.Printf("Current branch: %s\n", current)
+		if working == "" {
+			fmt.Println("Working branch: (none, syncing the current branch)")
+		} else {
+			fmt.Printf("Working branch: %s\n", working)
+		}
+		fmt.Printf("Main branch:    %s\n", mainBranch)
+		return nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--main":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--main requires a branch name")
+			}
+			if err := c.service.SetMainBranch(args[i+1]); err != nil {
+				return err
+			}
+			i++
+		case "--clear":
+			if err := c.service.SetWorkingBranch(""); err != nil {
+				return err
+			}
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown option: %s", args[i])
+			}
+			if err := c.service.SetWorkingBranch(args[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	current, working, mainBranch = c.service.GitBranches()
+	if working == "" {
+		fmt.Printf("Syncing the checked-out branch (%s); pull requests target %s\n", current, mainBranch)
+	} else {
+		fmt.Printf("Syncing edits to %s; pull requests target %s\n", working, mainBranch)
+	}
+	return nil
+}
+
+// handleGitPullRequest pushes the working branch and opens a pull request
+func (c *CLI) handleGitPullRequest(args []string) error {
+	var title, body string
+	var draft bool
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--title":
+			if i+1 < len(args) {
+				title = args[i+1]
+				i++
+			}
+		case "--body":
+			if i+1 < len(args) {
+				body = args[i+1]
+				i++
+			}
+		case "--draft":
+			draft = true
+		}
+	}
+
+	pr, err := c.service.OpenPullRequest(title, body, draft)
+	if err == git.ErrGHNotFound {
+		fmt.Println("Pushed. The gh CLI is not installed, so open the pull request yourself:")
+		if url := c.service.PullRequestCompareURL(); url != "" {
+			fmt.Printf("  %s\n", url)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open pull request: %w", err)
+	}
+
+	if pr.Created {
+		fmt.Printf("Opened pull request: %s\n", pr.URL)
+	} else {
+		fmt.Printf("Pushed to existing pull request: %s\n", pr.URL)
+	}
+	return nil
+}
+
+// handleGitSparse shows or changes the directories this clone checks out
+func (c *CLI) handleGitSparse(args []string) error {
+	dirs := c.service.SparseCheckout()
+	if len(args) > 0 {
+		var add, whole bool
+		var given []string
+		for _, arg := range args {
+			switch arg {
+			case "--add":
+				add = true
+			case "--clear":
+				whole = true
+			default:
+				if strings.HasPrefix(arg, "-") {
+					return fmt.Errorf("unknown option: %s", arg)
+				}
+				given = append(given, arg)
+			}
+		}
+		switch {
+		case whole:
+			dirs = nil
+		case add:
+			dirs = append(dirs, given...)
+		default:
+			dirs = given
+		}
+		if err := c.service.SetSparseCheckout(dirs); err != nil {
+			return err
+		}
+		dirs = c.service.SparseCheckout()
+	}
+
+	if len(dirs) == 0 {
+		fmt.Println("Checking out the whole library")
+	} else {
+		fmt.Println("Checking out only:")
+		for _, dir := range dirs {
+			fmt.Printf("  %s/\n", dir)
+		}
+		fmt.Println("Prompts and templates elsewhere are left out and cannot be saved here.")
+	}
+	if c.service.ShallowClone() {
+		fmt.Println("History is shallow; 'pkt git unshallow' fetches the rest.")
+	}
+	return nil
+}
+
+// handleGitAuth checks or changes the credentials git sync uses
+func (c *CLI) handleGitAuth(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("git auth subcommand required (test, ssh-key, token)")
+	}
+	switch args[0] {
+	case "test":
+		report, err := c.service.TestGitAuth()
+		if err != nil {
+			return err
+		}
+		check := func(ok bool) string {
+			if ok {
+				return "ok"
+			}
+			return "failed"
+		}
+		fmt.Printf("Remote: %s\n", report.Remote)
+		fmt.Printf("Auth:   %s\n", report.Method)
+		fmt.Printf("Read:   %s\n", check(report.Read))
+		if report.WriteSkipped != "" {
+			fmt.Printf("Write:  not checked, %s\n", report.WriteSkipped)
+		} else if report.Read {
+			fmt.Printf("Write:  %s\n", check(report.Write))
+		}
+		if report.Error != "" {
+			return fmt.Errorf("%s", report.Error)
+		}
+		return nil
+	case "ssh-key":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: pkt git auth ssh-key <path>|--clear")
+		}
+		path := args[1]
+		if path == "--clear" {
+			path = ""
+		} else if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if err := c.service.SetGitSSHKey(path); err != nil {
+			return err
+		}
+		if path == "" {
+			fmt.Println("Git sync uses ssh-agent and the default SSH keys")
+		} else {
+			fmt.Printf("Git sync uses the SSH key %s\n", path)
+		}
+		return nil
+	case "token":
+		usage := fmt.Errorf("usage: pkt git auth token --env <VAR>|--file <path>|--keychain <service/account>|--clear")
+		if len(args) < 2 {
+			return usage
+		}
+		var from, value string
+		switch args[1] {
+		case "--env":
+			from = service.TokenFromEnv
+		case "--file":
+			from = service.TokenFromFile
+		case "--keychain":
+			from = service.TokenFromKeychain
+		case "--clear":
+		default:
+			return usage
+		}
+		if from != "" {
+			if len(args) < 3 {
+				return usage
+			}
+			value = args[2]
+			if from == service.TokenFromFile {
+				if abs, err := filepath.Abs(value); err == nil {
+					value = abs
+				}
+			}
+		}
+		if err := c.service.SetGitToken(from, value); err != nil {
+			return err
+		}
+		switch from {
+		case "":
+			fmt.Println("Git sync no longer sends a token")
+		case service.TokenFromEnv:
+			if os.Getenv(value) == "" {
+				fmt.Fprintf(os.Stderr, "Warning: $%s is not set in this shell\n", value)
+			}
+			fmt.Printf("Git sync reads its token from $%s\n", value)
+		default:
+			fmt.Printf("Git sync reads its token from %s %s\n", from, value)
+		}
+		fmt.Println("Check it with 'pkt git auth test'")
+		return nil
+	default:
+		return fmt.Errorf("unknown git auth subcommand: %s", args[0])
+	}
+}
+
+// ghValueFlags are the gh create options 'pkt gh' passes on along with the
+// value after them; anything else for gh goes after --
+var ghValueFlags = map[string]bool{
+	"--title": true, "-t": true, "--repo": true, "-R": true, "--label": true, "-l": true,
+	"--assignee": true, "-a": true, "--base": true, "-B": true,
+}
+
+// handleGH renders a prompt and opens a GitHub issue or pull request for the
+// repository in the working directory with the text as its description
+func (c *CLI) handleGH(args []string) error {
+	if len(args) < 2 || (args[0] != "issue" && args[0] != "pr") || strings.HasPrefix(args[1], "-") {
+		return fmt.Errorf("gh requires issue or pr and a prompt ID, e.g. pkt gh issue bug-report --title \"Crash on save\"")
+	}
+	kind, rest := args[0], args[2:]
+	var ghArgs, renderArgs, passThrough []string
+	if i := slices.Index(rest, "--"); i >= 0 {
+		passThrough = rest[i+1:]
+		rest = rest[:i]
+	}
+	dryRun := false
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case ghValueFlags[rest[i]]:
+			if i+1 >= len(rest) {
+				return fmt.Errorf("%s requires a value", rest[i])
+			}
+			ghArgs = append(ghArgs, rest[i], rest[i+1])
+			i++
+		case rest[i] == "--draft" || rest[i] == "--web" || rest[i] == "-w":
+			ghArgs = append(ghArgs, rest[i])
+		case rest[i] == "--dry-run":
+			dryRun = true
+		default:
+			renderArgs = append(renderArgs, rest[i])
+		}
+	}
+
+	ghArgs = append(ghArgs, passThrough...)
+
+	id, err := c.service.ResolvePromptID(args[1])
+	if err != nil {
+		return err
+	}
+	id, opts, err := c.parseRenderArgs(id, renderArgs)
+	if err != nil {
+		return err
+	}
+	body, err := c.service.RenderPrompt(id, opts)
+	if err != nil {
+		return fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	if dryRun {
+		fmt.Printf("gh %s create --body-file <body> %s\n\n%s\n", kind, config.JoinArgs(ghArgs), body)
+		return nil
+	}
+	err = git.CreateWithGH(kind, body, ghArgs)
+	if err == git.ErrGHNotFound {
+		return fmt.Errorf("the gh CLI is not installed (see https://cli.github.com); --dry-run prints the description instead")
+	}
+	return err
+}
+
+func (c *CLI) printUsage() error {
+	fmt.Println(i18n.T("cli.usage"))
+	return nil
+}
+
+// handleEmail runs email gateway commands
+func (c *CLI) handleEmail(args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return fmt.Errorf("email subcommand required (check)")
+	}
+
+	result, err := c.service.CheckEmail(c.service.Settings().Email)
+	if err != nil {
+		return err
+	}
+	for _, prompt := range result.Prompts {
+		fmt.Printf("Created prompt %s: %s\n", prompt.ID, prompt.Title())
+	}
+	for _, err := range result.Errors {
+		fmt.Fprintf(os.Stderr, "Skipped: %v\n", err)
+	}
+	if len(result.Prompts) == 0 && len(result.Errors) == 0 {
+		fmt.Println("No new prompts")
+	}
+	return nil
+}
+
+// handleConfig shows and changes the library settings in
+// .pocket-prompt/config.json
+func (c *CLI) handleConfig(args []string) error {
+	settings := c.service.Settings()
+	if len(args) == 0 {
+		args = []string{"get"}
+	}
+
+	switch args[0] {
+	case "get", "show":
+		key := ""
+		if len(args) > 1 {
+			key = args[1]
+		}
+		value, err := settings.Get(key)
+		if err != nil {
+			return err
+		}
+		if err := printSetting(value); err != nil {
+			return err
+		}
+		if key == "" {
+			if data, err := os.ReadFile(settings.Path()); err == nil {
+				warnings, _ := config.CheckConfig(data)
+				for _, warning := range warnings {
+					warnf("%s in %s", warning, settings.Path())
+				}
+			}
+		} else if name := settings.EnvOverride(key); name != "" {
+			warnf("set by %s; the file may hold a different value", name)
+		}
+		return nil
+	case "set", "unset":
+		if args[0] == "set" && len(args) < 3 {
+			return fmt.Errorf("config set requires a key and a value, e.g. pkt config set server.port 9000")
+		}
+		if len(args) < 2 {
+			return fmt.Errorf("config unset requires a key")
+		}
+		key := args[1]
+		var err error
+		if args[0] == "set" {
+			err = settings.Set(key, strings.Join(args[2:], " "))
+		} else {
+			err = settings.Unset(key)
+		}
+		if err != nil {
+			return err
+		}
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		value, _ := settings.Get(key)
+		fmt.Printf("%s = ", key)
+		if err := printSetting(value); err != nil {
+			return err
+		}
+		if name := settings.EnvOverride(key); name != "" {
+			warnf("%s is set and overrides this setting until it is unset", name)
+		}
+		if config.IsUserSetting(key) {
+			fmt.Println(c.out.muted("Saved in " + settings.UserPath() + ", outside the library"))
+		}
+		return nil
+	case "edit":
+		return c.editConfig(settings)
+	case "path":
+		fmt.Println(settings.Path())
+		return nil
+	default:
+		return fmt.Errorf("unknown config subcommand: %s (use get, set, unset, edit or path)", args[0])
+	}
+}
+
+// builtinCommands are the commands ExecuteCommand dispatches, which aliases
+// cannot replace
+var builtinCommands = map[string]bool{
+	"list": true, "ls": true, "search": true, "sources": true, "source": true,
+	"project": true, "suggest": true, "get": true, "show": true, "path": true,
+	"create": true, "new": true, "edit": true, "delete": true, "rm": true,
+	"copy": true, "render": true, "preview": true, "share": true, "cat": true, "speak": true, "profiles": true, "profile": true,
+	"eval": true, "templates": true, "template": true, "tags": true, "archive": true,
+	"search-saved": true, "boolean-search": true, "export": true, "import": true,
+	"git": true, "migrate": true, "attach": true, "detach": true, "propose": true,
+	"approve": true, "reject": true, "review": true, "lock": true, "unlock": true,
+	"protect": true, "unprotect": true, "locks": true, "log": true, "variants": true,
+	"changelog": true, "diff": true, "merge": true, "digest": true, "stats": true, "analytics": true, "lint": true, "hooks": true, "hook": true,
+	"ci": true, "maintenance": true, "bench": true, "doctor": true, "remote": true,
+	"url-scheme": true, "qr": true, "server": true, "packs": true, "pack": true,
+	"email": true, "config": true, "plugins": true, "plugin": true, "alias": true,
+	"shell": true, "gh": true, "watch-clipboard": true, "summarize": true, "autotag": true, "translate": true, "check-links": true, "keys": true, "move": true, "open": true,
+	"history": true, "repeat": true, "!!": true, "help": true,
+}
+
+// expandAlias replaces a command that is an alias with the command it stands
+// for. The arguments given come straight after that command and the alias's
+// own arguments last, since commands take an ID first and options after it:
+// with cr = "copy --format json", 'pkt cr review' runs 'pkt copy review
+// --format json'. Aliases may use other aliases.
+func (c *CLI) expandAlias(args []string) ([]string, error) {
+	seen := map[string]bool{}
+	for !builtinCommands[args[0]] {
+		expansion, ok := c.defaults().Alias(args[0])
+		if !ok {
+			break
+		}
+		if seen[args[0]] {
+			return nil, fmt.Errorf("alias %s refers back to itself", args[0])
+		}
+		seen[args[0]] = true
+		args = append(append([]string{expansion[0]}, args[1:]...), expansion[1:]...)
+	}
+	return args, nil
+}
+
+// promptIDCommands are the commands whose first argument names a prompt.
+// The ones set to true take any number of prompt IDs.
+var promptIDCommands = map[string]bool{
+	"get": false, "show": false, "path": false, "edit": false, "delete": false,
+	"rm": false, "copy": false, "render": false, "preview": false, "share": false, "speak": false, "attach": false,
+	"detach": false, "eval": false, "propose": false, "approve": false,
+	"reject": false, "lock": false, "unlock": false, "log": false, "qr": false,
+	"changelog": false, "diff": false, "protect": true, "unprotect": true,
+}
+
+// resolvePromptArgs replaces prompt IDs in a command's arguments that are
+// shortened, like git commit hashes, to a prefix only one prompt ID starts
+// with. IDs no prompt starts with are left for the command to report.
+func (c *CLI) resolvePromptArgs(command string, args []string) error {
+	several, ok := promptIDCommands[command]
+	// With --variant the ID names a variant group, and with --template a template
+	if !ok || hasFlag(args, "--variant") || hasFlag(args, "--template") {
+		return nil
+	}
+	for i, arg := range args {
+		if i > 0 && !several {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			if several {
+				continue
+			}
+			break
+		}
+		id, err := c.service.ResolvePromptID(arg)
+		if err != nil {
+			return err
+		}
+		args[i] = id
+	}
+	return nil
+}
+
+// handleAlias lists and changes command aliases
+func (c *CLI) handleAlias(args []string) error {
+	settings := c.service.Settings()
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		names := make([]string, 0, len(settings.CLI.Aliases))
+		for name := range settings.CLI.Aliases {
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			fmt.Println("No aliases. Add one with: pkt alias add <name> <command> [args]")
+			return nil
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-12s %s\n", name, settings.CLI.Aliases[name])
+		}
+		return nil
+	case "add", "set":
+		if len(args) < 3 {
+			return fmt.Errorf("alias add requires a name and a command, e.g. pkt alias add cr copy --format json")
+		}
+		name := args[1]
+		if builtinCommands[name] {
+			return fmt.Errorf("%s is a built-in command and cannot be an alias", name)
+		}
+		// A single argument is the whole command line, as it would be quoted
+		// in the config file
+		expansion := args[2]
+		if len(args) > 3 {
+			expansion = config.JoinArgs(args[2:])
+		}
+		if err := settings.SetAlias(name, expansion); err != nil {
+			return err
+		}
+		if first, _ := settings.CLI.Alias(name); !builtinCommands[first[0]] {
+			if _, ok := settings.CLI.Alias(first[0]); !ok {
+				delete(settings.CLI.Aliases, name)
+				return fmt.Errorf("unknown command %s in alias %s", first[0], name)
+			}
+		}
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		fmt.Printf("pkt %s now runs: pkt %s\n", name, settings.CLI.Aliases[name])
+		return nil
+	case "remove", "rm":
+		if len(args) < 2 {
+			return fmt.Errorf("alias remove requires an alias name")
+		}
+		if err := settings.RemoveAlias(args[1]); err != nil {
+			return err
+		}
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		fmt.Printf("Removed alias %s\n", args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown alias subcommand: %s (use list, add or remove)", args[0])
+	}
+}
+
+// printSetting prints a setting's value: strings, numbers and booleans as
+// they are, lists comma-separated as they are set, and sections as JSON
+func printSetting(value interface{}) error {
+	switch v := value.(type) {
+	case string, bool, int, float64:
+		fmt.Println(v)
+		return nil
+	case []string:
+		fmt.Println(strings.Join(v, ","))
+		return nil
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format setting: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// editConfig opens config.json in the editor and saves it only once it
+// parses and validates, offering to edit again when it does not
+func (c *CLI) editConfig(settings *config.Config) error {
+	original, err := os.ReadFile(settings.Path())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read configuration: %w", err)
+	}
+	if len(original) == 0 {
+		original = []byte("{\n}\n")
+	}
+
+	edited := original
+	var warnings []string
+	for {
+		if edited, err = c.openInEditor(edited, "pkt-config-*.json"); err != nil {
+			return err
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Println("No changes")
+			return nil
+		}
+		if warnings, err = config.CheckConfig(edited); err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		if c.defaults().SkipConfirm() || !c.confirm("Edit again?") {
+			// Keep the edit so it is not lost with the temporary file
+			kept, keepErr := os.CreateTemp("", "pkt-config-*.json")
+			if keepErr != nil {
+				return fmt.Errorf("configuration not saved: %w", err)
+			}
+			defer kept.Close()
+			if _, keepErr := kept.Write(edited); keepErr != nil {
+				return fmt.Errorf("configuration not saved: %w", err)
+			}
+			return fmt.Errorf("configuration not saved; your edit was saved to %s", kept.Name())
+		}
+	}
+
+	for _, warning := range warnings {
+		warnf("%s; it is ignored", warning)
+	}
+	if err := os.MkdirAll(filepath.Dir(settings.Path()), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(settings.Path(), edited, 0644); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	fmt.Printf("Saved %s\n", settings.Path())
+	return nil
+}
+
+// handleTemplate handles individual template operations  
+func (c *CLI) handleTemplate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("template command requires a subcommand (create, edit, delete, show)")
+	}
+
+	subcommand := args[0]
+	switch subcommand {
+	case "create":
+		return c.createTemplate(args[1:])
+	case "edit":
+		return c.editTemplate(args[1:])  
+	case "delete":
+		return c.deleteTemplate(args[1:])
+	case "show":
+		if len(args) < 2 {
+			return fmt.Errorf("template show requires a template ID")
+		}
+		template, err := c.getTemplate(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to get template: %w", err)
+		}
+		return c.formatSingleTemplate(template, "")
+	default:
+		return fmt.Errorf("unknown template subcommand: %s", subcommand)
+	}
+}
+
+// createTemplate creates a new template
+func (c *CLI) createTemplate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("create template requires a template ID")
+	}
+
+	id := args[0]
+	var name, description, content string
+	var slots []string
+
+	// Parse flags
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--name":
+			if i+1 < len(args) {
+				name = args[i+1]
+				i++
+			}
+		case "--description":
+			if i+1 < len(args) {
+				description = args[i+1]
+				i++
+			}
+		case "--content":
+			if i+1 < len(args) {
+				content = args[i+1]
+				i++
+			}
+		case "--slots":
+			if i+1 < len(args) {
+				slots = strings.Split(args[i+1], ",")
+				for j := range slots {
+					slots[j] = strings.TrimSpace(slots[j])
+				}
+				i++
+			}
+		case "--stdin":
+			// Read content from stdin
+			var buf strings.Builder
+			for {
+				var line string
+				n, err := fmt.Scanln(&line)
+				if n == 0 || err != nil {
+					break
+				}
+				buf.WriteString(line + "\n")
+			}
+			content = buf.String()
+		}
+	}
+
+	template := &models.Template{
+		ID:          id,
+		Version:     "1.0.0",
+		Name:        name,
+		Description: description,
+		Content:     content,
+	}
+
+	// Convert slot strings to template slots
+	for _, slot := range slots {
+		template.Slots = append(template.Slots, models.Slot{
+			Name:        slot,
+			Required:    false,
+			Description: "",
+			Default:     "",
+		})
+	}
+
+	if _, err := c.runCommand("create-template", map[string]interface{}{"template": template}); err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	fmt.Printf("Created template: %s\n", id)
+	return nil
+}
+
+// editTemplate edits an existing template
+func (c *CLI) editTemplate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("edit template requires a template ID")
+	}
+
+	id := args[0]
+	template, err := c.getTemplate(id)
+	if err != nil {
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+
+	// Parse flags to update fields
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--name":
+			if i+1 < len(args) {
+				template.Name = args[i+1]
+				i++
+			}
+		case "--description":
+			if i+1 < len(args) {
+				template.Description = args[i+1]
+				i++
+			}
+		case "--content":
+			if i+1 < len(args) {
+				template.Content = args[i+1]
+				i++
+			}
+		case "--slots":
+			if i+1 < len(args) {
+				slots := strings.Split(args[i+1], ",")
+				template.Slots = []models.Slot{}
+				for _, slot := range slots {
+					template.Slots = append(template.Slots, models.Slot{
+						Name:        strings.TrimSpace(slot),
+						Required:    false,
+						Description: "",
+						Default:     "",
+					})
+				}
+				i++
+			}
+		}
+	}
+
+	result, err := c.runCommand("update-template", map[string]interface{}{"template": template})
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	fmt.Println(result.Message)
+	return nil
+}
+
+// deleteTemplate deletes a template
+func (c *CLI) deleteTemplate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("delete template requires a template ID")
+	}
+
+	id := args[0]
+	var force bool
+
+	// Parse flags
+	for _, arg := range args[1:] {
+		if arg == "--force" || arg == "-f" {
+			force = true
+		}
+	}
+
+	question := fmt.Sprintf("Are you sure you want to delete template '%s'?", id)
+	if usage, err := c.service.TemplateUsage(id); err == nil && usage.HeavilyUsed() {
+		warnf("template '%s' is heavily used (%s). Prompts using it will render without it.", id, usage.Summary())
+		question = fmt.Sprintf("Delete template '%s' anyway?", id)
+	} else if err == nil && len(usage.Prompts) > 0 {
+		question = fmt.Sprintf("Template '%s' is used by %d prompts. Are you sure you want to delete it?", id, len(usage.Prompts))
+	}
+	if !force && !c.confirm(question) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	if _, err := c.runCommand("delete-template", map[string]interface{}{"id": id}); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	fmt.Printf("Deleted template: %s\n", id)
+	return nil
+}
+
+// formatSingleTemplate formats a single template for output
+func (c *CLI) formatSingleTemplate(template *models.Template, format string) error {
+	switch c.outputFormat(format, "json") {
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(template)
+	default:
+		fmt.Printf("ID: %s\n", template.ID)
+		fmt.Printf("Name: %s\n", template.Name)
+		fmt.Printf("Version: %s\n", template.Version)
+		if template.Description != "" {
+			fmt.Printf("Description: %s\n", template.Description)
+		}
+		fmt.Printf("Created: %s\n", i18n.FormatDateTime(template.CreatedAt))
+		fmt.Printf("Updated: %s\n", i18n.FormatDateTime(template.UpdatedAt))
+		
+		if len(template.Slots) > 0 {
+			fmt.Println("\nSlots:")
+			for _, slot := range template.Slots {
+				fmt.Printf("  %s", slot.Name)
+				if slot.Required {
+					fmt.Print(" [required]")
+				}
+				if slot.Default != "" {
+					fmt.Printf(" [default: %s]", slot.Default)
+				}
+				if slot.Description != "" {
+					fmt.Printf(" - %s", slot.Description)
+				}
+				fmt.Println()
+			}
+		}
+		
+		fmt.Printf("\nContent:\n%s\n", template.Content)
+	}
+	return nil
+}
+
+// booleanSearchSubcommands are the first arguments of boolean-search that
+// are subcommands rather than the start of an expression
+var booleanSearchSubcommands = []string{"create", "edit", "delete", "list", "run", "explain"}
+
+// handleBooleanSearch handles boolean search operations
+func (c *CLI) handleBooleanSearch(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("boolean-search requires a subcommand (create, edit, delete, list, run, explain)")
+	}
+
+	subcommand := args[0]
+	switch subcommand {
+	case "create":
+		return c.createBooleanSearch(args[1:])
+	case "edit":
+		return c.editBooleanSearch(args[1:])
+	case "delete":
+		return c.deleteBooleanSearch(args[1:])
+	case "list":
+		return c.listBooleanSearches()
+	case "run":
+		return c.runBooleanSearch(args[1:])
+	case "explain":
+		return c.explainBooleanSearch(args[1:])
+	default:
+		return fmt.Errorf("unknown boolean-search subcommand: %s", subcommand)
+	}
+}
+
+// createBooleanSearch creates a new saved boolean search
+func (c *CLI) createBooleanSearch(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("create boolean search requires name and expression")
+	}
+
+	name := args[0]
+	var textQuery string
+	var expressionParts []string
+	
+	// Parse flags
+	i := 1
+	for i < len(args) {
+		arg := args[i]
+		switch arg {
+		case "--text", "-t":
+			if i+1 < len(args) {
+				textQuery = args[i+1]
+				i += 2
+			} else {
+				i++
+			}
+		default:
+			expressionParts = append(expressionParts, arg)
+			i++
+		}
+	}
+	
+	if len(expressionParts) == 0 {
+		return fmt.Errorf("boolean expression is required")
+	}
+	
+	expression := strings.Join(expressionParts, " ")
+
+	// Parse the boolean expression
+	expr, err := parseBooleanExpression(expression)
+	if err != nil {
+		return fmt.Errorf("invalid boolean expression: %w", err)
+	}
+
+	savedSearch := models.SavedSearch{
+		Name:       name,
+		Expression: expr,
+		TextQuery:  textQuery,
+	}
+
+	if err := c.service.SaveBooleanSearch(savedSearch); err != nil {
+		return fmt.Errorf("failed to save boolean search: %w", err)
+	}
+
+	message := fmt.Sprintf("Created boolean search: %s", name)
+	if textQuery != "" {
+		message += fmt.Sprintf(" (with text filter: '%s')", textQuery)
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// editBooleanSearch edits an existing saved boolean search
+func (c *CLI) editBooleanSearch(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("edit boolean search requires name and new expression")
+	}
+
+	name := args[0]
+	expression := strings.Join(args[1:], " ")
+
+	// Parse the boolean expression
+	expr, err := parseBooleanExpression(expression)
+	if err != nil {
+		return fmt.Errorf("invalid boolean expression: %w", err)
+	}
+
+	// Delete old search
+	if err := c.service.DeleteSavedSearch(name); err != nil {
+		return fmt.Errorf("failed to delete old search: %w", err)
+	}
+
+	savedSearch := models.SavedSearch{
+		Name:       name,
+		Expression: expr,
+	}
+
+	if err := c.service.SaveBooleanSearch(savedSearch); err != nil {
+		return fmt.Errorf("failed to save updated boolean search: %w", err)
+	}
+
+	fmt.Printf("Updated boolean search: %s\n", name)
+	return nil
+}
+
+// deleteBooleanSearch deletes a saved boolean search
+func (c *CLI) deleteBooleanSearch(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("delete boolean search requires a name")
+	}
+
+	name := args[0]
+	var force bool
+
+	// Parse flags
+	for _, arg := range args[1:] {
+		if arg == "--force" || arg == "-f" {
+			force = true
+		}
+	}
+
+	if !force && !c.confirm(fmt.Sprintf("Are you sure you want to delete boolean search '%s'?", name)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	if err := c.service.DeleteSavedSearch(name); err != nil {
+		return fmt.Errorf("failed to delete boolean search: %w", err)
+	}
+
+	fmt.Printf("Deleted boolean search: %s\n", name)
+	return nil
+}
+
+// listBooleanSearches lists all saved boolean searches
+func (c *CLI) listBooleanSearches() error {
+	searches, err := c.service.ListSavedSearches()
+	if err != nil {
+		return fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	c.printSavedSearches(searches)
+	return nil
+}
+
+// printSavedSearches prints one line per saved search, marking the pinned
+// and subscribed ones
+func (c *CLI) printSavedSearches(searches []models.SavedSearch) {
+	pinned := c.service.PinnedSearch()
+	for _, search := range searches {
+		marker := ""
+		if search.Name == pinned {
+			marker = " (pinned)"
+		}
+		if c.service.Subscribed(search.Name) {
+			marker += " (subscribed)"
+		}
+		fmt.Printf("%s: %s%s\n", search.Name, search.Expression.String(), marker)
+	}
+}
+
+// listPinnedSearch lists the results of the pinned saved search, for 'pkt
+// list' without flags. It reports false when nothing is pinned or the pinned
+// search is gone, and the whole library should be listed instead.
+func (c *CLI) listPinnedSearch() (bool, error) {
+	name := c.service.PinnedSearch()
+	if name == "" {
+		return false, nil
+	}
+	prompts, err := c.service.ExecuteSavedSearch(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Pinned search %q is unavailable (%v); listing every prompt\n", name, err)
+		return false, nil
+	}
+	fmt.Fprintf(os.Stderr, "Showing pinned search %q; 'pkt list --all' lists every prompt\n", name)
+	return true, c.formatOutput(prompts, "")
+}
+
+// runBooleanSearch executes a boolean search expression
+func (c *CLI) runBooleanSearch(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("run boolean search requires either a saved search name or expression")
+	}
+
+	var format string
+	var expression string
+	var useSavedSearch bool
+	var addTags, removeTags []string
+	var dryRun bool
+
+	// Check if first arg is --saved to use a saved search
+	if args[0] == "--saved" {
+		if len(args) < 2 {
+			return fmt.Errorf("--saved requires a search name")
+		}
+		useSavedSearch = true
+		expression = args[1]
+		args = args[2:]
+	}
+
+	// Parse flags, also from inside a quoted expression; everything else is
+	// the expression
+	args = strings.Fields(strings.Join(args, " "))
+	var parts []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case "--add-tag":
+			if i+1 < len(args) {
+				addTags = append(addTags, args[i+1])
+				i++
+			}
+		case "--remove-tag":
+			if i+1 < len(args) {
+				removeTags = append(removeTags, args[i+1])
+				i++
+			}
+		case "--dry-run", "-n":
+			dryRun = true
+		default:
+			parts = append(parts, args[i])
+		}
+	}
+	if !useSavedSearch {
+		expression = strings.Join(parts, " ")
+	}
+	if dryRun && len(addTags) == 0 && len(removeTags) == 0 {
+		return fmt.Errorf("--dry-run needs --add-tag or --remove-tag")
+	}
+
+	var prompts []*models.Prompt
+	var err error
+
+	if useSavedSearch {
+		prompts, err = c.service.ExecuteSavedSearch(expression)
+	} else {
+		// Parse the boolean expression
+		expr, parseErr := parseBooleanExpression(expression)
+		if parseErr != nil {
+			return fmt.Errorf("invalid boolean expression: %w", parseErr)
+		}
+		prompts, err = c.service.SearchPromptsByBooleanExpression(expr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("boolean search failed: %w", err)
+	}
+
+	if len(addTags) > 0 || len(removeTags) > 0 {
+		return c.retagResults(prompts, addTags, removeTags, dryRun, format)
+	}
+	return c.formatOutput(prompts, format)
+}
+
+// retagResults applies --add-tag and --remove-tag to every result of a
+// boolean search and prints what changed
+func (c *CLI) retagResults(prompts []*models.Prompt, add, remove []string, dryRun bool, format string) error {
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported retag format %q (expected text or json)", format)
+	}
+
+	report, err := c.service.RetagPrompts(prompts, add, remove, dryRun)
+	if err != nil {
+		return fmt.Errorf("retag failed: %w", err)
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	verb := "Retagged"
+	if dryRun {
+		verb = "Would retag"
+	}
+	fmt.Printf("%s %d of %d matching prompts\n", verb, len(report.Changed), len(prompts))
+	for _, change := range report.Changed {
+		var tags []string
+		for _, tag := range change.Added {
+			tags = append(tags, "+"+tag)
+		}
+		for _, tag := range change.Removed {
+			tags = append(tags, "-"+tag)
+		}
+		version := ""
+		if change.Version != "" {
+			version = " (v" + change.Version + ")"
+		}
+		fmt.Printf("  %-30s %s%s\n", change.ID, strings.Join(tags, " "), version)
+	}
+	if len(report.Unchanged) > 0 {
+		fmt.Printf("Unchanged: %d already tagged as asked\n", len(report.Unchanged))
+	}
+	for _, skip := range report.Skipped {
+		fmt.Printf("Skipped %s: %s\n", skip.ID, skip.Reason)
+	}
+	if dryRun && len(report.Changed) > 0 {
+		fmt.Println("Run again without --dry-run to apply.")
+	}
+	return nil
+}
+
+// explainBooleanSearch shows how an expression parses and how many prompts
+// each part of it matches, or where its syntax goes wrong
+func (c *CLI) explainBooleanSearch(args []string) error {
+	var format string
+	var parts []string
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--format" || args[i] == "-f") && i+1 < len(args) {
+			format = args[i+1]
+			i++
+			continue
+		}
+		parts = append(parts, args[i])
+	}
+	expression := strings.Join(parts, " ")
+	format = c.outputFormat(format, "json")
+
+	explanation, err := c.service.ExplainBooleanExpression(expression)
+	if err != nil {
+		return fmt.Errorf("failed to explain boolean expression: %w", err)
+	}
+
+	if format == "json" {
+		if err := json.NewEncoder(os.Stdout).Encode(explanation); err != nil {
+			return err
+		}
+	} else if explanation.Valid {
+		fmt.Printf("Expression: %s\n", explanation.Expression)
+		fmt.Printf("Normalized: %s\n", explanation.Normalized)
+		fmt.Printf("Matches:    %d of %d prompts\n\n", explanation.Matches, explanation.TotalPrompts)
+		printExplainedNode(explanation.Tree, "", "")
+
+		fmt.Println("\nPrompts per tag:")
+		for _, tag := range explanation.Tags {
+			note := ""
+			if tag.Prompts == 0 {
+				note = "  (no prompt has this tag)"
+			}
+			fmt.Printf("  %-24s %d%s\n", tag.Tag, tag.Prompts, note)
+		}
+	} else {
+		for _, syntaxErr := range explanation.Errors {
+			fmt.Println(explanation.Expression)
+			fmt.Printf("%s^\n", strings.Repeat(" ", syntaxErr.Position-1))
+		}
+	}
+
+	if !explanation.Valid {
+		return fmt.Errorf("invalid boolean expression: %w", &explanation.Errors[0])
+	}
+	return nil
+}
+
+// printExplainedNode prints node and its children as a tree, with the number
+// of prompts each matches in a column on the right
+func printExplainedNode(node *service.ExplainedNode, prefix, childPrefix string) {
+	label := strings.ToUpper(string(node.Type))
+	if node.Type == models.ExpressionTag {
+		label = node.Tag
+	}
+	fmt.Printf("%-40s %d\n", prefix+label, node.Matches)
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printExplainedNode(child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			printExplainedNode(child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+// handleExport handles export operations
+func (c *CLI) handleExport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("export requires a subcommand (prompts, templates, all)")
+	}
+
+	// PDF exports are of prompts picked by ID or tag
+	for i := 0; i+1 < len(args); i++ {
+		if (args[i] == "--format" || args[i] == "-f") && args[i+1] == "pdf" {
+			return c.exportPDF(args)
+		}
+	}
+
+	subcommand := args[0]
+	var format string
+	var outputFile string
+	var redactor *redact.Redactor
+
+	// Parse flags
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case "--output", "-o":
+			if i+1 < len(args) {
+				outputFile = args[i+1]
+				i++
+			}
+		case "--redact":
+			var err error
+			if redactor, err = c.service.Redactor(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" {
+		return c.exportWithPlugin(subcommand, format, outputFile, redactor)
+	}
+
+	switch subcommand {
+	case "prompts":
+		prompts, err := c.service.ListPrompts()
+		if err != nil {
+			return fmt.Errorf("failed to list prompts: %w", err)
+		}
+		if redactor != nil {
+			prompts = redactor.Prompts(prompts)
+		}
+		if err := c.exportData(prompts, format, outputFile); err != nil {
+			return err
+		}
+		return c.exportAttachments(prompts, outputFile, redactor != nil)
+	case "templates":
+		templates, err := c.service.ListTemplates()
+		if err != nil {
+			return fmt.Errorf("failed to list templates: %w", err)
+		}
+		if redactor != nil {
+			templates = redactTemplates(redactor, templates)
+		}
+		return c.exportData(templates, format, outputFile)
+	case "all":
+		prompts, err := c.service.ListPrompts()
+		if err != nil {
+			return fmt.Errorf("failed to list prompts: %w", err)
+		}
+		templates, err := c.service.ListTemplates()
+		if err != nil {
+			return fmt.Errorf("failed to list templates: %w", err)
+		}
+		if redactor != nil {
+			prompts = redactor.Prompts(prompts)
+			templates = redactTemplates(redactor, templates)
+		}
+		data := map[string]interface{}{
+			"prompts":   prompts,
+			"templates": templates,
+		}
+		if err := c.exportData(data, format, outputFile); err != nil {
+			return err
+		}
+		return c.exportAttachments(prompts, outputFile, redactor != nil)
+	default:
+		return fmt.Errorf("unknown export subcommand: %s", subcommand)
+	}
+}
+
+// exportPDF typesets prompts, named by ID, by --tag or all of them with
+// "prompts" or "all", as a PDF
+func (c *CLI) exportPDF(args []string) error {
+	var ids []string
+	var tag, outputFile, title string
+	var all bool
+	var redactor *redact.Redactor
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "-f":
+			i++
+		case "--output", "-o":
+			if i+1 < len(args) {
+				outputFile = args[i+1]
+				i++
+			}
+		case "--tag", "-t":
+			if i+1 < len(args) {
+				tag = args[i+1]
+				i++
+			}
+		case "--title":
+			if i+1 < len(args) {
+				title = args[i+1]
+				i++
+			}
+		case "--redact":
+			var err error
+			if redactor, err = c.service.Redactor(); err != nil {
+				return err
+			}
+		case "prompts", "all":
+			all = true
+		case "templates":
+			return fmt.Errorf("PDF exports are of prompts; use --format json for templates")
+		default:
+			if strings.HasPrefix(args[i], "-") {
+				return fmt.Errorf("unknown option for PDF export: %s", args[i])
+			}
+			ids = append(ids, args[i])
+		}
+	}
+
+	var prompts []*models.Prompt
+	seen := map[string]bool{}
+	add := func(selected []*models.Prompt) {
+		for _, prompt := range selected {
+			if !seen[prompt.ID] {
+				seen[prompt.ID] = true
+				prompts = append(prompts, prompt)
+			}
+		}
+	}
+	for _, given := range ids {
+		id, err := c.resolvePromptID(given)
+		if err != nil {
+			return fmt.Errorf("failed to get prompt: %w", err)
+		}
+		prompt, err := c.service.GetPrompt(id)
+		if err != nil {
+			return fmt.Errorf("failed to get prompt: %w", err)
+		}
+		add([]*models.Prompt{prompt})
+	}
+	if tag != "" || all {
+		scope := "all"
+		if tag != "" {
+			scope = "tag:" + tag
+		}
+		selected, _, err := c.service.ExportSelection(scope)
+		if err != nil {
+			return fmt.Errorf("failed to list prompts: %w", err)
+		}
+		add(selected)
+	}
+	if len(prompts) == 0 {
+		if len(ids) == 0 && tag == "" && !all {
+			return fmt.Errorf("PDF export requires prompt IDs, --tag <tag> or prompts")
+		}
+		if tag != "" {
+			return fmt.Errorf("no prompts tagged %s", tag)
+		}
+		return fmt.Errorf("no prompts to export")
+	}
+
+	if title == "" {
+		switch {
+		case len(prompts) == 1:
+			title = prompts[0].Name
+		case tag != "" && len(ids) == 0:
+			title = "Prompts tagged " + tag
+		default:
+			title = "Prompts"
+		}
+	}
+	if outputFile == "" && term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("PDF export needs --output <file.pdf>, or its output redirected to a file")
+	}
+
+	output, err := c.service.ExportPDF(prompts, title, redactor, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to export PDF: %w", err)
+	}
+	if outputFile == "" {
+		os.Stdout.Write(output)
+		return nil
+	}
+	if err := os.WriteFile(outputFile, output, 0644); err != nil {
+		return err
+	}
+	if len(prompts) == 1 {
+		fmt.Printf("Exported %s to %s\n", prompts[0].ID, outputFile)
+	} else {
+		fmt.Printf("Exported %d prompts to %s\n", len(prompts), outputFile)
+	}
+	return nil
+}
+
+// exportWithPlugin exports with the plugin exporter called format, which is
+// given the prompts, the templates or both as subcommand asks
+func (c *CLI) exportWithPlugin(subcommand, format, outputFile string, redactor *redact.Redactor) error {
+	var prompts []*models.Prompt
+	var templates []*models.Template
+	var err error
+	switch subcommand {
+	case "prompts", "templates", "all":
+	default:
+		return fmt.Errorf("unknown export subcommand: %s", subcommand)
+	}
+	if subcommand != "templates" {
+		if prompts, err = c.service.ListPrompts(); err != nil {
+			return fmt.Errorf("failed to list prompts: %w", err)
+		}
+	}
+	if subcommand != "prompts" {
+		if templates, err = c.service.ListTemplates(); err != nil {
+			return fmt.Errorf("failed to list templates: %w", err)
+		}
+	}
+	if redactor != nil {
+		prompts = redactor.Prompts(prompts)
+		templates = redactTemplates(redactor, templates)
+	}
+
+	output, err := c.service.ExportWithPlugin(format, prompts, templates)
+	if err != nil {
+		return err
+	}
+	if outputFile != "" {
+		return os.WriteFile(outputFile, output, 0644)
+	}
+	os.Stdout.Write(output)
+	return nil
+}
+
+// redactTemplates applies redaction rules to each template in a list
+func redactTemplates(redactor *redact.Redactor, templates []*models.Template) []*models.Template {
+	redacted := make([]*models.Template, len(templates))
+	for i, t := range templates {
+		redacted[i] = redactor.Template(t)
+	}
+	return redacted
+}
+
+// exportAttachments copies attachments next to an export file so importing
+// it elsewhere brings them along. Exports to stdout only reference them.
+// Redacted exports leave them out, since images cannot be redacted.
+func (c *CLI) exportAttachments(prompts []*models.Prompt, outputFile string, redacted bool) error {
+	if outputFile == "" {
+		return nil
+	}
+	if redacted {
+		fmt.Fprintln(os.Stderr, "Attachments are not exported with --redact")
+		return nil
+	}
+	copied, err := c.service.ExportAttachments(prompts, filepath.Dir(outputFile))
+	if err != nil {
+		return err
+	}
+	if copied > 0 {
+		fmt.Printf("Exported %d attachments to %s\n", copied, filepath.Join(filepath.Dir(outputFile), "assets"))
+	}
+	return nil
+}
+
+// exportData exports data in the specified format
+func (c *CLI) exportData(data interface{}, format, outputFile string) error {
+	var output []byte
+	var err error
+
+	switch format {
+	case "json":
+		output, err = json.MarshalIndent(data, "", "  ")
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	if outputFile != "" {
+		return os.WriteFile(outputFile, output, 0644)
+	}
+
+	fmt.Print(string(output))
+	return nil
+}
+
+// handleImport handles import operations
+func (c *CLI) handleImport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("import requires a subcommand or file path\n\nUsage:\n  pkt import wizard [options]       # Find sources on this machine and import them\n  pkt import claude-code [options]  # Import from Claude Code\n  pkt import git-repo <repo-url> [options]  # Import from Git repository\n  pkt import promptlayer|langfuse <export.json> [options]  # Import a prompt registry export\n  pkt import <plugin-importer> [args] [options]  # Import with a plugin (see 'pkt plugins')\n  pkt import <file> [options]       # Import from JSON file")
+	}
+
+	subcommand := args[0]
+
+	// Handle the onboarding wizard, which imports from every source it finds
+	if subcommand == "wizard" {
+		return c.handleImportWizard(args[1:])
+	}
+	
+	// Handle Claude Code import
+	if subcommand == "claude-code" {
+		return c.handleClaudeCodeImport(args[1:])
+	}
+	
+	// Handle Git repository import
+	if subcommand == "git-repo" {
+		return c.handleGitRepoImport(args[1:])
+	}
+
+	// Handle hosted prompt registry exports
+	if subcommand == importer.RegistryPromptLayer || subcommand == importer.RegistryLangfuse {
+		return c.handleRegistryImport(subcommand, args[1:])
+	}
+
+	// Handle importers installed as plugins, unless a file has the same name
+	if _, err := os.Stat(subcommand); err != nil && c.service.HasPluginImporter(subcommand) {
+		return c.handlePluginImport(subcommand, args[1:])
+	}
+	
+	// Handle file import (existing functionality)
+	return c.handleFileImport(args)
+}
+
+// handleClaudeCodeImport handles importing from Claude Code installations
+func (c *CLI) handleClaudeCodeImport(args []string) error {
+	options := importer.ImportOptions{}
+	interactive := false
+	watch := false
+	interval := 2 * time.Second
+	
+	// Parse flags
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--path":
+			if i+1 < len(args) {
+				options.Path = args[i+1]
+				i++
+			}
+		case "--user":
+			options.UserLevel = true
+		case "--commands-only":
+			options.CommandsOnly = true
+		case "--workflows-only":
+			options.WorkflowsOnly = true
+		case "--config-only":
+			options.ConfigOnly = true
+		case "--preview", "--dry-run":
+			options.DryRun = true
+		case "--tags":
+			if i+1 < len(args) {
+				tags := strings.Split(args[i+1], ",")
+				for j := range tags {
+					tags[j] = strings.TrimSpace(tags[j])
+				}
+				options.Tags = tags
+				i++
+			}
+		case "--overwrite":
+			options.OverwriteExisting = true
+		case "--skip-existing":
+			options.SkipExisting = true
+		case "--deduplicate":
+			options.DeduplicateByPath = true
+		case "--interactive", "-i":
+			interactive = true
+		case "--watch":
+			watch = true
+		case "--interval":
 			if i+1 < len(args) {
-				slots := strings.Split(args[i+1], ",")
-				template.Slots = []models.Slot{}
-				for _, slot := range slots {
-					template.Slots = append(template.Slots, models.Slot{
-						Name:        strings.TrimSpace(slot),
-						Required:    false,
-						Description: "",
-						Default:     "",
-					})
+				d, err := time.ParseDuration(args[i+1])
+				if err != nil || d <= 0 {
+					return fmt.Errorf("invalid --interval %q (expected a duration such as 2s or 1m)", args[i+1])
 				}
+				interval = d
 				i++
 			}
 		}
 	}
 
-	if err := c.service.SaveTemplate(template); err != nil {
-		return fmt.Errorf("failed to update template: %w", err)
+	if watch {
+		if options.DryRun || interactive {
+			return fmt.Errorf("--watch cannot be combined with --preview or --interactive")
+		}
+		return c.watchClaudeCode(options, interval)
 	}
 
-	fmt.Printf("Updated template: %s\n", id)
-	return nil
-}
+	if interactive && !options.DryRun {
+		preview := options
+		preview.DryRun = true
+		found, err := c.service.ImportFromClaudeCode(preview)
+		if err != nil {
+			return fmt.Errorf("failed to import from Claude Code: %w", err)
+		}
+		selection, merged, ok, err := c.pickImportItems(found)
+		if !ok {
+			return err
+		}
+		options.Selection, options.Merged = selection, merged
+	}
 
-// deleteTemplate deletes a template
-func (c *CLI) deleteTemplate(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("delete template requires a template ID")
+	// Perform the import
+	result, err := c.service.ImportFromClaudeCode(options)
+	if err != nil {
+		return fmt.Errorf("failed to import from Claude Code: %w", err)
 	}
 
-	id := args[0]
-	var force bool
+	// Display results
+	if options.DryRun {
+		fmt.Println("Claude Code Import Preview:")
+		fmt.Println("===========================")
+	} else {
+		fmt.Println("Claude Code Import Complete:")
+		fmt.Println("============================")
+	}
 
-	// Parse flags
-	for _, arg := range args[1:] {
-		if arg == "--force" || arg == "-f" {
-			force = true
+	if len(result.Prompts) > 0 {
+		fmt.Printf("Prompts: %d\n", len(result.Prompts))
+		for _, prompt := range result.Prompts {
+			fmt.Printf("  - %s (%s)\n", prompt.Name, prompt.ID)
 		}
 	}
 
-	if !force {
-		fmt.Printf("Are you sure you want to delete template '%s'? (y/N): ", id)
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-			fmt.Println("Cancelled")
-			return nil
+	if len(result.Workflows) > 0 {
+		fmt.Printf("Workflows: %d\n", len(result.Workflows))
+		for _, wf := range result.Workflows {
+			fmt.Printf("  - %s (%s)\n", wf.Name, wf.ID)
 		}
 	}
 
-	if err := c.service.DeleteTemplate(id); err != nil {
-		return fmt.Errorf("failed to delete template: %w", err)
+	if len(result.Errors) > 0 {
+		fmt.Printf("\nErrors encountered: %d\n", len(result.Errors))
+		for _, err := range result.Errors {
+			fmt.Printf("  - %v\n", err)
+		}
+	}
+
+	if options.DryRun {
+		fmt.Printf("\nTo actually import these items, run the same command without --preview\n")
+	} else {
+		total := len(result.Prompts) + len(result.Workflows)
+		fmt.Printf("\nSuccessfully imported %d items from Claude Code\n", total)
 	}
 
-	fmt.Printf("Deleted template: %s\n", id)
 	return nil
 }
 
-// formatSingleTemplate formats a single template for output
-func (c *CLI) formatSingleTemplate(template *models.Template, format string) error {
-	switch format {
-	case "json":
-		return json.NewEncoder(os.Stdout).Encode(template)
-	default:
-		fmt.Printf("ID: %s\n", template.ID)
-		fmt.Printf("Name: %s\n", template.Name)
-		fmt.Printf("Version: %s\n", template.Version)
-		if template.Description != "" {
-			fmt.Printf("Description: %s\n", template.Description)
+// watchClaudeCode keeps importing Claude Code changes until interrupted
+func (c *CLI) watchClaudeCode(options importer.ImportOptions, interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), platform.ShutdownSignals...)
+	defer stop()
+
+	fmt.Printf("Watching for Claude Code changes every %s (Ctrl+C to stop):\n", interval)
+	for _, dir := range importer.NewClaudeCodeImporter(c.service.GetBaseDir()).SourceDirs(options) {
+		fmt.Printf("  %s\n", dir)
+	}
+
+	err := c.service.WatchClaudeCode(ctx, options, interval, func(result *importer.ImportResult) {
+		stamp := time.Now().Format("15:04:05")
+		for _, prompt := range append(result.Prompts, result.Workflows...) {
+			fmt.Printf("[%s] Imported %s (%s)\n", stamp, prompt.Name, prompt.ID)
 		}
-		fmt.Printf("Created: %s\n", template.CreatedAt.Format("2006-01-02 15:04"))
-		fmt.Printf("Updated: %s\n", template.UpdatedAt.Format("2006-01-02 15:04"))
-		
-		if len(template.Slots) > 0 {
-			fmt.Println("\nSlots:")
-			for _, slot := range template.Slots {
-				fmt.Printf("  %s", slot.Name)
-				if slot.Required {
-					fmt.Print(" [required]")
-				}
-				if slot.Default != "" {
-					fmt.Printf(" [default: %s]", slot.Default)
-				}
-				if slot.Description != "" {
-					fmt.Printf(" - %s", slot.Description)
-				}
-				fmt.Println()
-			}
+		for _, err := range result.Errors {
+			fmt.Printf("[%s] Error: %v\n", stamp, err)
 		}
-		
-		fmt.Printf("\nContent:\n%s\n", template.Content)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch Claude Code: %w", err)
 	}
+	fmt.Println("Stopped watching")
 	return nil
 }
 
-// handleBooleanSearch handles boolean search operations
-func (c *CLI) handleBooleanSearch(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("boolean-search requires a subcommand (create, edit, delete, list, run)")
+// handleWatchClipboard saves prompts copied to the clipboard until
+// interrupted. Flags replace the clipboard settings.
+func (c *CLI) handleWatchClipboard(args []string) error {
+	settings := c.service.Settings().Clipboard
+	var tags, patterns []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--tag", "-t", "--match", "--min-length", "--interval":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", args[i])
+			}
+			value := args[i+1]
+			switch args[i] {
+			case "--tag", "-t":
+				tags = append(tags, value)
+			case "--match":
+				patterns = append(patterns, value)
+			case "--min-length":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return fmt.Errorf("--min-length expects a number of characters, got %q", value)
+				}
+				settings.MinLength = n
+			case "--interval":
+				settings.Interval = value
+			}
+			i++
+		default:
+			return fmt.Errorf("unknown option: %s", args[i])
+		}
+	}
+	if len(tags) > 0 {
+		settings.Tags = tags
+	}
+	if len(patterns) > 0 {
+		settings.Patterns = patterns
 	}
 
-	subcommand := args[0]
-	switch subcommand {
-	case "create":
-		return c.createBooleanSearch(args[1:])
-	case "edit":
-		return c.editBooleanSearch(args[1:])
-	case "delete":
-		return c.deleteBooleanSearch(args[1:])
-	case "list":
-		return c.listBooleanSearches()
-	case "run":
-		return c.runBooleanSearch(args[1:])
-	default:
-		return fmt.Errorf("unknown boolean-search subcommand: %s", subcommand)
+	matchers, err := settings.Matchers()
+	if err != nil {
+		return err
+	}
+	interval, err := settings.PollInterval()
+	if err != nil {
+		return err
+	}
+	filter := service.ClipboardFilter{MinLength: settings.MinimumLength(), Patterns: matchers}
+
+	ctx, stop := signal.NotifyContext(context.Background(), platform.ShutdownSignals...)
+	defer stop()
+	fmt.Printf("Watching the clipboard for prompts of %d characters or more (Ctrl+C to stop)\n", filter.MinLength)
+	err = c.service.WatchClipboard(ctx, clipboard.Paste, filter, settings.TagNames(), interval, func(prompt *models.Prompt) {
+		fmt.Printf("[%s] Saved %s (%s)\n", time.Now().Format("15:04:05"), prompt.Name, c.out.id(prompt.ID))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch the clipboard: %w", err)
 	}
+	fmt.Println("Stopped watching")
+	return nil
 }
 
-// createBooleanSearch creates a new saved boolean search
-func (c *CLI) createBooleanSearch(args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("create boolean search requires name and expression")
+// handleRegistryImport handles importing PromptLayer and Langfuse registry exports
+func (c *CLI) handleRegistryImport(registry string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s import requires an export file path", registry)
 	}
 
-	name := args[0]
-	var textQuery string
-	var expressionParts []string
-	
+	options := importer.RegistryImportOptions{
+		Registry: registry,
+		File:     args[0],
+	}
+	interactive := false
+
 	// Parse flags
-	i := 1
-	for i < len(args) {
+	for i := 1; i < len(args); i++ {
 		arg := args[i]
 		switch arg {
-		case "--text", "-t":
+		case "--label":
 			if i+1 < len(args) {
-				textQuery = args[i+1]
-				i += 2
-			} else {
+				options.Label = args[i+1]
 				i++
 			}
-		default:
-			expressionParts = append(expressionParts, arg)
-			i++
+		case "--preview", "--dry-run":
+			options.DryRun = true
+		case "--tags":
+			if i+1 < len(args) {
+				tags := strings.Split(args[i+1], ",")
+				for j := range tags {
+					tags[j] = strings.TrimSpace(tags[j])
+				}
+				options.Tags = tags
+				i++
+			}
+		case "--overwrite":
+			options.OverwriteExisting = true
+		case "--skip-existing":
+			options.SkipExisting = true
+		case "--interactive", "-i":
+			interactive = true
 		}
 	}
-	
-	if len(expressionParts) == 0 {
-		return fmt.Errorf("boolean expression is required")
-	}
-	
-	expression := strings.Join(expressionParts, " ")
-
-	// Parse the boolean expression
-	expr, err := parseBooleanExpression(expression)
-	if err != nil {
-		return fmt.Errorf("invalid boolean expression: %w", err)
-	}
-
-	savedSearch := models.SavedSearch{
-		Name:       name,
-		Expression: expr,
-		TextQuery:  textQuery,
-	}
 
-	if err := c.service.SaveBooleanSearch(savedSearch); err != nil {
-		return fmt.Errorf("failed to save boolean search: %w", err)
+	if interactive && !options.DryRun {
+		preview := options
+		preview.DryRun = true
+		found, err := c.service.ImportFromRegistry(preview)
+		if err != nil {
+			return err
+		}
+		selection, merged, ok, err := c.pickImportItems(found.ImportResult)
+		if !ok {
+			return err
+		}
+		options.Selection, options.Merged = selection, merged
 	}
 
-	message := fmt.Sprintf("Created boolean search: %s", name)
-	if textQuery != "" {
-		message += fmt.Sprintf(" (with text filter: '%s')", textQuery)
+	// Perform the import
+	result, err := c.service.ImportFromRegistry(options)
+	if err != nil {
+		return err
 	}
-	fmt.Println(message)
-	return nil
-}
 
-// editBooleanSearch edits an existing saved boolean search
-func (c *CLI) editBooleanSearch(args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("edit boolean search requires name and new expression")
+	// Display results
+	registryName := map[string]string{
+		importer.RegistryPromptLayer: "PromptLayer",
+		importer.RegistryLangfuse:    "Langfuse",
+	}[registry]
+	header := fmt.Sprintf("%s Import Complete:", registryName)
+	if options.DryRun {
+		header = fmt.Sprintf("%s Import Preview:", registryName)
 	}
+	fmt.Println(header)
+	fmt.Println(strings.Repeat("=", len(header)))
 
-	name := args[0]
-	expression := strings.Join(args[1:], " ")
-
-	// Parse the boolean expression
-	expr, err := parseBooleanExpression(expression)
-	if err != nil {
-		return fmt.Errorf("invalid boolean expression: %w", err)
+	archivedByID := make(map[string]int)
+	for _, prompt := range result.Archived {
+		archivedByID[prompt.ID]++
 	}
 
-	// Delete old search
-	if err := c.service.DeleteSavedSearch(name); err != nil {
-		return fmt.Errorf("failed to delete old search: %w", err)
+	if len(result.Prompts) > 0 {
+		fmt.Printf("Prompts: %d\n", len(result.Prompts))
+		for _, prompt := range result.Prompts {
+			fmt.Printf("  - %s (%s) v%s", prompt.Name, prompt.ID, prompt.Version)
+			if n := archivedByID[prompt.ID]; n > 0 {
+				fmt.Printf(", %d archived versions", n)
+			}
+			fmt.Println()
+		}
 	}
 
-	savedSearch := models.SavedSearch{
-		Name:       name,
-		Expression: expr,
+	if len(result.Errors) > 0 {
+		fmt.Printf("\nErrors encountered: %d\n", len(result.Errors))
+		for _, err := range result.Errors {
+			fmt.Printf("  - %v\n", err)
+		}
 	}
 
-	if err := c.service.SaveBooleanSearch(savedSearch); err != nil {
-		return fmt.Errorf("failed to save updated boolean search: %w", err)
+	if options.DryRun {
+		fmt.Printf("\nTo actually import these items, run the same command without --preview\n")
+	} else {
+		fmt.Printf("\nSuccessfully imported %d prompts and %d archived versions from %s\n",
+			len(result.Prompts), len(result.Archived), registryName)
 	}
 
-	fmt.Printf("Updated boolean search: %s\n", name)
 	return nil
 }
 
-// deleteBooleanSearch deletes a saved boolean search
-func (c *CLI) deleteBooleanSearch(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("delete boolean search requires a name")
-	}
-
-	name := args[0]
-	var force bool
+// handlePluginImport runs an importer installed as a plugin. Import options
+// are taken by pkt; every other argument is passed to the plugin.
+func (c *CLI) handlePluginImport(name string, args []string) error {
+	options := importer.ImportOptions{}
+	interactive := false
+	var pluginArgs []string
 
 	// Parse flags
-	for _, arg := range args[1:] {
-		if arg == "--force" || arg == "-f" {
-			force = true
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--preview", "--dry-run":
+			options.DryRun = true
+		case "--tags":
+			if i+1 < len(args) {
+				tags := strings.Split(args[i+1], ",")
+				for j := range tags {
+					tags[j] = strings.TrimSpace(tags[j])
+				}
+				options.Tags = tags
+				i++
+			}
+		case "--overwrite":
+			options.OverwriteExisting = true
+		case "--skip-existing":
+			options.SkipExisting = true
+		case "--interactive", "-i":
+			interactive = true
+		default:
+			pluginArgs = append(pluginArgs, arg)
 		}
 	}
 
-	if !force {
-		fmt.Printf("Are you sure you want to delete boolean search '%s'? (y/N): ", name)
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-			fmt.Println("Cancelled")
-			return nil
+	if interactive && !options.DryRun {
+		preview := options
+		preview.DryRun = true
+		found, err := c.service.ImportFromPlugin(name, pluginArgs, preview)
+		if err != nil {
+			return err
 		}
+		selection, merged, ok, err := c.pickImportItems(found)
+		if !ok {
+			return err
+		}
+		options.Selection, options.Merged = selection, merged
 	}
 
-	if err := c.service.DeleteSavedSearch(name); err != nil {
-		return fmt.Errorf("failed to delete boolean search: %w", err)
-	}
-
-	fmt.Printf("Deleted boolean search: %s\n", name)
-	return nil
-}
-
-// listBooleanSearches lists all saved boolean searches
-func (c *CLI) listBooleanSearches() error {
-	searches, err := c.service.ListSavedSearches()
+	result, err := c.service.ImportFromPlugin(name, pluginArgs, options)
 	if err != nil {
-		return fmt.Errorf("failed to list saved searches: %w", err)
+		return err
 	}
 
-	for _, search := range searches {
-		fmt.Printf("%s: %s\n", search.Name, search.Expression.String())
+	header := fmt.Sprintf("%s Import Complete:", name)
+	if options.DryRun {
+		header = fmt.Sprintf("%s Import Preview:", name)
 	}
-	return nil
-}
+	fmt.Println(header)
+	fmt.Println(strings.Repeat("=", len(header)))
 
-// runBooleanSearch executes a boolean search expression
-func (c *CLI) runBooleanSearch(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("run boolean search requires either a saved search name or expression")
+	if len(result.Prompts) > 0 {
+		fmt.Printf("Prompts: %d\n", len(result.Prompts))
+		for _, prompt := range result.Prompts {
+			fmt.Printf("  - %s (%s)\n", prompt.Name, prompt.ID)
+		}
 	}
-
-	var format string
-	var expression string
-	var useSavedSearch bool
-
-	// Check if first arg is --saved to use a saved search
-	if args[0] == "--saved" {
-		if len(args) < 2 {
-			return fmt.Errorf("--saved requires a search name")
+	if len(result.Templates) > 0 {
+		fmt.Printf("Templates: %d\n", len(result.Templates))
+		for _, template := range result.Templates {
+			fmt.Printf("  - %s (%s)\n", template.Name, template.ID)
 		}
-		useSavedSearch = true
-		expression = args[1]
-		args = args[2:]
-	} else {
-		expression = strings.Join(args, " ")
 	}
 
-	// Parse remaining flags
-	parts := strings.Fields(expression)
-	var cleanedParts []string
-	for i, part := range parts {
-		switch part {
-		case "--format", "-f":
-			if i+1 < len(parts) {
-				format = parts[i+1]
-			}
-		default:
-			if i == 0 || (parts[i-1] != "--format" && parts[i-1] != "-f") {
-				cleanedParts = append(cleanedParts, part)
-			}
+	if len(result.Errors) > 0 {
+		fmt.Printf("\nErrors encountered: %d\n", len(result.Errors))
+		for _, err := range result.Errors {
+			fmt.Printf("  - %v\n", err)
 		}
 	}
-	expression = strings.Join(cleanedParts, " ")
-
-	var prompts []*models.Prompt
-	var err error
 
-	if useSavedSearch {
-		prompts, err = c.service.ExecuteSavedSearch(expression)
+	if options.DryRun {
+		fmt.Printf("\nTo actually import these items, run the same command without --preview\n")
 	} else {
-		// Parse the boolean expression
-		expr, parseErr := parseBooleanExpression(expression)
-		if parseErr != nil {
-			return fmt.Errorf("invalid boolean expression: %w", parseErr)
-		}
-		prompts, err = c.service.SearchPromptsByBooleanExpression(expr)
+		fmt.Printf("\nSuccessfully imported %d prompts and %d templates from %s\n",
+			len(result.Prompts), len(result.Templates), name)
 	}
+	return nil
+}
 
-	if err != nil {
-		return fmt.Errorf("boolean search failed: %w", err)
+// handlePlugins lists the installed plugins and what they provide
+func (c *CLI) handlePlugins(args []string) error {
+	if len(args) > 0 && args[0] != "list" {
+		return fmt.Errorf("unknown plugins subcommand: %s", args[0])
 	}
 
-	return c.formatOutput(prompts, format)
+	plugins, errs := c.service.Plugins()
+	for _, err := range errs {
+		warnf("%v", err)
+	}
+	if len(plugins) == 0 {
+		dir, _ := plugin.Dir()
+		fmt.Printf("No plugins installed. Put pkt-plugin-<name> executables in %s or on PATH (see 'pkt help plugins').\n", dir)
+		return nil
+	}
+
+	for _, p := range plugins {
+		fmt.Printf("%s  %s\n", p.Name, p.Path)
+		for _, kind := range []string{plugin.KindImporter, plugin.KindExporter, plugin.KindFormatter} {
+			for _, capability := range p.Capabilities(kind) {
+				fmt.Printf("  %-10s %-16s %s\n", kind, capability.Name, capability.Description)
+			}
+		}
+	}
+	return nil
 }
 
-// handleExport handles export operations
-func (c *CLI) handleExport(args []string) error {
+// handleFileImport handles importing from JSON files (existing functionality)
+func (c *CLI) handleFileImport(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("export requires a subcommand (prompts, templates, all)")
+		return fmt.Errorf("file import requires a file path")
 	}
 
-	subcommand := args[0]
+	filePath := args[0]
 	var format string
-	var outputFile string
+	interactive := false
 
 	// Parse flags
 	for i := 1; i < len(args); i++ {
@@ -1359,11 +6449,8 @@ func (c *CLI) handleExport(args []string) error {
 				format = args[i+1]
 				i++
 			}
-		case "--output", "-o":
-			if i+1 < len(args) {
-				outputFile = args[i+1]
-				i++
-			}
+		case "--interactive", "-i":
+			interactive = true
 		}
 	}
 
@@ -1371,240 +6458,285 @@ func (c *CLI) handleExport(args []string) error {
 		format = "json"
 	}
 
-	switch subcommand {
-	case "prompts":
-		prompts, err := c.service.ListPrompts()
-		if err != nil {
-			return fmt.Errorf("failed to list prompts: %w", err)
-		}
-		return c.exportData(prompts, format, outputFile)
-	case "templates":
-		templates, err := c.service.ListTemplates()
-		if err != nil {
-			return fmt.Errorf("failed to list templates: %w", err)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	switch format {
+	case "json":
+		var importData map[string]interface{}
+		if err := json.Unmarshal(data, &importData); err != nil {
+			return fmt.Errorf("failed to parse JSON: %w", err)
 		}
-		return c.exportData(templates, format, outputFile)
-	case "all":
-		prompts, err := c.service.ListPrompts()
-		if err != nil {
-			return fmt.Errorf("failed to list prompts: %w", err)
+
+		found := &importer.ImportResult{}
+		promptsData, hasPrompts := importData["prompts"]
+		if hasPrompts {
+			promptsJSON, _ := json.Marshal(promptsData)
+			hasPrompts = json.Unmarshal(promptsJSON, &found.Prompts) == nil
 		}
-		templates, err := c.service.ListTemplates()
-		if err != nil {
-			return fmt.Errorf("failed to list templates: %w", err)
+		templatesData, hasTemplates := importData["templates"]
+		if hasTemplates {
+			templatesJSON, _ := json.Marshal(templatesData)
+			hasTemplates = json.Unmarshal(templatesJSON, &found.Templates) == nil
 		}
-		data := map[string]interface{}{
-			"prompts":   prompts,
-			"templates": templates,
+
+		if interactive {
+			selection, merged, ok, err := c.pickImportItems(found)
+			if !ok {
+				return err
+			}
+			found.ApplySelection(importer.ImportOptions{Selection: selection, Merged: merged})
 		}
-		return c.exportData(data, format, outputFile)
-	default:
-		return fmt.Errorf("unknown export subcommand: %s", subcommand)
-	}
-}
 
-// exportData exports data in the specified format
-func (c *CLI) exportData(data interface{}, format, outputFile string) error {
-	var output []byte
-	var err error
+		// Import prompts if present
+		if hasPrompts {
+			track := c.service.TrackSubscriptions(service.MatchFromImport)
+			for _, prompt := range found.Prompts {
+				if err := c.service.SavePrompt(prompt); err != nil {
+					warnf("failed to import prompt %s: %v", prompt.ID, err)
+				}
+			}
+			track()
+			fmt.Printf("Imported %d prompts\n", len(found.Prompts))
+			if copied, err := c.service.ImportAttachments(found.Prompts, filepath.Dir(filePath)); err != nil {
+				warnf("%v", err)
+			} else if copied > 0 {
+				fmt.Printf("Imported %d attachments\n", copied)
+			}
+		}
 
-	switch format {
-	case "json":
-		output, err = json.MarshalIndent(data, "", "  ")
+		// Import templates if present
+		if hasTemplates {
+			for _, template := range found.Templates {
+				if err := c.service.SaveTemplate(template); err != nil {
+					warnf("failed to import template %s: %v", template.ID, err)
+				}
+			}
+			fmt.Printf("Imported %d templates\n", len(found.Templates))
+		}
 	default:
-		return fmt.Errorf("unsupported export format: %s", format)
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
-	}
-
-	if outputFile != "" {
-		return os.WriteFile(outputFile, output, 0644)
+		return fmt.Errorf("unsupported import format: %s", format)
 	}
 
-	fmt.Print(string(output))
 	return nil
 }
 
-// handleImport handles import operations
-func (c *CLI) handleImport(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("import requires a subcommand or file path\n\nUsage:\n  pkt import claude-code [options]  # Import from Claude Code\n  pkt import git-repo <repo-url> [options]  # Import from Git repository\n  pkt import <file> [options]       # Import from JSON file")
+// handleImportWizard finds prompts to import on this machine, asks which
+// sources to bring in, with which tags and into which packs, then imports
+// them all and reports on every source
+func (c *CLI) handleImportWizard(args []string) error {
+	dryRun, yes := false, false
+	options := importer.ImportOptions{SkipExisting: true}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--preview", "--dry-run":
+			dryRun = true
+		case "--yes", "-y":
+			yes = true
+		case "--tags":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--tags requires a value")
+			}
+			options.Tags = append(options.Tags, splitWizardTags(args[i+1])...)
+			i++
+		default:
+			return fmt.Errorf("unknown import wizard option: %s", args[i])
+		}
 	}
+	options.DryRun = dryRun
 
-	subcommand := args[0]
-	
-	// Handle Claude Code import
-	if subcommand == "claude-code" {
-		return c.handleClaudeCodeImport(args[1:])
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to find your home folder: %w", err)
 	}
-	
-	// Handle Git repository import
-	if subcommand == "git-repo" {
-		return c.handleGitRepoImport(args[1:])
+	cwd, _ := os.Getwd()
+	fmt.Println("Looking for prompts to import...")
+	sources := c.service.DetectImportSources(home, cwd)
+	if len(sources) == 0 {
+		fmt.Println("No sources found. Import from elsewhere with pkt import claude-code --path <dir>, pkt import git-repo <url> or pkt import <file>.")
+		return nil
+	}
+	fmt.Printf("\nFound %d sources:\n", len(sources))
+	for i, src := range sources {
+		fmt.Printf("  %d. %-16s %s  (%d items)\n", i+1, src.Label(), wizardSourceName(src, home), src.Items)
 	}
-	
-	// Handle file import (existing functionality)
-	return c.handleFileImport(args)
-}
 
-// handleClaudeCodeImport handles importing from Claude Code installations
-func (c *CLI) handleClaudeCodeImport(args []string) error {
-	options := importer.ImportOptions{}
-	
-	// Parse flags
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		switch arg {
-		case "--path":
-			if i+1 < len(args) {
-				options.Path = args[i+1]
-				i++
+	reader := bufio.NewReader(os.Stdin)
+	ask := func(question string) string {
+		fmt.Print(question)
+		answer, _ := reader.ReadString('\n')
+		return strings.TrimSpace(answer)
+	}
+	selected := sources
+	packs := make([]string, len(sources))
+	if !yes {
+		for {
+			selected, err = pickWizardSources(sources, ask("\nImport which sources? (numbers such as 1,3; Enter for all, \"none\" to stop): "))
+			if err == nil {
+				break
 			}
-		case "--user":
-			options.UserLevel = true
-		case "--commands-only":
-			options.CommandsOnly = true
-		case "--workflows-only":
-			options.WorkflowsOnly = true
-		case "--config-only":
-			options.ConfigOnly = true
-		case "--preview", "--dry-run":
-			options.DryRun = true
-		case "--tags":
-			if i+1 < len(args) {
-				tags := strings.Split(args[i+1], ",")
-				for j := range tags {
-					tags[j] = strings.TrimSpace(tags[j])
+			fmt.Println(err)
+		}
+		if len(selected) == 0 {
+			fmt.Println("Import cancelled")
+			return nil
+		}
+
+		if answer := ask("Tags to add to everything imported (comma-separated, Enter for none): "); answer != "" {
+			options.Tags = append(options.Tags, splitWizardTags(answer)...)
+		}
+		if names := c.service.GetAvailablePackNames(); len(names) > 1 {
+			fmt.Printf("Installed packs: %s\n", strings.Join(names[1:], ", "))
+			for i, src := range selected {
+				for {
+					pack := ask(fmt.Sprintf("Pack for %s (Enter for the personal library): ", wizardSourceName(src, home)))
+					if pack == "" || pack == "personal" || c.service.IsPackInstalled(pack) {
+						packs[i] = pack
+						break
+					}
+					fmt.Printf("Pack '%s' is not installed\n", pack)
 				}
-				options.Tags = tags
-				i++
 			}
-		case "--overwrite":
-			options.OverwriteExisting = true
-		case "--skip-existing":
-			options.SkipExisting = true
-		case "--deduplicate":
-			options.DeduplicateByPath = true
 		}
-	}
+		switch strings.ToLower(ask("Prompts that already exist: [s]kip or [o]verwrite? (Enter to skip): ")) {
+		case "o", "overwrite":
+			options.SkipExisting, options.OverwriteExisting = false, true
+		}
 
-	// Perform the import
-	result, err := c.service.ImportFromClaudeCode(options)
-	if err != nil {
-		return fmt.Errorf("failed to import from Claude Code: %w", err)
+		if !dryRun && !c.defaults().SkipConfirm() {
+			total := 0
+			for _, src := range selected {
+				total += src.Items
+			}
+			answer := strings.ToLower(ask(fmt.Sprintf("\nImport up to %d items from %d sources? (y/N): ", total, len(selected))))
+			if answer != "y" && answer != "yes" {
+				fmt.Println("Import cancelled")
+				return nil
+			}
+		}
 	}
 
-	// Display results
-	if options.DryRun {
-		fmt.Println("Claude Code Import Preview:")
-		fmt.Println("===========================")
+	if dryRun {
+		fmt.Println("\nImport Wizard Preview:")
+		fmt.Println("======================")
 	} else {
-		fmt.Println("Claude Code Import Complete:")
-		fmt.Println("============================")
-	}
-
-	if len(result.Prompts) > 0 {
-		fmt.Printf("Prompts: %d\n", len(result.Prompts))
-		for _, prompt := range result.Prompts {
-			fmt.Printf("  - %s (%s)\n", prompt.Name, prompt.ID)
+		fmt.Println("\nImport Wizard Report:")
+		fmt.Println("=====================")
+	}
+	imported, failed := 0, 0
+	var problems []error
+	for i, src := range selected {
+		sourceOptions := options
+		sourceOptions.Pack = packs[i]
+		destination := "personal library"
+		if packs[i] != "" && packs[i] != "personal" {
+			destination = "pack " + packs[i]
 		}
-	}
+		name := fmt.Sprintf("%s %s", src.Label(), wizardSourceName(src, home))
 
-	if len(result.Workflows) > 0 {
-		fmt.Printf("Workflows: %d\n", len(result.Workflows))
-		for _, wf := range result.Workflows {
-			fmt.Printf("  - %s (%s)\n", wf.Name, wf.ID)
+		result, err := c.service.ImportFromSource(src, sourceOptions)
+		if err != nil {
+			fmt.Printf("  %s: failed\n", name)
+			problems = append(problems, err)
+			failed++
+			continue
 		}
+		count := len(result.Prompts) + len(result.Templates) + len(result.Workflows)
+		line := fmt.Sprintf("  %s → %s: %d items", name, destination, count)
+		if len(result.Errors) > 0 {
+			line += fmt.Sprintf(", %d errors", len(result.Errors))
+			problems = append(problems, result.Errors...)
+		}
+		fmt.Println(line)
+		imported += count
 	}
 
-	if len(result.Errors) > 0 {
-		fmt.Printf("\nErrors encountered: %d\n", len(result.Errors))
-		for _, err := range result.Errors {
+	if len(problems) > 0 {
+		fmt.Printf("\nErrors encountered: %d\n", len(problems))
+		for _, err := range problems {
 			fmt.Printf("  - %v\n", err)
 		}
 	}
-
-	if options.DryRun {
-		fmt.Printf("\nTo actually import these items, run the same command without --preview\n")
+	if dryRun {
+		fmt.Printf("\nWould import %d items from %d sources; run again without --dry-run to import them\n", imported, len(selected)-failed)
 	} else {
-		total := len(result.Prompts) + len(result.Workflows)
-		fmt.Printf("\nSuccessfully imported %d items from Claude Code\n", total)
+		fmt.Printf("\nImported %d items from %d sources\n", imported, len(selected)-failed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sources could not be imported", failed, len(selected))
 	}
-
 	return nil
 }
 
-// handleFileImport handles importing from JSON files (existing functionality)
-func (c *CLI) handleFileImport(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("file import requires a file path")
+// pickWizardSources reads an answer such as "1,3" or "all" into the sources
+// it picks; "none" picks none
+func pickWizardSources(sources []service.ImportSource, answer string) ([]service.ImportSource, error) {
+	switch strings.ToLower(answer) {
+	case "", "all":
+		return sources, nil
+	case "none", "q", "quit":
+		return nil, nil
+	}
+	var picked []service.ImportSource
+	seen := map[int]bool{}
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(sources) {
+			return nil, fmt.Errorf("pick sources by number, from 1 to %d", len(sources))
+		}
+		if !seen[n] {
+			seen[n] = true
+			picked = append(picked, sources[n-1])
+		}
 	}
+	return picked, nil
+}
 
-	filePath := args[0]
-	var format string
+// wizardSourceName shows where a source is, with ~ for home, and the
+// origin of a git checkout
+func wizardSourceName(src service.ImportSource, home string) string {
+	name := src.Path
+	if rel, err := filepath.Rel(home, src.Path); err == nil && !strings.HasPrefix(rel, "..") {
+		name = filepath.Join("~", rel)
+	}
+	if src.RepoURL != "" {
+		name += " (" + src.RepoURL + ")"
+	}
+	return name
+}
 
-	// Parse flags
-	for i := 1; i < len(args); i++ {
-		arg := args[i]
-		switch arg {
-		case "--format", "-f":
-			if i+1 < len(args) {
-				format = args[i+1]
-				i++
-			}
+// splitWizardTags splits a comma-separated list of tags
+func splitWizardTags(list string) []string {
+	var tags []string
+	for _, tag := range strings.Split(list, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
 		}
 	}
+	return tags
+}
 
-	if format == "" {
-		format = "json"
+// pickImportItems shows the items an import found as a checklist with diffs
+// for conflicts, which can be merged hunk by hunk. merged holds the content
+// of the prompts merged, by selection key. ok is false when there is nothing
+// to import or the user cancels, in which case err reports any failure.
+func (c *CLI) pickImportItems(found *importer.ImportResult) (selection map[string]bool, merged map[string]string, ok bool, err error) {
+	candidates := c.service.ImportCandidates(found)
+	if len(candidates) == 0 {
+		fmt.Println("Nothing to import")
+		return nil, nil, false, nil
 	}
 
-	data, err := os.ReadFile(filePath)
+	selection, merged, ok, err = ui.RunImportPicker(candidates)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return nil, nil, false, fmt.Errorf("import picker failed: %w", err)
 	}
-
-	switch format {
-	case "json":
-		var importData map[string]interface{}
-		if err := json.Unmarshal(data, &importData); err != nil {
-			return fmt.Errorf("failed to parse JSON: %w", err)
-		}
-
-		// Import prompts if present
-		if promptsData, ok := importData["prompts"]; ok {
-			promptsJSON, _ := json.Marshal(promptsData)
-			var prompts []*models.Prompt
-			if json.Unmarshal(promptsJSON, &prompts) == nil {
-				for _, prompt := range prompts {
-					if err := c.service.SavePrompt(prompt); err != nil {
-						fmt.Printf("Warning: failed to import prompt %s: %v\n", prompt.ID, err)
-					}
-				}
-				fmt.Printf("Imported %d prompts\n", len(prompts))
-			}
-		}
-
-		// Import templates if present
-		if templatesData, ok := importData["templates"]; ok {
-			templatesJSON, _ := json.Marshal(templatesData)
-			var templates []*models.Template
-			if json.Unmarshal(templatesJSON, &templates) == nil {
-				for _, template := range templates {
-					if err := c.service.SaveTemplate(template); err != nil {
-						fmt.Printf("Warning: failed to import template %s: %v\n", template.ID, err)
-					}
-				}
-				fmt.Printf("Imported %d templates\n", len(templates))
-			}
-		}
-	default:
-		return fmt.Errorf("unsupported import format: %s", format)
+	if !ok {
+		fmt.Println("Import cancelled")
 	}
-
-	return nil
+	return selection, merged, ok, nil
 }
 
 // handleGitRepoImport handles importing from git repositories
@@ -1617,6 +6749,7 @@ func (c *CLI) handleGitRepoImport(args []string) error {
 	options := importer.GitImportOptions{
 		RepoURL: repoURL,
 	}
+	interactive := false
 	
 	// Parse flags
 	for i := 1; i < len(args); i++ {
@@ -1661,7 +6794,23 @@ func (c *CLI) handleGitRepoImport(args []string) error {
 			options.SkipExisting = true
 		case "--deduplicate":
 			options.DeduplicateByPath = true
+		case "--interactive", "-i":
+			interactive = true
+		}
+	}
+
+	if interactive && !options.DryRun {
+		preview := options
+		preview.DryRun = true
+		found, err := c.service.ImportFromGitRepository(preview)
+		if err != nil {
+			return fmt.Errorf("failed to import from git repository: %w", err)
+		}
+		selection, merged, ok, err := c.pickImportItems(found.ImportResult)
+		if !ok {
+			return err
 		}
+		options.Selection, options.Merged = selection, merged
 	}
 
 	// Perform the import
@@ -1716,247 +6865,81 @@ func (c *CLI) handleGitRepoImport(args []string) error {
 	return nil
 }
 
+// maxTopicLines is how many matching lines of one topic's help 'pkt help
+// --search' shows before pointing at the full help
+const maxTopicLines = 3
+
+// searchHelp prints the commands, detailed help and TUI keybindings that
+// mention every word of a term
+func (c *CLI) searchHelp(args []string) error {
+	term := strings.Join(args, " ")
+	if strings.TrimSpace(term) == "" {
+		return fmt.Errorf("usage: pkt help --search <term>")
+	}
+	matches := help.Search(term)
+	if len(matches) == 0 {
+		fmt.Printf("No help mentions %q\n", term)
+		return nil
+	}
+
+	byKind := map[string][]help.Match{}
+	for _, match := range matches {
+		byKind[match.Kind] = append(byKind[match.Kind], match)
+	}
+	if commands := byKind[help.KindCommand]; len(commands) > 0 {
+		fmt.Println(c.out.header("Commands:"))
+		for _, match := range commands {
+			fmt.Printf("  %s\n", match.Text)
+		}
+	}
+	if lines := byKind[help.KindTopic]; len(lines) > 0 {
+		fmt.Println(c.out.header("Help topics:"))
+		for i := 0; i < len(lines); {
+			topic := lines[i].Topic
+			j := i
+			for j < len(lines) && lines[j].Topic == topic {
+				j++
+			}
+			fmt.Printf("  %s (pkt help %s)\n", c.out.id(topic), topic)
+			for k := i; k < j && k < i+maxTopicLines; k++ {
+				fmt.Printf("    %s\n", lines[k].Text)
+			}
+			if more := j - i - maxTopicLines; more > 0 {
+				fmt.Printf("    ... and %d more lines\n", more)
+			}
+			i = j
+		}
+	}
+	if tui := byKind[help.KindTUI]; len(tui) > 0 {
+		fmt.Println(c.out.header("TUI:"))
+		for _, match := range tui {
+			if match.Key != "" {
+				fmt.Printf("  %-8s %s (%s)\n", match.Key, match.Text, match.Topic)
+			} else {
+				fmt.Printf("  %s (%s)\n", match.Text, match.Topic)
+			}
+		}
+	}
+	return nil
+}
+
+// printHelp prints the command list, the detailed help for one command, or
+// with --search the help that mentions a term
 func (c *CLI) printHelp(args []string) error {
 	if len(args) == 0 {
 		return c.printUsage()
 	}
+	if args[0] == "--search" || args[0] == "-s" {
+		return c.searchHelp(args[1:])
+	}
 
 	command := args[0]
-	switch command {
-	case "list", "ls":
-		fmt.Println(`list - List all prompts
-
-Usage: pocket-prompt list [options]
-
-Options:
-  --format, -f <format>  Output format (table, json, ids, default)
-  --tag, -t <tag>        Filter by tag
-  --archived, -a         Show archived prompts`)
-
-	case "search":
-		fmt.Println(`search - Search prompts
-
-Usage: pkt search <query> [options]
-
-Options:
-  --format, -f <format>  Output format (table, json, ids, default)
-  --boolean, -b          Use boolean expression search
-
-Examples:
-  pkt search "machine learning"
-  pkt search --boolean "(ai AND analysis) OR writing"`)
-
-	case "create", "new":
-		fmt.Println(`create - Create a new prompt
-
-Usage: pkt create <id> [options]
-
-Options:
-  --title <title>        Prompt title
-  --description <desc>   Prompt description
-  --content <content>    Prompt content
-  --template <id>        Template to use
-  --tags <tag1,tag2>     Comma-separated tags
-  --pack <pack>          Pack to save to (default: personal)
-  --stdin                Read content from stdin
-
-Example:
-  pkt create my-prompt --title "My Prompt" --content "Hello world" --pack "personal"`)
-
-	case "template":
-		fmt.Println(`template - Template management
-
-Usage: pkt template <subcommand> [options]
-
-Subcommands:
-  create <id>     Create a new template
-  edit <id>       Edit an existing template
-  delete <id>     Delete a template
-  show <id>       Show template details
-
-Create Options:
-  --name <name>           Template name
-  --description <desc>    Template description
-  --content <content>     Template content
-  --slots <slot1,slot2>   Comma-separated slot names
-  --stdin                 Read content from stdin
-
-Edit Options:
-  --name <name>           Update template name
-  --description <desc>    Update template description
-  --content <content>     Update template content
-  --slots <slot1,slot2>   Update slot names
-
-Delete Options:
-  --force, -f             Force deletion without confirmation
-
-Examples:
-  pkt template create my-template --name "My Template" --content "Hello {{name}}"
-  pkt template edit my-template --content "Updated content"`)
-
-	case "boolean-search":
-		fmt.Println(`boolean-search - Manage boolean searches
-
-Usage: pkt boolean-search <subcommand> [options]
-
-Subcommands:
-  create <name> <expression>  Create a new saved boolean search
-  edit <name> <expression>    Edit an existing saved boolean search  
-  delete <name>               Delete a saved boolean search
-  list                        List all saved boolean searches
-  run <expression>            Execute a boolean search expression
-  run --saved <name>          Execute a saved boolean search
-
-Delete Options:
-  --force, -f                 Force deletion without confirmation
-
-Examples:
-  pkt boolean-search create ai-search "(ai AND analysis) OR machine-learning"
-  pkt boolean-search run "(python AND tutorial) OR beginner"
-  pkt boolean-search run --saved ai-search`)
-
-	case "export":
-		fmt.Println(`export - Export prompts and templates
-
-Usage: pkt export <type> [options]
-
-Types:
-  prompts     Export all prompts
-  templates   Export all templates
-  all         Export prompts and templates
-
-Options:
-  --format, -f <format>   Export format (json)
-  --output, -o <file>     Output file (default: stdout)
-
-Examples:
-  pkt export all --output backup.json
-  pkt export prompts --format json`)
-
-	case "import":
-		fmt.Println(`import - Import prompts and templates
-
-Usage: 
-  pkt import claude-code [options]   # Import from Claude Code
-  pkt import git-repo <repo-url> [options]  # Import from Git repository
-  pkt import <file> [options]        # Import from JSON file
-
-Claude Code Import Options:
-  --path <path>           Directory to import from (default: current dir + ~/.claude)
-  --user                  When used with --path, also import from ~/.claude
-  --commands-only         Import only command files (.claude/commands/ and .claude/agents/)
-  --workflows-only        Import only GitHub Actions workflows
-  --config-only           Import only configuration files (CLAUDE.md)
-  --preview, --dry-run    Preview what would be imported without importing
-  --tags <tag1,tag2>      Additional tags to apply to imported items
-  --overwrite             Overwrite existing prompts/templates with same ID
-  --skip-existing         Skip items that already exist (no conflict errors)
-  --deduplicate           Skip duplicates based on original file path
-
-Git Repository Import Options:
-  --owner-tag <tag>       Override owner tag (default: username from URL)
-  --temp-dir <path>       Temporary directory for cloning (default: system temp)
-  --branch <name>         Import from specific branch (default: repository default)
-  --depth <number>        Shallow clone depth (default: full clone)
-  --preview, --dry-run    Preview what would be imported without importing
-  --tags <tag1,tag2>      Additional tags to apply to imported items
-  --overwrite             Overwrite existing prompts/templates with same ID
-  --skip-existing         Skip items that already exist (no conflict errors)
-  --deduplicate           Skip duplicates based on original file path
-
-File Import Options:
-  --format, -f <format>   Import format (json)
-
-Examples:
-  # Import from current project + ~/.claude/commands and ~/.claude/agents
-  pkt import claude-code
-
-  # Preview what would be imported
-  pkt import claude-code --preview
-
-  # Import from specific directory only (without ~/.claude)
-  pkt import claude-code --path /path/to/project
-
-  # Import from specific directory + ~/.claude directories
-  pkt import claude-code --path /path/to/project --user
-
-  # Import from Git repository
-  pkt import git-repo https://github.com/user/prompts.git
-
-  # Import from Git repository with custom owner tag
-  pkt import git-repo https://github.com/user/prompts.git --owner-tag "team-ai"
-
-  # Preview Git repository import
-  pkt import git-repo https://github.com/user/prompts.git --preview
-
-  # Import from specific branch with additional tags
-  pkt import git-repo https://github.com/user/prompts.git --branch "development" --tags "experimental,dev"
-
-  # Import from JSON backup
-  pkt import backup.json --format json`)
-
-	case "git":
-		fmt.Println(`git - Git synchronization
-
-Usage: pkt git <subcommand>
-
-Subcommands:
-  setup <url>     Setup Git repository (handles everything automatically)
-  status          Show git sync status
-  sync            Manual sync with remote repository  
-  pull            Pull changes from remote repository
-  enable          Enable git synchronization
-  disable         Disable git synchronization
-
-Examples:
-  pkt git setup https://github.com/username/my-prompts.git
-  pkt git setup git@github.com:username/my-prompts.git
-  pkt git status
-  pkt git sync`)
-
-	case "packs", "pack":
-		fmt.Println(`packs - Pack management
-
-Packs are collections of prompts and templates that can be installed and shared.
-Perfect for distributing actionable prompts for specific use cases.
-
-Usage: pkt packs <subcommand>
-
-Subcommands:
-  list, ls              List all installed packs
-  install <url|path>    Install a pack from Git URL or directory
-  uninstall <name>      Uninstall a pack
-  info, show <name>     Show detailed pack information
-  create <dir> <name>   Create a new pack scaffold
-  refresh               Refresh pack metadata
-
-Flags:
-  --format json         Output in JSON format
-  --verbose, -v         Show detailed information
-  --tag <tag>           Filter by tag
-  --name <name>         Override pack name when installing
-  --branch <branch>     Install from specific Git branch
-  --force               Force reinstall if already exists
-
-Examples:
-  pkt packs list
-  pkt packs install https://github.com/user/decentral-compute-pack.git
-  pkt packs install ./my-pack-directory
-  pkt packs show decentral-compute-adoption
-  pkt packs create ./my-new-pack awesome-pack --title "Awesome Pack"
-  pkt packs uninstall old-pack
-
-Pack Structure:
-  my-pack/
-  ├── pack.json         # Pack metadata and configuration
-  ├── prompts/          # Prompt files (.md with YAML frontmatter)
-  ├── templates/        # Template files (.md with YAML frontmatter)
-  └── README.md         # Documentation`)
-
-	default:
+	text, ok := help.Topic(command)
+	if !ok {
 		fmt.Printf("No help available for command: %s\n", command)
+		return nil
 	}
-
+	fmt.Print(text)
 	return nil
 }
 
@@ -1976,12 +6959,16 @@ func (c *CLI) handlePacks(args []string) error {
 		return c.installPack(subArgs)
 	case "uninstall", "remove", "rm":
 		return c.uninstallPack(subArgs)
+	case "update":
+		return c.updatePack(subArgs)
 	case "info", "show":
 		return c.showPack(subArgs)
 	case "create", "new":
 		return c.createPack(subArgs)
 	case "refresh":
 		return c.refreshPacks(subArgs)
+	case "diff":
+		return c.diffPack(subArgs)
 	default:
 		return fmt.Errorf("unknown packs subcommand: %s", subcommand)
 	}
@@ -2009,6 +6996,8 @@ func (c *CLI) listPacks(args []string) error {
 		}
 	}
 
+	format = c.outputFormat(format, "json")
+
 	var packs []config.Pack
 	var err error
 
@@ -2049,7 +7038,7 @@ func (c *CLI) listPacks(args []string) error {
 		}
 		if verbose {
 			fmt.Printf("   Path: %s\n", pack.Path)
-			fmt.Printf("   Installed: %s\n", pack.InstallTime.Format("2006-01-02 15:04:05"))
+			fmt.Printf("   Installed: %s\n", i18n.FormatDateTime(pack.InstallTime))
 			if pack.InstallURL != "" {
 				fmt.Printf("   Source: %s\n", pack.InstallURL)
 			}
@@ -2061,7 +7050,7 @@ func (c *CLI) listPacks(args []string) error {
 					fmt.Printf("   Git Status: ✓ Owner (sync disabled)\n")
 				}
 				if pack.LastSync != nil {
-					fmt.Printf("   Last Sync: %s\n", pack.LastSync.Format("2006-01-02 15:04:05"))
+					fmt.Printf("   Last Sync: %s\n", i18n.FormatDateTime(*pack.LastSync))
 				}
 			} else {
 				fmt.Printf("   Git Status: ✗ Read-only (local changes only)\n")
@@ -2107,16 +7096,7 @@ func (c *CLI) installPack(args []string) error {
 		}
 	}
 
-	var err error
-	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") || strings.HasSuffix(source, ".git") {
-		// Install from Git URL
-		err = c.service.InstallPackFromGit(source, options)
-	} else {
-		// Install from local directory
-		err = c.service.InstallPackFromDirectory(source, options)
-	}
-
-	if err != nil {
+	if _, err := c.service.InstallPack(source, options); err != nil {
 		return fmt.Errorf("failed to install pack: %w", err)
 	}
 
@@ -2141,6 +7121,21 @@ func (c *CLI) uninstallPack(args []string) error {
 	return nil
 }
 
+// updatePack pulls the latest version of a pack installed from Git
+func (c *CLI) updatePack(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("update requires pack name: packs update <name>")
+	}
+
+	pack, err := c.service.UpdatePack(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to update pack: %w", err)
+	}
+
+	fmt.Printf("Pack '%s' updated to version %s\n", pack.Name, pack.Version)
+	return nil
+}
+
 // showPack shows information about a specific pack
 func (c *CLI) showPack(args []string) error {
 	if len(args) == 0 {
@@ -2162,6 +7157,8 @@ func (c *CLI) showPack(args []string) error {
 		}
 	}
 
+	format = c.outputFormat(format, "json")
+
 	pack, err := c.service.GetPack(name)
 	if err != nil {
 		return fmt.Errorf("failed to get pack: %w", err)
@@ -2192,7 +7189,7 @@ func (c *CLI) showPack(args []string) error {
 	if len(pack.Tags) > 0 {
 		fmt.Printf("Tags: %s\n", strings.Join(pack.Tags, ", "))
 	}
-	fmt.Printf("Installed: %s\n", pack.InstallTime.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Installed: %s\n", i18n.FormatDateTime(pack.InstallTime))
 	if pack.InstallURL != "" {
 		fmt.Printf("Source: %s\n", pack.InstallURL)
 	}
@@ -2206,7 +7203,7 @@ func (c *CLI) showPack(args []string) error {
 			fmt.Printf("Git Status: ✓ Owner (sync disabled)\n")
 		}
 		if pack.LastSync != nil {
-			fmt.Printf("Last Sync: %s\n", pack.LastSync.Format("2006-01-02 15:04:05"))
+			fmt.Printf("Last Sync: %s\n", i18n.FormatDateTime(*pack.LastSync))
 		} else {
 			fmt.Printf("Last Sync: Never\n")
 		}
@@ -2228,6 +7225,13 @@ func (c *CLI) showPack(args []string) error {
 		}
 	}
 
+	if len(pack.Searches) > 0 {
+		fmt.Printf("\nSaved Searches (%d):\n", len(pack.Searches))
+		for _, search := range pack.Searches {
+			fmt.Printf("  - %s/%s: %s\n", pack.Name, search.Name, strings.TrimSpace(search.Expression+" "+search.TextQuery))
+		}
+	}
+
 	return nil
 }
 
@@ -2293,6 +7297,92 @@ func (c *CLI) refreshPacks(args []string) error {
 	return nil
 }
 
+// diffPack shows how a pack's prompts were customized since the upstream
+// version it was installed at
+func (c *CLI) diffPack(args []string) error {
+	var name, format string
+	var remote, stat bool
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--remote", "-r":
+			remote = true
+		case "--stat":
+			stat = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown flag: %s", arg)
+			}
+			if name != "" {
+				return fmt.Errorf("diff takes one pack name: packs diff <name>")
+			}
+			name = arg
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("diff requires pack name: packs diff <name>")
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported diff format %q (expected text or json)", format)
+	}
+
+	result, err := c.service.DiffPack(name, remote)
+	if err != nil {
+		return fmt.Errorf("failed to diff pack: %w", err)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	fmt.Printf("Pack '%s' compared with upstream %s", result.Pack, shortCommit(result.Installed))
+	if result.Latest != "" {
+		fmt.Printf(" (latest %s)", shortCommit(result.Latest))
+	}
+	fmt.Println()
+
+	if len(result.Prompts) == 0 {
+		fmt.Println("\nNo local changes to prompts")
+	}
+	if stat {
+		c.printPackStat(result.Prompts)
+	} else {
+		for _, prompt := range result.Prompts {
+			fmt.Printf("\n%s %s (%s)\n", prompt.Status, prompt.Path, prompt.ID)
+			c.printChangedLines(prompt.Diff)
+			switch {
+			case prompt.Remote == "unchanged":
+				fmt.Println("  Upstream hasn't changed it since, so an update keeps this version")
+			case prompt.Remote != "":
+				fmt.Printf("  Upstream has %s it since; compared with the latest version:\n", prompt.Remote)
+				c.printChangedLines(prompt.RemoteDiff)
+			}
+		}
+	}
+
+	if len(result.Incoming) > 0 {
+		fmt.Printf("\nAn update would also change %d prompt file(s):\n", len(result.Incoming))
+		for _, change := range result.Incoming {
+			fmt.Printf("  %s %s\n", change.Status, change.Path)
+		}
+	}
+	return nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
 // packsUsage prints usage for pack commands
 func (c *CLI) packsUsage() error {
 	fmt.Println(`packs - Pack management
@@ -2303,9 +7393,11 @@ Subcommands:
   list, ls              List all installed packs
   install <url|path>    Install a pack from Git URL or directory
   uninstall <name>      Uninstall a pack
+  update <name>         Pull the latest version of a pack installed from Git
   info, show <name>     Show detailed pack information
   create <dir> <name>   Create a new pack scaffold
   refresh               Refresh pack metadata
+  diff <name>           Show how you've customized a pack installed from Git
 
 Flags:
   --format json         Output in JSON format
@@ -2314,6 +7406,8 @@ Flags:
   --name <name>         Override pack name when installing
   --branch <branch>     Install from specific Git branch
   --force               Force reinstall if already exists
+  --remote, -r          With diff, also compare with the latest remote version
+  --stat                With diff, list changed files with counts of changed lines
 
 Examples:
   pkt packs list
@@ -2321,6 +7415,8 @@ Examples:
   pkt packs install ./my-pack-directory
   pkt packs show decentral-compute-adoption
   pkt packs create ./my-new-pack awesome-pack --title "Awesome Pack"
+  pkt packs diff decentral-compute-adoption --remote
+  pkt packs update decentral-compute-adoption
   pkt packs uninstall old-pack`)
 
 	return nil
@@ -2354,8 +7450,18 @@ func (c *CLI) parseListArgs(args []string) map[string]interface{} {
 			if i+1 < len(args) {
 				params["pack"] = args[i+1]
 			}
+		case "--lang":
+			if i+1 < len(args) {
+				params["lang"] = args[i+1]
+			}
+		case "--sort":
+			if i+1 < len(args) {
+				params["sort"] = args[i+1]
+			}
 		case "--archived", "-a":
 			params["archived"] = true
+		case "--all":
+			// Lists everything, skipping any pinned search
 		}
 	}
 	
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+	"github.com/dpshade/pocket-prompt/internal/errors"
+)
+
+// colorDisabled is set by --no-color
+var colorDisabled bool
+
+// DisableColor turns off colored output, as --no-color does
+func DisableColor() {
+	colorDisabled = true
+}
+
+// colorEnabled reports whether output to f is colored: f must be a terminal,
+// and neither --no-color, NO_COLOR nor TERM=dumb turns color off
+func colorEnabled(f *os.File) bool {
+	if colorDisabled || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// palette colors text for one output stream. Without color every method
+// returns its text unchanged, so handlers use it unconditionally.
+type palette struct {
+	renderer *lipgloss.Renderer // nil when color is off
+}
+
+// newPalette returns a palette for f, colored only when colorEnabled(f)
+func newPalette(f *os.File) palette {
+	if !colorEnabled(f) {
+		return palette{}
+	}
+	return palette{renderer: lipgloss.NewRenderer(f)}
+}
+
+func (p palette) render(style func(lipgloss.Style) lipgloss.Style, text string) string {
+	if p.renderer == nil || text == "" {
+		return text
+	}
+	return style(p.renderer.NewStyle()).Render(text)
+}
+
+// header styles column headers and field labels
+func (p palette) header(text string) string {
+	return p.render(func(s lipgloss.Style) lipgloss.Style { return s.Bold(true) }, text)
+}
+
+// id styles prompt IDs in lists
+func (p palette) id(text string) string {
+	return p.render(func(s lipgloss.Style) lipgloss.Style { return s.Bold(true).Foreground(lipgloss.Color("4")) }, text)
+}
+
+// tag styles tags
+func (p palette) tag(text string) string {
+	return p.render(func(s lipgloss.Style) lipgloss.Style { return s.Foreground(lipgloss.Color("6")) }, text)
+}
+
+// muted styles secondary text such as summaries and result counts
+func (p palette) muted(text string) string {
+	return p.render(func(s lipgloss.Style) lipgloss.Style { return s.Faint(true) }, text)
+}
+
+// severity colors text by an error, lint or CI severity: red for errors,
+// yellow for warnings and blue for information
+func (p palette) severity(severity, text string) string {
+	color := lipgloss.Color("1")
+	switch severity {
+	case string(errors.SeverityWarning):
+		color = lipgloss.Color("3")
+	case string(errors.SeverityInfo):
+		color = lipgloss.Color("4")
+	}
+	return p.render(func(s lipgloss.Style) lipgloss.Style { return s.Bold(true).Foreground(color) }, text)
+}
+
+// tags joins tags with sep, coloring each
+func (p palette) tags(tags []string, sep string) string {
+	var out string
+	for i, tag := range tags {
+		if i > 0 {
+			out += sep
+		}
+		out += p.tag(tag)
+	}
+	return out
+}
+
+// warnf prints a warning on stderr
+func warnf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", newPalette(os.Stderr).severity(string(errors.SeverityWarning), "Warning:"), fmt.Sprintf(format, args...))
+}
+
+// PrintError prints an error returned by ExecuteCommand on stderr, colored by
+// its severity
+func PrintError(err error) {
+	severity := string(errors.SeverityError)
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) && appErr.Severity != "" {
+		severity = string(appErr.Severity)
+	}
+	fmt.Fprintf(os.Stderr, "%s %v\n", newPalette(os.Stderr).severity(severity, "Error:"), err)
+}
+
+// diffStyle colors a diff: added lines green, removed lines red and notes
+// bold, with the words that changed within a line in reverse video
+func (p palette) diffStyle() diff.Style {
+	color := func(c string, reverse bool) func(string) string {
+		return func(text string) string {
+			return p.render(func(s lipgloss.Style) lipgloss.Style {
+				return s.Foreground(lipgloss.Color(c)).Reverse(reverse)
+			}, text)
+		}
+	}
+	return diff.Style{
+		Added:       color("2", false),
+		Removed:     color("1", false),
+		AddedWord:   color("2", true),
+		RemovedWord: color("1", true),
+		Note:        p.header,
+		Gap:         func(int) string { return p.muted("...") },
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// statWidth is the most pluses and minuses a --stat line draws
+const statWidth = 40
+
+// handleDiff compares two versions of a prompt, by default the latest with
+// the one before it
+func (c *CLI) handleDiff(args []string) error {
+	var id, format string
+	var versions []string
+	stat := false
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--stat":
+			stat = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown diff option: %s", arg)
+			}
+			if id == "" {
+				id = arg
+			} else {
+				versions = append(versions, arg)
+			}
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("diff requires a prompt ID: diff <id> [from] [to]")
+	}
+	if len(versions) > 2 {
+		return fmt.Errorf("diff compares two versions: diff <id> [from] [to]")
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported diff format %q (expected text or json)", format)
+	}
+
+	var from, to string
+	switch len(versions) {
+	case 1:
+		from = versions[0]
+	case 2:
+		from, to = versions[0], versions[1]
+	}
+	result, err := c.service.DiffVersions(id, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to diff versions: %w", err)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	title := fmt.Sprintf("%s v%s → v%s", c.out.id(result.ID), result.From, result.To)
+	if stat {
+		fmt.Println(c.statLine(title, 0, result.Added, result.Removed))
+		for _, note := range result.Notes {
+			fmt.Printf("  %s\n", note)
+		}
+		return nil
+	}
+
+	fmt.Println(title)
+	for _, note := range result.Notes {
+		fmt.Printf("  %s\n", c.out.header(note))
+	}
+	if result.Added == 0 && result.Removed == 0 {
+		fmt.Println(c.out.muted("  Content unchanged"))
+		return nil
+	}
+	c.printChangedLines(result.Diff)
+	return nil
+}
+
+// printChangedLines prints the added and removed lines of a diff with a
+// couple of unchanged lines around each, eliding the rest. The words that
+// changed within a line are highlighted when output is colored.
+func (c *CLI) printChangedLines(lines []string) {
+	trimmed := diff.Trim(diff.Annotate(lines), 2)
+	// Unchanged lines after the last change are left out without a gap
+	if n := len(trimmed); n > 0 && trimmed[n-1].Skipped > 0 {
+		trimmed = trimmed[:n-1]
+	}
+	for _, line := range diff.Render(trimmed, c.out.diffStyle()) {
+		fmt.Printf("    %s\n", line)
+	}
+}
+
+// statLine summarizes a diff git-style, as name, the number of lines
+// changed and a bar of pluses and minuses. The name is padded to width
+// printed characters so the bars of several lines align.
+func (c *CLI) statLine(name string, width, added, removed int) string {
+	plus, minus := diff.Bar(added, removed, statWidth)
+	padding := strings.Repeat(" ", max(0, width-lipgloss.Width(name)))
+	return fmt.Sprintf("%s%s | %d %s%s", name, padding, added+removed,
+		c.out.diffStyle().Added(plus), c.out.diffStyle().Removed(minus))
+}
+
+// printPackStat lists a pack's changed prompt files with a --stat line each,
+// noting those upstream has changed since
+func (c *CLI) printPackStat(prompts []service.PackPromptDiff) {
+	if len(prompts) == 0 {
+		return
+	}
+	names := make([]string, len(prompts))
+	width := 0
+	for i, prompt := range prompts {
+		names[i] = prompt.Status + " " + prompt.Path
+		width = max(width, len(names[i]))
+	}
+	fmt.Println()
+	for i, prompt := range prompts {
+		added, removed := diff.Count(prompt.Diff)
+		line := "  " + c.statLine(names[i], width, added, removed)
+		if prompt.Remote != "" && prompt.Remote != "unchanged" {
+			line += c.out.muted(fmt.Sprintf(" (upstream %s it since)", prompt.Remote))
+		}
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/ui"
+)
+
+// handleMerge resolves the prompt files git left conflicted by a merge or
+// pull, one at a time in the merge view, or lists them
+func (c *CLI) handleMerge(args []string) error {
+	list := false
+	var targets []string
+	for _, arg := range args {
+		switch arg {
+		case "--list", "-l":
+			list = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown merge option: %s", arg)
+			}
+			targets = append(targets, arg)
+		}
+	}
+
+	conflicts, err := c.service.PromptConflicts()
+	if err != nil {
+		return fmt.Errorf("failed to list conflicts: %w", err)
+	}
+	if len(targets) > 0 {
+		conflicts = filterConflicts(conflicts, targets)
+	}
+	if len(conflicts) == 0 {
+		fmt.Println("No conflicted prompts")
+		return nil
+	}
+
+	if list {
+		for _, conflict := range conflicts {
+			fmt.Printf("%s  %s  v%s ↔ v%s\n", c.out.id(conflict.Local.ID), conflict.Path,
+				conflict.Local.Version, conflict.Incoming.Version)
+		}
+		return nil
+	}
+
+	for _, conflict := range conflicts {
+		merged, ok, err := ui.RunMergeView(conflict.Local.ID,
+			"Local v"+conflict.Local.Version, "Incoming v"+conflict.Incoming.Version,
+			conflict.Local.Content, conflict.Incoming.Content)
+		if err != nil {
+			return fmt.Errorf("merge view failed: %w", err)
+		}
+		if !ok {
+			fmt.Printf("Merge of %s cancelled\n", conflict.Local.ID)
+			return nil
+		}
+		resolved, err := c.service.ResolvePromptConflict(conflict, merged)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", conflict.Path, err)
+		}
+		fmt.Printf("Resolved %s as v%s\n", resolved.ID, resolved.Version)
+	}
+
+	if c.service.MergeInProgress() {
+		fmt.Println("Commit the merge with 'git commit' once every conflicted file is resolved")
+	}
+	return nil
+}
+
+// filterConflicts keeps the conflicts of the prompts named by ID or path
+func filterConflicts(conflicts []service.PromptConflict, targets []string) []service.PromptConflict {
+	var kept []service.PromptConflict
+	for _, conflict := range conflicts {
+		for _, target := range targets {
+			if conflict.Local.ID == target || conflict.Path == filepath.Clean(target) {
+				kept = append(kept, conflict)
+				break
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/dpshade/pocket-prompt/internal/clipboard"
+	"github.com/dpshade/pocket-prompt/internal/git"
+)
+
+// platformTools are the programs pkt runs when they are installed, with what
+// each is for
+var platformTools = []struct{ name, use string }{
+	{"git", "sync, history and review branches"},
+	{"git-lfs", "attachments tracked with Git LFS"},
+	{"gh", "pull requests from 'pkt git pr'"},
+	{"pgrep", "stopping a running URL server"},
+	{"lsof", "naming what holds a taken port"},
+}
+
+// platformReport is what 'pkt doctor --platform' reports
+type platformReport struct {
+	OS        string          `json:"os"`
+	Arch      string          `json:"arch"`
+	Clipboard platformBackend `json:"clipboard"`
+	Git       platformBackend `json:"git"`
+	Tools     []platformTool  `json:"tools"`
+}
+
+// platformBackend is a configured backend and what it resolved to here
+type platformBackend struct {
+	Backend  string `json:"backend"`
+	Resolved string `json:"resolved"`
+}
+
+// platformTool is a program pkt uses and where it was found, if it was
+type platformTool struct {
+	Name string `json:"name"`
+	Use  string `json:"use"`
+	Path string `json:"path,omitempty"`
+}
+
+// doctorPlatform reports the system pkt runs on: the clipboard and git
+// backends in use and which of the programs it runs are installed
+func (c *CLI) doctorPlatform(format string) error {
+	settings := c.service.Settings().Platform
+	report := platformReport{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Clipboard: platformBackend{Backend: settings.ClipboardBackend(), Resolved: clipboard.Resolved()},
+		Git:       platformBackend{Backend: settings.Git, Resolved: "built in"},
+	}
+	if report.Git.Backend == "" {
+		report.Git.Backend = "auto"
+	}
+	for _, tool := range platformTools {
+		path, _ := exec.LookPath(tool.name)
+		report.Tools = append(report.Tools, platformTool{Name: tool.name, Use: tool.use, Path: path})
+	}
+	if git.Available() && !settings.BuiltinGit() {
+		path, _ := exec.LookPath("git")
+		report.Git.Resolved = path
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	fmt.Printf("System:    %s/%s\n", report.OS, report.Arch)
+	fmt.Printf("Clipboard: %s  %s\n", report.Clipboard.Resolved, c.out.muted("backend "+report.Clipboard.Backend))
+	fmt.Printf("Git:       %s  %s\n", report.Git.Resolved, c.out.muted("backend "+report.Git.Backend))
+	fmt.Println()
+	fmt.Println(c.out.header("Programs pkt runs:"))
+	for _, tool := range report.Tools {
+		path := tool.Path
+		if path == "" {
+			path = c.out.muted("not installed")
+		}
+		fmt.Printf("  %-8s %s  %s\n", tool.Name, path, c.out.muted(tool.Use))
+	}
+	return nil
+}
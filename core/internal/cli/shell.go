@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+// shellPrompt is printed before each command the shell reads
+const shellPrompt = "pkt> "
+
+// shellHistorySize is how many commands the shell keeps between sessions,
+// which is as many as the line editor can step back through
+const shellHistorySize = 100
+
+// handleShell runs commands read one line at a time against the library that
+// is already loaded, so repeated searches skip starting up and reading the
+// library again. Commands on one line may be separated by ';', which always
+// runs the next one, or '&&', which runs it only if the last one succeeded.
+func (c *CLI) handleShell(args []string) error {
+	if c.inShell {
+		return fmt.Errorf("already in the shell")
+	}
+	c.inShell = true
+	defer func() { c.inShell = false }()
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// Commands piped in run as a script, without prompts or history
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if !c.runShellLine(scanner.Text()) {
+				return nil
+			}
+		}
+		return scanner.Err()
+	}
+
+	history := loadShellHistory()
+	screen := &shellScreen{}
+	t := term.NewTerminal(screen, "")
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		t.SetSize(width, height)
+	}
+	// The line editor only takes history as lines it has read, so the saved
+	// history is read through it with its echo discarded
+	if len(history) > 0 {
+		screen.preload = strings.NewReader(strings.Join(history, "\r") + "\r")
+		for range history {
+			if _, err := t.ReadLine(); err != nil {
+				break
+			}
+		}
+		screen.preload = nil
+	}
+	screen.ready = true
+	t.SetPrompt(shellPrompt)
+	t.AutoCompleteCallback = c.completeShell(t)
+
+	fmt.Println("Type a command without 'pkt', 'help' for commands, or 'exit' to leave.")
+	for {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to read from terminal: %w", err)
+		}
+		line, err := t.ReadLine()
+		term.Restore(fd, state)
+		if err == io.EOF {
+			fmt.Println()
+			break
+		}
+		if err != nil && err != term.ErrPasteIndicator {
+			return fmt.Errorf("failed to read from terminal: %w", err)
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		history = append(history, line)
+		if !c.runShellLine(line) {
+			break
+		}
+	}
+	saveShellHistory(history)
+	return nil
+}
+
+// runShellLine runs the commands on one line of shell input, printing their
+// errors rather than stopping. It returns false once the shell should end.
+func (c *CLI) runShellLine(line string) bool {
+	args, err := config.SplitArgs(line)
+	if err != nil {
+		PrintError(err)
+		return true
+	}
+
+	var failed bool
+	for len(args) > 0 {
+		command, sep := args, ""
+		for i, arg := range args {
+			if arg == ";" || arg == "&&" {
+				command, sep = args[:i], arg
+				break
+			}
+		}
+		args = args[len(command):]
+		if sep != "" {
+			args = args[1:]
+		}
+		if len(command) == 0 {
+			continue
+		}
+
+		switch command[0] {
+		case "exit", "quit":
+			return false
+		case "pkt":
+			// Lines copied from a terminal or the docs often keep it
+			command = command[1:]
+			if len(command) == 0 {
+				continue
+			}
+		}
+		err := c.ExecuteCommand(command)
+		// --force-protected lasts for the one command it was given to
+		c.service.SetForceProtected(false)
+		failed = err != nil
+		if failed {
+			PrintError(err)
+		}
+		if failed && sep == "&&" {
+			// Skip to the next command after a ';'
+			for len(args) > 0 && args[0] != ";" {
+				args = args[1:]
+			}
+		}
+	}
+	return true
+}
+
+// completeShell completes the word before the cursor when Tab is pressed:
+// commands and aliases at the start of a command, tags after tag: or --tag,
+// and prompt IDs anywhere else. When several completions remain they are
+// listed above the prompt.
+func (c *CLI) completeShell(t *term.Terminal) func(line string, pos int, key rune) (string, int, bool) {
+	return func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		before := line[:pos]
+		start := strings.LastIndexAny(before, " \t") + 1
+		word := before[start:]
+		fields := strings.Fields(before[:start])
+
+		prefix := ""
+		var candidates []string
+		switch {
+		case len(fields) == 0 || fields[len(fields)-1] == ";" || fields[len(fields)-1] == "&&":
+			candidates = c.shellCommands()
+		case strings.HasPrefix(word, "tag:") || strings.HasPrefix(word, "-tag:"):
+			prefix = word[:strings.Index(word, ":")+1]
+			candidates, _ = c.service.GetAllTags()
+		case fields[len(fields)-1] == "--tag" || fields[len(fields)-1] == "-t" || fields[len(fields)-1] == "--tags":
+			candidates, _ = c.service.GetAllTags()
+		default:
+			prompts, _ := c.service.ListPrompts()
+			for _, p := range prompts {
+				candidates = append(candidates, p.ID)
+			}
+		}
+
+		var matches []string
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate, word[len(prefix):]) {
+				matches = append(matches, candidate)
+			}
+		}
+		if len(matches) == 0 {
+			return "", 0, false
+		}
+		sort.Strings(matches)
+
+		completion := prefix + commonPrefix(matches)
+		if len(matches) == 1 {
+			completion += " "
+		} else if completion == word {
+			// Nothing more to fill in, so show what it could be
+			fmt.Fprintln(t, strings.Join(matches, "  "))
+			return "", 0, false
+		}
+		newLine := before[:start] + completion + line[pos:]
+		return newLine, start + len(completion), true
+	}
+}
+
+// shellCommands returns the commands and aliases the shell completes
+func (c *CLI) shellCommands() []string {
+	commands := []string{"exit", "quit"}
+	for name := range builtinCommands {
+		commands = append(commands, name)
+	}
+	for name := range c.defaults().Aliases {
+		commands = append(commands, name)
+	}
+	return commands
+}
+
+// commonPrefix returns the longest prefix all of words share
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// shellScreen connects the line editor to the terminal. Until it is ready
+// the editor reads the saved history instead and what it prints is dropped.
+type shellScreen struct {
+	preload io.Reader
+	ready   bool
+}
+
+func (s *shellScreen) Read(p []byte) (int, error) {
+	if s.preload != nil {
+		return s.preload.Read(p)
+	}
+	return os.Stdin.Read(p)
+}
+
+func (s *shellScreen) Write(p []byte) (int, error) {
+	if !s.ready {
+		return len(p), nil
+	}
+	return os.Stdout.Write(p)
+}
+
+// shellHistoryPath returns the file shell history is kept in. It is in the
+// user's cache directory rather than the library, so it is never synced.
+func shellHistoryPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "pocket-prompt", "shell_history"), nil
+}
+
+// loadShellHistory returns the commands from earlier sessions, oldest first
+func loadShellHistory() []string {
+	path, err := shellHistoryPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var history []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			history = append(history, line)
+		}
+	}
+	if len(history) > shellHistorySize {
+		history = history[len(history)-shellHistorySize:]
+	}
+	return history
+}
+
+// saveShellHistory keeps the most recent commands for the next session. The
+// shell still works when they cannot be saved, so failures are only warned of.
+func saveShellHistory(history []string) {
+	if len(history) > shellHistorySize {
+		history = history[len(history)-shellHistorySize:]
+	}
+	path, err := shellHistoryPath()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0600)
+	}
+	if err != nil {
+		warnf("could not save shell history: %v", err)
+	}
+}
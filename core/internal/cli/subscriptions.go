@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// newMatches lists the prompts that started matching a subscribed search
+// since it was last acknowledged, or those of every subscribed search
+// without a name, acknowledging them with --ack
+func (c *CLI) newMatches(args []string) error {
+	var name, format string
+	ack := false
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--ack":
+			ack = true
+		case "--format", "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown search-saved new option: %s", arg)
+			}
+			name = arg
+		}
+	}
+	format = c.outputFormat(format, "json")
+	if format != "" && format != "text" && format != "json" {
+		return fmt.Errorf("unsupported format %q (expected text or json)", format)
+	}
+
+	var searches []service.SearchMatches
+	if name != "" {
+		matches, err := c.service.NewMatches(name)
+		if err != nil {
+			return err
+		}
+		searches = []service.SearchMatches{*matches}
+	} else {
+		all, err := c.service.AllNewMatches()
+		if err != nil {
+			return fmt.Errorf("failed to list new matches: %w", err)
+		}
+		searches = all
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(searches); err != nil {
+			return err
+		}
+	} else {
+		c.printNewMatches(name, searches)
+	}
+
+	if ack {
+		for _, search := range searches {
+			if err := c.service.AcknowledgeMatches(search.Search); err != nil {
+				return fmt.Errorf("failed to acknowledge new matches: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// printNewMatches prints the new matches of each search under its name
+func (c *CLI) printNewMatches(name string, searches []service.SearchMatches) {
+	if len(searches) == 0 || (name != "" && len(searches[0].Matches) == 0) {
+		if name != "" {
+			fmt.Printf("No new matches in %s since %s\n", name, searches[0].Since.Format("2 Jan 2006 15:04"))
+		} else {
+			fmt.Println("No new matches in subscribed searches")
+		}
+		return
+	}
+	for i, search := range searches {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(c.out.header(fmt.Sprintf("%s: %d new since %s", search.Search,
+			len(search.Matches), search.Since.Format("2 Jan 2006 15:04"))))
+		for _, match := range search.Matches {
+			fmt.Printf("  %s  %s  %s\n", c.out.id(match.Prompt.ID), match.Prompt.Title(),
+				c.out.muted(fmt.Sprintf("(%s, %s)", match.Source, match.At.Format("2 Jan 15:04"))))
+		}
+	}
+}
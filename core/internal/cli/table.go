@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// tableColumns lists the columns --columns accepts, in their default order
+var tableColumns = []string{"id", "title", "description", "tags", "pack", "version", "updated", "tokens"}
+
+// columnHeaders names each column in the header row
+var columnHeaders = map[string]string{
+	"id":          "ID",
+	"title":       "Title",
+	"description": "Description",
+	"tags":        "Tags",
+	"pack":        "Pack",
+	"version":     "Version",
+	"updated":     "Updated",
+	"tokens":      "Tokens",
+}
+
+// defaultTableColumns are shown when --columns is not given
+var defaultTableColumns = []string{"id", "title", "version", "updated"}
+
+// flexibleColumns give up width, widest first, when a table is too wide for
+// the terminal; the others are never truncated
+var flexibleColumns = []string{"title", "description", "tags"}
+
+// minColumnWidth is as narrow as truncation makes a flexible column
+const minColumnWidth = 10
+
+// tableOptions controls --format table output for prompt lists
+type tableOptions struct {
+	columns    []string
+	noTruncate bool
+}
+
+// extractTableFlags removes --columns and --no-truncate from args, which any
+// command that lists prompts accepts
+func extractTableFlags(args []string) ([]string, tableOptions, error) {
+	var opts tableOptions
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--no-truncate":
+			opts.noTruncate = true
+		case args[i] == "--columns":
+			if i+1 >= len(args) {
+				return nil, opts, fmt.Errorf("--columns requires a list such as id,title,tags")
+			}
+			columns, err := parseColumns(args[i+1])
+			if err != nil {
+				return nil, opts, err
+			}
+			opts.columns = columns
+			i++
+		case strings.HasPrefix(args[i], "--columns="):
+			columns, err := parseColumns(strings.TrimPrefix(args[i], "--columns="))
+			if err != nil {
+				return nil, opts, err
+			}
+			opts.columns = columns
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, opts, nil
+}
+
+func parseColumns(list string) ([]string, error) {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column == "" {
+			continue
+		}
+		if !slices.Contains(tableColumns, column) {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", column, strings.Join(tableColumns, ", "))
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("--columns requires a list such as id,title,tags")
+	}
+	return columns, nil
+}
+
+// printTable writes prompts as aligned columns. Columns are as wide as their
+// widest value; when that is wider than the terminal, titles, descriptions
+// and tags are cut to fit unless noTruncate is set. Output that is not a
+// terminal is never cut.
+func (c *CLI) printTable(out io.Writer, prompts []*models.Prompt) {
+	columns := c.table.columns
+	if len(columns) == 0 {
+		columns = defaultTableColumns
+	}
+
+	rows := make([][]string, 0, len(prompts)+1)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = columnHeaders[column]
+	}
+	rows = append(rows, header)
+	for _, p := range prompts {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			// Keep a placeholder in empty cells so awk sees every field
+			if row[i] = c.tableValue(p, column); row[i] == "" {
+				row[i] = "-"
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, value := range row {
+			widths[i] = max(widths[i], lipgloss.Width(value))
+		}
+	}
+	if !c.table.noTruncate {
+		if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+			fitWidths(columns, widths, width)
+		}
+	}
+
+	for r, row := range rows {
+		var line strings.Builder
+		for i, value := range row {
+			value = truncate(value, widths[i])
+			padding := widths[i] - lipgloss.Width(value) + 2
+			switch {
+			case r == 0:
+				value = c.out.header(value)
+			case columns[i] == "id":
+				value = c.out.id(value)
+			case columns[i] == "tags" && value != "-":
+				value = c.out.tag(value)
+			}
+			line.WriteString(value)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", padding))
+			}
+		}
+		fmt.Fprintln(out, line.String())
+		if r == 0 {
+			total := 0
+			for _, w := range widths {
+				total += w + 2
+			}
+			fmt.Fprintln(out, strings.Repeat("-", total-2))
+		}
+	}
+}
+
+// tableValue returns the text of one column for a prompt
+func (c *CLI) tableValue(p *models.Prompt, column string) string {
+	switch column {
+	case "id":
+		return p.ID
+	case "title":
+		return oneLine(p.Name)
+	case "description":
+		return oneLine(p.Summary)
+	case "tags":
+		return strings.Join(p.Tags, ",")
+	case "pack":
+		if pack := storage.PackFromPath(p.FilePath); pack != "" {
+			return pack
+		}
+		if p.Pack != "" {
+			return p.Pack
+		}
+		return "personal"
+	case "version":
+		return p.Version
+	case "updated":
+		return i18n.FormatDate(p.UpdatedAt)
+	case "tokens":
+		if c.service == nil {
+			return strconv.Itoa(service.EstimateTokens(p.Content))
+		}
+		return strconv.Itoa(c.service.PromptTokens(p))
+	}
+	return ""
+}
+
+// fitWidths narrows the flexible columns, widest first, until the table fits
+// in width or they reach minColumnWidth
+func fitWidths(columns []string, widths []int, width int) {
+	total := 0
+	for _, w := range widths {
+		total += w + 2
+	}
+	total -= 2
+
+	for total > width {
+		widest := -1
+		for i, column := range columns {
+			if slices.Contains(flexibleColumns, column) && widths[i] > minColumnWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// truncate cuts value to width display cells, ending it with "..."
+func truncate(value string, width int) string {
+	if lipgloss.Width(value) <= width {
+		return value
+	}
+	var out strings.Builder
+	used := 0
+	for _, r := range value {
+		w := lipgloss.Width(string(r))
+		if used+w > width-3 {
+			break
+		}
+		out.WriteRune(r)
+		used += w
+	}
+	return out.String() + "..."
+}
+
+// oneLine collapses whitespace so a value fits on one table row
+func oneLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,150 @@
+// Package client runs unified commands against a running pocket-prompt API
+// server instead of the library on disk, over TCP or a Unix domain socket.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// Environment variables read by the CLI when --remote is not given
+const (
+	RemoteEnv = "POCKET_PROMPT_REMOTE"
+	APIKeyEnv = "POCKET_PROMPT_API_KEY"
+)
+
+const unixPrefix = "unix:"
+
+// Client sends commands to the server's /api/v1/commands endpoint
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// New creates a client for addr, which is "unix:/path/to/socket", an http(s)
+// URL, or a bare "host:port"
+func New(addr, apiKey string) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	baseURL := strings.TrimRight(addr, "/")
+
+	switch {
+	case strings.HasPrefix(addr, unixPrefix):
+		path := strings.TrimPrefix(addr, unixPrefix)
+		if path == "" {
+			return nil, fmt.Errorf("unix address requires a socket path")
+		}
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+		// The host is ignored by the dialer but must be present in the URL
+		baseURL = "http://pocket-prompt"
+	case strings.HasPrefix(addr, "http://"), strings.HasPrefix(addr, "https://"):
+		if _, err := url.Parse(addr); err != nil {
+			return nil, fmt.Errorf("invalid server URL %q: %w", addr, err)
+		}
+	case addr == "":
+		return nil, fmt.Errorf("server address is required")
+	default:
+		baseURL = "http://" + baseURL
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		http:    &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Execute runs a command on the server. Command failures are returned as an
+// unsuccessful result, as CommandExecutor.Execute does; only transport and
+// protocol problems are returned as errors.
+func (c *Client) Execute(ctx context.Context, commandName string, params map[string]interface{}) (*commands.CommandResult, error) {
+	defer tracing.Begin("remote " + commandName)()
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode parameters: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/commands/"+url.PathEscape(commandName), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var failure struct {
+			Error commands.ErrorInfo `json:"error"`
+		}
+		if err := json.Unmarshal(data, &failure); err != nil || failure.Error.Message == "" {
+			return nil, fmt.Errorf("server returned %s", resp.Status)
+		}
+		return &commands.CommandResult{Success: false, Error: &failure.Error}, nil
+	}
+
+	var envelope struct {
+		Data    json.RawMessage `json:"data"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := &commands.CommandResult{Success: true, Message: envelope.Message}
+	if len(envelope.Data) > 0 && string(envelope.Data) != "null" {
+		if result.Data, err = decodeData(commandName, envelope.Data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s result: %w", commandName, err)
+		}
+	}
+	return result, nil
+}
+
+// decodeData restores the Go types the local executor returns for each
+// command, so callers can treat remote and local results alike
+func decodeData(commandName string, raw json.RawMessage) (interface{}, error) {
+	switch commandName {
+	case "list", "search", "boolean-search", "execute-saved-search":
+		var prompts []*models.Prompt
+		err := json.Unmarshal(raw, &prompts)
+		return prompts, err
+	case "get", "create", "update":
+		var prompt models.Prompt
+		err := json.Unmarshal(raw, &prompt)
+		return &prompt, err
+	case "list-tags":
+		var tags []string
+		err := json.Unmarshal(raw, &tags)
+		return tags, err
+	default:
+		var value interface{}
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	}
+}
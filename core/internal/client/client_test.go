@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestExecuteOverUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pkt-client")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "pkt.sock")
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/commands/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"data":[{"ID":"review","Name":"Code Review"}],"message":"Found 1 prompts"}`))
+	})
+	mux.HandleFunc("/api/v1/commands/get", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"prompt not found: nope"}}`))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(lis)
+	defer server.Close()
+
+	c, err := New("unix:"+socket, "")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := c.Execute(context.Background(), "list", nil)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	prompts, ok := result.Data.([]*models.Prompt)
+	if !result.Success || !ok || len(prompts) != 1 || prompts[0].ID != "review" {
+		t.Fatalf("list result = %+v, want one prompt named review", result)
+	}
+
+	result, err = c.Execute(context.Background(), "get", map[string]interface{}{"id": "nope"})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if result.Success || result.Error == nil || result.Error.Code != "NOT_FOUND" {
+		t.Fatalf("get result = %+v, want NOT_FOUND failure", result)
+	}
+}
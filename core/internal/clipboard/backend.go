@@ -0,0 +1,162 @@
+package clipboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	native "github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"golang.org/x/term"
+)
+
+// Clipboard backends, as set with platform.clipboard in the config
+const (
+	BackendAuto    = "auto"    // The set command or a detected utility, else the best fallback
+	BackendCommand = "command" // Only clipboard utilities
+	BackendNative  = "native"  // The system clipboard API, without utilities on Windows
+	BackendOSC52   = "osc52"   // The terminal's clipboard over an OSC 52 escape sequence
+)
+
+// backend is the clipboard backend Copy and Paste use
+var backend = BackendAuto
+
+// SetBackend picks the clipboard backend. An empty name restores auto.
+func SetBackend(name string) {
+	if name == "" {
+		name = BackendAuto
+	}
+	backend = name
+}
+
+// resolve returns the backend Copy uses for the set backend, resolving auto:
+// the set command or a detected utility, the native clipboard on Windows,
+// where it needs neither cmd nor PowerShell, and OSC 52 when there is no
+// utility but there is a terminal, as over SSH or in a container
+func resolve() string {
+	if backend != BackendAuto {
+		return backend
+	}
+	switch {
+	case len(command) > 0:
+		return BackendCommand
+	case runtime.GOOS == "windows":
+		return BackendNative
+	case utilityAvailable():
+		return BackendCommand
+	case terminalOutput() != nil:
+		return BackendOSC52
+	default:
+		return BackendCommand
+	}
+}
+
+// Resolved describes what Copy copies with, such as "xclip" or "osc52
+// (tmux)", or why it cannot copy
+func Resolved() string {
+	switch resolve() {
+	case BackendNative:
+		return "native"
+	case BackendOSC52:
+		if terminalOutput() == nil {
+			return "osc52 (no terminal attached)"
+		}
+		if multiplexer := multiplexer(); multiplexer != "" {
+			return "osc52 (" + multiplexer + ")"
+		}
+		return "osc52"
+	}
+	if len(command) > 0 {
+		return strings.Join(command, " ")
+	}
+	for _, name := range utilities() {
+		if isCommandAvailable(name) {
+			return name
+		}
+	}
+	return "none found"
+}
+
+// utilities lists the clipboard utilities Copy looks for on this system
+func utilities() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"pbcopy"}
+	case "linux":
+		return []string{"xclip", "xsel", "wl-copy"}
+	case "windows":
+		return []string{"clip"}
+	default:
+		return nil
+	}
+}
+
+// utilityAvailable reports whether any of the clipboard utilities is installed
+func utilityAvailable() bool {
+	for _, name := range utilities() {
+		if isCommandAvailable(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyNative copies text through the system clipboard API
+func copyNative(text string) error {
+	if native.Unsupported {
+		return NewClipboardError()
+	}
+	return native.WriteAll(text)
+}
+
+// pasteNative reads the clipboard through the system clipboard API
+func pasteNative() (string, error) {
+	if native.Unsupported {
+		return "", NewClipboardError()
+	}
+	return native.ReadAll()
+}
+
+// copyOSC52 asks the terminal to copy text with an OSC 52 escape sequence,
+// wrapped so tmux and screen pass it on. Terminals that don't support OSC 52
+// ignore it, so a copy that succeeds may still not reach the clipboard.
+func copyOSC52(text string) error {
+	out := terminalOutput()
+	if out == nil {
+		return fmt.Errorf("osc52 clipboard needs a terminal, but none is attached")
+	}
+	seq := osc52.New(text)
+	switch multiplexer() {
+	case "tmux":
+		seq = seq.Tmux()
+	case "screen":
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(out)
+	return err
+}
+
+// terminalOutput returns the terminal an escape sequence can be written to,
+// preferring stderr so it stays out of piped output, or nil
+func terminalOutput() io.Writer {
+	for _, f := range []*os.File{os.Stderr, os.Stdout} {
+		if term.IsTerminal(int(f.Fd())) {
+			return f
+		}
+	}
+	return nil
+}
+
+// multiplexer names the terminal multiplexer pkt runs in, if any
+func multiplexer() string {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return "tmux"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return "screen"
+	default:
+		return ""
+	}
+}
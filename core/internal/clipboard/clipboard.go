@@ -6,6 +6,10 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+
+	native "github.com/atotto/clipboard"
+
+	"github.com/dpshade/pocket-prompt/internal/platform"
 )
 
 // ClipboardError represents an error when no clipboard utility is available
@@ -42,8 +46,28 @@ func NewClipboardError() *ClipboardError {
 	}
 }
 
-// Copy copies text to the system clipboard
+// command, when set, is run in place of the detected clipboard utility
+var command []string
+
+// SetCommand makes Copy pipe text to cmd, such as "wl-copy" or
+// "xclip -selection primary", instead of detecting a clipboard utility.
+// An empty cmd restores detection.
+func SetCommand(cmd string) {
+	command = strings.Fields(cmd)
+}
+
+// Copy copies text to the system clipboard with the backend set by
+// SetBackend
 func Copy(text string) error {
+	switch resolve() {
+	case BackendNative:
+		return copyNative(text)
+	case BackendOSC52:
+		return copyOSC52(text)
+	}
+	if len(command) > 0 {
+		return copyCommand(text)
+	}
 	switch runtime.GOOS {
 	case "darwin":
 		return copyDarwin(text)
@@ -56,6 +80,16 @@ func Copy(text string) error {
 	}
 }
 
+// copyCommand copies text with the command set by SetCommand
+func copyCommand(text string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", command[0], err)
+	}
+	return nil
+}
+
 // copyDarwin copies text to clipboard on macOS
 func copyDarwin(text string) error {
 	cmd := exec.Command("pbcopy")
@@ -116,11 +150,7 @@ func copyWindows(text string) error {
 
 // isCommandAvailable checks if a command is available in PATH
 func isCommandAvailable(name string) bool {
-	cmd := exec.Command("which", name)
-	if err := cmd.Run(); err != nil {
-		return false
-	}
-	return true
+	return platform.HasCommand(name)
 }
 
 // CopyWithFallback attempts to copy to clipboard and returns a message
@@ -141,6 +171,15 @@ func CopyWithFallback(text string) (string, error) {
 
 // IsClipboardAvailable checks if clipboard functionality is available
 func IsClipboardAvailable() bool {
+	switch resolve() {
+	case BackendNative:
+		return !native.Unsupported
+	case BackendOSC52:
+		return terminalOutput() != nil
+	}
+	if len(command) > 0 {
+		return isCommandAvailable(command[0])
+	}
 	switch runtime.GOOS {
 	case "darwin":
 		return isCommandAvailable("pbcopy")
@@ -169,4 +208,77 @@ func GetInstallInstructions() string {
 	default:
 		return fmt.Sprintf("Clipboard not supported on %s", runtime.GOOS)
 	}
-}
\ No newline at end of file
+}
+
+// pasteCommand, when set, is run in place of the detected clipboard utility
+// to read the clipboard
+var pasteCommand []string
+
+// SetPasteCommand makes Paste run cmd, such as "wl-paste --no-newline", and
+// read what it prints instead of detecting a clipboard utility. An empty cmd
+// restores detection.
+func SetPasteCommand(cmd string) {
+	pasteCommand = strings.Fields(cmd)
+}
+
+// Paste returns the text on the system clipboard. The osc52 backend cannot
+// read it, since few terminals answer OSC 52 queries.
+func Paste() (string, error) {
+	if len(pasteCommand) > 0 {
+		return pasteWith(pasteCommand[0], pasteCommand[1:]...)
+	}
+	switch backend {
+	case BackendNative:
+		return pasteNative()
+	case BackendOSC52:
+		return "", fmt.Errorf("the osc52 clipboard cannot be read; set clipboard.paste_command")
+	case BackendAuto:
+		if runtime.GOOS == "windows" {
+			return pasteNative()
+		}
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return pasteWith("pbpaste")
+	case "linux":
+		return pasteLinux()
+	case "windows":
+		return pasteWith("powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw")
+	default:
+		return "", fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// pasteLinux reads the clipboard with the first utility that works, in the
+// order copyLinux tries them
+func pasteLinux() (string, error) {
+	utilities := [][]string{
+		{"xclip", "-selection", "clipboard", "-o"},
+		{"xsel", "--clipboard", "--output"},
+		{"wl-paste", "--no-newline"},
+	}
+	var lastErr error
+	for _, utility := range utilities {
+		if !isCommandAvailable(utility[0]) {
+			continue
+		}
+		text, err := pasteWith(utility[0], utility[1:]...)
+		if err == nil {
+			return text, nil
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		return "", fmt.Errorf("clipboard utilities available but failed: %w", lastErr)
+	}
+	return "", NewClipboardError()
+}
+
+// pasteWith runs a command that prints the clipboard
+func pasteWith(name string, args ...string) (string, error) {
+	output, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", name, err)
+	}
+	return string(output), nil
+}
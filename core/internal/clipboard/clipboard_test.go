@@ -2,6 +2,9 @@ package clipboard
 
 import (
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
 	"testing"
 )
@@ -108,4 +111,52 @@ func findSubstring(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+func TestSetCommand(t *testing.T) {
+	if _, err := exec.LookPath("tee"); err != nil {
+		t.Skip("tee not available")
+	}
+	path := filepath.Join(t.TempDir(), "clipboard.txt")
+	SetCommand("tee " + path)
+	defer SetCommand("")
+
+	if err := Copy("copied text"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "copied text" {
+		t.Errorf("clipboard command received %q, %v", data, err)
+	}
+}
+
+func TestSetPasteCommand(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo not available")
+	}
+	SetPasteCommand("echo pasted text")
+	defer SetPasteCommand("")
+
+	text, err := Paste()
+	if err != nil || text != "pasted text\n" {
+		t.Errorf("Paste = %q, %v; want what the command printed", text, err)
+	}
+}
+
+func TestSetBackend(t *testing.T) {
+	defer SetBackend("")
+
+	// A configured command wins over detection and the fallbacks
+	SetCommand("wl-copy --primary")
+	if got := Resolved(); got != "wl-copy --primary" {
+		t.Errorf("Resolved with a command = %q, want the command", got)
+	}
+	SetCommand("")
+
+	SetBackend(BackendOSC52)
+	if _, err := Paste(); err == nil {
+		t.Error("Paste read the clipboard with the osc52 backend")
+	}
+	if terminalOutput() == nil && IsClipboardAvailable() {
+		t.Error("osc52 clipboard reported available without a terminal")
+	}
+}
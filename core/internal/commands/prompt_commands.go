@@ -45,11 +45,17 @@ package commands
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/language"
 	"github.com/dpshade/pocket-prompt/internal/models"
 	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/storage"
 )
 
 // ListPromptsCommand lists all prompts with optional filtering
@@ -57,7 +63,9 @@ type ListPromptsCommand struct {
 	service  *service.Service
 	Tag      string
 	Pack     string
+	Language string
 	Format   string
+	Sort     string // "frecency" puts the prompts used most, and most recently, first
 	Archived bool
 }
 
@@ -72,12 +80,18 @@ func (c *ListPromptsCommand) SetParameters(params map[string]interface{}) error
 	if pack, ok := params["pack"].(string); ok {
 		c.Pack = pack
 	}
+	if lang, ok := params["lang"].(string); ok {
+		c.Language = lang
+	}
 	if format, ok := params["format"].(string); ok {
 		c.Format = format
 	}
 	if archived, ok := params["archived"].(bool); ok {
 		c.Archived = archived
 	}
+	if sort, ok := params["sort"].(string); ok {
+		c.Sort = sort
+	}
 	return nil
 }
 
@@ -85,6 +99,12 @@ func (c *ListPromptsCommand) Validate() error {
 	if c.service == nil {
 		return fmt.Errorf("service not set")
 	}
+	if c.Language != "" && language.Normalize(c.Language) == "" {
+		return fmt.Errorf("invalid language %q (use a language code such as de or pt-BR)", c.Language)
+	}
+	if c.Sort != "" && c.Sort != "frecency" {
+		return fmt.Errorf("unsupported sort %q (expected frecency)", c.Sort)
+	}
 	return nil
 }
 
@@ -93,7 +113,7 @@ func (c *ListPromptsCommand) GetName() string {
 }
 
 func (c *ListPromptsCommand) GetDescription() string {
-	return "List all prompts with optional filtering by tag, pack, or archived status"
+	return "List all prompts with optional filtering by tag, pack, language, or archived status"
 }
 
 func (c *ListPromptsCommand) Execute(ctx context.Context) (*CommandResult, error) {
@@ -109,12 +129,18 @@ func (c *ListPromptsCommand) Execute(ctx context.Context) (*CommandResult, error
 	} else {
 		prompts, err = c.service.ListPrompts()
 	}
+	if err == nil && c.Language != "" {
+		prompts, err = c.service.FilterByLanguage(prompts, c.Language)
+	}
+	if err == nil && c.Sort == "frecency" {
+		err = c.service.SortByFrecency(prompts)
+	}
 
 	if err != nil {
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "LIST_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
@@ -172,12 +198,16 @@ func (c *SearchPromptsCommand) Execute(ctx context.Context) (*CommandResult, err
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "SEARCH_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
 	}
 
+	if c.Query != "" {
+		c.service.RecordSearch("search", c.Query, len(prompts))
+	}
+
 	// TODO: Add pack filtering when supported in service
 	// For now, return all results
 
@@ -238,7 +268,7 @@ func (c *BooleanSearchCommand) Execute(ctx context.Context) (*CommandResult, err
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "INVALID_EXPRESSION",
+				Code:    string(errors.ErrCodeInvalidExpression),
 				Message: err.Error(),
 			},
 		}, nil
@@ -249,11 +279,12 @@ func (c *BooleanSearchCommand) Execute(ctx context.Context) (*CommandResult, err
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "BOOLEAN_SEARCH_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
 	}
+	c.service.RecordSearch("boolean", c.Expression, len(prompts))
 
 	return &CommandResult{
 		Success: true,
@@ -309,7 +340,7 @@ func (c *GetPromptCommand) Execute(ctx context.Context) (*CommandResult, error)
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "PROMPT_NOT_FOUND",
+				Code:    failureCode(err, errors.ErrCodeNotFound),
 				Message: err.Error(),
 			},
 		}, nil
@@ -322,10 +353,85 @@ func (c *GetPromptCommand) Execute(ctx context.Context) (*CommandResult, error)
 	}, nil
 }
 
+// RenderPromptCommand renders a prompt ready to paste, applying its template
+// and filling in variables from a profile and explicit values
+type RenderPromptCommand struct {
+	service   *service.Service
+	ID        string
+	Format    string // "text" (default) or "json"
+	Profile   string
+	Variables map[string]interface{}
+}
+
+func (c *RenderPromptCommand) SetService(svc *service.Service) {
+	c.service = svc
+}
+
+func (c *RenderPromptCommand) SetParameters(params map[string]interface{}) error {
+	if id, ok := params["id"].(string); ok {
+		c.ID = id
+	}
+	if format, ok := params["format"].(string); ok {
+		c.Format = format
+	}
+	if profile, ok := params["profile"].(string); ok {
+		c.Profile = profile
+	}
+	if variables, ok := params["variables"].(map[string]interface{}); ok {
+		c.Variables = variables
+	}
+	return nil
+}
+
+func (c *RenderPromptCommand) Validate() error {
+	if c.service == nil {
+		return fmt.Errorf("service not set")
+	}
+	if c.ID == "" {
+		return fmt.Errorf("prompt ID is required")
+	}
+	if c.Format != "" && c.Format != "text" && c.Format != "json" {
+		return fmt.Errorf("unsupported format %q (expected text or json)", c.Format)
+	}
+	return nil
+}
+
+func (c *RenderPromptCommand) GetName() string {
+	return "render"
+}
+
+func (c *RenderPromptCommand) GetDescription() string {
+	return "Render a prompt with its template applied, counting it as a use"
+}
+
+func (c *RenderPromptCommand) Execute(ctx context.Context) (*CommandResult, error) {
+	rendered, err := c.service.RenderPrompt(c.ID, service.RenderOptions{
+		Format:    c.Format,
+		Profile:   c.Profile,
+		Variables: c.Variables,
+	})
+	if err != nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &CommandResult{
+		Success: true,
+		Data:    rendered,
+		Message: fmt.Sprintf("Rendered prompt: %s", c.ID),
+	}, nil
+}
+
 // CreatePromptCommand creates a new prompt
 type CreatePromptCommand struct {
-	service *service.Service
-	Prompt  *models.Prompt
+	service   *service.Service
+	Prompt    *models.Prompt
+	Directory string // Optional subdirectory under the prompts folder
 }
 
 func (c *CreatePromptCommand) SetService(svc *service.Service) {
@@ -362,6 +468,9 @@ func (c *CreatePromptCommand) SetParameters(params map[string]interface{}) error
 	if pack, ok := params["pack"].(string); ok {
 		prompt.Pack = pack
 	}
+	if dir, ok := params["directory"].(string); ok {
+		c.Directory = dir
+	}
 
 	// Handle tags array
 	if tagsInterface, ok := params["tags"]; ok {
@@ -410,12 +519,26 @@ func (c *CreatePromptCommand) GetDescription() string {
 }
 
 func (c *CreatePromptCommand) Execute(ctx context.Context) (*CommandResult, error) {
+	if c.Directory != "" && c.Prompt.FilePath == "" {
+		path, err := c.service.PromptFilePath(c.Prompt.Pack, c.Directory, c.Prompt.ID)
+		if err != nil {
+			return &CommandResult{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    string(errors.ErrCodeInvalidInput),
+					Message: err.Error(),
+				},
+			}, nil
+		}
+		c.Prompt.FilePath = path
+	}
+
 	err := c.service.CreatePrompt(c.Prompt)
 	if err != nil {
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "CREATE_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
@@ -430,8 +553,9 @@ func (c *CreatePromptCommand) Execute(ctx context.Context) (*CommandResult, erro
 
 // UpdatePromptCommand updates an existing prompt
 type UpdatePromptCommand struct {
-	service *service.Service
-	Prompt  *models.Prompt
+	service         *service.Service
+	Prompt          *models.Prompt
+	ExpectedVersion string // Refuse the update unless the prompt is still at this version or revision
 }
 
 func (c *UpdatePromptCommand) SetService(svc *service.Service) {
@@ -446,6 +570,9 @@ func (c *UpdatePromptCommand) SetParameters(params map[string]interface{}) error
 			return fmt.Errorf("invalid prompt data type")
 		}
 	}
+	if expected, ok := params["expected_version"].(string); ok {
+		c.ExpectedVersion = expected
+	}
 	return nil
 }
 
@@ -471,12 +598,17 @@ func (c *UpdatePromptCommand) GetDescription() string {
 }
 
 func (c *UpdatePromptCommand) Execute(ctx context.Context) (*CommandResult, error) {
-	err := c.service.UpdatePrompt(c.Prompt)
+	var err error
+	if c.ExpectedVersion != "" {
+		err = c.service.UpdatePromptIfVersion(c.Prompt, c.ExpectedVersion)
+	} else {
+		err = c.service.UpdatePrompt(c.Prompt)
+	}
 	if err != nil {
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "UPDATE_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
@@ -530,7 +662,7 @@ func (c *DeletePromptCommand) Execute(ctx context.Context) (*CommandResult, erro
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "DELETE_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
@@ -540,4 +672,35 @@ func (c *DeletePromptCommand) Execute(ctx context.Context) (*CommandResult, erro
 		Success: true,
 		Message: fmt.Sprintf("Deleted prompt: %s", c.ID),
 	}, nil
-}
\ No newline at end of file
+}
+
+// failureCode is the error code for a command that failed with err, so the
+// API answers with a matching status: PERMISSION_DENIED for a protected
+// prompt or a read-only library, ALREADY_EXISTS for a prompt someone else has
+// locked, VERSION_CONFLICT for a prompt changed since the expected version,
+// NOT_FOUND for a missing prompt, template or search, and fallback otherwise
+func failureCode(err error, fallback errors.ErrorCode) string {
+	return string(FailureCode(err, fallback))
+}
+
+// FailureCode is failureCode for callers outside the command system, such as
+// API handlers that call the service directly
+func FailureCode(err error, fallback errors.ErrorCode) errors.ErrorCode {
+	var protected *service.ProtectedError
+	var locked *service.LockedError
+	var conflict *service.VersionConflictError
+	var ambiguous *service.AmbiguousIDError
+	switch {
+	case stderrors.As(err, &protected), stderrors.Is(err, storage.ErrReadOnly), stderrors.Is(err, config.ErrReadOnly):
+		return errors.ErrCodePermissionDenied
+	case stderrors.As(err, &locked):
+		return errors.ErrCodeAlreadyExists
+	case stderrors.As(err, &conflict):
+		return errors.ErrCodeVersionConflict
+	case stderrors.As(err, &ambiguous):
+		return errors.ErrCodeAmbiguousID
+	case strings.Contains(err.Error(), "not found"):
+		return errors.ErrCodeNotFound
+	}
+	return fallback
+}
@@ -0,0 +1,411 @@
+// Package commands/template_commands implements template management commands.
+//
+// SYSTEM ARCHITECTURE ROLE:
+// This module gives templates the same unified command surface prompts have,
+// so the CLI, TUI and HTTP API list, create, edit and delete templates through
+// one code path instead of each calling the service directly.
+//
+// KEY RESPONSIBILITIES:
+// - Implement Command interface for template operations (list, get, create, update, delete)
+// - Build templates from primitive parameters for HTTP and remote callers
+// - Report missing and duplicate templates as structured errors
+//
+// INTEGRATION POINTS:
+// - internal/service/service.go: Delegates to ListTemplates(), GetTemplate(), SaveTemplate(), DeleteTemplate()
+// - internal/models/template.go: Works with models.Template and models.Slot
+// - internal/commands/types.go: Registered in registerCommands(); list and get are read-only
+// - internal/cli/cli.go: pkt templates and pkt template subcommands run these commands
+// - internal/ui/model.go: Template management view saves, deletes and reloads templates through them
+// - internal/api/server.go: /api/v1/templates endpoints run these commands
+//
+// COMMAND IMPLEMENTATIONS:
+// - ListTemplatesCommand: Lists all templates
+// - GetTemplateCommand: Retrieves one template by ID
+// - CreateTemplateCommand: Creates a template, failing if the ID is taken
+// - UpdateTemplateCommand: Edits a template, archiving the version it replaces
+// - DeleteTemplateCommand: Removes a template by ID
+//
+// USAGE PATTERNS:
+// - Local interfaces pass a built *models.Template as the "template" parameter
+// - HTTP and remote callers pass id, name, description, content, version and slots
+// - Updates from primitive parameters change only the fields given
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// ListTemplatesCommand lists all templates
+type ListTemplatesCommand struct {
+	service *service.Service
+}
+
+func (c *ListTemplatesCommand) SetService(svc *service.Service) {
+	c.service = svc
+}
+
+func (c *ListTemplatesCommand) SetParameters(params map[string]interface{}) error {
+	// No parameters needed for listing templates
+	return nil
+}
+
+func (c *ListTemplatesCommand) Validate() error {
+	if c.service == nil {
+		return fmt.Errorf("service not set")
+	}
+	return nil
+}
+
+func (c *ListTemplatesCommand) GetName() string {
+	return "list-templates"
+}
+
+func (c *ListTemplatesCommand) GetDescription() string {
+	return "List all templates"
+}
+
+func (c *ListTemplatesCommand) Execute(ctx context.Context) (*CommandResult, error) {
+	templates, err := c.service.ListTemplates()
+	if err != nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &CommandResult{
+		Success: true,
+		Data:    templates,
+		Message: fmt.Sprintf("Found %d templates", len(templates)),
+	}, nil
+}
+
+// GetTemplateCommand retrieves a template by ID
+type GetTemplateCommand struct {
+	service *service.Service
+	ID      string
+}
+
+func (c *GetTemplateCommand) SetService(svc *service.Service) {
+	c.service = svc
+}
+
+func (c *GetTemplateCommand) SetParameters(params map[string]interface{}) error {
+	if id, ok := params["id"].(string); ok {
+		c.ID = id
+	}
+	return nil
+}
+
+func (c *GetTemplateCommand) Validate() error {
+	if c.service == nil {
+		return fmt.Errorf("service not set")
+	}
+	if c.ID == "" {
+		return fmt.Errorf("template ID is required")
+	}
+	return nil
+}
+
+func (c *GetTemplateCommand) GetName() string {
+	return "get-template"
+}
+
+func (c *GetTemplateCommand) GetDescription() string {
+	return "Retrieve a specific template by ID"
+}
+
+func (c *GetTemplateCommand) Execute(ctx context.Context) (*CommandResult, error) {
+	template, err := c.service.GetTemplate(c.ID)
+	if err != nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    string(errors.ErrCodeNotFound),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &CommandResult{
+		Success: true,
+		Data:    template,
+		Message: fmt.Sprintf("Retrieved template: %s", template.Name),
+	}, nil
+}
+
+// CreateTemplateCommand creates a new template
+type CreateTemplateCommand struct {
+	service  *service.Service
+	Template *models.Template
+}
+
+func (c *CreateTemplateCommand) SetService(svc *service.Service) {
+	c.service = svc
+}
+
+func (c *CreateTemplateCommand) SetParameters(params map[string]interface{}) error {
+	if template, ok := params["template"].(*models.Template); ok {
+		c.Template = template
+		return nil
+	}
+
+	template := &models.Template{Version: "1.0.0"}
+	if err := applyTemplateParams(template, params); err != nil {
+		return err
+	}
+	c.Template = template
+	return nil
+}
+
+func (c *CreateTemplateCommand) Validate() error {
+	if c.service == nil {
+		return fmt.Errorf("service not set")
+	}
+	if c.Template == nil {
+		return fmt.Errorf("template data is required")
+	}
+	if c.Template.ID == "" {
+		return fmt.Errorf("template ID is required")
+	}
+	return nil
+}
+
+func (c *CreateTemplateCommand) GetName() string {
+	return "create-template"
+}
+
+func (c *CreateTemplateCommand) GetDescription() string {
+	return "Create a new template"
+}
+
+func (c *CreateTemplateCommand) Execute(ctx context.Context) (*CommandResult, error) {
+	if _, err := c.service.GetTemplate(c.Template.ID); err == nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    string(errors.ErrCodeAlreadyExists),
+				Message: fmt.Sprintf("template already exists: %s", c.Template.ID),
+			},
+		}, nil
+	}
+
+	if err := c.service.SaveTemplate(c.Template); err != nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &CommandResult{
+		Success: true,
+		Data:    c.Template,
+		Message: fmt.Sprintf("Created template: %s", c.Template.ID),
+	}, nil
+}
+
+// UpdateTemplateCommand updates an existing template. The version it replaces
+// is archived by the service.
+type UpdateTemplateCommand struct {
+	service  *service.Service
+	Template *models.Template
+	params   map[string]interface{} // Fields to change when no template is given
+}
+
+func (c *UpdateTemplateCommand) SetService(svc *service.Service) {
+	c.service = svc
+}
+
+func (c *UpdateTemplateCommand) SetParameters(params map[string]interface{}) error {
+	if template, ok := params["template"].(*models.Template); ok {
+		c.Template = template
+		return nil
+	}
+	c.params = params
+	return nil
+}
+
+func (c *UpdateTemplateCommand) Validate() error {
+	if c.service == nil {
+		return fmt.Errorf("service not set")
+	}
+	if c.Template == nil {
+		if id, _ := c.params["id"].(string); id == "" {
+			return fmt.Errorf("template ID is required")
+		}
+		return nil
+	}
+	if c.Template.ID == "" {
+		return fmt.Errorf("template ID is required")
+	}
+	return nil
+}
+
+func (c *UpdateTemplateCommand) GetName() string {
+	return "update-template"
+}
+
+func (c *UpdateTemplateCommand) GetDescription() string {
+	return "Update an existing template, archiving the previous version"
+}
+
+func (c *UpdateTemplateCommand) Execute(ctx context.Context) (*CommandResult, error) {
+	id, _ := c.params["id"].(string)
+	if c.Template != nil {
+		id = c.Template.ID
+	}
+	existing, err := c.service.GetTemplate(id)
+	if err != nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    string(errors.ErrCodeNotFound),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	template := c.Template
+	if template == nil {
+		updated := *existing
+		if err := applyTemplateParams(&updated, c.params); err != nil {
+			return &CommandResult{
+				Success: false,
+				Error: &ErrorInfo{
+					Code:    string(errors.ErrCodeValidation),
+					Message: err.Error(),
+				},
+			}, nil
+		}
+		template = &updated
+	}
+
+	if err := c.service.SaveTemplate(template); err != nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &CommandResult{
+		Success: true,
+		Data:    template,
+		Message: fmt.Sprintf("Updated template: %s (v%s)", template.ID, template.Version),
+	}, nil
+}
+
+// DeleteTemplateCommand deletes a template by ID
+type DeleteTemplateCommand struct {
+	service *service.Service
+	ID      string
+}
+
+func (c *DeleteTemplateCommand) SetService(svc *service.Service) {
+	c.service = svc
+}
+
+func (c *DeleteTemplateCommand) SetParameters(params map[string]interface{}) error {
+	if id, ok := params["id"].(string); ok {
+		c.ID = id
+	}
+	return nil
+}
+
+func (c *DeleteTemplateCommand) Validate() error {
+	if c.service == nil {
+		return fmt.Errorf("service not set")
+	}
+	if c.ID == "" {
+		return fmt.Errorf("template ID is required")
+	}
+	return nil
+}
+
+func (c *DeleteTemplateCommand) GetName() string {
+	return "delete-template"
+}
+
+func (c *DeleteTemplateCommand) GetDescription() string {
+	return "Delete a template by ID"
+}
+
+func (c *DeleteTemplateCommand) Execute(ctx context.Context) (*CommandResult, error) {
+	if err := c.service.DeleteTemplate(c.ID); err != nil {
+		return &CommandResult{
+			Success: false,
+			Error: &ErrorInfo{
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &CommandResult{
+		Success: true,
+		Message: fmt.Sprintf("Deleted template: %s", c.ID),
+	}, nil
+}
+
+// applyTemplateParams sets the template fields present in params. Slots are
+// given as names or as objects with name, description, required and default.
+func applyTemplateParams(template *models.Template, params map[string]interface{}) error {
+	if id, ok := params["id"].(string); ok {
+		template.ID = id
+	}
+	if name, ok := params["name"].(string); ok {
+		template.Name = name
+	}
+	if description, ok := params["description"].(string); ok {
+		template.Description = description
+	}
+	if content, ok := params["content"].(string); ok {
+		template.Content = content
+	}
+	if version, ok := params["version"].(string); ok && version != "" {
+		template.Version = version
+	}
+
+	slotsParam, ok := params["slots"]
+	if !ok {
+		return nil
+	}
+	slotList, ok := slotsParam.([]interface{})
+	if !ok {
+		return fmt.Errorf("slots must be a list")
+	}
+	template.Slots = []models.Slot{}
+	for _, item := range slotList {
+		switch slot := item.(type) {
+		case string:
+			template.Slots = append(template.Slots, models.Slot{Name: slot})
+		case map[string]interface{}:
+			s := models.Slot{}
+			s.Name, _ = slot["name"].(string)
+			s.Description, _ = slot["description"].(string)
+			s.Required, _ = slot["required"].(bool)
+			s.Default, _ = slot["default"].(string)
+			s.Example, _ = slot["example"].(string)
+			if s.Name == "" {
+				return fmt.Errorf("every slot needs a name")
+			}
+			template.Slots = append(template.Slots, s)
+		default:
+			return fmt.Errorf("slots must be names or objects")
+		}
+	}
+	return nil
+}
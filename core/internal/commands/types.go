@@ -20,6 +20,7 @@
 // - internal/validation/validator.go: CommandExecutor.validator validates parameters before execution
 // - internal/errors/errors.go: Command failures are converted to ErrorInfo via AppError conversion
 // - internal/commands/prompt_commands.go: Prompt command implementations registered in registerCommands()
+// - internal/commands/template_commands.go: Template command implementations for CRUD parity with prompts
 // - internal/commands/utility_commands.go: System command implementations for metadata and health
 //
 // COMMAND FLOW:
@@ -47,6 +48,7 @@ package commands
 
 import (
 	"context"
+	"sort"
 
 	"github.com/dpshade/pocket-prompt/internal/errors"
 	"github.com/dpshade/pocket-prompt/internal/service"
@@ -225,6 +227,47 @@ func (e *CommandExecutor) Execute(ctx context.Context, commandName string, param
 	return result, nil
 }
 
+// CommandInfo describes a registered command
+type CommandInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Commands lists the registered commands sorted by name
+func (e *CommandExecutor) Commands() []CommandInfo {
+	names := e.registry.List()
+	sort.Strings(names)
+
+	infos := make([]CommandInfo, 0, len(names))
+	for _, name := range names {
+		factory, _ := e.registry.Get(name)
+		infos = append(infos, CommandInfo{Name: name, Description: factory().GetDescription()})
+	}
+	return infos
+}
+
+// readOnlyCommands never modify the library
+var readOnlyCommands = map[string]bool{
+	"list":                 true,
+	"search":               true,
+	"boolean-search":       true,
+	"get":                  true,
+	"render":               true,
+	"list-tags":            true,
+	"list-packs":           true,
+	"health":               true,
+	"list-saved-searches":  true,
+	"execute-saved-search": true,
+	"list-templates":       true,
+	"get-template":         true,
+}
+
+// IsReadOnly reports whether the named command only reads the library, so
+// remote interfaces can run it with a read-scoped API key
+func IsReadOnly(commandName string) bool {
+	return readOnlyCommands[commandName]
+}
+
 // getValidationSchema returns the validation schema name for a command
 func (e *CommandExecutor) getValidationSchema(commandName string) string {
 	switch commandName {
@@ -291,6 +334,15 @@ func (e *CommandExecutor) registerCommands() {
 		return cmd
 	})
 	
+	// Render prompt command
+	e.registry.Register("render", func() Command {
+		cmd := &RenderPromptCommand{}
+		if serviceAware, ok := interface{}(cmd).(ServiceAwareCommand); ok {
+			serviceAware.SetService(e.service)
+		}
+		return cmd
+	})
+	
 	// Create prompt command
 	e.registry.Register("create", func() Command {
 		cmd := &CreatePromptCommand{}
@@ -362,4 +414,49 @@ func (e *CommandExecutor) registerCommands() {
 		}
 		return cmd
 	})
+	
+	// List templates command
+	e.registry.Register("list-templates", func() Command {
+		cmd := &ListTemplatesCommand{}
+		if serviceAware, ok := interface{}(cmd).(ServiceAwareCommand); ok {
+			serviceAware.SetService(e.service)
+		}
+		return cmd
+	})
+	
+	// Get template command
+	e.registry.Register("get-template", func() Command {
+		cmd := &GetTemplateCommand{}
+		if serviceAware, ok := interface{}(cmd).(ServiceAwareCommand); ok {
+			serviceAware.SetService(e.service)
+		}
+		return cmd
+	})
+	
+	// Create template command
+	e.registry.Register("create-template", func() Command {
+		cmd := &CreateTemplateCommand{}
+		if serviceAware, ok := interface{}(cmd).(ServiceAwareCommand); ok {
+			serviceAware.SetService(e.service)
+		}
+		return cmd
+	})
+	
+	// Update template command
+	e.registry.Register("update-template", func() Command {
+		cmd := &UpdateTemplateCommand{}
+		if serviceAware, ok := interface{}(cmd).(ServiceAwareCommand); ok {
+			serviceAware.SetService(e.service)
+		}
+		return cmd
+	})
+	
+	// Delete template command
+	e.registry.Register("delete-template", func() Command {
+		cmd := &DeleteTemplateCommand{}
+		if serviceAware, ok := interface{}(cmd).(ServiceAwareCommand); ok {
+			serviceAware.SetService(e.service)
+		}
+		return cmd
+	})
 }
\ No newline at end of file
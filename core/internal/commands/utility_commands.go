@@ -42,6 +42,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dpshade/pocket-prompt/internal/errors"
 	"github.com/dpshade/pocket-prompt/internal/service"
 )
 
@@ -80,7 +81,7 @@ func (c *ListTagsCommand) Execute(ctx context.Context) (*CommandResult, error) {
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "LIST_TAGS_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
@@ -128,7 +129,7 @@ func (c *ListPacksCommand) Execute(ctx context.Context) (*CommandResult, error)
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "LIST_PACKS_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
@@ -177,7 +178,7 @@ func (c *HealthCheckCommand) Execute(ctx context.Context) (*CommandResult, error
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "HEALTH_CHECK_FAILED",
+				Code:    string(errors.ErrCodeServiceUnavailable),
 				Message: fmt.Sprintf("Service health check failed: %v", err),
 			},
 		}, nil
@@ -235,7 +236,7 @@ func (c *ListSavedSearchesCommand) Execute(ctx context.Context) (*CommandResult,
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "LIST_SAVED_SEARCHES_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
@@ -305,11 +306,12 @@ func (c *ExecuteSavedSearchCommand) Execute(ctx context.Context) (*CommandResult
 		return &CommandResult{
 			Success: false,
 			Error: &ErrorInfo{
-				Code:    "EXECUTE_SAVED_SEARCH_FAILED",
+				Code:    failureCode(err, errors.ErrCodeCommandFailed),
 				Message: err.Error(),
 			},
 		}, nil
 	}
+	c.service.RecordSearch("saved-search", c.Name, len(prompts))
 
 	return &CommandResult{
 		Success: true,
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// API key scopes, each including the permissions of the ones before it
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+var scopeRank = map[string]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3}
+
+// apiKeyPrefix marks generated keys so they are recognizable in logs and secret scanners
+const apiKeyPrefix = "pkt_"
+
+// ServerConfig holds settings for the HTTP API server
+type ServerConfig struct {
+	// APIKeys lists the keys accepted by the server. When empty the API is
+	// open. They are kept in this device's .pocket-prompt/api-keys.json, never
+	// in the synced config.json, so a teammate cannot add or remove them.
+	APIKeys []APIKey `json:"api_keys,omitempty"`
+
+	// SlackSecretEnv names the environment variable holding the Slack app's
+	// signing secret, which authenticates Slack slash commands instead of an API key
+	SlackSecretEnv string `json:"slack_secret_env,omitempty"`
+
+	// WebURL is the base address of the web UI. Feed entries link to
+	// <web_url>/prompts/<id>; without it they link to the API.
+	WebURL string `json:"web_url,omitempty"`
+
+	// Port, Listen and GRPCPort are the defaults for --port, --listen and
+	// --grpc-port, which take precedence when given
+	Port     int    `json:"port,omitempty"`
+	Listen   string `json:"listen,omitempty"`
+	GRPCPort int    `json:"grpc_port,omitempty"`
+
+	// PortFallback makes the server listen on the next free port when its
+	// own is taken instead of failing to start, like --port-fallback
+	PortFallback bool `json:"port_fallback,omitempty"`
+
+	// CORSOrigins lists the browser origins allowed to call the API, such as
+	// "chrome-extension://<id>" or "moz-extension://*". When empty any origin
+	// may; when set, browser requests from other origins are refused.
+	CORSOrigins []string `json:"cors_origins,omitempty"`
+
+	// PageSize is how many prompts list and search responses hold, with a
+	// next link to the rest. Clients can ask for up to MaxPageSize with
+	// ?limit=. When 0, responses hold every prompt unless a limit is given.
+	PageSize int `json:"page_size,omitempty"`
+}
+
+// MaxPageSize is the most prompts one list or search response holds
+const MaxPageSize = 1000
+
+// Validate reports settings the server cannot act on
+func (c ServerConfig) Validate() error {
+	for _, origin := range c.CORSOrigins {
+		if _, err := path.Match(origin, ""); err != nil || !(origin == "*" || strings.Contains(origin, "://")) {
+			return fmt.Errorf("invalid server cors origin %q (use scheme://host, a * pattern such as moz-extension://*, or *)", origin)
+		}
+	}
+	if c.PageSize < 0 || c.PageSize > MaxPageSize {
+		return fmt.Errorf("invalid server page_size %d (use 1 to %d, or 0 for no paging)", c.PageSize, MaxPageSize)
+	}
+	return nil
+}
+
+// AllowsOrigin reports whether a browser request from origin may use the API.
+// The server's own origin, host, is always allowed.
+func (c ServerConfig) AllowsOrigin(origin, host string) bool {
+	if len(c.CORSOrigins) == 0 {
+		return true
+	}
+	if _, rest, ok := strings.Cut(origin, "://"); ok && rest == host {
+		return true
+	}
+	for _, pattern := range c.CORSOrigins {
+		if matched, _ := path.Match(pattern, origin); matched || pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultPort is the port the server listens on when none is configured
+const DefaultPort = 8080
+
+// ListenPort returns the configured server port, or DefaultPort
+func (c ServerConfig) ListenPort() int {
+	if c.Port > 0 {
+		return c.Port
+	}
+	return DefaultPort
+}
+
+// APIKey is a named server key. Only a SHA-256 hash of the key is stored.
+type APIKey struct {
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"` // First characters of the key, shown in listings
+	Hash      string    `json:"hash"`
+	Scope     string    `json:"scope"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ValidScope reports whether scope names a known API key scope
+func ValidScope(scope string) bool {
+	_, ok := scopeRank[scope]
+	return ok
+}
+
+// Allows reports whether the key's scope grants the required scope
+func (k APIKey) Allows(required string) bool {
+	return scopeRank[k.Scope] >= scopeRank[required]
+}
+
+// AddAPIKey generates a new key with the given name and scope and returns the
+// plaintext key, which is not stored and cannot be recovered later
+func (c *Config) AddAPIKey(name, scope string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("API key name is required")
+	}
+	if !ValidScope(scope) {
+		return "", fmt.Errorf("invalid scope %q (expected read, write or admin)", scope)
+	}
+	for _, key := range c.Server.APIKeys {
+		if key.Name == name {
+			return "", fmt.Errorf("an API key named %q already exists", name)
+		}
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	plaintext := apiKeyPrefix + hex.EncodeToString(secret)
+
+	c.Server.APIKeys = append(c.Server.APIKeys, APIKey{
+		Name:      name,
+		Prefix:    plaintext[:len(apiKeyPrefix)+6],
+		Hash:      hashAPIKey(plaintext),
+		Scope:     scope,
+		CreatedAt: time.Now(),
+	})
+
+	return plaintext, nil
+}
+
+// RevokeAPIKey removes the named key
+func (c *Config) RevokeAPIKey(name string) error {
+	for i, key := range c.Server.APIKeys {
+		if key.Name == name {
+			c.Server.APIKeys = append(c.Server.APIKeys[:i], c.Server.APIKeys[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no API key named %q", name)
+}
+
+// LookupAPIKey returns the stored key matching plaintext, or nil
+func (c *Config) LookupAPIKey(plaintext string) *APIKey {
+	hash := []byte(hashAPIKey(plaintext))
+	for i := range c.Server.APIKeys {
+		if subtle.ConstantTimeCompare(hash, []byte(c.Server.APIKeys[i].Hash)) == 1 {
+			return &c.Server.APIKeys[i]
+		}
+	}
+	return nil
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeysFile is the device file under .pocket-prompt/ holding the API keys
+const apiKeysFile = "api-keys.json"
+
+// APIKeysPath returns this device's API key file for the library
+func (c *Config) APIKeysPath() string {
+	return filepath.Join(filepath.Dir(c.configPath), apiKeysFile)
+}
+
+// loadAPIKeys replaces any keys read from the library's file, which anyone
+// who can push to it controls, with those in this device's key file
+func (c *Config) loadAPIKeys() error {
+	if len(c.Server.APIKeys) > 0 {
+		log.Printf("Warning: ignoring server.api_keys in %s; API keys are only read from %s (pkt server keys add)", c.configPath, c.APIKeysPath())
+		c.Server.APIKeys = nil
+	}
+	data, err := os.ReadFile(c.APIKeysPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", c.APIKeysPath(), err)
+	}
+	if err := json.Unmarshal(data, &c.Server.APIKeys); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", c.APIKeysPath(), err)
+	}
+	c.savedAPIKeys = append([]APIKey(nil), c.Server.APIKeys...)
+	return nil
+}
+
+// saveAPIKeys writes the keys to this device's key file when they changed
+// since it was read
+func (c *Config) saveAPIKeys() error {
+	if reflect.DeepEqual(c.Server.APIKeys, c.savedAPIKeys) || (len(c.Server.APIKeys) == 0 && len(c.savedAPIKeys) == 0) {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.Server.APIKeys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal API keys: %w", err)
+	}
+	if err := os.WriteFile(c.APIKeysPath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", c.APIKeysPath(), err)
+	}
+	c.savedAPIKeys = append([]APIKey(nil), c.Server.APIKeys...)
+	return nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIKeysOnlyFromDeviceFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	library := t.TempDir()
+
+	// A library pushed by someone else brings its own admin key
+	data := []byte(`{"server": {"port": 9000, "api_keys": [{"name": "intruder", "hash": "abc", "scope": "admin"}]}}`)
+	path := filepath.Join(library, ".pocket-prompt", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if warnings, err := CheckConfig(data); err != nil || len(warnings) != 1 || !strings.Contains(warnings[0], "server.api_keys is ignored") {
+		t.Errorf("CheckConfig = %q, %v; want the keys reported as ignored", warnings, err)
+	}
+	cfg, err := LoadConfig(library)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(cfg.Server.APIKeys) != 0 || cfg.Server.Port != 9000 {
+		t.Fatalf("server = %+v; want the library's keys ignored and other settings kept", cfg.Server)
+	}
+
+	// Keys added on this device are saved beside config.json, not in it
+	watcher := NewWatcher(library, cfg)
+	plaintext, err := cfg.AddAPIKey("raycast", ScopeWrite)
+	if err != nil {
+		t.Fatalf("AddAPIKey: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved, _ := os.ReadFile(path); strings.Contains(string(saved), "api_keys") {
+		t.Errorf("config.json holds API keys:\n%s", saved)
+	}
+	if info, err := os.Stat(cfg.APIKeysPath()); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("API key file: %v, %v", info, err)
+	}
+	cfg, err = LoadConfig(library)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if key := cfg.LookupAPIKey(plaintext); key == nil || key.Name != "raycast" {
+		t.Errorf("LookupAPIKey after reload = %+v, want the raycast key", key)
+	}
+
+	// A running server sees keys revoked from the device file
+	time.Sleep(10 * time.Millisecond)
+	if err := cfg.RevokeAPIKey("raycast"); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if key := watcher.Current().LookupAPIKey(plaintext); key != nil {
+		t.Errorf("watcher still accepts a revoked key")
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults for 'pkt autotag'
+const (
+	DefaultAutotagMaxTags = 3
+	DefaultAutotagMinTags = 2
+	DefaultAutotagTimeout = time.Minute
+)
+
+// AutotagConfig sets how 'pkt autotag' proposes tags for prompts with fewer
+// than MinTags tags. The command runs with $POCKET_PROMPT_TAGS holding the
+// library's tags and prints the tags to add separated by commas or lines, so
+// an LLM command line tool can choose them. Without a command tags are found
+// from the prompt's words.
+type AutotagConfig struct {
+	CommandConfig     // Timeout is per prompt (default 1m)
+	MaxTags       int `json:"max_tags,omitempty"` // Proposed per prompt (default 3)
+	MinTags       int `json:"min_tags,omitempty"` // Prompts with fewer tags get proposals (default 2)
+}
+
+// MaximumTags returns how many tags are proposed for one prompt at most
+func (c AutotagConfig) MaximumTags() int {
+	if c.MaxTags <= 0 {
+		return DefaultAutotagMaxTags
+	}
+	return c.MaxTags
+}
+
+// MinimumTags returns how many tags a prompt needs to be left alone
+func (c AutotagConfig) MinimumTags() int {
+	if c.MinTags <= 0 {
+		return DefaultAutotagMinTags
+	}
+	return c.MinTags
+}
+
+// Validate reports a timeout that is not a duration and negative counts
+func (c AutotagConfig) Validate() error {
+	if c.MaxTags < 0 {
+		return fmt.Errorf("invalid autotag max_tags %d", c.MaxTags)
+	}
+	if c.MinTags < 0 {
+		return fmt.Errorf("invalid autotag min_tags %d", c.MinTags)
+	}
+	return c.CommandConfig.Validate()
+}
@@ -0,0 +1,33 @@
+package config
+
+// Environment variables read for bot tokens when BotConfig does not name others
+const (
+	DefaultDiscordTokenEnv  = "DISCORD_BOT_TOKEN"
+	DefaultTelegramTokenEnv = "TELEGRAM_BOT_TOKEN"
+)
+
+// DefaultDiscordPrefix starts bot commands in Discord messages, e.g. "!pkt search review"
+const DefaultDiscordPrefix = "!pkt"
+
+// BotConfig sets up the chat bots started with --bot. Tokens stay out of the
+// config file: each field names the environment variable that holds one.
+type BotConfig struct {
+	DiscordTokenEnv  string `json:"discord_token_env,omitempty"`
+	TelegramTokenEnv string `json:"telegram_token_env,omitempty"`
+	DiscordPrefix    string `json:"discord_prefix,omitempty"`
+}
+
+// DiscordTokenVar returns the environment variable holding the Discord bot token
+func (c BotConfig) DiscordTokenVar() string {
+	return orDefault(c.DiscordTokenEnv, DefaultDiscordTokenEnv)
+}
+
+// TelegramTokenVar returns the environment variable holding the Telegram bot token
+func (c BotConfig) TelegramTokenVar() string {
+	return orDefault(c.TelegramTokenEnv, DefaultTelegramTokenEnv)
+}
+
+// Prefix returns the command prefix the Discord bot listens for
+func (c BotConfig) Prefix() string {
+	return orDefault(c.DiscordPrefix, DefaultDiscordPrefix)
+}
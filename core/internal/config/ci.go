@@ -0,0 +1,16 @@
+package config
+
+// CIConfig tunes the checks 'pkt ci' runs on top of the lint rules
+type CIConfig struct {
+	TokenBudget int            `json:"token_budget,omitempty"` // Estimated tokens a rendered prompt may use; 0 means no limit
+	TagBudgets  map[string]int `json:"tag_budgets,omitempty"`  // Budgets for prompts with a tag, e.g. {"system": 500}; the lowest that applies wins
+	Similarity  float64        `json:"similarity,omitempty"`   // Word overlap from 0 to 1 at which prompts count as near duplicates (default: 0.9)
+}
+
+// DuplicateThreshold returns the similarity at which prompts are reported as near duplicates
+func (c CIConfig) DuplicateThreshold() float64 {
+	if c.Similarity <= 0 || c.Similarity > 1 {
+		return 0.9
+	}
+	return c.Similarity
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// CLIConfig holds defaults for pkt commands, so flags used on every
+// invocation can be set once
+type CLIConfig struct {
+	Format    string `json:"format,omitempty"`     // Output format when --format is not given: "text", "json", "table" or "ids"
+	Pack      string `json:"pack,omitempty"`       // Pack 'pkt create' uses when --pack is not given (default: personal)
+	Confirm   string `json:"confirm,omitempty"`    // "ask" (default) to confirm deletions, or "never"
+	Editor    string `json:"editor,omitempty"`     // Command 'pkt edit' opens prompt files with (default: $VISUAL, then $EDITOR)
+	Clipboard string `json:"clipboard,omitempty"`  // Command copied text is piped to, e.g. "wl-copy" (default: detected)
+	NoHistory bool   `json:"no_history,omitempty"` // Don't record commands for 'pkt history' and 'pkt repeat'
+
+	// Aliases name shortcuts for commands with their arguments, e.g.
+	// "cr": "copy --format json" makes 'pkt cr review' run 'pkt copy review
+	// --format json'. Arguments with spaces are quoted as in a shell.
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// Validate reports settings pkt cannot act on
+func (c CLIConfig) Validate() error {
+	switch c.Format {
+	case "", "text", "json", "table", "ids":
+	default:
+		return fmt.Errorf("invalid cli format %q (use text, json, table or ids)", c.Format)
+	}
+	switch c.Confirm {
+	case "", "ask", "never":
+	default:
+		return fmt.Errorf("invalid cli confirm %q (use ask or never)", c.Confirm)
+	}
+	for name, expansion := range c.Aliases {
+		if err := checkAlias(name, expansion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Alias returns the command and arguments the alias called name stands for
+func (c CLIConfig) Alias(name string) ([]string, bool) {
+	expansion, ok := c.Aliases[name]
+	if !ok {
+		return nil, false
+	}
+	args, err := SplitArgs(expansion)
+	if err != nil || len(args) == 0 {
+		return nil, false
+	}
+	return args, true
+}
+
+// SetAlias defines or replaces the alias called name
+func (c *Config) SetAlias(name, expansion string) error {
+	name, expansion = strings.TrimSpace(name), strings.TrimSpace(expansion)
+	if err := checkAlias(name, expansion); err != nil {
+		return err
+	}
+	if c.CLI.Aliases == nil {
+		c.CLI.Aliases = map[string]string{}
+	}
+	c.CLI.Aliases[name] = expansion
+	return nil
+}
+
+// RemoveAlias deletes the alias called name
+func (c *Config) RemoveAlias(name string) error {
+	if _, ok := c.CLI.Aliases[name]; !ok {
+		return fmt.Errorf("no alias named %q", name)
+	}
+	delete(c.CLI.Aliases, name)
+	return nil
+}
+
+func checkAlias(name, expansion string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t\"'") {
+		return fmt.Errorf("invalid alias name %q (use a single word, such as cr)", name)
+	}
+	args, err := SplitArgs(expansion)
+	if err != nil {
+		return fmt.Errorf("alias %s: %w", name, err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("alias %s has no command", name)
+	}
+	return nil
+}
+
+// SplitArgs splits a command line into arguments at spaces, keeping text in
+// single or double quotes together, as a shell would
+func SplitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed %c quote in %q", quote, line)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// JoinArgs joins arguments into a command line SplitArgs splits back,
+// quoting those with spaces or quotes
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case arg == "":
+			quoted[i] = `""`
+		case !strings.ContainsAny(arg, " \t\n\"'"):
+			quoted[i] = arg
+		case !strings.Contains(arg, `"`):
+			quoted[i] = `"` + arg + `"`
+		default:
+			quoted[i] = "'" + arg + "'"
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// DefaultPack returns the pack new prompts are created in
+func (c CLIConfig) DefaultPack() string {
+	if c.Pack == "" {
+		return "personal"
+	}
+	return c.Pack
+}
+
+// SkipConfirm reports whether destructive commands run without asking first
+func (c CLIConfig) SkipConfirm() bool {
+	return c.Confirm == "never"
+}
+
+// EditorCommand returns the editor to open files with: the configured one,
+// then $VISUAL, then $EDITOR, then the platform default
+func (c CLIConfig) EditorCommand() string {
+	for _, editor := range []string{c.Editor, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if strings.TrimSpace(editor) != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
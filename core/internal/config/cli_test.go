@@ -0,0 +1,38 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	var cfg Config
+	if err := cfg.SetAlias("rev", `search "code review" --format ids`); err != nil {
+		t.Fatalf("SetAlias: %v", err)
+	}
+	args, ok := cfg.CLI.Alias("rev")
+	if want := []string{"search", "code review", "--format", "ids"}; !ok || !reflect.DeepEqual(args, want) {
+		t.Errorf("Alias rev = %q, want %q", args, want)
+	}
+
+	for _, bad := range []struct{ name, expansion string }{
+		{"two words", "list"},
+		{"--flag", "list"},
+		{"empty", "  "},
+		{"open", `search "code review`},
+	} {
+		if err := cfg.SetAlias(bad.name, bad.expansion); err == nil {
+			t.Errorf("SetAlias(%q, %q) succeeded, want an error", bad.name, bad.expansion)
+		}
+	}
+
+	if joined := JoinArgs([]string{"search", "code review", `say "hi"`, ""}); joined != `search "code review" 'say "hi"' ""` {
+		t.Errorf("JoinArgs = %s", joined)
+	}
+	if err := cfg.RemoveAlias("rev"); err != nil || len(cfg.CLI.Aliases) != 0 {
+		t.Errorf("RemoveAlias: %v, aliases left %v", err, cfg.CLI.Aliases)
+	}
+	if err := cfg.RemoveAlias("rev"); err == nil {
+		t.Error("RemoveAlias of a missing alias succeeded")
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Defaults for 'pkt watch-clipboard'
+const (
+	DefaultClipboardMinLength = 80
+	DefaultClipboardInterval  = time.Second
+	DefaultClipboardTag       = "inbox"
+)
+
+// DefaultClipboardPatterns are the usual openings of a prompt, which copied
+// text must start with to be kept unless patterns are configured
+var DefaultClipboardPatterns = []string{`(?i)^\s*you are\b`, `(?i)^\s*act as\b`}
+
+// ClipboardConfig decides which copied text 'pkt watch-clipboard' saves as
+// a prompt: text at least MinLength characters long that matches one of
+// Patterns
+type ClipboardConfig struct {
+	MinLength    int      `json:"min_length,omitempty"`    // In characters (default 80)
+	Patterns     []string `json:"patterns,omitempty"`      // Go regular expressions (default: starts with "You are" or "Act as")
+	Interval     string   `json:"interval,omitempty"`      // How often the clipboard is read, e.g. "2s" (default 1s)
+	Tags         []string `json:"tags,omitempty"`          // Given to saved prompts (default: inbox)
+	PasteCommand string   `json:"paste_command,omitempty"` // Prints the clipboard, e.g. "wl-paste --no-newline", instead of the detected utility
+}
+
+// MinimumLength returns how long copied text must be to be kept
+func (c ClipboardConfig) MinimumLength() int {
+	if c.MinLength <= 0 {
+		return DefaultClipboardMinLength
+	}
+	return c.MinLength
+}
+
+// Matchers returns the compiled patterns copied text must match one of
+func (c ClipboardConfig) Matchers() ([]*regexp.Regexp, error) {
+	patterns := c.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultClipboardPatterns
+	}
+	matchers := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clipboard pattern %q: %w", pattern, err)
+		}
+		matchers[i] = re
+	}
+	return matchers, nil
+}
+
+// PollInterval returns how often to read the clipboard
+func (c ClipboardConfig) PollInterval() (time.Duration, error) {
+	if c.Interval == "" {
+		return DefaultClipboardInterval, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d < 100*time.Millisecond {
+		return 0, fmt.Errorf("invalid clipboard interval %q (use a duration of at least 100ms, such as 2s)", c.Interval)
+	}
+	return d, nil
+}
+
+// TagNames returns the tags saved prompts are given
+func (c ClipboardConfig) TagNames() []string {
+	if len(c.Tags) == 0 {
+		return []string{DefaultClipboardTag}
+	}
+	return c.Tags
+}
+
+// Validate reports patterns that do not compile and an interval that is not
+// a duration
+func (c ClipboardConfig) Validate() error {
+	if c.MinLength < 0 {
+		return fmt.Errorf("invalid clipboard min_length %d", c.MinLength)
+	}
+	if _, err := c.Matchers(); err != nil {
+		return err
+	}
+	_, err := c.PollInterval()
+	return err
+}
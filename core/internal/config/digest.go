@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/cron"
+)
+
+// Defaults for digests
+const (
+	DefaultDigestTitle       = "Prompt digest"
+	DefaultDigestPasswordEnv = "POCKET_PROMPT_SMTP_PASSWORD"
+	DefaultDigestMaxPrompts  = 50
+)
+
+// DigestConfig sets up digests the server sends on a schedule: the prompts
+// added and updated since the last digest, or a saved search's results, as
+// Markdown posted to a webhook, emailed or both. Digests are off until
+// Schedule is set. SMTP and PasswordEnv are only read from the user's own
+// configuration, since the password is sent to the mail server.
+type DigestConfig struct {
+	Schedule    string   `json:"schedule,omitempty"`     // Cron expression such as "0 9 * * mon", or @daily, @weekly or @monthly, in the server's time zone
+	SavedSearch string   `json:"saved_search,omitempty"` // List this saved search's results instead of recent changes
+	Title       string   `json:"title,omitempty"`        // Heading and email subject (default: Prompt digest)
+	Webhook     string   `json:"webhook,omitempty"`      // URL the digest is posted to, such as a Slack, Discord or Mattermost incoming webhook
+	SMTP        string   `json:"smtp,omitempty"`         // Mail server host:port the digest is emailed through
+	Username    string   `json:"username,omitempty"`     // Mail server login (default: from)
+	PasswordEnv string   `json:"password_env,omitempty"` // Environment variable holding the mail server password
+	From        string   `json:"from,omitempty"`
+	To          []string `json:"to,omitempty"`
+	MaxPrompts  int      `json:"max_prompts,omitempty"` // Prompts listed before the rest are counted (default: 50)
+	SendEmpty   bool     `json:"send_empty,omitempty"`  // Send a digest even when nothing changed
+}
+
+// Scheduled reports whether the server should send digests
+func (c DigestConfig) Scheduled() bool {
+	return c.Schedule != ""
+}
+
+// Emailed reports whether digests go out by email
+func (c DigestConfig) Emailed() bool {
+	return c.SMTP != ""
+}
+
+// HasDestination reports whether digests have a webhook or mail server to go to
+func (c DigestConfig) HasDestination() bool {
+	return c.Webhook != "" || c.Emailed()
+}
+
+// Cron returns the parsed schedule
+func (c DigestConfig) Cron() (*cron.Schedule, error) {
+	schedule, err := cron.Parse(c.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// Heading returns the digest's title
+func (c DigestConfig) Heading() string {
+	return orDefault(c.Title, DefaultDigestTitle)
+}
+
+// Login returns the mail server user name
+func (c DigestConfig) Login() string {
+	return orDefault(c.Username, c.From)
+}
+
+// PasswordVar returns the environment variable holding the mail server password
+func (c DigestConfig) PasswordVar() string {
+	return orDefault(c.PasswordEnv, DefaultDigestPasswordEnv)
+}
+
+// Limit returns how many prompts a digest lists
+func (c DigestConfig) Limit() int {
+	if c.MaxPrompts > 0 {
+		return c.MaxPrompts
+	}
+	return DefaultDigestMaxPrompts
+}
+
+// Validate reports a schedule that cannot be parsed and destinations that
+// cannot be sent to. A schedule without any destination is left to the
+// server to warn about, so the settings can be made in any order.
+func (c DigestConfig) Validate() error {
+	if c.Scheduled() {
+		if _, err := c.Cron(); err != nil {
+			return err
+		}
+	}
+	if c.Webhook != "" {
+		u, err := url.Parse(c.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid digest webhook %q (use an http or https URL)", c.Webhook)
+		}
+	}
+	if c.Emailed() {
+		if !strings.Contains(c.SMTP, ":") {
+			return fmt.Errorf("invalid digest smtp %q (use host:port, such as smtp.fastmail.com:587)", c.SMTP)
+		}
+		if !strings.Contains(c.From, "@") {
+			return fmt.Errorf("digest smtp needs a from address")
+		}
+		if len(c.To) == 0 {
+			return fmt.Errorf("digest smtp needs at least one to address")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Get returns the setting at path, such as "server.port", or a whole section
+// such as "git", as it is in effect: environment overrides included and
+// unset settings at their zero value. Items of lists are numbered from 0,
+// as in "sources.0.name".
+func (c *Config) Get(path string) (interface{}, error) {
+	v := reflect.ValueOf(c).Elem()
+	if path == "" {
+		return v.Interface(), nil
+	}
+	var walked []string
+	for _, part := range strings.Split(path, ".") {
+		walked = append(walked, part)
+		next, ok := childValue(v, part)
+		if !ok && v.Kind() == reflect.Slice {
+			return nil, fmt.Errorf("%s has no item %s (it has %d)", strings.Join(walked[:len(walked)-1], "."), part, v.Len())
+		}
+		if !ok {
+			return nil, unknownSetting(strings.Join(walked, "."))
+		}
+		v = next
+	}
+	return v.Interface(), nil
+}
+
+// Set changes the setting at path, parsing value the way its POCKET_PROMPT_*
+// variable is parsed: "true" or "false", numbers, comma-separated lists and
+// key=value,key=value maps. The setting is left unchanged if the result does
+// not validate. Lists of objects, such as sources and API keys, can only be
+// changed by editing the file.
+func (c *Config) Set(path, value string) error {
+	f, err := settingField(path)
+	if err != nil {
+		return err
+	}
+	field := reflect.ValueOf(c).Elem().FieldByIndex(f.index)
+	parsed, err := parseEnvValue(field.Type(), value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return c.setField(f, field, parsed)
+}
+
+// Unset returns the setting at path to its default
+func (c *Config) Unset(path string) error {
+	f, err := settingField(path)
+	if err != nil {
+		return err
+	}
+	field := reflect.ValueOf(c).Elem().FieldByIndex(f.index)
+	return c.setField(f, field, reflect.Zero(field.Type()))
+}
+
+// EnvOverride returns the environment variable overriding the setting at
+// path when this configuration was loaded, or "" if it is not overridden
+func (c *Config) EnvOverride(path string) string {
+	for _, f := range envFields() {
+		if f.Path != path {
+			continue
+		}
+		for _, o := range c.envOverrides {
+			if reflect.DeepEqual(o.index, f.index) {
+				return f.Name
+			}
+		}
+	}
+	return ""
+}
+
+func (c *Config) setField(f envField, field, value reflect.Value) error {
+	previous := reflect.ValueOf(field.Interface())
+	field.Set(value)
+	if err := c.validate(); err != nil {
+		field.Set(previous)
+		return err
+	}
+
+	// A setting changed on purpose is saved even if the environment set it
+	for i, o := range c.envOverrides {
+		if reflect.DeepEqual(o.index, f.index) {
+			c.envOverrides = append(c.envOverrides[:i], c.envOverrides[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// settingField finds the setting Set can change at path, explaining why
+// sections, lists of objects and unknown keys cannot be set
+func settingField(path string) (envField, error) {
+	for _, f := range envFields() {
+		if f.Path == path {
+			return f, nil
+		}
+	}
+	t, ok := settingType(path)
+	if !ok {
+		return envField{}, unknownSetting(path)
+	}
+	if t.Kind() == reflect.Struct {
+		return envField{}, fmt.Errorf("%s is a section; set one of its settings, such as %s", path, firstSetting(path))
+	}
+	return envField{}, fmt.Errorf("%s cannot be set from the command line; change it with 'pkt config edit'", path)
+}
+
+// settingType returns the type of the setting or section at path
+func settingType(path string) (reflect.Type, bool) {
+	t := reflect.TypeOf(Config{})
+	for _, part := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, ok := fieldByKey(t, part)
+		if !ok {
+			return nil, false
+		}
+		t = field.Type
+	}
+	return t, true
+}
+
+func firstSetting(section string) string {
+	for _, f := range envFields() {
+		if strings.HasPrefix(f.Path, section+".") {
+			return f.Path
+		}
+	}
+	return section
+}
+
+// childValue steps into the struct field, map entry or list item named key
+func childValue(v reflect.Value, key string) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		field, ok := fieldByKey(v.Type(), key)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		return v.FieldByIndex(field.Index), true
+	case reflect.Map:
+		entry := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+		return entry, entry.IsValid()
+	case reflect.Slice:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, false
+		}
+		return v.Index(i), true
+	}
+	return reflect.Value{}, false
+}
+
+// fieldByKey finds the field of struct type t whose JSON name is key,
+// including those of embedded structs
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.IsExported() && name == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// CheckConfig checks data as the contents of config.json, returning an error
+// for invalid JSON, values of the wrong type and settings that do not
+// validate. Keys pocket-prompt does not know, which it would silently
+// ignore, command and endpoint settings and server sources, which are only
+// read from the user's own file, and API keys are returned as warnings.
+func CheckConfig(data []byte) ([]string, error) {
+	var config Config
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, key := range unknownKeys(raw, reflect.TypeOf(config), "") {
+		warnings = append(warnings, unknownSetting(key).Error())
+	}
+	if len(config.Server.APIKeys) > 0 {
+		warnings = append(warnings, "server.api_keys is ignored, since API keys are only read from each device's .pocket-prompt/api-keys.json (pkt server keys add)")
+	}
+	values := config.userSettingValues()
+	for _, path := range userSettings() {
+		if _, ok := values[path]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored, since %s only read from your own configuration (pkt config set %s ...)", path, userSettingReason(path), path))
+		}
+	}
+	for _, source := range config.serverSources() {
+		warnings = append(warnings, fmt.Sprintf("source %q is ignored, since server sources are only read from your own configuration (pkt sources add ...)", source.Name))
+	}
+
+	// Settings that are ignored when loading are not validated either
+	config.withoutUserSettings()
+	config.withoutServerSources()
+	config.Server.APIKeys = nil
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// unknownKeys walks raw JSON alongside the type it is read into, returning
+// the paths of keys no field is tagged with
+func unknownKeys(raw interface{}, t reflect.Type, path string) []string {
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := fieldByKey(t, key)
+			if !ok {
+				unknown = append(unknown, joinPath(path, key))
+				continue
+			}
+			unknown = append(unknown, unknownKeys(object[key], field.Type, joinPath(path, key))...)
+		}
+	case reflect.Slice:
+		items, _ := raw.([]interface{})
+		for i, item := range items {
+			unknown = append(unknown, unknownKeys(item, t.Elem(), joinPath(path, strconv.Itoa(i)))...)
+		}
+	case reflect.Map:
+		object, _ := raw.(map[string]interface{})
+		for key, value := range object {
+			unknown = append(unknown, unknownKeys(value, t.Elem(), joinPath(path, key))...)
+		}
+	case reflect.Ptr:
+		return unknownKeys(raw, t.Elem(), path)
+	}
+	return unknown
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// unknownSetting reports a key that is not a setting, suggesting the closest
+// known one in case it was mistyped
+func unknownSetting(path string) error {
+	if suggestion := closestSetting(path); suggestion != "" {
+		return fmt.Errorf("unknown setting %q (did you mean %s?)", path, suggestion)
+	}
+	return fmt.Errorf("unknown setting %q", path)
+}
+
+// closestSetting returns the known setting or section nearest to path, by
+// edit distance, or "" if none is close enough to be a typo of it. List
+// indexes in path are kept.
+func closestSetting(path string) string {
+	parts := strings.Split(path, ".")
+	var generic, indexes []string
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil {
+			indexes = append(indexes, part)
+			generic = append(generic, "#")
+			continue
+		}
+		generic = append(generic, part)
+	}
+	target := strings.Join(generic, ".")
+
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	best, bestDistance := "", len(parts[len(parts)-1])/3+2
+	for _, key := range keys {
+		if strings.Count(key, ".") != strings.Count(target, ".") {
+			continue
+		}
+		if d := editDistance(key, target); d < bestDistance {
+			best, bestDistance = key, d
+		}
+	}
+	for _, index := range indexes {
+		best = strings.Replace(best, "#", index, 1)
+	}
+	return best
+}
+
+// collectKeys lists the paths of every setting and section, with # standing
+// for list indexes
+func collectKeys(t reflect.Type, path string, keys *[]string) {
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Struct {
+			collectKeys(t.Elem(), joinPath(path, "#"), keys)
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" {
+			collectKeys(field.Type, path, keys)
+			continue
+		}
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		*keys = append(*keys, joinPath(path, name))
+		collectKeys(field.Type, joinPath(path, name), keys)
+	}
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of
+// neighbouring letters that turn a into b
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
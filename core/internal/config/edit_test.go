@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConfigSet(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg, err := LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if err := cfg.Set("server.port", "9000"); err != nil {
+		t.Fatalf("Set server.port: %v", err)
+	}
+	if err := cfg.Set("lint.forbidden_phrases", "as an AI, delve"); err != nil {
+		t.Fatalf("Set lint.forbidden_phrases: %v", err)
+	}
+	if err := cfg.Set("cli.format", "sideways"); err == nil || cfg.CLI.Format != "" {
+		t.Errorf("Set cli.format to an invalid format: err %v, format %q; want an error and no change", err, cfg.CLI.Format)
+	}
+	if err := cfg.Set("server.prot", "9000"); err == nil || !strings.Contains(err.Error(), "did you mean server.port") {
+		t.Errorf("Set server.prot: %v, want a suggestion of server.port", err)
+	}
+	if err := cfg.Set("sources", "x"); err == nil {
+		t.Error("Set sources succeeded, want lists of objects left to the file")
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	cfg, err = LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if port, _ := cfg.Get("server.port"); port != 9000 {
+		t.Errorf("server.port = %v after reloading, want 9000", port)
+	}
+	if phrases, _ := cfg.Get("lint.forbidden_phrases"); len(phrases.([]string)) != 2 {
+		t.Errorf("lint.forbidden_phrases = %q, want two phrases", phrases)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	warnings, err := CheckConfig([]byte(`{"server": {"port": 9000, "prot": 1}, "sources": [{"name": "team", "path": "/srv", "pth": "/srv"}], "extra": true}`))
+	if err != nil {
+		t.Fatalf("CheckConfig: %v", err)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("warnings = %q, want unknown keys extra, server.prot and sources.0.pth", warnings)
+	}
+	if !strings.Contains(warnings[1], "server.prot") || !strings.Contains(warnings[1], "did you mean server.port") {
+		t.Errorf("warning %q, want server.prot with a suggestion", warnings[1])
+	}
+	if !strings.Contains(warnings[2], "did you mean sources.0.path") {
+		t.Errorf("warning %q, want a suggestion of sources.0.path", warnings[2])
+	}
+
+	if _, err := CheckConfig([]byte(`{"server": {"port": "9000"}}`)); err == nil {
+		t.Error("CheckConfig accepted a port given as a string")
+	}
+	if _, err := CheckConfig([]byte(`{"cli": {"format": "sideways"}}`)); err == nil {
+		t.Error("CheckConfig accepted an invalid output format")
+	}
+}
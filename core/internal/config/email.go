@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults for the email gateway
+const (
+	DefaultEmailPasswordEnv = "POCKET_PROMPT_EMAIL_PASSWORD"
+	DefaultEmailMailbox     = "INBOX"
+	DefaultEmailPrefix      = "[pkt]"
+	DefaultEmailInterval    = 5 * time.Minute
+)
+
+// EmailConfig sets up the email gateway, which turns messages whose subject
+// starts with a prefix into prompts. The gateway is off until Server is set.
+// Server and PasswordEnv are only read from the user's own configuration,
+// since the password is sent to the server.
+type EmailConfig struct {
+	Server        string   `json:"server,omitempty"` // IMAP host:port, e.g. imap.fastmail.com:993
+	Username      string   `json:"username,omitempty"`
+	PasswordEnv   string   `json:"password_env,omitempty"`   // Environment variable holding the password
+	Mailbox       string   `json:"mailbox,omitempty"`        // Folder to watch (default: INBOX)
+	SubjectPrefix string   `json:"subject_prefix,omitempty"` // Only subjects starting with this become prompts (default: [pkt])
+	Interval      string   `json:"interval,omitempty"`       // How often the server checks, e.g. "2m" (default: 5m)
+	Tags          []string `json:"tags,omitempty"`           // Added to every emailed prompt
+	Insecure      bool     `json:"insecure,omitempty"`       // Connect without TLS, for local mail bridges only
+}
+
+// Enabled reports whether a mailbox is configured
+func (c EmailConfig) Enabled() bool {
+	return c.Server != ""
+}
+
+// PasswordVar returns the environment variable holding the mailbox password
+func (c EmailConfig) PasswordVar() string {
+	return orDefault(c.PasswordEnv, DefaultEmailPasswordEnv)
+}
+
+// MailboxName returns the folder to watch
+func (c EmailConfig) MailboxName() string {
+	return orDefault(c.Mailbox, DefaultEmailMailbox)
+}
+
+// Prefix returns the subject prefix that marks a message for import
+func (c EmailConfig) Prefix() string {
+	return orDefault(c.SubjectPrefix, DefaultEmailPrefix)
+}
+
+// PollInterval returns how often to check the mailbox
+func (c EmailConfig) PollInterval() (time.Duration, error) {
+	if c.Interval == "" {
+		return DefaultEmailInterval, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid email interval %q (use a duration such as 2m)", c.Interval)
+	}
+	return d, nil
+}
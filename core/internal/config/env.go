@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the environment variables that override settings in
+// config.json. Each variable is named after its setting's JSON path, so
+// POCKET_PROMPT_SERVER_PORT=9000 overrides {"server": {"port": 9000}}.
+const EnvPrefix = "POCKET_PROMPT_"
+
+// EnvVar is an environment variable that overrides a configuration setting
+type EnvVar struct {
+	Name string // e.g. POCKET_PROMPT_SERVER_PORT
+	Path string // e.g. server.port
+	Type string // "string", "bool", "int", "number", "list" (comma-separated) or "map" (key=value,...)
+}
+
+// envField is a setting that can be overridden, found by its field index in Config
+type envField struct {
+	EnvVar
+	index []int
+}
+
+// envOverride records a setting replaced from the environment, so Save can
+// write back the value from the file instead
+type envOverride struct {
+	index []int
+	file  reflect.Value
+	env   reflect.Value
+}
+
+// EnvVars lists the environment variables that override configuration
+// settings, sorted by name. Lists of objects, such as sources and API keys,
+// can only be set in the file.
+func EnvVars() []EnvVar {
+	var vars []EnvVar
+	for _, f := range envFields() {
+		vars = append(vars, f.EnvVar)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+func envFields() []envField {
+	var fields []envField
+	collectEnvFields(reflect.TypeOf(Config{}), nil, nil, &fields)
+	return fields
+}
+
+func collectEnvFields(t reflect.Type, path []string, index []int, fields *[]envField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			// Embedded settings, such as CommandConfig, belong to the section
+			collectEnvFields(field.Type, path, append(append([]int{}, index...), i), fields)
+			continue
+		}
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		fieldPath := append(append([]string{}, path...), name)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if field.Type.Kind() == reflect.Struct {
+			collectEnvFields(field.Type, fieldPath, fieldIndex, fields)
+			continue
+		}
+		kind := envType(field.Type)
+		if kind == "" {
+			continue
+		}
+		*fields = append(*fields, envField{
+			EnvVar: EnvVar{
+				Name: EnvPrefix + strings.ToUpper(strings.Join(fieldPath, "_")),
+				Path: strings.Join(fieldPath, "."),
+				Type: kind,
+			},
+			index: fieldIndex,
+		})
+	}
+}
+
+// envType names how a field's variable is written, or returns "" for fields
+// that cannot be set from the environment
+func envType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int:
+		return "int"
+	case reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return "list"
+		}
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String && (t.Elem().Kind() == reflect.String || t.Elem().Kind() == reflect.Int) {
+			return "map"
+		}
+	}
+	return ""
+}
+
+// applyEnv replaces settings with the values of their environment variables
+func (c *Config) applyEnv() error {
+	v := reflect.ValueOf(c).Elem()
+	for _, f := range envFields() {
+		raw, ok := os.LookupEnv(f.Name)
+		if !ok {
+			continue
+		}
+		field := v.FieldByIndex(f.index)
+		value, err := parseEnvValue(field.Type(), raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		c.envOverrides = append(c.envOverrides, envOverride{
+			index: f.index,
+			file:  reflect.ValueOf(field.Interface()),
+			env:   value,
+		})
+		field.Set(value)
+	}
+	return nil
+}
+
+// withoutEnv returns a copy of the configuration for saving, with settings
+// taken from the environment restored to their values in the file. Settings
+// changed since loading are kept.
+func (c *Config) withoutEnv() *Config {
+	saved := *c
+	v := reflect.ValueOf(&saved).Elem()
+	for _, o := range c.envOverrides {
+		field := v.FieldByIndex(o.index)
+		if reflect.DeepEqual(field.Interface(), o.env.Interface()) {
+			field.Set(o.file)
+		}
+	}
+	return &saved
+}
+
+func parseEnvValue(t reflect.Type, raw string) (reflect.Value, error) {
+	raw = strings.TrimSpace(raw)
+	switch envType(t) {
+	case "string":
+		return reflect.ValueOf(raw).Convert(t), nil
+	case "bool":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid boolean %q (use true or false)", raw)
+		}
+		return reflect.ValueOf(b).Convert(t), nil
+	case "int":
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid integer %q", raw)
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid number %q", raw)
+		}
+		return reflect.ValueOf(n).Convert(t), nil
+	case "list":
+		list := reflect.MakeSlice(t, 0, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = reflect.Append(list, reflect.ValueOf(item).Convert(t.Elem()))
+			}
+		}
+		return list, nil
+	case "map":
+		m := reflect.MakeMap(t)
+		for _, pair := range strings.Split(raw, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("invalid entry %q (use key=value,key=value)", pair)
+			}
+			elem, err := parseEnvValue(t.Elem(), value)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s: %w", strings.TrimSpace(key), err)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)).Convert(t.Key()), elem)
+		}
+		return m, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported setting type %s", t)
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg, err := LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	cfg.Server.Port = 9000
+	cfg.Git.MainBranch = "main"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	t.Setenv("POCKET_PROMPT_SERVER_PORT", "9100")
+	t.Setenv("POCKET_PROMPT_GIT_NO_SYNC", "true")
+	t.Setenv("POCKET_PROMPT_LINT_FORBIDDEN_PHRASES", "as an AI, delve")
+	t.Setenv("POCKET_PROMPT_CI_TAG_BUDGETS", "system=500,chat=2000")
+
+	cfg, err = LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadConfig with overrides: %v", err)
+	}
+	if cfg.Server.Port != 9100 || !cfg.Git.NoSync || cfg.Git.MainBranch != "main" {
+		t.Errorf("server.port = %d, git = %+v; want the environment to override only the variables set", cfg.Server.Port, cfg.Git)
+	}
+	if len(cfg.Lint.ForbiddenPhrases) != 2 || cfg.Lint.ForbiddenPhrases[1] != "delve" {
+		t.Errorf("lint.forbidden_phrases = %q", cfg.Lint.ForbiddenPhrases)
+	}
+	if cfg.CI.TagBudgets["system"] != 500 || cfg.CI.TagBudgets["chat"] != 2000 {
+		t.Errorf("ci.tag_budgets = %v", cfg.CI.TagBudgets)
+	}
+
+	// Saving keeps environment values out of the file but keeps other edits
+	cfg.Server.WebURL = "https://prompts.example"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".pocket-prompt", "config.json"))
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var saved Config
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to parse saved config: %v", err)
+	}
+	if saved.Server.Port != 9000 || saved.Git.NoSync || saved.Server.WebURL != "https://prompts.example" {
+		t.Errorf("saved server = %+v, git = %+v", saved.Server, saved.Git)
+	}
+
+	t.Setenv("POCKET_PROMPT_SERVER_PORT", "ninety")
+	if _, err := LoadConfig(tmpDir); err == nil {
+		t.Error("LoadConfig accepted a non-numeric port")
+	}
+	t.Setenv("POCKET_PROMPT_SERVER_PORT", "9100")
+
+	// Containers without clipboard utilities pick a backend from the environment
+	t.Setenv("POCKET_PROMPT_PLATFORM_CLIPBOARD", "osc52")
+	cfg, err = LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadConfig with a clipboard backend: %v", err)
+	}
+	if cfg.Platform.ClipboardBackend() != ClipboardOSC52 || cfg.Platform.RequiresGit() {
+		t.Errorf("platform = %+v, want the osc52 clipboard and git only when installed", cfg.Platform)
+	}
+	t.Setenv("POCKET_PROMPT_PLATFORM_CLIPBOARD", "xclip")
+	if _, err := LoadConfig(tmpDir); err == nil {
+		t.Error("LoadConfig accepted an unknown clipboard backend")
+	}
+}
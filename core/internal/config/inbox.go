@@ -0,0 +1,40 @@
+package config
+
+// InboxConfig maps the JSON that external tools such as Zapier or n8n post to
+// /inbox onto prompt fields. Each field holds a dot-separated path into the
+// payload, e.g. "data.body" or "items.0.text"; empty fields use the defaults below.
+type InboxConfig struct {
+	Content     string            `json:"content,omitempty"`      // Prompt text (default: "content")
+	Title       string            `json:"title,omitempty"`        // Title (default: "title"); falls back to the first line of content
+	Description string            `json:"description,omitempty"`  // Summary (default: "description")
+	Tags        string            `json:"tags,omitempty"`         // A list or comma-separated string (default: "tags")
+	DefaultTags []string          `json:"default_tags,omitempty"` // Added to every inbox prompt, e.g. ["inbox"]
+	Metadata    map[string]string `json:"metadata,omitempty"`     // Metadata key to payload path, e.g. {"author": "user.name"}
+}
+
+// ContentPath returns the payload path holding the prompt text
+func (c InboxConfig) ContentPath() string {
+	return orDefault(c.Content, "content")
+}
+
+// TitlePath returns the payload path holding the title
+func (c InboxConfig) TitlePath() string {
+	return orDefault(c.Title, "title")
+}
+
+// DescriptionPath returns the payload path holding the description
+func (c InboxConfig) DescriptionPath() string {
+	return orDefault(c.Description, "description")
+}
+
+// TagsPath returns the payload path holding the tags
+func (c InboxConfig) TagsPath() string {
+	return orDefault(c.Tags, "tags")
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,10 @@
+package config
+
+// LintConfig sets the style rules 'pkt lint' checks prompts against, so a
+// team can enforce its prompt style guide
+type LintConfig struct {
+	MaxTokens        int               `json:"max_tokens,omitempty"`        // Estimated tokens a prompt may use; 0 means no limit
+	ForbiddenPhrases []string          `json:"forbidden_phrases,omitempty"` // Matched case-insensitively, e.g. "as an AI"
+	RequiredSections []string          `json:"required_sections,omitempty"` // Headings or "Name:" lines every prompt needs, e.g. "Output format"
+	Severity         map[string]string `json:"severity,omitempty"`          // Rule name to "error", "warning" or "off"
+}
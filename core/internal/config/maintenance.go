@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults for the expiry policy
+const (
+	ExpiredArchive           = "archive" // Archive prompts past their expires date
+	ExpiredWarn              = "warn"    // Only list them
+	DefaultExpiryWarningDays = 7
+)
+
+// MaintenanceConfig sets the archive retention and expiry policies for 'pkt
+// maintenance' and how often the server runs it
+type MaintenanceConfig struct {
+	KeepVersions int    `json:"keep_versions,omitempty"` // Archived versions kept per prompt, newest first; 0 keeps all
+	MaxAgeDays   int    `json:"max_age_days,omitempty"`  // Archived versions older than this are removed; 0 keeps them
+	Interval     string `json:"interval,omitempty"`      // How often the server runs maintenance, e.g. "24h"; empty means never
+
+	Expired     string `json:"expired,omitempty"`      // What happens to prompts past their expires date: "archive" (default) or "warn"
+	WarningDays int    `json:"warning_days,omitempty"` // Prompts expiring within this many days are listed as expiring soon (default: 7)
+}
+
+// ArchivesExpired reports whether maintenance archives expired prompts
+// rather than only listing them
+func (c MaintenanceConfig) ArchivesExpired() bool {
+	return c.Expired != ExpiredWarn
+}
+
+// ExpiryWarning returns how long before its expiry a prompt is listed as
+// expiring soon
+func (c MaintenanceConfig) ExpiryWarning() time.Duration {
+	days := c.WarningDays
+	if days <= 0 {
+		days = DefaultExpiryWarningDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Validate reports an unknown expiry policy
+func (c MaintenanceConfig) Validate() error {
+	switch c.Expired {
+	case "", ExpiredArchive, ExpiredWarn:
+		return nil
+	}
+	return fmt.Errorf("invalid maintenance.expired %q (expected archive or warn)", c.Expired)
+}
+
+// Scheduled reports whether the server should run maintenance on its own
+func (c MaintenanceConfig) Scheduled() bool {
+	return c.Interval != ""
+}
+
+// RunInterval returns how often the server runs maintenance
+func (c MaintenanceConfig) RunInterval() (time.Duration, error) {
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d < time.Minute {
+		return 0, fmt.Errorf("invalid maintenance interval %q (use a duration of at least 1m, such as 24h)", c.Interval)
+	}
+	return d, nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// PackFileChange is a file of a pack that differs between two versions
+type PackFileChange struct {
+	Path   string `json:"path"`   // Relative to the pack, with forward slashes
+	Status string `json:"status"` // "modified", "added" or "deleted"
+}
+
+// Ways a pack file can change
+const (
+	PackFileModified = "modified"
+	PackFileAdded    = "added"
+	PackFileDeleted  = "deleted"
+)
+
+// packGit runs git in a pack installed from Git and returns its output
+func (c *PackConfig) packGit(name string, args ...string) (string, error) {
+	pack, err := c.GetPack(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(pack.Path, ".git")); err != nil {
+		return "", fmt.Errorf("pack '%s' was not installed from Git, so there is no upstream version to compare with", name)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = pack.Path
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(output), nil
+}
+
+// InstalledCommit returns the upstream commit a pack was installed or last
+// updated at: where its history and the branch it tracks last met, so
+// neither local commits nor fetching moves it. A pack tracking no branch
+// is compared with its latest commit.
+func (c *PackConfig) InstalledCommit(name string) (string, error) {
+	commit, err := c.packGit(name, "merge-base", "HEAD", "@{upstream}")
+	if err != nil {
+		commit, err = c.packGit(name, "rev-parse", "--verify", "HEAD")
+	}
+	return strings.TrimSpace(commit), err
+}
+
+// UpstreamCommit returns the latest commit of the branch a pack tracks, as
+// last fetched
+func (c *PackConfig) UpstreamCommit(name string) (string, error) {
+	commit, err := c.packGit(name, "rev-parse", "--verify", "@{upstream}")
+	return strings.TrimSpace(commit), err
+}
+
+// FetchUpstream fetches the latest version of a pack from where it was
+// installed from, without changing its files
+func (c *PackConfig) FetchUpstream(name string) error {
+	_, err := c.packGit(name, "fetch", "--quiet")
+	return err
+}
+
+// LocalChanges lists the files under dir in a pack whose contents differ
+// from commit: edited, deleted and new ones, whether committed or not
+func (c *PackConfig) LocalChanges(name, commit, dir string) ([]PackFileChange, error) {
+	changes, err := c.ChangesBetween(name, commit, "", dir)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := c.packGit(name, "ls-files", "--others", "--exclude-standard", "--", dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range strings.Split(strings.TrimSpace(untracked), "\n") {
+		if path != "" {
+			changes = append(changes, PackFileChange{Path: path, Status: PackFileAdded})
+		}
+	}
+	return changes, nil
+}
+
+// ChangesBetween lists the files under dir in a pack that differ between
+// two commits, or between from and the files on disk when to is empty
+func (c *PackConfig) ChangesBetween(name, from, to, dir string) ([]PackFileChange, error) {
+	args := []string{"diff", "--name-status", "--no-renames", from}
+	if to != "" {
+		args = append(args, to)
+	}
+	output, err := c.packGit(name, append(args, "--", dir)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []PackFileChange
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		status, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		change := PackFileChange{Path: path, Status: PackFileModified}
+		switch status {
+		case "A":
+			change.Status = PackFileAdded
+		case "D":
+			change.Status = PackFileDeleted
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// FileAt returns the contents of a pack file at commit, and false when the
+// file doesn't exist there
+func (c *PackConfig) FileAt(name, commit, path string) (string, bool) {
+	content, err := c.packGit(name, "show", commit+":"+path)
+	if err != nil {
+		return "", false
+	}
+	return content, true
+}
@@ -27,6 +27,14 @@ func (pi *PackInstaller) InstallFromGit(gitURL string, options PackInstallOption
 	if packName == "" {
 		return fmt.Errorf("could not determine pack name from URL: %s", gitURL)
 	}
+	if err := validPackName(packName); err != nil {
+		return err
+	}
+	if options.Name != "" {
+		if err := validPackName(options.Name); err != nil {
+			return err
+		}
+	}
 
 	// Check if pack is already installed
 	if pi.packConfig.IsPackInstalled(packName) {
@@ -66,6 +74,10 @@ func (pi *PackInstaller) InstallFromGit(gitURL string, options PackInstallOption
 		pack.Name = options.Name
 	}
 
+	if err := validPackName(pack.Name); err != nil {
+		return err
+	}
+
 	// Set install URL
 	pack.InstallURL = gitURL
 
@@ -107,6 +119,9 @@ func (pi *PackInstaller) InstallFromDirectory(srcDir string, options PackInstall
 	if options.Name != "" {
 		pack.Name = options.Name
 	}
+	if err := validPackName(pack.Name); err != nil {
+		return err
+	}
 
 	// Check if pack is already installed
 	if pi.packConfig.IsPackInstalled(pack.Name) {
@@ -161,6 +176,38 @@ func (pi *PackInstaller) UninstallPack(name string) error {
 	return nil
 }
 
+// UpdatePack pulls the latest version of a pack installed from Git and
+// reloads its pack.json, keeping where and when it was installed
+func (pi *PackInstaller) UpdatePack(name string) (*Pack, error) {
+	pack, err := pi.packConfig.GetPack(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(pack.Path, ".git")); pack.InstallURL == "" || err != nil {
+		return nil, fmt.Errorf("pack '%s' was not installed from Git, so there is nothing to pull; reinstall it from its directory instead", name)
+	}
+
+	cmd := exec.Command("git", "pull", "--ff-only")
+	cmd.Dir = pack.Path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to pull pack: %s\nOutput: %s", err, string(output))
+	}
+
+	updated, err := pi.packConfig.LoadPackMetadata(pack.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pack metadata: %w", err)
+	}
+	updated.Name = pack.Name
+	updated.InstallURL = pack.InstallURL
+	updated.HasWriteAccess = pack.HasWriteAccess
+	updated.GitSyncEnabled = pack.GitSyncEnabled
+	updated.LastSync = pack.LastSync
+	if err := pi.packConfig.UpdatePack(*updated); err != nil {
+		return nil, fmt.Errorf("failed to update pack configuration: %w", err)
+	}
+	return pi.packConfig.GetPack(name)
+}
+
 // CreatePackScaffold creates a new pack structure in the specified directory
 func (pi *PackInstaller) CreatePackScaffold(packDir, name, title, description, author string) error {
 	// Create directory structure
@@ -259,6 +306,19 @@ func extractPackNameFromGitURL(gitURL string) string {
 	return name
 }
 
+// validPackName rejects a name that is not a plain file name. Packs are
+// copied into a directory named after the pack, and the name can come from a
+// request body or a cloned pack.json, so it must not reach outside packs/.
+func validPackName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("pack name is required")
+	case strings.ContainsAny(name, `/\`) || name == "." || name == "..":
+		return fmt.Errorf("pack name %q cannot contain path separators", name)
+	}
+	return nil
+}
+
 // copyDir recursively copies a directory (excludes .git)
 func copyDir(src, dst string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
@@ -1,31 +1,46 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
 )
 
 // Pack represents a collection of prompts and templates
 type Pack struct {
-	Name           string    `json:"name"`
-	Version        string    `json:"version"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description,omitempty"`
-	Author         string    `json:"author,omitempty"`
-	Homepage       string    `json:"homepage,omitempty"`
-	Tags           []string  `json:"tags,omitempty"`
-	Prompts        []string  `json:"prompts,omitempty"`     // List of prompt IDs in this pack
-	Templates      []string  `json:"templates,omitempty"`   // List of template IDs in this pack
-	InstallTime    time.Time `json:"install_time"`
-	InstallURL     string    `json:"install_url,omitempty"` // URL pack was installed from
-	Path           string    `json:"path"`                  // Local path to pack directory
-	HasWriteAccess bool      `json:"has_write_access"`      // Whether user can push to pack's Git repo
-	GitSyncEnabled bool      `json:"git_sync_enabled"`      // Whether to auto-sync changes to Git
-	LastSync       *time.Time `json:"last_sync,omitempty"`  // Last successful Git sync time
+	Name           string       `json:"name"`
+	Version        string       `json:"version"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description,omitempty"`
+	Author         string       `json:"author,omitempty"`
+	Homepage       string       `json:"homepage,omitempty"`
+	Tags           []string     `json:"tags,omitempty"`
+	Prompts        []string     `json:"prompts,omitempty"`   // List of prompt IDs in this pack
+	Templates      []string     `json:"templates,omitempty"` // List of template IDs in this pack
+	Searches       []PackSearch `json:"searches,omitempty"`  // Saved searches installed as <pack>/<name>
+	InstallTime    time.Time    `json:"install_time"`
+	InstallURL     string       `json:"install_url,omitempty"` // URL pack was installed from
+	Path           string       `json:"path"`                  // Local path to pack directory
+	HasWriteAccess bool         `json:"has_write_access"`      // Whether user can push to pack's Git repo
+	GitSyncEnabled bool         `json:"git_sync_enabled"`      // Whether to auto-sync changes to Git
+	LastSync       *time.Time   `json:"last_sync,omitempty"`   // Last successful Git sync time
+}
+
+// PackSearch is a saved search a pack ships, so a team pack can include its
+// curated views of the library as well as content
+type PackSearch struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Expression  string `json:"expression,omitempty"` // Boolean expression, e.g. "ai AND NOT draft"
+	TextQuery   string `json:"text_query,omitempty"`
 }
 
 // PackConfig manages installed packs
@@ -33,6 +48,7 @@ type PackConfig struct {
 	Packs      []Pack `json:"packs"`
 	configPath string
 	packsDir   string
+	readOnly   bool // See SetReadOnly
 }
 
 // NewPackConfig creates a new pack configuration manager
@@ -84,6 +100,9 @@ func (c *PackConfig) Load() error {
 
 // Save writes the pack configuration to disk
 func (c *PackConfig) Save() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal pack configuration: %w", err)
@@ -195,7 +214,7 @@ func (c *PackConfig) ValidatePackStructure(packPath string) error {
 // LoadPackMetadata loads pack metadata from pack.json file
 func (c *PackConfig) LoadPackMetadata(packPath string) (*Pack, error) {
 	packJSONPath := filepath.Join(packPath, "pack.json")
-	
+
 	data, err := os.ReadFile(packJSONPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read pack.json: %w", err)
@@ -216,6 +235,19 @@ func (c *PackConfig) LoadPackMetadata(packPath string) (*Pack, error) {
 	if pack.Title == "" {
 		return nil, fmt.Errorf("pack.json must contain 'title' field")
 	}
+	for _, search := range pack.Searches {
+		if search.Name == "" || strings.Contains(search.Name, "/") {
+			return nil, fmt.Errorf("pack.json search names must be set and cannot contain '/': %q", search.Name)
+		}
+		if search.Expression == "" && search.TextQuery == "" {
+			return nil, fmt.Errorf("pack.json search %q needs an expression or text_query", search.Name)
+		}
+		if search.Expression != "" {
+			if _, err := models.ParseBooleanExpression(search.Expression); err != nil {
+				return nil, fmt.Errorf("pack.json search %q: %w", search.Name, err)
+			}
+		}
+	}
 
 	pack.Path = packPath
 	return &pack, nil
@@ -223,12 +255,15 @@ func (c *PackConfig) LoadPackMetadata(packPath string) (*Pack, error) {
 
 // SavePackMetadata saves pack metadata to pack.json file
 func (c *PackConfig) SavePackMetadata(pack *Pack) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
 	if pack.Path == "" {
 		return fmt.Errorf("pack path not set")
 	}
 
 	packJSONPath := filepath.Join(pack.Path, "pack.json")
-	
+
 	data, err := json.MarshalIndent(pack, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal pack metadata: %w", err)
@@ -237,6 +272,93 @@ func (c *PackConfig) SavePackMetadata(pack *Pack) error {
 	return os.WriteFile(packJSONPath, data, 0644)
 }
 
+// SetPromptListed adds a prompt ID to, or removes it from, the prompts that
+// a pack's pack.json lists. A pack.json without a prompts list doesn't keep
+// one, so nothing is added to it. The rest of the file is left as written.
+func (c *PackConfig) SetPromptListed(packName, id string, listed bool) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	pack, err := c.GetPack(packName)
+	if err != nil {
+		return err
+	}
+	packJSONPath := filepath.Join(pack.Path, "pack.json")
+	data, err := os.ReadFile(packJSONPath)
+	if err != nil {
+		return fmt.Errorf("failed to read pack.json: %w", err)
+	}
+	keys, values, err := decodeObject(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse pack.json: %w", err)
+	}
+
+	index := slices.Index(keys, "prompts")
+	if index < 0 {
+		return nil
+	}
+	var prompts []string
+	if err := json.Unmarshal(values[index], &prompts); err != nil {
+		return fmt.Errorf("failed to parse pack.json prompts: %w", err)
+	}
+	if slices.Contains(prompts, id) == listed {
+		return nil
+	}
+	if listed {
+		prompts = append(prompts, id)
+	} else {
+		prompts = slices.DeleteFunc(prompts, func(p string) bool { return p == id })
+	}
+	if values[index], err = json.Marshal(prompts); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("{\n")
+	for i, key := range keys {
+		name, _ := json.Marshal(key)
+		fmt.Fprintf(&b, "  %s: ", name)
+		if err := json.Indent(&b, values[i], "  ", "  "); err != nil {
+			return err
+		}
+		if i < len(keys)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	if err := os.WriteFile(packJSONPath, b.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	pack.Prompts = prompts
+	return c.Save()
+}
+
+// decodeObject splits a JSON object into its keys and values, in the order
+// they are written
+func decodeObject(data []byte) ([]string, []json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected an object")
+	}
+	var keys []string
+	var values []json.RawMessage
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, token.(string))
+		values = append(values, value)
+	}
+	return keys, values, nil
+}
+
 // IsPackInstalled checks if a pack with the given name is installed
 func (c *PackConfig) IsPackInstalled(name string) bool {
 	for _, pack := range c.Packs {
@@ -324,7 +446,7 @@ func (c *PackConfig) RefreshPackMetadata() error {
 		}
 
 		packPath := filepath.Join(c.packsDir, entry.Name())
-		
+
 		// Try to load pack metadata
 		pack, err := c.LoadPackMetadata(packPath)
 		if err != nil {
@@ -357,11 +479,11 @@ func (c *PackConfig) TestPackWriteAccess(packPath string) bool {
 	// Try a dry-run push to test write access
 	cmd := exec.Command("git", "push", "--dry-run", "origin", "HEAD")
 	cmd.Dir = packPath
-	
+
 	// Capture both stdout and stderr to suppress output
 	cmd.Stdout = nil
 	cmd.Stderr = nil
-	
+
 	// If the command succeeds, user has write access
 	return cmd.Run() == nil
 }
@@ -443,4 +565,4 @@ func (c *PackConfig) DisablePackGitSync(packName string) error {
 
 	pack.GitSyncEnabled = false
 	return c.UpdatePack(*pack)
-}
\ No newline at end of file
+}
@@ -0,0 +1,59 @@
+package config
+
+import "fmt"
+
+// Clipboard backends
+const (
+	ClipboardAuto    = "auto"    // The configured or a detected utility, else the best fallback (default)
+	ClipboardCommand = "command" // Only clipboard utilities such as pbcopy, xclip or wl-copy
+	ClipboardNative  = "native"  // The system clipboard, without utilities where pkt can reach it itself
+	ClipboardOSC52   = "osc52"   // The terminal's clipboard over an OSC 52 escape sequence; copy only
+)
+
+// Git backends
+const (
+	GitAuto    = "auto"    // Run git when it is installed, else sync with the built-in implementation (default)
+	GitExec    = "exec"    // Run git, and fail when it is not installed
+	GitBuiltin = "builtin" // Sync with the implementation built into pkt, even when git is installed
+)
+
+// PlatformConfig picks how pkt reaches the system it runs on, so it behaves
+// the same in minimal containers, over SSH and on Windows
+type PlatformConfig struct {
+	Clipboard string `json:"clipboard,omitempty"` // "auto" (default), "command", "native" or "osc52"
+	Git       string `json:"git,omitempty"`       // "auto" (default), "exec" or "builtin"
+}
+
+// ClipboardBackend returns the clipboard backend to use
+func (c PlatformConfig) ClipboardBackend() string {
+	if c.Clipboard == "" {
+		return ClipboardAuto
+	}
+	return c.Clipboard
+}
+
+// RequiresGit reports whether a missing git is an error rather than a
+// reason to sync with the built-in implementation
+func (c PlatformConfig) RequiresGit() bool {
+	return c.Git == GitExec
+}
+
+// BuiltinGit reports whether sync always uses the built-in implementation
+func (c PlatformConfig) BuiltinGit() bool {
+	return c.Git == GitBuiltin
+}
+
+// Validate reports an unknown backend
+func (c PlatformConfig) Validate() error {
+	switch c.Clipboard {
+	case "", ClipboardAuto, ClipboardCommand, ClipboardNative, ClipboardOSC52:
+	default:
+		return fmt.Errorf("invalid platform clipboard %q (use auto, command, native or osc52)", c.Clipboard)
+	}
+	switch c.Git {
+	case "", GitAuto, GitExec, GitBuiltin:
+	default:
+		return fmt.Errorf("invalid platform git %q (use auto, exec or builtin)", c.Git)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import "fmt"
+
+// Project library modes, see ProjectConfig
+const (
+	ProjectMerge    = "merge"
+	ProjectOverride = "override"
+	ProjectOff      = "off"
+)
+
+// ProjectConfig controls project-local libraries: a .pocket-prompt/
+// directory at a project root, found by walking up from the working
+// directory the way git finds .git
+type ProjectConfig struct {
+	// Mode is "merge" (default) to list project prompts alongside this
+	// library's, taking precedence when IDs clash, "override" to use the
+	// project library instead of this one, or "off" to ignore it
+	Mode string `json:"mode,omitempty"`
+}
+
+// Validate reports an unknown mode
+func (c ProjectConfig) Validate() error {
+	switch c.Mode {
+	case "", ProjectMerge, ProjectOverride, ProjectOff:
+		return nil
+	default:
+		return fmt.Errorf("invalid project mode %q (use merge, override or off)", c.Mode)
+	}
+}
+
+// LibraryMode returns the mode, defaulting to merge
+func (c ProjectConfig) LibraryMode() string {
+	if c.Mode == "" {
+		return ProjectMerge
+	}
+	return c.Mode
+}
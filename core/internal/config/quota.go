@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultQuotaWarnPercent is how full a limit gets before it is warned about
+const DefaultQuotaWarnPercent = 90
+
+// QuotaConfig sets soft limits on how far the library grows, so a shared
+// repository does not balloon unnoticed. Going over a limit never blocks a
+// change; 'pkt doctor' and the TUI status bar warn about it instead.
+type QuotaConfig struct {
+	MaxPromptsPerPack int    `json:"max_prompts_per_pack,omitempty"` // Prompts in the personal library or any one pack; 0 for no limit
+	MaxTotalSize      string `json:"max_total_size,omitempty"`       // Library files without .git and .pocket-prompt, e.g. "50MB"
+	WarnPercent       int    `json:"warn_percent,omitempty"`         // Warn once a limit is this full (default: 90)
+}
+
+// Enabled reports whether any limit is set
+func (c QuotaConfig) Enabled() bool {
+	return c.MaxPromptsPerPack > 0 || c.MaxTotalSize != ""
+}
+
+// TotalSizeLimit returns max_total_size in bytes, or 0 when there is none
+func (c QuotaConfig) TotalSizeLimit() (int64, error) {
+	if c.MaxTotalSize == "" {
+		return 0, nil
+	}
+	size, err := ParseSize(c.MaxTotalSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quota max_total_size: %w", err)
+	}
+	return size, nil
+}
+
+// WarnAt returns the percentage of a limit at which warnings start
+func (c QuotaConfig) WarnAt() int {
+	if c.WarnPercent > 0 {
+		return c.WarnPercent
+	}
+	return DefaultQuotaWarnPercent
+}
+
+// Validate reports limits that cannot be applied
+func (c QuotaConfig) Validate() error {
+	if c.MaxPromptsPerPack < 0 {
+		return fmt.Errorf("invalid quota max_prompts_per_pack %d", c.MaxPromptsPerPack)
+	}
+	if c.WarnPercent < 0 || c.WarnPercent > 100 {
+		return fmt.Errorf("invalid quota warn_percent %d (use 1-100)", c.WarnPercent)
+	}
+	_, err := c.TotalSizeLimit()
+	return err
+}
+
+// sizeUnits are the suffixes ParseSize accepts. KB and KiB alike are 1024
+// bytes, as file managers and du report them.
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30},
+	{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+	{"B", 1},
+}
+
+// ParseSize reads a size such as "500KB", "1.5 MiB" or "2G"; a bare number
+// is bytes
+func ParseSize(text string) (int64, error) {
+	number := strings.ToUpper(strings.TrimSpace(text))
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(number, unit.suffix) {
+			number = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%q is not a size (use a number with KB, MB or GB, such as 50MB)", text)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// FormatSize prints a size with a binary unit, e.g. 1.5 MiB
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
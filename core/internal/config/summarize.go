@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+// DefaultSummarizeTimeout bounds one run of the summarize command
+const DefaultSummarizeTimeout = time.Minute
+
+// SummarizeConfig sets how 'pkt summarize' writes prompt summaries. The
+// command prints a prompt's summary, so any LLM command line tool can write
+// them, such as `llm -s "Summarize this prompt in one sentence"`. Without a
+// command each summary is taken from the prompt's first sentence.
+type SummarizeConfig struct {
+	CommandConfig // Timeout is per prompt (default 1m)
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// DefaultTranslateTimeout bounds one run of the translate command
+const DefaultTranslateTimeout = 2 * time.Minute
+
+// TranslateConfig sets how 'pkt translate' translates prompts. The command
+// runs once each for a prompt's title, description and content, with the
+// target language's name in $POCKET_PROMPT_LANGUAGE and its code in
+// $POCKET_PROMPT_LANGUAGE_CODE, and prints the translation, so any LLM
+// command line tool can do it.
+type TranslateConfig struct {
+	CommandConfig // Timeout is per text (default 2m)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+)
+
+// UIConfig controls the terminal interface
+type UIConfig struct {
+	Theme  string `json:"theme,omitempty"`  // "light", "dark" or "auto" (default), which follows the terminal background
+	Locale string `json:"locale,omitempty"` // Language and date format, such as de or en-GB; defaults to LANG
+
+	// PinnedSearch names a saved search applied when the TUI opens and when
+	// 'pkt list' runs without flags
+	PinnedSearch string `json:"pinned_search,omitempty"`
+
+	// NoSessionState starts the TUI in the full library every time, instead
+	// of with the filter, selection and view it was closed with
+	NoSessionState bool `json:"no_session_state,omitempty"`
+
+	// ListPageSize is how many prompts the TUI list loads at first and then
+	// each time the cursor nears the end of them (default
+	// DefaultListPageSize). Filtering loads them all.
+	ListPageSize int `json:"list_page_size,omitempty"`
+
+	// Keys rebinds TUI actions, such as {"copy": "C", "new": "ctrl+n n"},
+	// with several keys separated by spaces. 'pkt keys' lists the actions.
+	Keys map[string]string `json:"keys,omitempty"`
+
+	// SpellCheck picks how the prompt editor checks spelling: "auto" (the
+	// default) uses aspell or hunspell when installed and a built-in list of
+	// common misspellings otherwise; "aspell", "hunspell" and "builtin" use
+	// that one; "off" turns checking off
+	SpellCheck string `json:"spellcheck,omitempty"`
+}
+
+// DefaultListPageSize is how many prompts the TUI list loads at a time
+const DefaultListPageSize = 500
+
+// ListPage returns how many prompts the TUI list loads at a time
+func (c UIConfig) ListPage() int {
+	if c.ListPageSize > 0 {
+		return c.ListPageSize
+	}
+	return DefaultListPageSize
+}
+
+// Validate reports an unknown theme or spell checker, a malformed locale, a
+// negative list page size or an action rebound to no keys
+func (c UIConfig) Validate() error {
+	switch c.Theme {
+	case "", "auto", "light", "dark":
+	default:
+		return fmt.Errorf("invalid ui theme %q (use auto, light or dark)", c.Theme)
+	}
+	switch c.SpellCheck {
+	case "", "auto", "aspell", "hunspell", "builtin", "off":
+	default:
+		return fmt.Errorf("invalid ui spellcheck %q (use auto, aspell, hunspell, builtin or off)", c.SpellCheck)
+	}
+	if !i18n.Valid(c.Locale) {
+		return fmt.Errorf("invalid ui locale %q (use a language tag such as de or en-GB)", c.Locale)
+	}
+	if c.ListPageSize < 0 {
+		return fmt.Errorf("invalid ui list_page_size %d (use a positive number)", c.ListPageSize)
+	}
+	for action, keys := range c.Keys {
+		if strings.TrimSpace(keys) == "" {
+			return fmt.Errorf("invalid ui keys for %q (use keys such as ctrl+n, separated by spaces)", action)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Package demo provides a small sample library for trying pocket-prompt,
+// taking screenshots and onboarding, without touching the user's own library.
+// The library is embedded in the binary, unpacked into a private temporary
+// directory and opened read-only.
+package demo
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+//go:embed library
+var library embed.FS
+
+// Open unpacks the sample library and opens it read-only, without git sync.
+// cleanup removes the unpacked copy.
+func Open() (svc *service.Service, cleanup func(), err error) {
+	dir, err := os.MkdirTemp("", "pocket-prompt-demo-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create demo directory: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	if err := Unpack(dir); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	svc, err = service.OpenLibrary(dir)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := svc.InitLibrary(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	svc.SetReadOnly(true)
+	return svc, cleanup, nil
+}
+
+// Unpack writes the sample library's files into dir
+func Unpack(dir string) error {
+	root, err := fs.Sub(library, "library")
+	if err != nil {
+		return err
+	}
+	return fs.WalkDir(root, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(path))
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		data, err := fs.ReadFile(root, path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(dest, data, 0644); err != nil {
+			return fmt.Errorf("failed to unpack demo library: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,53 @@
+package demo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestOpenLoadsSampleLibraryReadOnly(t *testing.T) {
+	svc, cleanup, err := Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	dir := svc.GetBaseDir()
+
+	prompts, err := svc.ListPrompts()
+	if err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+	if len(prompts) == 0 {
+		t.Fatal("demo library has no prompts")
+	}
+	templates, err := svc.ListTemplates()
+	if err != nil || len(templates) == 0 {
+		t.Fatalf("ListTemplates = %d templates, %v", len(templates), err)
+	}
+	for _, p := range prompts {
+		if p.TemplateRef == "" {
+			continue
+		}
+		if _, err := svc.GetTemplate(p.TemplateRef); err != nil {
+			t.Errorf("prompt %s uses missing template %s", p.ID, p.TemplateRef)
+		}
+	}
+
+	if !svc.ReadOnly() {
+		t.Fatal("demo library is writable")
+	}
+	if err := svc.DeletePrompt(prompts[0].ID); !errors.Is(err, storage.ErrReadOnly) {
+		t.Errorf("DeletePrompt error = %v, want ErrReadOnly", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, prompts[0].FilePath)); err != nil {
+		t.Errorf("prompt file is gone after a rejected delete: %v", err)
+	}
+
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("cleanup left %s behind", dir)
+	}
+}
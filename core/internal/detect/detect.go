@@ -0,0 +1,237 @@
+// Package detect inspects a project directory for the languages, frameworks
+// and tools it uses, read from well-known files such as go.mod or
+// package.json, and for the name of its git repository. The results are
+// lowercase terms meant to be matched against prompt tags.
+package detect
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Project is what was found in a project directory
+type Project struct {
+	Root       string   `json:"root"`
+	Languages  []string `json:"languages,omitempty"`
+	Frameworks []string `json:"frameworks,omitempty"`
+	Tools      []string `json:"tools,omitempty"`
+	Repo       string   `json:"repo,omitempty"` // Repository name from the origin remote
+}
+
+// Signals returns every detected term, languages first
+func (p *Project) Signals() []string {
+	var signals []string
+	signals = append(signals, p.Languages...)
+	signals = append(signals, p.Frameworks...)
+	signals = append(signals, p.Tools...)
+	if p.Repo != "" {
+		signals = append(signals, p.Repo)
+	}
+	return signals
+}
+
+// manifest is a file that marks a language, with dependencies that mark
+// frameworks when they appear in the file
+type manifest struct {
+	file       string
+	language   string
+	frameworks map[string]string // Text in the file -> framework
+}
+
+var manifests = []manifest{
+	{"go.mod", "go", map[string]string{
+		"github.com/gin-gonic/gin":           "gin",
+		"github.com/labstack/echo":           "echo",
+		"github.com/gofiber/fiber":           "fiber",
+		"github.com/spf13/cobra":             "cobra",
+		"github.com/charmbracelet/bubbletea": "bubbletea",
+		"google.golang.org/grpc":             "grpc",
+	}},
+	{"package.json", "javascript", map[string]string{
+		`"react"`:         "react",
+		`"next"`:          "nextjs",
+		`"vue"`:           "vue",
+		`"svelte"`:        "svelte",
+		`"@angular/core"`: "angular",
+		`"express"`:       "express",
+		`"@nestjs/core"`:  "nestjs",
+		`"react-native"`:  "react-native",
+		`"typescript"`:    "typescript",
+	}},
+	{"tsconfig.json", "typescript", nil},
+	{"Cargo.toml", "rust", map[string]string{
+		"tokio":     "tokio",
+		"actix-web": "actix",
+		"axum":      "axum",
+		"rocket":    "rocket",
+	}},
+	{"pyproject.toml", "python", pythonFrameworks},
+	{"requirements.txt", "python", pythonFrameworks},
+	{"setup.py", "python", pythonFrameworks},
+	{"Pipfile", "python", pythonFrameworks},
+	{"Gemfile", "ruby", map[string]string{"rails": "rails", "sinatra": "sinatra"}},
+	{"pom.xml", "java", map[string]string{"spring-boot": "spring"}},
+	{"build.gradle", "java", map[string]string{"spring-boot": "spring"}},
+	{"build.gradle.kts", "kotlin", map[string]string{"spring-boot": "spring"}},
+	{"composer.json", "php", map[string]string{"laravel/framework": "laravel", "symfony/": "symfony"}},
+	{"mix.exs", "elixir", map[string]string{":phoenix": "phoenix"}},
+	{"Package.swift", "swift", nil},
+	{"pubspec.yaml", "dart", map[string]string{"flutter:": "flutter"}},
+}
+
+var pythonFrameworks = map[string]string{
+	"django":  "django",
+	"flask":   "flask",
+	"fastapi": "fastapi",
+	"torch":   "pytorch",
+	"pandas":  "pandas",
+}
+
+// tools are files or directories whose presence marks a tool
+var tools = map[string]string{
+	"Dockerfile":          "docker",
+	"docker-compose.yml":  "docker",
+	"docker-compose.yaml": "docker",
+	"compose.yaml":        "docker",
+	".github/workflows":   "github-actions",
+	".gitlab-ci.yml":      "gitlab-ci",
+	"Makefile":            "make",
+	"k8s":                 "kubernetes",
+	"helm":                "kubernetes",
+}
+
+// Files in the root with these extensions mark a language or a tool
+var (
+	languageExtensions = map[string]string{".csproj": "csharp", ".fsproj": "fsharp"}
+	toolExtensions     = map[string]string{".tf": "terraform"}
+)
+
+// Detect inspects the project containing dir: the nearest directory above
+// it holding .git, or dir itself outside a repository. Every directory from
+// dir up to the root is read, so in a monorepo the package being worked on
+// counts as well as the top level.
+func Detect(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	root := gitRoot(dir)
+	if root == "" {
+		root = dir
+	}
+
+	languages := map[string]bool{}
+	frameworks := map[string]bool{}
+	found := map[string]bool{}
+	for _, d := range between(dir, root) {
+		inspect(d, languages, frameworks, found)
+	}
+	// TypeScript is a language, though package.json lists it as a dependency
+	if frameworks["typescript"] {
+		delete(frameworks, "typescript")
+		languages["typescript"] = true
+	}
+
+	return &Project{
+		Root:       root,
+		Languages:  sorted(languages),
+		Frameworks: sorted(frameworks),
+		Tools:      sorted(found),
+		Repo:       repoName(originURL(root)),
+	}, nil
+}
+
+// inspect adds what the files directly in dir show to the sets
+func inspect(dir string, languages, frameworks, found map[string]bool) {
+	for _, m := range manifests {
+		data, err := os.ReadFile(filepath.Join(dir, m.file))
+		if err != nil {
+			continue
+		}
+		languages[m.language] = true
+		content := strings.ToLower(string(data))
+		for needle, framework := range m.frameworks {
+			if strings.Contains(content, strings.ToLower(needle)) {
+				frameworks[framework] = true
+			}
+		}
+	}
+
+	for path, tool := range tools {
+		if _, err := os.Stat(filepath.Join(dir, path)); err == nil {
+			found[tool] = true
+		}
+	}
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			ext := filepath.Ext(entry.Name())
+			if language, ok := languageExtensions[ext]; ok {
+				languages[language] = true
+			}
+			if tool, ok := toolExtensions[ext]; ok {
+				found[tool] = true
+			}
+		}
+	}
+}
+
+// between returns dir and each directory above it up to root
+func between(dir, root string) []string {
+	dirs := []string{dir}
+	for dir != root {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// gitRoot returns the nearest directory at or above dir holding .git
+func gitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func originURL(root string) string {
+	out, err := exec.Command("git", "-C", root, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// repoName returns the repository name from a remote URL such as
+// git@github.com:acme/billing-api.git or https://github.com/acme/billing-api
+func repoName(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return strings.ToLower(url)
+}
+
+func sorted(set map[string]bool) []string {
+	terms := make([]string, 0, len(set))
+	for term := range set {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	return terms
+}
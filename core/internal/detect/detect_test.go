@@ -0,0 +1,58 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                   "module example.com/api\n\nrequire github.com/gin-gonic/gin v1.9.1\n",
+		"web/package.json":         `{"dependencies": {"react": "^18.0.0"}}`,
+		"docs/package.json":        `{"dependencies": {"vue": "^3.0.0"}}`,
+		"Dockerfile":               "FROM golang:1.22\n",
+		"main.tf":                  "",
+		".github/workflows/ci.yml": "on: push\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run from a subdirectory: the project is found at the repository root,
+	// and manifests from there down to the subdirectory are read
+	project, err := Detect(filepath.Join(root, "web"))
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if project.Root != root {
+		t.Errorf("Root = %q, want %q", project.Root, root)
+	}
+	want := []string{"go", "javascript", "gin", "react", "docker", "github-actions", "terraform"}
+	if got := project.Signals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Signals() = %q, want %q", got, want)
+	}
+}
+
+func TestRepoName(t *testing.T) {
+	for url, want := range map[string]string{
+		"git@github.com:acme/Billing-API.git":  "billing-api",
+		"https://github.com/acme/billing-api/": "billing-api",
+		"":                                     "",
+	} {
+		if got := repoName(url); got != want {
+			t.Errorf("repoName(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+// Package diff compares texts line by line for prompt version comparisons,
+// import previews and pack diffs. Within a changed line it finds the words
+// that changed, so they can be highlighted, and it draws the +/- bars of
+// --stat summaries. Merges split two versions into hunks whose conflicts are
+// resolved one by one.
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Operations a line of a diff can have
+const (
+	OpAdded   byte = '+'
+	OpRemoved byte = '-'
+	OpKept    byte = ' '
+	OpNote    byte = 0 // A note about something other than content, such as a new title
+)
+
+// maxWordCells bounds the word comparison of one pair of lines; longer pairs
+// are shown as changed in full
+const maxWordCells = 1 << 20
+
+// Lines returns a line diff of two texts, each line prefixed "+ " when added,
+// "- " when removed, or "  " when kept
+func Lines(before, after string) []string {
+	a, b := split(before), split(after)
+	var diff []string
+	for _, op := range lcs(a, b) {
+		switch op.kind {
+		case OpKept:
+			diff = append(diff, "  "+a[op.i])
+		case OpRemoved:
+			diff = append(diff, "- "+a[op.i])
+		default:
+			diff = append(diff, "+ "+b[op.j])
+		}
+	}
+	return diff
+}
+
+// Count returns how many lines a diff from Lines adds and removes
+func Count(diff []string) (added, removed int) {
+	for _, line := range diff {
+		switch op, _ := parse(line); op {
+		case OpAdded:
+			added++
+		case OpRemoved:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+func split(text string) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// edit is one step of a diff: keep a[i], remove a[i] or add b[j]
+type edit struct {
+	kind byte
+	i, j int
+}
+
+// lcs diffs a and b by their longest common subsequence, removals before
+// additions
+func lcs(a, b []string) []edit {
+	// common[i][j] is the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{OpKept, i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			edits = append(edits, edit{OpRemoved, i, j})
+			i++
+		default:
+			edits = append(edits, edit{OpAdded, i, j})
+			j++
+		}
+	}
+	return edits
+}
+
+// Segment is part of a changed line. Changed segments are the words that
+// are not in the line on the other side.
+type Segment struct {
+	Text    string
+	Changed bool
+}
+
+// Words compares a removed line with the line added in its place word by
+// word. ok is false when the lines have too little in common for the
+// changed words to be worth highlighting, or are too long to compare.
+func Words(before, after string) (removed, added []Segment, ok bool) {
+	a, b := tokens(before), tokens(after)
+	if len(a)*len(b) > maxWordCells {
+		return nil, nil, false
+	}
+
+	kept, words := 0, 0
+	for _, op := range lcs(a, b) {
+		switch op.kind {
+		case OpKept:
+			removed = appendSegment(removed, a[op.i], false)
+			added = appendSegment(added, b[op.j], false)
+			if strings.TrimSpace(a[op.i]) != "" {
+				kept++
+			}
+		case OpRemoved:
+			removed = appendSegment(removed, a[op.i], true)
+		default:
+			added = appendSegment(added, b[op.j], true)
+		}
+	}
+	for _, token := range append(a, b...) {
+		if strings.TrimSpace(token) != "" {
+			words++
+		}
+	}
+	// Lines that share less than half their words were rewritten, not edited
+	if 4*kept < words {
+		return nil, nil, false
+	}
+	return joinPhrases(removed), joinPhrases(added), true
+}
+
+// appendSegment adds text to the last segment when it is changed or kept alike
+func appendSegment(segments []Segment, text string, changed bool) []Segment {
+	if text == "" {
+		return segments
+	}
+	if n := len(segments); n > 0 && segments[n-1].Changed == changed {
+		segments[n-1].Text += text
+		return segments
+	}
+	return append(segments, Segment{Text: text, Changed: changed})
+}
+
+// joinPhrases counts spaces between two changed words as changed, so a
+// changed phrase is highlighted in one piece, and leaves the spaces around
+// a phrase unhighlighted
+func joinPhrases(segments []Segment) []Segment {
+	var joined []Segment
+	for i, segment := range segments {
+		between := i > 0 && i < len(segments)-1 && !segment.Changed && strings.TrimSpace(segment.Text) == ""
+		joined = appendSegment(joined, segment.Text, segment.Changed || between)
+	}
+
+	var out []Segment
+	for _, segment := range joined {
+		if !segment.Changed {
+			out = appendSegment(out, segment.Text, false)
+			continue
+		}
+		phrase := strings.TrimLeftFunc(segment.Text, unicode.IsSpace)
+		out = appendSegment(out, segment.Text[:len(segment.Text)-len(phrase)], false)
+		trimmed := strings.TrimRightFunc(phrase, unicode.IsSpace)
+		out = appendSegment(out, trimmed, true)
+		out = appendSegment(out, phrase[len(trimmed):], false)
+	}
+	return out
+}
+
+// tokens splits a line into words, runs of spaces, and single other characters
+func tokens(line string) []string {
+	var out []string
+	runes := []rune(line)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		switch r := runes[start]; {
+		case isWord(r):
+			for end < len(runes) && isWord(runes[end]) {
+				end++
+			}
+		case unicode.IsSpace(r):
+			for end < len(runes) && unicode.IsSpace(runes[end]) {
+				end++
+			}
+		}
+		out = append(out, string(runes[start:end]))
+		start = end
+	}
+	return out
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Line is a line of a diff ready to be styled
+type Line struct {
+	Op       byte
+	Text     string    // The line without its "+ ", "- " or "  " prefix
+	Segments []Segment // Set on changed lines whose changed words are highlighted
+	Skipped  int       // Set on a gap standing in for this many unchanged lines, see Trim
+}
+
+// Annotate reads a diff from Lines, which may also hold notes, and pairs the
+// lines of each run of removals with the additions that follow it so the
+// words that changed can be highlighted
+func Annotate(diff []string) []Line {
+	lines := make([]Line, len(diff))
+	for i, text := range diff {
+		lines[i].Op, lines[i].Text = parse(text)
+	}
+	for i := 0; i < len(lines); {
+		if lines[i].Op != OpRemoved {
+			i++
+			continue
+		}
+		removedStart := i
+		for i < len(lines) && lines[i].Op == OpRemoved {
+			i++
+		}
+		addedStart := i
+		for i < len(lines) && lines[i].Op == OpAdded {
+			i++
+		}
+		pairs := min(addedStart-removedStart, i-addedStart)
+		for k := 0; k < pairs; k++ {
+			removed, added := &lines[removedStart+k], &lines[addedStart+k]
+			if r, a, ok := Words(removed.Text, added.Text); ok {
+				removed.Segments, added.Segments = r, a
+			}
+		}
+	}
+	return lines
+}
+
+// Clip shortens the text of a line to at most n characters, marking the cut
+// with an ellipsis
+func (l Line) Clip(n int) Line {
+	if n < 1 || len([]rune(l.Text)) <= n {
+		return l
+	}
+	l.Text = string([]rune(l.Text)[:n-1]) + "…"
+	if l.Segments == nil {
+		return l
+	}
+	var clipped []Segment
+	left := n - 1
+	for _, segment := range l.Segments {
+		runes := []rune(segment.Text)
+		if len(runes) >= left {
+			segment.Text = string(runes[:left]) + "…"
+			clipped = append(clipped, segment)
+			break
+		}
+		clipped = append(clipped, segment)
+		left -= len(runes)
+	}
+	l.Segments = clipped
+	return l
+}
+
+// parse splits a line of a diff into its operation and text
+func parse(line string) (byte, string) {
+	if len(line) >= 2 && line[1] == ' ' {
+		switch line[0] {
+		case OpAdded, OpRemoved, OpKept:
+			return line[0], line[2:]
+		}
+	}
+	if line == "" {
+		return OpKept, ""
+	}
+	return OpNote, line
+}
+
+// Trim keeps the changed lines and notes with context unchanged lines around
+// each, replacing every other run of unchanged lines with one gap line
+func Trim(lines []Line, context int) []Line {
+	show := make([]bool, len(lines))
+	for i, line := range lines {
+		if line.Op == OpKept {
+			continue
+		}
+		for j := max(0, i-context); j <= min(len(lines)-1, i+context); j++ {
+			show[j] = true
+		}
+	}
+	var trimmed []Line
+	skipped := 0
+	for i, line := range lines {
+		if !show[i] {
+			skipped++
+			continue
+		}
+		if skipped > 0 {
+			trimmed = append(trimmed, Line{Op: OpKept, Skipped: skipped})
+			skipped = 0
+		}
+		trimmed = append(trimmed, line)
+	}
+	if skipped > 0 {
+		trimmed = append(trimmed, Line{Op: OpKept, Skipped: skipped})
+	}
+	return trimmed
+}
+
+// Style colors the parts of a diff. A nil function leaves its text as is.
+type Style struct {
+	Added, Removed, Kept, Note func(string) string // Whole lines
+	AddedWord, RemovedWord     func(string) string // Changed words in a highlighted line
+	Gap                        func(int) string    // A run of unchanged lines left out by Trim
+}
+
+// Render formats each line with its "+ ", "- " or "  " prefix. Highlighted
+// lines have their changed words styled on top of the line's own style.
+func Render(lines []Line, style Style) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		switch {
+		case line.Skipped > 0:
+			if style.Gap != nil {
+				out[i] = style.Gap(line.Skipped)
+			} else {
+				out[i] = "  ..."
+			}
+		case line.Op == OpNote:
+			out[i] = apply(style.Note, line.Text)
+		case line.Op == OpKept:
+			out[i] = apply(style.Kept, "  "+line.Text)
+		default:
+			lineStyle, wordStyle := style.Added, style.AddedWord
+			if line.Op == OpRemoved {
+				lineStyle, wordStyle = style.Removed, style.RemovedWord
+			}
+			if line.Segments == nil {
+				out[i] = apply(lineStyle, string(line.Op)+" "+line.Text)
+				continue
+			}
+			var b strings.Builder
+			b.WriteString(apply(lineStyle, string(line.Op)+" "))
+			for _, segment := range line.Segments {
+				if segment.Changed {
+					b.WriteString(apply(wordStyle, segment.Text))
+				} else {
+					b.WriteString(apply(lineStyle, segment.Text))
+				}
+			}
+			out[i] = b.String()
+		}
+	}
+	return out
+}
+
+func apply(style func(string) string, text string) string {
+	if style == nil {
+		return text
+	}
+	return style(text)
+}
+
+// Bar draws the pluses and minuses of a --stat line, scaled down to at most
+// width characters when there are more changed lines, keeping at least one
+// of each kind that changed
+func Bar(added, removed, width int) (plus, minus string) {
+	total := added + removed
+	if total > width && width > 0 {
+		scaledAdded := added * width / total
+		scaledRemoved := width - scaledAdded
+		if added > 0 && scaledAdded == 0 {
+			scaledAdded, scaledRemoved = 1, scaledRemoved-1
+		}
+		if removed > 0 && scaledRemoved == 0 {
+			scaledAdded, scaledRemoved = scaledAdded-1, 1
+		}
+		added, removed = scaledAdded, scaledRemoved
+	}
+	return strings.Repeat("+", added), strings.Repeat("-", removed)
+}
@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	got := Lines("one\ntwo\nthree\n", "one\n2\nthree\nfour")
+	want := []string{"  one", "- two", "+ 2", "  three", "+ four"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %q, want %q", got, want)
+	}
+	if added, removed := Count(got); added != 2 || removed != 1 {
+		t.Errorf("Count = +%d/-%d, want +2/-1", added, removed)
+	}
+}
+
+func TestWords(t *testing.T) {
+	removed, added, ok := Words("Review this code for bugs.", "Review this pull request for bugs.")
+	if !ok {
+		t.Fatal("expected the edited line to be highlighted")
+	}
+	wantRemoved := []Segment{{"Review this ", false}, {"code", true}, {" for bugs.", false}}
+	wantAdded := []Segment{{"Review this ", false}, {"pull request", true}, {" for bugs.", false}}
+	if !reflect.DeepEqual(removed, wantRemoved) {
+		t.Errorf("removed = %+v, want %+v", removed, wantRemoved)
+	}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Errorf("added = %+v, want %+v", added, wantAdded)
+	}
+
+	if _, _, ok := Words("Summarize the meeting notes", "List every open question"); ok {
+		t.Error("expected a rewritten line not to be highlighted word by word")
+	}
+}
+
+func TestRender(t *testing.T) {
+	lines := Annotate([]string{`title "A" → "B"`, "  keep", "- old text here", "+ new text here", "+ extra"})
+	style := Style{
+		Added:       func(s string) string { return "<" + s + ">" },
+		AddedWord:   func(s string) string { return "{" + s + "}" },
+		RemovedWord: func(s string) string { return "[" + s + "]" },
+	}
+	got := Render(lines, style)
+	want := []string{
+		`title "A" → "B"`,
+		"  keep",
+		"- [old] text here",
+		"<+ >{new}< text here>",
+		"<+ extra>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Render =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTrim(t *testing.T) {
+	lines := Annotate(Lines("a\nb\nc\nd\ne\nf\ng", "a\nb\nc\nD\ne\nf\ng"))
+	got := Render(Trim(lines, 1), Style{Gap: func(n int) string { return strings.Repeat(".", n) }})
+	want := []string{"..", "  c", "- d", "+ D", "  e", ".."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Trim = %q, want %q", got, want)
+	}
+}
+
+func TestClip(t *testing.T) {
+	line := Annotate([]string{"- Review this code for bugs.", "+ Review this pull request for bugs."})[1]
+	clipped := line.Clip(16)
+	if clipped.Text != "Review this pul…" {
+		t.Errorf("Text = %q", clipped.Text)
+	}
+	want := []Segment{{"Review this ", false}, {"pul…", true}}
+	if !reflect.DeepEqual(clipped.Segments, want) {
+		t.Errorf("Segments = %+v, want %+v", clipped.Segments, want)
+	}
+	if short := line.Clip(80); short.Text != line.Text {
+		t.Errorf("a line that fits was clipped to %q", short.Text)
+	}
+}
+
+func TestBar(t *testing.T) {
+	for _, tc := range []struct {
+		added, removed, width int
+		plus, minus           string
+	}{
+		{3, 1, 20, "+++", "-"},
+		{90, 10, 10, "+++++++++", "-"},
+		{100, 1, 4, "+++", "-"},
+		{0, 8, 4, "", "----"},
+	} {
+		plus, minus := Bar(tc.added, tc.removed, tc.width)
+		if plus != tc.plus || minus != tc.minus {
+			t.Errorf("Bar(%d, %d, %d) = %q %q, want %q %q", tc.added, tc.removed, tc.width, plus, minus, tc.plus, tc.minus)
+		}
+	}
+}
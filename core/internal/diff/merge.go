@@ -0,0 +1,76 @@
+package diff
+
+import "strings"
+
+// Hunk is a run of lines two versions of a text agree on, or a conflict
+// where they differ
+type Hunk struct {
+	Local, Incoming []string // The same lines when the hunk isn't a conflict
+	Conflict        bool
+}
+
+// Pick is the side a conflict is resolved with
+type Pick int
+
+const (
+	PickNone     Pick = iota // Not resolved yet
+	PickLocal                // The local lines
+	PickIncoming             // The incoming lines
+	PickBoth                 // The local lines, then the incoming ones
+)
+
+// Hunks splits two versions of a text into the runs of lines they share and
+// the conflicts between them
+func Hunks(local, incoming string) []Hunk {
+	a, b := split(local), split(incoming)
+	var hunks []Hunk
+	for _, op := range lcs(a, b) {
+		conflict := op.kind != OpKept
+		if n := len(hunks); n == 0 || hunks[n-1].Conflict != conflict {
+			hunks = append(hunks, Hunk{Conflict: conflict})
+		}
+		h := &hunks[len(hunks)-1]
+		switch op.kind {
+		case OpKept:
+			h.Local = append(h.Local, a[op.i])
+			h.Incoming = append(h.Incoming, b[op.j])
+		case OpRemoved:
+			h.Local = append(h.Local, a[op.i])
+		default:
+			h.Incoming = append(h.Incoming, b[op.j])
+		}
+	}
+	return hunks
+}
+
+// Resolve returns the lines the hunk contributes to a merge when pick is
+// chosen for it. Hunks that aren't conflicts contribute their lines whatever
+// the pick; an unresolved conflict contributes none.
+func (h Hunk) Resolve(pick Pick) []string {
+	if !h.Conflict {
+		return h.Local
+	}
+	switch pick {
+	case PickLocal:
+		return h.Local
+	case PickIncoming:
+		return h.Incoming
+	case PickBoth:
+		return append(append([]string(nil), h.Local...), h.Incoming...)
+	}
+	return nil
+}
+
+// Merge joins the hunks into one text, resolving each conflict with the pick
+// at the same index in picks
+func Merge(hunks []Hunk, picks []Pick) string {
+	var lines []string
+	for i, h := range hunks {
+		pick := PickNone
+		if i < len(picks) {
+			pick = picks[i]
+		}
+		lines = append(lines, h.Resolve(pick)...)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,32 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHunks(t *testing.T) {
+	hunks := Hunks("intro\nold rule\nend", "intro\nnew rule\nextra\nend")
+	want := []Hunk{
+		{Local: []string{"intro"}, Incoming: []string{"intro"}},
+		{Local: []string{"old rule"}, Incoming: []string{"new rule", "extra"}, Conflict: true},
+		{Local: []string{"end"}, Incoming: []string{"end"}},
+	}
+	if !reflect.DeepEqual(hunks, want) {
+		t.Fatalf("Hunks = %+v, want %+v", hunks, want)
+	}
+
+	for _, tc := range []struct {
+		pick Pick
+		want string
+	}{
+		{PickLocal, "intro\nold rule\nend"},
+		{PickIncoming, "intro\nnew rule\nextra\nend"},
+		{PickBoth, "intro\nold rule\nnew rule\nextra\nend"},
+		{PickNone, "intro\nend"},
+	} {
+		if got := Merge(hunks, []Pick{PickNone, tc.pick, PickNone}); got != tc.want {
+			t.Errorf("Merge with pick %d = %q, want %q", tc.pick, got, tc.want)
+		}
+	}
+}
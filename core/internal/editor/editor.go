@@ -0,0 +1,216 @@
+// Package editor serves a small line protocol for editor plugins on stdin
+// and stdout, so an editor can start pkt once and keep asking it for prompts
+// instead of running a command for every keystroke.
+//
+// Each request is one line of JSON with an id the response echoes:
+//
+//	{"id": 1, "method": "search", "params": {"query": "review", "limit": 20}}
+//	{"id": 2, "method": "get", "params": {"id": "code-review"}}
+//	{"id": 3, "method": "render", "params": {"id": "code-review", "variables": {"language": "go"}}}
+//
+// and each response is one line with either a result or an error:
+//
+//	{"id": 3, "result": {"text": "Review this go code..."}}
+//	{"id": 4, "error": "prompt not found: nope"}
+//
+// Requests are answered in order. The connection ends when stdin closes.
+package editor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// ProtocolVersion is reported by ping, for plugins to check compatibility
+const ProtocolVersion = 1
+
+// maxLine bounds a request line; render variables can hold whole files
+const maxLine = 4 << 20
+
+// Request is one line from the editor
+type Request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response answers a request with a result or an error, never both
+type Response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// PromptSummary is a search result, enough to show in a picker
+type PromptSummary struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// PromptDetail is a prompt with its content and the variables render needs
+type PromptDetail struct {
+	PromptSummary
+	Content   string     `json:"content"`
+	Variables []Variable `json:"variables,omitempty"`
+}
+
+// Variable is a placeholder the editor can ask the user to fill
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Sensitive   bool   `json:"sensitive,omitempty"` // Ask without echoing the value
+}
+
+// Serve answers requests read from in on out until in is closed
+func Serve(svc *service.Service, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxLine)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp.ID = req.ID
+			result, err := handle(svc, req)
+			if err != nil {
+				resp.Error = err.Error()
+			} else {
+				resp.Result = result
+			}
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle runs one request
+func handle(svc *service.Service, req Request) (interface{}, error) {
+	switch req.Method {
+	case "ping":
+		return map[string]int{"protocol": ProtocolVersion}, nil
+
+	case "search":
+		var params struct {
+			Query string `json:"query"`
+			Limit int    `json:"limit"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		prompts, err := svc.SearchPrompts(params.Query)
+		if err != nil {
+			return nil, err
+		}
+		if params.Limit > 0 && len(prompts) > params.Limit {
+			prompts = prompts[:params.Limit]
+		}
+		results := make([]PromptSummary, 0, len(prompts))
+		for _, p := range prompts {
+			results = append(results, summarize(p))
+		}
+		return results, nil
+
+	case "get":
+		var params struct {
+			ID string `json:"id"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.ID == "" {
+			return nil, fmt.Errorf("get requires an id")
+		}
+		prompt, err := svc.GetPrompt(params.ID)
+		if err != nil {
+			return nil, err
+		}
+		var template *models.Template
+		if prompt.TemplateRef != "" {
+			template, _ = svc.GetTemplate(prompt.TemplateRef)
+		}
+		detail := PromptDetail{PromptSummary: summarize(prompt), Content: prompt.Content}
+		for _, v := range svc.DeclaredVariables(prompt, template) {
+			detail.Variables = append(detail.Variables, Variable{
+				Name:        v.Name,
+				Description: v.Description,
+				Type:        v.Type,
+				Required:    v.Required,
+				Default:     v.Default,
+				Sensitive:   v.Sensitive,
+			})
+		}
+		return detail, nil
+
+	case "render":
+		var params struct {
+			ID            string                 `json:"id"`
+			Profile       string                 `json:"profile"`
+			Variables     map[string]interface{} `json:"variables"`
+			Dir           string                 `json:"dir"`            // Directory context placeholders such as {{git.branch}} are filled from
+			AllowCommands bool                   `json:"allow_commands"` // Run {{cmd:...}} placeholders
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.ID == "" {
+			return nil, fmt.Errorf("render requires an id")
+		}
+		// The editor belongs to the library's owner, so secrets may be read,
+		// but there is no terminal to ask for missing ones
+		text, err := svc.RenderPrompt(params.ID, service.RenderOptions{
+			Images:      renderer.ImagePath,
+			Profile:     params.Profile,
+			Variables:   params.Variables,
+			ReadSecrets: true,
+			Context:     &renderer.Context{Dir: params.Dir, AllowCommands: params.AllowCommands},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"text": text}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown method %q (expected ping, search, get or render)", req.Method)
+	}
+}
+
+func decodeParams(raw json.RawMessage, params interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, params); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
+
+func summarize(p *models.Prompt) PromptSummary {
+	return PromptSummary{
+		ID:          p.ID,
+		Title:       p.Title(),
+		Description: p.Summary,
+		Tags:        p.Tags,
+	}
+}
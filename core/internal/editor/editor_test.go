@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestServe(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-editor-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := service.OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{
+		ID:        "review",
+		Name:      "Code review",
+		Version:   "1.0.0",
+		Content:   "Review this {{language}} code",
+		Variables: []models.Variable{{Name: "language", Required: true}},
+	}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	in := strings.Join([]string{
+		`{"id": 1, "method": "search", "params": {"query": "review"}}`,
+		`{"id": 2, "method": "get", "params": {"id": "review"}}`,
+		``,
+		`{"id": 3, "method": "render", "params": {"id": "review", "variables": {"language": "Go"}}}`,
+		`{"id": 4, "method": "render", "params": {"id": "missing"}}`,
+		`not json`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := Serve(svc, strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d responses, want 5:\n%s", len(lines), out.String())
+	}
+	var responses []map[string]interface{}
+	for _, line := range lines {
+		var resp map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("response %q is not JSON: %v", line, err)
+		}
+		responses = append(responses, resp)
+	}
+
+	if results, _ := responses[0]["result"].([]interface{}); len(results) != 1 {
+		t.Errorf("search result = %v, want one prompt", responses[0]["result"])
+	}
+	detail, _ := responses[1]["result"].(map[string]interface{})
+	if variables, _ := detail["variables"].([]interface{}); len(variables) != 1 {
+		t.Errorf("get result = %v, want the language variable", detail)
+	}
+	if rendered, _ := responses[2]["result"].(map[string]interface{}); rendered["text"] != "Review this Go code" {
+		t.Errorf("render result = %v", responses[2]["result"])
+	}
+	if responses[3]["id"] != float64(4) || responses[3]["error"] == nil {
+		t.Errorf("render of a missing prompt = %v, want an error for id 4", responses[3])
+	}
+	if responses[4]["error"] == nil {
+		t.Errorf("invalid line answered with %v, want an error", responses[4])
+	}
+}
@@ -33,6 +33,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"time"
 )
@@ -57,6 +58,8 @@ const (
 	// Resource errors
 	ErrCodeNotFound        ErrorCode = "NOT_FOUND"
 	ErrCodeAlreadyExists   ErrorCode = "ALREADY_EXISTS"
+	ErrCodeVersionConflict ErrorCode = "VERSION_CONFLICT"
+	ErrCodeAmbiguousID     ErrorCode = "AMBIGUOUS_ID"
 	ErrCodePermissionDenied ErrorCode = "PERMISSION_DENIED"
 	ErrCodeQuotaExceeded    ErrorCode = "QUOTA_EXCEEDED"
 
@@ -81,6 +84,7 @@ const (
 	ErrCodeCommandNotFound ErrorCode = "COMMAND_NOT_FOUND"
 	ErrCodeCommandFailed   ErrorCode = "COMMAND_FAILED"
 	ErrCodeInvalidCommand  ErrorCode = "INVALID_COMMAND"
+	ErrCodeMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
 
 	// Git sync errors
 	ErrCodeGitFailure      ErrorCode = "GIT_FAILURE"
@@ -204,7 +208,7 @@ func categorizeError(code ErrorCode) (ErrorCategory, ErrorSeverity) {
 	// Resource errors
 	case ErrCodeNotFound:
 		return CategoryService, SeverityInfo
-	case ErrCodeAlreadyExists:
+	case ErrCodeAlreadyExists, ErrCodeVersionConflict, ErrCodeAmbiguousID:
 		return CategoryService, SeverityWarning
 	case ErrCodePermissionDenied, ErrCodeQuotaExceeded:
 		return CategoryService, SeverityError
@@ -230,6 +234,8 @@ func categorizeError(code ErrorCode) (ErrorCategory, ErrorSeverity) {
 		return CategoryCommand, SeverityInfo
 	case ErrCodeCommandFailed, ErrCodeInvalidCommand:
 		return CategoryCommand, SeverityError
+	case ErrCodeMethodNotAllowed:
+		return CategoryCommand, SeverityWarning
 
 	// Git sync errors
 	case ErrCodeGitFailure, ErrCodeGitConflict:
@@ -262,7 +268,8 @@ func IsAppError(err error) bool {
 
 // GetAppError extracts an AppError from an error, or converts it to one
 func GetAppError(err error) *AppError {
-	if appErr, ok := err.(*AppError); ok {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
 		return appErr
 	}
 	return Wrap(err, ErrCodeInternalError, "Internal error occurred")
@@ -44,6 +44,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
 // ErrorHandler provides interface-specific error handling
@@ -77,9 +78,20 @@ func (h *CLIErrorHandler) HandleError(err error) error {
 	}
 	
 	// Return formatted error for display
-	return fmt.Errorf(h.FormatError(appErr))
+	return &formattedError{text: h.FormatError(appErr), err: appErr}
 }
 
+// formattedError is an AppError formatted for display. Unwrap keeps the
+// AppError, and its severity, reachable with errors.As.
+type formattedError struct {
+	text string
+	err  *AppError
+}
+
+func (e *formattedError) Error() string { return e.text }
+
+func (e *formattedError) Unwrap() error { return e.err }
+
 // FormatError formats an error for CLI display
 func (h *CLIErrorHandler) FormatError(err error) string {
 	appErr := GetAppError(err)
@@ -124,27 +136,62 @@ func (h *HTTPErrorHandler) HandleError(err error) error {
 	return appErr
 }
 
-// FormatError formats an error for HTTP response
-func (h *HTTPErrorHandler) FormatError(err error) string {
+// ErrorResponse is the body of every HTTP error response. It shares success
+// and timestamp with the API's success envelope.
+type ErrorResponse struct {
+	Success   bool      `json:"success"` // Always false
+	Error     ErrorBody `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ErrorBody describes an error to HTTP clients, which branch on Code or
+// Category rather than on Message
+type ErrorBody struct {
+	Code      ErrorCode              `json:"code"`
+	Category  ErrorCategory          `json:"category"`
+	Severity  ErrorSeverity          `json:"severity"`
+	Message   string                 `json:"message"`
+	Details   string                 `json:"details,omitempty"`
+	Retryable bool                   `json:"retryable"` // The same request may succeed later
+	Context   map[string]interface{} `json:"context,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// NewErrorResponse builds the response body for err. Category, severity and
+// retryability default from the code when err does not set them, as for
+// errors rebuilt from a command result.
+func (h *HTTPErrorHandler) NewErrorResponse(err error) ErrorResponse {
 	appErr := GetAppError(err)
-	
-	response := map[string]interface{}{
-		"error": map[string]interface{}{
-			"code":      appErr.Code,
-			"message":   appErr.Message,
-			"timestamp": appErr.Timestamp,
-		},
+	category, severity := categorizeError(appErr.Code)
+
+	body := ErrorBody{
+		Code:      appErr.Code,
+		Category:  appErr.Category,
+		Severity:  appErr.Severity,
+		Message:   appErr.Message,
+		Retryable: appErr.Retryable || isRetryable(appErr.Code),
+		Timestamp: appErr.Timestamp,
 	}
-	
-	if h.IncludeDetails && appErr.Details != "" {
-		response["error"].(map[string]interface{})["details"] = appErr.Details
+	if body.Category == "" {
+		body.Category = category
 	}
-	
-	if h.IncludeDetails && appErr.Context != nil {
-		response["error"].(map[string]interface{})["context"] = appErr.Context
+	if body.Severity == "" {
+		body.Severity = severity
 	}
-	
-	jsonBytes, _ := json.Marshal(response)
+	if body.Timestamp.IsZero() {
+		body.Timestamp = time.Now()
+	}
+	if h.IncludeDetails {
+		body.Details = appErr.Details
+		body.Context = appErr.Context
+	}
+
+	return ErrorResponse{Error: body, Timestamp: body.Timestamp}
+}
+
+// FormatError formats an error for HTTP response
+func (h *HTTPErrorHandler) FormatError(err error) string {
+	jsonBytes, _ := json.Marshal(h.NewErrorResponse(err))
 	return string(jsonBytes)
 }
 
@@ -171,10 +218,12 @@ func (h *HTTPErrorHandler) getHTTPStatusCode(appErr *AppError) int {
 	switch appErr.Code {
 	case ErrCodeValidation, ErrCodeInvalidInput, ErrCodeMissingField, ErrCodeInvalidFormat:
 		return http.StatusBadRequest
-	case ErrCodeNotFound, ErrCodeFileNotFound:
+	case ErrCodeNotFound, ErrCodeFileNotFound, ErrCodeCommandNotFound:
 		return http.StatusNotFound
-	case ErrCodeAlreadyExists:
+	case ErrCodeAlreadyExists, ErrCodeAmbiguousID:
 		return http.StatusConflict
+	case ErrCodeVersionConflict:
+		return http.StatusPreconditionFailed
 	case ErrCodeUnauthorized, ErrCodeInvalidToken, ErrCodeTokenExpired:
 		return http.StatusUnauthorized
 	case ErrCodePermissionDenied, ErrCodeAccessDenied:
@@ -187,6 +236,8 @@ func (h *HTTPErrorHandler) getHTTPStatusCode(appErr *AppError) int {
 		return http.StatusGatewayTimeout
 	case ErrCodeNotImplemented:
 		return http.StatusNotImplemented
+	case ErrCodeMethodNotAllowed:
+		return http.StatusMethodNotAllowed
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,343 @@
+// Package federation searches several prompt libraries at once: the local
+// library plus any registered sources, which are other library directories,
+// read-only mirrors of public git repositories, or running pocket-prompt
+// servers. Results are labelled with the source they came from and merged by
+// rank.
+package federation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/client"
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/git"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// Library is a prompt library that can be listed and searched
+type Library interface {
+	List(ctx context.Context) ([]*models.Prompt, error)
+	Search(ctx context.Context, query string) ([]*models.Prompt, error)
+	Get(ctx context.Context, id string) (*models.Prompt, error)
+}
+
+// Federation holds the local library and the registered sources, in order
+type Federation struct {
+	names   []string
+	sources map[string]config.SourceConfig
+	local   Library
+
+	mu   sync.Mutex
+	open map[string]Library
+}
+
+// New federates the local service with the given sources
+func New(local *service.Service, sources []config.SourceConfig) *Federation {
+	f := &Federation{
+		names:   []string{config.LocalSourceName},
+		sources: make(map[string]config.SourceConfig, len(sources)),
+		local:   serviceLibrary{local},
+		open:    make(map[string]Library),
+	}
+	for _, source := range sources {
+		f.names = append(f.names, source.Name)
+		f.sources[source.Name] = source
+	}
+	return f
+}
+
+// Sources returns the source names, starting with the local library
+func (f *Federation) Sources() []string {
+	return append([]string(nil), f.names...)
+}
+
+// Resolve expands a selection into source names: empty or "all" means every
+// source, otherwise each name must be registered
+func (f *Federation) Resolve(selected []string) ([]string, error) {
+	if len(selected) == 0 || (len(selected) == 1 && selected[0] == config.AllSourcesName) {
+		return f.Sources(), nil
+	}
+	for _, name := range selected {
+		if _, ok := f.sources[name]; !ok && name != config.LocalSourceName {
+			return nil, fmt.Errorf("unknown source %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// List returns every prompt in the named sources
+func (f *Federation) List(ctx context.Context, names []string) ([]*models.Prompt, []error) {
+	return f.gather(ctx, names, func(lib Library) ([]*models.Prompt, error) {
+		return lib.List(ctx)
+	})
+}
+
+// Search runs query against the named sources
+func (f *Federation) Search(ctx context.Context, names []string, query string) ([]*models.Prompt, []error) {
+	return f.gather(ctx, names, func(lib Library) ([]*models.Prompt, error) {
+		return lib.Search(ctx, query)
+	})
+}
+
+// Get loads a single prompt, with its content, from the named source
+func (f *Federation) Get(ctx context.Context, source, id string) (*models.Prompt, error) {
+	lib, err := f.library(source)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := lib.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return label([]*models.Prompt{prompt}, source)[0], nil
+}
+
+// gather queries the sources concurrently. A failing source is reported in
+// the errors and left out of the results rather than failing the whole call.
+func (f *Federation) gather(ctx context.Context, names []string, query func(Library) ([]*models.Prompt, error)) ([]*models.Prompt, []error) {
+	results := make([][]*models.Prompt, len(names))
+	errs := make([]error, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			lib, err := f.library(name)
+			if err == nil {
+				results[i], err = query(lib)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", name, err)
+				return
+			}
+			results[i] = label(results[i], name)
+		}(i, name)
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return merge(results), failed
+}
+
+// library opens the named source on first use
+func (f *Federation) library(name string) (Library, error) {
+	if name == config.LocalSourceName {
+		return f.local, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if lib, ok := f.open[name]; ok {
+		return lib, nil
+	}
+	source, ok := f.sources[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q", name)
+	}
+	lib, err := Open(source)
+	if err != nil {
+		return nil, err
+	}
+	f.open[name] = lib
+	return lib, nil
+}
+
+// Open connects to a registered source
+func Open(source config.SourceConfig) (Library, error) {
+	if source.URL != "" {
+		apiKey := ""
+		if source.APIKeyEnv != "" {
+			apiKey = os.Getenv(source.APIKeyEnv)
+		}
+		c, err := client.New(source.URL, apiKey)
+		if err != nil {
+			return nil, err
+		}
+		return clientLibrary{c}, nil
+	}
+
+	if source.Mirror != "" {
+		dir, err := git.MirrorDir(source.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &mirrorLibrary{source: source, dir: dir}, nil
+	}
+
+	svc, err := service.OpenLibrary(source.Path)
+	if err != nil {
+		return nil, err
+	}
+	return serviceLibrary{svc}, nil
+}
+
+// label copies prompts with their source set, leaving cached prompts untouched
+func label(prompts []*models.Prompt, source string) []*models.Prompt {
+	labelled := make([]*models.Prompt, len(prompts))
+	for i, p := range prompts {
+		copied := *p
+		copied.Source = source
+		labelled[i] = &copied
+	}
+	return labelled
+}
+
+// merge interleaves per-source results by rank, so the best match from every
+// source comes before the second-best match from any of them
+func merge(results [][]*models.Prompt) []*models.Prompt {
+	var merged []*models.Prompt
+	for rank := 0; ; rank++ {
+		added := false
+		for _, prompts := range results {
+			if rank < len(prompts) {
+				merged = append(merged, prompts[rank])
+				added = true
+			}
+		}
+		if !added {
+			return merged
+		}
+	}
+}
+
+type serviceLibrary struct {
+	svc *service.Service
+}
+
+func (l serviceLibrary) List(ctx context.Context) ([]*models.Prompt, error) {
+	return l.svc.ListPrompts()
+}
+
+func (l serviceLibrary) Search(ctx context.Context, query string) ([]*models.Prompt, error) {
+	return l.svc.SearchPrompts(query)
+}
+
+func (l serviceLibrary) Get(ctx context.Context, id string) (*models.Prompt, error) {
+	return l.svc.GetPrompt(id)
+}
+
+// mirrorLibrary reads a mirror clone, refreshing it from upstream once the
+// source's interval has passed. Nothing is ever written to the clone besides
+// the refresh itself, which discards local changes.
+type mirrorLibrary struct {
+	source config.SourceConfig
+	dir    string
+
+	mu        sync.Mutex
+	svc       *service.Service
+	nextCheck time.Time
+}
+
+// current refreshes the clone when it is due and returns a service over it.
+// A failed refresh keeps serving the last good copy until the next interval.
+func (l *mirrorLibrary) current() (*service.Service, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	refreshedAt, cloned := git.MirrorRefreshedAt(l.dir)
+	interval := l.source.RefreshInterval()
+	if (!cloned || time.Since(refreshedAt) >= interval) && time.Now().After(l.nextCheck) {
+		err := git.RefreshMirror(l.dir, l.source.Mirror, l.source.Branch)
+		switch {
+		case err != nil && !cloned:
+			return nil, fmt.Errorf("failed to clone mirror: %w", err)
+		case err != nil:
+			l.nextCheck = time.Now().Add(interval)
+		default:
+			l.svc = nil
+		}
+	}
+
+	if l.svc == nil {
+		svc, err := service.OpenLibrary(l.dir)
+		if err != nil {
+			return nil, err
+		}
+		l.svc = svc
+	}
+	return l.svc, nil
+}
+
+func (l *mirrorLibrary) List(ctx context.Context) ([]*models.Prompt, error) {
+	svc, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListPrompts()
+}
+
+func (l *mirrorLibrary) Search(ctx context.Context, query string) ([]*models.Prompt, error) {
+	svc, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	return svc.SearchPrompts(query)
+}
+
+func (l *mirrorLibrary) Get(ctx context.Context, id string) (*models.Prompt, error) {
+	svc, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetPrompt(id)
+}
+
+type clientLibrary struct {
+	client *client.Client
+}
+
+func (l clientLibrary) List(ctx context.Context) ([]*models.Prompt, error) {
+	return l.run(ctx, "list", nil)
+}
+
+func (l clientLibrary) Search(ctx context.Context, query string) ([]*models.Prompt, error) {
+	return l.run(ctx, "search", map[string]interface{}{"query": query})
+}
+
+func (l clientLibrary) Get(ctx context.Context, id string) (*models.Prompt, error) {
+	data, err := l.execute(ctx, "get", map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	prompt, ok := data.(*models.Prompt)
+	if !ok {
+		return nil, fmt.Errorf("prompt not found: %s", id)
+	}
+	return prompt, nil
+}
+
+func (l clientLibrary) run(ctx context.Context, command string, params map[string]interface{}) ([]*models.Prompt, error) {
+	data, err := l.execute(ctx, command, params)
+	if err != nil {
+		return nil, err
+	}
+	prompts, _ := data.([]*models.Prompt)
+	return prompts, nil
+}
+
+// execute runs a command on the server, turning command failures into errors
+func (l clientLibrary) execute(ctx context.Context, command string, params map[string]interface{}) (interface{}, error) {
+	result, err := l.client.Execute(ctx, command, params)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		if result.Error != nil {
+			return nil, fmt.Errorf("%s", result.Error.Message)
+		}
+		return nil, fmt.Errorf("%s failed", command)
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,36 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestMergeInterleavesByRank(t *testing.T) {
+	local := label([]*models.Prompt{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}}, "local")
+	team := label([]*models.Prompt{{ID: "b1"}}, "team")
+
+	merged := merge([][]*models.Prompt{local, team})
+
+	want := []string{"local/a1", "team/b1", "local/a2", "local/a3"}
+	if len(merged) != len(want) {
+		t.Fatalf("merged %d prompts, want %d", len(merged), len(want))
+	}
+	for i, p := range merged {
+		if got := p.Source + "/" + p.ID; got != want[i] {
+			t.Fatalf("merged[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestLabelDoesNotModifyOriginal(t *testing.T) {
+	original := &models.Prompt{ID: "a1"}
+	labelled := label([]*models.Prompt{original}, "team")
+
+	if original.Source != "" {
+		t.Fatalf("original prompt was labelled %q", original.Source)
+	}
+	if labelled[0].Source != "team" || labelled[0].Title() != "[team] a1" {
+		t.Fatalf("labelled prompt = %q titled %q", labelled[0].Source, labelled[0].Title())
+	}
+}
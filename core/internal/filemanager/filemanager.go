@@ -0,0 +1,43 @@
+// Package filemanager shows files in the operating system's file manager
+package filemanager
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// start runs a command without waiting for it, so a file manager left open
+// does not hold up the caller
+var start = func(name string, args ...string) error {
+	return exec.Command(name, args...).Start()
+}
+
+// Reveal opens the file manager at path's folder with path selected, where
+// the platform's file manager can select files, or at the folder otherwise
+func Reveal(path string) error {
+	name, args, err := revealCommand(runtime.GOOS, path)
+	if err != nil {
+		return err
+	}
+	if err := start(name, args...); err != nil {
+		return fmt.Errorf("failed to open the file manager: %w", err)
+	}
+	return nil
+}
+
+// revealCommand is the command that reveals path on goos
+func revealCommand(goos, path string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "open", []string{"-R", path}, nil
+	case "windows":
+		return "explorer", []string{"/select," + path}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		// xdg-open cannot select a file, so it opens the folder
+		return "xdg-open", []string{filepath.Dir(path)}, nil
+	default:
+		return "", nil, fmt.Errorf("revealing files is not supported on %s", goos)
+	}
+}
@@ -0,0 +1,37 @@
+package filemanager
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRevealCommand(t *testing.T) {
+	path := filepath.Join("lib", "prompts", "review.md")
+	tests := map[string][]string{
+		"darwin":  {"open", "-R", path},
+		"windows": {"explorer", "/select," + path},
+		"linux":   {"xdg-open", filepath.Join("lib", "prompts")},
+	}
+	for goos, want := range tests {
+		name, args, err := revealCommand(goos, path)
+		if err != nil {
+			t.Fatalf("%s: %v", goos, err)
+		}
+		if got := append([]string{name}, args...); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: command = %q, want %q", goos, got, want)
+		}
+	}
+	if _, _, err := revealCommand("plan9", path); err == nil {
+		t.Error("Expected an error on a platform without a known file manager")
+	}
+}
+
+func TestRevealReportsFailure(t *testing.T) {
+	defer func(original func(string, ...string) error) { start = original }(start)
+	start = func(string, ...string) error { return errors.New("not installed") }
+	if err := Reveal(filepath.Join("lib", "review.md")); err == nil {
+		t.Error("Expected a file manager that fails to start to be reported")
+	}
+}
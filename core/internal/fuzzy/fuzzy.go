@@ -0,0 +1,129 @@
+// Package fuzzy matches search queries against prompt text regardless of
+// accents and Unicode normalization, so "resume" finds "résumé" whether it
+// was typed precomposed or decomposed. Matching itself is sahilm/fuzzy's.
+package fuzzy
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sahilm/fuzzy"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Match is one matching target
+type Match struct {
+	Index          int   // Position of the target in the searched slice
+	MatchedIndexes []int // Rune positions in the target that matched, for highlighting
+}
+
+// Find returns the targets query matches, best first. Both sides are folded
+// with Fold before matching.
+func Find(query string, targets []string) []Match {
+	folded := make([]foldedText, len(targets))
+	texts := make([]string, len(targets))
+	for i, target := range targets {
+		folded[i] = foldWithOrigin(target)
+		texts[i] = folded[i].text
+	}
+
+	matches := fuzzy.Find(Fold(query), texts)
+	results := make([]Match, len(matches))
+	for i, m := range matches {
+		results[i] = Match{Index: m.Index, MatchedIndexes: folded[m.Index].originRunes(m.MatchedIndexes)}
+	}
+	return results
+}
+
+// Fold strips accents and other diacritics, expands letters such as ß and æ,
+// and turns full-width forms into their usual ones. Scripts that are not
+// written with diacritics, such as CJK, pass through unchanged.
+func Fold(s string) string {
+	return foldWithOrigin(s).text
+}
+
+// foldedText is folded text with, for each of its runes, the position of the
+// rune in the original string it came from
+type foldedText struct {
+	text   string
+	origin []int
+}
+
+func foldWithOrigin(s string) foldedText {
+	var b strings.Builder
+	origin := make([]int, 0, len(s))
+	i := 0
+	for _, r := range s {
+		for _, f := range foldRune(r) {
+			b.WriteRune(f)
+			origin = append(origin, i)
+		}
+		i++
+	}
+	return foldedText{text: b.String(), origin: origin}
+}
+
+// originRunes maps byte offsets in the folded text, as sahilm/fuzzy reports
+// them, to rune positions in the original string
+func (f foldedText) originRunes(byteOffsets []int) []int {
+	runes := make([]int, 0, len(byteOffsets))
+	for _, offset := range byteOffsets {
+		pos := utf8.RuneCountInString(f.text[:offset])
+		if pos >= len(f.origin) {
+			continue
+		}
+		original := f.origin[pos]
+		// Expanded letters such as ß→ss match twice at one position
+		if n := len(runes); n == 0 || runes[n-1] != original {
+			runes = append(runes, original)
+		}
+	}
+	return runes
+}
+
+// expansions fold letters that have no decomposition
+var expansions = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L",
+	'đ': "d", 'Đ': "D",
+	'ħ': "h", 'Ħ': "H",
+	'ı': "i",
+	'þ': "th", 'Þ': "TH",
+}
+
+// foldRune returns the folded form of r: its decomposition without
+// combining diacritical marks, or r itself
+func foldRune(r rune) string {
+	if r < utf8.RuneSelf {
+		return string(r)
+	}
+	if isDiacritic(r) {
+		return "" // A mark left over from decomposed input
+	}
+	if expanded, ok := expansions[r]; ok {
+		return expanded
+	}
+	if r >= 0xFF00 && r <= 0xFFEF {
+		return norm.NFKC.String(string(r)) // Full- and half-width forms
+	}
+
+	decomposed := norm.NFKD.String(string(r))
+	if !strings.ContainsFunc(decomposed, isDiacritic) {
+		return string(r)
+	}
+	return strings.Map(func(c rune) rune {
+		if isDiacritic(c) {
+			return -1
+		}
+		return c
+	}, decomposed)
+}
+
+// isDiacritic reports whether r is in the Combining Diacritical Marks block,
+// the accents of Latin, Greek and Cyrillic letters
+func isDiacritic(r rune) bool {
+	return r >= 0x0300 && r <= 0x036F
+}
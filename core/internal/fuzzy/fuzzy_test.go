@@ -0,0 +1,93 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"Résumé":             "Resume",
+		"re\u0301sume\u0301": "resume", // decomposed input
+		"Straße":             "Strasse",
+		"Ærøskøbing":         "AEroskobing",
+		"Łódź":               "Lodz",
+		"Ｐｒｏｍｐｔ":             "Prompt",
+		"日本語のプロンプト":          "日本語のプロンプト",
+		"한국어":                "한국어",
+		"が":                  "が",
+	}
+	for input, want := range tests {
+		if got := Fold(input); got != want {
+			t.Errorf("Fold(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestFindIgnoresAccents(t *testing.T) {
+	targets := []string{"Cover letter", "Résumé review", "Café menu"}
+
+	matches := Find("resume", targets)
+	if len(matches) != 1 || matches[0].Index != 1 {
+		t.Fatalf("Find(resume) = %+v, want only target 1", matches)
+	}
+	if want := []int{0, 1, 2, 3, 4, 5}; !slices.Equal(matches[0].MatchedIndexes, want) {
+		t.Errorf("MatchedIndexes = %v, want %v", matches[0].MatchedIndexes, want)
+	}
+
+	if matches := Find("café", targets); len(matches) != 1 || matches[0].Index != 2 {
+		t.Errorf("Find(café) = %+v, want only target 2", matches)
+	}
+}
+
+func TestFindReportsRunePositions(t *testing.T) {
+	matches := Find("翻訳", []string{"英語から日本語へ翻訳する"})
+	if len(matches) != 1 {
+		t.Fatalf("Find(翻訳) = %+v, want one match", matches)
+	}
+	if want := []int{8, 9}; !slices.Equal(matches[0].MatchedIndexes, want) {
+		t.Errorf("MatchedIndexes = %v, want rune positions %v", matches[0].MatchedIndexes, want)
+	}
+
+	matches = Find("strasse", []string{"Straße"})
+	if len(matches) != 1 {
+		t.Fatalf("Find(strasse) = %+v, want one match", matches)
+	}
+	if want := []int{0, 1, 2, 3, 4, 5}; !slices.Equal(matches[0].MatchedIndexes, want) {
+		t.Errorf("MatchedIndexes = %v, want %v", matches[0].MatchedIndexes, want)
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	ids := []string{"code-review", "code-reveiw-go", "commit-message", "résumé-tailor", "review"}
+
+	if got, want := Similar("code-reviw", ids, 3), []string{"code-review", "code-reveiw-go"}; !slices.Equal(got, want) {
+		t.Errorf("Similar(code-reviw) = %q, want %q", got, want)
+	}
+	if got, want := Similar("review", ids, 2), []string{"review", "code-review"}; !slices.Equal(got, want) {
+		t.Errorf("Similar(review) = %q, want %q", got, want)
+	}
+	if got := Similar("resume-taylor", ids, 3); !slices.Equal(got, []string{"résumé-tailor"}) {
+		t.Errorf("Similar(resume-taylor) = %q, want the accented ID", got)
+	}
+	if got := Similar("translate", ids, 3); len(got) != 0 {
+		t.Errorf("Similar(translate) = %q, want nothing", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"Code Review", "code review", 0},
+		{"Résumé", "resume", 0},
+		{"Summarize text", "Summarise text", 1},
+		{"review", "reveiw", 1},
+		{"review", "rewrite", 4},
+	} {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Similar returns up to limit targets that look like a mistyped query, the
+// closest first: those within a few typos of it and those containing it.
+// Case and accents are ignored, as in Find.
+func Similar(query string, targets []string, limit int) []string {
+	q := []rune(strings.ToLower(Fold(query)))
+	if len(q) == 0 {
+		return nil
+	}
+	// A typo in every third letter is still recognisable; more is a different word
+	maxDistance := len(q)/3 + 1
+
+	type candidate struct {
+		target   string
+		distance int
+	}
+	var candidates []candidate
+	for _, target := range targets {
+		t := []rune(strings.ToLower(Fold(target)))
+		d := distance(q, t)
+		if d > maxDistance {
+			if !strings.Contains(string(t), string(q)) {
+				continue
+			}
+			// Containing the query is a match however much longer the target is
+			d = maxDistance + len(t) - len(q)
+		}
+		candidates = append(candidates, candidate{target, d})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].target < candidates[j].target
+	})
+
+	var similar []string
+	for _, c := range candidates {
+		if len(similar) == limit {
+			break
+		}
+		similar = append(similar, c.target)
+	}
+	return similar
+}
+
+// Distance counts the typos that turn a into b, ignoring case and accents
+// as in Find
+func Distance(a, b string) int {
+	return distance([]rune(strings.ToLower(Fold(a))), []rune(strings.ToLower(Fold(b))))
+}
+
+// distance counts the insertions, deletions, substitutions and swaps of
+// neighbouring letters that turn a into b
+func distance(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
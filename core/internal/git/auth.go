@@ -0,0 +1,108 @@
+package git
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultTokenUser is sent with a token when no user name is configured. GitHub
+// accepts it for any token; GitLab expects "oauth2".
+const DefaultTokenUser = "x-access-token"
+
+// Auth holds credentials for the library's origin remote
+type Auth struct {
+	SSHKey    string // Path to a private key for SSH remotes
+	Token     string // Token for HTTPS remotes
+	TokenUser string // User name sent with the token (default: DefaultTokenUser)
+	Source    string // Where Token came from, for TestAuth to report
+}
+
+// ConfigureAuth sets up the environment that every git command in this
+// process inherits. The library is trusted even when another user owns it, as
+// a mounted volume often is, and auth's credentials are used for the remote.
+// The token is only sent to the origin remote's host.
+func (g *GitSync) ConfigureAuth(auth Auth) error {
+	g.auth = auth
+	addGitConfig("safe.directory", g.baseDir)
+
+	if auth.SSHKey != "" {
+		key, err := privateKeyFile(auth.SSHKey)
+		if err != nil {
+			return err
+		}
+		os.Setenv("GIT_SSH_COMMAND", "ssh -i "+shellQuote(key)+" -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new")
+	}
+
+	return g.configureToken()
+}
+
+// configureToken sends the token to the origin remote's host, when there is
+// a token and an HTTPS remote
+func (g *GitSync) configureToken() error {
+	if g.auth.Token == "" {
+		return nil
+	}
+	remote, err := g.getRemoteURL()
+	if err != nil {
+		return nil // Nothing to authenticate to yet
+	}
+	u, err := url.Parse(remote)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil // SSH remotes use the key
+	}
+	user := g.auth.TokenUser
+	if user == "" {
+		user = DefaultTokenUser
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(user + ":" + g.auth.Token))
+	addGitConfig(fmt.Sprintf("http.%s://%s/.extraHeader", u.Scheme, u.Host), "Authorization: Basic "+credentials)
+	return nil
+}
+
+// addGitConfig adds a setting through GIT_CONFIG_COUNT, which git (2.31 and
+// later) reads as if it were given with -c
+func addGitConfig(key, value string) {
+	n, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", n), key)
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", n), value)
+	os.Setenv("GIT_CONFIG_COUNT", strconv.Itoa(n+1))
+}
+
+// privateKeyFile returns a path to the key that ssh will accept. Mounted
+// secrets are often readable by everyone, which ssh refuses, so such keys are
+// copied to a file only this user can read.
+func privateKeyFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("git SSH key: %w", err)
+	}
+	if info.Mode().Perm()&0077 == 0 {
+		return path, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("git SSH key: %w", err)
+	}
+	copied, err := os.CreateTemp("", "pkt-ssh-key-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to copy git SSH key: %w", err)
+	}
+	defer copied.Close()
+	if err := copied.Chmod(0600); err != nil {
+		return "", fmt.Errorf("failed to copy git SSH key: %w", err)
+	}
+	if _, err := copied.Write(data); err != nil {
+		return "", fmt.Errorf("failed to copy git SSH key: %w", err)
+	}
+	return copied.Name(), nil
+}
+
+// shellQuote quotes s for the shell that runs GIT_SSH_COMMAND
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
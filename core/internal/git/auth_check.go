@@ -0,0 +1,206 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Time limits on requests to the remote: each check TestAuth makes, and the
+// first fetch and push of SetupRepository, which may transfer a whole library
+const (
+	authCheckTimeout = 20 * time.Second
+	setupTimeout     = 10 * time.Minute
+)
+
+// AuthError explains why the origin remote refused the library's credentials,
+// with a hint at how to fix it
+type AuthError struct {
+	Reason string // e.g. "token lacks repo scope"
+	Hint   string
+}
+
+func (e *AuthError) Error() string {
+	if e.Hint == "" {
+		return "auth failed: " + e.Reason
+	}
+	return fmt.Sprintf("auth failed: %s\n\n%s", e.Reason, e.Hint)
+}
+
+// AuthReport is what TestAuth found out about reaching the origin remote
+type AuthReport struct {
+	Remote string `json:"remote"` // Origin URL, without any credentials in it
+	Method string `json:"method"` // How git authenticates, e.g. "SSH key ~/.ssh/deploy"
+	Read   bool   `json:"read"`   // Fetching works
+	Write  bool   `json:"write"`  // Pushing is allowed
+	Error  string `json:"error,omitempty"`
+	// WriteSkipped says why pushing was not checked, such as a library
+	// without commits to push
+	WriteSkipped string `json:"write_skipped,omitempty"`
+}
+
+// TestAuth checks that the origin remote accepts the library's credentials,
+// first for reading and then, with a dry-run push, for writing. Git is never
+// left waiting for a password or passphrase. A refusal is reported as an
+// AuthError in the report rather than returned; the error is for a library
+// without a remote.
+func (g *GitSync) TestAuth() (*AuthReport, error) {
+	remote, err := g.getRemoteURL()
+	if err != nil {
+		return nil, fmt.Errorf("no origin remote; set one up with 'pkt git setup <url>'")
+	}
+	report := &AuthReport{Remote: redactURL(remote), Method: g.authMethod(remote)}
+
+	if out, err := g.remoteCommand(authCheckTimeout, "ls-remote", "--heads", "origin"); err != nil {
+		report.Error = g.describeRemoteFailure(out, err).Error()
+		return report, nil
+	}
+	report.Read = true
+
+	if !g.hasCommits() {
+		report.WriteSkipped = "the library has no commits to push yet"
+		return report, nil
+	}
+	branch := g.getCurrentBranch()
+	if out, err := g.remoteCommand(authCheckTimeout, "push", "--dry-run", "--porcelain", "origin", "HEAD:refs/heads/"+branch); err != nil {
+		// A rejected update (non-fast-forward) still means we may push
+		if !strings.Contains(out, "[rejected]") {
+			report.Error = g.describeRemoteFailure(out, err).Error()
+			return report, nil
+		}
+	}
+	report.Write = true
+	return report, nil
+}
+
+// remoteCommand runs a git command that talks to the remote, failing rather
+// than prompting for credentials, and returns its combined output
+func (g *GitSync) remoteCommand(timeout time.Duration, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.baseDir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+	sshCommand := os.Getenv("GIT_SSH_COMMAND")
+	if sshCommand == "" {
+		sshCommand = "ssh"
+	}
+	cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND="+sshCommand+" -o BatchMode=yes")
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return string(out), fmt.Errorf("git %s timed out after %v", args[0], timeout)
+	}
+	return string(out), err
+}
+
+// authMethod describes how git will authenticate to remote
+func (g *GitSync) authMethod(remote string) string {
+	u, err := url.Parse(remote)
+	switch {
+	case err == nil && (u.Scheme == "https" || u.Scheme == "http"):
+		if g.auth.Token != "" {
+			return "token from " + g.auth.Source
+		}
+		if u.User != nil {
+			return "credentials in the remote URL"
+		}
+		if helper, err := g.gitOutput("config", "credential.helper"); err == nil && helper != "" {
+			return "credential helper " + helper
+		}
+		return "none (set git.token_env, git.token_file or git.token_keychain)"
+	case isSSHRemote(remote):
+		if g.auth.SSHKey != "" {
+			return "SSH key " + g.auth.SSHKey
+		}
+		if os.Getenv("SSH_AUTH_SOCK") != "" {
+			return "SSH agent"
+		}
+		return "default SSH keys in ~/.ssh"
+	}
+	return "local path"
+}
+
+// isSSHRemote reports whether remote is reached over SSH: ssh:// URLs and
+// scp-like addresses such as git@github.com:user/repo.git
+func isSSHRemote(remote string) bool {
+	if strings.HasPrefix(remote, "ssh://") || strings.HasPrefix(remote, "git+ssh://") {
+		return true
+	}
+	host, _, found := strings.Cut(remote, ":")
+	return found && !strings.Contains(host, "/") && !strings.Contains(remote, "://") && len(host) > 1
+}
+
+// redactURL removes any password or token from a remote URL
+func redactURL(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil {
+		return remote
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	} else {
+		u.User = url.User("xxxxx")
+	}
+	return u.String()
+}
+
+// describeRemoteFailure turns what git printed when the remote refused it
+// into an AuthError that says why, or the git error when it was not refused
+// for its credentials
+func (g *GitSync) describeRemoteFailure(out string, err error) error {
+	lower := strings.ToLower(out)
+	has := func(phrases ...string) bool {
+		for _, phrase := range phrases {
+			if strings.Contains(lower, phrase) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case has("permission denied (publickey"):
+		return &AuthError{
+			Reason: "SSH key was refused",
+			Hint:   "Add the public key to your account or as a deploy key, load it into ssh-agent, or pick another with 'pkt git auth ssh-key <path>'.",
+		}
+	case has("host key verification failed"):
+		return &AuthError{
+			Reason: "SSH host key is not trusted",
+			Hint:   "Connect once with ssh to accept the host key, or add it to ~/.ssh/known_hosts.",
+		}
+	case has("could not read username", "terminal prompts disabled", "could not read password") && g.auth.Token == "":
+		return &AuthError{
+			Reason: "no credentials for the HTTPS remote",
+			Hint:   "Give git sync a personal access token with 'pkt git auth token --env <VAR>', --file <path> or --keychain <service/account>.",
+		}
+	case has("write access to repository not granted", "the requested url returned error: 403", "permission to", "http 403"):
+		return &AuthError{
+			Reason: "token lacks repo scope",
+			Hint:   "The token was accepted but may not use this repository. Create one with the repo scope (or contents: write for fine-grained tokens).",
+		}
+	case has("authentication failed", "invalid username or password", "http basic: access denied", "the requested url returned error: 401",
+		"could not read username", "terminal prompts disabled", "could not read password"):
+		// Git asks for credentials again when the token it sent was refused
+		return &AuthError{
+			Reason: "token was rejected",
+			Hint:   "The token is wrong or has expired. Create a new one and store it where git.token_env, token_file or token_keychain points.",
+		}
+	case has("repository not found", "does not appear to be a git repository", "the requested url returned error: 404"):
+		return &AuthError{
+			Reason: "repository not found, or the credentials cannot see it",
+			Hint:   "Check the remote URL. Private repositories need a token with the repo scope, or a key with access to them.",
+		}
+	case has("could not resolve host", "connection timed out", "connection refused", "couldn't connect", "network is unreachable"):
+		line, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+		return fmt.Errorf("cannot reach the remote: %s", line)
+	}
+	if strings.TrimSpace(out) == "" {
+		return err
+	}
+	return fmt.Errorf("%s", strings.TrimSpace(out))
+}
@@ -0,0 +1,195 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrGHNotFound is returned by OpenPullRequest when the GitHub CLI is not installed
+var ErrGHNotFound = errors.New("gh CLI not found")
+
+// PullRequest is a pull request from the working branch into the main branch
+type PullRequest struct {
+	URL     string
+	Created bool // false when a pull request for the branch was already open
+}
+
+// workingBranchKey is the local git config key holding the working branch. It
+// lives in .git/config rather than the library config because it belongs to
+// one clone, not to everyone sharing the library.
+const workingBranchKey = "pocket-prompt.workingBranch"
+
+// SetMainBranch sets the branch pull requests target; empty detects it from
+// the remote
+func (g *GitSync) SetMainBranch(name string) {
+	g.mainBranch = name
+}
+
+// WorkingBranch returns the branch edits are synced to, or "" when sync uses
+// the checked-out branch, as built-in sync always does
+func (g *GitSync) WorkingBranch() string {
+	if g.builtin || !g.isGitInitialized() {
+		return ""
+	}
+	branch, _ := g.gitOutput("config", "--get", workingBranchKey)
+	return branch
+}
+
+// SetWorkingBranch makes sync commit and push edits to name instead of
+// whatever is checked out, so they reach the main branch through pull
+// requests. An empty name goes back to syncing the checked-out branch.
+func (g *GitSync) SetWorkingBranch(name string) error {
+	if name == "" {
+		if g.WorkingBranch() == "" {
+			return nil
+		}
+		return g.runGitCommand("config", "--unset", workingBranchKey)
+	}
+	if err := g.runGitCommand("check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	return g.runGitCommand("config", workingBranchKey, name)
+}
+
+// MainBranch returns the shared branch pull requests target: the configured
+// one, else the remote's default branch, else main or master
+func (g *GitSync) MainBranch() string {
+	if g.mainBranch != "" {
+		return g.mainBranch
+	}
+	if ref, err := g.gitOutput("symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil && ref != "" {
+		return strings.TrimPrefix(ref, "origin/")
+	}
+	if !g.BranchExists("main") && g.BranchExists("master") {
+		return "master"
+	}
+	return "main"
+}
+
+// CheckoutWorkingBranch switches to the working branch if one is configured,
+// creating it from the remote copy of the branch or of the main branch.
+// Uncommitted changes are carried over.
+func (g *GitSync) CheckoutWorkingBranch() error {
+	branch := g.WorkingBranch()
+	if branch == "" || g.getCurrentBranch() == branch {
+		return nil
+	}
+
+	switch {
+	case g.BranchExists(branch):
+		return g.runGitCommand("checkout", branch)
+	case g.remoteBranchExists(branch):
+		return g.runGitCommand("checkout", "-b", branch, "--track", "origin/"+branch)
+	case g.remoteBranchExists(g.MainBranch()):
+		return g.runGitCommand("checkout", "--no-track", "-b", branch, "origin/"+g.MainBranch())
+	default:
+		return g.runGitCommand("checkout", "-b", branch)
+	}
+}
+
+// PushBranch pushes the current branch to origin, setting its upstream
+func (g *GitSync) PushBranch() error {
+	return g.runGitCommandWithTimeout(time.Minute, "push", "-u", "origin", g.getCurrentBranch())
+}
+
+// push sends new commits upstream. On a working branch the upstream is set
+// explicitly, since a freshly created branch has none yet.
+func (g *GitSync) push() error {
+	if g.builtin {
+		return g.builtinPush()
+	}
+	if g.WorkingBranch() != "" {
+		return g.PushBranch()
+	}
+	return g.runGitCommand("push")
+}
+
+// mergeMainBranch keeps the working branch current with changes others have
+// landed on the main branch, which must already be fetched
+func (g *GitSync) mergeMainBranch() error {
+	working := g.WorkingBranch()
+	if working == "" || g.getCurrentBranch() != working {
+		return nil
+	}
+	if !g.remoteBranchExists(g.MainBranch()) {
+		return nil
+	}
+	upstream := "origin/" + g.MainBranch()
+	if exec.Command("git", "-C", g.baseDir, "merge-base", "--is-ancestor", upstream, "HEAD").Run() == nil {
+		return nil // Already up to date with main
+	}
+	if err := g.runGitCommand("merge", "--no-edit", upstream); err != nil {
+		g.runGitCommand("merge", "--abort")
+		return fmt.Errorf("failed to merge %s into %s: %w", upstream, working, err)
+	}
+	return nil
+}
+
+func (g *GitSync) remoteBranchExists(name string) bool {
+	return g.runGitCommand("rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+name) == nil
+}
+
+// OpenPullRequest opens a pull request from the current branch into the main
+// branch with the gh CLI, or returns the one already open. The branch must
+// have been pushed. Without gh it returns ErrGHNotFound.
+func (g *GitSync) OpenPullRequest(title, body string, draft bool) (*PullRequest, error) {
+	head := g.getCurrentBranch()
+	base := g.MainBranch()
+	if head == base {
+		return nil, fmt.Errorf("on the main branch %s; set a working branch first", base)
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, ErrGHNotFound
+	}
+
+	if url, err := g.ghOutput("pr", "view", head, "--json", "url,state", "--jq", `select(.state == "OPEN") | .url`); err == nil && url != "" {
+		return &PullRequest{URL: url}, nil
+	}
+
+	args := []string{"pr", "create", "--base", base, "--head", head}
+	if title != "" {
+		args = append(args, "--title", title, "--body", body)
+	} else {
+		args = append(args, "--fill")
+	}
+	if draft {
+		args = append(args, "--draft")
+	}
+	output, err := g.ghOutput(args...)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(output, "\n")
+	return &PullRequest{URL: strings.TrimSpace(lines[len(lines)-1]), Created: true}, nil
+}
+
+// githubRemote matches the owner/repo part of SSH and HTTPS GitHub remotes
+var githubRemote = regexp.MustCompile(`github\.com[:/]([^/]+/[^/]+?)(\.git)?$`)
+
+// CompareURL returns the GitHub page for opening a pull request from the
+// current branch by hand, or "" when origin is not on GitHub
+func (g *GitSync) CompareURL() string {
+	remote, err := g.getRemoteURL()
+	if err != nil {
+		return ""
+	}
+	match := githubRemote.FindStringSubmatch(strings.TrimSpace(remote))
+	if match == nil {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/compare/%s...%s?expand=1", match[1], g.MainBranch(), g.getCurrentBranch())
+}
+
+func (g *GitSync) ghOutput(args ...string) (string, error) {
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = g.baseDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("gh %s failed: %s", args[0]+" "+args[1], strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
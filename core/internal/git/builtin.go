@@ -0,0 +1,515 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// ErrDiverged is returned by built-in sync when the library and its remote
+// have both changed the same files, which only git can merge
+var ErrDiverged = errors.New("the library and its remote have diverged; install git to merge them")
+
+// builtinTimeout bounds each fetch, pull and push of built-in sync
+const builtinTimeout = 30 * time.Second
+
+// UseBuiltin makes sync use the git implementation built into pkt instead
+// of the git program, for systems without git such as minimal containers.
+// It commits every change, pulls, merging changes to different files, pushes,
+// and reports what a pull changed. Merging changes to the same file, working
+// branches, signed commits, history, review branches, Git LFS and sparse
+// checkouts still need git.
+func (g *GitSync) UseBuiltin() {
+	g.builtin = true
+}
+
+// Builtin reports whether sync uses the built-in git implementation
+func (g *GitSync) Builtin() bool {
+	return g.builtin
+}
+
+// builtinHasRemote reports whether the origin remote is configured
+func (g *GitSync) builtinHasRemote() bool {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return false
+	}
+	_, err = repo.Remote("origin")
+	return err == nil
+}
+
+// builtinHead returns the hash of the checked-out commit, or "" when the
+// library has no commits
+func (g *GitSync) builtinHead() string {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
+
+// builtinBranch returns the checked-out branch, falling back to master as
+// getCurrentBranch does
+func (g *GitSync) builtinBranch() string {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return "master"
+	}
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil || head.Type() != plumbing.SymbolicReference {
+		return "master" // Detached HEAD
+	}
+	return head.Target().Short()
+}
+
+// builtinAddAll stages every change in the library, as git add -A does.
+// Paths in .git/info/exclude, where stageAll keeps .pktignore patterns and
+// device files, are skipped.
+func (g *GitSync) builtinAddAll() error {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.AddWithOptions(&gogit.AddOptions{All: true})
+}
+
+// builtinHasStaged reports whether there are staged changes to commit
+func (g *GitSync) builtinHasStaged() (bool, error) {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return false, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return false, err
+	}
+	for _, file := range status {
+		if file.Staging != gogit.Unmodified && file.Staging != gogit.Untracked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// builtinCommit commits the staged changes, see builtinAuthor for by whom
+func (g *GitSync) builtinCommit(message string) error {
+	if g.identity.SigningKey != "" {
+		return fmt.Errorf("signing commits needs git installed")
+	}
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	_, err = worktree.Commit(message, &gogit.CommitOptions{Author: g.builtinAuthor()})
+	if errors.Is(err, gogit.ErrMissingAuthor) {
+		return fmt.Errorf("no commit author: set git.author_name and git.author_email")
+	}
+	return err
+}
+
+// builtinAuthor returns the configured commit author, or nil for go-git to
+// read user.name and user.email from the git configuration as git would
+func (g *GitSync) builtinAuthor() *object.Signature {
+	if g.identity.Name == "" || g.identity.Email == "" {
+		return nil
+	}
+	return &object.Signature{Name: g.identity.Name, Email: g.identity.Email, When: time.Now()}
+}
+
+// builtinPull brings in the changes on the checked-out branch's copy on
+// origin, fast-forwarding when it can and merging otherwise
+func (g *GitSync) builtinPull() error {
+	defer tracing.Begin("git pull")()
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	auth, err := g.builtinAuth(repo)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), builtinTimeout)
+	defer cancel()
+	err = worktree.PullContext(ctx, &gogit.PullOptions{
+		RemoteName:    "origin",
+		ReferenceName: plumbing.NewBranchReferenceName(g.builtinBranch()),
+		SingleBranch:  true,
+		Auth:          auth,
+	})
+	switch {
+	case err == nil, errors.Is(err, gogit.NoErrAlreadyUpToDate):
+		return nil
+	case errors.Is(err, transport.ErrEmptyRemoteRepository), errors.Is(err, plumbing.ErrReferenceNotFound):
+		return nil // Nothing pushed to the remote branch yet
+	case errors.Is(err, gogit.ErrNonFastForwardUpdate):
+		return g.builtinMerge(repo, worktree)
+	default:
+		return fmt.Errorf("failed to pull: %w", err)
+	}
+}
+
+// builtinMerge merges the fetched copy of the checked-out branch into it
+// with a merge commit, when the two sides changed different files. Changes
+// to the same file are refused with ErrDiverged, as are uncommitted ones.
+func (g *GitSync) builtinMerge(repo *gogit.Repository, worktree *gogit.Worktree) error {
+	status, err := worktree.Status()
+	if err != nil {
+		return err
+	}
+	if !status.IsClean() {
+		return ErrDiverged
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	remote, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", g.builtinBranch()), true)
+	if err != nil {
+		return err
+	}
+	local, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return err
+	}
+	upstream, err := repo.CommitObject(remote.Hash())
+	if err != nil {
+		return err
+	}
+	bases, err := local.MergeBase(upstream)
+	if err != nil || len(bases) == 0 {
+		return ErrDiverged
+	}
+
+	ours, err := changedPaths(bases[0], local)
+	if err != nil {
+		return err
+	}
+	theirs, err := changedPaths(bases[0], upstream)
+	if err != nil {
+		return err
+	}
+	for path := range theirs {
+		if ours[path] {
+			return ErrDiverged
+		}
+	}
+
+	// Write their side's files over ours, then commit both as parents
+	tree, err := upstream.Tree()
+	if err != nil {
+		return err
+	}
+	for path := range theirs {
+		target := filepath.Join(g.baseDir, filepath.FromSlash(path))
+		file, err := tree.File(path)
+		if errors.Is(err, object.ErrFileNotFound) {
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		mode := os.FileMode(0644)
+		if file.Mode == filemode.Executable {
+			mode = 0755
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, []byte(content), mode); err != nil {
+			return err
+		}
+	}
+	if err := worktree.AddWithOptions(&gogit.AddOptions{All: true}); err != nil {
+		return err
+	}
+
+	opts := &gogit.CommitOptions{Author: g.builtinAuthor(), Parents: []plumbing.Hash{local.Hash, upstream.Hash}}
+	message := fmt.Sprintf("Merge branch '%s' of origin", g.builtinBranch())
+	if _, err := worktree.Commit(message, opts); err != nil {
+		return fmt.Errorf("failed to commit merge: %w", err)
+	}
+	return nil
+}
+
+// changedPaths returns the paths that differ between two commits, both the
+// old and new path of a rename
+func changedPaths(from, to *object.Commit) (map[string]bool, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]bool)
+	for _, change := range changes {
+		if change.From.Name != "" {
+			paths[change.From.Name] = true
+		}
+		if change.To.Name != "" {
+			paths[change.To.Name] = true
+		}
+	}
+	return paths, nil
+}
+
+// builtinPush pushes the checked-out branch to origin
+func (g *GitSync) builtinPush() error {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return err
+	}
+	auth, err := g.builtinAuth(repo)
+	if err != nil {
+		return err
+	}
+
+	branch := plumbing.NewBranchReferenceName(g.builtinBranch())
+	ctx, cancel := context.WithTimeout(context.Background(), builtinTimeout)
+	defer cancel()
+	err = repo.PushContext(ctx, &gogit.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(branch + ":" + branch)},
+		Auth:       auth,
+	})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		if errors.Is(err, gogit.ErrNonFastForwardUpdate) {
+			return ErrDiverged
+		}
+		return err
+	}
+	return nil
+}
+
+// commit commits the staged changes with message
+func (g *GitSync) commit(message string) error {
+	if g.builtin {
+		return g.builtinCommit(message)
+	}
+	return g.runGitCommand("commit", "-m", message)
+}
+
+// builtinFetch fetches origin without changing the checked-out branch
+func (g *GitSync) builtinFetch() error {
+	defer tracing.Begin("git fetch")()
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return err
+	}
+	auth, err := g.builtinAuth(repo)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), builtinTimeout)
+	defer cancel()
+	err = repo.FetchContext(ctx, &gogit.FetchOptions{RemoteName: "origin", Auth: auth})
+	if err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return err
+	}
+	return nil
+}
+
+// builtinAheadBehind compares the checked-out commit with the last fetched
+// copy of its branch on origin: ahead when it has commits the remote lacks,
+// behind when the remote has commits it lacks. Both are false when the
+// remote branch does not exist yet.
+func (g *GitSync) builtinAheadBehind() (ahead, behind bool, err error) {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return false, false, err
+	}
+	remote, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", g.builtinBranch()), true)
+	if err != nil {
+		return false, false, nil
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return false, false, err
+	}
+	if remote.Hash() == head.Hash() {
+		return false, false, nil
+	}
+
+	local, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, false, err
+	}
+	upstream, err := repo.CommitObject(remote.Hash())
+	if err != nil {
+		return false, false, err
+	}
+	localInRemote, err := local.IsAncestor(upstream)
+	if err != nil {
+		return false, false, err
+	}
+	remoteInLocal, err := upstream.IsAncestor(local)
+	if err != nil {
+		return false, false, err
+	}
+	return !localInRemote, !remoteInLocal, nil
+}
+
+// builtinChangedFiles lists the files that differ between commit from and
+// the checked-out commit, with renames detected, as ChangedFiles does
+func (g *GitSync) builtinChangedFiles(from string) ([]FileChange, error) {
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	fromTree, err := commitTree(repo, plumbing.NewHash(from))
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := commitTree(repo, head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := object.DiffTreeWithOptions(context.Background(), fromTree, headTree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, err
+	}
+	var changes []FileChange
+	for _, change := range diff {
+		switch {
+		case change.From.Name == "":
+			changes = append(changes, FileChange{Status: "A", Path: change.To.Name})
+		case change.To.Name == "":
+			changes = append(changes, FileChange{Status: "D", Path: change.From.Name})
+		case change.From.Name != change.To.Name:
+			changes = append(changes, FileChange{Status: "R", Path: change.To.Name, OldPath: change.From.Name})
+		default:
+			changes = append(changes, FileChange{Status: "M", Path: change.To.Name})
+		}
+	}
+	return changes, nil
+}
+
+// commitTree returns the tree of the commit with hash
+func commitTree(repo *gogit.Repository, hash plumbing.Hash) (*object.Tree, error) {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", hash, err)
+	}
+	return commit.Tree()
+}
+
+// builtinStatus summarizes the library's sync state as getDetailedStatus does
+func (g *GitSync) builtinStatus() (string, error) {
+	if !g.builtinHasRemote() {
+		return "No remote configured", nil
+	}
+	ahead, behind, err := g.builtinAheadBehind()
+	if err != nil {
+		return "Git status unknown", err
+	}
+	switch {
+	case ahead:
+		return "Changes need to be pushed", nil
+	case behind:
+		return "Remote has new changes", nil
+	}
+
+	repo, err := gogit.PlainOpen(g.baseDir)
+	if err != nil {
+		return "Git status unknown", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "Git status unknown", err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return "Git status unknown", err
+	}
+	if !status.IsClean() {
+		return "Uncommitted changes", nil
+	}
+	return "In sync", nil
+}
+
+// builtinAuth returns the configured credentials for the origin remote: the
+// token for an HTTPS remote, the SSH key for any other. Without either,
+// go-git falls back to the SSH agent.
+func (g *GitSync) builtinAuth(repo *gogit.Repository) (transport.AuthMethod, error) {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return nil, err
+	}
+	var remoteURL string
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		remoteURL = urls[0]
+	}
+	u, err := url.Parse(remoteURL)
+	isHTTP := err == nil && (u.Scheme == "https" || u.Scheme == "http")
+
+	switch {
+	case isHTTP && g.auth.Token != "":
+		user := g.auth.TokenUser
+		if user == "" {
+			user = DefaultTokenUser
+		}
+		return &http.BasicAuth{Username: user, Password: g.auth.Token}, nil
+	case !isHTTP && g.auth.SSHKey != "":
+		keys, err := ssh.NewPublicKeysFromFile("git", g.auth.SSHKey, "")
+		if err != nil {
+			return nil, fmt.Errorf("git SSH key: %w", err)
+		}
+		return keys, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,156 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+)
+
+// builtinLibrary opens dir, a clone of remote, for built-in sync
+func builtinLibrary(t *testing.T, dir string) *GitSync {
+	t.Helper()
+	g := NewGitSync(dir)
+	g.UseBuiltin()
+	if err := g.ConfigureIdentity(Identity{Name: "Test", Email: "test@example.com"}); err != nil {
+		t.Fatalf("ConfigureIdentity: %v", err)
+	}
+	if err := g.Initialize(); err != nil || !g.IsEnabled() {
+		t.Fatalf("Initialize = %v, enabled %v; want built-in sync enabled", err, g.IsEnabled())
+	}
+	return g
+}
+
+func writeFile(t *testing.T, dir, path, content string) {
+	t.Helper()
+	target := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, dir, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestBuiltinSync(t *testing.T) {
+	remote := t.TempDir()
+	if _, err := gogit.PlainInit(remote, true); err != nil {
+		t.Fatalf("init remote: %v", err)
+	}
+	dirA := t.TempDir()
+	repo, err := gogit.PlainInit(dirA, false)
+	if err != nil {
+		t.Fatalf("init library: %v", err)
+	}
+	if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{remote}}); err != nil {
+		t.Fatalf("add remote: %v", err)
+	}
+	a := builtinLibrary(t, dirA)
+
+	// The first sync commits and pushes, leaving device files out
+	writeFile(t, dirA, "prompts/one.md", "one")
+	writeFile(t, dirA, ".pocket-prompt/device", "laptop")
+	if err := a.SyncCommit("Add one"); err != nil {
+		t.Fatalf("SyncCommit: %v", err)
+	}
+	if status, err := a.GetStatus(); err != nil || status != "In sync" {
+		t.Errorf("status = %q, %v; want in sync", status, err)
+	}
+
+	dirB := t.TempDir()
+	if _, err := gogit.PlainClone(dirB, false, &gogit.CloneOptions{URL: remote}); err != nil {
+		t.Fatalf("clone: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dirB, ".pocket-prompt/device")); !os.IsNotExist(err) {
+		t.Errorf("device file was committed: %v", err)
+	}
+	b := builtinLibrary(t, dirB)
+	if b.HeadCommit() != a.HeadCommit() || b.HeadCommit() == "" {
+		t.Fatalf("clone head %q, library head %q", b.HeadCommit(), a.HeadCommit())
+	}
+
+	// A pull fast-forwards and reports what it brought
+	writeFile(t, dirA, "prompts/one.md", "one, edited")
+	writeFile(t, dirA, "prompts/two.md", "two")
+	if err := a.SyncCommit("Edit one, add two"); err != nil {
+		t.Fatalf("SyncCommit: %v", err)
+	}
+	before := b.HeadCommit()
+	if err := b.PullChanges(); err != nil {
+		t.Fatalf("PullChanges: %v", err)
+	}
+	changes, err := b.ChangedFiles(before)
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	if len(changes) != 2 || changes[0] != (FileChange{Status: "M", Path: "prompts/one.md"}) || changes[1] != (FileChange{Status: "A", Path: "prompts/two.md"}) {
+		t.Errorf("changes = %+v, want one.md modified and two.md added", changes)
+	}
+	if got := readFile(t, dirB, "prompts/one.md"); got != "one, edited" {
+		t.Errorf("one.md = %q after the pull", got)
+	}
+
+	// Changes to different files on both sides are merged
+	writeFile(t, dirA, "prompts/two.md", "two, edited")
+	if err := a.SyncCommit("Edit two"); err != nil {
+		t.Fatalf("SyncCommit: %v", err)
+	}
+	writeFile(t, dirB, "prompts/three.md", "three")
+	if committed, err := b.CommitChanges("Add three"); err != nil || !committed {
+		t.Fatalf("CommitChanges = %v, %v", committed, err)
+	}
+	if err := b.PullChanges(); err != nil {
+		t.Fatalf("PullChanges after both sides changed: %v", err)
+	}
+	if got := readFile(t, dirB, "prompts/two.md"); got != "two, edited" {
+		t.Errorf("two.md = %q after the merge", got)
+	}
+	if got := readFile(t, dirB, "prompts/three.md"); got != "three" {
+		t.Errorf("three.md = %q after the merge", got)
+	}
+	merged, err := gogit.PlainOpen(dirB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := merged.Head()
+	if commit, err := merged.CommitObject(head.Hash()); err != nil || commit.NumParents() != 2 {
+		t.Errorf("head after the merge = %v, %v; want a merge commit", commit, err)
+	}
+	if err := b.push(); err != nil {
+		t.Fatalf("push after the merge: %v", err)
+	}
+	if err := a.PullChanges(); err != nil {
+		t.Fatalf("PullChanges of the merge: %v", err)
+	}
+	if got := readFile(t, dirA, "prompts/three.md"); got != "three" {
+		t.Errorf("three.md = %q after pulling the merge", got)
+	}
+
+	// Changes to the same file need git
+	writeFile(t, dirA, "prompts/one.md", "one, from a")
+	if err := a.SyncCommit("Edit one on a"); err != nil {
+		t.Fatalf("SyncCommit: %v", err)
+	}
+	writeFile(t, dirB, "prompts/one.md", "one, from b")
+	if _, err := b.CommitChanges("Edit one on b"); err != nil {
+		t.Fatalf("CommitChanges: %v", err)
+	}
+	if err := b.PullChanges(); !errors.Is(err, ErrDiverged) {
+		t.Errorf("PullChanges = %v, want ErrDiverged", err)
+	}
+	if got := readFile(t, dirB, "prompts/one.md"); got != "one, from b" {
+		t.Errorf("one.md = %q after a refused merge, want the local edit kept", got)
+	}
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileChange is a file that differs between two commits
+type FileChange struct {
+	Status  string // A (added), M (modified), D (deleted) or R (renamed)
+	Path    string // The file's path in the newer commit, or the deleted path
+	OldPath string // The path before a rename
+}
+
+// HeadCommit returns the hash of the checked-out commit, or "" when the
+// library has no commits
+func (g *GitSync) HeadCommit() string {
+	if g.builtin {
+		return g.builtinHead()
+	}
+	if !g.isGitInitialized() || !g.hasCommits() {
+		return ""
+	}
+	hash, err := g.gitOutput("rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// ChangedFiles lists the files that differ between commit from and the
+// checked-out commit, as git diff --name-status reports them, with renames
+// detected
+func (g *GitSync) ChangedFiles(from string) ([]FileChange, error) {
+	if g.builtin {
+		return g.builtinChangedFiles(from)
+	}
+	output, err := g.gitOutput("diff", "--name-status", "-z", "-M", from, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s failed: %w", from, err)
+	}
+	return parseNameStatus(output), nil
+}
+
+// parseNameStatus reads git diff --name-status -z output: a status, then
+// the path, or the old and new paths of a rename or copy, each ending in NUL.
+// Copies are reported as additions and type changes as modifications.
+func parseNameStatus(output string) []FileChange {
+	var changes []FileChange
+	fields := strings.Split(output, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status := fields[i]
+		if status == "" {
+			break
+		}
+		change := FileChange{Status: status[:1], Path: fields[i+1]}
+		switch change.Status {
+		case "R", "C":
+			if i+2 >= len(fields) {
+				return changes
+			}
+			i++
+			change.OldPath, change.Path = change.Path, fields[i+1]
+			if change.Status == "C" {
+				change.Status, change.OldPath = "A", ""
+			}
+		case "T":
+			change.Status = "M"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
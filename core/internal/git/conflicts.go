@@ -0,0 +1,50 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictedFiles returns the files git could not merge, relative to the
+// library root
+func (g *GitSync) ConflictedFiles() ([]string, error) {
+	if !g.isGitInitialized() {
+		return nil, nil
+	}
+	output, err := g.gitOutput("diff", "--name-only", "--diff-filter=U", "--relative", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("git diff --diff-filter=U failed: %w", err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(output, "\x00") {
+		if path != "" {
+			files = append(files, filepath.FromSlash(path))
+		}
+	}
+	return files, nil
+}
+
+// ConflictSides returns the two versions of a conflicted file git tried to
+// merge: ours, from the branch being merged into, and theirs
+func (g *GitSync) ConflictSides(path string) (ours, theirs []byte, err error) {
+	if ours, err = g.FileAt(":2", "./"+filepath.ToSlash(path)); err != nil {
+		return nil, nil, err
+	}
+	if theirs, err = g.FileAt(":3", "./"+filepath.ToSlash(path)); err != nil {
+		return nil, nil, err
+	}
+	return ours, theirs, nil
+}
+
+// MarkResolved stages a conflicted file once its conflict is resolved
+func (g *GitSync) MarkResolved(path string) error {
+	return g.runGitCommand("add", "--", path)
+}
+
+// MergeInProgress reports whether a merge is waiting to be committed
+func (g *GitSync) MergeInProgress() bool {
+	_, err := g.gitOutput("rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return err == nil
+}
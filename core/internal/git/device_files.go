@@ -0,0 +1,55 @@
+package git
+
+import "strings"
+
+// deviceFiles are the files under .pocket-prompt/ that belong to one clone of
+// the library and are never committed: its device name, caches, the TUI
+// session, saved variable answers, saved search subscriptions, the slow
+// query log, opt-in analytics, where this device's server listens, the API
+// keys it accepts and its log and audit log, which record client addresses,
+// and how far this device's digests and email gateway have got. Usage counts are committed,
+// one file per device, so they add up across devices without conflicts.
+var deviceFiles = []string{
+	".pocket-prompt/device",
+	".pocket-prompt/cache/",
+	".pocket-prompt/tui-session.json",
+	".pocket-prompt/variable-answers.json",
+	".pocket-prompt/subscriptions.json",
+	".pocket-prompt/slow-queries.jsonl",
+	".pocket-prompt/analytics/",
+	".pocket-prompt/audit.log",
+	".pocket-prompt/server.json",
+	".pocket-prompt/api-keys.json",
+	".pocket-prompt/server.log",
+	".pocket-prompt/digest.json",
+	".pocket-prompt/email.json",
+}
+
+// unionMerged are git attributes for files that several devices append
+// lines to: a merge keeps the lines of both sides rather than conflicting
+var unionMerged = []string{
+	"*.outcomes.jsonl merge=union",
+}
+
+// syncDeviceFiles keeps device files out of git through .git/info/exclude,
+// untracking any committed before they were excluded, and sets up the union
+// merge of appended logs in .git/info/attributes
+func (g *GitSync) syncDeviceFiles() error {
+	excludes := make([]string, len(deviceFiles))
+	for i, path := range deviceFiles {
+		excludes[i] = "/" + path
+	}
+	if err := g.writeInfoBlock("exclude", "device files", excludes); err != nil {
+		return err
+	}
+	if err := g.writeInfoBlock("attributes", "merges", unionMerged); err != nil {
+		return err
+	}
+
+	tracked, err := g.gitOutput(append([]string{"ls-files", "--"}, deviceFiles...)...)
+	if err != nil || tracked == "" {
+		return nil
+	}
+	args := append([]string{"rm", "-r", "--cached", "--quiet", "--"}, strings.Split(tracked, "\n")...)
+	return g.runGitCommand(args...)
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// CreateWithGH runs 'gh issue create' or 'gh pr create', as kind says, in the
+// working directory with body as the description and args passed on to gh.
+// gh talks to the terminal itself, so it can ask for a title or anything else
+// args leave out. Without gh it returns ErrGHNotFound.
+func CreateWithGH(kind, body string, args []string) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ErrGHNotFound
+	}
+
+	// A file rather than an argument, so long bodies stay within command line limits
+	bodyFile, err := os.CreateTemp("", "pkt-gh-body-*.md")
+	if err != nil {
+		return fmt.Errorf("failed to write the description: %w", err)
+	}
+	defer os.Remove(bodyFile.Name())
+	if _, err := bodyFile.WriteString(body); err != nil {
+		bodyFile.Close()
+		return fmt.Errorf("failed to write the description: %w", err)
+	}
+	if err := bodyFile.Close(); err != nil {
+		return fmt.Errorf("failed to write the description: %w", err)
+	}
+
+	cmd := exec.Command("gh", append([]string{kind, "create", "--body-file", bodyFile.Name()}, args...)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("gh %s create failed: %w", kind, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Revision is a commit that changed a file
+type Revision struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+	Path    string // The file's path at this commit, which differs from the current one after a rename
+	Deleted bool   // The commit removed the file
+}
+
+// historyFormat separates commit fields with the ASCII unit separator
+const historyFormat = "--format=%x1e%H%x1f%an%x1f%aI%x1f%s"
+
+// FileHistory returns the commits that changed path, newest first, following
+// renames. It returns nil when the library is not a git repository.
+func (g *GitSync) FileHistory(path string) ([]Revision, error) {
+	if !g.isGitInitialized() || !g.hasCommits() {
+		return nil, nil
+	}
+	output, err := g.gitOutput("log", "--follow", "--name-status", historyFormat, "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("git log %s failed: %w", path, err)
+	}
+	return parseHistory(output), nil
+}
+
+// DeletedFiles returns the commits since the given time that deleted files
+// under dir, one revision per deleted file, newest first
+func (g *GitSync) DeletedFiles(dir string, since time.Time) ([]Revision, error) {
+	if !g.isGitInitialized() || !g.hasCommits() {
+		return nil, nil
+	}
+	args := []string{"log", "--diff-filter=D", "--name-status", historyFormat}
+	if !since.IsZero() {
+		args = append(args, "--since", since.Format(time.RFC3339))
+	}
+	output, err := g.gitOutput(append(args, "--", dir)...)
+	if err != nil {
+		return nil, fmt.Errorf("git log %s failed: %w", dir, err)
+	}
+	return parseHistory(output), nil
+}
+
+// FileAt returns the content of path as of a commit. Pass hash + "^" to read
+// a deleted file from the commit before its deletion.
+func (g *GitSync) FileAt(hash, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", hash+":"+path)
+	cmd.Dir = g.baseDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show %s:%s failed: %w", hash, path, err)
+	}
+	return output, nil
+}
+
+// parseHistory reads git log output written with historyFormat and
+// --name-status, producing one revision per changed file
+func parseHistory(output string) []Revision {
+	var revisions []Revision
+	for _, record := range strings.Split(output, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		commit := Revision{Hash: fields[0], Author: fields[1], Date: date, Subject: fields[3]}
+
+		for _, line := range lines[1:] {
+			// Status, then the path; renames and copies list old and new paths
+			parts := strings.Split(strings.TrimSpace(line), "\t")
+			if len(parts) < 2 {
+				continue
+			}
+			revision := commit
+			revision.Path = parts[len(parts)-1]
+			revision.Deleted = parts[0] == "D"
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HookMarker is a line in every hook pkt installs, so it can replace its own
+// hooks without overwriting ones written by hand or by other tools
+const HookMarker = "# Installed by pkt hooks install"
+
+// StagedFiles returns the files added, copied, modified or renamed in the
+// index, relative to the library root
+func (g *GitSync) StagedFiles() ([]string, error) {
+	if !g.isGitInitialized() {
+		return nil, fmt.Errorf("library is not a git repository")
+	}
+	output, err := g.gitOutput("diff", "--cached", "--name-only", "--diff-filter=ACMR", "--relative", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("git diff --cached failed: %w", err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(output, "\x00") {
+		if path != "" {
+			files = append(files, filepath.FromSlash(path))
+		}
+	}
+	return files, nil
+}
+
+// RepoPrefix returns the library's directory relative to the root of the git
+// repository containing it, ending in a slash, or "" when the library is the
+// root or not in a repository. CI annotations need paths from the repository root.
+func (g *GitSync) RepoPrefix() string {
+	prefix, err := g.gitOutput("rev-parse", "--show-prefix")
+	if err != nil {
+		return ""
+	}
+	return prefix
+}
+
+// StagedFile returns the content of path as staged in the index
+func (g *GitSync) StagedFile(path string) ([]byte, error) {
+	return g.FileAt("", filepath.ToSlash(path))
+}
+
+// InstallHook writes script as the named git hook, such as "pre-commit", and
+// returns its path. An existing hook is replaced only if pkt installed it or
+// force is set. Hooks go where core.hooksPath points, if it is configured.
+func (g *GitSync) InstallHook(name, script string, force bool) (string, error) {
+	if !g.isGitInitialized() {
+		return "", fmt.Errorf("library is not a git repository (run 'git init' or 'pkt git setup <repo-url>' first)")
+	}
+	hooksDir, err := g.gitOutput("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the git hooks directory: %w", err)
+	}
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(g.baseDir, hooksDir)
+	}
+
+	path := filepath.Join(hooksDir, name)
+	if existing, err := os.ReadFile(path); err == nil && !force && !strings.Contains(string(existing), HookMarker) {
+		return "", fmt.Errorf("%s already exists and was not installed by pkt; use --force to replace it", path)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", hooksDir, err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to make %s executable: %w", path, err)
+	}
+	return path, nil
+}
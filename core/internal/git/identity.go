@@ -0,0 +1,44 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Identity is who the commits git sync makes are by, and the key that signs
+// them, in place of the user's own git configuration
+type Identity struct {
+	Name          string // Author and committer name
+	Email         string // Author and committer email
+	SigningKey    string // GPG key ID, or an SSH key file or "key::" literal for the ssh format
+	SigningFormat string // openpgp (default), ssh or x509
+}
+
+// ConfigureIdentity sets the author of, and the key signing, every commit
+// made by git commands in this process or by built-in sync. Settings left empty fall back to the
+// git configuration. An SSH signing key must be a file that exists.
+func (g *GitSync) ConfigureIdentity(identity Identity) error {
+	g.identity = identity
+	if identity.Name != "" {
+		addGitConfig("user.name", identity.Name)
+	}
+	if identity.Email != "" {
+		addGitConfig("user.email", identity.Email)
+	}
+	if identity.SigningKey == "" {
+		return nil
+	}
+
+	if identity.SigningFormat == "ssh" && !strings.HasPrefix(identity.SigningKey, "key::") {
+		if _, err := os.Stat(identity.SigningKey); err != nil {
+			return fmt.Errorf("git signing key: %w", err)
+		}
+	}
+	if identity.SigningFormat != "" {
+		addGitConfig("gpg.format", identity.SigningFormat)
+	}
+	addGitConfig("user.signingkey", identity.SigningKey)
+	addGitConfig("commit.gpgsign", "true")
+	return nil
+}
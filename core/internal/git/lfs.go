@@ -0,0 +1,30 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// lfsPattern is the .gitattributes pattern that sends attachments through LFS
+const lfsPattern = "assets/**"
+
+// LFSAvailable reports whether the git-lfs extension is installed
+func LFSAvailable() bool {
+	return exec.Command("git", "lfs", "version").Run() == nil
+}
+
+// EnableLFS installs the LFS hooks in the repository and tracks every file
+// under assets/ with LFS, recording the pattern in .gitattributes. Files
+// already committed stay in regular git history.
+func (g *GitSync) EnableLFS() error {
+	if !LFSAvailable() {
+		return fmt.Errorf("git-lfs is not installed (see https://git-lfs.com)")
+	}
+	if err := g.runGitCommand("lfs", "install", "--local"); err != nil {
+		return fmt.Errorf("failed to install git-lfs hooks: %w", err)
+	}
+	if err := g.runGitCommand("lfs", "track", lfsPattern); err != nil {
+		return fmt.Errorf("failed to track %s with git-lfs: %w", lfsPattern, err)
+	}
+	return nil
+}
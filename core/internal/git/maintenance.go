@@ -0,0 +1,45 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// fsckTimeout bounds repository checks, which read every object
+const fsckTimeout = 5 * time.Minute
+
+// Fsck verifies the repository's objects and returns the problems git fsck
+// reports. Dangling objects are normal leftovers and are not reported.
+func (g *GitSync) Fsck() ([]string, error) {
+	if !g.isGitInitialized() {
+		return nil, fmt.Errorf("library is not a git repository")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fsckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "fsck", "--no-progress", "--no-dangling")
+	cmd.Dir = g.baseDir
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("git fsck timed out after %v", fsckTimeout)
+	}
+
+	var problems []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			problems = append(problems, line)
+		}
+	}
+	if err != nil && len(problems) == 0 {
+		return nil, fmt.Errorf("git fsck failed: %w", err)
+	}
+	return problems, nil
+}
+
+// Compact packs loose objects when git thinks it worthwhile
+func (g *GitSync) Compact() error {
+	return g.runGitCommandWithTimeout(fsckTimeout, "gc", "--auto", "--quiet")
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// mirrorStamp is touched inside .git after every successful refresh
+const mirrorStamp = "pocket-prompt-refreshed"
+
+// MirrorDir returns where the mirror source called name is cloned. Mirrors
+// live in the user cache directory so they never become part of a library.
+// Names that would reach outside the mirrors directory are refused.
+func MirrorDir(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid mirror name %q", name)
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	mirrors := filepath.Join(cacheDir, "pocket-prompt", "mirrors")
+	dir := filepath.Join(mirrors, name)
+	if filepath.Dir(dir) != mirrors {
+		return "", fmt.Errorf("invalid mirror name %q", name)
+	}
+	return dir, nil
+}
+
+// MirrorRefreshedAt returns when the mirror in dir was last refreshed, and
+// false if it has not been cloned yet
+func MirrorRefreshedAt(dir string) (time.Time, bool) {
+	info, err := os.Stat(filepath.Join(dir, ".git", mirrorStamp))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// RefreshMirror makes dir an exact copy of the upstream branch: it clones url
+// the first time, and afterwards fetches and hard-resets, discarding anything
+// changed locally. An empty branch tracks the remote's default branch. Only a
+// directory carrying the mirror stamp is ever removed or reset; anything else
+// already at dir is left alone and reported.
+func RefreshMirror(dir, url, branch string) error {
+	if dir == "" {
+		return fmt.Errorf("mirror directory is not set")
+	}
+	_, stamped := MirrorRefreshedAt(dir)
+	if stamped && mirrorOrigin(dir) != url {
+		// The source now points elsewhere; start over
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove old mirror: %w", err)
+		}
+		stamped = false
+	}
+
+	if !stamped {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return fmt.Errorf("failed to create mirror directory: %w", err)
+		}
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+			return fmt.Errorf("%s already exists and is not a mirror", dir)
+		}
+		args := []string{"clone", "--depth", "1"}
+		if branch != "" {
+			args = append(args, "--branch", branch)
+		}
+		if err := runMirrorGit("", 2*time.Minute, append(args, url, dir)...); err != nil {
+			return err
+		}
+	} else {
+		ref := branch
+		if ref == "" {
+			ref = "HEAD"
+		}
+		if err := runMirrorGit(dir, time.Minute, "fetch", "--depth", "1", "origin", ref); err != nil {
+			return err
+		}
+		if err := runMirrorGit(dir, 10*time.Second, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return err
+		}
+	}
+
+	stamp := filepath.Join(dir, ".git", mirrorStamp)
+	return os.WriteFile(stamp, []byte(time.Now().Format(time.RFC3339)+"\n"), 0644)
+}
+
+func mirrorOrigin(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func runMirrorGit(dir string, timeout time.Duration, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Never stop to ask for credentials; mirrors are public repositories
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("git %s timed out after %v", args[0], timeout)
+		}
+		return fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(output)))
+	}
+	return nil
+}
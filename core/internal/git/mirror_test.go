@@ -0,0 +1,102 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMirrorDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir, err := MirrorDir("awesome")
+	if err != nil || filepath.Base(dir) != "awesome" || filepath.Base(filepath.Dir(dir)) != "mirrors" {
+		t.Fatalf("MirrorDir = %q, %v; want a directory under mirrors", dir, err)
+	}
+	for _, name := range []string{"", ".", "..", "../../../Documents", "a/b", `..\x`} {
+		if dir, err := MirrorDir(name); err == nil {
+			t.Errorf("MirrorDir(%q) = %q, want an error", name, dir)
+		}
+	}
+}
+
+// upstreamRepo creates a repository with one committed prompt file
+func upstreamRepo(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, dir, "prompts/one.md", content)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "Add one"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", args[0], err, out)
+		}
+	}
+	return dir
+}
+
+func TestRefreshMirror(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	upstream := upstreamRepo(t, "one")
+	dir := filepath.Join(t.TempDir(), "mirror")
+
+	if err := RefreshMirror(dir, upstream, ""); err != nil {
+		t.Fatalf("RefreshMirror: %v", err)
+	}
+	if _, ok := MirrorRefreshedAt(dir); !ok {
+		t.Error("a fresh clone was not stamped")
+	}
+	if got := readFile(t, dir, "prompts/one.md"); got != "one" {
+		t.Errorf("one.md = %q after the clone", got)
+	}
+
+	// Local changes are discarded on refresh
+	writeFile(t, dir, "prompts/one.md", "edited")
+	if err := RefreshMirror(dir, upstream, ""); err != nil {
+		t.Fatalf("RefreshMirror: %v", err)
+	}
+	if got := readFile(t, dir, "prompts/one.md"); got != "one" {
+		t.Errorf("one.md = %q after a refresh, want upstream's", got)
+	}
+
+	// A mirror pointed at another repository is replaced
+	other := upstreamRepo(t, "other")
+	if err := RefreshMirror(dir, other, ""); err != nil {
+		t.Fatalf("RefreshMirror to another upstream: %v", err)
+	}
+	if got := readFile(t, dir, "prompts/one.md"); got != "other" {
+		t.Errorf("one.md = %q after switching upstream", got)
+	}
+}
+
+func TestRefreshMirrorLeavesOtherDirectories(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	upstream := upstreamRepo(t, "one")
+
+	// A directory that is not a mirror, with or without a repository, is
+	// never removed or reset to make room for the clone
+	documents := t.TempDir()
+	writeFile(t, documents, "notes.txt", "keep me")
+	err := RefreshMirror(documents, upstream, "")
+	if err == nil || !strings.Contains(err.Error(), "not a mirror") {
+		t.Errorf("RefreshMirror over a plain directory = %v, want it refused", err)
+	}
+	if got := readFile(t, documents, "notes.txt"); got != "keep me" {
+		t.Errorf("notes.txt = %q", got)
+	}
+
+	project := upstreamRepo(t, "mine")
+	writeFile(t, project, "prompts/one.md", "uncommitted")
+	if err := RefreshMirror(project, upstream, ""); err == nil {
+		t.Error("RefreshMirror over an unstamped repository succeeded, want it refused")
+	}
+	if got := readFile(t, project, "prompts/one.md"); got != "uncommitted" {
+		t.Errorf("one.md = %q, want the repository's own changes kept", got)
+	}
+}
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// ReviewBranchPrefix namespaces the branches that carry prompt proposals
+const ReviewBranchPrefix = "review/"
+
+// ReviewBranch returns the branch a proposal for the prompt id is committed to
+func ReviewBranch(id string) string {
+	return ReviewBranchPrefix + id
+}
+
+// CurrentBranch returns the checked-out branch
+func (g *GitSync) CurrentBranch() string {
+	return g.getCurrentBranch()
+}
+
+// BranchExists reports whether a local branch called name exists
+func (g *GitSync) BranchExists(name string) bool {
+	return g.runGitCommand("rev-parse", "--verify", "--quiet", "refs/heads/"+name) == nil
+}
+
+// SwitchBranch checks out name, creating it from the current branch first if
+// it does not exist. Uncommitted changes are carried over.
+func (g *GitSync) SwitchBranch(name string) error {
+	if g.BranchExists(name) {
+		return g.runGitCommand("checkout", name)
+	}
+	return g.runGitCommand("checkout", "-b", name)
+}
+
+// MergeBranch merges name into the current branch with a merge commit
+func (g *GitSync) MergeBranch(name string) error {
+	return g.runGitCommand("merge", "--no-ff", "--no-edit", name)
+}
+
+// DeleteBranch removes a local branch that has been merged
+func (g *GitSync) DeleteBranch(name string) error {
+	return g.runGitCommand("branch", "-d", name)
+}
+
+// CommitPaths commits only the given paths, leaving any other changes in the
+// library uncommitted. It reports whether there was anything to commit.
+func (g *GitSync) CommitPaths(message string, paths ...string) (bool, error) {
+	defer tracing.Begin("git commit")()
+	args := append([]string{"add", "-A", "--"}, paths...)
+	if err := g.runGitCommand(args...); err != nil {
+		return false, err
+	}
+
+	diff := exec.Command("git", append([]string{"diff", "--cached", "--quiet", "--"}, paths...)...)
+	diff.Dir = g.baseDir
+	if diff.Run() == nil {
+		return false, nil
+	}
+
+	args = append([]string{"commit", "-m", message, "--"}, paths...)
+	if err := g.runGitCommand(args...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnmergedReviewBranches lists review branches that have not been merged into
+// the current branch
+func (g *GitSync) UnmergedReviewBranches() []string {
+	output, err := g.gitOutput("branch", "--list", ReviewBranchPrefix+"*", "--no-merged")
+	if err != nil {
+		return nil
+	}
+
+	var branches []string
+	for _, line := range strings.Split(output, "\n") {
+		if branch := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*")); branch != "" {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// UserName returns the name commits are attributed to, falling back to the
+// login name
+func (g *GitSync) UserName() string {
+	if name, err := g.gitOutput("config", "user.name"); err == nil && name != "" {
+		return name
+	}
+	return os.Getenv("USER")
+}
+
+func (g *GitSync) gitOutput(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.baseDir
+	output, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", ErrGitNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sparseAlways are the directories every sparse checkout includes: the
+// library settings and the prompt locks teammates need to see
+var sparseAlways = []string{".pocket-prompt", "locks"}
+
+// unshallowTimeout bounds fetching the history a shallow clone left out
+const unshallowTimeout = 30 * time.Minute
+
+// SetupOptions are what SetupRepository does beyond connecting the remote
+type SetupOptions struct {
+	LFS    bool     // Track attachments under assets/ with Git LFS
+	Depth  int      // Fetch only this many commits of history; 0 fetches all
+	Sparse []string // Check out only these directories, such as packs/team-a
+}
+
+// SparsePaths returns the directories a sparse checkout of the library
+// includes, or nil when the whole library is checked out. The list belongs to
+// this clone and lives in .git/info/sparse-checkout.
+func (g *GitSync) SparsePaths() []string {
+	if _, err := os.Stat(filepath.Join(g.baseDir, ".git", "info", "sparse-checkout")); err != nil {
+		return nil
+	}
+	output, err := g.gitOutput("sparse-checkout", "list")
+	if err != nil || output == "" {
+		return nil // Not sparse, or sparse checkout was turned off
+	}
+	return strings.Split(output, "\n")
+}
+
+// SetSparsePaths checks out only dirs, along with the files at the top of
+// the library and the directories above them, and removes everything else
+// from the working tree; history and the remote keep it. No dirs checks out
+// the whole library again.
+func (g *GitSync) SetSparsePaths(dirs []string) error {
+	if len(dirs) == 0 {
+		if g.SparsePaths() == nil {
+			return nil
+		}
+		return g.runGitCommandWithTimeout(5*time.Minute, "sparse-checkout", "disable")
+	}
+	paths, err := SparseDirs(dirs)
+	if err != nil {
+		return err
+	}
+	args := append([]string{"sparse-checkout", "set", "--cone"}, paths...)
+	return g.runGitCommandWithTimeout(5*time.Minute, args...)
+}
+
+// SparseDirs cleans up directories given for a sparse checkout into paths
+// relative to the library, adding the ones every checkout needs
+func SparseDirs(dirs []string) ([]string, error) {
+	paths := slices.Clone(sparseAlways)
+	for _, dir := range dirs {
+		clean := path.Clean(filepath.ToSlash(strings.TrimSpace(dir)))
+		if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || path.IsAbs(clean) {
+			return nil, fmt.Errorf("invalid sparse checkout directory %q (use a directory inside the library, such as packs/team-a)", dir)
+		}
+		if !slices.Contains(paths, clean) {
+			paths = append(paths, clean)
+		}
+	}
+	return paths, nil
+}
+
+// IsShallow reports whether the library was cloned without its full history
+func (g *GitSync) IsShallow() bool {
+	output, err := g.gitOutput("rev-parse", "--is-shallow-repository")
+	return err == nil && output == "true"
+}
+
+// Unshallow fetches the history a shallow clone left out, which prompt
+// history and restores need
+func (g *GitSync) Unshallow() error {
+	if !g.IsShallow() {
+		return nil
+	}
+	return g.runGitCommandWithTimeout(unshallowTimeout, "fetch", "--unshallow", "origin")
+}
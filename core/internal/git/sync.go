@@ -2,18 +2,37 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/ignore"
+	"github.com/dpshade/pocket-prompt/internal/platform"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
 )
 
+// ErrGitNotFound is returned by git operations when git is not installed,
+// as in minimal containers
+var ErrGitNotFound = errors.New("git is not installed")
+
+// Available reports whether git is installed, which sync needs
+func Available() bool {
+	return platform.HasCommand("git")
+}
+
 // GitSync handles automatic git synchronization
 type GitSync struct {
-	baseDir string
-	enabled bool
+	baseDir    string
+	enabled    bool
+	mainBranch string   // Branch pull requests target; empty means detect it
+	auth       Auth     // Credentials for the origin remote, see ConfigureAuth
+	identity   Identity // Commit author, see ConfigureIdentity
+	builtin    bool     // Sync without the git program, see UseBuiltin
 }
 
 // NewGitSync creates a new GitSync instance
@@ -31,6 +50,10 @@ func (g *GitSync) IsEnabled() bool {
 
 // Initialize checks if git is set up and enables sync if available
 func (g *GitSync) Initialize() error {
+	if !g.builtin && !Available() {
+		g.enabled = false
+		return ErrGitNotFound
+	}
 	if !g.isGitInitialized() {
 		g.enabled = false
 		return nil // Not an error, just not available
@@ -46,8 +69,10 @@ func (g *GitSync) Initialize() error {
 	return nil
 }
 
-// SetupRepository initializes git and sets up remote repository automatically
-func (g *GitSync) SetupRepository(repoURL string) error {
+// SetupRepository initializes git and sets up remote repository automatically.
+// opts can track attachments with Git LFS from the first commit, and make a
+// shallow clone or a sparse checkout of a large shared library.
+func (g *GitSync) SetupRepository(repoURL string, opts SetupOptions) error {
 	// Validate the repository URL
 	if repoURL == "" {
 		return fmt.Errorf("repository URL cannot be empty")
@@ -67,6 +92,12 @@ func (g *GitSync) SetupRepository(repoURL string) error {
 		}
 	}
 	
+	if opts.LFS {
+		if err := g.EnableLFS(); err != nil {
+			return err
+		}
+	}
+	
 	// Check if remote already exists
 	if g.hasRemote() {
 		// Update the remote URL if different
@@ -84,6 +115,10 @@ func (g *GitSync) SetupRepository(repoURL string) error {
 		}
 		fmt.Printf("Added remote repository: %s\n", repoURL)
 	}
+	// A token is only sent to the remote's host, which is known now
+	if err := g.configureToken(); err != nil {
+		return err
+	}
 	
 	// Create initial commit if no commits exist
 	if !g.hasCommits() {
@@ -97,7 +132,7 @@ func (g *GitSync) SetupRepository(repoURL string) error {
 		}
 		
 		// Stage all files
-		if err := g.runGitCommand("add", "-A"); err != nil {
+		if err := g.stageAll(); err != nil {
 			return fmt.Errorf("failed to stage files: %w", err)
 		}
 		
@@ -110,24 +145,27 @@ func (g *GitSync) SetupRepository(repoURL string) error {
 		}
 	}
 	
-	// Try to fetch from remote to check if it exists and is accessible
-	fetchErr := g.runGitCommand("fetch", "origin")
-	if fetchErr != nil {
-		if strings.Contains(fetchErr.Error(), "could not read Username") || 
-		   strings.Contains(fetchErr.Error(), "Authentication failed") ||
-		   strings.Contains(fetchErr.Error(), "Permission denied") {
-			fmt.Printf("\n⚠️  Authentication required for: %s\n", repoURL)
-			fmt.Println("\nFor GitHub repositories, you have two options:")
-			fmt.Println("\n1. Use HTTPS with a Personal Access Token:")
-			fmt.Println("   - Create a token at: https://github.com/settings/tokens")
-			fmt.Println("   - Use format: https://YOUR_TOKEN@github.com/username/repo.git")
-			fmt.Println("\n2. Use SSH (recommended):")
-			fmt.Println("   - Setup SSH key: https://docs.github.com/en/authentication/connecting-to-github-with-ssh")
-			fmt.Println("   - Use format: git@github.com:username/repo.git")
-			return fmt.Errorf("authentication required for remote repository")
+	// Check out only the chosen directories, before the remote's content
+	// arrives. Local files outside them stay committed and are pushed below.
+	if len(opts.Sparse) > 0 {
+		if err := g.SetSparsePaths(opts.Sparse); err != nil {
+			return fmt.Errorf("failed to set up sparse checkout: %w", err)
+		}
+	}
+
+	// Try to fetch from remote to check if it exists and is accessible. Git
+	// never prompts for credentials here, so a refusal fails setup with why.
+	fetchArgs := []string{"fetch", "origin"}
+	if opts.Depth > 0 {
+		fetchArgs = append(fetchArgs, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if out, err := g.remoteCommand(setupTimeout, fetchArgs...); err != nil {
+		fetchErr := g.describeRemoteFailure(out, err)
+		if _, ok := fetchErr.(*AuthError); ok {
+			return fetchErr
 		}
 		// For new repositories, fetch might fail which is okay
-		if !strings.Contains(fetchErr.Error(), "couldn't find remote ref") {
+		if !strings.Contains(out, "couldn't find remote ref") {
 			fmt.Printf("Warning: Could not fetch from remote (this is normal for new repositories): %v\n", fetchErr)
 		}
 	} else {
@@ -181,11 +219,10 @@ func (g *GitSync) SetupRepository(repoURL string) error {
 	currentBranch := g.getCurrentBranch()
 	fmt.Printf("📤 Pushing to remote branch '%s'...\n", currentBranch)
 	
-	pushErr := g.runGitCommand("push", "-u", "origin", currentBranch)
-	if pushErr != nil {
-		if strings.Contains(pushErr.Error(), "could not read Username") || 
-		   strings.Contains(pushErr.Error(), "Authentication failed") {
-			return fmt.Errorf("authentication failed: please check your repository URL and credentials")
+	if out, err := g.remoteCommand(setupTimeout, "push", "-u", "origin", currentBranch); err != nil {
+		pushErr := g.describeRemoteFailure(out, err)
+		if _, ok := pushErr.(*AuthError); ok {
+			return pushErr
 		}
 		// Non-fatal push error
 		fmt.Printf("Warning: Push failed (you can push manually later): %v\n", pushErr)
@@ -247,6 +284,9 @@ func contains(slice []string, item string) bool {
 
 // hasCommits checks if the repository has any commits
 func (g *GitSync) hasCommits() bool {
+	if g.builtin {
+		return g.builtinHead() != ""
+	}
 	cmd := exec.Command("git", "rev-list", "-n", "1", "--all")
 	cmd.Dir = g.baseDir
 	output, err := cmd.Output()
@@ -266,6 +306,11 @@ func (g *GitSync) Disable() {
 	g.enabled = false
 }
 
+// IsInitialized reports whether the library directory is a git repository
+func (g *GitSync) IsInitialized() bool {
+	return g.isGitInitialized()
+}
+
 // isGitInitialized checks if the directory has git initialized
 func (g *GitSync) isGitInitialized() bool {
 	gitDir := filepath.Join(g.baseDir, ".git")
@@ -277,6 +322,9 @@ func (g *GitSync) isGitInitialized() bool {
 
 // hasRemote checks if git has a remote configured
 func (g *GitSync) hasRemote() bool {
+	if g.builtin {
+		return g.builtinHasRemote()
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	
@@ -291,6 +339,9 @@ func (g *GitSync) hasRemote() bool {
 
 // hasRemoteQuick checks if git has a remote configured with very short timeout for UI
 func (g *GitSync) hasRemoteQuick() bool {
+	if g.builtin {
+		return g.builtinHasRemote()
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 	
@@ -303,14 +354,25 @@ func (g *GitSync) hasRemoteQuick() bool {
 	return len(strings.TrimSpace(string(output))) > 0
 }
 
-// SyncChanges commits and pushes changes to git
+// SyncChanges commits and pushes changes to git, with the time appended to
+// the message
 func (g *GitSync) SyncChanges(message string) error {
+	return g.SyncCommit(fmt.Sprintf("%s - %s", message, time.Now().Format("2006-01-02 15:04:05")))
+}
+
+// SyncCommit commits and pushes changes to git with message as it is
+func (g *GitSync) SyncCommit(message string) error {
 	if !g.IsEnabled() {
 		return nil // Silently skip if not enabled
 	}
+	defer tracing.Begin("git sync")()
+
+	if err := g.CheckoutWorkingBranch(); err != nil {
+		return fmt.Errorf("failed to switch to working branch: %w", err)
+	}
 
 	// Stage all changes
-	if err := g.runGitCommand("add", "-A"); err != nil {
+	if err := g.stageAll(); err != nil {
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
 
@@ -325,13 +387,12 @@ func (g *GitSync) SyncChanges(message string) error {
 	}
 
 	// Commit changes
-	commitMessage := fmt.Sprintf("%s - %s", message, time.Now().Format("2006-01-02 15:04:05"))
-	if err := g.runGitCommand("commit", "-m", commitMessage); err != nil {
+	if err := g.commit(message); err != nil {
 		return fmt.Errorf("failed to commit changes: %w", err)
 	}
 
 	// Push changes (best effort - don't fail if push fails)
-	if err := g.runGitCommand("push"); err != nil {
+	if err := g.push(); err != nil {
 		// Log the error but don't fail the operation
 		// The user can manually push later if needed
 		return fmt.Errorf("committed locally but failed to push: %w", err)
@@ -340,8 +401,103 @@ func (g *GitSync) SyncChanges(message string) error {
 	return nil
 }
 
+// CommitChanges stages and commits all changes locally without pushing.
+// It only requires an initialized repository, not a configured remote.
+func (g *GitSync) CommitChanges(message string) (bool, error) {
+	if !g.isGitInitialized() {
+		return false, fmt.Errorf("git is not initialized in %s", g.baseDir)
+	}
+	defer tracing.Begin("git commit")()
+
+	if err := g.stageAll(); err != nil {
+		return false, fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	hasChanges, err := g.hasChangesToCommit()
+	if err != nil {
+		return false, fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !hasChanges {
+		return false, nil
+	}
+
+	if err := g.commit(message); err != nil {
+		return false, fmt.Errorf("failed to commit changes: %w", err)
+	}
+
+	return true, nil
+}
+
+// stageAll stages every change in the library except paths excluded by
+// .pktignore and the files that belong to this device
+func (g *GitSync) stageAll() error {
+	if err := g.syncIgnoreFile(); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", ignore.FileName, err)
+	}
+	if err := g.syncDeviceFiles(); err != nil {
+		return fmt.Errorf("failed to keep device files out of git: %w", err)
+	}
+	if g.builtin {
+		return g.builtinAddAll()
+	}
+	err := g.runGitCommand("add", "-A")
+	if err != nil && strings.Contains(err.Error(), "outside of your sparse-checkout definition") {
+		// Changes in the checked-out directories were staged; the rest stay
+		// local until the sparse checkout includes them
+		return nil
+	}
+	return err
+}
+
+// syncIgnoreFile mirrors the .pktignore patterns into .git/info/exclude so git
+// skips the same files pocket-prompt does, without touching the user's .gitignore
+func (g *GitSync) syncIgnoreFile() error {
+	patterns, err := ignore.Patterns(g.baseDir)
+	if err != nil {
+		return err
+	}
+	return g.writeInfoBlock("exclude", ignore.FileName, patterns)
+}
+
+// writeInfoBlock replaces the block of lines pocket-prompt manages under
+// name in a file of .git/info, such as exclude, keeping any user-authored
+// lines. No lines removes the block.
+func (g *GitSync) writeInfoBlock(file, name string, lines []string) error {
+	path := filepath.Join(g.baseDir, ".git", "info", file)
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	blockStart := "# BEGIN pocket-prompt " + name
+	blockEnd := "# END pocket-prompt " + name
+	content := string(existing)
+	if start := strings.Index(content, blockStart); start != -1 {
+		if end := strings.Index(content[start:], blockEnd); end != -1 {
+			content = content[:start] + strings.TrimPrefix(content[start+end+len(blockEnd):], "\n")
+		}
+	}
+	if len(lines) > 0 {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += blockStart + "\n" + strings.Join(lines, "\n") + "\n" + blockEnd + "\n"
+	}
+
+	if content == string(existing) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
 // hasChangesToCommit checks if there are staged changes ready to commit
 func (g *GitSync) hasChangesToCommit() (bool, error) {
+	if g.builtin {
+		return g.builtinHasStaged()
+	}
 	cmd := exec.Command("git", "diff", "--cached", "--quiet")
 	cmd.Dir = g.baseDir
 	err := cmd.Run()
@@ -374,6 +530,9 @@ func (g *GitSync) runGitCommandWithTimeout(timeout time.Duration, args ...string
 	
 	// Capture both stdout and stderr for better error messages
 	output, err := cmd.CombinedOutput()
+	if errors.Is(err, exec.ErrNotFound) {
+		return ErrGitNotFound
+	}
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return fmt.Errorf("git %s timed out after %v", strings.Join(args, " "), timeout)
@@ -401,6 +560,9 @@ func (g *GitSync) GetStatus() (string, error) {
 
 // getDetailedStatus performs the actual git status check with timeouts
 func (g *GitSync) getDetailedStatus() (string, error) {
+	if g.builtin {
+		return g.builtinStatus()
+	}
 	// Quick check for remote with reduced timeout
 	if !g.hasRemoteQuick() {
 		return "No remote configured", nil
@@ -462,11 +624,19 @@ func (g *GitSync) PullChanges() error {
 
 // pullChangesInternal contains the actual pull logic
 func (g *GitSync) pullChangesInternal() error {
+	if g.builtin {
+		return g.builtinPull()
+	}
+	defer tracing.Begin("git pull")()
 	// First, fetch the latest changes from remote
 	if err := g.runGitCommand("fetch", "origin"); err != nil {
 		return fmt.Errorf("failed to fetch from remote: %w", err)
 	}
 
+	if err := g.CheckoutWorkingBranch(); err != nil {
+		return fmt.Errorf("failed to switch to working branch: %w", err)
+	}
+
 	// Check if we're behind the remote
 	behind, err := g.isBehindRemote()
 	if err != nil {
@@ -474,7 +644,7 @@ func (g *GitSync) pullChangesInternal() error {
 	}
 
 	if !behind {
-		return nil // Already up to date
+		return g.mergeMainBranch() // Already up to date
 	}
 
 	// Check for uncommitted changes and stash them if present
@@ -509,36 +679,14 @@ func (g *GitSync) pullChangesInternal() error {
 		}
 	}
 
-	return nil
-}
-
-// BackgroundSync runs continuous background synchronization
-func (g *GitSync) BackgroundSync(ctx context.Context, interval time.Duration) {
-	if !g.IsEnabled() {
-		return
-	}
-	
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-	
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			// Silently pull changes in background
-			if err := g.PullChanges(); err != nil {
-				// Log but don't spam - only log once per error type
-				if !strings.Contains(err.Error(), "timeout") {
-					fmt.Printf("Background sync warning: %v\n", err)
-				}
-			}
-		}
-	}
+	return g.mergeMainBranch()
 }
 
 // getCurrentBranch returns the current git branch name
 func (g *GitSync) getCurrentBranch() string {
+	if g.builtin {
+		return g.builtinBranch()
+	}
 	cmd := exec.Command("git", "branch", "--show-current")
 	cmd.Dir = g.baseDir
 	output, err := cmd.Output()
@@ -554,8 +702,12 @@ func (g *GitSync) getCurrentBranch() string {
 	return branch
 }
 
-// isBehindRemote checks if local branch is behind remote
+// isBehindRemote checks if the remote branch has commits the local one lacks
 func (g *GitSync) isBehindRemote() (bool, error) {
+	if g.builtin {
+		_, behind, err := g.builtinAheadBehind()
+		return behind, err
+	}
 	branch := g.getCurrentBranch()
 	
 	// Get remote hash
@@ -577,13 +729,13 @@ func (g *GitSync) isBehindRemote() (bool, error) {
 	}
 	localHash := strings.TrimSpace(string(localOutput))
 	
-	// If hashes are different, check if we're behind
+	// If hashes are different, check if the remote has commits we lack,
+	// including when both sides have committed since they last synced
 	if remoteHash != localHash {
-		// Check if remote hash is reachable from local (i.e., we're behind)
-		mergeBaseCmd := exec.Command("git", "merge-base", "--is-ancestor", localHash, remoteHash)
+		mergeBaseCmd := exec.Command("git", "merge-base", "--is-ancestor", remoteHash, localHash)
 		mergeBaseCmd.Dir = g.baseDir
 		err := mergeBaseCmd.Run()
-		return err == nil, nil // If no error, we're behind
+		return err != nil, nil // If the remote is not in our history, we're behind
 	}
 	
 	return false, nil // Up to date
@@ -672,6 +824,10 @@ func (g *GitSync) FetchChanges() error {
 	}
 	
 	// Fetch with a reasonable timeout
+	if g.builtin {
+		return g.builtinFetch()
+	}
+	defer tracing.Begin("git fetch")()
 	return g.runGitCommandWithTimeout(30*time.Second, "fetch", "origin")
 }
 
@@ -0,0 +1,47 @@
+package help
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopicsHaveHelp(t *testing.T) {
+	for _, name := range append(Topics(), "ls", "unlock", "render") {
+		text, ok := Topic(name)
+		if !ok || strings.TrimSpace(text) == "" {
+			t.Errorf("Topic(%q) has no help", name)
+		}
+	}
+	if _, ok := Topic("no-such-command"); ok {
+		t.Error("Topic found help for an unknown command")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	if got := Search("  "); got != nil {
+		t.Errorf("Search of a blank term = %+v, want nothing", got)
+	}
+
+	found := map[string][]Match{}
+	for _, match := range Search("BOOLEAN search") {
+		found[match.Kind] = append(found[match.Kind], match)
+	}
+	if commands := found[KindCommand]; len(commands) != 1 || commands[0].Topic != "boolean-search" {
+		t.Errorf("commands = %+v, want boolean-search", commands)
+	}
+	if topics := found[KindTopic]; len(topics) == 0 || topics[0].Topic != "boolean-search" {
+		t.Errorf("topic lines = %+v, want boolean-search's first", topics)
+	}
+	var key bool
+	for _, match := range found[KindTUI] {
+		key = key || match.Key == "Ctrl+f"
+	}
+	if !key {
+		t.Errorf("TUI matches = %+v, want the Ctrl+f keybinding", found[KindTUI])
+	}
+
+	// Accents in the term are ignored
+	if got := Search("tránslate"); len(got) == 0 || got[0].Topic != "translate" {
+		t.Errorf("Search(tránslate) = %+v", got)
+	}
+}
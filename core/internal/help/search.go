@@ -0,0 +1,101 @@
+package help
+
+import (
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/fuzzy"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+)
+
+// Kinds of help a search finds
+const (
+	KindCommand = "command" // A line of the command list that 'pkt help' prints
+	KindTopic   = "topic"   // A line of a command's or topic's detailed help
+	KindTUI     = "tui"     // A keybinding or note in the TUI help
+)
+
+// Match is a line of help that a search found
+type Match struct {
+	Kind  string
+	Topic string // The command or topic the line belongs to, or the TUI help section
+	Key   string // The keybinding, for TUI keybindings
+	Text  string
+}
+
+// Search finds the lines of every part of the help that contain each word of
+// term, ignoring case and accents: the command list first, then the detailed
+// help of each topic, then the TUI's keybindings and notes. Lines of one topic
+// stay together.
+func Search(term string) []Match {
+	words := strings.Fields(normalize(term))
+	if len(words) == 0 {
+		return nil
+	}
+	matches := func(text string) bool {
+		text = normalize(text)
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var found []Match
+	for _, command := range Commands() {
+		if matches(command.Text) {
+			found = append(found, command)
+		}
+	}
+	// Topics named after the term lead the rest
+	var named, others []Match
+	for _, topic := range topics {
+		text, _ := Topic(topic)
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line == "" || !matches(line) {
+				continue
+			}
+			match := Match{Kind: KindTopic, Topic: topic, Text: line}
+			if matches(topic) {
+				named = append(named, match)
+			} else {
+				others = append(others, match)
+			}
+		}
+	}
+	found = append(append(found, named...), others...)
+	for _, section := range TUISections() {
+		for _, key := range section.Keys {
+			if matches(key.Key + " " + key.Description) {
+				found = append(found, Match{Kind: KindTUI, Topic: section.Title, Key: key.Key, Text: key.Description})
+			}
+		}
+		for _, note := range section.Notes {
+			if matches(note) {
+				found = append(found, Match{Kind: KindTUI, Topic: section.Title, Text: note})
+			}
+		}
+	}
+	return found
+}
+
+// Commands returns the command list that 'pkt help' prints, one entry per
+// command with the command's first name as its topic. The list is the
+// indented lines of the usage text, whatever the language.
+func Commands() []Match {
+	var commands []Match
+	for _, line := range strings.Split(i18n.T("cli.usage"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || !strings.HasPrefix(line, " ") {
+			continue
+		}
+		name := strings.FieldsFunc(trimmed, func(r rune) bool { return r == ' ' || r == ',' })[0]
+		commands = append(commands, Match{Kind: KindCommand, Topic: name, Text: trimmed})
+	}
+	return commands
+}
+
+// normalize folds text for matching: lowercased, without accents
+func normalize(text string) string {
+	return strings.ToLower(fuzzy.Fold(text))
+}
@@ -0,0 +1,1921 @@
+// Package help holds the text behind 'pkt help': the detailed help for each
+// command and topic, the TUI's keybindings and notes, and a search across all
+// of it for 'pkt help --search' and the TUI help modal.
+package help
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+)
+
+// topics names every command and topic with detailed help, by the first of
+// its names, in the order 'pkt help --search' lists them
+var topics = []string{
+	"list", "search", "path", "sources", "suggest", "project", "create", "edit",
+	"log", "lock", "protect", "templates", "template", "search-saved",
+	"boolean-search", "copy", "variants", "preview", "share", "cat", "speak", "profiles", "attach",
+	"eval", "lint", "maintenance", "bench", "doctor", "ci", "hooks", "stats", "analytics",
+	"changelog", "diff", "merge", "digest", "export", "import", "git", "migrate", "propose", "remote",
+	"open", "server", "email", "summarize", "autotag", "translate",
+	"check-links", "keys", "move", "watch-clipboard", "alias", "gh", "shell", "history", "plugins",
+	"config", "remote-mode", "env", "qr", "packs",
+}
+
+// Topics returns the names of every command and topic with detailed help
+func Topics() []string {
+	return append([]string(nil), topics...)
+}
+
+// Topic returns the detailed help for a command or topic as 'pkt help <name>'
+// prints it, or false when there is none. Catalogs for other languages may
+// translate a topic; the English text stays here.
+func Topic(name string) (string, bool) {
+	if text, ok := i18n.Translated("cli.help." + name); ok {
+		return text + "\n", true
+	}
+	var b strings.Builder
+	if !writeTopic(&b, name) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// writeTopic writes the English help for command to w, reporting false when
+// there is none
+func writeTopic(w io.Writer, command string) bool {
+	switch command {
+	case "list", "ls":
+		fmt.Fprintln(w, `list - List all prompts
+
+Usage: pocket-prompt list [options]
+
+Options:
+  --format, -f <format>  Output format (table, json, ids, default)
+  --tag, -t <tag>        Filter by tag
+  --lang <language>      Only prompts written in a language, such as de
+  --archived, -a         Show archived prompts
+  --all                  List every prompt, even with a pinned search
+  --sort frecency        Most used, and most recently used, first (needs
+                         'pkt analytics enable uses')
+  --columns <list>       Table columns, comma-separated (implies --format table):
+                         id, title, description, tags, pack, version, updated,
+                         tokens (default: id,title,version,updated)
+  --no-truncate          Never cut titles, descriptions or tags to fit the terminal
+
+Set "cli": {"format": "table"} in .pocket-prompt/config.json to change the
+default format of list, search, get and other commands.
+
+Table columns are as wide as their longest value. In a terminal too narrow
+for them, titles, descriptions and tags are cut with "..."; piped output is
+never cut. Empty cells show "-" so every row has the same fields, e.g.
+  pkt list --columns id,tokens | awk '$2 ~ /^[0-9]+$/ && $2 > 500 { print $1 }'
+
+With a pinned saved search ('pkt search-saved pin <name>'), list without
+options shows only that search's results.`)
+
+	case "search":
+		fmt.Fprintln(w, `search - Search prompts
+
+Usage: pkt search <query> [options]
+
+Options:
+  --format, -f <format>  Output format (table, json, ids, default)
+  --columns <list>       Table columns, as for 'pkt help list'
+  --no-truncate          Never cut table values to fit the terminal
+  --boolean, -b          Use boolean expression search
+  --all-sources          Also search every registered source (see 'pkt help sources')
+  --source <names>       Search only these sources, comma-separated (local = this library)
+
+Filters:
+  tag:<tag>              Only prompts with the tag
+  -tag:<tag>             Leave out prompts with the tag
+  title:<text>           Only prompts whose title contains the text
+  -title:<text>          Leave out prompts whose title contains the text
+  A * in a tag or title matches anything, so title:review* matches titles
+  starting with "review". Quote values with spaces: title:"code review".
+  The rest of the query is fuzzy matched as usual.
+
+Fields:
+  Free text matches the title, summary, ID and tags, here, from the server
+  and in the TUI's / filter alike. Choose the fields, from title, summary,
+  id, tags and content, under "search" in .pocket-prompt/config.json:
+
+    "search": {"fields": ["title", "tags", "content"]}
+
+Examples:
+  pkt search "machine learning"
+  pkt search "review tag:code -tag:draft"
+  pkt search --boolean "(ai AND analysis) OR writing"
+  pkt search "onboarding" --all-sources
+  pkt search "brief" --source team,acme`)
+
+	case "path":
+		fmt.Fprintln(w, `path - Print the path of a prompt's file
+
+Usage: pkt path <id> [--reveal]
+
+Prints the absolute path of the markdown file a prompt is stored in, with
+its frontmatter, for editors and scripts. --reveal also shows the file in
+the file manager (on Linux, opens its folder). In the TUI, press O on a
+prompt to do the same, and R to read the file as stored.
+
+Examples:
+  vim "$(pkt path code-review)"
+  pkt path code-review --reveal`)
+
+	case "sources", "source":
+		fmt.Fprintln(w, `sources - Register other libraries for federated search
+
+Usage:
+  pkt sources list                                  List registered sources
+  pkt sources add <name> <dir|url> [options]        Register a library directory or server
+  pkt sources add <name> <repo> --mirror [options]  Mirror a public git repository
+  pkt sources refresh [name]                        Update mirrors from upstream now
+  pkt sources remove <name>                         Unregister a source
+
+Options:
+  --api-key-env <VAR>   Environment variable holding the server's API key
+  --branch <branch>     Branch a mirror tracks (default: the repository default)
+  --refresh <interval>  How often a mirror is refreshed (default: 1h)
+
+A source is another library directory, a running server reached by
+http(s)://host:port or unix:/path/to/socket, or a mirror. A mirror is a
+read-only clone of a public repository kept in your cache directory; it
+always tracks upstream and is never written to, unlike an installed pack.
+Server sources are saved in your own config rather than the library's, since
+they are sent the API key from --api-key-env. 'pkt search --all-sources'
+merges results from every source and labels each with its source name; in
+the TUI, press S to switch between sources.
+
+Examples:
+  pkt sources add team ~/work/team-prompts
+  pkt sources add acme https://prompts.acme.example --api-key-env ACME_PKT_KEY
+  pkt sources add community https://github.com/example/prompts.git --mirror --refresh 6h
+  pkt search "onboarding" --all-sources`)
+
+	case "suggest":
+		fmt.Fprintln(w, `suggest - Suggest prompts for the project you are in
+
+Usage: pkt suggest [options]
+
+Options:
+  --dir <path>          Project to inspect (default: the working directory)
+  --limit, -n <n>       Number of suggestions (default: 5, 0 for all)
+  --format, -f json     Print the detected project and suggestions as JSON
+
+suggest looks at the repository around the directory for its languages and
+frameworks (go.mod, package.json, pyproject.toml, Cargo.toml and the like),
+tools (Dockerfile, .github/workflows, *.tf) and the name of its origin
+remote, then ranks prompts by those terms: a match in the tags counts most,
+then in metadata values, then in the title or description. Tags may carry a
+namespace, so lang/go matches a Go project.
+
+Examples:
+  pkt suggest
+  pkt suggest --dir ~/src/billing-api --limit 10`)
+
+	case "project":
+		fmt.Fprintln(w, `project - Keep prompts with the codebase they belong to
+
+Usage:
+  pkt project           Show the project library in use and its prompts
+  pkt project init      Create a project library in the current directory
+
+A project library is a .pocket-prompt/ directory at the root of a project,
+committed with the code. pkt finds it by walking up from the working
+directory, as git finds .git, and by default merges it with your own
+library: its prompts and templates are listed first and win when IDs clash,
+edits to them are saved back into the project, and your personal prompts
+stay in ~/.pocket-prompt (or POCKET_PROMPT_DIR). Set project.mode in your
+library's config to choose:
+
+  merge      Use both libraries (default)
+  override   Use only the project library while inside the project
+  off        Ignore project libraries
+
+  {"project": {"mode": "override"}}
+
+POCKET_PROMPT_PROJECT_MODE sets the mode for a single command.
+
+Examples:
+  pkt project init
+  pkt create review-checklist --project --title "PR Review Checklist"
+  POCKET_PROMPT_PROJECT_MODE=off pkt list`)
+
+	case "create", "new":
+		fmt.Fprintln(w, `create - Create a new prompt
+
+Usage: pkt create <id> [options]
+
+Options:
+  --title <title>        Prompt title
+  --description <desc>   Prompt description
+  --content <content>    Prompt content
+  --template <id>        Template to use
+  --tags <tag1,tag2>     Comma-separated tags
+  --pack <pack>          Pack to save to (default: cli.pack in the config, or personal)
+  --dir <path>           Subdirectory of the prompts folder (e.g. clients/acme)
+  --variant-group <name> Mark the prompt as a variant of an experiment
+  --lang <language>      Language it is written in (default: detected)
+  --project              Save to the project library instead (see 'pkt help project')
+  --stdin                Read content from stdin
+
+Examples:
+  pkt create my-prompt --title "My Prompt" --content "Hello world" --pack "personal"
+  pkt create acme-brief --title "Acme Brief" --dir clients/acme`)
+
+	case "edit":
+		fmt.Fprintln(w, `edit - Edit an existing prompt
+
+Usage: pkt edit <id> [options]
+
+With no options, the prompt's file opens in your editor and is saved as the
+next version when you quit. The editor is cli.editor in your own config, then
+$VISUAL, then $EDITOR.
+
+Options:
+  --title <title>        New title
+  --description <desc>   New description
+  --content <content>    New content
+  --template <id>        Template to use
+  --variant-group <name> Experiment the prompt is a variant of ("" to clear)
+  --lang <language>      Language it is written in, such as de or pt-BR
+  --tags <tag1,tag2>     Replace the tags
+  --add-tag <tag>        Add a tag
+  --remove-tag <tag>     Remove a tag
+  --pack <pack>          Move the prompt to another pack
+  --force                Edit without asking when someone else has locked it
+  --force-protected      Edit a protected prompt (see 'pkt help protect')
+  --if-version <v>       Refuse the edit unless the prompt is still at version
+                         v (or API revision v), such as when a git sync or
+                         another editor changed it since you read it
+
+Examples:
+  pkt edit my-prompt
+  pkt edit my-prompt --add-tag reviewed
+  pkt edit my-prompt --if-version 1.2.0 --content "..."`)
+
+	case "log":
+		fmt.Fprintln(w, `log - Log how a run of a prompt went
+
+Usage:
+  pkt log <id> --outcome good|bad [--note <text>]
+  pkt log <id> [--format table|json]
+
+Each outcome is appended to a log next to the prompt file
+(prompts/<id>.outcomes.jsonl) with the time, the prompt version that was run
+and your git user.name. Outcomes logged on different machines merge in git.
+Without --outcome, the log is listed with a count of good and bad results.
+In the TUI, press R in a prompt's detail view to see its results.
+
+Options:
+  --outcome, -o <good|bad>  Result of the run
+  --note, -m <text>         What went well or wrong
+  --format, -f <format>     table or json when listing
+
+Examples:
+  pkt log code-review --outcome good --note "caught the off-by-one"
+  pkt log code-review --outcome bad -m "ignored the style guide"
+  pkt log code-review --format table`)
+
+	case "lock", "unlock", "locks":
+		fmt.Fprintln(w, `lock - Tell teammates you are editing a prompt
+
+Usage:
+  pkt lock <id> [--note <text>] [--for <duration>] [--force]
+  pkt unlock <id> [--force]
+  pkt locks [--format table|json]
+
+Locks are advisory: they never stop a save, but 'pkt edit' and the TUI warn
+before editing a prompt someone else has locked. Each lock is a file under
+locks/ in the library, committed and shared by git sync, and lapses after
+8 hours unless --for says otherwise. The owner is your git user.name.
+
+Options:
+  --note, -m <text>      What you are changing, shown to others
+  --for <duration>       How long the lock lasts (e.g. 30m, 2h)
+  --force                Take over or release someone else's lock
+
+Examples:
+  pkt lock onboarding --note "rewriting for the new API"
+  pkt locks --format table
+  pkt unlock onboarding`)
+
+	case "protect", "unprotect":
+		fmt.Fprintln(w, `protect - Guard critical prompts against accidental changes
+
+Usage:
+  pkt protect [id...]
+  pkt unprotect <id...>
+
+A protected prompt cannot be deleted, edited, overwritten by an import or
+changed by a bulk retag unless the command is run with --force-protected.
+Bulk changes skip protected prompts and report them. The API and TUI refuse
+to change them.
+
+protect sets protected: true in the prompt's frontmatter; with no ID it lists
+the protected prompts. Whole groups can be protected in
+.pocket-prompt/config.json with ID patterns, which unprotect cannot remove:
+
+  {"storage": {"protected": ["prod-*", "onboarding-email"]}}
+
+Examples:
+  pkt protect checkout-flow
+  pkt delete checkout-flow --force-protected
+  pkt boolean-search run "prod" --add-tag reviewed --force-protected
+  pkt unprotect checkout-flow`)
+
+	case "templates":
+		fmt.Fprintln(w, `templates - List templates
+
+Usage:
+  pkt templates
+  pkt templates show <id>
+  pkt templates --stats [--format table|json]
+
+--stats lists how many prompts use each template and how often those prompts
+have been rendered or copied on any of your devices, most used first. Each
+device keeps its render counts in .pocket-prompt/template-usage/<device>.json,
+so git sync adds them up without conflicts.
+
+Examples:
+  pkt templates --stats
+  pkt templates --stats --format json`)
+
+	case "template":
+		fmt.Fprintln(w, `template - Template management
+
+Usage: pkt template <subcommand> [options]
+
+Subcommands:
+  create <id>     Create a new template
+  edit <id>       Edit an existing template
+  delete <id>     Delete a template
+  show <id>       Show template details
+
+Create Options:
+  --name <name>           Template name
+  --description <desc>    Template description
+  --content <content>     Template content
+  --slots <slot1,slot2>   Comma-separated slot names
+  --stdin                 Read content from stdin
+
+Edit Options:
+  --name <name>           Update template name
+  --description <desc>    Update template description
+  --content <content>     Update template content
+  --slots <slot1,slot2>   Update slot names
+
+Delete Options:
+  --force, -f             Force deletion without confirmation
+
+Deleting a template that prompts use asks for confirmation naming how many;
+a heavily used one (5 or more prompts, or 25 or more renders) also prints a
+warning, even with --force. Prompts using a deleted template render without it.
+
+Examples:
+  pkt template create my-template --name "My Template" --content "Hello {{name}}"
+  pkt template edit my-template --content "Updated content"`)
+
+	case "search-saved":
+		fmt.Fprintln(w, `search-saved - Manage saved searches
+
+Usage: pkt search-saved [subcommand] [options]
+
+Subcommands:
+  (none)                      List saved searches
+  run <name>                  Execute a saved search
+  pin <name>                  Apply a saved search when the TUI opens and
+                              when 'pkt list' runs without options
+  unpin                       Stop applying the pinned search
+  subscribe <name>            Report prompts that git sync, imports and
+                              remote sync make match the search
+  unsubscribe <name>          Stop following a search
+  new [name]                  List the new matches since they were last
+                              acknowledged, of one search or all of them
+  ack <name>                  Acknowledge the new matches of a search
+
+Run Options:
+  --text, -t <query>          Fuzzy filter the results, replacing the saved text
+  --format, -f <format>       Output format (table, json, ids, default)
+
+New Options:
+  --ack                       Acknowledge the matches once listed
+  --format, -f json           Print the matches as JSON
+
+The TUI pins and unpins the selected search with P in the saved searches
+view, and subscribes with s. A badge above the library counts unacknowledged
+matches; running the search there acknowledges them. Scheduled digests list
+them too. Subscriptions belong to the device and are never committed.
+
+Examples:
+  pkt search-saved pin client-acme
+  pkt list --all
+  pkt search-saved subscribe needs-review
+  pkt search-saved new needs-review --ack`)
+
+	case "boolean-search":
+		fmt.Fprintln(w, `boolean-search - Manage boolean searches
+
+Usage: pkt boolean-search <subcommand> [options]
+
+Subcommands:
+  create <name> <expression>  Create a new saved boolean search
+  edit <name> <expression>    Edit an existing saved boolean search  
+  delete <name>               Delete a saved boolean search
+  list                        List all saved boolean searches
+  run <expression>            Execute a boolean search expression
+  run --saved <name>          Execute a saved boolean search
+  explain <expression>        Show how an expression parses, what each part
+                              matches, and where any syntax error is
+
+Run Options:
+  --format, -f <format>       Output format
+  --add-tag <tag>             Add a tag to every result (repeatable)
+  --remove-tag <tag>          Remove a tag from every result (repeatable)
+  --dry-run, -n               With --add-tag or --remove-tag, list the changes
+                              without making them
+
+With --add-tag or --remove-tag, run changes the tags of every result instead
+of listing them. Each changed prompt gets a new version and the change is
+synced as one git commit. Prompts from other sources are skipped, as are ones
+whose tag to remove comes from their folder.
+
+Explain Options:
+  --format, -f <format>       Output format (text, json)
+
+Delete Options:
+  --force, -f                 Force deletion without confirmation
+
+Examples:
+  pkt boolean-search create ai-search "(ai AND analysis) OR machine-learning"
+  pkt boolean-search run "(python AND tutorial) OR beginner"
+  pkt boolean-search run --saved ai-search
+  pkt boolean-search run "draft AND reviewed" --add-tag ready --remove-tag draft --dry-run
+  pkt boolean-search explain "(python AND tutorial) OR NOT beginner"`)
+
+	case "copy", "render":
+		fmt.Fprintln(w, `copy - Copy a rendered prompt to the clipboard
+render - Print a rendered prompt
+
+Usage:
+  pkt copy <id> [options]
+  pkt render <id> [options]
+
+Options:
+  --format, -f json       Render as a JSON message array for LLM APIs
+  --format, -f <name>     Render with a plugin formatter (see 'pkt help plugins')
+  --images base64|path    How JSON output includes images (default: base64)
+  --profile, -p <name>    Fill in variables from profiles/<name>.yaml
+  --var <name>=<value>    Set a variable, overriding the profile (repeatable)
+  --redact                Apply the library's redaction rules (see 'pkt help export')
+  --allow-cmd             Run {{cmd:...}} placeholders
+  --fresh                 Don't fill in the values last given for this prompt
+  --section <heading>     Only the part under this Markdown heading, up to the
+                          next heading of the same level
+  --variant random        Treat <id> as a variant group and render one of its
+                          prompts at random (see 'pkt help variants')
+
+{{name}} placeholders in the prompt are replaced by variable values, and
+variables also fill template slots. Placeholders without a value are left as
+they are. A profile is a YAML file of values you reuse across prompts, such as
+a client's brand name, tone and URLs; 'pkt profiles' lists them.
+
+The values given with --var are remembered for the prompt on this device and
+fill in its variables the next time, under the profile and any new --var, so
+a prompt rendered again and again only needs what changed. Sensitive values
+are never remembered. Pass --fresh to start from the defaults instead.
+
+For long prompts, --section picks out one part, such as "Output format".
+Headings match without regard to case; when none does, the error lists the
+prompt's sections. In the TUI, n and p move between sections of the open
+prompt and c copies the current one.
+
+Variables declared with an env or keychain source are read from there, and
+sensitive variables nothing else provides are asked for without echo. Avoid
+--var for secrets, since the command line is saved in your shell history.
+
+Context placeholders pull in the directory you run pkt from: {{cwd}},
+{{git.branch}}, {{git.commit}}, {{git.root}} and {{file:path}}, which inserts
+a file of up to 64 KB. {{cmd:command}} inserts a shell command's output, but
+only with --allow-cmd, so copying a prompt never runs anything unasked.
+Variables with the same name take precedence.
+
+When the prompt has an output schema (see 'pkt help eval'), JSON output is a
+request body with "messages" and a "response_format" block holding the schema.
+
+Images are referenced inline with {{image:assets/chart.png}} or listed under
+"images" in the frontmatter, as paths under assets/ or http(s) URLs.
+JSON output turns them into multimodal content parts: data: URLs with base64,
+or file:// URLs with --images path. Plain text shows [image: path] markers.
+
+Examples:
+  pkt copy code-review
+  pkt copy describe-chart --format json
+  pkt copy describe-chart --format json --images path
+  pkt render launch-email --profile client-acme
+  pkt render launch-email --profile client-acme --var tone=playful`)
+
+	case "variants":
+		fmt.Fprintln(w, `variants - Compare the variants of an A/B experiment
+
+Usage: pkt variants <group> [--format table|json]
+
+Prompts with the same variant_group in their frontmatter are variants of one
+experiment. This lists them side by side with how often each was copied or
+rendered and the good and bad outcomes logged with 'pkt log'.
+
+For blind testing, render a random variant with --variant random. The rendered
+prompt goes to stdout and the chosen ID to stderr, so you can log the outcome
+against it afterwards without knowing which one you judged.
+
+Options:
+  --format, -f <format>  table or json
+
+Examples:
+  pkt create summary-terse --variant-group summary --content "Summarize in one line"
+  pkt render summary --variant random 2>variant.txt | llm
+  pkt log "$(cut -d' ' -f2 variant.txt)" --outcome good
+  pkt variants summary`)
+
+	case "preview":
+		fmt.Fprintln(w, `preview - Write a shareable HTML preview of a prompt or template
+
+Usage: pkt preview <id> [options]
+
+The preview is a single HTML file with its styles inline: the title,
+description, version and tags above the content formatted from Markdown.
+Images are embedded, so the file can be sent or hosted on its own. HTML
+written in the prompt itself is left out.
+
+Options:
+  --out, -o <file>        Write to a file instead of stdout
+  --template, -t          Preview the template <id>, listing its slots
+  --images base64|path    Embed images (default) or link to the files
+  --profile, -p <name>    Fill in variables from profiles/<name>.yaml
+  --var <name>=<value>    Set a variable (repeatable)
+  --redact                Apply the library's redaction rules
+  --variant random        Preview a random variant of the group <id>
+
+The HTTP API serves the same page at /api/v1/prompts/{id}/preview and
+/api/v1/templates/{id}/preview.
+
+Examples:
+  pkt preview code-review --out code-review.html
+  pkt preview launch-email --profile client-acme --redact -o share.html
+  pkt preview meeting-notes --template > template.html`)
+
+	case "share":
+		fmt.Fprintln(w, `share - Write a prompt as one Markdown document to paste anywhere
+
+Usage: pkt share <id> [--out <file.md> | --clipboard]
+
+The document is self-contained: frontmatter with the prompt's ID, title,
+description, version and tags, then its content with any template applied,
+a table of its variables and a footer saying where it came from.
+Placeholders are left for the reader to fill in, and sensitive variables
+never show their defaults.
+
+Options:
+  --out, -o <file>        Write to a file instead of stdout
+  --clipboard, -c         Copy to the clipboard instead of printing
+  --redact                Apply the library's redaction rules
+
+Examples:
+  pkt share code-review --out code-review.md
+  pkt share launch-email --redact --clipboard`)
+
+	case "cat":
+		fmt.Fprintln(w, `cat - Render several prompts and join them into one
+
+Usage: pkt cat <id> <id>... [options]
+
+The prompts are rendered in the order given and joined with a blank line
+between them, for composing one large prompt from building blocks in the
+library such as a persona, a task and an output format.
+
+Options:
+  --separator, -s <text>  Put <text> on a line of its own between prompts
+  --copy, -c              Copy the result to the clipboard instead of printing
+  --vars <id>=<file>      Fill one prompt's variables from a YAML or JSON file,
+                          over the values given for every prompt (repeatable)
+
+The options of 'pkt render' (--var, --profile, --section, --redact, --fresh
+and --allow-cmd) apply to every prompt. Values given to cat are not
+remembered as the prompts' saved answers.
+
+Examples:
+  pkt cat persona-reviewer code-review output-table --separator "---"
+  pkt cat brand-voice launch-email --profile client-acme --copy
+  pkt cat intro summarize --vars summarize=summarize.yaml --var audience=execs`)
+
+	case "speak":
+		fmt.Fprintln(w, `speak - Read a prompt aloud
+
+Usage: pkt speak <id> [options]
+
+Renders the prompt and reads it aloud with the system's text-to-speech
+engine: say on macOS, espeak-ng or espeak on Linux and System.Speech on
+Windows. Markdown is read as prose, without heading marks, bullets or
+link targets.
+
+Options:
+  --voice <name>          A voice the engine knows, such as Samantha or en-gb
+  --rate <wpm>            Words per minute
+  --out, -o <file>        Write the speech to an audio file instead: .aiff or
+                          .m4a with say, .wav elsewhere, and other formats
+                          such as .mp3 when ffmpeg is installed
+
+The options of 'pkt render' (--var, --profile, --section, --redact, --fresh
+and --allow-cmd) choose what is read.
+
+Examples:
+  pkt speak code-review
+  pkt speak launch-email --section "Call to action" --rate 160
+  pkt speak onboarding-guide --out onboarding.mp3`)
+
+	case "profiles", "profile":
+		fmt.Fprintln(w, `profiles - List variable profiles
+
+Usage: pkt profiles
+
+A profile is a YAML file in the library's profiles/ directory mapping variable
+names to values, for example profiles/client-acme.yaml:
+
+  brand: Acme Corp
+  tone: friendly but concise
+  website: https://acme.example
+
+'pkt render <id> --profile client-acme' fills {{brand}}, {{tone}} and
+{{website}} from it. In the TUI, press v on a prompt to cycle through profiles.`)
+
+	case "attach", "detach":
+		fmt.Fprintln(w, `attach - Attach files to a prompt
+
+Usage:
+  pkt attach <id> <file>...             Copy files into assets/<id>/ and attach them
+  pkt detach <id> <path> [--delete]     Remove an attachment, deleting the file with --delete
+
+Attachments are images for multimodal prompts, example inputs, and other
+files a prompt refers to. They are stored under assets/ in the library,
+listed in the prompt's "attachments" frontmatter, shown as links by 'pkt get'
+and the TUI, and exported alongside prompts. Use 'pkt git setup <url> --lfs'
+or 'pkt git lfs' to keep large files in Git LFS.
+
+Examples:
+  pkt attach describe-chart ~/Pictures/chart.png
+  pkt detach describe-chart assets/describe-chart/chart.png --delete`)
+
+	case "eval":
+		fmt.Fprintln(w, `eval - Validate sample outputs against a prompt's output schema
+
+Usage: pkt eval <id> <output-file>...
+
+Each file holds one JSON response from a model; use - to read stdin.
+Violations are listed with the JSON path they apply to, and the command
+fails when any sample is invalid.
+
+A prompt's output schema is a JSON Schema document, given either under
+"output_schema" in the frontmatter or in a sidecar file next to the prompt
+with the same name and a .schema.json extension (prompts/triage.md pairs with
+prompts/triage.schema.json). The frontmatter wins when both exist.
+
+Examples:
+  pkt eval triage samples/triage-1.json samples/triage-2.json
+  llm "..." | pkt eval triage -`)
+
+	case "lint":
+		fmt.Fprintln(w, `lint - Check prompts against the library's style rules
+
+Usage: pkt lint [id...] [--strict] [--staged-only] [--format text|json]
+
+Checks every prompt, or the given ones, and fails when a rule at error
+severity is broken; --strict fails on warnings too. --staged-only checks just
+the prompt files staged for commit, as staged, which is what the pre-commit
+hook from 'pkt hooks install' runs.
+
+Files whose frontmatter does not parse or has no id, and prompts sharing an
+id, are always errors. Style rules:
+
+  max-tokens         Estimated tokens over "max_tokens" (default: error)
+  forbidden-phrase   Any of "forbidden_phrases", ignoring case (default: error)
+  required-section   A heading or "Name:" line for each of "required_sections"
+                     is missing (default: error)
+  heading-structure  Empty headings and skipped heading levels (default: warning)
+  passive-voice      Phrases such as "is written" that suggest passive voice
+                     (default: warning)
+
+Configure them under "lint" in .pocket-prompt/config.json:
+
+  "lint": {
+    "max_tokens": 2000,
+    "forbidden_phrases": ["as an AI language model"],
+    "required_sections": ["Output format"],
+    "severity": {"passive-voice": "off", "max-tokens": "warning"}
+  }
+
+Silence rules inside a prompt with HTML comments:
+
+  <!-- pkt-lint-disable passive-voice -->              whole prompt
+  <!-- pkt-lint-disable-next-line forbidden-phrase --> the next line only
+
+A comment without rule names silences every rule.
+
+Examples:
+  pkt lint
+  pkt lint code-review --strict
+  pkt lint --format json > lint.json`)
+
+	case "maintenance":
+		fmt.Fprintln(w, `maintenance - Tidy the library and report disk usage
+
+Usage: pkt maintenance [--dry-run] [--format text|json]
+
+Steps:
+  1. Archive prompts whose expires date has passed
+  2. Remove archived versions outside the retention policy
+  3. Remove empty directories under prompts/, templates/, archive/ and assets/
+  4. Rebuild the metadata index from the prompt files
+  5. Verify the git repository with git fsck, then let git gc compact it
+  6. Report disk usage by category: prompts, archive, assets, git and so on
+
+--dry-run lists what would be removed without changing anything. The command
+fails when git fsck reports problems.
+
+Archived versions are kept until a policy is set under "maintenance" in
+.pocket-prompt/config.json. A version is removed when it is older than
+max_age_days or beyond the newest keep_versions of its prompt:
+
+  "maintenance": {"keep_versions": 10, "max_age_days": 365, "interval": "24h"}
+
+With "interval" set, the URL server runs maintenance on that schedule. Older
+versions stay in git history when the library is synced with git.
+
+A prompt with "expires: 2026-12-31" in its frontmatter is archived by the
+first run after that day ends and removed from the library; restoring its
+archived version brings it back. Protected prompts are kept. Prompts that
+expire within warning_days (default 7) are listed. Set "expired" to "warn"
+to only report expired prompts instead of archiving them:
+
+  "maintenance": {"expired": "warn", "warning_days": 14}
+
+Examples:
+  pkt maintenance --dry-run
+  pkt maintenance --format json`)
+
+	case "bench":
+		fmt.Fprintln(w, `bench - Generate synthetic libraries and measure performance
+
+Usage:
+  pkt bench generate [--count N] [--seed N] [--dir <path>]
+  pkt bench run [--dir <path>] [--iterations N] [--duration 3s] [--concurrency N] [--format text|json]
+
+generate writes N synthetic prompts (default 1000) to a new directory,
+./pocket-prompt-bench unless --dir is given. The content depends only on the
+count and seed, so the same command gives the same library on any machine.
+
+run measures the library at --dir, or the current library: opening and
+listing it, parsing every prompt file, fuzzy, filtered and boolean search,
+getting and rendering each prompt, and serving list, get and search requests
+over the URL server from --concurrency clients for --duration. The library
+is opened read-only for the measurements, so renders do not count as uses.
+
+Compare runs on the same generated library before and after a change to see
+its effect on the cache, parser or search.
+
+Examples:
+  pkt bench generate --count 10000 --dir /tmp/bench-10k
+  pkt bench run --dir /tmp/bench-10k
+  pkt bench run --dir /tmp/bench-10k --iterations 50 --format json > baseline.json`)
+
+	case "doctor":
+		fmt.Fprintln(w, `doctor - Diagnose the library
+
+Usage: pkt doctor [--size | --perf [--clear] | --platform] [--format text|json]
+
+--size, the check run when none is given, reports the library's size
+without git repositories and .pocket-prompt, the prompts and bytes in the
+personal library and each pack, and the largest files, and warns about soft
+limits the library is over or close to. Limits are set under "quota" in
+.pocket-prompt/config.json and only ever warn:
+
+  "quota": {"max_prompts_per_pack": 500, "max_total_size": "50MB"}
+
+Warnings start once a limit is warn_percent full (default 90). The TUI shows
+the first of them in its status bar.
+
+--perf times every saved search against the library, slowest first, and
+lists the searches logged as slow, most total time first. A search is logged
+when it takes longer than the threshold, whether run from the TUI, the CLI or
+the server, with the library size and the complexity of its query: filters
+and words for a search, tags and operators for a boolean expression.
+
+The threshold is 200ms unless set under "search" in .pocket-prompt/config.json,
+where "off" turns logging off:
+
+  "search": {"slow_query": "500ms"}
+
+The log is kept in .pocket-prompt/slow-queries.jsonl, newest 500 entries.
+--clear empties it after the report, to measure again after a change.
+
+--platform reports the system pkt runs on: the clipboard backend copies go
+to, where git is or that sync is built in without it, and which of the programs
+pkt runs when installed are found. The backends are set under "platform" in
+.pocket-prompt/config.json:
+
+  "platform": {"clipboard": "osc52", "git": "exec"}
+
+clipboard is auto (default), command for clipboard utilities only, native
+for the system clipboard API, or osc52 for the terminal's clipboard, which
+works over SSH and in containers but cannot be read. Auto uses the
+configured or a detected utility, the native clipboard on Windows, and OSC 52
+when there is no utility but there is a terminal. git is auto (default),
+which syncs with a git implementation built into pkt when git is not
+installed, exec, which refuses to start without git, or builtin, which always
+uses the built-in implementation. It commits, pulls and pushes, and merges
+changes to different files; merging changes to the same file, working
+branches, signed commits, history and review branches need git.
+
+Examples:
+  pkt doctor
+  pkt doctor --perf
+  pkt doctor --platform
+  pkt doctor --perf --format json | jq '.saved_searches[] | select(.slow)'`)
+
+	case "ci":
+		fmt.Fprintln(w, `ci - Run every validation check, for CI pipelines
+
+Usage: pkt ci [--format text|json|github] [--report <file>] [--strict]
+
+Checks:
+  lint          The style rules from 'pkt help lint'
+  integrity     Frontmatter that does not parse or has no id, duplicate IDs,
+                missing templates, attachments and images, and output
+                schemas that do not load
+  duplicates    Prompts with the same content as another, or whose word
+                pairs overlap by "similarity" or more (warnings)
+  token-budget  Prompts whose rendered text, template included, is over
+                "token_budget" or the lowest matching "tag_budgets" entry
+
+Budgets and the duplicate threshold are set under "ci" in
+.pocket-prompt/config.json:
+
+  "ci": {"token_budget": 4000, "tag_budgets": {"system": 800}, "similarity": 0.85}
+
+Output is text, a JSON report, or GitHub Actions annotations that mark
+findings on a pull request's files. Annotations are the default when
+GITHUB_ACTIONS is set; --report also writes the JSON report to a file.
+
+Exit codes: 0 when checks pass, 1 when any error is found (or any warning,
+with --strict), 2 when the checks could not run.
+
+Examples:
+  pkt ci
+  pkt ci --format json > ci-report.json
+  pkt ci --report ci-report.json --strict`)
+
+	case "hooks", "hook":
+		fmt.Fprintln(w, `hooks - Git hooks for the library repository
+
+Usage: pkt hooks install [--force]
+
+Installs a pre-commit hook that runs 'pkt lint --staged-only', so commits
+with broken frontmatter, duplicate prompt IDs or lint errors are blocked
+before they reach other people. Skip the check once with
+'git commit --no-verify'.
+
+The hook calls this pkt executable by its full path; run the command again
+after moving or reinstalling pkt. An existing pre-commit hook not written by
+pkt is left alone unless --force is given.
+
+Examples:
+  pkt hooks install`)
+
+	case "stats":
+		fmt.Fprintln(w, `stats - Per-prompt metrics for reporting
+
+Usage: pkt stats [--format table|json|csv]
+
+Columns: ID, title, pack, current version, number of versions (current plus
+archived), estimated tokens (about four characters each), words, tags, review
+state, created and last edited times, and usage count with the time of last
+use. Usage counts copies and renders on every device syncing the library:
+'pkt copy', the TUI copy keys, and the server's render endpoint. Each device
+keeps its counts in .pocket-prompt/usage/<device>.json, so they never conflict.
+
+The server provides the same data at GET /api/v1/stats?format=json|csv.
+
+Examples:
+  pkt stats
+  pkt stats --format csv > prompts.csv`)
+
+	case "analytics":
+		fmt.Fprintln(w, `analytics - Opt-in usage analytics kept on this device
+
+Usage:
+  pkt analytics [report] [--since <date>] [--format text|json]
+  pkt analytics status
+  pkt analytics enable <scope>... | all
+  pkt analytics disable <scope>... | all
+  pkt analytics purge
+
+Nothing is recorded until you enable a scope, and nothing recorded is ever
+sent anywhere. Each scope is opted into on its own:
+  uses       Prompts copied and rendered, for the report and 'pkt list
+             --sort frecency'
+  searches   Searches run from the CLI, the TUI and the server, with whether
+             they found anything
+  commands   Names of the CLI commands run, never their arguments
+  digest     List this device's most used prompts in the digests it sends
+
+The report covers the last 30 days unless --since says otherwise: the most
+used prompts by frecency (uses weighted by how recent they are, halving each
+week), the searches run and the commands run. status shows which scopes are
+on and how much has been recorded.
+
+Everything is kept in .pocket-prompt/analytics/ in the library: the scopes in
+settings.json and the events in events.jsonl. Git sync never commits it.
+disable stops recording but keeps the events; purge deletes them and keeps
+the scopes enabled.
+
+Examples:
+  pkt analytics enable uses searches
+  pkt analytics
+  pkt list --sort frecency
+  pkt analytics purge`)
+
+	case "changelog":
+		fmt.Fprintln(w, `changelog - Summarise prompt changes across versions
+
+Usage: pkt changelog [id] [options]
+
+Options:
+  --since <date>          Only changes on or after this date (YYYY-MM-DD)
+  --format, -f json       Output JSON instead of Markdown
+
+Each version of a prompt is compared with the one before it, listing lines
+added and removed and changes to the title, description, tags and template.
+Versions come from archive/ and from the library's git history, which also
+supplies authors and reports prompts deleted from the repository. The
+Markdown output is grouped by day, newest first, for use in release notes.
+In the TUI, press H on a prompt to show its history.
+
+Examples:
+  pkt changelog
+  pkt changelog code-review
+  pkt changelog --since 2026-01-01 > CHANGELOG.md`)
+
+	case "diff":
+		fmt.Fprintln(w, `diff - Compare two versions of a prompt
+
+Usage: pkt diff <id> [from] [to] [options]
+
+Options:
+  --stat                  Only count the lines added and removed
+  --format, -f json       Output JSON, with the diff as prefixed lines
+
+Without versions, the latest version is compared with the one before it;
+with one, that version is compared with the latest. Versions come from
+archive/ and the library's git history, as for 'pkt changelog', and may be
+written with a leading "v".
+
+Added lines are green and removed lines red. When a line was edited rather
+than rewritten, the words that changed are highlighted within it. Colors
+follow --no-color and NO_COLOR. 'pkt packs diff', the conflict preview of
+'pkt import --interactive' and the TUI's versions tab highlight the same way.
+
+Examples:
+  pkt diff code-review
+  pkt diff code-review 1.0.2
+  pkt diff code-review v1.0.2 v1.0.4 --stat`)
+
+	case "merge":
+		fmt.Fprintln(w, `merge - Resolve conflicted prompts hunk by hunk
+
+Usage: pkt merge [id|path...] [options]
+
+Options:
+  --list, -l              List the conflicted prompts without merging
+
+Opens each prompt file git left conflicted by a merge or pull in a merge
+view: the local version, the incoming one and the merged result side by
+side. Pick a side for every conflicting hunk, then press Enter to save.
+
+Keys:
+  ←, 1                    Keep the local lines
+  →, 2                    Take the incoming lines
+  b, 3                    Keep both, local first
+  u                       Undo the pick
+  n, p                    Next or previous conflict
+  Enter / Esc             Save / stop merging
+
+The merged prompt keeps the local title and tags and takes the version
+after the higher of the two sides. Each file is staged as it is resolved;
+finish with 'git commit'. Interactive imports open the same view when you
+press m on a changed prompt (see 'pkt help import').
+
+Examples:
+  pkt merge --list
+  pkt merge
+  pkt merge code-review`)
+
+	case "digest":
+		fmt.Fprintln(w, `digest - Send digests of library changes
+
+Usage: pkt digest [options]
+
+Options:
+  --send                  Send the digest now instead of printing it
+  --since <date>          Preview the changes since this date (YYYY-MM-DD)
+
+A digest lists the prompts added, updated and removed since the last one,
+taken from the changelog, or with "saved_search" set, that search's results.
+A running server (pkt --url-server) sends one each time "schedule" fires, a
+cron expression such as "0 9 * * mon" or @daily, @weekly or @monthly, in the
+server's local time. Digests with no changes are skipped unless "send_empty"
+is true. Without --send, 'pkt digest' prints the Markdown the next digest
+would contain. A device that opted in with 'pkt analytics enable digest uses'
+adds the prompts it used most in the period.
+
+Digests are configured under "digest" in .pocket-prompt/config.json. They
+are posted as JSON to "webhook", in the fields Slack, Mattermost and Discord
+incoming webhooks read, and emailed through "smtp" (host:port) from "from"
+to the "to" addresses. The mail server password is read from the variable
+named by password_env (default $POCKET_PROMPT_SMTP_PASSWORD). digest.smtp
+and digest.password_env are only read from your own config ('pkt config set
+digest.smtp smtp.fastmail.com:587'), since the password is sent to the
+server.
+
+Example configuration:
+  "digest": {
+    "schedule": "0 9 * * mon",
+    "webhook": "https://hooks.slack.com/services/...",
+    "from": "prompts@example.com",
+    "to": ["team@example.com"]
+  }`)
+
+	case "export":
+		fmt.Fprintln(w, `export - Export prompts and templates
+
+Usage: pkt export <type> [options]
+       pkt export <id>... | --tag <tag> --format pdf [options]
+
+Types:
+  prompts     Export all prompts
+  templates   Export all templates
+  all         Export prompts and templates
+
+Options:
+  --format, -f <format>   Export format: json, pdf, or a plugin exporter (see 'pkt help plugins')
+  --output, -o <file>     Output file (default: stdout)
+  --redact                Remove email addresses, API keys and other matches
+                          of the library's redaction rules
+  --tag, -t <tag>         PDF only: export the prompts with a tag
+  --title <title>         PDF only: the document's title
+
+With --output, prompt attachments are copied into an assets/ folder next to
+the file, where 'pkt import <file>' picks them up again. Redacted exports
+leave attachments out.
+
+PDF exports (--format pdf) typeset prompts for offline review: each on its
+own pages with a header of its metadata, its content and a table of its
+variables, and a contents page when there are several. Pick the prompts by
+ID, with --tag <tag> or with prompts for all of them; --title names the
+document. Only Western European characters print, others show as "?".
+
+Redaction rules live under "redaction" in .pocket-prompt/config.json. Each
+rule is a regular expression replaced by "[<name>]" unless it sets its own
+replacement; built-in rules for email addresses and API keys apply unless
+"no_defaults" is true:
+
+  "redaction": {"rules": [{"name": "client", "pattern": "(?i)acme corp"}]}
+
+Examples:
+  pkt export all --output backup.json
+  pkt export prompts --format json
+  pkt export prompts --redact --output share.json
+  pkt export --tag legal --format pdf --output legal.pdf`)
+
+	case "import":
+		fmt.Fprintln(w, `import - Import prompts and templates
+
+Usage: 
+  pkt import wizard [options]        # Find sources on this machine and import them
+  pkt import claude-code [options]   # Import from Claude Code
+  pkt import git-repo <repo-url> [options]  # Import from Git repository
+  pkt import promptlayer <export.json> [options]  # Import PromptLayer registry export
+  pkt import langfuse <export.json> [options]     # Import Langfuse prompt export
+  pkt import <importer> [args] [options]          # Import with a plugin importer
+  pkt import <file> [options]        # Import from JSON file
+
+Import Wizard Options:
+  The wizard finds Claude Code commands and agents, Obsidian vaults (their
+  prompts folder when they have one), folders named prompts and git
+  checkouts with prompts/ under ~/src, ~/code, ~/projects and similar. It
+  asks which to import, which tags to add, which pack each goes into and
+  what to do with prompts that already exist, then reports on every source.
+  --preview, --dry-run    Show what each source would import without importing
+  --tags <tag1,tag2>      Tags to add to everything imported
+  --yes, -y               Import every source found into the personal library
+                          without asking, skipping existing prompts
+
+Claude Code Import Options:
+  --path <path>           Directory to import from (default: current dir + ~/.claude)
+  --user                  When used with --path, also import from ~/.claude
+  --commands-only         Import only command files (.claude/commands/ and .claude/agents/)
+  --workflows-only        Import only GitHub Actions workflows
+  --config-only           Import only configuration files (CLAUDE.md)
+  --preview, --dry-run    Preview what would be imported without importing
+  --tags <tag1,tag2>      Additional tags to apply to imported items
+  --overwrite             Overwrite existing prompts/templates with same ID
+  --skip-existing         Skip items that already exist (no conflict errors)
+  --deduplicate           Skip duplicates based on original file path
+  --interactive, -i       Pick which items to import from a checklist with diffs
+  --watch                 Keep importing new and changed commands and agents until Ctrl+C
+                          (implies --deduplicate)
+  --interval <duration>   How often --watch checks for changes (default: 2s)
+
+Git Repository Import Options:
+  --owner-tag <tag>       Override owner tag (default: username from URL)
+  --temp-dir <path>       Temporary directory for cloning (default: system temp)
+  --branch <name>         Import from specific branch (default: repository default)
+  --depth <number>        Shallow clone depth (default: full clone)
+  --preview, --dry-run    Preview what would be imported without importing
+  --tags <tag1,tag2>      Additional tags to apply to imported items
+  --overwrite             Overwrite existing prompts/templates with same ID
+  --skip-existing         Skip items that already exist (no conflict errors)
+  --deduplicate           Skip duplicates based on original file path
+  --interactive, -i       Pick which items to import from a checklist with diffs
+
+Prompt Registry Import Options (promptlayer, langfuse):
+  --label <label>         Langfuse label whose version becomes current (default: latest;
+                          an error for PromptLayer)
+  --preview, --dry-run    Preview what would be imported without importing
+  --tags <tag1,tag2>      Additional tags to apply to imported items
+  --overwrite             Overwrite existing prompts with same ID
+  --skip-existing         Skip prompts that already exist
+  --interactive, -i       Pick which prompts to import from a checklist with diffs
+  Older registry versions are saved to archive/ as version history, unless the
+  prompt itself is skipped.
+
+Plugin Import Options (see 'pkt help plugins'):
+  --preview, --dry-run    Preview what would be imported without importing
+  --tags <tag1,tag2>      Additional tags to apply to imported items
+  --overwrite             Overwrite existing prompts with same ID
+  --skip-existing         Skip prompts that already exist
+  --interactive, -i       Pick which items to import from a checklist with diffs
+  Any other arguments are passed to the plugin.
+
+File Import Options:
+  --format, -f <format>   Import format (json)
+  --interactive, -i       Pick which items to import from a checklist with diffs
+
+Examples:
+  # Find prompts on this machine and pick what to import
+  pkt import wizard
+
+  # Import from current project + ~/.claude/commands and ~/.claude/agents
+  pkt import claude-code
+
+  # Preview what would be imported
+  pkt import claude-code --preview
+
+  # Choose items from a checklist, with diffs for changed prompts;
+  # press m on one to merge it hunk by hunk
+  pkt import claude-code --interactive
+
+  # Keep the library in sync with a project's commands and agents
+  pkt import claude-code --path /path/to/project --watch
+
+  # Import from specific directory only (without ~/.claude)
+  pkt import claude-code --path /path/to/project
+
+  # Import from specific directory + ~/.claude directories
+  pkt import claude-code --path /path/to/project --user
+
+  # Import from Git repository
+  pkt import git-repo https://github.com/user/prompts.git
+
+  # Import from Git repository with custom owner tag
+  pkt import git-repo https://github.com/user/prompts.git --owner-tag "team-ai"
+
+  # Preview Git repository import
+  pkt import git-repo https://github.com/user/prompts.git --preview
+
+  # Import from specific branch with additional tags
+  pkt import git-repo https://github.com/user/prompts.git --branch "development" --tags "experimental,dev"
+
+  # Import a Langfuse export, using the production-labelled version as current
+  pkt import langfuse prompts.json --label production
+
+  # Import from JSON backup
+  pkt import backup.json --format json`)
+
+	case "git":
+		fmt.Fprintln(w, `git - Git synchronization
+
+Usage: pkt git <subcommand>
+
+Subcommands:
+  setup <url>     Setup Git repository (handles everything automatically)
+                  --lfs tracks attachments under assets/ with Git LFS
+                  --depth <n> fetches only the last n commits of history
+                  --sparse <dir,...> checks out only those directories
+  lfs             Track attachments with Git LFS in an existing repository
+  status          Show git sync status
+  sync            Manual sync with remote repository  
+  pull            Pull changes from remote repository
+  enable          Enable git synchronization
+  disable         Disable git synchronization
+  branch [name]   Show branches, or sync edits to a working branch
+  pr              Push the working branch and open a pull request
+  sparse [dir...] Show or set the directories this clone checks out
+                  --add adds to them, --clear checks out everything again
+  unshallow       Fetch the full history of a shallow clone
+  auth test       Check the remote accepts the credentials for pulling and pushing
+  auth ssh-key <path>|--clear
+                  Use a private key for SSH remotes instead of ssh-agent
+  auth token --env <VAR>|--file <path>|--keychain <service/account>|--clear
+                  Read a personal access token for HTTPS remotes from there
+
+Branch options:
+  --main <branch>   Branch pull requests target (default: the remote's default)
+  --clear           Stop using a working branch and return to the main branch
+
+Pull request options:
+  --title <title>   Title (default: filled in from the commits)
+  --body <text>     Description
+  --draft           Open as a draft
+
+By default git sync commits and pushes whatever branch is checked out. With a
+working branch set, edits are synced to that branch instead, and it is kept
+up to date with the main branch on pull. 'pkt git pr' uses the gh CLI when it
+is installed; otherwise it prints a link for opening the pull request. The
+working branch belongs to this clone and is kept in .git/config; the main
+branch is shared through "git" in .pocket-prompt/config.json.
+
+Git never prompts for credentials during setup or 'pkt git auth test'; when
+the remote refuses them the error says why, e.g. "auth failed: token lacks
+repo scope". Tokens are only sent to the origin remote's host.
+
+For a large shared library, --depth and --sparse keep the clone small and
+sync quick. A sparse checkout belongs to this clone: it always includes
+.pocket-prompt/ and locks/, plus the files directly in the directories above
+the chosen ones. Prompts outside it cannot be saved, so everything edited
+here can still be pushed.
+
+Examples:
+  pkt git setup https://github.com/username/my-prompts.git
+  pkt git setup git@github.com:username/my-prompts.git
+  pkt git status
+  pkt git sync
+  pkt git branch alice/prompts
+  pkt git pr --title "New onboarding prompts"
+  pkt git branch --clear
+  pkt git setup git@github.com:acme/prompts.git --depth 1 --sparse packs/support,prompts/support
+  pkt git sparse --add packs/sales
+  pkt git auth token --keychain github.com/pocket-prompt
+  pkt git auth test`)
+
+	case "migrate":
+		fmt.Fprintln(w, `migrate - Upgrade prompt files to the current schema
+
+Rewrites every prompt file (prompts/, archive/, and installed packs) to the
+schema 2 frontmatter format. Legacy field names are renamed, missing versions
+are filled in, fields pocket-prompt does not know are moved into metadata,
+and prompts still carrying the old "archive" tag inside prompts/ are moved
+to archive/. Changes are committed when the library is a git repository.
+
+Usage: pkt migrate [options]
+
+Options:
+  --dry-run, --preview   Show planned changes without writing files
+  --no-commit            Do not create a git commit after migrating
+  --format, -f json      Output the migration report as JSON
+
+Examples:
+  pkt migrate --dry-run
+  pkt migrate`)
+
+	case "propose", "approve", "reject", "review":
+		fmt.Fprintln(w, `review - Propose and approve prompts in a shared library
+
+Proposed and rejected prompts are kept out of default listings and searches;
+prompts that have never been proposed count as approved. The review state is
+stored in the prompt's frontmatter.
+
+Usage:
+  pkt propose <id> [options]   Submit a prompt for review
+  pkt approve <id> [options]   Accept a proposed prompt
+  pkt reject <id> [options]    Send a proposed prompt back with a note
+  pkt review [--format json]   Show the review queue
+
+Options:
+  --note, -m <text>   Note recorded with the transition
+  --no-commit         Only update the frontmatter; leave git alone
+
+In a git library, propose switches to a review/<id> branch and commits the
+proposal there so it can be pushed for others to see. Approving from another
+branch merges review/<id> first, commits the approval and deletes the branch.
+Rejecting commits the note to review/<id> when it exists.
+
+Examples:
+  pkt propose onboarding-email -m "Ready for a look"
+  git push -u origin review/onboarding-email
+  pkt review
+  pkt approve onboarding-email
+  pkt reject onboarding-email -m "Needs a shorter intro"`)
+
+	case "remote":
+		fmt.Fprintln(w, `remote - Sync with a hosted prompt registry
+
+Mirrors prompts to and from a registry configured under "remote" in
+.pocket-prompt/config.json. remote.url and remote.secret_env are only read
+from your own config ('pkt config set remote.url ...'), since the secret is
+sent to the URL. Changes are detected by content hash against the last sync;
+prompts edited on both sides are reported as conflicts.
+
+Usage: pkt remote <subcommand> [options]
+
+Subcommands:
+  pull        Fetch registry changes into the library
+  push        Publish library changes to the registry
+  sync        Push and pull in one pass
+  status      Show what sync would do without changing anything
+
+Options:
+  --dry-run, --preview     Show planned actions without applying them
+  --prefer local|remote    Resolve conflicts in favour of one side
+  --format, -f json        Output the plan as JSON
+
+Configuration (.pocket-prompt/config.json):
+  "remote": {
+    "adapter": "langfuse",              // or "rest"
+    "url": "https://cloud.langfuse.com", // your own config only
+    "public_key": "pk-lf-...",
+    "secret_env": "LANGFUSE_SECRET_KEY", // env var holding the secret or token; your own config only
+    "label": "production",              // langfuse: version label to pull
+    "push_labels": ["staging"],         // langfuse: labels for pushed versions
+    "tag": "shared"                     // only sync prompts with this tag
+  }
+
+Examples:
+  pkt remote status
+  pkt remote sync --prefer local`)
+
+	case "open", "url-scheme":
+		fmt.Fprintln(w, `open - Open pocket-prompt:// deep links
+
+Links can be placed in notes apps, docs, or bookmarks:
+  pocket-prompt://prompt/<id>   Open the TUI at the prompt
+  pocket-prompt://copy/<id>     Copy the rendered prompt to the clipboard
+
+Usage:
+  pkt open <link>
+  pkt url-scheme install      Register this binary as the link handler
+  pkt url-scheme uninstall    Remove the link handler
+
+Registration writes a desktop entry on Linux (xdg-mime), a small applet in
+~/Applications on macOS, and per-user registry keys on Windows.
+
+Examples:
+  pkt url-scheme install
+  pkt open pocket-prompt://copy/code-review`)
+
+	case "server":
+		fmt.Fprintln(w, `server - HTTP API server helpers
+
+Usage:
+  pkt server qr [--host <host>] [--port <port>]   Show the server address as a QR code
+  pkt server keys list                             List API keys
+  pkt server keys add <name> [--scope <scope>]     Create a key (scope: read, write, admin)
+  pkt server keys revoke <name>                    Revoke a key
+
+Once any key exists, every request must send 'Authorization: Bearer <key>' or
+'X-API-Key: <key>'. read keys may use GET endpoints, write keys may also change
+prompts, and admin keys may additionally read the audit log at /api/v1/audit.
+Changes and rejected requests are logged with the key name in
+.pocket-prompt/audit.log. Keys are stored hashed in .pocket-prompt/api-keys.json,
+which git sync never commits, so each device's server has its own keys. They
+take effect on a running server without a restart.
+
+Examples:
+  pkt server keys add raycast --scope write
+  pkt server keys add dashboard
+  pkt server keys revoke raycast`)
+
+	case "email":
+		fmt.Fprintln(w, `email - Capture prompts by email
+
+Usage:
+  pkt email check   Import matching messages that arrived since the last check
+
+The gateway reads an IMAP mailbox configured under "email" in
+.pocket-prompt/config.json. Messages whose subject starts with the prefix
+(default "[pkt]") become prompts: #hashtags in the subject become tags, the
+rest of the subject becomes the title, and the plain-text body becomes the
+content. Imported messages are marked read. The mailbox password is read from
+the variable named by password_env (default $POCKET_PROMPT_EMAIL_PASSWORD).
+email.server and email.password_env are only read from your own config
+('pkt config set email.server imap.example.com:993'), since the password is
+sent to the server.
+
+A running server (pkt --url-server) checks the mailbox on its own every
+interval (default 5m); 'pkt email check' runs a single check, e.g. from cron.
+
+Example subject:
+  [pkt] Summarise a support ticket #support #summaries`)
+
+	case "summarize":
+		fmt.Fprintln(w, `summarize - Write summaries for prompts
+
+Usage: pkt summarize [id...] [options]
+
+Proposes a summary for each prompt given, or every prompt, and saves the
+ones you accept as the prompt's description in a new version. Summaries
+help search find prompts, and imported prompts often have none.
+
+Summaries come from the command set with 'pkt config set summarize.command',
+which gets each prompt's content on stdin and
+prints its summary, so any LLM command line tool can write them. Without a
+command the summary is the prompt's first sentence.
+
+In a terminal each proposal is shown and you choose to save it, skip it,
+edit it first or stop. Elsewhere proposals are only listed unless --yes is
+given.
+
+Options:
+  --missing-only, -m    Only prompts without a summary
+  --yes, -y             Save every proposal without asking
+  --dry-run, -n         Only show the proposals
+  --format, -f json     List proposals (and saved versions, with --yes) as JSON
+
+Examples:
+  pkt summarize --missing-only
+  pkt summarize code-review --dry-run
+  pkt summarize --missing-only --yes
+  pkt config set summarize.command 'llm -s "Summarize this prompt in one sentence"'`)
+
+	case "autotag":
+		fmt.Fprintln(w, `autotag - Propose tags for sparsely tagged prompts
+
+Usage: pkt autotag [id...] [options]
+
+Proposes tags for each prompt given, or for every prompt with fewer than
+two tags, shows them as a diff and adds them once you confirm, each prompt
+getting a new version and the whole change a single Git commit. It
+retrofits structure onto large imported collections.
+
+Without a command, tags the library already uses that a prompt mentions
+come first, then words the prompt uses often. The command set with
+'pkt config set autotag.command' gets each prompt's content on stdin and the library's tags in $POCKET_PROMPT_TAGS,
+and prints the tags to add separated by commas or lines, so any LLM command
+line tool can choose them. "max_tags" and "min_tags" set how many tags are
+proposed and which prompts count as sparsely tagged.
+
+Options:
+  --dry-run, -n         Only show the proposed tags
+  --yes, -y             Add them without asking
+  --max n               Propose at most n tags per prompt (default 3)
+  --format, -f json     List proposals (and new versions, with --yes) as JSON
+
+Examples:
+  pkt autotag --dry-run
+  pkt autotag imported-prompt --max 5
+  pkt autotag --yes
+  pkt config set autotag.command 'llm -s "Reply with 3 comma separated tags for this prompt, reusing these where they fit: $POCKET_PROMPT_TAGS"'`)
+
+	case "translate":
+		fmt.Fprintln(w, `translate - Translate a prompt into another language
+
+Usage: pkt translate <id> --to <language> [options]
+
+Translates a prompt's title, description and content with the command set
+with 'pkt config set translate.command' and saves
+the result as a new prompt beside the original. The new prompt records the
+original's ID in translation_of, so 'pkt get' lists each prompt's
+translations, and keeps its tags, template and variables.
+
+The command runs once per text with it on stdin, the target language's
+name in $POCKET_PROMPT_LANGUAGE and its code in
+$POCKET_PROMPT_LANGUAGE_CODE, and prints the translation, so any LLM
+command line tool can do it. A translation that drops a {{variable}} is
+refused.
+
+Prompts record the language they are written in as "language", detected
+from their content when they are saved; set it with --lang on create and
+edit, and list prompts in one language with 'pkt list --lang de'.
+
+Options:
+  --to <language>       Language code to translate into, such as de or pt-BR
+  --id <new-id>         ID for the translation (default: <id>-<language>)
+  --dry-run, -n         Only show the translation
+  --format, -f json     Print the translated prompt as JSON
+
+Examples:
+  pkt translate code-review --to de
+  pkt translate code-review --to pt-BR --id revisao-de-codigo --dry-run
+  pkt config set translate.command 'llm -s "Translate this text into $POCKET_PROMPT_LANGUAGE. Keep {{placeholders}} and Markdown unchanged. Reply with the translation only."'`)
+
+	case "check-links":
+		fmt.Fprintln(w, `check-links - Check the resources prompts reference
+
+Usage: pkt check-links [id...] [options]
+
+Checks every URL and library doc that prompts list under "references" in
+their frontmatter, so instructions pointing at documentation that has moved
+or vanished get noticed. URLs must answer with a successful status after
+redirects; a 404 or 410 is reported as broken, other failing statuses as
+error and requests that get no answer as unreachable. Library docs are
+paths relative to the library and must exist. Each resource is checked once
+however many prompts reference it.
+
+Exits with an error when any reference needs attention. 'pkt ci' also
+reports missing library docs, without making network requests.
+
+Options:
+  --all, -a             Also list references that are fine
+  --timeout <duration>  Time to wait for each URL (default 10s)
+  --format, -f json     Print the report as JSON
+
+Examples:
+  pkt check-links
+  pkt check-links api-client code-review --all
+  pkt check-links --timeout 30s --format json`)
+
+	case "keys":
+		fmt.Fprintln(w, `keys - Print the TUI keybindings
+
+Usage: pkt keys [options]
+
+Prints every keybinding of the TUI, grouped by the view it works in, with
+the keys set under "ui.keys" in the config in place of the defaults. The
+Markdown makes a one-page cheat sheet; 'K' shows the same list in the TUI.
+
+Rebind an action by setting its name to one or more keys separated by
+spaces. Unknown actions are reported when the TUI starts.
+
+Options:
+  --format, -f <format>  md (default) or json
+
+Examples:
+  pkt keys > keybindings.md
+  pkt keys --format json
+  pkt config set ui.keys "copy=C,new=ctrl+n n"`)
+
+	case "move":
+		fmt.Fprintln(w, `move - Move prompts between packs
+
+Usage: pkt move [id...] [options] --to-pack <pack>
+
+Moves prompts into an installed pack, or back into the personal library
+with --to-pack personal. Name the prompts by ID, or pick them by tag or
+boolean search from the personal library or, with --from-pack, from a pack.
+
+Each prompt is saved as its next version in the pack's prompts folder,
+keeping its subfolder, and its old file is removed. Packs whose pack.json
+lists their prompts have the listing updated. The move is committed once
+to the library and once to each pack with Git sync enabled.
+
+Prompts from other sources or the project library, protected prompts and
+prompts whose file name is taken in the pack are skipped.
+
+Options:
+  --to-pack <pack>          Pack to move the prompts to, or personal
+  --tag, -t <tag>           Move the prompts with this tag (repeat for all of several)
+  --search, -s <expr>       Move the prompts matching a boolean expression
+  --from-pack <pack>        Pick prompts from this pack instead of the personal library
+  --dry-run, -n             Show what would move without changing anything
+  --format, -f json         Print the report as JSON
+
+Examples:
+  pkt move --tag drafts --to-pack team
+  pkt move --search "review AND NOT wip" --to-pack team --dry-run
+  pkt move --from-pack team --tag personal-only --to-pack personal
+  pkt move api-client code-review --to-pack team`)
+
+	case "watch-clipboard":
+		fmt.Fprintln(w, `watch-clipboard - Save prompts you copy during the day
+
+Usage: pkt watch-clipboard [options]
+
+Reads the clipboard every second until Ctrl+C and saves copied text that
+looks like a prompt: at least 80 characters that start with "You are" or
+"Act as". Saved prompts are tagged inbox, titled by their first line and
+record source: clipboard in their metadata; review them later with
+'pkt list --tag inbox'. What is on the clipboard when watching starts is
+left alone.
+
+The defaults can be changed under "clipboard" in .pocket-prompt/config.json
+(min_length, patterns, interval and tags). A command printing the clipboard,
+such as "wl-paste --no-newline", is set with 'pkt config set
+clipboard.paste_command'.
+
+Options:
+  --tag, -t <tag>         Tag saved prompts (repeatable; default inbox)
+  --match <regex>         Keep text matching a Go regular expression
+                          (repeatable; replaces the default patterns)
+  --min-length <n>        Ignore text shorter than n characters
+  --interval <duration>   How often to read the clipboard, e.g. 2s
+
+Examples:
+  pkt watch-clipboard
+  pkt watch-clipboard --tag inbox --tag found
+  pkt watch-clipboard --match '(?i)^(you are|your task)' --min-length 200`)
+
+	case "alias":
+		fmt.Fprintln(w, `alias - Shortcuts for commands you type often
+
+Usage:
+  pkt alias [list]                       List aliases
+  pkt alias add <name> <command> [args]  Define or replace an alias
+  pkt alias remove <name>                Remove an alias
+
+Running 'pkt <name> [args]' runs the alias's command with the arguments given
+first and the alias's own after them, since commands take an ID before their
+options: with 'cr' for 'copy --format json', 'pkt cr review' runs
+'pkt copy review --format json'. Aliases may use other aliases but cannot
+replace built-in commands. Quote the whole command to keep quotes inside it:
+
+  pkt alias add rev 'search "code review"'
+
+Aliases are stored under "cli": {"aliases": {...}} in .pocket-prompt/config.json.
+
+Examples:
+  pkt alias add cr copy --format json
+  pkt alias add work list --pack work
+  pkt cr code-review
+  pkt alias remove cr`)
+
+	case "gh":
+		fmt.Fprintln(w, `gh - Open a GitHub issue or pull request described by a prompt
+
+Usage:
+  pkt gh issue <id> [options] [-- gh options]
+  pkt gh pr <id> [options] [-- gh options]
+
+Renders the prompt, filling in variables as 'pkt render' does, and runs
+'gh issue create' or 'gh pr create' in the current directory with the text as
+the body. gh asks for anything not given, such as the title. Needs the GitHub
+CLI (https://cli.github.com), signed in with 'gh auth login'.
+
+Options:
+  --var <name>=<value>      Set a variable (repeatable)
+  --profile, -p <name>      Fill in variables from profiles/<name>.yaml
+  --redact                  Apply the library's redaction rules
+  --title, -t <title>       Passed on to gh, as are --repo, --label,
+                            --assignee, --base, --draft and --web
+  --dry-run                 Print the gh command and the body instead
+
+Examples:
+  pkt gh issue bug-report --title "Crash on save" --var version=1.4.2
+  pkt gh pr pr-description --draft --profile backend
+  pkt gh pr release-notes --base main -- --reviewer alice`)
+
+	case "shell":
+		fmt.Fprintln(w, `shell - Run commands one after another without restarting pkt
+
+Usage:
+  pkt shell
+
+Reads commands, typed without 'pkt', and runs them against the library
+already loaded, so repeated searches skip reading the library again. Tab
+completes commands and aliases at the start of a command, tags after tag:
+or --tag, and prompt IDs anywhere else, and lists the choices when more than
+one is left. Up and down step through earlier commands, including the last 100 from
+previous sessions. Type exit or press Ctrl-D to leave.
+
+Separate commands on one line with ';' to run them all, or with '&&' to stop
+at the first that fails. Commands piped into 'pkt shell' run as a script.
+
+Examples:
+  pkt shell
+  pkt> search review && copy code-review
+  pkt> list --tag go ; tags
+  printf 'lint\nci\n' | pkt shell`)
+
+	case "history", "repeat", "!!":
+		fmt.Fprintln(w, `history - List, repeat and export the commands you have run
+
+Usage:
+  pkt history [--limit <n>] [--format json]
+  pkt history export [<n> | <n>-<m> | --last <n>] [--format sh|json] [--output <file>]
+  pkt history clear
+  pkt repeat [<n>]
+  pkt !!
+
+Every pkt command is recorded with when it ran and whether it failed, in
+history.jsonl in your cache directory (~/.cache/pocket-prompt on Linux), so it
+is never synced with the library. The last 1000 are kept. Commands run in
+'pkt shell' are recorded one by one. Values of flags and settings named like
+passwords, secrets, tokens or API keys, API keys anywhere in the arguments
+and passwords in URLs are recorded as [redacted]. Set cli.no_history to stop
+recording.
+
+'pkt history' lists the last 20 commands, numbered; --limit 0 lists them
+all. 'pkt repeat' runs the last command again, and 'pkt repeat 12' runs
+number 12. 'pkt !!' is the same as 'pkt repeat', but interactive shells
+expand !! themselves, so quote it: pkt '!!'. Commands with redacted
+arguments cannot be repeated.
+
+'pkt history export' writes the commands as a shell script, to share a
+workflow or run it again. Give one number or a range, or --last <n>, to
+export part of the history. Failed commands are left out, and those with
+redacted arguments are commented out. --format json writes the entries
+instead.
+
+Examples:
+  pkt history
+  pkt repeat
+  pkt repeat 12
+  pkt history export 12-15 --output release-notes.sh
+  pkt history export --last 5 --format json`)
+
+	case "plugins", "plugin":
+		fmt.Fprintln(w, `plugins - Importers, exporters and formatters from other programs
+
+Usage:
+  pkt plugins                          List installed plugins and what they provide
+  pkt import <importer> [args]         Import with a plugin importer
+  pkt export <type> --format <name>    Export with a plugin exporter
+  pkt copy <id> --format <name>        Copy a prompt through a plugin formatter
+
+A plugin is an executable named pkt-plugin-<name>, in any language, placed in
+~/.config/pocket-prompt/plugins (the user config directory) or on PATH. Plugins
+are never loaded from a library, so syncing one cannot run anything.
+
+pkt runs the plugin with one of these arguments, always setting
+POCKET_PROMPT_PLUGIN_PROTOCOL=1:
+
+  describe               Print a manifest: {"protocol": 1,
+                         "importers": [{"name": "notion", "description": "..."}],
+                         "exporters": [...], "formatters": [...]}
+  import <name> [args]   Print {"prompts": [...], "templates": [...]}, the shape
+                         'pkt export all' writes; IDs must be file names
+  export <name>          Read that shape on stdin and print the export
+  format <name>          Read {"prompt": {...}, "text": "..."} on stdin, with
+                         variables already filled in, and print the result
+
+A plugin reports failure by exiting non-zero with a message on stderr.
+Imported prompts are saved like any other import, with the plugin importer's
+name in metadata.source unless the plugin sets one.
+
+Examples:
+  pkt plugins
+  pkt import notion --database prompts --tags notion
+  pkt export all --format csv --output prompts.csv
+  pkt copy code-review --format anthropic`)
+
+	case "config":
+		fmt.Fprintln(w, `config - Show and change library settings
+
+Usage:
+  pkt config [get] [key]        Show every setting, or one setting or section
+  pkt config set <key> <value>  Change a setting
+  pkt config unset <key>        Return a setting to its default
+  pkt config edit               Edit config.json in $EDITOR
+  pkt config path               Print the location of config.json
+
+Keys are paths into .pocket-prompt/config.json, such as server.port or
+git.no_sync; items of lists are numbered from 0, as in sources.0.name.
+Values are written as for environment variables (see 'pkt help env'):
+true or false, numbers, comma-separated lists and key=value,key=value maps.
+A mistyped key is rejected with the closest known setting. Lists of
+objects, such as sources and API keys, are changed with 'pkt config edit'
+or their own commands.
+
+'pkt config edit' opens the file in cli.editor, $VISUAL or $EDITOR and saves
+it only if it parses and every setting is valid; otherwise it offers to edit
+again. Keys pocket-prompt does not know are saved, with a warning, since they
+would be ignored.
+
+Settings that name a command to run (cli.editor, cli.clipboard,
+clipboard.paste_command, summarize.command, autotag.command and
+translate.command) are only read from your own config file, such as
+~/.config/pocket-prompt/config.json, since the library's config.json comes
+from everyone who can push to it. So are the settings that name a server
+given a secret and the variable holding it (remote.url,
+remote.secret_env, email.server, email.password_env, digest.smtp and
+digest.password_env). 'pkt config set' saves them there, and ones set in the
+library are ignored with a warning.
+
+Examples:
+  pkt config get server
+  pkt config set server.port 9000
+  pkt config set lint.forbidden_phrases "as an AI,delve"
+  pkt config unset cli.format
+  pkt config edit`)
+
+	case "remote-mode":
+		fmt.Fprintln(w, `--remote - Run commands against a running server
+
+Usage:
+  pkt --remote <addr> <command> [args]
+
+The address is a Unix socket (unix:/path/to/socket), a URL
+(http://host:port) or host:port. It defaults to $POCKET_PROMPT_REMOTE, and
+an API key is read from $POCKET_PROMPT_API_KEY. The local library is never
+opened, so the CLI works from any directory and sees the server's view.
+
+Available commands: list, search, boolean-search, get, tags, packs, health
+
+Start a server on a socket with:
+  pocket-prompt --url-server --listen unix:$HOME/.pocket-prompt/pkt.sock
+
+Examples:
+  pkt --remote unix:$HOME/.pocket-prompt/pkt.sock search "code review"
+  pkt --remote http://localhost:8080 get my-prompt`)
+
+	case "env", "environment":
+		fmt.Fprintln(w, `env - Override configuration with environment variables
+
+Every setting in .pocket-prompt/config.json can be set with a variable named
+after its path, which suits containers and scripts. Flags such as --port take
+precedence over variables, and variables over the config file. Lists are
+comma-separated; maps are written key=value,key=value. Lists of objects, such
+as sources and API keys, can only be set in the file.
+
+Example:
+  POCKET_PROMPT_SERVER_PORT=9000 POCKET_PROMPT_GIT_NO_SYNC=true pocket-prompt --url-server
+
+Variables:`)
+		for _, v := range config.EnvVars() {
+			fmt.Fprintf(w, "  %-44s %-7s %s\n", v.Name, v.Type, v.Path)
+		}
+
+	case "qr":
+		fmt.Fprintln(w, `qr - Move prompts to a phone with a QR code
+
+Usage:
+  pkt qr <id> [options]       Encode the prompt text
+  pkt qr <id> --url           Encode the server URL for the prompt
+  pkt server qr [options]     Encode the API server address
+
+Options:
+  --host <host>    Address the phone should use (default: the running
+                   server's tailnet name, else the first LAN address)
+  --port <port>    API server port (default: the running server's, else
+                   server.port in the config, or 8080)
+
+A QR code holds about 2,900 characters; use --url for longer prompts and
+start the server with: pocket-prompt --url-server
+
+Examples:
+  pkt qr code-review
+  pkt qr code-review --url --port 9000
+  pkt server qr`)
+
+	case "packs", "pack":
+		fmt.Fprintln(w, `packs - Pack management
+
+Packs are collections of prompts and templates that can be installed and shared.
+Perfect for distributing actionable prompts for specific use cases.
+
+Usage: pkt packs <subcommand>
+
+Subcommands:
+  list, ls              List all installed packs
+  install <url|path>    Install a pack from Git URL or directory
+  uninstall <name>      Uninstall a pack
+  update <name>         Pull the latest version of a pack installed from Git
+  info, show <name>     Show detailed pack information
+  create <dir> <name>   Create a new pack scaffold
+  refresh               Refresh pack metadata
+  diff <name>           Show how you've customized a pack installed from Git
+
+Flags:
+  --format json         Output in JSON format
+  --verbose, -v         Show detailed information
+  --tag <tag>           Filter by tag
+  --name <name>         Override pack name when installing
+  --branch <branch>     Install from specific Git branch
+  --force               Force reinstall if already exists
+  --remote, -r          With diff, also compare with the latest remote version
+  --stat                With diff, list changed files with counts of changed lines
+
+Examples:
+  pkt packs list
+  pkt packs install https://github.com/user/decentral-compute-pack.git
+  pkt packs install ./my-pack-directory
+  pkt packs show decentral-compute-adoption
+  pkt packs create ./my-new-pack awesome-pack --title "Awesome Pack"
+  pkt packs diff decentral-compute-adoption --remote
+  pkt packs update decentral-compute-adoption
+  pkt packs uninstall old-pack
+
+Pack Structure:
+  my-pack/
+  ├── pack.json         # Pack metadata and configuration
+  ├── prompts/          # Prompt files (.md with YAML frontmatter)
+  ├── templates/        # Template files (.md with YAML frontmatter)
+  └── README.md         # Documentation`)
+
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package help
+
+import "github.com/dpshade/pocket-prompt/internal/i18n"
+
+// Section is a section of the TUI help modal
+type Section struct {
+	Title string
+	Keys  []Key    // Keybindings, listed first
+	Notes []string // Lines of text after the keybindings
+}
+
+// Key is a TUI keybinding and what it does
+type Key struct {
+	Key         string
+	Description string
+}
+
+// TUISections returns the sections of the TUI help modal in the current locale
+func TUISections() []Section {
+	return []Section{
+		{
+			Title: i18n.T("help.overview"),
+			Notes: []string{i18n.T("help.overview_what"), i18n.T("help.overview_how")},
+		},
+		{
+			Title: i18n.T("help.navigation"),
+			Keys: []Key{
+				{"↑/↓", i18n.T("help.key_navigate")},
+				{"Enter", i18n.T("help.key_select")},
+				{"b", i18n.T("help.key_back")},
+				{"q", i18n.T("help.key_quit")},
+				{"?", i18n.T("help.key_help")},
+				{"K", i18n.T("help.key_keys")},
+			},
+		},
+		{
+			Title: i18n.T("help.prompts"),
+			Keys: []Key{
+				{"n", i18n.T("help.key_new")},
+				{"e", i18n.T("help.key_edit")},
+				{"c", i18n.T("help.key_copy")},
+				{"y", i18n.T("help.key_copy_json")},
+				{"1-4", i18n.T("help.key_tabs")},
+				{"H", i18n.T("help.key_history")},
+				{"R", i18n.T("help.key_raw")},
+				{"O", i18n.T("help.key_reveal")},
+				{"v", i18n.T("help.key_profile")},
+				{"n/p", i18n.T("help.key_sections")},
+				{"+/-", i18n.T("help.key_quick_tag")},
+				{"Space", i18n.T("help.key_mark")},
+				{"M", i18n.T("help.key_move_pack")},
+				{"Ctrl+s", i18n.T("help.key_save")},
+				{"Ctrl+d", i18n.T("help.key_delete")},
+			},
+		},
+		{
+			Title: i18n.T("help.search"),
+			Keys: []Key{
+				{"/", i18n.T("help.key_fuzzy")},
+				{"Ctrl+f", i18n.T("help.key_boolean")},
+				{"f", i18n.T("help.key_saved_searches")},
+				{"P", i18n.T("help.key_pin_search")},
+				{"Tab", i18n.T("help.key_switch_focus")},
+				{"Ctrl+s", i18n.T("help.key_save_search")},
+			},
+		},
+		{
+			Title: i18n.T("help.templates"),
+			Keys: []Key{
+				{"t", i18n.T("help.key_templates")},
+				{"T", i18n.T("help.key_template_gallery")},
+			},
+			Notes: []string{i18n.T("help.templates_what"), i18n.T("help.templates_syntax")},
+		},
+		{
+			Title: i18n.T("help.boolean_examples"),
+			Notes: []string{
+				i18n.T("help.example_and"),
+				i18n.T("help.example_or"),
+				i18n.T("help.example_not"),
+				i18n.T("help.example_group"),
+			},
+		},
+		{
+			Title: i18n.T("help.files"),
+			Notes: []string{
+				i18n.T("help.files_storage"),
+				i18n.T("help.files_prompts"),
+				i18n.T("help.files_templates"),
+				i18n.T("help.files_archive"),
+				i18n.T("help.files_sync"),
+			},
+		},
+		{
+			Title: i18n.T("help.tips"),
+			Notes: []string{
+				i18n.T("help.tip_tags"),
+				i18n.T("help.tip_templates"),
+				i18n.T("help.tip_boolean"),
+				i18n.T("help.tip_filters"),
+				i18n.T("help.tip_json"),
+				i18n.T("help.tip_keyboard"),
+				i18n.T("help.tip_history"),
+			},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date and date-time layouts for one locale
+type dateLayouts struct {
+	date     string
+	dateTime string
+}
+
+// isoLayouts are used without a locale and for locales not listed below
+var isoLayouts = dateLayouts{"2006-01-02", "2006-01-02 15:04"}
+
+// layoutsByLocale holds layouts by region, then by language
+var layoutsByLocale = map[string]dateLayouts{
+	"en":    {"Jan 2, 2006", "Jan 2, 2006 3:04 PM"},
+	"en-AU": {"2 Jan 2006", "2 Jan 2006 15:04"},
+	"en-CA": isoLayouts,
+	"en-GB": {"2 Jan 2006", "2 Jan 2006 15:04"},
+	"en-IE": {"2 Jan 2006", "2 Jan 2006 15:04"},
+	"en-IN": {"2 Jan 2006", "2 Jan 2006 15:04"},
+	"en-NZ": {"2 Jan 2006", "2 Jan 2006 15:04"},
+	"cs":    {"2. 1. 2006", "2. 1. 2006 15:04"},
+	"da":    {"02.01.2006", "02.01.2006 15.04"},
+	"de":    {"02.01.2006", "02.01.2006 15:04"},
+	"es":    {"02/01/2006", "02/01/2006 15:04"},
+	"fi":    {"2.1.2006", "2.1.2006 15.04"},
+	"fr":    {"02/01/2006", "02/01/2006 15:04"},
+	"fr-CA": isoLayouts,
+	"it":    {"02/01/2006", "02/01/2006 15:04"},
+	"ja":    {"2006/01/02", "2006/01/02 15:04"},
+	"ko":    {"2006. 1. 2.", "2006. 1. 2. 15:04"},
+	"nb":    {"02.01.2006", "02.01.2006 15:04"},
+	"nl":    {"02-01-2006", "02-01-2006 15:04"},
+	"pl":    {"02.01.2006", "02.01.2006 15:04"},
+	"pt":    {"02/01/2006", "02/01/2006 15:04"},
+	"ru":    {"02.01.2006", "02.01.2006 15:04"},
+	"tr":    {"02.01.2006", "02.01.2006 15:04"},
+	"uk":    {"02.01.2006", "02.01.2006 15:04"},
+	"zh":    {"2006/01/02", "2006/01/02 15:04"},
+}
+
+// layouts returns the layouts for the current locale
+func layouts() dateLayouts {
+	tag := Locale()
+	for tag != "" {
+		if l, ok := layoutsByLocale[tag]; ok {
+			return l
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return isoLayouts
+}
+
+// FormatDate formats the date of t for display in the current locale
+func FormatDate(t time.Time) string {
+	return t.Format(layouts().date)
+}
+
+// FormatDateTime formats the date and time of t, to the minute, for display
+// in the current locale
+func FormatDateTime(t time.Time) string {
+	return t.Format(layouts().dateTime)
+}
@@ -0,0 +1,181 @@
+// Package i18n localizes user-facing text. Messages live in YAML catalogs
+// embedded from catalogs/, one per language or region (de.yaml, pt-BR.yaml),
+// keyed by message ID. A message missing from the current locale's catalog
+// falls back to its language, then to English.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguage is the language every message is written in first
+const DefaultLanguage = "en"
+
+//go:embed catalogs/*.yaml
+var catalogFiles embed.FS
+
+var (
+	mu       sync.RWMutex
+	locale   string
+	catalogs map[string]map[string]string // by locale tag, loaded on first use
+)
+
+// localeTag matches the tags SetLocale accepts: a language with optional
+// subtags, such as de, en-GB or zh-Hant-TW
+var localeTag = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// Normalize turns a configured locale or POSIX locale name such as
+// de_DE.UTF-8 into a tag like de-DE. The C and POSIX locales, and anything
+// unrecognised, normalize to "", the untranslated default.
+func Normalize(name string) string {
+	name, _, _ = strings.Cut(name, ".") // encoding
+	name, _, _ = strings.Cut(name, "@") // modifier
+	name = strings.ReplaceAll(name, "_", "-")
+	if name == "" || !localeTag.MatchString(name) {
+		return ""
+	}
+
+	parts := strings.Split(name, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Valid reports whether name is empty or a locale Normalize accepts
+func Valid(name string) bool {
+	return name == "" || Normalize(name) != ""
+}
+
+// Detect picks the locale: the configured one if set, else the first of
+// LC_ALL, LC_MESSAGES and LANG that is set, as POSIX tools do
+func Detect(configured string) string {
+	if configured != "" {
+		return Normalize(configured)
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return Normalize(value)
+		}
+	}
+	return ""
+}
+
+// SetLocale sets the locale messages and dates are formatted for
+func SetLocale(tag string) {
+	mu.Lock()
+	defer mu.Unlock()
+	locale = Normalize(tag)
+}
+
+// Locale returns the current locale tag, or "" when none is set
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// T returns the message for key in the current locale, formatted with args
+// as by fmt.Sprintf when any are given. An unknown key is returned as is, so
+// a missing message shows up rather than disappearing.
+func T(key string, args ...interface{}) string {
+	message, ok := lookup(key, fallbacks(Locale()))
+	if !ok {
+		message = key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Translated returns the message for key from the current locale's own
+// catalogs only, without the English fallback. Long text kept in code, such
+// as per-command help, uses it to show a translation when one exists.
+func Translated(key string) (string, bool) {
+	tags := fallbacks(Locale())
+	return lookup(key, tags[:len(tags)-1])
+}
+
+// fallbacks lists the catalogs to search for tag, most specific first and
+// ending with English: de-AT, de, en
+func fallbacks(tag string) []string {
+	var tags []string
+	for tag != "" {
+		if tag != DefaultLanguage {
+			tags = append(tags, tag)
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return append(tags, DefaultLanguage)
+}
+
+// lookup returns the first message for key in the catalogs for tags
+func lookup(key string, tags []string) (string, bool) {
+	loaded := loadCatalogs()
+	for _, tag := range tags {
+		if message, ok := loaded[tag][key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// loadCatalogs parses the embedded catalogs once. A catalog that fails to
+// parse is a build mistake, caught by the package tests.
+func loadCatalogs() map[string]map[string]string {
+	mu.RLock()
+	loaded := catalogs
+	mu.RUnlock()
+	if loaded != nil {
+		return loaded
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if catalogs != nil {
+		return catalogs
+	}
+	catalogs, _ = parseCatalogs()
+	return catalogs
+}
+
+// parseCatalogs reads every embedded catalog, keyed by the tag in its file name
+func parseCatalogs() (map[string]map[string]string, error) {
+	entries, err := catalogFiles.ReadDir("catalogs")
+	if err != nil {
+		return map[string]map[string]string{}, err
+	}
+
+	parsed := make(map[string]map[string]string, len(entries))
+	var firstErr error
+	for _, entry := range entries {
+		data, err := catalogFiles.ReadFile("catalogs/" + entry.Name())
+		if err != nil {
+			return parsed, err
+		}
+		messages := map[string]string{}
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("catalog %s: %w", entry.Name(), err)
+			}
+			continue
+		}
+		parsed[Normalize(strings.TrimSuffix(entry.Name(), ".yaml"))] = messages
+	}
+	return parsed, firstErr
+}
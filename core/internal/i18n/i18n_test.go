@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"de_DE.UTF-8":      "de-DE",
+		"en_GB":            "en-GB",
+		"pt-br":            "pt-BR",
+		"sr_RS@latin":      "sr-RS",
+		"zh-Hant-TW":       "zh-Hant-TW",
+		"C":                "",
+		"POSIX":            "",
+		"C.UTF-8":          "",
+		"":                 "",
+		"not a locale!":    "",
+		"FR":               "fr",
+		"es_419.ISO8859-1": "es-419",
+	}
+	for input, want := range tests {
+		if got := Normalize(input); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestDetectPrefersConfigThenPOSIXOrder(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "es_ES.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+
+	if got := Detect("en-GB"); got != "en-GB" {
+		t.Errorf("Detect(en-GB) = %q", got)
+	}
+	if got := Detect(""); got != "es-ES" {
+		t.Errorf("Detect with LC_MESSAGES = %q, want es-ES", got)
+	}
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	if got := Detect(""); got != "fr-FR" {
+		t.Errorf("Detect with LC_ALL = %q, want fr-FR", got)
+	}
+}
+
+func TestTFallsBackToLanguageThenEnglish(t *testing.T) {
+	t.Cleanup(func() { SetLocale("") })
+	SetLocale("")
+	english := T("cli.usage")
+
+	SetLocale("de_AT.UTF-8")
+	if got := T("status.found", 3); got != "3 Prompts gefunden" {
+		t.Errorf("de-AT status.found = %q", got)
+	}
+	SetLocale("es")
+	if got := T("cli.usage"); got != english || got == "cli.usage" {
+		t.Errorf("es cli.usage should fall back to English, got %q", got)
+	}
+	if _, ok := Translated("cli.usage"); ok {
+		t.Error("Translated(cli.usage) found a Spanish message that does not exist")
+	}
+
+	SetLocale("")
+	if got := T("status.found", 3); got != "Found 3 prompts" {
+		t.Errorf("default status.found = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key = %q, want the key itself", got)
+	}
+	if _, ok := Translated("status.found"); ok {
+		t.Error("Translated should ignore the English catalog")
+	}
+}
+
+var verb = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	parsed, err := parseCatalogs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	english := parsed[DefaultLanguage]
+	if len(english) == 0 {
+		t.Fatal("English catalog is empty")
+	}
+
+	for tag, messages := range parsed {
+		if tag == "" {
+			t.Errorf("catalog file name is not a locale tag")
+		}
+		for key, message := range messages {
+			source, ok := english[key]
+			if !ok {
+				t.Errorf("%s: %s is not in the English catalog", tag, key)
+				continue
+			}
+			if got, want := verb.FindAllString(message, -1), verb.FindAllString(source, -1); !slices.Equal(got, want) {
+				t.Errorf("%s: %s uses %v, English uses %v", tag, key, got, want)
+			}
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	t.Cleanup(func() { SetLocale("") })
+	at := time.Date(2025, time.March, 7, 14, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale, date, dateTime string
+	}{
+		{"", "2025-03-07", "2025-03-07 14:05"},
+		{"en-US", "Mar 7, 2025", "Mar 7, 2025 2:05 PM"},
+		{"en-GB", "7 Mar 2025", "7 Mar 2025 14:05"},
+		{"de-CH", "07.03.2025", "07.03.2025 14:05"},
+		{"fr-CA", "2025-03-07", "2025-03-07 14:05"},
+		{"ja-JP", "2025/03/07", "2025/03/07 14:05"},
+		{"xx", "2025-03-07", "2025-03-07 14:05"},
+	}
+	for _, tt := range tests {
+		SetLocale(tt.locale)
+		if got := FormatDate(at); got != tt.date {
+			t.Errorf("%q FormatDate = %q, want %q", tt.locale, got, tt.date)
+		}
+		if got := FormatDateTime(at); got != tt.dateTime {
+			t.Errorf("%q FormatDateTime = %q, want %q", tt.locale, got, tt.dateTime)
+		}
+	}
+}
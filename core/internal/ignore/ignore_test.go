@@ -0,0 +1,39 @@
+package ignore
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	m := Parse(`
+# scratch work
+scratch/
+*.bak
+*~
+/drafts
+prompts/**/experimental
+!keep.bak
+`)
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"scratch", true, true},
+		{"prompts/scratch/idea.md", false, true},
+		{"scratch", false, false},
+		{"prompts/old.md.bak", false, true},
+		{"prompts/keep.bak", false, false},
+		{"prompts/notes.md~", false, true},
+		{"drafts/a.md", false, true},
+		{"prompts/drafts/a.md", false, false},
+		{"prompts/experimental/x.md", false, true},
+		{"prompts/a/b/experimental", true, true},
+		{"prompts/real.md", false, false},
+	}
+
+	for _, c := range cases {
+		if got := m.Match(c.path, c.isDir); got != c.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", c.path, c.isDir, got, c.want)
+		}
+	}
+}
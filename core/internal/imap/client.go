@@ -0,0 +1,207 @@
+// Package imap is a minimal IMAP4rev1 client: enough to log in, find messages
+// by UID and download them, for polling a mailbox
+package imap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client is a connection to an IMAP server. It is not safe for concurrent use.
+type Client struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// Response is an untagged server response line, with the literals it carried
+type Response struct {
+	Line     string
+	Literals [][]byte
+}
+
+// Dial connects to addr (host:port) over TLS, or in plain text when insecure
+// is set, which is only meant for local test servers
+func Dial(addr string, insecure bool) (*Client, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if insecure {
+		conn, err = dialer.Dial("tcp", addr)
+	} else {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn)
+}
+
+// NewClient starts a session on an open connection, reading the server greeting
+func NewClient(conn net.Conn) (*Client, error) {
+	c := &Client{conn: conn, r: bufio.NewReader(conn)}
+	greeting, err := c.readLine()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("no greeting from server: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		conn.Close()
+		return nil, fmt.Errorf("server refused connection: %s", greeting)
+	}
+	return c, nil
+}
+
+// Login authenticates with a username and password
+func (c *Client) Login(username, password string) error {
+	_, err := c.Command("LOGIN " + Quote(username) + " " + Quote(password))
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	return nil
+}
+
+// Select opens a mailbox and returns its UIDVALIDITY, which changes when the
+// server renumbers the mailbox's UIDs
+func (c *Client) Select(mailbox string) (uint32, error) {
+	responses, err := c.Command("SELECT " + Quote(mailbox))
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range responses {
+		if _, rest, ok := strings.Cut(r.Line, "[UIDVALIDITY "); ok {
+			value, _, _ := strings.Cut(rest, "]")
+			n, err := strconv.ParseUint(value, 10, 32)
+			if err == nil {
+				return uint32(n), nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// Search runs UID SEARCH with the given criteria, e.g. `UID 10:* SUBJECT "[pkt]"`
+func (c *Client) Search(criteria string) ([]uint32, error) {
+	responses, err := c.Command("UID SEARCH " + criteria)
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, r := range responses {
+		fields := strings.Fields(r.Line)
+		if len(fields) < 2 || !strings.EqualFold(fields[1], "SEARCH") {
+			continue
+		}
+		for _, field := range fields[2:] {
+			if n, err := strconv.ParseUint(field, 10, 32); err == nil {
+				uids = append(uids, uint32(n))
+			}
+		}
+	}
+	return uids, nil
+}
+
+// Fetch downloads the full message with the given UID without marking it read
+func (c *Client) Fetch(uid uint32) ([]byte, error) {
+	responses, err := c.Command(fmt.Sprintf("UID FETCH %d BODY.PEEK[]", uid))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range responses {
+		if strings.Contains(strings.ToUpper(r.Line), "FETCH") && len(r.Literals) > 0 {
+			return r.Literals[0], nil
+		}
+	}
+	return nil, fmt.Errorf("message %d not found", uid)
+}
+
+// MarkSeen flags a message as read
+func (c *Client) MarkSeen(uid uint32) error {
+	_, err := c.Command(fmt.Sprintf(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid))
+	return err
+}
+
+// Logout ends the session and closes the connection
+func (c *Client) Logout() error {
+	_, err := c.Command("LOGOUT")
+	c.conn.Close()
+	return err
+}
+
+// Command sends a command and collects the untagged responses until the
+// server completes it. A NO or BAD completion is returned as an error.
+func (c *Client) Command(command string) ([]Response, error) {
+	c.tag++
+	tag := fmt.Sprintf("A%03d", c.tag)
+	c.conn.SetDeadline(time.Now().Add(2 * time.Minute))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, command); err != nil {
+		return nil, err
+	}
+
+	var responses []Response
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if status, ok := strings.CutPrefix(line, tag+" "); ok {
+			if strings.HasPrefix(strings.ToUpper(status), "OK") {
+				return responses, nil
+			}
+			return nil, fmt.Errorf("%s", status)
+		}
+
+		// Literals are announced as {size} at the end of a line, followed by
+		// the raw bytes and the rest of the line
+		response := Response{Line: line}
+		for {
+			size, ok := literalSize(line)
+			if !ok {
+				break
+			}
+			literal := make([]byte, size)
+			if _, err := io.ReadFull(c.r, literal); err != nil {
+				return nil, err
+			}
+			response.Literals = append(response.Literals, literal)
+			if line, err = c.readLine(); err != nil {
+				return nil, err
+			}
+			response.Line += line
+		}
+		responses = append(responses, response)
+	}
+}
+
+func (c *Client) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// literalSize parses a trailing {size} literal marker
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	open := strings.LastIndex(line, "{")
+	if open < 0 {
+		return 0, false
+	}
+	size, err := strconv.Atoi(line[open+1 : len(line)-1])
+	return size, err == nil && size >= 0
+}
+
+// Quote formats s as an IMAP quoted string
+func Quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
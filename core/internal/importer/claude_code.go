@@ -39,6 +39,15 @@ type ImportOptions struct {
 	OverwriteExisting bool     // Overwrite existing prompts/templates with same ID
 	SkipExisting     bool     // Skip items that already exist
 	DeduplicateByPath bool    // Check for duplicates by original file path
+
+	// Selection limits the import to the chosen items, keyed by ItemKey; nil imports everything
+	Selection map[string]bool
+	// Merged replaces the content of prompts whose conflicts were resolved in
+	// the merge view, keyed by ItemKey
+	Merged map[string]string
+
+	// Pack receives new prompts; empty or "personal" keeps them in the personal library
+	Pack string
 }
 
 // ImportResult contains the results of an import operation
@@ -118,36 +127,24 @@ func (i *ClaudeCodeImporter) determinePaths(options ImportOptions) []string {
 
 // importFromPath imports from a single path
 func (i *ClaudeCodeImporter) importFromPath(basePath string, options ImportOptions, result *ImportResult) error {
-	// Check if we're already in a .claude directory (for user-level imports)
-	isClaudeDir := filepath.Base(basePath) == ".claude"
-	
+	dirs := sourceDirsFor(basePath, options)
+
 	// Import commands from .claude/commands/ or commands/ if already in .claude
-	if !options.WorkflowsOnly && !options.ConfigOnly {
-		var commandsPath, agentsPath string
-		
-		if isClaudeDir {
-			// We're already in ~/.claude, so look for commands/ and agents/ directly
-			commandsPath = filepath.Join(basePath, "commands")
-			agentsPath = filepath.Join(basePath, "agents")
-		} else {
-			// We're in a project directory, look for .claude/commands/ and .claude/agents/
-			commandsPath = filepath.Join(basePath, ".claude", "commands")
-			agentsPath = filepath.Join(basePath, ".claude", "agents")
-		}
-		
-		if err := i.importCommands(commandsPath, options, result); err != nil && !os.IsNotExist(err) {
+	if dirs.commands != "" {
+		if err := i.importCommands(dirs.commands, options, result); err != nil && !os.IsNotExist(err) {
 			result.Errors = append(result.Errors, fmt.Errorf("failed to import commands: %w", err))
 		}
-		
-		if err := i.importAgents(agentsPath, options, result); err != nil && !os.IsNotExist(err) {
+	}
+	
+	if dirs.agents != "" {
+		if err := i.importAgents(dirs.agents, options, result); err != nil && !os.IsNotExist(err) {
 			result.Errors = append(result.Errors, fmt.Errorf("failed to import agents: %w", err))
 		}
 	}
 
 	// Import GitHub Actions workflows (only when explicitly requested)
-	if options.WorkflowsOnly {
-		workflowsPath := filepath.Join(basePath, ".github", "workflows")
-		if err := i.importWorkflows(workflowsPath, options, result); err != nil && !os.IsNotExist(err) {
+	if dirs.workflows != "" {
+		if err := i.importWorkflows(dirs.workflows, options, result); err != nil && !os.IsNotExist(err) {
 			result.Errors = append(result.Errors, fmt.Errorf("failed to import workflows: %w", err))
 		}
 	}
@@ -157,6 +154,37 @@ func (i *ClaudeCodeImporter) importFromPath(basePath string, options ImportOptio
 	return nil
 }
 
+// sourceDirs holds the directories read under one import path; empty fields are skipped
+type sourceDirs struct {
+	commands  string
+	agents    string
+	workflows string
+}
+
+// sourceDirsFor resolves the directories to read under basePath for the given options
+func sourceDirsFor(basePath string, options ImportOptions) sourceDirs {
+	var dirs sourceDirs
+
+	if !options.WorkflowsOnly && !options.ConfigOnly {
+		// Check if we're already in a .claude directory (for user-level imports)
+		if filepath.Base(basePath) == ".claude" {
+			// We're already in ~/.claude, so look for commands/ and agents/ directly
+			dirs.commands = filepath.Join(basePath, "commands")
+			dirs.agents = filepath.Join(basePath, "agents")
+		} else {
+			// We're in a project directory, look for .claude/commands/ and .claude/agents/
+			dirs.commands = filepath.Join(basePath, ".claude", "commands")
+			dirs.agents = filepath.Join(basePath, ".claude", "agents")
+		}
+	}
+
+	if options.WorkflowsOnly {
+		dirs.workflows = filepath.Join(basePath, ".github", "workflows")
+	}
+
+	return dirs
+}
+
 // importCommands imports command files from .claude/commands/
 func (i *ClaudeCodeImporter) importCommands(commandsPath string, options ImportOptions, result *ImportResult) error {
 	if _, err := os.Stat(commandsPath); os.IsNotExist(err) {
@@ -586,7 +614,7 @@ func (i *ClaudeCodeImporter) parseFrontmatter(content []byte) (map[string]interf
 	scanner := bufio.NewScanner(bytes.NewReader(content))
 	
 	if !scanner.Scan() || scanner.Text() != "---" {
-		return nil, string(content)
+		return nil, strings.TrimSpace(string(content))
 	}
 
 	var frontmatterLines []string
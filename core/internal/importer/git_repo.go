@@ -36,6 +36,7 @@ type GitImportOptions struct {
 	TempDir      string  // Temporary directory for cloning (default: system temp)
 	Branch       string  // Specific branch to import (default: repository default)
 	Depth        int     // Shallow clone depth (0 = full clone)
+	CloneFrom    string  // Local checkout to clone instead of RepoURL, which is still recorded (default: RepoURL)
 }
 
 // GitImportResult contains the results of a git repository import
@@ -83,7 +84,11 @@ func (g *GitRepoImporter) ImportFromGitRepo(options GitImportOptions) (*GitImpor
 	defer os.RemoveAll(tempDir) // Cleanup on exit
 
 	// Clone repository
-	clonePath, err := g.cloneRepository(options.RepoURL, tempDir, options.Branch, options.Depth)
+	source := options.RepoURL
+	if options.CloneFrom != "" {
+		source = options.CloneFrom
+	}
+	clonePath, err := g.cloneRepository(source, tempDir, options.Branch, options.Depth)
 	if err != nil {
 		return result, fmt.Errorf("failed to clone repository: %w", err)
 	}
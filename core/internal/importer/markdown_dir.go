@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Kinds of plain Markdown folder, used as the tag and ID prefix of their prompts
+const (
+	MarkdownObsidian = "obsidian"
+	MarkdownFolder   = "markdown"
+)
+
+// MarkdownDirImporter imports every Markdown note in a folder, such as an
+// Obsidian vault or a folder of prompts kept by hand, as a prompt
+type MarkdownDirImporter struct {
+	baseDir string              // Base directory for storing imported prompts
+	parser  *ClaudeCodeImporter // Shares frontmatter, title and tag handling with Claude Code commands
+}
+
+// NewMarkdownDirImporter creates a new Markdown folder importer
+func NewMarkdownDirImporter(baseDir string) *MarkdownDirImporter {
+	return &MarkdownDirImporter{
+		baseDir: baseDir,
+		parser:  NewClaudeCodeImporter(baseDir),
+	}
+}
+
+// MarkdownImportOptions extends ImportOptions with the kind of folder; Path is the folder
+type MarkdownImportOptions struct {
+	ImportOptions        // Embed base import options
+	Kind          string // MarkdownObsidian or MarkdownFolder (default)
+}
+
+// Import reads every .md file under options.Path. Hidden folders such as
+// .obsidian, .git and .trash are skipped, as are empty notes.
+func (m *MarkdownDirImporter) Import(options MarkdownImportOptions) (*ImportResult, error) {
+	result := &ImportResult{
+		Prompts:   []*models.Prompt{},
+		Templates: []*models.Template{},
+		Errors:    []error{},
+	}
+	if options.Path == "" {
+		return result, fmt.Errorf("a folder to import is required")
+	}
+	if options.Kind == "" {
+		options.Kind = MarkdownFolder
+	}
+	if info, err := os.Stat(options.Path); err != nil {
+		return result, err
+	} else if !info.IsDir() {
+		return result, fmt.Errorf("%s is not a folder", options.Path)
+	}
+
+	err := filepath.Walk(options.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != options.Path && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		prompt, err := m.importNote(path, options)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to import note %s: %w", path, err))
+			return nil // Continue walking
+		}
+		if prompt != nil {
+			result.Prompts = append(result.Prompts, prompt)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// importNote imports one note, or returns nil for an empty one
+func (m *MarkdownDirImporter) importNote(filePath string, options MarkdownImportOptions) (*models.Prompt, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	frontmatter, body := m.parser.parseFrontmatter(content)
+	if body == "" {
+		return nil, nil
+	}
+
+	relPath, _ := filepath.Rel(options.Path, filePath)
+	id := frontmatterString(frontmatter, "id")
+	if id == "" {
+		id = options.Kind + "-" + models.Slug(strings.TrimSuffix(relPath, filepath.Ext(relPath)))
+	}
+
+	// Folders become tags, as they do for Claude Code commands
+	tags := []string{options.Kind}
+	if dir := filepath.Dir(relPath); dir != "." {
+		for _, part := range strings.Split(dir, string(os.PathSeparator)) {
+			tags = append(tags, models.Slug(part))
+		}
+	}
+	tags = append(tags, frontmatterTags(frontmatter)...)
+	tags = append(tags, options.Tags...)
+
+	title := frontmatterString(frontmatter, "title")
+	if title == "" {
+		title = frontmatterString(frontmatter, "name")
+	}
+	if title == "" {
+		title = m.parser.extractTitle(body, filePath)
+	}
+	summary := frontmatterString(frontmatter, "description")
+	if summary == "" {
+		summary = frontmatterString(frontmatter, "summary")
+	}
+
+	now := time.Now()
+	return &models.Prompt{
+		ID:        id,
+		Version:   "1.0.0",
+		Name:      title,
+		Summary:   summary,
+		Content:   body,
+		Tags:      m.parser.cleanTags(tags),
+		CreatedAt: now,
+		UpdatedAt: now,
+		FilePath:  filepath.Join("prompts", m.parser.sanitizeFilename(id)+".md"),
+		Metadata: map[string]interface{}{
+			"source":        options.Kind,
+			"original_path": filePath,
+		},
+	}, nil
+}
+
+// frontmatterString returns a string field of frontmatter, or ""
+func frontmatterString(frontmatter map[string]interface{}, key string) string {
+	value, _ := frontmatter[key].(string)
+	return strings.TrimSpace(value)
+}
+
+// frontmatterTags returns the tags of frontmatter, written as a list or as
+// one string separated by commas or spaces, without Obsidian's leading #
+func frontmatterTags(frontmatter map[string]interface{}) []string {
+	var tags []string
+	for _, key := range []string{"tags", "tag"} {
+		switch value := frontmatter[key].(type) {
+		case []interface{}:
+			for _, tag := range value {
+				if s, ok := tag.(string); ok {
+					tags = append(tags, s)
+				}
+			}
+		case string:
+			tags = append(tags, strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })...)
+		}
+	}
+	for i := range tags {
+		tags[i] = strings.TrimPrefix(strings.TrimSpace(tags[i]), "#")
+	}
+	return tags
+}
@@ -0,0 +1,459 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Supported hosted prompt registries
+const (
+	RegistryPromptLayer = "promptlayer"
+	RegistryLangfuse    = "langfuse"
+)
+
+// RegistryImporter imports prompt-registry exports from hosted tools
+type RegistryImporter struct {
+	baseDir string // Base directory for storing imported prompts
+}
+
+// NewRegistryImporter creates a new hosted registry importer
+func NewRegistryImporter(baseDir string) *RegistryImporter {
+	return &RegistryImporter{
+		baseDir: baseDir,
+	}
+}
+
+// RegistryImportOptions extends ImportOptions with registry-specific settings
+type RegistryImportOptions struct {
+	ImportOptions        // Embed base import options
+	Registry      string // promptlayer or langfuse
+	File          string // Path to the JSON export
+	Label         string // Langfuse label whose version becomes current (default: latest version)
+}
+
+// RegistryImportResult contains the results of a registry import
+type RegistryImportResult struct {
+	*ImportResult                  // Embed base import result; Prompts holds current versions
+	Registry      string           // The registry the export came from
+	Archived      []*models.Prompt // Older revisions, stored as archived versions
+}
+
+// registryVersion is one revision of a prompt in a registry export, normalized across registries
+type registryVersion struct {
+	Name          string
+	Version       int
+	Content       string
+	Tags          []string
+	Labels        []string
+	CommitMessage string
+	Config        map[string]interface{}
+	CreatedAt     time.Time
+}
+
+// Import reads a registry export and maps its version history onto prompts
+func (r *RegistryImporter) Import(options RegistryImportOptions) (*RegistryImportResult, error) {
+	result := &RegistryImportResult{
+		ImportResult: &ImportResult{
+			Prompts:   []*models.Prompt{},
+			Templates: []*models.Template{},
+			Errors:    []error{},
+		},
+		Registry: options.Registry,
+	}
+
+	// PromptLayer exports carry no labels to pick a version by
+	if options.Label != "" && options.Registry != RegistryLangfuse {
+		return result, fmt.Errorf("labels are only supported for %s exports", RegistryLangfuse)
+	}
+
+	data, err := os.ReadFile(options.File)
+	if err != nil {
+		return result, fmt.Errorf("failed to read export: %w", err)
+	}
+
+	var versions []registryVersion
+	switch options.Registry {
+	case RegistryPromptLayer:
+		versions, err = parsePromptLayerExport(data)
+	case RegistryLangfuse:
+		versions, err = parseLangfuseExport(data)
+	default:
+		return result, fmt.Errorf("unsupported registry %q (expected %s or %s)", options.Registry, RegistryPromptLayer, RegistryLangfuse)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to parse %s export: %w", options.Registry, err)
+	}
+
+	// Group revisions by prompt name, oldest first
+	byName := make(map[string][]registryVersion)
+	var names []string
+	for _, v := range versions {
+		if v.Name == "" {
+			result.Errors = append(result.Errors, fmt.Errorf("skipping revision without a prompt name"))
+			continue
+		}
+		if _, seen := byName[v.Name]; !seen {
+			names = append(names, v.Name)
+		}
+		byName[v.Name] = append(byName[v.Name], v)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		revisions := byName[name]
+		sort.Slice(revisions, func(a, b int) bool { return revisions[a].Version < revisions[b].Version })
+
+		current := len(revisions) - 1
+		if options.Label != "" {
+			for idx, rev := range revisions {
+				if containsString(rev.Labels, options.Label) {
+					current = idx
+				}
+			}
+		}
+
+		id := RegistryPromptID(name)
+		for idx, rev := range revisions {
+			prompt := r.buildPrompt(id, rev, options)
+
+			if idx == current {
+				result.Prompts = append(result.Prompts, prompt)
+				continue
+			}
+
+			// Every other revision becomes an archived version, matching how
+			// edits inside pocket-prompt preserve history
+			prompt.Tags = append(prompt.Tags, "archive")
+			prompt.FilePath = filepath.Join("archive", fmt.Sprintf("%s-v%s.md", id, prompt.Version))
+			result.Archived = append(result.Archived, prompt)
+		}
+	}
+
+	return result, nil
+}
+
+// buildPrompt converts a registry revision into a prompt
+func (r *RegistryImporter) buildPrompt(id string, rev registryVersion, options RegistryImportOptions) *models.Prompt {
+	tags := []string{options.Registry}
+	extra := append(append([]string{}, rev.Tags...), options.Tags...)
+	for _, tag := range extra {
+		if tag = strings.TrimSpace(tag); tag != "" && !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"source":           options.Registry,
+		"registry_name":    rev.Name,
+		"registry_version": rev.Version,
+	}
+	if len(rev.Labels) > 0 {
+		metadata["registry_labels"] = rev.Labels
+	}
+	if rev.CommitMessage != "" {
+		metadata["commit_message"] = rev.CommitMessage
+	}
+	if len(rev.Config) > 0 {
+		metadata["model_config"] = rev.Config
+	}
+
+	created := rev.CreatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	summary := rev.CommitMessage
+	if summary == "" {
+		summary = fmt.Sprintf("Imported from %s (version %d)", options.Registry, rev.Version)
+	}
+
+	return &models.Prompt{
+		ID:        id,
+		Version:   fmt.Sprintf("%d.0.0", rev.Version),
+		Name:      rev.Name,
+		Summary:   summary,
+		Content:   rev.Content,
+		Tags:      tags,
+		CreatedAt: created,
+		UpdatedAt: created,
+		FilePath:  filepath.Join("prompts", id+".md"),
+		Metadata:  metadata,
+	}
+}
+
+// Langfuse
+
+// langfusePrompt matches a prompt version from the Langfuse public API / export
+type langfusePrompt struct {
+	Name          string                 `json:"name"`
+	Version       int                    `json:"version"`
+	Type          string                 `json:"type"`
+	Prompt        json.RawMessage        `json:"prompt"`
+	Config        map[string]interface{} `json:"config"`
+	Labels        []string               `json:"labels"`
+	Tags          []string               `json:"tags"`
+	CommitMessage string                 `json:"commitMessage"`
+	CreatedAt     time.Time              `json:"createdAt"`
+}
+
+type chatMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// parseLangfuseExport accepts a list of prompt versions, either bare or wrapped in "data"/"prompts"
+func parseLangfuseExport(data []byte) ([]registryVersion, error) {
+	var items []langfusePrompt
+	if err := unmarshalList(data, &items, "data", "prompts"); err != nil {
+		return nil, err
+	}
+
+	versions := make([]registryVersion, 0, len(items))
+	for _, item := range items {
+		content, err := DecodeLangfusePrompt(item.Prompt)
+		if err != nil {
+			return nil, fmt.Errorf("%s v%d: %w", item.Name, item.Version, err)
+		}
+		versions = append(versions, registryVersion{
+			Name:          item.Name,
+			Version:       item.Version,
+			Content:       content,
+			Tags:          item.Tags,
+			Labels:        item.Labels,
+			CommitMessage: item.CommitMessage,
+			Config:        item.Config,
+			CreatedAt:     item.CreatedAt,
+		})
+	}
+	return versions, nil
+}
+
+// DecodeLangfusePrompt flattens a Langfuse text or chat prompt body into markdown.
+// Langfuse already uses {{variable}} placeholders, so no syntax conversion is needed.
+func DecodeLangfusePrompt(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+
+	var messages []chatMessage
+	if err := json.Unmarshal(raw, &messages); err != nil {
+		return "", fmt.Errorf("unrecognized prompt body")
+	}
+	return chatToMarkdown(messages), nil
+}
+
+// PromptLayer
+
+// promptLayerTemplate matches a PromptLayer registry template, either with a
+// nested "versions" list or as a single flattened version
+type promptLayerTemplate struct {
+	PromptName     string                 `json:"prompt_name"`
+	Tags           []string               `json:"tags"`
+	Versions       []promptLayerVersion   `json:"versions"`
+	PromptTemplate *promptLayerBody       `json:"prompt_template"`
+	Version        int                    `json:"version"`
+	CommitMessage  string                 `json:"commit_message"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+type promptLayerVersion struct {
+	Version        int                    `json:"version"`
+	PromptTemplate *promptLayerBody       `json:"prompt_template"`
+	CommitMessage  string                 `json:"commit_message"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+type promptLayerBody struct {
+	Type           string          `json:"type"`
+	TemplateFormat string          `json:"template_format"`
+	Content        json.RawMessage `json:"content"`
+	Messages       []struct {
+		Role           string          `json:"role"`
+		Content        json.RawMessage `json:"content"`
+		TemplateFormat string          `json:"template_format"`
+	} `json:"messages"`
+}
+
+// parsePromptLayerExport accepts a list of templates, either bare or wrapped in "items"/"prompt_templates"
+func parsePromptLayerExport(data []byte) ([]registryVersion, error) {
+	var templates []promptLayerTemplate
+	if err := unmarshalList(data, &templates, "items", "prompt_templates"); err != nil {
+		return nil, err
+	}
+
+	var versions []registryVersion
+	for _, tmpl := range templates {
+		revisions := tmpl.Versions
+		if len(revisions) == 0 && tmpl.PromptTemplate != nil {
+			revisions = []promptLayerVersion{{
+				Version:        tmpl.Version,
+				PromptTemplate: tmpl.PromptTemplate,
+				CommitMessage:  tmpl.CommitMessage,
+				Metadata:       tmpl.Metadata,
+				CreatedAt:      tmpl.CreatedAt,
+			}}
+		}
+
+		for _, rev := range revisions {
+			version := rev.Version
+			if version == 0 {
+				version = 1
+			}
+			var config map[string]interface{}
+			if model, ok := rev.Metadata["model"].(map[string]interface{}); ok {
+				config = model
+			}
+			versions = append(versions, registryVersion{
+				Name:          tmpl.PromptName,
+				Version:       version,
+				Content:       promptLayerContent(rev.PromptTemplate),
+				Tags:          tmpl.Tags,
+				CommitMessage: rev.CommitMessage,
+				Config:        config,
+				CreatedAt:     rev.CreatedAt,
+			})
+		}
+	}
+	return versions, nil
+}
+
+// promptLayerContent flattens completion or chat templates into markdown,
+// converting f-string placeholders to pocket-prompt's {{variable}} syntax
+func promptLayerContent(body *promptLayerBody) string {
+	if body == nil {
+		return ""
+	}
+
+	if body.Type == "chat" || len(body.Messages) > 0 {
+		messages := make([]chatMessage, 0, len(body.Messages))
+		for _, msg := range body.Messages {
+			content := msg.Content
+			format := msg.TemplateFormat
+			if format == "" {
+				format = body.TemplateFormat
+			}
+			if format != "jinja2" {
+				converted, _ := json.Marshal(convertFString(contentText(msg.Content)))
+				content = converted
+			}
+			messages = append(messages, chatMessage{Role: msg.Role, Content: content})
+		}
+		return chatToMarkdown(messages)
+	}
+
+	text := contentText(body.Content)
+	if body.TemplateFormat != "jinja2" {
+		text = convertFString(text)
+	}
+	return text
+}
+
+// Shared helpers
+
+// unmarshalList decodes either a bare JSON array or an object wrapping the array in one of keys
+func unmarshalList(data []byte, out interface{}, keys ...string) error {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		return json.Unmarshal(data, out)
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if list, ok := wrapper[key]; ok {
+			return json.Unmarshal(list, out)
+		}
+	}
+
+	// A single exported object
+	return json.Unmarshal([]byte("["+trimmed+"]"), out)
+}
+
+// contentText extracts text from a string or a list of {type: text, text: ...} parts
+func contentText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err == nil {
+		var texts []string
+		for _, part := range parts {
+			if part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+		}
+		return strings.Join(texts, "\n\n")
+	}
+
+	return string(raw)
+}
+
+// chatToMarkdown renders chat messages as one markdown section per message
+func chatToMarkdown(messages []chatMessage) string {
+	var sections []string
+	for _, msg := range messages {
+		role := msg.Role
+		if role == "" {
+			role = "message"
+		}
+		heading := strings.ToUpper(role[:1]) + role[1:]
+		sections = append(sections, fmt.Sprintf("## %s\n\n%s", heading, strings.TrimSpace(contentText(msg.Content))))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+var fStringVariable = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// convertFString rewrites {name} placeholders as {{name}} and unescapes {{ and }}
+func convertFString(text string) string {
+	const open, close = "\x00", "\x01"
+	text = strings.ReplaceAll(text, "{{", open)
+	text = strings.ReplaceAll(text, "}}", close)
+	text = fStringVariable.ReplaceAllString(text, "{{$1}}")
+	text = strings.ReplaceAll(text, open, "{")
+	return strings.ReplaceAll(text, close, "}")
+}
+
+// RegistryPromptID turns a registry prompt name into a pocket-prompt ID
+func RegistryPromptID(name string) string {
+	id := models.Slug(name)
+	if id == "" {
+		id = "registry-prompt"
+	}
+	return id
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
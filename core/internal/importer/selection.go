@@ -0,0 +1,64 @@
+package importer
+
+import "github.com/dpshade/pocket-prompt/internal/models"
+
+// Kinds of importable item, used in selection keys
+const (
+	ItemPrompt   = "prompt"
+	ItemTemplate = "template"
+	ItemWorkflow = "workflow"
+)
+
+// ItemKey identifies an importable item in ImportOptions.Selection
+func ItemKey(kind, id string) string {
+	return kind + ":" + id
+}
+
+// Includes reports whether an item is part of the import
+func (o ImportOptions) Includes(kind, id string) bool {
+	return o.Selection == nil || o.Selection[ItemKey(kind, id)]
+}
+
+// ApplySelection drops the items options does not include and gives merged
+// prompts their merged content
+func (r *ImportResult) ApplySelection(options ImportOptions) {
+	applyMerged(r.Prompts, ItemPrompt, options)
+	applyMerged(r.Workflows, ItemWorkflow, options)
+	if options.Selection == nil {
+		return
+	}
+	r.Prompts = selectPrompts(r.Prompts, ItemPrompt, options)
+	r.Workflows = selectPrompts(r.Workflows, ItemWorkflow, options)
+
+	var templates []*models.Template
+	for _, template := range r.Templates {
+		if options.Includes(ItemTemplate, template.ID) {
+			templates = append(templates, template)
+		}
+	}
+	r.Templates = templates
+}
+
+// ApplySelection drops unselected prompts along with their archived revisions
+func (r *RegistryImportResult) ApplySelection(options ImportOptions) {
+	r.ImportResult.ApplySelection(options)
+	r.Archived = selectPrompts(r.Archived, ItemPrompt, options)
+}
+
+func applyMerged(prompts []*models.Prompt, kind string, options ImportOptions) {
+	for _, prompt := range prompts {
+		if content, ok := options.Merged[ItemKey(kind, prompt.ID)]; ok {
+			prompt.Content = content
+		}
+	}
+}
+
+func selectPrompts(prompts []*models.Prompt, kind string, options ImportOptions) []*models.Prompt {
+	var selected []*models.Prompt
+	for _, prompt := range prompts {
+		if options.Includes(kind, prompt.ID) {
+			selected = append(selected, prompt)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,41 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SourceDirs returns the directories Import reads for the given options, whether
+// or not they exist yet
+func (i *ClaudeCodeImporter) SourceDirs(options ImportOptions) []string {
+	var dirs []string
+	for _, path := range i.determinePaths(options) {
+		d := sourceDirsFor(path, options)
+		for _, dir := range []string{d.commands, d.agents, d.workflows} {
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// SourceState records the modification time of every file under the source
+// directories. Two states differ when a file was added, edited or removed, so a
+// watcher can compare them to decide when to import again.
+func (i *ClaudeCodeImporter) SourceState(options ImportOptions) map[string]time.Time {
+	state := make(map[string]time.Time)
+	for _, dir := range i.SourceDirs(options) {
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil // Missing or unreadable directories just contribute nothing
+			}
+			if !info.IsDir() {
+				state[path] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return state
+}
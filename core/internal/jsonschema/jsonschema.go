@@ -0,0 +1,296 @@
+// Package jsonschema validates JSON documents against the commonly used subset
+// of JSON Schema: type, enum, const, properties, required,
+// additionalProperties, items, the length and size bounds, numeric bounds,
+// pattern, and the anyOf/oneOf/allOf combinators. Unsupported keywords are
+// ignored rather than rejected, so schemas written for other tools still load.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a decoded JSON Schema document
+type Schema map[string]interface{}
+
+// Normalize converts a schema decoded from YAML, TOML or JSON into plain JSON
+// values, so numbers are float64 and nested objects are map[string]interface{}
+func Normalize(v interface{}) (Schema, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("schema must be a JSON object: %w", err)
+	}
+	return schema, nil
+}
+
+// ValidateJSON parses data and validates it against the schema. It returns one
+// message per violation, each prefixed with the JSON path it applies to.
+// Malformed JSON is reported as a violation of the root.
+func (s Schema) ValidateJSON(data []byte) []string {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []string{"$: invalid JSON: " + err.Error()}
+	}
+	return s.Validate(doc)
+}
+
+// Validate checks a decoded JSON value against the schema
+func (s Schema) Validate(doc interface{}) []string {
+	var errs []string
+	validate(s, doc, "$", &errs)
+	return errs
+}
+
+func validate(schema map[string]interface{}, v interface{}, path string, errs *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, v) {
+		fail("expected %s, got %s", describeType(t), typeOf(v))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			if reflect.DeepEqual(option, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value %s is not one of %s", encode(v), encode(enum))
+		}
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		fail("value %s must equal %s", encode(v), encode(c))
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		validateObject(schema, value, path, errs)
+	case []interface{}:
+		validateArray(schema, value, path, errs)
+	case string:
+		length := len([]rune(value))
+		if min, ok := number(schema["minLength"]); ok && float64(length) < min {
+			fail("string is shorter than %v characters", min)
+		}
+		if max, ok := number(schema["maxLength"]); ok && float64(length) > max {
+			fail("string is longer than %v characters", max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(value) {
+				fail("string does not match pattern %s", pattern)
+			}
+		}
+	case float64:
+		if min, ok := number(schema["minimum"]); ok && value < min {
+			fail("%v is less than the minimum %v", value, min)
+		}
+		if max, ok := number(schema["maximum"]); ok && value > max {
+			fail("%v is greater than the maximum %v", value, max)
+		}
+		if min, ok := number(schema["exclusiveMinimum"]); ok && value <= min {
+			fail("%v must be greater than %v", value, min)
+		}
+		if max, ok := number(schema["exclusiveMaximum"]); ok && value >= max {
+			fail("%v must be less than %v", value, max)
+		}
+	}
+
+	validateCombinators(schema, v, path, errs)
+}
+
+func validateObject(schema, obj map[string]interface{}, path string, errs *[]string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := obj[key]; !present {
+					*errs = append(*errs, fmt.Sprintf("%s: missing required property %q", path, key))
+				}
+			}
+		}
+	}
+	if min, ok := number(schema["minProperties"]); ok && float64(len(obj)) < min {
+		*errs = append(*errs, fmt.Sprintf("%s: object has fewer than %v properties", path, min))
+	}
+	if max, ok := number(schema["maxProperties"]); ok && float64(len(obj)) > max {
+		*errs = append(*errs, fmt.Sprintf("%s: object has more than %v properties", path, max))
+	}
+
+	// Walk keys in order so errors come out the same way every run
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "." + key
+		if sub, ok := properties[key].(map[string]interface{}); ok {
+			validate(sub, obj[key], childPath, errs)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				*errs = append(*errs, fmt.Sprintf("%s: unexpected property %q", path, key))
+			}
+		case map[string]interface{}:
+			validate(extra, obj[key], childPath, errs)
+		}
+	}
+}
+
+func validateArray(schema map[string]interface{}, arr []interface{}, path string, errs *[]string) {
+	if min, ok := number(schema["minItems"]); ok && float64(len(arr)) < min {
+		*errs = append(*errs, fmt.Sprintf("%s: array has fewer than %v items", path, min))
+	}
+	if max, ok := number(schema["maxItems"]); ok && float64(len(arr)) > max {
+		*errs = append(*errs, fmt.Sprintf("%s: array has more than %v items", path, max))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if reflect.DeepEqual(arr[i], arr[j]) {
+					*errs = append(*errs, fmt.Sprintf("%s: items %d and %d are equal", path, i, j))
+				}
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range arr {
+			validate(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateCombinators(schema map[string]interface{}, v interface{}, path string, errs *[]string) {
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, s := range all {
+			if sub, ok := s.(map[string]interface{}); ok {
+				validate(sub, v, path, errs)
+			}
+		}
+	}
+	matches := func(list []interface{}) int {
+		n := 0
+		for _, s := range list {
+			if sub, ok := s.(map[string]interface{}); ok {
+				var subErrs []string
+				validate(sub, v, path, &subErrs)
+				if len(subErrs) == 0 {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok && matches(anyOf) == 0 {
+		*errs = append(*errs, fmt.Sprintf("%s: value does not match any of the anyOf schemas", path))
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		if n := matches(oneOf); n != 1 {
+			*errs = append(*errs, fmt.Sprintf("%s: value matches %d of the oneOf schemas, want exactly 1", path, n))
+		}
+	}
+}
+
+// matchesType reports whether v has the type named by t, a string or a list of strings
+func matchesType(t interface{}, v interface{}) bool {
+	switch typ := t.(type) {
+	case string:
+		return isType(typ, v)
+	case []interface{}:
+		for _, option := range typ {
+			if name, ok := option.(string); ok && isType(name, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func isType(name string, v interface{}) bool {
+	switch name {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true // Unknown type names are not enforced
+}
+
+func typeOf(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func describeType(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		names := make([]string, 0, len(list))
+		for _, option := range list {
+			names = append(names, fmt.Sprint(option))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func number(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func encode(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
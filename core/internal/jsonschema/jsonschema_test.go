@@ -0,0 +1,45 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateJSON(t *testing.T) {
+	schema, err := Normalize(map[string]interface{}{
+		"type":     "object",
+		"required": []string{"title", "score"},
+		"properties": map[string]interface{}{
+			"title": map[string]interface{}{"type": "string", "minLength": 1},
+			"score": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 10},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"enum": []string{"bug", "feature"}},
+			},
+		},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+
+	errs := schema.ValidateJSON([]byte(`{"title": "Fix", "score": 7, "tags": ["bug"]}`))
+	if len(errs) != 0 {
+		t.Fatalf("valid document reported errors: %v", errs)
+	}
+
+	errs = schema.ValidateJSON([]byte(`{"score": 11.5, "tags": ["chore"], "extra": true}`))
+	want := []string{
+		`$: missing required property "title"`,
+		`$: unexpected property "extra"`,
+		`$.score: expected integer, got number`,
+		`$.tags[0]: value "chore" is not one of ["bug","feature"]`,
+	}
+	if strings.Join(errs, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("errors =\n%s\nwant\n%s", strings.Join(errs, "\n"), strings.Join(want, "\n"))
+	}
+
+	if errs := schema.ValidateJSON([]byte(`{not json`)); len(errs) != 1 || !strings.HasPrefix(errs[0], "$: invalid JSON") {
+		t.Fatalf("malformed JSON errors = %v", errs)
+	}
+}
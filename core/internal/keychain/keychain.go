@@ -0,0 +1,59 @@
+// Package keychain reads secrets from the operating system's credential store:
+// the login keychain on macOS and the Secret Service (GNOME Keyring, KWallet)
+// on Linux, through their command-line tools
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNotFound means the credential store has no matching entry
+var ErrNotFound = errors.New("not found in keychain")
+
+// Lookup returns the secret stored under ref, written "service" or
+// "service/account"
+func Lookup(ref string) (string, error) {
+	service, account, _ := strings.Cut(ref, "/")
+	if service == "" {
+		return "", fmt.Errorf("invalid keychain reference %q (expected service or service/account)", ref)
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		args := []string{"find-generic-password", "-s", service, "-w"}
+		if account != "" {
+			args = append(args, "-a", account)
+		}
+		cmd = exec.Command("security", args...)
+	case "linux":
+		args := []string{"lookup", "service", service}
+		if account != "" {
+			args = append(args, "account", account)
+		}
+		cmd = exec.Command("secret-tool", args...)
+	default:
+		return "", fmt.Errorf("keychain lookups are not supported on %s; use env instead", runtime.GOOS)
+	}
+
+	if cmd.Err != nil {
+		return "", fmt.Errorf("keychain tool %s is not installed", cmd.Args[0])
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// Both tools exit non-zero when nothing matches
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("%s: %w", ref, ErrNotFound)
+		}
+		return "", fmt.Errorf("keychain lookup for %s failed: %w", ref, err)
+	}
+	secret := strings.TrimSuffix(string(out), "\n")
+	if secret == "" {
+		return "", fmt.Errorf("%s: %w", ref, ErrNotFound)
+	}
+	return secret, nil
+}
@@ -0,0 +1,139 @@
+// Package language detects the natural language a prompt is written in.
+// Detection is offline and deliberately simple: the writing system settles
+// it for scripts used by one language, and for Latin text each candidate
+// language is scored by how many of its most common words appear.
+package language
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+)
+
+// Minimum evidence for a detection: letters in the text, and common words
+// of the language found in Latin text
+const (
+	minLetters     = 12
+	minCommonWords = 2
+)
+
+// commonWords are frequent words that tell the Latin-script languages apart
+var commonWords = map[string]map[string]bool{
+	"en": wordSet("the and is are to of that it you for with this be on as your what how not or can will an from have"),
+	"de": wordSet("der die das und ist nicht ein eine zu den mit von sie du ich es auf für im dem wie sich auch werden bitte oder"),
+	"fr": wordSet("le la les et est des une un du que qui pour dans pas vous sur ce avec au sont il elle tu ou"),
+	"es": wordSet("el la los las y es que en un una por para con no del se al como su lo más tu usted"),
+	"it": wordSet("il la le di che è un una per non con del della sono gli come anche nel ed tu"),
+	"pt": wordSet("o a os as e é que um uma para com não do da em por no na se você seu sua"),
+	"nl": wordSet("de het een en is van dat niet op te met voor zijn je ik er die aan wat of"),
+}
+
+var (
+	templateVariable = regexp.MustCompile(`\{\{[^}]*\}\}`)
+	codeBlock        = regexp.MustCompile("(?s)```.*?(```|$)")
+)
+
+// Detect returns the language code of text, such as en or de, or "" when
+// there is too little text to tell. Code blocks and {{variables}} are
+// ignored, as they are mostly English whatever the prompt's language.
+func Detect(text string) string {
+	text = codeBlock.ReplaceAllString(text, " ")
+	text = templateVariable.ReplaceAllString(text, " ")
+
+	scripts := map[string]int{}
+	letters, kana := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+			scripts["ja"]++
+		case unicode.Is(unicode.Han, r):
+			scripts["zh"]++
+		case unicode.Is(unicode.Hangul, r):
+			scripts["ko"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scripts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			scripts["ar"]++
+		case unicode.Is(unicode.Greek, r):
+			scripts["el"]++
+		case unicode.Is(unicode.Hebrew, r):
+			scripts["he"]++
+		case unicode.Is(unicode.Devanagari, r):
+			scripts["hi"]++
+		case unicode.Is(unicode.Thai, r):
+			scripts["th"]++
+		}
+	}
+	if letters < minLetters {
+		return ""
+	}
+	// Japanese mixes kana with Han characters
+	if kana > 0 {
+		scripts["ja"] += scripts["zh"]
+		delete(scripts, "zh")
+	}
+	for code, n := range scripts {
+		if n*2 >= letters {
+			return code
+		}
+	}
+
+	found := map[string]int{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for code, words := range commonWords {
+			if words[word] {
+				found[code]++
+			}
+		}
+	}
+	best, bestCount, tied := "", 0, false
+	for code, n := range found {
+		switch {
+		case n > bestCount:
+			best, bestCount, tied = code, n, false
+		case n == bestCount:
+			tied = true
+		}
+	}
+	if bestCount < minCommonWords || tied {
+		return ""
+	}
+	return best
+}
+
+// Normalize turns a language given by hand, such as DE or pt_br, into a tag
+// like de or pt-BR, or "" when it is not a language tag
+func Normalize(code string) string {
+	return i18n.Normalize(code)
+}
+
+// Name returns the English name of the language code, such as German for
+// de, or the code itself when it is unknown
+func Name(code string) string {
+	tag, err := language.Parse(code)
+	if err != nil {
+		return code
+	}
+	if name := display.English.Tags().Name(tag); name != "" {
+		return name
+	}
+	return code
+}
+
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
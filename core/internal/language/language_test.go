@@ -0,0 +1,36 @@
+package language
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "Review the code and explain what it does.", "en"},
+		{"german", "Bitte prüfe den Code und erkläre, was er tut und wie er sich verhält.", "de"},
+		{"french", "Résume le texte suivant pour un public qui ne le connaît pas.", "fr"},
+		{"spanish", "Escribe un resumen del texto para un lector que no lo conoce.", "es"},
+		{"japanese", "次のコードをレビューして、問題点を説明してください。", "ja"},
+		{"russian", "Проверь этот код и объясни, что он делает.", "ru"},
+		{"code and variables are ignored", "Bitte erkläre den Code und die Fehler:\n```go\nfunc main() { if err != nil { return the error } }\n```\n{{the_code_for_you}}", "de"},
+		{"too short", "hi", ""},
+		{"no common words", "Lorem ipsum dolor sit amet consectetur", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	for code, want := range map[string]string{"de": "German", "pt-BR": "Brazilian Portuguese", "zz-nope-x": "zz-nope-x"} {
+		if got := Name(code); got != want {
+			t.Errorf("Name(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Package lint checks prompt content against configurable style rules: size,
+// forbidden phrases, required sections, heading structure and passive voice.
+//
+// Rules can be silenced inside a prompt with HTML comments, which Markdown
+// renderers hide. This one silences a rule for the whole prompt:
+//
+//	<!-- pkt-lint-disable passive-voice -->
+//
+// and this one for the line after it:
+//
+//	<!-- pkt-lint-disable-next-line forbidden-phrase -->
+//
+// Without rule names a comment silences every rule.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+// Rule names, as used in the config and in suppression comments
+const (
+	RuleMaxTokens        = "max-tokens"
+	RuleForbiddenPhrase  = "forbidden-phrase"
+	RuleRequiredSection  = "required-section"
+	RuleHeadingStructure = "heading-structure"
+	RulePassiveVoice     = "passive-voice"
+)
+
+// Library checks reported alongside the style rules. They are always errors
+// and cannot be configured or silenced.
+const (
+	RuleFrontmatter = "frontmatter"  // The frontmatter does not parse or lacks an id
+	RuleDuplicateID = "duplicate-id" // Another prompt uses the same id
+)
+
+// Severity is how seriously an issue is reported
+type Severity string
+
+// Severities. Off disables a rule.
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Off     Severity = "off"
+)
+
+// defaultSeverity lists every rule with the severity it has unless configured
+var defaultSeverity = map[string]Severity{
+	RuleMaxTokens:        Error,
+	RuleForbiddenPhrase:  Error,
+	RuleRequiredSection:  Error,
+	RuleHeadingStructure: Warning,
+	RulePassiveVoice:     Warning,
+}
+
+// Issue is one rule violation. Line counts from 1 at the start of the
+// content, or is 0 for issues about the prompt as a whole.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Linter checks content against a set of rules
+type Linter struct {
+	cfg      config.LintConfig
+	severity map[string]Severity
+}
+
+var (
+	headingLine  = regexp.MustCompile(`^(#{1,6})(?:\s+(.*?))?\s*#*\s*$`)
+	disableRule  = regexp.MustCompile(`<!--\s*pkt-lint-disable(-next-line)?\b([^>]*?)-->`)
+	passiveVoice = regexp.MustCompile(`(?i)\b(?:am|is|are|was|were|be|been|being)\s+(?:\w+ly\s+)?(\w{2,}ed|known|written|given|done|made|seen|taken|shown|told|built|found|kept|sent|held|chosen|broken|spoken|driven|forgotten|hidden|understood)\b`)
+)
+
+// New returns a linter for cfg, rejecting unknown rules and severities
+func New(cfg config.LintConfig) (*Linter, error) {
+	severity := make(map[string]Severity, len(defaultSeverity))
+	for rule, s := range defaultSeverity {
+		severity[rule] = s
+	}
+	for rule, s := range cfg.Severity {
+		if _, ok := defaultSeverity[rule]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %q (expected one of %s)", rule, strings.Join(Rules(), ", "))
+		}
+		switch Severity(s) {
+		case Error, Warning, Off:
+			severity[rule] = Severity(s)
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint rule %s (expected error, warning or off)", s, rule)
+		}
+	}
+	return &Linter{cfg: cfg, severity: severity}, nil
+}
+
+// Rules returns the names of all rules, sorted
+func Rules() []string {
+	rules := make([]string, 0, len(defaultSeverity))
+	for rule := range defaultSeverity {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// Check returns the issues in content, ordered by line
+func (l *Linter) Check(content string) []Issue {
+	lines := strings.Split(content, "\n")
+	disabled, disabledLines := suppressions(lines)
+
+	var issues []Issue
+	report := func(rule string, line int, format string, args ...interface{}) {
+		severity := l.severity[rule]
+		if severity == Off || disabled[rule] || disabled[""] {
+			return
+		}
+		if line > 0 && (disabledLines[line][rule] || disabledLines[line][""]) {
+			return
+		}
+		issues = append(issues, Issue{Rule: rule, Severity: severity, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if l.cfg.MaxTokens > 0 {
+		if tokens := (utf8.RuneCountInString(content) + 3) / 4; tokens > l.cfg.MaxTokens {
+			report(RuleMaxTokens, 0, "about %d tokens, over the limit of %d", tokens, l.cfg.MaxTokens)
+		}
+	}
+
+	found := make(map[string]bool)
+	lastLevel := 0
+	inFence := false
+	for i, line := range lines {
+		n := i + 1
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		for _, section := range l.cfg.RequiredSections {
+			if isSection(trimmed, section) {
+				found[section] = true
+			}
+		}
+
+		if m := headingLine.FindStringSubmatch(trimmed); m != nil {
+			level := len(m[1])
+			if m[2] == "" {
+				report(RuleHeadingStructure, n, "empty heading")
+			}
+			if lastLevel > 0 && level > lastLevel+1 {
+				report(RuleHeadingStructure, n, "heading level %d follows level %d; don't skip levels", level, lastLevel)
+			} else if lastLevel == 0 && level > 2 {
+				report(RuleHeadingStructure, n, "first heading is level %d; start at level 1 or 2", level)
+			}
+			lastLevel = level
+		}
+
+		lower := strings.ToLower(line)
+		for _, phrase := range l.cfg.ForbiddenPhrases {
+			if phrase != "" && strings.Contains(lower, strings.ToLower(phrase)) {
+				report(RuleForbiddenPhrase, n, "forbidden phrase %q", phrase)
+			}
+		}
+
+		for _, m := range passiveVoice.FindAllString(line, -1) {
+			report(RulePassiveVoice, n, "possible passive voice %q; prefer direct instructions", m)
+		}
+	}
+
+	for _, section := range l.cfg.RequiredSections {
+		if !found[section] {
+			report(RuleRequiredSection, 0, "missing required section %q", section)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// isSection reports whether line is a heading named section, or a line
+// starting with the section name and a colon, such as "**Output format:** JSON"
+func isSection(line, section string) bool {
+	text := strings.TrimSpace(strings.TrimLeft(line, "#"))
+	text = strings.TrimLeft(text, "*_ ")
+	if len(text) < len(section) || !strings.EqualFold(text[:len(section)], section) {
+		return false
+	}
+	rest := strings.TrimLeft(text[len(section):], "*_ ")
+	return rest == "" || strings.HasPrefix(rest, ":")
+}
+
+// suppressions reads the disable comments in lines. The first map holds rules
+// disabled for the whole content, the second rules disabled by line number,
+// with "" standing for every rule.
+func suppressions(lines []string) (map[string]bool, map[int]map[string]bool) {
+	disabled := make(map[string]bool)
+	disabledLines := make(map[int]map[string]bool)
+	for i, line := range lines {
+		for _, m := range disableRule.FindAllStringSubmatch(line, -1) {
+			rules := strings.FieldsFunc(m[2], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+			if len(rules) == 0 {
+				rules = []string{""}
+			}
+			for _, rule := range rules {
+				if m[1] == "" {
+					disabled[rule] = true
+					continue
+				}
+				if disabledLines[i+2] == nil {
+					disabledLines[i+2] = make(map[string]bool)
+				}
+				disabledLines[i+2][rule] = true
+			}
+		}
+	}
+	return disabled, disabledLines
+}
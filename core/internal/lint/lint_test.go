@@ -0,0 +1,91 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+func TestCheck(t *testing.T) {
+	l, err := New(config.LintConfig{
+		MaxTokens:        10,
+		ForbiddenPhrases: []string{"As an AI"},
+		RequiredSections: []string{"Output format", "Examples"},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	content := `# Review
+#### Details
+As an ai, review the code.
+The code was written quickly.
+` + "```" + `
+### Inside a fence is ignored
+` + "```" + `
+**Output format:** a bulleted list`
+
+	issues := l.Check(content)
+	want := []struct {
+		rule string
+		line int
+	}{
+		{RuleMaxTokens, 0},
+		{RuleRequiredSection, 0},
+		{RuleHeadingStructure, 2},
+		{RuleForbiddenPhrase, 3},
+		{RulePassiveVoice, 4},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Rule != w.rule || issues[i].Line != w.line {
+			t.Errorf("issue %d = %s at line %d, want %s at line %d", i, issues[i].Rule, issues[i].Line, w.rule, w.line)
+		}
+	}
+	if issues[1].Message != `missing required section "Examples"` {
+		t.Errorf("unexpected message %q", issues[1].Message)
+	}
+	if issues[4].Severity != Warning {
+		t.Errorf("passive voice severity = %s, want warning", issues[4].Severity)
+	}
+}
+
+func TestCheckSuppressionsAndSeverity(t *testing.T) {
+	l, err := New(config.LintConfig{
+		ForbiddenPhrases: []string{"delve"},
+		Severity:         map[string]string{RuleHeadingStructure: "off", RulePassiveVoice: "error"},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	content := `### Skipped levels are off
+<!-- pkt-lint-disable-next-line forbidden-phrase -->
+Delve into the details.
+Delve again.
+The plan is made by the team.`
+
+	issues := l.Check(content)
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2: %+v", len(issues), issues)
+	}
+	if issues[0].Rule != RuleForbiddenPhrase || issues[0].Line != 4 {
+		t.Errorf("first issue = %+v, want forbidden-phrase at line 4", issues[0])
+	}
+	if issues[1].Rule != RulePassiveVoice || issues[1].Severity != Error {
+		t.Errorf("second issue = %+v, want passive-voice error", issues[1])
+	}
+
+	if got := l.Check("<!-- pkt-lint-disable -->\nDelve. It is done."); len(got) != 0 {
+		t.Errorf("disable without rules should silence everything, got %+v", got)
+	}
+
+	if _, err := New(config.LintConfig{Severity: map[string]string{"no-such-rule": "error"}}); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+	if _, err := New(config.LintConfig{Severity: map[string]string{RulePassiveVoice: "fatal"}}); err == nil {
+		t.Error("expected an error for an invalid severity")
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Expiry returns when the prompt expires, or the zero time when it has no
+// expires date. A date without a time expires at the end of that day, in
+// local time.
+func (p Prompt) Expiry() (time.Time, error) {
+	if p.Expires == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.ParseInLocation("2006-01-02", p.Expires, time.Local); err == nil {
+		return day.AddDate(0, 0, 1), nil
+	}
+	if t, err := time.Parse(time.RFC3339, p.Expires); err == nil {
+		// A TOML date such as expires = 2026-12-31 arrives as midnight UTC
+		if strings.HasSuffix(p.Expires, "T00:00:00Z") {
+			return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.Local), nil
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expires %q (use a date such as 2026-12-31 or an RFC 3339 time)", p.Expires)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PromptLock is an advisory claim that someone is editing a prompt. Locks
+// never stop a save; interfaces show them before editing so teammates sharing
+// a library don't silently overwrite each other.
+type PromptLock struct {
+	ID      string    `json:"id"`             // Prompt ID
+	Owner   string    `json:"owner"`          // Who is editing
+	Note    string    `json:"note,omitempty"` // What they are doing
+	Since   time.Time `json:"since"`
+	Expires time.Time `json:"expires"` // Locks left behind stop counting after this
+}
+
+// Expired reports whether the lock has lapsed at now
+func (l *PromptLock) Expired(now time.Time) bool {
+	return !l.Expires.IsZero() && !now.Before(l.Expires)
+}
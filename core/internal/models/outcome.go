@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Outcome is how one run of a prompt went
+type Outcome string
+
+const (
+	OutcomeGood Outcome = "good"
+	OutcomeBad  Outcome = "bad"
+)
+
+// ParseOutcome accepts "good" or "bad"
+func ParseOutcome(value string) (Outcome, error) {
+	switch outcome := Outcome(value); outcome {
+	case OutcomeGood, OutcomeBad:
+		return outcome, nil
+	}
+	return "", fmt.Errorf("outcome must be good or bad, not %q", value)
+}
+
+// OutcomeEntry records the result of running a prompt, so changes to it can
+// be judged by how they perform
+type OutcomeEntry struct {
+	Time    time.Time `json:"time"`
+	Version string    `json:"version,omitempty"` // Prompt version that was run
+	Outcome Outcome   `json:"outcome"`
+	Note    string    `json:"note,omitempty"`
+	Author  string    `json:"author,omitempty"`
+}
+
+// OutcomeSummary counts a prompt's logged outcomes
+type OutcomeSummary struct {
+	Good int       `json:"good"`
+	Bad  int       `json:"bad"`
+	Last time.Time `json:"last,omitempty"`
+}
+
+// SummarizeOutcomes counts good and bad outcomes and finds the latest
+func SummarizeOutcomes(entries []OutcomeEntry) OutcomeSummary {
+	var summary OutcomeSummary
+	for _, entry := range entries {
+		switch entry.Outcome {
+		case OutcomeGood:
+			summary.Good++
+		case OutcomeBad:
+			summary.Bad++
+		}
+		if entry.Time.After(summary.Last) {
+			summary.Last = entry.Time
+		}
+	}
+	return summary
+}
+
+// Total returns how many outcomes were logged
+func (s OutcomeSummary) Total() int {
+	return s.Good + s.Bad
+}
+
+// SuccessRate returns the share of good outcomes from 0 to 1, or 0 with none
+func (s OutcomeSummary) SuccessRate() float64 {
+	if s.Total() == 0 {
+		return 0
+	}
+	return float64(s.Good) / float64(s.Total())
+}
+
+// String describes the summary, e.g. "7 good, 2 bad (78%)"
+func (s OutcomeSummary) String() string {
+	return fmt.Sprintf("%d good, %d bad (%.0f%%)", s.Good, s.Bad, s.SuccessRate()*100)
+}
@@ -1,28 +1,87 @@
 package models
 
 import (
+	"regexp"
 	"strings"
 	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
 )
 
+// CurrentSchemaVersion is the prompt file format version written by this build.
+// Files without a schema field are treated as version 1.
+const CurrentSchemaVersion = 2
+
 // Prompt represents a prompt artifact with YAML frontmatter and markdown content
 type Prompt struct {
 	// Frontmatter fields
-	ID           string                 `yaml:"id"`
-	Version      string                 `yaml:"version"`
-	Name         string                 `yaml:"title"`
-	Summary      string                 `yaml:"description"`
-	Tags         []string               `yaml:"tags"`
-	TemplateRef  string                 `yaml:"template,omitempty"`
-	Pack         string                 `yaml:"pack,omitempty"`
-	Metadata     map[string]interface{} `yaml:"metadata,omitempty"`
-	CreatedAt    time.Time              `yaml:"created_at"`
-	UpdatedAt    time.Time              `yaml:"updated_at"`
+	Schema        int                    `yaml:"schema,omitempty"`
+	ID            string                 `yaml:"id"`
+	Version       string                 `yaml:"version"`
+	Name          string                 `yaml:"title"`
+	Summary       string                 `yaml:"description"`
+	Tags          []string               `yaml:"tags"`
+	TemplateRef   string                 `yaml:"template,omitempty"`
+	Pack          string                 `yaml:"pack,omitempty"`
+	VariantGroup  string                 `yaml:"variant_group,omitempty"`  // Experiment this prompt is one variant of
+	Language      string                 `yaml:"language,omitempty"`       // Language it is written in, such as en or pt-BR
+	TranslationOf string                 `yaml:"translation_of,omitempty"` // ID of the prompt it was translated from
+	Protected     bool                   `yaml:"protected,omitempty"`      // Refuse deletes and overwrites without --force-protected
+	Expires       string                 `yaml:"expires,omitempty"`        // Date or time maintenance archives the prompt after, such as 2026-12-31
+	Metadata      map[string]interface{} `yaml:"metadata,omitempty"`
+	Review        *Review                `yaml:"review,omitempty"`
+	Attachments   []string               `yaml:"attachments,omitempty"`   // Files under assets/, e.g. assets/review/diagram.png
+	Images        []string               `yaml:"images,omitempty"`        // Images sent with the prompt: library paths or URLs
+	References    []string               `yaml:"references,omitempty"`    // URLs and library docs the prompt relies on
+	OutputSchema  map[string]interface{} `yaml:"output_schema,omitempty"` // JSON Schema the model's response should follow
+	Variables     []Variable             `yaml:"variables,omitempty"`     // Declared {{name}} placeholders
+	ContextSlot   string                 `yaml:"context_slot,omitempty"`  // Variable text sent with an API render fills (default: context)
+	CreatedAt     time.Time              `yaml:"created_at"`
+	UpdatedAt     time.Time              `yaml:"updated_at"`
 
 	// Content fields
 	Content     string `yaml:"-"` // The markdown content after frontmatter
 	FilePath    string `yaml:"-"` // Path to the file
 	ContentHash string `yaml:"-"` // SHA256 hash of the content
+	Format      string `yaml:"-"` // Frontmatter format the file uses: yaml, toml or json
+
+	// DerivedTags lists tags contributed by the prompt's directory rather than its frontmatter
+	DerivedTags []string `yaml:"-"`
+
+	// Source names the registered library a federated result came from
+	Source string `yaml:"-" json:",omitempty"`
+}
+
+// DefaultContextSlot is the variable text sent with an API render fills when
+// the prompt names no other
+const DefaultContextSlot = "context"
+
+// ContextSlotName returns the variable text sent with a render fills
+func (p Prompt) ContextSlotName() string {
+	if p.ContextSlot != "" {
+		return p.ContextSlot
+	}
+	return DefaultContextSlot
+}
+
+// StoredTags returns the tags declared in the prompt file, excluding derived tags
+func (p Prompt) StoredTags() []string {
+	if len(p.DerivedTags) == 0 {
+		return p.Tags
+	}
+
+	derived := make(map[string]bool, len(p.DerivedTags))
+	for _, tag := range p.DerivedTags {
+		derived[tag] = true
+	}
+
+	var tags []string
+	for _, tag := range p.Tags {
+		if !derived[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
 
@@ -35,10 +94,14 @@ func (p Prompt) FilterValue() string {
 
 // Title satisfies the list.Item interface
 func (p Prompt) Title() string {
+	title := p.ID
 	if p.Name != "" {
-		return cleanString(p.Name)
+		title = p.Name
+	}
+	if p.Source != "" {
+		title = "[" + p.Source + "] " + title
 	}
-	return cleanString(p.ID)
+	return cleanString(title)
 }
 
 // Description satisfies the list.Item interface  
@@ -60,7 +123,7 @@ func (p Prompt) Description() string {
 	
 	// Add last edited info
 	if !p.UpdatedAt.IsZero() {
-		parts = append(parts, "Last edited: " + p.UpdatedAt.Format("2006-01-02 15:04"))
+		parts = append(parts, i18n.T("prompt.last_edited", i18n.FormatDateTime(p.UpdatedAt)))
 	}
 	
 	// Add tags if available
@@ -126,4 +189,28 @@ func joinTags(tags []string) string {
 		result += tag
 	}
 	return result
-}
\ No newline at end of file
+}
+
+// maxIDLength caps IDs made from titles, which can be whole sentences
+const maxIDLength = 50
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug lowercases s and joins its runs of letters and digits with hyphens,
+// the form IDs and tags made from names and paths take
+func Slug(s string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// IDFromTitle turns a title into a prompt ID of at most 50 characters,
+// returning fallback for a title with no letters or digits
+func IDFromTitle(title, fallback string) string {
+	id := Slug(title)
+	if len(id) > maxIDLength {
+		id = strings.TrimRight(id[:maxIDLength], "-")
+	}
+	if id == "" {
+		return fallback
+	}
+	return id
+}
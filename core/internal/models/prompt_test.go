@@ -0,0 +1,23 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIDFromTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Code Review: Go & Rust", "code-review-go-rust"},
+		{"  --Hello, World!--  ", "hello-world"},
+		{"日本語", "fallback"},
+		{strings.Repeat("word ", 20), "word-word-word-word-word-word-word-word-word-word"},
+	}
+	for _, tt := range tests {
+		if got := IDFromTitle(tt.title, "fallback"); got != tt.want {
+			t.Errorf("IDFromTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
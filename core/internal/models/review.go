@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Review states a prompt moves through in a shared library. A prompt starts
+// out proposed and is either approved or rejected; a rejected prompt can be
+// proposed again after it is revised.
+const (
+	ReviewProposed = "proposed"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Review records where a prompt stands in the review workflow
+type Review struct {
+	State      string    `yaml:"state"`
+	ProposedBy string    `yaml:"proposed_by,omitempty"`
+	ProposedAt time.Time `yaml:"proposed_at,omitempty"`
+	ReviewedBy string    `yaml:"reviewed_by,omitempty"`
+	ReviewedAt time.Time `yaml:"reviewed_at,omitempty"`
+	Note       string    `yaml:"note,omitempty"`
+}
+
+// ReviewState returns the prompt's review state. Prompts that have never been
+// through review are approved, so libraries that don't use the workflow are
+// unaffected.
+func (p Prompt) ReviewState() string {
+	if p.Review == nil || p.Review.State == "" {
+		return ReviewApproved
+	}
+	return p.Review.State
+}
+
+// IsApproved reports whether the prompt belongs in default listings
+func (p Prompt) IsApproved() bool {
+	return p.ReviewState() == ReviewApproved
+}
@@ -15,7 +15,8 @@
 // - internal/cli/cli.go: CLI boolean-search command parses expressions via parseBooleanExpression()
 // - internal/api/server.go: API boolean-search endpoint uses ParseBooleanExpression()
 // - internal/validation/validator.go: boolean_search schema validates expression syntax
-// - internal/service/service.go: SearchPromptsByBooleanExpression() evaluates expressions against prompts
+// - internal/service/tag_index.go: SearchPromptsByBooleanExpression() evaluates expressions over a tag
+//   index with the same results as Evaluate(), visiting only prompts with the named tags
 //
 // EXPRESSION SYNTAX:
 // - Tags: Simple tag names (e.g., "ai", "programming")
@@ -24,9 +25,12 @@
 // - NOT: "NOT tag" (tag must not be present)
 // - XOR: "tag1 XOR tag2" (exactly one tag must be present)
 // - Grouping: "(tag1 AND tag2) OR tag3" (parentheses for precedence)
+// - Precedence: NOT binds tightest, then AND, then XOR, then OR
+// - Operators are matched case-insensitively; adjacent words form one tag
 //
 // USAGE PATTERNS:
-// - Parse: Use ParseBooleanExpression(string) to convert text to BooleanExpression
+// - Parse: Use ParseBooleanExpression(string) to convert text to BooleanExpression;
+//   a syntax error is a *SyntaxError with the column it was found at
 // - Evaluate: Use expression.Evaluate([]string) to check against tag lists
 // - Display: Use expression.String() for human-readable representation
 //
@@ -66,6 +70,7 @@ type SavedSearch struct {
 	Description string             `json:"description,omitempty"`
 	Expression  *BooleanExpression `json:"expression"`
 	TextQuery   string             `json:"text_query,omitempty"` // Optional text search filter
+	Pack        string             `json:"pack,omitempty"`       // Pack that ships the search, which is then read-only
 	CreatedAt   string             `json:"created_at"`
 	UpdatedAt   string             `json:"updated_at"`
 }
@@ -82,7 +87,7 @@ func (be *BooleanExpression) Evaluate(tags []string) bool {
 		if !ok {
 			return false
 		}
-		return containsTag(tags, tagName)
+		return ContainsTag(tags, tagName)
 
 	case ExpressionAnd:
 		expressions, ok := be.Value.([]*BooleanExpression)
@@ -129,7 +134,9 @@ func (be *BooleanExpression) Evaluate(tags []string) bool {
 	}
 }
 
-// QueryString returns the expression as an editable query string (without brackets for tags)
+// QueryString returns the expression as an editable query string (without
+// brackets for tags), parenthesizing only where precedence requires, so that
+// ParseBooleanExpression reads it back as the same expression
 func (be *BooleanExpression) QueryString() string {
 	if be == nil {
 		return ""
@@ -146,7 +153,7 @@ func (be *BooleanExpression) QueryString() string {
 		if expressions, ok := be.Value.([]*BooleanExpression); ok {
 			var parts []string
 			for _, expr := range expressions {
-				parts = append(parts, expr.QueryString())
+				parts = append(parts, expr.operandString(be, false))
 			}
 			return strings.Join(parts, " AND ")
 		}
@@ -156,7 +163,7 @@ func (be *BooleanExpression) QueryString() string {
 		if expressions, ok := be.Value.([]*BooleanExpression); ok {
 			var parts []string
 			for _, expr := range expressions {
-				parts = append(parts, expr.QueryString())
+				parts = append(parts, expr.operandString(be, false))
 			}
 			return strings.Join(parts, " OR ")
 		}
@@ -164,13 +171,13 @@ func (be *BooleanExpression) QueryString() string {
 
 	case ExpressionXor:
 		if expressions, ok := be.Value.([]*BooleanExpression); ok && len(expressions) == 2 {
-			return fmt.Sprintf("%s XOR %s", expressions[0].QueryString(), expressions[1].QueryString())
+			return fmt.Sprintf("%s XOR %s", expressions[0].operandString(be, false), expressions[1].operandString(be, true))
 		}
 		return "XOR ?"
 
 	case ExpressionNot:
 		if expressions, ok := be.Value.([]*BooleanExpression); ok && len(expressions) == 1 {
-			return fmt.Sprintf("NOT %s", expressions[0].QueryString())
+			return fmt.Sprintf("NOT %s", expressions[0].operandString(be, false))
 		}
 		return "NOT ?"
 
@@ -179,6 +186,34 @@ func (be *BooleanExpression) QueryString() string {
 	}
 }
 
+// operandString is QueryString for an operand of parent, in parentheses when
+// it binds more loosely than parent. XOR groups from the left, so its right
+// operand also needs them when it is another XOR.
+func (be *BooleanExpression) operandString(parent *BooleanExpression, right bool) string {
+	query := be.QueryString()
+	inner, outer := be.Type.precedence(), parent.Type.precedence()
+	if inner < outer || (right && inner == outer) {
+		return "(" + query + ")"
+	}
+	return query
+}
+
+// precedence ranks how tightly each operator binds, loosest first
+func (t ExpressionType) precedence() int {
+	switch t {
+	case ExpressionOr:
+		return 1
+	case ExpressionXor:
+		return 2
+	case ExpressionAnd:
+		return 3
+	case ExpressionNot:
+		return 4
+	default:
+		return 5
+	}
+}
+
 // String returns a human-readable string representation of the expression
 func (be *BooleanExpression) String() string {
 	if be == nil {
@@ -229,8 +264,43 @@ func (be *BooleanExpression) String() string {
 	}
 }
 
-// containsTag checks if a tag is present in the tags slice (case-insensitive)
-func containsTag(tags []string, target string) bool {
+// Tags returns the tags the expression refers to, in the order they appear
+func (be *BooleanExpression) Tags() []string {
+	if be == nil {
+		return nil
+	}
+	if be.Type == ExpressionTag {
+		if tag, ok := be.Value.(string); ok {
+			return []string{tag}
+		}
+		return nil
+	}
+	var tags []string
+	if expressions, ok := be.Value.([]*BooleanExpression); ok {
+		for _, expr := range expressions {
+			tags = append(tags, expr.Tags()...)
+		}
+	}
+	return tags
+}
+
+// Complexity counts the tags and operators in the expression, a rough
+// measure of how much work evaluating it against one prompt takes
+func (be *BooleanExpression) Complexity() int {
+	if be == nil {
+		return 0
+	}
+	n := 1
+	if expressions, ok := be.Value.([]*BooleanExpression); ok {
+		for _, expr := range expressions {
+			n += expr.Complexity()
+		}
+	}
+	return n
+}
+
+// ContainsTag checks if a tag is present in the tags slice (case-insensitive)
+func ContainsTag(tags []string, target string) bool {
 	targetLower := strings.ToLower(target)
 	for _, tag := range tags {
 		if strings.ToLower(tag) == targetLower {
@@ -334,93 +404,3 @@ func (be *BooleanExpression) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// ParseBooleanExpression parses a boolean search expression string into a BooleanExpression
-// This is the consolidated parser used by all interfaces (CLI, TUI, HTTP)
-func ParseBooleanExpression(expr string) (*BooleanExpression, error) {
-	expr = strings.TrimSpace(expr)
-	
-	// Handle parentheses by finding the innermost ones first
-	for {
-		start := -1
-		for i, r := range expr {
-			if r == '(' {
-				start = i
-			} else if r == ')' && start >= 0 {
-				// Found innermost parentheses
-				inner := expr[start+1 : i]
-				_, err := parseBooleanExpressionSimple(inner)
-				if err != nil {
-					return nil, err
-				}
-				// Replace the parentheses with a placeholder
-				// For simplicity, we'll just parse the simple case for now
-				return parseBooleanExpressionSimple(expr)
-			}
-		}
-		break
-	}
-	
-	return parseBooleanExpressionSimple(expr)
-}
-
-// parseBooleanExpressionSimple handles the core parsing logic without parentheses
-func parseBooleanExpressionSimple(expr string) (*BooleanExpression, error) {
-	expr = strings.TrimSpace(expr)
-	
-	// Handle NOT expressions
-	if strings.HasPrefix(strings.ToUpper(expr), "NOT ") {
-		inner := strings.TrimSpace(expr[4:])
-		innerExpr, err := parseBooleanExpressionSimple(inner)
-		if err != nil {
-			return nil, err
-		}
-		return NewNotExpression(innerExpr), nil
-	}
-	
-	// Handle OR expressions (lower precedence)
-	if orParts := strings.Split(expr, " OR "); len(orParts) > 1 {
-		var expressions []*BooleanExpression
-		for _, part := range orParts {
-			subExpr, err := parseBooleanExpressionSimple(strings.TrimSpace(part))
-			if err != nil {
-				return nil, err
-			}
-			expressions = append(expressions, subExpr)
-		}
-		return NewOrExpression(expressions...), nil
-	}
-	
-	// Handle AND expressions (higher precedence)
-	if andParts := strings.Split(expr, " AND "); len(andParts) > 1 {
-		var expressions []*BooleanExpression
-		for _, part := range andParts {
-			subExpr, err := parseBooleanExpressionSimple(strings.TrimSpace(part))
-			if err != nil {
-				return nil, err
-			}
-			expressions = append(expressions, subExpr)
-		}
-		return NewAndExpression(expressions...), nil
-	}
-	
-	// Handle XOR expressions
-	if xorParts := strings.Split(expr, " XOR "); len(xorParts) == 2 {
-		left, err := parseBooleanExpressionSimple(strings.TrimSpace(xorParts[0]))
-		if err != nil {
-			return nil, err
-		}
-		right, err := parseBooleanExpressionSimple(strings.TrimSpace(xorParts[1]))
-		if err != nil {
-			return nil, err
-		}
-		return NewXorExpression(left, right), nil
-	}
-	
-	// Remove parentheses if present
-	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
-		return parseBooleanExpressionSimple(expr[1 : len(expr)-1])
-	}
-	
-	// Single tag expression
-	return NewTagExpression(expr), nil
-}
\ No newline at end of file
@@ -0,0 +1,257 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError is a problem found while parsing a boolean expression
+type SyntaxError struct {
+	Position int    `json:"position"` // 1-based character column
+	Message  string `json:"message"`
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Position, e.Message)
+}
+
+// ParseBooleanExpression parses a boolean search expression string into a BooleanExpression
+// This is the consolidated parser used by all interfaces (CLI, TUI, HTTP)
+func ParseBooleanExpression(expr string) (*BooleanExpression, error) {
+	p := &expressionParser{tokens: tokenizeExpression(expr), end: len([]rune(expr)) + 1}
+	if len(p.tokens) == 0 {
+		return nil, &SyntaxError{Position: 1, Message: "expression is empty"}
+	}
+
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.peek(); ok {
+		if t.kind == tokenClose {
+			return nil, &SyntaxError{Position: t.pos, Message: "unmatched ')'"}
+		}
+		return nil, &SyntaxError{Position: t.pos, Message: fmt.Sprintf("missing AND, OR or XOR before %s", t)}
+	}
+	return result, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenAnd
+	tokenOr
+	tokenXor
+	tokenNot
+)
+
+// expressionToken is one word, operator or parenthesis and the column it starts at
+type expressionToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t expressionToken) String() string {
+	switch t.kind {
+	case tokenOpen, tokenClose:
+		return "'" + t.text + "'"
+	case tokenWord:
+		return fmt.Sprintf("%q", t.text)
+	default:
+		return strings.ToUpper(t.text)
+	}
+}
+
+func (t expressionToken) isOperator() bool {
+	return t.kind >= tokenAnd
+}
+
+var operatorKinds = map[string]tokenKind{
+	"AND": tokenAnd,
+	"OR":  tokenOr,
+	"XOR": tokenXor,
+	"NOT": tokenNot,
+}
+
+// tokenizeExpression splits expr into words, operators and parentheses
+func tokenizeExpression(expr string) []expressionToken {
+	var tokens []expressionToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, expressionToken{kind: tokenOpen, text: "(", pos: i + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, expressionToken{kind: tokenClose, text: ")", pos: i + 1})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				i++
+			}
+			word := string(runes[start:i])
+			kind, ok := operatorKinds[strings.ToUpper(word)]
+			if !ok {
+				kind = tokenWord
+			}
+			tokens = append(tokens, expressionToken{kind: kind, text: word, pos: start + 1})
+		}
+	}
+	return tokens
+}
+
+// expressionParser is a recursive descent parser over the tokens of one expression
+type expressionParser struct {
+	tokens []expressionToken
+	next   int
+	end    int // Column just past the end of the input
+}
+
+func (p *expressionParser) peek() (expressionToken, bool) {
+	if p.next >= len(p.tokens) {
+		return expressionToken{}, false
+	}
+	return p.tokens[p.next], true
+}
+
+// accept consumes the next token if it is of the given kind
+func (p *expressionParser) accept(kind tokenKind) bool {
+	if t, ok := p.peek(); ok && t.kind == kind {
+		p.next++
+		return true
+	}
+	return false
+}
+
+// previous returns the last consumed token
+func (p *expressionParser) previous() (expressionToken, bool) {
+	if p.next == 0 {
+		return expressionToken{}, false
+	}
+	return p.tokens[p.next-1], true
+}
+
+func (p *expressionParser) parseOr() (*BooleanExpression, error) {
+	return p.parseList(tokenOr, p.parseXor, NewOrExpression)
+}
+
+func (p *expressionParser) parseXor() (*BooleanExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenXor) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = NewXorExpression(left, right)
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (*BooleanExpression, error) {
+	return p.parseList(tokenAnd, p.parseUnary, NewAndExpression)
+}
+
+// parseList parses operands joined by one associative operator into a single
+// flat expression, so "a AND (b AND c)" becomes AND(a, b, c)
+func (p *expressionParser) parseList(op tokenKind, operand func() (*BooleanExpression, error), build func(...*BooleanExpression) *BooleanExpression) (*BooleanExpression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*BooleanExpression{first}
+	for p.accept(op) {
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+
+	joined := build()
+	var flat []*BooleanExpression
+	for _, e := range operands {
+		if children, ok := e.Value.([]*BooleanExpression); ok && e.Type == joined.Type {
+			flat = append(flat, children...)
+		} else {
+			flat = append(flat, e)
+		}
+	}
+	joined.Value = flat
+	return joined, nil
+}
+
+func (p *expressionParser) parseUnary() (*BooleanExpression, error) {
+	if p.accept(tokenNot) {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return NewNotExpression(inner), nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a tag or a parenthesized group, explaining what is
+// wrong when neither is next
+func (p *expressionParser) parsePrimary() (*BooleanExpression, error) {
+	t, ok := p.peek()
+	prev, hasPrev := p.previous()
+
+	switch {
+	case !ok:
+		if hasPrev && prev.isOperator() {
+			return nil, &SyntaxError{Position: prev.pos, Message: fmt.Sprintf("%s needs a tag or group after it", prev)}
+		}
+		return nil, &SyntaxError{Position: p.end, Message: "expected a tag or group"}
+
+	case t.kind == tokenWord:
+		words := []string{t.text}
+		for p.next++; p.next < len(p.tokens) && p.tokens[p.next].kind == tokenWord; p.next++ {
+			words = append(words, p.tokens[p.next].text)
+		}
+		return NewTagExpression(strings.Join(words, " ")), nil
+
+	case t.kind == tokenOpen:
+		p.next++
+		if p.accept(tokenClose) {
+			return nil, &SyntaxError{Position: t.pos, Message: "empty parentheses"}
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(tokenClose) {
+			if next, ok := p.peek(); ok {
+				return nil, &SyntaxError{Position: next.pos, Message: fmt.Sprintf("missing AND, OR or XOR before %s", next)}
+			}
+			return nil, &SyntaxError{Position: t.pos, Message: "'(' is never closed"}
+		}
+		return inner, nil
+
+	case t.kind == tokenClose:
+		if hasPrev && prev.isOperator() {
+			return nil, &SyntaxError{Position: prev.pos, Message: fmt.Sprintf("%s needs a tag or group after it", prev)}
+		}
+		return nil, &SyntaxError{Position: t.pos, Message: "unmatched ')'"}
+
+	default: // A binary operator where an operand belongs
+		if hasPrev && prev.isOperator() {
+			return nil, &SyntaxError{Position: t.pos, Message: fmt.Sprintf("%s cannot follow %s", t, prev)}
+		}
+		return nil, &SyntaxError{Position: t.pos, Message: fmt.Sprintf("%s needs a tag or group before it", t)}
+	}
+}
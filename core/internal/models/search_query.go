@@ -0,0 +1,166 @@
+package models
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/fuzzy"
+)
+
+// SearchQuery is a simple search split into free text, which is fuzzy
+// matched, and field filters such as tag:ai, -tag:draft and title:review*
+type SearchQuery struct {
+	Text    string
+	Filters []SearchFilter
+}
+
+// SearchFilter keeps or excludes prompts by one field
+type SearchFilter struct {
+	Field   string // "tag" or "title"
+	Pattern string // May contain * wildcards
+	Negate  bool   // Exclude the prompts that match
+
+	match *regexp.Regexp
+}
+
+// searchFields are the fields a filter can name
+var searchFields = []string{"tag", "title"}
+
+// ParseSearchQuery splits a query into free text and filters. A filter is
+// field:value or -field:value; quote the value to include spaces, as in
+// title:"code review". Tags match whole, title matches anywhere in the
+// title, and * matches any run of characters. Anything else, including
+// unknown fields, is free text.
+func ParseSearchQuery(query string) SearchQuery {
+	var q SearchQuery
+	var text []string
+	for _, term := range splitSearchTerms(query) {
+		if filter, ok := parseSearchFilter(term); ok {
+			q.Filters = append(q.Filters, filter)
+		} else {
+			text = append(text, strings.ReplaceAll(term, `"`, ""))
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q
+}
+
+// Complexity counts the query's filters and words of free text
+func (q SearchQuery) Complexity() int {
+	return len(q.Filters) + len(strings.Fields(q.Text))
+}
+
+// Matches reports whether p passes every filter in the query
+func (q SearchQuery) Matches(p *Prompt) bool {
+	for _, f := range q.Filters {
+		if !f.Matches(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether p passes the filter: has a matching field, or for
+// a negated filter, has none. Matching ignores case and accents.
+func (f SearchFilter) Matches(p *Prompt) bool {
+	found := false
+	switch f.Field {
+	case "tag":
+		for _, tag := range p.Tags {
+			if f.match.MatchString(foldSearchText(tag)) {
+				found = true
+				break
+			}
+		}
+	case "title":
+		title := p.Name
+		if title == "" {
+			title = p.ID
+		}
+		found = f.match.MatchString(foldSearchText(title))
+	}
+	return found != f.Negate
+}
+
+// parseSearchFilter parses one term as a filter, if it is one
+func parseSearchFilter(term string) (SearchFilter, bool) {
+	negate := strings.HasPrefix(term, "-")
+	field, value, ok := strings.Cut(strings.TrimPrefix(term, "-"), ":")
+	field = strings.ToLower(field)
+	value = strings.ReplaceAll(value, `"`, "")
+	if !ok || value == "" || !slices.Contains(searchFields, field) {
+		return SearchFilter{}, false
+	}
+
+	// Wildcards become .*, everything else is literal
+	parts := strings.Split(foldSearchText(value), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	pattern := strings.Join(parts, ".*")
+	if field == "tag" || strings.Contains(value, "*") {
+		pattern = "^" + pattern + "$"
+	}
+	return SearchFilter{
+		Field:   field,
+		Pattern: value,
+		Negate:  negate,
+		match:   regexp.MustCompile(pattern),
+	}, true
+}
+
+// splitSearchTerms splits a query on whitespace outside double quotes,
+// keeping the quotes in the terms
+func splitSearchTerms(query string) []string {
+	var terms []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if current.Len() > 0 {
+				terms = append(terms, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		terms = append(terms, current.String())
+	}
+	return terms
+}
+
+// SearchText joins the fields of p that free text in a search is fuzzy
+// matched against, title first so a list can highlight matches in it. The
+// title is FilterValue; content is matched only when loaded.
+func (p *Prompt) SearchText(fields []string) string {
+	var parts []string
+	for _, field := range []string{"title", "summary", "id", "tags", "content"} {
+		if !slices.Contains(fields, field) {
+			continue
+		}
+		switch field {
+		case "title":
+			parts = append(parts, p.FilterValue())
+		case "summary":
+			parts = append(parts, p.Summary)
+		case "id":
+			parts = append(parts, p.ID)
+		case "tags":
+			parts = append(parts, strings.Join(p.Tags, " "))
+		case "content":
+			parts = append(parts, p.Content)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func foldSearchText(s string) string {
+	return strings.ToLower(fuzzy.Fold(s))
+}
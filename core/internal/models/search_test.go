@@ -0,0 +1,116 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBooleanExpression(t *testing.T) {
+	tests := map[string]string{
+		"ai":                            "[ai]",
+		"(ai OR ml) AND writing":        "(([ai] OR [ml]) AND [writing])",
+		"ai AND (writing AND draft)":    "([ai] AND [writing] AND [draft])",
+		"NOT draft AND ai":              "(NOT [draft] AND [ai])",
+		"a OR b XOR c AND d":            "([a] OR ([b] XOR ([c] AND [d])))",
+		"a XOR b XOR c":                 "(([a] XOR [b]) XOR [c])",
+		"machine learning or not draft": "([machine learning] OR NOT [draft])",
+		"((ai))":                        "[ai]",
+	}
+	for input, want := range tests {
+		expr, err := ParseBooleanExpression(input)
+		if err != nil {
+			t.Errorf("ParseBooleanExpression(%q): %v", input, err)
+			continue
+		}
+		if got := expr.String(); got != want {
+			t.Errorf("ParseBooleanExpression(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestParseBooleanExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+		message  string
+	}{
+		{"", 1, "expression is empty"},
+		{"ai AND", 4, "AND needs a tag or group after it"},
+		{"OR ai", 1, "OR needs a tag or group before it"},
+		{"ai AND OR ml", 8, "OR cannot follow AND"},
+		{"(ai OR ml", 1, "'(' is never closed"},
+		{"ai OR ml)", 9, "unmatched ')'"},
+		{"ai AND ()", 8, "empty parentheses"},
+		{"ai (ml)", 4, "missing AND, OR or XOR before '('"},
+		{"ai NOT ml", 4, "missing AND, OR or XOR before NOT"},
+		{"résumé AND", 8, "AND needs a tag or group after it"},
+	}
+	for _, tt := range tests {
+		_, err := ParseBooleanExpression(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseBooleanExpression(%q) error = %v, want a SyntaxError", tt.input, err)
+			continue
+		}
+		if syntaxErr.Position != tt.position || syntaxErr.Message != tt.message {
+			t.Errorf("ParseBooleanExpression(%q) = %v, want column %d: %s", tt.input, syntaxErr, tt.position, tt.message)
+		}
+	}
+}
+
+func TestQueryStringRoundTrips(t *testing.T) {
+	for _, input := range []string{
+		"(ai OR ml) AND writing",
+		"NOT (draft OR archived)",
+		"a XOR (b XOR c)",
+		"(a OR b) XOR c",
+		"NOT NOT ai",
+	} {
+		expr, err := ParseBooleanExpression(input)
+		if err != nil {
+			t.Fatalf("ParseBooleanExpression(%q): %v", input, err)
+		}
+		if got := expr.QueryString(); got != input {
+			t.Errorf("QueryString() = %q, want %q", got, input)
+		}
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	query := ParseSearchQuery(`review tag:code -tag:Draft title:"pull req*" -title:old notes:x`)
+	if query.Text != "review notes:x" {
+		t.Errorf("Text = %q, want the free text and unknown fields", query.Text)
+	}
+	if len(query.Filters) != 4 {
+		t.Fatalf("Filters = %+v, want 4", query.Filters)
+	}
+
+	tests := []struct {
+		prompt *Prompt
+		want   bool
+	}{
+		{&Prompt{Name: "Pull request review", Tags: []string{"code"}}, true},
+		{&Prompt{Name: "Pull request review", Tags: []string{"code", "draft"}}, false},
+		{&Prompt{Name: "Pull request review", Tags: []string{"coding"}}, false},
+		{&Prompt{Name: "Review a pull request", Tags: []string{"code"}}, false},
+		{&Prompt{Name: "Pull request review (old)", Tags: []string{"code"}}, false},
+	}
+	for _, tt := range tests {
+		if got := query.Matches(tt.prompt); got != tt.want {
+			t.Errorf("Matches(%q %v) = %v, want %v", tt.prompt.Name, tt.prompt.Tags, got, tt.want)
+		}
+	}
+}
+
+func TestSearchFilterWildcardsAndAccents(t *testing.T) {
+	query := ParseSearchQuery("tag:ml-* title:resume")
+	if query.Text != "" {
+		t.Errorf("Text = %q, want none", query.Text)
+	}
+	if !query.Matches(&Prompt{Name: "Résumé polish", Tags: []string{"ML-Ops"}}) {
+		t.Error("Expected tag:ml-* to match ML-Ops and title:resume to match Résumé")
+	}
+	if query.Matches(&Prompt{Name: "Résumé polish", Tags: []string{"ml"}}) {
+		t.Error("Expected tag:ml-* not to match ml")
+	}
+}
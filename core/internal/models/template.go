@@ -18,6 +18,7 @@ type Template struct {
 	// Content fields
 	Content  string `yaml:"-"` // The template markdown content
 	FilePath string `yaml:"-"` // Path to the file
+	Format   string `yaml:"-"` // Frontmatter format the file uses: yaml, toml or json
 }
 
 // Slot represents a named placeholder in a template
@@ -26,6 +27,23 @@ type Slot struct {
 	Description string `yaml:"description,omitempty"`
 	Required    bool   `yaml:"required"`
 	Default     string `yaml:"default,omitempty"`
+	Example     string `yaml:"example,omitempty"`   // Sample value the template gallery renders with
+	Sensitive   bool   `yaml:"sensitive,omitempty"` // Masked on screen; see Variable.Sensitive
+	Env         string `yaml:"env,omitempty"`
+	Keychain    string `yaml:"keychain,omitempty"`
+}
+
+// Variable returns the slot as a variable declaration
+func (s Slot) Variable() Variable {
+	return Variable{
+		Name:        s.Name,
+		Description: s.Description,
+		Required:    s.Required,
+		Default:     s.Default,
+		Sensitive:   s.Sensitive,
+		Env:         s.Env,
+		Keychain:    s.Keychain,
+	}
 }
 
 // TemplateRules defines validation constraints for templates
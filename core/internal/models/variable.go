@@ -0,0 +1,27 @@
+package models
+
+// Variable declares a {{name}} placeholder in a prompt's content. Values come
+// from the render call, a variable profile, Env or Keychain, then Default.
+type Variable struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Type        string `yaml:"type,omitempty"` // string, number, boolean or list
+	Required    bool   `yaml:"required,omitempty"`
+	Default     string `yaml:"default,omitempty"`
+
+	// Sensitive values such as API keys or customer data are masked on screen,
+	// asked for without echo, and never written to library files
+	Sensitive bool `yaml:"sensitive,omitempty"`
+
+	// Env names an environment variable holding the value
+	Env string `yaml:"env,omitempty"`
+
+	// Keychain names an OS keychain entry holding the value, as "service" or
+	// "service/account"
+	Keychain string `yaml:"keychain,omitempty"`
+}
+
+// HasSecretSource reports whether the value is read from the environment or keychain
+func (v Variable) HasSecretSource() bool {
+	return v.Env != "" || v.Keychain != ""
+}
@@ -0,0 +1,259 @@
+// Package pdf writes simple PDF documents: pages of text, lines and filled
+// rectangles. Text is set in the PDF standard fonts, Helvetica and Courier,
+// which every reader has, so no font is embedded and files stay small. The
+// standard fonts only cover WinAnsiEncoding, the Windows-1252 character set;
+// other characters are written as "?".
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A4 page size in points, 72 to the inch
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Font is one of the standard fonts
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+	Italic
+	BoldItalic
+	Mono
+	MonoBold
+)
+
+// baseFonts are the PostScript names of the fonts, in Font order
+var baseFonts = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Helvetica-BoldOblique", "Courier", "Courier-Bold"}
+
+// Color is an RGB color with components from 0 to 1
+type Color struct {
+	R, G, B float64
+}
+
+// Black is the color text is set in unless another is given
+var Black = Color{}
+
+// Gray returns a gray, from 0 for black to 1 for white
+func Gray(level float64) Color {
+	return Color{level, level, level}
+}
+
+// Info is the document's metadata, shown in a reader's document properties
+type Info struct {
+	Title   string
+	Subject string
+	Creator string
+	Created time.Time
+}
+
+// Document is a PDF being written. Drawing goes to the current page, the
+// last one added unless SetPage picks another.
+type Document struct {
+	width, height float64
+	pages         []*bytes.Buffer
+	page          int
+	info          Info
+}
+
+// New returns an empty document with pages of the given size in points
+func New(width, height float64, info Info) *Document {
+	return &Document{width: width, height: height, info: info, page: -1}
+}
+
+// Size returns the page size in points
+func (d *Document) Size() (width, height float64) {
+	return d.width, d.height
+}
+
+// AddPage starts a new page and makes it the current one
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.page = len(d.pages) - 1
+}
+
+// PageCount returns how many pages there are
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage makes page, counted from 0, the current one
+func (d *Document) SetPage(page int) {
+	d.page = page
+}
+
+// current returns the current page's content, starting the first page when
+// there is none yet
+func (d *Document) current() *bytes.Buffer {
+	if d.page < 0 {
+		d.AddPage()
+	}
+	return d.pages[d.page]
+}
+
+// Text sets text on one line with its baseline at y, measured from the
+// bottom of the page
+func (d *Document) Text(x, y float64, font Font, size float64, color Color, text string) {
+	fmt.Fprintf(d.current(), "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		color.components(), font+1, num(size), num(x), num(y), escape(encode(text)))
+}
+
+// Line draws a line of the given width
+func (d *Document) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(d.current(), "%s RG %s w %s %s m %s %s l S\n",
+		color.components(), num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Rect fills a rectangle whose lower left corner is at x, y
+func (d *Document) Rect(x, y, width, height float64, color Color) {
+	fmt.Fprintf(d.current(), "%s rg %s %s %s %s re f\n",
+		color.components(), num(x), num(y), num(width), num(height))
+}
+
+// TextWidth returns the width of text set in font at size
+func TextWidth(text string, font Font, size float64) float64 {
+	var units int
+	for _, b := range encode(text) {
+		units += charWidth(font, b)
+	}
+	return float64(units) * size / 1000
+}
+
+// charWidth returns the width of the encoded character c in font
+func charWidth(font Font, c byte) int {
+	if c < 32 {
+		return 0
+	}
+	switch font {
+	case Mono, MonoBold:
+		return 600
+	case Bold, BoldItalic:
+		return int(helveticaBoldWidths[c-32])
+	default:
+		return int(helveticaWidths[c-32])
+	}
+}
+
+// WriteTo writes the document as a PDF file
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	out := &bytes.Buffer{}
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 and 2 are the catalog and the page tree, then come the
+	// fonts, the info dictionary and a page and its content for each page
+	fontObject := 3
+	infoObject := fontObject + len(baseFonts)
+	firstPage := infoObject + 1
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	var fonts []string
+	for i := range baseFonts {
+		fonts = append(fonts, fmt.Sprintf("/F%d %d 0 R", i+1, fontObject+i))
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, name := range baseFonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	object(d.infoDictionary())
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			num(d.width), num(d.height), strings.Join(fonts, " "), firstPage+2*i+1))
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(content.Bytes())
+		zw.Close()
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, infoObject, xref)
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// infoDictionary returns the document information dictionary
+func (d *Document) infoDictionary() string {
+	fields := []string{"/Producer (Pocket Prompt)"}
+	for _, field := range []struct{ key, value string }{
+		{"Title", d.info.Title}, {"Subject", d.info.Subject}, {"Creator", d.info.Creator},
+	} {
+		if field.value != "" {
+			fields = append(fields, fmt.Sprintf("/%s (%s)", field.key, escape(encode(field.value))))
+		}
+	}
+	if !d.info.Created.IsZero() {
+		created := d.info.Created.UTC().Format("20060102150405")
+		fields = append(fields, fmt.Sprintf("/CreationDate (D:%sZ)", created))
+	}
+	return "<< " + strings.Join(fields, " ") + " >>"
+}
+
+// components writes a color as PDF color operands
+func (c Color) components() string {
+	return num(c.R) + " " + num(c.G) + " " + num(c.B)
+}
+
+// encode converts text to WinAnsiEncoding, writing "?" for characters it
+// does not have and dropping control characters
+func encode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 32 {
+			if r == '\t' {
+				encoded = append(encoded, "    "...)
+			}
+			continue
+		}
+		if b, ok := charmap.Windows1252.EncodeRune(r); ok {
+			encoded = append(encoded, b)
+		} else {
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// escape writes encoded text as the inside of a PDF string literal
+func escape(encoded []byte) string {
+	var b strings.Builder
+	for _, c := range encoded {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// num formats a number with at most two decimals and no trailing zeros
+func num(f float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", f), "0")
+	return strings.TrimSuffix(s, ".")
+}
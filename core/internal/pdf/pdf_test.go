@@ -0,0 +1,91 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument(t *testing.T) {
+	doc := New(A4Width, A4Height, Info{Title: "Review (draft)", Created: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)})
+	doc.Text(56, 700, Bold, 12, Black, "Café (menu) \\ 日本")
+	doc.AddPage()
+	doc.Rect(56, 100, 200, 20, Gray(0.9))
+	doc.SetPage(0)
+	doc.Line(56, 690, 300, 690, 1, Gray(0.5))
+
+	var out bytes.Buffer
+	if _, err := doc.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	data := out.Bytes()
+	for _, want := range []string{"%PDF-1.4", "/Count 2", "/Title (Review \\(draft\\))", "/CreationDate (D:20260102030405Z)", "%%EOF"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("PDF lacks %s", want)
+		}
+	}
+
+	// Every object is where the cross-reference table says
+	start, _ := strconv.Atoi(string(regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(data)[1]))
+	xref := strings.Split(string(data[start:]), "\n")
+	if xref[0] != "xref" {
+		t.Fatalf("startxref points at %q", xref[0])
+	}
+	count, _ := strconv.Atoi(strings.Fields(xref[1])[1])
+	for i := 1; i < count; i++ {
+		offset, _ := strconv.Atoi(xref[2+i][:10])
+		if !bytes.HasPrefix(data[offset:], []byte(strconv.Itoa(i)+" 0 obj")) {
+			t.Errorf("object %d is not at offset %d", i, offset)
+		}
+	}
+
+	pages := streams(t, data)
+	if len(pages) != 2 {
+		t.Fatalf("got %d content streams, want 2", len(pages))
+	}
+	if want := "/F2 12 Tf 56 700 Td (Caf\xe9 \\(menu\\) \\\\ ??) Tj"; !strings.Contains(pages[0], want) {
+		t.Errorf("first page lacks %q:\n%s", want, pages[0])
+	}
+	if !strings.Contains(pages[0], "56 690 m 300 690 l S") || !strings.Contains(pages[1], "56 100 200 20 re f") {
+		t.Errorf("drawing went to the wrong pages:\n%s\n---\n%s", pages[0], pages[1])
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		text string
+		font Font
+		want float64
+	}{
+		{"Hello", Regular, 22.78},
+		{"Hello", Bold, 24.45},
+		{"Hello", Mono, 30},
+		{"é", Italic, 5.56},
+	}
+	for _, tt := range tests {
+		if got := TextWidth(tt.text, tt.font, 10); got < tt.want-0.01 || got > tt.want+0.01 {
+			t.Errorf("TextWidth(%q, %d) = %.2f, want %.2f", tt.text, tt.font, got, tt.want)
+		}
+	}
+}
+
+// streams returns the inflated content streams of a PDF in order
+func streams(t *testing.T, data []byte) []string {
+	t.Helper()
+	var contents []string
+	for _, match := range regexp.MustCompile(`/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		r, err := zlib.NewReader(bytes.NewReader(data[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatalf("content stream: %v", err)
+		}
+		content, _ := io.ReadAll(r)
+		contents = append(contents, string(content))
+	}
+	return contents
+}
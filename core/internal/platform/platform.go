@@ -0,0 +1,52 @@
+// Package platform hides the differences between the systems pkt runs on,
+// such as Windows and minimal containers without the usual Unix tools:
+// finding programs, listing and stopping processes, the signals that ask a
+// long-running command to stop and the errors of a port already in use.
+package platform
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// ErrUnsupported is returned for what this system cannot do
+var ErrUnsupported = errors.New("not supported on " + runtime.GOOS)
+
+// ShutdownSignals are the signals that ask a server or watcher to stop:
+// Ctrl+C everywhere, and SIGTERM where there is one
+var ShutdownSignals = shutdownSignals
+
+// HasCommand reports whether the program name is on PATH. Unlike running
+// which, it works on Windows and in containers without which installed.
+func HasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// Terminate asks the process pid to stop, killing it outright when it
+// cannot be asked or the request fails
+func Terminate(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := terminate(process); err != nil {
+		return process.Kill()
+	}
+	return nil
+}
+
+// FindProcesses returns the IDs of the processes whose command line matches
+// the regular expression pattern, or ErrUnsupported where they cannot be
+// listed
+func FindProcesses(pattern string) ([]int, error) {
+	return findProcesses(pattern)
+}
+
+// IsAddrInUse reports whether err is from listening on an address that
+// another process already listens on
+func IsAddrInUse(err error) bool {
+	return isAddrInUse(err)
+}
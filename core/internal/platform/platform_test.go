@@ -0,0 +1,73 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestHasCommand(t *testing.T) {
+	if HasCommand("pkt-no-such-program") {
+		t.Error("HasCommand found a program that does not exist")
+	}
+	if runtime.GOOS != "windows" && !HasCommand("sh") {
+		t.Error("HasCommand did not find sh")
+	}
+}
+
+func TestTerminate(t *testing.T) {
+	if !HasCommand("sleep") {
+		t.Skip("sleep is not installed")
+	}
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start sleep: %v", err)
+	}
+	if err := Terminate(cmd.Process.Pid); err != nil {
+		t.Fatalf("Terminate: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("process still running after Terminate")
+	}
+}
+
+func TestFindProcessesNoMatch(t *testing.T) {
+	// Built at runtime, so no command line running this test contains it
+	pids, err := FindProcesses(fmt.Sprintf("pkt-test-%d", time.Now().UnixNano()))
+	if errors.Is(err, ErrUnsupported) {
+		return
+	}
+	if err != nil {
+		t.Fatalf("FindProcesses: %v", err)
+	}
+	if len(pids) != 0 {
+		t.Errorf("FindProcesses matched %v, want none", pids)
+	}
+}
+
+func TestIsAddrInUse(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer lis.Close()
+	_, err = net.Listen("tcp", lis.Addr().String())
+	if err == nil {
+		t.Fatal("listened twice on the same address")
+	}
+	if !IsAddrInUse(err) {
+		t.Errorf("IsAddrInUse(%v) = false", err)
+	}
+	if IsAddrInUse(ErrUnsupported) {
+		t.Error("IsAddrInUse matched an unrelated error")
+	}
+}
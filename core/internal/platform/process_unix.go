@@ -0,0 +1,46 @@
+//go:build !windows
+
+package platform
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// terminate sends SIGTERM, so the process can shut down cleanly
+func terminate(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
+
+// findProcesses lists matching processes with pgrep
+func findProcesses(pattern string) ([]int, error) {
+	if !HasCommand("pgrep") {
+		return nil, ErrUnsupported
+	}
+	output, err := exec.Command("pgrep", "-f", pattern).Output()
+	if err != nil {
+		// pgrep exits with 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(output)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// isAddrInUse reports a listen on an address something else listens on
+func isAddrInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE)
+}
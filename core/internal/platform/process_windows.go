@@ -0,0 +1,31 @@
+//go:build windows
+
+package platform
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Windows delivers Ctrl+C, but has no SIGTERM to ask a process to stop
+var shutdownSignals = []os.Signal{os.Interrupt}
+
+// terminate cannot ask a process to stop on Windows, so Terminate kills it
+func terminate(process *os.Process) error {
+	return ErrUnsupported
+}
+
+// findProcesses cannot match command lines without WMI, which pkt does not use
+func findProcesses(pattern string) ([]int, error) {
+	return nil, ErrUnsupported
+}
+
+// wsaeaddrinuse is the Winsock error for an address in use, which is not
+// syscall.EADDRINUSE on Windows
+const wsaeaddrinuse = syscall.Errno(10048)
+
+// isAddrInUse reports a listen on an address something else listens on
+func isAddrInUse(err error) bool {
+	return errors.Is(err, wsaeaddrinuse)
+}
@@ -0,0 +1,255 @@
+// Package plugin runs importers, exporters and copy formatters that live
+// outside pocket-prompt. A plugin is any executable named pkt-plugin-<name>
+// in the plugin directory or on PATH; pocket-prompt asks it what it provides
+// and exchanges prompts with it as JSON over stdin and stdout, so plugins
+// can be written in any language.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Prefix starts the file name of every plugin executable
+const Prefix = "pkt-plugin-"
+
+// Protocol is the version of the plugin protocol this build speaks. It is
+// passed to plugins in POCKET_PROMPT_PLUGIN_PROTOCOL.
+const Protocol = 1
+
+// What a plugin can provide
+const (
+	KindImporter  = "importer"  // pkt import <name> [args]
+	KindExporter  = "exporter"  // pkt export ... --format <name>
+	KindFormatter = "formatter" // pkt copy/render <id> --format <name>
+)
+
+const (
+	describeTimeout = 5 * time.Second
+	runTimeout      = 5 * time.Minute
+)
+
+// Capability is one importer, exporter or formatter a plugin provides
+type Capability struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Manifest is what a plugin prints, as JSON, when run with "describe"
+type Manifest struct {
+	Name       string       `json:"name"`
+	Protocol   int          `json:"protocol"`
+	Importers  []Capability `json:"importers,omitempty"`
+	Exporters  []Capability `json:"exporters,omitempty"`
+	Formatters []Capability `json:"formatters,omitempty"`
+}
+
+// Plugin is an installed plugin and what it provides
+type Plugin struct {
+	Manifest
+	Path string // The executable
+}
+
+// Bundle is the prompts and templates exchanged with importers and
+// exporters, in the shape 'pkt export all --format json' writes
+type Bundle struct {
+	Prompts   []*models.Prompt   `json:"prompts"`
+	Templates []*models.Template `json:"templates"`
+}
+
+// FormatRequest is what a formatter reads: the prompt and its text with
+// variables filled in
+type FormatRequest struct {
+	Prompt *models.Prompt `json:"prompt"`
+	Text   string         `json:"text"`
+}
+
+// Dir returns the directory pocket-prompt looks in for plugins before PATH.
+// It is in the user's configuration directory, never in a library, so a
+// synced library cannot bring programs along with it.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "pocket-prompt", "plugins"), nil
+}
+
+// Discover finds the installed plugins, sorted by name. A plugin in the
+// plugin directory hides one of the same name on PATH. Plugins that cannot
+// describe themselves are left out and reported in errs.
+func Discover() (plugins []*Plugin, errs []error) {
+	var dirs []string
+	if dir, err := Dir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+
+			p, err := describe(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("plugin %s: %w", name, err))
+				continue
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, errs
+}
+
+// Find returns the plugin providing the importer, exporter or formatter
+// called name
+func Find(kind, name string) (*Plugin, error) {
+	plugins, _ := Discover()
+	var available []string
+	for _, p := range plugins {
+		for _, c := range p.Capabilities(kind) {
+			if c.Name == name {
+				return p, nil
+			}
+			available = append(available, c.Name)
+		}
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no plugin provides the %s %q (see 'pkt plugins')", kind, name)
+	}
+	return nil, fmt.Errorf("no plugin provides the %s %q (installed: %s)", kind, name, strings.Join(available, ", "))
+}
+
+// Capabilities returns what the plugin provides of kind
+func (p *Plugin) Capabilities(kind string) []Capability {
+	switch kind {
+	case KindImporter:
+		return p.Importers
+	case KindExporter:
+		return p.Exporters
+	case KindFormatter:
+		return p.Formatters
+	}
+	return nil
+}
+
+// Import runs the importer called name with the arguments given after it
+// on the command line, returning the prompts and templates it prints.
+// Nothing is saved; that is left to the caller.
+func (p *Plugin) Import(name string, args []string) (*Bundle, error) {
+	output, err := p.run(runTimeout, nil, append([]string{"import", name}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(output, &bundle); err != nil {
+		return nil, fmt.Errorf("plugin %s printed invalid import output: %w", p.Name, err)
+	}
+	return &bundle, nil
+}
+
+// Export runs the exporter called name on bundle and returns what it prints
+func (p *Plugin) Export(name string, bundle *Bundle) ([]byte, error) {
+	input, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export: %w", err)
+	}
+	return p.run(runTimeout, input, "export", name)
+}
+
+// Format runs the formatter called name and returns the text it prints
+func (p *Plugin) Format(name string, req FormatRequest) (string, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal prompt: %w", err)
+	}
+	output, err := p.run(runTimeout, input, "format", name)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// describe asks the plugin at path for its manifest
+func describe(path string) (*Plugin, error) {
+	name, _ := pluginName(filepath.Base(path))
+	p := &Plugin{Manifest: Manifest{Name: name}, Path: path}
+	output, err := p.run(describeTimeout, nil, "describe")
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(output, &p.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid describe output: %w", err)
+	}
+	if p.Protocol > Protocol {
+		return nil, fmt.Errorf("needs plugin protocol %d; this pocket-prompt speaks %d", p.Protocol, Protocol)
+	}
+	p.Name = name // The file name is what users see and install by
+	return p, nil
+}
+
+// run runs the plugin with args, feeding it input, and returns its output.
+// What the plugin writes to stderr explains a failure.
+func (p *Plugin) run(timeout time.Duration, input []byte, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Path, args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("POCKET_PROMPT_PLUGIN_PROTOCOL=%d", Protocol))
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("plugin %s %s timed out after %s", p.Name, args[0], timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s %s failed: %s", p.Name, args[0], msg)
+		}
+		return nil, fmt.Errorf("plugin %s %s failed: %w", p.Name, args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// pluginName returns the plugin name in an executable's file name
+func pluginName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(strings.ToLower(file), ".exe")
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	return name, name != file && name != ""
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0111 != 0
+}
@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// testPlugin is a plugin providing the importer "notes", the exporter
+// "count" and the formatter "shout"
+const testPlugin = `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"protocol": 1, "importers": [{"name": "notes"}], "exporters": [{"name": "count"}], "formatters": [{"name": "shout"}]}' ;;
+import)
+	echo '{"prompts": [{"ID": "'"$3"'", "Name": "From notes", "Content": "Hello"}]}' ;;
+export)
+	grep -o '"ID"' | wc -l | tr -d ' ' ;;
+format)
+	tr a-z A-Z ;;
+*)
+	echo "unknown command $1" >&2; exit 1 ;;
+esac
+`
+
+func installTestPlugin(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, Prefix+"test"), []byte(testPlugin), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, Prefix+"broken"), []byte("#!/bin/sh\necho not json\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+}
+
+func TestDiscover(t *testing.T) {
+	installTestPlugin(t)
+
+	plugins, errs := Discover()
+	if len(plugins) != 1 || plugins[0].Name != "test" {
+		t.Fatalf("Discover found %v, want only the test plugin", plugins)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken") {
+		t.Errorf("Discover errors = %v, want one for the broken plugin", errs)
+	}
+
+	if _, err := Find(KindFormatter, "shout"); err != nil {
+		t.Errorf("Find shout: %v", err)
+	}
+	if _, err := Find(KindFormatter, "whisper"); err == nil || !strings.Contains(err.Error(), "shout") {
+		t.Errorf("Find whisper: %v, want an error listing shout", err)
+	}
+}
+
+func TestPluginCommands(t *testing.T) {
+	installTestPlugin(t)
+	p, err := Find(KindImporter, "notes")
+	if err != nil {
+		t.Fatalf("Find notes: %v", err)
+	}
+
+	bundle, err := p.Import("notes", []string{"greeting"})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if len(bundle.Prompts) != 1 || bundle.Prompts[0].ID != "greeting" || bundle.Prompts[0].Content != "Hello" {
+		t.Errorf("Import returned %+v, want the prompt greeting", bundle.Prompts)
+	}
+
+	output, err := p.Export("count", &Bundle{Prompts: []*models.Prompt{{ID: "a"}, {ID: "b"}}})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "2" {
+		t.Errorf("Export printed %q, want 2", output)
+	}
+
+	text, err := p.Format("shout", FormatRequest{Prompt: &models.Prompt{ID: "a"}, Text: "hello"})
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	if !strings.Contains(text, `"TEXT":"HELLO"`) {
+		t.Errorf("Format printed %q, want the request upper-cased", text)
+	}
+
+	if _, err := p.run(describeTimeout, nil, "explode"); err == nil || !strings.Contains(err.Error(), "unknown command explode") {
+		t.Errorf("run explode: %v, want the plugin's stderr in the error", err)
+	}
+}
@@ -0,0 +1,53 @@
+// Package qr renders QR codes as text so they can be scanned off a terminal.
+package qr
+
+import (
+	"fmt"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// MaxBytes is the most a code can hold at the lowest error correction level
+const MaxBytes = 2953
+
+// Render encodes content as a QR code drawn with half-block characters, two
+// modules per character row, light on dark so it scans on dark terminals too
+func Render(content string) (string, error) {
+	if len(content) > MaxBytes {
+		return "", fmt.Errorf("content is %d bytes; a QR code holds at most %d", len(content), MaxBytes)
+	}
+
+	// Medium recovery keeps codes small while tolerating a little glare
+	level := qrcode.Medium
+	if len(content) > 2000 {
+		level = qrcode.Low
+	}
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	// Bitmap includes the quiet zone; true means a dark module
+	bitmap := code.Bitmap()
+	var b strings.Builder
+	for y := 0; y < len(bitmap); y += 2 {
+		for x := range bitmap[y] {
+			top := bitmap[y][x]
+			bottom := y+1 < len(bitmap) && bitmap[y+1][x]
+			switch {
+			case top && bottom:
+				b.WriteRune(' ')
+			case top:
+				b.WriteRune('▄')
+			case bottom:
+				b.WriteRune('▀')
+			default:
+				b.WriteRune('█')
+			}
+		}
+		b.WriteRune('\n')
+	}
+
+	return b.String(), nil
+}
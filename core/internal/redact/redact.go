@@ -0,0 +1,102 @@
+// Package redact removes sensitive text such as email addresses, API keys and
+// client names from prompts before they are shared
+package redact
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Defaults are applied unless the config turns them off
+var Defaults = []config.RedactionRule{
+	{Name: "email", Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`},
+	{Name: "api-key", Pattern: `\b(?:(?:sk|pk|rk)[-_](?:live_|test_|proj-)?[A-Za-z0-9_-]{16,}|gh[pousr]_[A-Za-z0-9]{30,}|xox[abprs]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{35}|pkt_[A-Za-z0-9_-]{16,})`},
+}
+
+type rule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Redactor applies a set of redaction rules
+type Redactor struct {
+	rules []rule
+}
+
+// New compiles the configured rules, after the defaults unless they are turned off
+func New(cfg config.RedactionConfig) (*Redactor, error) {
+	configured := cfg.Rules
+	if !cfg.NoDefaults {
+		configured = append(append([]config.RedactionRule{}, Defaults...), cfg.Rules...)
+	}
+
+	r := &Redactor{}
+	for _, c := range configured {
+		pattern, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction rule %q: %w", c.Name, err)
+		}
+		replacement := c.Replacement
+		if replacement == "" {
+			replacement = "[redacted]"
+			if c.Name != "" {
+				replacement = "[" + c.Name + "]"
+			}
+		}
+		r.rules = append(r.rules, rule{pattern: pattern, replacement: replacement})
+	}
+	return r, nil
+}
+
+// String redacts text. Replacements are literal, so "$1" is not expanded.
+func (r *Redactor) String(text string) string {
+	for _, rule := range r.rules {
+		text = rule.pattern.ReplaceAllLiteralString(text, rule.replacement)
+	}
+	return text
+}
+
+// Prompt returns a copy of p with its title, description, content and string
+// metadata redacted
+func (r *Redactor) Prompt(p *models.Prompt) *models.Prompt {
+	redacted := *p
+	redacted.Name = r.String(p.Name)
+	redacted.Summary = r.String(p.Summary)
+	redacted.Content = r.String(p.Content)
+	if p.Metadata != nil {
+		redacted.Metadata = make(map[string]interface{}, len(p.Metadata))
+		for key, value := range p.Metadata {
+			if text, ok := value.(string); ok {
+				value = r.String(text)
+			}
+			redacted.Metadata[key] = value
+		}
+	}
+	return &redacted
+}
+
+// Prompts redacts each prompt in a list
+func (r *Redactor) Prompts(prompts []*models.Prompt) []*models.Prompt {
+	redacted := make([]*models.Prompt, len(prompts))
+	for i, p := range prompts {
+		redacted[i] = r.Prompt(p)
+	}
+	return redacted
+}
+
+// Template returns a copy of t with its description, content and slot
+// defaults redacted
+func (r *Redactor) Template(t *models.Template) *models.Template {
+	redacted := *t
+	redacted.Description = r.String(t.Description)
+	redacted.Content = r.String(t.Content)
+	redacted.Slots = make([]models.Slot, len(t.Slots))
+	for i, slot := range t.Slots {
+		slot.Default = r.String(slot.Default)
+		redacted.Slots[i] = slot
+	}
+	return &redacted
+}
@@ -0,0 +1,194 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+)
+
+// LangfuseAdapter syncs with the Langfuse prompt management API
+type LangfuseAdapter struct {
+	baseURL    string
+	publicKey  string
+	secretKey  string
+	label      string
+	pushLabels []string
+	client     *http.Client
+}
+
+// Name returns the adapter identifier
+func (a *LangfuseAdapter) Name() string {
+	return "langfuse"
+}
+
+type langfuseListResponse struct {
+	Data []struct {
+		Name string `json:"name"`
+	} `json:"data"`
+	Meta struct {
+		Page       int `json:"page"`
+		TotalPages int `json:"totalPages"`
+	} `json:"meta"`
+}
+
+type langfusePromptResponse struct {
+	Name      string          `json:"name"`
+	Version   int             `json:"version"`
+	Prompt    json.RawMessage `json:"prompt"`
+	Tags      []string        `json:"tags"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// List fetches every prompt name, then the version carrying the configured label
+func (a *LangfuseAdapter) List(ctx context.Context) ([]Prompt, error) {
+	var names []string
+	for page := 1; ; page++ {
+		var list langfuseListResponse
+		endpoint := fmt.Sprintf("%s/api/public/v2/prompts?page=%d&limit=100", a.baseURL, page)
+		if err := a.do(ctx, http.MethodGet, endpoint, nil, &list); err != nil {
+			return nil, err
+		}
+		for _, item := range list.Data {
+			names = append(names, item.Name)
+		}
+		if list.Meta.TotalPages <= page || len(list.Data) == 0 {
+			break
+		}
+	}
+
+	prompts := make([]Prompt, 0, len(names))
+	for _, name := range names {
+		var item langfusePromptResponse
+		endpoint := fmt.Sprintf("%s/api/public/v2/prompts/%s?label=%s", a.baseURL, url.PathEscape(name), url.QueryEscape(a.label))
+		if err := a.do(ctx, http.MethodGet, endpoint, nil, &item); err != nil {
+			return nil, err
+		}
+		prompt, err := a.toPrompt(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		prompts = append(prompts, prompt)
+	}
+
+	return prompts, nil
+}
+
+// Push creates a new text prompt version in Langfuse
+func (a *LangfuseAdapter) Push(ctx context.Context, prompt Prompt) (*Prompt, error) {
+	body := map[string]interface{}{
+		"name":          prompt.Name,
+		"type":          "text",
+		"prompt":        prompt.Content,
+		"tags":          prompt.Tags,
+		"labels":        a.pushLabels,
+		"commitMessage": "Synced from pocket-prompt",
+	}
+
+	var item langfusePromptResponse
+	if err := a.do(ctx, http.MethodPost, a.baseURL+"/api/public/v2/prompts", body, &item); err != nil {
+		return nil, err
+	}
+	stored, err := a.toPrompt(item)
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (a *LangfuseAdapter) toPrompt(item langfusePromptResponse) (Prompt, error) {
+	content, err := importer.DecodeLangfusePrompt(item.Prompt)
+	if err != nil {
+		return Prompt{}, err
+	}
+	return Prompt{
+		Name:      item.Name,
+		Content:   content,
+		Tags:      item.Tags,
+		Version:   strconv.Itoa(item.Version),
+		UpdatedAt: item.UpdatedAt,
+	}, nil
+}
+
+func (a *LangfuseAdapter) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(a.publicKey, a.secretKey)
+	return doJSON(a.client, req, body, out)
+}
+
+// RESTAdapter syncs with a generic registry exposing:
+//
+//	GET {url}/prompts         -> [Prompt] or {"prompts": [Prompt]}
+//	PUT {url}/prompts/{name}  <- Prompt, -> Prompt
+//
+// using the JSON field names of Prompt and optional bearer token auth.
+type RESTAdapter struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// Name returns the adapter identifier
+func (a *RESTAdapter) Name() string {
+	return "rest"
+}
+
+// List fetches all prompts from the registry
+func (a *RESTAdapter) List(ctx context.Context) ([]Prompt, error) {
+	var raw json.RawMessage
+	if err := a.do(ctx, http.MethodGet, a.baseURL+"/prompts", nil, &raw); err != nil {
+		return nil, err
+	}
+
+	var prompts []Prompt
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		if err := json.Unmarshal(raw, &prompts); err != nil {
+			return nil, fmt.Errorf("failed to decode prompt list: %w", err)
+		}
+		return prompts, nil
+	}
+
+	var wrapper struct {
+		Prompts []Prompt `json:"prompts"`
+	}
+	if err := json.Unmarshal(raw, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to decode prompt list: %w", err)
+	}
+	return wrapper.Prompts, nil
+}
+
+// Push stores the prompt under its name
+func (a *RESTAdapter) Push(ctx context.Context, prompt Prompt) (*Prompt, error) {
+	var stored Prompt
+	endpoint := fmt.Sprintf("%s/prompts/%s", a.baseURL, url.PathEscape(prompt.Name))
+	if err := a.do(ctx, http.MethodPut, endpoint, prompt, &stored); err != nil {
+		return nil, err
+	}
+
+	// Servers that reply without a body are assumed to have stored the prompt as sent
+	if stored.Name == "" {
+		stored = prompt
+		stored.UpdatedAt = time.Now()
+	}
+	return &stored, nil
+}
+
+func (a *RESTAdapter) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	return doJSON(a.client, req, body, out)
+}
@@ -0,0 +1,152 @@
+package remote
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Sync directions
+const (
+	DirectionPull = "pull"
+	DirectionPush = "push"
+	DirectionBoth = "both"
+)
+
+// Plan actions
+const (
+	ActionPush     = "push"
+	ActionPull     = "pull"
+	ActionConflict = "conflict"
+	ActionInSync   = "in-sync"
+)
+
+// Local is a library prompt as seen by the sync planner
+type Local struct {
+	ID         string
+	RemoteName string
+	Hash       string
+}
+
+// PlanItem is the action chosen for a single remote name
+type PlanItem struct {
+	Name    string  `json:"name"`
+	LocalID string  `json:"local_id,omitempty"`
+	Action  string  `json:"action"`
+	Reason  string  `json:"reason"`
+	Remote  *Prompt `json:"-"`
+}
+
+// Report summarizes a sync run
+type Report struct {
+	Adapter   string
+	Direction string
+	DryRun    bool
+	Items     []PlanItem
+	Errors    []error
+}
+
+// Count returns how many items were planned with the given action
+func (r *Report) Count(action string) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Plan decides, for every prompt known on either side, whether to push, pull,
+// or report a conflict. A side counts as changed when its hash differs from the
+// one recorded at the last sync; when both sides changed to different content
+// the item is a conflict unless prefer ("local" or "remote") settles it.
+func Plan(locals []Local, remotes []Prompt, state *State, direction, prefer string) ([]PlanItem, error) {
+	switch direction {
+	case DirectionPull, DirectionPush, DirectionBoth:
+	default:
+		return nil, fmt.Errorf("unknown sync direction %q", direction)
+	}
+	switch prefer {
+	case "", "local", "remote":
+	default:
+		return nil, fmt.Errorf("--prefer must be local or remote, got %q", prefer)
+	}
+
+	localByName := make(map[string]Local, len(locals))
+	for _, l := range locals {
+		localByName[l.RemoteName] = l
+	}
+	remoteByName := make(map[string]*Prompt, len(remotes))
+	for i := range remotes {
+		remoteByName[remotes[i].Name] = &remotes[i]
+	}
+
+	names := make([]string, 0, len(localByName)+len(remoteByName))
+	for name := range localByName {
+		names = append(names, name)
+	}
+	for name := range remoteByName {
+		if _, ok := localByName[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	canPush := direction != DirectionPull
+	canPull := direction != DirectionPush
+
+	var items []PlanItem
+	for _, name := range names {
+		local, hasLocal := localByName[name]
+		remote := remoteByName[name]
+		item := PlanItem{Name: name, LocalID: local.ID, Remote: remote}
+
+		switch {
+		case hasLocal && remote == nil:
+			if !canPush {
+				continue
+			}
+			item.Action, item.Reason = ActionPush, "not in registry"
+
+		case !hasLocal && remote != nil:
+			if !canPull {
+				continue
+			}
+			item.Action, item.Reason = ActionPull, "not in library"
+
+		default:
+			remoteHash := remote.Hash()
+			if local.Hash == remoteHash {
+				item.Action, item.Reason = ActionInSync, "identical content"
+				break
+			}
+
+			entry := state.Entries[name]
+			localChanged := entry == nil || entry.LocalHash != local.Hash
+			remoteChanged := entry == nil || entry.RemoteHash != remoteHash
+
+			switch {
+			case !localChanged && !remoteChanged:
+				item.Action, item.Reason = ActionInSync, "unchanged since last sync"
+			case localChanged && !remoteChanged:
+				item.Action, item.Reason = ActionPush, "changed locally"
+			case remoteChanged && !localChanged:
+				item.Action, item.Reason = ActionPull, "changed in registry"
+			case prefer == "local":
+				item.Action, item.Reason = ActionPush, "conflict resolved in favour of local"
+			case prefer == "remote":
+				item.Action, item.Reason = ActionPull, "conflict resolved in favour of registry"
+			default:
+				item.Action, item.Reason = ActionConflict, "changed on both sides since last sync"
+			}
+
+			if (item.Action == ActionPush && !canPush) || (item.Action == ActionPull && !canPull) {
+				continue
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,66 @@
+package remote
+
+import (
+	"testing"
+)
+
+func TestPlanDetectsChangesAgainstLastSync(t *testing.T) {
+	base := Prompt{Name: "base", Content: "v1"}
+	edited := Prompt{Name: "base", Content: "v2"}
+
+	state := &State{Entries: make(map[string]*Entry)}
+	state.Record("base", base.Hash(), base.Hash(), base.UpdatedAt)
+
+	cases := []struct {
+		name      string
+		local     string
+		remote    Prompt
+		prefer    string
+		direction string
+		want      string
+	}{
+		{"unchanged", "v1", base, "", DirectionBoth, ActionInSync},
+		{"local edit", "v2", base, "", DirectionBoth, ActionPush},
+		{"remote edit", "v1", edited, "", DirectionBoth, ActionPull},
+		{"both edited", "v3", edited, "", DirectionBoth, ActionConflict},
+		{"both edited prefer local", "v3", edited, "local", DirectionBoth, ActionPush},
+		{"both edited prefer remote", "v3", edited, "remote", DirectionBoth, ActionPull},
+		{"local edit on pull", "v2", base, "", DirectionPull, ""},
+	}
+
+	for _, tc := range cases {
+		locals := []Local{{ID: "base", RemoteName: "base", Hash: ContentHash(tc.local, nil)}}
+		items, err := Plan(locals, []Prompt{tc.remote}, state, tc.direction, tc.prefer)
+		if err != nil {
+			t.Fatalf("%s: Plan failed: %v", tc.name, err)
+		}
+
+		got := ""
+		if len(items) == 1 {
+			got = items[0].Action
+		}
+		if got != tc.want {
+			t.Errorf("%s: expected action %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestPlanNewPromptsOnEitherSide(t *testing.T) {
+	state := &State{Entries: make(map[string]*Entry)}
+	locals := []Local{{ID: "local-only", RemoteName: "local-only", Hash: ContentHash("a", nil)}}
+	remotes := []Prompt{{Name: "remote-only", Content: "b"}}
+
+	items, err := Plan(locals, remotes, state, DirectionBoth, "")
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].Name != "local-only" || items[0].Action != ActionPush {
+		t.Errorf("expected local-only to be pushed, got %+v", items[0])
+	}
+	if items[1].Name != "remote-only" || items[1].Action != ActionPull {
+		t.Errorf("expected remote-only to be pulled, got %+v", items[1])
+	}
+}
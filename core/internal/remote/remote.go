@@ -0,0 +1,142 @@
+// Package remote keeps the library in sync with hosted prompt registries.
+// Registries are reached through adapters; the sync planner compares content
+// hashes against the last synced state to decide what to push, pull, or flag
+// as a conflict.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+// Prompt is the registry-side representation of a prompt
+type Prompt struct {
+	Name      string    `json:"name"`
+	Content   string    `json:"content"`
+	Tags      []string  `json:"tags,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Hash returns the content hash used for change detection
+func (p Prompt) Hash() string {
+	return ContentHash(p.Content, p.Tags)
+}
+
+// ContentHash hashes the fields both sides of a sync agree on: content and tags.
+// Titles and version numbers are local concerns and do not count as changes.
+func ContentHash(content string, tags []string) string {
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	h.Write([]byte(strings.TrimSpace(content)))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(sorted, ",")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Adapter talks to one kind of hosted registry
+type Adapter interface {
+	// Name identifies the adapter in reports and commit messages
+	Name() string
+	// List returns the current version of every prompt in the registry
+	List(ctx context.Context) ([]Prompt, error)
+	// Push publishes prompt as a new version and returns the stored result
+	Push(ctx context.Context, prompt Prompt) (*Prompt, error)
+}
+
+// NewAdapter builds the adapter selected in the configuration. The URL and
+// SecretEnv only ever come from the user's own configuration, since the
+// secret is sent to the URL.
+func NewAdapter(cfg config.RemoteConfig) (Adapter, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("remote registry is not configured (pkt config set remote.url <url>)")
+	}
+
+	secret := ""
+	if cfg.SecretEnv != "" {
+		secret = os.Getenv(cfg.SecretEnv)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	baseURL := strings.TrimRight(cfg.URL, "/")
+
+	switch cfg.Adapter {
+	case "langfuse":
+		if cfg.PublicKey == "" || secret == "" {
+			return nil, fmt.Errorf("langfuse adapter requires remote.public_key and a secret key in $%s", cfg.SecretEnv)
+		}
+		label := cfg.Label
+		if label == "" {
+			label = "latest"
+		}
+		return &LangfuseAdapter{
+			baseURL:    baseURL,
+			publicKey:  cfg.PublicKey,
+			secretKey:  secret,
+			label:      label,
+			pushLabels: cfg.PushLabels,
+			client:     client,
+		}, nil
+	case "rest", "":
+		return &RESTAdapter{
+			baseURL: baseURL,
+			token:   secret,
+			client:  client,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown remote adapter %q (expected langfuse or rest)", cfg.Adapter)
+	}
+}
+
+// doJSON sends a request with an optional JSON body and decodes a JSON response into out
+func doJSON(client *http.Client, req *http.Request, body interface{}, out interface{}) error {
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet := strings.TrimSpace(string(data))
+		if len(snippet) > 200 {
+			snippet = snippet[:200] + "..."
+		}
+		return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, snippet)
+	}
+
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StateFileName is where the last synced hashes are kept, inside .pocket-prompt/
+const StateFileName = "remote-sync.json"
+
+// Entry records what both sides looked like the last time a prompt was synced
+type Entry struct {
+	RemoteName      string    `json:"remote_name"`
+	LocalHash       string    `json:"local_hash"`
+	RemoteHash      string    `json:"remote_hash"`
+	RemoteUpdatedAt time.Time `json:"remote_updated_at"`
+	SyncedAt        time.Time `json:"synced_at"`
+}
+
+// State is the per-workspace sync state, keyed by remote prompt name
+type State struct {
+	Entries map[string]*Entry `json:"entries"`
+	path    string
+}
+
+// LoadState reads the sync state for the library at baseDir; a missing file yields empty state
+func LoadState(baseDir string) (*State, error) {
+	state := &State{
+		Entries: make(map[string]*Entry),
+		path:    filepath.Join(baseDir, ".pocket-prompt", StateFileName),
+	}
+
+	data, err := os.ReadFile(state.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	if state.Entries == nil {
+		state.Entries = make(map[string]*Entry)
+	}
+
+	return state, nil
+}
+
+// Record marks name as synced with the given hashes
+func (s *State) Record(name, localHash, remoteHash string, remoteUpdatedAt time.Time) {
+	s.Entries[name] = &Entry{
+		RemoteName:      name,
+		LocalHash:       localHash,
+		RemoteHash:      remoteHash,
+		RemoteUpdatedAt: remoteUpdatedAt,
+		SyncedAt:        time.Now(),
+	}
+}
+
+// Save writes the sync state to disk
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
@@ -0,0 +1,175 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultMaxFileSize bounds {{file:path}} contents and {{cmd:...}} output
+// when Context.MaxFileSize is not set
+const DefaultMaxFileSize = 64 << 10
+
+// commandTimeout bounds how long a {{cmd:...}} placeholder may run
+const commandTimeout = 10 * time.Second
+
+// ErrCommandsNotAllowed is returned for {{cmd:...}} placeholders when the
+// render does not allow commands
+var ErrCommandsNotAllowed = errors.New("running commands is not allowed for this render")
+
+// Context fills placeholders from the place a prompt is rendered in:
+//
+//	{{cwd}}          the working directory
+//	{{git.branch}}   the current git branch
+//	{{git.commit}}   the current commit, abbreviated
+//	{{git.root}}     the top of the git repository
+//	{{file:path}}    a file's contents, relative to the working directory
+//	{{cmd:command}}  a shell command's output, only with AllowCommands
+//
+// Variables given for a render take precedence over these.
+type Context struct {
+	Dir           string // Working directory; the process's when empty
+	AllowCommands bool   // Run {{cmd:...}} placeholders
+	MaxFileSize   int64  // Largest file or command output included (default: DefaultMaxFileSize)
+}
+
+// resolve returns the value of a context placeholder, and false when name is
+// not one
+func (c *Context) resolve(name string) (string, bool, error) {
+	kind, arg, _ := strings.Cut(name, ":")
+	switch {
+	case name == "cwd":
+		dir, err := c.dir()
+		return dir, true, err
+	case strings.HasPrefix(name, "git."):
+		value, err := c.git(strings.TrimPrefix(name, "git."))
+		if err != nil {
+			return "", true, fmt.Errorf("{{%s}}: %w", name, err)
+		}
+		return value, true, nil
+	case kind == "file":
+		value, err := c.file(strings.TrimSpace(arg))
+		if err != nil {
+			return "", true, fmt.Errorf("{{file:%s}}: %w", arg, err)
+		}
+		return value, true, nil
+	case kind == "cmd":
+		value, err := c.command(strings.TrimSpace(arg))
+		if err != nil {
+			return "", true, fmt.Errorf("{{cmd:%s}}: %w", arg, err)
+		}
+		return value, true, nil
+	}
+	return "", false, nil
+}
+
+func (c *Context) dir() (string, error) {
+	if c.Dir != "" {
+		return filepath.Abs(c.Dir)
+	}
+	return os.Getwd()
+}
+
+func (c *Context) maxSize() int64 {
+	if c.MaxFileSize > 0 {
+		return c.MaxFileSize
+	}
+	return DefaultMaxFileSize
+}
+
+func (c *Context) git(field string) (string, error) {
+	var args []string
+	switch field {
+	case "branch":
+		args = []string{"branch", "--show-current"} // Empty on a detached HEAD
+	case "commit":
+		args = []string{"rev-parse", "--short", "HEAD"}
+	case "root":
+		args = []string{"rev-parse", "--show-toplevel"}
+	default:
+		return "", fmt.Errorf("unknown git field (expected branch, commit or root)")
+	}
+	dir, err := c.dir()
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *Context) file(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("missing path")
+	}
+	if !filepath.IsAbs(path) {
+		dir, err := c.dir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(dir, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > c.maxSize() {
+		return "", fmt.Errorf("file is %d bytes, over the %d byte limit", info.Size(), c.maxSize())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+func (c *Context) command(command string) (string, error) {
+	if !c.AllowCommands {
+		return "", ErrCommandsNotAllowed
+	}
+	if command == "" {
+		return "", fmt.Errorf("missing command")
+	}
+	dir, err := c.dir()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("timed out after %s", commandTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	if int64(len(out)) > c.maxSize() {
+		return "", fmt.Errorf("output is %d bytes, over the %d byte limit", len(out), c.maxSize())
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,132 @@
+package renderer
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRenderTextWithContext(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pkt-renderer")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("fix the parser\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	prompt := &models.Prompt{Content: "In {{cwd}}, for {{ who }}: {{file:notes.txt}}. {{unknown}} stays."}
+	r := NewRenderer(prompt, nil)
+
+	// Without a context, context placeholders are left alone
+	text, err := r.RenderText(map[string]interface{}{"who": "Sam"})
+	if err != nil {
+		t.Fatalf("RenderText: %v", err)
+	}
+	if text != "In {{cwd}}, for Sam: {{file:notes.txt}}. {{unknown}} stays." {
+		t.Fatalf("RenderText without context = %q", text)
+	}
+
+	r.SetContext(&Context{Dir: dir})
+	text, err = r.RenderText(map[string]interface{}{"who": "Sam"})
+	if err != nil {
+		t.Fatalf("RenderText: %v", err)
+	}
+	want := "In " + dir + ", for Sam: fix the parser. {{unknown}} stays."
+	if text != want {
+		t.Fatalf("RenderText = %q, want %q", text, want)
+	}
+
+	// Variables take precedence over context placeholders
+	text, err = r.RenderText(map[string]interface{}{"who": "Sam", "cwd": "~/src"})
+	if err != nil {
+		t.Fatalf("RenderText: %v", err)
+	}
+	if !strings.HasPrefix(text, "In ~/src,") {
+		t.Fatalf("cwd variable not used: %q", text)
+	}
+}
+
+func TestContextFileLimits(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pkt-renderer")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "big.log"), []byte(strings.Repeat("x", 100)), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	live := &Context{Dir: dir, MaxFileSize: 10}
+	for _, content := range []string{"{{file:big.log}}", "{{file:missing.txt}}", "{{git.nope}}"} {
+		r := NewRenderer(&models.Prompt{Content: content}, nil)
+		r.SetContext(live)
+		if _, err := r.RenderText(nil); err == nil {
+			t.Errorf("%s rendered without error", content)
+		}
+	}
+}
+
+func TestContextCommands(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir, err := os.MkdirTemp("", "pkt-renderer")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	prompt := &models.Prompt{Content: "Files: {{cmd:printf '%s' \"$(ls)\" | awk '{print toupper($0)}'}}"}
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), nil, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	r := NewRenderer(prompt, nil)
+	r.SetContext(&Context{Dir: dir})
+	if _, err := r.RenderText(nil); !errors.Is(err, ErrCommandsNotAllowed) {
+		t.Fatalf("command ran without being allowed: %v", err)
+	}
+
+	r.SetContext(&Context{Dir: dir, AllowCommands: true})
+	text, err := r.RenderText(nil)
+	if err != nil {
+		t.Fatalf("RenderText: %v", err)
+	}
+	if text != "Files: A.GO" {
+		t.Fatalf("RenderText = %q", text)
+	}
+}
+
+func TestContextGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir, err := os.MkdirTemp("", "pkt-renderer")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if out, err := exec.Command("git", "-C", dir, "init", "-q", "-b", "feature/login").CombinedOutput(); err != nil {
+		t.Skipf("git init: %v: %s", err, out)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	r := NewRenderer(&models.Prompt{Content: "On {{git.branch}}"}, nil)
+	r.SetContext(&Context{Dir: filepath.Join(dir, "sub")})
+	text, err := r.RenderText(nil)
+	if err != nil {
+		t.Fatalf("RenderText: %v", err)
+	}
+	if text != "On feature/login" {
+		t.Fatalf("RenderText = %q", text)
+	}
+}
@@ -0,0 +1,85 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// GalleryCard is one template on a gallery page
+type GalleryCard struct {
+	PreviewInfo
+	Slots       []GallerySlot
+	Example     string // Shown as is, since it is what a render sends
+	ExampleFrom string // Prompt whose content the example wraps, if any
+	Error       string // Why the example could not be rendered
+}
+
+// GallerySlot is a template slot with the sample value its example uses
+type GallerySlot struct {
+	Name        string
+	Description string
+	Required    bool
+	Sample      string
+}
+
+// galleryPage lists templates as cards on one standalone page, styled like
+// previews
+var galleryPage = template.Must(template.New("gallery").Parse(pageStyles + `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="pocket-prompt">
+<title>Template Gallery</title>
+{{template "styles"}}
+</head>
+<body>
+<main>
+<header>
+  <div class="kind">Gallery</div>
+  <h1 class="title">Templates</h1>
+  <p class="description">{{len .}} template{{if ne (len .) 1}}s{{end}}, each with an example rendered from sample values</p>
+</header>
+{{- range .}}
+<section class="card">
+  <div class="kind">{{.Kind}}{{if .Version}} · v{{.Version}}{{end}}</div>
+  <h2>{{.Title}}</h2>
+  {{- if .Description}}
+  <p class="description">{{.Description}}</p>
+  {{- end}}
+  {{- range .Details}}
+  <p class="meta">{{.}}</p>
+  {{- end}}
+  <article>
+  {{- if .Slots}}
+  <table>
+    <tr><th>Slot</th><th>Description</th><th>Example value</th></tr>
+    {{- range .Slots}}
+    <tr><td><code>{{.Name}}</code>{{if .Required}} (required){{end}}</td><td>{{.Description}}</td><td>{{.Sample}}</td></tr>
+    {{- end}}
+  </table>
+  {{- end}}
+  <p class="meta">Example{{if .ExampleFrom}} with the content of {{.ExampleFrom}}{{end}}</p>
+  {{- if .Error}}
+  <p class="error">{{.Error}}</p>
+  {{- else}}
+  <pre>{{.Example}}</pre>
+  {{- end}}
+  </article>
+</section>
+{{- end}}
+<footer>Shared from Pocket Prompt</footer>
+</main>
+</body>
+</html>
+`))
+
+// GalleryHTML renders templates as a standalone, styled HTML page of cards
+func GalleryHTML(cards []GalleryCard) (string, error) {
+	var page bytes.Buffer
+	if err := galleryPage.Execute(&page, cards); err != nil {
+		return "", fmt.Errorf("failed to render gallery: %w", err)
+	}
+	return page.String(), nil
+}
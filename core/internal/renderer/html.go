@@ -0,0 +1,166 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// PreviewInfo is the header shown above a rendered preview
+type PreviewInfo struct {
+	Kind        string // "Prompt" or "Template"
+	Title       string
+	Description string
+	Version     string
+	Tags        []string
+	Details     []string // Further lines such as the template used or slot names
+}
+
+// markdown converts previews to HTML. Raw HTML in the source is left out, so
+// a shared preview cannot run scripts from a prompt.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// pageStyles are the inline styles of previews and the template gallery
+const pageStyles = `{{define "styles"}}<style>
+  :root { color-scheme: light dark; --fg: #1f2328; --muted: #59636e; --bg: #ffffff; --card: #f6f8fa; --border: #d1d9e0; --accent: #8250df; }
+  @media (prefers-color-scheme: dark) {
+    :root { --fg: #e6edf3; --muted: #9198a1; --bg: #0d1117; --card: #151b23; --border: #3d444d; --accent: #ab7df8; }
+  }
+  body { margin: 0; background: var(--bg); color: var(--fg); font: 16px/1.6 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; }
+  main { max-width: 46rem; margin: 2.5rem auto; padding: 0 1.25rem; }
+  header { border-bottom: 1px solid var(--border); margin-bottom: 1.5rem; padding-bottom: 1rem; }
+  .kind { color: var(--accent); font-size: .8rem; font-weight: 600; letter-spacing: .06em; text-transform: uppercase; }
+  h1.title { margin: .25rem 0; font-size: 1.8rem; line-height: 1.25; }
+  .description { color: var(--muted); margin: .25rem 0 .75rem; }
+  .meta { color: var(--muted); font-size: .85rem; margin: .15rem 0; }
+  .tag { display: inline-block; border: 1px solid var(--border); border-radius: 999px; padding: 0 .6rem; margin: 0 .25rem .25rem 0; font-size: .8rem; }
+  article { background: var(--card); border: 1px solid var(--border); border-radius: 8px; padding: .5rem 1.5rem; }
+  pre { background: var(--bg); border: 1px solid var(--border); border-radius: 6px; padding: .75rem 1rem; overflow-x: auto; }
+  code { font: .9em ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+  img { max-width: 100%; border-radius: 6px; }
+  blockquote { margin: 0; padding: 0 1rem; border-left: 3px solid var(--border); color: var(--muted); }
+  table { border-collapse: collapse; } th, td { border: 1px solid var(--border); padding: .3rem .6rem; }
+  footer { color: var(--muted); font-size: .75rem; margin-top: 1.5rem; text-align: right; }
+  section.card { margin-bottom: 2.5rem; }
+  section.card h2 { margin: .25rem 0; font-size: 1.4rem; }
+  .error { color: #cf222e; }
+</style>{{end}}`
+
+// previewPage is a standalone page with its styles inline, so a preview works
+// wherever the file is opened or sent
+var previewPage = template.Must(template.New("preview").Parse(pageStyles + `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="pocket-prompt">
+<title>{{.Info.Title}}</title>
+{{template "styles"}}
+</head>
+<body>
+<main>
+<header>
+  <div class="kind">{{.Info.Kind}}{{if .Info.Version}} · v{{.Info.Version}}{{end}}</div>
+  <h1 class="title">{{.Info.Title}}</h1>
+  {{- if .Info.Description}}
+  <p class="description">{{.Info.Description}}</p>
+  {{- end}}
+  {{- range .Info.Details}}
+  <p class="meta">{{.}}</p>
+  {{- end}}
+  {{- if .Info.Tags}}
+  <div>{{range .Info.Tags}}<span class="tag">{{.}}</span>{{end}}</div>
+  {{- end}}
+</header>
+<article>
+{{.Body}}
+</article>
+<footer>Shared from Pocket Prompt</footer>
+</main>
+</body>
+</html>
+`))
+
+// PreviewHTML renders Markdown content as a standalone, styled HTML page
+// under a header built from info
+func PreviewHTML(info PreviewInfo, content string) (string, error) {
+	var body bytes.Buffer
+	if err := markdown.Convert([]byte(content), &body); err != nil {
+		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	}
+	if info.Title == "" {
+		info.Title = "Untitled"
+	}
+
+	var page bytes.Buffer
+	err := previewPage.Execute(&page, struct {
+		Info PreviewInfo
+		Body template.HTML
+	}{info, template.HTML(body.String())})
+	if err != nil {
+		return "", fmt.Errorf("failed to render preview: %w", err)
+	}
+	return page.String(), nil
+}
+
+// RenderHTML renders the prompt as a shareable HTML page, filling in
+// variables as for RenderText. Images are shown inline, embedded or linked as
+// set by SetImageEncoding, with the frontmatter's images after the content.
+func (r *Renderer) RenderHTML(variables map[string]interface{}) (string, error) {
+	content, err := r.renderContent(variables)
+	if err != nil {
+		return "", err
+	}
+
+	var withImages strings.Builder
+	last := 0
+	for _, match := range r.imageMatches(content) {
+		image, err := r.markdownImage(content[match[2]:match[3]])
+		if err != nil {
+			return "", err
+		}
+		withImages.WriteString(content[last:match[0]] + image)
+		last = match[1]
+	}
+	content = withImages.String() + content[last:]
+	for _, ref := range r.prompt.Images {
+		image, err := r.markdownImage(ref)
+		if err != nil {
+			return "", err
+		}
+		content += "\n\n" + image
+	}
+
+	info := PreviewInfo{
+		Kind:        "Prompt",
+		Title:       r.prompt.Name,
+		Description: r.prompt.Summary,
+		Version:     r.prompt.Version,
+		Tags:        r.prompt.Tags,
+	}
+	if info.Title == "" {
+		info.Title = r.prompt.ID
+	}
+	if r.template != nil {
+		info.Details = append(info.Details, "Template: "+r.template.Name)
+	}
+	if r.redact != nil {
+		info.Title = r.redact(info.Title)
+		info.Description = r.redact(info.Description)
+	}
+	return PreviewHTML(info, content)
+}
+
+// markdownImage turns an image reference into a Markdown image
+func (r *Renderer) markdownImage(ref string) (string, error) {
+	part, err := r.imagePart(ref)
+	if err != nil {
+		return "", err
+	}
+	alt := strings.NewReplacer("[", "", "]", "").Replace(strings.TrimSpace(ref))
+	return fmt.Sprintf("![%s](<%s>)", alt, part.ImageURL.URL), nil
+}
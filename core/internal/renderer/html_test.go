@@ -0,0 +1,40 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRenderHTML(t *testing.T) {
+	prompt := &models.Prompt{
+		ID:      "review",
+		Name:    "Code <Review>",
+		Summary: "Review a diff",
+		Version: "1.2.0",
+		Tags:    []string{"code"},
+		Content: "# Review {{lang}}\n\n- Be **brief**\n\n<script>alert(1)</script>\n\n{{image:https://example.com/diagram.png}}",
+	}
+	r := NewRenderer(prompt, nil)
+
+	page, err := r.RenderHTML(map[string]interface{}{"lang": "Go"})
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	for _, want := range []string{
+		"<title>Code &lt;Review&gt;</title>",
+		"v1.2.0",
+		`<span class="tag">code</span>`,
+		"<h1>Review Go</h1>",
+		"<strong>brief</strong>",
+		`<img src="https://example.com/diagram.png" alt="https://example.com/diagram.png">`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("preview is missing %q", want)
+		}
+	}
+	if strings.Contains(page, "<script>alert(1)</script>") {
+		t.Error("raw HTML from the prompt was copied into the preview")
+	}
+}
@@ -0,0 +1,170 @@
+package renderer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// Image encodings for RenderJSON
+const (
+	ImageBase64 = "base64" // Embed image data as data: URLs
+	ImagePath   = "path"   // Reference images by file:// URL
+)
+
+// imagePlaceholder matches inline image references such as {{image:assets/chart.png}}
+var imagePlaceholder = regexp.MustCompile(`\{\{\s*image:\s*([^}]+?)\s*\}\}`)
+
+// ContentPart is one part of a multimodal message, in the content-part format
+// used by OpenAI-compatible chat APIs
+type ContentPart struct {
+	Type     string    `json:"type"` // "text" or "image_url"
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL locates an image: an http(s) URL, a data: URL, or a file:// URL
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// ImageRefs returns the image references written inline in content
+func ImageRefs(content string) []string {
+	var refs []string
+	for _, m := range imagePlaceholder.FindAllStringSubmatch(content, -1) {
+		refs = append(refs, m[1])
+	}
+	return refs
+}
+
+// storedImageRefs returns the image references written in the prompt and its
+// template as stored. Only these are expanded: a placeholder that arrives in
+// a variable value, such as text sent to the server, stays plain text, so a
+// render cannot be made to read files the prompt does not refer to.
+func (r *Renderer) storedImageRefs() map[string]bool {
+	refs := map[string]bool{}
+	for _, ref := range ImageRefs(r.prompt.Content) {
+		refs[ref] = true
+	}
+	if r.template != nil {
+		for _, ref := range ImageRefs(r.template.Content) {
+			refs[ref] = true
+		}
+	}
+	return refs
+}
+
+// imageMatches returns the positions of the image placeholders in rendered
+// content that come from the stored prompt or template
+func (r *Renderer) imageMatches(content string) [][]int {
+	stored := r.storedImageRefs()
+	var matches [][]int
+	for _, match := range imagePlaceholder.FindAllStringSubmatchIndex(content, -1) {
+		if stored[content[match[2]:match[3]]] {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// markImages replaces the image placeholders from the stored prompt or
+// template with [image: path] markers for plain text
+func (r *Renderer) markImages(content string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range r.imageMatches(content) {
+		b.WriteString(content[last:match[0]])
+		b.WriteString("[image: " + content[match[2]:match[3]] + "]")
+		last = match[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// hasImages reports whether the rendered content or the prompt's frontmatter
+// refers to any images
+func (r *Renderer) hasImages(content string) bool {
+	return len(r.prompt.Images) > 0 || len(r.imageMatches(content)) > 0
+}
+
+// contentParts splits content at its image placeholders into text and image
+// parts, in order, followed by the images listed in the frontmatter
+func (r *Renderer) contentParts(content string) ([]ContentPart, error) {
+	var parts []ContentPart
+	addText := func(text string) {
+		if strings.TrimSpace(text) != "" {
+			parts = append(parts, ContentPart{Type: "text", Text: text})
+		}
+	}
+
+	last := 0
+	for _, match := range r.imageMatches(content) {
+		addText(content[last:match[0]])
+		part, err := r.imagePart(content[match[2]:match[3]])
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+		last = match[1]
+	}
+	addText(content[last:])
+
+	for _, ref := range r.prompt.Images {
+		part, err := r.imagePart(ref)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// imagePart resolves an image reference into a content part. URLs are passed
+// through; files must be under assets/ and are read relative to the asset
+// directory.
+func (r *Renderer) imagePart(ref string) (ContentPart, error) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "data:") {
+		return ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: ref}}, nil
+	}
+
+	cleaned, err := storage.CleanAttachmentPath(ref)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("image %s: %w", ref, err)
+	}
+	path := filepath.FromSlash(cleaned)
+	if r.assetDir != "" {
+		path = filepath.Join(r.assetDir, path)
+	}
+
+	if r.imageEncoding == ImagePath {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return ContentPart{}, fmt.Errorf("image %s: %w", ref, err)
+		}
+		fileURL := &url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+		return ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: fileURL.String()}}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read image %s: %w", ref, err)
+	}
+	mediaType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(data)
+	}
+	if i := strings.Index(mediaType, ";"); i != -1 {
+		mediaType = mediaType[:i]
+	}
+	dataURL := fmt.Sprintf("data:%s;base64,%s", mediaType, base64.StdEncoding.EncodeToString(data))
+	return ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: dataURL}}, nil
+}
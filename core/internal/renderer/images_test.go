@@ -0,0 +1,112 @@
+package renderer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRenderJSONWithImages(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pkt-renderer")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "assets"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "assets", "chart.png"), []byte("png"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	prompt := &models.Prompt{
+		Content: "Describe this chart:\n{{image:assets/chart.png}}\nBe brief.",
+		Images:  []string{"https://example.com/logo.png"},
+	}
+	r := NewRenderer(prompt, nil)
+	r.SetAssetDir(dir)
+
+	text, err := r.RenderText(nil)
+	if err != nil {
+		t.Fatalf("RenderText: %v", err)
+	}
+	if text != "Describe this chart:\n[image: assets/chart.png]\nBe brief." {
+		t.Fatalf("RenderText = %q", text)
+	}
+
+	out, err := r.RenderJSON(nil)
+	if err != nil {
+		t.Fatalf("RenderJSON: %v", err)
+	}
+	var messages []struct {
+		Content []ContentPart `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(out), &messages); err != nil {
+		t.Fatalf("RenderJSON output is not multimodal: %v\n%s", err, out)
+	}
+
+	parts := messages[0].Content
+	if len(parts) != 4 {
+		t.Fatalf("got %d content parts, want 4: %s", len(parts), out)
+	}
+	if parts[1].ImageURL == nil || parts[1].ImageURL.URL != "data:image/png;base64,cG5n" {
+		t.Fatalf("inline image part = %+v", parts[1])
+	}
+	if parts[3].ImageURL == nil || parts[3].ImageURL.URL != "https://example.com/logo.png" {
+		t.Fatalf("frontmatter image part = %+v", parts[3])
+	}
+}
+
+func TestRenderJSONWithoutImagesKeepsStringContent(t *testing.T) {
+	out, err := NewRenderer(&models.Prompt{Content: "Hello"}, nil).RenderJSON(nil)
+	if err != nil {
+		t.Fatalf("RenderJSON: %v", err)
+	}
+	var messages []Message
+	if err := json.Unmarshal([]byte(out), &messages); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if content, ok := messages[0].Content.(string); !ok || content != "Hello" {
+		t.Fatalf("content = %#v, want the plain string", messages[0].Content)
+	}
+}
+
+func TestImagesOnlyFromStoredContent(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// A placeholder in a variable value stays text
+	r := NewRenderer(&models.Prompt{Content: "Hello {{name}}"}, nil)
+	r.SetAssetDir(dir)
+	value := "{{image:" + secret + "}}"
+	out, err := r.RenderJSON(map[string]interface{}{"name": value})
+	if err != nil {
+		t.Fatalf("RenderJSON: %v", err)
+	}
+	var messages []Message
+	if err := json.Unmarshal([]byte(out), &messages); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if content, ok := messages[0].Content.(string); !ok || content != "Hello "+value {
+		t.Fatalf("content = %#v, want the value as text", messages[0].Content)
+	}
+	text, err := r.RenderText(map[string]interface{}{"name": value})
+	if err != nil || text != "Hello "+value {
+		t.Fatalf("RenderText = %q, %v; want the value as text", text, err)
+	}
+
+	// Files in the prompt itself must be under assets/
+	for _, ref := range []string{secret, "../secret.txt", "assets/../secret.txt"} {
+		r := NewRenderer(&models.Prompt{Content: "{{image:" + ref + "}}"}, nil)
+		r.SetAssetDir(filepath.Join(dir, "library"))
+		if _, err := r.RenderJSON(nil); err == nil {
+			t.Errorf("RenderJSON read image %s outside assets/", ref)
+		}
+	}
+}
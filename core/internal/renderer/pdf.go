@@ -0,0 +1,587 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/dpshade/pocket-prompt/internal/pdf"
+)
+
+// PDFExport is a set of prompts to typeset as one PDF
+type PDFExport struct {
+	Title     string    // Heads the contents page and names the document
+	Generated time.Time // Printed in every page's footer
+	Entries   []PDFEntry
+}
+
+// PDFEntry is one prompt of a PDF export, starting on a new page under a
+// header with its metadata
+type PDFEntry struct {
+	Info    PreviewInfo
+	Content string // Markdown
+}
+
+// Page layout, in points
+const (
+	pdfMargin     = 56
+	pdfFooterY    = 30
+	pdfBodySize   = 10.5
+	pdfCodeSize   = 9
+	pdfLeading    = 1.45 // Line height as a multiple of the font size
+	pdfIndent     = 18   // How far list items and quotes are indented
+	pdfCellMargin = 4
+	contentsLines = 36 // Entries listed on each contents page
+)
+
+// PDF colors, close to those of the HTML preview printed
+var (
+	pdfText   = pdf.Gray(0.12)
+	pdfMuted  = pdf.Gray(0.38)
+	pdfBorder = pdf.Gray(0.8)
+	pdfShade  = pdf.Gray(0.95)
+	pdfAccent = pdf.Color{R: 0.51, G: 0.31, B: 0.87}
+)
+
+// RenderPDF typesets prompts as a print-friendly A4 PDF: each on its own
+// pages under a header with its metadata, its Markdown laid out with
+// headings, lists, quotes, tables and code, and a footer with the export
+// date and page numbers. More than one prompt gets a contents page first.
+func RenderPDF(export PDFExport) ([]byte, error) {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height, pdf.Info{
+		Title:   export.Title,
+		Creator: "Pocket Prompt",
+		Created: export.Generated,
+	})
+	l := &pdfLayout{doc: doc}
+
+	// Contents pages come first, filled in once the entries' pages are known
+	var contentsPages int
+	if len(export.Entries) > 1 {
+		contentsPages = (len(export.Entries) + contentsLines - 1) / contentsLines
+		for i := 0; i < contentsPages; i++ {
+			doc.AddPage()
+		}
+	}
+	starts := make([]int, len(export.Entries))
+	for i, entry := range export.Entries {
+		l.newPage()
+		starts[i] = doc.PageCount()
+		l.header(entry.Info)
+		source := []byte(entry.Content)
+		root := markdown.Parser().Parse(text.NewReader(source))
+		for block := root.FirstChild(); block != nil; block = block.NextSibling() {
+			l.block(block, source)
+		}
+	}
+	if contentsPages > 0 {
+		l.contents(export, starts)
+	}
+	l.footers(export)
+
+	var out bytes.Buffer
+	if _, err := doc.WriteTo(&out); err != nil {
+		return nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// pdfLayout places blocks one under another, starting a page when the next
+// line does not fit
+type pdfLayout struct {
+	doc    *pdf.Document
+	y      float64 // The top of the next line
+	indent float64 // How far blocks are indented from the left margin
+	quotes int     // How many block quotes the current block is in
+	top    bool    // Nothing is on the current page yet
+}
+
+// span is a run of text in one font and color
+type span struct {
+	text  string
+	font  pdf.Font
+	color pdf.Color
+}
+
+// word is a piece of a line that is never broken, unless it is wider than
+// the whole line
+type word struct {
+	span
+	space bool // A space comes before it
+	hard  bool // It starts a new line
+}
+
+func (l *pdfLayout) newPage() {
+	l.doc.AddPage()
+	_, height := l.doc.Size()
+	l.y = height - pdfMargin
+	l.top = true
+}
+
+// left and width are the bounds of the current block
+func (l *pdfLayout) left() float64 {
+	return pdfMargin + l.indent
+}
+
+func (l *pdfLayout) width() float64 {
+	pageWidth, _ := l.doc.Size()
+	return pageWidth - 2*pdfMargin - l.indent
+}
+
+// ensure starts a new page unless height fits above the bottom margin
+func (l *pdfLayout) ensure(height float64) {
+	if l.y-height < pdfMargin && !l.top {
+		l.newPage()
+	}
+}
+
+// space leaves a gap between blocks, except at the top of a page
+func (l *pdfLayout) space(height float64) {
+	if !l.top {
+		l.y -= height
+	}
+}
+
+// header writes an entry's kind, title, description and metadata, ruled
+// off from its content
+func (l *pdfLayout) header(info PreviewInfo) {
+	if info.Kind != "" {
+		l.lines([]span{{strings.ToUpper(info.Kind), pdf.Bold, pdfAccent}}, 8)
+		l.y -= 2
+	}
+	title := info.Title
+	if title == "" {
+		title = "Untitled"
+	}
+	l.lines([]span{{title, pdf.Bold, pdfText}}, 20)
+	if info.Description != "" {
+		l.y -= 2
+		l.lines([]span{{info.Description, pdf.Regular, pdfMuted}}, 11)
+	}
+	var meta []string
+	if info.Version != "" {
+		meta = append(meta, "Version "+info.Version)
+	}
+	if len(info.Tags) > 0 {
+		meta = append(meta, "Tags: "+strings.Join(info.Tags, ", "))
+	}
+	if len(meta) > 0 || len(info.Details) > 0 {
+		l.y -= 4
+	}
+	if len(meta) > 0 {
+		l.lines([]span{{strings.Join(meta, "  ·  "), pdf.Regular, pdfMuted}}, 9)
+	}
+	for _, detail := range info.Details {
+		l.lines([]span{{detail, pdf.Regular, pdfMuted}}, 9)
+	}
+	l.y -= 8
+	l.doc.Line(l.left(), l.y, l.left()+l.width(), l.y, 0.75, pdfBorder)
+	l.y -= 14
+}
+
+// block lays out a block node and its children
+func (l *pdfLayout) block(n ast.Node, source []byte) {
+	switch n := n.(type) {
+	case *ast.Heading:
+		size := map[int]float64{1: 16, 2: 13.5, 3: 12}[n.Level]
+		if size == 0 {
+			size = 11
+		}
+		l.space(size * 0.8)
+		// Keep a heading on the page of the lines under it
+		l.ensure(size*pdfLeading + 2*pdfBodySize*pdfLeading)
+		l.lines(l.inline(n, source, pdf.Bold, pdfText), size)
+		l.y -= 3
+
+	case *ast.Paragraph:
+		l.lines(l.inline(n, source, pdf.Regular, pdfText), pdfBodySize)
+		l.y -= pdfBodySize * 0.6
+
+	case *ast.TextBlock:
+		l.lines(l.inline(n, source, pdf.Regular, pdfText), pdfBodySize)
+
+	case *ast.List:
+		number := n.Start
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "•"
+			if n.IsOrdered() {
+				marker = fmt.Sprintf("%d.", number)
+				number++
+			}
+			l.ensure(pdfBodySize * pdfLeading)
+			l.doc.Text(l.left()+4, l.y-pdfBodySize, pdf.Regular, pdfBodySize, l.color(pdfText), marker)
+			l.indent += pdfIndent
+			for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+				l.block(child, source)
+			}
+			l.indent -= pdfIndent
+			if !n.IsTight {
+				l.y -= pdfBodySize * 0.4
+			}
+		}
+		if l.indent == 0 {
+			l.y -= pdfBodySize * 0.6
+		}
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		l.code(n, source)
+
+	case *ast.Blockquote:
+		l.quotes++
+		l.indent += pdfIndent
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			l.block(child, source)
+		}
+		l.indent -= pdfIndent
+		l.quotes--
+
+	case *ast.ThematicBreak:
+		l.space(6)
+		l.ensure(12)
+		l.doc.Line(l.left(), l.y, l.left()+l.width(), l.y, 0.75, pdfBorder)
+		l.y -= 12
+
+	case *east.Table:
+		l.table(n, source)
+
+	case *ast.HTMLBlock:
+		// Left out, as in the HTML preview
+
+	default:
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			l.block(child, source)
+		}
+	}
+}
+
+// code lays out a code block in a monospaced font on a shaded band,
+// wrapping lines too long for the page
+func (l *pdfLayout) code(n ast.Node, source []byte) {
+	lineHeight := pdfCodeSize * pdfLeading
+	perLine := max(1, int((l.width()-2*pdfCellMargin)/(pdfCodeSize*0.6)))
+	var rows []string
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		line := strings.TrimRight(string(segment.Value(source)), "\n")
+		runes := []rune(strings.ReplaceAll(line, "\t", "    "))
+		for len(runes) > perLine {
+			rows = append(rows, string(runes[:perLine]))
+			runes = runes[perLine:]
+		}
+		rows = append(rows, string(runes))
+	}
+
+	l.y -= 2
+	for i, row := range rows {
+		l.ensure(lineHeight)
+		top, bottom := 0.0, 0.0
+		if i == 0 || l.top {
+			top = pdfCellMargin
+		}
+		if i == len(rows)-1 {
+			bottom = pdfCellMargin
+		}
+		l.doc.Rect(l.left(), l.y-lineHeight-bottom, l.width(), lineHeight+top+bottom, pdfShade)
+		l.quoteBars(lineHeight + top + bottom)
+		l.doc.Text(l.left()+pdfCellMargin, l.y-pdfCodeSize, pdf.Mono, pdfCodeSize, l.color(pdfText), row)
+		l.y -= lineHeight
+		l.top = false
+	}
+	l.y -= pdfCellMargin + pdfBodySize*0.8
+}
+
+// table lays out a table with equal columns, its header row shaded
+func (l *pdfLayout) table(n *east.Table, source []byte) {
+	var columns int
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		columns = max(columns, row.ChildCount())
+	}
+	if columns == 0 {
+		return
+	}
+	columnWidth := l.width() / float64(columns)
+	size := pdfBodySize - 1
+	lineHeight := size * pdfLeading
+
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		_, header := row.(*east.TableHeader)
+		font := pdf.Regular
+		if header {
+			font = pdf.Bold
+		}
+		var cells [][][]word
+		height := 0
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			wrapped := wrap(l.inline(cell, source, font, pdfText), size, columnWidth-2*pdfCellMargin)
+			cells = append(cells, wrapped)
+			height = max(height, len(wrapped))
+		}
+		rowHeight := float64(height)*lineHeight + 2*pdfCellMargin
+		l.ensure(rowHeight)
+		if header {
+			l.doc.Rect(l.left(), l.y-rowHeight, l.width(), rowHeight, pdfShade)
+		}
+		for i, lines := range cells {
+			x := l.left() + float64(i)*columnWidth + pdfCellMargin
+			for j, line := range lines {
+				l.drawWords(line, x, l.y-pdfCellMargin-float64(j)*lineHeight-size, size)
+			}
+		}
+		l.y -= rowHeight
+		l.doc.Line(l.left(), l.y, l.left()+l.width(), l.y, 0.5, pdfBorder)
+		l.top = false
+	}
+	l.y -= pdfBodySize * 0.8
+}
+
+// inline collects the text of an inline node's children in spans, styled
+// by their emphasis
+func (l *pdfLayout) inline(n ast.Node, source []byte, font pdf.Font, color pdf.Color) []span {
+	var spans []span
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child := child.(type) {
+		case *ast.Text:
+			spans = append(spans, span{string(child.Segment.Value(source)), font, color})
+			if child.HardLineBreak() {
+				spans = append(spans, span{"\n", font, color})
+			} else if child.SoftLineBreak() {
+				spans = append(spans, span{" ", font, color})
+			}
+		case *ast.String:
+			spans = append(spans, span{string(child.Value), font, color})
+		case *ast.Emphasis:
+			spans = append(spans, l.inline(child, source, emphasize(font, child.Level), color)...)
+		case *ast.CodeSpan:
+			mono := pdf.Mono
+			if font == pdf.Bold || font == pdf.BoldItalic {
+				mono = pdf.MonoBold
+			}
+			spans = append(spans, l.inline(child, source, mono, color)...)
+		case *ast.Link:
+			spans = append(spans, l.inline(child, source, font, color)...)
+			// Print the address, since a printed link cannot be followed
+			if destination := string(child.Destination); destination != "" && !strings.HasPrefix(destination, "#") {
+				spans = append(spans, span{" (" + destination + ")", pdf.Regular, pdfMuted})
+			}
+		case *ast.AutoLink:
+			spans = append(spans, span{string(child.URL(source)), font, color})
+		case *ast.Image:
+			alt := spanText(l.inline(child, source, font, color))
+			if alt == "" {
+				alt = string(child.Destination)
+			}
+			spans = append(spans, span{"[Image: " + alt + "]", pdf.Italic, pdfMuted})
+		case *east.TaskCheckBox:
+			box := "[ ] "
+			if child.IsChecked {
+				box = "[x] "
+			}
+			spans = append(spans, span{box, pdf.Mono, color})
+		case *ast.RawHTML:
+			// Left out, as in the HTML preview
+		default:
+			spans = append(spans, l.inline(child, source, font, color)...)
+		}
+	}
+	return spans
+}
+
+// emphasize returns font in italics for level 1 emphasis, bold for level 2
+func emphasize(font pdf.Font, level int) pdf.Font {
+	switch {
+	case font == pdf.Mono || font == pdf.MonoBold:
+		return pdf.MonoBold
+	case level >= 2 && (font == pdf.Italic || font == pdf.BoldItalic):
+		return pdf.BoldItalic
+	case level >= 2:
+		return pdf.Bold
+	case font == pdf.Bold || font == pdf.BoldItalic:
+		return pdf.BoldItalic
+	default:
+		return pdf.Italic
+	}
+}
+
+// spanText returns the text of spans without their styles
+func spanText(spans []span) string {
+	var b strings.Builder
+	for _, s := range spans {
+		b.WriteString(s.text)
+	}
+	return b.String()
+}
+
+// lines writes spans wrapped to the current width, one line under another
+func (l *pdfLayout) lines(spans []span, size float64) {
+	lineHeight := size * pdfLeading
+	for _, line := range wrap(spans, size, l.width()) {
+		l.ensure(lineHeight)
+		l.quoteBars(lineHeight)
+		l.drawWords(line, l.left(), l.y-size, size)
+		l.y -= lineHeight
+		l.top = false
+	}
+}
+
+// drawWords sets one wrapped line with its first word at x, words of the
+// same style together
+func (l *pdfLayout) drawWords(line []word, x, baseline, size float64) {
+	for len(line) > 0 {
+		run := line[0].text
+		n := 1
+		for n < len(line) && line[n].font == line[0].font && line[n].color == line[0].color {
+			if line[n].space {
+				run += " "
+			}
+			run += line[n].text
+			n++
+		}
+		l.doc.Text(x, baseline, line[0].font, size, l.color(line[0].color), run)
+		x += pdf.TextWidth(run, line[0].font, size)
+		if n < len(line) && line[n].space {
+			x += pdf.TextWidth(" ", line[n].font, size)
+		}
+		line = line[n:]
+	}
+}
+
+// quoteBars draws the bars left of a line in block quotes
+func (l *pdfLayout) quoteBars(height float64) {
+	for i := 0; i < l.quotes; i++ {
+		x := pdfMargin + l.indent - float64(l.quotes-i)*pdfIndent + 4
+		l.doc.Rect(x, l.y-height, 2, height, pdfBorder)
+	}
+}
+
+// color mutes text in block quotes
+func (l *pdfLayout) color(c pdf.Color) pdf.Color {
+	if l.quotes > 0 && c == pdfText {
+		return pdfMuted
+	}
+	return c
+}
+
+// wrap breaks spans into lines no wider than width, between words where it
+// can and inside words wider than a whole line
+func wrap(spans []span, size, width float64) [][]word {
+	var words []word
+	space, hard := false, false
+	for _, s := range spans {
+		if s.text == "\n" {
+			hard = true
+			continue
+		}
+		fields := strings.FieldsFunc(s.text, func(r rune) bool { return r == ' ' || r == '\n' || r == '\t' })
+		leading := len(s.text) > 0 && strings.ContainsRune(" \n\t", rune(s.text[0]))
+		for i, field := range fields {
+			words = append(words, word{span{field, s.font, s.color}, space || leading || i > 0, hard})
+			space, hard, leading = false, false, false
+		}
+		if len(s.text) > 0 && strings.ContainsRune(" \n\t", rune(s.text[len(s.text)-1])) {
+			space = true
+		}
+	}
+
+	var lines [][]word
+	var line []word
+	var lineWidth float64
+	for _, w := range words {
+		wordWidth := pdf.TextWidth(w.text, w.font, size)
+		gap := 0.0
+		if w.space && len(line) > 0 {
+			gap = pdf.TextWidth(" ", w.font, size)
+		}
+		if len(line) > 0 && (w.hard || lineWidth+gap+wordWidth > width) {
+			lines = append(lines, line)
+			line, lineWidth, gap = nil, 0, 0
+		}
+		// Break a word wider than the line, such as a long URL
+		for wordWidth > width && len([]rune(w.text)) > 1 {
+			runes := []rune(w.text)
+			fit := 1
+			for fit < len(runes) && pdf.TextWidth(string(runes[:fit+1]), w.font, size) <= width-lineWidth-gap {
+				fit++
+			}
+			piece := w
+			piece.text = string(runes[:fit])
+			lines = append(lines, append(line, piece))
+			line, lineWidth, gap = nil, 0, 0
+			w.text, w.space = string(runes[fit:]), false
+			wordWidth = pdf.TextWidth(w.text, w.font, size)
+		}
+		line = append(line, w)
+		lineWidth += gap + wordWidth
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// contents lists the entries and the pages they start on in the pages
+// reserved at the start of the document
+func (l *pdfLayout) contents(export PDFExport, starts []int) {
+	pageWidth, height := l.doc.Size()
+	right := pageWidth - pdfMargin
+	for i, entry := range export.Entries {
+		if i%contentsLines == 0 {
+			l.doc.SetPage(i / contentsLines)
+			l.y, l.top = height-pdfMargin, true
+			if i == 0 {
+				title := export.Title
+				if title == "" {
+					title = "Prompts"
+				}
+				l.lines([]span{{title, pdf.Bold, pdfText}}, 20)
+				l.lines([]span{{fmt.Sprintf("%d prompts", len(export.Entries)), pdf.Regular, pdfMuted}}, 11)
+				l.y -= 14
+			}
+		}
+		title := entry.Info.Title
+		if title == "" {
+			title = "Untitled"
+		}
+		page := fmt.Sprint(starts[i])
+		pageX := right - pdf.TextWidth(page, pdf.Regular, pdfBodySize)
+		title = fit(title, pdf.Regular, pdfBodySize, pageX-pdfMargin-12)
+		baseline := l.y - pdfBodySize
+		l.doc.Text(pdfMargin, baseline, pdf.Regular, pdfBodySize, pdfText, title)
+		l.doc.Text(pageX, baseline, pdf.Regular, pdfBodySize, pdfMuted, page)
+		l.y -= pdfBodySize * 1.9
+	}
+}
+
+// fit shortens text with "…" until it is no wider than width
+func fit(text string, font pdf.Font, size, width float64) string {
+	runes := []rune(text)
+	for len(runes) > 1 && pdf.TextWidth(string(runes), font, size) > width {
+		runes = append(runes[:len(runes)-2], '…')
+	}
+	return string(runes)
+}
+
+// footers writes the export's date and the page number at the foot of
+// every page
+func (l *pdfLayout) footers(export PDFExport) {
+	pageWidth, _ := l.doc.Size()
+	left := "Pocket Prompt"
+	if export.Title != "" {
+		left = export.Title
+	}
+	if !export.Generated.IsZero() {
+		left += "  ·  Exported " + export.Generated.Format("2006-01-02 15:04")
+	}
+	total := l.doc.PageCount()
+	for i := 0; i < total; i++ {
+		l.doc.SetPage(i)
+		number := fmt.Sprintf("Page %d of %d", i+1, total)
+		l.doc.Text(pdfMargin, pdfFooterY, pdf.Regular, 8, pdfMuted, left)
+		l.doc.Text(pageWidth-pdfMargin-pdf.TextWidth(number, pdf.Regular, 8), pdfFooterY, pdf.Regular, 8, pdfMuted, number)
+	}
+}
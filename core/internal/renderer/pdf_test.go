@@ -0,0 +1,122 @@
+package renderer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/pdf"
+)
+
+func TestRenderPDF(t *testing.T) {
+	long := "# Steps\n\n" + strings.Repeat("A paragraph long enough to wrap across the page more than once, so that the prompt fills several pages of the export.\n\n", 40)
+	export := PDFExport{
+		Title:     "Legal prompts",
+		Generated: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Entries: []PDFEntry{
+			{Info: PreviewInfo{Kind: "Prompt", Title: "Contract Review", Version: "1.2.0", Tags: []string{"legal"}, Details: []string{"ID: contract-review"}}, Content: long},
+			{Info: PreviewInfo{Kind: "Prompt", Title: "NDA"}, Content: "Check **every** clause in `{{nda}}`:\n\n- [x] parties\n- term\n\n| Clause | Risk |\n| --- | --- |\n| 4.2 | high |\n\n```\nquote(\"term\")\n```"},
+		},
+	}
+	data, err := RenderPDF(export)
+	if err != nil {
+		t.Fatalf("RenderPDF: %v", err)
+	}
+	pages := pdfPages(t, data)
+	if len(pages) < 4 {
+		t.Fatalf("got %d pages, want a contents page and the long prompt over several", len(pages))
+	}
+	last := len(pages)
+
+	// The contents page lists each prompt with the page it starts on
+	for _, want := range []string{"(Legal prompts)", "(Contract Review)", "(2)", "(NDA)", fmt.Sprintf("(%d)", last)} {
+		if !strings.Contains(pages[0], want) {
+			t.Errorf("contents page lacks %s:\n%s", want, pages[0])
+		}
+	}
+	for i, page := range pages {
+		if want := fmt.Sprintf("(Page %d of %d)", i+1, last); !strings.Contains(page, want) {
+			t.Errorf("page %d lacks %s", i+1, want)
+		}
+		if !strings.Contains(page, "(Legal prompts  \xb7  Exported 2026-10-16 09:30)") {
+			t.Errorf("page %d lacks the footer", i+1)
+		}
+	}
+	for _, want := range []string{"(PROMPT)", "/F2 20 Tf 56 752.29 Td (Contract Review)", "(Version 1.2.0 \xb7 Tags: legal)", "(ID: contract-review)"} {
+		if !strings.Contains(pages[1], want) {
+			t.Errorf("first prompt's page lacks %s:\n%s", want, pages[1])
+		}
+	}
+	nda := pages[last-1]
+	for _, want := range []string{"/F2 10.5 Tf", "(every)", "/F5 10.5 Tf", "({{nda}})", "(\x95)", "([x])", "/F2 9.5 Tf", "(Clause)", "(4.2)", "/F5 9 Tf", `(quote\("term"\))`} {
+		if !strings.Contains(nda, want) {
+			t.Errorf("NDA page lacks %s:\n%s", want, nda)
+		}
+	}
+
+	// A single prompt has no contents page
+	single, err := RenderPDF(PDFExport{Entries: export.Entries[1:]})
+	if err != nil {
+		t.Fatalf("RenderPDF: %v", err)
+	}
+	if pages := pdfPages(t, single); len(pages) != 1 || !strings.Contains(pages[0], "(NDA)") {
+		t.Errorf("single prompt gave %d pages", len(pages))
+	}
+}
+
+func TestWrap(t *testing.T) {
+	spans := []span{
+		{"Read the ", pdf.Regular, pdfText},
+		{"whole", pdf.Bold, pdfText},
+		{" contract\nnow https://example.com/a-very-long-address-that-cannot-fit", pdf.Regular, pdfText},
+	}
+	var got []string
+	for _, line := range wrap(spans, 10, 120) {
+		var words []string
+		for _, w := range line {
+			words = append(words, w.text)
+		}
+		got = append(got, strings.Join(words, "|"))
+		if width := lineWidth(line, 10); width > 120 {
+			t.Errorf("line %q is %.1f wide, over 120", words, width)
+		}
+	}
+	// The address is wider than a line, so it starts one and is broken
+	if len(got) < 4 || got[0] != "Read|the|whole|contract" || got[1] != "now" || !strings.HasPrefix(got[2], "https://") {
+		t.Errorf("wrap = %q", got)
+	}
+}
+
+// lineWidth measures a wrapped line as drawWords sets it
+func lineWidth(line []word, size float64) float64 {
+	var width float64
+	for i, w := range line {
+		if w.space && i > 0 {
+			width += pdf.TextWidth(" ", w.font, size)
+		}
+		width += pdf.TextWidth(w.text, w.font, size)
+	}
+	return width
+}
+
+// pdfPages returns the inflated content stream of each page of a PDF
+func pdfPages(t *testing.T, data []byte) []string {
+	t.Helper()
+	var pages []string
+	for _, match := range regexp.MustCompile(`/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		r, err := zlib.NewReader(bytes.NewReader(data[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatalf("content stream: %v", err)
+		}
+		content, _ := io.ReadAll(r)
+		pages = append(pages, string(content))
+	}
+	return pages
+}
@@ -13,6 +13,16 @@ import (
 type Renderer struct {
 	prompt   *models.Prompt
 	template *models.Template
+
+	assetDir      string // Directory relative image paths are resolved against
+	imageEncoding string // How RenderJSON includes images: ImageBase64 or ImagePath
+
+	schemaName   string                 // Name the output schema is sent under
+	outputSchema map[string]interface{} // Expected response shape, sent as response_format
+
+	redact func(string) string // Applied to the rendered text, if set
+
+	context *Context // Fills context placeholders such as {{git.branch}}, if set
 }
 
 // NewRenderer creates a new renderer instance
@@ -23,27 +33,84 @@ func NewRenderer(prompt *models.Prompt, tmpl *models.Template) *Renderer {
 	}
 }
 
-// RenderText renders the prompt as plain text
-func (r *Renderer) RenderText(_ map[string]interface{}) (string, error) {
+// SetAssetDir sets the directory relative image paths are resolved against,
+// normally the library root
+func (r *Renderer) SetAssetDir(dir string) {
+	r.assetDir = dir
+}
+
+// SetImageEncoding chooses how RenderJSON includes images: ImageBase64 (the
+// default) or ImagePath
+func (r *Renderer) SetImageEncoding(encoding string) error {
+	switch encoding {
+	case "", ImageBase64, ImagePath:
+		r.imageEncoding = encoding
+		return nil
+	default:
+		return fmt.Errorf("unsupported image encoding %q (expected %s or %s)", encoding, ImageBase64, ImagePath)
+	}
+}
+
+// SetOutputSchema makes RenderJSON request structured output following schema,
+// a JSON Schema document. A nil schema renders a plain message array.
+func (r *Renderer) SetOutputSchema(name string, schema map[string]interface{}) {
+	r.schemaName = name
+	r.outputSchema = schema
+}
+
+// SetRedactor sets a function applied to the rendered text before images are
+// embedded, such as redact.Redactor.String
+func (r *Renderer) SetRedactor(redact func(string) string) {
+	r.redact = redact
+}
+
+// SetContext fills context placeholders such as {{cwd}}, {{git.branch}} and
+// {{file:path}} from ctx. Without a context they are left as they are.
+func (r *Renderer) SetContext(ctx *Context) {
+	r.context = ctx
+}
+
+// RenderText renders the prompt as plain text, filling in {{name}}
+// placeholders from variables. Image placeholders become [image: path]
+// markers, since plain text cannot carry the images themselves.
+func (r *Renderer) RenderText(variables map[string]interface{}) (string, error) {
+	content, err := r.renderContent(variables)
+	if err != nil {
+		return "", err
+	}
+	return r.markImages(content), nil
+}
+
+// renderContent fills in variables and applies the template, leaving image
+// placeholders in place
+func (r *Renderer) renderContent(variables map[string]interface{}) (string, error) {
 	// Start with the prompt content
-	content := r.prompt.Content
+	content, err := substituteVariables(r.prompt.Content, variables, r.context)
+	if err != nil {
+		return "", err
+	}
 
 	// If there's a template, apply it first
 	if r.template != nil {
-		templateContent, err := r.applyTemplate(content)
+		templateContent, err := r.applyTemplate(content, variables)
 		if err != nil {
 			return "", fmt.Errorf("failed to apply template: %w", err)
 		}
 		content = templateContent
 	}
 
+	if r.redact != nil {
+		content = r.redact(content)
+	}
 	return content, nil
 }
 
-// RenderJSON renders the prompt as a JSON message array for LLM APIs
-func (r *Renderer) RenderJSON(_ map[string]interface{}) (string, error) {
-	// First render as text
-	text, err := r.RenderText(nil)
+// RenderJSON renders the prompt as a JSON message array for LLM APIs. With an
+// output schema it renders a request body instead, holding the messages and a
+// response_format block. Variables are filled in as for RenderText.
+func (r *Renderer) RenderJSON(variables map[string]interface{}) (string, error) {
+	// First render the content, keeping image placeholders for the parts
+	text, err := r.renderContent(variables)
 	if err != nil {
 		return "", err
 	}
@@ -55,9 +122,21 @@ func (r *Renderer) RenderJSON(_ map[string]interface{}) (string, error) {
 			Content: text,
 		},
 	}
+	if r.hasImages(text) {
+		parts, err := r.contentParts(text)
+		if err != nil {
+			return "", err
+		}
+		messages[0].Content = parts
+	}
+
+	var body interface{} = messages
+	if r.outputSchema != nil {
+		body = Request{Messages: messages, ResponseFormat: r.responseFormat()}
+	}
 
 	// Marshal to JSON
-	jsonBytes, err := json.MarshalIndent(messages, "", "  ")
+	jsonBytes, err := json.MarshalIndent(body, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal to JSON: %w", err)
 	}
@@ -65,14 +144,16 @@ func (r *Renderer) RenderJSON(_ map[string]interface{}) (string, error) {
 	return string(jsonBytes), nil
 }
 
-// Message represents a chat message for LLM APIs
+// Message represents a chat message for LLM APIs. Content is a string, or a
+// []ContentPart when the prompt includes images.
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
 }
 
-// applyTemplate applies a template to the prompt content
-func (r *Renderer) applyTemplate(content string) (string, error) {
+// applyTemplate applies a template to the prompt content. Variables fill the
+// template's slots, overriding slot defaults.
+func (r *Renderer) applyTemplate(content string, variables map[string]interface{}) (string, error) {
 	if r.template == nil {
 		return content, nil
 	}
@@ -83,15 +164,18 @@ func (r *Renderer) applyTemplate(content string) (string, error) {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	// Prepare template data with defaults only
+	// Prepare template data from slot defaults and variables
 	data := make(map[string]interface{})
 	
-	// Add default slot values only
+	// Add default slot values
 	for _, slot := range r.template.Slots {
 		if slot.Default != "" {
 			data[slot.Name] = slot.Default
 		}
 	}
+	for name, value := range variables {
+		data[name] = value
+	}
 
 	// Add the prompt content as a special "content" slot
 	data["content"] = content
@@ -0,0 +1,37 @@
+package renderer
+
+import "regexp"
+
+// Request is a chat request body carrying messages and, for prompts with an
+// output schema, the structured output format the response should follow
+type Request struct {
+	Messages       []Message       `json:"messages"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat asks for a response matching a JSON Schema, in the format
+// used by OpenAI-compatible chat APIs
+type ResponseFormat struct {
+	Type       string     `json:"type"` // Always "json_schema"
+	JSONSchema JSONSchema `json:"json_schema"`
+}
+
+// JSONSchema names a schema inside a response_format block
+type JSONSchema struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// invalidSchemaName matches characters APIs reject in schema names
+var invalidSchemaName = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func (r *Renderer) responseFormat() *ResponseFormat {
+	name := invalidSchemaName.ReplaceAllString(r.schemaName, "_")
+	if name == "" {
+		name = "output"
+	}
+	return &ResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: JSONSchema{Name: name, Schema: r.outputSchema},
+	}
+}
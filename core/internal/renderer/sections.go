@@ -0,0 +1,75 @@
+package renderer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// headingPattern matches a Markdown ATX heading, capturing its level and text
+// without any closing #s
+var headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+
+// Section is the part of a Markdown document from a heading to the next
+// heading of the same or a higher level
+type Section struct {
+	Title string // The heading's text
+	Level int    // 1 for #, 2 for ## and so on
+	Line  int    // The heading's line in the document, from 0
+	Text  string // The heading and everything under it, subsections included
+}
+
+// Sections returns the sections of a Markdown document in order. Headings in
+// code fences are not headings, and text before the first heading is in no
+// section.
+func Sections(markdown string) []Section {
+	lines := strings.Split(markdown, "\n")
+	var sections []Section
+	var fence string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if match := headingPattern.FindStringSubmatch(line); match != nil && strings.TrimSpace(match[2]) != "" {
+			sections = append(sections, Section{Title: strings.TrimSpace(match[2]), Level: len(match[1]), Line: i})
+		}
+	}
+
+	for i := range sections {
+		end := len(lines)
+		for _, next := range sections[i+1:] {
+			if next.Level <= sections[i].Level {
+				end = next.Line
+				break
+			}
+		}
+		sections[i].Text = strings.TrimRight(strings.Join(lines[sections[i].Line:end], "\n"), " \t\n")
+	}
+	return sections
+}
+
+// FindSection returns the section of a Markdown document with the given
+// title, compared without regard to case, or an error naming the sections
+// there are
+func FindSection(markdown, title string) (Section, error) {
+	sections := Sections(markdown)
+	if len(sections) == 0 {
+		return Section{}, fmt.Errorf("no section %q: the prompt has no headings", title)
+	}
+	titles := make([]string, len(sections))
+	for i, section := range sections {
+		if strings.EqualFold(section.Title, strings.TrimSpace(title)) {
+			return section, nil
+		}
+		titles[i] = fmt.Sprintf("%q", section.Title)
+	}
+	return Section{}, fmt.Errorf("no section %q (sections: %s)", title, strings.Join(titles, ", "))
+}
@@ -0,0 +1,36 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSections(t *testing.T) {
+	markdown := "Intro text.\n\n# Task\nReview the code.\n\n## Checks ##\n- tests\n\n```sh\n# not a heading\n```\n\n# Output format\nA table.\n\n#hashtag\n"
+
+	sections := Sections(markdown)
+	want := []Section{
+		{Title: "Task", Level: 1, Line: 2, Text: "# Task\nReview the code.\n\n## Checks ##\n- tests\n\n```sh\n# not a heading\n```"},
+		{Title: "Checks", Level: 2, Line: 5, Text: "## Checks ##\n- tests\n\n```sh\n# not a heading\n```"},
+		{Title: "Output format", Level: 1, Line: 12, Text: "# Output format\nA table.\n\n#hashtag"},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("Sections = %+v, want %d sections", sections, len(want))
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Errorf("section %d = %+v, want %+v", i, sections[i], want[i])
+		}
+	}
+
+	section, err := FindSection(markdown, " output FORMAT ")
+	if err != nil || section.Title != "Output format" {
+		t.Errorf("FindSection = %+v, %v", section, err)
+	}
+	if _, err := FindSection(markdown, "Examples"); err == nil || !strings.Contains(err.Error(), `"Task", "Checks", "Output format"`) {
+		t.Errorf("FindSection of a missing section = %v, want the sections listed", err)
+	}
+	if _, err := FindSection("No headings here.", "Task"); err == nil {
+		t.Error("FindSection without headings succeeded")
+	}
+}
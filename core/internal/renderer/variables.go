@@ -0,0 +1,59 @@
+package renderer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// variablePlaceholder matches {{name}} placeholders, along with the context
+// placeholders {{git.branch}}, {{file:path}} and {{cmd:command}}. Image
+// references such as {{image:chart.png}} and template actions such as
+// {{.content}} do not match.
+var variablePlaceholder = regexp.MustCompile(`\{\{\s*((?:file|cmd):(?:[^}]|\}[^}])+?|[A-Za-z_][\w.-]*)\s*\}\}`)
+
+// substituteVariables replaces each placeholder that names a variable with its
+// value, then each context placeholder with its value when live is set.
+// Placeholders without a value are left as they are.
+func substituteVariables(content string, variables map[string]interface{}, live *Context) (string, error) {
+	if len(variables) == 0 && live == nil {
+		return content, nil
+	}
+	var failed error
+	content = variablePlaceholder.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := variablePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return variableText(value)
+		}
+		if live == nil || failed != nil {
+			return placeholder
+		}
+		value, ok, err := live.resolve(name)
+		if err != nil {
+			failed = err
+		}
+		if !ok || err != nil {
+			return placeholder
+		}
+		return value
+	})
+	return content, failed
+}
+
+// variableText formats a variable value, joining lists with commas
+func variableText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = variableText(item)
+		}
+		return strings.Join(items, ", ")
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
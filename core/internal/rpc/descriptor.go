@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/structpb" // registers google/protobuf/struct.proto
+)
+
+// ServiceName is the fully qualified gRPC service name
+const ServiceName = "pocketprompt.v1.CommandService"
+
+// protoFile mirrors proto/pocketprompt/v1/commands.proto. It is built here
+// rather than generated so the module needs no protoc step; keep the two in sync.
+func protoFile() *descriptorpb.FileDescriptorProto {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptorpb.FieldDescriptorProto {
+		label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(jsonName(name)),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	boolean := descriptorpb.FieldDescriptorProto_TYPE_BOOL
+
+	message := func(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+	}
+	method := func(name, in, out string, streaming bool) *descriptorpb.MethodDescriptorProto {
+		m := &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(".pocketprompt.v1." + in),
+			OutputType: proto.String(".pocketprompt.v1." + out),
+		}
+		if streaming {
+			m.ClientStreaming = proto.Bool(true)
+			m.ServerStreaming = proto.Bool(true)
+		}
+		return m
+	}
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("pocketprompt/v1/commands.proto"),
+		Package:    proto.String("pocketprompt.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/struct.proto"},
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/dpshade/pocket-prompt/proto/pocketprompt/v1;pocketpromptv1"),
+		},
+		MessageType: []*descriptorpb.DescriptorProto{
+			message("ExecuteRequest",
+				field("command", 1, str, "", false),
+				field("params", 2, msg, ".google.protobuf.Struct", false),
+				field("request_id", 3, str, "", false),
+			),
+			message("ExecuteResponse",
+				field("success", 1, boolean, "", false),
+				field("message", 2, str, "", false),
+				field("data", 3, msg, ".google.protobuf.Value", false),
+				field("error", 4, msg, ".pocketprompt.v1.Error", false),
+				field("request_id", 5, str, "", false),
+			),
+			message("Error",
+				field("code", 1, str, "", false),
+				field("message", 2, str, "", false),
+				field("details", 3, str, "", false),
+				field("category", 4, str, "", false),
+				field("severity", 5, str, "", false),
+			),
+			message("ListCommandsRequest"),
+			message("ListCommandsResponse",
+				field("commands", 1, msg, ".pocketprompt.v1.CommandInfo", true),
+			),
+			message("CommandInfo",
+				field("name", 1, str, "", false),
+				field("description", 2, str, "", false),
+			),
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("CommandService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("Execute", "ExecuteRequest", "ExecuteResponse", false),
+				method("ExecuteStream", "ExecuteRequest", "ExecuteResponse", true),
+				method("ListCommands", "ListCommandsRequest", "ListCommandsResponse", false),
+			},
+		}},
+	}
+}
+
+// jsonName converts a snake_case field name to its lowerCamelCase JSON name
+func jsonName(name string) string {
+	out := make([]byte, 0, len(name))
+	upper := false
+	for i := 0; i < len(name); i++ {
+		if name[i] == '_' {
+			upper = true
+			continue
+		}
+		c := name[i]
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+// descriptors holds the message types used by the service
+type descriptors struct {
+	file                 protoreflect.FileDescriptor
+	executeRequest       protoreflect.MessageDescriptor
+	executeResponse      protoreflect.MessageDescriptor
+	listCommandsRequest  protoreflect.MessageDescriptor
+	listCommandsResponse protoreflect.MessageDescriptor
+}
+
+// loadDescriptors builds the file descriptor and registers it globally so
+// server reflection can describe the service to clients
+func loadDescriptors() (*descriptors, error) {
+	if existing, err := protoregistry.GlobalFiles.FindFileByPath("pocketprompt/v1/commands.proto"); err == nil {
+		return newDescriptors(existing), nil
+	}
+
+	file, err := protodesc.NewFile(protoFile(), protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service descriptor: %w", err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(file); err != nil {
+		return nil, fmt.Errorf("failed to register service descriptor: %w", err)
+	}
+	return newDescriptors(file), nil
+}
+
+func newDescriptors(file protoreflect.FileDescriptor) *descriptors {
+	messages := file.Messages()
+	return &descriptors{
+		file:                 file,
+		executeRequest:       messages.ByName("ExecuteRequest"),
+		executeResponse:      messages.ByName("ExecuteResponse"),
+		listCommandsRequest:  messages.ByName("ListCommandsRequest"),
+		listCommandsResponse: messages.ByName("ListCommandsResponse"),
+	}
+}
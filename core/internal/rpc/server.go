@@ -0,0 +1,217 @@
+// Package rpc serves the unified command system over gRPC.
+//
+// The service is defined in proto/pocketprompt/v1/commands.proto. Requests
+// carry a command name and JSON-like params, exactly as the HTTP API passes
+// them to commands.CommandExecutor, so both interfaces behave identically.
+// Messages are handled with dynamicpb, so no generated code is needed.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// Server exposes the command executor as a gRPC service
+type Server struct {
+	executor *commands.CommandExecutor
+	keys     *config.Watcher
+	desc     *descriptors
+	grpc     *grpc.Server
+}
+
+// NewServer creates a gRPC server backed by svc
+func NewServer(svc *service.Service) (*Server, error) {
+	desc, err := loadDescriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		executor: commands.NewCommandExecutor(svc),
+		keys:     config.NewWatcher(svc.GetBaseDir(), svc.Settings()),
+		desc:     desc,
+	}
+
+	s.grpc = grpc.NewServer()
+	s.grpc.RegisterService(s.serviceDesc(), s)
+	reflection.Register(s.grpc)
+
+	return s, nil
+}
+
+// Serve accepts connections on lis until Stop is called
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Stop gracefully stops the server
+func (s *Server) Stop() {
+	s.grpc.GracefulStop()
+}
+
+// commandService is the handler type registered with grpc
+type commandService interface {
+	execute(ctx context.Context, req *dynamicpb.Message) (*dynamicpb.Message, error)
+}
+
+func (s *Server) serviceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: ServiceName,
+		HandlerType: (*commandService)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Execute",
+				Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					req := dynamicpb.NewMessage(s.desc.executeRequest)
+					if err := dec(req); err != nil {
+						return nil, err
+					}
+					return s.execute(ctx, req)
+				},
+			},
+			{
+				MethodName: "ListCommands",
+				Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					if err := dec(dynamicpb.NewMessage(s.desc.listCommandsRequest)); err != nil {
+						return nil, err
+					}
+					if _, err := s.authorize(ctx, "list"); err != nil {
+						return nil, err
+					}
+					return s.toMessage(s.desc.listCommandsResponse, map[string]interface{}{
+						"commands": s.executor.Commands(),
+					})
+				},
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "ExecuteStream",
+				ClientStreams: true,
+				ServerStreams: true,
+				Handler: func(_ interface{}, stream grpc.ServerStream) error {
+					for {
+						req := dynamicpb.NewMessage(s.desc.executeRequest)
+						if err := stream.RecvMsg(req); err != nil {
+							if err == io.EOF {
+								return nil
+							}
+							return err
+						}
+						resp, err := s.execute(stream.Context(), req)
+						if err != nil {
+							return err
+						}
+						if err := stream.SendMsg(resp); err != nil {
+							return err
+						}
+					}
+				},
+			},
+		},
+		Metadata: "pocketprompt/v1/commands.proto",
+	}
+}
+
+// executeRequest is the JSON form of ExecuteRequest
+type executeRequest struct {
+	Command   string                 `json:"command"`
+	Params    map[string]interface{} `json:"params"`
+	RequestID string                 `json:"requestId"`
+}
+
+// execute runs one ExecuteRequest through the command executor
+func (s *Server) execute(ctx context.Context, msg *dynamicpb.Message) (*dynamicpb.Message, error) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	var req executeRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	if req.Command == "" {
+		return nil, status.Error(codes.InvalidArgument, "command is required")
+	}
+
+	if _, err := s.authorize(ctx, req.Command); err != nil {
+		return nil, err
+	}
+
+	result, err := s.executor.Execute(ctx, req.Command, req.Params)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return s.toMessage(s.desc.executeResponse, map[string]interface{}{
+		"success":    result.Success,
+		"message":    result.Message,
+		"data":       result.Data,
+		"error":      result.Error,
+		"request_id": req.RequestID,
+	})
+}
+
+// authorize checks the caller's API key against the scope the command needs.
+// Like the HTTP API, no key is required until one has been configured.
+func (s *Server) authorize(ctx context.Context, command string) (*config.APIKey, error) {
+	cfg := s.keys.Current()
+	if len(cfg.Server.APIKeys) == 0 {
+		return nil, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var plaintext string
+	if values := md.Get("authorization"); len(values) > 0 {
+		plaintext = strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+	} else if values := md.Get("x-api-key"); len(values) > 0 {
+		plaintext = strings.TrimSpace(values[0])
+	}
+	if plaintext == "" {
+		return nil, status.Error(codes.Unauthenticated, "API key required")
+	}
+
+	key := cfg.LookupAPIKey(plaintext)
+	if key == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+
+	required := config.ScopeWrite
+	if commands.IsReadOnly(command) {
+		required = config.ScopeRead
+	}
+	if !key.Allows(required) {
+		return nil, status.Errorf(codes.PermissionDenied, "API key %s lacks the %s scope", key.Name, required)
+	}
+	return key, nil
+}
+
+// toMessage fills a dynamic message of type desc from a JSON-encodable value
+func (s *Server) toMessage(desc protoreflect.MessageDescriptor, value interface{}) (*dynamicpb.Message, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode response: %v", err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,104 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestExecuteOverGRPC(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-rpc-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := service.NewServiceWithDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := svc.InitLibrary(); err != nil {
+		t.Fatalf("Failed to init library: %v", err)
+	}
+
+	srv, err := NewServer(svc)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	newRequest := func(body string) *dynamicpb.Message {
+		req := dynamicpb.NewMessage(srv.desc.executeRequest)
+		if err := protojson.Unmarshal([]byte(body), req); err != nil {
+			t.Fatalf("Failed to build request: %v", err)
+		}
+		return req
+	}
+
+	resp := dynamicpb.NewMessage(srv.desc.executeResponse)
+	create := newRequest(`{"command": "create", "params": {"id": "greeting", "name": "Greeting", "content": "Hello there"}}`)
+	if err := conn.Invoke(ctx, "/"+ServiceName+"/Execute", create, resp); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !resp.Get(srv.desc.executeResponse.Fields().ByName("success")).Bool() {
+		out, _ := protojson.Marshal(resp)
+		t.Fatalf("create did not succeed: %s", out)
+	}
+
+	// Stream a get followed by an unknown command; each gets a tagged response
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, "/"+ServiceName+"/ExecuteStream")
+	if err != nil {
+		t.Fatalf("ExecuteStream failed: %v", err)
+	}
+	stream.SendMsg(newRequest(`{"command": "get", "params": {"id": "greeting"}, "request_id": "1"}`))
+	stream.SendMsg(newRequest(`{"command": "nope", "request_id": "2"}`))
+	stream.CloseSend()
+
+	var results []map[string]interface{}
+	for {
+		msg := dynamicpb.NewMessage(srv.desc.executeResponse)
+		if err := stream.RecvMsg(msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			t.Fatalf("RecvMsg failed: %v", err)
+		}
+		out, _ := protojson.Marshal(msg)
+		results = append(results, map[string]interface{}{"json": string(out), "success": msg.Get(srv.desc.executeResponse.Fields().ByName("success")).Bool()})
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 streamed responses, got %d", len(results))
+	}
+	if results[0]["success"] != true || !strings.Contains(results[0]["json"].(string), "Hello there") {
+		t.Errorf("expected get to return the prompt: %v", results[0]["json"])
+	}
+	if results[1]["success"] != false {
+		t.Errorf("expected unknown command to fail: %v", results[1]["json"])
+	}
+}
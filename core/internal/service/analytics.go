@@ -0,0 +1,361 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// DefaultAnalyticsPeriod is how far back an analytics report looks by default
+const DefaultAnalyticsPeriod = 30 * 24 * time.Hour
+
+// frecencyHalfLife is how long it takes a use to count half as much toward
+// a prompt's frecency
+const frecencyHalfLife = 7 * 24 * time.Hour
+
+// digestMostUsed is how many of the most used prompts a digest lists
+const digestMostUsed = 5
+
+// ErrUsesNotRecorded is returned by what needs prompt uses recorded when
+// they are not
+var ErrUsesNotRecorded = errors.New("prompt uses are not recorded (opt in with 'pkt analytics enable uses')")
+
+// AnalyticsStatus describes what analytics this device records and where
+type AnalyticsStatus struct {
+	Dir     string               `json:"dir"`
+	Enabled map[string]time.Time `json:"enabled"` // Scopes opted into, with when
+	Events  int                  `json:"events"`
+	Oldest  time.Time            `json:"oldest"` // First event kept, zero without any
+}
+
+// AnalyticsReport sums up the analytics recorded on this device over a period
+type AnalyticsReport struct {
+	Since    time.Time         `json:"since"`
+	Until    time.Time         `json:"until"`
+	Enabled  []string          `json:"enabled"`
+	Uses     int               `json:"uses"`
+	Prompts  []PromptActivity  `json:"prompts"` // Highest frecency first
+	Searches []SearchActivity  `json:"searches"`
+	Commands []CommandActivity `json:"commands"`
+}
+
+// PromptActivity is how one prompt was used over a period
+type PromptActivity struct {
+	ID       string    `json:"id"`
+	Title    string    `json:"title,omitempty"` // Empty for prompts since removed
+	Uses     int       `json:"uses"`
+	LastUsed time.Time `json:"last_used"`
+	Frecency float64   `json:"frecency"` // Uses weighted by how recent they are
+}
+
+// SearchActivity is how often one query was searched for over a period
+type SearchActivity struct {
+	Query string `json:"query"`
+	Via   string `json:"via"`
+	Count int    `json:"count"`
+	Empty int    `json:"empty"` // Runs that found nothing
+}
+
+// CommandActivity is how often one CLI command was run over a period
+type CommandActivity struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// AnalyticsScopes expands scope names given by the user, where "all" is
+// every scope, rejecting any that is unknown
+func AnalyticsScopes(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("name a scope: %s or all", strings.Join(storage.AnalyticsScopes, ", "))
+	}
+	var scopes []string
+	for _, name := range names {
+		switch {
+		case name == "all":
+			return append([]string(nil), storage.AnalyticsScopes...), nil
+		case slices.Contains(storage.AnalyticsScopes, name):
+			if !slices.Contains(scopes, name) {
+				scopes = append(scopes, name)
+			}
+		default:
+			return nil, fmt.Errorf("unknown analytics scope %q (expected %s or all)", name, strings.Join(storage.AnalyticsScopes, ", "))
+		}
+	}
+	return scopes, nil
+}
+
+// EnableAnalytics opts this device into recording scopes. Scopes already
+// enabled keep the time they were enabled at.
+func (s *Service) EnableAnalytics(scopes []string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	settings, err := s.analytics.Settings()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, scope := range scopes {
+		if !settings.Has(scope) {
+			settings.Enabled[scope] = now
+		}
+	}
+	return s.analytics.SaveSettings(settings)
+}
+
+// DisableAnalytics stops recording scopes. What was recorded is kept until
+// it is purged.
+func (s *Service) DisableAnalytics(scopes []string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	settings, err := s.analytics.Settings()
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		delete(settings.Enabled, scope)
+	}
+	return s.analytics.SaveSettings(settings)
+}
+
+// AnalyticsEnabled reports whether this device records scope
+func (s *Service) AnalyticsEnabled(scope string) bool {
+	settings, err := s.analytics.Settings()
+	return err == nil && settings.Has(scope)
+}
+
+// AnalyticsDir returns the directory this device keeps its analytics in
+func (s *Service) AnalyticsDir() string {
+	return s.analytics.Dir()
+}
+
+// AnalyticsStatus reports the scopes opted into, where analytics are kept
+// and how much has been recorded
+func (s *Service) AnalyticsStatus() (*AnalyticsStatus, error) {
+	settings, err := s.analytics.Settings()
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.analytics.Events()
+	if err != nil {
+		return nil, err
+	}
+	status := &AnalyticsStatus{Dir: s.analytics.Dir(), Enabled: settings.Enabled, Events: len(events)}
+	if len(events) > 0 {
+		status.Oldest = events[0].Time
+	}
+	return status, nil
+}
+
+// PurgeAnalytics deletes every recorded event and returns how many there
+// were. The scopes opted into stay enabled.
+func (s *Service) PurgeAnalytics() (int, error) {
+	if s.ReadOnly() {
+		return 0, storage.ErrReadOnly
+	}
+	return s.analytics.Purge()
+}
+
+// RecordSearch notes a search run by the user, via the kind of search it
+// was, when searches are recorded. Recording is best effort and never fails
+// the search.
+func (s *Service) RecordSearch(via, query string, results int) {
+	s.analytics.Record(storage.AnalyticsSearches, storage.AnalyticsEvent{
+		Time:  time.Now().UTC(),
+		Kind:  storage.EventSearch,
+		Name:  query,
+		Via:   via,
+		Empty: results == 0,
+	})
+}
+
+// RecordCommand notes the name of a CLI command that was run, when commands
+// are recorded. Its arguments are never recorded.
+func (s *Service) RecordCommand(name string) {
+	s.analytics.Record(storage.AnalyticsCommands, storage.AnalyticsEvent{
+		Time: time.Now().UTC(),
+		Kind: storage.EventCommand,
+		Name: name,
+	})
+}
+
+// recordUse notes a copy or render of a prompt when uses are recorded
+func (s *Service) recordUse(id string) {
+	s.analytics.Record(storage.AnalyticsUses, storage.AnalyticsEvent{
+		Time: time.Now().UTC(),
+		Kind: storage.EventUse,
+		Name: id,
+	})
+}
+
+// AnalyticsReport sums up the events recorded between since and until: the
+// prompts used, highest frecency first, the searches run and the commands
+// run, most often first
+func (s *Service) AnalyticsReport(since, until time.Time) (*AnalyticsReport, error) {
+	settings, err := s.analytics.Settings()
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.analytics.Events()
+	if err != nil {
+		return nil, err
+	}
+	report := &AnalyticsReport{Since: since, Until: until, Enabled: []string{},
+		Prompts: []PromptActivity{}, Searches: []SearchActivity{}, Commands: []CommandActivity{}}
+	for _, scope := range storage.AnalyticsScopes {
+		if settings.Has(scope) {
+			report.Enabled = append(report.Enabled, scope)
+		}
+	}
+
+	prompts := map[string]*PromptActivity{}
+	searches := map[string]*SearchActivity{}
+	commands := map[string]*CommandActivity{}
+	for _, event := range events {
+		if event.Time.Before(since) || event.Time.After(until) {
+			continue
+		}
+		switch event.Kind {
+		case storage.EventUse:
+			activity, ok := prompts[event.Name]
+			if !ok {
+				activity = &PromptActivity{ID: event.Name}
+				prompts[event.Name] = activity
+			}
+			activity.Uses++
+			activity.Frecency += frecencyWeight(event.Time, until)
+			if event.Time.After(activity.LastUsed) {
+				activity.LastUsed = event.Time
+			}
+			report.Uses++
+		case storage.EventSearch:
+			key := event.Via + "\x00" + event.Name
+			activity, ok := searches[key]
+			if !ok {
+				activity = &SearchActivity{Query: event.Name, Via: event.Via}
+				searches[key] = activity
+			}
+			activity.Count++
+			if event.Empty {
+				activity.Empty++
+			}
+		case storage.EventCommand:
+			activity, ok := commands[event.Name]
+			if !ok {
+				activity = &CommandActivity{Name: event.Name}
+				commands[event.Name] = activity
+			}
+			activity.Count++
+		}
+	}
+
+	titles := map[string]string{}
+	if active, err := s.activePrompts(); err == nil {
+		for _, p := range active {
+			titles[p.ID] = p.Title()
+		}
+	}
+	for _, activity := range prompts {
+		activity.Title = titles[activity.ID]
+		activity.Frecency = math.Round(activity.Frecency*100) / 100
+		report.Prompts = append(report.Prompts, *activity)
+	}
+	sort.Slice(report.Prompts, func(i, j int) bool {
+		a, b := report.Prompts[i], report.Prompts[j]
+		if a.Frecency != b.Frecency {
+			return a.Frecency > b.Frecency
+		}
+		return a.ID < b.ID
+	})
+	for _, activity := range searches {
+		report.Searches = append(report.Searches, *activity)
+	}
+	sort.Slice(report.Searches, func(i, j int) bool {
+		a, b := report.Searches[i], report.Searches[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Query < b.Query
+	})
+	for _, activity := range commands {
+		report.Commands = append(report.Commands, *activity)
+	}
+	sort.Slice(report.Commands, func(i, j int) bool {
+		a, b := report.Commands[i], report.Commands[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+// frecencyWeight is how much a use at used counts toward frecency at now:
+// one for a use just made, halving every frecencyHalfLife
+func frecencyWeight(used, now time.Time) float64 {
+	age := now.Sub(used)
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(frecencyHalfLife))
+}
+
+// FrecencyScores returns each used prompt's frecency at now: its recorded
+// uses, each weighted by how recent it is. It fails with ErrUsesNotRecorded
+// unless uses are recorded.
+func (s *Service) FrecencyScores(now time.Time) (map[string]float64, error) {
+	if !s.AnalyticsEnabled(storage.AnalyticsUses) {
+		return nil, ErrUsesNotRecorded
+	}
+	events, err := s.analytics.Events()
+	if err != nil {
+		return nil, err
+	}
+	scores := map[string]float64{}
+	for _, event := range events {
+		if event.Kind == storage.EventUse && !event.Time.After(now) {
+			scores[event.Name] += frecencyWeight(event.Time, now)
+		}
+	}
+	return scores, nil
+}
+
+// SortByFrecency orders prompts by frecency, highest first. Prompts never
+// used keep their order after the rest.
+func (s *Service) SortByFrecency(prompts []*models.Prompt) error {
+	scores, err := s.FrecencyScores(time.Now())
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(prompts, func(i, j int) bool {
+		return scores[prompts[i].ID] > scores[prompts[j].ID]
+	})
+	return nil
+}
+
+// mostUsed returns the prompts used most on this device between since and
+// until for a digest, when it has opted into sharing them, or nil
+func (s *Service) mostUsed(since, until time.Time) []PromptActivity {
+	if !s.AnalyticsEnabled(storage.AnalyticsDigest) || !s.AnalyticsEnabled(storage.AnalyticsUses) {
+		return nil
+	}
+	report, err := s.AnalyticsReport(since, until)
+	if err != nil {
+		return nil
+	}
+	var used []PromptActivity
+	for _, activity := range report.Prompts {
+		if activity.Title != "" && len(used) < digestMostUsed {
+			used = append(used, activity)
+		}
+	}
+	return used
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestAnalyticsOptIn(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, id := range []string{"draft", "review", "summary"} {
+		if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: strings.ToUpper(id[:1]) + id[1:], Content: id}); err != nil {
+			t.Fatalf("Failed to create %s: %v", id, err)
+		}
+	}
+
+	// Nothing is recorded before opting in
+	svc.RecordUsage("review")
+	svc.RecordSearch("search", "review", 1)
+	if _, err := os.Stat(svc.analytics.EventsPath()); !os.IsNotExist(err) {
+		t.Fatalf("events recorded without opting in: %v", err)
+	}
+	var prompts []*models.Prompt
+	for _, id := range []string{"draft", "review", "summary"} {
+		p, _ := svc.GetPrompt(id)
+		prompts = append(prompts, p)
+	}
+	if err := svc.SortByFrecency(prompts); !errors.Is(err, ErrUsesNotRecorded) {
+		t.Fatalf("SortByFrecency without uses recorded = %v, want ErrUsesNotRecorded", err)
+	}
+
+	if _, err := AnalyticsScopes([]string{"uses", "keystrokes"}); err == nil {
+		t.Error("expected an unknown scope to be rejected")
+	}
+	if err := svc.EnableAnalytics([]string{storage.AnalyticsUses, storage.AnalyticsDigest}); err != nil {
+		t.Fatalf("EnableAnalytics: %v", err)
+	}
+	svc.RecordUsage("summary")
+	svc.RecordUsage("review")
+	svc.RecordUsage("review")
+	svc.RecordSearch("search", "review", 0) // Searches are not opted into
+
+	if err := svc.SortByFrecency(prompts); err != nil {
+		t.Fatalf("SortByFrecency: %v", err)
+	}
+	if prompts[0].ID != "review" || prompts[1].ID != "summary" || prompts[2].ID != "draft" {
+		t.Errorf("frecency order = %s, %s, %s; want review, summary, draft", prompts[0].ID, prompts[1].ID, prompts[2].ID)
+	}
+
+	now := time.Now()
+	report, err := svc.AnalyticsReport(now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("AnalyticsReport: %v", err)
+	}
+	if report.Uses != 3 || len(report.Prompts) != 2 || report.Prompts[0].ID != "review" || report.Prompts[0].Uses != 2 {
+		t.Errorf("report prompts = %+v with %d uses, want review used twice first", report.Prompts, report.Uses)
+	}
+	if len(report.Searches) != 0 {
+		t.Errorf("report searches = %+v, want none", report.Searches)
+	}
+
+	digest, err := svc.BuildDigestSince(config.DigestConfig{}, now.Add(-time.Hour), now)
+	if err != nil {
+		t.Fatalf("BuildDigestSince: %v", err)
+	}
+	if len(digest.MostUsed) != 2 || !strings.Contains(digest.Markdown(), "`review` — Review (2 uses)") {
+		t.Errorf("digest most used = %+v:\n%s", digest.MostUsed, digest.Markdown())
+	}
+
+	purged, err := svc.PurgeAnalytics()
+	if err != nil || purged != 3 {
+		t.Fatalf("PurgeAnalytics = %d, %v; want 3", purged, err)
+	}
+	status, err := svc.AnalyticsStatus()
+	if err != nil {
+		t.Fatalf("AnalyticsStatus: %v", err)
+	}
+	if status.Events != 0 || len(status.Enabled) != 2 {
+		t.Errorf("status after purge = %+v, want no events and both scopes still enabled", status)
+	}
+
+	if err := svc.DisableAnalytics([]string{storage.AnalyticsUses}); err != nil {
+		t.Fatalf("DisableAnalytics: %v", err)
+	}
+	svc.RecordUsage("review")
+	if status, _ := svc.AnalyticsStatus(); status.Events != 0 {
+		t.Errorf("%d events recorded after opting out", status.Events)
+	}
+}
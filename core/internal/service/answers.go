@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// SavedAnswers returns the variable values last given when rendering a
+// prompt on this device, by name, or nil when there are none
+func (s *Service) SavedAnswers(id string) map[string]string {
+	answers, err := storage.LoadVariableAnswers(s.GetBaseDir())
+	if err != nil {
+		return nil
+	}
+	return answers[id]
+}
+
+// withSavedAnswers returns values on top of the answers saved for prompt id,
+// so anything given now wins
+func (s *Service) withSavedAnswers(id string, values map[string]interface{}) map[string]interface{} {
+	saved := s.SavedAnswers(id)
+	if len(saved) == 0 {
+		return values
+	}
+	merged := make(map[string]interface{}, len(saved)+len(values))
+	for name, value := range saved {
+		merged[name] = value
+	}
+	maps.Copy(merged, values)
+	return merged
+}
+
+// rememberAnswers saves the values given for a render of prompt id over the
+// ones saved before, so a render that changes one value keeps the others.
+// Values of sensitive variables are never saved. Nothing is written for a
+// read-only library.
+func (s *Service) rememberAnswers(id string, declared []models.Variable, given map[string]interface{}) error {
+	if len(given) == 0 || s.ReadOnly() {
+		return nil
+	}
+	sensitive := map[string]bool{}
+	for _, v := range declared {
+		sensitive[v.Name] = v.Sensitive
+	}
+
+	answers, err := storage.LoadVariableAnswers(s.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	saved := answers[id]
+	if saved == nil {
+		saved = map[string]string{}
+	}
+	for name, value := range given {
+		if !sensitive[name] {
+			saved[name] = fmt.Sprint(value)
+		}
+	}
+	if len(saved) == 0 {
+		return nil
+	}
+	answers[id] = saved
+	return storage.SaveVariableAnswers(s.GetBaseDir(), answers)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRenderPromptSavedAnswers(t *testing.T) {
+	tmpDir := t.TempDir()
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	prompt := &models.Prompt{
+		ID:      "follow-up",
+		Name:    "Follow Up",
+		Content: "Write a {{tone}} follow-up to {{name}} using {{token}}.",
+		Variables: []models.Variable{
+			{Name: "tone", Default: "formal"},
+			{Name: "token", Sensitive: true},
+		},
+	}
+	if err := svc.CreatePrompt(prompt); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	render := func(opts RenderOptions) string {
+		t.Helper()
+		rendered, err := svc.RenderPrompt("follow-up", opts)
+		if err != nil {
+			t.Fatalf("RenderPrompt: %v", err)
+		}
+		return rendered
+	}
+	cli := func(values map[string]interface{}) RenderOptions {
+		return RenderOptions{Variables: values, UseAnswers: true, RememberAnswers: true}
+	}
+
+	render(cli(map[string]interface{}{"tone": "friendly", "name": "Ada", "token": "t-123"}))
+	if saved := svc.SavedAnswers("follow-up"); !maps.Equal(saved, map[string]string{"tone": "friendly", "name": "Ada"}) {
+		t.Fatalf("SavedAnswers = %v, want tone and name without the sensitive token", saved)
+	}
+
+	// Changing one value keeps the others
+	if got, want := render(cli(map[string]interface{}{"name": "Bo", "token": "t-456"})), "Write a friendly follow-up to Bo using t-456."; got != want {
+		t.Errorf("render with saved answers = %q, want %q", got, want)
+	}
+
+	// Renders for others neither use nor change the answers
+	if got, want := render(RenderOptions{Variables: map[string]interface{}{"name": "Cy"}}), "Write a formal follow-up to Cy using {{token}}."; got != want {
+		t.Errorf("render without answers = %q, want %q", got, want)
+	}
+	if saved := svc.SavedAnswers("follow-up"); saved["name"] != "Bo" {
+		t.Errorf("SavedAnswers = %v, want name Bo kept", saved)
+	}
+
+	// A profile's values come before the saved ones
+	os.MkdirAll(filepath.Join(tmpDir, "profiles"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "profiles", "support.yaml"), []byte("tone: terse\n"), 0644)
+	opts := cli(nil)
+	opts.Profile = "support"
+	if got, want := render(opts), "Write a terse follow-up to Bo using {{token}}."; got != want {
+		t.Errorf("render with a profile = %q, want %q", got, want)
+	}
+}
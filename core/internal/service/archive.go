@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// ArchiveFilter narrows the archive to one prompt's versions and to versions
+// last edited within a time range. Zero fields match everything.
+type ArchiveFilter struct {
+	ID    string    // Original prompt ID
+	Since time.Time // Inclusive
+	Until time.Time // Exclusive
+}
+
+// ListArchive returns the archived prompt versions matching filter, sorted by
+// prompt ID and then newest version first
+func (s *Service) ListArchive(filter ArchiveFilter) ([]*models.Prompt, error) {
+	archived, err := s.storage.ListArchivedPrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*models.Prompt
+	for _, p := range archived {
+		if filter.ID != "" && p.ID != filter.ID {
+			continue
+		}
+		if !filter.Since.IsZero() && p.UpdatedAt.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && !p.UpdatedAt.Before(filter.Until) {
+			continue
+		}
+		versions = append(versions, p)
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].ID != versions[j].ID {
+			return versions[i].ID < versions[j].ID
+		}
+		if !versions[i].UpdatedAt.Equal(versions[j].UpdatedAt) {
+			return versions[i].UpdatedAt.After(versions[j].UpdatedAt)
+		}
+		return compareVersions(versions[i].Version, versions[j].Version) > 0
+	})
+	return versions, nil
+}
+
+// compareVersions orders dotted versions part by part, numerically where both
+// parts are numbers, so 1.0.10 comes after 1.0.9
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, errX := strconv.Atoi(as[i])
+		y, errY := strconv.Atoi(bs[i])
+		switch {
+		case errX == nil && errY == nil && x != y:
+			return x - y
+		case (errX != nil || errY != nil) && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// GetArchivedVersion returns one archived version of a prompt with its content
+func (s *Service) GetArchivedVersion(id, version string) (*models.Prompt, error) {
+	versions, err := s.ListArchive(ArchiveFilter{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range versions {
+		if p.Version == version {
+			return s.storage.LoadPrompt(p.FilePath)
+		}
+	}
+	return nil, fmt.Errorf("archived version not found: %s v%s", id, version)
+}
+
+// RestoreArchivedVersion makes an archived version of a prompt current again.
+// The current version is archived in turn and the restored content saved as
+// the next version, so restoring never loses history. A prompt deleted since
+// it was archived is recreated, without an expires date that has passed, so
+// maintenance does not archive it again.
+func (s *Service) RestoreArchivedVersion(id, version string) (*models.Prompt, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+
+	archived, err := s.GetArchivedVersion(id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	restored := *archived
+	restored.FilePath = ""
+	if expires, err := archived.Expiry(); err == nil && !expires.IsZero() && !expires.After(time.Now()) {
+		restored.Expires = ""
+	}
+	restored.Tags = nil
+	for _, tag := range archived.Tags {
+		if tag != "archive" {
+			restored.Tags = append(restored.Tags, tag)
+		}
+	}
+
+	if _, err := s.GetPrompt(id); err != nil {
+		if !errors.Is(err, ErrPromptNotFound) {
+			return nil, err
+		}
+		if err := s.CreatePrompt(&restored); err != nil {
+			return nil, err
+		}
+		return &restored, nil
+	}
+
+	if err := s.UpdatePrompt(&restored); err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRestoreArchivedVersion(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	prompt := &models.Prompt{ID: "review", Name: "Review", Version: "1.0.0", Tags: []string{"code"}, Content: "first"}
+	if err := svc.CreatePrompt(prompt); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	for _, content := range []string{"second", "third"} {
+		current, _ := svc.GetPrompt("review")
+		updated := *current
+		updated.Content = content
+		if err := svc.UpdatePrompt(&updated); err != nil {
+			t.Fatalf("Failed to update prompt: %v", err)
+		}
+	}
+
+	versions, err := svc.ListArchive(ArchiveFilter{ID: "review"})
+	if err != nil {
+		t.Fatalf("ListArchive: %v", err)
+	}
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.Version)
+	}
+	if !slices.Equal(got, []string{"1.0.1", "1.0.0"}) {
+		t.Fatalf("archived versions = %v, want newest first [1.0.1 1.0.0]", got)
+	}
+	if versions, _ := svc.ListArchive(ArchiveFilter{Since: time.Now().Add(time.Hour)}); len(versions) != 0 {
+		t.Errorf("ListArchive since an hour from now = %d versions, want 0", len(versions))
+	}
+
+	restored, err := svc.RestoreArchivedVersion("review", "1.0.0")
+	if err != nil {
+		t.Fatalf("RestoreArchivedVersion: %v", err)
+	}
+	if restored.Version != "1.0.3" {
+		t.Errorf("restored version = %s, want 1.0.3", restored.Version)
+	}
+	current, _ := svc.GetPrompt("review")
+	if current.Content != "first" || !slices.Equal(current.Tags, []string{"code"}) {
+		t.Errorf("current prompt = %q with tags %v, want the first content without the archive tag", current.Content, current.Tags)
+	}
+	if _, err := svc.GetArchivedVersion("review", "1.0.2"); err != nil {
+		t.Errorf("the replaced version was not archived: %v", err)
+	}
+
+	if _, err := svc.RestoreArchivedVersion("review", "9.9.9"); err == nil {
+		t.Error("restoring a missing version succeeded")
+	}
+}
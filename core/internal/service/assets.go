@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// AttachFiles copies files into the prompt's asset folder and records them as
+// attachments, saving the prompt as a new version
+func (s *Service) AttachFiles(id string, files []string) (*models.Prompt, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	updated := *prompt
+	updated.Attachments = append([]string(nil), prompt.Attachments...)
+
+	for _, file := range files {
+		rel, err := s.storage.ImportAsset(file, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach %s: %w", file, err)
+		}
+		if !slices.Contains(updated.Attachments, rel) {
+			updated.Attachments = append(updated.Attachments, rel)
+		}
+	}
+
+	if err := s.UpdatePrompt(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DetachFile removes an attachment from a prompt, deleting the file too when
+// deleteFile is set
+func (s *Service) DetachFile(id, rel string, deleteFile bool) error {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return err
+	}
+	cleaned, err := storage.CleanAttachmentPath(rel)
+	if err != nil {
+		return err
+	}
+
+	updated := *prompt
+	updated.Attachments = nil
+	for _, attachment := range prompt.Attachments {
+		if attachment != cleaned {
+			updated.Attachments = append(updated.Attachments, attachment)
+		}
+	}
+	if len(updated.Attachments) == len(prompt.Attachments) {
+		return fmt.Errorf("prompt %s has no attachment %s", id, rel)
+	}
+
+	if deleteFile {
+		if err := s.storage.DeleteAsset(cleaned); err != nil {
+			return err
+		}
+	}
+	return s.UpdatePrompt(&updated)
+}
+
+// AttachmentPath returns where an attachment lives on disk
+func (s *Service) AttachmentPath(rel string) (string, error) {
+	return s.storage.AssetPath(rel)
+}
+
+// ExportAttachments copies the attachments of prompts into dir, keeping their
+// assets/ paths so an export can be imported elsewhere. Missing files are
+// skipped. It returns how many files were copied.
+func (s *Service) ExportAttachments(prompts []*models.Prompt, dir string) (int, error) {
+	copied := 0
+	for _, prompt := range prompts {
+		for _, rel := range prompt.Attachments {
+			src, err := s.storage.AssetPath(rel)
+			if err != nil {
+				continue
+			}
+			if _, err := os.Stat(src); err != nil {
+				continue
+			}
+			if err := storage.CopyFile(src, filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+				return copied, fmt.Errorf("failed to export %s: %w", rel, err)
+			}
+			copied++
+		}
+	}
+	return copied, nil
+}
+
+// ImportAttachments copies the attachments of imported prompts from an
+// export in dir into the library. It returns how many files were copied.
+func (s *Service) ImportAttachments(prompts []*models.Prompt, dir string) (int, error) {
+	copied := 0
+	for _, prompt := range prompts {
+		for _, rel := range prompt.Attachments {
+			dest, err := s.storage.AssetPath(rel)
+			if err != nil {
+				continue
+			}
+			src := filepath.Join(dir, filepath.FromSlash(rel))
+			if _, err := os.Stat(src); err != nil {
+				continue
+			}
+			if err := storage.CopyFile(src, dest); err != nil {
+				return copied, fmt.Errorf("failed to import %s: %w", rel, err)
+			}
+			copied++
+		}
+	}
+	return copied, nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// Where proposed tags came from
+const (
+	TagsFromCommand  = "command"  // The configured autotag command
+	TagsFromKeywords = "keywords" // The library's tags and the prompt's own words
+)
+
+// AutotagProposal is the tags proposed for one prompt, shown as a diff for
+// review before they are added
+type AutotagProposal struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Tags    []string `json:"tags"`  // The tags it has now
+	Added   []string `json:"added"` // The tags to add
+	Source  string   `json:"source"`
+	Version string   `json:"version,omitempty"` // The new version; empty until applied
+}
+
+// AutotagCandidates returns the prompts with ids, or, when ids is empty,
+// every prompt with fewer tags than autotag.min_tags. Prompts from other
+// sources are left out of the latter, as their tags can't be changed.
+func (s *Service) AutotagCandidates(ids []string) ([]*models.Prompt, error) {
+	if len(ids) > 0 {
+		var prompts []*models.Prompt
+		for _, id := range ids {
+			p, err := s.GetPrompt(id)
+			if err != nil {
+				return nil, err
+			}
+			prompts = append(prompts, p)
+		}
+		return prompts, nil
+	}
+
+	all, err := s.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+	minimum := s.settings.Autotag.MinimumTags()
+	var candidates []*models.Prompt
+	for _, p := range all {
+		if p.Source == "" && len(p.Tags) < minimum {
+			candidates = append(candidates, p)
+		}
+	}
+	return candidates, nil
+}
+
+// ProposeTags proposes up to max new tags for each prompt, or
+// autotag.max_tags when max is 0. The autotag command chooses them when one
+// is configured; otherwise the library's tags that the prompt mentions come
+// first, then words the prompt uses often. Prompts nothing was found for,
+// or the command failed for, are returned as skipped. Nothing is saved.
+func (s *Service) ProposeTags(prompts []*models.Prompt, max int) ([]*AutotagProposal, []RetagSkip, error) {
+	if max <= 0 {
+		max = s.settings.Autotag.MaximumTags()
+	}
+	counts, err := s.TagCounts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var proposals []*AutotagProposal
+	var skipped []RetagSkip
+	for _, p := range prompts {
+		content := s.promptContent(p)
+		proposal := &AutotagProposal{ID: p.ID, Title: p.Title(), Tags: slices.Clone(p.Tags)}
+		if command := s.settings.Autotag.Command; command != "" {
+			tags, err := s.runAutotagCommand(command, content, counts)
+			if err != nil {
+				skipped = append(skipped, RetagSkip{ID: p.ID, Reason: fmt.Sprintf("autotag command failed: %v", err)})
+				continue
+			}
+			proposal.Added = newTags(tags, p.Tags, max)
+			proposal.Source = TagsFromCommand
+		} else {
+			text := p.Title() + " " + p.Summary
+			proposal.Added = newTags(keywordTags(text, content, counts), p.Tags, max)
+			proposal.Source = TagsFromKeywords
+		}
+		if len(proposal.Added) == 0 {
+			skipped = append(skipped, RetagSkip{ID: p.ID, Reason: "no tags found"})
+			continue
+		}
+		proposals = append(proposals, proposal)
+	}
+	return proposals, skipped, nil
+}
+
+// ApplyTags adds the proposed tags, each prompt getting a new version, and
+// syncs them as a single git commit. Prompts from other sources and
+// protected prompts are skipped, as by 'pkt retag'.
+func (s *Service) ApplyTags(proposals []*AutotagProposal) ([]RetagSkip, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+
+	var skipped []RetagSkip
+	applied := 0
+	library, packs := false, map[string]bool{}
+	for _, proposal := range proposals {
+		full, err := s.GetPrompt(proposal.ID)
+		if err != nil {
+			return skipped, err
+		}
+		if full.Source != "" {
+			skipped = append(skipped, RetagSkip{ID: full.ID, Reason: fmt.Sprintf("from source %s", full.Source)})
+			continue
+		}
+		if err := s.CheckProtected(full); err != nil {
+			skipped = append(skipped, RetagSkip{ID: full.ID, Reason: "protected"})
+			continue
+		}
+
+		updated := *full
+		updated.Tags = slices.Clone(full.Tags)
+		for _, tag := range proposal.Added {
+			if !slices.Contains(updated.Tags, tag) {
+				updated.Tags = append(updated.Tags, tag)
+			}
+		}
+		if err := s.writePromptVersion(&updated); err != nil {
+			return skipped, fmt.Errorf("failed to tag %s: %w", full.ID, err)
+		}
+		proposal.Version = updated.Version
+		applied++
+		if pack := storage.PackFromPath(updated.FilePath); pack != "" {
+			packs[pack] = true
+		} else {
+			library = true
+		}
+	}
+
+	if applied == 0 {
+		return skipped, nil
+	}
+	s.syncBulkChange(fmt.Sprintf("Autotag %d prompts", applied), "autotagging prompts", library, packs)
+	return skipped, s.loadPrompts()
+}
+
+// runAutotagCommand runs the autotag command with content on stdin and the
+// library's tags, most used first, in $POCKET_PROMPT_TAGS
+func (s *Service) runAutotagCommand(command, content string, counts map[string]int) ([]string, error) {
+	timeout, err := s.settings.Autotag.CommandTimeout(config.DefaultAutotagTimeout)
+	if err != nil {
+		return nil, err
+	}
+	library := make([]string, 0, len(counts))
+	for tag := range counts {
+		library = append(library, tag)
+	}
+	sort.Slice(library, func(i, j int) bool {
+		if counts[library[i]] != counts[library[j]] {
+			return counts[library[i]] > counts[library[j]]
+		}
+		return library[i] < library[j]
+	})
+
+	out, err := s.runShellCommand(command, content, timeout, []string{"POCKET_PROMPT_TAGS=" + strings.Join(library, ",")})
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, field := range strings.FieldsFunc(out, func(r rune) bool { return r == ',' || r == '\n' }) {
+		tag := strings.ToLower(strings.Trim(field, " \t\r\"'`#-*"))
+		tag = strings.Join(strings.Fields(tag), "-")
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("it printed no tags")
+	}
+	return tags, nil
+}
+
+// newTags returns up to max of tags that prompt doesn't have yet, in order
+func newTags(tags, existing []string, max int) []string {
+	var added []string
+	for _, tag := range tags {
+		tag, err := cleanTag(tag)
+		if err != nil || slices.Contains(existing, tag) || slices.Contains(added, tag) {
+			continue
+		}
+		added = append(added, tag)
+		if len(added) == max {
+			break
+		}
+	}
+	return added
+}
+
+// Words in keyword tags: at least this long and used this often
+const (
+	minKeywordLength = 4
+	minKeywordUses   = 2
+)
+
+// titleWeight is how many uses of a word in the body a word in the title or
+// summary counts as
+const titleWeight = 3
+
+var templateVariable = regexp.MustCompile(`\{\{[^}]*\}\}`)
+
+// keywordTags ranks tags for a prompt whose title and summary are heading
+// and whose content is body. The library's tags whose words all appear
+// come first, the more used in the prompt the better, then the prompt's
+// most frequent words that aren't stopwords.
+func keywordTags(heading, body string, library map[string]int) []string {
+	uses := map[string]int{}
+	for _, word := range keywordWords(heading) {
+		uses[word] += titleWeight
+	}
+	for _, word := range keywordWords(templateVariable.ReplaceAllString(body, " ")) {
+		uses[word]++
+	}
+
+	type scored struct {
+		tag   string
+		score int
+		known int
+	}
+	var known, frequent []scored
+	for tag, count := range library {
+		score := 0
+		for _, word := range strings.FieldsFunc(strings.ToLower(tag), isKeywordSeparator) {
+			if uses[word] == 0 {
+				score = 0
+				break
+			}
+			score += uses[word]
+		}
+		if score > 0 {
+			known = append(known, scored{tag, score, count})
+		}
+	}
+	for word, n := range uses {
+		if n >= minKeywordUses && len(word) >= minKeywordLength && !stopwords[word] {
+			frequent = append(frequent, scored{tag: word, score: n})
+		}
+	}
+
+	rank := func(list []scored) {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].score != list[j].score {
+				return list[i].score > list[j].score
+			}
+			if list[i].known != list[j].known {
+				return list[i].known > list[j].known
+			}
+			return list[i].tag < list[j].tag
+		})
+	}
+	rank(known)
+	rank(frequent)
+
+	var tags []string
+	for _, s := range append(known, frequent...) {
+		tags = append(tags, s.tag)
+	}
+	return tags
+}
+
+// keywordWords splits text into lowercase words of letters and digits,
+// dropping those that are only digits
+func keywordWords(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), isKeywordSeparator) {
+		if strings.IndexFunc(word, unicode.IsLetter) >= 0 {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func isKeywordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// stopwords are common words, and words common to most prompts, that say
+// nothing about what a prompt is for
+var stopwords = wordSet(`
+		about above after again against also always answer assistant based
+		been before being below between both could does doing down during
+		each either every example examples following format from further
+		given have having helpful here into just like make more most must
+		need only other output over please prompt provide really response
+		same should some such sure than that their them then there these
+		they this those through under until user using very want well were
+		what when where which while will with within without would write
+		your yours yourself
+	`)
+
+func wordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestAutotagPrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "sql-review", Name: "SQL Review", Tags: []string{"code-review", "sql", "database"}, Content: "Review the query."},
+		{ID: "query-tuning", Name: "Query tuning", Content: "Tune this SQL query for the database. Explain which indexes the query needs and why the query is slow."},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	candidates, err := svc.AutotagCandidates(nil)
+	if err != nil {
+		t.Fatalf("AutotagCandidates: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != "query-tuning" {
+		t.Fatalf("AutotagCandidates = %d prompts, want query-tuning", len(candidates))
+	}
+
+	proposals, skipped, err := svc.ProposeTags(candidates, 3)
+	if err != nil {
+		t.Fatalf("ProposeTags: %v", err)
+	}
+	if len(proposals) != 1 || len(skipped) != 0 {
+		t.Fatalf("ProposeTags = %d proposals, %d skipped, want 1 and 0", len(proposals), len(skipped))
+	}
+	// Library tags the prompt mentions come before its own frequent words
+	want := []string{"database", "sql", "query"}
+	if !slices.Equal(proposals[0].Added, want) || proposals[0].Source != TagsFromKeywords {
+		t.Errorf("Keyword tags = %v from %s, want %v", proposals[0].Added, proposals[0].Source, want)
+	}
+
+	svc.Settings().Autotag.Command = `cat >/dev/null; echo "$POCKET_PROMPT_TAGS" | grep -q code-review && printf '#Performance, SQL\nIndex Tuning\n'`
+	proposals, _, err = svc.ProposeTags(candidates, 0)
+	if err != nil {
+		t.Fatalf("ProposeTags with command: %v", err)
+	}
+	want = []string{"performance", "sql", "index-tuning"}
+	if len(proposals) != 1 || !slices.Equal(proposals[0].Added, want) || proposals[0].Source != TagsFromCommand {
+		t.Fatalf("Command tags = %+v, want %v", proposals, want)
+	}
+
+	svc.Settings().Autotag.Command = "exit 3"
+	if failed, skipped, _ := svc.ProposeTags(candidates, 0); len(failed) != 0 || len(skipped) != 1 {
+		t.Errorf("Expected a failing command to skip the prompt, got %d proposals", len(failed))
+	}
+
+	if skipped, err := svc.ApplyTags(proposals); err != nil || len(skipped) != 0 {
+		t.Fatalf("ApplyTags: %v (skipped %v)", err, skipped)
+	}
+	saved, err := svc.GetPrompt("query-tuning")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if !slices.Equal(saved.Tags, want) || saved.Version == "" {
+		t.Errorf("Saved prompt has tags %v at version %q", saved.Tags, saved.Version)
+	}
+	if proposals[0].Version != saved.Version {
+		t.Errorf("Proposal version = %q, want %q", proposals[0].Version, saved.Version)
+	}
+}
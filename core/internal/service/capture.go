@@ -0,0 +1,39 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Capture is text saved from a web page, such as a browser extension's selection
+type Capture struct {
+	Text      string
+	Title     string // Defaults to the first line of Text
+	Tags      []string
+	SourceURL string // Page the text was selected on
+	PageTitle string
+}
+
+// CapturePrompt saves captured text as a new prompt. Like QuickAdd, the ID is
+// derived from the title; the page it came from is kept in the prompt's
+// metadata rather than its content.
+func (s *Service) CapturePrompt(c Capture) (*models.Prompt, error) {
+	prompt, err := s.newQuickPrompt(c.Text, c.Title, c.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt.Metadata = map[string]interface{}{"source": "capture"}
+	if url := strings.TrimSpace(c.SourceURL); url != "" {
+		prompt.Metadata["source_url"] = url
+	}
+	if title := strings.TrimSpace(c.PageTitle); title != "" {
+		prompt.Metadata["page_title"] = title
+	}
+
+	if err := s.CreatePrompt(prompt); err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
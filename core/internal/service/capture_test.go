@@ -0,0 +1,50 @@
+package service
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCapturePrompt(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+
+	prompt, err := svc.CapturePrompt(Capture{
+		Text:      "  Summarize this thread in three bullets.\nKeep names.  ",
+		Tags:      []string{"web", " web ", ""},
+		SourceURL: "https://example.com/thread/42",
+		PageTitle: "Thread 42",
+	})
+	if err != nil {
+		t.Fatalf("CapturePrompt: %v", err)
+	}
+	if prompt.ID != "summarize-this-thread-in-three-bullets" {
+		t.Errorf("ID = %q", prompt.ID)
+	}
+	if len(prompt.Tags) != 1 || prompt.Tags[0] != "web" {
+		t.Errorf("Tags = %v, want [web]", prompt.Tags)
+	}
+
+	saved, err := svc.GetPrompt(prompt.ID)
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if saved.Content != "Summarize this thread in three bullets.\nKeep names." {
+		t.Errorf("Content = %q", saved.Content)
+	}
+	if saved.Metadata["source_url"] != "https://example.com/thread/42" || saved.Metadata["page_title"] != "Thread 42" || saved.Metadata["source"] != "capture" {
+		t.Errorf("Metadata = %v", saved.Metadata)
+	}
+
+	if _, err := svc.CapturePrompt(Capture{Text: "   "}); err == nil {
+		t.Error("empty capture was saved")
+	}
+}
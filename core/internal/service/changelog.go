@@ -0,0 +1,279 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// Kinds of prompt change in a changelog
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeRemoved  = "removed"
+)
+
+// PromptChange is one changelog entry: a prompt version compared with the one
+// before it
+type PromptChange struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Version      string    `json:"version"`
+	Date         time.Time `json:"date"`
+	Author       string    `json:"author,omitempty"` // Known when the version came from git history
+	Kind         string    `json:"kind"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesRemoved int       `json:"lines_removed"`
+	Notes        []string  `json:"notes,omitempty"` // Metadata changes, e.g. a new title or tags
+}
+
+// ChangelogOptions narrows a changelog to one prompt or to recent changes
+type ChangelogOptions struct {
+	ID    string
+	Since time.Time
+}
+
+// promptSnapshot is one known version of a prompt
+type promptSnapshot struct {
+	prompt *models.Prompt
+	date   time.Time
+	author string
+}
+
+// Changelog lists prompt changes, newest first. Versions are gathered from
+// the archive and from the git history of each prompt file, so edits synced
+// from other clones appear even without an archived copy. Prompts deleted in
+// git are listed as removed.
+func (s *Service) Changelog(opts ChangelogOptions) ([]PromptChange, error) {
+	current, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.storage.ListArchivedPrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	currentByID := make(map[string]*models.Prompt)
+	archivedByID := make(map[string][]*models.Prompt)
+	var ids []string
+	for _, p := range current {
+		if opts.ID == "" || p.ID == opts.ID {
+			currentByID[p.ID] = p
+			ids = append(ids, p.ID)
+		}
+	}
+	for _, p := range archived {
+		if opts.ID != "" && p.ID != opts.ID {
+			continue
+		}
+		if _, seen := currentByID[p.ID]; !seen && len(archivedByID[p.ID]) == 0 {
+			ids = append(ids, p.ID)
+		}
+		archivedByID[p.ID] = append(archivedByID[p.ID], p)
+	}
+
+	var changes []PromptChange
+	for _, id := range ids {
+		snapshots := s.promptSnapshots(currentByID[id], archivedByID[id])
+		for i, snap := range snapshots {
+			var previous *models.Prompt
+			if i > 0 {
+				previous = snapshots[i-1].prompt
+			}
+			changes = append(changes, describeChange(previous, snap))
+		}
+	}
+
+	removed, err := s.removedPrompts(opts, currentByID)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, removed...)
+
+	if opts.ID != "" && len(changes) == 0 {
+		return nil, fmt.Errorf("no history found for prompt %s", opts.ID)
+	}
+
+	// Changes were collected oldest first; walking them backwards keeps
+	// versions dated the same second newest first after the stable sort
+	var filtered []PromptChange
+	for i := len(changes) - 1; i >= 0; i-- {
+		if opts.Since.IsZero() || !changes[i].Date.Before(opts.Since) {
+			filtered = append(filtered, changes[i])
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Date.After(filtered[j].Date)
+	})
+	return filtered, nil
+}
+
+// promptSnapshots collects the distinct versions of a prompt, oldest first.
+// Versions seen in git take the author and date of the first commit that
+// contained them.
+func (s *Service) promptSnapshots(current *models.Prompt, archived []*models.Prompt) []promptSnapshot {
+	byVersion := make(map[string]*promptSnapshot)
+	add := func(p *models.Prompt, date time.Time, author string) {
+		existing, ok := byVersion[p.Version]
+		if !ok {
+			byVersion[p.Version] = &promptSnapshot{prompt: p, date: date, author: author}
+			return
+		}
+		if author != "" && (existing.author == "" || date.Before(existing.date)) {
+			existing.date = date
+			existing.author = author
+		}
+	}
+
+	for _, p := range archived {
+		full, err := s.storage.LoadPrompt(p.FilePath)
+		if err != nil {
+			continue
+		}
+		add(full, full.UpdatedAt, "")
+	}
+
+	if current != nil {
+		full := current
+		if full.Content == "" && full.FilePath != "" {
+			if loaded, err := s.loadPromptContent(full); err == nil {
+				full = loaded
+			}
+		}
+		add(full, full.UpdatedAt, "")
+
+		// Git history is newest first, so older commits of a version win
+		revisions, _ := s.gitSync.FileHistory(full.FilePath)
+		for _, rev := range revisions {
+			if rev.Deleted {
+				continue
+			}
+			data, err := s.gitSync.FileAt(rev.Hash, rev.Path)
+			if err != nil {
+				continue
+			}
+			p, err := storage.ParsePrompt(data)
+			if err != nil || p.ID != full.ID {
+				continue
+			}
+			add(p, rev.Date, rev.Author)
+		}
+	}
+
+	snapshots := make([]promptSnapshot, 0, len(byVersion))
+	for _, snap := range byVersion {
+		snapshots = append(snapshots, *snap)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].date.Equal(snapshots[j].date) {
+			return snapshots[i].date.Before(snapshots[j].date)
+		}
+		return snapshots[i].prompt.Version < snapshots[j].prompt.Version
+	})
+	return snapshots
+}
+
+// removedPrompts lists prompt files deleted in git history that no longer
+// exist in the library
+func (s *Service) removedPrompts(opts ChangelogOptions, current map[string]*models.Prompt) ([]PromptChange, error) {
+	revisions, err := s.gitSync.DeletedFiles("prompts", opts.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []PromptChange
+	seen := make(map[string]bool)
+	for _, rev := range revisions {
+		data, err := s.gitSync.FileAt(rev.Hash+"^", rev.Path)
+		if err != nil {
+			continue
+		}
+		p, err := storage.ParsePrompt(data)
+		if err != nil || p.ID == "" || seen[p.ID] || current[p.ID] != nil {
+			continue
+		}
+		if opts.ID != "" && p.ID != opts.ID {
+			continue
+		}
+		seen[p.ID] = true
+		changes = append(changes, PromptChange{
+			ID:           p.ID,
+			Title:        p.Title(),
+			Version:      p.Version,
+			Date:         rev.Date,
+			Author:       rev.Author,
+			Kind:         ChangeRemoved,
+			LinesRemoved: len(contentLines(p.Content)),
+		})
+	}
+	return changes, nil
+}
+
+// describeChange compares a snapshot with the previous version, or describes
+// it as added when there is none
+func describeChange(previous *models.Prompt, snap promptSnapshot) PromptChange {
+	p := snap.prompt
+	change := PromptChange{
+		ID:      p.ID,
+		Title:   p.Title(),
+		Version: p.Version,
+		Date:    snap.date,
+		Author:  snap.author,
+		Kind:    ChangeAdded,
+	}
+	if previous == nil {
+		change.LinesAdded = len(contentLines(p.Content))
+		return change
+	}
+
+	change.Kind = ChangeModified
+	change.LinesAdded, change.LinesRemoved = diff.Count(diff.Lines(previous.Content, p.Content))
+	if previous.Name != p.Name {
+		change.Notes = append(change.Notes, fmt.Sprintf("title %q → %q", previous.Name, p.Name))
+	}
+	if previous.Summary != p.Summary {
+		change.Notes = append(change.Notes, "description updated")
+	}
+	if tags := tagChanges(previous.StoredTags(), p.StoredTags()); tags != "" {
+		change.Notes = append(change.Notes, tags)
+	}
+	if previous.TemplateRef != p.TemplateRef {
+		change.Notes = append(change.Notes, fmt.Sprintf("template %q → %q", previous.TemplateRef, p.TemplateRef))
+	}
+	return change
+}
+
+// tagChanges summarises added and removed tags, e.g. "tags +review -draft".
+// The archive tag added to archived copies is ignored.
+func tagChanges(before, after []string) string {
+	var parts []string
+	for _, tag := range after {
+		if tag != "archive" && !slices.Contains(before, tag) {
+			parts = append(parts, "+"+tag)
+		}
+	}
+	for _, tag := range before {
+		if tag != "archive" && !slices.Contains(after, tag) {
+			parts = append(parts, "-"+tag)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "tags " + strings.Join(parts, " ")
+}
+
+func contentLines(content string) []string {
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
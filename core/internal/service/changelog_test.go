@@ -0,0 +1,54 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestChangelogFromArchivedVersions(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "notes", Name: "Notes", Content: "one\ntwo\nthree"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	prompt, err := svc.GetPrompt("notes")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	updated := *prompt
+	updated.Name = "Meeting Notes"
+	updated.Content = "one\n2\nthree\nfour"
+	if err := svc.UpdatePrompt(&updated); err != nil {
+		t.Fatalf("UpdatePrompt: %v", err)
+	}
+
+	changes, err := svc.Changelog(ChangelogOptions{ID: "notes"})
+	if err != nil {
+		t.Fatalf("Changelog: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, want 2: %+v", len(changes), changes)
+	}
+
+	latest, first := changes[0], changes[1]
+	if first.Kind != ChangeAdded || first.LinesAdded != 3 {
+		t.Fatalf("first change = %+v, want added with 3 lines", first)
+	}
+	if latest.Kind != ChangeModified || latest.LinesAdded != 2 || latest.LinesRemoved != 1 {
+		t.Fatalf("latest change = %+v, want +2/-1 lines", latest)
+	}
+	if len(latest.Notes) != 1 || latest.Notes[0] != `title "Notes" → "Meeting Notes"` {
+		t.Fatalf("latest notes = %v", latest.Notes)
+	}
+}
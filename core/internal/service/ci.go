@@ -0,0 +1,253 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/lint"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+)
+
+// CI checks, each reporting a kind of finding
+const (
+	CheckLint        = "lint"         // Style rules from the lint config
+	CheckIntegrity   = "integrity"    // Broken frontmatter, duplicate IDs and references that do not resolve
+	CheckDuplicates  = "duplicates"   // Prompts with the same or nearly the same content
+	CheckTokenBudget = "token-budget" // Rendered prompts over their token budget
+)
+
+// minSimilarWords keeps short prompts, which overlap by chance, out of the
+// near-duplicate report
+const minSimilarWords = 10
+
+// CIFinding is one problem found by RunCI
+type CIFinding struct {
+	Check    string        `json:"check"`
+	Rule     string        `json:"rule,omitempty"` // The lint rule, for lint and frontmatter findings
+	Severity lint.Severity `json:"severity"`
+	PromptID string        `json:"prompt_id,omitempty"`
+	File     string        `json:"file,omitempty"` // Relative to the library root
+	Line     int           `json:"line,omitempty"`
+	Message  string        `json:"message"`
+}
+
+// CIReport is the outcome of RunCI
+type CIReport struct {
+	Prompts  int         `json:"prompts"`
+	Errors   int         `json:"errors"`
+	Warnings int         `json:"warnings"`
+	Findings []CIFinding `json:"findings"`
+}
+
+// RunCI runs every validation check over the library: lint rules, integrity,
+// a duplicate report and token budgets. Duplicates are warnings; the other
+// checks report errors, except lint rules configured as warnings.
+func (s *Service) RunCI() (*CIReport, error) {
+	report := &CIReport{Findings: []CIFinding{}}
+
+	results, err := s.LintPrompts(LintOptions{})
+	if err != nil {
+		return nil, err
+	}
+	report.Prompts = len(results)
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			check := CheckLint
+			if issue.Rule == lint.RuleFrontmatter || issue.Rule == lint.RuleDuplicateID {
+				check = CheckIntegrity
+			}
+			report.add(CIFinding{Check: check, Rule: issue.Rule, Severity: issue.Severity, PromptID: result.ID, File: result.FilePath, Line: issue.Line, Message: issue.Message})
+		}
+	}
+
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+	full := make([]*models.Prompt, 0, len(prompts))
+	for _, p := range prompts {
+		if p.Content == "" && p.FilePath != "" {
+			loaded, err := s.loadPromptContent(p)
+			if err != nil {
+				continue // Reported by lint as a frontmatter error
+			}
+			p = loaded
+		}
+		full = append(full, p)
+	}
+	sort.Slice(full, func(i, j int) bool { return full[i].FilePath < full[j].FilePath })
+
+	for _, p := range full {
+		template := s.checkIntegrity(report, p)
+		s.checkTokenBudget(report, p, template)
+	}
+	s.checkDuplicates(report, full)
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+// add records a finding and counts it
+func (r *CIReport) add(f CIFinding) {
+	if f.Severity == lint.Error {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	r.Findings = append(r.Findings, f)
+}
+
+// checkIntegrity reports a missing template, missing attachment or image
+// files, and an output schema that does not load. It returns the prompt's
+// template, if it has one that exists.
+func (s *Service) checkIntegrity(report *CIReport, p *models.Prompt) *models.Template {
+	fail := func(format string, args ...interface{}) {
+		report.add(CIFinding{Check: CheckIntegrity, Severity: lint.Error, PromptID: p.ID, File: p.FilePath, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var template *models.Template
+	if p.TemplateRef != "" {
+		t, err := s.GetTemplate(p.TemplateRef)
+		if err != nil {
+			fail("template %q does not exist", p.TemplateRef)
+		} else {
+			template = t
+		}
+	}
+
+	for _, rel := range p.Attachments {
+		if path, err := s.storage.AssetPath(rel); err != nil {
+			fail("invalid attachment %s: %v", rel, err)
+		} else if _, err := os.Stat(path); err != nil {
+			fail("attachment %s is missing", rel)
+		}
+	}
+
+	images := append(renderer.ImageRefs(p.Content), p.Images...)
+	for _, ref := range images {
+		if strings.Contains(ref, "://") || strings.HasPrefix(ref, "data:") {
+			continue
+		}
+		if path, err := s.storage.AssetPath(ref); err != nil {
+			fail("invalid image %s: %v", ref, err)
+		} else if _, err := os.Stat(path); err != nil {
+			fail("image %s is missing", ref)
+		}
+	}
+
+	// URLs are left to 'pkt check-links', which needs the network
+	for _, ref := range p.References {
+		if strings.Contains(ref, "://") {
+			continue
+		}
+		if _, err := os.Stat(s.resolveLibraryPath(ref)); err != nil {
+			fail("referenced doc %s is missing", ref)
+		}
+	}
+
+	if _, err := s.OutputSchema(p); err != nil {
+		fail("output schema: %v", err)
+	}
+	return template
+}
+
+// resolveLibraryPath resolves a path written relative to the library root
+func (s *Service) resolveLibraryPath(ref string) string {
+	path := filepath.FromSlash(strings.TrimSpace(ref))
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(s.GetBaseDir(), path)
+}
+
+// checkTokenBudget reports prompts whose rendered text, with template and
+// variable defaults applied, is over budget
+func (s *Service) checkTokenBudget(report *CIReport, p *models.Prompt, template *models.Template) {
+	budget, source := s.settings.CI.TokenBudget, "the token budget"
+	for _, tag := range p.Tags {
+		if b, ok := s.settings.CI.TagBudgets[tag]; ok && b > 0 && (budget == 0 || b < budget) {
+			budget, source = b, fmt.Sprintf("the budget for #%s", tag)
+		}
+	}
+	if budget == 0 {
+		return
+	}
+
+	variables, err := s.FillVariables(s.DeclaredVariables(p, template), nil, false, nil)
+	if err != nil {
+		return
+	}
+	rendered, err := renderer.NewRenderer(p, template).RenderText(variables)
+	if err != nil {
+		report.add(CIFinding{Check: CheckIntegrity, Severity: lint.Error, PromptID: p.ID, File: p.FilePath, Message: fmt.Sprintf("failed to render: %v", err)})
+		return
+	}
+	if tokens := EstimateTokens(rendered); tokens > budget {
+		report.add(CIFinding{Check: CheckTokenBudget, Severity: lint.Error, PromptID: p.ID, File: p.FilePath,
+			Message: fmt.Sprintf("renders to about %d tokens, over %s of %d", tokens, source, budget)})
+	}
+}
+
+// checkDuplicates reports prompts with the same content as an earlier one, or
+// whose word pairs overlap with it by at least the configured similarity
+func (s *Service) checkDuplicates(report *CIReport, prompts []*models.Prompt) {
+	threshold := s.settings.CI.DuplicateThreshold()
+	normalized := make([]string, len(prompts))
+	pairs := make([]map[string]bool, len(prompts))
+	for i, p := range prompts {
+		words := strings.Fields(strings.ToLower(p.Content))
+		normalized[i] = strings.Join(words, " ")
+		if len(words) >= minSimilarWords {
+			pairs[i] = make(map[string]bool, len(words))
+			for j := 1; j < len(words); j++ {
+				pairs[i][words[j-1]+" "+words[j]] = true
+			}
+		}
+	}
+
+	for i, p := range prompts {
+		for j := 0; j < i; j++ {
+			other := prompts[j]
+			message := ""
+			if normalized[i] != "" && normalized[i] == normalized[j] {
+				message = fmt.Sprintf("same content as %s (%s)", other.ID, other.FilePath)
+			} else if similarity := jaccard(pairs[i], pairs[j]); similarity >= threshold {
+				message = fmt.Sprintf("%.0f%% similar to %s (%s)", similarity*100, other.ID, other.FilePath)
+			}
+			if message != "" {
+				report.add(CIFinding{Check: CheckDuplicates, Severity: lint.Warning, PromptID: p.ID, File: p.FilePath, Message: message})
+				break
+			}
+		}
+	}
+}
+
+// jaccard returns the overlap of two sets, or 0 when either is empty
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for key := range a {
+		if b[key] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// RepoPrefix returns the library's directory within its git repository, for
+// reporting paths the way CI systems expect them
+func (s *Service) RepoPrefix() string {
+	return s.gitSync.RepoPrefix()
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/lint"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRunCI(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	svc.Settings().CI.TagBudgets = map[string]int{"short": 5}
+
+	long := "Summarise the attached report in three bullet points for a busy executive audience"
+	prompts := []*models.Prompt{
+		{ID: "summary", Name: "Summary", Content: long},
+		{ID: "summary-copy", Name: "Summary copy", Content: strings.ToUpper(long)},
+		{ID: "tiny", Name: "Tiny", Tags: []string{"short"}, Content: long},
+		{ID: "chart", Name: "Chart", TemplateRef: "missing", Content: "Describe {{image:assets/chart.png}}",
+			References: []string{"docs/charts.md", "https://example.com/charts"}},
+	}
+	for _, p := range prompts {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create %s: %v", p.ID, err)
+		}
+	}
+
+	report, err := svc.RunCI()
+	if err != nil {
+		t.Fatalf("RunCI: %v", err)
+	}
+	if report.Prompts != 4 {
+		t.Errorf("checked %d prompts, want 4", report.Prompts)
+	}
+
+	found := make(map[string]CIFinding)
+	for _, f := range report.Findings {
+		found[f.PromptID+" "+f.Check+" "+f.Message] = f
+	}
+	want := []string{
+		`chart integrity template "missing" does not exist`,
+		"chart integrity image assets/chart.png is missing",
+		"chart integrity referenced doc docs/charts.md is missing",
+		"tiny token-budget renders to about 21 tokens, over the budget for #short of 5",
+		"summary duplicates same content as summary-copy (prompts/summary-copy.md)",
+		"tiny duplicates same content as summary-copy (prompts/summary-copy.md)",
+	}
+	for _, key := range want {
+		if _, ok := found[key]; !ok {
+			t.Errorf("missing finding %q in %+v", key, report.Findings)
+		}
+	}
+	if f := found[want[4]]; f.Severity != lint.Warning {
+		t.Errorf("duplicate severity = %s, want warning", f.Severity)
+	}
+	if report.Errors != 4 || report.Warnings != 2 {
+		t.Errorf("got %d errors and %d warnings, want 4 and 2", report.Errors, report.Warnings)
+	}
+}
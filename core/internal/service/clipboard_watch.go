@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// ClipboardFilter decides which copied text WatchClipboard keeps
+type ClipboardFilter struct {
+	MinLength int              // In characters
+	Patterns  []*regexp.Regexp // The text must match one
+}
+
+// Matches reports whether copied text looks like a prompt worth keeping
+func (f ClipboardFilter) Matches(text string) bool {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) < f.MinLength {
+		return false
+	}
+	for _, pattern := range f.Patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchClipboard reads the clipboard with read every interval until ctx is
+// done and saves each newly copied text that filter matches as a prompt with
+// tags, titled like QuickAdd's. What is on the clipboard when watching starts
+// is left alone, as is text already saved while watching. onSave receives
+// every saved prompt.
+func (s *Service) WatchClipboard(ctx context.Context, read func() (string, error), filter ClipboardFilter, tags []string, interval time.Duration, onSave func(*models.Prompt)) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	// A clipboard that cannot be read at all is reported; later failures,
+	// such as an image on the clipboard, are not
+	last, err := read()
+	if err != nil {
+		return err
+	}
+	saved := make(map[string]bool)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		text, err := read()
+		if err != nil || text == last {
+			continue
+		}
+		last = text
+		text = strings.TrimSpace(text)
+		if saved[text] || !filter.Matches(text) {
+			continue
+		}
+
+		prompt, err := s.newQuickPrompt(text, "", tags)
+		if err != nil {
+			return err
+		}
+		prompt.Metadata = map[string]interface{}{"source": "clipboard"}
+		if err := s.CreatePrompt(prompt); err != nil {
+			return err
+		}
+		saved[text] = true
+		onSave(prompt)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestWatchClipboard(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+
+	// What was on the clipboard at the start, then a grocery list, a prompt,
+	// the prompt copied again after something else, and a short prompt
+	system := "You are a careful reviewer. Point out bugs before style."
+	copies := []string{"You are already on the clipboard and long enough to match.", "eggs, milk, bread", system, "eggs", system, "You are short"}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	read := func() (string, error) {
+		text := copies[0]
+		if len(copies) > 1 {
+			copies = copies[1:]
+		} else {
+			cancel()
+		}
+		return text, nil
+	}
+	filter := ClipboardFilter{MinLength: 20, Patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)^you are\b`)}}
+
+	var saved []*models.Prompt
+	err = svc.WatchClipboard(ctx, read, filter, []string{"inbox"}, 10*time.Millisecond, func(p *models.Prompt) {
+		saved = append(saved, p)
+	})
+	if err != nil {
+		t.Fatalf("WatchClipboard: %v", err)
+	}
+
+	if len(saved) != 1 {
+		t.Fatalf("saved %d prompts, want only the system prompt once", len(saved))
+	}
+	prompt, err := svc.GetPrompt(saved[0].ID)
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if prompt.Content != system || !slices.Contains(prompt.Tags, "inbox") || prompt.Metadata["source"] != "clipboard" {
+		t.Errorf("saved %q with tags %v and metadata %v", prompt.Content, prompt.Tags, prompt.Metadata)
+	}
+}
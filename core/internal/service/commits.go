@@ -0,0 +1,29 @@
+package service
+
+import (
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+// SetInterface names the interface changes are made from, such as cli, tui
+// or api, for the {{interface}} of commit messages
+func (s *Service) SetInterface(name string) {
+	s.iface = name
+	if s.project != nil {
+		s.project.SetInterface(name)
+	}
+}
+
+// commitMessage fills in the git.commit_messages template for a change to
+// the prompt, template or saved search id
+func (s *Service) commitMessage(change, id, title, version string) string {
+	return config.FillCommitTemplate(s.settings.Git.CommitTemplate(change), map[string]string{
+		"id":        id,
+		"title":     title,
+		"version":   version,
+		"interface": s.iface,
+		"user":      s.LockOwner(),
+		"time":      time.Now().Format("2006-01-02 15:04:05"),
+	})
+}
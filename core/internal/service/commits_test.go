@@ -0,0 +1,55 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+func TestCommitMessages(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	svc.SetInterface("cli")
+
+	// The plain style keeps the messages commits have always had
+	message := svc.commitMessage(config.CommitUpdatePrompt, "brief", "Client Brief", "1.2.0")
+	if !strings.HasPrefix(message, "Update prompt: Client Brief (v1.2.0) - ") {
+		t.Errorf("plain message = %q", message)
+	}
+
+	svc.settings.Git.CommitStyle = config.CommitStyleConventional
+	if message := svc.commitMessage(config.CommitCreatePrompt, "brief", "Client Brief", "1.0.0"); message != "feat(prompts): add brief" {
+		t.Errorf("conventional message = %q", message)
+	}
+
+	svc.settings.Git.CommitMessages = map[string]string{
+		config.CommitDeletePrompt: "chore(prompts)!: drop {{ id }} via {{interface}} by {{user}}",
+	}
+	want := "chore(prompts)!: drop brief via cli by " + svc.LockOwner()
+	if message := svc.commitMessage(config.CommitDeletePrompt, "brief", "Client Brief", "1.0.0"); message != want {
+		t.Errorf("configured message = %q, want %q", message, want)
+	}
+	if message := svc.commitMessage(config.CommitSaveSearch, "work", "work", ""); message != "chore(searches): save work" {
+		t.Errorf("style message for a change not configured = %q", message)
+	}
+
+	for _, git := range []config.GitConfig{
+		{CommitStyle: "angular"},
+		{CommitMessages: map[string]string{"rename_prompt": "rename {{id}}"}},
+		{CommitMessages: map[string]string{config.CommitCreatePrompt: "add {{name}}"}},
+	} {
+		if err := git.Validate(); err == nil {
+			t.Errorf("Validate accepted %+v", git)
+		}
+	}
+}
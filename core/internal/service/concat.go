@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConcatPart is one prompt of a concatenation, with values for its variables
+// that override the ones given for every prompt
+type ConcatPart struct {
+	ID        string
+	Variables map[string]interface{}
+}
+
+// ConcatPrompts renders prompts in order as text and joins them into one,
+// separated by a blank line or, when separator is set, by separator on a
+// line of its own. opts applies to every prompt, each part's Variables
+// going over opts.Variables. Each prompt counts as used.
+func (s *Service) ConcatPrompts(parts []ConcatPart, separator string, opts RenderOptions) (string, error) {
+	if len(parts) == 0 {
+		return "", fmt.Errorf("no prompts to concatenate")
+	}
+	if opts.Format != "" && opts.Format != "text" {
+		return "", fmt.Errorf("prompts can only be concatenated as text, not %s", opts.Format)
+	}
+
+	rendered := make([]string, len(parts))
+	for i, part := range parts {
+		partOpts := opts
+		partOpts.Variables = make(map[string]interface{}, len(opts.Variables)+len(part.Variables))
+		for name, value := range opts.Variables {
+			partOpts.Variables[name] = value
+		}
+		for name, value := range part.Variables {
+			partOpts.Variables[name] = value
+		}
+		text, err := s.RenderPrompt(part.ID, partOpts)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", part.ID, err)
+		}
+		rendered[i] = strings.Trim(text, "\n")
+	}
+
+	join := "\n\n"
+	if separator != "" {
+		join = "\n\n" + separator + "\n\n"
+	}
+	return strings.Join(rendered, join), nil
+}
+
+// LoadVariablesFile reads variable values from a YAML or JSON file mapping
+// names to values, laid out like a profile
+func (s *Service) LoadVariablesFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variables: %w", err)
+	}
+	variables := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &variables); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return variables, nil
+}
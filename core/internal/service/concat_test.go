@@ -0,0 +1,56 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestConcatPrompts(t *testing.T) {
+	tmpDir := t.TempDir()
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "persona", Name: "Persona", Content: "You are a {{role}}.\n"},
+		{ID: "task", Name: "Task", Content: "Review this {{language}} code for a {{role}}.\n\n"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	varsFile := filepath.Join(tmpDir, "task.yaml")
+	os.WriteFile(varsFile, []byte("language: Go\nrole: junior developer\n"), 0644)
+	taskVars, err := svc.LoadVariablesFile(varsFile)
+	if err != nil {
+		t.Fatalf("LoadVariablesFile: %v", err)
+	}
+
+	parts := []ConcatPart{{ID: "persona"}, {ID: "task", Variables: taskVars}}
+	opts := RenderOptions{Variables: map[string]interface{}{"role": "senior reviewer"}}
+	got, err := svc.ConcatPrompts(parts, "---", opts)
+	if err != nil {
+		t.Fatalf("ConcatPrompts: %v", err)
+	}
+	if want := "You are a senior reviewer.\n\n---\n\nReview this Go code for a junior developer."; got != want {
+		t.Errorf("ConcatPrompts = %q, want %q", got, want)
+	}
+	if opts.Variables["role"] != "senior reviewer" || len(opts.Variables) != 1 {
+		t.Errorf("ConcatPrompts changed the shared variables: %v", opts.Variables)
+	}
+
+	got, err = svc.ConcatPrompts(parts[:1], "", opts)
+	if err != nil || got != "You are a senior reviewer." {
+		t.Errorf("ConcatPrompts of one prompt = %q, %v", got, err)
+	}
+	if _, err := svc.ConcatPrompts([]ConcatPart{{ID: "persona"}, {ID: "missing"}}, "", opts); err == nil {
+		t.Error("ConcatPrompts with a missing prompt succeeded")
+	}
+	if _, err := svc.ConcatPrompts(parts, "", RenderOptions{Format: "json"}); err == nil {
+		t.Error("ConcatPrompts as json succeeded")
+	}
+}
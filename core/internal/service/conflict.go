@@ -0,0 +1,57 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// VersionConflictError is returned when a prompt changed after the version an
+// edit was based on, such as by someone else between git syncs
+type VersionConflictError struct {
+	ID       string
+	Expected string // Version or revision the edit was based on
+	Current  string // Version stored now
+	Revision string // Revision stored now, see PromptRevision
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s has changed: the edit is based on %s, but it is now at version %s (reload it and edit again)", e.ID, e.Expected, e.Current)
+}
+
+// PromptRevision identifies a prompt's stored version and content, as an
+// HTTP ETag does. Unlike the version, it differs when two libraries saved
+// different edits as the same version before syncing.
+func (s *Service) PromptRevision(prompt *models.Prompt) string {
+	sum := sha256.Sum256([]byte(prompt.Version + "\x00" + s.promptContent(prompt)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// CheckPromptVersion returns a VersionConflictError unless the prompt is
+// still at expected, either its version or its PromptRevision
+func (s *Service) CheckPromptVersion(id, expected string) error {
+	existing, err := s.GetPrompt(id)
+	if err != nil {
+		return err
+	}
+	revision := s.PromptRevision(existing)
+	if expected == existing.Version || expected == revision {
+		return nil
+	}
+	return &VersionConflictError{ID: id, Expected: expected, Current: existing.Version, Revision: revision}
+}
+
+// UpdatePromptIfVersion saves prompt as its next version, as UpdatePrompt
+// does, only when the stored prompt is still at expected. Conditional
+// updates are serialized, so two that expect the same version cannot both
+// succeed.
+func (s *Service) UpdatePromptIfVersion(prompt *models.Prompt, expected string) error {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+	if err := s.CheckPromptVersion(prompt.ID, expected); err != nil {
+		return err
+	}
+	return s.UpdatePrompt(prompt)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestUpdatePromptIfVersion(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Content: "Review this"}); err != nil {
+		t.Fatalf("CreatePrompt: %v", err)
+	}
+
+	base, err := svc.GetPrompt("review")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	baseVersion, baseRevision := base.Version, svc.PromptRevision(base)
+
+	// Someone else saves first
+	theirs := *base
+	theirs.Content = "Review this carefully"
+	if err := svc.UpdatePromptIfVersion(&theirs, baseRevision); err != nil {
+		t.Fatalf("UpdatePromptIfVersion with the current revision: %v", err)
+	}
+
+	ours := *base
+	ours.Content = "Review this quickly"
+	err = svc.UpdatePromptIfVersion(&ours, baseVersion)
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want a VersionConflictError", err)
+	}
+	if conflict.Current != theirs.Version || conflict.Revision == baseRevision {
+		t.Errorf("conflict = %+v, want the version saved by the other edit", conflict)
+	}
+
+	saved, err := svc.GetPrompt("review")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if saved.Content != "Review this carefully" {
+		t.Errorf("content = %q, the refused edit was saved", saved.Content)
+	}
+	if err := svc.CheckPromptVersion("review", conflict.Revision); err != nil {
+		t.Errorf("CheckPromptVersion with the current revision: %v", err)
+	}
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// DefaultDigestPeriod is how far back a digest looks when none was sent
+// before and there is no schedule to take the period from
+const DefaultDigestPeriod = 7 * 24 * time.Hour
+
+var errNoDigestDestination = errors.New("digest has nowhere to go (set digest.webhook or digest.smtp in .pocket-prompt/config.json)")
+
+// discordContentLimit is the longest message a Discord webhook accepts
+const discordContentLimit = 2000
+
+// Digest summarises the library for a team: the prompts added, updated and
+// removed since Since, or the results of a saved search
+type Digest struct {
+	Title       string           `json:"title"`
+	Since       time.Time        `json:"since"`
+	Generated   time.Time        `json:"generated"`
+	SavedSearch string           `json:"saved_search,omitempty"`
+	Changes     []PromptChange   `json:"changes,omitempty"` // Latest change of each prompt, newest first
+	Results     []*models.Prompt `json:"results,omitempty"` // Saved search results
+
+	// Subscriptions are the new matches of subscribed saved searches not yet
+	// acknowledged on the device building the digest
+	Subscriptions []SearchMatches `json:"subscriptions,omitempty"`
+
+	// MostUsed are the prompts used most on the device building the digest,
+	// when it has opted into sharing them
+	MostUsed []PromptActivity `json:"most_used,omitempty"`
+
+	limit int
+}
+
+// Empty reports whether the digest has nothing to tell
+func (d *Digest) Empty() bool {
+	return len(d.Changes) == 0 && len(d.Results) == 0 && len(d.Subscriptions) == 0
+}
+
+// Summary describes the digest in a line, such as "2 added, 1 updated"
+func (d *Digest) Summary() string {
+	summary := d.changeSummary()
+	if n := d.newMatches(); n > 0 {
+		summary += fmt.Sprintf("; %d new in subscribed searches", n)
+	}
+	return summary
+}
+
+// changeSummary describes the changes or saved search results in a line
+func (d *Digest) changeSummary() string {
+	if d.SavedSearch != "" {
+		return fmt.Sprintf("%s in %s", plural(len(d.Results), "prompt"), d.SavedSearch)
+	}
+	if len(d.Changes) == 0 {
+		return "no changes"
+	}
+	counts := map[string]int{}
+	for _, change := range d.Changes {
+		counts[change.Kind]++
+	}
+	var parts []string
+	for _, kind := range []string{ChangeAdded, ChangeModified, ChangeRemoved} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], changeVerb(kind)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// newMatches counts the new matches of subscribed searches
+func (d *Digest) newMatches() int {
+	count := 0
+	for _, search := range d.Subscriptions {
+		count += len(search.Matches)
+	}
+	return count
+}
+
+// Markdown renders the digest as a Markdown message
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", d.Title)
+	since := d.Since.Format("2 Jan 2006 15:04")
+
+	listed := 0
+	item := func(line string) {
+		if d.limit <= 0 || listed < d.limit {
+			b.WriteString("- " + line + "\n")
+		}
+		listed++
+	}
+
+	switch {
+	case d.SavedSearch != "" && len(d.Results) == 0:
+		fmt.Fprintf(&b, "No prompts match the saved search %q.\n", d.SavedSearch)
+	case d.SavedSearch != "":
+		fmt.Fprintf(&b, "%s in the saved search %q:\n\n", plural(len(d.Results), "prompt"), d.SavedSearch)
+		for _, p := range d.Results {
+			line := digestID(p.ID, p.Version) + " — " + p.Title()
+			if p.Summary != "" {
+				line += ": " + p.Summary
+			}
+			item(line)
+		}
+	case len(d.Changes) == 0:
+		fmt.Fprintf(&b, "No prompts were added, updated or removed since %s.\n", since)
+	default:
+		fmt.Fprintf(&b, "Since %s: %s.\n", since, d.changeSummary())
+		for _, kind := range []string{ChangeAdded, ChangeModified, ChangeRemoved} {
+			heading := false
+			for _, change := range d.Changes {
+				if change.Kind != kind {
+					continue
+				}
+				if !heading && (d.limit <= 0 || listed < d.limit) {
+					fmt.Fprintf(&b, "\n## %s\n\n", strings.ToUpper(changeVerb(kind)[:1])+changeVerb(kind)[1:])
+					heading = true
+				}
+				item(digestLine(change))
+			}
+		}
+	}
+
+	if d.limit > 0 && listed > d.limit {
+		fmt.Fprintf(&b, "\n…and %d more\n", listed-d.limit)
+	}
+
+	for _, search := range d.Subscriptions {
+		fmt.Fprintf(&b, "\n## New in %s\n\n", search.Search)
+		for _, match := range search.Matches {
+			p := match.Prompt
+			fmt.Fprintf(&b, "- %s — %s (%s)\n", digestID(p.ID, p.Version), p.Title(), match.Source)
+		}
+	}
+
+	if len(d.MostUsed) > 0 {
+		b.WriteString("\n## Most used\n\n")
+		for _, used := range d.MostUsed {
+			fmt.Fprintf(&b, "- %s — %s (%s)\n", digestID(used.ID, ""), used.Title, plural(used.Uses, "use"))
+		}
+	}
+	return b.String()
+}
+
+// digestLine renders a change as a list item
+func digestLine(change PromptChange) string {
+	line := digestID(change.ID, change.Version) + " — " + change.Title
+	var details []string
+	if change.Kind == ChangeModified {
+		if change.LinesAdded > 0 || change.LinesRemoved > 0 {
+			details = append(details, fmt.Sprintf("+%d/-%d lines", change.LinesAdded, change.LinesRemoved))
+		}
+		details = append(details, change.Notes...)
+	}
+	if len(details) > 0 {
+		line += ": " + strings.Join(details, "; ")
+	}
+	if change.Author != "" {
+		line += fmt.Sprintf(" (%s)", change.Author)
+	}
+	return line
+}
+
+// digestID writes a prompt ID and its version, when it has one
+func digestID(id, version string) string {
+	if version == "" {
+		return "`" + id + "`"
+	}
+	return fmt.Sprintf("`%s` v%s", id, version)
+}
+
+// BuildDigest compiles the digest cfg describes as of now: the saved
+// search's results when one is set, otherwise the prompts changed since the
+// last digest was sent. A first digest covers one period of the schedule.
+func (s *Service) BuildDigest(cfg config.DigestConfig, now time.Time) (*Digest, error) {
+	since, err := s.digestSince(cfg, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.BuildDigestSince(cfg, since, now)
+}
+
+// BuildDigestSince compiles a digest of the changes made after since, with
+// the new matches of subscribed saved searches and, when this device shares
+// them, its most used prompts
+func (s *Service) BuildDigestSince(cfg config.DigestConfig, since, now time.Time) (*Digest, error) {
+	digest := &Digest{Title: cfg.Heading(), Since: since, Generated: now, SavedSearch: cfg.SavedSearch, limit: cfg.Limit()}
+	digest.MostUsed = s.mostUsed(since, now)
+	subscriptions, err := s.AllNewMatches()
+	if err != nil {
+		return nil, err
+	}
+	digest.Subscriptions = subscriptions
+	if cfg.SavedSearch != "" {
+		results, err := s.ExecuteSavedSearch(cfg.SavedSearch)
+		if err != nil {
+			return nil, err
+		}
+		digest.Results = results
+		return digest, nil
+	}
+
+	changes, err := s.Changelog(ChangelogOptions{Since: since})
+	if err != nil {
+		return nil, err
+	}
+	digest.Changes = latestChanges(changes)
+	return digest, nil
+}
+
+// latestChanges keeps one change per prompt from a changelog, newest first:
+// the latest, counted as added when the prompt is new in the period, with
+// the lines changed in the period added up
+func latestChanges(changes []PromptChange) []PromptChange {
+	var latest []PromptChange
+	index := map[string]int{}
+	for _, change := range changes {
+		i, seen := index[change.ID]
+		if !seen {
+			index[change.ID] = len(latest)
+			latest = append(latest, change)
+			continue
+		}
+		if change.Kind == ChangeAdded && latest[i].Kind == ChangeModified {
+			latest[i].Kind = ChangeAdded
+			latest[i].Notes = nil
+		}
+		latest[i].LinesAdded += change.LinesAdded
+		latest[i].LinesRemoved += change.LinesRemoved
+	}
+	return latest
+}
+
+// digestSince returns when the last digest was sent, or one schedule period
+// before now when none was
+func (s *Service) digestSince(cfg config.DigestConfig, now time.Time) (time.Time, error) {
+	state, err := storage.LoadDigestState(s.GetBaseDir())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !state.LastSent.IsZero() {
+		return state.LastSent, nil
+	}
+	if cfg.Scheduled() {
+		if schedule, err := cfg.Cron(); err == nil {
+			next := schedule.Next(now)
+			if after := schedule.Next(next); !after.IsZero() {
+				return now.Add(-after.Sub(next)), nil
+			}
+		}
+	}
+	return now.Add(-DefaultDigestPeriod), nil
+}
+
+// SendDigest posts the digest to the webhook and emails it, whichever cfg sets up
+func (s *Service) SendDigest(ctx context.Context, cfg config.DigestConfig, digest *Digest) error {
+	if !cfg.HasDestination() {
+		return errNoDigestDestination
+	}
+	if cfg.Webhook != "" {
+		if err := postDigest(ctx, cfg.Webhook, digest); err != nil {
+			return err
+		}
+	}
+	if cfg.Emailed() {
+		if err := emailDigest(cfg, digest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDigest builds and sends a digest and records when it was sent, so the
+// next one starts there. An empty digest is skipped unless cfg.SendEmpty is
+// set; sent reports whether it went out.
+func (s *Service) RunDigest(ctx context.Context, cfg config.DigestConfig, now time.Time) (digest *Digest, sent bool, err error) {
+	if s.ReadOnly() {
+		return nil, false, storage.ErrReadOnly
+	}
+	if !cfg.HasDestination() {
+		return nil, false, errNoDigestDestination
+	}
+	digest, err = s.BuildDigest(cfg, now)
+	if err != nil {
+		return nil, false, err
+	}
+	if !digest.Empty() || cfg.SendEmpty {
+		if err := s.SendDigest(ctx, cfg, digest); err != nil {
+			return digest, false, err
+		}
+		sent = true
+	}
+	if err := storage.SaveDigestState(s.GetBaseDir(), &storage.DigestState{LastSent: now}); err != nil {
+		return digest, sent, err
+	}
+	return digest, sent, nil
+}
+
+// PollDigests sends a digest each time the schedule fires until ctx is done,
+// passing each run's outcome to fn
+func (s *Service) PollDigests(ctx context.Context, cfg config.DigestConfig, fn func(*Digest, bool, error)) error {
+	schedule, err := cfg.Cron()
+	if err != nil {
+		return err
+	}
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("digest schedule %q never fires", cfg.Schedule)
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+			fn(s.RunDigest(ctx, cfg, time.Now()))
+		}
+	}
+}
+
+// postDigest posts the digest as JSON that Slack and Mattermost (text) and
+// Discord (content) incoming webhooks understand, with the digest itself for
+// other receivers
+func postDigest(ctx context.Context, webhook string, digest *Digest) error {
+	text := digest.Markdown()
+	content := text
+	if runes := []rune(content); len(runes) > discordContentLimit {
+		content = string(runes[:discordContentLimit-1]) + "…"
+	}
+	body, err := json.Marshal(map[string]any{"text": text, "content": content, "digest": digest})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post digest: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("digest webhook returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// emailDigest sends the digest as a plain-text email. The password, when
+// set, is sent with PLAIN authentication, which Go only allows over TLS or
+// to localhost.
+func emailDigest(cfg config.DigestConfig, digest *Digest) error {
+	host, port, err := net.SplitHostPort(cfg.SMTP)
+	if err != nil {
+		return fmt.Errorf("invalid digest smtp %q: %w", cfg.SMTP, err)
+	}
+	var auth smtp.Auth
+	if password := os.Getenv(cfg.PasswordVar()); password != "" {
+		auth = smtp.PlainAuth("", cfg.Login(), password, host)
+	}
+	message := digestEmail(cfg, digest)
+
+	// SendMail upgrades to TLS with STARTTLS; port 465 expects TLS from the start
+	if port != "465" {
+		if err := smtp.SendMail(cfg.SMTP, auth, cfg.From, cfg.To, message); err != nil {
+			return fmt.Errorf("failed to email digest: %w", err)
+		}
+		return nil
+	}
+	conn, err := tls.Dial("tcp", cfg.SMTP, &tls.Config{ServerName: host})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", cfg.SMTP, err)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to %s: %w", cfg.SMTP, err)
+	}
+	defer client.Close()
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to email digest: %w", err)
+		}
+	}
+	if err := client.Mail(cfg.From); err != nil {
+		return fmt.Errorf("failed to email digest: %w", err)
+	}
+	for _, to := range cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("failed to email digest to %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to email digest: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to email digest: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to email digest: %w", err)
+	}
+	return client.Quit()
+}
+
+// digestEmail writes the digest as a MIME message with a quoted-printable
+// Markdown body
+func digestEmail(cfg config.DigestConfig, digest *Digest) []byte {
+	var b bytes.Buffer
+	subject := fmt.Sprintf("%s: %s", digest.Title, digest.Summary())
+	fmt.Fprintf(&b, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", digest.Generated.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	w := quotedprintable.NewWriter(&b)
+	w.Write([]byte(strings.ReplaceAll(digest.Markdown(), "\n", "\r\n")))
+	w.Close()
+	return b.Bytes()
+}
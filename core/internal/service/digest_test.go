@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestRunDigest(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "notes", Name: "Notes", Version: "1.0.0", Content: "one\ntwo"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	var posted []map[string]any
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		posted = append(posted, payload)
+	}))
+	defer webhook.Close()
+	cfg := config.DigestConfig{Schedule: "@daily", Webhook: webhook.URL}
+
+	// The first digest covers one period of the schedule
+	digest, sent, err := svc.RunDigest(context.Background(), cfg, time.Now())
+	if err != nil || !sent {
+		t.Fatalf("RunDigest = %v, %v; want sent", sent, err)
+	}
+	if len(digest.Changes) != 1 || digest.Changes[0].Kind != ChangeAdded {
+		t.Fatalf("changes = %+v, want notes added", digest.Changes)
+	}
+	if len(posted) != 1 {
+		t.Fatalf("webhook got %d posts, want 1", len(posted))
+	}
+	text, _ := posted[0]["text"].(string)
+	if !strings.HasPrefix(text, "# Prompt digest\n") || !strings.Contains(text, "## Added\n\n- `notes` v1.0.0 — Notes\n") {
+		t.Errorf("webhook text = %q", text)
+	}
+	if posted[0]["content"] != text {
+		t.Errorf("expected the Discord content to match the text")
+	}
+
+	// The next covers the changes since, and nothing is sent without any
+	if _, sent, err := svc.RunDigest(context.Background(), cfg, time.Now()); err != nil || sent {
+		t.Fatalf("RunDigest without changes = %v, %v; want skipped", sent, err)
+	}
+
+	prompt, _ := svc.GetPrompt("notes")
+	updated := *prompt
+	updated.Content = "one\n2"
+	if err := svc.UpdatePrompt(&updated); err != nil {
+		t.Fatalf("UpdatePrompt: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "brief", Name: "Brief", Content: "draft"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	digest, sent, err = svc.RunDigest(context.Background(), cfg, time.Now())
+	if err != nil || !sent {
+		t.Fatalf("RunDigest = %v, %v; want sent", sent, err)
+	}
+	if got := digest.Summary(); got != "1 added, 1 updated" {
+		t.Errorf("summary = %q", got)
+	}
+	if text, _ := posted[1]["text"].(string); !strings.Contains(text, "## Added\n\n- `brief` — Brief\n\n## Updated\n\n- `notes` v1.0.1 — Notes: +1/-1 lines\n") {
+		t.Errorf("webhook text = %q", text)
+	}
+
+	state, err := storage.LoadDigestState(tmpDir)
+	if err != nil || !state.LastSent.Equal(digest.Generated) {
+		t.Errorf("state = %+v, %v; want last sent %v", state, err, digest.Generated)
+	}
+}
+
+func TestDigestMarkdownLimit(t *testing.T) {
+	digest := &Digest{Title: "Weekly prompts", Since: time.Date(2026, 10, 9, 9, 0, 0, 0, time.UTC), limit: 2}
+	for _, id := range []string{"a", "b", "c"} {
+		digest.Changes = append(digest.Changes, PromptChange{ID: id, Title: strings.ToUpper(id), Version: "1.0.0", Kind: ChangeAdded})
+	}
+	want := "# Weekly prompts\n\nSince 9 Oct 2026 09:00: 3 added.\n\n## Added\n\n" +
+		"- `a` v1.0.0 — A\n- `b` v1.0.0 — B\n\n…and 1 more\n"
+	if got := digest.Markdown(); got != want {
+		t.Errorf("Markdown =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// fakeSMTP accepts one message and sends its recipients and data on the channel
+func fakeSMTP(t *testing.T) (string, chan []string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("220 localhost ready\r\n"))
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"):
+				conn.Write([]byte("250 localhost\r\n"))
+			case strings.HasPrefix(command, "RCPT"):
+				lines = append(lines, strings.TrimSpace(line))
+				conn.Write([]byte("250 OK\r\n"))
+			case command == "DATA":
+				conn.Write([]byte("354 go ahead\r\n"))
+				for {
+					data, err := reader.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+					lines = append(lines, strings.TrimRight(data, "\r\n"))
+				}
+				conn.Write([]byte("250 OK\r\n"))
+			case command == "QUIT":
+				conn.Write([]byte("221 bye\r\n"))
+				received <- lines
+				return
+			default:
+				conn.Write([]byte("250 OK\r\n"))
+			}
+		}
+	}()
+	return lis.Addr().String(), received
+}
+
+func TestEmailDigest(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	cfg := config.DigestConfig{SMTP: addr, From: "pkt@example.com", To: []string{"team@example.com"}}
+	digest := &Digest{
+		Title:       "Prompt digest",
+		Generated:   time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		SavedSearch: "légal",
+		Results:     []*models.Prompt{{ID: "nda", Name: "NDA review", Version: "2.0.0", Summary: "Check an NDA for unusual clauses"}},
+	}
+	if err := emailDigest(cfg, digest); err != nil {
+		t.Fatalf("emailDigest: %v", err)
+	}
+
+	lines := <-received
+	message := strings.Join(lines, "\n")
+	if lines[0] != "RCPT TO:<team@example.com>" {
+		t.Errorf("recipient = %q", lines[0])
+	}
+	if !strings.Contains(message, "Subject: =?utf-8?q?Prompt_digest:_1_prompt_in_l=C3=A9gal?=") {
+		t.Errorf("message headers:\n%s", message)
+	}
+	_, body, _ := strings.Cut(message, "\n\n")
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if want := "- `nda` v2.0.0 — NDA review: Check an NDA for unusual clauses"; !strings.Contains(string(decoded), want) {
+		t.Errorf("body = %q, want it to contain %q", decoded, want)
+	}
+}
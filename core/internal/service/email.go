@@ -0,0 +1,294 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/imap"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// EmailCheckResult lists the prompts created from one mailbox check, and the
+// messages that could not be turned into prompts
+type EmailCheckResult struct {
+	Prompts []*models.Prompt
+	Errors  []error
+}
+
+// CheckEmail imports messages that arrived since the last check and whose
+// subject starts with the configured prefix. Hashtags in the subject become
+// tags and the rest becomes the title; the plain-text body becomes the content.
+// Imported messages are marked read.
+func (s *Service) CheckEmail(cfg config.EmailConfig) (*EmailCheckResult, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("email gateway is not configured (pkt config set email.server <host:port>)")
+	}
+	password := os.Getenv(cfg.PasswordVar())
+	if password == "" {
+		return nil, fmt.Errorf("no mailbox password: set $%s", cfg.PasswordVar())
+	}
+
+	client, err := imap.Dial(cfg.Server, cfg.Insecure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cfg.Server, err)
+	}
+	defer client.Logout()
+
+	if err := client.Login(cfg.Username, password); err != nil {
+		return nil, err
+	}
+	validity, err := client.Select(cfg.MailboxName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mailbox %s: %w", cfg.MailboxName(), err)
+	}
+
+	baseDir := s.GetBaseDir()
+	state, err := storage.LoadEmailState(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if state.Mailbox != cfg.MailboxName() || state.UIDValidity != validity {
+		state = &storage.EmailState{Mailbox: cfg.MailboxName(), UIDValidity: validity}
+	}
+
+	// The server's subject match is a substring search, so the prefix is checked again per message
+	uids, err := client.Search(fmt.Sprintf("UID %d:* SUBJECT %s", state.LastUID+1, imap.Quote(cfg.Prefix())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search mailbox: %w", err)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	result := &EmailCheckResult{}
+	for _, uid := range uids {
+		// "n:*" always includes the newest message, even when it is older than n
+		if uid <= state.LastUID {
+			continue
+		}
+		prompt, err := s.importEmail(client, uid, cfg)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("message %d: %w", uid, err))
+		} else if prompt != nil {
+			result.Prompts = append(result.Prompts, prompt)
+		}
+
+		// Move past the message either way, so a malformed one is not retried forever
+		state.LastUID = uid
+		if err := storage.SaveEmailState(baseDir, state); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// PollEmail checks the mailbox every configured interval until ctx is done,
+// passing each check's outcome to onCheck
+func (s *Service) PollEmail(ctx context.Context, cfg config.EmailConfig, onCheck func(*EmailCheckResult, error)) error {
+	interval, err := cfg.PollInterval()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		onCheck(s.CheckEmail(cfg))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) importEmail(client *imap.Client, uid uint32, cfg config.EmailConfig) (*models.Prompt, error) {
+	raw, err := client.Fetch(uid)
+	if err != nil {
+		return nil, err
+	}
+	email, err := parseEmail(raw, cfg.Prefix())
+	if err != nil || email == nil {
+		return nil, err
+	}
+
+	prompt, err := s.newQuickPrompt(email.body, email.title, append(append([]string{}, cfg.Tags...), email.tags...))
+	if err != nil {
+		return nil, err
+	}
+	prompt.Metadata = map[string]interface{}{"source": "email", "from": email.from}
+	if email.messageID != "" {
+		prompt.Metadata["message_id"] = email.messageID
+	}
+	if err := s.CreatePrompt(prompt); err != nil {
+		return nil, err
+	}
+	return prompt, client.MarkSeen(uid)
+}
+
+// emailPrompt holds the parts of a message that make up a prompt
+type emailPrompt struct {
+	title     string
+	tags      []string
+	body      string
+	from      string
+	messageID string
+}
+
+var subjectTag = regexp.MustCompile(`(^|\s)#([\w-]+)`)
+
+// parseEmail reads a raw message, returning nil when its subject does not
+// start with prefix
+func parseEmail(raw []byte, prefix string) (*emailPrompt, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	decoder := &mime.WordDecoder{}
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	subject = strings.TrimSpace(subject)
+	if len(subject) < len(prefix) || !strings.EqualFold(subject[:len(prefix)], prefix) {
+		return nil, nil
+	}
+	subject = subject[len(prefix):]
+
+	email := &emailPrompt{messageID: strings.Trim(msg.Header.Get("Message-Id"), "<>")}
+	for _, match := range subjectTag.FindAllStringSubmatch(subject, -1) {
+		email.tags = append(email.tags, strings.ToLower(match[2]))
+	}
+	email.title = strings.Join(strings.Fields(subjectTag.ReplaceAllString(subject, " ")), " ")
+
+	if from, err := msg.Header.AddressList("From"); err == nil && len(from) > 0 {
+		email.from = from[0].Address
+	}
+
+	body, err := textBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	email.body = strings.TrimSpace(withoutSignature(body))
+	if email.body == "" {
+		// A subject-only message is itself the prompt
+		email.body = email.title
+	}
+	return email, nil
+}
+
+// withoutSignature drops the signature block below the "-- " delimiter line.
+// Quoted-printable decoding strips the delimiter's trailing space.
+func withoutSignature(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " ") == "--" {
+			return strings.Join(lines[:i], "\n")
+		}
+	}
+	return body
+}
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+	htmlTag   = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// textBody extracts the readable text of a message body, preferring
+// text/plain over text/html in multipart messages
+func textBody(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	body = decodeTransfer(encoding, body)
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		var fallback string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to read message part: %w", err)
+			}
+			partType := part.Header.Get("Content-Type")
+			if partType == "" {
+				partType = "text/plain"
+			}
+			text, err := textBody(partType, part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+			if strings.HasPrefix(partType, "text/plain") && text != "" {
+				return text, nil
+			}
+			if fallback == "" {
+				fallback = text
+			}
+		}
+		return fallback, nil
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message body: %w", err)
+	}
+	switch mediaType {
+	case "text/plain":
+		return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+	case "text/html":
+		text := strings.ReplaceAll(string(data), "\r\n", "\n")
+		text = htmlTag.ReplaceAllString(htmlBreak.ReplaceAllString(text, "\n"), "")
+		return html.UnescapeString(text), nil
+	default:
+		return "", nil // Attachments and other parts carry no prompt text
+	}
+}
+
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// newlineStripper drops the line breaks base64 bodies are wrapped with
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	kept := 0
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
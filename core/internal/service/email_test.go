@@ -0,0 +1,165 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+const multipartEmail = "From: Ana <ana@example.com>\r\n" +
+	"Subject: [PKT] Review a pull request #Code-Review #dev\r\n" +
+	"Message-ID: <abc@example.com>\r\n" +
+	"Content-Type: multipart/alternative; boundary=XYZ\r\n" +
+	"\r\n" +
+	"--XYZ\r\n" +
+	"Content-Type: text/html\r\n" +
+	"\r\n" +
+	"<p>Ignored</p>\r\n" +
+	"--XYZ\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Review this diff for bugs and =\r\n" +
+	"style issues.\r\n" +
+	"\r\n" +
+	"-- \r\n" +
+	"Sent from my phone\r\n" +
+	"--XYZ--\r\n"
+
+func TestParseEmail(t *testing.T) {
+	email, err := parseEmail([]byte(multipartEmail), "[pkt]")
+	if err != nil {
+		t.Fatalf("parseEmail: %v", err)
+	}
+	if email == nil {
+		t.Fatal("expected the prefix to match regardless of case")
+	}
+	if email.title != "Review a pull request" {
+		t.Errorf("title = %q", email.title)
+	}
+	if strings.Join(email.tags, ",") != "code-review,dev" {
+		t.Errorf("tags = %v", email.tags)
+	}
+	if email.body != "Review this diff for bugs and style issues." {
+		t.Errorf("body = %q", email.body)
+	}
+	if email.from != "ana@example.com" || email.messageID != "abc@example.com" {
+		t.Errorf("from/message id = %q/%q", email.from, email.messageID)
+	}
+
+	other := "Subject: Re: [pkt] not a capture\r\n\r\nbody\r\n"
+	if email, err := parseEmail([]byte(other), "[pkt]"); err != nil || email != nil {
+		t.Errorf("expected a subject without the leading prefix to be skipped, got %+v, %v", email, err)
+	}
+
+	subjectOnly := "Subject: [pkt] Explain this error like I'm new #debug\r\n\r\n"
+	email, err = parseEmail([]byte(subjectOnly), "[pkt]")
+	if err != nil || email == nil {
+		t.Fatalf("parseEmail: %+v, %v", email, err)
+	}
+	if email.body != "Explain this error like I'm new" {
+		t.Errorf("expected the subject to stand in for an empty body, got %q", email.body)
+	}
+}
+
+// fakeIMAP serves one mailbox session with the given messages, keyed by UID
+func fakeIMAP(t *testing.T, messages map[uint32]string) (addr string, seen chan uint32) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	seen = make(chan uint32, len(messages))
+
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "* OK ready\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			tag, command, _ := strings.Cut(strings.TrimSpace(line), " ")
+			switch {
+			case strings.HasPrefix(command, "SELECT"):
+				fmt.Fprint(conn, "* OK [UIDVALIDITY 7] UIDs valid\r\n")
+			case strings.HasPrefix(command, "UID SEARCH"):
+				fmt.Fprint(conn, "* SEARCH 3 5\r\n")
+			case strings.HasPrefix(command, "UID FETCH"):
+				var uid uint32
+				fmt.Sscanf(command, "UID FETCH %d", &uid)
+				body := messages[uid]
+				fmt.Fprintf(conn, "* 1 FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", uid, len(body), body)
+			case strings.HasPrefix(command, "UID STORE"):
+				var uid uint32
+				fmt.Sscanf(command, "UID STORE %d", &uid)
+				seen <- uid
+			}
+			fmt.Fprintf(conn, "%s OK done\r\n", tag)
+			if command == "LOGOUT" {
+				return
+			}
+		}
+	}()
+	return lis.Addr().String(), seen
+}
+
+func TestCheckEmail(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+
+	addr, seen := fakeIMAP(t, map[uint32]string{
+		3: multipartEmail,
+		5: "Subject: Weekly newsletter [pkt]\r\n\r\nNot a capture\r\n",
+	})
+	t.Setenv("POCKET_PROMPT_EMAIL_PASSWORD", "secret")
+	cfg := config.EmailConfig{Server: addr, Username: "me", Insecure: true, Tags: []string{"email"}}
+
+	result, err := svc.CheckEmail(cfg)
+	if err != nil {
+		t.Fatalf("CheckEmail: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.Prompts) != 1 {
+		t.Fatalf("expected one prompt and no errors, got %d, %v", len(result.Prompts), result.Errors)
+	}
+	prompt := result.Prompts[0]
+	if strings.Join(prompt.Tags, ",") != "email,code-review,dev" {
+		t.Errorf("tags = %v", prompt.Tags)
+	}
+	if prompt.Metadata["source"] != "email" || prompt.Metadata["from"] != "ana@example.com" {
+		t.Errorf("metadata = %v", prompt.Metadata)
+	}
+	if _, err := svc.GetPrompt(prompt.ID); err != nil {
+		t.Errorf("expected the prompt to be saved: %v", err)
+	}
+	if uid := <-seen; uid != 3 || len(seen) != 0 {
+		t.Errorf("expected only message 3 to be marked read, got %d", uid)
+	}
+
+	state, err := storage.LoadEmailState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadEmailState: %v", err)
+	}
+	if state.UIDValidity != 7 || state.LastUID != 5 {
+		t.Errorf("state = %+v, want UIDVALIDITY 7 and last UID 5", state)
+	}
+}
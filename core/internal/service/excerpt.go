@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Excerpt bounds: sentences taken by default and at most, and the longest
+// excerpt in characters
+const (
+	DefaultExcerptSentences = 2
+	MaxExcerptSentences     = 10
+	maxExcerptRunes         = 400
+)
+
+// Excerpt returns the first sentences of content as one line of plain text,
+// for a teaser that doesn't need the whole prompt. Markdown headings, list
+// markers and code fences are dropped, and an excerpt that would still run
+// past a few hundred characters is cut short with an ellipsis.
+func Excerpt(content string, sentences int) string {
+	if sentences < 1 {
+		sentences = DefaultExcerptSentences
+	}
+
+	var words []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			continue
+		}
+		line = strings.TrimLeft(line, "#>-*+ ")
+		words = append(words, strings.Fields(line)...)
+	}
+	text := strings.Join(words, " ")
+
+	// A sentence ends at ., ! or ? followed by a space or the end of the text
+	end := len(text)
+	found := 0
+	for i, r := range text {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		if i+1 < len(text) && text[i+1] != ' ' {
+			continue
+		}
+		found++
+		if found == sentences {
+			end = i + 1
+			break
+		}
+	}
+	text = text[:end]
+
+	if utf8.RuneCountInString(text) > maxExcerptRunes {
+		runes := []rune(text)[:maxExcerptRunes]
+		text = strings.TrimRight(string(runes), " ") + "…"
+	}
+	return text
+}
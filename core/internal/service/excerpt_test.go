@@ -0,0 +1,32 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		sentences int
+		want      string
+	}{
+		{"first two", "You review code. Be direct. Cite lines.", 2, "You review code. Be direct."},
+		{"default", "One. Two. Three.", 0, "One. Two."},
+		{"across lines", "# Reviewer\n\nYou review\ncode carefully! Then\nsummarize.", 2, "Reviewer You review code carefully! Then summarize."},
+		{"no end", "Fill in {{topic}}", 2, "Fill in {{topic}}"},
+		{"decimal", "Use version 1.2 only. Stop.", 1, "Use version 1.2 only."},
+		{"lists and fences", "- First item.\n```go\nx := 1\n```\n* Second item.", 2, "First item. x := 1 Second item."},
+	}
+	for _, tt := range tests {
+		if got := Excerpt(tt.content, tt.sentences); got != tt.want {
+			t.Errorf("%s: Excerpt = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	long := Excerpt(strings.Repeat("word ", 200), 1)
+	if !strings.HasSuffix(long, "…") || len([]rune(long)) > maxExcerptRunes+1 {
+		t.Errorf("Long excerpt = %d characters, want it cut to %d with an ellipsis", len([]rune(long)), maxExcerptRunes)
+	}
+}
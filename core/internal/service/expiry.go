@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// ExpiringPrompt is a prompt with an expires date that has passed or is near
+type ExpiringPrompt struct {
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Path    string    `json:"path"`
+	Expires string    `json:"expires"`    // As written in the frontmatter
+	At      time.Time `json:"expires_at"` // When it expires: a date's end, in local time
+}
+
+// expiryScan sorts the prompts with an expires date by where they stand at
+// a point in time
+type expiryScan struct {
+	expired  []*models.Prompt // Past their expires date, soonest expired first
+	expiring []*models.Prompt // Expiring within the warning period, soonest first
+	invalid  []string         // Problems with expires dates that cannot be read
+}
+
+// scanExpiry finds the prompts that have expired by now or expire within
+// warning of it
+func (s *Service) scanExpiry(now time.Time, warning time.Duration) (*expiryScan, error) {
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	scan := &expiryScan{}
+	expiries := map[*models.Prompt]time.Time{}
+	for _, p := range prompts {
+		expires, err := p.Expiry()
+		if err != nil {
+			scan.invalid = append(scan.invalid, fmt.Sprintf("%s: %v", p.FilePath, err))
+			continue
+		}
+		switch {
+		case expires.IsZero():
+			continue
+		case !expires.After(now):
+			scan.expired = append(scan.expired, p)
+		case expires.Sub(now) <= warning:
+			scan.expiring = append(scan.expiring, p)
+		default:
+			continue
+		}
+		expiries[p] = expires
+	}
+
+	for _, list := range [][]*models.Prompt{scan.expired, scan.expiring} {
+		sort.SliceStable(list, func(i, j int) bool {
+			if !expiries[list[i]].Equal(expiries[list[j]]) {
+				return expiries[list[i]].Before(expiries[list[j]])
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+	sort.Strings(scan.invalid)
+	return scan, nil
+}
+
+// expiringPrompt describes a prompt for a maintenance report
+func expiringPrompt(p *models.Prompt) ExpiringPrompt {
+	at, _ := p.Expiry()
+	return ExpiringPrompt{ID: p.ID, Title: p.Title(), Path: p.FilePath, Expires: p.Expires, At: at}
+}
+
+// archiveExpired moves an expired prompt into the archive, from which it can
+// be restored, and removes it from the library
+func (s *Service) archiveExpired(p *models.Prompt) error {
+	if err := s.CheckProtected(p); err != nil {
+		return err
+	}
+	lib := s.libraryFor(p.ID)
+	prompt, err := lib.storage.LoadPrompt(p.FilePath)
+	if err != nil {
+		return err
+	}
+	if err := lib.archivePromptByTag(prompt); err != nil {
+		return fmt.Errorf("failed to archive: %w", err)
+	}
+	if err := lib.storage.DeletePrompt(prompt); err != nil {
+		return fmt.Errorf("failed to remove after archiving: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// BooleanExplanation describes how a boolean expression parses and what each
+// part of it matches, for debugging searches
+type BooleanExplanation struct {
+	Expression   string               `json:"expression"`
+	Valid        bool                 `json:"valid"`
+	Normalized   string               `json:"normalized,omitempty"` // Parses back to the same tree
+	Tree         *ExplainedNode       `json:"ast,omitempty"`
+	Tags         []TagMatch           `json:"tags,omitempty"`
+	Matches      int                  `json:"matches"`
+	TotalPrompts int                  `json:"total_prompts"`
+	Errors       []models.SyntaxError `json:"errors,omitempty"`
+}
+
+// ExplainedNode is one node of a parsed expression with the number of prompts
+// it matches on its own
+type ExplainedNode struct {
+	Type     models.ExpressionType `json:"type"`
+	Tag      string                `json:"tag,omitempty"`
+	Matches  int                   `json:"matches"`
+	Children []*ExplainedNode      `json:"children,omitempty"`
+}
+
+// TagMatch is how many prompts carry a tag the expression refers to
+type TagMatch struct {
+	Tag     string `json:"tag"`
+	Prompts int    `json:"prompts"`
+}
+
+// ExplainBooleanExpression parses expr and counts the prompts matched by the
+// whole expression, each part of it and each tag it uses. A syntax error is
+// reported in the explanation rather than returned.
+func (s *Service) ExplainBooleanExpression(expr string) (*BooleanExplanation, error) {
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &BooleanExplanation{Expression: expr, TotalPrompts: len(prompts)}
+	parsed, err := models.ParseBooleanExpression(expr)
+	if err != nil {
+		var syntaxErr *models.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			return nil, err
+		}
+		explanation.Errors = []models.SyntaxError{*syntaxErr}
+		return explanation, nil
+	}
+
+	explanation.Valid = true
+	explanation.Normalized = parsed.QueryString()
+	explanation.Tree = explainNode(parsed, prompts)
+	explanation.Matches = explanation.Tree.Matches
+
+	seen := make(map[string]bool)
+	for _, tag := range parsed.Tags() {
+		if seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		explanation.Tags = append(explanation.Tags, TagMatch{Tag: tag, Prompts: countMatches(models.NewTagExpression(tag), prompts)})
+	}
+	return explanation, nil
+}
+
+// explainNode mirrors expr as ExplainedNodes, counting matches at every level
+func explainNode(expr *models.BooleanExpression, prompts []*models.Prompt) *ExplainedNode {
+	node := &ExplainedNode{Type: expr.Type, Matches: countMatches(expr, prompts)}
+	switch value := expr.Value.(type) {
+	case string:
+		node.Tag = value
+	case []*models.BooleanExpression:
+		for _, child := range value {
+			node.Children = append(node.Children, explainNode(child, prompts))
+		}
+	}
+	return node
+}
+
+func countMatches(expr *models.BooleanExpression, prompts []*models.Prompt) int {
+	n := 0
+	for _, p := range prompts {
+		if expr.Evaluate(p.Tags) {
+			n++
+		}
+	}
+	return n
+}
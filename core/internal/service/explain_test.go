@@ -0,0 +1,56 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestExplainBooleanExpression(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "one", Name: "One", Tags: []string{"ai", "writing"}, Content: "One"},
+		{ID: "two", Name: "Two", Tags: []string{"ai"}, Content: "Two"},
+		{ID: "three", Name: "Three", Tags: []string{"draft"}, Content: "Three"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	explanation, err := svc.ExplainBooleanExpression("ai AND (writing OR NOT draft) AND AI")
+	if err != nil {
+		t.Fatalf("ExplainBooleanExpression: %v", err)
+	}
+	if !explanation.Valid || explanation.Matches != 2 || explanation.TotalPrompts != 3 {
+		t.Errorf("Expected a valid expression matching 2 of 3 prompts, got %+v", explanation)
+	}
+	if want := "ai AND (writing OR NOT draft) AND AI"; explanation.Normalized != want {
+		t.Errorf("Normalized = %q, want %q", explanation.Normalized, want)
+	}
+	if len(explanation.Tags) != 3 || explanation.Tags[0] != (TagMatch{Tag: "ai", Prompts: 2}) || explanation.Tags[2] != (TagMatch{Tag: "draft", Prompts: 1}) {
+		t.Errorf("Tags = %+v", explanation.Tags)
+	}
+	group := explanation.Tree.Children[1]
+	if group.Type != models.ExpressionOr || group.Matches != 2 || group.Children[1].Matches != 2 {
+		t.Errorf("Expected the OR group and NOT draft to match 2 prompts each, got %+v", group)
+	}
+
+	explanation, err = svc.ExplainBooleanExpression("ai AND (writing")
+	if err != nil {
+		t.Fatalf("ExplainBooleanExpression: %v", err)
+	}
+	if explanation.Valid || len(explanation.Errors) != 1 || explanation.Errors[0].Position != 8 {
+		t.Errorf("Expected one syntax error at column 8, got %+v", explanation)
+	}
+}
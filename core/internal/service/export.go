@@ -0,0 +1,114 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// ExportSelection returns what an export of scope covers: "all", or "", for
+// every prompt and template, and "tag:<tag>" for the prompts with that tag.
+// Prompts come with their content, which the metadata cache leaves out.
+func (s *Service) ExportSelection(scope string) ([]*models.Prompt, []*models.Template, error) {
+	if scope == "" || scope == "all" {
+		prompts, err := s.ListPrompts()
+		if err != nil {
+			return nil, nil, err
+		}
+		prompts, err = s.WithContent(prompts)
+		if err != nil {
+			return nil, nil, err
+		}
+		// A library without a templates folder simply has none
+		templates, err := s.ListTemplates()
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, err
+		}
+		return prompts, templates, nil
+	}
+
+	tag, ok := strings.CutPrefix(scope, "tag:")
+	if !ok || tag == "" {
+		return nil, nil, fmt.Errorf("invalid export scope %q (use all or tag:<tag>)", scope)
+	}
+	prompts, err := s.FilterPromptsByTag(tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	prompts, err = s.WithContent(prompts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prompts, nil, nil
+}
+
+// WriteExportZip writes prompts and templates to w as a zip holding each one's
+// file at its path in the library, so unzipping it into an empty directory
+// gives a library. Entries are written as they are encoded, so a large
+// library is never held in memory as a whole.
+func WriteExportZip(w io.Writer, prompts []*models.Prompt, templates []*models.Template) error {
+	archive := zip.NewWriter(w)
+	for _, prompt := range prompts {
+		content, err := storage.EncodePrompt(prompt)
+		if err != nil {
+			return fmt.Errorf("failed to encode prompt %s: %w", prompt.ID, err)
+		}
+		if err := writeExportEntry(archive, exportPath(prompt.FilePath, "prompts", prompt.ID), content, prompt.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	for _, template := range templates {
+		content, err := storage.EncodeTemplate(template)
+		if err != nil {
+			return fmt.Errorf("failed to encode template %s: %w", template.ID, err)
+		}
+		if err := writeExportEntry(archive, exportPath(template.FilePath, "templates", template.ID), content, template.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// WriteExportNDJSON writes prompts to w as newline-delimited JSON, one prompt
+// per line
+func WriteExportNDJSON(w io.Writer, prompts []*models.Prompt) error {
+	encoder := json.NewEncoder(w)
+	for _, prompt := range prompts {
+		if err := encoder.Encode(prompt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeExportEntry(archive *zip.Writer, name string, content []byte, modified time.Time) error {
+	entry, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = entry.Write(content)
+	return err
+}
+
+// exportPath returns the zip entry name for a file at filePath in the
+// library, falling back to dir/id.md for items that were never saved
+func exportPath(filePath, dir, id string) string {
+	if filePath == "" || filepath.IsAbs(filePath) {
+		return path.Join(dir, id+".md")
+	}
+	return filepath.ToSlash(filepath.Clean(filePath))
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestExportSelection(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "greet", Name: "Greet", Tags: []string{"work"}, Content: "Say hello"},
+		{ID: "part", Name: "Part", Content: "Say goodbye"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	prompts, templates, err := svc.ExportSelection("tag:work")
+	if err != nil {
+		t.Fatalf("ExportSelection: %v", err)
+	}
+	if len(prompts) != 1 || prompts[0].ID != "greet" || templates != nil {
+		t.Fatalf("tag:work selected %d prompts and %d templates, want greet only", len(prompts), len(templates))
+	}
+	if _, _, err := svc.ExportSelection("pack:work"); err == nil {
+		t.Error("Expected an error for an unknown scope")
+	}
+
+	prompts, templates, err = svc.ExportSelection("all")
+	if err != nil {
+		t.Fatalf("ExportSelection: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteExportZip(&buf, prompts, templates); err != nil {
+		t.Fatalf("WriteExportZip: %v", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	if len(files) != 2 || !strings.Contains(files["prompts/greet.md"], "Say hello") {
+		t.Errorf("Zip holds %v, want prompts/greet.md and prompts/part.md", files)
+	}
+}
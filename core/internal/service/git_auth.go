@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dpshade/pocket-prompt/internal/git"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// Where SetGitToken reads the HTTPS token from
+const (
+	TokenFromEnv      = "env"
+	TokenFromFile     = "file"
+	TokenFromKeychain = "keychain"
+)
+
+// TestGitAuth checks that the origin remote accepts the library's
+// credentials for fetching and pushing
+func (s *Service) TestGitAuth() (*git.AuthReport, error) {
+	if !s.gitSync.IsInitialized() {
+		return nil, fmt.Errorf("git is not initialized in %s", s.GetBaseDir())
+	}
+	return s.gitSync.TestAuth()
+}
+
+// SetGitSSHKey saves the private key git sync uses for SSH remotes, in place
+// of ssh-agent and the default keys. An empty path goes back to those.
+func (s *Service) SetGitSSHKey(path string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("git SSH key: %w", err)
+		}
+	}
+	s.settings.Git.SSHKey = path
+	if err := s.settings.Save(); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return s.configureGitAuth()
+}
+
+// SetGitToken saves where git sync reads the token for HTTPS remotes from:
+// TokenFromEnv with a variable name, TokenFromFile with a path or
+// TokenFromKeychain with a "service" or "service/account" entry. It replaces
+// any other source; an empty from removes them all.
+func (s *Service) SetGitToken(from, value string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	cfg := &s.settings.Git
+	previous := *cfg
+	cfg.TokenEnv, cfg.TokenFile, cfg.TokenKeychain = "", "", ""
+	switch from {
+	case TokenFromEnv:
+		cfg.TokenEnv = value
+	case TokenFromFile:
+		cfg.TokenFile = value
+	case TokenFromKeychain:
+		cfg.TokenKeychain = value
+	case "":
+	default:
+		*cfg = previous
+		return fmt.Errorf("invalid token source %q (use %s, %s or %s)", from, TokenFromEnv, TokenFromFile, TokenFromKeychain)
+	}
+	if from != "" && value == "" {
+		*cfg = previous
+		return fmt.Errorf("no %s given for the git token", from)
+	}
+	if _, _, err := cfg.Token(); err != nil {
+		*cfg = previous
+		return err
+	}
+	if err := s.settings.Save(); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return s.configureGitAuth()
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+func TestGitAuth(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	remote := filepath.Join(tmpDir, "remote.git")
+	if out, err := exec.Command("git", "init", "--bare", "-q", remote).CombinedOutput(); err != nil {
+		t.Skipf("git is not available: %v: %s", err, out)
+	}
+	libDir := filepath.Join(tmpDir, "library")
+	svc, err := OpenLibrary(libDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+
+	if _, err := svc.TestGitAuth(); err == nil {
+		t.Fatal("TestGitAuth without git succeeded")
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "Initial"},
+		{"remote", "add", "origin", remote},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = libDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	report, err := svc.TestGitAuth()
+	if err != nil {
+		t.Fatalf("TestGitAuth: %v", err)
+	}
+	if !report.Read || !report.Write || report.Error != "" || report.Method != "local path" {
+		t.Errorf("report = %+v, want read and write over a local path", report)
+	}
+
+	// A token source replaces the others and is saved
+	t.Setenv("PKT_TEST_GIT_TOKEN", "secret")
+	if err := svc.SetGitToken(TokenFromFile, filepath.Join(tmpDir, "token")); err == nil {
+		t.Error("SetGitToken accepted a missing token file")
+	}
+	if err := svc.SetGitToken(TokenFromEnv, "PKT_TEST_GIT_TOKEN"); err != nil {
+		t.Fatalf("SetGitToken: %v", err)
+	}
+	saved, err := config.LoadConfig(svc.GetBaseDir())
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if saved.Git.TokenEnv != "PKT_TEST_GIT_TOKEN" || saved.Git.TokenFile != "" {
+		t.Errorf("saved git config = %+v, want token_env only", saved.Git)
+	}
+	if err := svc.SetGitToken("vault", "x"); err == nil {
+		t.Error("SetGitToken accepted an unknown source")
+	}
+	if err := svc.SetGitSSHKey(filepath.Join(tmpDir, "missing_key")); err == nil {
+		t.Error("SetGitSSHKey accepted a missing key")
+	}
+}
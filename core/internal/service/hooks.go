@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/git"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// InstallPreCommitHook installs a git pre-commit hook in the library that
+// runs 'pkt lint --staged-only', blocking commits with broken frontmatter,
+// duplicate IDs or lint errors. It returns the hook's path.
+func (s *Service) InstallPreCommitHook(force bool) (string, error) {
+	if s.ReadOnly() {
+		return "", storage.ErrReadOnly
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the pkt executable: %w", err)
+	}
+	library, err := filepath.Abs(s.GetBaseDir())
+	if err != nil {
+		return "", err
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+%s
+# Checks staged prompts before each commit. Skip it once with
+# 'git commit --no-verify'; run 'pkt hooks install' again after moving pkt.
+POCKET_PROMPT_DIR=%s exec %s lint --staged-only
+`, git.HookMarker, shellQuote(library), shellQuote(executable))
+
+	return s.gitSync.InstallHook("pre-commit", script, force)
+}
+
+// shellQuote quotes a value for a POSIX shell
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Status of an import candidate compared with the library
+const (
+	CandidateNew       = "new"
+	CandidateChanged   = "changed"
+	CandidateUnchanged = "unchanged"
+)
+
+// ImportCandidate is an item an import would bring in, compared with what
+// the library already holds under the same ID
+type ImportCandidate struct {
+	Key    string // Selection key, see importer.ItemKey
+	Kind   string // importer.ItemPrompt, ItemTemplate or ItemWorkflow
+	ID     string
+	Title  string
+	Status string
+	Diff   []string // For changed items: metadata notes, then content lines prefixed "+ ", "- " or "  "
+	// For changed prompts: the content in the library and in the import,
+	// for resolving the conflict in a merge
+	Local, Incoming string
+}
+
+// ImportCandidates lists the items of a previewed import with diffs against
+// existing prompts and templates, for choosing which to bring in
+func (s *Service) ImportCandidates(result *importer.ImportResult) []ImportCandidate {
+	var candidates []ImportCandidate
+	for _, p := range result.Prompts {
+		candidates = append(candidates, s.promptCandidate(importer.ItemPrompt, p))
+	}
+	for _, p := range result.Workflows {
+		candidates = append(candidates, s.promptCandidate(importer.ItemWorkflow, p))
+	}
+	for _, t := range result.Templates {
+		candidate := ImportCandidate{
+			Key:    importer.ItemKey(importer.ItemTemplate, t.ID),
+			Kind:   importer.ItemTemplate,
+			ID:     t.ID,
+			Title:  t.Name,
+			Status: CandidateNew,
+		}
+		if existing, err := s.GetTemplate(t.ID); err == nil {
+			candidate.Status = CandidateUnchanged
+			if existing.Content != t.Content || existing.Name != t.Name {
+				candidate.Status = CandidateChanged
+				if existing.Name != t.Name {
+					candidate.Diff = append(candidate.Diff, fmt.Sprintf("name %q → %q", existing.Name, t.Name))
+				}
+				candidate.Diff = append(candidate.Diff, diff.Lines(existing.Content, t.Content)...)
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+func (s *Service) promptCandidate(kind string, p *models.Prompt) ImportCandidate {
+	candidate := ImportCandidate{
+		Key:    importer.ItemKey(kind, p.ID),
+		Kind:   kind,
+		ID:     p.ID,
+		Title:  p.Title(),
+		Status: CandidateNew,
+	}
+	existing, err := s.GetPrompt(p.ID)
+	if err != nil {
+		return candidate
+	}
+
+	// Matches what savePromptWithConflictResolution treats as a change
+	candidate.Status = CandidateUnchanged
+	if existing.Content == p.Content && equalStringSlices(existing.Tags, p.Tags) && equalMetadata(existing.Metadata, p.Metadata) {
+		return candidate
+	}
+	candidate.Status = CandidateChanged
+	if existing.Name != p.Name {
+		candidate.Diff = append(candidate.Diff, fmt.Sprintf("title %q → %q", existing.Name, p.Name))
+	}
+	if tags := tagChanges(existing.Tags, p.Tags); tags != "" {
+		candidate.Diff = append(candidate.Diff, tags)
+	}
+	if !equalMetadata(existing.Metadata, p.Metadata) {
+		candidate.Diff = append(candidate.Diff, "metadata updated")
+	}
+	if existing.Content != p.Content {
+		candidate.Diff = append(candidate.Diff, diff.Lines(existing.Content, p.Content)...)
+		candidate.Local, candidate.Incoming = existing.Content, p.Content
+	}
+	return candidate
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestImportCandidates(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "same", Name: "Same", Content: "unchanged body"},
+		{ID: "edited", Name: "Edited", Content: "first line\nold line"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	result := &importer.ImportResult{Prompts: []*models.Prompt{
+		{ID: "same", Name: "Same", Content: "unchanged body"},
+		{ID: "edited", Name: "Edited", Content: "first line\nnew line"},
+		{ID: "fresh", Name: "Fresh", Content: "brand new"},
+	}}
+	status := make(map[string]ImportCandidate)
+	for _, c := range svc.ImportCandidates(result) {
+		status[c.ID] = c
+	}
+	if status["same"].Status != CandidateUnchanged || status["fresh"].Status != CandidateNew {
+		t.Fatalf("unexpected statuses: %+v", status)
+	}
+	edited := status["edited"]
+	if edited.Status != CandidateChanged {
+		t.Fatalf("edited status = %q, want %q", edited.Status, CandidateChanged)
+	}
+	if got := strings.Join(edited.Diff, "|"); got != "  first line|- old line|+ new line" {
+		t.Errorf("edited diff = %q", got)
+	}
+
+	if edited.Local != "first line\nold line" || edited.Incoming != "first line\nnew line" {
+		t.Errorf("edited sides = %q and %q", edited.Local, edited.Incoming)
+	}
+
+	merged := "first line\nold line\nnew line"
+	options := importer.ImportOptions{
+		Selection: map[string]bool{edited.Key: true},
+		Merged:    map[string]string{edited.Key: merged},
+	}
+	result.ApplySelection(options)
+	if len(result.Prompts) != 1 || result.Prompts[0].ID != "edited" {
+		t.Fatalf("ApplySelection kept %d prompts, want only edited", len(result.Prompts))
+	}
+	if result.Prompts[0].Content != merged {
+		t.Errorf("edited content = %q, want the merged content", result.Prompts[0].Content)
+	}
+}
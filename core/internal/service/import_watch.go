@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// WatchClaudeCode imports from Claude Code, then checks the commands and agents
+// directories every interval and imports again whenever a file is added or
+// edited, until ctx is done. Each round brings in only new and changed items,
+// matched to earlier imports by original file path. onImport receives every
+// round that imported something or hit errors. Prompts whose source file is
+// deleted stay in the library.
+func (s *Service) WatchClaudeCode(ctx context.Context, options importer.ImportOptions, interval time.Duration, onImport func(*importer.ImportResult)) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	options.DryRun = false
+	options.DeduplicateByPath = true
+	claudeImporter := importer.NewClaudeCodeImporter(s.storage.GetBaseDir())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var state map[string]time.Time
+	for {
+		// Capture the state before importing so edits made during the import are seen next round
+		current := claudeImporter.SourceState(options)
+		if state == nil || !maps.EqualFunc(current, state, time.Time.Equal) {
+			state = current
+			result, err := s.importClaudeCodeChanges(options)
+			if err != nil {
+				return err
+			}
+			if result != nil {
+				onImport(result)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// importClaudeCodeChanges imports the Claude Code items that are new or differ
+// from the library, returning nil when there is nothing to report
+func (s *Service) importClaudeCodeChanges(options importer.ImportOptions) (*importer.ImportResult, error) {
+	preview := options
+	preview.DryRun = true
+	found, err := s.ImportFromClaudeCode(preview)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := make(map[string]bool)
+	for _, candidate := range s.ImportCandidates(found) {
+		if candidate.Status != CandidateUnchanged {
+			selection[candidate.Key] = true
+		}
+	}
+	if len(selection) == 0 {
+		if len(found.Errors) == 0 {
+			return nil, nil
+		}
+		found.Prompts, found.Workflows = nil, nil
+		return found, nil
+	}
+
+	options.Selection = selection
+	return s.ImportFromClaudeCode(options)
+}
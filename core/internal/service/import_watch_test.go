@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+)
+
+func TestWatchClaudeCodeImportsChangesOnce(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	project := filepath.Join(tmpDir, "project")
+	commands := filepath.Join(project, ".claude", "commands")
+	if err := os.MkdirAll(commands, 0755); err != nil {
+		t.Fatalf("Failed to create commands directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(commands, "review.md"), []byte("# Review\nCheck the diff.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write command: %v", err)
+	}
+
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+
+	// Each watch stops after its first round; the second finds nothing new to import
+	var imported []int
+	for round := 0; round < 2; round++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		err := svc.WatchClaudeCode(ctx, importer.ImportOptions{Path: project}, time.Hour, func(result *importer.ImportResult) {
+			imported = append(imported, len(result.Prompts))
+			cancel()
+		})
+		cancel()
+		if err != nil {
+			t.Fatalf("WatchClaudeCode: %v", err)
+		}
+	}
+
+	if len(imported) != 1 || imported[0] != 1 {
+		t.Fatalf("imports per round = %v, want a single round importing 1 prompt", imported)
+	}
+	prompt, err := svc.GetPrompt("claude-code-review")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if prompt.Version != "1.0.0" {
+		t.Errorf("version = %s, want 1.0.0 after re-watching an unchanged file", prompt.Version)
+	}
+}
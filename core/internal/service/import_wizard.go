@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+)
+
+// Kinds of import source the import wizard finds
+const (
+	SourceClaudeCode = "claude-code"
+	SourceObsidian   = importer.MarkdownObsidian
+	SourceMarkdown   = importer.MarkdownFolder
+	SourceGitRepo    = "git-repo"
+)
+
+// ImportSource is a place on this machine the import wizard can bring
+// prompts in from
+type ImportSource struct {
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`
+	RepoURL string `json:"repo_url,omitempty"` // The origin of a git checkout
+	Items   int    `json:"items"`              // Prompts and templates an import finds
+}
+
+// Label names the kind of source for people
+func (src ImportSource) Label() string {
+	switch src.Kind {
+	case SourceClaudeCode:
+		return "Claude Code"
+	case SourceObsidian:
+		return "Obsidian vault"
+	case SourceGitRepo:
+		return "Git repository"
+	default:
+		return "Markdown folder"
+	}
+}
+
+// Folders under home the import wizard looks in
+var (
+	// obsidianRoots hold Obsidian vaults, or are one
+	obsidianRoots = []string{"", "Documents", "Obsidian", filepath.Join("Library", "Mobile Documents", "iCloud~md~obsidian", "Documents")}
+	// markdownFolders are where prompts kept by hand usually live
+	markdownFolders = []string{"prompts", "Prompts", filepath.Join("Documents", "prompts"), filepath.Join("Documents", "Prompts"), filepath.Join("notes", "prompts")}
+	// checkoutRoots hold git checkouts
+	checkoutRoots = []string{"src", "code", "projects", "dev", "repos", "git", "Developer", "workspace"}
+)
+
+// DetectImportSources looks for prompts to import under home and in cwd:
+// Claude Code commands and agents, Obsidian vaults, Markdown folders named
+// prompts and git checkouts with an origin and a prompts or templates
+// folder. A vault with a prompts folder is offered as that folder. Sources
+// without anything to import, and the library itself, are left out.
+func (s *Service) DetectImportSources(home, cwd string) []ImportSource {
+	var sources []ImportSource
+	var seen []os.FileInfo
+	add := func(src ImportSource) {
+		info, err := os.Stat(src.Path)
+		if err != nil || src.Items == 0 || s.isLibraryDir(src.Path) {
+			return
+		}
+		for _, other := range seen {
+			if os.SameFile(info, other) {
+				return
+			}
+		}
+		seen = append(seen, info)
+		sources = append(sources, src)
+	}
+
+	// Claude Code keeps user commands in ~/.claude and project ones in .claude
+	claude := importer.NewClaudeCodeImporter(s.GetBaseDir())
+	for _, path := range []string{filepath.Join(home, ".claude"), cwd} {
+		if path == "" || path == home {
+			continue
+		}
+		if found, err := claude.Import(importer.ImportOptions{Path: path, DryRun: true}); err == nil {
+			add(ImportSource{Kind: SourceClaudeCode, Path: path, Items: len(found.Prompts)})
+		}
+	}
+
+	for _, root := range obsidianRoots {
+		for _, vault := range obsidianVaults(filepath.Join(home, root)) {
+			path := vault
+			if prompts := childNamed(vault, "prompts"); prompts != "" {
+				path = prompts
+			}
+			add(ImportSource{Kind: SourceObsidian, Path: path, Items: countMarkdown(path)})
+		}
+	}
+
+	for _, folder := range markdownFolders {
+		path := filepath.Join(home, folder)
+		add(ImportSource{Kind: SourceMarkdown, Path: path, Items: countMarkdown(path)})
+	}
+
+	checkouts := []string{cwd}
+	for _, root := range checkoutRoots {
+		entries, _ := os.ReadDir(filepath.Join(home, root))
+		for _, entry := range entries {
+			if entry.IsDir() {
+				checkouts = append(checkouts, filepath.Join(home, root, entry.Name()))
+			}
+		}
+	}
+	for _, dir := range checkouts {
+		if dir == "" || !isDir(filepath.Join(dir, ".git")) {
+			continue
+		}
+		items := countMarkdown(filepath.Join(dir, "prompts")) + countMarkdown(filepath.Join(dir, "templates"))
+		if items == 0 {
+			continue
+		}
+		out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+		if err != nil {
+			continue
+		}
+		add(ImportSource{Kind: SourceGitRepo, Path: dir, RepoURL: strings.TrimSpace(string(out)), Items: items})
+	}
+	return sources
+}
+
+// ImportFromSource runs the import of one source the wizard found, with
+// options applying to every source. A git checkout is cloned locally, so
+// it imports what is committed there, tagged with its origin's owner.
+func (s *Service) ImportFromSource(src ImportSource, options importer.ImportOptions) (*importer.ImportResult, error) {
+	options.Path = src.Path
+	var result *importer.ImportResult
+	var err error
+	switch src.Kind {
+	case SourceClaudeCode:
+		result, err = s.ImportFromClaudeCode(options)
+	case SourceObsidian, SourceMarkdown:
+		result, err = s.ImportFromMarkdown(importer.MarkdownImportOptions{ImportOptions: options, Kind: src.Kind})
+	case SourceGitRepo:
+		options.Path = ""
+		var gitResult *importer.GitImportResult
+		gitResult, err = s.ImportFromGitRepository(importer.GitImportOptions{ImportOptions: options, RepoURL: src.RepoURL, CloneFrom: src.Path})
+		if err == nil {
+			result = gitResult.ImportResult
+		}
+	default:
+		return nil, fmt.Errorf("unknown import source kind %q", src.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The import synced the library; prompts it put in a pack sync with the pack
+	if !options.DryRun && options.Pack != "" && options.Pack != "personal" && len(result.Prompts) > 0 {
+		s.syncBulkChange(fmt.Sprintf("Import %d prompts from %s", len(result.Prompts), src.Path), "importing", false, map[string]bool{options.Pack: true})
+	}
+	return result, nil
+}
+
+// isLibraryDir reports whether path is the library or inside it
+func (s *Service) isLibraryDir(path string) bool {
+	rel, err := filepath.Rel(s.GetBaseDir(), path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
+// obsidianVaults returns root if it is an Obsidian vault, else the vaults
+// directly inside it
+func obsidianVaults(root string) []string {
+	if isDir(filepath.Join(root, ".obsidian")) {
+		return []string{root}
+	}
+	var vaults []string
+	entries, _ := os.ReadDir(root)
+	for _, entry := range entries {
+		path := filepath.Join(root, entry.Name())
+		if entry.IsDir() && isDir(filepath.Join(path, ".obsidian")) {
+			vaults = append(vaults, path)
+		}
+	}
+	return vaults
+}
+
+// childNamed returns the folder in dir called name in any case, or ""
+func childNamed(dir, name string) string {
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if entry.IsDir() && strings.EqualFold(entry.Name(), name) {
+			return filepath.Join(dir, entry.Name())
+		}
+	}
+	return ""
+}
+
+// countMarkdown counts the .md files under dir outside hidden folders
+func countMarkdown(dir string) int {
+	count := 0
+	filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() && path != dir && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+// isDir reports whether path is a folder
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/importer"
+)
+
+func TestImportWizard(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+	}
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	home := filepath.Join(tmpDir, "home")
+	write(filepath.Join(home, ".claude", "commands", "review.md"), "# Review\n\nReview this code.\n")
+	vault := filepath.Join(home, "Documents", "Vault")
+	os.MkdirAll(filepath.Join(vault, ".obsidian"), 0755)
+	write(filepath.Join(vault, "Journal", "monday.md"), "Not a prompt\n")
+	write(filepath.Join(vault, "Prompts", "Work", "Daily Standup.md"), "---\ntags: [meeting, \"#daily\"]\ndescription: Standup notes\n---\nSummarize yesterday and today.\n")
+	write(filepath.Join(vault, "Prompts", "empty.md"), "")
+	os.MkdirAll(filepath.Join(home, "notes"), 0755)
+	write(filepath.Join(home, "prompts", "haiku.md"), "Write a haiku about {{topic}}.\n")
+	checkout := filepath.Join(home, "src", "team-prompts")
+	write(filepath.Join(checkout, "prompts", "triage.md"), "---\nid: triage\ntitle: Triage\n---\nTriage this bug.\n")
+	git(checkout, "init", "-q")
+	git(checkout, "add", "-A")
+	git(checkout, "commit", "-qm", "Prompts")
+	git(checkout, "remote", "add", "origin", "https://github.com/acme/team-prompts.git")
+	// A checkout without prompts is not a source
+	os.MkdirAll(filepath.Join(home, "src", "tool", ".git"), 0755)
+
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	sources := svc.DetectImportSources(home, "")
+	want := []ImportSource{
+		{Kind: SourceClaudeCode, Path: filepath.Join(home, ".claude"), Items: 1},
+		{Kind: SourceObsidian, Path: filepath.Join(vault, "Prompts"), Items: 2},
+		{Kind: SourceMarkdown, Path: filepath.Join(home, "prompts"), Items: 1},
+		{Kind: SourceGitRepo, Path: checkout, RepoURL: "https://github.com/acme/team-prompts.git", Items: 1},
+	}
+	if !slices.Equal(sources, want) {
+		t.Fatalf("DetectImportSources =\n%+v\nwant\n%+v", sources, want)
+	}
+
+	// The vault goes into a pack, everything else into the library
+	team := filepath.Join(tmpDir, "team")
+	write(filepath.Join(team, "pack.json"), `{"name": "team", "version": "1.0.0", "title": "Team Pack"}`)
+	os.MkdirAll(filepath.Join(team, "prompts"), 0755)
+	if _, err := svc.InstallPack(team, config.PackInstallOptions{}); err != nil {
+		t.Fatalf("InstallPack: %v", err)
+	}
+	if _, err := svc.ImportFromSource(sources[1], importer.ImportOptions{Pack: "missing"}); err == nil {
+		t.Error("importing into a pack that is not installed succeeded")
+	}
+	for i, src := range sources {
+		options := importer.ImportOptions{Tags: []string{"onboarding"}, SkipExisting: true}
+		if src.Kind == SourceObsidian {
+			options.Pack = "team"
+		}
+		result, err := svc.ImportFromSource(src, options)
+		if err != nil || len(result.Errors) > 0 || len(result.Prompts) != 1 {
+			t.Fatalf("ImportFromSource(%d) = %+v, %v, want one prompt", i, result, err)
+		}
+	}
+
+	standup, err := svc.GetPrompt("obsidian-work-daily-standup")
+	if err != nil {
+		t.Fatalf("vault prompt not imported: %v", err)
+	}
+	if standup.FilePath != filepath.Join("packs", "team", "prompts", "obsidian-work-daily-standup.md") || standup.Summary != "Standup notes" {
+		t.Errorf("vault prompt = %s %q, want it in the team pack", standup.FilePath, standup.Summary)
+	}
+	for _, tag := range []string{"obsidian", "work", "meeting", "daily", "onboarding"} {
+		if !slices.Contains(standup.Tags, tag) {
+			t.Errorf("vault prompt tags = %v, want %s", standup.Tags, tag)
+		}
+	}
+	if haiku, err := svc.GetPrompt("markdown-haiku"); err != nil || haiku.Name != "Haiku" || haiku.FilePath != filepath.Join("prompts", "markdown-haiku.md") {
+		t.Errorf("markdown prompt = %+v, %v", haiku, err)
+	}
+	if triage, err := svc.GetPrompt("triage"); err != nil || !slices.Contains(triage.Tags, "acme") {
+		t.Errorf("git prompt = %+v, %v, want it tagged with the origin's owner", triage, err)
+	}
+}
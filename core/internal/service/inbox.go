@@ -0,0 +1,104 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// InboxPrompt builds an unsaved prompt from a JSON payload sent by an external
+// system, picking the content, title, description, tags and metadata out of it
+// with mapping. Like QuickAdd, the title defaults to the first line of content.
+// Errors describe what is wrong with the payload.
+func (s *Service) InboxPrompt(payload interface{}, mapping config.InboxConfig) (*models.Prompt, error) {
+	contentValue, ok := lookupPath(payload, mapping.ContentPath())
+	if !ok {
+		return nil, fmt.Errorf("payload has no value at %q", mapping.ContentPath())
+	}
+	content := inboxText(contentValue)
+
+	var title, description string
+	if value, ok := lookupPath(payload, mapping.TitlePath()); ok {
+		title = inboxText(value)
+	}
+	if value, ok := lookupPath(payload, mapping.DescriptionPath()); ok {
+		description = inboxText(value)
+	}
+
+	tags := append([]string{}, mapping.DefaultTags...)
+	if value, ok := lookupPath(payload, mapping.TagsPath()); ok {
+		tags = append(tags, inboxTags(value)...)
+	}
+
+	prompt, err := s.newQuickPrompt(content, title, tags)
+	if err != nil {
+		return nil, err
+	}
+	prompt.Summary = strings.TrimSpace(description)
+
+	prompt.Metadata = map[string]interface{}{"source": "inbox"}
+	for key, path := range mapping.Metadata {
+		if value, ok := lookupPath(payload, path); ok {
+			prompt.Metadata[key] = value
+		}
+	}
+
+	return prompt, nil
+}
+
+// lookupPath follows a dot-separated path through decoded JSON. Numeric
+// segments index arrays. Null values count as missing.
+func lookupPath(value interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+		if value == nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// inboxText renders a payload value as prompt text; objects and arrays become indented JSON
+func inboxText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// inboxTags accepts a list of tags or a comma-separated string
+func inboxTags(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		var tags []string
+		for _, item := range v {
+			tags = append(tags, inboxText(item))
+		}
+		return tags
+	default:
+		return strings.Split(inboxText(v), ",")
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+func TestInboxPromptMapping(t *testing.T) {
+	var payload interface{}
+	body := `{"data": {"items": [{"text": "Summarise the ticket"}]}, "labels": "support, triage", "user": {"name": "Ana"}}`
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("Failed to parse payload: %v", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	mapping := config.InboxConfig{
+		Content:     "data.items.0.text",
+		Tags:        "labels",
+		DefaultTags: []string{"inbox"},
+		Metadata:    map[string]string{"author": "user.name"},
+	}
+	prompt, err := svc.InboxPrompt(payload, mapping)
+	if err != nil {
+		t.Fatalf("InboxPrompt: %v", err)
+	}
+	if prompt.Content != "Summarise the ticket" || prompt.Name != "Summarise the ticket" {
+		t.Errorf("content/title = %q/%q", prompt.Content, prompt.Name)
+	}
+	if len(prompt.Tags) != 3 || prompt.Tags[0] != "inbox" || prompt.Tags[2] != "triage" {
+		t.Errorf("tags = %v, want [inbox support triage]", prompt.Tags)
+	}
+	if prompt.Metadata["author"] != "Ana" || prompt.Metadata["source"] != "inbox" {
+		t.Errorf("metadata = %v", prompt.Metadata)
+	}
+
+	mapping.Content = "data.missing"
+	if _, err := svc.InboxPrompt(payload, mapping); err == nil {
+		t.Error("expected an error for a payload without content")
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Outcomes of checking a referenced resource
+const (
+	LinkOK          = "ok"
+	LinkBroken      = "broken"      // Gone: a 404 or 410, or a library doc that is missing
+	LinkError       = "error"       // Any other failing status, or a reference that can't be checked
+	LinkUnreachable = "unreachable" // No response at all, such as a DNS failure or a timeout
+)
+
+// DefaultLinkTimeout bounds each request of a link check
+const DefaultLinkTimeout = 10 * time.Second
+
+// linkCheckWorkers is how many references are checked at once
+const linkCheckWorkers = 8
+
+// LinkCheck is the outcome of checking one resource that prompts reference
+type LinkCheck struct {
+	Reference string   `json:"reference"`
+	Result    string   `json:"result"`
+	Status    int      `json:"status,omitempty"` // The HTTP status, for URLs that answered
+	Error     string   `json:"error,omitempty"`
+	Prompts   []string `json:"prompts"` // IDs of the prompts that reference it
+}
+
+// Failed reports whether the reference needs attention
+func (c LinkCheck) Failed() bool {
+	return c.Result != LinkOK
+}
+
+// LinkReport is the outcome of CheckLinks
+type LinkReport struct {
+	Prompts int         `json:"prompts"` // Prompts checked that declare references
+	Failed  int         `json:"failed"`
+	Links   []LinkCheck `json:"links"` // Failures first, then by reference
+}
+
+// LinkCheckOptions tunes CheckLinks
+type LinkCheckOptions struct {
+	Timeout time.Duration // Per request (default DefaultLinkTimeout)
+	Client  *http.Client  // Sends the requests; nil for a default client
+}
+
+// CheckLinks checks every resource that prompts declare in references: URLs
+// must answer with a successful status, after redirects, and library docs
+// must exist. Each resource is checked once however many prompts reference
+// it, with a HEAD request retried as GET for servers that refuse HEAD.
+func (s *Service) CheckLinks(prompts []*models.Prompt, opts LinkCheckOptions) *LinkReport {
+	client := opts.Client
+	if client == nil {
+		timeout := opts.Timeout
+		if timeout <= 0 {
+			timeout = DefaultLinkTimeout
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+
+	report := &LinkReport{Links: []LinkCheck{}}
+	byReference := map[string]*LinkCheck{}
+	var checks []*LinkCheck
+	for _, p := range prompts {
+		if len(p.References) == 0 {
+			continue
+		}
+		report.Prompts++
+		for _, ref := range p.References {
+			ref = strings.TrimSpace(ref)
+			if ref == "" {
+				continue
+			}
+			check, ok := byReference[ref]
+			if !ok {
+				check = &LinkCheck{Reference: ref}
+				byReference[ref] = check
+				checks = append(checks, check)
+			}
+			if len(check.Prompts) == 0 || check.Prompts[len(check.Prompts)-1] != p.ID {
+				check.Prompts = append(check.Prompts, p.ID)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	work := make(chan *LinkCheck)
+	for i := 0; i < min(linkCheckWorkers, len(checks)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for check := range work {
+				s.checkLink(client, check)
+			}
+		}()
+	}
+	for _, check := range checks {
+		work <- check
+	}
+	close(work)
+	wg.Wait()
+
+	for _, check := range checks {
+		if check.Failed() {
+			report.Failed++
+		}
+		report.Links = append(report.Links, *check)
+	}
+	sort.SliceStable(report.Links, func(i, j int) bool {
+		if report.Links[i].Failed() != report.Links[j].Failed() {
+			return report.Links[i].Failed()
+		}
+		return report.Links[i].Reference < report.Links[j].Reference
+	})
+	return report
+}
+
+// checkLink fills in the outcome of check
+func (s *Service) checkLink(client *http.Client, check *LinkCheck) {
+	ref := check.Reference
+	if !strings.Contains(ref, "://") {
+		if _, err := os.Stat(s.resolveLibraryPath(ref)); err != nil {
+			check.Result = LinkBroken
+			check.Error = "no such file in the library"
+		} else {
+			check.Result = LinkOK
+		}
+		return
+	}
+	if !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://") {
+		check.Result = LinkError
+		check.Error = "only http and https URLs can be checked"
+		return
+	}
+
+	status, err := requestStatus(client, http.MethodHead, ref)
+	if err == nil && status >= 400 {
+		// Plenty of servers answer HEAD wrongly but GET correctly
+		status, err = requestStatus(client, http.MethodGet, ref)
+	}
+	switch {
+	case err != nil:
+		check.Result = LinkUnreachable
+		check.Error = err.Error()
+	case status == http.StatusNotFound || status == http.StatusGone:
+		check.Result = LinkBroken
+		check.Status = status
+	case status >= 400:
+		check.Result = LinkError
+		check.Status = status
+	default:
+		check.Result = LinkOK
+		check.Status = status
+	}
+}
+
+// requestStatus sends a request to url and returns the status it ends with
+func requestStatus(client *http.Client, method, url string) (int, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "pocket-prompt link check")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// LinkCheckCandidates returns the prompts with ids, or every prompt when ids
+// is empty, with their references
+func (s *Service) LinkCheckCandidates(ids []string) ([]*models.Prompt, error) {
+	if len(ids) == 0 {
+		return s.ListPrompts()
+	}
+	var prompts []*models.Prompt
+	for _, id := range ids {
+		p, err := s.GetPrompt(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prompt: %w", err)
+		}
+		prompts = append(prompts, p)
+	}
+	return prompts, nil
+}
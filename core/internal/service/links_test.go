@@ -0,0 +1,80 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestCheckLinks(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	os.MkdirAll(filepath.Join(tmpDir, "docs"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "docs", "style.md"), []byte("# Style"), 0644)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	prompts := []*models.Prompt{
+		{ID: "api", References: []string{server.URL + "/ok", server.URL + "/moved", server.URL + "/gone"}},
+		{ID: "style", References: []string{"docs/style.md", "docs/missing.md", server.URL + "/gone"}},
+		{ID: "ops", References: []string{server.URL + "/no-head", server.URL + "/down", "ftp://example.com/runbook"}},
+		{ID: "plain"},
+	}
+	report := svc.CheckLinks(prompts, LinkCheckOptions{})
+	if report.Prompts != 3 || len(report.Links) != 8 {
+		t.Fatalf("CheckLinks checked %d prompts and %d links, want 3 and 8", report.Prompts, len(report.Links))
+	}
+
+	results := map[string]LinkCheck{}
+	for _, link := range report.Links {
+		results[link.Reference] = link
+	}
+	want := map[string]string{
+		server.URL + "/ok":          LinkOK,
+		server.URL + "/moved":       LinkOK,
+		server.URL + "/no-head":     LinkOK,
+		server.URL + "/gone":        LinkBroken,
+		server.URL + "/down":        LinkError,
+		"docs/style.md":             LinkOK,
+		"docs/missing.md":           LinkBroken,
+		"ftp://example.com/runbook": LinkError,
+	}
+	for ref, result := range want {
+		if results[ref].Result != result {
+			t.Errorf("%s: result %q (status %d, %s), want %q", ref, results[ref].Result, results[ref].Status, results[ref].Error, result)
+		}
+	}
+	if gone := results[server.URL+"/gone"]; len(gone.Prompts) != 2 || gone.Status != http.StatusNotFound {
+		t.Errorf("Gone link = %+v, want status 404 referenced by api and style", gone)
+	}
+	if report.Failed != 4 || !report.Links[0].Failed() || report.Links[len(report.Links)-1].Failed() {
+		t.Errorf("Report has %d failures, want 4 listed first", report.Failed)
+	}
+}
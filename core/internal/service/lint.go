@@ -0,0 +1,167 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/lint"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// LintOptions selects the prompts LintPrompts checks
+type LintOptions struct {
+	IDs        []string // Prompts to check; empty checks the whole library
+	StagedOnly bool     // Check only prompt files staged for commit, as staged
+}
+
+// LintResult holds the issues found in one prompt file
+type LintResult struct {
+	ID       string       `json:"id,omitempty"`
+	FilePath string       `json:"file_path"`
+	Issues   []lint.Issue `json:"issues"`
+}
+
+// lintFile is a prompt file's path and the content to check
+type lintFile struct {
+	path string
+	data []byte
+}
+
+// LintPrompts checks prompt files against the library's lint rules, and
+// reports files whose frontmatter does not parse or whose ID another prompt
+// also uses. Issue lines are line numbers in the file, frontmatter included.
+func (s *Service) LintPrompts(opts LintOptions) ([]LintResult, error) {
+	linter, err := lint.New(s.settings.Lint)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.lintFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]LintResult, 0, len(files))
+	owners := make(map[string][]string) // Prompt ID to the files using it
+	checked := make(map[string]bool)
+	for _, f := range files {
+		checked[f.path] = true
+		result := LintResult{FilePath: f.path}
+
+		p, err := storage.ParsePrompt(f.data)
+		if err != nil {
+			result.Issues = []lint.Issue{{Rule: lint.RuleFrontmatter, Severity: lint.Error, Message: err.Error()}}
+			results = append(results, result)
+			continue
+		}
+		if p.ID == "" {
+			result.Issues = append(result.Issues, lint.Issue{Rule: lint.RuleFrontmatter, Severity: lint.Error, Message: "frontmatter has no id"})
+		} else {
+			result.ID = p.ID
+			owners[p.ID] = append(owners[p.ID], f.path)
+		}
+
+		offset := contentLineOffset(f.data, p.Content)
+		for _, issue := range linter.Check(p.Content) {
+			if issue.Line > 0 {
+				issue.Line += offset
+			}
+			result.Issues = append(result.Issues, issue)
+		}
+		results = append(results, result)
+	}
+
+	// Prompts that were not checked can still clash with ones that were
+	if opts.StagedOnly || len(opts.IDs) > 0 {
+		prompts, err := s.storage.ListPrompts()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prompts {
+			if !checked[p.FilePath] && p.ID != "" {
+				owners[p.ID] = append(owners[p.ID], p.FilePath)
+			}
+		}
+	}
+	for i, result := range results {
+		var others []string
+		for _, path := range owners[result.ID] {
+			if path != result.FilePath {
+				others = append(others, path)
+			}
+		}
+		if len(others) > 0 {
+			sort.Strings(others)
+			issue := lint.Issue{Rule: lint.RuleDuplicateID, Severity: lint.Error, Message: fmt.Sprintf("id %q is also used by %s", result.ID, strings.Join(others, ", "))}
+			results[i].Issues = append([]lint.Issue{issue}, result.Issues...)
+		}
+	}
+	return results, nil
+}
+
+// lintFiles reads the prompt files LintPrompts checks
+func (s *Service) lintFiles(opts LintOptions) ([]lintFile, error) {
+	var files []lintFile
+	switch {
+	case opts.StagedOnly:
+		staged, err := s.gitSync.StagedFiles()
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range staged {
+			if !s.storage.IsPromptFile(path) {
+				continue
+			}
+			data, err := s.gitSync.StagedFile(path)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, lintFile{path: path, data: data})
+		}
+		return files, nil
+	case len(opts.IDs) > 0:
+		var paths []string
+		for _, id := range opts.IDs {
+			p, err := s.GetPrompt(id)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, p.FilePath)
+		}
+		return s.readLintFiles(paths)
+	default:
+		paths, err := s.storage.PromptFiles()
+		if err != nil {
+			return nil, err
+		}
+		return s.readLintFiles(paths)
+	}
+}
+
+// readLintFiles reads prompt files from the working tree
+func (s *Service) readLintFiles(paths []string) ([]lintFile, error) {
+	files := make([]lintFile, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(filepath.Join(s.GetBaseDir(), path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files = append(files, lintFile{path: path, data: data})
+	}
+	return files, nil
+}
+
+// contentLineOffset counts the lines of a prompt file before its content, so
+// content line numbers can be reported as file line numbers
+func contentLineOffset(data []byte, content string) int {
+	if content == "" {
+		return 0
+	}
+	idx := strings.Index(string(data), content)
+	if idx < 0 {
+		return 0
+	}
+	return strings.Count(string(data[:idx]), "\n")
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/lint"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestLintPrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	svc.Settings().Lint.ForbiddenPhrases = []string{"delve"}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "greet", Name: "Greet", Content: "Say hello.\nDelve into it."}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	files := map[string]string{
+		"copy.md":   "---\nid: greet\ntitle: Copy\n---\nHello\n",
+		"broken.md": "---\nid: [broken\n---\nHello\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, "prompts", name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	results, err := svc.LintPrompts(LintOptions{})
+	if err != nil {
+		t.Fatalf("LintPrompts: %v", err)
+	}
+	rules := make(map[string][]lint.Issue)
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			rules[filepath.Base(result.FilePath)] = append(rules[filepath.Base(result.FilePath)], issue)
+		}
+	}
+
+	if got := rules["broken.md"]; len(got) != 1 || got[0].Rule != lint.RuleFrontmatter {
+		t.Errorf("broken.md issues = %+v, want one frontmatter error", got)
+	}
+	if got := rules["copy.md"]; len(got) != 1 || got[0].Rule != lint.RuleDuplicateID {
+		t.Errorf("copy.md issues = %+v, want one duplicate-id error", got)
+	}
+	greet := rules["greet.md"]
+	if len(greet) != 2 || greet[0].Rule != lint.RuleDuplicateID || greet[1].Rule != lint.RuleForbiddenPhrase {
+		t.Fatalf("greet.md issues = %+v, want duplicate-id and forbidden-phrase", greet)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "prompts", "greet.md"))
+	if want := contentLineOffset(data, "Say hello.\nDelve into it.") + 2; greet[1].Line != want {
+		t.Errorf("forbidden phrase reported at line %d, want file line %d", greet[1].Line, want)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// DefaultLockTTL is how long a lock lasts unless taken again, so a lock
+// forgotten after an edit stops warning teammates by the next day
+const DefaultLockTTL = 8 * time.Hour
+
+// LockOptions describe a lock to take on a prompt
+type LockOptions struct {
+	Owner string        // Defaults to LockOwner()
+	Note  string        // What the owner is doing, shown to others
+	TTL   time.Duration // Defaults to DefaultLockTTL
+	Force bool          // Take over a lock someone else holds
+}
+
+// LockedError is returned when a prompt is locked by someone else
+type LockedError struct {
+	Lock *models.PromptLock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s is being edited by %s since %s", e.Lock.ID, e.Lock.Owner, i18n.FormatDateTime(e.Lock.Since))
+}
+
+// LockOwner names the person using this library: the git user name, or the
+// login name without one
+func (s *Service) LockOwner() string {
+	if name := s.gitSync.UserName(); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// PromptLock returns the lock on a prompt, or nil when it is not locked or
+// its lock has expired
+func (s *Service) PromptLock(id string) (*models.PromptLock, error) {
+	lock, err := s.storage.LoadLock(id)
+	if err != nil || lock == nil || lock.Expired(time.Now()) {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// LockedByOther returns the lock on a prompt when someone other than owner
+// holds it, for interfaces to show before editing
+func (s *Service) LockedByOther(id, owner string) *models.PromptLock {
+	lock, err := s.PromptLock(id)
+	if err != nil || lock == nil || lock.Owner == owner {
+		return nil
+	}
+	return lock
+}
+
+// ListLocks returns the locks that have not expired, sorted by prompt ID
+func (s *Service) ListLocks() ([]*models.PromptLock, error) {
+	locks, err := s.storage.ListLocks()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	active := []*models.PromptLock{}
+	for _, lock := range locks {
+		if !lock.Expired(now) {
+			active = append(active, lock)
+		}
+	}
+	return active, nil
+}
+
+// LockPrompt marks a prompt as being edited. Taking a lock you already hold
+// renews it; taking someone else's needs opts.Force.
+func (s *Service) LockPrompt(id string, opts LockOptions) (*models.PromptLock, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	if _, err := s.GetPrompt(id); err != nil {
+		return nil, err
+	}
+	if opts.Owner == "" {
+		opts.Owner = s.LockOwner()
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultLockTTL
+	}
+
+	now := time.Now()
+	lock := &models.PromptLock{ID: id, Owner: opts.Owner, Note: opts.Note, Since: now}
+	existing, err := s.PromptLock(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.Owner != opts.Owner && !opts.Force {
+			return nil, &LockedError{Lock: existing}
+		}
+		if existing.Owner == opts.Owner {
+			lock.Since = existing.Since
+		}
+	}
+	lock.Expires = now.Add(opts.TTL)
+
+	if err := s.storage.SaveLock(lock); err != nil {
+		return nil, err
+	}
+	s.syncLocks(fmt.Sprintf("Lock prompt: %s (%s)", id, opts.Owner))
+	return lock, nil
+}
+
+// UnlockPrompt releases a prompt's lock. Releasing someone else's lock needs
+// force; releasing an expired lock just tidies it away.
+func (s *Service) UnlockPrompt(id, owner string, force bool) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	lock, err := s.storage.LoadLock(id)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return fmt.Errorf("%s is not locked", id)
+	}
+	if owner == "" {
+		owner = s.LockOwner()
+	}
+	if lock.Owner != owner && !lock.Expired(time.Now()) && !force {
+		return &LockedError{Lock: lock}
+	}
+
+	if err := s.storage.DeleteLock(id); err != nil {
+		return err
+	}
+	s.syncLocks(fmt.Sprintf("Unlock prompt: %s", id))
+	return nil
+}
+
+// syncLocks commits a lock change so teammates see it
+func (s *Service) syncLocks(message string) {
+	if !s.gitSync.IsEnabled() {
+		return
+	}
+	if err := s.gitSync.SyncChanges(message); err != nil {
+		fmt.Printf("Warning: Git sync failed after updating locks: %v\n", err)
+	}
+}
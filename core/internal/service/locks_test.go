@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestPromptLocks(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Content: "Review this"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	if _, err := svc.LockPrompt("missing", LockOptions{Owner: "alice"}); err == nil {
+		t.Error("locking a missing prompt succeeded")
+	}
+	if _, err := svc.LockPrompt("review", LockOptions{Owner: "alice", Note: "tightening tone"}); err != nil {
+		t.Fatalf("LockPrompt: %v", err)
+	}
+	if lock := svc.LockedByOther("review", "alice"); lock != nil {
+		t.Errorf("the owner's own lock was reported as someone else's")
+	}
+	lock := svc.LockedByOther("review", "bob")
+	if lock == nil || lock.Owner != "alice" || lock.Note != "tightening tone" {
+		t.Fatalf("LockedByOther for bob = %+v, want alice's lock", lock)
+	}
+
+	var locked *LockedError
+	if _, err := svc.LockPrompt("review", LockOptions{Owner: "bob"}); !errors.As(err, &locked) {
+		t.Errorf("bob locking alice's prompt = %v, want a LockedError", err)
+	}
+	if err := svc.UnlockPrompt("review", "bob", false); !errors.As(err, &locked) {
+		t.Errorf("bob unlocking alice's prompt = %v, want a LockedError", err)
+	}
+	if _, err := svc.LockPrompt("review", LockOptions{Owner: "bob", Force: true}); err != nil {
+		t.Fatalf("forced LockPrompt: %v", err)
+	}
+	locks, err := svc.ListLocks()
+	if err != nil || len(locks) != 1 || locks[0].Owner != "bob" {
+		t.Fatalf("ListLocks = %v, %v; want bob's lock", locks, err)
+	}
+
+	if err := svc.UnlockPrompt("review", "bob", false); err != nil {
+		t.Fatalf("UnlockPrompt: %v", err)
+	}
+	if lock, _ := svc.PromptLock("review"); lock != nil {
+		t.Errorf("prompt still locked after unlocking: %+v", lock)
+	}
+	if err := svc.UnlockPrompt("review", "bob", false); err == nil {
+		t.Error("unlocking an unlocked prompt succeeded")
+	}
+}
+
+func TestExpiredLocksAreIgnored(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Content: "Review this"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	if _, err := svc.LockPrompt("review", LockOptions{Owner: "alice", TTL: time.Nanosecond}); err != nil {
+		t.Fatalf("LockPrompt: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if lock := svc.LockedByOther("review", "bob"); lock != nil {
+		t.Errorf("expired lock still reported: %+v", lock)
+	}
+	if locks, _ := svc.ListLocks(); len(locks) != 0 {
+		t.Errorf("ListLocks = %d locks, want expired locks left out", len(locks))
+	}
+	if _, err := svc.LockPrompt("review", LockOptions{Owner: "bob"}); err != nil {
+		t.Errorf("locking over an expired lock: %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// MaintenanceReport describes what RunMaintenance did, or would do in a dry run
+type MaintenanceReport struct {
+	DryRun          bool                `json:"dry_run"`
+	ArchivedPrompts []ExpiringPrompt    `json:"archived_prompts"`          // Prompts archived by the expiry policy
+	ExpiredPrompts  []ExpiringPrompt    `json:"expired_prompts,omitempty"` // Expired prompts left in place: protected, or the policy only warns
+	ExpiringPrompts []ExpiringPrompt    `json:"expiring_prompts"`          // Prompts that expire within the warning period
+	RemovedVersions []string            `json:"removed_versions"`          // Archived versions removed by the retention policy
+	RemovedDirs     []string            `json:"removed_dirs"`              // Empty directories pruned
+	IndexedPrompts  int                 `json:"indexed_prompts"`           // Prompts in the rebuilt index; 0 in a dry run
+	GitChecked      bool                `json:"git_checked"`
+	GitProblems     []string            `json:"git_problems"` // Problems reported by git fsck
+	DiskUsage       []storage.DiskUsage `json:"disk_usage"`
+	Warnings        []string            `json:"warnings,omitempty"`
+}
+
+// RunMaintenance tidies the library: it archives prompts past their expires
+// date, removes archived versions outside the retention policy, prunes empty
+// directories, rebuilds the metadata index, verifies and compacts the git
+// repository, and measures disk usage. A dry run only reports what would be
+// archived and removed.
+func (s *Service) RunMaintenance(dryRun bool) (*MaintenanceReport, error) {
+	if !dryRun && s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	report := &MaintenanceReport{DryRun: dryRun, ArchivedPrompts: []ExpiringPrompt{}, ExpiringPrompts: []ExpiringPrompt{},
+		RemovedVersions: []string{}, RemovedDirs: []string{}, GitProblems: []string{}}
+	now := time.Now()
+
+	// Archive expired prompts first, so the retention policy sees them
+	policy := s.settings.Maintenance
+	scan, err := s.scanExpiry(now, policy.ExpiryWarning())
+	if err != nil {
+		return nil, err
+	}
+	report.Warnings = append(report.Warnings, scan.invalid...)
+	for _, p := range scan.expired {
+		if !policy.ArchivesExpired() {
+			report.ExpiredPrompts = append(report.ExpiredPrompts, expiringPrompt(p))
+			continue
+		}
+		if !dryRun {
+			if err := s.archiveExpired(p); err != nil {
+				report.ExpiredPrompts = append(report.ExpiredPrompts, expiringPrompt(p))
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%s expired but was not archived: %v", p.ID, err))
+				continue
+			}
+		}
+		report.ArchivedPrompts = append(report.ArchivedPrompts, expiringPrompt(p))
+	}
+	for _, p := range scan.expiring {
+		report.ExpiringPrompts = append(report.ExpiringPrompts, expiringPrompt(p))
+	}
+
+	expired, err := s.expiredVersions(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range expired {
+		if !dryRun {
+			if err := s.storage.DeleteArchivedPrompt(p); err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("failed to remove %s: %v", p.FilePath, err))
+				continue
+			}
+		}
+		report.RemovedVersions = append(report.RemovedVersions, p.FilePath)
+	}
+
+	empty, err := s.storage.EmptyDirs()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range empty {
+		if !dryRun {
+			if err := s.storage.RemoveDir(dir); err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("failed to remove %s: %v", dir, err))
+				continue
+			}
+		}
+		report.RemovedDirs = append(report.RemovedDirs, dir)
+	}
+
+	// Rebuild last, since listing the archive above rewrites the cache
+	if !dryRun {
+		indexed, err := s.storage.RebuildIndex()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rebuild index: %w", err)
+		}
+		report.IndexedPrompts = indexed
+		if err := s.loadPrompts(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.gitSync.IsInitialized() {
+		problems, err := s.gitSync.Fsck()
+		if err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		} else {
+			report.GitChecked = true
+			report.GitProblems = append(report.GitProblems, problems...)
+		}
+		if message := maintenanceCommitMessage(report); !dryRun && message != "" && s.gitSync.IsEnabled() {
+			if err := s.gitSync.SyncChanges(message); err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("git sync failed: %v", err))
+			}
+		}
+		// Only compact a repository that checked out clean
+		if !dryRun && report.GitChecked && len(report.GitProblems) == 0 {
+			if err := s.gitSync.Compact(); err != nil {
+				report.Warnings = append(report.Warnings, err.Error())
+			}
+		}
+	}
+
+	report.DiskUsage, err = s.storage.DiskUsage()
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// maintenanceCommitMessage describes the changes a maintenance run made to
+// the library, or returns "" when it made none worth committing
+func maintenanceCommitMessage(report *MaintenanceReport) string {
+	var parts []string
+	if n := len(report.ArchivedPrompts); n > 0 {
+		parts = append(parts, fmt.Sprintf("archive %s", plural(n, "expired prompt")))
+	}
+	if n := len(report.RemovedVersions); n > 0 {
+		parts = append(parts, fmt.Sprintf("remove %d archived versions", n))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Maintenance: " + strings.Join(parts, ", ")
+}
+
+// PollMaintenance runs maintenance every interval until ctx is cancelled,
+// passing each outcome to fn
+func (s *Service) PollMaintenance(ctx context.Context, interval time.Duration, fn func(*MaintenanceReport, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn(s.RunMaintenance(false))
+		}
+	}
+}
+
+// expiredVersions returns the archived versions the retention policy removes:
+// those beyond the newest keep_versions of each prompt, and those older than
+// max_age_days
+func (s *Service) expiredVersions(now time.Time) ([]*models.Prompt, error) {
+	policy := s.settings.Maintenance
+	if policy.KeepVersions <= 0 && policy.MaxAgeDays <= 0 {
+		return nil, nil
+	}
+	archived, err := s.storage.ListArchivedPrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string][]*models.Prompt)
+	for _, p := range archived {
+		byID[p.ID] = append(byID[p.ID], p)
+	}
+	cutoff := now.AddDate(0, 0, -policy.MaxAgeDays)
+
+	var expired []*models.Prompt
+	for _, versions := range byID {
+		sort.Slice(versions, func(i, j int) bool {
+			return versionTime(versions[i]).After(versionTime(versions[j]))
+		})
+		for i, p := range versions {
+			tooMany := policy.KeepVersions > 0 && i >= policy.KeepVersions
+			tooOld := policy.MaxAgeDays > 0 && versionTime(p).Before(cutoff)
+			if tooMany || tooOld {
+				expired = append(expired, p)
+			}
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].FilePath < expired[j].FilePath })
+	return expired, nil
+}
+
+// versionTime is when an archived version was saved, before it was replaced
+func versionTime(p *models.Prompt) time.Time {
+	if p.UpdatedAt.IsZero() {
+		return p.CreatedAt
+	}
+	return p.UpdatedAt
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRunMaintenance(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "greet", Name: "Greet", Content: "v1"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	for _, content := range []string{"v2", "v3", "v4"} {
+		prompt, _ := svc.GetPrompt("greet")
+		updated := *prompt
+		updated.Content = content
+		if err := svc.UpdatePrompt(&updated); err != nil {
+			t.Fatalf("UpdatePrompt: %v", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "prompts", "old", "empty"), 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	svc.Settings().Maintenance.KeepVersions = 1
+
+	before, err := svc.ListArchivedPrompts()
+	if err != nil || len(before) != 3 {
+		t.Fatalf("ListArchivedPrompts = %d versions, %v; want 3", len(before), err)
+	}
+	newest := before[0]
+	for _, p := range before {
+		if versionTime(p).After(versionTime(newest)) {
+			newest = p
+		}
+	}
+
+	report, err := svc.RunMaintenance(true)
+	if err != nil {
+		t.Fatalf("RunMaintenance dry run: %v", err)
+	}
+	if len(report.RemovedVersions) != 2 || len(report.RemovedDirs) != 2 {
+		t.Fatalf("dry run would remove %v and %v, want 2 versions and 2 directories", report.RemovedVersions, report.RemovedDirs)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, report.RemovedVersions[0])); err != nil {
+		t.Fatalf("dry run removed %s", report.RemovedVersions[0])
+	}
+
+	report, err = svc.RunMaintenance(false)
+	if err != nil {
+		t.Fatalf("RunMaintenance: %v", err)
+	}
+	archived, err := svc.ListArchivedPrompts()
+	if err != nil {
+		t.Fatalf("ListArchivedPrompts: %v", err)
+	}
+	if len(archived) != 1 || archived[0].FilePath != newest.FilePath {
+		t.Fatalf("archive holds %d versions, want only the newest (%s)", len(archived), newest.FilePath)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "prompts", "old")); !os.IsNotExist(err) {
+		t.Errorf("empty directory prompts/old was not removed")
+	}
+	if report.IndexedPrompts != 1 {
+		t.Errorf("indexed %d prompts, want 1", report.IndexedPrompts)
+	}
+
+	usage := make(map[string]int)
+	for _, u := range report.DiskUsage {
+		usage[u.Category] = u.Files
+	}
+	if usage["prompts"] != 1 || usage["archive"] != 1 {
+		t.Errorf("disk usage = %+v, want 1 prompt file and 1 archive file", report.DiskUsage)
+	}
+}
+
+func TestRunMaintenanceArchivesExpiredPrompts(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	for _, p := range []*models.Prompt{
+		{ID: "launch", Name: "Launch", Content: "v1", Expires: yesterday},
+		{ID: "policy", Name: "Policy", Content: "v1", Expires: yesterday, Protected: true},
+		{ID: "beta", Name: "Beta", Content: "v1", Expires: tomorrow},
+		{ID: "greet", Name: "Greet", Content: "v1"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create %s: %v", p.ID, err)
+		}
+	}
+
+	svc.Settings().Maintenance.Expired = config.ExpiredWarn
+	report, err := svc.RunMaintenance(false)
+	if err != nil {
+		t.Fatalf("RunMaintenance with the warn policy: %v", err)
+	}
+	if len(report.ArchivedPrompts) != 0 || len(report.ExpiredPrompts) != 2 {
+		t.Fatalf("warn policy archived %+v and kept %+v, want 2 kept", report.ArchivedPrompts, report.ExpiredPrompts)
+	}
+
+	svc.Settings().Maintenance.Expired = ""
+	report, err = svc.RunMaintenance(false)
+	if err != nil {
+		t.Fatalf("RunMaintenance: %v", err)
+	}
+	if len(report.ArchivedPrompts) != 1 || report.ArchivedPrompts[0].ID != "launch" || report.ArchivedPrompts[0].Expires != yesterday {
+		t.Fatalf("archived %+v, want launch", report.ArchivedPrompts)
+	}
+	if len(report.ExpiredPrompts) != 1 || report.ExpiredPrompts[0].ID != "policy" {
+		t.Errorf("kept %+v, want the protected policy prompt", report.ExpiredPrompts)
+	}
+	if len(report.ExpiringPrompts) != 1 || report.ExpiringPrompts[0].ID != "beta" {
+		t.Errorf("expiring %+v, want beta", report.ExpiringPrompts)
+	}
+	if _, err := svc.GetPrompt("launch"); err == nil {
+		t.Error("expired prompt launch is still in the library")
+	}
+	for _, id := range []string{"policy", "beta", "greet"} {
+		if _, err := svc.GetPrompt(id); err != nil {
+			t.Errorf("GetPrompt(%s): %v", id, err)
+		}
+	}
+
+	archived, err := svc.ListArchivedPrompts()
+	if err != nil {
+		t.Fatalf("ListArchivedPrompts: %v", err)
+	}
+	found := false
+	for _, p := range archived {
+		found = found || p.ID == "launch"
+	}
+	if !found {
+		t.Errorf("archive holds %d versions, none of launch", len(archived))
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// PromptConflict is a prompt file git could not merge, with the two versions
+// it tried to merge
+type PromptConflict struct {
+	Path     string         // Relative to the library
+	Local    *models.Prompt // Ours: the version on the branch merged into
+	Incoming *models.Prompt // Theirs: the version being merged in
+}
+
+// PromptConflicts lists the prompt files git left conflicted by a merge or
+// pull. Files deleted on one side are left for git to resolve.
+func (s *Service) PromptConflicts() ([]PromptConflict, error) {
+	files, err := s.gitSync.ConflictedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []PromptConflict
+	for _, path := range files {
+		if filepath.Ext(path) != ".md" || !isPromptPath(path) {
+			continue
+		}
+		ours, theirs, err := s.gitSync.ConflictSides(path)
+		if err != nil {
+			continue
+		}
+		local, err := s.storage.ParsePromptAt(path, ours)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the local version of %s: %w", path, err)
+		}
+		incoming, err := s.storage.ParsePromptAt(path, theirs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the incoming version of %s: %w", path, err)
+		}
+		conflicts = append(conflicts, PromptConflict{Path: path, Local: local, Incoming: incoming})
+	}
+	return conflicts, nil
+}
+
+// isPromptPath reports whether a file relative to the library holds a prompt
+// rather than a template, archived version or other file
+func isPromptPath(path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	if parts[0] == "prompts" {
+		return true
+	}
+	return len(parts) > 3 && parts[0] == "packs" && parts[2] == "prompts"
+}
+
+// ResolvePromptConflict writes the merged content of a conflicted prompt and
+// marks the file resolved for git. The prompt keeps the local title, tags and
+// other metadata, and takes the version after the higher of the two merged,
+// so it is newer than both.
+func (s *Service) ResolvePromptConflict(conflict PromptConflict, content string) (*models.Prompt, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+
+	base := conflict.Local.Version
+	if compareVersions(conflict.Incoming.Version, base) > 0 {
+		base = conflict.Incoming.Version
+	}
+	version, err := s.incrementVersion(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to increment version: %w", err)
+	}
+
+	merged := *conflict.Local
+	merged.Content = content
+	merged.Version = version
+	merged.UpdatedAt = time.Now()
+	merged.FilePath = conflict.Path
+	if err := s.storage.SavePrompt(&merged); err != nil {
+		return nil, err
+	}
+	if err := s.gitSync.MarkResolved(conflict.Path); err != nil {
+		return nil, fmt.Errorf("failed to mark %s resolved: %w", conflict.Path, err)
+	}
+	if err := s.loadPrompts(); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// MergeInProgress reports whether the library has a git merge waiting to be
+// committed
+func (s *Service) MergeInProgress() bool {
+	return s.gitSync.MergeInProgress()
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolvePromptConflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+	}
+	git := func(args ...string) (string, error) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	mustGit := func(args ...string) {
+		if out, err := git(args...); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	path := filepath.Join("prompts", "notes.md")
+	write := func(version, content string) {
+		data := "---\nid: notes\ntitle: Notes\nversion: " + version + "\n---\n" + content + "\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.MkdirAll(filepath.Join(tmpDir, "prompts"), 0755)
+	write("1.0.0", "intro\nrule\nend")
+	mustGit("init", "-q", "-b", "main")
+	mustGit("add", "-A")
+	mustGit("commit", "-qm", "First version")
+	mustGit("checkout", "-q", "-b", "other")
+	write("1.0.2", "intro\ntheir rule\nend")
+	mustGit("commit", "-qam", "Their edit")
+	mustGit("checkout", "-q", "main")
+	write("1.0.1", "intro\nour rule\nend")
+	mustGit("commit", "-qam", "Our edit")
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if _, err := git("merge", "-q", "other"); err == nil {
+		t.Fatal("expected the merge to conflict")
+	}
+
+	conflicts, err := svc.PromptConflicts()
+	if err != nil {
+		t.Fatalf("PromptConflicts: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Path != path {
+		t.Fatalf("conflicts = %+v, want %s", conflicts, path)
+	}
+	conflict := conflicts[0]
+	if conflict.Local.Version != "1.0.1" || conflict.Incoming.Version != "1.0.2" || !strings.Contains(conflict.Incoming.Content, "their rule") {
+		t.Fatalf("sides = v%s and v%s %q", conflict.Local.Version, conflict.Incoming.Version, conflict.Incoming.Content)
+	}
+
+	resolved, err := svc.ResolvePromptConflict(conflict, "intro\nour rule\ntheir rule\nend")
+	if err != nil {
+		t.Fatalf("ResolvePromptConflict: %v", err)
+	}
+	if resolved.Version != "1.0.3" {
+		t.Errorf("version = %s, want 1.0.3, after both merged versions", resolved.Version)
+	}
+	prompt, err := svc.GetPrompt("notes")
+	if err != nil || prompt.Content != "intro\nour rule\ntheir rule\nend" {
+		t.Fatalf("GetPrompt = %+v, %v", prompt, err)
+	}
+	if remaining, err := svc.PromptConflicts(); err != nil || len(remaining) != 0 {
+		t.Errorf("conflicts after resolving = %+v, %v", remaining, err)
+	}
+	if !svc.MergeInProgress() {
+		t.Error("expected the merge to be waiting for a commit")
+	}
+}
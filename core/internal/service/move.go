@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// MoveChange is a prompt moved, or to be moved, to another pack
+type MoveChange struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	From    string `json:"from"`
+	Path    string `json:"path"`              // Its file in the library afterwards
+	Version string `json:"version,omitempty"` // The new version; empty in a dry run
+}
+
+// MoveReport summarises a bulk move between packs
+type MoveReport struct {
+	DryRun    bool         `json:"dry_run"`
+	Pack      string       `json:"pack"`
+	Moved     []MoveChange `json:"moved"`
+	Unchanged []string     `json:"unchanged,omitempty"` // IDs already in the pack
+	Skipped   []RetagSkip  `json:"skipped,omitempty"`
+}
+
+// MoveCandidates returns the prompts of a pack, or of the personal library
+// when pack is empty or "personal", that match expr, or all of them when
+// expr is nil
+func (s *Service) MoveCandidates(pack string, expr *models.BooleanExpression) ([]*models.Prompt, error) {
+	var prompts []*models.Prompt
+	var err error
+	if pack == "" || pack == "personal" {
+		prompts, err = s.activePrompts()
+	} else if _, err = s.packConfig.GetPack(pack); err == nil {
+		prompts, err = s.storage.ListPromptsByPack(pack)
+	}
+	if err != nil || expr == nil {
+		return prompts, err
+	}
+
+	var matched []*models.Prompt
+	for _, p := range prompts {
+		if expr.Evaluate(p.Tags) {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// MovePrompts moves every prompt in prompts into pack, or back into the
+// personal library when pack is "personal". Each moved prompt is saved as
+// its next version in the pack's prompts folder, keeping its subfolder, and
+// its old file is removed. Packs whose pack.json lists their prompts have
+// the listing updated, and the whole move is synced as one git commit to
+// the library and to each pack it touched. With dryRun nothing is written
+// and the report says what would move.
+//
+// Prompts from other sources or the project library are skipped, as are
+// protected prompts and prompts whose file name is already taken in pack.
+func (s *Service) MovePrompts(prompts []*models.Prompt, pack string, dryRun bool) (*MoveReport, error) {
+	if pack == "" {
+		return nil, fmt.Errorf("no pack to move prompts to")
+	}
+	if pack != "personal" && !s.packConfig.IsPackInstalled(pack) {
+		return nil, fmt.Errorf("pack '%s' is not installed (see pkt packs list)", pack)
+	}
+	if !dryRun && s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+
+	report := &MoveReport{DryRun: dryRun, Pack: pack, Moved: []MoveChange{}}
+	library, packs := false, map[string]bool{}
+	touch := func(name string) {
+		if name == "personal" {
+			library = true
+		} else {
+			packs[name] = true
+		}
+	}
+	for _, p := range prompts {
+		if p.Source != "" {
+			report.Skipped = append(report.Skipped, RetagSkip{ID: p.ID, Reason: fmt.Sprintf("from source %s", p.Source)})
+			continue
+		}
+		if s.InProjectLibrary(p.ID) {
+			report.Skipped = append(report.Skipped, RetagSkip{ID: p.ID, Reason: "in the project library"})
+			continue
+		}
+		from := storage.PackFromPath(p.FilePath)
+		if from == "" {
+			from = "personal"
+		}
+		if from == pack {
+			report.Unchanged = append(report.Unchanged, p.ID)
+			continue
+		}
+		if err := s.CheckProtected(p); err != nil {
+			report.Skipped = append(report.Skipped, RetagSkip{ID: p.ID, Reason: "protected"})
+			continue
+		}
+		path, err := s.PromptFilePath(pack, storage.PromptSubdir(p.FilePath), p.ID)
+		if err != nil {
+			return report, err
+		}
+		if _, err := os.Stat(filepath.Join(s.GetBaseDir(), path)); err == nil {
+			report.Skipped = append(report.Skipped, RetagSkip{ID: p.ID, Reason: fmt.Sprintf("%s already exists", path)})
+			continue
+		}
+
+		change := MoveChange{ID: p.ID, Title: p.Title(), From: from, Path: path}
+		if !dryRun {
+			version, err := s.movePrompt(p, pack, path)
+			if err != nil {
+				return report, fmt.Errorf("failed to move %s: %w", p.ID, err)
+			}
+			change.Version = version
+			touch(from)
+			touch(pack)
+		}
+		report.Moved = append(report.Moved, change)
+	}
+
+	if dryRun || len(report.Moved) == 0 {
+		return report, nil
+	}
+	message := fmt.Sprintf("Move %d prompts to %s", len(report.Moved), pack)
+	if pack != "personal" {
+		message = fmt.Sprintf("Move %d prompts to pack %s", len(report.Moved), pack)
+	}
+	s.syncBulkChange(message, "moving prompts", library, packs)
+	return report, s.loadPrompts()
+}
+
+// movePrompt saves p as its next version at path in pack, removes its old
+// file and updates the pack.json listings, returning the new version
+func (s *Service) movePrompt(p *models.Prompt, pack, path string) (string, error) {
+	full, err := s.GetPrompt(p.ID)
+	if err != nil {
+		return "", err
+	}
+	moved := *full
+	moved.Pack = pack
+	if pack == "personal" {
+		moved.Pack = ""
+	}
+	moved.FilePath = path
+	if err := s.writePromptVersion(&moved); err != nil {
+		return "", err
+	}
+	// A prompt whose pack label didn't match its folder is saved at path
+	// without the old file being removed
+	if _, err := os.Stat(filepath.Join(s.GetBaseDir(), full.FilePath)); err == nil && moved.FilePath != full.FilePath {
+		if err := s.storage.DeletePrompt(full); err != nil {
+			return "", err
+		}
+	}
+
+	if from := storage.PackFromPath(full.FilePath); from != "" {
+		if err := s.packConfig.SetPromptListed(from, p.ID, false); err != nil {
+			fmt.Printf("Warning: failed to update pack.json of %s: %v\n", from, err)
+		}
+	}
+	if pack != "personal" {
+		if err := s.packConfig.SetPromptListed(pack, p.ID, true); err != nil {
+			fmt.Printf("Warning: failed to update pack.json of %s: %v\n", pack, err)
+		}
+	}
+	return moved.Version, nil
+}
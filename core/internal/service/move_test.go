@@ -0,0 +1,104 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestMovePrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	team := filepath.Join(tmpDir, "team")
+	os.MkdirAll(filepath.Join(team, "prompts"), 0755)
+	manifest := "{\n  \"name\": \"team\",\n  \"version\": \"1.0.0\",\n  \"title\": \"Team Pack\",\n  \"prompts\": [\"shared\"]\n}\n"
+	os.WriteFile(filepath.Join(team, "pack.json"), []byte(manifest), 0644)
+	os.WriteFile(filepath.Join(team, "prompts", "shared.md"), []byte("---\nid: shared\ntitle: Shared\npack: team\n---\nShared\n"), 0644)
+	if _, err := svc.InstallPack(team, config.PackInstallOptions{}); err != nil {
+		t.Fatalf("InstallPack: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a", Name: "A", Tags: []string{"drafts"}, Content: "a"},
+		{ID: "b", Name: "B", Tags: []string{"drafts"}, Content: "b"},
+		{ID: "c", Name: "C", Tags: []string{"ready"}, Content: "c"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	library := svc.GetBaseDir()
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Join(library, path))
+		return err == nil
+	}
+
+	drafts, err := svc.MoveCandidates("", models.NewTagExpression("drafts"))
+	if err != nil || len(drafts) != 2 {
+		t.Fatalf("MoveCandidates = %d prompts, %v, want a and b", len(drafts), err)
+	}
+	report, err := svc.MovePrompts(drafts, "team", true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(report.Moved) != 2 || report.Moved[0].Path != filepath.Join("packs", "team", "prompts", "a.md") || !exists(filepath.Join("prompts", "a.md")) {
+		t.Fatalf("dry run = %+v, want a and b listed without moving them", report)
+	}
+
+	report, err = svc.MovePrompts(drafts, "team", false)
+	if err != nil {
+		t.Fatalf("MovePrompts: %v", err)
+	}
+	if len(report.Moved) != 2 || report.Moved[0].From != "personal" || report.Moved[0].Version == "" {
+		t.Fatalf("MovePrompts = %+v, want a and b moved from the personal library in new versions", report)
+	}
+	for _, id := range []string{"a", "b"} {
+		if exists(filepath.Join("prompts", id+".md")) || !exists(filepath.Join("packs", "team", "prompts", id+".md")) {
+			t.Errorf("%s was not moved into the team pack's folder", id)
+		}
+	}
+	if prompts, _ := svc.ListPrompts(); len(prompts) != 1 || prompts[0].ID != "c" {
+		t.Errorf("The personal library still lists %d prompts, want only c", len(prompts))
+	}
+	data, _ := os.ReadFile(filepath.Join(library, "packs", "team", "pack.json"))
+	if !strings.HasPrefix(string(data), "{\n  \"name\": \"team\",\n  \"version\"") || !strings.Contains(string(data), `"prompts": [`) {
+		t.Errorf("pack.json was rewritten out of order:\n%s", data)
+	}
+	if pack, _ := svc.GetPack("team"); strings.Join(pack.Prompts, " ") != "shared a b" {
+		t.Errorf("pack.json lists %v, want shared a b", pack.Prompts)
+	}
+
+	// Moving back takes the prompt off the pack's listing
+	a, _ := svc.GetPrompt("a")
+	report, err = svc.MovePrompts([]*models.Prompt{a}, "personal", false)
+	if err != nil || len(report.Moved) != 1 || !exists(filepath.Join("prompts", "a.md")) || exists(filepath.Join("packs", "team", "prompts", "a.md")) {
+		t.Fatalf("Moving a back = %+v, %v, want it in the personal library again", report, err)
+	}
+	if pack, _ := svc.GetPack("team"); strings.Join(pack.Prompts, " ") != "shared b" {
+		t.Errorf("pack.json lists %v after moving a back, want shared b", pack.Prompts)
+	}
+	if moved, _ := svc.GetPrompt("a"); moved.Pack != "" || moved.Version == a.Version {
+		t.Errorf("a moved back has pack %q at version %s, want no pack in a new version", moved.Pack, moved.Version)
+	}
+
+	a, _ = svc.GetPrompt("a")
+	c, _ := svc.GetPrompt("c")
+	report, err = svc.MovePrompts([]*models.Prompt{a, c}, "personal", false)
+	if err != nil || len(report.Moved) != 0 || len(report.Unchanged) != 2 {
+		t.Errorf("Moving prompts already there = %+v, %v, want both unchanged", report, err)
+	}
+	if _, err := svc.MovePrompts([]*models.Prompt{c}, "nowhere", true); err == nil {
+		t.Error("Moving to a pack that isn't installed should fail")
+	}
+}
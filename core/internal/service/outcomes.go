@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// LogOutcome records how a run of a prompt went in the prompt's outcome log,
+// noting the version that was run and who logged it
+func (s *Service) LogOutcome(id string, outcome models.Outcome, note string) (*models.OutcomeEntry, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := models.OutcomeEntry{
+		Time:    time.Now().UTC(),
+		Version: prompt.Version,
+		Outcome: outcome,
+		Note:    note,
+		Author:  s.LockOwner(),
+	}
+	if err := s.storage.AppendOutcome(prompt, entry); err != nil {
+		return nil, err
+	}
+
+	if s.gitSync.IsEnabled() {
+		if err := s.gitSync.SyncChanges(fmt.Sprintf("Log outcome: %s (%s)", id, outcome)); err != nil {
+			fmt.Printf("Warning: Git sync failed after logging outcome: %v\n", err)
+		}
+	}
+	return &entry, nil
+}
+
+// Outcomes returns a prompt's logged outcomes, oldest first
+func (s *Service) Outcomes(id string) ([]models.OutcomeEntry, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.LoadOutcomes(prompt)
+}
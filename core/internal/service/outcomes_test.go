@@ -0,0 +1,58 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestLogOutcome(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Version: "1.0.0", Content: "Review this"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	if _, err := svc.LogOutcome("review", models.OutcomeGood, "caught the race"); err != nil {
+		t.Fatalf("LogOutcome: %v", err)
+	}
+	current, _ := svc.GetPrompt("review")
+	updated := *current
+	updated.Content = "Review this carefully"
+	if err := svc.UpdatePrompt(&updated); err != nil {
+		t.Fatalf("UpdatePrompt: %v", err)
+	}
+	if _, err := svc.LogOutcome("review", models.OutcomeBad, ""); err != nil {
+		t.Fatalf("LogOutcome: %v", err)
+	}
+
+	entries, err := svc.Outcomes("review")
+	if err != nil {
+		t.Fatalf("Outcomes: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Note != "caught the race" || entries[0].Version != "1.0.0" || entries[1].Version != "1.0.1" {
+		t.Fatalf("outcomes = %+v, want a good 1.0.0 entry then a bad 1.0.1 entry", entries)
+	}
+	if summary := models.SummarizeOutcomes(entries); summary.String() != "1 good, 1 bad (50%)" {
+		t.Errorf("summary = %s, want 1 good, 1 bad (50%%)", summary)
+	}
+
+	prompt, _ := svc.GetPrompt("review")
+	if _, err := os.Stat(filepath.Join(tmpDir, storage.OutcomesPath(prompt))); err != nil {
+		t.Errorf("outcome log is not next to the prompt: %v", err)
+	}
+	if _, err := svc.LogOutcome("missing", models.OutcomeGood, ""); err == nil {
+		t.Error("logging an outcome for a missing prompt succeeded")
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/diff"
+)
+
+// PackPromptDiff is a prompt file of a pack changed locally since the
+// version installed from upstream
+type PackPromptDiff struct {
+	Path   string   `json:"path"` // Relative to the pack
+	ID     string   `json:"id"`
+	Status string   `json:"status"` // modified, added or deleted
+	Diff   []string `json:"diff,omitempty"`
+	// Compared with the latest remote, when it was fetched
+	Remote     string   `json:"remote,omitempty"` // Whether upstream left the file unchanged, modified, added or deleted it since install
+	RemoteDiff []string `json:"remote_diff,omitempty"`
+}
+
+// PackDiff shows how a pack's prompts were customized since it was
+// installed or last updated from upstream
+type PackDiff struct {
+	Pack      string           `json:"pack"`
+	Installed string           `json:"installed"`        // Upstream commit compared with
+	Latest    string           `json:"latest,omitempty"` // Latest remote commit, when fetched
+	Prompts   []PackPromptDiff `json:"prompts"`
+	// Prompt files changed in the latest remote that weren't changed locally,
+	// which an update would bring in
+	Incoming []config.PackFileChange `json:"incoming,omitempty"`
+}
+
+// DiffPack compares the prompts of a pack installed from Git with the
+// upstream version it was installed or last updated at. With remote, it
+// fetches the latest version first and also compares each customized
+// prompt with it, and lists the prompts an update would change.
+func (s *Service) DiffPack(name string, remote bool) (*PackDiff, error) {
+	pack, err := s.packConfig.GetPack(name)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := s.packConfig.InstalledCommit(name)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.packConfig.LocalChanges(name, installed, "prompts")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PackDiff{Pack: name, Installed: installed, Prompts: []PackPromptDiff{}}
+	var incoming []config.PackFileChange
+	upstream := map[string]string{}
+	if remote {
+		if err := s.packConfig.FetchUpstream(name); err != nil {
+			return nil, err
+		}
+		if result.Latest, err = s.packConfig.UpstreamCommit(name); err != nil {
+			return nil, err
+		}
+		if incoming, err = s.packConfig.ChangesBetween(name, installed, result.Latest, "prompts"); err != nil {
+			return nil, err
+		}
+		for _, change := range incoming {
+			upstream[change.Path] = change.Status
+		}
+	}
+
+	local := map[string]bool{}
+	for _, change := range changes {
+		if !strings.HasSuffix(change.Path, ".md") {
+			continue
+		}
+		local[change.Path] = true
+		before, _ := s.packConfig.FileAt(name, installed, change.Path)
+		after := ""
+		if change.Status != config.PackFileDeleted {
+			data, err := os.ReadFile(filepath.Join(pack.Path, filepath.FromSlash(change.Path)))
+			if err != nil {
+				return nil, err
+			}
+			after = string(data)
+		}
+
+		prompt := PackPromptDiff{
+			Path:   change.Path,
+			ID:     strings.TrimSuffix(path.Base(change.Path), ".md"),
+			Status: change.Status,
+			Diff:   diff.Lines(before, after),
+		}
+		if remote {
+			prompt.Remote = "unchanged"
+			if status, ok := upstream[change.Path]; ok {
+				prompt.Remote = status
+				latest, _ := s.packConfig.FileAt(name, result.Latest, change.Path)
+				prompt.RemoteDiff = diff.Lines(latest, after)
+			}
+		}
+		result.Prompts = append(result.Prompts, prompt)
+	}
+
+	for _, change := range incoming {
+		if strings.HasSuffix(change.Path, ".md") && !local[change.Path] {
+			result.Incoming = append(result.Incoming, change)
+		}
+	}
+	return result, nil
+}
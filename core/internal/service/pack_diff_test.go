@@ -0,0 +1,98 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+func TestDiffPack(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+	}
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A pack published as a Git repository
+	work := filepath.Join(tmpDir, "work")
+	os.MkdirAll(filepath.Join(work, "prompts"), 0755)
+	write(filepath.Join(work, "pack.json"), `{"name": "team", "version": "1.0.0", "title": "Team Pack"}`)
+	write(filepath.Join(work, "prompts", "review.md"), "---\nid: review\ntitle: Review\n---\nReview this code.\n")
+	write(filepath.Join(work, "prompts", "summary.md"), "---\nid: summary\ntitle: Summary\n---\nSummarize this.\n")
+	git(work, "init", "-q")
+	git(work, "add", "-A")
+	git(work, "commit", "-qm", "First version")
+	git(tmpDir, "clone", "-q", "--bare", "work", "team-pack.git")
+	git(work, "remote", "add", "origin", filepath.Join(tmpDir, "team-pack.git"))
+
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	pack, err := svc.InstallPack(filepath.Join(tmpDir, "team-pack.git"), config.PackInstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallPack: %v", err)
+	}
+	if diff, err := svc.DiffPack("team", false); err != nil || len(diff.Prompts) != 0 {
+		t.Fatalf("DiffPack of an untouched pack = %+v, %v, want no changes", diff, err)
+	}
+
+	// Customize one prompt and add another
+	write(filepath.Join(pack.Path, "prompts", "review.md"), "---\nid: review\ntitle: Review\n---\nReview this Go code.\n")
+	write(filepath.Join(pack.Path, "prompts", "mine.md"), "---\nid: mine\ntitle: Mine\n---\nMine.\n")
+
+	diff, err := svc.DiffPack("team", false)
+	if err != nil {
+		t.Fatalf("DiffPack: %v", err)
+	}
+	if len(diff.Prompts) != 2 || diff.Prompts[0].ID != "review" || diff.Prompts[0].Status != config.PackFileModified || diff.Prompts[1].Status != config.PackFileAdded {
+		t.Fatalf("DiffPack = %+v, want review modified and mine added", diff.Prompts)
+	}
+	if review := diff.Prompts[0].Diff; !slices.Contains(review, "- Review this code.") || !slices.Contains(review, "+ Review this Go code.") {
+		t.Errorf("review diff = %q", review)
+	}
+
+	// Upstream changes the customized prompt and another one
+	write(filepath.Join(work, "prompts", "review.md"), "---\nid: review\ntitle: Review\n---\nReview this code carefully.\n")
+	write(filepath.Join(work, "prompts", "summary.md"), "---\nid: summary\ntitle: Summary\n---\nSummarize this briefly.\n")
+	git(work, "commit", "-qam", "Second version")
+	git(work, "push", "-q", "origin", "HEAD")
+
+	diff, err = svc.DiffPack("team", true)
+	if err != nil {
+		t.Fatalf("DiffPack with remote: %v", err)
+	}
+	if diff.Latest == "" || diff.Latest == diff.Installed {
+		t.Errorf("Latest = %q, want the pushed commit rather than %q", diff.Latest, diff.Installed)
+	}
+	if review := diff.Prompts[0]; review.Remote != config.PackFileModified || !slices.Contains(review.RemoteDiff, "- Review this code carefully.") {
+		t.Errorf("review compared with the remote = %+v", review)
+	}
+	if mine := diff.Prompts[1]; mine.Remote != "unchanged" {
+		t.Errorf("mine compared with the remote = %+v, want unchanged", mine)
+	}
+	if len(diff.Incoming) != 1 || diff.Incoming[0].Path != "prompts/summary.md" {
+		t.Errorf("Incoming = %+v, want only summary", diff.Incoming)
+	}
+
+	// Fetching doesn't change what was installed
+	if again, _ := svc.DiffPack("team", false); again.Installed != diff.Installed {
+		t.Errorf("Installed moved from %s to %s after fetching", diff.Installed, again.Installed)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// packSearches returns the saved searches installed packs declare in their
+// pack.json, named <pack>/<search> so they never collide with the library's
+// own searches or with another pack's
+func (s *Service) packSearches() []models.SavedSearch {
+	var searches []models.SavedSearch
+	for _, pack := range s.packConfig.ListPacks() {
+		installed := pack.InstallTime.Format(time.RFC3339)
+		for _, ps := range pack.Searches {
+			search := models.SavedSearch{
+				Name:        pack.Name + "/" + ps.Name,
+				Description: ps.Description,
+				TextQuery:   ps.TextQuery,
+				Pack:        pack.Name,
+				CreatedAt:   installed,
+				UpdatedAt:   installed,
+			}
+			if ps.Expression != "" {
+				expr, err := models.ParseBooleanExpression(ps.Expression)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: skipping saved search %s: %v\n", search.Name, err)
+					continue
+				}
+				search.Expression = expr
+			}
+			searches = append(searches, search)
+		}
+	}
+	return searches
+}
+
+// packSearch returns the pack search called name, if a pack ships one
+func (s *Service) packSearch(name string) (*models.SavedSearch, bool) {
+	if !strings.Contains(name, "/") {
+		return nil, false
+	}
+	for _, search := range s.packSearches() {
+		if search.Name == name {
+			return &search, true
+		}
+	}
+	return nil, false
+}
+
+// readOnlyPackSearch reports an attempt to change a search a pack ships
+func readOnlyPackSearch(search *models.SavedSearch) error {
+	return fmt.Errorf("saved search %s comes from pack %s and cannot be changed; save a copy under another name instead", search.Name, search.Pack)
+}
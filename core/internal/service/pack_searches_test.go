@@ -0,0 +1,86 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestPackSavedSearches(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "draft-brief", Name: "Draft Brief", Tags: []string{"brief", "draft"}, Content: "Brief"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	packDir := filepath.Join(tmpDir, "team-pack")
+	if err := os.MkdirAll(filepath.Join(packDir, "prompts"), 0755); err != nil {
+		t.Fatalf("Failed to create pack: %v", err)
+	}
+	manifest := `{
+  "name": "team",
+  "version": "1.0.0",
+  "title": "Team Pack",
+  "searches": [
+    {"name": "drafts", "description": "Unfinished work", "expression": "draft AND NOT reviewed"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(packDir, "pack.json"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write pack.json: %v", err)
+	}
+	if err := svc.InstallPackFromDirectory(packDir, config.PackInstallOptions{}); err != nil {
+		t.Fatalf("InstallPackFromDirectory: %v", err)
+	}
+
+	searches, err := svc.ListSavedSearches()
+	if err != nil || len(searches) != 1 || searches[0].Name != "team/drafts" || searches[0].Pack != "team" {
+		t.Fatalf("ListSavedSearches = %+v, %v; want team/drafts from the pack", searches, err)
+	}
+	results, err := svc.ExecuteSavedSearch("team/drafts")
+	if err != nil || len(results) != 1 || results[0].ID != "draft-brief" {
+		t.Errorf("ExecuteSavedSearch(team/drafts) = %v, %v; want draft-brief", results, err)
+	}
+
+	if err := svc.DeleteSavedSearch("team/drafts"); err == nil {
+		t.Error("deleting a pack's saved search succeeded")
+	}
+	if err := svc.SaveBooleanSearch(models.SavedSearch{Name: "team/drafts", Expression: models.NewTagExpression("draft")}); err == nil {
+		t.Error("overwriting a pack's saved search succeeded")
+	}
+	if err := svc.SetPinnedSearch("team/drafts"); err != nil {
+		t.Errorf("pinning a pack's saved search: %v", err)
+	}
+
+	if err := svc.UninstallPack("team"); err != nil {
+		t.Fatalf("UninstallPack: %v", err)
+	}
+	if searches, _ := svc.ListSavedSearches(); len(searches) != 0 {
+		t.Errorf("after uninstalling, saved searches = %+v, want none", searches)
+	}
+}
+
+func TestPackSearchesAreValidated(t *testing.T) {
+	packDir := t.TempDir()
+	manifest := `{"name": "team", "version": "1.0.0", "title": "Team", "searches": [{"name": "broken", "expression": "ai AND ("}]}`
+	if err := os.WriteFile(filepath.Join(packDir, "pack.json"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write pack.json: %v", err)
+	}
+	packs, err := config.NewPackConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewPackConfig: %v", err)
+	}
+	if _, err := packs.LoadPackMetadata(packDir); err == nil {
+		t.Error("a pack with an invalid search expression loaded")
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+)
+
+func TestInstallAndUpdatePack(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+	}
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	writePack := func(dir, version string) {
+		manifest := `{"name": "team", "version": "` + version + `", "title": "Team Pack"}`
+		if err := os.WriteFile(filepath.Join(dir, "pack.json"), []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A pack published as a Git repository
+	work := filepath.Join(tmpDir, "work")
+	os.MkdirAll(filepath.Join(work, "prompts"), 0755)
+	os.WriteFile(filepath.Join(work, "prompts", "hello.md"), []byte("---\nid: hello\ntitle: Hello\n---\nHi\n"), 0644)
+	writePack(work, "1.0.0")
+	git(work, "init", "-q")
+	git(work, "add", "-A")
+	git(work, "commit", "-qm", "First version")
+	git(tmpDir, "clone", "-q", "--bare", "work", "team-pack.git")
+	git(work, "remote", "add", "origin", filepath.Join(tmpDir, "team-pack.git"))
+
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	pack, err := svc.InstallPack(filepath.Join(tmpDir, "team-pack.git"), config.PackInstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallPack: %v", err)
+	}
+	if pack.Name != "team" || pack.Version != "1.0.0" {
+		t.Fatalf("InstallPack = %s %s, want team 1.0.0", pack.Name, pack.Version)
+	}
+
+	writePack(work, "1.1.0")
+	git(work, "commit", "-qam", "Second version")
+	git(work, "push", "-q", "origin", "HEAD")
+
+	pack, err = svc.UpdatePack("team")
+	if err != nil {
+		t.Fatalf("UpdatePack: %v", err)
+	}
+	if pack.Version != "1.1.0" || pack.InstallURL == "" {
+		t.Errorf("UpdatePack = version %s from %q, want 1.1.0 from the original URL", pack.Version, pack.InstallURL)
+	}
+
+	// A pack copied from a directory has nothing to pull from
+	local := filepath.Join(tmpDir, "local")
+	os.MkdirAll(filepath.Join(local, "prompts"), 0755)
+	writePack(local, "1.0.0")
+	if _, err := svc.InstallPack(local, config.PackInstallOptions{Name: "local"}); err != nil {
+		t.Fatalf("InstallPack from directory: %v", err)
+	}
+	if _, err := svc.UpdatePack("local"); err == nil {
+		t.Error("Expected updating a pack installed from a directory to fail")
+	}
+}
+
+func TestInstallPackRejectsNamesOutsidePacksDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+	}
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	writePack := func(dir, name string) {
+		os.MkdirAll(filepath.Join(dir, "prompts"), 0755)
+		manifest := `{"name": "` + name + `", "version": "1.0.0", "title": "Pack"}`
+		if err := os.WriteFile(filepath.Join(dir, "pack.json"), []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The packs directory is library/packs, so these names resolve to tmpDir/escape
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+
+	src := filepath.Join(tmpDir, "src")
+	writePack(src, "team")
+	if _, err := svc.InstallPack(src, config.PackInstallOptions{Name: "../../escape"}); err == nil || !strings.Contains(err.Error(), "path separators") {
+		t.Errorf("Expected a requested name with path separators to be rejected, got %v", err)
+	}
+
+	hostile := filepath.Join(tmpDir, "hostile")
+	writePack(hostile, "../../escape")
+	if _, err := svc.InstallPack(hostile, config.PackInstallOptions{}); err == nil || !strings.Contains(err.Error(), "path separators") {
+		t.Errorf("Expected a pack.json name with path separators to be rejected, got %v", err)
+	}
+
+	git(hostile, "init", "-q")
+	git(hostile, "add", "-A")
+	git(hostile, "commit", "-qm", "Pack")
+	git(tmpDir, "clone", "-q", "--bare", "hostile", "hostile.git")
+	if _, err := svc.InstallPack(filepath.Join(tmpDir, "hostile.git"), config.PackInstallOptions{}); err == nil || !strings.Contains(err.Error(), "path separators") {
+		t.Errorf("Expected a cloned pack.json name with path separators to be rejected, got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "escape")); !os.IsNotExist(err) {
+		t.Errorf("A pack was written outside the packs directory: %v", err)
+	}
+	if packs, _ := svc.ListPacks(); len(packs) != 0 {
+		t.Errorf("ListPacks = %d packs, want none installed", len(packs))
+	}
+}
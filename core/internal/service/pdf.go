@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/redact"
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+)
+
+// ExportPDF typesets prompts as a print-friendly PDF for offline review and
+// compliance records. Each prompt starts on a new page under a header with
+// its title, description, version, tags and where it comes from, followed
+// by its content as 'pkt share' writes it and a table of its variables.
+// Sensitive variables never show their defaults. With a redactor, the
+// library's redaction rules apply to all of the text.
+func (s *Service) ExportPDF(prompts []*models.Prompt, title string, redactor *redact.Redactor, generated time.Time) ([]byte, error) {
+	export := renderer.PDFExport{Title: title, Generated: generated}
+	for _, prompt := range prompts {
+		content, declared, err := s.documentContent(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if len(declared) > 0 {
+			content += "\n\n## Variables\n\n" + variablesTable(declared)
+		}
+
+		info := renderer.PreviewInfo{
+			Kind:        "Prompt",
+			Title:       prompt.Name,
+			Description: prompt.Summary,
+			Version:     prompt.Version,
+			Tags:        prompt.Tags,
+			Details:     []string{"ID: " + prompt.ID},
+		}
+		if info.Title == "" {
+			info.Title = prompt.ID
+		}
+		if prompt.TemplateRef != "" {
+			info.Details = append(info.Details, "Template: "+prompt.TemplateRef)
+		}
+		if prompt.Pack != "" && prompt.Pack != "personal" {
+			info.Details = append(info.Details, "Pack: "+prompt.Pack)
+		}
+		if !prompt.UpdatedAt.IsZero() {
+			info.Details = append(info.Details, "Updated: "+prompt.UpdatedAt.Format("2006-01-02"))
+		}
+
+		if redactor != nil {
+			info.Title = redactor.String(info.Title)
+			info.Description = redactor.String(info.Description)
+			content = redactor.String(content)
+		}
+		export.Entries = append(export.Entries, renderer.PDFEntry{Info: info, Content: content})
+	}
+	return renderer.RenderPDF(export)
+}
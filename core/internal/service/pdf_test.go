@@ -0,0 +1,68 @@
+package service
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestExportPDF(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	prompts := []*models.Prompt{
+		{ID: "contract-review", Version: "1.0.0", Name: "Contract Review", Tags: []string{"legal"}, Content: "Send questions to counsel@example.com.",
+			Variables: []models.Variable{{Name: "token", Default: "secret-value", Sensitive: true}}},
+		{ID: "nda", Version: "2.1.0", Content: "Check the NDA."},
+	}
+	for _, prompt := range prompts {
+		if err := svc.CreatePrompt(prompt); err != nil {
+			t.Fatalf("CreatePrompt: %v", err)
+		}
+	}
+	redactor, err := svc.Redactor()
+	if err != nil {
+		t.Fatalf("Redactor: %v", err)
+	}
+
+	data, err := svc.ExportPDF(prompts, "Legal", redactor, time.Now())
+	if err != nil {
+		t.Fatalf("ExportPDF: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.Contains(data, []byte("/Count 3")) {
+		t.Fatalf("export is not a PDF with a contents page and a page for each prompt")
+	}
+
+	var text strings.Builder
+	for _, match := range regexp.MustCompile(`/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		r, err := zlib.NewReader(bytes.NewReader(data[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatalf("content stream: %v", err)
+		}
+		content, _ := io.ReadAll(r)
+		text.Write(content)
+	}
+	// PDF text is in the Windows-1252 encoding of the standard fonts
+	masked, _ := charmap.Windows1252.NewEncoder().String(MaskedValue)
+	for _, want := range []string{"(Contract Review)", "(ID: contract-review)", "(Variables)", "(" + masked + ")", "(nda)", "(Version 2.1.0)"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("PDF lacks %s", want)
+		}
+	}
+	for _, leak := range []string{"secret-value", "counsel@example.com"} {
+		if strings.Contains(text.String(), leak) {
+			t.Errorf("PDF reveals %q", leak)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// profileRuns is how many times PerfReport runs each saved search
+const profileRuns = 3
+
+// PerfReport shows where search time goes in a library: how long each saved
+// search takes now, and the searches logged as slow while it was in use
+type PerfReport struct {
+	Prompts       int              `json:"prompts"`
+	Threshold     time.Duration    `json:"threshold_ns"` // 0 when slow queries are not logged
+	SavedSearches []SearchProfile  `json:"saved_searches"`
+	SlowQueries   []SlowQueryGroup `json:"slow_queries"`
+}
+
+// SearchProfile is how long one saved search takes against the library
+type SearchProfile struct {
+	Name       string        `json:"name"`
+	Query      string        `json:"query"`
+	Duration   time.Duration `json:"duration_ns"` // Mean of profileRuns runs
+	Results    int           `json:"results"`
+	Complexity int           `json:"complexity"`
+	Slow       bool          `json:"slow"`
+}
+
+// SlowQueryGroup is every logged run of one slow query
+type SlowQueryGroup struct {
+	Kind        string        `json:"kind"`
+	Query       string        `json:"query"`
+	SavedSearch string        `json:"saved_search,omitempty"`
+	Count       int           `json:"count"`
+	Total       time.Duration `json:"total_ns"`
+	Max         time.Duration `json:"max_ns"`
+	Mean        time.Duration `json:"mean_ns"`
+	Prompts     int           `json:"prompts"` // Library size at the latest run
+	Complexity  int           `json:"complexity"`
+	LastSeen    time.Time     `json:"last_seen"`
+}
+
+// logIfSlow records a search in the slow-query log when it took longer than
+// the library's threshold since start, which excludes loading the prompts.
+// Logging is best effort and never fails the search.
+func (s *Service) logIfSlow(query storage.SlowQuery, start time.Time) {
+	threshold := s.settings.Search.SlowQueryThreshold()
+	query.Duration = time.Since(start)
+	if threshold == 0 || query.Duration < threshold {
+		return
+	}
+	query.Time = time.Now().UTC()
+	s.slowQueries.Record(query)
+}
+
+// PerfReport times every saved search against the library, slowest first,
+// and groups the slow-query log by query, most time spent first. Profiling
+// runs are not logged.
+func (s *Service) PerfReport() (*PerfReport, error) {
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+	searches, err := s.ListSavedSearches()
+	if err != nil {
+		return nil, err
+	}
+	report := &PerfReport{
+		Prompts:       len(prompts),
+		Threshold:     s.settings.Search.SlowQueryThreshold(),
+		SavedSearches: []SearchProfile{},
+	}
+
+	for i := range searches {
+		search := &searches[i]
+		var results []*models.Prompt
+		start := time.Now()
+		for run := 0; run < profileRuns; run++ {
+			results = s.runSavedSearch(search, search.TextQuery)
+		}
+		profile := SearchProfile{
+			Name:       search.Name,
+			Query:      search.Expression.QueryString(),
+			Duration:   time.Since(start) / profileRuns,
+			Results:    len(results),
+			Complexity: search.Expression.Complexity() + len(strings.Fields(search.TextQuery)),
+		}
+		profile.Slow = report.Threshold > 0 && profile.Duration >= report.Threshold
+		report.SavedSearches = append(report.SavedSearches, profile)
+	}
+	sort.SliceStable(report.SavedSearches, func(i, j int) bool {
+		return report.SavedSearches[i].Duration > report.SavedSearches[j].Duration
+	})
+
+	logged, err := s.slowQueries.Load()
+	if err != nil {
+		return nil, err
+	}
+	report.SlowQueries = groupSlowQueries(logged, searches)
+	return report, nil
+}
+
+// ClearSlowQueries empties the slow-query log
+func (s *Service) ClearSlowQueries() error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	return s.slowQueries.Clear()
+}
+
+// groupSlowQueries merges logged runs of the same query. A boolean search
+// whose expression is a saved search's, as when the TUI runs one, is
+// attributed to that saved search.
+func groupSlowQueries(logged []storage.SlowQuery, searches []models.SavedSearch) []SlowQueryGroup {
+	savedByQuery := make(map[string]string)
+	for _, search := range searches {
+		savedByQuery[search.Expression.QueryString()] = search.Name
+	}
+
+	groups := []SlowQueryGroup{}
+	index := make(map[string]int)
+	for _, entry := range logged {
+		if entry.SavedSearch == "" && entry.Kind == "boolean" {
+			entry.SavedSearch = savedByQuery[entry.Query]
+		}
+		key := entry.Kind + "\x00" + entry.SavedSearch + "\x00" + entry.Query
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, SlowQueryGroup{Kind: entry.Kind, Query: entry.Query, SavedSearch: entry.SavedSearch})
+		}
+		group := &groups[i]
+		group.Count++
+		group.Total += entry.Duration
+		group.Max = max(group.Max, entry.Duration)
+		if !entry.Time.Before(group.LastSeen) {
+			group.LastSeen = entry.Time
+			group.Prompts = entry.Prompts
+			group.Complexity = entry.Complexity
+		}
+	}
+	for i := range groups {
+		groups[i].Mean = groups[i].Total / time.Duration(groups[i].Count)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Total > groups[j].Total })
+	return groups
+}
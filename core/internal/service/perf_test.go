@@ -0,0 +1,89 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "review", Name: "Code Review", Content: "Review this", Tags: []string{"code", "review"}},
+		{ID: "draft", Name: "Draft", Content: "Draft this", Tags: []string{"code", "draft"}},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("CreatePrompt: %v", err)
+		}
+	}
+	expression := models.NewAndExpression(models.NewTagExpression("code"), models.NewNotExpression(models.NewTagExpression("draft")))
+	if err := svc.SaveBooleanSearch(models.SavedSearch{Name: "ready", Expression: expression}); err != nil {
+		t.Fatalf("SaveBooleanSearch: %v", err)
+	}
+
+	// Every search is slow with a 1ns threshold
+	svc.Settings().Search.SlowQuery = "1ns"
+	for i := 0; i < 2; i++ {
+		if _, err := svc.SearchPrompts("tag:code review"); err != nil {
+			t.Fatalf("SearchPrompts: %v", err)
+		}
+	}
+	if _, err := svc.SearchPromptsByBooleanExpression(expression); err != nil {
+		t.Fatalf("SearchPromptsByBooleanExpression: %v", err)
+	}
+	svc.SetReadOnly(true)
+	if _, err := svc.ExecuteSavedSearch("ready"); err != nil {
+		t.Fatalf("ExecuteSavedSearch: %v", err)
+	}
+	svc.SetReadOnly(false)
+
+	report, err := svc.PerfReport()
+	if err != nil {
+		t.Fatalf("PerfReport: %v", err)
+	}
+	if report.Prompts != 2 || len(report.SavedSearches) != 1 {
+		t.Fatalf("report = %+v, want 2 prompts and 1 saved search", report)
+	}
+	if profile := report.SavedSearches[0]; profile.Name != "ready" || profile.Results != 1 || profile.Complexity != 4 || !profile.Slow {
+		t.Errorf("saved search profile = %+v", profile)
+	}
+
+	// The read-only run is not logged, and neither is profiling
+	if len(report.SlowQueries) != 2 {
+		t.Fatalf("slow queries = %+v, want the search and the boolean search", report.SlowQueries)
+	}
+	byKind := make(map[string]SlowQueryGroup)
+	for _, g := range report.SlowQueries {
+		byKind[g.Kind] = g
+	}
+	if g := byKind["search"]; g.Count != 2 || g.Query != "tag:code review" || g.Complexity != 2 || g.Prompts != 2 {
+		t.Errorf("search group = %+v", g)
+	}
+	if g := byKind["boolean"]; g.Count != 1 || g.SavedSearch != "ready" || g.Complexity != 4 {
+		t.Errorf("boolean group = %+v, want it attributed to the saved search", g)
+	}
+
+	if err := svc.ClearSlowQueries(); err != nil {
+		t.Fatalf("ClearSlowQueries: %v", err)
+	}
+	svc.Settings().Search.SlowQuery = "off"
+	if _, err := svc.SearchPrompts("review"); err != nil {
+		t.Fatalf("SearchPrompts: %v", err)
+	}
+	if report, err = svc.PerfReport(); err != nil {
+		t.Fatalf("PerfReport: %v", err)
+	}
+	if len(report.SlowQueries) != 0 {
+		t.Errorf("slow queries = %+v, want none once logging is off or cleared", report.SlowQueries)
+	}
+}
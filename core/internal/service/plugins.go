@@ -0,0 +1,179 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/plugin"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// Plugins lists the installed plugins, with the ones that failed to
+// describe themselves in errs
+func (s *Service) Plugins() ([]*plugin.Plugin, []error) {
+	return plugin.Discover()
+}
+
+// HasPluginImporter reports whether an installed plugin imports name, so
+// 'pkt import <name>' can tell plugin importers from files to import
+func (s *Service) HasPluginImporter(name string) bool {
+	_, err := plugin.Find(plugin.KindImporter, name)
+	return err == nil
+}
+
+// ImportFromPlugin runs the plugin importer called name with args and saves
+// what it returns as the built-in importers do, resolving conflicts with
+// existing prompts as options ask
+func (s *Service) ImportFromPlugin(name string, args []string, options importer.ImportOptions) (*importer.ImportResult, error) {
+	p, err := plugin.Find(plugin.KindImporter, name)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := p.Import(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &importer.ImportResult{}
+	for _, prompt := range bundle.Prompts {
+		if err := preparePluginPrompt(prompt, name, options.Tags); err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		result.Prompts = append(result.Prompts, prompt)
+	}
+	for _, template := range bundle.Templates {
+		if err := preparePluginTemplate(template); err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		result.Templates = append(result.Templates, template)
+	}
+	result.ApplySelection(options)
+	if options.DryRun {
+		return result, nil
+	}
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+
+	track := s.TrackSubscriptions(MatchFromImport)
+	for _, prompt := range result.Prompts {
+		if err := s.savePromptWithConflictResolution(prompt, options); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to save prompt %s: %w", prompt.ID, err))
+		}
+	}
+	for _, template := range result.Templates {
+		if err := s.saveTemplateWithConflictResolution(template, options); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to save template %s: %w", template.ID, err))
+		}
+	}
+
+	// Refresh the prompts cache after import
+	if err := s.loadPrompts(); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("failed to refresh prompts cache: %w", err))
+	}
+	track()
+
+	// Sync to git if enabled and no errors occurred
+	if s.gitSync.IsEnabled() && len(result.Errors) == 0 {
+		commitMessage := fmt.Sprintf("Import from %s: %d prompts, %d templates", name, len(result.Prompts), len(result.Templates))
+		if err := s.gitSync.SyncChanges(commitMessage); err != nil {
+			// Don't fail the operation if git sync fails
+			result.Errors = append(result.Errors, fmt.Errorf("git sync failed after import: %w", err))
+		}
+	}
+	return result, nil
+}
+
+// ExportWithPlugin runs the plugin exporter called name on prompts and
+// templates and returns what it writes
+func (s *Service) ExportWithPlugin(name string, prompts []*models.Prompt, templates []*models.Template) ([]byte, error) {
+	p, err := plugin.Find(plugin.KindExporter, name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported export format %q: %w", name, err)
+	}
+	return p.Export(name, &plugin.Bundle{Prompts: prompts, Templates: templates})
+}
+
+// formatWithPlugin hands a prompt and its rendered text to the plugin
+// formatter called name
+func (s *Service) formatWithPlugin(name string, prompt *models.Prompt, text string) (string, error) {
+	p, err := plugin.Find(plugin.KindFormatter, name)
+	if err != nil {
+		return "", fmt.Errorf("unsupported format %q (expected text, json or a plugin formatter): %w", name, err)
+	}
+	return p.Format(name, plugin.FormatRequest{Prompt: prompt, Text: text})
+}
+
+// preparePluginPrompt checks a prompt a plugin imported and fills in what
+// plugins may leave out. Plugins never choose where files are written.
+func preparePluginPrompt(prompt *models.Prompt, source string, tags []string) error {
+	if prompt == nil {
+		return fmt.Errorf("skipping an empty prompt")
+	}
+	if err := checkPluginID(prompt.ID); err != nil {
+		return fmt.Errorf("skipping prompt %q: %w", prompt.Name, err)
+	}
+	if prompt.Version == "" {
+		prompt.Version = "1.0.0"
+	}
+	now := time.Now()
+	if prompt.CreatedAt.IsZero() {
+		prompt.CreatedAt = now
+	}
+	if prompt.UpdatedAt.IsZero() {
+		prompt.UpdatedAt = now
+	}
+	for _, tag := range tags {
+		if !models.ContainsTag(prompt.Tags, tag) {
+			prompt.Tags = append(prompt.Tags, tag)
+		}
+	}
+	if prompt.Metadata == nil {
+		prompt.Metadata = map[string]interface{}{}
+	}
+	if _, ok := prompt.Metadata["source"]; !ok {
+		prompt.Metadata["source"] = source
+	}
+	prompt.FilePath = filepath.Join("prompts", prompt.ID+".md")
+	prompt.Source = ""
+	return nil
+}
+
+// preparePluginTemplate checks a template a plugin imported
+func preparePluginTemplate(template *models.Template) error {
+	if template == nil {
+		return fmt.Errorf("skipping an empty template")
+	}
+	if err := checkPluginID(template.ID); err != nil {
+		return fmt.Errorf("skipping template %q: %w", template.Name, err)
+	}
+	if template.Version == "" {
+		template.Version = "1.0.0"
+	}
+	now := time.Now()
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = now
+	}
+	if template.UpdatedAt.IsZero() {
+		template.UpdatedAt = now
+	}
+	template.FilePath = filepath.Join("templates", template.ID+".md")
+	return nil
+}
+
+// checkPluginID rejects IDs that would not name a single file
+func checkPluginID(id string) error {
+	if id == "" {
+		return fmt.Errorf("missing ID")
+	}
+	if id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("invalid ID %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/plugin"
+)
+
+func TestPluginImportAndFormat(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin is a shell script")
+	}
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	bin := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+describe) echo '{"protocol": 1, "importers": [{"name": "notes"}], "formatters": [{"name": "shout"}]}' ;;
+import) echo '{"prompts": [{"ID": "greeting", "Name": "Greeting", "Content": "Hello {{who}}"}, {"ID": "../escape", "Name": "Escape"}]}' ;;
+format) sed -n 's/.*"text":"\([^"]*\)".*/\1/p' | tr a-z A-Z ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, plugin.Prefix+"notes"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("OpenLibrary: %v", err)
+	}
+	if !svc.HasPluginImporter("notes") || svc.HasPluginImporter("shout") {
+		t.Error("HasPluginImporter should only report the importer")
+	}
+
+	result, err := svc.ImportFromPlugin("notes", nil, importer.ImportOptions{Tags: []string{"imported"}})
+	if err != nil {
+		t.Fatalf("ImportFromPlugin: %v", err)
+	}
+	if len(result.Prompts) != 1 || len(result.Errors) != 1 {
+		t.Fatalf("imported %d prompts with errors %v, want greeting saved and ../escape refused", len(result.Prompts), result.Errors)
+	}
+	prompt, err := svc.GetPrompt("greeting")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if prompt.FilePath != filepath.Join("prompts", "greeting.md") || prompt.Metadata["source"] != "notes" || !slices.Contains(prompt.Tags, "imported") {
+		t.Errorf("imported prompt at %s with metadata %v and tags %v", prompt.FilePath, prompt.Metadata, prompt.Tags)
+	}
+
+	text, err := svc.RenderPrompt("greeting", RenderOptions{Format: "shout", Variables: map[string]interface{}{"who": "bob"}})
+	if err != nil {
+		t.Fatalf("RenderPrompt with a plugin formatter: %v", err)
+	}
+	if strings.TrimSpace(text) != "HELLO BOB" {
+		t.Errorf("RenderPrompt = %q, want HELLO BOB", text)
+	}
+	if _, err := svc.RenderPrompt("greeting", RenderOptions{Format: "whisper"}); err == nil {
+		t.Error("RenderPrompt accepted a format no plugin provides")
+	}
+}
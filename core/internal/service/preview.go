@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+)
+
+// PreviewPrompt renders a prompt as a standalone HTML page for sharing, with
+// its title, description and tags above the formatted content. Variables,
+// profiles and redaction apply as for RenderPrompt, but a preview is not
+// counted as a use of the prompt.
+func (s *Service) PreviewPrompt(id string, opts RenderOptions) (string, error) {
+	_, r, variables, err := s.promptRenderer(id, opts)
+	if err != nil {
+		return "", err
+	}
+	return r.RenderHTML(variables)
+}
+
+// PreviewTemplate renders a template as a standalone HTML page for sharing,
+// listing its slots above its content
+func (s *Service) PreviewTemplate(id string, opts RenderOptions) (string, error) {
+	template, err := s.GetTemplate(id)
+	if err != nil {
+		return "", err
+	}
+
+	info := renderer.PreviewInfo{
+		Kind:        "Template",
+		Title:       template.Name,
+		Description: template.Description,
+		Version:     template.Version,
+	}
+	if info.Title == "" {
+		info.Title = template.ID
+	}
+	if len(template.Slots) > 0 {
+		slots := make([]string, 0, len(template.Slots))
+		for _, slot := range template.Slots {
+			name := slot.Name
+			if slot.Required {
+				name += " (required)"
+			}
+			slots = append(slots, name)
+		}
+		info.Details = append(info.Details, "Slots: "+strings.Join(slots, ", "))
+	}
+
+	content := template.Content
+	if opts.Redactor != nil {
+		info.Title = opts.Redactor.String(info.Title)
+		info.Description = opts.Redactor.String(info.Description)
+		content = opts.Redactor.String(content)
+	}
+	return renderer.PreviewHTML(info, content)
+}
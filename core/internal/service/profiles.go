@@ -0,0 +1,30 @@
+package service
+
+// ListProfiles returns the names of the library's variable profiles
+func (s *Service) ListProfiles() ([]string, error) {
+	return s.storage.ListProfiles()
+}
+
+// LoadProfile returns the variables a profile defines
+func (s *Service) LoadProfile(name string) (map[string]interface{}, error) {
+	return s.storage.LoadProfile(name)
+}
+
+// ProfileVariables merges overrides over the named profile's variables. With
+// no profile it returns overrides alone.
+func (s *Service) ProfileVariables(profile string, overrides map[string]interface{}) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	if profile != "" {
+		loaded, err := s.storage.LoadProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range loaded {
+			variables[name] = value
+		}
+	}
+	for name, value := range overrides {
+		variables[name] = value
+	}
+	return variables, nil
+}
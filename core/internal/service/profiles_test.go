@@ -0,0 +1,58 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRenderPromptWithProfile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "launch", Name: "Launch", Content: "Write a launch post for {{ brand }} in a {{tone}} tone. Mention {{channels}}. Sign as {{author}}."}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	profile := "brand: Acme Corp\ntone: formal\nchannels: [blog, newsletter]\n"
+	if err := os.MkdirAll(filepath.Join(tmpDir, "profiles"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "profiles", "client-acme.yaml"), []byte(profile), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	names, err := svc.ListProfiles()
+	if err != nil || strings.Join(names, ",") != "client-acme" {
+		t.Fatalf("ListProfiles = %v, %v", names, err)
+	}
+
+	rendered, err := svc.RenderPrompt("launch", RenderOptions{
+		Profile:   "client-acme",
+		Variables: map[string]interface{}{"tone": "playful"},
+	})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	want := "Write a launch post for Acme Corp in a playful tone. Mention blog, newsletter. Sign as {{author}}."
+	if rendered != want {
+		t.Errorf("rendered = %q, want %q", rendered, want)
+	}
+
+	if _, err := svc.RenderPrompt("launch", RenderOptions{Profile: "missing"}); err == nil {
+		t.Error("expected an unknown profile to fail")
+	}
+	if _, err := svc.RenderPrompt("launch", RenderOptions{Profile: "../prompts/launch"}); err == nil {
+		t.Error("expected a profile name with a path to be rejected")
+	}
+}
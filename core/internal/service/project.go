@@ -0,0 +1,220 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// ProjectDirName is the directory holding a project-local library at the
+// root of a project, next to .git
+const ProjectDirName = ".pocket-prompt"
+
+// FindProjectLibrary walks up from dir to the filesystem root looking for a
+// project library: a .pocket-prompt directory with a prompts/ folder inside.
+// It returns "" when there is none.
+func FindProjectLibrary(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, ProjectDirName)
+		if info, err := os.Stat(filepath.Join(candidate, "prompts")); err == nil && info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// InitProjectLibrary creates a project library in dir and returns its path
+func InitProjectLibrary(dir string) (string, error) {
+	projectDir := filepath.Join(dir, ProjectDirName)
+	lib, err := openLibrary(projectDir)
+	if err != nil {
+		return "", err
+	}
+	if err := lib.InitLibrary(); err != nil {
+		return "", err
+	}
+	// Keep the cache and usage counts out of the project's commits, but not the settings
+	ignore := filepath.Join(projectDir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		if err := os.WriteFile(ignore, []byte(".pocket-prompt/*\n!.pocket-prompt/config.json\n"), 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", ignore, err)
+		}
+	}
+	return projectDir, nil
+}
+
+// withProjectLibrary applies the project library found at dir according to
+// the library's project mode: merged into s, used instead of s, or ignored
+func (s *Service) withProjectLibrary(dir string) (*Service, error) {
+	if dir == "" || sameDir(dir, s.GetBaseDir()) {
+		return s, nil
+	}
+
+	mode := s.settings.Project.LibraryMode()
+	switch mode {
+	case config.ProjectOff:
+		return s, nil
+	case config.ProjectOverride:
+		project, err := openLibrary(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open project library %s: %w", dir, err)
+		}
+		project.projectDir, project.projectMode = dir, mode
+		return project, nil
+	default:
+		project, err := openLibrary(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open project library %s: %w", dir, err)
+		}
+		s.project = project
+		s.projectDir, s.projectMode = dir, mode
+		return s, nil
+	}
+}
+
+// ProjectLibrary returns the directory of the project library in use and
+// how it is used (merge or override), or empty strings when there is none
+func (s *Service) ProjectLibrary() (dir, mode string) {
+	return s.projectDir, s.projectMode
+}
+
+// InProjectLibrary reports whether the prompt with this ID is stored in the
+// merged project library rather than in this one
+func (s *Service) InProjectLibrary(id string) bool {
+	return s.libraryFor(id) != s
+}
+
+// libraryFor returns the merged project library when it holds the prompt
+// with this ID, and s otherwise
+func (s *Service) libraryFor(id string) *Service {
+	if s.project == nil {
+		return s
+	}
+	prompts, err := s.project.activePrompts()
+	if err != nil {
+		return s
+	}
+	for _, p := range prompts {
+		if p.ID == id {
+			return s.project
+		}
+	}
+	return s
+}
+
+// templateLibraryFor returns the merged project library when it holds the
+// template with this ID, and s otherwise
+func (s *Service) templateLibraryFor(id string) *Service {
+	if s.project == nil {
+		return s
+	}
+	if _, err := s.project.GetTemplate(id); err == nil {
+		return s.project
+	}
+	return s
+}
+
+// libraryPrompts lists the prompts in storage, with the merged project
+// library's prompts first and replacing any of this library's with the same ID
+func (s *Service) libraryPrompts() ([]*models.Prompt, error) {
+	prompts, err := s.storage.ListPrompts()
+	if err != nil || s.project == nil {
+		return prompts, err
+	}
+	projectPrompts, err := s.project.storage.ListPrompts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project prompts: %w", err)
+	}
+
+	merged := append([]*models.Prompt(nil), projectPrompts...)
+	inProject := make(map[string]bool, len(projectPrompts))
+	for _, p := range projectPrompts {
+		inProject[p.ID] = true
+	}
+	for _, p := range prompts {
+		if !inProject[p.ID] {
+			merged = append(merged, p)
+		}
+	}
+	return merged, nil
+}
+
+// libraryTemplates lists the templates in storage merged with the project
+// library's, as libraryPrompts does for prompts
+func (s *Service) libraryTemplates() ([]*models.Template, error) {
+	templates, err := s.storage.ListTemplates()
+	if err != nil || s.project == nil {
+		return templates, err
+	}
+	projectTemplates, err := s.project.storage.ListTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project templates: %w", err)
+	}
+
+	merged := append([]*models.Template(nil), projectTemplates...)
+	inProject := make(map[string]bool, len(projectTemplates))
+	for _, t := range projectTemplates {
+		inProject[t.ID] = true
+	}
+	for _, t := range templates {
+		if !inProject[t.ID] {
+			merged = append(merged, t)
+		}
+	}
+	return merged, nil
+}
+
+// loadPromptContent loads a listed prompt's file from whichever library
+// holds it
+func (s *Service) loadPromptContent(p *models.Prompt) (*models.Prompt, error) {
+	return s.libraryFor(p.ID).storage.LoadPrompt(p.FilePath)
+}
+
+// WithContent returns prompts with the content the metadata cache leaves
+// out loaded from their files
+func (s *Service) WithContent(prompts []*models.Prompt) ([]*models.Prompt, error) {
+	full := make([]*models.Prompt, len(prompts))
+	for i, p := range prompts {
+		if p.Content == "" && p.FilePath != "" {
+			loaded, err := s.loadPromptContent(p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load prompt %s: %w", p.ID, err)
+			}
+			p = loaded
+		}
+		full[i] = p
+	}
+	return full, nil
+}
+
+// CreateProjectPrompt creates a prompt in the merged project library
+func (s *Service) CreateProjectPrompt(prompt *models.Prompt) error {
+	if s.project == nil {
+		return fmt.Errorf("no project library is merged; create %s in a project with 'pkt project init'", ProjectDirName)
+	}
+	if err := s.project.CreatePrompt(prompt); err != nil {
+		return err
+	}
+	return s.loadPrompts()
+}
+
+// sameDir reports whether a and b name the same directory
+func sameDir(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return os.SameFile(infoA, infoB)
+}
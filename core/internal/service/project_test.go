@@ -0,0 +1,88 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestProjectLibrary(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	global, err := OpenLibrary(filepath.Join(tmpDir, "global"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, id := range []string{"personal", "review"} {
+		if err := global.CreatePrompt(&models.Prompt{ID: id, Name: "Global " + id, Version: "1.0.0", Content: "Global"}); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	repo := filepath.Join(tmpDir, "repo")
+	projectDir, err := InitProjectLibrary(repo)
+	if err != nil {
+		t.Fatalf("InitProjectLibrary: %v", err)
+	}
+	nested := filepath.Join(repo, "cmd", "tool")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if found := FindProjectLibrary(nested); found != projectDir {
+		t.Fatalf("FindProjectLibrary(%s) = %q, want %q", nested, found, projectDir)
+	}
+
+	svc, err := global.withProjectLibrary(projectDir)
+	if err != nil {
+		t.Fatalf("withProjectLibrary: %v", err)
+	}
+	if err := svc.CreateProjectPrompt(&models.Prompt{ID: "review", Name: "Project review", Version: "1.0.0", Content: "Project"}); err != nil {
+		t.Fatalf("CreateProjectPrompt: %v", err)
+	}
+
+	prompts, err := svc.ListPrompts()
+	if err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("ListPrompts returned %d prompts, want 2", len(prompts))
+	}
+	review, err := svc.GetPrompt("review")
+	if err != nil || review.Content != "Project" {
+		t.Fatalf("GetPrompt(review) = %+v, %v; want the project's prompt", review, err)
+	}
+
+	// Edits go back to the library the prompt came from
+	review.Content = "Project, edited"
+	if err := svc.UpdatePrompt(review); err != nil {
+		t.Fatalf("UpdatePrompt: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, "prompts", "review.md")); err != nil {
+		t.Errorf("edited project prompt not saved in the project: %v", err)
+	}
+	if p, _ := global.storage.LoadPrompt(filepath.Join("prompts", "review.md")); p == nil || p.Content != "Global" {
+		t.Errorf("editing the project prompt changed the global one: %+v", p)
+	}
+
+	// Off ignores the project; override uses it alone
+	off, _ := OpenLibrary(filepath.Join(tmpDir, "global"))
+	off.settings.Project.Mode = config.ProjectOff
+	if off, _ = off.withProjectLibrary(projectDir); off.project != nil {
+		t.Error("project library merged with project.mode off")
+	}
+	override, _ := OpenLibrary(filepath.Join(tmpDir, "global"))
+	override.settings.Project.Mode = config.ProjectOverride
+	if override, err = override.withProjectLibrary(projectDir); err != nil {
+		t.Fatalf("withProjectLibrary: %v", err)
+	}
+	if prompts, _ := override.ListPrompts(); len(prompts) != 1 || prompts[0].ID != "review" {
+		t.Errorf("override listed %d prompts, want only the project's review", len(prompts))
+	}
+}
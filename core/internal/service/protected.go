@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// ProtectedError is returned when a delete, edit or bulk change would touch
+// a protected prompt
+type ProtectedError struct {
+	ID string
+}
+
+func (e *ProtectedError) Error() string {
+	return fmt.Sprintf("%s is protected (use --force-protected to change it anyway)", e.ID)
+}
+
+// IsProtected reports whether a prompt is protected by its frontmatter or by
+// the storage.protected list in the config
+func (s *Service) IsProtected(prompt *models.Prompt) bool {
+	return prompt.Protected || s.settings.Storage.IsProtected(prompt.ID)
+}
+
+// ListProtected returns the protected prompts, sorted by ID
+func (s *Service) ListProtected() ([]*models.Prompt, error) {
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+	var protected []*models.Prompt
+	for _, p := range prompts {
+		if s.IsProtected(p) {
+			protected = append(protected, p)
+		}
+	}
+	sort.Slice(protected, func(i, j int) bool { return protected[i].ID < protected[j].ID })
+	return protected, nil
+}
+
+// SetForceProtected lets deletes, edits and bulk changes touch protected
+// prompts, for a command run with --force-protected
+func (s *Service) SetForceProtected(force bool) {
+	s.forceProtected = force
+	if s.project != nil {
+		s.project.SetForceProtected(force)
+	}
+}
+
+// CheckProtected returns a ProtectedError for a protected prompt unless
+// protection is being overridden, so interfaces can refuse before asking for
+// confirmation
+func (s *Service) CheckProtected(prompt *models.Prompt) error {
+	if s.forceProtected || !s.IsProtected(prompt) {
+		return nil
+	}
+	return &ProtectedError{ID: prompt.ID}
+}
+
+// ProtectPrompt sets or clears the protected flag in a prompt's frontmatter,
+// saving it as the next version. Prompts protected by the config cannot be
+// unprotected here.
+func (s *Service) ProtectPrompt(id string, protected bool) (*models.Prompt, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	if !protected && s.settings.Storage.IsProtected(id) {
+		return nil, fmt.Errorf("%s is protected by storage.protected in the config", id)
+	}
+	if prompt.Protected == protected {
+		return prompt, nil
+	}
+
+	updated := *prompt
+	updated.Protected = protected
+	if err := s.updatePrompt(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
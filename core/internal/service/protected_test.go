@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestProtectedPrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	svc.settings.Storage.Protected = []string{"prod-*"}
+	for _, id := range []string{"checkout", "prod-welcome", "draft"} {
+		if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: id, Version: "1.0.0", Content: "Hello"}); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	if _, err := svc.ProtectPrompt("checkout", true); err != nil {
+		t.Fatalf("ProtectPrompt: %v", err)
+	}
+
+	var protectedErr *ProtectedError
+	for _, id := range []string{"checkout", "prod-welcome"} {
+		if err := svc.DeletePrompt(id); !errors.As(err, &protectedErr) {
+			t.Errorf("deleting %s: err = %v, want a ProtectedError", id, err)
+		}
+		prompt, _ := svc.GetPrompt(id)
+		updated := *prompt
+		updated.Content = "Changed"
+		if err := svc.UpdatePrompt(&updated); !errors.As(err, &protectedErr) {
+			t.Errorf("updating %s: err = %v, want a ProtectedError", id, err)
+		}
+	}
+
+	prompts, _ := svc.ListPrompts()
+	report, err := svc.RetagPrompts(prompts, []string{"reviewed"}, nil, false)
+	if err != nil {
+		t.Fatalf("RetagPrompts: %v", err)
+	}
+	if len(report.Changed) != 1 || report.Changed[0].ID != "draft" || len(report.Skipped) != 2 {
+		t.Errorf("retag report = %+v, want only draft changed and two prompts skipped", report)
+	}
+
+	if _, err := svc.ProtectPrompt("prod-welcome", false); err == nil {
+		t.Error("unprotecting a prompt protected by the config succeeded")
+	}
+	protected, _ := svc.ListProtected()
+	if len(protected) != 2 {
+		t.Errorf("ListProtected returned %d prompts, want 2", len(protected))
+	}
+
+	svc.SetForceProtected(true)
+	if err := svc.DeletePrompt("prod-welcome"); err != nil {
+		t.Errorf("deleting with protection overridden: %v", err)
+	}
+	svc.SetForceProtected(false)
+
+	if _, err := svc.ProtectPrompt("checkout", false); err != nil {
+		t.Fatalf("ProtectPrompt: %v", err)
+	}
+	if err := svc.DeletePrompt("checkout"); err != nil {
+		t.Errorf("deleting an unprotected prompt: %v", err)
+	}
+}
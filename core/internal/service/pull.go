@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/git"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/notify"
+)
+
+// PromptEvent is a prompt that a git pull added, modified or removed
+type PromptEvent struct {
+	Kind string // ChangeAdded, ChangeModified or ChangeRemoved
+	ID   string
+	Path string // Library-relative path of the prompt file
+}
+
+// OnPromptChanges registers fn to be called with the prompts each git pull
+// changed. It is not called for pulls that change no prompts.
+func (s *Service) OnPromptChanges(fn func([]PromptEvent)) {
+	s.changeListeners = append(s.changeListeners, fn)
+}
+
+// StartBackgroundSync pulls from the remote every interval until ctx is
+// done, updating the prompt cache with what each pull changed. With
+// git.notify set, pulled prompts and failures also show as desktop
+// notifications.
+func (s *Service) StartBackgroundSync(ctx context.Context, interval time.Duration) {
+	if !s.gitSync.IsEnabled() {
+		return
+	}
+	var notifier *syncNotifier
+	if s.settings.Git.Notify {
+		notifier = &syncNotifier{send: notify.Send}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Timeouts are routine on flaky networks; anything else is worth a line
+			changes, err := s.pullGitChanges()
+			if err != nil && strings.Contains(err.Error(), "timeout") {
+				continue
+			}
+			if err != nil {
+				log.Printf("Background sync warning: %v", err)
+			}
+			if notifier != nil {
+				notifier.result(changes, err)
+			}
+		}
+	}
+}
+
+// PullPromptChanges pulls from the remote once and returns the prompts the
+// pull changed, for callers that schedule their own pulls like the TUI.
+// Without git sync it pulls nothing.
+func (s *Service) PullPromptChanges() ([]PromptEvent, error) {
+	if !s.gitSync.IsEnabled() {
+		return nil, nil
+	}
+	return s.pullGitChanges()
+}
+
+// applyPull brings the prompt cache up to date after a pull moved HEAD from
+// commit before, re-reading only the prompt files the pull changed, and
+// returns what changed. Without a starting commit, or when the changes
+// cannot be listed, everything is reloaded and no changes are reported.
+func (s *Service) applyPull(before string) ([]PromptEvent, error) {
+	after := s.gitSync.HeadCommit()
+	if after == before {
+		return nil, nil
+	}
+	if before == "" || len(s.cachedPrompts()) == 0 {
+		return nil, s.loadPrompts()
+	}
+	files, err := s.gitSync.ChangedFiles(before)
+	if err != nil {
+		log.Printf("Warning: reloading all prompts: %v", err)
+		return nil, s.loadPrompts()
+	}
+
+	changes := s.updateCachedPrompts(files)
+	if len(changes) > 0 {
+		for _, fn := range s.changeListeners {
+			fn(changes)
+		}
+	}
+	return changes, nil
+}
+
+// updateCachedPrompts re-reads the prompt files among files into the cache
+// and drops the deleted ones, returning what changed
+func (s *Service) updateCachedPrompts(files []git.FileChange) []PromptEvent {
+	cached := s.cachedPrompts()
+	prompts := make([]*models.Prompt, len(cached))
+	copy(prompts, cached)
+	index := make(map[string]int, len(prompts))
+	for i, p := range prompts {
+		index[p.FilePath] = i
+	}
+
+	var changes []PromptEvent
+	remove := func(path string) {
+		i, ok := index[path]
+		if !ok {
+			return
+		}
+		changes = append(changes, PromptEvent{Kind: ChangeRemoved, ID: prompts[i].ID, Path: path})
+		prompts[i] = nil
+		delete(index, path)
+	}
+
+	for _, file := range files {
+		path := filepath.FromSlash(file.Path)
+		if file.OldPath != "" {
+			// A rename out of the library is a deletion; into it, an addition
+			remove(filepath.FromSlash(file.OldPath))
+		}
+		if file.Status == "D" {
+			remove(path)
+			continue
+		}
+		if !s.storage.IsPromptFile(path) || !s.storage.InCheckout(path) {
+			continue
+		}
+
+		prompt, err := s.storage.LoadPrompt(path)
+		if err != nil {
+			// Listing skips files that fail to parse, so the cache does too
+			log.Printf("Warning: failed to load pulled prompt %s: %v", path, err)
+			remove(path)
+			continue
+		}
+		if i, ok := index[path]; ok {
+			prompts[i] = prompt
+			changes = append(changes, PromptEvent{Kind: ChangeModified, ID: prompt.ID, Path: path})
+			continue
+		}
+		index[path] = len(prompts)
+		prompts = append(prompts, prompt)
+		changes = append(changes, PromptEvent{Kind: ChangeAdded, ID: prompt.ID, Path: path})
+	}
+
+	kept := prompts[:0]
+	for _, p := range prompts {
+		if p != nil {
+			kept = append(kept, p)
+		}
+	}
+	s.setPrompts(kept)
+	return changes
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/git"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestUpdateCachedPromptsAfterPull(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "kept", Name: "Kept", Content: "unchanged"},
+		{ID: "edited", Name: "Edited", Content: "before"},
+		{ID: "deleted", Name: "Deleted", Content: "gone soon"},
+		{ID: "renamed", Name: "Renamed", Content: "moves"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	if err := svc.loadPrompts(); err != nil {
+		t.Fatalf("loadPrompts: %v", err)
+	}
+	kept := svc.findCached("kept")
+
+	// What a pull from another clone would leave on disk
+	write := func(path, id, content string) {
+		data := "---\nid: " + id + "\ntitle: " + id + "\n---\n" + content + "\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("prompts/edited.md", "edited", "after")
+	write("prompts/added.md", "added", "new")
+	os.Remove(filepath.Join(tmpDir, "prompts/deleted.md"))
+	os.Rename(filepath.Join(tmpDir, "prompts/renamed.md"), filepath.Join(tmpDir, "prompts/moved.md"))
+	write("README.md", "ignored", "not a prompt")
+
+	events := svc.updateCachedPrompts([]git.FileChange{
+		{Status: "M", Path: "prompts/edited.md"},
+		{Status: "A", Path: "prompts/added.md"},
+		{Status: "D", Path: "prompts/deleted.md"},
+		{Status: "R", Path: "prompts/moved.md", OldPath: "prompts/renamed.md"},
+		{Status: "A", Path: "README.md"},
+	})
+
+	got := map[string]string{}
+	for _, event := range events {
+		got[event.Path] = event.Kind + " " + event.ID
+	}
+	want := map[string]string{
+		"prompts/edited.md":  "modified edited",
+		"prompts/added.md":   "added added",
+		"prompts/deleted.md": "removed deleted",
+		"prompts/renamed.md": "removed renamed",
+		"prompts/moved.md":   "added renamed",
+	}
+	if len(got) != len(want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	for path, kind := range want {
+		if got[path] != kind {
+			t.Errorf("event for %s = %q, want %q", path, got[path], kind)
+		}
+	}
+
+	if len(svc.prompts) != 4 {
+		t.Errorf("Expected 4 cached prompts, got %d", len(svc.prompts))
+	}
+	if p := svc.findCached("edited"); p == nil || p.Content != "after" {
+		t.Errorf("Expected the edited prompt re-read, got %+v", p)
+	}
+	if svc.findCached("deleted") != nil {
+		t.Error("Expected the deleted prompt dropped from the cache")
+	}
+	if p := svc.findCached("renamed"); p == nil || p.FilePath != filepath.FromSlash("prompts/moved.md") {
+		t.Errorf("Expected the renamed prompt at its new path, got %+v", p)
+	}
+	if svc.findCached("kept") != kept {
+		t.Error("Expected the untouched prompt to be left as it was")
+	}
+}
+
+func TestPullAfterBothDevicesRecordedUsage(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+	}
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return string(out)
+	}
+
+	// A shared library that committed a TUI session before it was kept local
+	seed := filepath.Join(tmpDir, "seed")
+	for path, content := range map[string]string{
+		"prompts/review.md":               "---\nid: review\ntitle: Review\n---\nReview this\n",
+		"prompts/review.outcomes.jsonl":   `{"outcome":"good"}` + "\n",
+		".pocket-prompt/tui-session.json": "{}",
+	} {
+		os.MkdirAll(filepath.Join(seed, filepath.Dir(path)), 0755)
+		if err := os.WriteFile(filepath.Join(seed, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(tmpDir, "init", "-q", "--bare", "remote.git")
+	git(seed, "init", "-q")
+	git(seed, "add", "-A")
+	git(seed, "commit", "-q", "-m", "Seed")
+	git(seed, "push", "-q", filepath.Join(tmpDir, "remote.git"), "HEAD:refs/heads/master")
+
+	// Each device copies the prompt, logs an outcome and syncs
+	var svc *Service
+	for _, device := range []string{"laptop", "desktop"} {
+		dir := filepath.Join(tmpDir, device)
+		git(tmpDir, "clone", "-q", "remote.git", device)
+		t.Setenv("POCKET_PROMPT_DEVICE", device)
+		if svc, err = OpenLibrary(dir); err != nil {
+			t.Fatalf("Failed to open library: %v", err)
+		}
+		svc.gitSync.Initialize()
+		if err := svc.usage.Record("review"); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		f, err := os.OpenFile(filepath.Join(dir, "prompts", "review.outcomes.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(`{"outcome":"bad","note":"` + device + `"}` + "\n")
+		f.Close()
+		os.WriteFile(filepath.Join(dir, ".pocket-prompt", "tui-session.json"), []byte(device), 0644)
+		svc.gitSync.SyncCommit("Use review on " + device) // The desktop cannot push yet
+	}
+
+	if err := svc.PullGitChanges(); err != nil {
+		t.Fatalf("PullGitChanges on the second device: %v", err)
+	}
+	usage, err := svc.usage.Load()
+	if err != nil {
+		t.Fatalf("Load usage: %v", err)
+	}
+	if usage["review"].Count != 2 {
+		t.Errorf("review used %d times, want 2 across both devices", usage["review"].Count)
+	}
+	outcomes, _ := os.ReadFile(filepath.Join(tmpDir, "desktop", "prompts", "review.outcomes.jsonl"))
+	for _, device := range []string{"laptop", "desktop"} {
+		if !strings.Contains(string(outcomes), device) {
+			t.Errorf("outcome logged on %s lost in the pull:\n%s", device, outcomes)
+		}
+	}
+	if tracked := git(filepath.Join(tmpDir, "desktop"), "ls-files", ".pocket-prompt/tui-session.json"); tracked != "" {
+		t.Error("TUI session is still committed")
+	}
+}
+
+// findCached returns the cached prompt with id, or nil
+func (s *Service) findCached(id string) *models.Prompt {
+	for _, p := range s.prompts {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// largestFilesListed is how many of the largest files a quota report lists
+const largestFilesListed = 10
+
+// personalPack names the library's own prompts among the packs
+const personalPack = "personal"
+
+// QuotaReport measures the library against its soft limits and lists what
+// takes up the most room
+type QuotaReport struct {
+	TotalBytes        int64              `json:"total_bytes"`
+	MaxTotalBytes     int64              `json:"max_total_bytes,omitempty"`
+	MaxPromptsPerPack int                `json:"max_prompts_per_pack,omitempty"`
+	Packs             []PackUsage        `json:"packs"`         // Most prompts first
+	LargestFiles      []storage.FileSize `json:"largest_files"` // Largest first
+	Warnings          []string           `json:"warnings"`      // Limits reached or close to it
+}
+
+// PackUsage is how much of the library the personal prompts or one pack take
+type PackUsage struct {
+	Name    string `json:"name"`
+	Prompts int    `json:"prompts"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// QuotaReport totals the library's files and counts the prompts in each
+// pack, warning about every limit that is over the configured percentage
+func (s *Service) QuotaReport() (*QuotaReport, error) {
+	quota := s.settings.Quota
+	maxBytes, err := quota.TotalSizeLimit()
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.storage.LibraryFiles()
+	if err != nil {
+		return nil, err
+	}
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &QuotaReport{MaxTotalBytes: maxBytes, MaxPromptsPerPack: quota.MaxPromptsPerPack, Warnings: []string{}}
+	packs := map[string]*PackUsage{personalPack: {Name: personalPack}}
+	pack := func(name string) *PackUsage {
+		if name == "" {
+			name = personalPack
+		}
+		if packs[name] == nil {
+			packs[name] = &PackUsage{Name: name}
+		}
+		return packs[name]
+	}
+	for _, f := range files {
+		report.TotalBytes += f.Bytes
+		pack(storage.PackFromPath(f.Path)).Bytes += f.Bytes
+	}
+	for _, p := range prompts {
+		pack(storage.PackFromPath(p.FilePath)).Prompts++
+	}
+
+	for _, usage := range packs {
+		report.Packs = append(report.Packs, *usage)
+	}
+	sort.Slice(report.Packs, func(i, j int) bool {
+		a, b := report.Packs[i], report.Packs[j]
+		if a.Prompts != b.Prompts {
+			return a.Prompts > b.Prompts
+		}
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Name < b.Name
+	})
+	report.LargestFiles = files[:min(len(files), largestFilesListed)]
+
+	warnAt := int64(quota.WarnAt())
+	if maxBytes > 0 && report.TotalBytes*100 >= maxBytes*warnAt {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("library is %s, %s its %s limit",
+			config.FormatSize(report.TotalBytes), limitState(report.TotalBytes, maxBytes), config.FormatSize(maxBytes)))
+	}
+	if limit := int64(quota.MaxPromptsPerPack); limit > 0 {
+		for _, usage := range report.Packs {
+			if count := int64(usage.Prompts); count*100 >= limit*warnAt {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%s has %s, %s its limit of %d",
+					packLabel(usage.Name), plural(usage.Prompts, "prompt"), limitState(count, limit), limit))
+			}
+		}
+	}
+	return report, nil
+}
+
+// QuotaWarnings returns the quota report's warnings, or none without any
+// limits set, when the library is not measured at all
+func (s *Service) QuotaWarnings() ([]string, error) {
+	if !s.settings.Quota.Enabled() {
+		return nil, nil
+	}
+	report, err := s.QuotaReport()
+	if err != nil {
+		return nil, err
+	}
+	return report.Warnings, nil
+}
+
+// limitState says how a value stands against its limit
+func limitState(value, limit int64) string {
+	switch {
+	case value > limit:
+		return "over"
+	case value == limit:
+		return "at"
+	default:
+		return "close to"
+	}
+}
+
+// packLabel names a pack in a warning
+func packLabel(name string) string {
+	if name == personalPack {
+		return "the personal library"
+	}
+	return "pack " + name
+}
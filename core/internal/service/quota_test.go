@@ -0,0 +1,76 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestQuotaReport(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, id := range []string{"one", "two", "three"} {
+		if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: id, Content: "text"}); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "assets", "diagram.png"), make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if warnings, err := svc.QuotaWarnings(); err != nil || warnings != nil {
+		t.Fatalf("QuotaWarnings without limits = %v, %v; want none", warnings, err)
+	}
+
+	svc.Settings().Quota.MaxPromptsPerPack = 3
+	svc.Settings().Quota.MaxTotalSize = "4KB"
+	report, err := svc.QuotaReport()
+	if err != nil {
+		t.Fatalf("QuotaReport: %v", err)
+	}
+	if report.TotalBytes <= 4096 || report.MaxTotalBytes != 4096 {
+		t.Errorf("total = %d of %d, want over 4096 of 4096", report.TotalBytes, report.MaxTotalBytes)
+	}
+	if len(report.Packs) != 1 || report.Packs[0].Name != "personal" || report.Packs[0].Prompts != 3 {
+		t.Errorf("packs = %+v, want 3 personal prompts", report.Packs)
+	}
+	if len(report.LargestFiles) == 0 || report.LargestFiles[0].Path != "assets/diagram.png" {
+		t.Errorf("largest files = %+v, want the diagram first", report.LargestFiles)
+	}
+	for _, f := range report.LargestFiles {
+		if strings.HasPrefix(f.Path, ".pocket-prompt/") {
+			t.Errorf("largest files include %s", f.Path)
+		}
+	}
+
+	want := []string{
+		"library is " + config.FormatSize(report.TotalBytes) + ", over its 4.0 KiB limit",
+		"the personal library has 3 prompts, at its limit of 3",
+	}
+	if strings.Join(report.Warnings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings = %q, want %q", report.Warnings, want)
+	}
+
+	svc.Settings().Quota.MaxPromptsPerPack = 4
+	svc.Settings().Quota.WarnPercent = 75
+	svc.Settings().Quota.MaxTotalSize = "1MB"
+	warnings, err := svc.QuotaWarnings()
+	if err != nil || len(warnings) != 1 || warnings[0] != "the personal library has 3 prompts, close to its limit of 4" {
+		t.Errorf("QuotaWarnings = %q, %v; want the pack close to its limit", warnings, err)
+	}
+}
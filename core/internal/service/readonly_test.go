@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestNewReadOnlyService(t *testing.T) {
+	tmpDir := t.TempDir()
+	writable, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := writable.InitLibrary(); err != nil {
+		t.Fatalf("InitLibrary: %v", err)
+	}
+	if err := writable.CreatePrompt(&models.Prompt{ID: "notes", Name: "Notes", Content: "text"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	// Keep any project library above the working directory out of the test
+	wd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("POCKET_PROMPT_DIR", tmpDir)
+
+	svc, err := NewReadOnlyService()
+	if err != nil {
+		t.Fatalf("NewReadOnlyService: %v", err)
+	}
+	if !svc.ReadOnly() {
+		t.Fatal("library is writable")
+	}
+	if prompts, err := svc.ListPrompts(); err != nil || len(prompts) != 1 {
+		t.Fatalf("ListPrompts = %d prompts, %v; want the library's prompt", len(prompts), err)
+	}
+
+	if err := svc.CreatePrompt(&models.Prompt{ID: "brief", Name: "Brief", Content: "draft"}); !errors.Is(err, storage.ErrReadOnly) {
+		t.Errorf("CreatePrompt error = %v, want ErrReadOnly", err)
+	}
+	if err := svc.InitLibrary(); !errors.Is(err, storage.ErrReadOnly) {
+		t.Errorf("InitLibrary error = %v, want ErrReadOnly", err)
+	}
+	if _, err := svc.RunMaintenance(false); !errors.Is(err, storage.ErrReadOnly) {
+		t.Errorf("RunMaintenance error = %v, want ErrReadOnly", err)
+	}
+	if _, err := svc.RunMaintenance(true); err != nil {
+		t.Errorf("dry-run RunMaintenance: %v", err)
+	}
+	cfg := config.DigestConfig{Schedule: "@daily", Webhook: "http://127.0.0.1:0"}
+	if _, _, err := svc.RunDigest(context.Background(), cfg, time.Now()); !errors.Is(err, storage.ErrReadOnly) {
+		t.Errorf("RunDigest error = %v, want ErrReadOnly", err)
+	}
+	if err := svc.SyncChanges("edit"); !errors.Is(err, storage.ErrReadOnly) {
+		t.Errorf("SyncChanges error = %v, want ErrReadOnly", err)
+	}
+
+	svc.Settings().UI.Locale = "de"
+	if err := svc.Settings().Save(); !errors.Is(err, config.ErrReadOnly) {
+		t.Errorf("settings Save error = %v, want config.ErrReadOnly", err)
+	}
+	if _, err := os.Stat(svc.Settings().Path()); !os.IsNotExist(err) {
+		t.Errorf("settings were written to %s", svc.Settings().Path())
+	}
+}
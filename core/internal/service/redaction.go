@@ -0,0 +1,10 @@
+package service
+
+import (
+	"github.com/dpshade/pocket-prompt/internal/redact"
+)
+
+// Redactor builds a redactor from the library's redaction rules
+func (s *Service) Redactor() (*redact.Redactor, error) {
+	return redact.New(s.settings.Redaction)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// archivedVersions returns the library's archived versions of id by version
+func archivedVersions(t *testing.T, svc *Service, id string) map[string]*models.Prompt {
+	t.Helper()
+	archived, err := svc.ListArchivedPrompts()
+	if err != nil {
+		t.Fatalf("ListArchivedPrompts: %v", err)
+	}
+	versions := make(map[string]*models.Prompt)
+	for _, p := range archived {
+		if p.ID == id {
+			versions[p.Version] = p
+		}
+	}
+	return versions
+}
+
+func TestImportPromptLayerExport(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	result, err := svc.ImportFromRegistry(importer.RegistryImportOptions{
+		Registry: importer.RegistryPromptLayer,
+		File:     "testdata/promptlayer-export.json",
+	})
+	if err != nil || len(result.Errors) > 0 {
+		t.Fatalf("ImportFromRegistry = %v, errors %v", err, result.Errors)
+	}
+	if len(result.Prompts) != 2 || len(result.Archived) != 1 {
+		t.Fatalf("imported %d prompts and %d archived versions, want 2 and 1", len(result.Prompts), len(result.Archived))
+	}
+
+	// The newest version is current, with f-string placeholders converted
+	review, err := svc.GetPrompt("code-review")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	wantContent := "## System\n\nYou review {{language}} code. Keep {braces} as they are.\n\n## User\n\n{{code}}"
+	if review.Version != "2.0.0" || review.Name != "Code Review" || review.Content != wantContent {
+		t.Errorf("current version %s %q:\n%s", review.Version, review.Name, review.Content)
+	}
+	if strings.Join(review.Tags, ",") != "promptlayer,engineering" {
+		t.Errorf("tags = %v, want the registry and the export's tags", review.Tags)
+	}
+	if review.Summary != "Split into system and user messages" || fmt.Sprint(review.Metadata["registry_version"]) != "2" {
+		t.Errorf("summary %q, metadata %v", review.Summary, review.Metadata)
+	}
+	if config, ok := review.Metadata["model_config"].(map[string]interface{}); !ok || config["name"] != "gpt-4o" {
+		t.Errorf("model_config = %v, want the version's model", review.Metadata["model_config"])
+	}
+
+	// Older versions are archived beside it, out of listings
+	archived := archivedVersions(t, svc, "code-review")
+	first := archived["1.0.0"]
+	if len(archived) != 1 || first == nil {
+		t.Fatalf("archived versions = %v, want 1.0.0", archived)
+	}
+	if first.Content != "Review this {{language}} code:\n\n{{code}}" || first.Summary != "First draft" {
+		t.Errorf("archived version %q:\n%s", first.Summary, first.Content)
+	}
+	if first.FilePath != "archive/code-review-v1.0.0.md" || strings.Join(first.Tags, ",") != "promptlayer,engineering,archive" {
+		t.Errorf("archived version at %s with tags %v", first.FilePath, first.Tags)
+	}
+	prompts, err := svc.ListPrompts()
+	if err != nil || len(prompts) != 2 {
+		t.Errorf("ListPrompts = %d, %v; want only the current versions", len(prompts), err)
+	}
+
+	// A flattened template keeps its version, and jinja2 is left alone
+	summarize, err := svc.GetPrompt("summarize")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if summarize.Version != "3.0.0" || summarize.Content != "Summarize {{ text }}" {
+		t.Errorf("summarize %s: %q", summarize.Version, summarize.Content)
+	}
+}
+
+func TestImportLangfuseExport(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	options := importer.RegistryImportOptions{
+		Registry: importer.RegistryLangfuse,
+		File:     "testdata/langfuse-export.json",
+		Label:    "production",
+	}
+
+	options.DryRun = true
+	if result, err := svc.ImportFromRegistry(options); err != nil || len(result.Prompts) != 1 || len(result.Archived) != 2 {
+		t.Fatalf("dry run = %v; want one prompt and two archived versions", err)
+	}
+	if _, err := svc.GetPrompt("support-reply"); err == nil {
+		t.Fatal("a dry run saved the prompt")
+	}
+
+	options.DryRun = false
+	result, err := svc.ImportFromRegistry(options)
+	if err != nil || len(result.Errors) > 0 {
+		t.Fatalf("ImportFromRegistry = %v, errors %v", err, result.Errors)
+	}
+
+	// The labelled version is current, even with a newer one in the export
+	reply, err := svc.GetPrompt("support-reply")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if reply.Version != "2.0.0" || reply.Content != "Reply kindly to {{ticket}}" {
+		t.Errorf("current version %s: %q", reply.Version, reply.Content)
+	}
+	if labels := fmt.Sprint(reply.Metadata["registry_labels"]); labels != "[production]" {
+		t.Errorf("registry_labels = %s", labels)
+	}
+
+	// Both the older and the newer version are archived
+	archived := archivedVersions(t, svc, "support-reply")
+	var versions []string
+	for version := range archived {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	if strings.Join(versions, ",") != "1.0.0,3.0.0" {
+		t.Fatalf("archived versions = %v, want 1.0.0 and 3.0.0", versions)
+	}
+	if got := archived["1.0.0"]; got.Content != "Reply to {{ticket}}" || got.Summary != "Initial version" {
+		t.Errorf("version 1 %q: %q", got.Summary, got.Content)
+	}
+	chat := archived["3.0.0"]
+	if chat.Content != "## System\n\nBe brief.\n\n## User\n\n{{ticket}}" {
+		t.Errorf("chat version content:\n%s", chat.Content)
+	}
+	if config, ok := chat.Metadata["model_config"].(map[string]interface{}); !ok || fmt.Sprint(config["temperature"]) != "0.2" {
+		t.Errorf("model_config = %v", chat.Metadata["model_config"])
+	}
+
+	// Importing again leaves the archived history as it was
+	if result, err := svc.ImportFromRegistry(options); err != nil || len(result.Errors) > 0 {
+		t.Fatalf("second import = %v, errors %v", err, result.Errors)
+	}
+	if again := archivedVersions(t, svc, "support-reply"); len(again) != 2 {
+		t.Errorf("archived versions after a second import = %d, want 2", len(again))
+	}
+}
+
+func TestImportRegistrySkipsHistoryOfSkippedPrompts(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	local := &models.Prompt{ID: "code-review", Name: "My Review", Content: "My own review prompt"}
+	if err := svc.CreatePrompt(local); err != nil {
+		t.Fatalf("CreatePrompt: %v", err)
+	}
+
+	options := importer.RegistryImportOptions{
+		Registry: importer.RegistryPromptLayer,
+		File:     "testdata/promptlayer-export.json",
+	}
+	options.SkipExisting = true
+	result, err := svc.ImportFromRegistry(options)
+	if err != nil || len(result.Errors) > 0 {
+		t.Fatalf("ImportFromRegistry = %v, errors %v", err, result.Errors)
+	}
+	if len(result.Archived) != 0 {
+		t.Errorf("archived %d versions, want none for the skipped prompt", len(result.Archived))
+	}
+	if archived := archivedVersions(t, svc, "code-review"); len(archived) != 0 {
+		t.Errorf("archived versions = %v, want the local prompt's history left alone", archived)
+	}
+	review, err := svc.GetPrompt("code-review")
+	if err != nil || review.Content != local.Content {
+		t.Errorf("code-review = %v, %v; want the local prompt kept", review, err)
+	}
+
+	// A prompt that can't be overwritten is skipped with an error, and so is its history
+	if _, err := svc.ProtectPrompt("code-review", true); err != nil {
+		t.Fatalf("ProtectPrompt: %v", err)
+	}
+	before := len(archivedVersions(t, svc, "code-review"))
+	options.SkipExisting = false
+	result, err = svc.ImportFromRegistry(options)
+	if err != nil || len(result.Errors) != 1 {
+		t.Fatalf("ImportFromRegistry = %v, errors %v; want the protected prompt reported", err, result.Errors)
+	}
+	if len(result.Archived) != 0 || len(archivedVersions(t, svc, "code-review")) != before {
+		t.Errorf("archived %d versions of a prompt that was not imported", len(result.Archived))
+	}
+}
+
+func TestImportRegistryLabelOnlyForLangfuse(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	_, err = svc.ImportFromRegistry(importer.RegistryImportOptions{
+		Registry: importer.RegistryPromptLayer,
+		File:     "testdata/promptlayer-export.json",
+		Label:    "production",
+	})
+	if err == nil || !strings.Contains(err.Error(), "labels are only supported") {
+		t.Errorf("ImportFromRegistry with a PromptLayer label = %v, want it rejected", err)
+	}
+}
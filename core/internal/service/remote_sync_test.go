@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/remote"
+)
+
+// fakeRegistry serves the REST adapter's API from memory
+type fakeRegistry struct {
+	mu      sync.Mutex
+	prompts map[string]remote.Prompt
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/prompts":
+		var list []remote.Prompt
+		for _, p := range f.prompts {
+			list = append(list, p)
+		}
+		json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/prompts/"):
+		var p remote.Prompt
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p.UpdatedAt = time.Now()
+		f.prompts[p.Name] = p
+		json.NewEncoder(w).Encode(p)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeRegistry) edit(name, content string, tags ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prompts[name] = remote.Prompt{Name: name, Content: content, Tags: tags, UpdatedAt: time.Now()}
+}
+
+func TestSyncRemotePullKeepsLocalTags(t *testing.T) {
+	registry := &fakeRegistry{prompts: make(map[string]remote.Prompt)}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	svc.Settings().Remote = config.RemoteConfig{URL: server.URL, Tag: "shared"}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Content: "Review this", Tags: []string{"shared", "mine"}}); err != nil {
+		t.Fatalf("CreatePrompt: %v", err)
+	}
+	if report, err := svc.SyncRemote(remote.DirectionBoth, false, ""); err != nil || len(report.Errors) > 0 || report.Count(remote.ActionPush) != 1 {
+		t.Fatalf("first sync = %+v, %v; want review pushed", report, err)
+	}
+
+	// A registry that drops the filter tag when the prompt is edited there
+	registry.edit("review", "Review this carefully", "edited")
+	if report, err := svc.SyncRemote(remote.DirectionBoth, false, ""); err != nil || len(report.Errors) > 0 || report.Count(remote.ActionPull) != 1 {
+		t.Fatalf("second sync = %+v, %v; want review pulled", report, err)
+	}
+	review, err := svc.GetPrompt("review")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if review.Content != "Review this carefully" {
+		t.Errorf("content = %q, want the registry's edit", review.Content)
+	}
+	for _, tag := range []string{"shared", "mine", "edited"} {
+		if !slices.Contains(review.Tags, tag) {
+			t.Errorf("tags = %v, want %s kept", review.Tags, tag)
+		}
+	}
+
+	// The pulled prompt still takes part in the sync, so nothing is pulled again
+	report, err := svc.SyncRemote(remote.DirectionBoth, false, "")
+	if err != nil || len(report.Errors) > 0 {
+		t.Fatalf("third sync = %+v, %v", report, err)
+	}
+	if report.Count(remote.ActionPull) != 0 {
+		t.Errorf("third sync pulled %d prompts, want none", report.Count(remote.ActionPull))
+	}
+	if prompts, _ := svc.ListPrompts(); len(prompts) != 1 {
+		t.Errorf("ListPrompts = %d prompts, want review alone", len(prompts))
+	}
+}
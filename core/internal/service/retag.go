@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// RetagChange is the tag change made, or to be made, to one prompt
+type RetagChange struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Version string   `json:"version,omitempty"` // The new version; empty in a dry run
+}
+
+// RetagSkip is a prompt the tag change could not be made to
+type RetagSkip struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// RetagReport summarises a bulk tag change
+type RetagReport struct {
+	DryRun    bool          `json:"dry_run"`
+	Changed   []RetagChange `json:"changed"`
+	Unchanged []string      `json:"unchanged,omitempty"` // IDs that already had the tags as asked
+	Skipped   []RetagSkip   `json:"skipped,omitempty"`
+}
+
+// RetagPrompts adds and removes tags on every prompt in prompts, such as the
+// results of a boolean search. Each changed prompt gets a new version, and
+// the whole change is synced as a single git commit. With dryRun nothing is
+// written and the report says what would change.
+//
+// Prompts from other sources are skipped, as are protected prompts and
+// prompts for which a tag to remove comes from their folder, so no prompt is
+// changed only in part.
+func (s *Service) RetagPrompts(prompts []*models.Prompt, add, remove []string, dryRun bool) (*RetagReport, error) {
+	add, remove, err := cleanRetagTags(add, remove)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun && s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+
+	report := &RetagReport{DryRun: dryRun, Changed: []RetagChange{}}
+	library, packs := false, map[string]bool{}
+	for _, p := range prompts {
+		if p.Source != "" {
+			report.Skipped = append(report.Skipped, RetagSkip{ID: p.ID, Reason: fmt.Sprintf("from source %s", p.Source)})
+			continue
+		}
+		if err := s.CheckProtected(p); err != nil {
+			report.Skipped = append(report.Skipped, RetagSkip{ID: p.ID, Reason: "protected"})
+			continue
+		}
+		if derived := slices.IndexFunc(remove, func(tag string) bool { return slices.Contains(p.DerivedTags, tag) }); derived >= 0 {
+			report.Skipped = append(report.Skipped, RetagSkip{ID: p.ID, Reason: fmt.Sprintf("tag %s comes from its folder", remove[derived])})
+			continue
+		}
+
+		change := RetagChange{ID: p.ID, Title: p.Title()}
+		tags := slices.Clone(p.Tags)
+		for _, tag := range remove {
+			if slices.Contains(tags, tag) {
+				tags = slices.DeleteFunc(tags, func(t string) bool { return t == tag })
+				change.Removed = append(change.Removed, tag)
+			}
+		}
+		for _, tag := range add {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+				change.Added = append(change.Added, tag)
+			}
+		}
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			report.Unchanged = append(report.Unchanged, p.ID)
+			continue
+		}
+
+		if !dryRun {
+			full, err := s.GetPrompt(p.ID)
+			if err != nil {
+				return report, err
+			}
+			updated := *full
+			updated.Tags = tags
+			if err := s.writePromptVersion(&updated); err != nil {
+				return report, fmt.Errorf("failed to retag %s: %w", p.ID, err)
+			}
+			change.Version = updated.Version
+			if pack := storage.PackFromPath(updated.FilePath); pack != "" {
+				packs[pack] = true
+			} else {
+				library = true
+			}
+		}
+		report.Changed = append(report.Changed, change)
+	}
+
+	if dryRun || len(report.Changed) == 0 {
+		return report, nil
+	}
+	s.syncRetag(report, library, packs, add, remove)
+	return report, s.loadPrompts()
+}
+
+// syncRetag commits a bulk tag change once to the library, if it touched
+// prompts there, and once to each pack it touched
+func (s *Service) syncRetag(report *RetagReport, library bool, packs map[string]bool, add, remove []string) {
+	var changes []string
+	for _, tag := range add {
+		changes = append(changes, "+"+tag)
+	}
+	for _, tag := range remove {
+		changes = append(changes, "-"+tag)
+	}
+	message := fmt.Sprintf("Retag %d prompts: %s", len(report.Changed), strings.Join(changes, " "))
+	s.syncBulkChange(message, "retagging prompts", library, packs)
+}
+
+// syncBulkChange commits a change to many prompts with message, once to the
+// library if it touched prompts there and once to each pack it touched.
+// Failures are reported as warnings after doing what the change did.
+func (s *Service) syncBulkChange(message, doing string, library bool, packs map[string]bool) {
+	if library && s.gitSync.IsEnabled() {
+		if err := s.gitSync.SyncChanges(message); err != nil {
+			fmt.Printf("Warning: Git sync failed after %s: %v\n", doing, err)
+		}
+	}
+	for name := range packs {
+		if pack, err := s.packConfig.GetPack(name); err == nil && pack.GitSyncEnabled && pack.HasWriteAccess {
+			if err := s.packConfig.SyncPackToGit(name, message); err != nil {
+				fmt.Printf("Warning: Pack Git sync failed after %s: %v\n", doing, err)
+			}
+		}
+	}
+}
+
+// cleanRetagTags checks the tags of a bulk change
+func cleanRetagTags(add, remove []string) ([]string, []string, error) {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, nil, fmt.Errorf("no tags to add or remove")
+	}
+	clean := func(tags []string) ([]string, error) {
+		var cleaned []string
+		for _, tag := range tags {
+			tag, err := cleanTag(tag)
+			if err != nil {
+				return nil, err
+			}
+			if !slices.Contains(cleaned, tag) {
+				cleaned = append(cleaned, tag)
+			}
+		}
+		return cleaned, nil
+	}
+	add, err := clean(add)
+	if err != nil {
+		return nil, nil, err
+	}
+	remove, err = clean(remove)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, tag := range add {
+		if slices.Contains(remove, tag) {
+			return nil, nil, fmt.Errorf("tag %s is both added and removed", tag)
+		}
+	}
+	return add, remove, nil
+}
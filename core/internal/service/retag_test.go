@@ -0,0 +1,77 @@
+package service
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRetagPrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a", Name: "A", Tags: []string{"draft"}, Content: "a"},
+		{ID: "b", Name: "B", Tags: []string{"draft", "ready"}, Content: "b"},
+		{ID: "c", Name: "C", Tags: []string{"ready"}, Content: "c"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	results, err := svc.SearchPromptsByBooleanExpression(models.NewTagExpression("draft"))
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	versions := map[string]string{}
+	for _, p := range results {
+		versions[p.ID] = p.Version
+	}
+
+	report, err := svc.RetagPrompts(results, []string{"ready"}, []string{"draft"}, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(report.Changed) != 2 || report.Changed[0].Version != "" {
+		t.Fatalf("dry run report = %+v, want 2 changes without versions", report)
+	}
+	if a, _ := svc.GetPrompt("a"); !slices.Equal(a.Tags, []string{"draft"}) {
+		t.Fatalf("dry run changed a's tags to %v", a.Tags)
+	}
+
+	report, err = svc.RetagPrompts(results, []string{"ready"}, []string{"draft"}, false)
+	if err != nil {
+		t.Fatalf("RetagPrompts: %v", err)
+	}
+	for _, change := range report.Changed {
+		p, _ := svc.GetPrompt(change.ID)
+		if !slices.Equal(p.Tags, []string{"ready"}) || p.Version == versions[p.ID] || p.Version != change.Version {
+			t.Errorf("%s has tags %v at version %s, want ready in the reported new version %s", p.ID, p.Tags, p.Version, change.Version)
+		}
+	}
+	if b := report.Changed[1]; b.ID != "b" || len(b.Added) != 0 || !slices.Equal(b.Removed, []string{"draft"}) {
+		t.Errorf("change to b = %+v, want only draft removed", b)
+	}
+
+	results, _ = svc.SearchPromptsByBooleanExpression(models.NewTagExpression("ready"))
+	report, err = svc.RetagPrompts(results, []string{"ready"}, nil, false)
+	if err != nil || len(report.Changed) != 0 || len(report.Unchanged) != 3 {
+		t.Errorf("re-adding ready = %+v, %v, want every prompt unchanged", report, err)
+	}
+
+	if _, err := svc.RetagPrompts(results, []string{"x"}, []string{"x"}, true); err == nil {
+		t.Error("adding and removing the same tag should fail")
+	}
+	if _, err := svc.RetagPrompts(results, nil, nil, true); err == nil {
+		t.Error("a retag without tags should fail")
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/git"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// ReviewResult describes what a review transition did
+type ReviewResult struct {
+	Prompt    *models.Prompt
+	Branch    string // Branch the transition was committed to, empty without git
+	Committed bool
+	Merged    bool // The review branch was merged before approving
+}
+
+// ProposePrompt submits a prompt for review. It drops out of default listings
+// until approved. In a git library the proposal is committed to its own
+// review branch, which is left checked out so it can be pushed and shared.
+func (s *Service) ProposePrompt(id, note string, commit bool) (*ReviewResult, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	useGit := commit && s.gitSync.IsInitialized()
+	result := &ReviewResult{}
+
+	if useGit {
+		result.Branch = git.ReviewBranch(id)
+		if s.gitSync.CurrentBranch() != result.Branch {
+			if err := s.gitSync.SwitchBranch(result.Branch); err != nil {
+				return nil, fmt.Errorf("failed to switch to review branch: %w", err)
+			}
+			if err := s.loadPrompts(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	prompt, err := s.reviewablePrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	prompt.Review = &models.Review{
+		State:      models.ReviewProposed,
+		ProposedBy: s.gitSync.UserName(),
+		ProposedAt: time.Now(),
+		Note:       note,
+	}
+
+	if err := s.saveReview(prompt, fmt.Sprintf("Propose prompt: %s", prompt.Title()), useGit, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ApprovePrompt accepts a proposed prompt so it appears in default listings.
+// If the proposal is on a review branch other than the current one, that
+// branch is merged first and deleted once the approval is committed.
+func (s *Service) ApprovePrompt(id, note string, commit bool) (*ReviewResult, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	useGit := commit && s.gitSync.IsInitialized()
+	result := &ReviewResult{}
+
+	if useGit {
+		result.Branch = s.gitSync.CurrentBranch()
+		branch := git.ReviewBranch(id)
+		if result.Branch != branch && s.gitSync.BranchExists(branch) {
+			if err := s.gitSync.MergeBranch(branch); err != nil {
+				return nil, fmt.Errorf("failed to merge %s: %w", branch, err)
+			}
+			result.Merged = true
+			if err := s.loadPrompts(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	prompt, err := s.proposedPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	s.markReviewed(prompt, models.ReviewApproved, note)
+
+	if err := s.saveReview(prompt, fmt.Sprintf("Approve prompt: %s", prompt.Title()), useGit, result); err != nil {
+		return nil, err
+	}
+	if result.Merged {
+		if err := s.gitSync.DeleteBranch(git.ReviewBranch(id)); err != nil {
+			return result, fmt.Errorf("approved, but failed to delete review branch: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// RejectPrompt sends a proposed prompt back to its author with a note. It
+// stays out of default listings until it is proposed again. A rejection is
+// committed to the prompt's review branch when one exists, returning to the
+// current branch afterwards.
+func (s *Service) RejectPrompt(id, note string, commit bool) (*ReviewResult, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	useGit := commit && s.gitSync.IsInitialized()
+	result := &ReviewResult{}
+
+	if useGit {
+		current := s.gitSync.CurrentBranch()
+		result.Branch = current
+		branch := git.ReviewBranch(id)
+		if current != branch && s.gitSync.BranchExists(branch) {
+			if err := s.gitSync.SwitchBranch(branch); err != nil {
+				return nil, fmt.Errorf("failed to switch to review branch: %w", err)
+			}
+			result.Branch = branch
+			defer func() {
+				s.gitSync.SwitchBranch(current)
+				s.loadPrompts()
+			}()
+			if err := s.loadPrompts(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	prompt, err := s.proposedPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	s.markReviewed(prompt, models.ReviewRejected, note)
+
+	if err := s.saveReview(prompt, fmt.Sprintf("Reject prompt: %s", prompt.Title()), useGit, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReviewQueue returns the prompts that are not approved: proposals awaiting
+// review first, then rejected prompts, oldest first within each
+func (s *Service) ReviewQueue() ([]*models.Prompt, error) {
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	var queue []*models.Prompt
+	for _, prompt := range prompts {
+		if !prompt.IsApproved() {
+			queue = append(queue, prompt)
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, b := queue[i], queue[j]
+		if a.ReviewState() != b.ReviewState() {
+			return a.ReviewState() == models.ReviewProposed
+		}
+		return a.Review.ProposedAt.Before(b.Review.ProposedAt)
+	})
+	return queue, nil
+}
+
+// UnmergedReviewBranches lists review branches holding proposals that have
+// not been merged into the current branch
+func (s *Service) UnmergedReviewBranches() []string {
+	if !s.gitSync.IsInitialized() {
+		return nil
+	}
+	return s.gitSync.UnmergedReviewBranches()
+}
+
+// reviewablePrompt loads a prompt from the library itself; pack prompts are
+// reviewed in their pack's own repository
+func (s *Service) reviewablePrompt(id string) (*models.Prompt, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	if storage.PackFromPath(prompt.FilePath) != "" || s.isArchived(prompt) {
+		return nil, fmt.Errorf("prompt %s is not part of this library and cannot be reviewed here", id)
+	}
+	return prompt, nil
+}
+
+func (s *Service) proposedPrompt(id string) (*models.Prompt, error) {
+	prompt, err := s.reviewablePrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	if state := prompt.ReviewState(); state != models.ReviewProposed {
+		return nil, fmt.Errorf("prompt %s is not awaiting review (state: %s)", id, state)
+	}
+	return prompt, nil
+}
+
+func (s *Service) markReviewed(prompt *models.Prompt, state, note string) {
+	review := *prompt.Review
+	review.State = state
+	review.ReviewedBy = s.gitSync.UserName()
+	review.ReviewedAt = time.Now()
+	review.Note = note
+	prompt.Review = &review
+}
+
+// saveReview writes the prompt's review state without bumping its version,
+// since the content is unchanged, and commits just that file
+func (s *Service) saveReview(prompt *models.Prompt, message string, useGit bool, result *ReviewResult) error {
+	if err := s.storage.SavePrompt(prompt); err != nil {
+		return fmt.Errorf("failed to save review state: %w", err)
+	}
+	if err := s.loadPrompts(); err != nil {
+		return err
+	}
+	result.Prompt = prompt
+
+	if useGit {
+		committed, err := s.gitSync.CommitPaths(message, prompt.FilePath)
+		if err != nil {
+			return fmt.Errorf("review state saved but git commit failed: %w", err)
+		}
+		result.Committed = committed
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestReviewHidesPromptUntilApproved(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "draft", Name: "Draft", Content: "Hello"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	if _, err := svc.ProposePrompt("draft", "please look", false); err != nil {
+		t.Fatalf("ProposePrompt: %v", err)
+	}
+	listed, _ := svc.ListPrompts()
+	if len(listed) != 0 {
+		t.Fatalf("proposed prompt is listed: %d prompts", len(listed))
+	}
+	queue, _ := svc.ReviewQueue()
+	if len(queue) != 1 || queue[0].ReviewState() != models.ReviewProposed {
+		t.Fatalf("review queue = %d prompts, want the proposed prompt", len(queue))
+	}
+	if _, err := svc.GetPrompt("draft"); err != nil {
+		t.Fatalf("GetPrompt on proposed prompt: %v", err)
+	}
+
+	if _, err := svc.ApprovePrompt("draft", "", false); err != nil {
+		t.Fatalf("ApprovePrompt: %v", err)
+	}
+	listed, _ = svc.ListPrompts()
+	if len(listed) != 1 || !listed[0].IsApproved() {
+		t.Fatalf("approved prompt missing from listing")
+	}
+	if _, err := svc.RejectPrompt("draft", "", false); err == nil {
+		t.Fatalf("RejectPrompt accepted a prompt that is not awaiting review")
+	}
+}
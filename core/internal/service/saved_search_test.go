@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/dpshade/pocket-prompt/internal/config"
 	"github.com/dpshade/pocket-prompt/internal/models"
 )
 
@@ -129,4 +130,47 @@ func TestExecuteSavedSearchWithText(t *testing.T) {
 	if len(results) != 2 {
 		t.Errorf("Expected 2 results when using saved text query, got %d", len(results))
 	}
-}
\ No newline at end of file
+}
+
+func TestSetPinnedSearch(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.SetPinnedSearch("missing"); err == nil {
+		t.Error("Expected pinning an unknown saved search to fail")
+	}
+
+	search := models.SavedSearch{Name: "work", Expression: models.NewTagExpression("work")}
+	if err := svc.SaveBooleanSearch(search); err != nil {
+		t.Fatalf("Failed to save search: %v", err)
+	}
+	if err := svc.SetPinnedSearch("work"); err != nil {
+		t.Fatalf("SetPinnedSearch: %v", err)
+	}
+	if got := svc.PinnedSearch(); got != "work" {
+		t.Errorf("PinnedSearch() = %q, want work", got)
+	}
+
+	// The pin is kept in the library configuration
+	settings, err := config.LoadConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if settings.UI.PinnedSearch != "work" {
+		t.Errorf("Saved pinned_search = %q, want work", settings.UI.PinnedSearch)
+	}
+
+	if err := svc.SetPinnedSearch(""); err != nil {
+		t.Fatalf("Unpin: %v", err)
+	}
+	if got := svc.PinnedSearch(); got != "" {
+		t.Errorf("PinnedSearch() after unpin = %q", got)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/jsonschema"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/redact"
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// OutputSchema returns the JSON Schema a prompt's response should follow,
+// taken from its output_schema frontmatter or else its .schema.json sidecar.
+// It returns nil when the prompt declares neither.
+func (s *Service) OutputSchema(prompt *models.Prompt) (jsonschema.Schema, error) {
+	raw := prompt.OutputSchema
+	if raw == nil {
+		sidecar, err := s.storage.LoadOutputSchema(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if sidecar == nil {
+			return nil, nil
+		}
+		raw = sidecar
+	}
+
+	schema, err := jsonschema.Normalize(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output schema for %s: %w", prompt.ID, err)
+	}
+	return schema, nil
+}
+
+// ValidateOutput checks a sample model response against a prompt's output
+// schema, returning one message per violation
+func (s *Service) ValidateOutput(id string, output []byte) ([]string, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := s.OutputSchema(prompt)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("prompt %s has no output schema", id)
+	}
+	return schema.ValidateJSON(output), nil
+}
+
+// RenderOptions controls how RenderPrompt renders a prompt
+type RenderOptions struct {
+	Format    string                 // "text" (default), "json" or a plugin formatter
+	Images    string                 // How images are embedded: renderer.ImageBase64 (the default) or renderer.ImagePath
+	Profile   string                 // Variable profile to fill placeholders from
+	Variables map[string]interface{} // Values for placeholders, overriding the profile's
+	Redactor  *redact.Redactor       // Redacts the result, if set
+
+	// ReadSecrets reads variables from their env and keychain sources. Set it
+	// only when the result is shown to the library's owner, never for renders
+	// served to others such as API or chat requests.
+	ReadSecrets bool
+
+	// AskSecret, when set, is called for sensitive variables nothing else provides
+	AskSecret func(models.Variable) (string, error)
+
+	// Context fills placeholders such as {{git.branch}} and {{file:path}} from
+	// the caller's working directory. Like ReadSecrets, set it only for renders
+	// shown to the library's owner.
+	Context *renderer.Context
+
+	// Selection is text sent along with the render, such as code selected in a
+	// browser. It fills the prompt's context slot, or follows the prompt's
+	// content when nothing uses that slot.
+	Selection string
+
+	// UseAnswers fills variables from the values last given for the prompt on
+	// this device, under the profile's and Variables. RememberAnswers saves
+	// Variables as those values, except sensitive ones. Like ReadSecrets, set
+	// them only for renders by the library's owner.
+	UseAnswers      bool
+	RememberAnswers bool
+
+	// Section renders only the part of the prompt under the Markdown heading
+	// with this title. It works with the text format only.
+	Section string
+}
+
+// RenderPrompt renders a prompt as text or, with format "json", as a chat
+// request that includes the prompt's images and output schema, counting it as
+// a use of the prompt. Other formats are handed to the plugin formatter of
+// that name.
+func (s *Service) RenderPrompt(id string, opts RenderOptions) (string, error) {
+	defer tracing.Begin("render")()
+	if opts.Section != "" && opts.Format != "" && opts.Format != "text" {
+		return "", fmt.Errorf("a section can only be rendered as text, not %s", opts.Format)
+	}
+	prompt, r, variables, err := s.promptRenderer(id, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered string
+	switch opts.Format {
+	case "json":
+		schema, err := s.OutputSchema(prompt)
+		if err != nil {
+			return "", err
+		}
+		if schema != nil {
+			r.SetOutputSchema(prompt.ID, schema)
+		}
+		rendered, err = r.RenderJSON(variables)
+		if err != nil {
+			return "", err
+		}
+	case "", "text":
+		rendered, err = r.RenderText(variables)
+		if err != nil {
+			return "", err
+		}
+		if opts.Section != "" {
+			section, err := renderer.FindSection(rendered, opts.Section)
+			if err != nil {
+				return "", err
+			}
+			rendered = section.Text
+		}
+	default:
+		// Any other format is a plugin formatter, given the text render
+		text, err := r.RenderText(variables)
+		if err != nil {
+			return "", err
+		}
+		rendered, err = s.formatWithPlugin(opts.Format, prompt, text)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	s.RecordUsage(prompt.ID)
+	return rendered, nil
+}
+
+// promptRenderer loads a prompt and its template and sets up a renderer for
+// it as opts asks, returning the variables to render with
+func (s *Service) promptRenderer(id string, opts RenderOptions) (*models.Prompt, *renderer.Renderer, map[string]interface{}, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	variables, err := s.ProfileVariables(opts.Profile, opts.Variables)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var template *models.Template
+	if prompt.TemplateRef != "" {
+		template, _ = s.GetTemplate(prompt.TemplateRef)
+	}
+	if opts.UseAnswers {
+		variables = s.withSavedAnswers(prompt.ID, variables)
+	}
+	declared := s.DeclaredVariables(prompt, template)
+	variables, err = s.FillVariables(declared, variables, opts.ReadSecrets, opts.AskSecret)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if opts.RememberAnswers {
+		if err := s.rememberAnswers(prompt.ID, declared, opts.Variables); err != nil {
+			log.Printf("Warning: failed to save variable answers: %v", err)
+		}
+	}
+	if opts.Selection != "" {
+		slot := prompt.ContextSlotName()
+		if !usesPlaceholder(prompt.Content, declared, slot) {
+			// Copy, so the cached prompt keeps its content
+			withSlot := *prompt
+			withSlot.Content = strings.TrimRight(prompt.Content, "\n") + "\n\n{{" + slot + "}}"
+			prompt = &withSlot
+		}
+		variables[slot] = opts.Selection
+	}
+
+	r := renderer.NewRenderer(prompt, template)
+	r.SetAssetDir(s.GetBaseDir())
+	if err := r.SetImageEncoding(opts.Images); err != nil {
+		return nil, nil, nil, err
+	}
+	if opts.Redactor != nil {
+		r.SetRedactor(opts.Redactor.String)
+	}
+	r.SetContext(opts.Context)
+	return prompt, r, variables, nil
+}
+
+// usesPlaceholder reports whether a prompt declares the variable name or uses
+// it as a {{name}} placeholder
+func usesPlaceholder(content string, declared []models.Variable, name string) bool {
+	for _, v := range declared {
+		if v.Name == name {
+			return true
+		}
+	}
+	return regexp.MustCompile(`\{\{\s*` + regexp.QuoteMeta(name) + `\s*\}\}`).MatchString(content)
+}
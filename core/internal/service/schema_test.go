@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestOutputSchemaFromSidecar(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "triage", Name: "Triage", Content: "Classify this issue"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	prompt, err := svc.GetPrompt("triage")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+
+	sidecar := filepath.Join(tmpDir, filepath.FromSlash(storage.OutputSchemaPath(prompt)))
+	schema := `{"type": "object", "required": ["label"], "properties": {"label": {"enum": ["bug", "feature"]}}}`
+	if err := os.WriteFile(sidecar, []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	if problems, err := svc.ValidateOutput("triage", []byte(`{"label": "bug"}`)); err != nil || len(problems) != 0 {
+		t.Fatalf("valid output: problems %v, err %v", problems, err)
+	}
+	if problems, _ := svc.ValidateOutput("triage", []byte(`{"label": "question"}`)); len(problems) != 1 {
+		t.Fatalf("invalid output: problems %v, want 1", problems)
+	}
+
+	rendered, err := svc.RenderPrompt("triage", RenderOptions{Format: "json"})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	var request struct {
+		ResponseFormat struct {
+			JSONSchema struct {
+				Name   string                 `json:"name"`
+				Schema map[string]interface{} `json:"schema"`
+			} `json:"json_schema"`
+		} `json:"response_format"`
+	}
+	if err := json.Unmarshal([]byte(rendered), &request); err != nil {
+		t.Fatalf("rendered JSON is not a request body: %v\n%s", err, rendered)
+	}
+	if request.ResponseFormat.JSONSchema.Name != "triage" || request.ResponseFormat.JSONSchema.Schema["type"] != "object" {
+		t.Fatalf("response_format = %+v", request.ResponseFormat)
+	}
+}
+
+func TestRenderPromptWithSelection(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	prompts := []*models.Prompt{
+		{ID: "slotted", Name: "Slotted", Content: "Review this {{language}} code:\n{{code}}\nBe brief.", ContextSlot: "code"},
+		{ID: "plain", Name: "Plain", Content: "Explain this:\n"},
+	}
+	for _, p := range prompts {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	rendered, err := svc.RenderPrompt("slotted", RenderOptions{
+		Variables: map[string]interface{}{"language": "Go"},
+		Selection: "func main() {}",
+	})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	if rendered != "Review this Go code:\nfunc main() {}\nBe brief." {
+		t.Fatalf("slotted render = %q", rendered)
+	}
+
+	// Without a slot in the prompt, the selection follows the content
+	rendered, err = svc.RenderPrompt("plain", RenderOptions{Selection: "x := 1"})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	if rendered != "Explain this:\n\nx := 1" {
+		t.Fatalf("plain render = %q", rendered)
+	}
+	if prompt, _ := svc.GetPrompt("plain"); strings.Contains(prompt.Content, "context") {
+		t.Fatalf("render changed the stored prompt: %q", prompt.Content)
+	}
+}
+
+func TestRenderPromptSection(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	prompt := &models.Prompt{ID: "report", Name: "Report", Content: "# Task\nSummarize {{topic}}.\n\n# Output format\nA table about {{topic}}.\n"}
+	if err := svc.CreatePrompt(prompt); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	rendered, err := svc.RenderPrompt("report", RenderOptions{Section: "output format", Variables: map[string]interface{}{"topic": "sales"}})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	if rendered != "# Output format\nA table about sales." {
+		t.Errorf("section render = %q", rendered)
+	}
+	if _, err := svc.RenderPrompt("report", RenderOptions{Section: "Examples"}); err == nil || !strings.Contains(err.Error(), `"Task"`) {
+		t.Errorf("render of a missing section = %v, want the sections listed", err)
+	}
+	if _, err := svc.RenderPrompt("report", RenderOptions{Section: "Task", Format: "json"}); err == nil {
+		t.Error("rendering a section as json succeeded")
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/dpshade/pocket-prompt/internal/fuzzy"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// SearchTargets returns the text free text in a search is matched against
+// for each prompt, from the fields search.fields names. The TUI's / filter
+// matches the same text as SearchPrompts, ranked the same way. Content left
+// out of the prompt cache is read from disk the first time it is searched.
+func (s *Service) SearchTargets(prompts []*models.Prompt) []string {
+	fields := s.settings.Search.TextFields()
+	withContent := slices.Contains(fields, "content")
+	targets := make([]string, len(prompts))
+	for i, p := range prompts {
+		if withContent && p.Content == "" && p.FilePath != "" {
+			loaded := *p
+			loaded.Content = s.searchContent(p)
+			p = &loaded
+		}
+		targets[i] = p.SearchText(fields)
+	}
+	return targets
+}
+
+// searchContent returns the content of a cached prompt, kept until the prompt
+// cache is replaced. A prompt that fails to load searches as empty.
+func (s *Service) searchContent(p *models.Prompt) string {
+	s.contentMu.Lock()
+	defer s.contentMu.Unlock()
+	if content, ok := s.contents[p]; ok {
+		return content
+	}
+	content := ""
+	if full, err := s.storage.LoadPrompt(p.FilePath); err == nil {
+		content = full.Content
+	}
+	if s.contents == nil {
+		s.contents = make(map[*models.Prompt]string)
+	}
+	s.contents[p] = content
+	return content
+}
+
+// fuzzyMatch returns the prompts whose search targets match text, best first
+func (s *Service) fuzzyMatch(prompts []*models.Prompt, text string) []*models.Prompt {
+	var results []*models.Prompt
+	for _, match := range fuzzy.Find(text, s.SearchTargets(prompts)) {
+		results = append(results, prompts[match.Index])
+	}
+	return results
+}
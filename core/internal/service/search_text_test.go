@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSearchFields(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "fib", Name: "Sequences", Content: "Explain the fibonacci numbers", Tags: []string{"math"}}); err != nil {
+		t.Fatalf("CreatePrompt: %v", err)
+	}
+	// Search what a later run sees: the prompt cache without content
+	if svc, err = OpenLibrary(tmpDir); err != nil {
+		t.Fatalf("Failed to reopen library: %v", err)
+	}
+
+	search := func(query string) int {
+		t.Helper()
+		results, err := svc.SearchPrompts(query)
+		if err != nil {
+			t.Fatalf("SearchPrompts(%q): %v", query, err)
+		}
+		return len(results)
+	}
+	if search("math") != 1 || search("fibonacci") != 0 {
+		t.Error("Expected tags but not content to be searched by default")
+	}
+
+	svc.Settings().Search.Fields = []string{"title", "content"}
+	if search("fibonacci") != 1 || search("math") != 0 {
+		t.Error("Expected content but not tags to be searched once configured")
+	}
+	prompts, _ := svc.ListPrompts()
+	if targets := svc.SearchTargets(prompts); len(targets) != 1 || targets[0] != "Sequences Explain the fibonacci numbers" {
+		t.Errorf("targets = %q, want the title then the content", targets)
+	}
+}
+
+func TestSimilarPromptIDs(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, id := range []string{"code-review", "commit-message"} {
+		if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: id, Content: "text"}); err != nil {
+			t.Fatalf("CreatePrompt: %v", err)
+		}
+	}
+
+	if _, err := svc.GetPrompt("code-reviw"); !errors.Is(err, ErrPromptNotFound) {
+		t.Errorf("GetPrompt(code-reviw) = %v, want ErrPromptNotFound", err)
+	}
+	if similar := svc.SimilarPromptIDs("code-reviw"); len(similar) != 1 || similar[0] != "code-review" {
+		t.Errorf("SimilarPromptIDs(code-reviw) = %q, want code-review", similar)
+	}
+}
+
+func TestResolvePromptID(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, id := range []string{"code", "code-review", "commit-message"} {
+		if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: id, Content: "text"}); err != nil {
+			t.Fatalf("CreatePrompt: %v", err)
+		}
+	}
+
+	for input, want := range map[string]string{"code": "code", "code-r": "code-review", "com": "commit-message", "nope": "nope"} {
+		if id, err := svc.ResolvePromptID(input); err != nil || id != want {
+			t.Errorf("ResolvePromptID(%q) = %q, %v; want %q", input, id, err, want)
+		}
+	}
+	var ambiguous *AmbiguousIDError
+	if _, err := svc.ResolvePromptID("co"); !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 3 {
+		t.Errorf("ResolvePromptID(co) = %v, want all three IDs as candidates", err)
+	}
+}
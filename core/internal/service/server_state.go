@@ -0,0 +1,55 @@
+package service
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// RecordServer remembers that the URL server listens at address on port, so
+// commands such as 'pkt server qr' give its real address. Read-only
+// libraries, such as the demo, are left as they are.
+func (s *Service) RecordServer(address string, port int) error {
+	if s.ReadOnly() {
+		return nil
+	}
+	return storage.SaveServerState(s.GetBaseDir(), &storage.ServerState{
+		URL:       address,
+		Port:      port,
+		PID:       os.Getpid(),
+		StartedAt: time.Now(),
+	})
+}
+
+// ForgetServer clears what RecordServer remembered, once the server stops
+func (s *Service) ForgetServer() error {
+	if s.ReadOnly() {
+		return nil
+	}
+	return storage.RemoveServerState(s.GetBaseDir())
+}
+
+// RunningServer returns where the URL server listens, or nil when none is.
+// A server that was killed before it could forget its address leaves it
+// behind, so the address is tried rather than trusted.
+func (s *Service) RunningServer() *storage.ServerState {
+	state, err := storage.LoadServerState(s.GetBaseDir())
+	if err != nil || state == nil || state.Port <= 0 {
+		return nil
+	}
+	// A server bound to one address, such as its tailnet one, answers only there
+	host := "localhost"
+	if u, err := url.Parse(state.URL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(state.Port)), 300*time.Millisecond)
+	if err != nil {
+		return nil
+	}
+	conn.Close()
+	return state
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+func TestRunningServer(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if state := svc.RunningServer(); state != nil {
+		t.Fatalf("RunningServer before any server = %+v, want nil", state)
+	}
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := lis.Addr().(*net.TCPAddr).Port
+	if err := svc.RecordServer("http://localhost", port); err != nil {
+		t.Fatalf("RecordServer: %v", err)
+	}
+	if state := svc.RunningServer(); state == nil || state.Port != port || state.PID != os.Getpid() {
+		t.Errorf("RunningServer = %+v, want port %d", state, port)
+	}
+
+	// A server that died without forgetting its address is not running
+	lis.Close()
+	if state := svc.RunningServer(); state != nil {
+		t.Errorf("RunningServer after the port closed = %+v, want nil", state)
+	}
+
+	if err := svc.ForgetServer(); err != nil {
+		t.Fatalf("ForgetServer: %v", err)
+	}
+	if err := svc.ForgetServer(); err != nil {
+		t.Errorf("ForgetServer without a recorded server: %v", err)
+	}
+}
@@ -2,28 +2,56 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/fuzzy"
 	"github.com/dpshade/pocket-prompt/internal/git"
 	"github.com/dpshade/pocket-prompt/internal/importer"
+	"github.com/dpshade/pocket-prompt/internal/language"
 	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/remote"
+	"github.com/dpshade/pocket-prompt/internal/startup"
 	"github.com/dpshade/pocket-prompt/internal/storage"
-	"github.com/sahilm/fuzzy"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
 )
 
 // Service provides business logic for prompt management
 type Service struct {
 	storage       *storage.Storage
-	prompts       []*models.Prompt             // Cached prompts for fast access
+	prompts       []*models.Prompt             // Cached prompts for fast access, set with setPrompts
+	tagIndex      *tagIndex                    // Cached prompts by tag, for boolean search
+	cacheMu       sync.RWMutex                 // Guards prompts, tagIndex and the load counts, read with cachedPrompts
+	loadsStarted  uint64                       // Library reads begun, see beginLoad
+	loadSet       uint64                       // The read the cache holds, see setLoaded
 	gitSync       *git.GitSync                 // Git synchronization
 	savedSearches *storage.SavedSearchesStorage // Saved boolean searches
+	usage         *storage.UsageStorage        // How often each prompt is used
+	templateUsage *storage.UsageStorage        // How often prompts using each template are used
+	slowQueries   *storage.SlowQueryLog        // Searches slower than the configured threshold
+	analytics     *storage.AnalyticsLog        // Opt-in local usage analytics
 	packConfig    *config.PackConfig           // Pack configuration
+	settings      *config.Config               // Library settings
+	iface         string                       // Interface changes are made from, see SetInterface
+
+	changeListeners []func([]PromptEvent)     // Told about prompts each git pull changed
+	forceProtected  bool                      // Let changes touch protected prompts, for --force-protected
+	updateMu        sync.Mutex                // Serializes UpdatePromptIfVersion
+	contents        map[*models.Prompt]string // Content of cached prompts read for search, see searchContent
+	contentMu       sync.Mutex                // Guards contents
+
+	project     *Service // Project library merged into this one, see withProjectLibrary
+	projectDir  string   // Project library in use, merged or instead of the global one
+	projectMode string   // How projectDir is used: merge or override
 }
 
 // NewService creates a new service instance 
@@ -31,9 +59,22 @@ func NewService() (*Service, error) {
 	return NewServiceWithDirectory("")
 }
 
+// NewReadOnlyService opens the same library as NewService, but read-only:
+// every change is refused with storage.ErrReadOnly and git never pulls into it
+func NewReadOnlyService() (*Service, error) {
+	return newService("", true)
+}
+
 // NewServiceWithDirectory creates a new service instance for a specific directory
-// If directory is empty, it uses POCKET_PROMPT_DIR or default ~/.pocket-prompt
+// If directory is empty, it uses POCKET_PROMPT_DIR or default ~/.pocket-prompt,
+// combined with any project library above the working directory
 func NewServiceWithDirectory(directory string) (*Service, error) {
+	return newService(directory, false)
+}
+
+// newService opens the library for NewServiceWithDirectory, starting git sync
+// in the background unless it is disabled or the library is read-only
+func newService(directory string, readOnly bool) (*Service, error) {
 	var rootPath string
 	
 	if directory != "" {
@@ -52,38 +93,49 @@ func NewServiceWithDirectory(directory string) (*Service, error) {
 		}
 	}
 
-	store, err := storage.NewStorage(rootPath)
+	svc, err := openLibrary(rootPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+		return nil, err
 	}
-
-	// Initialize pack configuration
-	packConfig, err := config.NewPackConfig(store.GetBaseDir())
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize pack configuration: %w", err)
+	if directory == "" {
+		if wd, err := os.Getwd(); err == nil {
+			if svc, err = svc.withProjectLibrary(FindProjectLibrary(wd)); err != nil {
+				return nil, err
+			}
+		}
 	}
-
-	// Initialize git sync
-	gitSync := git.NewGitSync(store.GetBaseDir())
-	// Don't block on git initialization - it will be done in background
-
-	// Initialize saved searches storage
-	savedSearches := storage.NewSavedSearchesStorage(store.GetBaseDir())
-
-	svc := &Service{
-		storage:       store,
-		gitSync:       gitSync,
-		savedSearches: savedSearches,
-		packConfig:    packConfig,
+	if readOnly {
+		svc.SetReadOnly(true)
+		return svc, nil
+	}
+	gitSync := svc.gitSync
+	if err := svc.configureGitAuth(); err != nil {
+		return nil, err
+	}
+	if svc.settings.Git.NoSync {
+		return svc, nil
+	}
+	// Without git, as in a minimal container, the library syncs with the
+	// built-in implementation unless the config requires git
+	switch {
+	case svc.settings.Platform.BuiltinGit():
+		gitSync.UseBuiltin()
+	case !git.Available():
+		if svc.settings.Platform.RequiresGit() {
+			return nil, fmt.Errorf("platform git is exec, but %w", git.ErrGitNotFound)
+		}
+		gitSync.UseBuiltin()
 	}
 
-	// Initialize git sync and auto-pull in background
 	go func() {
 		// Small delay to let service initialize
 		time.Sleep(50 * time.Millisecond)
 		
 		// Initialize git sync first
-		if err := gitSync.Initialize(); err != nil {
+		endGitInit := startup.Begin("git init")
+		err := gitSync.Initialize()
+		endGitInit()
+		if err != nil {
 			// Git sync initialization failure is not fatal
 			// The service can still work without git sync
 			return
@@ -91,6 +143,7 @@ func NewServiceWithDirectory(directory string) (*Service, error) {
 		
 		// Always attempt to pull latest changes on startup
 		// This ensures users get latest prompts automatically
+		defer startup.Begin("git pull")()
 		if err := gitSync.AutoPullOnStartup(); err != nil {
 			// Pull failure is not fatal - user may be offline or have local changes
 			// Silently continue without error message
@@ -103,6 +156,110 @@ func NewServiceWithDirectory(directory string) (*Service, error) {
 	return svc, nil
 }
 
+// OpenLibrary opens the library at directory without starting git sync, for
+// reading another library (such as a federated source) alongside the main one
+func OpenLibrary(directory string) (*Service, error) {
+	if directory == "" {
+		return nil, fmt.Errorf("library directory is required")
+	}
+	return openLibrary(directory)
+}
+
+// SetReadOnly makes the library reject changes with storage.ErrReadOnly, or
+// config.ErrReadOnly for its settings and packs, and stops usage from being
+// counted
+func (s *Service) SetReadOnly(readOnly bool) {
+	s.storage.SetReadOnly(readOnly)
+	s.settings.SetReadOnly(readOnly)
+	s.packConfig.SetReadOnly(readOnly)
+	s.savedSearches.SetReadOnly(readOnly)
+	s.usage.SetReadOnly(readOnly)
+	s.templateUsage.SetReadOnly(readOnly)
+	s.slowQueries.SetReadOnly(readOnly)
+	s.analytics.SetReadOnly(readOnly)
+	if s.project != nil {
+		s.project.SetReadOnly(readOnly)
+	}
+}
+
+// ReadOnly reports whether the library rejects changes
+func (s *Service) ReadOnly() bool {
+	return s.storage.ReadOnly()
+}
+
+// configureGitAuth passes the configured credentials, commit author and
+// signing key to every git command, for servers without a credential helper
+// or git identity such as containers
+func (s *Service) configureGitAuth() error {
+	token, source, err := s.settings.Git.Token()
+	if err != nil {
+		return err
+	}
+	if err := s.gitSync.ConfigureAuth(git.Auth{
+		SSHKey:    s.settings.Git.SSHKey,
+		Token:     token,
+		TokenUser: s.settings.Git.TokenUser,
+		Source:    source,
+	}); err != nil {
+		return err
+	}
+	return s.gitSync.ConfigureIdentity(git.Identity{
+		Name:          s.settings.Git.AuthorName,
+		Email:         s.settings.Git.AuthorEmail,
+		SigningKey:    s.settings.Git.SigningKey,
+		SigningFormat: s.settings.Git.SigningFormat,
+	})
+}
+
+// openLibrary loads the storage and settings for the library at rootPath
+func openLibrary(rootPath string) (*Service, error) {
+	endStorage := startup.Begin("storage init")
+	store, err := storage.NewStorage(rootPath)
+	endStorage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	// Load library settings
+	endConfig := startup.Begin("config load")
+	settings, err := config.LoadConfig(store.GetBaseDir())
+	endConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := store.SetDefaultFormat(settings.Storage.FrontmatterFormat); err != nil {
+		return nil, fmt.Errorf("invalid storage configuration: %w", err)
+	}
+	store.SetDirectoryTags(settings.Storage.DirectoryTags)
+
+	// Initialize pack configuration
+	packConfig, err := config.NewPackConfig(store.GetBaseDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize pack configuration: %w", err)
+	}
+
+	// Initialize git sync
+	gitSync := git.NewGitSync(store.GetBaseDir())
+	gitSync.SetMainBranch(settings.Git.MainBranch)
+	store.SetCheckoutPaths(gitSync.SparsePaths())
+	// Don't block on git initialization - it will be done in background
+
+	// Initialize saved searches storage
+	savedSearches := storage.NewSavedSearchesStorage(store.GetBaseDir())
+
+	return &Service{
+		storage:       store,
+		gitSync:       gitSync,
+		savedSearches: savedSearches,
+		usage:         storage.NewUsageStorage(store.GetBaseDir()),
+		templateUsage: storage.NewTemplateUsageStorage(store.GetBaseDir()),
+		slowQueries:   storage.NewSlowQueryLog(store.GetBaseDir()),
+		analytics:     storage.NewAnalyticsLog(store.GetBaseDir()),
+		packConfig:    packConfig,
+		settings:      settings,
+	}, nil
+}
+
 // LoadPromptsAsync loads prompts asynchronously and returns a function to check completion
 func (s *Service) LoadPromptsAsync() func() ([]*models.Prompt, bool, error) {
 	resultChan := make(chan struct {
@@ -111,9 +268,10 @@ func (s *Service) LoadPromptsAsync() func() ([]*models.Prompt, bool, error) {
 	}, 1)
 
 	go func() {
-		prompts, err := s.storage.ListPrompts()
+		load := s.beginLoad()
+		prompts, err := s.libraryPrompts()
 		if err == nil {
-			s.prompts = prompts
+			s.setLoaded(load, prompts)
 		}
 		resultChan <- struct {
 			prompts []*models.Prompt
@@ -135,9 +293,10 @@ func (s *Service) LoadPromptsAsync() func() ([]*models.Prompt, bool, error) {
 func (s *Service) LoadPromptsIncremental(callback func([]*models.Prompt, bool, error)) {
 	go func() {
 		// Load prompts in the background
-		prompts, err := s.storage.ListPrompts()
+		load := s.beginLoad()
+		prompts, err := s.libraryPrompts()
 		if err == nil {
-			s.prompts = prompts
+			s.setLoaded(load, prompts)
 		}
 		// Send final result
 		callback(prompts, true, err)
@@ -146,30 +305,60 @@ func (s *Service) LoadPromptsIncremental(callback func([]*models.Prompt, bool, e
 
 // InitLibrary initializes a new prompt library
 func (s *Service) InitLibrary() error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
 	return s.storage.InitLibrary()
 }
 
 // loadPrompts loads all prompts into memory for fast access
 func (s *Service) loadPrompts() error {
-	prompts, err := s.storage.ListPrompts()
+	load := s.beginLoad()
+	prompts, err := s.libraryPrompts()
 	if err != nil {
 		return err
 	}
-	s.prompts = prompts
+	s.setLoaded(load, prompts)
 	return nil
 }
 
-// ListPrompts returns all non-archived prompts
+// ensurePrompts loads the prompt cache unless it holds prompts already
+func (s *Service) ensurePrompts() error {
+	if len(s.cachedPrompts()) > 0 {
+		return nil
+	}
+	endLoad := startup.Begin("cache load")
+	defer endLoad()
+	defer tracing.Begin("storage load")()
+	return s.loadPrompts()
+}
+
+// ListPrompts returns all non-archived prompts that have been approved
 func (s *Service) ListPrompts() ([]*models.Prompt, error) {
-	if len(s.prompts) == 0 {
-		if err := s.loadPrompts(); err != nil {
-			return nil, err
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	// Prompts still in review stay out of listings until approved
+	var approved []*models.Prompt
+	for _, prompt := range prompts {
+		if prompt.IsApproved() {
+			approved = append(approved, prompt)
 		}
 	}
+	return approved, nil
+}
+
+// activePrompts returns every prompt that is not archived, whatever its review state
+func (s *Service) activePrompts() ([]*models.Prompt, error) {
+	if err := s.ensurePrompts(); err != nil {
+		return nil, err
+	}
 	
 	// Filter out archived prompts
 	var activePrompts []*models.Prompt
-	for _, prompt := range s.prompts {
+	for _, prompt := range s.cachedPrompts() {
 		if !s.isArchived(prompt) {
 			activePrompts = append(activePrompts, prompt)
 		}
@@ -203,44 +392,62 @@ func (s *Service) GetAvailablePacks() (map[string]string, error) {
 	return result, nil
 }
 
-// SearchPrompts searches prompts by query string
+// SearchPrompts searches prompts by query string, ignoring accents, so
+// "resume" finds "Résumé". Filters in the query, such as tag:ai, -tag:draft
+// and title:review*, narrow the prompts before the rest is fuzzy matched.
 func (s *Service) SearchPrompts(query string) ([]*models.Prompt, error) {
 	prompts, err := s.ListPrompts()
 	if err != nil {
 		return nil, err
 	}
+	start := time.Now()
 
 	if query == "" {
 		return prompts, nil
 	}
+	defer tracing.Begin("search")()
+
+	parsed := models.ParseSearchQuery(query)
+	results := s.searchPrompts(prompts, parsed)
+	s.logIfSlow(storage.SlowQuery{
+		Kind:       "search",
+		Query:      query,
+		Prompts:    len(prompts),
+		Results:    len(results),
+		Complexity: parsed.Complexity(),
+	}, start)
+	return results, nil
+}
 
-	// Create searchable strings for each prompt
-	var searchStrings []string
-	for _, p := range prompts {
-		searchStr := fmt.Sprintf("%s %s %s %s", 
-			p.Name, 
-			p.Summary, 
-			p.ID,
-			strings.Join(p.Tags, " "))
-		searchStrings = append(searchStrings, searchStr)
+// searchPrompts narrows prompts by the query's filters, then fuzzy matches
+// its text
+func (s *Service) searchPrompts(prompts []*models.Prompt, parsed models.SearchQuery) []*models.Prompt {
+	if len(parsed.Filters) > 0 {
+		var filtered []*models.Prompt
+		for _, p := range prompts {
+			if parsed.Matches(p) {
+				filtered = append(filtered, p)
+			}
+		}
+		prompts = filtered
 	}
-
-	// Perform fuzzy search
-	matches := fuzzy.Find(query, searchStrings)
-	
-	// Build result list
-	var results []*models.Prompt
-	for _, match := range matches {
-		results = append(results, prompts[match.Index])
+	if parsed.Text == "" {
+		return prompts
 	}
-
-	return results, nil
+	return s.fuzzyMatch(prompts, parsed.Text)
 }
 
+// ErrPromptNotFound is returned by GetPrompt for an ID no prompt has
+var ErrPromptNotFound = errors.New("prompt not found")
+
 // GetPrompt returns a prompt by ID with full content loaded
 func (s *Service) GetPrompt(id string) (*models.Prompt, error) {
-	// First try to find in personal prompts cache
-	prompts, err := s.ListPrompts()
+	if lib := s.libraryFor(id); lib != s {
+		return lib.GetPrompt(id)
+	}
+
+	// First try to find in personal prompts cache, including prompts in review
+	prompts, err := s.activePrompts()
 	if err != nil {
 		return nil, err
 	}
@@ -282,7 +489,60 @@ func (s *Service) GetPrompt(id string) (*models.Prompt, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("prompt not found: %s", id)
+	return nil, fmt.Errorf("%w: %s", ErrPromptNotFound, id)
+}
+
+// AmbiguousIDError is returned when a shortened prompt ID starts more than
+// one prompt's ID
+type AmbiguousIDError struct {
+	ID         string
+	Candidates []string
+}
+
+func (e *AmbiguousIDError) Error() string {
+	return fmt.Sprintf("prompt ID %s is ambiguous; it could be %s", e.ID, strings.Join(e.Candidates, ", "))
+}
+
+// ResolvePromptID returns the ID of the prompt id names: id itself when a
+// prompt has it, or else the one prompt whose ID starts with it, so long IDs
+// can be shortened as far as they stay unique. When no prompt matches, id is
+// returned as it is for the caller to report.
+func (s *Service) ResolvePromptID(id string) (string, error) {
+	prompts, err := s.activePrompts()
+	if err != nil || id == "" {
+		return id, err
+	}
+	var candidates []string
+	for _, p := range prompts {
+		if p.ID == id {
+			return id, nil
+		}
+		if strings.HasPrefix(p.ID, id) {
+			candidates = append(candidates, p.ID)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return id, nil
+	case 1:
+		return candidates[0], nil
+	}
+	sort.Strings(candidates)
+	return "", &AmbiguousIDError{ID: id, Candidates: candidates}
+}
+
+// SimilarPromptIDs returns up to five IDs of prompts that id may have been
+// meant for, the closest first
+func (s *Service) SimilarPromptIDs(id string) []string {
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, len(prompts))
+	for i, p := range prompts {
+		ids[i] = p.ID
+	}
+	return fuzzy.Similar(id, ids, 5)
 }
 
 // CreatePrompt creates a new prompt
@@ -291,16 +551,13 @@ func (s *Service) CreatePrompt(prompt *models.Prompt) error {
 	now := time.Now()
 	prompt.CreatedAt = now
 	prompt.UpdatedAt = now
+	if prompt.Language == "" {
+		prompt.Language = language.Detect(prompt.Content)
+	}
 
 	// Generate file path if not set
 	if prompt.FilePath == "" {
-		if prompt.Pack != "" && prompt.Pack != "personal" {
-			// Route to pack directory
-			prompt.FilePath = filepath.Join("packs", prompt.Pack, "prompts", fmt.Sprintf("%s.md", prompt.ID))
-		} else {
-			// Route to personal library (default)
-			prompt.FilePath = filepath.Join("prompts", fmt.Sprintf("%s.md", prompt.ID))
-		}
+		prompt.FilePath, _ = s.PromptFilePath(prompt.Pack, "", prompt.ID)
 	}
 
 	// Save to storage
@@ -308,11 +565,12 @@ func (s *Service) CreatePrompt(prompt *models.Prompt) error {
 		return err
 	}
 
-	// Sync to pack Git repo if prompt is in a pack with write access
-	if prompt.Pack != "" && prompt.Pack != "personal" {
-		if pack, err := s.packConfig.GetPack(prompt.Pack); err == nil && pack.GitSyncEnabled && pack.HasWriteAccess {
+	// Sync to pack Git repo if prompt is in a pack with write access.
+	// Route by file location: a pack label inherited from _meta.yaml does not move the file.
+	if packName := storage.PackFromPath(prompt.FilePath); packName != "" {
+		if pack, err := s.packConfig.GetPack(packName); err == nil && pack.GitSyncEnabled && pack.HasWriteAccess {
 			go func() {
-				if err := s.packConfig.SyncPackToGit(prompt.Pack, fmt.Sprintf("Create prompt: %s", prompt.Title())); err != nil {
+				if err := s.packConfig.SyncPackToGit(packName, s.commitMessage(config.CommitCreatePrompt, prompt.ID, prompt.Title(), prompt.Version)); err != nil {
 					fmt.Printf("Warning: Pack Git sync failed after creating prompt: %v\n", err)
 				}
 			}()
@@ -320,7 +578,7 @@ func (s *Service) CreatePrompt(prompt *models.Prompt) error {
 	} else {
 		// Sync to personal git if enabled
 		if s.gitSync.IsEnabled() {
-			if err := s.gitSync.SyncChanges(fmt.Sprintf("Create prompt: %s", prompt.Title())); err != nil {
+			if err := s.gitSync.SyncCommit(s.commitMessage(config.CommitCreatePrompt, prompt.ID, prompt.Title(), prompt.Version)); err != nil {
 				// Don't fail the operation if git sync fails, just log it
 				// The prompt was saved successfully to local storage
 				fmt.Printf("Warning: Git sync failed after creating prompt: %v\n", err)
@@ -332,8 +590,129 @@ func (s *Service) CreatePrompt(prompt *models.Prompt) error {
 	return s.loadPrompts()
 }
 
-// UpdatePrompt updates an existing prompt with version management
+// QuickAdd creates a prompt from bare text, filling in everything else: the
+// title defaults to the first line of content and the ID is derived from the
+// title, suffixed with a number when it is already taken
+func (s *Service) QuickAdd(content, title string, tags []string) (*models.Prompt, error) {
+	prompt, err := s.newQuickPrompt(content, title, tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CreatePrompt(prompt); err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
+
+// newQuickPrompt builds an unsaved prompt the way QuickAdd does
+func (s *Service) newQuickPrompt(content, title string, tags []string) (*models.Prompt, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	if title = strings.TrimSpace(title); title == "" {
+		title, _, _ = strings.Cut(content, "\n")
+		title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+		if len([]rune(title)) > 60 {
+			title = strings.TrimSpace(string([]rune(title)[:60])) + "..."
+		}
+	}
+
+	baseID := models.IDFromTitle(title, "quick-add")
+	id := baseID
+	for n := 2; ; n++ {
+		if _, err := s.GetPrompt(id); err != nil {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", baseID, n)
+	}
+
+	var cleanTags []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !models.ContainsTag(cleanTags, tag) {
+			cleanTags = append(cleanTags, tag)
+		}
+	}
+
+	prompt := &models.Prompt{
+		ID:      id,
+		Version: "1.0.0",
+		Name:    title,
+		Tags:    cleanTags,
+		Content: content,
+	}
+	return prompt, nil
+}
+
+// PromptFilePath returns the library-relative path for a new prompt file.
+// dir optionally places the file in a subdirectory of the pack's prompts folder.
+func (s *Service) PromptFilePath(pack, dir, id string) (string, error) {
+	subdir, err := storage.ValidateSubdir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	filename := fmt.Sprintf("%s.md", id)
+	if pack != "" && pack != "personal" {
+		// Route to pack directory
+		return filepath.Join("packs", pack, "prompts", subdir, filename), nil
+	}
+	// Route to personal library (default)
+	return filepath.Join("prompts", subdir, filename), nil
+}
+
+// UpdatePrompt updates an existing prompt with version management. A
+// protected prompt is refused unless protection is overridden.
 func (s *Service) UpdatePrompt(prompt *models.Prompt) error {
+	if existing, err := s.GetPrompt(prompt.ID); err == nil {
+		if err := s.CheckProtected(existing); err != nil {
+			return err
+		}
+	}
+	return s.updatePrompt(prompt)
+}
+
+// updatePrompt saves prompt as the next version of an existing prompt and
+// syncs it, whether or not it is protected
+func (s *Service) updatePrompt(prompt *models.Prompt) error {
+	if lib := s.libraryFor(prompt.ID); lib != s {
+		if err := lib.updatePrompt(prompt); err != nil {
+			return err
+		}
+		return s.loadPrompts()
+	}
+
+	if err := s.writePromptVersion(prompt); err != nil {
+		return err
+	}
+
+	// Sync to pack Git repo if prompt is in a pack with write access
+	if packName := storage.PackFromPath(prompt.FilePath); packName != "" {
+		if pack, err := s.packConfig.GetPack(packName); err == nil && pack.GitSyncEnabled && pack.HasWriteAccess {
+			go func() {
+				if err := s.packConfig.SyncPackToGit(packName, s.commitMessage(config.CommitUpdatePrompt, prompt.ID, prompt.Title(), prompt.Version)); err != nil {
+					fmt.Printf("Warning: Pack Git sync failed after updating prompt: %v\n", err)
+				}
+			}()
+		}
+	} else {
+		// Sync to personal git if enabled
+		if s.gitSync.IsEnabled() {
+			if err := s.gitSync.SyncCommit(s.commitMessage(config.CommitUpdatePrompt, prompt.ID, prompt.Title(), prompt.Version)); err != nil {
+				// Don't fail the operation if git sync fails, just log it
+				fmt.Printf("Warning: Git sync failed after updating prompt: %v\n", err)
+			}
+		}
+	}
+
+	// Reload prompts cache
+	return s.loadPrompts()
+}
+
+// writePromptVersion archives the current version of a prompt and saves
+// prompt as the next one, without syncing or reloading the cache
+func (s *Service) writePromptVersion(prompt *models.Prompt) error {
 	// Get the existing prompt to check current version
 	existing, err := s.GetPrompt(prompt.ID)
 	if err != nil {
@@ -355,6 +734,9 @@ func (s *Service) UpdatePrompt(prompt *models.Prompt) error {
 	// Update timestamp but keep original creation time
 	prompt.CreatedAt = existing.CreatedAt
 	prompt.UpdatedAt = time.Now()
+	if prompt.Language == "" {
+		prompt.Language = language.Detect(prompt.Content)
+	}
 	
 	// Check if pack has changed and update file path accordingly
 	packChanged := false
@@ -369,12 +751,8 @@ func (s *Service) UpdatePrompt(prompt *models.Prompt) error {
 	
 	if existingPack != newPack {
 		packChanged = true
-		// Generate new file path for the new pack
-		if newPack != "personal" {
-			prompt.FilePath = filepath.Join("packs", newPack, "prompts", fmt.Sprintf("%s.md", prompt.ID))
-		} else {
-			prompt.FilePath = filepath.Join("prompts", fmt.Sprintf("%s.md", prompt.ID))
-		}
+		// Generate new file path for the new pack, keeping the prompt's subdirectory
+		prompt.FilePath, _ = s.PromptFilePath(newPack, storage.PromptSubdir(existing.FilePath), prompt.ID)
 	} else {
 		// Keep original file path if pack hasn't changed
 		if prompt.FilePath == "" {
@@ -382,6 +760,11 @@ func (s *Service) UpdatePrompt(prompt *models.Prompt) error {
 		}
 	}
 
+	// Directory-derived tags carried over from the loaded prompt must not be persisted
+	if prompt.DerivedTags == nil {
+		prompt.DerivedTags = existing.DerivedTags
+	}
+
 	// Save the new version (without archive tag)
 	if err := s.storage.SavePrompt(prompt); err != nil {
 		return err
@@ -394,36 +777,25 @@ func (s *Service) UpdatePrompt(prompt *models.Prompt) error {
 			fmt.Printf("Warning: Failed to delete old prompt file at %s: %v\n", existing.FilePath, err)
 		}
 	}
-
-	// Sync to pack Git repo if prompt is in a pack with write access
-	if prompt.Pack != "" && prompt.Pack != "personal" {
-		if pack, err := s.packConfig.GetPack(prompt.Pack); err == nil && pack.GitSyncEnabled && pack.HasWriteAccess {
-			go func() {
-				if err := s.packConfig.SyncPackToGit(prompt.Pack, fmt.Sprintf("Update prompt: %s (v%s)", prompt.Title(), prompt.Version)); err != nil {
-					fmt.Printf("Warning: Pack Git sync failed after updating prompt: %v\n", err)
-				}
-			}()
-		}
-	} else {
-		// Sync to personal git if enabled
-		if s.gitSync.IsEnabled() {
-			if err := s.gitSync.SyncChanges(fmt.Sprintf("Update prompt: %s (v%s)", prompt.Title(), prompt.Version)); err != nil {
-				// Don't fail the operation if git sync fails, just log it
-				fmt.Printf("Warning: Git sync failed after updating prompt: %v\n", err)
-			}
-		}
-	}
-
-	// Reload prompts cache
-	return s.loadPrompts()
+	return nil
 }
 
-// DeletePrompt deletes a prompt by ID
+// DeletePrompt deletes a prompt by ID, refusing a protected prompt unless
+// protection is overridden
 func (s *Service) DeletePrompt(id string) error {
 	prompt, err := s.GetPrompt(id)
 	if err != nil {
 		return err
 	}
+	if err := s.CheckProtected(prompt); err != nil {
+		return err
+	}
+	if lib := s.libraryFor(id); lib != s {
+		if err := lib.DeletePrompt(id); err != nil {
+			return err
+		}
+		return s.loadPrompts()
+	}
 
 	// Delete the file from storage
 	if err := s.storage.DeletePrompt(prompt); err != nil {
@@ -431,10 +803,10 @@ func (s *Service) DeletePrompt(id string) error {
 	}
 
 	// Sync to pack Git repo if prompt is in a pack with write access
-	if prompt.Pack != "" && prompt.Pack != "personal" {
-		if pack, err := s.packConfig.GetPack(prompt.Pack); err == nil && pack.GitSyncEnabled && pack.HasWriteAccess {
+	if packName := storage.PackFromPath(prompt.FilePath); packName != "" {
+		if pack, err := s.packConfig.GetPack(packName); err == nil && pack.GitSyncEnabled && pack.HasWriteAccess {
 			go func() {
-				if err := s.packConfig.SyncPackToGit(prompt.Pack, fmt.Sprintf("Delete prompt: %s", prompt.Title())); err != nil {
+				if err := s.packConfig.SyncPackToGit(packName, s.commitMessage(config.CommitDeletePrompt, prompt.ID, prompt.Title(), prompt.Version)); err != nil {
 					fmt.Printf("Warning: Pack Git sync failed after deleting prompt: %v\n", err)
 				}
 			}()
@@ -442,7 +814,7 @@ func (s *Service) DeletePrompt(id string) error {
 	} else {
 		// Sync to personal git if enabled
 		if s.gitSync.IsEnabled() {
-			if err := s.gitSync.SyncChanges(fmt.Sprintf("Delete prompt: %s", prompt.Title())); err != nil {
+			if err := s.gitSync.SyncCommit(s.commitMessage(config.CommitDeletePrompt, prompt.ID, prompt.Title(), prompt.Version)); err != nil {
 				// Don't fail the operation if git sync fails, just log it
 				fmt.Printf("Warning: Git sync failed after deleting prompt: %v\n", err)
 			}
@@ -495,9 +867,25 @@ func (s *Service) GetAllTags() ([]string, error) {
 	return tags, nil
 }
 
+// TagCounts returns how many prompts carry each tag
+func (s *Service) TagCounts() (map[string]int, error) {
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, p := range prompts {
+		for _, tag := range p.Tags {
+			counts[tag]++
+		}
+	}
+	return counts, nil
+}
+
 // ListTemplates returns all available templates
 func (s *Service) ListTemplates() ([]*models.Template, error) {
-	return s.storage.ListTemplates()
+	return s.libraryTemplates()
 }
 
 // GetTemplate returns a template by ID
@@ -533,14 +921,37 @@ func (s *Service) SavePrompt(prompt *models.Prompt) error {
 
 // SaveTemplate saves a template (create or update)
 func (s *Service) SaveTemplate(template *models.Template) error {
-	// Set file path if not set
-	if template.FilePath == "" {
-		template.FilePath = filepath.Join("templates", fmt.Sprintf("%s.md", template.ID))
+	if lib := s.templateLibraryFor(template.ID); lib != s {
+		return lib.SaveTemplate(template)
 	}
 
 	// Check if this is an existing template
 	existing, err := s.GetTemplate(template.ID)
+
+	// Set file path if not set, keeping an existing template where it is
+	if template.FilePath == "" {
+		if existing != nil {
+			template.FilePath = existing.FilePath
+		} else {
+			template.FilePath = filepath.Join("templates", fmt.Sprintf("%s.md", template.ID))
+		}
+	}
+
 	if err == nil {
+		// Archive the version being replaced, as prompt updates do
+		if err := s.archiveTemplate(existing); err != nil {
+			return fmt.Errorf("failed to archive old version: %w", err)
+		}
+
+		// Bump the version unless the edit set a new one
+		if template.Version == "" || template.Version == existing.Version {
+			newVersion, err := s.incrementVersion(existing.Version)
+			if err != nil {
+				return fmt.Errorf("failed to increment version: %w", err)
+			}
+			template.Version = newVersion
+		}
+
 		// Update existing template
 		template.CreatedAt = existing.CreatedAt // Keep original creation time
 		template.UpdatedAt = time.Now()
@@ -558,11 +969,11 @@ func (s *Service) SaveTemplate(template *models.Template) error {
 
 	// Sync to git if enabled
 	if s.gitSync.IsEnabled() {
-		action := "Create"
+		change := config.CommitCreateTemplate
 		if existing != nil {
-			action = "Update"
+			change = config.CommitUpdateTemplate
 		}
-		if err := s.gitSync.SyncChanges(fmt.Sprintf("%s template: %s", action, template.Name)); err != nil {
+		if err := s.gitSync.SyncCommit(s.commitMessage(change, template.ID, template.Name, template.Version)); err != nil {
 			// Don't fail the operation if git sync fails, just log it
 			fmt.Printf("Warning: Git sync failed after saving template: %v\n", err)
 		}
@@ -571,8 +982,20 @@ func (s *Service) SaveTemplate(template *models.Template) error {
 	return nil
 }
 
+// archiveTemplate saves a copy of a template's current version in the
+// template archive, named by ID and version like archived prompts
+func (s *Service) archiveTemplate(template *models.Template) error {
+	archived := *template
+	archived.FilePath = filepath.Join(storage.TemplateArchiveDir, fmt.Sprintf("%s-v%s.md", template.ID, template.Version))
+	return s.storage.SaveTemplate(&archived)
+}
+
 // DeleteTemplate deletes a template by ID
 func (s *Service) DeleteTemplate(id string) error {
+	if lib := s.templateLibraryFor(id); lib != s {
+		return lib.DeleteTemplate(id)
+	}
+
 	template, err := s.GetTemplate(id)
 	if err != nil {
 		return err
@@ -585,7 +1008,7 @@ func (s *Service) DeleteTemplate(id string) error {
 
 	// Sync to git if enabled
 	if s.gitSync.IsEnabled() {
-		if err := s.gitSync.SyncChanges(fmt.Sprintf("Delete template: %s", template.Name)); err != nil {
+		if err := s.gitSync.SyncCommit(s.commitMessage(config.CommitDeleteTemplate, template.ID, template.Name, template.Version)); err != nil {
 			// Don't fail the operation if git sync fails, just log it
 			fmt.Printf("Warning: Git sync failed after deleting template: %v\n", err)
 		}
@@ -601,6 +1024,11 @@ func (s *Service) IsGitSyncEnabled() bool {
 	return s.gitSync.IsEnabled()
 }
 
+// Settings returns the library configuration loaded from .pocket-prompt/config.json
+func (s *Service) Settings() *config.Config {
+	return s.settings
+}
+
 // GetBaseDir returns the base directory for the prompt library
 func (s *Service) GetBaseDir() string {
 	return s.storage.GetBaseDir()
@@ -611,8 +1039,11 @@ func (s *Service) GetGitSyncStatus() (string, error) {
 	return s.gitSync.GetStatus()
 }
 
-// EnableGitSync enables git synchronization
+// EnableGitSync enables git synchronization, except for a read-only library
 func (s *Service) EnableGitSync() {
+	if s.ReadOnly() {
+		return
+	}
 	s.gitSync.Enable()
 }
 
@@ -623,25 +1054,27 @@ func (s *Service) DisableGitSync() {
 
 // AutoPullOnStartup pulls latest changes from remote without checking enabled flag
 func (s *Service) AutoPullOnStartup() error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
 	return s.gitSync.AutoPullOnStartup()
 }
 
-// StartBackgroundSync starts background git synchronization
-func (s *Service) StartBackgroundSync(ctx context.Context, interval time.Duration) {
-	s.gitSync.BackgroundSync(ctx, interval)
-}
-
 // SetupGitRepository configures Git sync with the provided repository URL
-func (s *Service) SetupGitRepository(repoURL string) error {
+func (s *Service) SetupGitRepository(repoURL string, opts git.SetupOptions) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
 	// Setup the repository
-	if err := s.gitSync.SetupRepository(repoURL); err != nil {
+	if err := s.gitSync.SetupRepository(repoURL, opts); err != nil {
 		return fmt.Errorf("failed to setup Git repository: %w", err)
 	}
+	s.storage.SetCheckoutPaths(s.gitSync.SparsePaths())
 	
 	// If successful, start background sync
 	if s.gitSync.IsEnabled() {
 		ctx := context.Background()
-		go s.gitSync.BackgroundSync(ctx, 5*time.Minute)
+		go s.StartBackgroundSync(ctx, s.settings.Git.Interval(5*time.Minute))
 	}
 	
 	// Perform initial sync
@@ -653,18 +1086,56 @@ func (s *Service) SetupGitRepository(repoURL string) error {
 	return nil
 }
 
-// PullGitChanges manually pulls changes from remote repository
+// EnableGitLFS tracks attachments with Git LFS in an existing repository and
+// commits the updated .gitattributes
+func (s *Service) EnableGitLFS() error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	if !s.gitSync.IsInitialized() {
+		return fmt.Errorf("git is not initialized in %s; run 'pkt git setup <url> --lfs'", s.GetBaseDir())
+	}
+	if err := s.gitSync.EnableLFS(); err != nil {
+		return err
+	}
+	if _, err := s.gitSync.CommitPaths("Track attachments with Git LFS", ".gitattributes"); err != nil {
+		return fmt.Errorf("failed to commit .gitattributes: %w", err)
+	}
+	return nil
+}
+
+// PullGitChanges pulls changes from the remote repository and updates the
+// prompt cache with the prompts they touched
 func (s *Service) PullGitChanges() error {
+	_, err := s.pullGitChanges()
+	return err
+}
+
+// pullGitChanges pulls from the remote and returns the prompts the pull changed
+func (s *Service) pullGitChanges() ([]PromptEvent, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
 	if !s.gitSync.IsEnabled() {
-		return fmt.Errorf("git sync is not enabled")
+		return nil, fmt.Errorf("git sync is not enabled")
 	}
-	
+
+	// Load the cache first, so deleted prompts can be reported by ID
+	if len(s.cachedPrompts()) == 0 {
+		if err := s.loadPrompts(); err != nil {
+			return nil, err
+		}
+	}
+	track := s.TrackSubscriptions(MatchFromSync)
+	before := s.gitSync.HeadCommit()
 	if err := s.gitSync.PullChanges(); err != nil {
-		return fmt.Errorf("failed to pull changes: %w", err)
+		return nil, fmt.Errorf("failed to pull changes: %w", err)
 	}
-	
-	// Reload prompts cache after pulling changes
-	return s.loadPrompts()
+	changes, err := s.applyPull(before)
+	if err == nil {
+		track()
+	}
+	return changes, err
 }
 
 // CheckForGitChanges fetches from remote and checks if there are changes to pull
@@ -703,24 +1174,107 @@ func (s *Service) PullGitChangesIfNeeded() (bool, error) {
 	return true, nil // Successfully pulled changes
 }
 
-// ForceGitSync attempts to re-enable git sync and recover from errors
-func (s *Service) ForceGitSync() error {
-	// Try to initialize git sync again
-	if err := s.gitSync.Initialize(); err != nil {
-		return fmt.Errorf("failed to initialize git sync: %w", err)
+// ForceGitSync attempts to re-enable git sync and recover from errors
+func (s *Service) ForceGitSync() error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	// Try to initialize git sync again
+	if err := s.gitSync.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize git sync: %w", err)
+	}
+	
+	// If successful, start background sync
+	if s.gitSync.IsEnabled() {
+		ctx := context.Background()
+		go s.StartBackgroundSync(ctx, s.settings.Git.Interval(5*time.Minute))
+	}
+	
+	return nil
+}
+
+// GitBranches returns the checked-out branch, the configured working branch
+// ("" if none), and the main branch pull requests target
+func (s *Service) GitBranches() (current, working, main string) {
+	return s.gitSync.CurrentBranch(), s.gitSync.WorkingBranch(), s.gitSync.MainBranch()
+}
+
+// SetWorkingBranch syncs edits to the named branch from now on and switches
+// to it. An empty name stops using a working branch and returns to the main
+// branch.
+func (s *Service) SetWorkingBranch(name string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	if !s.gitSync.IsInitialized() {
+		return fmt.Errorf("git is not initialized in %s", s.GetBaseDir())
+	}
+	if err := s.gitSync.SetWorkingBranch(name); err != nil {
+		return err
+	}
+
+	if name == "" {
+		if mainBranch := s.gitSync.MainBranch(); s.gitSync.CurrentBranch() != mainBranch && s.gitSync.BranchExists(mainBranch) {
+			if err := s.gitSync.SwitchBranch(mainBranch); err != nil {
+				return fmt.Errorf("failed to switch to %s: %w", mainBranch, err)
+			}
+		}
+	} else if err := s.gitSync.CheckoutWorkingBranch(); err != nil {
+		return fmt.Errorf("failed to switch to %s: %w", name, err)
+	}
+	return s.loadPrompts()
+}
+
+// SetMainBranch saves the branch pull requests target in the library settings
+func (s *Service) SetMainBranch(name string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	s.settings.Git.MainBranch = name
+	if err := s.settings.Save(); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	s.gitSync.SetMainBranch(name)
+	return nil
+}
+
+// OpenPullRequest commits outstanding edits on the working branch, pushes it,
+// and opens a pull request into the main branch. Without the gh CLI it returns
+// git.ErrGHNotFound after pushing, so the caller can point at CompareURL.
+func (s *Service) OpenPullRequest(title, body string, draft bool) (*git.PullRequest, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
 	}
-	
-	// If successful, start background sync
-	if s.gitSync.IsEnabled() {
-		ctx := context.Background()
-		go s.gitSync.BackgroundSync(ctx, 5*time.Minute)
+	if !s.gitSync.IsInitialized() {
+		return nil, fmt.Errorf("git is not initialized in %s", s.GetBaseDir())
 	}
-	
-	return nil
+	if err := s.gitSync.CheckoutWorkingBranch(); err != nil {
+		return nil, fmt.Errorf("failed to switch to working branch: %w", err)
+	}
+	current, _, mainBranch := s.GitBranches()
+	if current == mainBranch {
+		return nil, fmt.Errorf("on the main branch %s; set a working branch with 'pkt git branch <name>'", mainBranch)
+	}
+
+	if _, err := s.gitSync.CommitChanges(fmt.Sprintf("Update prompts on %s", current)); err != nil {
+		return nil, err
+	}
+	if err := s.gitSync.PushBranch(); err != nil {
+		return nil, fmt.Errorf("failed to push %s: %w", current, err)
+	}
+	return s.gitSync.OpenPullRequest(title, body, draft)
+}
+
+// PullRequestCompareURL returns the GitHub page for opening a pull request by hand
+func (s *Service) PullRequestCompareURL() string {
+	return s.gitSync.CompareURL()
 }
 
 // SyncChanges manually triggers a Git sync
 func (s *Service) SyncChanges(message string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
 	if !s.gitSync.IsEnabled() {
 		return fmt.Errorf("git sync is not enabled")
 	}
@@ -728,6 +1282,33 @@ func (s *Service) SyncChanges(message string) error {
 	return s.gitSync.SyncChanges(message)
 }
 
+// MigrateLibrary upgrades all prompt files to the current schema version.
+// When commit is true and the library is a git repository, the result is committed locally.
+func (s *Service) MigrateLibrary(dryRun bool, commit bool) (*storage.MigrationReport, error) {
+	report, err := s.storage.MigratePrompts(dryRun)
+	if err != nil {
+		return report, err
+	}
+
+	if dryRun || len(report.Changes) == 0 {
+		return report, nil
+	}
+
+	// Reload prompts cache so moved archive files disappear from listings
+	if err := s.loadPrompts(); err != nil {
+		report.Errors = append(report.Errors, fmt.Errorf("failed to refresh prompts cache: %w", err))
+	}
+
+	if commit && s.gitSync.IsInitialized() {
+		message := fmt.Sprintf("Migrate %d prompt files to schema v%d", len(report.Changes), models.CurrentSchemaVersion)
+		if _, err := s.gitSync.CommitChanges(message); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("git commit failed after migration: %w", err))
+		}
+	}
+
+	return report, nil
+}
+
 // archivePromptByTag archives a prompt by moving it to the archive folder
 func (s *Service) archivePromptByTag(prompt *models.Prompt) error {
 	// Create a copy of the prompt for archiving
@@ -792,46 +1373,75 @@ func (s *Service) ListArchivedPrompts() ([]*models.Prompt, error) {
 
 // SearchPromptsByBooleanExpression searches prompts using a boolean expression
 func (s *Service) SearchPromptsByBooleanExpression(expression *models.BooleanExpression) ([]*models.Prompt, error) {
-	prompts, err := s.ListPrompts()
-	if err != nil {
-		return nil, err
-	}
-
 	if expression == nil {
-		return prompts, nil
+		return s.ListPrompts()
+	}
+	if err := s.ensurePrompts(); err != nil {
+		return nil, err
 	}
+	defer tracing.Begin("search")()
+	start := time.Now()
+
+	results := s.matchPrompts(expression)
+	s.logIfSlow(storage.SlowQuery{
+		Kind:       "boolean",
+		Query:      expression.QueryString(),
+		Prompts:    len(s.cachedPrompts()),
+		Results:    len(results),
+		Complexity: expression.Complexity(),
+	}, start)
+	return results, nil
+}
 
+// matchPrompts returns the listed prompts whose tags satisfy expression, in
+// listing order. Using the tag index, it visits only the prompts carrying the
+// tags expression names, unless expression matches by the absence of a tag.
+func (s *Service) matchPrompts(expression *models.BooleanExpression) []*models.Prompt {
 	var results []*models.Prompt
-	for _, prompt := range prompts {
-		if expression.Evaluate(prompt.Tags) {
+	for _, prompt := range s.cachedIndex().match(expression) {
+		if !s.isArchived(prompt) && prompt.IsApproved() {
 			results = append(results, prompt)
 		}
 	}
-
-	return results, nil
+	return results
 }
 
 // Saved Search Methods
 
-// ListSavedSearches returns all saved boolean searches
+// ListSavedSearches returns all saved boolean searches: the library's own,
+// then those installed packs ship
 func (s *Service) ListSavedSearches() ([]models.SavedSearch, error) {
-	return s.savedSearches.LoadSavedSearches()
+	searches, err := s.savedSearches.LoadSavedSearches()
+	if err != nil {
+		return nil, err
+	}
+	return append(searches, s.packSearches()...), nil
 }
 
-// GetSavedSearch retrieves a saved search by name
+// GetSavedSearch retrieves a saved search by name, from the library or, for
+// names such as team/needs-review, from an installed pack
 func (s *Service) GetSavedSearch(name string) (*models.SavedSearch, error) {
-	return s.savedSearches.GetSavedSearch(name)
+	search, err := s.savedSearches.GetSavedSearch(name)
+	if err != nil {
+		if packSearch, ok := s.packSearch(name); ok {
+			return packSearch, nil
+		}
+	}
+	return search, err
 }
 
 // SaveBooleanSearch saves a new boolean search
 func (s *Service) SaveBooleanSearch(search models.SavedSearch) error {
+	if packSearch, ok := s.packSearch(search.Name); ok {
+		return readOnlyPackSearch(packSearch)
+	}
 	if err := s.savedSearches.AddSavedSearch(search); err != nil {
 		return err
 	}
 
 	// Sync to git if enabled
 	if s.gitSync.IsEnabled() {
-		if err := s.gitSync.SyncChanges(fmt.Sprintf("Save boolean search: %s", search.Name)); err != nil {
+		if err := s.gitSync.SyncCommit(s.commitMessage(config.CommitSaveSearch, search.Name, search.Name, "")); err != nil {
 			// Don't fail the operation if git sync fails, just log it
 			fmt.Printf("Warning: Git sync failed after saving boolean search: %v\n", err)
 		}
@@ -842,13 +1452,18 @@ func (s *Service) SaveBooleanSearch(search models.SavedSearch) error {
 
 // DeleteSavedSearch removes a saved search by name
 func (s *Service) DeleteSavedSearch(name string) error {
+	if packSearch, ok := s.packSearch(name); ok {
+		if _, err := s.savedSearches.GetSavedSearch(name); err != nil {
+			return readOnlyPackSearch(packSearch)
+		}
+	}
 	if err := s.savedSearches.DeleteSavedSearch(name); err != nil {
 		return err
 	}
 
 	// Sync to git if enabled
 	if s.gitSync.IsEnabled() {
-		if err := s.gitSync.SyncChanges(fmt.Sprintf("Delete boolean search: %s", name)); err != nil {
+		if err := s.gitSync.SyncCommit(s.commitMessage(config.CommitDeleteSearch, name, name, "")); err != nil {
 			// Don't fail the operation if git sync fails, just log it
 			fmt.Printf("Warning: Git sync failed after deleting boolean search: %v\n", err)
 		}
@@ -857,6 +1472,51 @@ func (s *Service) DeleteSavedSearch(name string) error {
 	return nil
 }
 
+// PinnedSearch returns the name of the saved search applied when the TUI
+// opens and when 'pkt list' runs without flags, or "" when none is pinned
+func (s *Service) PinnedSearch() string {
+	return s.settings.UI.PinnedSearch
+}
+
+// SetPinnedSearch pins a saved search, or unpins with "", and saves the setting
+func (s *Service) SetPinnedSearch(name string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	if name != "" {
+		if _, err := s.GetSavedSearch(name); err != nil {
+			return err
+		}
+	}
+	s.settings.UI.PinnedSearch = name
+	if err := s.settings.Save(); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return nil
+}
+
+// SessionState returns where the TUI was last closed, or nil when session
+// state is turned off or none has been saved
+func (s *Service) SessionState() (*storage.SessionState, error) {
+	if s.settings.UI.NoSessionState {
+		return nil, nil
+	}
+	state, err := storage.LoadSessionState(s.GetBaseDir())
+	if err != nil || *state == (storage.SessionState{}) {
+		return nil, err
+	}
+	return state, nil
+}
+
+// SaveSessionState records where the TUI was closed. It does nothing when
+// session state is turned off or the library is read-only.
+func (s *Service) SaveSessionState(state *storage.SessionState) error {
+	if s.settings.UI.NoSessionState || s.ReadOnly() {
+		return nil
+	}
+	return storage.SaveSessionState(s.GetBaseDir(), state)
+}
+
 // ExecuteSavedSearch executes a saved search by name
 func (s *Service) ExecuteSavedSearch(name string) ([]*models.Prompt, error) {
 	return s.ExecuteSavedSearchWithText(name, "")
@@ -868,12 +1528,10 @@ func (s *Service) ExecuteSavedSearchWithText(name string, textQueryOverride stri
 	if err != nil {
 		return nil, err
 	}
-
-	// First apply boolean expression filter
-	results, err := s.SearchPromptsByBooleanExpression(savedSearch.Expression)
-	if err != nil {
+	if err := s.ensurePrompts(); err != nil {
 		return nil, err
 	}
+	start := time.Now()
 
 	// Determine which text query to use
 	textQuery := textQueryOverride
@@ -881,13 +1539,26 @@ func (s *Service) ExecuteSavedSearchWithText(name string, textQueryOverride stri
 		textQuery = savedSearch.TextQuery
 	}
 
-	// If no text query, return boolean filter results
+	results := s.runSavedSearch(savedSearch, textQuery)
+	s.logIfSlow(storage.SlowQuery{
+		Kind:        "saved-search",
+		Query:       savedSearch.Expression.QueryString(),
+		SavedSearch: name,
+		Prompts:     len(s.cachedPrompts()),
+		Results:     len(results),
+		Complexity:  savedSearch.Expression.Complexity() + len(strings.Fields(textQuery)),
+	}, start)
+	return results, nil
+}
+
+// runSavedSearch applies a saved search's boolean expression to the listed
+// prompts, then fuzzy matches textQuery, if any, against the prompts left
+func (s *Service) runSavedSearch(savedSearch *models.SavedSearch, textQuery string) []*models.Prompt {
+	results := s.matchPrompts(savedSearch.Expression)
 	if textQuery == "" {
-		return results, nil
+		return results
 	}
-
-	// Apply text search filter on the boolean results
-	return s.filterPromptsByText(results, textQuery), nil
+	return s.filterPromptsByText(results, textQuery)
 }
 
 // filterPromptsByText filters prompts using fuzzy text search
@@ -895,44 +1566,27 @@ func (s *Service) filterPromptsByText(prompts []*models.Prompt, query string) []
 	if query == "" {
 		return prompts
 	}
-
-	// Create searchable strings for each prompt
-	var searchStrings []string
-	for _, p := range prompts {
-		searchStr := fmt.Sprintf("%s %s %s %s", 
-			p.Name, 
-			p.Summary,
-			strings.Join(p.Tags, " "),
-			p.Content,
-		)
-		searchStrings = append(searchStrings, searchStr)
-	}
-
-	// Perform fuzzy search
-	matches := fuzzy.Find(query, searchStrings)
-	
-	// Build results from matches
-	var results []*models.Prompt
-	for _, match := range matches {
-		results = append(results, prompts[match.Index])
-	}
-
-	return results
+	return s.fuzzyMatch(prompts, query)
 }
 
 // Claude Code Import Methods
 
 // ImportFromClaudeCode imports commands, workflows, and configurations from Claude Code installations
 func (s *Service) ImportFromClaudeCode(options importer.ImportOptions) (*importer.ImportResult, error) {
+	if err := s.checkImportPack(options.Pack); err != nil {
+		return nil, err
+	}
 	claudeImporter := importer.NewClaudeCodeImporter(s.storage.GetBaseDir())
 	
 	result, err := claudeImporter.Import(options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to import from Claude Code: %w", err)
 	}
+	result.ApplySelection(options)
 
 	// Save imported items to storage if not a dry run
 	if !options.DryRun {
+		track := s.TrackSubscriptions(MatchFromImport)
 		// Save prompts (agents, commands) and workflows
 		allPrompts := append(result.Prompts, result.Workflows...)
 		
@@ -946,6 +1600,7 @@ func (s *Service) ImportFromClaudeCode(options importer.ImportOptions) (*importe
 		if err := s.loadPrompts(); err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("failed to refresh prompts cache: %w", err))
 		}
+		track()
 
 		// Sync to git if enabled and no errors occurred
 		if s.gitSync.IsEnabled() && len(result.Errors) == 0 {
@@ -971,15 +1626,20 @@ func (s *Service) PreviewClaudeCodeImport(options importer.ImportOptions) (*impo
 
 // ImportFromGitRepository imports prompts and templates from a git repository
 func (s *Service) ImportFromGitRepository(options importer.GitImportOptions) (*importer.GitImportResult, error) {
+	if err := s.checkImportPack(options.Pack); err != nil {
+		return nil, err
+	}
 	gitImporter := importer.NewGitRepoImporter(s.storage.GetBaseDir())
 	
 	result, err := gitImporter.ImportFromGitRepo(options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to import from git repository: %w", err)
 	}
+	result.ApplySelection(options.ImportOptions)
 
 	// Save imported items to storage if not a dry run
 	if !options.DryRun {
+		track := s.TrackSubscriptions(MatchFromImport)
 		// Save prompts
 		for _, prompt := range result.Prompts {
 			if err := s.savePromptWithGitConflictResolution(prompt, options); err != nil {
@@ -998,6 +1658,7 @@ func (s *Service) ImportFromGitRepository(options importer.GitImportOptions) (*i
 		if err := s.loadPrompts(); err != nil {
 			result.Errors = append(result.Errors, fmt.Errorf("failed to refresh prompts cache: %w", err))
 		}
+		track()
 
 		// Sync to git if enabled and no errors occurred
 		if s.gitSync.IsEnabled() && len(result.Errors) == 0 {
@@ -1021,6 +1682,290 @@ func (s *Service) PreviewGitRepositoryImport(options importer.GitImportOptions)
 	return gitImporter.ImportFromGitRepo(options)
 }
 
+// ImportFromMarkdown imports the notes of a Markdown folder, such as an
+// Obsidian vault, as prompts
+func (s *Service) ImportFromMarkdown(options importer.MarkdownImportOptions) (*importer.ImportResult, error) {
+	if err := s.checkImportPack(options.Pack); err != nil {
+		return nil, err
+	}
+	markdownImporter := importer.NewMarkdownDirImporter(s.storage.GetBaseDir())
+
+	result, err := markdownImporter.Import(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import from %s: %w", options.Path, err)
+	}
+	result.ApplySelection(options.ImportOptions)
+
+	// Save imported items to storage if not a dry run
+	if !options.DryRun {
+		track := s.TrackSubscriptions(MatchFromImport)
+		for _, prompt := range result.Prompts {
+			if err := s.savePromptWithConflictResolution(prompt, options.ImportOptions); err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("failed to save prompt %s: %w", prompt.ID, err))
+			}
+		}
+
+		// Refresh the prompts cache after import
+		if err := s.loadPrompts(); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to refresh prompts cache: %w", err))
+		}
+		track()
+
+		// Sync to git if enabled and no errors occurred
+		if s.gitSync.IsEnabled() && len(result.Errors) == 0 {
+			commitMessage := fmt.Sprintf("Import from %s: %d prompts", options.Path, len(result.Prompts))
+			if err := s.gitSync.SyncChanges(commitMessage); err != nil {
+				// Don't fail the operation if git sync fails
+				result.Errors = append(result.Errors, fmt.Errorf("git sync failed after import: %w", err))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ImportFromRegistry imports a PromptLayer or Langfuse export, keeping older
+// registry revisions as archived versions
+func (s *Service) ImportFromRegistry(options importer.RegistryImportOptions) (*importer.RegistryImportResult, error) {
+	if err := s.checkImportPack(options.Pack); err != nil {
+		return nil, err
+	}
+	registryImporter := importer.NewRegistryImporter(s.storage.GetBaseDir())
+
+	result, err := registryImporter.Import(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import from %s: %w", options.Registry, err)
+	}
+	result.ApplySelection(options.ImportOptions)
+
+	// Save imported items to storage if not a dry run
+	if !options.DryRun {
+		track := s.TrackSubscriptions(MatchFromImport)
+		// A prompt left as it was, by --skip-existing or a conflict, keeps
+		// its own history too, so its registry revisions are not archived
+		skipped := make(map[string]bool)
+		for _, prompt := range result.Prompts {
+			if options.SkipExisting {
+				if _, err := s.GetPrompt(prompt.ID); err == nil {
+					skipped[prompt.ID] = true
+					continue
+				}
+			}
+			if err := s.savePromptWithConflictResolution(prompt, options.ImportOptions); err != nil {
+				skipped[prompt.ID] = true
+				result.Errors = append(result.Errors, fmt.Errorf("failed to save prompt %s: %w", prompt.ID, err))
+			}
+		}
+		result.Archived = slices.DeleteFunc(result.Archived, func(prompt *models.Prompt) bool {
+			return skipped[prompt.ID]
+		})
+
+		// Archived revisions are immutable history; never overwrite an existing one
+		for _, prompt := range result.Archived {
+			if _, err := os.Stat(filepath.Join(s.storage.GetBaseDir(), prompt.FilePath)); err == nil {
+				continue
+			}
+			if err := s.storage.SavePrompt(prompt); err != nil {
+				result.Errors = append(result.Errors, fmt.Errorf("failed to save archived version %s v%s: %w", prompt.ID, prompt.Version, err))
+			}
+		}
+
+		// Refresh the prompts cache after import
+		if err := s.loadPrompts(); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to refresh prompts cache: %w", err))
+		}
+		track()
+
+		// Sync to git if enabled and no errors occurred
+		if s.gitSync.IsEnabled() && len(result.Errors) == 0 {
+			commitMessage := fmt.Sprintf("Import from %s: %d prompts, %d archived versions",
+				options.Registry, len(result.Prompts), len(result.Archived))
+
+			if err := s.gitSync.SyncChanges(commitMessage); err != nil {
+				// Don't fail the operation if git sync fails
+				result.Errors = append(result.Errors, fmt.Errorf("git sync failed after import: %w", err))
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// SyncRemote pushes and pulls prompts between the library and the registry
+// configured under "remote" in the library settings. direction is one of
+// remote.DirectionPull, DirectionPush or DirectionBoth; prefer ("local" or
+// "remote") resolves prompts that changed on both sides since the last sync.
+func (s *Service) SyncRemote(direction string, dryRun bool, prefer string) (*remote.Report, error) {
+	if !dryRun && s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	cfg := s.settings.Remote
+	adapter, err := remote.NewAdapter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := remote.LoadState(s.storage.GetBaseDir())
+	if err != nil {
+		return nil, err
+	}
+	track := func() {}
+	if !dryRun {
+		track = s.TrackSubscriptions(MatchFromRemote)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	remotes, err := adapter.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s prompts: %w", adapter.Name(), err)
+	}
+
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only prompts carrying the configured tag (if any) take part in the sync
+	localByName := make(map[string]*models.Prompt)
+	var locals []remote.Local
+	for _, p := range prompts {
+		if cfg.Tag != "" && !models.ContainsTag(p.Tags, cfg.Tag) {
+			continue
+		}
+		full, err := s.GetPrompt(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		name := remoteName(full)
+		localByName[name] = full
+		locals = append(locals, remote.Local{
+			ID:         full.ID,
+			RemoteName: name,
+			Hash:       remote.ContentHash(full.Content, full.StoredTags()),
+		})
+	}
+
+	items, err := remote.Plan(locals, remotes, state, direction, prefer)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &remote.Report{
+		Adapter:   adapter.Name(),
+		Direction: direction,
+		DryRun:    dryRun,
+		Items:     items,
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	changed := false
+	for _, item := range items {
+		switch item.Action {
+		case remote.ActionPush:
+			local := localByName[item.Name]
+			tags := local.StoredTags()
+			stored, err := adapter.Push(ctx, remote.Prompt{
+				Name:    item.Name,
+				Content: local.Content,
+				Tags:    tags,
+				Version: local.Version,
+			})
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Errorf("failed to push %s: %w", item.Name, err))
+				continue
+			}
+			state.Record(item.Name, remote.ContentHash(local.Content, tags), stored.Hash(), stored.UpdatedAt)
+
+		case remote.ActionPull:
+			saved, err := s.pullRemotePrompt(localByName[item.Name], item.Remote, adapter.Name())
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Errorf("failed to pull %s: %w", item.Name, err))
+				continue
+			}
+			changed = true
+			state.Record(item.Name, remote.ContentHash(saved.Content, saved.StoredTags()), item.Remote.Hash(), item.Remote.UpdatedAt)
+
+		case remote.ActionInSync:
+			if local := localByName[item.Name]; local != nil {
+				state.Record(item.Name, remote.ContentHash(local.Content, local.StoredTags()), item.Remote.Hash(), item.Remote.UpdatedAt)
+			}
+		}
+	}
+
+	if err := state.Save(); err != nil {
+		report.Errors = append(report.Errors, err)
+	}
+	if changed {
+		if err := s.loadPrompts(); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("failed to refresh prompts cache: %w", err))
+		}
+		track()
+	}
+
+	return report, nil
+}
+
+// pullRemotePrompt writes a registry prompt into the library, updating the
+// matching local prompt or creating a new one. The registry's tags are added
+// to the prompt's own, and the remote.tag filter is always kept, or the next
+// sync would no longer see the prompt and pull it again as a new one.
+func (s *Service) pullRemotePrompt(local *models.Prompt, rp *remote.Prompt, source string) (*models.Prompt, error) {
+	if local != nil {
+		updated := *local
+		updated.Content = rp.Content
+		updated.Tags = s.withRemoteTag(mergeTags(local.StoredTags(), rp.Tags))
+		updated.DerivedTags = nil
+		if err := s.UpdatePrompt(&updated); err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	}
+
+	prompt := &models.Prompt{
+		ID:      importer.RegistryPromptID(rp.Name),
+		Version: "1.0.0",
+		Name:    rp.Name,
+		Content: rp.Content,
+		Tags:    s.withRemoteTag(rp.Tags),
+		Metadata: map[string]interface{}{
+			"source":        source,
+			"registry_name": rp.Name,
+		},
+	}
+	if err := s.CreatePrompt(prompt); err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
+
+// remoteName returns the registry name a prompt syncs under
+func remoteName(prompt *models.Prompt) string {
+	if name, ok := prompt.Metadata["registry_name"].(string); ok && name != "" {
+		return name
+	}
+	return prompt.ID
+}
+
+// withRemoteTag returns tags with the remote.tag filter added if missing
+func (s *Service) withRemoteTag(tags []string) []string {
+	return mergeTags(tags, []string{s.settings.Remote.Tag})
+}
+
+// mergeTags returns tags followed by the extra tags it doesn't have yet
+func mergeTags(tags, extra []string) []string {
+	merged := append([]string{}, tags...)
+	for _, tag := range extra {
+		if tag != "" && !models.ContainsTag(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
 // savePromptWithConflictResolution handles conflict resolution when saving imported prompts
 func (s *Service) savePromptWithConflictResolution(prompt *models.Prompt, options importer.ImportOptions) error {
 	// Check if prompt already exists
@@ -1040,6 +1985,9 @@ func (s *Service) savePromptWithConflictResolution(prompt *models.Prompt, option
 		if options.SkipExisting {
 			return nil // Skip without error even if content changed
 		}
+		if err := s.CheckProtected(existing); err != nil {
+			return err
+		}
 		
 		if options.DeduplicateByPath {
 			// Check if it's the same source file
@@ -1080,9 +2028,33 @@ func (s *Service) savePromptWithConflictResolution(prompt *models.Prompt, option
 		prompt.CreatedAt = existing.CreatedAt
 		prompt.UpdatedAt = time.Now()
 		prompt.FilePath = existing.FilePath // Keep the same file path
+		return s.storage.SavePrompt(prompt)
 	}
-	
-	return s.storage.SavePrompt(prompt)
+
+	return s.saveNewImportedPrompt(prompt, options.Pack)
+}
+
+// saveNewImportedPrompt saves a prompt an import brings in for the first
+// time. When the import targets a pack, the prompt goes into the pack's
+// prompts folder and is listed in its pack.json.
+func (s *Service) saveNewImportedPrompt(prompt *models.Prompt, pack string) error {
+	if pack == "" || pack == "personal" {
+		return s.storage.SavePrompt(prompt)
+	}
+	prompt.FilePath = filepath.Join("packs", pack, "prompts", storage.PromptSubdir(prompt.FilePath), filepath.Base(prompt.FilePath))
+	prompt.Pack = pack
+	if err := s.storage.SavePrompt(prompt); err != nil {
+		return err
+	}
+	return s.packConfig.SetPromptListed(pack, prompt.ID, true)
+}
+
+// checkImportPack fails unless pack, when an import targets one, is installed
+func (s *Service) checkImportPack(pack string) error {
+	if pack != "" && pack != "personal" && !s.packConfig.IsPackInstalled(pack) {
+		return fmt.Errorf("pack '%s' is not installed (see pkt packs list)", pack)
+	}
+	return nil
 }
 
 // saveTemplateWithConflictResolution handles conflict resolution when saving imported templates
@@ -1149,6 +2121,9 @@ func (s *Service) savePromptWithGitConflictResolution(prompt *models.Prompt, opt
 		if options.SkipExisting {
 			return nil // Skip without error even if content changed
 		}
+		if err := s.CheckProtected(existing); err != nil {
+			return err
+		}
 		
 		if options.DeduplicateByPath {
 			// Check if it's the same source file
@@ -1189,9 +2164,10 @@ func (s *Service) savePromptWithGitConflictResolution(prompt *models.Prompt, opt
 		prompt.CreatedAt = existing.CreatedAt
 		prompt.UpdatedAt = time.Now()
 		prompt.FilePath = existing.FilePath // Keep the same file path
+		return s.storage.SavePrompt(prompt)
 	}
-	
-	return s.storage.SavePrompt(prompt)
+
+	return s.saveNewImportedPrompt(prompt, options.Pack)
 }
 
 // saveTemplateWithGitConflictResolution handles conflict resolution when saving imported templates from git repositories
@@ -1322,8 +2298,50 @@ func (s *Service) InstallPackFromDirectory(srcDir string, options config.PackIns
 	return installer.InstallFromDirectory(srcDir, options)
 }
 
+// InstallPack installs a pack from source, which is a Git URL or a local
+// directory, and returns it
+func (s *Service) InstallPack(source string, options config.PackInstallOptions) (*config.Pack, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") || strings.HasSuffix(source, ".git") {
+		err = s.InstallPackFromGit(source, options)
+	} else {
+		err = s.InstallPackFromDirectory(source, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The pack's name comes from its pack.json, so find the one just installed
+	packs := s.packConfig.ListPacks()
+	var installed *config.Pack
+	for i := range packs {
+		if installed == nil || packs[i].InstallTime.After(installed.InstallTime) {
+			installed = &packs[i]
+		}
+	}
+	if installed == nil {
+		return nil, fmt.Errorf("pack from %s was not recorded as installed", source)
+	}
+	return installed, nil
+}
+
+// UpdatePack pulls the latest version of a pack installed from Git
+func (s *Service) UpdatePack(name string) (*config.Pack, error) {
+	if s.ReadOnly() {
+		return nil, storage.ErrReadOnly
+	}
+	installer := config.NewPackInstaller(s.packConfig)
+	return installer.UpdatePack(name)
+}
+
 // UninstallPack removes a pack
 func (s *Service) UninstallPack(name string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
 	installer := config.NewPackInstaller(s.packConfig)
 	return installer.UninstallPack(name)
 }
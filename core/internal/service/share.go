@@ -0,0 +1,144 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/redact"
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+)
+
+// shareFrontmatter is the metadata a shared prompt carries. Library details
+// such as file paths, review state and secret sources are left out.
+type shareFrontmatter struct {
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Version     string   `yaml:"version,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	Language    string   `yaml:"language,omitempty"`
+	Pack        string   `yaml:"pack,omitempty"`
+	Updated     string   `yaml:"updated,omitempty"`
+}
+
+// SharePrompt formats a prompt as one self-contained Markdown document for
+// pasting into wikis, docs or emails: frontmatter with its metadata, its
+// content with any template applied and placeholders left for the reader,
+// a table of its variables and a footer saying where it came from.
+// Sensitive variables never show their defaults. With a redactor, the
+// library's redaction rules apply to the whole document.
+func (s *Service) SharePrompt(id string, redactor *redact.Redactor) (string, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return "", err
+	}
+	content, declared, err := s.documentContent(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	meta := shareFrontmatter{
+		ID:          prompt.ID,
+		Title:       prompt.Name,
+		Description: prompt.Summary,
+		Version:     prompt.Version,
+		Tags:        prompt.Tags,
+		Language:    prompt.Language,
+	}
+	// Only an installed pack says anything about where the prompt came from
+	if prompt.Pack != "personal" {
+		meta.Pack = prompt.Pack
+	}
+	if !prompt.UpdatedAt.IsZero() {
+		meta.Updated = prompt.UpdatedAt.Format("2006-01-02")
+	}
+
+	var doc strings.Builder
+	doc.WriteString("---\n")
+	encoder := yaml.NewEncoder(&doc)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(meta); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	doc.WriteString("---\n\n")
+	title := prompt.Name
+	if title == "" {
+		title = prompt.ID
+	}
+	fmt.Fprintf(&doc, "# %s\n\n", title)
+	if prompt.Summary != "" {
+		fmt.Fprintf(&doc, "%s\n\n", prompt.Summary)
+	}
+	fmt.Fprintf(&doc, "%s\n", strings.TrimRight(content, "\n"))
+
+	if len(declared) > 0 {
+		doc.WriteString("\n## Variables\n\n")
+		doc.WriteString(variablesTable(declared))
+	}
+
+	attribution := fmt.Sprintf("`%s`", prompt.ID)
+	if prompt.Version != "" {
+		attribution += " v" + prompt.Version
+	}
+	if meta.Pack != "" {
+		attribution += " from the " + meta.Pack + " pack"
+	}
+	fmt.Fprintf(&doc, "\n---\n\n_Shared from Pocket Prompt: %s_\n", attribution)
+
+	if redactor != nil {
+		return redactor.String(doc.String()), nil
+	}
+	return doc.String(), nil
+}
+
+// documentContent returns a prompt's content with any template applied, as
+// shared or printed: template slots without a default stay placeholders,
+// like the prompt's own. The variables it declares come with it.
+func (s *Service) documentContent(prompt *models.Prompt) (string, []models.Variable, error) {
+	var template *models.Template
+	if prompt.TemplateRef != "" {
+		template, _ = s.GetTemplate(prompt.TemplateRef)
+	}
+	placeholders := map[string]interface{}{}
+	if template != nil {
+		for _, slot := range template.Slots {
+			if slot.Default == "" {
+				placeholders[slot.Name] = "{{" + slot.Name + "}}"
+			}
+		}
+	}
+	content, err := renderer.NewRenderer(prompt, template).RenderText(placeholders)
+	if err != nil {
+		return "", nil, err
+	}
+	return content, s.DeclaredVariables(prompt, template), nil
+}
+
+// variablesTable lists variables as a Markdown table. Sensitive variables
+// never show their defaults.
+func variablesTable(declared []models.Variable) string {
+	var table strings.Builder
+	table.WriteString("| Name | Description | Default | Required |\n")
+	table.WriteString("| --- | --- | --- | --- |\n")
+	for _, v := range declared {
+		value := v.Default
+		if v.Sensitive && value != "" {
+			value = MaskedValue
+		}
+		required := ""
+		if v.Required {
+			required = "yes"
+		}
+		fmt.Fprintf(&table, "| `%s` | %s | %s | %s |\n", v.Name, tableCell(v.Description), tableCell(value), required)
+	}
+	return table.String()
+}
+
+// tableCell keeps text on one line of a Markdown table
+func tableCell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSharePrompt(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	template := &models.Template{
+		ID:      "framed",
+		Name:    "Framed",
+		Content: "You are {{.role}} writing for {{.audience}}.\n\n{{.content}}",
+		Slots: []models.Slot{
+			{Name: "role", Default: "an editor"},
+			{Name: "audience", Description: "Who reads it", Required: true},
+		},
+	}
+	if err := svc.SaveTemplate(template); err != nil {
+		t.Fatalf("SaveTemplate: %v", err)
+	}
+	prompt := &models.Prompt{
+		ID:          "release-notes",
+		Version:     "1.2.0",
+		Name:        "Release Notes",
+		Summary:     "Turn a changelog into notes",
+		Tags:        []string{"writing"},
+		TemplateRef: "framed",
+		Content:     "Summarize {{changes}} for {{product}}.",
+		Variables: []models.Variable{
+			{Name: "changes", Description: "The changelog | or commits", Required: true},
+			{Name: "product", Default: "Acme"},
+			{Name: "token", Default: "secret-value", Sensitive: true, Env: "ACME_TOKEN"},
+		},
+	}
+	if err := svc.CreatePrompt(prompt); err != nil {
+		t.Fatalf("CreatePrompt: %v", err)
+	}
+
+	doc, err := svc.SharePrompt("release-notes", nil)
+	if err != nil {
+		t.Fatalf("SharePrompt: %v", err)
+	}
+	for _, want := range []string{
+		"---\nid: release-notes\ntitle: Release Notes\ndescription: Turn a changelog into notes\nversion: 1.2.0\ntags:\n  - writing\n",
+		"# Release Notes\n\nTurn a changelog into notes\n\n",
+		"You are an editor writing for {{audience}}.\n\nSummarize {{changes}} for {{product}}.\n",
+		"| `changes` | The changelog \\| or commits |  | yes |\n",
+		"| `product` |  | Acme |  |\n",
+		"| `token` |  | " + MaskedValue + " |  |\n",
+		"| `audience` | Who reads it |  | yes |\n",
+		"_Shared from Pocket Prompt: `release-notes` v1.2.0_\n",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("shared document lacks %q:\n%s", want, doc)
+		}
+	}
+	for _, leak := range []string{"secret-value", "ACME_TOKEN"} {
+		if strings.Contains(doc, leak) {
+			t.Errorf("shared document reveals %q:\n%s", leak, doc)
+		}
+	}
+
+	if _, err := svc.SharePrompt("missing", nil); err == nil {
+		t.Error("SharePrompt of a missing prompt should fail")
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// PromptPath returns the absolute path of the file a prompt is stored in
+func (s *Service) PromptPath(id string) (string, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.Abs(filepath.Join(s.libraryFor(id).GetBaseDir(), prompt.FilePath))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", prompt.FilePath, err)
+	}
+	return path, nil
+}
+
+// PromptSource returns the contents of the file a prompt is stored in, for
+// editing by hand
+func (s *Service) PromptSource(id string) ([]byte, error) {
+	path, err := s.PromptPath(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// UpdatePromptSource saves an edited copy of a prompt's file as the prompt's
+// next version. The prompt keeps its ID and location.
+func (s *Service) UpdatePromptSource(id string, data []byte) (*models.Prompt, error) {
+	existing, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := s.libraryFor(id).storage.ParsePromptAt(existing.FilePath, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt file: %w", err)
+	}
+	if prompt.ID != existing.ID {
+		return nil, fmt.Errorf("the prompt ID cannot be changed by editing (was %q, now %q)", existing.ID, prompt.ID)
+	}
+	if prompt.Pack == "" {
+		prompt.Pack = existing.Pack
+	}
+	if err := s.UpdatePrompt(prompt); err != nil {
+		return nil, err
+	}
+	return prompt, nil
+}
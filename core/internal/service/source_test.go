@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestUpdatePromptSource(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "greet", Version: "1.0.0", Name: "Greet", Content: "Hello there"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	source, err := svc.PromptSource("greet")
+	if err != nil {
+		t.Fatalf("PromptSource: %v", err)
+	}
+	if !bytes.Contains(source, []byte("Hello there")) {
+		t.Fatalf("PromptSource = %q, want the prompt's file", source)
+	}
+
+	edited := bytes.Replace(source, []byte("Hello there"), []byte("Hello again"), 1)
+	prompt, err := svc.UpdatePromptSource("greet", edited)
+	if err != nil {
+		t.Fatalf("UpdatePromptSource: %v", err)
+	}
+	if prompt.Version != "1.0.1" {
+		t.Errorf("Version = %s, want 1.0.1", prompt.Version)
+	}
+	reloaded, err := svc.GetPrompt("greet")
+	if err != nil || strings.TrimSpace(reloaded.Content) != "Hello again" {
+		t.Fatalf("GetPrompt after edit = %+v, %v", reloaded, err)
+	}
+
+	renamed := bytes.Replace(edited, []byte("id: greet"), []byte("id: welcome"), 1)
+	if _, err := svc.UpdatePromptSource("greet", renamed); err == nil {
+		t.Error("UpdatePromptSource accepted a changed ID")
+	}
+}
+
+func TestPromptPath(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "greet", Name: "Greet", Content: "Hello there"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	path, err := svc.PromptPath("greet")
+	if err != nil {
+		t.Fatalf("PromptPath: %v", err)
+	}
+	if !filepath.IsAbs(path) || !strings.HasPrefix(path, tmpDir) {
+		t.Errorf("PromptPath = %q, want an absolute path in the library", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), "Hello there") {
+		t.Errorf("Expected the path to hold the prompt, read %q, %v", data, err)
+	}
+	if _, err := svc.PromptPath("missing"); err == nil {
+		t.Error("Expected an error for an unknown prompt")
+	}
+}
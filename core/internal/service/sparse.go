@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// SparseCheckout returns the directories of the library this clone checks
+// out, or nil when it checks out all of it
+func (s *Service) SparseCheckout() []string {
+	return s.storage.CheckoutPaths()
+}
+
+// SetSparseCheckout checks out only dirs of the library, or all of it again
+// with none, and reloads the prompts that came or went. Prompts and templates
+// outside the checkout cannot be saved, so everything this clone changes can
+// still be committed and pushed.
+func (s *Service) SetSparseCheckout(dirs []string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	if !s.gitSync.IsInitialized() {
+		return fmt.Errorf("git is not initialized in %s", s.GetBaseDir())
+	}
+	if err := s.gitSync.SetSparsePaths(dirs); err != nil {
+		return fmt.Errorf("failed to change sparse checkout: %w", err)
+	}
+	s.storage.SetCheckoutPaths(s.gitSync.SparsePaths())
+	return s.loadPrompts()
+}
+
+// ShallowClone reports whether the library's git history was cloned only in
+// part, which limits prompt history and restores
+func (s *Service) ShallowClone() bool {
+	return s.gitSync.IsInitialized() && s.gitSync.IsShallow()
+}
+
+// UnshallowGit fetches the rest of a shallow clone's history
+func (s *Service) UnshallowGit() error {
+	if !s.gitSync.IsInitialized() {
+		return fmt.Errorf("git is not initialized in %s", s.GetBaseDir())
+	}
+	if err := s.gitSync.Unshallow(); err != nil {
+		return fmt.Errorf("failed to fetch full history: %w", err)
+	}
+	return nil
+}
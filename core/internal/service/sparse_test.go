@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestSparseCheckout(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, path := range []string{"prompts/team-a/a.md", "prompts/team-b/b.md"} {
+		id := filepath.Base(path[:len(path)-3])
+		if err := os.MkdirAll(filepath.Join(tmpDir, filepath.Dir(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := "---\nid: " + id + "\ntitle: " + id + "\n---\nHello\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(name+"_NAME", "Test")
+		t.Setenv(name+"_EMAIL", "test@example.com")
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Initial")
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if dirs := svc.SparseCheckout(); dirs != nil {
+		t.Fatalf("SparseCheckout = %v, want the whole library", dirs)
+	}
+	if err := svc.SetSparseCheckout([]string{"prompts/team-a/"}); err != nil {
+		t.Fatalf("SetSparseCheckout: %v", err)
+	}
+	prompts, _ := svc.ListPrompts()
+	if len(prompts) != 1 || prompts[0].ID != "a" {
+		t.Fatalf("prompts = %d, want only a", len(prompts))
+	}
+
+	// Prompts outside the checkout cannot be saved; those in it and at the
+	// top of prompts/ can, and are committed
+	err = svc.CreatePrompt(&models.Prompt{ID: "b2", Name: "B2", Content: "x", FilePath: "prompts/team-b/b2.md"})
+	if !errors.Is(err, storage.ErrOutsideCheckout) {
+		t.Errorf("CreatePrompt outside the checkout: err = %v, want ErrOutsideCheckout", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a2", Name: "A2", Content: "x", FilePath: "prompts/team-a/a2.md"},
+		{ID: "top", Name: "Top", Content: "x"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Errorf("CreatePrompt %s: %v", p.ID, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "prompts", "team-b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "prompts", "team-b", "notes.txt"), []byte("stray"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if committed, err := svc.gitSync.CommitChanges("Add prompts"); err != nil || !committed {
+		t.Fatalf("CommitChanges = %v, %v; want the checked-out prompts committed", committed, err)
+	}
+
+	if err := svc.SetSparseCheckout(nil); err != nil {
+		t.Fatalf("SetSparseCheckout(nil): %v", err)
+	}
+	prompts, _ = svc.ListPrompts()
+	if len(prompts) != 4 {
+		t.Errorf("prompts = %d after checking out everything, want 4", len(prompts))
+	}
+}
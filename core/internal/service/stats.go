@@ -0,0 +1,221 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// PromptStats holds reporting metrics for one prompt
+type PromptStats struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Pack        string    `json:"pack,omitempty"`
+	Version     string    `json:"version"`
+	Versions    int       `json:"versions"` // The current version plus archived ones
+	Tokens      int       `json:"tokens"`   // Estimated, at about four characters per token
+	Words       int       `json:"words"`
+	Tags        []string  `json:"tags"`
+	ReviewState string    `json:"review_state"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UsageCount  int       `json:"usage_count"`
+	LastUsed    time.Time `json:"last_used"`
+}
+
+// statsColumns is the CSV header, in the order of csvRecord's fields
+var statsColumns = []string{
+	"id", "title", "pack", "version", "versions", "tokens", "words", "tags",
+	"review_state", "created_at", "updated_at", "usage_count", "last_used",
+}
+
+// RecordUsage counts one use of a prompt, such as a copy or render, and of
+// the template it uses, and records it in the analytics when uses are opted
+// into. Usage counts are best effort and never fail the caller.
+func (s *Service) RecordUsage(id string) {
+	s.usage.Record(id)
+	s.recordUse(id)
+	for _, p := range s.cachedPrompts() {
+		if p.ID == id && p.TemplateRef != "" {
+			s.templateUsage.Record(p.TemplateRef)
+			break
+		}
+	}
+}
+
+// LibraryStats returns metrics for every prompt in the library, sorted by ID
+func (s *Service) LibraryStats() ([]PromptStats, error) {
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.storage.ListArchivedPrompts()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.usage.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	archivedVersions := make(map[string]int)
+	for _, p := range archived {
+		archivedVersions[p.ID]++
+	}
+
+	stats := make([]PromptStats, 0, len(prompts))
+	for _, p := range prompts {
+		stats = append(stats, s.promptStats(p, archivedVersions[p.ID]+1, usage[p.ID]))
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ID < stats[j].ID })
+	return stats, nil
+}
+
+// PromptStats returns the metrics LibraryStats reports for one prompt
+func (s *Service) PromptStats(id string) (*PromptStats, error) {
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.storage.ListArchivedPrompts()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.usage.Load()
+	if err != nil {
+		return nil, err
+	}
+	versions := 1
+	for _, p := range archived {
+		if p.ID == id {
+			versions++
+		}
+	}
+	stats := s.promptStats(prompt, versions, usage[id])
+	return &stats, nil
+}
+
+// promptStats gathers the metrics for p given its version count and usage
+func (s *Service) promptStats(p *models.Prompt, versions int, usage storage.PromptUsage) PromptStats {
+	content := s.promptContent(p)
+	pack := p.Pack
+	if fromPath := storage.PackFromPath(p.FilePath); fromPath != "" {
+		pack = fromPath
+	}
+	return PromptStats{
+		ID:          p.ID,
+		Title:       p.Title(),
+		Pack:        pack,
+		Version:     p.Version,
+		Versions:    versions,
+		Tokens:      EstimateTokens(content),
+		Words:       len(strings.Fields(content)),
+		Tags:        p.Tags,
+		ReviewState: p.ReviewState(),
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		UsageCount:  usage.Count,
+		LastUsed:    usage.LastUsed,
+	}
+}
+
+// WriteStatsCSV writes stats as CSV with a header row. Tags are joined with
+// semicolons and unset times are left empty.
+func WriteStatsCSV(w io.Writer, stats []PromptStats) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(statsColumns); err != nil {
+		return err
+	}
+	for _, st := range stats {
+		if err := writer.Write(st.csvRecord()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (st PromptStats) csvRecord() []string {
+	return []string{
+		st.ID,
+		st.Title,
+		st.Pack,
+		st.Version,
+		strconv.Itoa(st.Versions),
+		strconv.Itoa(st.Tokens),
+		strconv.Itoa(st.Words),
+		strings.Join(st.Tags, ";"),
+		st.ReviewState,
+		formatStatsTime(st.CreatedAt),
+		formatStatsTime(st.UpdatedAt),
+		strconv.Itoa(st.UsageCount),
+		formatStatsTime(st.LastUsed),
+	}
+}
+
+func formatStatsTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// PromptTokens estimates a prompt's token count like LibraryStats does
+func (s *Service) PromptTokens(p *models.Prompt) int {
+	return EstimateTokens(s.promptContent(p))
+}
+
+// promptContent returns a prompt's content, loading it when the prompt came
+// from the metadata cache
+func (s *Service) promptContent(p *models.Prompt) string {
+	if p.Content == "" && p.FilePath != "" {
+		if full, err := s.loadPromptContent(p); err == nil {
+			return full.Content
+		}
+	}
+	return p.Content
+}
+
+// readingWordsPerMinute is the reading speed reading times assume
+const readingWordsPerMinute = 200
+
+// TextStats measures the length of a prompt's text, for prompts that must fit
+// a length limit
+type TextStats struct {
+	Words          int `json:"words"`
+	Characters     int `json:"characters"`
+	Lines          int `json:"lines"`
+	Tokens         int `json:"tokens"`          // Estimated, like PromptStats.Tokens
+	ReadingMinutes int `json:"reading_minutes"` // At 200 words a minute, rounded up
+}
+
+// MeasureText returns the length of text. A trailing newline does not start
+// another line.
+func MeasureText(text string) TextStats {
+	words := len(strings.Fields(text))
+	lines := 0
+	if trimmed := strings.TrimSuffix(text, "\n"); trimmed != "" {
+		lines = strings.Count(trimmed, "\n") + 1
+	}
+	return TextStats{
+		Words:          words,
+		Characters:     utf8.RuneCountInString(text),
+		Lines:          lines,
+		Tokens:         EstimateTokens(text),
+		ReadingMinutes: (words + readingWordsPerMinute - 1) / readingWordsPerMinute,
+	}
+}
+
+// EstimateTokens approximates a token count without a model-specific
+// tokenizer, using the rule of thumb of four characters per token
+func EstimateTokens(content string) int {
+	return (utf8.RuneCountInString(content) + 3) / 4
+}
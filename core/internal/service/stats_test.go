@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestLibraryStats(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "greet", Name: "Greet", Tags: []string{"a", "b"}, Content: "Say hello to the user"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	prompt, _ := svc.GetPrompt("greet")
+	updated := *prompt
+	updated.Content = "Say hello to the user politely"
+	if err := svc.UpdatePrompt(&updated); err != nil {
+		t.Fatalf("UpdatePrompt: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.RenderPrompt("greet", RenderOptions{Format: "text"}); err != nil {
+			t.Fatalf("RenderPrompt: %v", err)
+		}
+	}
+
+	stats, err := svc.LibraryStats()
+	if err != nil {
+		t.Fatalf("LibraryStats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("got %d prompts, want 1", len(stats))
+	}
+	st := stats[0]
+	if st.Versions != 2 || st.UsageCount != 2 || st.Words != 6 || st.Tokens != 8 {
+		t.Fatalf("stats = %+v, want 2 versions, 2 uses, 6 words, 8 tokens", st)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteStatsCSV(&buf, stats); err != nil {
+		t.Fatalf("WriteStatsCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,title,pack,") || !strings.Contains(lines[1], ",a;b,") {
+		t.Fatalf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestLibraryStatsPackFromCache(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := OpenLibrary(dir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "notes", Name: "Notes", Pack: "personal", Content: "Take notes"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	if _, err := svc.ListPrompts(); err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+
+	// A second run lists the prompt from the metadata cache
+	svc, err = OpenLibrary(dir)
+	if err != nil {
+		t.Fatalf("Failed to reopen library: %v", err)
+	}
+	stats, err := svc.LibraryStats()
+	if err != nil {
+		t.Fatalf("LibraryStats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Pack != "personal" {
+		t.Fatalf("stats = %+v, want the prompt in pack personal", stats)
+	}
+}
+
+func TestMeasureText(t *testing.T) {
+	text := "# Täsk\n\n" + strings.Repeat("word ", 250) + "\n"
+	got := MeasureText(text)
+	want := TextStats{Words: 252, Characters: 1259, Lines: 3, Tokens: 315, ReadingMinutes: 2}
+	if got != want {
+		t.Errorf("MeasureText = %+v, want %+v", got, want)
+	}
+	if got := MeasureText(""); got != (TextStats{}) {
+		t.Errorf("MeasureText of nothing = %+v", got)
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// Where the prompts that start matching a subscribed search came from
+const (
+	MatchFromSync   = "sync"   // A git pull
+	MatchFromImport = "import" // An import into the library
+	MatchFromRemote = "remote" // A pull from the prompt registry
+)
+
+// SubscriptionMatch is a prompt that started matching a subscribed search
+type SubscriptionMatch struct {
+	Prompt *models.Prompt `json:"prompt"`
+	Source string         `json:"source"`
+	At     time.Time      `json:"at"`
+}
+
+// SearchMatches are the unacknowledged new matches of one subscribed search
+type SearchMatches struct {
+	Search  string              `json:"search"`
+	Since   time.Time           `json:"since"`
+	Matches []SubscriptionMatch `json:"matches"`
+}
+
+// Subscribe follows a saved search, so prompts that git sync and imports
+// make match it from now on are reported as new
+func (s *Service) Subscribe(name string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	if _, err := s.GetSavedSearch(name); err != nil {
+		return err
+	}
+	subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	if _, ok := subs.Searches[name]; ok {
+		return nil
+	}
+	subs.Searches[name] = &storage.Subscription{Acknowledged: time.Now()}
+	return storage.SaveSubscriptions(s.GetBaseDir(), subs)
+}
+
+// Unsubscribe stops following a saved search and forgets its new matches
+func (s *Service) Unsubscribe(name string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	if _, ok := subs.Searches[name]; !ok {
+		return nil
+	}
+	delete(subs.Searches, name)
+	return storage.SaveSubscriptions(s.GetBaseDir(), subs)
+}
+
+// Subscribed reports whether the saved search called name is followed
+func (s *Service) Subscribed(name string) bool {
+	subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+	if err != nil {
+		return false
+	}
+	_, ok := subs.Searches[name]
+	return ok
+}
+
+// NewMatches returns the prompts that started matching a subscribed search
+// since it was last acknowledged, oldest first. Prompts since removed, or
+// that no longer match, are left out.
+func (s *Service) NewMatches(name string) (*SearchMatches, error) {
+	subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+	if err != nil {
+		return nil, err
+	}
+	sub, ok := subs.Searches[name]
+	if !ok {
+		return nil, fmt.Errorf("not subscribed to saved search %q (use 'pkt search-saved subscribe %s')", name, name)
+	}
+	return s.searchMatches(name, sub)
+}
+
+// AllNewMatches returns the new matches of every subscribed search that has
+// any, by search name
+func (s *Service) AllNewMatches() ([]SearchMatches, error) {
+	subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name, sub := range subs.Searches {
+		if len(sub.New) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var all []SearchMatches
+	for _, name := range names {
+		matches, err := s.searchMatches(name, subs.Searches[name])
+		if err != nil {
+			// A search deleted since subscribing has nothing to report
+			continue
+		}
+		if len(matches.Matches) > 0 {
+			all = append(all, *matches)
+		}
+	}
+	return all, nil
+}
+
+// searchMatches looks up the new matches of a subscription that still match
+func (s *Service) searchMatches(name string, sub *storage.Subscription) (*SearchMatches, error) {
+	results, err := s.ExecuteSavedSearch(name)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*models.Prompt, len(results))
+	for _, p := range results {
+		current[p.ID] = p
+	}
+
+	matches := &SearchMatches{Search: name, Since: sub.Acknowledged}
+	for _, match := range sub.New {
+		if p, ok := current[match.ID]; ok {
+			matches.Matches = append(matches.Matches, SubscriptionMatch{Prompt: p, Source: match.Source, At: match.At})
+		}
+	}
+	return matches, nil
+}
+
+// AcknowledgeMatches clears the new matches of a subscribed search
+func (s *Service) AcknowledgeMatches(name string) error {
+	if s.ReadOnly() {
+		return storage.ErrReadOnly
+	}
+	subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+	if err != nil {
+		return err
+	}
+	sub, ok := subs.Searches[name]
+	if !ok {
+		return fmt.Errorf("not subscribed to saved search %q", name)
+	}
+	sub.New = nil
+	sub.Acknowledged = time.Now()
+	return storage.SaveSubscriptions(s.GetBaseDir(), subs)
+}
+
+// TrackSubscriptions notes which prompts match each subscribed search before
+// a sync or import changes the library. The function it returns, called
+// once the prompt cache is up to date, records the prompts that match now
+// but did not before as new matches from source. Without subscriptions
+// both do nothing.
+func (s *Service) TrackSubscriptions(source string) func() {
+	if s.ReadOnly() {
+		return func() {}
+	}
+	subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+	if err != nil || len(subs.Searches) == 0 {
+		return func() {}
+	}
+	before := map[string]map[string]bool{}
+	for name := range subs.Searches {
+		if ids := s.subscriptionMatchIDs(name); ids != nil {
+			before[name] = ids
+		}
+	}
+
+	return func() {
+		subs, err := storage.LoadSubscriptions(s.GetBaseDir())
+		if err != nil {
+			log.Printf("Warning: failed to update subscriptions: %v", err)
+			return
+		}
+		now := time.Now()
+		changed := false
+		for name, sub := range subs.Searches {
+			matched, tracked := before[name]
+			if !tracked {
+				continue // Subscribed while the sync ran, or the search failed
+			}
+			var added []string
+			for id := range s.subscriptionMatchIDs(name) {
+				if !matched[id] && !sub.Has(id) {
+					added = append(added, id)
+				}
+			}
+			sort.Strings(added)
+			for _, id := range added {
+				sub.New = append(sub.New, storage.NewMatch{ID: id, Source: source, At: now})
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+		if err := storage.SaveSubscriptions(s.GetBaseDir(), subs); err != nil {
+			log.Printf("Warning: failed to update subscriptions: %v", err)
+		}
+	}
+}
+
+// subscriptionMatchIDs returns the IDs of the prompts a saved search
+// matches, or nil when it cannot run
+func (s *Service) subscriptionMatchIDs(name string) map[string]bool {
+	results, err := s.ExecuteSavedSearch(name)
+	if err != nil {
+		return nil
+	}
+	ids := make(map[string]bool, len(results))
+	for _, p := range results {
+		ids[p.ID] = true
+	}
+	return ids
+}
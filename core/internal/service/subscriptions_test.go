@@ -0,0 +1,69 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSubscriptionNewMatches(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	create := func(id string, tags ...string) {
+		t.Helper()
+		if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: id, Version: "1.0.0", Content: id, Tags: tags}); err != nil {
+			t.Fatalf("Failed to create %s: %v", id, err)
+		}
+	}
+	if err := svc.SaveBooleanSearch(models.SavedSearch{Name: "review", Expression: models.NewTagExpression("review")}); err != nil {
+		t.Fatalf("Failed to save search: %v", err)
+	}
+	create("before", "review")
+	if err := svc.Subscribe("review"); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if !svc.Subscribed("review") {
+		t.Fatal("Expected review to be subscribed")
+	}
+
+	// Only prompts a sync or import brings in count, not local ones
+	create("local", "review")
+	track := svc.TrackSubscriptions(MatchFromImport)
+	create("incoming", "review")
+	create("unrelated", "other")
+	track()
+
+	matches, err := svc.NewMatches("review")
+	if err != nil {
+		t.Fatalf("NewMatches: %v", err)
+	}
+	if len(matches.Matches) != 1 || matches.Matches[0].Prompt.ID != "incoming" || matches.Matches[0].Source != MatchFromImport {
+		t.Fatalf("new matches = %+v, want incoming from import", matches.Matches)
+	}
+
+	digest, err := svc.BuildDigestSince(config.DigestConfig{}, time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("BuildDigestSince: %v", err)
+	}
+	if text := digest.Markdown(); !strings.Contains(text, "## New in review\n\n- `incoming` v1.0.0 — incoming (import)\n") {
+		t.Errorf("digest lacks the new match:\n%s", text)
+	}
+	if !strings.Contains(digest.Summary(), "1 new in subscribed searches") {
+		t.Errorf("summary = %q", digest.Summary())
+	}
+
+	if err := svc.AcknowledgeMatches("review"); err != nil {
+		t.Fatalf("AcknowledgeMatches: %v", err)
+	}
+	if all, err := svc.AllNewMatches(); err != nil || len(all) != 0 {
+		t.Errorf("new matches after acknowledging = %+v, %v", all, err)
+	}
+	if _, err := svc.NewMatches("unknown"); err == nil {
+		t.Error("Expected an error for a search that is not subscribed")
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dpshade/pocket-prompt/internal/detect"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Suggestion is a prompt ranked by how well it matches a project
+type Suggestion struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Score   int      `json:"score"`
+	Matches []string `json:"matches"` // Project signals the prompt matched
+}
+
+// How much a signal adds to a prompt's score, by where it matched
+const (
+	suggestTagWeight      = 3
+	suggestMetadataWeight = 2
+	suggestTextWeight     = 1
+)
+
+// SuggestPrompts ranks prompts against the languages, frameworks, tools and
+// repository name detected in a project. A signal found in a prompt's tags
+// counts most, then in its metadata values, then in its title or
+// description. Ties go to the more used prompt. At most limit suggestions
+// are returned, or all of them when limit is 0.
+func (s *Service) SuggestPrompts(project *detect.Project, limit int) ([]Suggestion, error) {
+	signals := project.Signals()
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("nothing recognisable found in %s", project.Root)
+	}
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.usage.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []Suggestion{}
+	for _, p := range prompts {
+		// Listings come from the metadata cache, which leaves metadata out
+		if p.Content == "" && p.FilePath != "" {
+			if full, err := s.loadPromptContent(p); err == nil {
+				p = full
+			}
+		}
+		suggestion := Suggestion{ID: p.ID, Title: p.Title()}
+		for _, signal := range signals {
+			if weight := signalWeight(p, signal); weight > 0 {
+				suggestion.Score += weight
+				suggestion.Matches = append(suggestion.Matches, signal)
+			}
+		}
+		if suggestion.Score > 0 {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if usage[a.ID].Count != usage[b.ID].Count {
+			return usage[a.ID].Count > usage[b.ID].Count
+		}
+		return a.ID < b.ID
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// signalWeight returns how strongly p matches signal, or 0
+func signalWeight(p *models.Prompt, signal string) int {
+	for _, tag := range p.Tags {
+		if tagMatches(tag, signal) {
+			return suggestTagWeight
+		}
+	}
+	for _, value := range p.Metadata {
+		if metadataMatches(value, signal) {
+			return suggestMetadataWeight
+		}
+	}
+	if containsWord(p.Name+" "+p.Summary, signal) {
+		return suggestTextWeight
+	}
+	return 0
+}
+
+// tagMatches compares a tag to a signal, ignoring case and any namespace
+// such as the lang/ in lang/go
+func tagMatches(tag, signal string) bool {
+	tag = strings.ToLower(tag)
+	if i := strings.LastIndexAny(tag, "/:"); i >= 0 {
+		tag = tag[i+1:]
+	}
+	return tag == signal
+}
+
+// metadataMatches reports whether a metadata value, or one of a list of
+// values, equals signal
+func metadataMatches(value interface{}, signal string) bool {
+	switch v := value.(type) {
+	case string:
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), signal) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if metadataMatches(item, signal) {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range v {
+			if metadataMatches(item, signal) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsWord reports whether text contains word on its own, so that
+// "go" matches "Go error handling" but not "good"
+func containsWord(text, word string) bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '+' && r != '#'
+	})
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
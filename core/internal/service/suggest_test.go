@@ -0,0 +1,62 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/detect"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSuggestPrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	prompts := []*models.Prompt{
+		{ID: "go-review", Name: "Code review", Tags: []string{"lang/go", "docker"}},
+		{ID: "dockerfile", Name: "Slim Dockerfile", Tags: []string{"docker"}},
+		{ID: "errors", Name: "Go error handling"},
+		{ID: "billing", Name: "Release notes", Metadata: map[string]interface{}{"repo": "billing-api"}},
+		{ID: "goodbye", Name: "Goodbye email", Tags: []string{"email"}},
+	}
+	for _, p := range prompts {
+		p.Version, p.Content = "1.0.0", "Hello"
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	project := &detect.Project{Root: tmpDir, Languages: []string{"go"}, Tools: []string{"docker"}, Repo: "billing-api"}
+	suggestions, err := svc.SuggestPrompts(project, 3)
+	if err != nil {
+		t.Fatalf("SuggestPrompts: %v", err)
+	}
+	var ids []string
+	for _, s := range suggestions {
+		ids = append(ids, s.ID)
+	}
+	want := []string{"go-review", "dockerfile", "billing"}
+	if len(ids) != len(want) {
+		t.Fatalf("suggested %q, want %q", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("suggested %q, want %q", ids, want)
+			break
+		}
+	}
+	if suggestions[0].Score != 6 || len(suggestions[0].Matches) != 2 {
+		t.Errorf("go-review scored %d matching %q, want 6 matching go and docker", suggestions[0].Score, suggestions[0].Matches)
+	}
+
+	if _, err := svc.SuggestPrompts(&detect.Project{Root: tmpDir}, 3); err == nil {
+		t.Error("SuggestPrompts with nothing detected succeeded")
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// Where a proposed summary came from
+const (
+	SummaryFromCommand = "command" // The configured summarize command
+	SummaryFromContent = "extract" // The prompt's first sentence
+)
+
+// SummaryProposal is a summary written for a prompt, shown for review before
+// it is saved
+type SummaryProposal struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Current string `json:"current,omitempty"` // The summary it replaces
+	Summary string `json:"summary"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"` // The new version; empty until saved
+}
+
+// SummaryCandidates returns the prompts with ids, or every prompt when ids is
+// empty. With missingOnly, prompts that already have a summary are left out.
+func (s *Service) SummaryCandidates(ids []string, missingOnly bool) ([]*models.Prompt, error) {
+	var prompts []*models.Prompt
+	if len(ids) == 0 {
+		all, err := s.ListPrompts()
+		if err != nil {
+			return nil, err
+		}
+		prompts = all
+	} else {
+		for _, id := range ids {
+			p, err := s.GetPrompt(id)
+			if err != nil {
+				return nil, err
+			}
+			prompts = append(prompts, p)
+		}
+	}
+
+	var candidates []*models.Prompt
+	for _, p := range prompts {
+		if missingOnly && strings.TrimSpace(p.Summary) != "" {
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+	return candidates, nil
+}
+
+// ProposeSummary writes a summary for prompt with the summarize command, or
+// takes its first sentence when no command is configured. Nothing is saved.
+func (s *Service) ProposeSummary(prompt *models.Prompt) (*SummaryProposal, error) {
+	content := s.promptContent(prompt)
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("prompt %s has no content to summarize", prompt.ID)
+	}
+
+	proposal := &SummaryProposal{ID: prompt.ID, Title: prompt.Title(), Current: prompt.Summary}
+	if command := s.settings.Summarize.Command; command != "" {
+		summary, err := s.runSummarizeCommand(command, content)
+		if err != nil {
+			return nil, fmt.Errorf("summarize command failed for %s: %w", prompt.ID, err)
+		}
+		proposal.Summary = summary
+		proposal.Source = SummaryFromCommand
+	} else {
+		proposal.Summary = Excerpt(content, 1)
+		proposal.Source = SummaryFromContent
+	}
+	return proposal, nil
+}
+
+// ApplySummary saves summary as the prompt's summary in a new version
+func (s *Service) ApplySummary(id, summary string) (*models.Prompt, error) {
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return nil, fmt.Errorf("summary for %s is empty", id)
+	}
+	existing, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.Content = s.promptContent(existing)
+	updated.Summary = summary
+	if err := s.UpdatePrompt(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// runSummarizeCommand runs the summarize command with content on stdin and
+// returns what it prints as one line. Models often wrap the answer in
+// quotes, which are removed.
+func (s *Service) runSummarizeCommand(command, content string) (string, error) {
+	timeout, err := s.settings.Summarize.CommandTimeout(config.DefaultSummarizeTimeout)
+	if err != nil {
+		return "", err
+	}
+	out, err := s.runShellCommand(command, content, timeout, nil)
+	if err != nil {
+		return "", err
+	}
+	summary := strings.Join(strings.Fields(out), " ")
+	summary = strings.Trim(summary, `"'`)
+	if summary == "" {
+		return "", fmt.Errorf("it printed nothing")
+	}
+	return summary, nil
+}
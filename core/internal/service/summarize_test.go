@@ -0,0 +1,73 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSummarizePrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "greet", Name: "Greet", Content: "Greet the user warmly. Ask how they are."},
+		{ID: "review", Name: "Review", Summary: "Reviews code", Content: "Review this code."},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	candidates, err := svc.SummaryCandidates(nil, true)
+	if err != nil {
+		t.Fatalf("SummaryCandidates: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != "greet" {
+		t.Fatalf("SummaryCandidates(missing only) = %d prompts, want greet", len(candidates))
+	}
+
+	proposal, err := svc.ProposeSummary(candidates[0])
+	if err != nil {
+		t.Fatalf("ProposeSummary: %v", err)
+	}
+	if proposal.Summary != "Greet the user warmly." || proposal.Source != SummaryFromContent {
+		t.Errorf("Extracted summary = %q from %s, want the first sentence", proposal.Summary, proposal.Source)
+	}
+
+	svc.Settings().Summarize.Command = `cat >/dev/null; echo '"Says hello"'`
+	proposal, err = svc.ProposeSummary(candidates[0])
+	if err != nil {
+		t.Fatalf("ProposeSummary with command: %v", err)
+	}
+	if proposal.Summary != "Says hello" || proposal.Source != SummaryFromCommand {
+		t.Errorf("Command summary = %q from %s, want Says hello", proposal.Summary, proposal.Source)
+	}
+
+	svc.Settings().Summarize.Command = "exit 3"
+	if _, err := svc.ProposeSummary(candidates[0]); err == nil {
+		t.Error("Expected a failing command to be reported")
+	}
+
+	if _, err := svc.ApplySummary("greet", proposal.Summary); err != nil {
+		t.Fatalf("ApplySummary: %v", err)
+	}
+	saved, err := svc.GetPrompt("greet")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if saved.Summary != "Says hello" || saved.Version == "" {
+		t.Errorf("Saved prompt has summary %q at version %q", saved.Summary, saved.Version)
+	}
+	if content := svc.promptContent(saved); content != "Greet the user warmly. Ask how they are." {
+		t.Errorf("Content after summarizing = %q, want it unchanged", content)
+	}
+}
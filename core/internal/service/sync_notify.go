@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// syncNotifier turns background sync results into desktop notifications: one
+// for each pull that changes prompts, one when sync starts failing and one
+// when it recovers, so an error repeating every interval is reported once
+type syncNotifier struct {
+	send     func(title, message string) error
+	failures int  // Failed pulls in a row
+	disabled bool // Sending failed, e.g. no notification tool is installed
+}
+
+// result reports the outcome of one background pull
+func (n *syncNotifier) result(changes []PromptEvent, err error) {
+	switch {
+	case err != nil:
+		n.failures++
+		if n.failures == 1 {
+			n.notify("Prompt sync failing", err.Error())
+		}
+	case n.failures > 0:
+		attempts := "attempt"
+		if n.failures > 1 {
+			attempts = "attempts"
+		}
+		message := fmt.Sprintf("Pulled from the remote after %d failed %s", n.failures, attempts)
+		if len(changes) > 0 {
+			message += ". " + describeChanges(changes)
+		}
+		n.failures = 0
+		n.notify("Prompt sync working again", message)
+	case len(changes) > 0:
+		n.notify("Prompts synced", describeChanges(changes))
+	}
+}
+
+func (n *syncNotifier) notify(title, message string) {
+	if n.disabled {
+		return
+	}
+	if err := n.send(title, message); err != nil {
+		log.Printf("Warning: desktop notifications disabled: %v", err)
+		n.disabled = true
+	}
+}
+
+// describeChanges summarises a pull as, for example,
+// "2 added, 1 updated: review, summary, notes"
+func describeChanges(changes []PromptEvent) string {
+	counts := map[string]int{}
+	var ids []string
+	for _, change := range changes {
+		counts[change.Kind]++
+		if len(ids) < 3 {
+			ids = append(ids, change.ID)
+		}
+	}
+
+	var parts []string
+	for _, kind := range []string{ChangeAdded, ChangeModified, ChangeRemoved} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], changeVerb(kind)))
+		}
+	}
+	summary := strings.Join(parts, ", ") + ": " + strings.Join(ids, ", ")
+	if more := len(changes) - len(ids); more > 0 {
+		summary += fmt.Sprintf(" and %d more", more)
+	}
+	return summary
+}
+
+func changeVerb(kind string) string {
+	switch kind {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	default:
+		return "updated"
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSyncNotifier(t *testing.T) {
+	var sent []string
+	n := &syncNotifier{send: func(title, message string) error {
+		sent = append(sent, title+": "+message)
+		return nil
+	}}
+
+	n.result(nil, nil)
+	n.result([]PromptEvent{
+		{Kind: ChangeAdded, ID: "review"},
+		{Kind: ChangeModified, ID: "summary"},
+		{Kind: ChangeAdded, ID: "notes"},
+		{Kind: ChangeRemoved, ID: "old"},
+	}, nil)
+	n.result(nil, errors.New("failed to pull changes: auth required"))
+	n.result(nil, errors.New("failed to pull changes: auth required"))
+	n.result(nil, nil)
+
+	want := []string{
+		"Prompts synced: 2 added, 1 updated, 1 removed: review, summary, notes and 1 more",
+		"Prompt sync failing: failed to pull changes: auth required",
+		"Prompt sync working again: Pulled from the remote after 2 failed attempts",
+	}
+	if len(sent) != len(want) {
+		t.Fatalf("sent %q, want %q", sent, want)
+	}
+	for i := range want {
+		if sent[i] != want[i] {
+			t.Errorf("notification %d = %q, want %q", i, sent[i], want[i])
+		}
+	}
+
+	broken := &syncNotifier{send: func(title, message string) error {
+		sent = append(sent, title)
+		return errors.New("notify-send is not installed")
+	}}
+	sent = nil
+	broken.result([]PromptEvent{{Kind: ChangeAdded, ID: "a"}}, nil)
+	broken.result([]PromptEvent{{Kind: ChangeAdded, ID: "b"}}, nil)
+	if len(sent) != 1 {
+		t.Errorf("tried to send %d notifications after sending failed, want 1", len(sent))
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// tagIndex is an inverted index from tag to prompts, so a boolean search
+// visits only the prompts carrying the tags it names instead of the whole
+// library. It is built from one snapshot of the prompt cache and keeps that
+// snapshot, so a search never mixes an index with a newer cache.
+type tagIndex struct {
+	prompts []*models.Prompt
+	tags    map[string][]int // Lower-cased tag -> ascending positions in prompts
+}
+
+// positionSet is a set of positions in tagIndex.prompts. A complemented set
+// holds every position except those listed, so NOT costs nothing until the
+// result is needed.
+type positionSet struct {
+	positions  []int // Ascending
+	complement bool
+}
+
+// setPrompts replaces the prompt cache and rebuilds the tag index over it,
+// dropping content read for search from the old cache
+func (s *Service) setPrompts(prompts []*models.Prompt) {
+	s.setLoaded(s.beginLoad(), prompts)
+}
+
+// beginLoad numbers a read of the library before it starts. Reads overlap
+// when prompts are saved from several goroutines, and one that started
+// earlier can finish last with an older listing.
+func (s *Service) beginLoad() uint64 {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.loadsStarted++
+	return s.loadsStarted
+}
+
+// setLoaded replaces the prompt cache with the prompts read by load, unless
+// the cache already holds a read that started later
+func (s *Service) setLoaded(load uint64, prompts []*models.Prompt) {
+	index := newTagIndex(prompts)
+	s.cacheMu.Lock()
+	if load < s.loadSet {
+		s.cacheMu.Unlock()
+		return
+	}
+	s.prompts = prompts
+	s.tagIndex = index
+	s.loadSet = load
+	s.cacheMu.Unlock()
+	s.contentMu.Lock()
+	s.contents = nil
+	s.contentMu.Unlock()
+}
+
+// cachedPrompts returns the prompt cache. The cache is only ever replaced,
+// never changed in place, so callers may range over it without the lock.
+func (s *Service) cachedPrompts() []*models.Prompt {
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+	return s.prompts
+}
+
+// cachedIndex returns the tag index over the prompt cache
+func (s *Service) cachedIndex() *tagIndex {
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+	return s.tagIndex
+}
+
+func newTagIndex(prompts []*models.Prompt) *tagIndex {
+	index := &tagIndex{prompts: prompts, tags: make(map[string][]int)}
+	for i, p := range prompts {
+		for _, tag := range p.Tags {
+			key := strings.ToLower(tag)
+			// A prompt tagged both "AI" and "ai" is listed once
+			if positions := index.tags[key]; len(positions) == 0 || positions[len(positions)-1] != i {
+				index.tags[key] = append(positions, i)
+			}
+		}
+	}
+	return index
+}
+
+// match returns the prompts whose tags satisfy expression, in cache order,
+// as expression.Evaluate would select them
+func (ix *tagIndex) match(expression *models.BooleanExpression) []*models.Prompt {
+	set := ix.evaluate(expression)
+	var matched []*models.Prompt
+	if !set.complement {
+		for _, i := range set.positions {
+			matched = append(matched, ix.prompts[i])
+		}
+		return matched
+	}
+	excluded := set.positions
+	for i, p := range ix.prompts {
+		if len(excluded) > 0 && excluded[0] == i {
+			excluded = excluded[1:]
+			continue
+		}
+		matched = append(matched, p)
+	}
+	return matched
+}
+
+// evaluate mirrors models.BooleanExpression.Evaluate over sets of prompts,
+// including its answers for malformed expressions
+func (ix *tagIndex) evaluate(expression *models.BooleanExpression) positionSet {
+	all := positionSet{complement: true}
+	if expression == nil {
+		return all
+	}
+
+	switch expression.Type {
+	case models.ExpressionTag:
+		tag, ok := expression.Value.(string)
+		if !ok {
+			return positionSet{}
+		}
+		return positionSet{positions: ix.tags[strings.ToLower(tag)]}
+
+	case models.ExpressionAnd:
+		expressions, ok := expression.Value.([]*models.BooleanExpression)
+		if !ok {
+			return all
+		}
+		result := all
+		for _, expr := range expressions {
+			result = intersectSets(result, ix.evaluate(expr))
+		}
+		return result
+
+	case models.ExpressionOr:
+		expressions, ok := expression.Value.([]*models.BooleanExpression)
+		if !ok {
+			return positionSet{}
+		}
+		var result positionSet
+		for _, expr := range expressions {
+			result = unionSets(result, ix.evaluate(expr))
+		}
+		return result
+
+	case models.ExpressionXor:
+		expressions, ok := expression.Value.([]*models.BooleanExpression)
+		if !ok || len(expressions) != 2 {
+			return positionSet{}
+		}
+		left, right := ix.evaluate(expressions[0]), ix.evaluate(expressions[1])
+		return positionSet{
+			positions:  symmetricDifference(left.positions, right.positions),
+			complement: left.complement != right.complement,
+		}
+
+	case models.ExpressionNot:
+		expressions, ok := expression.Value.([]*models.BooleanExpression)
+		if !ok || len(expressions) != 1 {
+			return positionSet{}
+		}
+		inner := ix.evaluate(expressions[0])
+		return positionSet{positions: inner.positions, complement: !inner.complement}
+
+	default:
+		return positionSet{}
+	}
+}
+
+// intersectSets returns a AND b
+func intersectSets(a, b positionSet) positionSet {
+	switch {
+	case !a.complement && !b.complement:
+		return positionSet{positions: intersect(a.positions, b.positions)}
+	case a.complement && b.complement:
+		return positionSet{positions: union(a.positions, b.positions), complement: true}
+	case a.complement:
+		return positionSet{positions: difference(b.positions, a.positions)}
+	default:
+		return positionSet{positions: difference(a.positions, b.positions)}
+	}
+}
+
+// unionSets returns a OR b
+func unionSets(a, b positionSet) positionSet {
+	switch {
+	case !a.complement && !b.complement:
+		return positionSet{positions: union(a.positions, b.positions)}
+	case a.complement && b.complement:
+		return positionSet{positions: intersect(a.positions, b.positions), complement: true}
+	case a.complement:
+		return positionSet{positions: difference(a.positions, b.positions), complement: true}
+	default:
+		return positionSet{positions: difference(b.positions, a.positions), complement: true}
+	}
+}
+
+// intersect returns the positions in both a and b
+func intersect(a, b []int) []int {
+	var out []int
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			a = a[1:]
+		case a[0] > b[0]:
+			b = b[1:]
+		default:
+			out = append(out, a[0])
+			a, b = a[1:], b[1:]
+		}
+	}
+	return out
+}
+
+// union returns the positions in a or b
+func union(a, b []int) []int {
+	out := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			out, a = append(out, a[0]), a[1:]
+		case a[0] > b[0]:
+			out, b = append(out, b[0]), b[1:]
+		default:
+			out = append(out, a[0])
+			a, b = a[1:], b[1:]
+		}
+	}
+	out = append(out, a...)
+	return append(out, b...)
+}
+
+// difference returns the positions in a but not b
+func difference(a, b []int) []int {
+	var out []int
+	for len(a) > 0 {
+		switch {
+		case len(b) == 0 || a[0] < b[0]:
+			out, a = append(out, a[0]), a[1:]
+		case a[0] > b[0]:
+			b = b[1:]
+		default:
+			a, b = a[1:], b[1:]
+		}
+	}
+	return out
+}
+
+// symmetricDifference returns the positions in exactly one of a and b
+func symmetricDifference(a, b []int) []int {
+	return union(difference(a, b), difference(b, a))
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestTagIndexMatchesEvaluate(t *testing.T) {
+	var prompts []*models.Prompt
+	tagSets := [][]string{
+		{"ai"}, {"AI", "code"}, {"code", "draft"}, {"review"}, {},
+		{"ai", "review", "draft"}, {"Code"}, {"ai", "ai"},
+	}
+	for i, tags := range tagSets {
+		prompts = append(prompts, &models.Prompt{ID: fmt.Sprintf("p%d", i), Tags: tags})
+	}
+	index := newTagIndex(prompts)
+
+	tag := models.NewTagExpression
+	expressions := map[string]*models.BooleanExpression{
+		"tag":            tag("ai"),
+		"unknown tag":    tag("missing"),
+		"and":            models.NewAndExpression(tag("ai"), tag("code")),
+		"or":             models.NewOrExpression(tag("review"), tag("draft")),
+		"not":            models.NewNotExpression(tag("code")),
+		"and not":        models.NewAndExpression(tag("code"), models.NewNotExpression(tag("draft"))),
+		"or not":         models.NewOrExpression(tag("review"), models.NewNotExpression(tag("ai"))),
+		"not or not":     models.NewOrExpression(models.NewNotExpression(tag("ai")), models.NewNotExpression(tag("code"))),
+		"xor":            models.NewXorExpression(tag("ai"), tag("code")),
+		"xor not":        models.NewXorExpression(models.NewNotExpression(tag("ai")), tag("draft")),
+		"nested":         models.NewNotExpression(models.NewAndExpression(tag("ai"), models.NewOrExpression(tag("code"), tag("review")))),
+		"empty and":      {Type: models.ExpressionAnd, Value: []*models.BooleanExpression{}},
+		"empty or":       {Type: models.ExpressionOr, Value: []*models.BooleanExpression{}},
+		"malformed tag":  {Type: models.ExpressionTag, Value: 42},
+		"malformed xor":  {Type: models.ExpressionXor, Value: []*models.BooleanExpression{tag("ai")}},
+		"malformed not":  {Type: models.ExpressionNot, Value: "ai"},
+		"unknown type":   {Type: "nand", Value: "ai"},
+		"nil expression": nil,
+	}
+	for name, expression := range expressions {
+		var want []string
+		for _, p := range prompts {
+			if expression.Evaluate(p.Tags) {
+				want = append(want, p.ID)
+			}
+		}
+		var got []string
+		for _, p := range index.match(expression) {
+			got = append(got, p.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: index matched %v, Evaluate %v", name, got, want)
+		}
+	}
+}
+
+func TestTagIndexFollowsChanges(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Content: "Review this", Tags: []string{"code"}}); err != nil {
+		t.Fatalf("CreatePrompt: %v", err)
+	}
+	expression := models.NewTagExpression("go")
+	if results, err := svc.SearchPromptsByBooleanExpression(expression); err != nil || len(results) != 0 {
+		t.Fatalf("results = %v, %v; want none before tagging", results, err)
+	}
+
+	if _, err := svc.AddTag("review", "go"); err != nil {
+		t.Fatalf("AddTag: %v", err)
+	}
+	results, err := svc.SearchPromptsByBooleanExpression(expression)
+	if err != nil || len(results) != 1 || results[0].ID != "review" {
+		t.Errorf("results = %v, %v; want the newly tagged prompt", results, err)
+	}
+}
+
+func TestPromptCacheConcurrentAccess(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "seed", Name: "Seed", Content: "Seed", Tags: []string{"go"}}); err != nil {
+		t.Fatalf("CreatePrompt: %v", err)
+	}
+
+	// Run with -race: creating prompts replaces the cache that listing and
+	// boolean search read from other goroutines, as in the TUI and server
+	expression := models.NewTagExpression("go")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("prompt-%d", i)
+			if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: id, Content: "Content", Tags: []string{"go"}}); err != nil {
+				t.Errorf("CreatePrompt: %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.ListPrompts(); err != nil {
+				t.Errorf("ListPrompts: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := svc.SearchPromptsByBooleanExpression(expression); err != nil {
+				t.Errorf("SearchPromptsByBooleanExpression: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	results, err := svc.SearchPromptsByBooleanExpression(expression)
+	if err != nil || len(results) != 5 {
+		t.Errorf("found %d prompts, %v; want all 5 after the writes", len(results), err)
+	}
+}
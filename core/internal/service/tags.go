@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// AddTag adds tag to a prompt and saves the result as its next version,
+// reporting whether anything changed: a tag the prompt already has is left
+// alone.
+func (s *Service) AddTag(id, tag string) (bool, error) {
+	tag, err := cleanTag(tag)
+	if err != nil {
+		return false, err
+	}
+	if s.ReadOnly() {
+		return false, storage.ErrReadOnly
+	}
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(prompt.Tags, tag) {
+		return false, nil
+	}
+
+	updated := *prompt
+	updated.Tags = append(slices.Clone(prompt.Tags), tag)
+	return true, s.UpdatePrompt(&updated)
+}
+
+// RemoveTag removes tag from a prompt and saves the result as its next
+// version, reporting whether the prompt had the tag. Tags that come from the
+// prompt's folder can't be removed this way.
+func (s *Service) RemoveTag(id, tag string) (bool, error) {
+	tag, err := cleanTag(tag)
+	if err != nil {
+		return false, err
+	}
+	if s.ReadOnly() {
+		return false, storage.ErrReadOnly
+	}
+	prompt, err := s.GetPrompt(id)
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(prompt.DerivedTags, tag) {
+		return false, fmt.Errorf("tag %q comes from the folder of %s; move the prompt to remove it", tag, id)
+	}
+	if !slices.Contains(prompt.Tags, tag) {
+		return false, nil
+	}
+
+	updated := *prompt
+	updated.Tags = slices.DeleteFunc(slices.Clone(prompt.Tags), func(t string) bool { return t == tag })
+	return true, s.UpdatePrompt(&updated)
+}
+
+// cleanTag trims a tag typed by hand and rejects ones that can't be stored
+// as a single tag
+func cleanTag(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", fmt.Errorf("tag cannot be empty")
+	}
+	if strings.ContainsAny(tag, ", \t\n") {
+		return "", fmt.Errorf("tag %q cannot contain spaces or commas", tag)
+	}
+	return tag, nil
+}
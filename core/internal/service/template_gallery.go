@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/redact"
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+)
+
+// GalleryTemplate is a template as the gallery shows it: how much it is
+// used and an example of what it renders
+type GalleryTemplate struct {
+	Template *models.Template  `json:"template"`
+	Usage    TemplateStats     `json:"usage"`
+	Samples  map[string]string `json:"samples"`          // Slot values the example is rendered with
+	Source   string            `json:"source,omitempty"` // Prompt whose content the example wraps, if any prompt uses the template
+	Example  string            `json:"example"`
+	Error    string            `json:"error,omitempty"` // Why the example could not be rendered
+}
+
+// sampleContent stands in for the prompt content of a template no prompt uses
+const sampleContent = "‹your prompt›"
+
+// SlotSample returns the value the gallery shows a slot with: its example,
+// else its default unless it is sensitive, else its name as a placeholder
+func SlotSample(slot models.Slot) string {
+	switch {
+	case slot.Example != "":
+		return slot.Example
+	case slot.Default != "" && !slot.Sensitive:
+		return slot.Default
+	default:
+		return "‹" + slot.Name + "›"
+	}
+}
+
+// TemplateGallery returns every template with its usage and an example
+// rendered from sample slot values. The example wraps the content of the
+// first prompt built from the template, so it shows the template in use.
+// A template whose example fails to render is still listed, with the error.
+func (s *Service) TemplateGallery() ([]GalleryTemplate, error) {
+	templates, err := s.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	// A library without a prompts folder simply has no prompts using them
+	stats, err := s.TemplateStats()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	gallery := make([]GalleryTemplate, 0, len(templates))
+	for _, template := range templates {
+		usage, ok := stats[template.ID]
+		if !ok {
+			usage = TemplateStats{ID: template.ID, Name: template.Name, Prompts: []string{}}
+		}
+		entry := GalleryTemplate{Template: template, Usage: usage, Samples: map[string]string{}}
+		values := map[string]interface{}{}
+		for _, slot := range template.Slots {
+			entry.Samples[slot.Name] = SlotSample(slot)
+			values[slot.Name] = entry.Samples[slot.Name]
+		}
+
+		example := &models.Prompt{ID: template.ID, Content: sampleContent}
+		for _, id := range entry.Usage.Prompts {
+			if prompt, err := s.GetPrompt(id); err == nil {
+				example.Content = prompt.Content
+				entry.Source = id
+				break
+			}
+		}
+		if entry.Example, err = renderer.NewRenderer(example, template).RenderText(values); err != nil {
+			entry.Error = err.Error()
+		}
+		gallery = append(gallery, entry)
+	}
+	return gallery, nil
+}
+
+// TemplateGalleryHTML renders the template gallery as a standalone HTML
+// page. With a redactor, the library's redaction rules apply to the text of
+// every card.
+func (s *Service) TemplateGalleryHTML(redactor *redact.Redactor) (string, error) {
+	gallery, err := s.TemplateGallery()
+	if err != nil {
+		return "", err
+	}
+	clean := func(text string) string {
+		if redactor == nil {
+			return text
+		}
+		return redactor.String(text)
+	}
+
+	cards := make([]renderer.GalleryCard, 0, len(gallery))
+	for _, entry := range gallery {
+		template := entry.Template
+		card := renderer.GalleryCard{
+			PreviewInfo: renderer.PreviewInfo{
+				Kind:        "Template",
+				Title:       clean(template.Name),
+				Description: clean(template.Description),
+				Version:     template.Version,
+				Details:     []string{"ID: " + template.ID, "Used by " + entry.Usage.Summary()},
+			},
+			Example:     clean(entry.Example),
+			ExampleFrom: entry.Source,
+			Error:       entry.Error,
+		}
+		if card.Title == "" {
+			card.Title = template.ID
+		}
+		for _, slot := range template.Slots {
+			card.Slots = append(card.Slots, renderer.GallerySlot{
+				Name:        slot.Name,
+				Description: clean(slot.Description),
+				Required:    slot.Required,
+				Sample:      clean(entry.Samples[slot.Name]),
+			})
+		}
+		cards = append(cards, card)
+	}
+	return renderer.GalleryHTML(cards)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestTemplateGallery(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	templates := []*models.Template{
+		{
+			ID:      "framed",
+			Name:    "Framed",
+			Content: "As {{.role}}, for {{.audience}} ({{.key}}):\n\n{{.content}}",
+			Slots: []models.Slot{
+				{Name: "role", Default: "an editor"},
+				{Name: "audience", Example: "new hires", Default: "everyone"},
+				{Name: "key", Default: "sk-secret", Sensitive: true},
+			},
+		},
+		{ID: "unused", Name: "Unused", Content: "Intro\n\n{{.content}}"},
+		{ID: "broken", Name: "Broken", Content: "{{.content"},
+	}
+	for _, template := range templates {
+		if err := svc.SaveTemplate(template); err != nil {
+			t.Fatalf("SaveTemplate: %v", err)
+		}
+	}
+	for _, id := range []string{"b-onboarding", "a-onboarding"} {
+		prompt := &models.Prompt{ID: id, Name: id, TemplateRef: "framed", Content: "Welcome " + id}
+		if err := svc.CreatePrompt(prompt); err != nil {
+			t.Fatalf("CreatePrompt: %v", err)
+		}
+	}
+
+	gallery, err := svc.TemplateGallery()
+	if err != nil {
+		t.Fatalf("TemplateGallery: %v", err)
+	}
+	entries := map[string]GalleryTemplate{}
+	for _, entry := range gallery {
+		entries[entry.Template.ID] = entry
+	}
+	if len(entries) != 3 {
+		t.Fatalf("gallery = %+v, want 3 templates", gallery)
+	}
+
+	framed := entries["framed"]
+	if want := "As an editor, for new hires (‹key›):\n\nWelcome a-onboarding"; framed.Example != want {
+		t.Errorf("framed example = %q, want %q", framed.Example, want)
+	}
+	if framed.Source != "a-onboarding" || len(framed.Usage.Prompts) != 2 {
+		t.Errorf("framed source = %q, usage = %+v", framed.Source, framed.Usage)
+	}
+	if unused := entries["unused"]; unused.Example != "Intro\n\n‹your prompt›" || unused.Source != "" {
+		t.Errorf("unused = %+v, want the sample content", unused)
+	}
+	if broken := entries["broken"]; broken.Error == "" || broken.Example != "" {
+		t.Errorf("broken = %+v, want a render error", broken)
+	}
+
+	page, err := svc.TemplateGalleryHTML(nil)
+	if err != nil {
+		t.Fatalf("TemplateGalleryHTML: %v", err)
+	}
+	for _, want := range []string{"<h2>Framed</h2>", "Used by 2 prompts, 0 renders", "<td>new hires</td>", "<pre>As an editor, for new hires"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("gallery page lacks %q", want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// A template is heavily used, and deleting it asks for more care, once this
+// many prompts use it or it has been rendered this many times
+const (
+	heavyTemplatePrompts = 5
+	heavyTemplateRenders = 25
+)
+
+// TemplateStats counts how a template is used
+type TemplateStats struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Prompts  []string  `json:"prompts"`   // IDs of the prompts that use the template, archived ones aside
+	Renders  int       `json:"renders"`   // Renders and copies of those prompts on every device
+	LastUsed time.Time `json:"last_used"` // Zero if never rendered
+}
+
+// HeavilyUsed reports whether enough prompts or renders depend on the
+// template that deleting it deserves a warning
+func (t TemplateStats) HeavilyUsed() bool {
+	return len(t.Prompts) >= heavyTemplatePrompts || t.Renders >= heavyTemplateRenders
+}
+
+// Summary describes the usage in a few words, such as "3 prompts, 12 renders"
+func (t TemplateStats) Summary() string {
+	return fmt.Sprintf("%s, %s", plural(len(t.Prompts), "prompt"), plural(t.Renders, "render"))
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// TemplateStats returns the usage of every template, by template ID
+func (s *Service) TemplateStats() (map[string]TemplateStats, error) {
+	templates, err := s.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.templateUsage.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]TemplateStats, len(templates))
+	for _, t := range templates {
+		stats[t.ID] = TemplateStats{
+			ID:       t.ID,
+			Name:     t.Name,
+			Prompts:  []string{},
+			Renders:  usage[t.ID].Count,
+			LastUsed: usage[t.ID].LastUsed,
+		}
+	}
+	for _, p := range prompts {
+		if st, ok := stats[p.TemplateRef]; ok {
+			st.Prompts = append(st.Prompts, p.ID)
+			stats[p.TemplateRef] = st
+		}
+	}
+	for id, st := range stats {
+		sort.Strings(st.Prompts)
+		stats[id] = st
+	}
+	return stats, nil
+}
+
+// TemplateUsage returns the usage of one template
+func (s *Service) TemplateUsage(id string) (TemplateStats, error) {
+	stats, err := s.TemplateStats()
+	if err != nil {
+		return TemplateStats{}, err
+	}
+	st, ok := stats[id]
+	if !ok {
+		return TemplateStats{}, fmt.Errorf("template not found: %s", id)
+	}
+	return st, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestTemplateStats(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, id := range []string{"used", "unused"} {
+		if err := svc.SaveTemplate(&models.Template{ID: id, Name: id, Content: "Intro\n\n{{.content}}"}); err != nil {
+			t.Fatalf("SaveTemplate: %v", err)
+		}
+	}
+	for _, id := range []string{"b", "a", "plain"} {
+		prompt := &models.Prompt{ID: id, Name: id, Content: "Body of " + id}
+		if id != "plain" {
+			prompt.TemplateRef = "used"
+		}
+		if err := svc.CreatePrompt(prompt); err != nil {
+			t.Fatalf("CreatePrompt: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := svc.RenderPrompt("a", RenderOptions{}); err != nil {
+			t.Fatalf("RenderPrompt: %v", err)
+		}
+	}
+	svc.RecordUsage("plain")
+
+	stats, err := svc.TemplateStats()
+	if err != nil {
+		t.Fatalf("TemplateStats: %v", err)
+	}
+	used := stats["used"]
+	if !slices.Equal(used.Prompts, []string{"a", "b"}) || used.Renders != 3 || used.LastUsed.IsZero() {
+		t.Errorf("used = %+v, want prompts a and b, 3 renders", used)
+	}
+	if used.HeavilyUsed() {
+		t.Error("a template with 2 prompts and 3 renders should not be heavily used")
+	}
+	if got := used.Summary(); got != "2 prompts, 3 renders" {
+		t.Errorf("Summary = %q", got)
+	}
+	if unused := stats["unused"]; len(unused.Prompts) != 0 || unused.Renders != 0 {
+		t.Errorf("unused = %+v, want no prompts or renders", unused)
+	}
+
+	for i := 0; i < heavyTemplateRenders; i++ {
+		svc.RecordUsage("b")
+	}
+	if used, err := svc.TemplateUsage("used"); err != nil || !used.HeavilyUsed() {
+		t.Errorf("after %d more renders TemplateUsage = %+v, %v, want heavily used", heavyTemplateRenders, used, err)
+	}
+	if _, err := svc.TemplateUsage("missing"); err == nil {
+		t.Error("TemplateUsage of a missing template should fail")
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dpshade/pocket-prompt/internal/fuzzy"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// maxSimilarTitles is how many prompts with a nearly identical title are
+// reported for one new prompt
+const maxSimilarTitles = 5
+
+// SimilarTitle is an existing prompt whose title is nearly identical to that
+// of a prompt being saved, so it can be extended instead of forked unknowingly
+type SimilarTitle struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Pack  string `json:"pack,omitempty"` // Empty for the personal library
+	Path  string `json:"path"`           // The prompt's file in the library
+}
+
+// SimilarTitles returns the prompts in the library and every pack, other than
+// the one with id, whose title is nearly identical to title: the same words
+// in any order, ignoring case, accents and punctuation, or within a typo for
+// every ten letters. The closest come first.
+func (s *Service) SimilarTitles(title, id string) []SimilarTitle {
+	words := titleWords(title)
+	if len(words) == 0 {
+		return nil
+	}
+	key := strings.Join(words, " ")
+	sortedKey := strings.Join(slices.Sorted(slices.Values(words)), " ")
+	maxTypos := len([]rune(key)) / 10
+
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil
+	}
+	for _, pack := range s.packConfig.ListPacks() {
+		if packPrompts, err := s.storage.ListPromptsByPack(pack.Name); err == nil {
+			prompts = append(prompts, packPrompts...)
+		}
+	}
+	type match struct {
+		SimilarTitle
+		distance int
+	}
+	var matches []match
+	for _, p := range prompts {
+		if p.ID == id || p.Source != "" {
+			continue
+		}
+		other := titleWords(p.Title())
+		if len(other) == 0 {
+			continue
+		}
+		otherKey := strings.Join(other, " ")
+		d := fuzzy.Distance(key, otherKey)
+		if d > maxTypos && strings.Join(slices.Sorted(slices.Values(other)), " ") != sortedKey {
+			continue
+		}
+		matches = append(matches, match{SimilarTitle{
+			ID:    p.ID,
+			Title: p.Title(),
+			Pack:  storage.PackFromPath(p.FilePath),
+			Path:  p.FilePath,
+		}, d})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	var similar []SimilarTitle
+	for _, m := range matches {
+		if len(similar) == maxSimilarTitles {
+			break
+		}
+		similar = append(similar, m.SimilarTitle)
+	}
+	return similar
+}
+
+// titleWords splits a title into its lowercase words, without accents or
+// punctuation
+func titleWords(title string) []string {
+	return strings.FieldsFunc(strings.ToLower(fuzzy.Fold(title)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
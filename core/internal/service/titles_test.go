@@ -0,0 +1,58 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSimilarTitles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// A prompt in an installed pack
+	pack := filepath.Join(tmpDir, "team")
+	os.MkdirAll(filepath.Join(pack, "prompts"), 0755)
+	os.WriteFile(filepath.Join(pack, "pack.json"), []byte(`{"name": "team", "version": "1.0.0", "title": "Team Pack"}`), 0644)
+	os.WriteFile(filepath.Join(pack, "prompts", "team-review.md"), []byte("---\nid: team-review\ntitle: 'Review: Code'\n---\nReview the diff.\n"), 0644)
+
+	svc, err := OpenLibrary(filepath.Join(tmpDir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if _, err := svc.InstallPack(pack, config.PackInstallOptions{}); err != nil {
+		t.Fatalf("InstallPack: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "code-review", Name: "Code Review", Content: "Review this code."},
+		{ID: "summarize", Name: "Summarise text", Content: "Summarize this."},
+		{ID: "code-reviewer", Name: "Code reviewer persona", Content: "You review code."},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	similar := svc.SimilarTitles("code review", "new-review")
+	if len(similar) != 2 || similar[0].ID != "code-review" || similar[1].ID != "team-review" {
+		t.Fatalf("SimilarTitles(code review) = %+v, want code-review then team-review", similar)
+	}
+	if similar[1].Pack != "team" || similar[1].Path != "packs/team/prompts/team-review.md" {
+		t.Errorf("Pack prompt reported in pack %q at %q", similar[1].Pack, similar[1].Path)
+	}
+	if similar := svc.SimilarTitles("Summarize Text!", "new"); len(similar) != 1 || similar[0].ID != "summarize" {
+		t.Errorf("SimilarTitles(Summarize Text!) = %+v, want summarize", similar)
+	}
+	if similar := svc.SimilarTitles("Code Review", "code-review"); len(similar) != 1 || similar[0].ID != "team-review" {
+		t.Errorf("SimilarTitles should leave out the prompt itself, got %+v", similar)
+	}
+	if similar := svc.SimilarTitles("Commit message", "new"); len(similar) != 0 {
+		t.Errorf("SimilarTitles(Commit message) = %+v, want none", similar)
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/language"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+// PromptLanguage returns the language prompt is written in: the one its
+// frontmatter names, or else the one detected from its content. It is ""
+// when the prompt is too short to tell.
+func (s *Service) PromptLanguage(prompt *models.Prompt) string {
+	if prompt.Language != "" {
+		return prompt.Language
+	}
+	return language.Detect(s.promptContent(prompt))
+}
+
+// FilterByLanguage returns the prompts written in lang. A language without
+// a region, such as pt, also matches its regional variants, such as pt-BR.
+func (s *Service) FilterByLanguage(prompts []*models.Prompt, lang string) ([]*models.Prompt, error) {
+	code := language.Normalize(lang)
+	if code == "" {
+		return nil, fmt.Errorf("invalid language %q (use a language code such as de or pt-BR)", lang)
+	}
+	var matched []*models.Prompt
+	for _, p := range prompts {
+		if found := s.PromptLanguage(p); found == code || strings.HasPrefix(found, code+"-") {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// Translations returns the prompts translated from the prompt with id,
+// sorted by language
+func (s *Service) Translations(id string) ([]*models.Prompt, error) {
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+	var translations []*models.Prompt
+	for _, p := range prompts {
+		if p.TranslationOf == id {
+			translations = append(translations, p)
+		}
+	}
+	sort.Slice(translations, func(i, j int) bool { return translations[i].Language < translations[j].Language })
+	return translations, nil
+}
+
+// TranslatePrompt translates the prompt with id into the language to with
+// the translate command, returning a new prompt linked to it by
+// translation_of. The new prompt's ID is newID, or the original's with the
+// language appended, and it sits beside the original. Nothing is saved.
+func (s *Service) TranslatePrompt(id, to, newID string) (*models.Prompt, error) {
+	command := s.settings.Translate.Command
+	if command == "" {
+		return nil, fmt.Errorf("no translate command is configured (set translate.command to a command that translates stdin into $POCKET_PROMPT_LANGUAGE)")
+	}
+	target := language.Normalize(to)
+	if target == "" {
+		return nil, fmt.Errorf("invalid language %q (use a language code such as de or pt-BR)", to)
+	}
+	original, err := s.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	content := s.promptContent(original)
+	if original.Language == target || (original.Language == "" && language.Detect(content) == target) {
+		return nil, fmt.Errorf("prompt %s is already in %s", id, language.Name(target))
+	}
+
+	if newID == "" {
+		newID = id + "-" + strings.ToLower(target)
+	}
+	if _, err := s.GetPrompt(newID); err == nil {
+		return nil, fmt.Errorf("prompt %s already exists (choose another ID with --id)", newID)
+	}
+
+	translate := func(text string) (string, error) {
+		if strings.TrimSpace(text) == "" {
+			return text, nil
+		}
+		return s.runTranslateCommand(command, text, target)
+	}
+	translated := &models.Prompt{
+		ID:            newID,
+		Version:       "1.0.0",
+		Tags:          original.StoredTags(),
+		TemplateRef:   original.TemplateRef,
+		Pack:          original.Pack,
+		Language:      target,
+		TranslationOf: original.ID,
+		Metadata:      original.Metadata,
+		Attachments:   original.Attachments,
+		Images:        original.Images,
+		OutputSchema:  original.OutputSchema,
+		Variables:     original.Variables,
+		ContextSlot:   original.ContextSlot,
+	}
+	if translated.Name, err = translate(original.Name); err != nil {
+		return nil, fmt.Errorf("failed to translate the title of %s: %w", id, err)
+	}
+	if translated.Summary, err = translate(original.Summary); err != nil {
+		return nil, fmt.Errorf("failed to translate the description of %s: %w", id, err)
+	}
+	if translated.Content, err = translate(content); err != nil {
+		return nil, fmt.Errorf("failed to translate %s: %w", id, err)
+	}
+
+	// Variables must survive translation, or renders would break
+	for _, placeholder := range templateVariable.FindAllString(content, -1) {
+		if !strings.Contains(translated.Content, placeholder) {
+			return nil, fmt.Errorf("the translation of %s lost the placeholder %s", id, placeholder)
+		}
+	}
+
+	translated.FilePath, err = s.PromptFilePath(storage.PackFromPath(original.FilePath), storage.PromptSubdir(original.FilePath), newID)
+	if err != nil {
+		return nil, err
+	}
+	return translated, nil
+}
+
+// runTranslateCommand runs the translate command with text on stdin and
+// returns what it prints, trimmed
+func (s *Service) runTranslateCommand(command, text, target string) (string, error) {
+	timeout, err := s.settings.Translate.CommandTimeout(config.DefaultTranslateTimeout)
+	if err != nil {
+		return "", err
+	}
+	env := []string{
+		"POCKET_PROMPT_LANGUAGE=" + language.Name(target),
+		"POCKET_PROMPT_LANGUAGE_CODE=" + target,
+	}
+	out, err := s.runShellCommand(command, text, timeout, env)
+	if err != nil {
+		return "", err
+	}
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return "", fmt.Errorf("it printed nothing")
+	}
+	return out, nil
+}
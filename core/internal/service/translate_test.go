@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestTranslatePrompt(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	greet := &models.Prompt{ID: "greet", Name: "Greet", Tags: []string{"chat"}, Content: "Greet {{name}} and ask how the day is going."}
+	if err := svc.CreatePrompt(greet); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	if greet.Language != "en" {
+		t.Errorf("Detected language = %q, want en", greet.Language)
+	}
+
+	if _, err := svc.TranslatePrompt("greet", "de", ""); err == nil {
+		t.Error("Expected an error without a translate command")
+	}
+	svc.Settings().Translate.Command = `[ "$POCKET_PROMPT_LANGUAGE" = German ] && sed -e 's/Greet/Begrüße/' -e 's/ and ask how the day is going/ und frage, wie der Tag läuft/'`
+	if _, err := svc.TranslatePrompt("greet", "en", ""); err == nil {
+		t.Error("Expected an error translating into the prompt's own language")
+	}
+
+	translated, err := svc.TranslatePrompt("greet", "DE", "")
+	if err != nil {
+		t.Fatalf("TranslatePrompt: %v", err)
+	}
+	if translated.ID != "greet-de" || translated.Language != "de" || translated.TranslationOf != "greet" {
+		t.Errorf("Translation is %s in %q of %q, want greet-de in de of greet", translated.ID, translated.Language, translated.TranslationOf)
+	}
+	if translated.Name != "Begrüße" || translated.Content != "Begrüße {{name}} und frage, wie der Tag läuft." {
+		t.Errorf("Translated title %q and content %q", translated.Name, translated.Content)
+	}
+	if err := svc.CreatePrompt(translated); err != nil {
+		t.Fatalf("Failed to save translation: %v", err)
+	}
+
+	all, err := svc.ListPrompts()
+	if err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+	german, err := svc.FilterByLanguage(all, "de")
+	if err != nil {
+		t.Fatalf("FilterByLanguage: %v", err)
+	}
+	if len(german) != 1 || german[0].ID != "greet-de" {
+		t.Errorf("FilterByLanguage(de) = %d prompts, want greet-de", len(german))
+	}
+	translations, err := svc.Translations("greet")
+	if err != nil || len(translations) != 1 || translations[0].ID != "greet-de" {
+		t.Errorf("Translations(greet) = %v, %v", translations, err)
+	}
+
+	svc.Settings().Translate.Command = `sed 's/{{name}}/NAME/'`
+	if _, err := svc.TranslatePrompt("greet", "fr", ""); err == nil {
+		t.Error("Expected an error when the translation loses a placeholder")
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dpshade/pocket-prompt/internal/keychain"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// MaskedValue is shown in place of sensitive variable values
+const MaskedValue = "••••••"
+
+// DeclaredVariables returns the variables a prompt declares, followed by the
+// slots of its template
+func (s *Service) DeclaredVariables(prompt *models.Prompt, template *models.Template) []models.Variable {
+	declared := append([]models.Variable{}, prompt.Variables...)
+	if template != nil {
+		for _, slot := range template.Slots {
+			declared = append(declared, slot.Variable())
+		}
+	}
+	return declared
+}
+
+// FillVariables completes values for a render. A declared variable without a
+// value is read from its environment variable or keychain entry when
+// readSecrets is set, asked for with ask when it is sensitive and ask is not
+// nil, and otherwise falls back to its default. values is not modified.
+func (s *Service) FillVariables(declared []models.Variable, values map[string]interface{}, readSecrets bool, ask func(models.Variable) (string, error)) (map[string]interface{}, error) {
+	filled := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		filled[name] = value
+	}
+
+	for _, v := range declared {
+		if _, ok := filled[v.Name]; ok {
+			continue
+		}
+		if readSecrets {
+			value, found, err := secretValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("variable %s: %w", v.Name, err)
+			}
+			if found {
+				filled[v.Name] = value
+				continue
+			}
+		}
+		if v.Sensitive && ask != nil {
+			value, err := ask(v)
+			if err != nil {
+				return nil, fmt.Errorf("variable %s: %w", v.Name, err)
+			}
+			filled[v.Name] = value
+			continue
+		}
+		if v.Default != "" {
+			filled[v.Name] = v.Default
+		}
+	}
+	return filled, nil
+}
+
+// MaskSensitive returns a copy of values with sensitive variables masked, for display
+func MaskSensitive(declared []models.Variable, values map[string]interface{}) map[string]interface{} {
+	masked := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		masked[name] = value
+	}
+	for _, v := range declared {
+		if _, ok := masked[v.Name]; ok && v.Sensitive {
+			masked[v.Name] = MaskedValue
+		}
+	}
+	return masked
+}
+
+// secretValue reads a variable from its environment variable, then its keychain entry
+func secretValue(v models.Variable) (string, bool, error) {
+	if v.Env != "" {
+		if value, ok := os.LookupEnv(v.Env); ok {
+			return value, true, nil
+		}
+	}
+	if v.Keychain != "" {
+		value, err := keychain.Lookup(v.Keychain)
+		if errors.Is(err, keychain.ErrNotFound) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		return value, true, nil
+	}
+	return "", false, nil
+}
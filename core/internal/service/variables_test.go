@@ -0,0 +1,75 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestRenderPromptSecrets(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	prompt := &models.Prompt{
+		ID:      "call-api",
+		Name:    "Call API",
+		Content: "Use key {{api_key}} for {{customer}} in {{region}}.",
+		Variables: []models.Variable{
+			{Name: "api_key", Sensitive: true, Env: "PKT_TEST_API_KEY"},
+			{Name: "customer", Sensitive: true},
+			{Name: "region", Default: "eu"},
+		},
+	}
+	if err := svc.CreatePrompt(prompt); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	t.Setenv("PKT_TEST_API_KEY", "sk-123")
+
+	// Renders served to others never read secret sources
+	rendered, err := svc.RenderPrompt("call-api", RenderOptions{})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	if want := "Use key {{api_key}} for {{customer}} in eu."; rendered != want {
+		t.Errorf("rendered = %q, want %q", rendered, want)
+	}
+
+	var asked []string
+	rendered, err = svc.RenderPrompt("call-api", RenderOptions{
+		ReadSecrets: true,
+		AskSecret: func(v models.Variable) (string, error) {
+			asked = append(asked, v.Name)
+			return "Ana", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+	if want := "Use key sk-123 for Ana in eu."; rendered != want {
+		t.Errorf("rendered = %q, want %q", rendered, want)
+	}
+	if len(asked) != 1 || asked[0] != "customer" {
+		t.Errorf("expected to be asked only for customer, asked for %v", asked)
+	}
+
+	saved, err := svc.GetPrompt("call-api")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if len(saved.Variables) != 3 || !saved.Variables[0].Sensitive || saved.Variables[0].Env != "PKT_TEST_API_KEY" {
+		t.Errorf("variables did not round-trip: %+v", saved.Variables)
+	}
+
+	masked := MaskSensitive(saved.Variables, map[string]interface{}{"api_key": "sk-123", "region": "eu"})
+	if masked["api_key"] != MaskedValue || masked["region"] != "eu" {
+		t.Errorf("masked = %v", masked)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// VariantRandom picks a variant of a group at random, for blind testing
+const VariantRandom = "random"
+
+// VariantStats compares one variant of an experiment with the others
+type VariantStats struct {
+	ID       string                `json:"id"`
+	Title    string                `json:"title"`
+	Version  string                `json:"version"`
+	Uses     int                   `json:"uses"`
+	Outcomes models.OutcomeSummary `json:"outcomes"`
+}
+
+// ListVariants returns the prompts whose variant_group is group, sorted by ID
+func (s *Service) ListVariants(group string) ([]*models.Prompt, error) {
+	if group == "" {
+		return nil, fmt.Errorf("variant group is required")
+	}
+	prompts, err := s.activePrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []*models.Prompt
+	for _, p := range prompts {
+		if p.VariantGroup == group {
+			variants = append(variants, p)
+		}
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("no prompts in variant group: %s", group)
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+	return variants, nil
+}
+
+// CompareVariants returns each variant of group with how often it has been
+// used and how its logged outcomes went
+func (s *Service) CompareVariants(group string) ([]VariantStats, error) {
+	variants, err := s.ListVariants(group)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.usage.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]VariantStats, 0, len(variants))
+	for _, p := range variants {
+		outcomes, err := s.storage.LoadOutcomes(p)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, VariantStats{
+			ID:       p.ID,
+			Title:    p.Name,
+			Version:  p.Version,
+			Uses:     usage[p.ID].Count,
+			Outcomes: models.SummarizeOutcomes(outcomes),
+		})
+	}
+	return stats, nil
+}
+
+// PickVariant chooses one variant of group. VariantRandom is the only mode.
+func (s *Service) PickVariant(group, mode string) (*models.Prompt, error) {
+	if mode != VariantRandom {
+		return nil, fmt.Errorf("unknown variant selection %q (use %s)", mode, VariantRandom)
+	}
+	variants, err := s.ListVariants(group)
+	if err != nil {
+		return nil, err
+	}
+	return variants[rand.Intn(len(variants))], nil
+}
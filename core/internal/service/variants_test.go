@@ -0,0 +1,64 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestVariants(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	svc, err := OpenLibrary(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "summary-b", Name: "Summary B", Version: "1.0.0", Content: "Summarize briefly", VariantGroup: "summary"},
+		{ID: "summary-a", Name: "Summary A", Version: "1.0.0", Content: "Summarize", VariantGroup: "summary"},
+		{ID: "other", Name: "Other", Version: "1.0.0", Content: "Other"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+
+	variants, err := svc.ListVariants("summary")
+	if err != nil {
+		t.Fatalf("ListVariants: %v", err)
+	}
+	if len(variants) != 2 || variants[0].ID != "summary-a" || variants[1].ID != "summary-b" {
+		t.Fatalf("variants = %v, want summary-a and summary-b", variants)
+	}
+	if _, err := svc.ListVariants("missing"); err == nil {
+		t.Error("listing an empty variant group succeeded")
+	}
+
+	if _, err := svc.LogOutcome("summary-a", models.OutcomeGood, ""); err != nil {
+		t.Fatalf("LogOutcome: %v", err)
+	}
+	svc.RecordUsage("summary-a")
+	stats, err := svc.CompareVariants("summary")
+	if err != nil {
+		t.Fatalf("CompareVariants: %v", err)
+	}
+	if stats[0].Uses != 1 || stats[0].Outcomes.Good != 1 || stats[1].Outcomes.Total() != 0 {
+		t.Errorf("stats = %+v, want one use and one good outcome for summary-a only", stats)
+	}
+
+	picked, err := svc.PickVariant("summary", VariantRandom)
+	if err != nil {
+		t.Fatalf("PickVariant: %v", err)
+	}
+	if picked.VariantGroup != "summary" {
+		t.Errorf("picked %s, which is not in the summary group", picked.ID)
+	}
+	if _, err := svc.PickVariant("summary", "best"); err == nil {
+		t.Error("an unknown selection mode was accepted")
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+)
+
+// VersionDiff compares two versions of a prompt
+type VersionDiff struct {
+	ID      string   `json:"id"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Notes   []string `json:"notes,omitempty"` // Metadata changes, e.g. a new title or tags
+	Diff    []string `json:"diff"`            // See diff.Lines
+	Added   int      `json:"lines_added"`
+	Removed int      `json:"lines_removed"`
+}
+
+// DiffVersions compares two versions of a prompt, from the archive or git
+// history. An empty to compares the latest version, and an empty from the
+// version before to. Versions may be written with a leading "v".
+func (s *Service) DiffVersions(id, from, to string) (*VersionDiff, error) {
+	current, err := s.GetPrompt(id)
+	if err != nil {
+		current = nil
+	}
+	archived, err := s.ListArchive(ArchiveFilter{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	if current == nil && len(archived) == 0 {
+		return nil, fmt.Errorf("prompt not found: %s", id)
+	}
+
+	snapshots := s.promptSnapshots(current, archived)
+	if len(snapshots) < 2 {
+		return nil, fmt.Errorf("%s has only one version", id)
+	}
+	find := func(version string) (int, error) {
+		version = strings.TrimPrefix(version, "v")
+		var known []string
+		for i, snap := range snapshots {
+			if snap.prompt.Version == version {
+				return i, nil
+			}
+			known = append(known, snap.prompt.Version)
+		}
+		return 0, fmt.Errorf("version not found: %s v%s (versions: %s)", id, version, strings.Join(known, ", "))
+	}
+
+	toIndex := len(snapshots) - 1
+	if to != "" {
+		if toIndex, err = find(to); err != nil {
+			return nil, err
+		}
+	}
+	fromIndex := toIndex - 1
+	if from != "" {
+		if fromIndex, err = find(from); err != nil {
+			return nil, err
+		}
+	} else if fromIndex < 0 {
+		return nil, fmt.Errorf("v%s is the first version of %s", snapshots[toIndex].prompt.Version, id)
+	}
+
+	before, after := snapshots[fromIndex].prompt, snapshots[toIndex].prompt
+	result := &VersionDiff{
+		ID:    id,
+		From:  before.Version,
+		To:    after.Version,
+		Notes: describeChange(before, snapshots[toIndex]).Notes,
+		Diff:  diff.Lines(before.Content, after.Content),
+	}
+	result.Added, result.Removed = diff.Count(result.Diff)
+	return result, nil
+}
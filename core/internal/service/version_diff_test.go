@@ -0,0 +1,63 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestDiffVersions(t *testing.T) {
+	svc, err := OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "notes", Name: "Notes", Version: "1.0.0", Content: "one\ntwo\nthree"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	if _, err := svc.DiffVersions("notes", "", ""); err == nil {
+		t.Fatal("expected an error for a prompt with one version")
+	}
+
+	prompt, err := svc.GetPrompt("notes")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	first := prompt.Version
+	updated := *prompt
+	updated.Name = "Meeting Notes"
+	updated.Content = "one\n2\nthree\nfour"
+	if err := svc.UpdatePrompt(&updated); err != nil {
+		t.Fatalf("UpdatePrompt: %v", err)
+	}
+
+	d, err := svc.DiffVersions("notes", "", "")
+	if err != nil {
+		t.Fatalf("DiffVersions: %v", err)
+	}
+	if d.From != first || d.To == first {
+		t.Errorf("compared v%s with v%s, want v%s with the latest", d.From, d.To, first)
+	}
+	want := []string{"  one", "- two", "+ 2", "  three", "+ four"}
+	if !reflect.DeepEqual(d.Diff, want) || d.Added != 2 || d.Removed != 1 {
+		t.Errorf("diff = %q (+%d/-%d), want %q (+2/-1)", d.Diff, d.Added, d.Removed, want)
+	}
+	if len(d.Notes) != 1 || d.Notes[0] != `title "Notes" → "Meeting Notes"` {
+		t.Errorf("notes = %v", d.Notes)
+	}
+
+	reverse, err := svc.DiffVersions("notes", "v"+d.To, first)
+	if err != nil {
+		t.Fatalf("DiffVersions backwards: %v", err)
+	}
+	if reverse.Added != 1 || reverse.Removed != 2 {
+		t.Errorf("backwards diff = +%d/-%d, want +1/-2", reverse.Added, reverse.Removed)
+	}
+
+	if _, err := svc.DiffVersions("notes", "9.9.9", ""); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+	if _, err := svc.DiffVersions("missing", "", ""); err == nil {
+		t.Error("expected an error for an unknown prompt")
+	}
+}
@@ -0,0 +1,236 @@
+// Package speech reads text aloud through the operating system's own
+// text-to-speech engine: say on macOS, espeak-ng or espeak on Linux and
+// PowerShell's System.Speech on Windows. The engines can also write the
+// speech to an audio file; formats an engine does not write itself are
+// converted with ffmpeg when it is installed.
+package speech
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Options choose how text is spoken
+type Options struct {
+	Voice string // The engine's name for a voice; empty for its default
+	Rate  int    // Words per minute; 0 for the engine's default
+	Out   string // An audio file to write instead of speaking
+}
+
+// engine is an installed text-to-speech command
+type engine struct {
+	name    string
+	path    string
+	formats []string // Audio file extensions it writes, the first preferred
+}
+
+// Speak reads text aloud, or writes it to opts.Out
+func Speak(text string, opts Options) error {
+	e, err := findEngine()
+	if err != nil {
+		return err
+	}
+	if opts.Out == "" {
+		return run(e.command(opts, ""), text)
+	}
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(opts.Out), "."))
+	if ext == "" {
+		return fmt.Errorf("%s has no extension to pick an audio format by, such as .%s", opts.Out, e.formats[0])
+	}
+	if slices.Contains(e.formats, ext) {
+		return run(e.command(opts, opts.Out), text)
+	}
+
+	// Write a format the engine knows and convert it
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("%s writes %s files; install ffmpeg to write .%s", e.name, strings.Join(e.formats, ", "), ext)
+	}
+	tmp, err := os.CreateTemp("", "pkt-speech-*."+e.formats[0])
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	if err := run(e.command(opts, tmp.Name()), text); err != nil {
+		return err
+	}
+	return run(exec.Command(ffmpeg, "-y", "-loglevel", "error", "-i", tmp.Name(), opts.Out), "")
+}
+
+// findEngine finds the platform's text-to-speech command
+func findEngine() (*engine, error) {
+	var candidates []engine
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = []engine{{name: "say", formats: []string{"aiff", "aif", "m4a", "caf"}}}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		candidates = []engine{
+			{name: "espeak-ng", formats: []string{"wav"}},
+			{name: "espeak", formats: []string{"wav"}},
+		}
+	case "windows":
+		candidates = []engine{{name: "powershell", formats: []string{"wav"}}}
+	default:
+		return nil, fmt.Errorf("text to speech is not supported on %s", runtime.GOOS)
+	}
+
+	for _, e := range candidates {
+		if path, err := exec.LookPath(e.name); err == nil {
+			e.path = path
+			return &e, nil
+		}
+	}
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return nil, fmt.Errorf("%s is not available (this should not happen on %s)", candidates[0].name, runtime.GOOS)
+	}
+	return nil, fmt.Errorf("no text-to-speech engine found. Install espeak-ng:\n" +
+		"  • Ubuntu/Debian: sudo apt install espeak-ng\n" +
+		"  • Fedora/RHEL: sudo dnf install espeak-ng\n" +
+		"  • Arch: sudo pacman -S espeak-ng")
+}
+
+// command is the engine's command reading text from stdin and speaking it,
+// or writing it to out
+func (e *engine) command(opts Options, out string) *exec.Cmd {
+	var args []string
+	switch e.name {
+	case "say":
+		if opts.Voice != "" {
+			args = append(args, "-v", opts.Voice)
+		}
+		if opts.Rate > 0 {
+			args = append(args, "-r", fmt.Sprint(opts.Rate))
+		}
+		if out != "" {
+			args = append(args, "-o", out)
+		}
+	case "espeak-ng", "espeak":
+		if opts.Voice != "" {
+			args = append(args, "-v", opts.Voice)
+		}
+		if opts.Rate > 0 {
+			args = append(args, "-s", fmt.Sprint(opts.Rate))
+		}
+		if out != "" {
+			args = append(args, "-w", out)
+		}
+		args = append(args, "--stdin")
+	case "powershell":
+		args = []string{"-NoProfile", "-NonInteractive", "-Command", windowsSpeech(opts, out)}
+	}
+	return exec.Command(e.path, args...)
+}
+
+// windowsSpeech is a PowerShell script speaking stdin through the
+// System.Speech synthesizer, which ships with Windows
+func windowsSpeech(opts Options, out string) string {
+	script := []string{
+		"[Console]::InputEncoding = [Text.Encoding]::UTF8",
+		"Add-Type -AssemblyName System.Speech",
+		"$speech = New-Object System.Speech.Synthesis.SpeechSynthesizer",
+	}
+	if opts.Voice != "" {
+		script = append(script, "$speech.SelectVoice("+powerShellString(opts.Voice)+")")
+	}
+	if opts.Rate > 0 {
+		script = append(script, fmt.Sprintf("$speech.Rate = %d", windowsRate(opts.Rate)))
+	}
+	if out != "" {
+		script = append(script, "$speech.SetOutputToWaveFile("+powerShellString(out)+")")
+	}
+	script = append(script, "$speech.Speak([Console]::In.ReadToEnd())", "$speech.Dispose()")
+	return strings.Join(script, "\n")
+}
+
+// windowsRate converts words per minute to System.Speech's rate, from -10 to
+// 10 with 0 at about 180 words per minute
+func windowsRate(wordsPerMinute int) int {
+	return max(-10, min(10, (wordsPerMinute-180)/20))
+}
+
+// powerShellString quotes s as a PowerShell single-quoted string, in which
+// only the quote itself needs escaping
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// run runs cmd with input on stdin
+func run(cmd *exec.Cmd, input string) error {
+	cmd.Stdin = strings.NewReader(input)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", filepath.Base(cmd.Args[0]), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+var (
+	htmlComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	fenceLine    = regexp.MustCompile("^[ \t]*(```|~~~)")
+	headingMarks = regexp.MustCompile(`^ {0,3}#{1,6}[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	listMarker   = regexp.MustCompile(`^[ \t]*(?:[-*+]|>)[ \t]+`)
+	ruleLine     = regexp.MustCompile(`^[ \t]*(?:[-*_][ \t]*){3,}$`)
+	tableRule    = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	link         = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	emphasis     = []*regexp.Regexp{
+		regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`),
+		regexp.MustCompile(`\b__(\S(?:.*?\S)?)__\b`),
+		regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`),
+		regexp.MustCompile(`\b_(\S(?:[^_]*?\S)?)_\b`),
+		regexp.MustCompile("`([^`]*)`"),
+	}
+	sentenceClose = regexp.MustCompile(`[.!?:;,]$`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+)
+
+// Text returns Markdown as it is best read aloud: without heading marks,
+// emphasis, list bullets, link targets, rules and comments, and with a full
+// stop after headings so the engine pauses there. Code is kept, without its
+// fences.
+func Text(markdown string) string {
+	markdown = htmlComment.ReplaceAllString(markdown, "")
+	var lines []string
+	inCode := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if fenceLine.MatchString(line) {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, line)
+			continue
+		}
+		if ruleLine.MatchString(line) || tableRule.MatchString(line) {
+			continue
+		}
+		heading := false
+		if match := headingMarks.FindStringSubmatch(line); match != nil {
+			line, heading = match[1], true
+		}
+		line = listMarker.ReplaceAllString(line, "")
+		line = link.ReplaceAllString(line, "$1")
+		for _, marks := range emphasis {
+			line = marks.ReplaceAllString(line, "$1")
+		}
+		if strings.Contains(line, "|") {
+			cells := strings.FieldsFunc(line, func(r rune) bool { return r == '|' })
+			for i := range cells {
+				cells[i] = strings.TrimSpace(cells[i])
+			}
+			line = strings.Join(cells, ", ")
+		}
+		line = strings.TrimSpace(line)
+		if heading && line != "" && !sentenceClose.MatchString(line) {
+			line += "."
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
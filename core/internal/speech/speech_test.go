@@ -0,0 +1,40 @@
+package speech
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	markdown := "# Code Review\n\nReview the **diff** for _bugs_ and see [the guide](https://example.com/guide).\n\n<!-- internal note -->\n- Check `errors`\n- Check tests\n\n---\n\n| Field | Type |\n|-------|------|\n| id | int |\n\n```sh\ngo test ./... | tee out.txt\n```\n\n## Output: ##\n> Be brief"
+
+	want := "Code Review.\n\nReview the diff for bugs and see the guide.\n\nCheck errors\nCheck tests\n\nField, Type\nid, int\n\ngo test ./... | tee out.txt\n\nOutput:\nBe brief"
+	if got := Text(markdown); got != want {
+		t.Errorf("Text =\n%q\nwant\n%q", got, want)
+	}
+	if got := Text("Use snake_case names and 2 * 3"); got != "Use snake_case names and 2 * 3" {
+		t.Errorf("Text changed words that are not emphasis: %q", got)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	opts := Options{Voice: "en-us", Rate: 220}
+	espeak := &engine{name: "espeak-ng", path: "/usr/bin/espeak-ng", formats: []string{"wav"}}
+	if got := strings.Join(espeak.command(opts, "out.wav").Args, " "); got != "/usr/bin/espeak-ng -v en-us -s 220 -w out.wav --stdin" {
+		t.Errorf("espeak-ng command = %s", got)
+	}
+	say := &engine{name: "say", path: "say"}
+	if got := strings.Join(say.command(Options{}, "").Args, " "); got != "say" {
+		t.Errorf("say command = %s", got)
+	}
+
+	script := windowsSpeech(Options{Voice: "Microsoft Zira's", Rate: 260}, `C:\out.wav`)
+	for _, want := range []string{"SelectVoice('Microsoft Zira''s')", "$speech.Rate = 4", `SetOutputToWaveFile('C:\out.wav')`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Windows script lacks %s:\n%s", want, script)
+		}
+	}
+	if windowsRate(1000) != 10 || windowsRate(20) != -8 {
+		t.Errorf("windowsRate(1000) = %d, windowsRate(20) = %d", windowsRate(1000), windowsRate(20))
+	}
+}
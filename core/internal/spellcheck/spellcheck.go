@@ -0,0 +1,163 @@
+// Package spellcheck finds misspelled words in prompt text. It uses aspell or
+// hunspell with the system's default dictionary when one is installed, and
+// otherwise a built-in list of common English misspellings, which only knows
+// those words but never flags a correct one.
+//
+// Placeholders, code, URLs and HTML comments are not checked, so variable
+// names and identifiers are never reported.
+package spellcheck
+
+import (
+	_ "embed"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Engines, as set in ui.spellcheck
+const (
+	EngineAuto     = "auto" // aspell or hunspell when installed, else the built-in list
+	EngineAspell   = "aspell"
+	EngineHunspell = "hunspell"
+	EngineBuiltin  = "builtin"
+	EngineOff      = "off"
+)
+
+// Misspelling is a word the checker does not know
+type Misspelling struct {
+	Word       string `json:"word"`
+	Line       int    `json:"line"`                 // Where the word first appears, from 1
+	Suggestion string `json:"suggestion,omitempty"` // The likely intended word, when known
+}
+
+// Checker checks text with one engine
+type Checker struct {
+	engine  string
+	command []string // aspell or hunspell and the arguments that make it list misspelled words
+}
+
+//go:embed misspellings.txt
+var misspellingList string
+
+// corrections maps common misspellings to the intended word
+var corrections = parseCorrections(misspellingList)
+
+var (
+	fencedCode  = regexp.MustCompile("(?ms)^[ \t]*(```|~~~).*?^[ \t]*(```|~~~)[ \t]*$")
+	unchecked   = regexp.MustCompile("`[^`\n]*`|\\{\\{.*?\\}\\}|<!--.*?-->|<[^>\n]*>|\\b(?:https?|ftp|file)://\\S+|\\S+@\\S+\\.\\w+")
+	wordPattern = regexp.MustCompile(`[\p{L}]+(?:'[\p{L}]+)*`)
+)
+
+// New returns a checker using engine, or nil when engine is off. Auto picks
+// aspell, then hunspell, then the built-in list. Asking for aspell or
+// hunspell when it is not installed is an error.
+func New(engine string) (*Checker, error) {
+	switch engine {
+	case EngineOff:
+		return nil, nil
+	case EngineBuiltin:
+		return &Checker{engine: EngineBuiltin}, nil
+	case "", EngineAuto:
+		for _, name := range []string{EngineAspell, EngineHunspell} {
+			if c, err := New(name); err == nil {
+				return c, nil
+			}
+		}
+		return &Checker{engine: EngineBuiltin}, nil
+	case EngineAspell, EngineHunspell:
+		path, err := exec.LookPath(engine)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not installed", engine)
+		}
+		args := []string{"list"}
+		if engine == EngineHunspell {
+			args = []string{"-l"}
+		}
+		return &Checker{engine: engine, command: append([]string{path}, args...)}, nil
+	default:
+		return nil, fmt.Errorf("unknown spell checker %q (use auto, aspell, hunspell, builtin or off)", engine)
+	}
+}
+
+// Engine names the engine the checker uses
+func (c *Checker) Engine() string {
+	return c.engine
+}
+
+// Check returns the misspelled words in text in the order they first
+// appear, each once
+func (c *Checker) Check(text string) ([]Misspelling, error) {
+	prose := Prose(text)
+	unknown := map[string]bool{}
+	if c.command != nil {
+		cmd := exec.Command(c.command[0], c.command[1:]...)
+		cmd.Stdin = strings.NewReader(prose)
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("%s failed: %w", c.engine, err)
+		}
+		for _, word := range strings.Fields(string(out)) {
+			unknown[word] = true
+		}
+	}
+
+	var found []Misspelling
+	seen := map[string]bool{}
+	for i, line := range strings.Split(prose, "\n") {
+		for _, word := range wordPattern.FindAllString(line, -1) {
+			if seen[word] {
+				continue
+			}
+			suggestion, known := corrections[strings.ToLower(word)]
+			if !known && !unknown[word] {
+				continue
+			}
+			seen[word] = true
+			found = append(found, Misspelling{Word: word, Line: i + 1, Suggestion: matchCase(suggestion, word)})
+		}
+	}
+	return found, nil
+}
+
+// Prose returns text with what is not prose, such as code, placeholders and
+// URLs, blanked out. Lines stay where they are.
+func Prose(text string) string {
+	blank := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '\n' {
+				return r
+			}
+			return ' '
+		}, s)
+	}
+	text = fencedCode.ReplaceAllStringFunc(text, blank)
+	return unchecked.ReplaceAllStringFunc(text, blank)
+}
+
+// matchCase capitalizes suggestion like word
+func matchCase(suggestion, word string) string {
+	if suggestion == "" {
+		return ""
+	}
+	first := []rune(word)[0]
+	if unicode.IsUpper(first) {
+		runes := []rune(suggestion)
+		runes[0] = unicode.ToUpper(runes[0])
+		return string(runes)
+	}
+	return suggestion
+}
+
+// parseCorrections reads lines of a misspelling and its correction
+func parseCorrections(list string) map[string]string {
+	corrections := map[string]string{}
+	for _, line := range strings.Split(list, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && !strings.HasPrefix(line, "#") {
+			corrections[fields[0]] = fields[1]
+		}
+	}
+	return corrections
+}
@@ -0,0 +1,83 @@
+package spellcheck
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinCheck(t *testing.T) {
+	checker, err := New(EngineBuiltin)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	text := "Teh reviewer should recieve the diff.\n" +
+		"Use `recieve()` and {{teh_value}} as given.\n" +
+		"```\nteh code\n```\n" +
+		"See https://example.com/recieve and seperate the parts. Teh end.\n"
+
+	got, err := checker.Check(text)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	want := []Misspelling{
+		{Word: "Teh", Line: 1, Suggestion: "The"},
+		{Word: "recieve", Line: 1, Suggestion: "receive"},
+		{Word: "seperate", Line: 6, Suggestion: "separate"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Check = %+v, want %+v", got, want)
+	}
+}
+
+func TestProseKeepsLines(t *testing.T) {
+	text := "a {{name}}\n```go\nx := 1\n```\nb"
+	prose := Prose(text)
+	if strings.Count(prose, "\n") != strings.Count(text, "\n") {
+		t.Errorf("Prose(%q) = %q, want the same lines", text, prose)
+	}
+	if strings.Contains(prose, "name") || strings.Contains(prose, "x := 1") {
+		t.Errorf("Prose(%q) = %q, want placeholders and code blanked", text, prose)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if c, err := New(EngineOff); c != nil || err != nil {
+		t.Errorf("New(off) = %v, %v, want no checker", c, err)
+	}
+	if c, err := New(""); err != nil || c == nil {
+		t.Errorf("New(auto) = %v, %v, want a checker", c, err)
+	}
+	if _, err := New("word"); err == nil {
+		t.Error("New with an unknown engine succeeded")
+	}
+}
+
+func TestExternalCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as aspell")
+	}
+	// A stand-in aspell that finds one word it does not know in its input
+	dir := t.TempDir()
+	script := "#!/bin/sh\ngrep -o Kubernetez\n"
+	if err := os.WriteFile(filepath.Join(dir, "aspell"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	checker, err := New(EngineAuto)
+	if err != nil || checker.Engine() != EngineAspell {
+		t.Fatalf("New(auto) = %v, %v, want aspell", checker, err)
+	}
+	got, err := checker.Check("Deploy to Kubernetez.\nThen recieve {{Kubernetez}}.")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	want := []Misspelling{{Word: "Kubernetez", Line: 1}, {Word: "recieve", Line: 2, Suggestion: "receive"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Check = %+v, want %+v", got, want)
+	}
+}
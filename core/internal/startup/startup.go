@@ -0,0 +1,125 @@
+// Package startup times the phases of starting pocket-prompt for
+// --profile-startup. Phases are recorded only once Enable is called, so the
+// calls left in the startup path cost nothing otherwise. Each phase is also a
+// runtime/trace region, which shows up in 'go tool trace' when a trace is
+// being written.
+package startup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/trace"
+	"sync"
+	"time"
+)
+
+// Phase is one timed step of startup
+type Phase struct {
+	Name     string
+	Start    time.Duration // When it began, since Enable
+	Duration time.Duration // How long it took; zero for a mark or an unfinished phase
+	Mark     bool          // A moment rather than a step, such as the TUI becoming ready
+	Done     bool
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	began   time.Time
+	phases  []*Phase
+)
+
+// Enable starts recording phases. Startup is timed from this call.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+	began = time.Now()
+	phases = nil
+}
+
+// Enabled reports whether phases are being recorded
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Begin starts timing the named phase and returns the function that ends it.
+// It must be ended on the goroutine that began it.
+func Begin(name string) (end func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return func() {}
+	}
+
+	region := trace.StartRegion(context.Background(), name)
+	phase := &Phase{Name: name, Start: time.Since(began)}
+	phases = append(phases, phase)
+	return func() {
+		region.End()
+		mu.Lock()
+		defer mu.Unlock()
+		phase.Duration = time.Since(began) - phase.Start
+		phase.Done = true
+	}
+}
+
+// Mark records that the named moment has been reached, the first time only
+func Mark(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return
+	}
+	for _, phase := range phases {
+		if phase.Mark && phase.Name == name {
+			return
+		}
+	}
+	trace.Log(context.Background(), "startup", name)
+	phases = append(phases, &Phase{Name: name, Start: time.Since(began), Mark: true, Done: true})
+}
+
+// Phases returns the phases recorded so far, in the order they began
+func Phases() []Phase {
+	mu.Lock()
+	defer mu.Unlock()
+	recorded := make([]Phase, len(phases))
+	for i, phase := range phases {
+		recorded[i] = *phase
+	}
+	return recorded
+}
+
+// Report writes the recorded phases to w as a table, for attaching to a
+// report of slow startup
+func Report(w io.Writer) {
+	recorded := Phases()
+	if len(recorded) == 0 {
+		return
+	}
+	mu.Lock()
+	total := time.Since(began)
+	mu.Unlock()
+
+	fmt.Fprintln(w, "Startup profile:")
+	for _, phase := range recorded {
+		switch {
+		case phase.Mark:
+			fmt.Fprintf(w, "  %-20s %10s  at %s\n", phase.Name, "", round(phase.Start))
+		case !phase.Done:
+			fmt.Fprintf(w, "  %-20s %10s  at %s\n", phase.Name, "unfinished", round(phase.Start))
+		default:
+			fmt.Fprintf(w, "  %-20s %10s  at %s\n", phase.Name, round(phase.Duration), round(phase.Start))
+		}
+	}
+	fmt.Fprintf(w, "  %-20s %10s\n", "total", round(total))
+}
+
+// round shortens a duration to tens of microseconds
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
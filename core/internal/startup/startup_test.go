@@ -0,0 +1,41 @@
+package startup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPhasesRecordedOnlyWhenEnabled(t *testing.T) {
+	Begin("before enable")()
+	Mark("not recorded")
+	if got := Phases(); len(got) != 0 {
+		t.Fatalf("Phases before Enable = %+v, want none", got)
+	}
+
+	Enable()
+	Begin("storage init")()
+	endLoad := Begin("cache load")
+	Mark("TUI ready")
+	Mark("TUI ready")
+
+	got := Phases()
+	if len(got) != 3 {
+		t.Fatalf("Phases = %+v, want storage init, cache load and one TUI ready", got)
+	}
+	if !got[0].Done || got[1].Done || !got[2].Mark {
+		t.Errorf("Phases = %+v", got)
+	}
+
+	var report strings.Builder
+	Report(&report)
+	for _, want := range []string{"storage init", "cache load", "unfinished", "TUI ready", "total"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("Report is missing %q:\n%s", want, report.String())
+		}
+	}
+
+	endLoad()
+	if got := Phases(); !got[1].Done {
+		t.Errorf("cache load not finished after its end func: %+v", got[1])
+	}
+}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// AnalyticsDir is where usage analytics are kept, under .pocket-prompt/. It
+// belongs to this clone of the library and is never committed.
+const AnalyticsDir = "analytics"
+
+const (
+	analyticsSettingsFile = "settings.json"
+	analyticsEventsFile   = "events.jsonl"
+)
+
+// analyticsTrimBytes is the size at which the event log is cut back to its
+// newest maxAnalyticsEvents events, so recording one only appends to the file
+const analyticsTrimBytes = 4 << 20
+
+// maxAnalyticsEvents bounds the event log; older events are dropped
+const maxAnalyticsEvents = 20000
+
+// What analytics can be recorded, each opted into on its own
+const (
+	AnalyticsUses     = "uses"     // Prompts copied and rendered
+	AnalyticsSearches = "searches" // Searches run, with whether they found anything
+	AnalyticsCommands = "commands" // CLI command names, without their arguments
+	AnalyticsDigest   = "digest"   // Add this device's most used prompts to digests it sends
+)
+
+// AnalyticsScopes lists every scope in the order they are shown
+var AnalyticsScopes = []string{AnalyticsUses, AnalyticsSearches, AnalyticsCommands, AnalyticsDigest}
+
+// Kinds of analytics events
+const (
+	EventUse     = "use"
+	EventSearch  = "search"
+	EventCommand = "command"
+)
+
+// AnalyticsSettings are the scopes opted into, with when each was
+type AnalyticsSettings struct {
+	Enabled map[string]time.Time `json:"enabled"`
+}
+
+// Has reports whether scope is opted into
+func (s *AnalyticsSettings) Has(scope string) bool {
+	_, ok := s.Enabled[scope]
+	return ok
+}
+
+// AnalyticsEvent is one recorded use of the library
+type AnalyticsEvent struct {
+	Time  time.Time `json:"time"`
+	Kind  string    `json:"kind"`            // EventUse, EventSearch or EventCommand
+	Name  string    `json:"name"`            // Prompt ID, query or command name
+	Via   string    `json:"via,omitempty"`   // Kind of search: "search", "boolean" or "saved-search"
+	Empty bool      `json:"empty,omitempty"` // A search that found nothing
+}
+
+// AnalyticsLog keeps opt-in usage analytics in .pocket-prompt/analytics/:
+// the scopes opted into in settings.json and the events recorded under them
+// in events.jsonl, one JSON object per line. Nothing is recorded until a
+// scope is enabled, and nothing in it is ever sent anywhere.
+type AnalyticsLog struct {
+	mu       sync.Mutex
+	dir      string
+	settings *AnalyticsSettings // Loaded on first use
+	readOnly bool
+}
+
+// NewAnalyticsLog creates the analytics log for the library at baseDir
+func NewAnalyticsLog(baseDir string) *AnalyticsLog {
+	return &AnalyticsLog{dir: filepath.Join(baseDir, ".pocket-prompt", AnalyticsDir)}
+}
+
+// Dir returns the directory analytics are kept in
+func (l *AnalyticsLog) Dir() string {
+	return l.dir
+}
+
+// EventsPath returns the file events are recorded in
+func (l *AnalyticsLog) EventsPath() string {
+	return filepath.Join(l.dir, analyticsEventsFile)
+}
+
+// SetReadOnly stops Record from recording events. Reads are unaffected.
+func (l *AnalyticsLog) SetReadOnly(readOnly bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.readOnly = readOnly
+}
+
+// Settings returns the scopes opted into. None are until the user enables one.
+func (l *AnalyticsLog) Settings() (*AnalyticsSettings, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	settings, err := l.loadSettings()
+	if err != nil {
+		return nil, err
+	}
+	copied := &AnalyticsSettings{Enabled: make(map[string]time.Time, len(settings.Enabled))}
+	for scope, since := range settings.Enabled {
+		copied.Enabled[scope] = since
+	}
+	return copied, nil
+}
+
+// SaveSettings records the scopes opted into
+func (l *AnalyticsLog) SaveSettings(settings *AnalyticsSettings) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.readOnly {
+		return ErrReadOnly
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal analytics settings: %w", err)
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create analytics directory: %w", err)
+	}
+	path := filepath.Join(l.dir, analyticsSettingsFile)
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write analytics settings: %w", err)
+	}
+	l.settings = settings
+	return nil
+}
+
+// loadSettings reads settings.json once, which may not exist yet
+func (l *AnalyticsLog) loadSettings() (*AnalyticsSettings, error) {
+	if l.settings != nil {
+		return l.settings, nil
+	}
+	settings := &AnalyticsSettings{}
+	path := filepath.Join(l.dir, analyticsSettingsFile)
+	tracing.Read(path)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read analytics settings: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, settings); err != nil {
+			return nil, fmt.Errorf("failed to parse analytics settings: %w", err)
+		}
+	}
+	if settings.Enabled == nil {
+		settings.Enabled = make(map[string]time.Time)
+	}
+	l.settings = settings
+	return settings, nil
+}
+
+// Record appends an event to the log when its scope is opted into, trimming
+// the log once it grows past analyticsTrimBytes
+func (l *AnalyticsLog) Record(scope string, event AnalyticsEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.readOnly {
+		return nil
+	}
+	settings, err := l.loadSettings()
+	if err != nil || !settings.Has(scope) {
+		return err
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analytics event: %w", err)
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create analytics directory: %w", err)
+	}
+	path := l.EventsPath()
+	tracing.Write(path)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open analytics log: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write analytics log: %w", err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > analyticsTrimBytes {
+		events, err := l.load()
+		if err != nil {
+			return err
+		}
+		return l.save(events[len(events)-min(len(events), maxAnalyticsEvents):])
+	}
+	return nil
+}
+
+// Events returns the recorded events, oldest first
+func (l *AnalyticsLog) Events() ([]AnalyticsEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.load()
+}
+
+// Purge deletes every recorded event, keeping the scopes opted into, and
+// returns how many there were
+func (l *AnalyticsLog) Purge() (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.readOnly {
+		return 0, ErrReadOnly
+	}
+	events, err := l.load()
+	if err != nil {
+		return 0, err
+	}
+	path := l.EventsPath()
+	tracing.Delete(path)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to purge analytics: %w", err)
+	}
+	return len(events), nil
+}
+
+// load reads the event log, skipping lines that cannot be parsed, such as
+// one cut short by a crash
+func (l *AnalyticsLog) load() ([]AnalyticsEvent, error) {
+	path := l.EventsPath()
+	tracing.Read(path)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analytics log: %w", err)
+	}
+	var events []AnalyticsEvent
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event AnalyticsEvent
+		if json.Unmarshal(scanner.Bytes(), &event) == nil && event.Kind != "" {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// save replaces the event log with events
+func (l *AnalyticsLog) save(events []AnalyticsEvent) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to marshal analytics event: %w", err)
+		}
+	}
+	path := l.EventsPath()
+	tracing.Write(path)
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write analytics log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// AssetsDir holds files prompts attach, such as images for multimodal prompts
+// and example inputs. Attachments are referenced by their slash-separated path
+// from the library root, e.g. assets/review/diagram.png.
+const AssetsDir = "assets"
+
+// CleanAttachmentPath normalises an attachment reference and checks that it
+// stays inside the assets directory
+func CleanAttachmentPath(rel string) (string, error) {
+	cleaned := filepath.ToSlash(filepath.Clean(filepath.FromSlash(rel)))
+	if filepath.IsAbs(rel) || !strings.HasPrefix(cleaned, AssetsDir+"/") {
+		return "", fmt.Errorf("attachment %q must be a path under %s/", rel, AssetsDir)
+	}
+	return cleaned, nil
+}
+
+// AssetPath returns the absolute path of an attachment in the library
+func (s *Storage) AssetPath(rel string) (string, error) {
+	cleaned, err := CleanAttachmentPath(rel)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.rootPath, filepath.FromSlash(cleaned)), nil
+}
+
+// ImportAsset copies the file at src into assets/<promptID>/ and returns the
+// attachment reference for it. An existing asset with the same name is replaced.
+func (s *Storage) ImportAsset(src, promptID string) (string, error) {
+	if err := s.writable(); err != nil {
+		return "", err
+	}
+	rel := AssetsDir + "/" + promptID + "/" + filepath.Base(src)
+	dest, err := s.AssetPath(rel)
+	if err != nil {
+		return "", err
+	}
+	if err := CopyFile(src, dest); err != nil {
+		return "", err
+	}
+	return rel, nil
+}
+
+// DeleteAsset removes an attachment file, and its prompt's asset folder once empty
+func (s *Storage) DeleteAsset(rel string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	path, err := s.AssetPath(rel)
+	if err != nil {
+		return err
+	}
+	tracing.Delete(path)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", rel, err)
+	}
+	os.Remove(filepath.Dir(path)) // Only succeeds when empty
+	return nil
+}
+
+// CopyFile copies src to dest, creating dest's directory
+func CopyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", src)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tracing.Write(dest)
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
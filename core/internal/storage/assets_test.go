@@ -0,0 +1,26 @@
+package storage
+
+import "testing"
+
+func TestCleanAttachmentPath(t *testing.T) {
+	cases := map[string]string{
+		"assets/review/diagram.png":    "assets/review/diagram.png",
+		"assets/./review//diagram.png": "assets/review/diagram.png",
+		"assets/../prompts/secret.md":  "",
+		"../assets/diagram.png":        "",
+		"/assets/diagram.png":          "",
+		"assets":                       "",
+	}
+	for input, want := range cases {
+		got, err := CleanAttachmentPath(input)
+		if want == "" {
+			if err == nil {
+				t.Fatalf("CleanAttachmentPath(%q) = %q, want error", input, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Fatalf("CleanAttachmentPath(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+}
@@ -12,21 +12,33 @@ import (
 	"time"
 
 	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
 )
 
 // PromptMetadata represents cached metadata for a prompt
 type PromptMetadata struct {
-	ID          string            `json:"id"`
-	Version     string            `json:"version"`
-	Name        string            `json:"name"`
-	Summary     string            `json:"summary"`
-	Tags        []string          `json:"tags"`
-	TemplateRef string            `json:"template_ref,omitempty"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
-	FilePath    string            `json:"file_path"`
-	ModTime     time.Time         `json:"mod_time"`
-	FileHash    string            `json:"file_hash"`
+	ID            string         `json:"id"`
+	Version       string         `json:"version"`
+	Name          string         `json:"name"`
+	Summary       string         `json:"summary"`
+	Tags          []string       `json:"tags"`
+	TemplateRef   string         `json:"template_ref,omitempty"`
+	Pack          string         `json:"pack,omitempty"`
+	VariantGroup  string         `json:"variant_group,omitempty"`
+	Language      string         `json:"language,omitempty"`
+	TranslationOf string         `json:"translation_of,omitempty"`
+	Protected     bool           `json:"protected,omitempty"`
+	Expires       string         `json:"expires,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	FilePath      string         `json:"file_path"`
+	ModTime       time.Time      `json:"mod_time"`
+	FileHash      string         `json:"file_hash"`
+	Format        string         `json:"format,omitempty"`
+	Review        *models.Review `json:"review,omitempty"`
+	Attachments   []string       `json:"attachments,omitempty"`
+	Images        []string       `json:"images,omitempty"`
+	References    []string       `json:"references,omitempty"`
 }
 
 // MetadataCache handles caching of prompt metadata
@@ -59,6 +71,7 @@ func (c *MetadataCache) Load() error {
 		return nil // No cache file exists yet
 	}
 
+	tracing.Read(c.cacheFile)
 	data, err := os.ReadFile(c.cacheFile)
 	if err != nil {
 		return fmt.Errorf("failed to read cache file: %w", err)
@@ -83,6 +96,7 @@ func (c *MetadataCache) Save() error {
 		return fmt.Errorf("failed to marshal cache: %w", err)
 	}
 
+	tracing.Write(c.cacheFile)
 	if err := os.WriteFile(c.cacheFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
@@ -118,17 +132,28 @@ func (c *MetadataCache) Set(relPath string, fullPath string, fileInfo os.FileInf
 
 	c.mu.Lock()
 	c.metadata[relPath] = &PromptMetadata{
-		ID:          prompt.ID,
-		Version:     prompt.Version,
-		Name:        prompt.Name,
-		Summary:     prompt.Summary,
-		Tags:        prompt.Tags,
-		TemplateRef: prompt.TemplateRef,
-		CreatedAt:   prompt.CreatedAt,
-		UpdatedAt:   prompt.UpdatedAt,
-		FilePath:    prompt.FilePath,
-		ModTime:     fileInfo.ModTime(),
-		FileHash:    fileHash,
+		ID:            prompt.ID,
+		Version:       prompt.Version,
+		Name:          prompt.Name,
+		Summary:       prompt.Summary,
+		Tags:          prompt.StoredTags(),
+		TemplateRef:   prompt.TemplateRef,
+		Pack:          prompt.Pack,
+		VariantGroup:  prompt.VariantGroup,
+		Language:      prompt.Language,
+		TranslationOf: prompt.TranslationOf,
+		Protected:     prompt.Protected,
+		Expires:       prompt.Expires,
+		CreatedAt:     prompt.CreatedAt,
+		UpdatedAt:     prompt.UpdatedAt,
+		FilePath:      prompt.FilePath,
+		ModTime:       fileInfo.ModTime(),
+		FileHash:      fileHash,
+		Format:        prompt.Format,
+		Review:        prompt.Review,
+		Attachments:   prompt.Attachments,
+		Images:        prompt.Images,
+		References:    prompt.References,
 	}
 	c.mu.Unlock()
 }
@@ -136,19 +161,37 @@ func (c *MetadataCache) Set(relPath string, fullPath string, fileInfo os.FileInf
 // ToPrompt converts cached metadata back to a Prompt (without content)
 func (m *PromptMetadata) ToPrompt() *models.Prompt {
 	return &models.Prompt{
-		ID:          m.ID,
-		Version:     m.Version,
-		Name:        m.Name,
-		Summary:     m.Summary,
-		Tags:        m.Tags,
-		TemplateRef: m.TemplateRef,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
-		FilePath:    m.FilePath,
-		Content:     "", // Content loaded on demand
+		ID:            m.ID,
+		Version:       m.Version,
+		Name:          m.Name,
+		Summary:       m.Summary,
+		Tags:          m.Tags,
+		TemplateRef:   m.TemplateRef,
+		Pack:          m.Pack,
+		VariantGroup:  m.VariantGroup,
+		Language:      m.Language,
+		TranslationOf: m.TranslationOf,
+		Protected:     m.Protected,
+		Expires:       m.Expires,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+		FilePath:      m.FilePath,
+		Format:        m.Format,
+		Review:        m.Review,
+		Attachments:   m.Attachments,
+		Images:        m.Images,
+		References:    m.References,
+		Content:       "", // Content loaded on demand
 	}
 }
 
+// Reset drops every cache entry, so the next listing re-reads each file
+func (c *MetadataCache) Reset() {
+	c.mu.Lock()
+	c.metadata = make(map[string]*PromptMetadata)
+	c.mu.Unlock()
+}
+
 // Cleanup removes cache entries for files that no longer exist
 func (c *MetadataCache) Cleanup(existingFiles map[string]bool) {
 	c.mu.Lock()
@@ -163,4 +206,4 @@ func (c *MetadataCache) Cleanup(existingFiles map[string]bool) {
 // IsArchived checks if a metadata entry represents an archived prompt
 func (m *PromptMetadata) IsArchived() bool {
 	return strings.HasPrefix(m.FilePath, "archive/")
-}
\ No newline at end of file
+}
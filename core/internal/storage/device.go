@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// deviceFile holds the name this clone of the library records analytics
+// under. It is never committed, so every clone gets its own.
+const deviceFile = "device"
+
+// DeviceEnv overrides the device name, e.g. for a server whose clone is
+// recreated with each deployment
+const DeviceEnv = "POCKET_PROMPT_DEVICE"
+
+var deviceUnsafe = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// DeviceName returns the name of this device in the library at baseDir: the
+// POCKET_PROMPT_DEVICE variable, or else the name picked the first time,
+// made from the host name and a random suffix so two laptops with the same
+// host name do not share analytics files
+func DeviceName(baseDir string) (string, error) {
+	if name := cleanDeviceName(os.Getenv(DeviceEnv)); name != "" {
+		return name, nil
+	}
+	path := filepath.Join(baseDir, ".pocket-prompt", deviceFile)
+	tracing.Read(path)
+	if data, err := os.ReadFile(path); err == nil {
+		if name := cleanDeviceName(string(data)); name != "" {
+			return name, nil
+		}
+	}
+
+	host, _ := os.Hostname()
+	host = cleanDeviceName(strings.Split(host, ".")[0])
+	if host == "" {
+		host = "device"
+	}
+	suffix := make([]byte, 2)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to name device: %w", err)
+	}
+	name := host + "-" + hex.EncodeToString(suffix)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create device file: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write device file: %w", err)
+	}
+	return name, nil
+}
+
+// cleanDeviceName makes name safe to use as a file name
+func cleanDeviceName(name string) string {
+	return strings.Trim(deviceUnsafe.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-"), "-")
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+const digestStateFile = "digest.json"
+
+// DigestState remembers when the last digest was sent, so the next one
+// covers the changes made since
+type DigestState struct {
+	LastSent time.Time `json:"last_sent"`
+}
+
+func digestStatePath(baseDir string) string {
+	return filepath.Join(baseDir, ".pocket-prompt", digestStateFile)
+}
+
+// LoadDigestState reads the digest state, returning a zero state before the first digest
+func LoadDigestState(baseDir string) (*DigestState, error) {
+	state := &DigestState{}
+	tracing.Read(digestStatePath(baseDir))
+	data, err := os.ReadFile(digestStatePath(baseDir))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read digest state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse digest state: %w", err)
+	}
+	return state, nil
+}
+
+// SaveDigestState records the digest state in .pocket-prompt/digest.json
+func SaveDigestState(baseDir string, state *DigestState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest state: %w", err)
+	}
+	path := digestStatePath(baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write digest state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+const emailStateFile = "email.json"
+
+// EmailState remembers how far the email gateway has read a mailbox, so each
+// message is imported once. UIDs are only comparable while UIDValidity is unchanged.
+type EmailState struct {
+	Mailbox     string `json:"mailbox"`
+	UIDValidity uint32 `json:"uid_validity"`
+	LastUID     uint32 `json:"last_uid"`
+}
+
+func emailStatePath(baseDir string) string {
+	return filepath.Join(baseDir, ".pocket-prompt", emailStateFile)
+}
+
+// LoadEmailState reads the gateway state, returning a zero state before the first check
+func LoadEmailState(baseDir string) (*EmailState, error) {
+	state := &EmailState{}
+	tracing.Read(emailStatePath(baseDir))
+	data, err := os.ReadFile(emailStatePath(baseDir))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse email state: %w", err)
+	}
+	return state, nil
+}
+
+// SaveEmailState records the gateway state in .pocket-prompt/email.json
+func SaveEmailState(baseDir string, state *EmailState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal email state: %w", err)
+	}
+	path := emailStatePath(baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write email state: %w", err)
+	}
+	return nil
+}
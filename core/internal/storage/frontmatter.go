@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported frontmatter formats
+const (
+	FormatYAML = "yaml" // --- delimited
+	FormatTOML = "toml" // +++ delimited
+	FormatJSON = "json" // leading JSON object
+)
+
+// ValidFrontmatterFormat reports whether format names a supported frontmatter format
+func ValidFrontmatterFormat(format string) bool {
+	switch format {
+	case FormatYAML, FormatTOML, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// splitFrontmatter detects the frontmatter format and separates it from the markdown body
+func splitFrontmatter(content []byte) (format string, frontmatter string, body string, err error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	// JSON frontmatter is a single object at the top of the file
+	if strings.HasPrefix(text, "{") {
+		decoder := json.NewDecoder(strings.NewReader(text))
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return "", "", "", fmt.Errorf("invalid JSON frontmatter: %w", err)
+		}
+		return FormatJSON, string(raw), text[decoder.InputOffset():], nil
+	}
+
+	firstLine, rest, _ := strings.Cut(text, "\n")
+	var delimiter string
+	switch firstLine {
+	case "---":
+		format, delimiter = FormatYAML, "---"
+	case "+++":
+		format, delimiter = FormatTOML, "+++"
+	default:
+		return "", "", "", fmt.Errorf("missing frontmatter delimiter")
+	}
+
+	lines := strings.Split(rest, "\n")
+	for i, line := range lines {
+		if line == delimiter {
+			return format, strings.Join(lines[:i], "\n"), strings.Join(lines[i+1:], "\n"), nil
+		}
+	}
+
+	// Unterminated frontmatter: treat the whole file as metadata
+	return format, rest, "", nil
+}
+
+// trimBody normalizes the markdown body that follows the frontmatter block
+func trimBody(body string) string {
+	body = strings.TrimSuffix(body, "\n")
+	return strings.TrimLeft(body, " \t\n")
+}
+
+// decodeFrontmatterMap parses frontmatter of any supported format into a generic map
+func decodeFrontmatterMap(format, frontmatter string) (map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+
+	var err error
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal([]byte(frontmatter), &raw)
+	case FormatTOML:
+		_, err = toml.Decode(frontmatter, &raw)
+	case FormatJSON:
+		err = json.Unmarshal([]byte(frontmatter), &raw)
+	default:
+		err = fmt.Errorf("unsupported frontmatter format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = make(map[string]interface{})
+	}
+
+	return raw, nil
+}
+
+// decodeFrontmatter parses frontmatter into out using its yaml struct tags,
+// so models only need a single set of field names for every format
+func decodeFrontmatter(format, frontmatter string, out interface{}) error {
+	if format == FormatYAML {
+		return yaml.Unmarshal([]byte(frontmatter), out)
+	}
+
+	raw, err := decodeFrontmatterMap(format, frontmatter)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+// encodeFrontmatter serializes v as a delimited frontmatter block in the given format
+func encodeFrontmatter(format string, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch format {
+	case FormatYAML, "":
+		buf.WriteString("---\n")
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+
+	case FormatJSON:
+		keys, values, err := orderedFields(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("{\n")
+		for i, key := range keys {
+			value, err := json.MarshalIndent(values[i], "  ", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+			}
+			fmt.Fprintf(&buf, "  %q: %s", key, value)
+			if i < len(keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+
+	case FormatTOML:
+		keys, values, err := orderedFields(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("+++\n")
+		// TOML requires tables to follow all plain key/value pairs
+		var tables []int
+		for i, key := range keys {
+			if isTOMLTable(values[i]) {
+				tables = append(tables, i)
+				continue
+			}
+			if err := encodeTOMLField(&buf, key, values[i]); err != nil {
+				return nil, err
+			}
+		}
+		for _, i := range tables {
+			if err := encodeTOMLField(&buf, keys[i], values[i]); err != nil {
+				return nil, err
+			}
+		}
+		buf.WriteString("+++\n")
+
+	default:
+		return nil, fmt.Errorf("unsupported frontmatter format %q", format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// orderedFields flattens v into its frontmatter keys and values, keeping
+// the struct field order and omitempty rules defined by the yaml tags
+func orderedFields(v interface{}) ([]string, []interface{}, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, nil, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("frontmatter must be a mapping")
+	}
+
+	var keys []string
+	var values []interface{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value interface{}
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if value == nil {
+			continue
+		}
+		keys = append(keys, node.Content[i].Value)
+		values = append(values, value)
+	}
+
+	return keys, values, nil
+}
+
+// isTOMLTable reports whether value encodes as a TOML table or array of tables
+func isTOMLTable(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		for _, item := range v {
+			if _, ok := item.(map[string]interface{}); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func encodeTOMLField(buf *bytes.Buffer, key string, value interface{}) error {
+	encoder := toml.NewEncoder(buf)
+	encoder.Indent = ""
+	if err := encoder.Encode(map[string]interface{}{key: value}); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePromptFileFormats(t *testing.T) {
+	files := map[string]string{
+		FormatYAML: "---\nid: demo\nversion: 1.0.0\ntitle: Demo\ntags:\n  - a\n  - b\ncreated_at: 2024-05-01T10:00:00Z\n---\n\nBody text\n",
+		FormatTOML: "+++\nid = \"demo\"\nversion = \"1.0.0\"\ntitle = \"Demo\"\ntags = [\"a\", \"b\"]\ncreated_at = 2024-05-01T10:00:00Z\n+++\n\nBody text\n",
+		FormatJSON: "{\n  \"id\": \"demo\",\n  \"version\": \"1.0.0\",\n  \"title\": \"Demo\",\n  \"tags\": [\"a\", \"b\"],\n  \"created_at\": \"2024-05-01T10:00:00Z\"\n}\n\nBody text\n",
+	}
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for format, content := range files {
+		prompt, err := parsePromptFile([]byte(content))
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", format, err)
+		}
+		if prompt.Format != format {
+			t.Errorf("%s: detected format %q", format, prompt.Format)
+		}
+		if prompt.ID != "demo" || prompt.Name != "Demo" || strings.Join(prompt.Tags, ",") != "a,b" {
+			t.Errorf("%s: unexpected fields: %+v", format, prompt)
+		}
+		if !prompt.CreatedAt.Equal(created) {
+			t.Errorf("%s: expected created_at %v, got %v", format, created, prompt.CreatedAt)
+		}
+		if prompt.Content != "Body text" {
+			t.Errorf("%s: unexpected content %q", format, prompt.Content)
+		}
+
+		// Serializing must keep the original format and round-trip cleanly
+		prompt.Metadata = map[string]interface{}{"owner": "team"}
+		serialized, err := serializePrompt(prompt)
+		if err != nil {
+			t.Fatalf("%s: failed to serialize: %v", format, err)
+		}
+		reparsed, err := parsePromptFile(serialized)
+		if err != nil {
+			t.Fatalf("%s: failed to reparse:\n%s\n%v", format, serialized, err)
+		}
+		if reparsed.Format != format || reparsed.ID != "demo" || reparsed.Metadata["owner"] != "team" {
+			t.Errorf("%s: round trip lost data:\n%s", format, serialized)
+		}
+		if !reparsed.CreatedAt.Equal(created) {
+			t.Errorf("%s: round trip changed created_at to %v", format, reparsed.CreatedAt)
+		}
+	}
+}
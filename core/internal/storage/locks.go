@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// LocksDir holds one JSON file per locked prompt. It sits in the library
+// rather than .pocket-prompt/ so git sync shares locks with the team, and one
+// file per prompt keeps teammates' locks from conflicting.
+const LocksDir = "locks"
+
+func (s *Storage) lockPath(id string) string {
+	return filepath.Join(s.rootPath, LocksDir, id+".json")
+}
+
+// LoadLock returns the lock on a prompt, or nil when it has none
+func (s *Storage) LoadLock(id string) (*models.PromptLock, error) {
+	tracing.Read(s.lockPath(id))
+	data, err := os.ReadFile(s.lockPath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock: %w", err)
+	}
+	var lock models.PromptLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock for %s: %w", id, err)
+	}
+	return &lock, nil
+}
+
+// SaveLock writes a prompt's lock, replacing any earlier one
+func (s *Storage) SaveLock(lock *models.PromptLock) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(s.rootPath, LocksDir), 0755); err != nil {
+		return fmt.Errorf("failed to create locks directory: %w", err)
+	}
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock: %w", err)
+	}
+	tracing.Write(s.lockPath(lock.ID))
+	return os.WriteFile(s.lockPath(lock.ID), append(data, '\n'), 0644)
+}
+
+// DeleteLock removes a prompt's lock. Removing a lock that does not exist is
+// not an error.
+func (s *Storage) DeleteLock(id string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	tracing.Delete(s.lockPath(id))
+	if err := os.Remove(s.lockPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lock: %w", err)
+	}
+	return nil
+}
+
+// ListLocks returns every lock in the library sorted by prompt ID, skipping
+// files that fail to parse
+func (s *Storage) ListLocks() ([]*models.PromptLock, error) {
+	entries, err := os.ReadDir(filepath.Join(s.rootPath, LocksDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locks: %w", err)
+	}
+
+	var locks []*models.PromptLock
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		lock, err := s.LoadLock(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		locks = append(locks, lock)
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].ID < locks[j].ID })
+	return locks, nil
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// DiskUsage is the space used by one part of the library
+type DiskUsage struct {
+	Category string `json:"category"`
+	Files    int    `json:"files"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// usageCategories maps top-level library entries to the category they count
+// towards; everything else is "other"
+var usageCategories = map[string]string{
+	"prompts":        "prompts",
+	"templates":      "templates",
+	"archive":        "archive",
+	AssetsDir:        "assets",
+	"packs":          "packs",
+	ProfilesDir:      "profiles",
+	SearchesDir:      "saved searches",
+	".pocket-prompt": "settings and cache",
+	".git":           "git",
+}
+
+// prunableDirs are the library folders whose empty subdirectories are removed
+var prunableDirs = []string{"prompts", "templates", "archive", AssetsDir}
+
+// RebuildIndex discards the metadata cache and re-reads every prompt file,
+// returning how many prompts were indexed
+func (s *Storage) RebuildIndex() (int, error) {
+	s.cache.Reset()
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		return 0, err
+	}
+	if err := s.cache.Save(); err != nil {
+		return 0, err
+	}
+	return len(prompts), nil
+}
+
+// EmptyDirs returns the empty directories below the prompts, templates,
+// archive and assets folders, deepest first. A directory holding only empty
+// directories counts as empty.
+func (s *Storage) EmptyDirs() ([]string, error) {
+	var empty []string
+	for _, root := range prunableDirs {
+		if _, err := os.Stat(filepath.Join(s.rootPath, root)); os.IsNotExist(err) {
+			continue
+		}
+		if _, err := s.collectEmptyDirs(root, &empty); err != nil {
+			return nil, err
+		}
+	}
+	return empty, nil
+}
+
+// collectEmptyDirs appends the empty directories below relDir to empty and
+// reports whether relDir itself is empty
+func (s *Storage) collectEmptyDirs(relDir string, empty *[]string) (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(s.rootPath, relDir))
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", relDir, err)
+	}
+	isEmpty := true
+	for _, entry := range entries {
+		// Symlinks are left alone, even to empty directories
+		if !entry.IsDir() || entry.Type()&fs.ModeSymlink != 0 {
+			isEmpty = false
+			continue
+		}
+		child := filepath.Join(relDir, entry.Name())
+		childEmpty, err := s.collectEmptyDirs(child, empty)
+		if err != nil {
+			return false, err
+		}
+		if childEmpty {
+			*empty = append(*empty, child)
+		} else {
+			isEmpty = false
+		}
+	}
+	return isEmpty, nil
+}
+
+// RemoveDir removes an empty library directory
+func (s *Storage) RemoveDir(relDir string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	tracing.Delete(filepath.Join(s.rootPath, relDir))
+	return os.Remove(filepath.Join(s.rootPath, relDir))
+}
+
+// DeleteArchivedPrompt removes an archived version's file
+func (s *Storage) DeleteArchivedPrompt(prompt *models.Prompt) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(filepath.ToSlash(prompt.FilePath), "archive/") {
+		return fmt.Errorf("%s is not in the archive", prompt.FilePath)
+	}
+	tracing.Delete(filepath.Join(s.rootPath, prompt.FilePath))
+	return os.Remove(filepath.Join(s.rootPath, prompt.FilePath))
+}
+
+// DiskUsage reports the files and bytes in each part of the library, largest
+// first. Symlinks are counted as links, not followed.
+func (s *Storage) DiskUsage() ([]DiskUsage, error) {
+	totals := make(map[string]*DiskUsage)
+	err := filepath.WalkDir(s.rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.rootPath, path)
+		if err != nil {
+			return err
+		}
+		top := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+		category, ok := usageCategories[top]
+		if !ok {
+			category = "other"
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if totals[category] == nil {
+			totals[category] = &DiskUsage{Category: category}
+		}
+		totals[category].Files++
+		totals[category].Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure disk usage: %w", err)
+	}
+
+	usage := make([]DiskUsage, 0, len(totals))
+	for _, u := range totals {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Bytes != usage[j].Bytes {
+			return usage[i].Bytes > usage[j].Bytes
+		}
+		return usage[i].Category < usage[j].Category
+	})
+	return usage, nil
+}
+
+// FileSize is a library file and its size
+type FileSize struct {
+	Path  string `json:"path"` // Relative to the library, with forward slashes
+	Bytes int64  `json:"bytes"`
+}
+
+// LibraryFiles lists the files that make up the library, largest first:
+// everything but git repositories, its own and those of packs, and the
+// settings and cache in .pocket-prompt
+func (s *Storage) LibraryFiles() ([]FileSize, error) {
+	var files []FileSize
+	err := filepath.WalkDir(s.rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.rootPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if d.Name() == ".git" || rel == ".pocket-prompt" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, FileSize{Path: rel, Bytes: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure library files: %w", err)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Bytes != files[j].Bytes {
+			return files[i].Bytes > files[j].Bytes
+		}
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
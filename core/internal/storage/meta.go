@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// MetaFileName is the per-directory defaults file inside prompt folders
+const MetaFileName = "_meta.yaml"
+
+// DirectoryMeta holds defaults inherited by every prompt below a directory
+type DirectoryMeta struct {
+	Tags     []string               `yaml:"tags,omitempty"`
+	Pack     string                 `yaml:"pack,omitempty"`
+	Metadata map[string]interface{} `yaml:"metadata,omitempty"`
+}
+
+// metaCache memoizes parsed _meta.yaml files, invalidated by modification time
+type metaCache struct {
+	mu      sync.Mutex
+	entries map[string]metaCacheEntry
+}
+
+type metaCacheEntry struct {
+	modTime time.Time
+	meta    *DirectoryMeta
+}
+
+// loadDirectoryMeta reads the _meta.yaml in relDir, returning nil if there is none
+func (s *Storage) loadDirectoryMeta(relDir string) *DirectoryMeta {
+	path := filepath.Join(s.rootPath, relDir, MetaFileName)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	s.metas.mu.Lock()
+	defer s.metas.mu.Unlock()
+
+	if s.metas.entries == nil {
+		s.metas.entries = make(map[string]metaCacheEntry)
+	}
+	if entry, ok := s.metas.entries[path]; ok && entry.modTime.Equal(info.ModTime()) {
+		return entry.meta
+	}
+
+	tracing.Read(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var meta DirectoryMeta
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to parse %s: %v\n", filepath.Join(relDir, MetaFileName), err)
+		meta = DirectoryMeta{}
+	}
+
+	s.metas.entries[path] = metaCacheEntry{modTime: info.ModTime(), meta: &meta}
+	return &meta
+}
+
+// DirectoryDefaults merges the _meta.yaml files from the prompts root down to
+// the directory containing relPath. Deeper files add tags and override pack
+// and metadata values set higher up.
+func (s *Storage) DirectoryDefaults(relPath string) *DirectoryMeta {
+	defaults := &DirectoryMeta{}
+
+	dir := filepath.Dir(relPath)
+	subdir := PromptSubdir(relPath)
+	root := strings.TrimSuffix(dir, subdir)
+	root = strings.TrimSuffix(root, string(filepath.Separator))
+	if root == "" || (subdir == "" && !isPromptsRoot(dir)) {
+		return defaults
+	}
+
+	dirs := []string{root}
+	if subdir != "" {
+		current := root
+		for _, part := range strings.Split(subdir, string(filepath.Separator)) {
+			current = filepath.Join(current, part)
+			dirs = append(dirs, current)
+		}
+	}
+
+	for _, d := range dirs {
+		meta := s.loadDirectoryMeta(d)
+		if meta == nil {
+			continue
+		}
+		for _, tag := range meta.Tags {
+			if !hasTag(defaults.Tags, tag) {
+				defaults.Tags = append(defaults.Tags, tag)
+			}
+		}
+		if meta.Pack != "" {
+			defaults.Pack = meta.Pack
+		}
+		for key, value := range meta.Metadata {
+			if defaults.Metadata == nil {
+				defaults.Metadata = make(map[string]interface{})
+			}
+			defaults.Metadata[key] = value
+		}
+	}
+
+	return defaults
+}
+
+// isPromptsRoot reports whether dir is prompts/ or a pack's prompts/ folder
+func isPromptsRoot(dir string) bool {
+	parts := strings.Split(filepath.ToSlash(dir), "/")
+	return (len(parts) == 1 && parts[0] == "prompts") ||
+		(len(parts) == 3 && parts[0] == "packs" && parts[2] == "prompts")
+}
+
+// applyDirectoryDefaults fills in values inherited from the prompt's directory:
+// folder-name tags (when enabled) and _meta.yaml tags, pack and metadata.
+// Inherited tags are recorded in DerivedTags so they are never written back to the file.
+func (s *Storage) applyDirectoryDefaults(prompt *models.Prompt) {
+	var inherited []string
+	if s.directoryTags {
+		inherited = append(inherited, directoryTags(prompt.FilePath)...)
+	}
+
+	defaults := s.DirectoryDefaults(prompt.FilePath)
+	inherited = append(inherited, defaults.Tags...)
+
+	for _, tag := range inherited {
+		if !hasTag(prompt.Tags, tag) {
+			prompt.Tags = append(prompt.Tags, tag)
+			prompt.DerivedTags = append(prompt.DerivedTags, tag)
+		}
+	}
+
+	if prompt.Pack == "" && defaults.Pack != "" {
+		prompt.Pack = defaults.Pack
+	}
+
+	for key, value := range defaults.Metadata {
+		if prompt.Metadata == nil {
+			prompt.Metadata = make(map[string]interface{})
+		}
+		if _, exists := prompt.Metadata[key]; !exists {
+			prompt.Metadata[key] = value
+		}
+	}
+}
+
+// withoutDirectoryDefaults returns a copy of prompt with values that merely
+// repeat its directory defaults removed, so _meta.yaml stays the single source
+func (s *Storage) withoutDirectoryDefaults(prompt *models.Prompt) *models.Prompt {
+	defaults := s.DirectoryDefaults(prompt.FilePath)
+	stored := *prompt
+
+	if defaults.Pack != "" && stored.Pack == defaults.Pack {
+		stored.Pack = ""
+	}
+
+	if len(defaults.Metadata) > 0 && len(prompt.Metadata) > 0 {
+		stored.Metadata = make(map[string]interface{}, len(prompt.Metadata))
+		for key, value := range prompt.Metadata {
+			if inherited, ok := defaults.Metadata[key]; ok && reflect.DeepEqual(inherited, value) {
+				continue
+			}
+			stored.Metadata[key] = value
+		}
+	}
+
+	return &stored
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// legacyFieldAliases maps frontmatter keys used by older files to their schema 2 names
+var legacyFieldAliases = map[string]string{
+	"name":         "title",
+	"summary":      "description",
+	"template_ref": "template",
+	"created":      "created_at",
+	"updated":      "updated_at",
+}
+
+// MigrationChange describes the changes applied (or planned) for a single file
+type MigrationChange struct {
+	FilePath string   `json:"file_path"`
+	NewPath  string   `json:"new_path,omitempty"`
+	Actions  []string `json:"actions"`
+}
+
+// MigrationReport summarizes a library migration run
+type MigrationReport struct {
+	Scanned int               `json:"scanned"`
+	Changes []MigrationChange `json:"changes"`
+	Errors  []error           `json:"-"`
+	DryRun  bool              `json:"dry_run"`
+}
+
+// MigratePrompts upgrades every prompt file in the library to the current schema.
+// With dryRun set, the report lists planned changes without touching any files.
+func (s *Storage) MigratePrompts(dryRun bool) (*MigrationReport, error) {
+	if !dryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	report := &MigrationReport{DryRun: dryRun}
+
+	for _, dir := range s.promptDirs() {
+		if _, err := os.Stat(filepath.Join(s.rootPath, dir)); os.IsNotExist(err) {
+			continue
+		}
+
+		err := s.walkPromptFiles(dir, func(relPath string, info os.FileInfo) error {
+			report.Scanned++
+
+			change, err := s.migratePromptFile(relPath, dryRun)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Errorf("%s: %w", relPath, err))
+				return nil
+			}
+			if change != nil {
+				report.Changes = append(report.Changes, *change)
+			}
+			return nil
+		})
+		if err != nil {
+			return report, fmt.Errorf("failed to scan %s: %w", dir, err)
+		}
+	}
+
+	return report, nil
+}
+
+// promptDirs returns the library directories that hold prompt files
+func (s *Storage) promptDirs() []string {
+	dirs := []string{"prompts", "archive"}
+
+	packsDir := filepath.Join(s.rootPath, "packs")
+	if entries, err := os.ReadDir(packsDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, filepath.Join("packs", entry.Name(), "prompts"))
+			}
+		}
+	}
+
+	return dirs
+}
+
+// migratePromptFile upgrades a single prompt file, returning nil if it is already current
+func (s *Storage) migratePromptFile(relPath string, dryRun bool) (*MigrationChange, error) {
+	fullPath := filepath.Join(s.rootPath, relPath)
+	tracing.Read(fullPath)
+	original, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	format, frontmatter, body, err := splitFrontmatter(original)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := decodeFrontmatterMap(format, frontmatter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	change := &MigrationChange{FilePath: relPath}
+	change.Actions = upgradeFrontmatter(raw)
+	moved, err := keepUnknownFields(raw)
+	if err != nil {
+		return nil, err
+	}
+	change.Actions = append(change.Actions, moved...)
+
+	// Re-decode the upgraded frontmatter through the canonical model
+	upgraded, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode upgraded frontmatter: %w", err)
+	}
+	var prompt models.Prompt
+	if err := yaml.Unmarshal(upgraded, &prompt); err != nil {
+		return nil, fmt.Errorf("failed to decode upgraded frontmatter: %w", err)
+	}
+	prompt.Content = trimBody(body)
+	prompt.FilePath = relPath
+	prompt.Format = format
+	prompt.Schema = models.CurrentSchemaVersion
+
+	// Legacy archive mechanism: archived versions were tagged in place
+	// instead of living under archive/
+	if strings.HasPrefix(relPath, "prompts"+string(filepath.Separator)) && hasTag(prompt.Tags, "archive") {
+		prompt.FilePath = filepath.Join("archive", fmt.Sprintf("%s-v%s.md", prompt.ID, prompt.Version))
+		change.NewPath = prompt.FilePath
+		change.Actions = append(change.Actions, fmt.Sprintf("move archived version to %s", prompt.FilePath))
+	}
+
+	serialized, err := serializePrompt(&prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize prompt: %w", err)
+	}
+
+	if change.NewPath == "" && bytes.Equal(serialized, original) {
+		return nil, nil
+	}
+	if len(change.Actions) == 0 {
+		change.Actions = append(change.Actions, "normalize frontmatter formatting")
+	}
+
+	if dryRun {
+		return change, nil
+	}
+
+	if change.NewPath != "" {
+		if _, err := os.Stat(filepath.Join(s.rootPath, change.NewPath)); err == nil {
+			return nil, fmt.Errorf("archive target %s already exists", change.NewPath)
+		}
+	}
+
+	if err := s.SavePrompt(&prompt); err != nil {
+		return nil, err
+	}
+	if change.NewPath != "" {
+		tracing.Delete(fullPath)
+		if err := os.Remove(fullPath); err != nil {
+			return nil, fmt.Errorf("saved to %s but failed to remove original: %w", change.NewPath, err)
+		}
+	}
+
+	return change, nil
+}
+
+// upgradeFrontmatter rewrites legacy keys and values in place and describes what changed
+func upgradeFrontmatter(raw map[string]interface{}) []string {
+	var actions []string
+
+	// Sort aliases so reported actions are deterministic
+	legacyKeys := make([]string, 0, len(legacyFieldAliases))
+	for key := range legacyFieldAliases {
+		legacyKeys = append(legacyKeys, key)
+	}
+	sort.Strings(legacyKeys)
+
+	for _, legacy := range legacyKeys {
+		canonical := legacyFieldAliases[legacy]
+		value, ok := raw[legacy]
+		if !ok {
+			continue
+		}
+		if _, exists := raw[canonical]; !exists {
+			raw[canonical] = value
+			actions = append(actions, fmt.Sprintf("rename '%s' to '%s'", legacy, canonical))
+		} else {
+			actions = append(actions, fmt.Sprintf("drop duplicate legacy field '%s'", legacy))
+		}
+		delete(raw, legacy)
+	}
+
+	// Older files sometimes stored tags as a comma-separated string
+	if tagStr, ok := raw["tags"].(string); ok {
+		var tags []string
+		for _, tag := range strings.Split(tagStr, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		raw["tags"] = tags
+		actions = append(actions, "convert tags string to list")
+	}
+
+	if version, ok := raw["version"]; !ok || version == nil || version == "" {
+		raw["version"] = "1.0.0"
+		actions = append(actions, "set missing version to 1.0.0")
+	} else if _, isString := version.(string); !isString {
+		raw["version"] = fmt.Sprintf("%v", version)
+		actions = append(actions, "quote numeric version")
+	}
+
+	schema, _ := raw["schema"].(int)
+	if schema < models.CurrentSchemaVersion {
+		raw["schema"] = models.CurrentSchemaVersion
+		actions = append(actions, fmt.Sprintf("set schema to %d", models.CurrentSchemaVersion))
+	}
+
+	return actions
+}
+
+// keepUnknownFields moves top-level keys the prompt model has no field for
+// into metadata, where they survive the round trip through models.Prompt
+// instead of being dropped
+func keepUnknownFields(raw map[string]interface{}) ([]string, error) {
+	known := promptFrontmatterKeys()
+	var unknown []string
+	for key := range raw {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil, nil
+	}
+	sort.Strings(unknown)
+
+	metadata, ok := raw["metadata"].(map[string]interface{})
+	if !ok && raw["metadata"] != nil {
+		return nil, fmt.Errorf("cannot keep unknown fields %s: metadata is not a map", strings.Join(unknown, ", "))
+	}
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	var actions []string
+	for _, key := range unknown {
+		if _, exists := metadata[key]; exists {
+			return nil, fmt.Errorf("cannot move unknown field '%s' into metadata, which already has it", key)
+		}
+		metadata[key] = raw[key]
+		delete(raw, key)
+		actions = append(actions, fmt.Sprintf("move unknown field '%s' into metadata", key))
+	}
+	raw["metadata"] = metadata
+	return actions, nil
+}
+
+// promptFrontmatterKeys returns the frontmatter keys models.Prompt reads
+func promptFrontmatterKeys() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(models.Prompt{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// hasTag reports whether tags contains tag
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
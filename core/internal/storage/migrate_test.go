@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigratePromptsUpgradesLegacyFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-migrate-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	s, err := NewStorage(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := s.InitLibrary(); err != nil {
+		t.Fatalf("Failed to init library: %v", err)
+	}
+
+	legacy := "---\nid: legacy\nname: Legacy Prompt\nsummary: Old style\ntags: ai, writing\nversion: 2\n---\n\nHello\n"
+	archived := "---\nid: old\ntitle: Old\nversion: 1.0.0\ntags:\n  - archive\n---\n\nOld body\n"
+	writeFile(t, filepath.Join(tmpDir, "prompts", "legacy.md"), legacy)
+	writeFile(t, filepath.Join(tmpDir, "prompts", "old.md"), archived)
+
+	// Dry run must not touch files
+	report, err := s.MigratePrompts(true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(report.Changes) != 2 {
+		t.Fatalf("Expected 2 planned changes, got %d", len(report.Changes))
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, "prompts", "legacy.md")); string(data) != legacy {
+		t.Fatalf("Dry run modified legacy.md")
+	}
+
+	if _, err := s.MigratePrompts(false); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	prompt, err := s.LoadPrompt("prompts/legacy.md")
+	if err != nil {
+		t.Fatalf("Failed to load migrated prompt: %v", err)
+	}
+	if prompt.Name != "Legacy Prompt" || prompt.Summary != "Old style" {
+		t.Errorf("Legacy fields not renamed: name=%q summary=%q", prompt.Name, prompt.Summary)
+	}
+	if strings.Join(prompt.Tags, ",") != "ai,writing" {
+		t.Errorf("Expected tags [ai writing], got %v", prompt.Tags)
+	}
+	if prompt.Version != "2" || prompt.Schema != 2 {
+		t.Errorf("Unexpected version/schema: %q/%d", prompt.Version, prompt.Schema)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "prompts", "old.md")); !os.IsNotExist(err) {
+		t.Errorf("Archived prompt was not moved out of prompts/")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "archive", "old-v1.0.0.md")); err != nil {
+		t.Errorf("Archived prompt not found in archive/: %v", err)
+	}
+
+	// A second run should find nothing left to do
+	report, err = s.MigratePrompts(true)
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("Expected migration to be idempotent, got %d changes", len(report.Changes))
+	}
+}
+
+func TestMigratePromptsKeepsUnknownFields(t *testing.T) {
+	tmpDir := t.TempDir()
+	s, err := NewStorage(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := s.InitLibrary(); err != nil {
+		t.Fatalf("Failed to init library: %v", err)
+	}
+
+	legacy := "---\nid: custom\ntitle: Custom\nversion: 1.0.0\nauthor: Sam\nmodel: gpt-4o\nmetadata:\n  team: docs\n---\n\nBody\n"
+	writeFile(t, filepath.Join(tmpDir, "prompts", "custom.md"), legacy)
+
+	report, err := s.MigratePrompts(false)
+	if err != nil || len(report.Errors) > 0 {
+		t.Fatalf("Migration failed: %v %v", err, report.Errors)
+	}
+	if len(report.Changes) != 1 || !strings.Contains(strings.Join(report.Changes[0].Actions, "; "), "move unknown field 'author' into metadata") {
+		t.Fatalf("Expected the moved fields to be reported, got %+v", report.Changes)
+	}
+
+	prompt, err := s.LoadPrompt("prompts/custom.md")
+	if err != nil {
+		t.Fatalf("Failed to load migrated prompt: %v", err)
+	}
+	if prompt.Metadata["author"] != "Sam" || prompt.Metadata["model"] != "gpt-4o" || prompt.Metadata["team"] != "docs" {
+		t.Errorf("Unknown fields not kept in metadata: %v", prompt.Metadata)
+	}
+
+	// A field that is also in metadata would be lost, so the file is left alone
+	clash := "---\nid: clash\ntitle: Clash\nversion: 1.0.0\nauthor: Sam\nmetadata:\n  author: Alex\n---\n\nBody\n"
+	writeFile(t, filepath.Join(tmpDir, "prompts", "clash.md"), clash)
+	report, err = s.MigratePrompts(false)
+	if err != nil || len(report.Errors) != 1 {
+		t.Fatalf("Expected one error for the clashing field, got %v %v", err, report.Errors)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, "prompts", "clash.md")); string(data) != clash {
+		t.Errorf("Migration rewrote a file with a clashing field")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
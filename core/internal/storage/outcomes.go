@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// OutcomesSuffix names the sidecar log of a prompt's outcomes:
+// prompts/review.md pairs with prompts/review.outcomes.jsonl. Each entry is a
+// line of JSON, so entries logged on different machines merge in git.
+const OutcomesSuffix = ".outcomes.jsonl"
+
+// OutcomesPath returns the library-relative path of a prompt's outcome log
+func OutcomesPath(prompt *models.Prompt) string {
+	return strings.TrimSuffix(prompt.FilePath, filepath.Ext(prompt.FilePath)) + OutcomesSuffix
+}
+
+// AppendOutcome adds an entry to the end of a prompt's outcome log
+func (s *Storage) AppendOutcome(prompt *models.Prompt, entry models.OutcomeEntry) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if prompt.FilePath == "" {
+		return fmt.Errorf("prompt %s has no file", prompt.ID)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outcome: %w", err)
+	}
+	rel := OutcomesPath(prompt)
+	tracing.Write(filepath.Join(s.rootPath, rel))
+	f, err := os.OpenFile(filepath.Join(s.rootPath, rel), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", rel, err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	return nil
+}
+
+// LoadOutcomes reads a prompt's outcome log, oldest first. Lines that fail to
+// parse, such as leftover merge markers, are skipped.
+func (s *Storage) LoadOutcomes(prompt *models.Prompt) ([]models.OutcomeEntry, error) {
+	if prompt.FilePath == "" {
+		return nil, nil
+	}
+	rel := OutcomesPath(prompt)
+	tracing.Read(filepath.Join(s.rootPath, rel))
+	data, err := os.ReadFile(filepath.Join(s.rootPath, rel))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+
+	var entries []models.OutcomeEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry models.OutcomeEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
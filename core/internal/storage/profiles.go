@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// ProfilesDir holds variable profiles: YAML files mapping variable names to
+// values, such as profiles/client-acme.yaml
+const ProfilesDir = "profiles"
+
+// ListProfiles returns the names of the library's variable profiles, sorted
+func (s *Storage) ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.rootPath, ProfilesDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ProfilesDir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ext))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadProfile reads the variables in profiles/<name>.yaml (or .yml)
+func (s *Storage) LoadProfile(name string) (map[string]interface{}, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid profile name %q", name)
+	}
+
+	for _, ext := range []string{".yaml", ".yml"} {
+		rel := filepath.Join(ProfilesDir, name+ext)
+		tracing.Read(filepath.Join(s.rootPath, rel))
+		data, err := os.ReadFile(filepath.Join(s.rootPath, rel))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+
+		variables := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &variables); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", rel, err)
+		}
+		return variables, nil
+	}
+	return nil, fmt.Errorf("profile %q not found in %s/", name, ProfilesDir)
+}
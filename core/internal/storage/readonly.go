@@ -0,0 +1,26 @@
+package storage
+
+import "errors"
+
+// ErrReadOnly is returned by writes to a library opened read-only, such as
+// the sample library of demo mode
+var ErrReadOnly = errors.New("library is read-only")
+
+// SetReadOnly makes every later write to the library's prompts, templates,
+// assets and archive fail with ErrReadOnly
+func (s *Storage) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
+
+// ReadOnly reports whether the library rejects writes
+func (s *Storage) ReadOnly() bool {
+	return s.readOnly
+}
+
+// writable returns ErrReadOnly when the library rejects writes
+func (s *Storage) writable() error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
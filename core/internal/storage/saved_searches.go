@@ -5,84 +5,129 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
 )
 
+// SearchesDir holds one YAML file per saved search, such as
+// searches/needs-review.yaml, so searches added on different machines merge
+// in git without conflicts
+const SearchesDir = "searches"
+
+// savedSearchesFile is the single JSON file saved searches used to share.
+// It is split into SearchesDir the first time searches are loaded.
 const savedSearchesFile = "saved_searches.json"
 
 // SavedSearchesStorage handles persistence of saved boolean searches
 type SavedSearchesStorage struct {
-	filePath string
+	dir        string
+	legacyPath string
+	readOnly   bool
 }
 
 // NewSavedSearchesStorage creates a new saved searches storage
 func NewSavedSearchesStorage(baseDir string) *SavedSearchesStorage {
 	return &SavedSearchesStorage{
-		filePath: filepath.Join(baseDir, savedSearchesFile),
+		dir:        filepath.Join(baseDir, SearchesDir),
+		legacyPath: filepath.Join(baseDir, savedSearchesFile),
 	}
 }
 
-// SavedSearchesData represents the JSON structure for saved searches
+// SavedSearchesData represents the JSON structure of the legacy
+// saved_searches.json file
 type SavedSearchesData struct {
 	Searches []models.SavedSearch `json:"searches"`
 	Version  string               `json:"version"`
 }
 
-// LoadSavedSearches loads all saved searches from disk
+// savedSearchFile is the YAML form of a saved search. The expression is kept
+// as its query string so the file reads, diffs and merges as plain text.
+type savedSearchFile struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Expression  string `yaml:"expression,omitempty"`
+	TextQuery   string `yaml:"text_query,omitempty"`
+	CreatedAt   string `yaml:"created_at"`
+	UpdatedAt   string `yaml:"updated_at"`
+}
+
+// LoadSavedSearches loads all saved searches from disk, oldest first
 func (s *SavedSearchesStorage) LoadSavedSearches() ([]models.SavedSearch, error) {
-	// Check if file exists
-	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
-		return []models.SavedSearch{}, nil
+	if err := s.migrateLegacyFile(); err != nil {
+		return nil, err
 	}
 
-	// Read file
-	data, err := os.ReadFile(s.filePath)
+	files, err := s.searchFiles()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read saved searches file: %w", err)
+		return nil, err
+	}
+	searches := []models.SavedSearch{}
+	for _, search := range files {
+		searches = append(searches, search)
 	}
 
-	// Parse JSON
-	var searchData SavedSearchesData
-	if err := json.Unmarshal(data, &searchData); err != nil {
-		return nil, fmt.Errorf("failed to parse saved searches JSON: %w", err)
+	// A read-only library cannot be migrated, so read the old file as well
+	if s.readOnly {
+		legacy, err := s.loadLegacyFile()
+		if err != nil {
+			return nil, err
+		}
+		for _, search := range legacy {
+			if _, ok := s.findFile(files, search.Name); !ok {
+				searches = append(searches, search)
+			}
+		}
 	}
 
-	return searchData.Searches, nil
+	sort.SliceStable(searches, func(i, j int) bool {
+		if searches[i].CreatedAt != searches[j].CreatedAt {
+			return searches[i].CreatedAt < searches[j].CreatedAt
+		}
+		return searches[i].Name < searches[j].Name
+	})
+	return searches, nil
 }
 
-// SaveSearches saves all searches to disk
-func (s *SavedSearchesStorage) SaveSearches(searches []models.SavedSearch) error {
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
-		return fmt.Errorf("failed to create saved searches directory: %w", err)
-	}
+// SetReadOnly makes saving searches fail with ErrReadOnly
+func (s *SavedSearchesStorage) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
 
-	// Prepare data structure
-	data := SavedSearchesData{
-		Searches: searches,
-		Version:  "1.0",
+// SaveSearches replaces all saved searches with searches
+func (s *SavedSearchesStorage) SaveSearches(searches []models.SavedSearch) error {
+	if s.readOnly {
+		return ErrReadOnly
 	}
-
-	// Marshal to JSON
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+	files, err := s.searchFiles()
 	if err != nil {
-		return fmt.Errorf("failed to marshal saved searches: %w", err)
+		return err
 	}
-
-	// Write to file
-	if err := os.WriteFile(s.filePath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write saved searches file: %w", err)
+	for path := range files {
+		tracing.Delete(path)
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove saved search: %w", err)
+		}
+	}
+	for _, search := range searches {
+		if err := s.writeSearch(s.newSearchPath(search.Name), search); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
-// AddSavedSearch adds a new saved search
+// AddSavedSearch adds a new saved search, or replaces the one with its name
 func (s *SavedSearchesStorage) AddSavedSearch(search models.SavedSearch) error {
-	// Load existing searches
-	searches, err := s.LoadSavedSearches()
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	files, err := s.searchFiles()
 	if err != nil {
 		return err
 	}
@@ -94,37 +139,32 @@ func (s *SavedSearchesStorage) AddSavedSearch(search models.SavedSearch) error {
 	}
 	search.UpdatedAt = now
 
-	// Check for duplicate names
-	for i, existing := range searches {
-		if existing.Name == search.Name {
-			// Update existing search
-			searches[i] = search
-			return s.SaveSearches(searches)
-		}
+	path, ok := s.findFile(files, search.Name)
+	if !ok {
+		path = s.newSearchPath(search.Name)
 	}
-
-	// Add new search
-	searches = append(searches, search)
-	return s.SaveSearches(searches)
+	return s.writeSearch(path, search)
 }
 
 // DeleteSavedSearch removes a saved search by name
 func (s *SavedSearchesStorage) DeleteSavedSearch(name string) error {
-	// Load existing searches
-	searches, err := s.LoadSavedSearches()
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	files, err := s.searchFiles()
 	if err != nil {
 		return err
 	}
 
-	// Find and remove the search
-	for i, search := range searches {
-		if search.Name == name {
-			searches = append(searches[:i], searches[i+1:]...)
-			return s.SaveSearches(searches)
-		}
+	path, ok := s.findFile(files, name)
+	if !ok {
+		return fmt.Errorf("saved search not found: %s", name)
 	}
-
-	return fmt.Errorf("saved search not found: %s", name)
+	tracing.Delete(path)
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	return nil
 }
 
 // GetSavedSearch retrieves a saved search by name
@@ -141,4 +181,180 @@ func (s *SavedSearchesStorage) GetSavedSearch(name string) (*models.SavedSearch,
 	}
 
 	return nil, fmt.Errorf("saved search not found: %s", name)
-}
\ No newline at end of file
+}
+
+// searchFiles reads every saved search file, keyed by path. A file that
+// fails to parse is skipped with a warning so one bad merge does not hide
+// the rest.
+func (s *SavedSearchesStorage) searchFiles() (map[string]models.SavedSearch, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return map[string]models.SavedSearch{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SearchesDir, err)
+	}
+
+	files := map[string]models.SavedSearch{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(s.dir, entry.Name())
+		search, err := readSearch(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s/%s: %v\n", SearchesDir, entry.Name(), err)
+			continue
+		}
+		files[path] = search
+	}
+	return files, nil
+}
+
+// findFile returns the path of the saved search called name
+func (s *SavedSearchesStorage) findFile(files map[string]models.SavedSearch, name string) (string, bool) {
+	for path, search := range files {
+		if search.Name == name {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// newSearchPath picks a file name for a new search from its name, adding a
+// number when another search already has that file
+func (s *SavedSearchesStorage) newSearchPath(name string) string {
+	base := searchFileName(name)
+	path := filepath.Join(s.dir, base+".yaml")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(s.dir, fmt.Sprintf("%s-%d.yaml", base, n))
+	}
+}
+
+// searchFileName turns a search name into a file name: lower case, with runs
+// of anything but letters, digits, '-' and '_' replaced by '-'
+func searchFileName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	if base := strings.Trim(b.String(), "-"); base != "" {
+		return base
+	}
+	return "search"
+}
+
+func readSearch(path string) (models.SavedSearch, error) {
+	tracing.Read(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+	var file savedSearchFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return models.SavedSearch{}, err
+	}
+	if file.Name == "" {
+		return models.SavedSearch{}, fmt.Errorf("missing name")
+	}
+
+	search := models.SavedSearch{
+		Name:        file.Name,
+		Description: file.Description,
+		TextQuery:   file.TextQuery,
+		CreatedAt:   file.CreatedAt,
+		UpdatedAt:   file.UpdatedAt,
+	}
+	if file.Expression != "" {
+		expr, err := models.ParseBooleanExpression(file.Expression)
+		if err != nil {
+			return models.SavedSearch{}, fmt.Errorf("invalid expression: %w", err)
+		}
+		search.Expression = expr
+	}
+	return search, nil
+}
+
+func (s *SavedSearchesStorage) writeSearch(path string, search models.SavedSearch) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create saved searches directory: %w", err)
+	}
+	data, err := yaml.Marshal(savedSearchFile{
+		Name:        search.Name,
+		Description: search.Description,
+		Expression:  search.Expression.QueryString(),
+		TextQuery:   search.TextQuery,
+		CreatedAt:   search.CreatedAt,
+		UpdatedAt:   search.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved search: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write saved search: %w", err)
+	}
+	return nil
+}
+
+// loadLegacyFile reads the searches in saved_searches.json, if it is there
+func (s *SavedSearchesStorage) loadLegacyFile() ([]models.SavedSearch, error) {
+	tracing.Read(s.legacyPath)
+	data, err := os.ReadFile(s.legacyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved searches file: %w", err)
+	}
+
+	var searchData SavedSearchesData
+	if err := json.Unmarshal(data, &searchData); err != nil {
+		return nil, fmt.Errorf("failed to parse saved searches JSON: %w", err)
+	}
+	return searchData.Searches, nil
+}
+
+// migrateLegacyFile moves the searches in saved_searches.json into a file
+// each and removes it. A search already in SearchesDir, say from a teammate
+// who migrated first, is kept rather than overwritten.
+func (s *SavedSearchesStorage) migrateLegacyFile() error {
+	if s.readOnly {
+		return nil
+	}
+	legacy, err := s.loadLegacyFile()
+	if err != nil || legacy == nil {
+		return err
+	}
+
+	files, err := s.searchFiles()
+	if err != nil {
+		return err
+	}
+	for _, search := range legacy {
+		if _, ok := s.findFile(files, search.Name); ok {
+			continue
+		}
+		path := s.newSearchPath(search.Name)
+		if err := s.writeSearch(path, search); err != nil {
+			return err
+		}
+		files[path] = search
+	}
+	tracing.Delete(s.legacyPath)
+	if err := os.Remove(s.legacyPath); err != nil {
+		return fmt.Errorf("failed to remove %s after migrating it: %w", savedSearchesFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestSavedSearchesOneFileEach(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := NewSavedSearchesStorage(tmpDir)
+
+	expr, err := models.ParseBooleanExpression("(ai OR ml) AND NOT draft")
+	if err != nil {
+		t.Fatalf("ParseBooleanExpression: %v", err)
+	}
+	for _, search := range []models.SavedSearch{
+		{Name: "AI Work", Expression: expr, TextQuery: "review", CreatedAt: "2025-01-01T00:00:00Z"},
+		{Name: "ai/work", Expression: models.NewTagExpression("ai"), CreatedAt: "2025-01-02T00:00:00Z"},
+	} {
+		if err := s.AddSavedSearch(search); err != nil {
+			t.Fatalf("AddSavedSearch(%s): %v", search.Name, err)
+		}
+	}
+
+	for _, name := range []string{"ai-work.yaml", "ai-work-2.yaml"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, SearchesDir, name)); err != nil {
+			t.Errorf("expected %s/%s: %v", SearchesDir, name, err)
+		}
+	}
+
+	got, err := s.GetSavedSearch("AI Work")
+	if err != nil {
+		t.Fatalf("GetSavedSearch: %v", err)
+	}
+	if got.Expression.QueryString() != expr.QueryString() || got.TextQuery != "review" {
+		t.Errorf("round trip = %q with text %q, want %q with text review", got.Expression.QueryString(), got.TextQuery, expr.QueryString())
+	}
+
+	if err := s.DeleteSavedSearch("AI Work"); err != nil {
+		t.Fatalf("DeleteSavedSearch: %v", err)
+	}
+	searches, _ := s.LoadSavedSearches()
+	if len(searches) != 1 || searches[0].Name != "ai/work" {
+		t.Errorf("after deleting, searches = %v, want only ai/work", searches)
+	}
+}
+
+func TestSavedSearchesMigrateLegacyFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, savedSearchesFile), `{
+  "searches": [
+    {"name": "drafts", "expression": {"type": "tag", "value": "draft"}, "created_at": "2025-01-01T00:00:00Z", "updated_at": "2025-01-01T00:00:00Z"},
+    {"name": "shared", "expression": {"type": "tag", "value": "old"}, "created_at": "2025-01-02T00:00:00Z", "updated_at": "2025-01-02T00:00:00Z"}
+  ],
+  "version": "1.0"
+}`)
+	// A teammate already migrated "shared"; their copy wins
+	writeFile(t, filepath.Join(tmpDir, SearchesDir, "shared.yaml"), "name: shared\nexpression: team AND new\ncreated_at: \"2025-01-03T00:00:00Z\"\nupdated_at: \"2025-01-03T00:00:00Z\"\n")
+
+	readOnly := NewSavedSearchesStorage(tmpDir)
+	readOnly.SetReadOnly(true)
+	if searches, err := readOnly.LoadSavedSearches(); err != nil || len(searches) != 2 {
+		t.Fatalf("read-only LoadSavedSearches = %v, %v; want both searches", searches, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, savedSearchesFile)); err != nil {
+		t.Fatalf("a read-only library was migrated: %v", err)
+	}
+
+	searches, err := NewSavedSearchesStorage(tmpDir).LoadSavedSearches()
+	if err != nil {
+		t.Fatalf("LoadSavedSearches: %v", err)
+	}
+	if len(searches) != 2 || searches[0].Name != "drafts" || searches[1].Expression.QueryString() != "team AND new" {
+		t.Errorf("migrated searches = %+v, want drafts then the teammate's shared", searches)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, savedSearchesFile)); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed after migrating: %v", savedSearchesFile, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, SearchesDir, "drafts.yaml")); err != nil {
+		t.Errorf("drafts was not migrated: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// OutputSchemaSuffix names the sidecar file that can hold a prompt's output
+// schema instead of its frontmatter: prompts/review.md pairs with
+// prompts/review.schema.json
+const OutputSchemaSuffix = ".schema.json"
+
+// OutputSchemaPath returns the library-relative path of a prompt's schema sidecar
+func OutputSchemaPath(prompt *models.Prompt) string {
+	return strings.TrimSuffix(prompt.FilePath, filepath.Ext(prompt.FilePath)) + OutputSchemaSuffix
+}
+
+// LoadOutputSchema reads a prompt's schema sidecar. It returns nil when the
+// prompt has none.
+func (s *Storage) LoadOutputSchema(prompt *models.Prompt) (map[string]interface{}, error) {
+	if prompt.FilePath == "" {
+		return nil, nil
+	}
+	rel := OutputSchemaPath(prompt)
+	tracing.Read(filepath.Join(s.rootPath, rel))
+	data, err := os.ReadFile(filepath.Join(s.rootPath, rel))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", rel, err)
+	}
+	return schema, nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+const serverStateFile = "server.json"
+
+// ServerState is where the URL server last started listening, which can
+// differ from the configured port when it fell back to a free one
+type ServerState struct {
+	URL       string    `json:"url"`
+	Port      int       `json:"port"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+func serverStatePath(baseDir string) string {
+	return filepath.Join(baseDir, ".pocket-prompt", serverStateFile)
+}
+
+// LoadServerState reads where the server last listened, returning nil when
+// no server has recorded it
+func LoadServerState(baseDir string) (*ServerState, error) {
+	tracing.Read(serverStatePath(baseDir))
+	data, err := os.ReadFile(serverStatePath(baseDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server state: %w", err)
+	}
+	state := &ServerState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse server state: %w", err)
+	}
+	return state, nil
+}
+
+// SaveServerState records where the server listens in .pocket-prompt/server.json
+func SaveServerState(baseDir string, state *ServerState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal server state: %w", err)
+	}
+	path := serverStatePath(baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write server state: %w", err)
+	}
+	return nil
+}
+
+// RemoveServerState forgets the server's address once it stops
+func RemoveServerState(baseDir string) error {
+	tracing.Delete(serverStatePath(baseDir))
+	if err := os.Remove(serverStatePath(baseDir)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove server state: %w", err)
+	}
+	return nil
+}
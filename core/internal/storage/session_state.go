@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+const sessionStateFile = "tui-session.json"
+
+// SessionState is where the TUI was when it last closed, so the next run can
+// resume there
+type SessionState struct {
+	View       string                    `json:"view,omitempty"`        // "library" or "prompt"
+	Filter     string                    `json:"filter,omitempty"`      // Text in the library's / filter
+	Expression *models.BooleanExpression `json:"expression,omitempty"`  // Boolean search the library was narrowed to
+	SelectedID string                    `json:"selected_id,omitempty"` // Highlighted or open prompt
+	Scroll     int                       `json:"scroll,omitempty"`      // Line the open prompt was scrolled to
+}
+
+func sessionStatePath(baseDir string) string {
+	return filepath.Join(baseDir, ".pocket-prompt", sessionStateFile)
+}
+
+// LoadSessionState reads the last TUI session, returning a zero state before the first one
+func LoadSessionState(baseDir string) (*SessionState, error) {
+	state := &SessionState{}
+	tracing.Read(sessionStatePath(baseDir))
+	data, err := os.ReadFile(sessionStatePath(baseDir))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse session state: %w", err)
+	}
+	return state, nil
+}
+
+// SaveSessionState records the TUI session in .pocket-prompt/tui-session.json
+func SaveSessionState(baseDir string, state *SessionState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session state: %w", err)
+	}
+	path := sessionStatePath(baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session state: %w", err)
+	}
+	return nil
+}
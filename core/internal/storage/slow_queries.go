@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+const slowQueryFile = "slow-queries.jsonl"
+
+// maxSlowQueries bounds the slow-query log; older entries are dropped
+const maxSlowQueries = 500
+
+// SlowQuery is one search that took longer than the configured threshold
+type SlowQuery struct {
+	Time        time.Time     `json:"time"`
+	Kind        string        `json:"kind"`                   // "search", "boolean" or "saved-search"
+	Query       string        `json:"query"`                  // Query text, or the boolean expression
+	SavedSearch string        `json:"saved_search,omitempty"` // Saved search that was run, if any
+	Duration    time.Duration `json:"duration_ns"`
+	Prompts     int           `json:"prompts"`    // Prompts in the library when it ran
+	Results     int           `json:"results"`    // Prompts it matched
+	Complexity  int           `json:"complexity"` // Filters and terms, or expression nodes
+}
+
+// SlowQueryLog keeps the slowest searches in .pocket-prompt/slow-queries.jsonl,
+// one JSON object per line, so they can be reviewed after the TUI or server
+// that ran them has exited. The log is local to this machine.
+type SlowQueryLog struct {
+	mu       sync.Mutex
+	filePath string
+	readOnly bool
+}
+
+// NewSlowQueryLog creates the slow-query log for the library at baseDir
+func NewSlowQueryLog(baseDir string) *SlowQueryLog {
+	return &SlowQueryLog{
+		filePath: filepath.Join(baseDir, ".pocket-prompt", slowQueryFile),
+	}
+}
+
+// SetReadOnly stops Record from logging queries. Reads are unaffected.
+func (l *SlowQueryLog) SetReadOnly(readOnly bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.readOnly = readOnly
+}
+
+// Record appends a slow query to the log, dropping the oldest entries once
+// it holds more than maxSlowQueries
+func (l *SlowQueryLog) Record(query SlowQuery) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.readOnly {
+		return nil
+	}
+
+	entries, err := l.load()
+	if err != nil {
+		return err
+	}
+	entries = append(entries, query)
+	if len(entries) > maxSlowQueries {
+		entries = entries[len(entries)-maxSlowQueries:]
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to marshal slow query: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(l.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create slow-query directory: %w", err)
+	}
+	tracing.Write(l.filePath)
+	if err := os.WriteFile(l.filePath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write slow-query log: %w", err)
+	}
+	return nil
+}
+
+// Load returns the logged slow queries, oldest first
+func (l *SlowQueryLog) Load() ([]SlowQuery, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.load()
+}
+
+// Clear empties the log
+func (l *SlowQueryLog) Clear() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tracing.Delete(l.filePath)
+	if err := os.Remove(l.filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear slow-query log: %w", err)
+	}
+	return nil
+}
+
+// load reads the log, skipping lines that cannot be parsed, such as one cut
+// short by a crash
+func (l *SlowQueryLog) load() ([]SlowQuery, error) {
+	tracing.Read(l.filePath)
+	data, err := os.ReadFile(l.filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slow-query log: %w", err)
+	}
+	var entries []SlowQuery
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry SlowQuery
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
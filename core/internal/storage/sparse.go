@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrOutsideCheckout is returned by writes to prompts and templates that the
+// sparse checkout of the library leaves out, since git sync could not commit
+// them
+var ErrOutsideCheckout = errors.New("outside the sparse checkout")
+
+// SetCheckoutPaths limits writes to prompts and templates to the files a
+// sparse checkout of dirs includes. No dirs makes the whole library writable.
+func (s *Storage) SetCheckoutPaths(dirs []string) {
+	s.checkoutPaths = dirs
+}
+
+// CheckoutPaths returns the directories of the sparse checkout, or nil when
+// the whole library is checked out
+func (s *Storage) CheckoutPaths() []string {
+	return s.checkoutPaths
+}
+
+// InCheckout reports whether the file at path, relative to the library, is
+// in the checkout: under one of its directories, or directly in the top of
+// the library or a directory above one of them, as git's cone mode has it
+func (s *Storage) InCheckout(file string) bool {
+	if len(s.checkoutPaths) == 0 {
+		return true
+	}
+	dir := path.Dir(filepath.ToSlash(file))
+	if dir == "." {
+		return true
+	}
+	for _, p := range s.checkoutPaths {
+		if dir == p || strings.HasPrefix(dir, p+"/") || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// writablePath returns ErrReadOnly when the library rejects writes, and
+// ErrOutsideCheckout when the file at path is not checked out
+func (s *Storage) writablePath(file string) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if !s.InCheckout(file) {
+		return fmt.Errorf("%s is %w (%s)", file, ErrOutsideCheckout, strings.Join(s.checkoutPaths, ", "))
+	}
+	return nil
+}
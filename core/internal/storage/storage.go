@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"bufio"
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
@@ -12,13 +11,18 @@ import (
 	"strings"
 
 	"github.com/dpshade/pocket-prompt/internal/models"
-	"gopkg.in/yaml.v3"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
 )
 
 // Storage handles all file system operations for prompts and templates
 type Storage struct {
-	rootPath string
-	cache    *MetadataCache
+	rootPath      string
+	cache         *MetadataCache
+	defaultFormat string // frontmatter format for newly created files
+	directoryTags bool   // derive tags from nested prompt directories
+	metas         metaCache
+	readOnly      bool     // reject writes, see SetReadOnly
+	checkoutPaths []string // sparse checkout directories, see SetCheckoutPaths
 }
 
 // NewStorage creates a new storage instance
@@ -38,11 +42,45 @@ func NewStorage(rootPath string) (*Storage, error) {
 	}
 
 	return &Storage{
-		rootPath: rootPath,
-		cache:    cache,
+		rootPath:      rootPath,
+		cache:         cache,
+		defaultFormat: FormatYAML,
 	}, nil
 }
 
+// SetDefaultFormat sets the frontmatter format used when writing new files.
+// Existing files always keep the format they were read with.
+func (s *Storage) SetDefaultFormat(format string) error {
+	if format == "" {
+		format = FormatYAML
+	}
+	if !ValidFrontmatterFormat(format) {
+		return fmt.Errorf("unsupported frontmatter format %q (expected yaml, toml or json)", format)
+	}
+	s.defaultFormat = format
+	return nil
+}
+
+// SetDirectoryTags controls whether nested directory names under prompts/
+// are added to the tags of the prompts they contain
+func (s *Storage) SetDirectoryTags(enabled bool) {
+	s.directoryTags = enabled
+}
+
+// resolveFormat picks the frontmatter format for a write: the format the object
+// was loaded with, else the format of the file already on disk, else the default
+func (s *Storage) resolveFormat(format string, fullPath string) string {
+	if format != "" {
+		return format
+	}
+	if content, err := os.ReadFile(fullPath); err == nil {
+		if existing, _, _, err := splitFrontmatter(content); err == nil {
+			return existing
+		}
+	}
+	return s.defaultFormat
+}
+
 // InitLibrary creates the directory structure for a prompt library
 func (s *Storage) InitLibrary() error {
 	dirs := []string{
@@ -68,10 +106,11 @@ func (s *Storage) GetBaseDir() string {
 	return s.rootPath
 }
 
-// LoadPrompt loads a prompt from a markdown file with YAML frontmatter
+// LoadPrompt loads a prompt from a markdown file with YAML, TOML or JSON frontmatter
 func (s *Storage) LoadPrompt(path string) (*models.Prompt, error) {
 	fullPath := filepath.Join(s.rootPath, path)
 	
+	tracing.Read(fullPath)
 	file, err := os.Open(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open prompt file: %w", err)
@@ -92,12 +131,34 @@ func (s *Storage) LoadPrompt(path string) (*models.Prompt, error) {
 
 	prompt.FilePath = path
 	prompt.ContentHash = calculateHash(content)
+	s.applyDirectoryDefaults(prompt)
 
 	return prompt, nil
 }
 
-// SavePrompt saves a prompt to a markdown file with YAML frontmatter
+// ParsePrompt parses the content of a prompt file that is not in the library,
+// such as a past revision read from git
+func ParsePrompt(content []byte) (*models.Prompt, error) {
+	return parsePromptFile(content)
+}
+
+// ParsePromptAt parses the content of a prompt file as if it were stored at
+// path, applying the defaults of the folders it sits in
+func (s *Storage) ParsePromptAt(path string, content []byte) (*models.Prompt, error) {
+	prompt, err := parsePromptFile(content)
+	if err != nil {
+		return nil, err
+	}
+	prompt.FilePath = path
+	s.applyDirectoryDefaults(prompt)
+	return prompt, nil
+}
+
+// SavePrompt saves a prompt to a markdown file with frontmatter
 func (s *Storage) SavePrompt(prompt *models.Prompt) error {
+	if err := s.writablePath(prompt.FilePath); err != nil {
+		return err
+	}
 	fullPath := filepath.Join(s.rootPath, prompt.FilePath)
 	
 	// Ensure directory exists
@@ -106,13 +167,18 @@ func (s *Storage) SavePrompt(prompt *models.Prompt) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Serialize prompt to YAML frontmatter + markdown
-	content, err := serializePrompt(prompt)
+	// Every write produces a current-format file
+	prompt.Schema = models.CurrentSchemaVersion
+	prompt.Format = s.resolveFormat(prompt.Format, fullPath)
+
+	// Serialize prompt to frontmatter + markdown, leaving inherited values to _meta.yaml
+	content, err := serializePrompt(s.withoutDirectoryDefaults(prompt))
 	if err != nil {
 		return fmt.Errorf("failed to serialize prompt: %w", err)
 	}
 
 	// Write to file
+	tracing.Write(fullPath)
 	if err := os.WriteFile(fullPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write prompt file: %w", err)
 	}
@@ -122,6 +188,9 @@ func (s *Storage) SavePrompt(prompt *models.Prompt) error {
 
 // DeletePrompt deletes a prompt file from the file system
 func (s *Storage) DeletePrompt(prompt *models.Prompt) error {
+	if err := s.writablePath(prompt.FilePath); err != nil {
+		return err
+	}
 	fullPath := filepath.Join(s.rootPath, prompt.FilePath)
 	
 	// Check if file exists
@@ -130,6 +199,7 @@ func (s *Storage) DeletePrompt(prompt *models.Prompt) error {
 	}
 	
 	// Delete the file
+	tracing.Delete(fullPath)
 	if err := os.Remove(fullPath); err != nil {
 		return fmt.Errorf("failed to delete prompt file: %w", err)
 	}
@@ -139,6 +209,9 @@ func (s *Storage) DeletePrompt(prompt *models.Prompt) error {
 
 // SaveTemplate saves a template to the file system
 func (s *Storage) SaveTemplate(template *models.Template) error {
+	if err := s.writablePath(template.FilePath); err != nil {
+		return err
+	}
 	fullPath := filepath.Join(s.rootPath, template.FilePath)
 	
 	// Ensure directory exists
@@ -147,13 +220,16 @@ func (s *Storage) SaveTemplate(template *models.Template) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 	
-	// Serialize template to YAML frontmatter + markdown
+	template.Format = s.resolveFormat(template.Format, fullPath)
+
+	// Serialize template to frontmatter + markdown
 	content, err := serializeTemplate(template)
 	if err != nil {
 		return fmt.Errorf("failed to serialize template: %w", err)
 	}
 	
 	// Write to file
+	tracing.Write(fullPath)
 	if err := os.WriteFile(fullPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write template file: %w", err)
 	}
@@ -220,42 +296,34 @@ func (s *Storage) listPackPrompts() ([]*models.Prompt, error) {
 
 // listPromptsFromDir returns prompts from a specific directory with caching
 func (s *Storage) listPromptsFromDir(dir string) ([]*models.Prompt, error) {
-	promptsDir := filepath.Join(s.rootPath, dir)
-	
 	var prompts []*models.Prompt
 	existingFiles := make(map[string]bool)
 	cacheModified := false
 	
-	err := filepath.Walk(promptsDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if !info.IsDir() && strings.HasSuffix(path, ".md") {
-			relPath, _ := filepath.Rel(s.rootPath, path)
-			existingFiles[relPath] = true
-			
-			// Try to get from cache first
-			if cached, valid := s.cache.Get(relPath, info); valid {
-				prompts = append(prompts, cached.ToPrompt())
-				return nil
-			}
-			
-			// Cache miss - load and parse the prompt
-			prompt, err := s.LoadPrompt(relPath)
-			if err != nil {
-				// Log error but continue walking
-				fmt.Fprintf(os.Stderr, "Warning: failed to load prompt %s: %v\n", relPath, err)
-				return nil
-			}
-			
-			// Cache the loaded prompt metadata
-			s.cache.Set(relPath, filepath.Join(s.rootPath, relPath), info, prompt)
-			cacheModified = true
-			
+	err := s.walkPromptFiles(dir, func(relPath string, info os.FileInfo) error {
+		existingFiles[relPath] = true
+		
+		// Try to get from cache first
+		if cached, valid := s.cache.Get(relPath, info); valid {
+			prompt := cached.ToPrompt()
+			s.applyDirectoryDefaults(prompt)
 			prompts = append(prompts, prompt)
+			return nil
 		}
-
+		
+		// Cache miss - load and parse the prompt
+		prompt, err := s.LoadPrompt(relPath)
+		if err != nil {
+			// Log error but continue walking
+			fmt.Fprintf(os.Stderr, "Warning: failed to load prompt %s: %v\n", relPath, err)
+			return nil
+		}
+		
+		// Cache the loaded prompt metadata
+		s.cache.Set(relPath, filepath.Join(s.rootPath, relPath), info, prompt)
+		cacheModified = true
+		
+		prompts = append(prompts, prompt)
 		return nil
 	})
 	
@@ -272,6 +340,10 @@ func (s *Storage) listPromptsFromDir(dir string) ([]*models.Prompt, error) {
 	return prompts, err
 }
 
+// TemplateArchiveDir holds the versions of templates replaced by edits. It
+// lives inside archive/ but is skipped when listing archived prompts.
+const TemplateArchiveDir = "archive/templates"
+
 // ListArchivedPrompts returns all archived prompts
 func (s *Storage) ListArchivedPrompts() ([]*models.Prompt, error) {
 	// Check if archive directory exists
@@ -285,7 +357,11 @@ func (s *Storage) ListArchivedPrompts() ([]*models.Prompt, error) {
 
 // DeleteTemplate deletes a template file
 func (s *Storage) DeleteTemplate(template *models.Template) error {
+	if err := s.writablePath(template.FilePath); err != nil {
+		return err
+	}
 	fullPath := filepath.Join(s.rootPath, template.FilePath)
+	tracing.Delete(fullPath)
 	return os.Remove(fullPath)
 }
 
@@ -293,6 +369,7 @@ func (s *Storage) DeleteTemplate(template *models.Template) error {
 func (s *Storage) LoadTemplate(path string) (*models.Template, error) {
 	fullPath := filepath.Join(s.rootPath, path)
 	
+	tracing.Read(fullPath)
 	file, err := os.Open(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open template file: %w", err)
@@ -317,14 +394,23 @@ func (s *Storage) LoadTemplate(path string) (*models.Template, error) {
 func (s *Storage) ListTemplates() ([]*models.Template, error) {
 	templatesDir := filepath.Join(s.rootPath, "templates")
 	
+	matcher := s.loadIgnore()
+
 	var templates []*models.Template
 	err := filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
+		relPath, _ := filepath.Rel(s.rootPath, path)
+		if matcher.Match(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		if !info.IsDir() && strings.HasSuffix(path, ".md") {
-			relPath, _ := filepath.Rel(s.rootPath, path)
 			template, err := s.LoadTemplate(relPath)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to load template %s: %v\n", relPath, err)
@@ -342,77 +428,39 @@ func (s *Storage) ListTemplates() ([]*models.Template, error) {
 // Helper functions
 
 func parsePromptFile(content []byte) (*models.Prompt, error) {
-	scanner := bufio.NewScanner(bytes.NewReader(content))
-	
-	// Check for frontmatter delimiter
-	if !scanner.Scan() || scanner.Text() != "---" {
-		return nil, fmt.Errorf("missing frontmatter delimiter")
-	}
-
-	// Read frontmatter
-	var frontmatterLines []string
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "---" {
-			break
-		}
-		frontmatterLines = append(frontmatterLines, line)
+	format, frontmatter, body, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse YAML frontmatter
-	frontmatter := strings.Join(frontmatterLines, "\n")
+	// Parse frontmatter in whichever format the file uses
 	var prompt models.Prompt
-	if err := yaml.Unmarshal([]byte(frontmatter), &prompt); err != nil {
+	if err := decodeFrontmatter(format, frontmatter, &prompt); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
+	prompt.Format = format
 
-	// Read remaining content
-	var contentLines []string
-	for scanner.Scan() {
-		contentLines = append(contentLines, scanner.Text())
-	}
-	// Join content preserving original formatting
-	prompt.Content = strings.Join(contentLines, "\n")
 	// Trim only leading whitespace/newlines
-	prompt.Content = strings.TrimLeft(prompt.Content, " \t\n")
+	prompt.Content = trimBody(body)
 
 	return &prompt, nil
 }
 
 func parseTemplateFile(content []byte) (*models.Template, error) {
-	scanner := bufio.NewScanner(bytes.NewReader(content))
-	
-	// Check for frontmatter delimiter
-	if !scanner.Scan() || scanner.Text() != "---" {
-		return nil, fmt.Errorf("missing frontmatter delimiter")
-	}
-
-	// Read frontmatter
-	var frontmatterLines []string
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "---" {
-			break
-		}
-		frontmatterLines = append(frontmatterLines, line)
+	format, frontmatter, body, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse YAML frontmatter
-	frontmatter := strings.Join(frontmatterLines, "\n")
+	// Parse frontmatter in whichever format the file uses
 	var template models.Template
-	if err := yaml.Unmarshal([]byte(frontmatter), &template); err != nil {
+	if err := decodeFrontmatter(format, frontmatter, &template); err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
+	template.Format = format
 
-	// Read remaining content
-	var contentLines []string
-	for scanner.Scan() {
-		contentLines = append(contentLines, scanner.Text())
-	}
-	// Join content preserving original formatting
-	template.Content = strings.Join(contentLines, "\n")
 	// Trim only leading whitespace/newlines
-	template.Content = strings.TrimLeft(template.Content, " \t\n")
+	template.Content = trimBody(body)
 
 	return &template, nil
 }
@@ -420,18 +468,16 @@ func parseTemplateFile(content []byte) (*models.Template, error) {
 func serializePrompt(prompt *models.Prompt) ([]byte, error) {
 	var buf bytes.Buffer
 
-	// Write frontmatter delimiter
-	buf.WriteString("---\n")
+	// Derived directory tags belong to the folder, not the file
+	stored := *prompt
+	stored.Tags = prompt.StoredTags()
 
-	// Serialize prompt metadata to YAML
-	encoder := yaml.NewEncoder(&buf)
-	encoder.SetIndent(2)
-	if err := encoder.Encode(prompt); err != nil {
+	// Serialize prompt metadata in the file's frontmatter format
+	frontmatter, err := encodeFrontmatter(prompt.Format, &stored)
+	if err != nil {
 		return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
 	}
-
-	// Write closing delimiter
-	buf.WriteString("---\n")
+	buf.Write(frontmatter)
 
 	// Write content with proper spacing
 	if prompt.Content != "" {
@@ -446,22 +492,16 @@ func serializePrompt(prompt *models.Prompt) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// serializeTemplate converts a template to YAML frontmatter + markdown content
+// serializeTemplate converts a template to frontmatter + markdown content
 func serializeTemplate(template *models.Template) ([]byte, error) {
 	var buf bytes.Buffer
 
-	// Write frontmatter delimiter
-	buf.WriteString("---\n")
-
-	// Serialize template metadata to YAML
-	encoder := yaml.NewEncoder(&buf)
-	encoder.SetIndent(2)
-	if err := encoder.Encode(template); err != nil {
+	// Serialize template metadata in the file's frontmatter format
+	frontmatter, err := encodeFrontmatter(template.Format, template)
+	if err != nil {
 		return nil, fmt.Errorf("failed to encode frontmatter: %w", err)
 	}
-
-	// Write closing delimiter
-	buf.WriteString("---\n")
+	buf.Write(frontmatter)
 
 	// Write content with proper spacing
 	if template.Content != "" {
@@ -476,6 +516,18 @@ func serializeTemplate(template *models.Template) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// EncodePrompt returns a prompt as a file in its frontmatter format. Unlike
+// SavePrompt it keeps values its directory defaults supply, so the file stands
+// on its own outside the library.
+func EncodePrompt(prompt *models.Prompt) ([]byte, error) {
+	return serializePrompt(prompt)
+}
+
+// EncodeTemplate returns a template as a file in its frontmatter format
+func EncodeTemplate(template *models.Template) ([]byte, error) {
+	return serializeTemplate(template)
+}
+
 func calculateHash(content []byte) string {
 	hash := sha256.Sum256(content)
 	return hex.EncodeToString(hash[:])
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+const subscriptionsFile = "subscriptions.json"
+
+// Subscriptions are the saved searches this device follows, keyed by search
+// name, with the prompts that started matching each since it was last
+// acknowledged
+type Subscriptions struct {
+	Searches map[string]*Subscription `json:"searches"`
+}
+
+// Subscription is one followed saved search
+type Subscription struct {
+	Acknowledged time.Time  `json:"acknowledged"` // When the new matches were last cleared, or the search subscribed to
+	New          []NewMatch `json:"new,omitempty"`
+}
+
+// NewMatch is a prompt that a sync or import made match a subscribed search
+type NewMatch struct {
+	ID     string    `json:"id"`
+	Source string    `json:"source"` // "sync", "import" or "remote"
+	At     time.Time `json:"at"`
+}
+
+// Has reports whether id is already among the new matches
+func (s *Subscription) Has(id string) bool {
+	for _, match := range s.New {
+		if match.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func subscriptionsPath(baseDir string) string {
+	return filepath.Join(baseDir, ".pocket-prompt", subscriptionsFile)
+}
+
+// LoadSubscriptions reads the subscriptions, returning none before the first
+func LoadSubscriptions(baseDir string) (*Subscriptions, error) {
+	subs := &Subscriptions{}
+	tracing.Read(subscriptionsPath(baseDir))
+	data, err := os.ReadFile(subscriptionsPath(baseDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read subscriptions: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, subs); err != nil {
+			return nil, fmt.Errorf("failed to parse subscriptions: %w", err)
+		}
+	}
+	if subs.Searches == nil {
+		subs.Searches = map[string]*Subscription{}
+	}
+	return subs, nil
+}
+
+// SaveSubscriptions records the subscriptions in .pocket-prompt/subscriptions.json
+func SaveSubscriptions(baseDir string, subs *Subscriptions) error {
+	data, err := json.MarshalIndent(subs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscriptions: %w", err)
+	}
+	path := subscriptionsPath(baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write subscriptions: %w", err)
+	}
+	return nil
+}
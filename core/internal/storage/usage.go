@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+// Usage is kept in a directory of files, one per device, so devices never
+// change the same file and syncing them cannot conflict. The single files
+// kept before are still counted, but no longer written.
+const (
+	usageDir          = "usage"
+	templateUsageDir  = "template-usage"
+	usageFile         = "usage.json"
+	templateUsageFile = "template-usage.json"
+)
+
+// PromptUsage counts how often a prompt, or the prompts using a template,
+// have been copied or rendered
+type PromptUsage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// UsageStorage keeps usage counts by ID under .pocket-prompt/, in usage/ for
+// prompts and template-usage/ for templates. Each device records its uses in
+// a file of its own there, named by DeviceName, and reads add up the uses of
+// every device.
+type UsageStorage struct {
+	mu         sync.Mutex
+	baseDir    string
+	dir        string
+	legacyPath string // File from before per-device usage, read but not written
+	readOnly   bool
+}
+
+// NewUsageStorage creates usage storage for the library at baseDir
+func NewUsageStorage(baseDir string) *UsageStorage {
+	return &UsageStorage{
+		baseDir:    baseDir,
+		dir:        filepath.Join(baseDir, ".pocket-prompt", usageDir),
+		legacyPath: filepath.Join(baseDir, ".pocket-prompt", usageFile),
+	}
+}
+
+// NewTemplateUsageStorage creates template usage storage for the library at baseDir
+func NewTemplateUsageStorage(baseDir string) *UsageStorage {
+	return &UsageStorage{
+		baseDir:    baseDir,
+		dir:        filepath.Join(baseDir, ".pocket-prompt", templateUsageDir),
+		legacyPath: filepath.Join(baseDir, ".pocket-prompt", templateUsageFile),
+	}
+}
+
+// Load returns the usage of everything that has been used on any device, by
+// ID: the sum of the counts and the latest use. A file that fails to parse,
+// such as an old usage.json left with merge conflicts, is skipped.
+func (u *UsageStorage) Load() (map[string]PromptUsage, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(u.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage files: %w", err)
+	}
+	total := make(map[string]PromptUsage)
+	for _, path := range append([]string{u.legacyPath}, paths...) {
+		usage, err := loadUsage(path)
+		if err != nil {
+			log.Printf("Warning: skipping usage file: %v", err)
+			continue
+		}
+		mergeUsage(total, usage)
+	}
+	return total, nil
+}
+
+// mergeUsage adds the counts in usage to total, keeping the latest use
+func mergeUsage(total, usage map[string]PromptUsage) {
+	for id, entry := range usage {
+		sum := total[id]
+		sum.Count += entry.Count
+		if entry.LastUsed.After(sum.LastUsed) {
+			sum.LastUsed = entry.LastUsed
+		}
+		total[id] = sum
+	}
+}
+
+// SetReadOnly stops Record from counting uses. Reads are unaffected.
+func (u *UsageStorage) SetReadOnly(readOnly bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.readOnly = readOnly
+}
+
+// Record counts one use of the prompt or template id in this device's file.
+// It does nothing once the usage is read-only, since counting a use is a side
+// effect of reading rather than a change.
+func (u *UsageStorage) Record(id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.readOnly {
+		return nil
+	}
+
+	device, err := DeviceName(u.baseDir)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(u.dir, device+".json")
+	usage, err := loadUsage(path)
+	if err != nil {
+		return err
+	}
+	entry := usage[id]
+	entry.Count++
+	entry.LastUsed = time.Now()
+	usage[id] = entry
+
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %w", err)
+	}
+	if err := os.MkdirAll(u.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	return nil
+}
+
+// loadUsage reads one usage file, which may not exist yet
+func loadUsage(path string) (map[string]PromptUsage, error) {
+	usage := make(map[string]PromptUsage)
+	tracing.Read(path)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage file: %w", err)
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, fmt.Errorf("failed to parse usage file %s: %w", filepath.Base(path), err)
+	}
+	return usage, nil
+}
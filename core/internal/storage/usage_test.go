@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsageAddsUpDevices(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Counts from before per-device usage, as a pull would leave them
+	legacy := filepath.Join(tmpDir, ".pocket-prompt", usageFile)
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte(`{"review": {"count": 5, "last_used": "2025-01-01T00:00:00Z"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := NewUsageStorage(tmpDir)
+	for _, use := range []struct{ device, id string }{
+		{"laptop", "review"},
+		{"laptop", "review"},
+		{"desktop", "review"},
+		{"desktop", "summary"},
+	} {
+		t.Setenv(DeviceEnv, use.device)
+		if err := usage.Record(use.id); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	for _, device := range []string{"laptop", "desktop"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, ".pocket-prompt", usageDir, device+".json")); err != nil {
+			t.Errorf("no usage file for %s: %v", device, err)
+		}
+	}
+
+	// A file left with merge conflicts is skipped
+	if err := os.WriteFile(filepath.Join(tmpDir, ".pocket-prompt", usageDir, "broken.json"), []byte("<<<<<<< HEAD"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := usage.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded["review"].Count != 8 || loaded["summary"].Count != 1 {
+		t.Errorf("counts = review %d, summary %d; want 8 and 1", loaded["review"].Count, loaded["summary"].Count)
+	}
+	if !loaded["review"].LastUsed.After(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("review last used %v, want the latest use", loaded["review"].LastUsed)
+	}
+}
+
+func TestDeviceNameIsKept(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	t.Setenv(DeviceEnv, "")
+	first, err := DeviceName(tmpDir)
+	if err != nil {
+		t.Fatalf("DeviceName: %v", err)
+	}
+	if second, _ := DeviceName(tmpDir); second != first || first == "" {
+		t.Errorf("device names %q and %q, want the same name", first, second)
+	}
+
+	t.Setenv(DeviceEnv, "Build Server 1")
+	if name, _ := DeviceName(tmpDir); name != "build-server-1" {
+		t.Errorf("DeviceName with %s set = %q, want build-server-1", DeviceEnv, name)
+	}
+}
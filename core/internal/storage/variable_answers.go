@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dpshade/pocket-prompt/internal/tracing"
+)
+
+const variableAnswersFile = "variable-answers.json"
+
+// VariableAnswers are the values last given for each prompt's variables on
+// this device, by prompt ID and then variable name
+type VariableAnswers map[string]map[string]string
+
+func variableAnswersPath(baseDir string) string {
+	return filepath.Join(baseDir, ".pocket-prompt", variableAnswersFile)
+}
+
+// LoadVariableAnswers reads the saved variable answers, returning none before the first render
+func LoadVariableAnswers(baseDir string) (VariableAnswers, error) {
+	answers := VariableAnswers{}
+	tracing.Read(variableAnswersPath(baseDir))
+	data, err := os.ReadFile(variableAnswersPath(baseDir))
+	if os.IsNotExist(err) {
+		return answers, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variable answers: %w", err)
+	}
+	if err := json.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("failed to parse variable answers: %w", err)
+	}
+	return answers, nil
+}
+
+// SaveVariableAnswers records the variable answers in .pocket-prompt/variable-answers.json
+func SaveVariableAnswers(baseDir string, answers VariableAnswers) error {
+	data, err := json.MarshalIndent(answers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal variable answers: %w", err)
+	}
+	path := variableAnswersPath(baseDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tracing.Write(path)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write variable answers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/ignore"
+)
+
+// walkPromptFiles calls fn for every markdown file below dir (relative to the
+// library root), descending into nested directories and following symlinks to
+// both files and directories. Paths passed to fn are the logical paths inside
+// the library, not the symlink targets. Paths matched by .pktignore are skipped.
+func (s *Storage) walkPromptFiles(dir string, fn func(relPath string, info os.FileInfo) error) error {
+	visited := make(map[string]bool)
+	return s.walkDir(dir, visited, s.loadIgnore(), fn)
+}
+
+// PromptFiles returns the paths of the personal library's prompt files,
+// including any that fail to parse
+func (s *Storage) PromptFiles() ([]string, error) {
+	var paths []string
+	err := s.walkPromptFiles("prompts", func(relPath string, info os.FileInfo) error {
+		paths = append(paths, relPath)
+		return nil
+	})
+	return paths, err
+}
+
+// IsPromptFile reports whether a library-relative path names a prompt file
+// in the personal library that .pktignore does not exclude. The file need
+// not exist.
+func (s *Storage) IsPromptFile(relPath string) bool {
+	relPath = filepath.Clean(relPath)
+	if !strings.HasPrefix(relPath, "prompts"+string(filepath.Separator)) || !strings.HasSuffix(relPath, ".md") {
+		return false
+	}
+	return !s.loadIgnore().Match(relPath, false)
+}
+
+// loadIgnore reads the library's .pktignore, re-read on every walk so edits apply immediately
+func (s *Storage) loadIgnore() *ignore.Matcher {
+	matcher, err := ignore.Load(s.rootPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", ignore.FileName, err)
+		return &ignore.Matcher{}
+	}
+	return matcher
+}
+
+func (s *Storage) walkDir(relDir string, visited map[string]bool, matcher *ignore.Matcher, fn func(relPath string, info os.FileInfo) error) error {
+	entries, err := s.readDir(relDir, visited)
+	if err != nil {
+		return err
+	}
+	return s.walkEntries(relDir, entries, visited, matcher, fn)
+}
+
+// readDir lists a directory the walk has not been through yet, returning no
+// entries for one it has
+func (s *Storage) readDir(relDir string, visited map[string]bool) ([]os.DirEntry, error) {
+	fullDir := filepath.Join(s.rootPath, relDir)
+
+	// Guard against symlink cycles by tracking resolved directories
+	realDir, err := filepath.EvalSymlinks(fullDir)
+	if err != nil {
+		return nil, err
+	}
+	if visited[realDir] {
+		return nil, nil
+	}
+	visited[realDir] = true
+
+	return os.ReadDir(fullDir)
+}
+
+func (s *Storage) walkEntries(relDir string, entries []os.DirEntry, visited map[string]bool, matcher *ignore.Matcher, fn func(relPath string, info os.FileInfo) error) error {
+	for _, entry := range entries {
+		name := entry.Name()
+		relPath := filepath.Join(relDir, name)
+
+		// os.Stat follows symlinks so linked folders and files behave like local ones
+		info, err := os.Stat(filepath.Join(s.rootPath, relPath))
+		if err != nil {
+			if !matcher.Match(relPath, false) {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", relPath, err)
+			}
+			continue
+		}
+		if matcher.Match(relPath, info.IsDir()) {
+			continue
+		}
+
+		if info.IsDir() {
+			// Hidden directories (.git, editor state) never contain prompts
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			// Archived templates are not prompts
+			if filepath.ToSlash(relPath) == TemplateArchiveDir {
+				continue
+			}
+			// An unreadable folder is reported rather than hiding its siblings
+			children, err := s.readDir(relPath, visited)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", relPath, err)
+				continue
+			}
+			if err := s.walkEntries(relPath, children, visited, matcher, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if strings.HasSuffix(name, ".md") {
+			if err := fn(relPath, info); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// PromptSubdir returns the directory of a prompt file relative to the prompts
+// root it lives in (prompts/ or packs/<name>/prompts/), or "" for top-level files
+func PromptSubdir(relPath string) string {
+	parts := strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/")
+
+	switch {
+	case len(parts) >= 1 && parts[0] == "prompts":
+		parts = parts[1:]
+	case len(parts) >= 3 && parts[0] == "packs" && parts[2] == "prompts":
+		parts = parts[3:]
+	default:
+		return ""
+	}
+
+	return filepath.Join(parts...)
+}
+
+// PackFromPath returns the pack a library-relative file lives in, or "" for the personal library
+func PackFromPath(relPath string) string {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	if len(parts) >= 3 && parts[0] == "packs" {
+		return parts[1]
+	}
+	return ""
+}
+
+// directoryTags derives tags from the nested directories a prompt lives in,
+// e.g. prompts/clients/acme/brief.md contributes "clients" and "acme"
+func directoryTags(relPath string) []string {
+	subdir := PromptSubdir(relPath)
+	if subdir == "" {
+		return nil
+	}
+
+	var tags []string
+	for _, part := range strings.Split(filepath.ToSlash(subdir), "/") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+			tags = append(tags, part)
+		}
+	}
+	return tags
+}
+
+// ValidateSubdir checks that a user-supplied directory stays inside its prompts root
+func ValidateSubdir(dir string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+
+	cleaned := filepath.Clean(filepath.FromSlash(strings.Trim(dir, "/")))
+	if filepath.IsAbs(dir) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("directory %q must be relative to the prompts folder", dir)
+	}
+	if cleaned == "." {
+		return "", nil
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestListPromptsNestedAndSymlinked(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-walk-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	external, err := os.MkdirTemp("", "pocket-prompt-external-*")
+	if err != nil {
+		t.Fatalf("Failed to create external directory: %v", err)
+	}
+	defer os.RemoveAll(external)
+
+	s, err := NewStorage(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := s.InitLibrary(); err != nil {
+		t.Fatalf("Failed to init library: %v", err)
+	}
+	s.SetDirectoryTags(true)
+
+	writeFile(t, filepath.Join(tmpDir, "prompts", "top.md"), "---\nid: top\ntitle: Top\n---\n\nTop\n")
+	writeFile(t, filepath.Join(tmpDir, "prompts", "clients", "acme", "brief.md"), "---\nid: brief\ntitle: Brief\ntags:\n  - writing\n---\n\nBrief\n")
+	writeFile(t, filepath.Join(external, "shared.md"), "---\nid: shared\ntitle: Shared\n---\n\nShared\n")
+
+	if err := os.Symlink(external, filepath.Join(tmpDir, "prompts", "team")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	// A link back to the library root must not cause an infinite walk
+	if err := os.Symlink(filepath.Join(tmpDir, "prompts"), filepath.Join(external, "loop")); err != nil {
+		t.Fatalf("Failed to create loop symlink: %v", err)
+	}
+
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		t.Fatalf("Failed to list prompts: %v", err)
+	}
+
+	byID := make(map[string][]string)
+	for _, p := range prompts {
+		tags := append([]string(nil), p.Tags...)
+		sort.Strings(tags)
+		byID[p.ID] = tags
+	}
+
+	if len(byID) != 3 {
+		t.Fatalf("Expected 3 prompts, got %d: %v", len(byID), byID)
+	}
+	if got := strings.Join(byID["brief"], ","); got != "acme,clients,writing" {
+		t.Errorf("Expected directory tags on brief, got %s", got)
+	}
+	if got := strings.Join(byID["shared"], ","); got != "team" {
+		t.Errorf("Expected symlinked prompt tagged team, got %s", got)
+	}
+
+	// Derived tags are not written back to the file
+	brief, err := s.LoadPrompt(filepath.Join("prompts", "clients", "acme", "brief.md"))
+	if err != nil {
+		t.Fatalf("Failed to load brief: %v", err)
+	}
+	if err := s.SavePrompt(brief); err != nil {
+		t.Fatalf("Failed to save brief: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "prompts", "clients", "acme", "brief.md"))
+	if strings.Contains(string(data), "acme") {
+		t.Errorf("Derived tag was persisted:\n%s", data)
+	}
+}
+
+func TestDirectoryMetaDefaults(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pocket-prompt-meta-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	s, err := NewStorage(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	writeFile(t, filepath.Join(tmpDir, "prompts", "clients", MetaFileName), "tags: [client]\nmetadata:\n  owner: sales\n")
+	writeFile(t, filepath.Join(tmpDir, "prompts", "clients", "acme", MetaFileName), "tags: [acme]\npack: acme\nmetadata:\n  owner: acme-team\n")
+	writeFile(t, filepath.Join(tmpDir, "prompts", "clients", "acme", "brief.md"), "---\nid: brief\ntitle: Brief\ntags:\n  - writing\n---\n\nBrief\n")
+
+	relPath := filepath.Join("prompts", "clients", "acme", "brief.md")
+	prompt, err := s.LoadPrompt(relPath)
+	if err != nil {
+		t.Fatalf("Failed to load prompt: %v", err)
+	}
+
+	tags := append([]string(nil), prompt.Tags...)
+	sort.Strings(tags)
+	if got := strings.Join(tags, ","); got != "acme,client,writing" {
+		t.Errorf("Expected inherited tags, got %s", got)
+	}
+	if prompt.Pack != "acme" {
+		t.Errorf("Expected inherited pack acme, got %q", prompt.Pack)
+	}
+	if prompt.Metadata["owner"] != "acme-team" {
+		t.Errorf("Expected nearest metadata to win, got %v", prompt.Metadata["owner"])
+	}
+
+	// Inherited values stay in _meta.yaml rather than being copied into the file
+	if err := s.SavePrompt(prompt); err != nil {
+		t.Fatalf("Failed to save prompt: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, relPath))
+	for _, inherited := range []string{"acme", "client", "owner"} {
+		if strings.Contains(string(data), inherited) {
+			t.Errorf("Inherited value %q was written to the prompt file:\n%s", inherited, data)
+		}
+	}
+}
+
+func TestListPromptsSkipsUnreadableDirectories(t *testing.T) {
+	tmpDir := t.TempDir()
+	s, err := NewStorage(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := s.InitLibrary(); err != nil {
+		t.Fatalf("Failed to init library: %v", err)
+	}
+
+	writeFile(t, filepath.Join(tmpDir, "prompts", "a", "first.md"), "---\nid: first\ntitle: First\n---\n\nFirst\n")
+	writeFile(t, filepath.Join(tmpDir, "prompts", "b", "hidden.md"), "---\nid: hidden\ntitle: Hidden\n---\n\nHidden\n")
+	writeFile(t, filepath.Join(tmpDir, "prompts", "c", "last.md"), "---\nid: last\ntitle: Last\n---\n\nLast\n")
+	locked := filepath.Join(tmpDir, "prompts", "b")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatalf("Chmod: %v", err)
+	}
+	defer os.Chmod(locked, 0755)
+	if _, err := os.ReadDir(locked); err == nil {
+		t.Skip("Directory permissions are not enforced for this user")
+	}
+	if err := os.Symlink(filepath.Join(tmpDir, "missing"), filepath.Join(tmpDir, "prompts", "gone")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	prompts, err := s.ListPrompts()
+	if err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+	var ids []string
+	for _, p := range prompts {
+		ids = append(ids, p.ID)
+	}
+	sort.Strings(ids)
+	if got := strings.Join(ids, ","); got != "first,last" {
+		t.Errorf("ListPrompts = %s, want the prompts beside the unreadable folder", got)
+	}
+}
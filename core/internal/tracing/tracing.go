@@ -0,0 +1,200 @@
+// Package tracing records where a command spends its time and which files it
+// touches for --trace, so a report of a slow or surprising command can come
+// with evidence. Like startup, nothing is recorded until Enable is called, so
+// the calls left in storage, search, rendering and git cost nothing otherwise.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ways a file can be touched
+const (
+	OpRead   = "read"
+	OpWrite  = "write"
+	OpDelete = "delete"
+)
+
+// Phase is the time spent in one named step, summed over every time it ran
+type Phase struct {
+	Name     string
+	Calls    int
+	Duration time.Duration
+}
+
+// File is a file touched while tracing
+type File struct {
+	Op   string
+	Path string
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	began   time.Time
+	phases  []*Phase
+	byName  map[string]*Phase
+	files   []File
+	touched map[File]bool
+)
+
+// Enable starts recording. The total time is measured from this call.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+	began = time.Now()
+	phases = nil
+	byName = map[string]*Phase{}
+	files = nil
+	touched = map[File]bool{}
+}
+
+// Enabled reports whether phases and files are being recorded
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Begin starts timing the named phase and returns the function that ends it.
+// A phase that runs several times, such as rendering several prompts, is
+// reported once with its calls counted. It must be ended on the goroutine
+// that began it.
+func Begin(name string) (end func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return func() {}
+	}
+
+	region := trace.StartRegion(context.Background(), name)
+	start := time.Now()
+	return func() {
+		region.End()
+		elapsed := time.Since(start)
+		mu.Lock()
+		defer mu.Unlock()
+		phase, ok := byName[name]
+		if !ok {
+			phase = &Phase{Name: name}
+			byName[name] = phase
+			phases = append(phases, phase)
+		}
+		phase.Calls++
+		phase.Duration += elapsed
+	}
+}
+
+// Read records that the file at path was read. Call it before reading; a
+// file that isn't there is left out.
+func Read(path string) {
+	touch(OpRead, path)
+}
+
+// Write records that the file at path was written
+func Write(path string) {
+	touch(OpWrite, path)
+}
+
+// Delete records that the file at path was deleted. Call it before deleting;
+// a file that isn't there is left out.
+func Delete(path string) {
+	touch(OpDelete, path)
+}
+
+// touch records a file the first time it is touched in a given way
+func touch(op, path string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return
+	}
+	if op != OpWrite {
+		if _, err := os.Stat(path); err != nil {
+			return
+		}
+	}
+	file := File{Op: op, Path: path}
+	if touched[file] {
+		return
+	}
+	touched[file] = true
+	files = append(files, file)
+}
+
+// Phases returns the phases recorded so far, in the order they first ended
+func Phases() []Phase {
+	mu.Lock()
+	defer mu.Unlock()
+	recorded := make([]Phase, len(phases))
+	for i, phase := range phases {
+		recorded[i] = *phase
+	}
+	return recorded
+}
+
+// Files returns the files touched so far, in the order they were touched
+func Files() []File {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]File(nil), files...)
+}
+
+// Report writes the phases and files recorded to w. Paths inside baseDir are
+// shown relative to it. Phases can nest, such as a git pull while the library
+// opens, so their times can add up to more than the total.
+func Report(w io.Writer, baseDir string) {
+	if !Enabled() {
+		return
+	}
+	recorded := Phases()
+	touchedFiles := Files()
+	mu.Lock()
+	total := time.Since(began)
+	mu.Unlock()
+
+	fmt.Fprintln(w, "Trace:")
+	for _, phase := range recorded {
+		calls := "1 call"
+		if phase.Calls != 1 {
+			calls = fmt.Sprintf("%d calls", phase.Calls)
+		}
+		fmt.Fprintf(w, "  %-20s %10s  %s\n", phase.Name, round(phase.Duration), calls)
+	}
+	fmt.Fprintf(w, "  %-20s %10s\n", "total", round(total))
+
+	if len(touchedFiles) == 0 {
+		fmt.Fprintln(w, "No files touched")
+		return
+	}
+	fmt.Fprintf(w, "Files touched (%d):\n", len(touchedFiles))
+	for _, file := range touchedFiles {
+		fmt.Fprintf(w, "  %-7s %s\n", file.Op, relative(baseDir, file.Path))
+	}
+}
+
+// relative shortens path to one relative to baseDir when it lies inside it
+func relative(baseDir, path string) string {
+	if baseDir == "" {
+		return path
+	}
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// round shortens a duration to tens of microseconds
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
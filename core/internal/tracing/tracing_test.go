@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordedOnlyWhenEnabled(t *testing.T) {
+	Begin("before enable")()
+	Write("/lib/prompts/a.md")
+	if len(Phases()) != 0 || len(Files()) != 0 {
+		t.Fatalf("recorded before Enable: %+v %+v", Phases(), Files())
+	}
+
+	Enable()
+	base := t.TempDir()
+	prompt := filepath.Join(base, "prompts", "a.md")
+	if err := os.MkdirAll(filepath.Dir(prompt), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prompt, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "old.md")
+	if err := os.WriteFile(outside, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	Begin("search")()
+	Begin("search")()
+	Begin("render")()
+	Read(prompt)
+	Read(prompt)
+	Read(filepath.Join(base, "missing.md"))
+	Write(prompt)
+	Delete(outside)
+
+	phases := Phases()
+	if len(phases) != 2 || phases[0].Name != "search" || phases[0].Calls != 2 || phases[1].Calls != 1 {
+		t.Fatalf("Phases = %+v, want search twice and render once", phases)
+	}
+	if files := Files(); len(files) != 3 {
+		t.Fatalf("Files = %+v, want the read once, the write and the delete, without the missing file", files)
+	}
+
+	var report strings.Builder
+	Report(&report, base)
+	for _, want := range []string{"search", "2 calls", "render", "1 call", "total", "Files touched (3)", "read    " + filepath.Join("prompts", "a.md"), "write   " + filepath.Join("prompts", "a.md"), "delete  " + outside} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("Report is missing %q:\n%s", want, report.String())
+		}
+	}
+}
@@ -2,7 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -33,6 +36,41 @@ type BooleanSearchModal struct {
 	applyRequested bool // Flag to indicate apply search and return to list was requested
 	editMode       bool // Flag to indicate edit mode
 	originalSearch *models.SavedSearch // Original search being edited
+	tagCounts      map[string]int      // Prompts per tag, lower-cased like tag matching
+	suggestions    []tagSuggestion     // Tags offered for the word at the cursor
+	moreMatches    int                 // Matching tags beyond those in suggestions
+	confirmEmpty   bool                // Enter was pressed once on a search with no results
+	searchSeq      int                 // Identifies the latest live search; older results are dropped
+	searching      bool                // A live search for the current query has not answered yet
+}
+
+// searchDebounce is how long typing must pause before the live search runs
+const searchDebounce = 150 * time.Millisecond
+
+// booleanSearchDebounceMsg fires once typing has paused for searchDebounce
+// after the query numbered seq
+type booleanSearchDebounceMsg struct {
+	seq int
+}
+
+// booleanSearchResultMsg carries the results of the live search numbered seq,
+// and for each suggested tag, the results with that tag completed at the cursor
+type booleanSearchResultMsg struct {
+	seq     int
+	results []*models.Prompt
+	err     error
+	impact  map[string]int
+}
+
+// maxSuggestions is how many tag suggestions are listed, with their impact
+const maxSuggestions = 6
+
+// tagSuggestion is a tag offered for completion: how many prompts carry it,
+// and how many the search would return with it in place of the current word
+type tagSuggestion struct {
+	tag     string
+	prompts int
+	results int
 }
 
 // NewBooleanSearchModal creates a new modal boolean search
@@ -148,6 +186,15 @@ func (m *BooleanSearchModal) Update(msg tea.Msg) tea.Cmd {
 				expr, err := m.parseQuery(m.currentQuery)
 				if err == nil {
 					m.expression = expr
+					// Results still on their way are needed now to tell whether any match
+					if m.searching {
+						m.searchNow()
+					}
+					// A search that matches nothing is flagged once before it is applied
+					if m.searchFunc != nil && len(m.searchResults) == 0 && !m.confirmEmpty {
+						m.confirmEmpty = true
+						return nil
+					}
 					m.applyRequested = true
 					m.isActive = false
 				}
@@ -157,7 +204,7 @@ func (m *BooleanSearchModal) Update(msg tea.Msg) tea.Cmd {
 
 		// Handle boolean input updates
 		if !m.focusResults && !m.focusTextInput {
-			oldQuery := m.booleanInput.Value()
+			oldQuery, oldPos := m.booleanInput.Value(), m.booleanInput.Position()
 			m.booleanInput, cmd = m.booleanInput.Update(msg)
 			newQuery := m.booleanInput.Value()
 			
@@ -167,18 +214,10 @@ func (m *BooleanSearchModal) Update(msg tea.Msg) tea.Cmd {
 			// Trigger live search if query changed
 			if newQuery != oldQuery {
 				m.currentQuery = newQuery
+				m.confirmEmpty = false
 				if newQuery != "" {
-					expr, err := m.parseQuery(newQuery)
-					if err == nil {
+					if expr, err := m.parseQuery(newQuery); err == nil {
 						m.expression = expr
-						// Perform live search if callback is set
-						if m.searchFunc != nil {
-							results, err := m.searchFunc(expr)
-							if err == nil {
-								m.searchResults = results
-								m.resultsCursor = 0
-							}
-						}
 					}
 				} else {
 					// Clear results when query is empty
@@ -186,6 +225,10 @@ func (m *BooleanSearchModal) Update(msg tea.Msg) tea.Cmd {
 					m.expression = nil
 				}
 			}
+			// Moving the cursor to another word changes the suggestions to count
+			if newQuery != oldQuery || m.booleanInput.Position() != oldPos {
+				cmd = tea.Batch(cmd, m.scheduleSearch())
+			}
 		}
 
 		// Handle text input updates
@@ -199,11 +242,83 @@ func (m *BooleanSearchModal) Update(msg tea.Msg) tea.Cmd {
 				m.textQuery = newTextQuery
 			}
 		}
+
+	case booleanSearchDebounceMsg:
+		// Typing continued since this was scheduled
+		if msg.seq != m.searchSeq {
+			return nil
+		}
+		return m.searchCmd()
+
+	case booleanSearchResultMsg:
+		// A newer query superseded this one
+		if msg.seq != m.searchSeq {
+			return nil
+		}
+		m.applySearchResult(msg)
 	}
 
 	return cmd
 }
 
+// scheduleSearch supersedes any live search under way and schedules one for
+// the current query and suggestions once typing pauses
+func (m *BooleanSearchModal) scheduleSearch() tea.Cmd {
+	m.searchSeq++
+	m.searching = false
+	if m.searchFunc == nil || (m.expression == nil || m.currentQuery == "") && len(m.suggestions) == 0 {
+		return nil
+	}
+	m.searching = true
+	seq := m.searchSeq
+	return tea.Tick(searchDebounce, func(time.Time) tea.Msg {
+		return booleanSearchDebounceMsg{seq: seq}
+	})
+}
+
+// searchCmd runs the live search for the current query, and counts the
+// results for each suggested tag, off the UI loop
+func (m *BooleanSearchModal) searchCmd() tea.Cmd {
+	search, seq := m.searchFunc, m.searchSeq
+	var expr *models.BooleanExpression
+	if m.currentQuery != "" {
+		expr = m.expression
+	}
+	candidates := m.suggestionExpressions()
+	return func() tea.Msg {
+		msg := booleanSearchResultMsg{seq: seq, impact: make(map[string]int, len(candidates))}
+		if expr != nil {
+			msg.results, msg.err = search(expr)
+		}
+		for tag, candidate := range candidates {
+			if results, err := search(candidate); err == nil {
+				msg.impact[tag] = len(results)
+			}
+		}
+		return msg
+	}
+}
+
+// searchNow runs the live search at once, dropping any still under way
+func (m *BooleanSearchModal) searchNow() {
+	m.searchSeq++
+	m.applySearchResult(m.searchCmd()().(booleanSearchResultMsg))
+}
+
+// applySearchResult shows the results of a live search
+func (m *BooleanSearchModal) applySearchResult(msg booleanSearchResultMsg) {
+	m.searching = false
+	if msg.err == nil && m.expression != nil && m.currentQuery != "" {
+		m.searchResults = msg.results
+		m.resultsCursor = 0
+	}
+	for i := range m.suggestions {
+		if n, ok := msg.impact[m.suggestions[i].tag]; ok {
+			m.suggestions[i].results = n
+		}
+	}
+}
+
 // updateAutocomplete updates the autocomplete suggestions based on current input context
 func (m *BooleanSearchModal) updateAutocomplete() {
 	if len(m.availableTags) == 0 {
@@ -219,6 +334,8 @@ func (m *BooleanSearchModal) updateAutocomplete() {
 	if currentWord == "" {
 		// Show all tags if no current word
 		m.booleanInput.SetSuggestions(m.availableTags)
+		m.suggestions = nil
+		m.moreMatches = 0
 	} else {
 		// Filter tags that start with the current word (case insensitive)
 		var filteredTags []string
@@ -229,7 +346,67 @@ func (m *BooleanSearchModal) updateAutocomplete() {
 			}
 		}
 		m.booleanInput.SetSuggestions(filteredTags)
+		m.suggestions = m.suggestionImpact(filteredTags)
+		m.moreMatches = len(filteredTags) - len(m.suggestions)
+	}
+}
+
+// suggestionImpact lists the first few matching tags with the number of
+// prompts that carry each. The results the search would have with each tag
+// completed at the cursor are counted by the live search.
+func (m *BooleanSearchModal) suggestionImpact(tags []string) []tagSuggestion {
+	suggestions := make([]tagSuggestion, 0, min(len(tags), maxSuggestions))
+	for _, tag := range tags[:min(len(tags), maxSuggestions)] {
+		suggestions = append(suggestions, tagSuggestion{tag: tag, prompts: m.tagCounts[strings.ToLower(tag)], results: -1})
+	}
+	return suggestions
+}
+
+// suggestionExpressions returns, for each suggested tag, the query with the
+// tag completed at the cursor
+func (m *BooleanSearchModal) suggestionExpressions() map[string]*models.BooleanExpression {
+	value := m.booleanInput.Value()
+	start, end := m.currentWordBounds(value, m.booleanInput.Position())
+	expressions := make(map[string]*models.BooleanExpression, len(m.suggestions))
+	for _, s := range m.suggestions {
+		if expr, err := m.parseQuery(value[:start] + s.tag + value[end:]); err == nil {
+			expressions[s.tag] = expr
+		}
+	}
+	return expressions
+}
+
+// SetTagCounts records how many prompts carry each tag. Tags are then offered
+// most used first.
+func (m *BooleanSearchModal) SetTagCounts(counts map[string]int) {
+	m.tagCounts = make(map[string]int, len(counts))
+	tags := make([]string, 0, len(counts))
+	for tag, n := range counts {
+		m.tagCounts[strings.ToLower(tag)] += n
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	m.availableTags = tags
+	m.updateAutocomplete()
+}
+
+// unknownTags returns the tags in the expression that no prompt carries
+func (m *BooleanSearchModal) unknownTags() []string {
+	if m.tagCounts == nil || m.expression == nil {
+		return nil
+	}
+	var unknown []string
+	for _, tag := range m.expression.Tags() {
+		if m.tagCounts[strings.ToLower(tag)] == 0 && !slices.Contains(unknown, tag) {
+			unknown = append(unknown, tag)
+		}
 	}
+	return unknown
 }
 
 // getCurrentWordForCompletion extracts the word at the cursor that should be completed
@@ -237,6 +414,24 @@ func (m *BooleanSearchModal) getCurrentWordForCompletion(text string, cursorPos
 	if cursorPos < 0 || cursorPos > len(text) {
 		return ""
 	}
+
+	wordStart, wordEnd := m.currentWordBounds(text, cursorPos)
+	word := strings.TrimSpace(text[wordStart:wordEnd])
+	
+	// Don't autocomplete boolean operators
+	upperWord := strings.ToUpper(word)
+	if upperWord == "AND" || upperWord == "OR" || upperWord == "NOT" {
+		return ""
+	}
+	
+	return word
+}
+
+// currentWordBounds returns where the word at the cursor starts and ends
+func (m *BooleanSearchModal) currentWordBounds(text string, cursorPos int) (int, int) {
+	if cursorPos < 0 || cursorPos > len(text) {
+		return len(text), len(text)
+	}
 	
 	// Find word boundaries - spaces and boolean operators
 	separators := []string{" AND ", " OR ", " NOT ", " ", "(", ")"}
@@ -278,61 +473,13 @@ func (m *BooleanSearchModal) getCurrentWordForCompletion(text string, cursorPos
 	if wordEnd > len(text) {
 		wordEnd = len(text)
 	}
-	
-	word := strings.TrimSpace(text[wordStart:wordEnd])
-	
-	// Don't autocomplete boolean operators
-	upperWord := strings.ToUpper(word)
-	if upperWord == "AND" || upperWord == "OR" || upperWord == "NOT" {
-		return ""
-	}
-	
-	return word
+	return wordStart, wordEnd
 }
 
-// parseQuery parses a simple boolean query string into an expression
+// parseQuery parses a boolean query string into an expression, as every
+// other interface does
 func (m *BooleanSearchModal) parseQuery(query string) (*models.BooleanExpression, error) {
-	// Simple parser for basic boolean queries
-	query = strings.TrimSpace(query)
-	
-	// Handle NOT operations first
-	if strings.HasPrefix(strings.ToUpper(query), "NOT ") {
-		inner := strings.TrimSpace(query[4:])
-		innerExpr, err := m.parseQuery(inner)
-		if err != nil {
-			return nil, err
-		}
-		return models.NewNotExpression(innerExpr), nil
-	}
-	
-	// Split by OR (lower precedence)
-	if orParts := strings.Split(query, " OR "); len(orParts) > 1 {
-		var expressions []*models.BooleanExpression
-		for _, part := range orParts {
-			expr, err := m.parseQuery(strings.TrimSpace(part))
-			if err != nil {
-				return nil, err
-			}
-			expressions = append(expressions, expr)
-		}
-		return models.NewOrExpression(expressions...), nil
-	}
-	
-	// Split by AND (higher precedence)
-	if andParts := strings.Split(query, " AND "); len(andParts) > 1 {
-		var expressions []*models.BooleanExpression
-		for _, part := range andParts {
-			expr, err := m.parseQuery(strings.TrimSpace(part))
-			if err != nil {
-				return nil, err
-			}
-			expressions = append(expressions, expr)
-		}
-		return models.NewAndExpression(expressions...), nil
-	}
-	
-	// Single tag
-	return models.NewTagExpression(query), nil
+	return models.ParseBooleanExpression(query)
 }
 
 // View renders the modal
@@ -393,6 +540,9 @@ func (m *BooleanSearchModal) View() string {
 	}
 	content = append(content, headerStyle.Render(booleanInputTitle))
 	content = append(content, m.booleanInput.View())
+	if !m.focusTextInput && !m.focusResults {
+		content = append(content, m.renderSuggestions()...)
+	}
 
 	// Text search input
 	textInputTitle := "Text Filter (optional):"
@@ -412,8 +562,12 @@ func (m *BooleanSearchModal) View() string {
 		if m.textQuery != "" {
 			exprText += fmt.Sprintf(" + text:\"%s\"", m.textQuery)
 		}
+		count := countNoun(len(m.searchResults), "prompt")
+		if m.searching {
+			count = "searching…"
+		}
 		content = append(content, "")
-		content = append(content, "Expression: "+exprStyle.Render(exprText))
+		content = append(content, "Expression: "+exprStyle.Render(exprText)+" → "+count)
 	}
 
 	// Results
@@ -439,8 +593,15 @@ func (m *BooleanSearchModal) View() string {
 			}
 			content = append(content, style.Render(promptLine))
 		}
-	} else if m.currentQuery != "" && m.expression != nil {
-		content = append(content, resultStyle.Render("No results found"))
+	} else if m.currentQuery != "" && m.expression != nil && !m.searching {
+		warning := "⚠ No prompts match this search"
+		if unknown := m.unknownTags(); len(unknown) > 0 {
+			warning += fmt.Sprintf(" (no prompt is tagged %s)", strings.Join(unknown, ", "))
+		}
+		content = append(content, resultStyle.Render(warning))
+		if m.confirmEmpty {
+			content = append(content, lipgloss.NewStyle().Bold(true).Render("Press Enter again to apply it anyway"))
+		}
 	}
 
 	// Save prompt if requested
@@ -479,6 +640,46 @@ func (m *BooleanSearchModal) View() string {
 	return modalStyle.Render(modalContent)
 }
 
+// renderSuggestions lists the tags offered for the word at the cursor, each
+// with the number of prompts tagged with it and the results the search would
+// have with it, flagging tags that would leave nothing
+func (m *BooleanSearchModal) renderSuggestions() []string {
+	if len(m.suggestions) == 0 {
+		return nil
+	}
+	dimStyle := lipgloss.NewStyle().Faint(true)
+	warnStyle := lipgloss.NewStyle().Bold(true)
+
+	width := 0
+	for _, s := range m.suggestions {
+		width = max(width, len(s.tag))
+	}
+
+	var lines []string
+	for _, s := range m.suggestions {
+		line := fmt.Sprintf("  %-*s %s", width, s.tag, dimStyle.Render(fmt.Sprintf("%4d", s.prompts)))
+		switch {
+		case s.results == 0:
+			line += " " + warnStyle.Render("→ no results ⚠")
+		case s.results > 0:
+			line += " " + dimStyle.Render("→ "+countNoun(s.results, "result"))
+		}
+		lines = append(lines, line)
+	}
+	if m.moreMatches > 0 {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  … %d more", m.moreMatches)))
+	}
+	return lines
+}
+
+// countNoun writes n with a noun, pluralised when needed
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
 // SetActive sets the modal active state
 func (m *BooleanSearchModal) SetActive(active bool) {
 	m.isActive = active
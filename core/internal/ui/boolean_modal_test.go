@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+func TestBooleanSearchModal_SuggestionImpact(t *testing.T) {
+	prompts := []*models.Prompt{
+		{ID: "a", Tags: []string{"ai", "writing"}},
+		{ID: "b", Tags: []string{"ai", "analysis"}},
+		{ID: "c", Tags: []string{"ai"}},
+	}
+
+	modal := NewBooleanSearchModal(nil)
+	modal.SetSearchFunc(func(expr *models.BooleanExpression) ([]*models.Prompt, error) {
+		var results []*models.Prompt
+		for _, p := range prompts {
+			if expr.Evaluate(p.Tags) {
+				results = append(results, p)
+			}
+		}
+		return results, nil
+	})
+	modal.SetTagCounts(map[string]int{"ai": 3, "writing": 1, "analysis": 1})
+
+	// Most used tags come first, ties by name
+	if got := modal.availableTags; len(got) != 3 || got[0] != "ai" || got[1] != "analysis" || got[2] != "writing" {
+		t.Errorf("Expected tags ordered by count, got %v", got)
+	}
+
+	modal.booleanInput.SetValue("writing AND a")
+	modal.updateAutocomplete()
+	// Suggestion counts come from the live search, which runs off the UI loop
+	modal.applySearchResult(modal.searchCmd()().(booleanSearchResultMsg))
+
+	if len(modal.suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions for 'a', got %+v", modal.suggestions)
+	}
+	for _, s := range modal.suggestions {
+		switch s.tag {
+		case "ai":
+			if s.prompts != 3 || s.results != 1 {
+				t.Errorf("Expected ai to have 3 prompts and 1 result, got %+v", s)
+			}
+		case "analysis":
+			if s.prompts != 1 || s.results != 0 {
+				t.Errorf("Expected analysis to have 1 prompt and 0 results, got %+v", s)
+			}
+		default:
+			t.Errorf("Unexpected suggestion %q", s.tag)
+		}
+	}
+
+	modal.expression = models.NewAndExpression(models.NewTagExpression("ai"), models.NewTagExpression("missing"))
+	if got := modal.unknownTags(); len(got) != 1 || got[0] != "missing" {
+		t.Errorf("Expected unknown tag 'missing', got %v", got)
+	}
+}
+
+func TestBooleanSearchModal_DebouncedLiveSearch(t *testing.T) {
+	prompts := []*models.Prompt{
+		{ID: "a", Tags: []string{"ai"}},
+		{ID: "b", Tags: []string{"go"}},
+	}
+	searches := 0
+	modal := NewBooleanSearchModal(nil)
+	modal.SetSearchFunc(func(expr *models.BooleanExpression) ([]*models.Prompt, error) {
+		searches++
+		var results []*models.Prompt
+		for _, p := range prompts {
+			if expr.Evaluate(p.Tags) {
+				results = append(results, p)
+			}
+		}
+		return results, nil
+	})
+	modal.SetActive(true)
+
+	for _, r := range "ai" {
+		if cmd := modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}); cmd == nil {
+			t.Fatalf("Expected typing %q to schedule a search", r)
+		}
+	}
+	if searches != 0 || !modal.searching {
+		t.Fatalf("Expected no search while typing, got %d searches (searching %v)", searches, modal.searching)
+	}
+
+	// Only the debounce for the latest keystroke starts a search
+	if cmd := modal.Update(booleanSearchDebounceMsg{seq: modal.searchSeq - 1}); cmd != nil {
+		t.Error("Expected the debounce for an earlier keystroke to be dropped")
+	}
+	cmd := modal.Update(booleanSearchDebounceMsg{seq: modal.searchSeq})
+	if cmd == nil {
+		t.Fatal("Expected the latest debounce to start a search")
+	}
+	result := cmd().(booleanSearchResultMsg)
+	if searches != 1 {
+		t.Errorf("Expected 1 search, got %d", searches)
+	}
+
+	// Results for a superseded query are dropped
+	modal.Update(booleanSearchResultMsg{seq: modal.searchSeq - 1, results: prompts})
+	if len(modal.searchResults) != 0 || !modal.searching {
+		t.Errorf("Expected stale results to be dropped, got %d results", len(modal.searchResults))
+	}
+	modal.Update(result)
+	if len(modal.searchResults) != 1 || modal.searchResults[0].ID != "a" || modal.searching {
+		t.Errorf("Expected the result for ai, got %+v (searching %v)", modal.searchResults, modal.searching)
+	}
+
+	// Enter while a search is pending searches at once, so an empty search is flagged
+	modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	modal.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !modal.confirmEmpty || modal.searching || len(modal.searchResults) != 0 {
+		t.Errorf("Expected Enter to search aix at once and ask to confirm no results, got confirm %v, %d results", modal.confirmEmpty, len(modal.searchResults))
+	}
+}
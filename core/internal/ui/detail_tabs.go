@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+	"github.com/dpshade/pocket-prompt/internal/filemanager"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+)
+
+// detailTab is a pane of the prompt detail view, switched with 1-4
+type detailTab int
+
+const (
+	tabContent  detailTab = iota // Rendered content, with attachments
+	tabSource                    // Raw markdown with the full frontmatter
+	tabVersions                  // Version history
+	tabUsage                     // Usage stats and logged outcomes
+)
+
+// detailTabLabels returns the names shown in the detail view's tab row
+func detailTabLabels() []string {
+	return []string{
+		i18n.T("prompt.tab_content"),
+		i18n.T("prompt.tab_source"),
+		i18n.T("prompt.tab_versions"),
+		i18n.T("prompt.tab_usage"),
+	}
+}
+
+// showDetailTab switches the detail view to tab, from its top
+func (m *Model) showDetailTab(tab detailTab) {
+	if tab == m.detailTab {
+		return
+	}
+	m.detailTab = tab
+	m.renderPreview()
+	m.viewport.GotoTop()
+}
+
+// detailMarkdown is the markdown of the current tab, given the selected
+// prompt rendered for display. Attachment links are shown but not copied.
+func (m *Model) detailMarkdown(display string) string {
+	switch m.detailTab {
+	case tabSource:
+		return m.sourceMarkdown()
+	case tabVersions:
+		return m.historyMarkdown()
+	case tabUsage:
+		return m.usageMarkdown() + m.resultsMarkdown()
+	default:
+		return display + m.attachmentsMarkdown()
+	}
+}
+
+// detailTrailer is what the current tab shows below its markdown, or nil:
+// on the versions tab, the changes the latest version made
+func (m *Model) detailTrailer() func(width int) string {
+	if m.detailTab != tabVersions || isForeign(m.selectedPrompt) {
+		return nil
+	}
+	changes, err := m.service.DiffVersions(m.selectedPrompt.ID, "", "")
+	if err != nil || changes.Added+changes.Removed == 0 {
+		return nil
+	}
+	lines := diff.Trim(diff.Annotate(changes.Diff), 2)
+	title := fmt.Sprintf("Changes in v%s", changes.To)
+	return func(width int) string {
+		// Indented to line up with glamour's margin
+		rendered := make([]diff.Line, len(lines))
+		for i, line := range lines {
+			rendered[i] = line.Clip(max(20, width-6))
+		}
+		var b strings.Builder
+		b.WriteString("  " + StyleText.Bold(true).Render(title) + "\n\n")
+		for _, line := range diff.Render(rendered, DiffStyle()) {
+			b.WriteString("  " + line + "\n")
+		}
+		return b.String()
+	}
+}
+
+// sourceMarkdown shows the selected prompt's file as stored: frontmatter
+// and markdown, unrendered
+func (m *Model) sourceMarkdown() string {
+	if isForeign(m.selectedPrompt) {
+		return "The source of prompts from other sources is not available here.\n"
+	}
+	path, err := m.service.PromptPath(m.selectedPrompt.ID)
+	if err != nil {
+		return fmt.Sprintf("No source available: %v\n", err)
+	}
+	data, err := m.service.PromptSource(m.selectedPrompt.ID)
+	if err != nil {
+		return fmt.Sprintf("No source available: %v\n", err)
+	}
+	// A fence longer than any in the file keeps it in one code block
+	fence := "```"
+	for strings.Contains(string(data), fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("`%s`\n\n%s\n%s\n%s\n", path, fence, strings.TrimRight(string(data), "\n"), fence)
+}
+
+// usageMarkdown summarises how the selected prompt is used: copies, versions
+// and size
+func (m *Model) usageMarkdown() string {
+	var b strings.Builder
+	b.WriteString("**Usage**\n\n")
+	if isForeign(m.selectedPrompt) {
+		b.WriteString("Usage of prompts from other sources is counted by their own library.\n")
+		return b.String()
+	}
+	stats, err := m.service.PromptStats(m.selectedPrompt.ID)
+	if err != nil {
+		fmt.Fprintf(&b, "No usage available: %v\n", err)
+		return b.String()
+	}
+	lastUsed := "never"
+	if !stats.LastUsed.IsZero() {
+		lastUsed = i18n.FormatDateTime(stats.LastUsed.Local())
+	}
+	fmt.Fprintf(&b, "- **Copies** %d · last used %s\n", stats.UsageCount, lastUsed)
+	fmt.Fprintf(&b, "- **Versions** %d · current v%s\n", stats.Versions, stats.Version)
+	fmt.Fprintf(&b, "- **Size** %d words · about %d tokens\n", stats.Words, stats.Tokens)
+	if !stats.CreatedAt.IsZero() {
+		fmt.Fprintf(&b, "- **Created** %s\n", i18n.FormatDate(stats.CreatedAt.Local()))
+	}
+	if stats.ReviewState != "" {
+		fmt.Fprintf(&b, "- **Review** %s\n", stats.ReviewState)
+	}
+	return b.String()
+}
+
+// revealPrompt shows the selected prompt's file in the file manager
+func (m *Model) revealPrompt() {
+	m.statusTimeout = 3
+	if isForeign(m.selectedPrompt) {
+		m.statusMsg = i18n.T("status.reveal_failed", "prompts from other sources are stored by their own library")
+		return
+	}
+	path, err := m.service.PromptPath(m.selectedPrompt.ID)
+	if err == nil {
+		err = filemanager.Reveal(path)
+	}
+	if err != nil {
+		m.statusMsg = i18n.T("status.reveal_failed", err)
+		return
+	}
+	m.statusMsg = i18n.T("status.revealed", path)
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestDetailTabs(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Content: "Review this code", Tags: []string{"code"}}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	svc.RecordUsage("review")
+
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	if err := model.OpenPrompt("review"); err != nil {
+		t.Fatalf("OpenPrompt: %v", err)
+	}
+	m, _ := resizeWindow(*model, 100, 40)
+	press := func(k string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = updated.(Model)
+	}
+
+	for _, tc := range []struct {
+		key  string
+		tab  detailTab
+		want string
+	}{
+		{"2", tabSource, "id: review"},
+		{"3", tabVersions, "**History**"},
+		{"4", tabUsage, "**Copies** 1"},
+		{"1", tabContent, "Review this code"},
+		{"R", tabSource, "---\nschema"},
+		{"H", tabVersions, "**History**"},
+	} {
+		press(tc.key)
+		if m.detailTab != tc.tab || !strings.Contains(m.preview.markdown, tc.want) {
+			t.Errorf("after %s: tab %d showing %q, want tab %d with %q", tc.key, m.detailTab, m.preview.markdown, tc.tab, tc.want)
+		}
+	}
+	if m.renderedContent != "Review this code" {
+		t.Errorf("Expected copies to take the content from any tab, got %q", m.renderedContent)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(Model); m.detailTab != tabContent {
+		t.Error("Expected leaving the prompt to return to the content tab")
+	}
+}
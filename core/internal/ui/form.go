@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"regexp"
 	"strings"
 	"time"
 
@@ -9,40 +8,11 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dpshade/pocket-prompt/internal/lint"
 	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/spellcheck"
 )
 
-// generateIDFromTitle creates a URL-safe ID from a title
-func generateIDFromTitle(title string) string {
-	if title == "" {
-		return "untitled-prompt"
-	}
-	
-	// Convert to lowercase
-	id := strings.ToLower(title)
-	
-	// Replace spaces and special characters with hyphens
-	reg := regexp.MustCompile(`[^a-z0-9]+`)
-	id = reg.ReplaceAllString(id, "-")
-	
-	// Remove leading and trailing hyphens
-	id = strings.Trim(id, "-")
-	
-	// Ensure it's not empty
-	if id == "" {
-		return "untitled-prompt"
-	}
-	
-	// Limit length to 50 characters
-	if len(id) > 50 {
-		id = id[:50]
-		// Remove trailing hyphen if trimming created one
-		id = strings.TrimSuffix(id, "-")
-	}
-	
-	return id
-}
-
 // CreateForm handles prompt creation
 type CreateForm struct {
 	inputs        []textinput.Model
@@ -52,6 +22,12 @@ type CreateForm struct {
 	fromScratch   bool // True for simplified "from scratch" form
 	availableTags []string // Added for tag autocomplete
 	availablePacks []string // Added for pack autocomplete
+
+	// Spell check and style hints for the content, from the latest check
+	misspellings   []spellcheck.Misspelling
+	styleIssues    []lint.Issue
+	checkScheduled bool // A check of the content has been started
+	saveAnyway     bool // Misspellings were shown; the next Ctrl+s saves regardless
 }
 
 // Form field indices
@@ -425,7 +401,7 @@ func (f *CreateForm) ToPrompt() *models.Prompt {
 	if f.fromScratch {
 		// From scratch form: auto-generate ID from title, use all form fields
 		title := f.inputs[titleField].Value()
-		id := generateIDFromTitle(title)
+		id := models.IDFromTitle(title, "untitled-prompt")
 		
 		// Parse tags from comma-separated string
 		tags := []string{}
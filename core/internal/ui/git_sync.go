@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// gitSyncTickMsg is sent when it is time for the next git.tui_interval pull
+type gitSyncTickMsg struct{}
+
+// gitPulledMsg carries what a scheduled pull changed
+type gitPulledMsg struct {
+	changes []service.PromptEvent
+	err     error
+}
+
+// gitSyncTickCmd waits for the next scheduled pull, or returns nil when the
+// TUI does not pull on its own
+func (m Model) gitSyncTickCmd() tea.Cmd {
+	if m.gitSyncInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(m.gitSyncInterval, func(time.Time) tea.Msg {
+		return gitSyncTickMsg{}
+	})
+}
+
+// gitPullCmd pulls from the remote without blocking the TUI
+func gitPullCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		changes, err := svc.PullPromptChanges()
+		return gitPulledMsg{changes: changes, err: err}
+	}
+}
+
+// handleGitPulled shows what a scheduled pull brought in. The list is
+// refreshed in place, keeping its filter and the selected prompt, and a
+// failure is reported once rather than after every pull.
+func (m Model) handleGitPulled(msg gitPulledMsg) (tea.Model, tea.Cmd) {
+	next := m.gitSyncTickCmd()
+	if msg.err != nil {
+		// Timeouts are routine on flaky networks, as in background sync
+		if strings.Contains(msg.err.Error(), "timeout") || msg.err.Error() == m.gitSyncErr {
+			return m, next
+		}
+		m.gitSyncErr = msg.err.Error()
+		m.statusMsg = i18n.T("status.git_sync_failed", msg.err)
+		m.statusTimeout = 5
+		return m, tea.Batch(next, clearStatusCmd())
+	}
+	m.gitSyncErr = ""
+	if len(msg.changes) == 0 {
+		return m, next
+	}
+
+	cmds := []tea.Cmd{next, clearStatusCmd(), quotaCheckCmd(m.service), subscriptionsCheckCmd(m.service)}
+	// Prompts from other sources did not change, so their list stays as it is
+	if m.currentSource == config.LocalSourceName && !m.loading {
+		cmd, err := m.refreshPulledPrompts()
+		if err != nil {
+			m.statusMsg = i18n.T("status.refresh_failed", err)
+			m.statusTimeout = 3
+			return m, tea.Batch(cmds...)
+		}
+		cmds = append(cmds, cmd)
+	}
+	m.statusMsg = i18n.T("status.git_pulled", len(msg.changes))
+	m.statusTimeout = 3
+	return m, tea.Batch(cmds...)
+}
+
+// refreshPulledPrompts lists the prompts again after a pull without moving
+// the cursor off the selected prompt or dropping the list's filter
+func (m *Model) refreshPulledPrompts() (tea.Cmd, error) {
+	var selected string
+	if p, ok := m.promptList.SelectedItem().(*models.Prompt); ok {
+		selected = p.ID
+	}
+	if err := m.refreshPromptListSmart(); err != nil {
+		return nil, err
+	}
+	if m.promptList.FilterState() == list.Unfiltered {
+		m.selectPrompt(selected)
+		return nil, nil
+	}
+	// A filter searches every prompt, and the list only shows its matches
+	// again once it has run over them
+	m.loadAllPrompts()
+	return m.promptList.SetItems(m.promptList.Items()), nil
+}
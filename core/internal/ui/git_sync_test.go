@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestScheduledPullRefreshesList(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a", Name: "Alpha", Content: "a"},
+		{ID: "b", Name: "Beta", Content: "b"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	if model.gitSyncTickCmd() != nil {
+		t.Error("Expected no scheduled pulls without git.tui_interval")
+	}
+	prompts, _ := svc.ListPrompts()
+	updated, _ := model.Update(loadCompleteMsg{prompts: prompts})
+	m := updated.(Model)
+	m.selectPrompt("b")
+
+	// As if a pull had brought in prompt c
+	if err := svc.CreatePrompt(&models.Prompt{ID: "c", Name: "Gamma", Content: "c"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	updated, _ = m.Update(gitPulledMsg{changes: []service.PromptEvent{{Kind: service.ChangeAdded, ID: "c"}}})
+	m = updated.(Model)
+	if len(m.promptList.Items()) != 3 {
+		t.Errorf("Expected the pulled prompt listed, got %d prompts", len(m.promptList.Items()))
+	}
+	if p, ok := m.promptList.SelectedItem().(*models.Prompt); !ok || p.ID != "b" {
+		t.Errorf("Expected prompt b still selected, got %v", m.promptList.SelectedItem())
+	}
+	if m.statusMsg == "" {
+		t.Error("Expected a notice of the pulled changes")
+	}
+
+	// A failure is reported once, not after every pull
+	updated, _ = m.Update(gitPulledMsg{err: errors.New("auth failed")})
+	m = updated.(Model)
+	if m.statusMsg != "Git sync failed: auth failed" {
+		t.Errorf("Expected the failure shown, got %q", m.statusMsg)
+	}
+	m.statusMsg = ""
+	updated, _ = m.Update(gitPulledMsg{err: errors.New("auth failed")})
+	if msg := updated.(Model).statusMsg; msg != "" {
+		t.Errorf("Expected the same failure not shown again, got %q", msg)
+	}
+}
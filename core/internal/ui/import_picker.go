@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// ImportPicker is a checklist of items found by an import preview. New and
+// changed items start selected; the diff of the highlighted item is shown
+// below the list so conflicts can be reviewed before importing, or resolved
+// hunk by hunk in a merge view.
+type ImportPicker struct {
+	candidates []service.ImportCandidate
+	selected   map[string]bool
+	merged     map[string]string // Content resolved in the merge view, by key
+	merge      *MergeView        // Open merge view, if any
+	cursor     int
+	diffOffset int
+	confirmed  bool
+	width      int
+	height     int
+}
+
+// NewImportPicker creates a picker for the given candidates
+func NewImportPicker(candidates []service.ImportCandidate) *ImportPicker {
+	selected := make(map[string]bool)
+	for _, c := range candidates {
+		if c.Status != service.CandidateUnchanged {
+			selected[c.Key] = true
+		}
+	}
+	return &ImportPicker{candidates: candidates, selected: selected, merged: make(map[string]string), width: 80, height: 24}
+}
+
+// RunImportPicker shows the picker full screen and returns the chosen items
+// as an importer selection, with the content of the prompts merged in the
+// merge view. ok is false when the user cancels.
+func RunImportPicker(candidates []service.ImportCandidate) (selection map[string]bool, merged map[string]string, ok bool, err error) {
+	final, err := tea.NewProgram(NewImportPicker(candidates), tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	picker := final.(*ImportPicker)
+	if !picker.confirmed {
+		return nil, nil, false, nil
+	}
+	return picker.selected, picker.merged, true, nil
+}
+
+// Init implements tea.Model
+func (p *ImportPicker) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (p *ImportPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if p.merge != nil {
+		if size, ok := msg.(tea.WindowSizeMsg); ok {
+			p.width, p.height = size.Width, size.Height
+		}
+		_, cmd := p.merge.Update(msg)
+		if p.merge.done {
+			if p.merge.confirmed {
+				key := p.candidates[p.cursor].Key
+				p.merged[key] = p.merge.Result()
+				p.selected[key] = true
+			}
+			p.merge = nil
+		}
+		return p, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if p.cursor > 0 {
+				p.cursor--
+				p.diffOffset = 0
+			}
+		case "down", "j":
+			if p.cursor < len(p.candidates)-1 {
+				p.cursor++
+				p.diffOffset = 0
+			}
+		case " ", "x":
+			if len(p.candidates) > 0 {
+				key := p.candidates[p.cursor].Key
+				p.selected[key] = !p.selected[key]
+			}
+		case "a":
+			for _, c := range p.candidates {
+				p.selected[c.Key] = true
+			}
+		case "n":
+			p.selected = make(map[string]bool)
+		case "m":
+			if len(p.candidates) > 0 {
+				p.openMerge(p.candidates[p.cursor])
+			}
+		case "pgdown", "ctrl+d":
+			p.diffOffset += p.diffHeight() / 2
+		case "pgup", "ctrl+u":
+			p.diffOffset = max(0, p.diffOffset-p.diffHeight()/2)
+		case "enter":
+			p.confirmed = true
+			return p, tea.Quit
+		case "esc", "q", "ctrl+c":
+			return p, tea.Quit
+		}
+	}
+	return p, nil
+}
+
+// openMerge resolves a changed prompt's content conflicts in a merge view.
+// Merging a prompt again starts over from no picks.
+func (p *ImportPicker) openMerge(c service.ImportCandidate) {
+	if c.Local == "" && c.Incoming == "" {
+		return // New, unchanged, or only its metadata changed
+	}
+	p.merge = NewMergeView(c.ID, "Library", "Import", c.Local, c.Incoming)
+	p.merge.embedded = true
+	p.merge.width, p.merge.height = p.width, p.height
+}
+
+// listHeight is how many rows the checklist gets: up to half the screen
+func (p *ImportPicker) listHeight() int {
+	return max(3, min(len(p.candidates), (p.height-6)/2))
+}
+
+func (p *ImportPicker) diffHeight() int {
+	return max(3, p.height-p.listHeight()-7)
+}
+
+// View implements tea.Model
+func (p *ImportPicker) View() string {
+	if p.merge != nil {
+		return p.merge.View()
+	}
+	count := 0
+	for _, c := range p.candidates {
+		if p.selected[c.Key] {
+			count++
+		}
+	}
+	header := CreateSubPageHeader(fmt.Sprintf("Import — %d of %d selected", count, len(p.candidates)))
+
+	// Keep the cursor inside the visible window of the list
+	rows := p.listHeight()
+	start := 0
+	if p.cursor >= rows {
+		start = p.cursor - rows + 1
+	}
+	var lines []string
+	for i := start; i < len(p.candidates) && i < start+rows; i++ {
+		lines = append(lines, p.renderRow(i))
+	}
+
+	help := CreateHelp("↑/↓ move • Space toggle • a all • n none • m merge • PgUp/PgDn scroll diff • Enter import • Esc cancel")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header, "", strings.Join(lines, "\n"), "", p.renderDiff(), "", help)
+}
+
+func (p *ImportPicker) renderRow(i int) string {
+	c := p.candidates[i]
+	check := "[ ]"
+	if p.selected[c.Key] {
+		check = "[x]"
+	}
+
+	status := c.Status
+	if _, ok := p.merged[c.Key]; ok {
+		status = "merged"
+	}
+	switch status {
+	case service.CandidateNew:
+		status = lipgloss.NewStyle().Foreground(ColorSuccess).Render(status)
+	case service.CandidateChanged:
+		status = lipgloss.NewStyle().Foreground(ColorWarning).Render(status)
+	case "merged":
+		status = lipgloss.NewStyle().Foreground(ColorPrimary).Render(status)
+	default:
+		status = StyleTextDim.Render(status)
+	}
+
+	row := fmt.Sprintf("%s %-9s %-28s %s", check, c.Kind, truncate(c.ID, 28), c.Title)
+	if i == p.cursor {
+		row = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("› " + row)
+	} else {
+		row = "  " + row
+	}
+	return row + "  " + status
+}
+
+// renderDiff shows the highlighted item's changes against the library
+func (p *ImportPicker) renderDiff() string {
+	if len(p.candidates) == 0 {
+		return StyleTextMuted.Render("Nothing to import")
+	}
+	c := p.candidates[p.cursor]
+	switch c.Status {
+	case service.CandidateNew:
+		return StyleTextMuted.Render(fmt.Sprintf("%s is new to the library", c.ID))
+	case service.CandidateUnchanged:
+		return StyleTextMuted.Render(fmt.Sprintf("%s matches the library copy; importing it changes nothing", c.ID))
+	}
+
+	changes, title := c.Diff, "Changes to %s (%d-%d of %d lines)"
+	if merged, ok := p.merged[c.Key]; ok {
+		changes, title = diff.Lines(c.Local, merged), "Merged changes to %s (%d-%d of %d lines)"
+	}
+	height := p.diffHeight()
+	offset := min(p.diffOffset, max(0, len(changes)-height))
+	annotated := diff.Annotate(changes)[offset:min(len(changes), offset+height)]
+	for i := range annotated {
+		annotated[i] = annotated[i].Clip(max(20, p.width-2) - 2)
+	}
+	lines := diff.Render(annotated, DiffStyle())
+	header := StyleTextMuted.Render(fmt.Sprintf(title, c.ID, offset+1, offset+len(lines), len(changes)))
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
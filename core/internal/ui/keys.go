@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyViews groups the actions that ui.keys can rebind by the view they work
+// in, in the order the cheat sheet lists them
+var keyViews = []struct {
+	View    string
+	Actions []string
+}{
+	{"Everywhere", []string{"enter", "back", "left", "help", "keys", "expand-help", "quit"}},
+	{"Library", []string{"search", "new", "edit", "add-tag", "remove-tag", "mark", "move-pack", "boolean-search", "saved-searches", "templates", "template-gallery", "pack-selector", "source-switch"}},
+	{"Prompt detail", []string{"copy", "copy-json", "edit", "detail-tab", "history", "raw", "reveal", "profile", "next-section", "prev-section"}},
+	{"Saved searches", []string{"pin-search", "subscribe-search"}},
+}
+
+// keyActions lists the actions ui.keys can rebind, in cheat sheet order
+func keyActions() []string {
+	var actions []string
+	for _, view := range keyViews {
+		for _, action := range view.Actions {
+			if !slices.Contains(actions, action) {
+				actions = append(actions, action)
+			}
+		}
+	}
+	return actions
+}
+
+// binding returns the binding in k for an action named in ui.keys, or nil
+func (k *KeyMap) binding(action string) *key.Binding {
+	switch action {
+	case "enter":
+		return &k.Enter
+	case "back":
+		return &k.Back
+	case "left":
+		return &k.Left
+	case "help":
+		return &k.Help
+	case "keys":
+		return &k.Keys
+	case "expand-help":
+		return &k.ExpandHelp
+	case "quit":
+		return &k.Quit
+	case "search":
+		return &k.Search
+	case "new":
+		return &k.New
+	case "edit":
+		return &k.Edit
+	case "add-tag":
+		return &k.AddTag
+	case "remove-tag":
+		return &k.RemoveTag
+	case "boolean-search":
+		return &k.BooleanSearch
+	case "saved-searches":
+		return &k.SavedSearches
+	case "templates":
+		return &k.Templates
+	case "template-gallery":
+		return &k.TemplateGallery
+	case "pack-selector":
+		return &k.PackSelector
+	case "source-switch":
+		return &k.SourceSwitch
+	case "copy":
+		return &k.Copy
+	case "copy-json":
+		return &k.CopyJSON
+	case "detail-tab":
+		return &k.DetailTab
+	case "history":
+		return &k.History
+	case "raw":
+		return &k.Raw
+	case "reveal":
+		return &k.Reveal
+	case "profile":
+		return &k.Profile
+	case "next-section":
+		return &k.NextSection
+	case "prev-section":
+		return &k.PrevSection
+	case "pin-search":
+		return &k.PinSearch
+	case "subscribe-search":
+		return &k.SubscribeSearch
+	case "mark":
+		return &k.Mark
+	case "move-pack":
+		return &k.MovePack
+	}
+	return nil
+}
+
+// EffectiveKeys returns the TUI's keymap with the keys set in ui.keys in
+// place of the defaults
+func EffectiveKeys(overrides map[string]string) (KeyMap, error) {
+	k := keys
+	actions := make([]string, 0, len(overrides))
+	for action := range overrides {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		b := k.binding(action)
+		if b == nil {
+			return KeyMap{}, fmt.Errorf("unknown action %q in ui.keys (use one of %s)", action, strings.Join(keyActions(), ", "))
+		}
+		bound := strings.Fields(overrides[action])
+		for i, name := range bound {
+			if name == "space" {
+				bound[i] = " "
+			}
+		}
+		*b = key.NewBinding(key.WithKeys(bound...), key.WithHelp(keyLabel(bound), b.Help().Desc))
+	}
+	return k, nil
+}
+
+// KeyGroup is the keybindings that work in one view of the TUI
+type KeyGroup struct {
+	View     string       `json:"view"`
+	Bindings []KeyBinding `json:"bindings"`
+}
+
+// KeyBinding is an action and the keys that run it
+type KeyBinding struct {
+	Action      string   `json:"action"` // Its name in ui.keys
+	Keys        []string `json:"keys"`
+	Label       string   `json:"label"` // The keys as help shows them, such as ↑/k
+	Description string   `json:"description"`
+	Customized  bool     `json:"customized,omitempty"` // Set in ui.keys
+}
+
+// KeyGroups lists the effective keybindings by view: the defaults with the
+// keys set in ui.keys in their place
+func KeyGroups(overrides map[string]string) ([]KeyGroup, error) {
+	k, err := EffectiveKeys(overrides)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]KeyGroup, 0, len(keyViews))
+	for _, view := range keyViews {
+		group := KeyGroup{View: view.View}
+		for _, action := range view.Actions {
+			b := k.binding(action)
+			_, customized := overrides[action]
+			group.Bindings = append(group.Bindings, KeyBinding{
+				Action:      action,
+				Keys:        keyNames(b.Keys()),
+				Label:       b.Help().Key,
+				Description: b.Help().Desc,
+				Customized:  customized,
+			})
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// KeysMarkdown renders keybindings as a one-page Markdown cheat sheet
+func KeysMarkdown(groups []KeyGroup) string {
+	var b strings.Builder
+	b.WriteString("# Pocket Prompt Keybindings\n")
+	for _, group := range groups {
+		fmt.Fprintf(&b, "\n## %s\n\n| Keys | Action |\n|------|--------|\n", group.View)
+		for _, binding := range group.Bindings {
+			quoted := make([]string, len(binding.Keys))
+			for i, k := range binding.Keys {
+				quoted[i] = "`" + strings.ReplaceAll(k, "|", `\|`) + "`"
+			}
+			description := binding.Description
+			if binding.Customized {
+				description += " (customized)"
+			}
+			fmt.Fprintf(&b, "| %s | %s |\n", strings.Join(quoted, ", "), description)
+		}
+	}
+	return b.String()
+}
+
+// keyNames spells keys the way ui.keys takes them, naming the space bar
+func keyNames(keys []string) []string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k
+		if k == " " {
+			names[i] = "space"
+		}
+	}
+	return names
+}
+
+// keyLabels spells keys the way help shows them
+var keyLabels = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→",
+	" ": "Space", "enter": "Enter", "esc": "Esc", "tab": "Tab", "backspace": "Backspace",
+	"delete": "Delete", "home": "Home", "end": "End", "pgup": "PgUp", "pgdown": "PgDn",
+}
+
+// keyLabel joins keys for help, such as "ctrl+n" and "n" as "Ctrl+n/n"
+func keyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if label, ok := keyLabels[k]; ok {
+			labels[i] = label
+			continue
+		}
+		labels[i] = k
+		if strings.LastIndex(k, "+") > 0 {
+			labels[i] = strings.ToUpper(k[:1]) + k[1:]
+		}
+	}
+	return strings.Join(labels, "/")
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// KeysModal lists the effective keybindings, including any set in ui.keys,
+// grouped by the view they work in
+type KeysModal struct {
+	viewport viewport.Model
+	groups   []KeyGroup
+	isActive bool
+}
+
+// NewKeysModal creates a keybindings modal
+func NewKeysModal() *KeysModal {
+	vp := viewport.New(56, 20)
+	vp.Style = lipgloss.NewStyle()
+	return &KeysModal{viewport: vp}
+}
+
+// Show opens the modal on groups, sized for a terminal of width by height
+func (m *KeysModal) Show(groups []KeyGroup, width, height int) {
+	m.groups = groups
+	m.isActive = true
+	m.Resize(width, height)
+	m.viewport.GotoTop()
+}
+
+// Hide closes the modal
+func (m *KeysModal) Hide() {
+	m.isActive = false
+}
+
+// IsActive reports whether the modal is open
+func (m *KeysModal) IsActive() bool {
+	return m.isActive
+}
+
+// Markdown returns the keybindings shown as a Markdown cheat sheet
+func (m *KeysModal) Markdown() string {
+	return KeysMarkdown(m.groups)
+}
+
+// Resize fits the modal to a terminal of width by height
+func (m *KeysModal) Resize(width, height int) {
+	m.viewport.Width = max(20, min(60, width-4)-4)
+	m.viewport.SetContent(m.content())
+	m.viewport.Height = max(3, min(m.viewport.TotalLineCount(), height-10))
+}
+
+// Update scrolls the modal. Closing and copying are left to the model,
+// which knows the keys for them.
+func (m *KeysModal) Update(msg tea.Msg) tea.Cmd {
+	if !m.isActive {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return cmd
+}
+
+// content renders the groups one view after another
+func (m *KeysModal) content() string {
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(ColorPrimary)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	labelWidth := 0
+	for _, group := range m.groups {
+		for _, binding := range group.Bindings {
+			labelWidth = max(labelWidth, lipgloss.Width(binding.Label))
+		}
+	}
+
+	var lines []string
+	for i, group := range m.groups {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, titleStyle.Render(group.View))
+		for _, binding := range group.Bindings {
+			label := binding.Label + strings.Repeat(" ", labelWidth-lipgloss.Width(binding.Label))
+			line := "  " + keyStyle.Render(label) + "  " + binding.Description
+			if binding.Customized {
+				line += hintStyle.Render(" (customized)")
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// View renders the modal
+func (m *KeysModal) View() string {
+	if !m.isActive {
+		return ""
+	}
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(1, 2)
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	helpStyle := lipgloss.NewStyle().Italic(true).MarginTop(1)
+
+	content := []string{
+		titleStyle.Render("Keybindings"),
+		m.viewport.View(),
+		helpStyle.Render("↑/↓: scroll • c: copy as Markdown • Esc: close"),
+	}
+	return modalStyle.Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestKeyGroups(t *testing.T) {
+	groups, err := KeyGroups(map[string]string{"copy": "C", "new": "ctrl+n n"})
+	if err != nil {
+		t.Fatalf("KeyGroups: %v", err)
+	}
+
+	var found int
+	for _, group := range groups {
+		for _, binding := range group.Bindings {
+			switch binding.Action {
+			case "copy":
+				found++
+				if group.View != "Prompt detail" || strings.Join(binding.Keys, " ") != "C" || !binding.Customized {
+					t.Errorf("copy = %+v in %s, want C customized in Prompt detail", binding, group.View)
+				}
+			case "new":
+				found++
+				if binding.Label != "Ctrl+n/n" || !binding.Customized {
+					t.Errorf("new = %+v, want Ctrl+n/n customized", binding)
+				}
+			case "quit":
+				found++
+				if binding.Customized || strings.Join(binding.Keys, " ") != "q ctrl+c" {
+					t.Errorf("quit = %+v, want the default keys", binding)
+				}
+			}
+		}
+	}
+	if found != 3 {
+		t.Fatalf("Found %d of copy, new and quit in %+v", found, groups)
+	}
+
+	md := KeysMarkdown(groups)
+	for _, want := range []string{"# Pocket Prompt Keybindings", "## Library", "## Prompt detail", "| `ctrl+n`, `n` | new prompt (customized) |", "| `q`, `ctrl+c` | quit |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown is missing %q:\n%s", want, md)
+		}
+	}
+
+	if _, err := KeyGroups(map[string]string{"launch": "l"}); err == nil || !strings.Contains(err.Error(), `"launch"`) {
+		t.Errorf("Unknown action gave %v, want an error naming it", err)
+	}
+}
+
+func TestKeysModal(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	svc.Settings().UI.Keys = map[string]string{"keys": "ctrl+k", "copy": "C"}
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(loadCompleteMsg{})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	if current := m.(Model); current.keysModal != nil && current.keysModal.IsActive() {
+		t.Fatal("K opened the modal after being rebound")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	current := m.(Model)
+	if current.keysModal == nil || !current.keysModal.IsActive() {
+		t.Fatal("ctrl+k didn't open the keybindings modal")
+	}
+	view := current.keysModal.content()
+	for _, want := range []string{"Everywhere", "Prompt detail", "Ctrl+k", "(customized)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Modal is missing %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).keysModal.IsActive() {
+		t.Error("Esc didn't close the keybindings modal")
+	}
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// setPromptItems shows prompts in the list. Only the first ui.list_page_size
+// of them are loaded into it; more load as the cursor nears the end of those.
+func (m *Model) setPromptItems(prompts []*models.Prompt) {
+	m.listed = prompts
+	m.showPrompts(min(len(prompts), m.listPageSize))
+	m.loadMorePrompts()
+}
+
+// showPrompts loads the first n listed prompts into the list, along with the
+// filter for them
+func (m *Model) showPrompts(n int) {
+	shown := m.listed[:n]
+	items := make([]list.Item, n)
+	for i, p := range shown {
+		items[i] = p
+	}
+	m.promptList.Filter = promptFilter(shown, m.service.SearchTargets(shown))
+	m.promptList.SetItems(items)
+}
+
+// loadMorePrompts loads pages of listed prompts until there is more than a
+// screen of them below the cursor
+func (m *Model) loadMorePrompts() {
+	if m.promptList.FilterState() != list.Unfiltered {
+		return
+	}
+	loaded := len(m.promptList.Items())
+	want := min(m.promptList.Index()+m.promptList.Paginator.PerPage+1, len(m.listed))
+	for loaded < want {
+		loaded = min(loaded+m.listPageSize, len(m.listed))
+	}
+	if loaded > len(m.promptList.Items()) {
+		m.showPrompts(loaded)
+	}
+}
+
+// loadAllPrompts loads every listed prompt, so a filter searches all of them
+func (m *Model) loadAllPrompts() {
+	if len(m.promptList.Items()) < len(m.listed) {
+		m.showPrompts(len(m.listed))
+	}
+}
+
+// loadPromptsFor loads what a key in the library list needs before the list
+// handles it: every prompt to filter them or to go to the end, including by
+// wrapping past the top
+func (m *Model) loadPromptsFor(msg tea.KeyMsg) {
+	keys := m.promptList.KeyMap
+	if key.Matches(msg, keys.Filter, keys.GoToEnd) || (key.Matches(msg, keys.CursorUp) && m.promptList.Index() == 0) {
+		m.loadAllPrompts()
+	}
+}
+
+// selectPrompt highlights the prompt with id among those the list shows,
+// loading prompts up to it first
+func (m *Model) selectPrompt(id string) {
+	if i := slices.IndexFunc(m.listed, func(p *models.Prompt) bool { return p.ID == id }); i >= len(m.promptList.Items()) {
+		m.showPrompts(min(i+m.listPageSize, len(m.listed)))
+	}
+	for i, item := range m.promptList.VisibleItems() {
+		if p, ok := item.(*models.Prompt); ok && p.ID == id {
+			m.promptList.Select(i)
+			break
+		}
+	}
+	m.loadMorePrompts()
+}
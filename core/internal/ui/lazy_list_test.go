@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestListLoadsPromptsLazily(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for i := 0; i < 60; i++ {
+		p := &models.Prompt{ID: fmt.Sprintf("p%02d", i), Name: fmt.Sprintf("Prompt %02d", i), Content: "x"}
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	prompts, _ := svc.ListPrompts()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	model.listPageSize = 10
+	updated, _ := model.Update(loadCompleteMsg{prompts: prompts})
+	m := updated.(Model)
+
+	loaded := len(m.promptList.Items())
+	if loaded == 0 || loaded%10 != 0 || loaded >= len(prompts) {
+		t.Fatalf("Expected the first pages of prompts loaded, got %d of %d", loaded, len(prompts))
+	}
+
+	// Moving toward the end of the loaded prompts loads the next page
+	for i := 0; i < loaded; i++ {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+	}
+	if more := len(m.promptList.Items()); more <= loaded || more == len(prompts) {
+		t.Errorf("Expected another page loaded, got %d of %d", more, len(prompts))
+	}
+	if m.promptList.Index() != loaded {
+		t.Errorf("Expected the cursor to move on past the first pages, got %d", m.promptList.Index())
+	}
+
+	// Selecting a prompt further down loads up to it
+	last := m.listed[len(m.listed)-1]
+	m.selectPrompt(last.ID)
+	if p, ok := m.promptList.SelectedItem().(*models.Prompt); !ok || p.ID != last.ID {
+		t.Errorf("Expected %s selected, got %v", last.ID, m.promptList.SelectedItem())
+	}
+
+	// Filtering searches every prompt
+	m.setPromptItems(prompts)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = updated.(Model)
+	if len(m.promptList.Items()) != len(prompts) {
+		t.Errorf("Expected all %d prompts loaded to filter, got %d", len(prompts), len(m.promptList.Items()))
+	}
+}
@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+)
+
+// warnIfLocked reports whether editing a prompt should wait because someone
+// else has locked it. The first edit shows who holds the lock; pressing edit
+// again goes ahead, since locks are only advisory.
+func (m *Model) warnIfLocked(id string) bool {
+	if m.lockWarned == id {
+		m.lockWarned = ""
+		return false
+	}
+	lock := m.service.LockedByOther(id, m.service.LockOwner())
+	if lock == nil {
+		return false
+	}
+	m.lockWarned = id
+	m.statusMsg = i18n.T("status.prompt_locked", id, lock.Owner, i18n.FormatDateTime(lock.Since))
+	m.statusTimeout = 100 // Keep showing until next action
+	return true
+}
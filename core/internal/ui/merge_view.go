@@ -0,0 +1,272 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
+)
+
+// MergeView resolves the conflicts between two versions of a prompt's
+// content hunk by hunk. It shows the local version, the incoming one and the
+// merged result side by side; each conflict is resolved by picking the local
+// lines, the incoming lines or both.
+type MergeView struct {
+	title           string
+	labels          [3]string // Local, incoming and result pane titles
+	hunks           []diff.Hunk
+	picks           []diff.Pick // Indexed like hunks
+	conflicts       []int       // Indexes of the conflicting hunks
+	current         int         // Index into conflicts of the highlighted conflict
+	offset          int         // First row shown
+	message         string
+	done, confirmed bool
+	embedded        bool // Shown inside another program, which closes it once done
+	width, height   int
+}
+
+// NewMergeView creates a merge view for local and incoming content, with the
+// panes titled by localLabel and incomingLabel
+func NewMergeView(title, localLabel, incomingLabel, local, incoming string) *MergeView {
+	hunks := diff.Hunks(local, incoming)
+	v := &MergeView{
+		title:  title,
+		labels: [3]string{localLabel, incomingLabel, "Result"},
+		hunks:  hunks,
+		picks:  make([]diff.Pick, len(hunks)),
+		width:  80,
+		height: 24,
+	}
+	for i, h := range hunks {
+		if h.Conflict {
+			v.conflicts = append(v.conflicts, i)
+		}
+	}
+	return v
+}
+
+// RunMergeView shows a merge view full screen and returns the merged
+// content. ok is false when the user cancels.
+func RunMergeView(title, localLabel, incomingLabel, local, incoming string) (merged string, ok bool, err error) {
+	final, err := tea.NewProgram(NewMergeView(title, localLabel, incomingLabel, local, incoming), tea.WithAltScreen()).Run()
+	if err != nil {
+		return "", false, err
+	}
+	v := final.(*MergeView)
+	if !v.confirmed {
+		return "", false, nil
+	}
+	return v.Result(), true, nil
+}
+
+// Result returns the merged content with the conflicts resolved so far
+func (v *MergeView) Result() string {
+	return diff.Merge(v.hunks, v.picks)
+}
+
+// unresolved counts the conflicts without a pick
+func (v *MergeView) unresolved() int {
+	count := 0
+	for _, i := range v.conflicts {
+		if v.picks[i] == diff.PickNone {
+			count++
+		}
+	}
+	return count
+}
+
+// Init implements tea.Model
+func (v *MergeView) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (v *MergeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.width, v.height = msg.Width, msg.Height
+
+	case tea.KeyMsg:
+		v.message = ""
+		switch msg.String() {
+		case "left", "h", "1":
+			v.pick(diff.PickLocal)
+		case "right", "l", "2":
+			v.pick(diff.PickIncoming)
+		case "b", "3":
+			v.pick(diff.PickBoth)
+		case "u", "backspace":
+			if len(v.conflicts) > 0 {
+				v.picks[v.conflicts[v.current]] = diff.PickNone
+			}
+		case "n", "tab":
+			v.moveTo(v.current + 1)
+		case "p", "shift+tab":
+			v.moveTo(v.current - 1)
+		case "down", "j":
+			v.offset++
+		case "up", "k":
+			v.offset = max(0, v.offset-1)
+		case "pgdown", "ctrl+d":
+			v.offset += v.bodyHeight() / 2
+		case "pgup", "ctrl+u":
+			v.offset = max(0, v.offset-v.bodyHeight()/2)
+		case "enter":
+			if n := v.unresolved(); n > 0 {
+				v.message = fmt.Sprintf("%d of %d conflicts still need a pick", n, len(v.conflicts))
+				return v, nil
+			}
+			v.done, v.confirmed = true, true
+			return v, v.finish()
+		case "esc", "q", "ctrl+c":
+			v.done = true
+			return v, v.finish()
+		}
+	}
+	return v, nil
+}
+
+// finish quits the program unless the view is embedded in another one
+func (v *MergeView) finish() tea.Cmd {
+	if v.embedded {
+		return nil
+	}
+	return tea.Quit
+}
+
+// pick resolves the highlighted conflict and moves on to the next one
+// without a pick
+func (v *MergeView) pick(pick diff.Pick) {
+	if len(v.conflicts) == 0 {
+		return
+	}
+	v.picks[v.conflicts[v.current]] = pick
+	for i := 1; i < len(v.conflicts); i++ {
+		next := (v.current + i) % len(v.conflicts)
+		if v.picks[v.conflicts[next]] == diff.PickNone {
+			v.moveTo(next)
+			return
+		}
+	}
+}
+
+// moveTo highlights a conflict and scrolls it into view
+func (v *MergeView) moveTo(conflict int) {
+	if conflict < 0 || conflict >= len(v.conflicts) {
+		return
+	}
+	v.current = conflict
+	_, starts := v.rows(0)
+	v.offset = max(0, starts[v.conflicts[conflict]]-v.bodyHeight()/3)
+}
+
+// bodyHeight is how many rows of the panes fit under the header and titles
+func (v *MergeView) bodyHeight() int {
+	return max(3, v.height-7)
+}
+
+// paneWidth is the width of each of the three panes
+func (v *MergeView) paneWidth() int {
+	return max(12, (v.width-6)/3)
+}
+
+// rows lays the hunks out as rows of the three panes, padding each hunk to
+// the height of its tallest pane so the panes line up, and returns the row
+// each hunk starts on. Cells are rendered width wide; width 0 only counts.
+func (v *MergeView) rows(width int) (rows [][3]string, starts []int) {
+	starts = make([]int, len(v.hunks))
+	for i, h := range v.hunks {
+		starts[i] = len(rows)
+		pick := v.picks[i]
+		result := h.Resolve(pick)
+		if h.Conflict && pick == diff.PickNone {
+			result = []string{"(pick a side)"}
+		}
+		height := max(len(h.Local), len(h.Incoming), len(result))
+		if width == 0 {
+			rows = append(rows, make([][3]string, height)...)
+			continue
+		}
+
+		current := len(v.conflicts) > 0 && v.conflicts[v.current] == i
+		localStyle, incomingStyle, resultStyle := StyleText, StyleText, StyleText
+		if h.Conflict {
+			localStyle = v.sideStyle(pick, diff.PickLocal)
+			incomingStyle = v.sideStyle(pick, diff.PickIncoming)
+			resultStyle = lipgloss.NewStyle().Foreground(ColorSuccess)
+			if pick == diff.PickNone {
+				resultStyle = lipgloss.NewStyle().Foreground(ColorWarning).Italic(true)
+			}
+		}
+		for row := 0; row < height; row++ {
+			gutter := "  "
+			if current {
+				gutter = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true).Render("› ")
+			}
+			rows = append(rows, [3]string{
+				gutter + cell(h.Local, row, width-2, localStyle),
+				gutter + cell(h.Incoming, row, width-2, incomingStyle),
+				gutter + cell(result, row, width-2, resultStyle),
+			})
+		}
+	}
+	return rows, starts
+}
+
+// sideStyle colors one side of a conflict: warning until a side is picked,
+// then success when this side is in the result and dim when it isn't
+func (v *MergeView) sideStyle(pick, side diff.Pick) lipgloss.Style {
+	switch {
+	case pick == diff.PickNone:
+		return lipgloss.NewStyle().Foreground(ColorWarning)
+	case pick == side || pick == diff.PickBoth:
+		return lipgloss.NewStyle().Foreground(ColorSuccess)
+	default:
+		return StyleTextDim
+	}
+}
+
+// cell renders one line of a pane, padded or truncated to width
+func cell(lines []string, row, width int, style lipgloss.Style) string {
+	text := ""
+	if row < len(lines) {
+		text = truncate(lines[row], width)
+	}
+	padding := strings.Repeat(" ", max(0, width-lipgloss.Width(text)))
+	return style.Render(text) + padding
+}
+
+// View implements tea.Model
+func (v *MergeView) View() string {
+	header := CreateSubPageHeader(fmt.Sprintf("Merge %s — %d of %d conflicts resolved",
+		v.title, len(v.conflicts)-v.unresolved(), len(v.conflicts)))
+
+	width := v.paneWidth()
+	separator := StyleTextDim.Render(" │ ")
+	var titles []string
+	for _, label := range v.labels {
+		titles = append(titles, StyleText.Bold(true).Render(truncate(label, width))+strings.Repeat(" ", max(0, width-lipgloss.Width(truncate(label, width)))))
+	}
+
+	rows, _ := v.rows(width)
+	height := v.bodyHeight()
+	v.offset = min(v.offset, max(0, len(rows)-height))
+	var body []string
+	for _, row := range rows[v.offset:min(len(rows), v.offset+height)] {
+		body = append(body, row[0]+separator+row[1]+separator+row[2])
+	}
+
+	status := ""
+	switch {
+	case v.message != "":
+		status = StyleWarning.Render(v.message)
+	case len(v.conflicts) == 0:
+		status = StyleTextMuted.Render("The two versions have the same content")
+	}
+	help := CreateHelp("←/1 local • →/2 incoming • b both • u undo • n/p next/prev conflict • ↑/↓ scroll • Enter save • Esc cancel")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header, "", strings.Join(titles, separator), strings.Join(body, "\n"), "", status, help)
+}
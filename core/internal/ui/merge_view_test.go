@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func mergeKey(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestMergeView_PicksEachConflict(t *testing.T) {
+	v := NewMergeView("notes", "Library", "Import",
+		"intro\nold rule\nmiddle\nold end", "intro\nnew rule\nmiddle\nnew end")
+	if len(v.conflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts, got %d", len(v.conflicts))
+	}
+
+	// Saving waits until every conflict has a pick
+	v.Update(mergeKey("enter"))
+	if v.done || v.message == "" {
+		t.Fatal("Expected saving with unresolved conflicts to be refused")
+	}
+
+	// A pick moves on to the next conflict
+	v.Update(mergeKey("2"))
+	v.Update(mergeKey("b"))
+	if got, want := v.Result(), "intro\nnew rule\nmiddle\nold end\nnew end"; got != want {
+		t.Errorf("Result = %q, want %q", got, want)
+	}
+
+	_, cmd := v.Update(mergeKey("enter"))
+	if !v.done || !v.confirmed || cmd == nil {
+		t.Error("Expected enter to save once every conflict is resolved")
+	}
+	if view := v.View(); view == "" {
+		t.Error("Expected the view to render")
+	}
+}
+
+func TestImportPicker_Merge(t *testing.T) {
+	candidate := service.ImportCandidate{
+		Key: "prompt:notes", Kind: "prompt", ID: "notes", Status: service.CandidateChanged,
+		Local: "keep\nlocal", Incoming: "keep\nincoming",
+	}
+	p := NewImportPicker([]service.ImportCandidate{candidate})
+
+	p.Update(mergeKey("m"))
+	if p.merge == nil {
+		t.Fatal("Expected m to open the merge view")
+	}
+	p.Update(mergeKey("1"))
+	if _, cmd := p.Update(mergeKey("enter")); cmd != nil {
+		t.Error("Expected the embedded merge view not to quit the picker")
+	}
+	if p.merge != nil {
+		t.Fatal("Expected the merge view to close after saving")
+	}
+	if got := p.merged["prompt:notes"]; got != "keep\nlocal" {
+		t.Errorf("merged content = %q, want the local version", got)
+	}
+	if !p.selected["prompt:notes"] {
+		t.Error("Expected the merged prompt to be selected")
+	}
+}
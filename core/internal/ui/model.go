@@ -1,32 +1,60 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 	"github.com/dpshade/pocket-prompt/internal/clipboard"
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/federation"
+	"github.com/dpshade/pocket-prompt/internal/fuzzy"
+	helptext "github.com/dpshade/pocket-prompt/internal/help"
+	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/lint"
 	"github.com/dpshade/pocket-prompt/internal/models"
 	"github.com/dpshade/pocket-prompt/internal/renderer"
 	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/spellcheck"
+	"github.com/dpshade/pocket-prompt/internal/startup"
+	"github.com/dpshade/pocket-prompt/internal/storage"
 )
 
-// createGlamourRenderer creates a glamour renderer with improved contrast handling
+var (
+	glamourStyleOnce    sync.Once
+	glamourStyleOptions []glamour.TermRendererOption
+)
+
+// createGlamourRenderer creates a glamour renderer with improved contrast handling.
+// The style is chosen on first use, so later renderers, including those made
+// off the UI loop for long previews, never query the terminal.
 func createGlamourRenderer(wordWrap int) (*glamour.TermRenderer, error) {
-	// Check for environment variable override first
-	if style := os.Getenv("GLAMOUR_STYLE"); style != "" {
-		return glamour.NewTermRenderer(
-			glamour.WithStandardStyle(style),
-			glamour.WithWordWrap(wordWrap),
-		)
+	glamourStyleOnce.Do(func() { glamourStyleOptions = detectGlamourStyle() })
+	options := append(slices.Clone(glamourStyleOptions), glamour.WithWordWrap(wordWrap))
+	return glamour.NewTermRenderer(options...)
+}
+
+// detectGlamourStyle picks the glamour style for the theme or the terminal
+func detectGlamourStyle() []glamour.TermRendererOption {
+	// Check for a theme or environment variable override first
+	if style := forcedStyle(); style != "" {
+		return []glamour.TermRendererOption{glamour.WithStandardStyle(style)}
 	}
 
 	// Detect terminal capabilities and background
@@ -60,11 +88,7 @@ func createGlamourRenderer(wordWrap int) (*glamour.TermRenderer, error) {
 		}
 	}
 
-	return glamour.NewTermRenderer(
-		styleOption,
-		glamour.WithColorProfile(profile),
-		glamour.WithWordWrap(wordWrap),
-	)
+	return []glamour.TermRendererOption{styleOption, glamour.WithColorProfile(profile)}
 }
 
 // Commands for async operations
@@ -74,6 +98,25 @@ type loadCompleteMsg struct {
 	err       error
 }
 
+// sourceLoadedMsg carries the prompts of a source picked with the source switcher
+type sourceLoadedMsg struct {
+	source  string
+	prompts []*models.Prompt
+	errs    []error
+}
+
+// ServerListeningMsg reports the address the URL server running alongside
+// the TUI listens on, once it does
+type ServerListeningMsg struct {
+	Addr string
+}
+
+// ServerStoppedMsg reports that the URL server running alongside the TUI
+// stopped on its own, such as when its port is taken
+type ServerStoppedMsg struct {
+	Err error
+}
+
 type gitSyncStatusMsg struct {
 	status string
 	err    error
@@ -89,7 +132,9 @@ func loadPromptsCmd(svc *service.Service) tea.Cmd {
 		}
 		
 		// Load templates (usually few files)
+		endTemplates := startup.Begin("template load")
 		templates, templateErr := svc.ListTemplates()
+		endTemplates()
 		if templateErr != nil {
 			templates = []*models.Template{}
 		}
@@ -111,6 +156,18 @@ func loadPromptsCmd(svc *service.Service) tea.Cmd {
 }
 
 
+// loadSourceCmd lists the prompts of the named source, or of every source for "all"
+func loadSourceCmd(fed *federation.Federation, source string) tea.Cmd {
+	return func() tea.Msg {
+		names, err := fed.Resolve([]string{source})
+		if err != nil {
+			return sourceLoadedMsg{source: source, errs: []error{err}}
+		}
+		prompts, errs := fed.List(context.Background(), names)
+		return sourceLoadedMsg{source: source, prompts: prompts, errs: errs}
+	}
+}
+
 // gitSyncStatusCmd gets the current git sync status (disabled for performance)
 func gitSyncStatusCmd(svc *service.Service) tea.Cmd {
 	return func() tea.Msg {
@@ -137,11 +194,13 @@ const (
 	ViewTemplateDetail
 	ViewTemplateManagement
 	ViewSavedSearches
+	ViewTemplateGallery
 )
 
 // Model represents the TUI application state
 type Model struct {
 	service  *service.Service
+	executor *commands.CommandExecutor // Runs template operations the way the CLI and API do
 	viewMode ViewMode
 
 	// UI components
@@ -152,7 +211,10 @@ type Model struct {
 
 	// Data
 	prompts        []*models.Prompt
+	listed         []*models.Prompt // Prompts the list shows; its items are the first of them loaded so far
+	listPageSize   int // How many listed prompts load into the list at a time
 	templates      []*models.Template
+	templateStats  map[string]service.TemplateStats // Usage by template ID, read when template management opens
 	loading        bool
 	selectedPrompt *models.Prompt
 	selectedTemplate *models.Template
@@ -164,11 +226,21 @@ type Model struct {
 	selectForm     *SelectForm
 	editMode       bool
 	deleteConfirm  bool
+	lockWarned     string // Prompt whose lock was just shown; editing it again goes ahead
 
 	// Rendered content
 	renderedContent     string
 	renderedContentJSON string
 	glamourRenderer     *glamour.TermRenderer
+	glamourWidth        int // Word wrap of glamourRenderer
+	renderers           map[int]*glamour.TermRenderer // Glamour renderers made so far, by width
+	resizeSeq           int // Latest window resize; earlier ones no longer re-render
+	preview             *previewDoc // Markdown in the detail view, rendered per width
+	detailTab           detailTab // Pane of the detail view shown
+	currentProfile      string // Variable profile filling placeholders in the preview, if any
+	usingSavedAnswers   bool   // Values last given for the previewed prompt fill some of its placeholders
+	section             int    // Section of the previewed prompt that c copies, from 1; 0 for the whole prompt
+	contentStats        service.TextStats // Length of the previewed prompt as copied
 
 	// Window dimensions
 	width  int
@@ -186,20 +258,54 @@ type Model struct {
 	showHelpModal  bool
 	showExpandedHelp bool // Whether to show expanded help in current view
 	helpViewport   viewport.Model // Viewport for scrollable help modal
+	helpSearch     textinput.Model // Filters the help modal, typed after /
+	helpSearching  bool            // Whether keys go to helpSearch
 	modalContent   string // Plain text content for copying
 	
 	// Git sync state
-	gitSyncStatus string
+	gitSyncStatus   string
+	gitSyncInterval time.Duration // Between pulls of git.tui_interval, 0 if off
+
+	// Prompt editor checks
+	spellChecker   *spellcheck.Checker // Nil when ui.spellcheck is off
+	linter         *lint.Linter        // The library's lint rules, for style hints
+	editorCheckSeq int                 // Latest change to the editor's content; earlier checks no longer run
+	gitSyncErr      string        // Last pull failure shown, so it is shown once
 
 	// Boolean search state
 	booleanSearchModal *BooleanSearchModal
 	currentExpression  *models.BooleanExpression
 	savedSearches      []models.SavedSearch
 	saveSearchModal    *SaveSearchModal
+
+	// Quick tag editing on the library list
+	quickTagModal *QuickTagModal
+	keysModal     *KeysModal
+	movePackModal *MovePackModal
+	templateGallery *TemplateGallery
+	marked        map[string]bool // IDs of the prompts marked in the library for a bulk action
 	
 	// Pack selection state
 	packSelectorModal  *PackSelectorModal
 	selectedPacks      []string
+
+	// Federated sources: local, each registered source, then all of them merged
+	federation    *federation.Federation
+	currentSource string
+
+	// Last session being restored while its list filter is applied
+	restoring *storage.SessionState
+
+	// URL server running alongside the TUI, if any
+	serverStatus string
+
+	// Soft limit the library is over or close to, if any
+	quotaStatus string
+
+	// New matches of subscribed saved searches, if any, and their count by
+	// search name
+	subscriptionStatus string
+	newMatches         map[string]int
 }
 
 // KeyMap defines all key bindings
@@ -212,6 +318,7 @@ type KeyMap struct {
 	Back   key.Binding
 	Quit   key.Binding
 	Help   key.Binding
+	Keys   key.Binding
 	ExpandHelp key.Binding
 	Search key.Binding
 	Copy     key.Binding
@@ -221,10 +328,25 @@ type KeyMap struct {
 	Edit     key.Binding
 	Delete   key.Binding
 	Templates key.Binding
+	TemplateGallery key.Binding
 	GHSyncInfo key.Binding
 	BooleanSearch key.Binding
 	SavedSearches key.Binding
+	PinSearch     key.Binding
+	SubscribeSearch key.Binding
 	PackSelector  key.Binding
+	SourceSwitch  key.Binding
+	DetailTab     key.Binding
+	History       key.Binding
+	Raw           key.Binding
+	Reveal        key.Binding
+	Profile       key.Binding
+	NextSection   key.Binding
+	PrevSection   key.Binding
+	AddTag        key.Binding
+	RemoveTag     key.Binding
+	Mark          key.Binding
+	MovePack      key.Binding
 }
 
 // ShortHelp returns keybindings to show in the mini help view
@@ -237,10 +359,12 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Enter, k.Back, k.Search, k.New},
-		{k.Edit, k.Delete, k.Templates, k.Copy},
-		{k.CopyJSON, k.Export, k.BooleanSearch, k.SavedSearches},
-		{k.PackSelector},
-		{k.Help, k.Quit},
+		{k.Edit, k.Delete, k.Templates, k.TemplateGallery, k.Copy},
+		{k.CopyJSON, k.Export, k.BooleanSearch, k.SavedSearches, k.PinSearch, k.SubscribeSearch},
+		{k.PackSelector, k.SourceSwitch, k.DetailTab, k.History, k.Raw, k.Reveal, k.Profile},
+		{k.NextSection, k.PrevSection},
+		{k.AddTag, k.RemoveTag, k.Mark, k.MovePack},
+		{k.Help, k.Keys, k.Quit},
 	}
 }
 
@@ -277,6 +401,10 @@ var keys = KeyMap{
 		key.WithKeys("?"),
 		key.WithHelp("?", "help"),
 	),
+	Keys: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "keybindings"),
+	),
 	ExpandHelp: key.NewBinding(
 		key.WithKeys("ctrl+g"),
 		key.WithHelp("Ctrl+g", "expand help"),
@@ -313,6 +441,10 @@ var keys = KeyMap{
 		key.WithKeys("t"),
 		key.WithHelp("t", "templates"),
 	),
+	TemplateGallery: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "template gallery"),
+	),
 	GHSyncInfo: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("Shift+?", "GitHub sync info"),
@@ -325,17 +457,86 @@ var keys = KeyMap{
 		key.WithKeys("f"),
 		key.WithHelp("f", "saved searches"),
 	),
+	PinSearch: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "pin saved search"),
+	),
+	SubscribeSearch: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "subscribe to saved search"),
+	),
 	PackSelector: key.NewBinding(
 		key.WithKeys("p"),
 		key.WithHelp("p", "select packs"),
 	),
+	SourceSwitch: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "switch source"),
+	),
+	DetailTab: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4"),
+		key.WithHelp("1-4", "detail tabs"),
+	),
+	History: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "version history"),
+	),
+	Raw: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "raw file"),
+	),
+	Reveal: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "show in file manager"),
+	),
+	Profile: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "variable profile"),
+	),
+	NextSection: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next section"),
+	),
+	PrevSection: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "previous section"),
+	),
+	AddTag: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+", "add tag"),
+	),
+	RemoveTag: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "remove tag"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("Space", "mark prompt"),
+	),
+	MovePack: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "move to pack"),
+	),
 }
 
 // NewModel creates a new TUI model
 func NewModel(svc *service.Service) (*Model, error) {
-	// Initialize adaptive colors based on terminal background
-	initializeColors()
+	// Initialize adaptive colors based on the theme or terminal background
+	initializeColors(svc.Settings().UI.Theme)
 	
+	// Apply any keys rebound in ui.keys
+	km, err := EffectiveKeys(svc.Settings().UI.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// A spell checker that is set but not installed falls back to the built-in list
+	spellChecker, err := spellcheck.New(svc.Settings().UI.SpellCheck)
+	if err != nil {
+		spellChecker, _ = spellcheck.New(spellcheck.EngineBuiltin)
+	}
+	linter, _ := lint.New(svc.Settings().Lint)
+
 	// Start with empty data for immediate UI responsiveness
 	// Data will be loaded asynchronously
 	prompts := []*models.Prompt{}
@@ -348,18 +549,17 @@ func NewModel(svc *service.Service) (*Model, error) {
 	}
 
 	// Create list with loading placeholder
-	l := list.New(items, list.NewDefaultDelegate(), 80, 20) // Default size, will be updated on first WindowSizeMsg
+	marked := map[string]bool{}
+	l := list.New(items, markDelegate{list.NewDefaultDelegate(), marked}, 80, 20) // Default size, will be updated on first WindowSizeMsg
 	l.Title = ""  // We'll handle title in the view
 	l.SetShowStatusBar(false) // We'll handle status in our custom view
 	l.SetFilteringEnabled(true) // Enable filtering from start
+	l.Filter = promptFilter(prompts, svc.SearchTargets(prompts))
 	l.SetShowHelp(false) // We'll handle help text ourselves
 	
 	// Set up the list's key map to use our preferred keys
 	keyMap := list.DefaultKeyMap()
-	keyMap.Filter = key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "filter"),
-	)
+	keyMap.Filter = km.Search
 	l.KeyMap = keyMap
 
 	// Create viewport for preview
@@ -370,6 +570,10 @@ func NewModel(svc *service.Service) (*Model, error) {
 	// Create viewport for help modal
 	helpVp := viewport.New(56, 23) // Smaller size for help modal
 	helpVp.Style = lipgloss.NewStyle()
+	helpSearch := textinput.New()
+	helpSearch.Prompt = "/ "
+	helpSearch.Placeholder = i18n.T("help.search_placeholder")
+	helpSearch.CharLimit = 60
 
 	// Create glamour renderer for markdown with improved contrast
 	// Start with a conservative default width for better wrapping
@@ -380,25 +584,64 @@ func NewModel(svc *service.Service) (*Model, error) {
 
 	return &Model{
 		service:         svc,
+		executor:        commands.NewCommandExecutor(svc),
 		viewMode:        ViewLibrary,
 		promptList:      l,
 		viewport:        vp,
 		helpViewport:    helpVp,
+		helpSearch:      helpSearch,
 		help:            help.New(),
-		keys:            keys,
+		keys:            km,
+		marked:          marked,
 		prompts:         prompts,
+		listPageSize:    svc.Settings().UI.ListPage(),
 		templates:       templates,
 		loading:         true, // Start in loading state
 		glamourRenderer: renderer,
+		glamourWidth:    60,
+		renderers:       map[int]*glamour.TermRenderer{60: renderer},
 		selectedPacks:   []string{"personal"}, // Default to personal pack
+		federation:      federation.New(svc, svc.Settings().Sources),
+		currentSource:   config.LocalSourceName,
+		gitSyncInterval: svc.Settings().Git.TUISyncInterval(),
+		spellChecker:    spellChecker,
+		linter:          linter,
 	}, nil
 }
 
+// OpenPrompt starts the TUI on the detail view of a prompt, as used by deep links
+func (m *Model) OpenPrompt(id string) error {
+	prompt, err := m.service.GetPrompt(id)
+	if err != nil {
+		return err
+	}
+	m.selectedPrompt = prompt
+	m.viewMode = ViewPromptDetail
+	return nil
+}
+
+// SetServer shows that the URL server is running alongside the TUI at addr
+func (m *Model) SetServer(addr string) {
+	m.serverStatus = i18n.T("status.server_listening", addr)
+}
+
+// isForeign reports whether p came from a registered source rather than this library
+func isForeign(p *models.Prompt) bool {
+	return p.Source != "" && p.Source != config.LocalSourceName
+}
+
+// readOnlySource explains why a prompt from another source cannot be edited
+func (m Model) readOnlySource(p *models.Prompt) (tea.Model, tea.Cmd) {
+	m.statusMsg = i18n.T("status.source_read_only", p.ID, p.Source)
+	m.statusTimeout = 3
+	return m, clearStatusCmd()
+}
+
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	// Simple approach: just load data synchronously (cache should make it fast)
-	// Skip git entirely for startup
-	return loadPromptsCmd(m.service)
+	// Skip git entirely for startup; scheduled pulls wait a full interval
+	return tea.Batch(loadPromptsCmd(m.service), m.gitSyncTickCmd(), quotaCheckCmd(m.service), subscriptionsCheckCmd(m.service))
 }
 
 // tickMsg is sent to clear the status message
@@ -411,8 +654,19 @@ func clearStatusCmd() tea.Cmd {
 	})
 }
 
-// Update handles messages and updates the model
+// Update handles messages and updates the model, then renders any chunks of
+// a long preview that have come into view
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	if model, ok := updated.(Model); ok {
+		if render := model.previewRenderCmd(); render != nil {
+			return model, tea.Batch(cmd, render)
+		}
+	}
+	return updated, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -432,16 +686,65 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.templates = msg.templates
 		
 		// Update prompt list with loaded data
-		items := make([]list.Item, len(m.prompts))
-		for i, p := range m.prompts {
-			items[i] = p
+		m.setPromptItems(m.prompts)
+		if name := m.service.PinnedSearch(); name != "" {
+			m.applyPinnedSearch(name)
+			cmds = append(cmds, clearStatusCmd())
 		}
-		m.promptList.SetItems(items)
+		cmds = append(cmds, m.restoreSession())
+		startup.Mark("TUI ready")
 		
 		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Warning: %v", msg.err)
+			m.statusMsg = i18n.T("status.warning", msg.err)
 			m.statusTimeout = 100 // Show for ~5 seconds
 		}
+	case list.FilterMatchesMsg:
+		if m.restoring != nil {
+			// The restored filter's matches: accept the filter as Enter would
+			m.promptList, _ = m.promptList.Update(msg)
+			m.promptList, _ = m.promptList.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			state := m.restoring
+			m.restoring = nil
+			return m, m.restoreSelection(state)
+		}
+	case previewChunksMsg:
+		m.applyPreviewChunks(msg)
+		return m, nil
+	case booleanSearchDebounceMsg, booleanSearchResultMsg:
+		// Live search in the boolean search modal, run off the UI loop
+		if m.booleanSearchModal != nil {
+			return m, m.booleanSearchModal.Update(msg)
+		}
+		return m, nil
+	case sourceLoadedMsg:
+		if msg.source != m.currentSource {
+			break // Superseded by a later switch
+		}
+		m.loading = false
+		m.prompts = msg.prompts
+		m.setPromptItems(m.prompts)
+
+		m.statusMsg = i18n.T("status.viewing_source", msg.source, len(msg.prompts))
+		if len(msg.errs) > 0 {
+			m.statusMsg = i18n.T("status.viewing_source_errors", msg.source, msg.errs[0])
+		}
+		m.statusTimeout = 3
+		return m, clearStatusCmd()
+	case ServerListeningMsg:
+		m.serverStatus = i18n.T("status.server_listening", msg.Addr)
+	case ServerStoppedMsg:
+		m.serverStatus = i18n.T("status.server_stopped", msg.Err)
+		m.statusMsg = i18n.T("status.server_failed", msg.Err)
+		m.statusTimeout = 5
+		return m, clearStatusCmd()
+	case gitSyncTickMsg:
+		return m, gitPullCmd(m.service)
+	case gitPulledMsg:
+		return m.handleGitPulled(msg)
+	case quotaCheckedMsg:
+		return m.handleQuotaChecked(msg)
+	case subscriptionsCheckedMsg:
+		return m.handleSubscriptionsChecked(msg)
 	case gitSyncStatusMsg:
 		// Update git sync status (skip to avoid any blocking)
 		m.gitSyncStatus = "Git sync disabled for startup performance"
@@ -470,13 +773,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				viewportWidth = 40 // Minimum readable width
 			}
 			m.viewport.Width = viewportWidth
-			m.viewport.Height = availableHeight + 1 // Reserve space for scroll indicators
-			// Also update the glamour renderer immediately with the new width
-			if viewportWidth > 0 {
-				if renderer, err := createGlamourRenderer(viewportWidth); err == nil {
-					m.glamourRenderer = renderer
-				}
-			}
+			m.viewport.Height = availableHeight // Reserve space for scroll indicators and the tab row
+			// Re-render at the new width once the size stops changing
+			cmds = append(cmds, m.scheduleResize())
 		case ViewCreateFromScratch, ViewCreateFromTemplate, ViewEditPrompt:
 			if m.createForm != nil {
 				m.createForm.Resize(msg.Width, availableHeight)
@@ -497,6 +796,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.packSelectorModal != nil {
 			m.packSelectorModal.SetSize(msg.Width, msg.Height)
 		}
+		if m.keysModal != nil {
+			m.keysModal.Resize(msg.Width, msg.Height)
+		}
+		if m.templateGallery != nil {
+			m.templateGallery.Resize(msg.Width, msg.Height)
+		}
 		
 		// Update help modal viewport size
 		helpWidth := min(60, msg.Width-4)
@@ -504,10 +809,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.helpViewport.Width = helpWidth - 4  // Account for modal padding and border
 		m.helpViewport.Height = helpHeight - 4 // Account for modal padding and border
 
-		// Re-render content if we're in prompt detail view and have content
-		if m.viewMode == ViewPromptDetail && m.selectedPrompt != nil {
-			m.renderPreview()
-		}
+	case resizeSettledMsg:
+		m.settleResize(msg)
+		return m, nil
+
+	case editorCheckMsg:
+		return m, m.checkEditorCmd(msg)
+
+	case editorCheckedMsg:
+		m.applyEditorCheck(msg)
+		return m, nil
 
 	case tea.KeyMsg:
 		// Handle pack selector modal first (highest priority)
@@ -522,18 +833,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				
 				// Update prompt list based on selected packs
 				if err := m.refreshPromptListByPacks(); err != nil {
-					m.statusMsg = fmt.Sprintf("Failed to refresh list: %v", err)
+					m.statusMsg = i18n.T("status.refresh_failed", err)
 					m.statusTimeout = 3
 					return m, clearStatusCmd()
 				}
 				
 				// Show status message
 				if len(m.selectedPacks) == 1 && m.selectedPacks[0] == "personal" {
-					m.statusMsg = "Viewing personal library"
+					m.statusMsg = i18n.T("status.viewing_personal")
 				} else if len(m.selectedPacks) == 1 {
-					m.statusMsg = fmt.Sprintf("Viewing %s pack", m.selectedPacks[0])
+					m.statusMsg = i18n.T("status.viewing_pack", m.selectedPacks[0])
 				} else {
-					m.statusMsg = fmt.Sprintf("Viewing %d selected packs", len(m.selectedPacks))
+					m.statusMsg = i18n.T("status.viewing_packs", len(m.selectedPacks))
 				}
 				m.statusTimeout = 2
 				return m, clearStatusCmd()
@@ -547,6 +858,71 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Handle quick tag modal
+		if m.quickTagModal != nil && m.quickTagModal.IsActive() {
+			cmd := m.quickTagModal.Update(msg)
+			if m.quickTagModal.IsSubmitted() {
+				m.quickTagModal.Hide()
+				return m, m.applyQuickTag()
+			}
+			return m, cmd
+		}
+
+		// Handle move to pack modal
+		if m.movePackModal != nil && m.movePackModal.IsActive() {
+			cmd := m.movePackModal.Update(msg)
+			if m.movePackModal.IsSubmitted() {
+				m.movePackModal.Hide()
+				return m, m.applyMovePack()
+			}
+			return m, cmd
+		}
+
+		// Handle keybindings modal
+		if m.keysModal != nil && m.keysModal.IsActive() {
+			switch {
+			case msg.String() == "esc", key.Matches(msg, m.keys.Keys):
+				m.keysModal.Hide()
+				return m, nil
+			case msg.String() == "c":
+				if statusMsg, err := clipboard.CopyWithFallback(m.keysModal.Markdown()); err != nil {
+					m.statusMsg = i18n.T("status.copy_failed", err)
+					m.statusTimeout = 3
+				} else {
+					m.statusMsg = statusMsg
+					m.statusTimeout = 2
+				}
+				return m, clearStatusCmd()
+			}
+			return m, m.keysModal.Update(msg)
+		}
+
+		// Handle the template gallery, where left and right browse rather than go back
+		if m.viewMode == ViewTemplateGallery && m.templateGallery != nil {
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m.viewMode = ViewLibrary
+				m.templateGallery = nil
+				return m, nil
+			case key.Matches(msg, m.keys.ExpandHelp):
+				m.showExpandedHelp = !m.showExpandedHelp
+				return m, nil
+			case key.Matches(msg, m.keys.Copy):
+				if entry := m.templateGallery.Selected(); entry != nil && entry.Error == "" {
+					if statusMsg, err := clipboard.CopyWithFallback(entry.Example); err != nil {
+						m.statusMsg = i18n.T("status.copy_failed", err)
+						m.statusTimeout = 3
+					} else {
+						m.statusMsg = statusMsg
+						m.statusTimeout = 2
+					}
+					return m, clearStatusCmd()
+				}
+				return m, nil
+			}
+			return m, m.templateGallery.Update(msg)
+		}
+
 		// Handle save search modal
 		if m.saveSearchModal != nil && m.saveSearchModal.IsActive() {
 			cmd := m.saveSearchModal.Update(msg)
@@ -559,7 +935,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						original := m.saveSearchModal.GetOriginalSearch()
 						if original != nil {
 							if err := m.service.DeleteSavedSearch(original.Name); err != nil {
-								m.statusMsg = fmt.Sprintf("Failed to delete original search: %v", err)
+								m.statusMsg = i18n.T("status.search_delete_original_failed", err)
 								m.statusTimeout = 3
 								m.saveSearchModal.SetActive(false)
 								m.saveSearchModal.ClearEditMode()
@@ -567,19 +943,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							}
 						}
 						if err := m.service.SaveBooleanSearch(*savedSearch); err != nil {
-							m.statusMsg = fmt.Sprintf("Failed to save updated search: %v", err)
+							m.statusMsg = i18n.T("status.search_update_failed", err)
 							m.statusTimeout = 3
 						} else {
-							m.statusMsg = fmt.Sprintf("Search '%s' updated successfully!", savedSearch.Name)
+							m.statusMsg = i18n.T("status.search_updated", savedSearch.Name)
 							m.statusTimeout = 3
 						}
 					} else {
 						// Regular save
 						if err := m.service.SaveBooleanSearch(*savedSearch); err != nil {
-							m.statusMsg = fmt.Sprintf("Failed to save search: %v", err)
+							m.statusMsg = i18n.T("status.search_save_failed", err)
 							m.statusTimeout = 3
 						} else {
-							m.statusMsg = fmt.Sprintf("Search '%s' saved successfully!", savedSearch.Name)
+							m.statusMsg = i18n.T("status.search_saved", savedSearch.Name)
 							m.statusTimeout = 3
 						}
 					}
@@ -638,18 +1014,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					results, err := m.service.SearchPromptsByBooleanExpression(expr)
 					if err == nil {
 						// Update prompt list with search results
-						items := make([]list.Item, len(results))
-						for i, p := range results {
-							items[i] = p
-						}
-						m.promptList.SetItems(items)
+						m.setPromptItems(results)
 						m.prompts = results
 						m.currentExpression = expr
+						m.service.RecordSearch("boolean", expr.QueryString(), len(results))
 						
-						m.statusMsg = fmt.Sprintf("Found %d prompts", len(results))
+						m.statusMsg = i18n.T("status.found", len(results))
 						m.statusTimeout = 2
 					} else {
-						m.statusMsg = fmt.Sprintf("Search failed: %v", err)
+						m.statusMsg = i18n.T("status.search_failed", err)
 						m.statusTimeout = 3
 					}
 				}
@@ -690,30 +1063,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					results, err := m.service.SearchPromptsByBooleanExpression(expr)
 					if err == nil {
 						// Update prompt list with search results
-						items := make([]list.Item, len(results))
-						for i, p := range results {
-							items[i] = p
-						}
-						m.promptList.SetItems(items)
+						m.setPromptItems(results)
 						m.prompts = results
 						m.currentExpression = expr
+						m.service.RecordSearch("boolean", expr.QueryString(), len(results))
 						
-						m.statusMsg = fmt.Sprintf("Found %d prompts", len(results))
+						m.statusMsg = i18n.T("status.found", len(results))
 						m.statusTimeout = 2
 						cmd = clearStatusCmd()
 					}
 				} else {
 					// No expression means search was cleared - restore full list
 					if allPrompts, err := m.service.ListPrompts(); err == nil {
-						items := make([]list.Item, len(allPrompts))
-						for i, p := range allPrompts {
-							items[i] = p
-						}
-						m.promptList.SetItems(items)
+						m.setPromptItems(allPrompts)
 						m.prompts = allPrompts
 						m.currentExpression = nil
 						
-						m.statusMsg = "Search cleared - showing all prompts"
+						m.statusMsg = i18n.T("status.search_cleared")
 						m.statusTimeout = 2
 						cmd = clearStatusCmd()
 					}
@@ -725,8 +1091,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Handle modal-specific keys for help modal
 		if m.showHelpModal {
+			// While searching, keys type the term; arrows still scroll
+			if m.helpSearching {
+				switch msg.String() {
+				case "esc":
+					m.clearHelpSearch()
+					return m, nil
+				case "enter":
+					m.helpSearching = false
+					m.helpSearch.Blur()
+					return m, nil
+				case "up":
+					m.helpViewport.LineUp(1)
+					return m, nil
+				case "down":
+					m.helpViewport.LineDown(1)
+					return m, nil
+				case "pgup":
+					m.helpViewport.HalfViewUp()
+					return m, nil
+				case "pgdown":
+					m.helpViewport.HalfViewDown()
+					return m, nil
+				}
+				var cmd tea.Cmd
+				m.helpSearch, cmd = m.helpSearch.Update(msg)
+				m.helpViewport.GotoTop()
+				return m, cmd
+			}
+
 			// First, handle viewport scrolling
 			switch msg.String() {
+			case "/":
+				m.helpSearching = true
+				return m, m.helpSearch.Focus()
 			case "up", "k":
 				m.helpViewport.LineUp(1)
 				return m, nil
@@ -749,7 +1147,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Copy modal content to clipboard
 				if m.modalContent != "" {
 					if statusMsg, err := clipboard.CopyWithFallback(m.modalContent); err != nil {
-						m.statusMsg = fmt.Sprintf("Copy failed: %v", err)
+						m.statusMsg = i18n.T("status.copy_failed", err)
 						m.statusTimeout = 3
 					} else {
 						m.statusMsg = statusMsg
@@ -758,9 +1156,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, clearStatusCmd()
 				}
 			case "?", "esc":
+				// Esc clears a search before it closes the modal
+				if msg.String() == "esc" && m.helpSearch.Value() != "" {
+					m.clearHelpSearch()
+					return m, nil
+				}
 				// Close modal
 				m.showHelpModal = false
 				m.modalContent = ""
+				m.clearHelpSearch()
 				// Clear copy status message when closing
 				if m.statusMsg == "Copied to clipboard!" {
 					m.statusMsg = ""
@@ -777,7 +1181,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Copy modal content to clipboard
 				if m.modalContent != "" {
 					if statusMsg, err := clipboard.CopyWithFallback(m.modalContent); err != nil {
-						m.statusMsg = fmt.Sprintf("Copy failed: %v", err)
+						m.statusMsg = i18n.T("status.copy_failed", err)
 						m.statusTimeout = 3
 					} else {
 						m.statusMsg = statusMsg
@@ -805,16 +1209,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.String() != "ctrl+d" {
 			m.deleteConfirm = false
 		}
+		// Reset the lock warning for any key except edit
+		if !key.Matches(msg, m.keys.Edit) {
+			m.lockWarned = ""
+		}
 
 		switch {
 		case key.Matches(msg, m.keys.Quit):
+			m.saveSession()
 			return m, tea.Quit
 
 		case key.Matches(msg, m.keys.Enter):
 			if m.viewMode == ViewLibrary && !m.loading {
 				if i, ok := m.promptList.SelectedItem().(*models.Prompt); ok {
-					// Load full prompt with content from service
-					fullPrompt, err := m.service.GetPrompt(i.ID)
+					// Load full prompt with content from service, or from its source
+					var fullPrompt *models.Prompt
+					var err error
+					if isForeign(i) {
+						fullPrompt, err = m.federation.Get(context.Background(), i.Source, i.ID)
+					} else {
+						fullPrompt, err = m.service.GetPrompt(i.ID)
+					}
 					if err != nil {
 						m.err = err
 						return m, nil
@@ -834,6 +1249,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				switch m.viewMode {
 				case ViewEditPrompt:
 					if m.createForm != nil {
+						// Misspellings are listed first; a second Ctrl+s saves anyway
+						if !m.confirmSpelling() {
+							return m, nil
+						}
 						// Save the prompt
 						prompt := m.createForm.ToPrompt()
 						if m.editMode && m.selectedPrompt != nil {
@@ -841,19 +1260,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							prompt.ID = m.selectedPrompt.ID // Ensure we're updating the same prompt
 						}
 						if err := m.service.SavePrompt(prompt); err != nil {
-							m.statusMsg = fmt.Sprintf("Save failed: %v", err)
+							m.statusMsg = i18n.T("status.save_failed", err)
 							m.statusTimeout = 3
 						} else {
 							if m.editMode {
-								m.statusMsg = "Prompt updated! Previous version archived."
+								m.statusMsg = i18n.T("status.prompt_updated")
 							} else {
-								m.statusMsg = "Prompt saved successfully!"
+								m.statusMsg = i18n.T("status.prompt_saved")
 							}
 							m.statusTimeout = 2
 							// Refresh prompt list (respects active boolean search filter)
 							if err := m.refreshPromptListSmart(); err != nil {
-								m.statusMsg = fmt.Sprintf("Failed to refresh list: %v", err)
+								m.statusMsg = i18n.T("status.refresh_failed", err)
 								m.statusTimeout = 3
+							} else if !m.editMode {
+								m.noteSimilarTitles(prompt)
 							}
 							// Go back to library
 							m.viewMode = ViewLibrary
@@ -872,20 +1293,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							// Keep original creation date for edits
 							template.CreatedAt = m.selectedTemplate.CreatedAt
 						}
-						if err := m.service.SaveTemplate(template); err != nil {
-							m.statusMsg = fmt.Sprintf("Save failed: %v", err)
+						if err := m.saveTemplate(template); err != nil {
+							m.statusMsg = i18n.T("status.save_failed", err)
 							m.statusTimeout = 3
 						} else {
-							m.statusMsg = "Template saved successfully!"
+							m.statusMsg = i18n.T("status.template_saved")
 							m.statusTimeout = 2
-							// Refresh template list
-							if templates, err := m.service.ListTemplates(); err == nil {
-								m.templates = templates
-							}
-							// Go back to template management
-							m.viewMode = ViewTemplateManagement
+							// Refresh template list and go back to template management
+							m.reloadTemplates()
 							m.templateForm = nil
 							m.editMode = false
+							m.selectedTemplate = nil
+							m.openTemplateManagement()
 						}
 						return m, clearStatusCmd()
 					}
@@ -895,24 +1314,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				switch m.viewMode {
 				case ViewEditPrompt:
 					if m.selectedPrompt != nil {
+						if err := m.service.CheckProtected(m.selectedPrompt); err != nil {
+							m.statusMsg = i18n.T("status.delete_failed", err)
+							m.statusTimeout = 3
+							return m, nil
+						}
 						if !m.deleteConfirm {
 							// First press: show confirmation
 							m.deleteConfirm = true
-							m.statusMsg = "Press Ctrl+D again to confirm deletion"
+							m.statusMsg = i18n.T("status.confirm_delete")
 							m.statusTimeout = 100 // Keep showing until next action
 							return m, nil
 						} else {
 							// Second press: actually delete
 							m.deleteConfirm = false
 							if err := m.service.DeletePrompt(m.selectedPrompt.ID); err != nil {
-								m.statusMsg = fmt.Sprintf("Delete failed: %v", err)
+								m.statusMsg = i18n.T("status.delete_failed", err)
 								m.statusTimeout = 3
 							} else {
-								m.statusMsg = "Prompt deleted successfully!"
+								m.statusMsg = i18n.T("status.prompt_deleted")
 								m.statusTimeout = 2
 								// Refresh prompt list (respects active boolean search filter)
 								if err := m.refreshPromptListSmart(); err != nil {
-									m.statusMsg = fmt.Sprintf("Failed to refresh list: %v", err)
+									m.statusMsg = i18n.T("status.refresh_failed", err)
 									m.statusTimeout = 3
 								}
 								// Go back to library
@@ -924,11 +1348,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return m, clearStatusCmd()
 						}
 					}
-				case ViewEditTemplate:
-					// Template deletion could be added here if needed
-					m.statusMsg = "Template deletion not yet implemented"
-					m.statusTimeout = 2
-					return m, clearStatusCmd()
+				case ViewEditTemplate, ViewTemplateDetail:
+					if m.selectedTemplate != nil && (m.editMode || m.viewMode == ViewTemplateDetail) {
+						m.deleteSelectedTemplate()
+						if m.deleteConfirm {
+							return m, nil
+						}
+						return m, clearStatusCmd()
+					}
 				case ViewSavedSearches:
 					// Delete saved search
 					if m.selectForm != nil && len(m.selectForm.options) > 0 {
@@ -938,41 +1365,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 								if !m.deleteConfirm {
 									// First press: show confirmation
 									m.deleteConfirm = true
-									m.statusMsg = fmt.Sprintf("Press Ctrl+D again to delete '%s'", savedSearch.Name)
+									m.statusMsg = i18n.T("status.confirm_delete_search", savedSearch.Name)
 									m.statusTimeout = 100 // Keep showing until next action
 									return m, nil
 								} else {
 									// Second press: actually delete
 									m.deleteConfirm = false
 									if err := m.service.DeleteSavedSearch(savedSearch.Name); err != nil {
-										m.statusMsg = fmt.Sprintf("Delete failed: %v", err)
+										m.statusMsg = i18n.T("status.delete_failed", err)
 										m.statusTimeout = 3
 									} else {
-										m.statusMsg = fmt.Sprintf("Search '%s' deleted!", savedSearch.Name)
+										m.statusMsg = i18n.T("status.search_deleted", savedSearch.Name)
 										m.statusTimeout = 2
 										// Refresh saved searches list
 										savedSearches, err := m.service.ListSavedSearches()
 										if err == nil {
 											m.savedSearches = savedSearches
 											// Update select form options with result counts
-											options := []SelectOption{}
-											for _, search := range savedSearches {
-												// Calculate result count for this search
-												results, err := m.service.SearchPromptsByBooleanExpression(search.Expression)
-												resultCount := 0
-												if err == nil {
-													resultCount = len(results)
-												}
-												
-												// Format description with expression and count
-												description := fmt.Sprintf("%s (%d results)", search.Expression.String(), resultCount)
-												
-												options = append(options, SelectOption{
-													Label:       search.Name,
-													Description: description,
-													Value:       search,
-												})
-											}
+											options := m.savedSearchOptions(savedSearches)
 											if len(options) == 0 {
 												// No more searches - go back to library
 												m.viewMode = ViewLibrary
@@ -1042,9 +1452,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.viewMode = ViewLibrary
 				m.selectForm = nil
 				m.savedSearches = nil
+			case ViewPromptDetail:
+				// Esc goes back to copying the whole prompt before it leaves
+				if m.section > 0 && key.Matches(msg, m.keys.Back) {
+					m.section = 0
+					m.statusMsg = i18n.T("status.whole_prompt")
+					m.statusTimeout = 2
+					return m, clearStatusCmd()
+				}
+			case ViewLibrary:
+				// Esc lets go of marked prompts before it clears a filter
+				if len(m.marked) > 0 && key.Matches(msg, m.keys.Back) && !m.promptList.SettingFilter() {
+					clear(m.marked)
+					m.statusMsg = i18n.T("status.marks_cleared")
+					m.statusTimeout = 2
+					return m, clearStatusCmd()
+				}
 			}
 
 
+		case key.Matches(msg, m.keys.NextSection) && m.viewMode == ViewPromptDetail && m.selectedPrompt != nil:
+			// n and p are New and PackSelector elsewhere
+			m.moveSection(1)
+			return m, clearStatusCmd()
+
+		case key.Matches(msg, m.keys.PrevSection) && m.viewMode == ViewPromptDetail && m.selectedPrompt != nil:
+			m.moveSection(-1)
+			return m, clearStatusCmd()
+
 		case key.Matches(msg, m.keys.New):
 			if m.viewMode == ViewLibrary && !m.loading && !m.promptList.SettingFilter() {
 				// Initialize the create menu select form
@@ -1070,6 +1505,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case ViewLibrary:
 				if !m.loading && !m.promptList.SettingFilter() {
 					if i, ok := m.promptList.SelectedItem().(*models.Prompt); ok {
+						if isForeign(i) {
+							return m.readOnlySource(i)
+						}
+						if m.warnIfLocked(i.ID) {
+							return m, nil
+						}
 						// Load full prompt with content from service
 						fullPrompt, err := m.service.GetPrompt(i.ID)
 						if err != nil {
@@ -1093,6 +1534,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case ViewPromptDetail:
 				if m.selectedPrompt != nil {
+					if isForeign(m.selectedPrompt) {
+						return m.readOnlySource(m.selectedPrompt)
+					}
+					if m.warnIfLocked(m.selectedPrompt.ID) {
+						return m, nil
+					}
 					m.createForm = NewCreateForm()
 					// Set available tags for autocomplete
 					if tags, err := m.service.GetAllTags(); err == nil {
@@ -1140,32 +1587,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.Templates):
 			if m.viewMode == ViewLibrary && !m.loading && !m.promptList.SettingFilter() {
-				// Create template management select form
-				options := []SelectOption{
-					{
-						Label:       "Create new template",
-						Description: "Start with a blank template",
-						Value:       "new",
-					},
-				}
-				// Add existing templates as options
-				for _, template := range m.templates {
-					options = append(options, SelectOption{
-						Label:       template.Name,
-						Description: template.Description,
-						Value:       template,
-					})
-				}
-				m.selectForm = NewSelectForm(options)
-				m.viewMode = ViewTemplateManagement
+				m.openTemplateManagement()
 				return m, nil
 			}
 
+		case key.Matches(msg, m.keys.TemplateGallery):
+			if m.viewMode == ViewLibrary && !m.loading && !m.promptList.SettingFilter() {
+				return m, m.openTemplateGallery()
+			}
+
 		case key.Matches(msg, m.keys.Help):
 			// Toggle help modal
 			m.showHelpModal = !m.showHelpModal
 			return m, nil
 
+		case key.Matches(msg, m.keys.Keys):
+			if !m.promptList.SettingFilter() {
+				groups, err := KeyGroups(m.service.Settings().UI.Keys)
+				if err != nil {
+					m.statusMsg = err.Error()
+					m.statusTimeout = 3
+					return m, clearStatusCmd()
+				}
+				if m.keysModal == nil {
+					m.keysModal = NewKeysModal()
+				}
+				m.keysModal.Show(groups, m.width, m.height)
+				return m, nil
+			}
+
 		case key.Matches(msg, m.keys.ExpandHelp):
 			// Toggle expanded help in current view
 			m.showExpandedHelp = !m.showExpandedHelp
@@ -1178,22 +1628,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.BooleanSearch):
 			if m.viewMode == ViewLibrary && !m.loading {
-				// Get available tags for boolean search
-				tags, err := m.service.GetAllTags()
+				// Get available tags for boolean search, with how often each is used
+				tagCounts, err := m.service.TagCounts()
 				if err != nil {
-					m.statusMsg = fmt.Sprintf("Failed to load tags: %v", err)
+					m.statusMsg = i18n.T("status.tags_failed", err)
 					m.statusTimeout = 3
 					return m, clearStatusCmd()
 				}
 				
 				// Initialize boolean search modal
 				if m.booleanSearchModal == nil {
-					m.booleanSearchModal = NewBooleanSearchModal(tags)
+					m.booleanSearchModal = NewBooleanSearchModal(nil)
 					// Set up live search callback
 					m.booleanSearchModal.SetSearchFunc(m.service.SearchPromptsByBooleanExpression)
 					// Set up save callback
 					m.booleanSearchModal.SetSaveFunc(m.service.SaveBooleanSearch)
 				}
+				m.booleanSearchModal.SetTagCounts(tagCounts)
 				m.booleanSearchModal.Resize(m.width, m.height)
 				m.booleanSearchModal.SetActive(true)
 				return m, nil
@@ -1204,33 +1655,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Load saved searches
 				savedSearches, err := m.service.ListSavedSearches()
 				if err != nil {
-					m.statusMsg = fmt.Sprintf("Failed to load saved searches: %v", err)
+					m.statusMsg = i18n.T("status.saved_searches_failed", err)
 					m.statusTimeout = 3
 					return m, clearStatusCmd()
 				}
 				
 				// Create saved searches select form with result counts
-				options := []SelectOption{}
-				for _, search := range savedSearches {
-					// Calculate result count for this search
-					results, err := m.service.SearchPromptsByBooleanExpression(search.Expression)
-					resultCount := 0
-					if err == nil {
-						resultCount = len(results)
-					}
-					
-					// Format description with expression and count
-					description := fmt.Sprintf("%s (%d results)", search.Expression.String(), resultCount)
-					
-					options = append(options, SelectOption{
-						Label:       search.Name,
-						Description: description,
-						Value:       search,
-					})
-				}
+				options := m.savedSearchOptions(savedSearches)
 				
 				if len(options) == 0 {
-					m.statusMsg = "No saved searches found. Create one with 'b' for boolean search."
+					m.statusMsg = i18n.T("status.no_saved_searches")
 					m.statusTimeout = 3
 					return m, clearStatusCmd()
 				}
@@ -1241,12 +1675,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case key.Matches(msg, m.keys.PinSearch):
+			if m.viewMode == ViewSavedSearches && m.selectForm != nil {
+				if selected := m.selectForm.GetSelected(); selected != nil {
+					if savedSearch, ok := selected.Value.(models.SavedSearch); ok {
+						// Pinning the pinned search again unpins it
+						name := savedSearch.Name
+						if m.service.PinnedSearch() == name {
+							name = ""
+						}
+						if err := m.service.SetPinnedSearch(name); err != nil {
+							m.statusMsg = i18n.T("status.pin_failed", err)
+						} else if name == "" {
+							m.statusMsg = i18n.T("status.search_unpinned", savedSearch.Name)
+						} else {
+							m.statusMsg = i18n.T("status.search_pinned", savedSearch.Name)
+						}
+						m.statusTimeout = 3
+
+						cursor := m.selectForm.selected
+						m.selectForm = NewSelectForm(m.savedSearchOptions(m.savedSearches))
+						m.selectForm.selected = cursor
+						return m, clearStatusCmd()
+					}
+				}
+			}
+
+		case key.Matches(msg, m.keys.SubscribeSearch):
+			if m.viewMode == ViewSavedSearches && m.selectForm != nil {
+				if selected := m.selectForm.GetSelected(); selected != nil {
+					if savedSearch, ok := selected.Value.(models.SavedSearch); ok {
+						return m.toggleSubscription(savedSearch.Name)
+					}
+				}
+			}
+
+		case key.Matches(msg, m.keys.SourceSwitch):
+			if m.viewMode == ViewLibrary && !m.promptList.SettingFilter() {
+				sources := append(m.federation.Sources(), config.AllSourcesName)
+				if len(sources) == 2 {
+					m.statusMsg = i18n.T("status.no_sources")
+					m.statusTimeout = 3
+					return m, clearStatusCmd()
+				}
+				next := sources[0]
+				for i, name := range sources {
+					if name == m.currentSource {
+						next = sources[(i+1)%len(sources)]
+						break
+					}
+				}
+				m.currentSource = next
+				if next == config.LocalSourceName {
+					if err := m.refreshPromptListSmart(); err != nil {
+						m.statusMsg = i18n.T("status.refresh_failed", err)
+					} else {
+						m.statusMsg = i18n.T("status.viewing_local")
+					}
+					m.statusTimeout = 2
+					return m, clearStatusCmd()
+				}
+				m.loading = true
+				return m, loadSourceCmd(m.federation, next)
+			}
+
 		case key.Matches(msg, m.keys.PackSelector):
 			if m.viewMode == ViewLibrary && !m.loading {
 				// Load available packs
 				availablePacks, err := m.service.GetAvailablePacks()
 				if err != nil {
-					m.statusMsg = fmt.Sprintf("Failed to load packs: %v", err)
+					m.statusMsg = i18n.T("status.packs_failed", err)
 					m.statusTimeout = 3
 					return m, clearStatusCmd()
 				}
@@ -1264,53 +1762,177 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.Copy):
 			if m.viewMode == ViewPromptDetail && m.renderedContent != "" {
-				if statusMsg, err := clipboard.CopyWithFallback(m.renderedContent); err != nil {
-					m.statusMsg = fmt.Sprintf("Copy failed: %v", err)
+				content := m.renderedContent
+				if section, _, ok := m.selectedSection(); ok {
+					content = section.Text
+				}
+				if statusMsg, err := clipboard.CopyWithFallback(content); err != nil {
+					m.statusMsg = i18n.T("status.copy_failed", err)
 					m.statusTimeout = 3
 				} else {
+					m.recordUsage()
 					m.statusMsg = statusMsg
 					m.statusTimeout = 2
 				}
 				return m, clearStatusCmd()
 			}
 
-		case key.Matches(msg, m.keys.CopyJSON):
-			if m.viewMode == ViewPromptDetail && m.renderedContentJSON != "" {
-				if _, err := clipboard.CopyWithFallback(m.renderedContentJSON); err != nil {
-					m.statusMsg = fmt.Sprintf("JSON copy failed: %v", err)
-					m.statusTimeout = 3
-				} else {
-					m.statusMsg = "Copied as JSON messages!"
-					m.statusTimeout = 2
+		case key.Matches(msg, m.keys.AddTag), key.Matches(msg, m.keys.RemoveTag):
+			if m.viewMode == ViewLibrary && !m.loading && !m.promptList.SettingFilter() {
+				if i, ok := m.promptList.SelectedItem().(*models.Prompt); ok {
+					if isForeign(i) {
+						return m.readOnlySource(i)
+					}
+					if m.quickTagModal == nil {
+						m.quickTagModal = NewQuickTagModal()
+					}
+					tags, _ := m.service.GetAllTags()
+					m.quickTagModal.Show(i, key.Matches(msg, m.keys.RemoveTag), tags)
+					return m, textinput.Blink
 				}
-				return m, clearStatusCmd()
 			}
 
-		}
-	}
+		case key.Matches(msg, m.keys.Mark):
+			if m.viewMode == ViewLibrary && !m.loading && !m.promptList.SettingFilter() {
+				if i, ok := m.promptList.SelectedItem().(*models.Prompt); ok {
+					if isForeign(i) {
+						return m.readOnlySource(i)
+					}
+					if m.marked[i.ID] {
+						delete(m.marked, i.ID)
+					} else {
+						m.marked[i.ID] = true
+					}
+					m.promptList.CursorDown()
+					m.statusMsg = i18n.T("status.marked", len(m.marked))
+					m.statusTimeout = 2
+					return m, clearStatusCmd()
+				}
+			}
 
-	// Update the appropriate component based on view mode
-	switch m.viewMode {
-	case ViewLibrary:
-		// Handle wraparound navigation when not actively typing in filter
-		if keyMsg, ok := msg.(tea.KeyMsg); ok && !m.promptList.SettingFilter() {
-			// Get the visible items (filtered items if filter is applied, all items if not)
-			visibleItems := m.promptList.VisibleItems()
-			visibleCount := len(visibleItems)
-			
-			if visibleCount > 0 {
-				switch keyMsg.String() {
-				case "up", "k":
-					if m.promptList.Index() == 0 {
-						// At top, wrap to bottom of visible items
-						m.promptList.Select(visibleCount - 1)
+		case key.Matches(msg, m.keys.MovePack):
+			if m.viewMode == ViewLibrary && !m.loading && !m.promptList.SettingFilter() {
+				prompts := m.markedPrompts()
+				if len(prompts) == 0 {
+					i, ok := m.promptList.SelectedItem().(*models.Prompt)
+					if !ok {
 						return m, nil
 					}
-				case "down", "j":
-					if m.promptList.Index() == visibleCount-1 {
-						// At bottom of visible items, wrap to top
-						m.promptList.Select(0)
-						return m, nil
+					prompts = []*models.Prompt{i}
+				}
+				for _, p := range prompts {
+					if isForeign(p) {
+						return m.readOnlySource(p)
+					}
+				}
+				availablePacks, err := m.service.GetAvailablePacks()
+				if err != nil {
+					m.statusMsg = i18n.T("status.packs_failed", err)
+					m.statusTimeout = 3
+					return m, clearStatusCmd()
+				}
+				if m.movePackModal == nil {
+					m.movePackModal = NewMovePackModal()
+				}
+				m.movePackModal.Show(prompts, availablePacks)
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.DetailTab):
+			if m.viewMode == ViewPromptDetail && m.selectedPrompt != nil {
+				m.showDetailTab(detailTab(msg.String()[0] - '1'))
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.History):
+			if m.viewMode == ViewPromptDetail && m.selectedPrompt != nil {
+				m.showDetailTab(tabVersions)
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.Raw):
+			if m.viewMode == ViewPromptDetail && m.selectedPrompt != nil {
+				m.showDetailTab(tabSource)
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.Reveal):
+			if m.viewMode == ViewPromptDetail && m.selectedPrompt != nil {
+				m.revealPrompt()
+				return m, clearStatusCmd()
+			}
+
+		case key.Matches(msg, m.keys.Profile):
+			if m.viewMode == ViewPromptDetail && m.selectedPrompt != nil {
+				profiles, err := m.service.ListProfiles()
+				if err != nil {
+					m.statusMsg = i18n.T("status.profiles_failed", err)
+					m.statusTimeout = 3
+					return m, clearStatusCmd()
+				}
+				if len(profiles) == 0 {
+					m.statusMsg = i18n.T("status.no_profiles")
+					m.statusTimeout = 3
+					return m, clearStatusCmd()
+				}
+				// Cycle through each profile, then back to none
+				options := append([]string{""}, profiles...)
+				next := ""
+				for i, name := range options {
+					if name == m.currentProfile {
+						next = options[(i+1)%len(options)]
+						break
+					}
+				}
+				m.currentProfile = next
+				m.statusMsg = i18n.T("status.no_profile")
+				if next != "" {
+					m.statusMsg = i18n.T("status.profile", next)
+				}
+				m.statusTimeout = 2
+				m.renderPreview() // Reports a profile that fails to load in the status line
+				return m, clearStatusCmd()
+			}
+
+		case key.Matches(msg, m.keys.CopyJSON):
+			if m.viewMode == ViewPromptDetail && m.renderedContentJSON != "" {
+				if _, err := clipboard.CopyWithFallback(m.renderedContentJSON); err != nil {
+					m.statusMsg = i18n.T("status.json_copy_failed", err)
+					m.statusTimeout = 3
+				} else {
+					m.recordUsage()
+					m.statusMsg = i18n.T("status.copied_json")
+					m.statusTimeout = 2
+				}
+				return m, clearStatusCmd()
+			}
+
+		}
+	}
+
+	// Update the appropriate component based on view mode
+	switch m.viewMode {
+	case ViewLibrary:
+		// Handle wraparound navigation when not actively typing in filter
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && !m.promptList.SettingFilter() {
+			m.loadPromptsFor(keyMsg)
+			// Get the visible items (filtered items if filter is applied, all items if not)
+			visibleItems := m.promptList.VisibleItems()
+			visibleCount := len(visibleItems)
+			
+			if visibleCount > 0 {
+				switch keyMsg.String() {
+				case "up", "k":
+					if m.promptList.Index() == 0 {
+						// At top, wrap to bottom of visible items
+						m.promptList.Select(visibleCount - 1)
+						return m, nil
+					}
+				case "down", "j":
+					if m.promptList.Index() == visibleCount-1 {
+						// At bottom of visible items, wrap to top
+						m.promptList.Select(0)
+						return m, nil
 					}
 				}
 			}
@@ -1318,6 +1940,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		
 		newListModel, cmd := m.promptList.Update(msg)
 		m.promptList = newListModel
+		m.loadMorePrompts()
 		cmds = append(cmds, cmd)
 
 	case ViewPromptDetail:
@@ -1326,6 +1949,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if key.Matches(keyMsg, m.keys.Back) || key.Matches(keyMsg, m.keys.Left) {
 				m.viewMode = ViewLibrary
 				m.selectedPrompt = nil
+				m.detailTab = tabContent
+				m.section = 0
 				m.renderedContent = ""
 				m.renderedContentJSON = ""
 				// Don't pass to viewport, navigation handled
@@ -1376,7 +2001,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							m.selectForm = NewSelectForm(templateOptions)
 							m.viewMode = ViewTemplateList
 						} else {
-							m.statusMsg = "No templates available"
+							m.statusMsg = i18n.T("status.no_templates")
 							m.statusTimeout = 2
 							m.viewMode = ViewLibrary
 							cmds = append(cmds, clearStatusCmd())
@@ -1405,8 +2030,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ViewEditPrompt:
 		if m.createForm != nil {
+			before := m.createForm.textarea.Value()
 			cmd := m.createForm.Update(msg)
-			cmds = append(cmds, cmd)
+			cmds = append(cmds, cmd, m.afterEditorUpdate(before))
 		}
 
 	case ViewEditTemplate:
@@ -1417,21 +2043,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ViewCreateFromScratch:
 		if m.createForm != nil {
+			before := m.createForm.textarea.Value()
 			cmd := m.createForm.Update(msg)
-			cmds = append(cmds, cmd)
+			cmds = append(cmds, cmd, m.afterEditorUpdate(before))
 			// Check if form was submitted
-			if m.createForm.IsSubmitted() {
+			if m.createForm.IsSubmitted() && !m.confirmSpelling() {
+				m.createForm.submitted = false
+			} else if m.createForm.IsSubmitted() {
 				prompt := m.createForm.ToPrompt()
 				if err := m.service.SavePrompt(prompt); err != nil {
-					m.statusMsg = fmt.Sprintf("Save failed: %v", err)
+					m.statusMsg = i18n.T("status.save_failed", err)
 					m.statusTimeout = 3
 				} else {
-					m.statusMsg = "Prompt created successfully!"
+					m.statusMsg = i18n.T("status.prompt_created")
 					m.statusTimeout = 2
 					// Refresh prompt list (respects active boolean search filter)
 					if err := m.refreshPromptListSmart(); err != nil {
-						m.statusMsg = fmt.Sprintf("Failed to refresh list: %v", err)
+						m.statusMsg = i18n.T("status.refresh_failed", err)
 						m.statusTimeout = 3
+					} else {
+						m.noteSimilarTitles(prompt)
 					}
 					// Go back to library
 					m.viewMode = ViewLibrary
@@ -1479,20 +2110,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						// Execute the saved search
 						results, err := m.service.SearchPromptsByBooleanExpression(savedSearch.Expression)
 						if err != nil {
-							m.statusMsg = fmt.Sprintf("Search failed: %v", err)
+							m.statusMsg = i18n.T("status.search_failed", err)
 							m.statusTimeout = 3
 						} else {
 							// Update prompt list with search results
-							items := make([]list.Item, len(results))
-							for i, p := range results {
-								items[i] = p
-							}
-							m.promptList.SetItems(items)
+							m.setPromptItems(results)
 							m.prompts = results
 							m.currentExpression = savedSearch.Expression
 							
-							m.statusMsg = fmt.Sprintf("'%s': Found %d prompts", savedSearch.Name, len(results))
+							m.statusMsg = i18n.T("status.saved_search_results", savedSearch.Name, len(results))
 							m.statusTimeout = 2
+							cmds = append(cmds, m.acknowledgeMatches(savedSearch.Name))
+							m.service.RecordSearch("saved-search", savedSearch.Name, len(results))
 						}
 						
 						// Return to library view
@@ -1528,6 +2157,39 @@ func (m Model) View() string {
 		return m.renderGHSyncInfoModal()
 	}
 
+	// If the quick tag modal is active, render it on top
+	if m.quickTagModal != nil && m.quickTagModal.IsActive() {
+		return lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			m.quickTagModal.View(),
+		)
+	}
+
+	// If the move to pack modal is active, render it on top
+	if m.movePackModal != nil && m.movePackModal.IsActive() {
+		return lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			m.movePackModal.View(),
+		)
+	}
+
+	// If the keybindings modal is active, render it on top
+	if m.keysModal != nil && m.keysModal.IsActive() {
+		return lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			m.keysModal.View(),
+		)
+	}
+
 	// If the save search modal is active, render it on top (highest priority)
 	if m.saveSearchModal != nil && m.saveSearchModal.IsActive() {
 		modalView := m.saveSearchModal.View()
@@ -1599,6 +2261,11 @@ func (m Model) View() string {
 	case ViewSavedSearches:
 		mainView = m.renderSavedSearchesView()
 
+	case ViewTemplateGallery:
+		if m.templateGallery != nil {
+			mainView = m.templateGallery.View(m.showExpandedHelp, m.width)
+		}
+
 	default:
 		mainView = "Unknown view mode"
 	}
@@ -1627,11 +2294,11 @@ func (m Model) renderLibraryView() string {
 		help = CreateGuaranteedHelp("Loading prompts... • q quit", m.width)
 	} else {
 		if m.currentExpression != nil {
-			essential := []string{"enter view • e edit • n create"}
+			essential := []string{"enter view • e edit • n create • +/- tag"}
 			additional := []string{"Ctrl+f modify search • q quit"}
 			help = CreateContextualHelp(essential, additional, m.showExpandedHelp, m.width)
 		} else {
-			essential := []string{"enter view • e edit • n create"}
+			essential := []string{"enter view • e edit • n create • +/- tag"}
 			additional := []string{"/ search • t templates • f saved searches", "Ctrl+f boolean search • ? help • q quit"}
 			help = CreateContextualHelp(essential, additional, m.showExpandedHelp, m.width)
 		}
@@ -1647,6 +2314,18 @@ func (m Model) renderLibraryView() string {
 	if gitStatus != "" {
 		elements = append(elements, gitStatus)
 	}
+	if m.serverStatus != "" {
+		elements = append(elements, CreateServerStatus(m.serverStatus))
+	}
+	if m.quotaStatus != "" {
+		elements = append(elements, CreateQuotaStatus(m.quotaStatus))
+	}
+	if m.subscriptionStatus != "" {
+		elements = append(elements, CreateSubscriptionStatus(m.subscriptionStatus))
+	}
+	if m.service.ReadOnly() {
+		elements = append(elements, CreateReadOnlyStatus(i18n.T("status.read_only")))
+	}
 	if searchIndicator != "" {
 		elements = append(elements, searchIndicator)
 	}
@@ -1676,7 +2355,7 @@ func (m Model) renderPromptDetailView() string {
 	// Create metadata line
 	metadata := fmt.Sprintf("ID: %s • Version: %s", m.selectedPrompt.ID, m.selectedPrompt.Version)
 	if !m.selectedPrompt.UpdatedAt.IsZero() {
-		metadata += fmt.Sprintf(" • Last edited: %s", m.selectedPrompt.UpdatedAt.Format("2006-01-02 15:04"))
+		metadata += " • " + i18n.T("prompt.last_edited", i18n.FormatDateTime(m.selectedPrompt.UpdatedAt))
 	}
 	if len(m.selectedPrompt.Tags) > 0 {
 		tags := ""
@@ -1688,11 +2367,32 @@ func (m Model) renderPromptDetailView() string {
 		}
 		metadata += fmt.Sprintf(" • Tags: %s", tags)
 	}
+	if m.service.IsProtected(m.selectedPrompt) {
+		metadata += " • " + i18n.T("prompt.protected")
+	}
+	if m.selectedPrompt.VariantGroup != "" {
+		metadata += " • " + i18n.T("prompt.variant_of", m.selectedPrompt.VariantGroup)
+	}
+	if m.currentProfile != "" {
+		metadata += " • " + i18n.T("status.profile", m.currentProfile)
+	}
+	if m.usingSavedAnswers {
+		metadata += " • " + i18n.T("prompt.saved_answers")
+	}
+	if section, count, ok := m.selectedSection(); ok {
+		metadata += " • " + i18n.T("prompt.section", m.section, count, section.Title)
+	}
+	if lock, _ := m.service.PromptLock(m.selectedPrompt.ID); lock != nil {
+		metadata += " • " + i18n.T("prompt.locked_by", lock.Owner)
+	}
+	stats := m.contentStats
+	metadata += " • " + i18n.T("prompt.length", stats.Words, stats.Characters, stats.Lines, stats.ReadingMinutes)
 	metadataLine := CreateMetadata(metadata)
+	tabs := CreateTabs(detailTabLabels(), int(m.detailTab))
 
 	// Help text
-	essential := []string{"c copy • e edit"}
-	additional := []string{"y copy JSON • x export • Esc back"}
+	essential := []string{"c copy • e edit • 1-4 tabs"}
+	additional := []string{"y copy JSON • x export • n/p section • H history • R raw file • O reveal • v profile • Esc back"}
 	help := CreateContextualHelp(essential, additional, m.showExpandedHelp, m.width)
 
 	// Check scroll state and create indicators
@@ -1719,6 +2419,7 @@ func (m Model) renderPromptDetailView() string {
 		lipgloss.Left,
 		headerLine,
 		metadataLine,
+		tabs,
 		content,
 		help,
 	))
@@ -1794,7 +2495,11 @@ func (m Model) renderCreateFromScratchView() string {
 
 	// Content field
 	contentLabel := StyleFormLabel.Render("Content:")
-	formFields = append(formFields, contentLabel, m.createForm.textarea.View(), "")
+	formFields = append(formFields, contentLabel, m.createForm.textarea.View())
+	if hints := m.createForm.hintsView(); hints != "" {
+		formFields = append(formFields, hints)
+	}
+	formFields = append(formFields, "")
 
 	// Help text
 	help := CreateGuaranteedHelp("Tab next field • Ctrl+s save • Esc cancel", m.width)
@@ -1891,7 +2596,11 @@ func (m Model) renderEditPromptView() string {
 
 	// Content field
 	contentLabel := StyleFormLabel.Render("Content:")
-	formFields = append(formFields, contentLabel, m.createForm.textarea.View(), "")
+	formFields = append(formFields, contentLabel, m.createForm.textarea.View())
+	if hints := m.createForm.hintsView(); hints != "" {
+		formFields = append(formFields, hints)
+	}
+	formFields = append(formFields, "")
 
 	// Help text
 	help := CreateGuaranteedHelp("Tab next field • Ctrl+s save • Ctrl+d delete • Esc cancel", m.width)
@@ -1937,7 +2646,11 @@ func (m Model) renderEditTemplateView() string {
 	formFields = append(formFields, contentLabel, m.templateForm.textarea.View(), "")
 
 	// Help text
-	help := CreateGuaranteedHelp("Tab next field • arrows navigate • Ctrl+s save • Esc cancel", m.width)
+	helpText := "Tab next field • arrows navigate • Ctrl+s save • Esc cancel"
+	if m.editMode {
+		helpText = "Tab next field • arrows navigate • Ctrl+s save • Ctrl+d delete • Esc cancel"
+	}
+	help := CreateGuaranteedHelp(helpText, m.width)
 
 	// Join all elements
 	allElements := []string{headerLine, ""}
@@ -1958,11 +2671,14 @@ func (m Model) renderTemplateDetailView() string {
 
 	// Create metadata line
 	metadata := fmt.Sprintf("ID: %s • Version: %s", m.selectedTemplate.ID, m.selectedTemplate.Version)
+	if st, ok := m.templateStats[m.selectedTemplate.ID]; ok {
+		metadata += " • Usage: " + st.Summary()
+	}
 	metadataLine := CreateMetadata(metadata)
 
 	// Help text
 	essential := []string{"e edit"}
-	additional := []string{"Esc back"}
+	additional := []string{"Ctrl+d delete • Esc back"}
 	help := CreateContextualHelp(essential, additional, m.showExpandedHelp, m.width)
 
 	// Content (template preview)
@@ -2160,7 +2876,20 @@ func (m *Model) renderGHSyncInfoModal() string {
 	)
 }
 
-// renderHelpModal renders the help modal with comprehensive app information
+// clearHelpSearch empties the help modal's search, showing all of its help
+func (m *Model) clearHelpSearch() {
+	m.helpSearching = false
+	m.helpSearch.Blur()
+	m.helpSearch.SetValue("")
+	m.helpViewport.GotoTop()
+}
+
+// maxHelpTopicLines is how many matching lines of one CLI help topic the help
+// modal's search shows
+const maxHelpTopicLines = 3
+
+// renderHelpModal renders the help modal with comprehensive app information,
+// or once a term is typed after /, the help that mentions it
 func (m *Model) renderHelpModal() string {
 	// Modal styles - smaller size with scrolling capability
 	maxWidth := min(60, m.width-4)   // Smaller width, responsive to terminal size
@@ -2195,156 +2924,106 @@ func (m *Model) renderHelpModal() string {
 	// Build modal content and plain text version
 	var content []string
 	var plainText []string
-
-	// Title
-	content = append(content, titleStyle.Render("Pocket Prompt - Help"))
-	plainText = append(plainText, "Pocket Prompt - Help")
-	content = append(content, "")
-	plainText = append(plainText, "")
-
-	// Overview
-	content = append(content, headerStyle.Render("Overview"))
-	plainText = append(plainText, "Overview")
-	content = append(content, contentStyle.Render("A fast, keyboard-driven terminal app for managing AI prompts and templates."))
-	plainText = append(plainText, "A fast, keyboard-driven terminal app for managing AI prompts and templates.")
-	content = append(content, contentStyle.Render("Store, organize, search, and copy prompts with powerful tagging and templates."))
-	plainText = append(plainText, "Store, organize, search, and copy prompts with powerful tagging and templates.")
-	content = append(content, "")
-	plainText = append(plainText, "")
-
-	// Navigation & Basic Commands
-	content = append(content, headerStyle.Render("Navigation & Basic Commands"))
-	plainText = append(plainText, "Navigation & Basic Commands")
-	
-	keys := [][]string{
-		{"↑/↓", "Navigate lists and prompts"},
-		{"Enter", "Select item / View prompt details"},
-		{"b", "Go back / Close modals"},
-		{"q", "Quit application"},
-		{"?", "Toggle this help modal"},
+	add := func(styled, plain string) {
+		content = append(content, styled)
+		plainText = append(plainText, plain)
 	}
-	
-	for _, kv := range keys {
-		line := keyStyle.Render(kv[0]) + " " + kv[1]
-		content = append(content, contentStyle.Render(line))
-		plainText = append(plainText, kv[0] + " " + kv[1])
+	addKey := func(key, description string) {
+		add(contentStyle.Render(keyStyle.Render(key)+" "+description), key+" "+description)
 	}
-	content = append(content, "")
-	plainText = append(plainText, "")
 
-	// Prompt Management
-	content = append(content, headerStyle.Render("Prompt Management"))
-	plainText = append(plainText, "Prompt Management")
-	
-	promptKeys := [][]string{
-		{"n", "Create new prompt (from scratch or template)"},
-		{"e", "Edit selected prompt"},
-		{"c", "Copy prompt as plain text"},
-		{"y", "Copy prompt as JSON messages for LLM APIs"},
-		{"Ctrl+s", "Save prompt when editing"},
-		{"Ctrl+d", "Delete prompt (press twice to confirm)"},
-	}
-	
-	for _, kv := range promptKeys {
-		line := keyStyle.Render(kv[0]) + " " + kv[1]
-		content = append(content, contentStyle.Render(line))
-		plainText = append(plainText, kv[0] + " " + kv[1])
-	}
-	content = append(content, "")
-	plainText = append(plainText, "")
+	// Title
+	add(titleStyle.Render(i18n.T("help.title")), i18n.T("help.title"))
+	add("", "")
 
-	// Search & Discovery
-	content = append(content, headerStyle.Render("Search & Discovery"))
-	plainText = append(plainText, "Search & Discovery")
-	
-	searchKeys := [][]string{
-		{"/", "Start fuzzy search (type to filter prompts)"},
-		{"Ctrl+f", "Advanced boolean search with tags"},
-		{"f", "View and execute saved searches"},
-		{"Tab", "Switch focus in boolean search"},
-		{"Ctrl+s", "Save current boolean search"},
+	term := strings.TrimSpace(m.helpSearch.Value())
+	if m.helpSearching || term != "" {
+		content = append(content, m.helpSearch.View(), "")
 	}
-	
-	for _, kv := range searchKeys {
-		line := keyStyle.Render(kv[0]) + " " + kv[1]
-		content = append(content, contentStyle.Render(line))
-		plainText = append(plainText, kv[0] + " " + kv[1])
-	}
-	content = append(content, "")
-	plainText = append(plainText, "")
 
-	// Templates
-	content = append(content, headerStyle.Render("Templates"))
-	plainText = append(plainText, "Templates")
-	
-	content = append(content, contentStyle.Render(keyStyle.Render("t")+" Manage templates (create, edit, view)"))
-	plainText = append(plainText, "t Manage templates (create, edit, view)")
-	content = append(content, contentStyle.Render("Templates are reusable prompt scaffolds with variable slots"))
-	plainText = append(plainText, "Templates are reusable prompt scaffolds with variable slots")
-	content = append(content, contentStyle.Render("Use {{variable_name}} syntax for substitution"))
-	plainText = append(plainText, "Use {{variable_name}} syntax for substitution")
-	content = append(content, "")
-	plainText = append(plainText, "")
+	if term == "" {
+		for _, section := range helptext.TUISections() {
+			add(headerStyle.Render(section.Title), section.Title)
+			for _, key := range section.Keys {
+				addKey(key.Key, key.Description)
+			}
+			for _, note := range section.Notes {
+				add(contentStyle.Render(note), note)
+			}
+			add("", "")
+		}
+	} else {
+		matches := helptext.Search(term)
+		if len(matches) == 0 {
+			add(contentStyle.Render(i18n.T("help.search_none", term)), i18n.T("help.search_none", term))
+			add("", "")
+		}
 
-	// Boolean Search Examples
-	content = append(content, headerStyle.Render("Boolean Search Examples"))
-	plainText = append(plainText, "Boolean Search Examples")
-	
-	examples := []string{
-		"ai AND writing    - Find prompts tagged with both 'ai' and 'writing'",
-		"code OR python    - Find prompts with either 'code' or 'python' tags",
-		"NOT draft         - Exclude prompts tagged as 'draft'",
-		"(ai OR ml) AND analysis - Complex expressions with parentheses",
-	}
-	
-	for _, example := range examples {
-		content = append(content, contentStyle.Render(example))
-		plainText = append(plainText, example)
-	}
-	content = append(content, "")
-	plainText = append(plainText, "")
+		// TUI help first, under its sections, then the CLI's
+		header := ""
+		for _, match := range matches {
+			if match.Kind != helptext.KindTUI {
+				continue
+			}
+			if match.Topic != header {
+				if header != "" {
+					add("", "")
+				}
+				header = match.Topic
+				add(headerStyle.Render(header), header)
+			}
+			if match.Key != "" {
+				addKey(match.Key, match.Text)
+			} else {
+				add(contentStyle.Render(match.Text), match.Text)
+			}
+		}
+		if header != "" {
+			add("", "")
+		}
 
-	// File Organization
-	content = append(content, headerStyle.Render("File Organization"))
-	plainText = append(plainText, "File Organization")
-	
-	orgInfo := []string{
-		"Storage: ~/.pocket-prompt/ (or POCKET_PROMPT_DIR)",
-		"Prompts: Stored as Markdown files with YAML frontmatter",
-		"Templates: Reusable scaffolds in templates/ directory", 
-		"Archives: Old versions kept in archive/ for history",
-		"Sync: Optional Git integration for backup and collaboration",
-	}
-	
-	for _, info := range orgInfo {
-		content = append(content, contentStyle.Render(info))
-		plainText = append(plainText, info)
-	}
-	content = append(content, "")
-	plainText = append(plainText, "")
+		header = ""
+		for _, match := range matches {
+			if match.Kind != helptext.KindCommand {
+				continue
+			}
+			if header == "" {
+				header = i18n.T("help.search_commands")
+				add(headerStyle.Render(header), header)
+			}
+			add(contentStyle.Render("pkt "+match.Text), "pkt "+match.Text)
+		}
+		if header != "" {
+			add("", "")
+		}
 
-	// Tips
-	content = append(content, headerStyle.Render("Pro Tips"))
-	plainText = append(plainText, "Pro Tips")
-	
-	tips := []string{
-		"• Use descriptive tags for better organization and search",
-		"• Templates save time for similar prompt structures",
-		"• Boolean search is powerful for large prompt libraries",
-		"• JSON copy format works directly with LLM API calls",
-		"• All operations are keyboard-driven for speed",
-		"• Version history preserved when editing prompts",
-	}
-	
-	for _, tip := range tips {
-		content = append(content, contentStyle.Render(tip))
-		plainText = append(plainText, tip)
+		header = ""
+		shown := 0
+		for _, match := range matches {
+			if match.Kind != helptext.KindTopic {
+				continue
+			}
+			if header == "" {
+				add(headerStyle.Render(i18n.T("help.search_topics")), i18n.T("help.search_topics"))
+			}
+			if match.Topic != header {
+				header = match.Topic
+				shown = 0
+				command := "pkt help " + header
+				add(contentStyle.Render(keyStyle.Render(command)), command)
+			}
+			if shown < maxHelpTopicLines {
+				add(contentStyle.Render("  "+match.Text), "  "+match.Text)
+			}
+			shown++
+		}
+		if header != "" {
+			add("", "")
+		}
 	}
-	content = append(content, "")
-	plainText = append(plainText, "")
 
 	// Help text
-	content = append(content, descStyle.Render("Press c to copy • ↑/↓ to scroll • ESC or ? to close"))
+	content = append(content, descStyle.Render(i18n.T("help.footer")))
 	
 	// Add status message if present
 	if m.statusMsg != "" {
@@ -2377,10 +3056,203 @@ func (m *Model) renderHelpModal() string {
 	)
 }
 
+// filterPrompts is fuzzy matching that ignores accents, with matches
+// reported by rune so highlighting lines up in any script
+func filterPrompts(term string, targets []string) []list.Rank {
+	matches := fuzzy.Find(term, targets)
+	ranks := make([]list.Rank, len(matches))
+	for i, m := range matches {
+		ranks[i] = list.Rank{Index: m.Index, MatchedIndexes: m.MatchedIndexes}
+	}
+	return ranks
+}
+
+// promptFilter is the list filter for prompts, the list's items in order,
+// with their search targets from service.SearchTargets. It matches the same
+// text as 'pkt search', ranked the same way: filters in the term such as
+// tag:ai and -tag:draft narrow the prompts, and the rest of the term is fuzzy
+// matched with filterPrompts. Only matches in the title are highlighted.
+func promptFilter(prompts []*models.Prompt, targets []string) list.FilterFunc {
+	return func(term string, _ []string) []list.Rank {
+		query := models.ParseSearchQuery(term)
+		var kept []int
+		var keptTargets []string
+		for i, target := range targets {
+			if query.Matches(prompts[i]) {
+				kept = append(kept, i)
+				keptTargets = append(keptTargets, target)
+			}
+		}
+
+		if query.Text == "" {
+			ranks := make([]list.Rank, len(kept))
+			for i, index := range kept {
+				ranks[i] = list.Rank{Index: index}
+			}
+			return ranks
+		}
+		ranks := filterPrompts(query.Text, keptTargets)
+		for i := range ranks {
+			ranks[i].Index = kept[ranks[i].Index]
+			ranks[i].MatchedIndexes = titleMatches(prompts[ranks[i].Index], ranks[i].MatchedIndexes)
+		}
+		return ranks
+	}
+}
+
+// titleMatches keeps the matched rune positions that fall in the title, which
+// leads a prompt's search target
+func titleMatches(p *models.Prompt, matched []int) []int {
+	title := utf8.RuneCountInString(p.FilterValue())
+	var kept []int
+	for _, i := range matched {
+		if i < title {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
+
+// applyPinnedSearch filters the library with the pinned saved search, as
+// choosing it in the saved searches view does
+func (m *Model) applyPinnedSearch(name string) {
+	savedSearch, err := m.service.GetSavedSearch(name)
+	if err != nil {
+		m.statusMsg = i18n.T("status.pinned_search_missing", name)
+		m.statusTimeout = 3
+		return
+	}
+	results, err := m.service.SearchPromptsByBooleanExpression(savedSearch.Expression)
+	if err != nil {
+		m.statusMsg = i18n.T("status.search_failed", err)
+		m.statusTimeout = 3
+		return
+	}
+
+	m.setPromptItems(results)
+	m.prompts = results
+	m.currentExpression = savedSearch.Expression
+	m.statusMsg = i18n.T("status.pinned_search_applied", name, len(results))
+	m.statusTimeout = 3
+}
+
+// saveSession records the filter, selection and view for the next run
+func (m *Model) saveSession() {
+	if m.currentSource != config.LocalSourceName {
+		return // Another source's prompts are not there at startup
+	}
+	state := &storage.SessionState{View: "library", Expression: m.currentExpression}
+	if m.promptList.FilterState() != list.Unfiltered {
+		state.Filter = m.promptList.FilterValue()
+	}
+	if m.viewMode == ViewPromptDetail && m.selectedPrompt != nil {
+		state.View = "prompt"
+		state.SelectedID = m.selectedPrompt.ID
+		state.Scroll = m.viewport.YOffset
+	} else if p, ok := m.promptList.SelectedItem().(*models.Prompt); ok {
+		state.SelectedID = p.ID
+	}
+	// Nothing to tell anyone on the way out if this fails
+	_ = m.service.SaveSessionState(state)
+}
+
+// restoreSession returns the TUI to where the last session was closed. A
+// pinned search replaces the last boolean search, and a prompt opened from
+// the command line replaces the last view.
+func (m *Model) restoreSession() tea.Cmd {
+	state, err := m.service.SessionState()
+	if err != nil {
+		m.statusMsg = i18n.T("status.warning", err)
+		m.statusTimeout = 3
+		return clearStatusCmd()
+	}
+	if state == nil || m.viewMode != ViewLibrary {
+		return nil
+	}
+
+	if state.Expression != nil && m.service.PinnedSearch() == "" {
+		if results, err := m.service.SearchPromptsByBooleanExpression(state.Expression); err == nil {
+			m.setPromptItems(results)
+			m.prompts = results
+			m.currentExpression = state.Expression
+		}
+	}
+	if state.Filter == "" || len(m.promptList.Items()) == 0 {
+		return m.restoreSelection(state)
+	}
+
+	// Type the filter into the list; its matches arrive as a FilterMatchesMsg
+	m.restoring = state
+	m.loadAllPrompts()
+	var cmds []tea.Cmd
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'/'}},
+		{Type: tea.KeyRunes, Runes: []rune(state.Filter)},
+	} {
+		var cmd tea.Cmd
+		m.promptList, cmd = m.promptList.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// restoreSelection highlights the last session's prompt and reopens it if it
+// was open, scrolled to where it was
+func (m *Model) restoreSelection(state *storage.SessionState) tea.Cmd {
+	m.selectPrompt(state.SelectedID)
+	if state.View != "prompt" || state.SelectedID == "" {
+		return nil
+	}
+
+	prompt, err := m.service.GetPrompt(state.SelectedID)
+	if err != nil {
+		return nil // Deleted since; stay in the library
+	}
+	m.selectedPrompt = prompt
+	m.viewMode = ViewPromptDetail
+	if err := m.renderPreview(); err != nil {
+		m.err = err
+	}
+	m.viewport.SetYOffset(state.Scroll)
+	// Size the preview for the terminal, as switching views does
+	return tea.WindowSize()
+}
+
+// savedSearchOptions lists saved searches for the saved searches view, with
+// their result counts and the pinned search marked
+func (m *Model) savedSearchOptions(searches []models.SavedSearch) []SelectOption {
+	pinned := m.service.PinnedSearch()
+	options := []SelectOption{}
+	for _, search := range searches {
+		// Calculate result count for this search
+		results, err := m.service.SearchPromptsByBooleanExpression(search.Expression)
+		resultCount := 0
+		if err == nil {
+			resultCount = len(results)
+		}
+
+		// Format description with expression and count
+		description := fmt.Sprintf("%s (%d results)", search.Expression.String(), resultCount)
+
+		label := search.Name
+		if search.Name == pinned {
+			label += " (pinned)"
+		}
+		label += m.subscriptionLabel(search.Name)
+		options = append(options, SelectOption{
+			Label:       label,
+			Description: description,
+			Value:       search,
+		})
+	}
+	return options
+}
+
 // refreshPromptList refreshes the prompt list, respecting any active boolean search filter
 func (m *Model) refreshPromptList() error {
 	var prompts []*models.Prompt
 	var err error
+	m.currentSource = config.LocalSourceName
 
 	// If there's an active boolean search expression, apply the filter
 	if m.currentExpression != nil {
@@ -2400,15 +3272,31 @@ func (m *Model) refreshPromptList() error {
 	m.prompts = prompts
 	
 	// Update list items
-	items := make([]list.Item, len(prompts))
-	for i, p := range prompts {
-		items[i] = p
-	}
-	m.promptList.SetItems(items)
+	m.setPromptItems(prompts)
 	
 	return nil
 }
 
+// noteSimilarTitles replaces the status after creating prompt with a longer
+// notice naming the existing prompts, in any pack, whose title is nearly
+// identical, so the user can extend one of them instead
+func (m *Model) noteSimilarTitles(prompt *models.Prompt) {
+	similar := m.service.SimilarTitles(prompt.Title(), prompt.ID)
+	if len(similar) == 0 {
+		return
+	}
+	var names []string
+	for _, other := range similar {
+		name := other.ID
+		if other.Pack != "" {
+			name += " (" + other.Pack + ")"
+		}
+		names = append(names, name)
+	}
+	m.statusMsg = i18n.T("status.similar_title", strings.Join(names, ", "))
+	m.statusTimeout = 6
+}
+
 // refreshPromptListSmart intelligently refreshes the prompt list based on current context
 func (m *Model) refreshPromptListSmart() error {
 	// Check if we're currently showing pack-filtered results
@@ -2424,6 +3312,7 @@ func (m *Model) refreshPromptListSmart() error {
 // refreshPromptListByPacks refreshes the prompt list with prompts from selected packs
 func (m *Model) refreshPromptListByPacks() error {
 	var allPrompts []*models.Prompt
+	m.currentSource = config.LocalSourceName
 	
 	if len(m.selectedPacks) == 0 {
 		// If no packs selected, default to personal
@@ -2464,11 +3353,7 @@ func (m *Model) refreshPromptListByPacks() error {
 	m.prompts = deduplicatedPrompts
 	
 	// Update list items
-	items := make([]list.Item, len(deduplicatedPrompts))
-	for i, p := range deduplicatedPrompts {
-		items[i] = p
-	}
-	m.promptList.SetItems(items)
+	m.setPromptItems(deduplicatedPrompts)
 	
 	return nil
 }
@@ -2481,32 +3366,248 @@ func (m *Model) renderPreview() error {
 
 	// Create a renderer for the prompt
 	r := renderer.NewRenderer(m.selectedPrompt, nil)
+	if !isForeign(m.selectedPrompt) {
+		r.SetAssetDir(m.service.GetBaseDir())
+		// Fill {{git.branch}}, {{file:path}} and the like; never commands
+		r.SetContext(&renderer.Context{})
+	}
+
+	// Fill placeholders from the chosen profile; copies use the same values
+	var variables map[string]interface{}
+	if m.currentProfile != "" {
+		loaded, err := m.service.LoadProfile(m.currentProfile)
+		if err != nil {
+			m.statusMsg = i18n.T("status.profile_not_applied", err)
+			m.statusTimeout = 3
+			m.currentProfile = ""
+		}
+		variables = loaded
+	}
 
-	rendered, err := r.RenderText(nil)
+	// Values last given when rendering the prompt fill what the profile leaves
+	saved := m.service.SavedAnswers(m.selectedPrompt.ID)
+	m.usingSavedAnswers = false
+	for name, value := range saved {
+		if _, ok := variables[name]; ok {
+			continue
+		}
+		if variables == nil {
+			variables = make(map[string]interface{}, len(saved))
+		}
+		variables[name] = value
+		m.usingSavedAnswers = true
+	}
+
+	// Secrets come from env and keychain only for the library's own prompts.
+	// Copies get the real values; the screen shows sensitive ones masked.
+	declared := m.service.DeclaredVariables(m.selectedPrompt, nil)
+	variables, err := m.service.FillVariables(declared, variables, !isForeign(m.selectedPrompt), nil)
+	if err != nil {
+		m.statusMsg = err.Error()
+		m.statusTimeout = 3
+	}
+
+	rendered, err := r.RenderText(variables)
 	if err != nil {
 		// Show the raw content if rendering fails
 		rendered = m.selectedPrompt.Content
 	}
+	display, err := r.RenderText(service.MaskSensitive(declared, variables))
+	if err != nil {
+		display = rendered
+	}
 
 	// Also render as JSON for the 'y' copy option
-	renderedJSON, err := r.RenderJSON(nil)
+	renderedJSON, err := r.RenderJSON(variables)
 	if err != nil {
 		renderedJSON = ""
 	}
 
-	// Format with glamour for display
-	formatted, err := m.glamourRenderer.Render(rendered)
-	if err != nil {
-		formatted = rendered
+	// A different prompt, or new values, start from the whole prompt again
+	if rendered != m.renderedContent {
+		m.section = 0
 	}
-
 	m.renderedContent = rendered
 	m.renderedContentJSON = renderedJSON
-	m.viewport.SetContent(formatted)
+	m.contentStats = service.MeasureText(rendered)
+	// Format the current tab with glamour for display
+	m.setPreview(m.detailMarkdown(display), m.detailTrailer())
 	return nil
 }
 
 
+// attachmentsMarkdown lists the selected prompt's attachments as links to the
+// files. Attachments of prompts from other sources are named but not linked.
+func (m *Model) attachmentsMarkdown() string {
+	if len(m.selectedPrompt.Attachments) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n---\n\n**Attachments**\n\n")
+	for _, attachment := range m.selectedPrompt.Attachments {
+		path, err := m.service.AttachmentPath(attachment)
+		if err != nil || isForeign(m.selectedPrompt) {
+			fmt.Fprintf(&b, "- %s\n", attachment)
+			continue
+		}
+		link := (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+		fmt.Fprintf(&b, "- [%s](%s)\n", attachment, link)
+	}
+	return b.String()
+}
+
+// applyQuickTag adds or removes the tag entered in the quick tag modal,
+// saving the prompt as its next version, and keeps the prompt highlighted
+func (m *Model) applyQuickTag() tea.Cmd {
+	prompt, tag := m.quickTagModal.Prompt(), m.quickTagModal.Tag()
+	var changed bool
+	var err error
+	if m.quickTagModal.Removing() {
+		changed, err = m.service.RemoveTag(prompt.ID, tag)
+	} else {
+		changed, err = m.service.AddTag(prompt.ID, tag)
+	}
+
+	m.statusTimeout = 2
+	switch {
+	case err != nil:
+		m.statusMsg = i18n.T("status.tag_failed", err)
+		m.statusTimeout = 3
+		return clearStatusCmd()
+	case !changed && m.quickTagModal.Removing():
+		m.statusMsg = i18n.T("status.tag_missing", prompt.ID, tag)
+		return clearStatusCmd()
+	case !changed:
+		m.statusMsg = i18n.T("status.tag_present", prompt.ID, tag)
+		return clearStatusCmd()
+	case m.quickTagModal.Removing():
+		m.statusMsg = i18n.T("status.tag_removed", tag, prompt.ID)
+	default:
+		m.statusMsg = i18n.T("status.tag_added", tag, prompt.ID)
+	}
+
+	if err := m.refreshPromptListSmart(); err != nil {
+		m.statusMsg = i18n.T("status.refresh_failed", err)
+		m.statusTimeout = 3
+	}
+	m.selectPrompt(prompt.ID)
+	return clearStatusCmd()
+}
+
+// markedPrompts returns the prompts marked in the library, in list order
+func (m Model) markedPrompts() []*models.Prompt {
+	var prompts []*models.Prompt
+	for _, item := range m.promptList.Items() {
+		if p, ok := item.(*models.Prompt); ok && m.marked[p.ID] {
+			prompts = append(prompts, p)
+		}
+	}
+	return prompts
+}
+
+// applyMovePack moves the prompts chosen in the move to pack modal and lets
+// go of the marks
+func (m *Model) applyMovePack() tea.Cmd {
+	prompts, pack := m.movePackModal.Prompts(), m.movePackModal.Pack()
+	report, err := m.service.MovePrompts(prompts, pack, false)
+	m.statusTimeout = 3
+	switch {
+	case err != nil:
+		m.statusMsg = i18n.T("status.move_failed", err)
+		return clearStatusCmd()
+	case len(report.Skipped) > 0:
+		m.statusMsg = i18n.T("status.moved_skipped", len(report.Moved), pack, len(report.Skipped), report.Skipped[0].ID, report.Skipped[0].Reason)
+	default:
+		m.statusMsg = i18n.T("status.moved", len(report.Moved), pack)
+		m.statusTimeout = 2
+	}
+	clear(m.marked)
+
+	if err := m.refreshPromptListSmart(); err != nil {
+		m.statusMsg = i18n.T("status.refresh_failed", err)
+		m.statusTimeout = 3
+	}
+	return clearStatusCmd()
+}
+
+// recordUsage counts a copy of the selected prompt toward its usage stats.
+// Prompts from other sources are counted by their own library, if at all.
+func (m *Model) recordUsage() {
+	if m.selectedPrompt != nil && !isForeign(m.selectedPrompt) {
+		m.service.RecordUsage(m.selectedPrompt.ID)
+	}
+}
+
+// historyMarkdown summarises each version of the selected prompt, newest
+// first, for the versions tab
+func (m *Model) historyMarkdown() string {
+	var b strings.Builder
+	b.WriteString("**History**\n\n")
+	if isForeign(m.selectedPrompt) {
+		b.WriteString("The history of prompts from other sources is kept by their own library.\n")
+		return b.String()
+	}
+	changes, err := m.service.Changelog(service.ChangelogOptions{ID: m.selectedPrompt.ID})
+	if err != nil {
+		fmt.Fprintf(&b, "No history available: %v\n", err)
+		return b.String()
+	}
+	for _, change := range changes {
+		line := fmt.Sprintf("- **v%s** %s", change.Version, i18n.FormatDate(change.Date.Local()))
+		switch change.Kind {
+		case service.ChangeAdded:
+			line += fmt.Sprintf(" · created, %d lines", change.LinesAdded)
+		default:
+			line += fmt.Sprintf(" · +%d/-%d lines", change.LinesAdded, change.LinesRemoved)
+		}
+		for _, note := range change.Notes {
+			line += " · " + note
+		}
+		if change.Author != "" {
+			line += " · " + change.Author
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// resultsMarkdown lists the outcomes logged for the selected prompt with
+// 'pkt log', newest first, for the usage tab
+func (m *Model) resultsMarkdown() string {
+	if isForeign(m.selectedPrompt) {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n**Results**\n\n")
+	entries, err := m.service.Outcomes(m.selectedPrompt.ID)
+	if err != nil {
+		fmt.Fprintf(&b, "No results available: %v\n", err)
+		return b.String()
+	}
+	if len(entries) == 0 {
+		b.WriteString("No outcomes logged yet. Log one with `pkt log " + m.selectedPrompt.ID + " --outcome good|bad`.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%s\n\n", models.SummarizeOutcomes(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		line := fmt.Sprintf("- **%s** %s", entry.Outcome, i18n.FormatDateTime(entry.Time.Local()))
+		if entry.Version != "" {
+			line += " · v" + entry.Version
+		}
+		if entry.Note != "" {
+			line += " · " + entry.Note
+		}
+		if entry.Author != "" {
+			line += " · " + entry.Author
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
 // renderSavedSearchesView renders the saved searches interface
 func (m Model) renderSavedSearchesView() string {
 	// Create header with consistent styling
@@ -2525,7 +3626,7 @@ func (m Model) renderSavedSearchesView() string {
 	}
 
 	essential := []string{"↑/↓ navigate • enter execute • e edit"}
-	additional := []string{"Ctrl+d delete • Esc back"}
+	additional := []string{"P pin at startup • s subscribe • Ctrl+d delete • Esc back"}
 	help := CreateContextualHelp(essential, additional, m.showExpandedHelp, m.width)
 
 	// Join all elements
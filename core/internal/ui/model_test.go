@@ -0,0 +1,609 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/storage"
+)
+
+func TestPromptFilter(t *testing.T) {
+	prompts := []*models.Prompt{
+		{ID: "a", Name: "Code review", Tags: []string{"code"}},
+		{ID: "b", Name: "Code explainer", Tags: []string{"code", "draft"}},
+		{ID: "c", Name: "Cover letter", Tags: []string{"writing"}},
+	}
+	targets := make([]string, len(prompts))
+	for i, p := range prompts {
+		targets[i] = p.SearchText(config.DefaultSearchFields)
+	}
+	filter := promptFilter(prompts, targets)
+
+	indexes := func(term string) []int {
+		var got []int
+		for _, r := range filter(term, targets) {
+			got = append(got, r.Index)
+		}
+		return got
+	}
+
+	if got := indexes("tag:code -tag:draft"); len(got) != 1 || got[0] != 0 {
+		t.Errorf("tag:code -tag:draft = %v, want [0]", got)
+	}
+	if got := indexes("co -tag:code"); len(got) != 1 || got[0] != 2 {
+		t.Errorf("co -tag:code = %v, want [2]", got)
+	}
+	if got := indexes("title:code* expl"); len(got) != 1 || got[0] != 1 {
+		t.Errorf("title:code* expl = %v, want [1]", got)
+	}
+	if ranks := filter("title:code* expl", targets); len(ranks[0].MatchedIndexes) == 0 {
+		t.Error("Expected the free text to be highlighted in the title")
+	}
+	// Tags are searched as by 'pkt search', but only the title is highlighted
+	if ranks := filter("writing", targets); len(ranks) != 1 || ranks[0].Index != 2 || len(ranks[0].MatchedIndexes) != 0 {
+		t.Errorf("writing = %+v, want prompt 2 matched by its tag", ranks)
+	}
+}
+
+func TestPinnedSearchAppliedOnLoad(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a", Name: "A", Tags: []string{"work"}, Content: "a"},
+		{ID: "b", Name: "B", Tags: []string{"home"}, Content: "b"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	if err := svc.SaveBooleanSearch(models.SavedSearch{Name: "work", Expression: models.NewTagExpression("work")}); err != nil {
+		t.Fatalf("Failed to save search: %v", err)
+	}
+	if err := svc.SetPinnedSearch("work"); err != nil {
+		t.Fatalf("SetPinnedSearch: %v", err)
+	}
+
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	prompts, _ := svc.ListPrompts()
+	updated, _ := model.Update(loadCompleteMsg{prompts: prompts})
+	m := updated.(Model)
+
+	if len(m.prompts) != 1 || m.prompts[0].ID != "a" || m.currentExpression == nil {
+		t.Errorf("Expected the pinned search to show only prompt a, got %d prompts", len(m.prompts))
+	}
+}
+
+func TestSessionRestoredOnLoad(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a", Name: "Alpha", Tags: []string{"work"}, Content: "a"},
+		{ID: "b", Name: "Beta", Tags: []string{"work"}, Content: "b"},
+		{ID: "c", Name: "Gamma", Tags: []string{"home"}, Content: "c"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	prompts, _ := svc.ListPrompts()
+	if err := svc.SaveSessionState(&storage.SessionState{View: "prompt", Filter: "tag:work", SelectedID: "b"}); err != nil {
+		t.Fatalf("SaveSessionState: %v", err)
+	}
+
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	updated, cmd := model.Update(loadCompleteMsg{prompts: prompts})
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(list.FilterMatchesMsg); ok {
+			updated, _ = updated.Update(msg)
+		}
+	}
+	m := updated.(Model)
+
+	if m.promptList.FilterState() != list.FilterApplied || len(m.promptList.VisibleItems()) != 2 {
+		t.Errorf("Expected the tag:work filter applied, got state %v with %d items", m.promptList.FilterState(), len(m.promptList.VisibleItems()))
+	}
+	if m.viewMode != ViewPromptDetail || m.selectedPrompt == nil || m.selectedPrompt.ID != "b" {
+		t.Fatalf("Expected prompt b open, got view %v", m.viewMode)
+	}
+
+	m.saveSession()
+	state, err := svc.SessionState()
+	if err != nil || state.Filter != "tag:work" || state.SelectedID != "b" || state.View != "prompt" {
+		t.Errorf("Saved session = %+v, %v", state, err)
+	}
+}
+
+// runCmd runs cmd and any commands it batches, returning their messages
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmd(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestServerStoppedShownInLibrary(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	model.SetServer(":8080")
+	if view := model.renderLibraryView(); !strings.Contains(view, "Server: listening on :8080") {
+		t.Errorf("Expected the server address in the library view, got:\n%s", view)
+	}
+
+	updated, _ := model.Update(ServerStoppedMsg{Err: errors.New("address already in use")})
+	if view := updated.(Model).renderLibraryView(); !strings.Contains(view, "address already in use") {
+		t.Errorf("Expected why the server stopped in the library view, got:\n%s", view)
+	}
+}
+
+func TestQuickTagFromLibrary(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a", Name: "Alpha", Tags: []string{"work"}, Content: "a"},
+		{ID: "b", Name: "Beta", Tags: []string{"urgent"}, Content: "b"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	prompts, _ := svc.ListPrompts()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(loadCompleteMsg{prompts: prompts})
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			m, _ = m.Update(k)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	before, _ := svc.GetPrompt("a")
+
+	// Add "urgent" to a by completing "ur"
+	press(runes("+"), runes("u"), runes("r"), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEnter})
+	a, _ := svc.GetPrompt("a")
+	if strings.Join(a.Tags, ",") != "work,urgent" || a.Version == before.Version {
+		t.Fatalf("after +urgent a has tags %v at version %s, want work,urgent in a new version", a.Tags, a.Version)
+	}
+	added := a.Version
+	if got := m.(Model).statusMsg; got != "Added tag urgent to a" {
+		t.Errorf("status = %q", got)
+	}
+
+	// Remove "work" by typing a prefix of it
+	press(runes("-"), runes("w"), tea.KeyMsg{Type: tea.KeyEnter})
+	a, _ = svc.GetPrompt("a")
+	if strings.Join(a.Tags, ",") != "urgent" || a.Version == added {
+		t.Errorf("after -work a has tags %v at version %s, want urgent in a new version", a.Tags, a.Version)
+	}
+	removed := a.Version
+	if selected, ok := m.(Model).promptList.SelectedItem().(*models.Prompt); !ok || selected.ID != "a" {
+		t.Errorf("Expected a to stay highlighted, got %v", selected)
+	}
+
+	// Esc leaves the prompt alone
+	press(runes("+"), runes("x"), tea.KeyMsg{Type: tea.KeyEsc})
+	if a, _ = svc.GetPrompt("a"); a.Version != removed {
+		t.Errorf("Esc saved a new version %s", a.Version)
+	}
+}
+
+func TestSavedAnswersFillPreview(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "greet", Name: "Greet", Content: "Hello {{name}}"}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	opts := service.RenderOptions{Variables: map[string]interface{}{"name": "Ada"}, UseAnswers: true, RememberAnswers: true}
+	if _, err := svc.RenderPrompt("greet", opts); err != nil {
+		t.Fatalf("RenderPrompt: %v", err)
+	}
+
+	prompts, _ := svc.ListPrompts()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m, _ = m.Update(loadCompleteMsg{prompts: prompts})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	got := m.(Model)
+	if got.renderedContent != "Hello Ada" {
+		t.Errorf("rendered = %q, want the saved name filled in", got.renderedContent)
+	}
+	if !strings.Contains(got.View(), "Saved answers") {
+		t.Error("preview does not say it uses saved answers")
+	}
+	if !strings.Contains(got.View(), "Words: 2, chars: 9, lines: 1, reading: ~1 min") {
+		t.Error("detail view does not show the length of the prompt as copied")
+	}
+}
+
+func TestSectionNavigation(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	content := "# Task\n\n" + strings.Repeat("Review the change carefully.\n\n", 30) + "# Output format\n\nA table.\n"
+	if err := svc.CreatePrompt(&models.Prompt{ID: "review", Name: "Review", Content: content}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	prompts, _ := svc.ListPrompts()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m, _ = m.Update(loadCompleteMsg{prompts: prompts})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	press := func(r rune) {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	// p from the whole prompt goes to the last section
+	press('p')
+	got := m.(Model)
+	if section, _, ok := got.selectedSection(); !ok || section.Text != "# Output format\n\nA table." {
+		t.Fatalf("selected section = %+v, %v, want Output format", section, ok)
+	}
+	if got.viewport.YOffset == 0 || !strings.Contains(ansi.Strip(got.viewport.View()), "Output format") {
+		t.Errorf("viewport at line %d does not show the section's heading", got.viewport.YOffset)
+	}
+	if !strings.Contains(got.View(), "Section 2/2: Output format") {
+		t.Error("detail view does not name the selected section")
+	}
+
+	// n wraps around to the first section and stays in the detail view
+	press('n')
+	if got = m.(Model); got.section != 1 || got.viewMode != ViewPromptDetail {
+		t.Errorf("after n, section = %d in view %v, want section 1 of the detail view", got.section, got.viewMode)
+	}
+
+	// Esc goes back to the whole prompt, then to the library
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got = m.(Model); got.section != 0 || got.viewMode != ViewPromptDetail {
+		t.Errorf("after esc, section = %d in view %v, want the whole prompt", got.section, got.viewMode)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got = m.(Model); got.viewMode != ViewLibrary {
+		t.Errorf("second esc left view %v, want the library", got.viewMode)
+	}
+}
+
+func TestEditorSpellCheck(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	svc.Settings().UI.SpellCheck = "builtin"
+	if err := svc.CreatePrompt(&models.Prompt{ID: "plan", Name: "Plan", Version: "1.0.0", Content: "Teh plan is to recieve {{teh_input}}."}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	prompts, _ := svc.ListPrompts()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m, _ = m.Update(loadCompleteMsg{prompts: prompts})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if m.(Model).viewMode != ViewEditPrompt {
+		t.Fatalf("view = %v, want the editor", m.(Model).viewMode)
+	}
+
+	// The editor checks its content once typing pauses
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m, cmd = m.Update(editorCheckMsg{seq: m.(Model).editorCheckSeq})
+	if cmd == nil {
+		t.Fatal("no check of the editor's content")
+	}
+	m, _ = m.Update(cmd())
+	if view := ansi.Strip(m.(Model).View()); !strings.Contains(view, "Spelling: Teh → The, recieve → receive") {
+		t.Errorf("editor does not list the misspellings:\n%s", view)
+	}
+
+	// The first Ctrl+s lists the misspellings, the second saves anyway
+	ctrlS := tea.KeyMsg{Type: tea.KeyCtrlS}
+	m, _ = m.Update(ctrlS)
+	if got := m.(Model); got.viewMode != ViewEditPrompt || !strings.Contains(got.statusMsg, "2 possible misspellings: Teh, recieve") {
+		t.Fatalf("first save left view %v with status %q, want the misspellings listed", got.viewMode, got.statusMsg)
+	}
+	m, _ = m.Update(ctrlS)
+	if got := m.(Model); got.viewMode != ViewLibrary {
+		t.Errorf("second save left view %v, want the library", got.viewMode)
+	}
+	if saved, _ := svc.GetPrompt("plan"); saved == nil || saved.Version == "1.0.0" {
+		t.Errorf("prompt not saved: %+v", saved)
+	}
+}
+
+func TestMoveMarkedPromptsToPack(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := service.OpenLibrary(filepath.Join(dir, "library"))
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	team := filepath.Join(dir, "team")
+	os.MkdirAll(filepath.Join(team, "prompts"), 0755)
+	os.WriteFile(filepath.Join(team, "pack.json"), []byte(`{"name": "team", "version": "1.0.0", "title": "Team Pack"}`), 0644)
+	if _, err := svc.InstallPack(team, config.PackInstallOptions{}); err != nil {
+		t.Fatalf("InstallPack: %v", err)
+	}
+	for _, p := range []*models.Prompt{
+		{ID: "a", Name: "Alpha", Content: "a"},
+		{ID: "b", Name: "Beta", Content: "b"},
+		{ID: "c", Name: "Gamma", Content: "c"},
+	} {
+		if err := svc.CreatePrompt(p); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	prompts, _ := svc.ListPrompts()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(loadCompleteMsg{prompts: prompts})
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			m, _ = m.Update(k)
+		}
+	}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	// Mark a and c, skipping b
+	press(space, down, space)
+	if marked := m.(Model).markedPrompts(); len(marked) != 2 || marked[0].ID != "a" || marked[1].ID != "c" {
+		t.Fatalf("Marked %v, want a and c", marked)
+	}
+
+	// The personal library comes first, so the team pack is one down
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")}, down, tea.KeyMsg{Type: tea.KeyEnter})
+	for id, want := range map[string]string{"a": "packs/team/prompts/a.md", "b": "prompts/b.md", "c": "packs/team/prompts/c.md"} {
+		if p, err := svc.GetPrompt(id); err != nil || filepath.ToSlash(p.FilePath) != want {
+			t.Errorf("%s is at %v (%v), want %s", id, p, err, want)
+		}
+	}
+	if got := m.(Model).statusMsg; got != "Moved 2 prompts to team" {
+		t.Errorf("status = %q", got)
+	}
+	if len(m.(Model).marked) != 0 {
+		t.Errorf("Marks kept after the move: %v", m.(Model).marked)
+	}
+}
+
+func TestDeleteTemplateFromTUI(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := service.OpenLibrary(dir)
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	if err := svc.SaveTemplate(&models.Template{ID: "brief", Name: "Brief", Version: "1.0.0", Content: "{{.content}}"}); err != nil {
+		t.Fatalf("Failed to save template: %v", err)
+	}
+	templates, _ := svc.ListTemplates()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(loadCompleteMsg{templates: templates})
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			m, _ = m.Update(k)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	ctrlD := tea.KeyMsg{Type: tea.KeyCtrlD}
+
+	// Open the template from template management, edit it and save
+	press(runes("t"), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter}, runes("e"), tea.KeyMsg{Type: tea.KeyCtrlS})
+	if tpl, err := svc.GetTemplate("brief"); err != nil || tpl.Version != "1.0.1" {
+		t.Fatalf("after saving, template = %+v (%v), want version 1.0.1", tpl, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "archive", "templates", "brief-v1.0.0.md")); err != nil {
+		t.Errorf("replaced version was not archived: %v", err)
+	}
+	if got := m.(Model).viewMode; got != ViewTemplateManagement {
+		t.Fatalf("after saving, view = %v, want template management", got)
+	}
+
+	// The first Ctrl+D only asks for confirmation
+	press(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter}, ctrlD)
+	if _, err := svc.GetTemplate("brief"); err != nil {
+		t.Fatalf("first Ctrl+D deleted the template")
+	}
+	if got := m.(Model).statusMsg; !strings.Contains(got, "brief") {
+		t.Errorf("confirmation = %q, want it to name the template", got)
+	}
+
+	press(ctrlD)
+	if _, err := svc.GetTemplate("brief"); err == nil {
+		t.Fatal("second Ctrl+D did not delete the template")
+	}
+	if got := m.(Model); got.viewMode != ViewTemplateManagement || len(got.templates) != 0 {
+		t.Errorf("after deleting, view = %v with %d templates, want template management with none", got.viewMode, len(got.templates))
+	}
+}
+
+func TestTemplateGalleryFromLibrary(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, tpl := range []*models.Template{
+		{ID: "brief", Name: "Brief", Content: "For {{.audience}}:\n\n{{.content}}", Slots: []models.Slot{{Name: "audience", Example: "engineers"}}},
+		{ID: "memo", Name: "Memo", Content: "MEMO\n\n{{.content}}"},
+	} {
+		if err := svc.SaveTemplate(tpl); err != nil {
+			t.Fatalf("Failed to save template: %v", err)
+		}
+	}
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m, _ = m.Update(loadCompleteMsg{})
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			m, _ = m.Update(k)
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if got := m.(Model).viewMode; got != ViewTemplateGallery {
+		t.Fatalf("view = %v (%q), want the template gallery", got, m.(Model).statusMsg)
+	}
+	view := m.(Model).View()
+	for _, want := range []string{"Brief (1/2)", "audience", "e.g. engineers", "For engineers:", "0 prompts"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("gallery lacks %q:\n%s", want, view)
+		}
+	}
+
+	// Right browses to the next template rather than going back
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	if entry := m.(Model).templateGallery.Selected(); entry == nil || entry.Template.ID != "memo" {
+		t.Errorf("after right, showing %+v, want memo", entry)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := m.(Model).viewMode; got != ViewLibrary {
+		t.Errorf("after Esc, view = %v, want the library", got)
+	}
+}
+
+func TestHelpModalSearch(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(loadCompleteMsg{})
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			m, _ = m.Update(k)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	shown := func() string {
+		current := m.(Model)
+		current.renderHelpModal()
+		return current.modalContent
+	}
+
+	press(runes("?"))
+	if help := shown(); !strings.Contains(help, "Quit application") {
+		t.Fatalf("Expected the full help, got:\n%s", help)
+	}
+
+	press(runes("/"), runes("b"), runes("o"), runes("o"), runes("l"), runes("e"), runes("a"), runes("n"))
+	help := shown()
+	for _, want := range []string{"Ctrl+f Advanced boolean search with tags", "pkt boolean-search", "pkt help boolean-search"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("Search for boolean is missing %q:\n%s", want, help)
+		}
+	}
+	if strings.Contains(help, "Quit application") {
+		t.Errorf("Search for boolean kept help that doesn't mention it:\n%s", help)
+	}
+
+	// Esc clears the search, then closes the modal
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if help := shown(); !m.(Model).showHelpModal || !strings.Contains(help, "Quit application") {
+		t.Errorf("Expected Esc to clear the search, got:\n%s", help)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).showHelpModal {
+		t.Error("Expected a second Esc to close the help")
+	}
+}
+
+func TestQuotaStatus(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	for _, id := range []string{"one", "two"} {
+		if err := svc.CreatePrompt(&models.Prompt{ID: id, Name: id, Version: "1.0.0", Content: "text"}); err != nil {
+			t.Fatalf("Failed to create prompt: %v", err)
+		}
+	}
+	svc.Settings().Quota.MaxPromptsPerPack = 1
+
+	prompts, _ := svc.ListPrompts()
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	var m tea.Model = *model
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m, _ = m.Update(loadCompleteMsg{prompts: prompts})
+	m, _ = m.Update(quotaCheckCmd(svc)())
+	want := "Quota: the personal library has 2 prompts, over its limit of 1 (pkt doctor --size)"
+	if view := ansi.Strip(m.(Model).View()); !strings.Contains(view, want) {
+		t.Errorf("status bar does not warn about the quota:\n%s", view)
+	}
+
+	svc.Settings().Quota.MaxPromptsPerPack = 10
+	m, _ = m.Update(quotaCheckCmd(svc)())
+	if view := ansi.Strip(m.(Model).View()); strings.Contains(view, "Quota:") {
+		t.Errorf("warning stays after the library is within its limits:\n%s", view)
+	}
+}
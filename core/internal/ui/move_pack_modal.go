@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// markDelegate renders the library list, checking off the prompts marked
+// for a bulk action
+type markDelegate struct {
+	list.DefaultDelegate
+	marked map[string]bool // Shared with the model, so cleared rather than replaced
+}
+
+// markedItem shows a marked prompt with a check before its title
+type markedItem struct {
+	*models.Prompt
+}
+
+func (i markedItem) Title() string {
+	return "✓ " + i.Prompt.Title()
+}
+
+func (d markDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if p, ok := item.(*models.Prompt); ok && d.marked[p.ID] {
+		item = markedItem{p}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// MovePackModal asks which pack to move the marked prompts to
+type MovePackModal struct {
+	prompts   []*models.Prompt
+	names     []string // Display names of the packs, sorted
+	packs     map[string]string
+	cursor    int
+	isActive  bool
+	submitted bool
+}
+
+// NewMovePackModal creates a move to pack modal
+func NewMovePackModal() *MovePackModal {
+	return &MovePackModal{}
+}
+
+// Show opens the modal for prompts, offering availablePacks (display name
+// to pack name) as destinations
+func (m *MovePackModal) Show(prompts []*models.Prompt, availablePacks map[string]string) {
+	m.prompts = prompts
+	m.packs = availablePacks
+	m.names = m.names[:0]
+	for name := range availablePacks {
+		m.names = append(m.names, name)
+	}
+	sort.Slice(m.names, func(i, j int) bool {
+		// The personal library first, then the packs by name
+		if pi, pj := availablePacks[m.names[i]] == "personal", availablePacks[m.names[j]] == "personal"; pi != pj {
+			return pi
+		}
+		return m.names[i] < m.names[j]
+	})
+	m.cursor = 0
+	m.submitted = false
+	m.isActive = true
+}
+
+// Hide closes the modal
+func (m *MovePackModal) Hide() {
+	m.isActive = false
+}
+
+// Update handles input for the modal
+func (m *MovePackModal) Update(msg tea.Msg) tea.Cmd {
+	if !m.isActive {
+		return nil
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.Hide()
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.names)-1 {
+				m.cursor++
+			}
+		case "enter":
+			if len(m.names) > 0 {
+				m.submitted = true
+			}
+		}
+	}
+	return nil
+}
+
+// View renders the modal
+func (m *MovePackModal) View() string {
+	if !m.isActive {
+		return ""
+	}
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(1, 2).
+		Width(60)
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	helpStyle := lipgloss.NewStyle().Italic(true).MarginTop(1)
+
+	title := fmt.Sprintf("Move %s to", m.prompts[0].Title())
+	if len(m.prompts) > 1 {
+		title = fmt.Sprintf("Move %d marked prompts to", len(m.prompts))
+	}
+	content := []string{titleStyle.Render(title)}
+	for i, name := range m.names {
+		if i == m.cursor {
+			content = append(content, selectedStyle.Render("> "+name))
+		} else {
+			content = append(content, "  "+name)
+		}
+	}
+	if len(m.names) == 1 {
+		content = append(content, "", hintStyle.Render("No packs are installed (see pkt packs install)"))
+	}
+
+	content = append(content, helpStyle.Render("Enter: move • ↑/↓: choose • Esc: cancel"))
+	return modalStyle.Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+}
+
+// IsActive reports whether the modal is open
+func (m *MovePackModal) IsActive() bool {
+	return m.isActive
+}
+
+// IsSubmitted reports whether a pack was chosen
+func (m *MovePackModal) IsSubmitted() bool {
+	return m.submitted
+}
+
+// Prompts returns the prompts to move
+func (m *MovePackModal) Prompts() []*models.Prompt {
+	return m.prompts
+}
+
+// Pack returns the name of the pack chosen
+func (m *MovePackModal) Pack() string {
+	if len(m.names) == 0 {
+		return ""
+	}
+	return m.packs[m.names[m.cursor]]
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// previewChunkLines is about how many markdown lines go in one chunk of
+	// a long preview. A preview no longer than that renders in place.
+	previewChunkLines = 200
+	// previewCacheWidths is how many widths of rendered output a preview keeps
+	previewCacheWidths = 4
+)
+
+// previewDoc is the markdown shown in the detail view with its glamour output
+// cached per width, so a resize back to a width already seen renders nothing.
+// A long preview is split into chunks that render off the UI loop as they
+// come into view; until then a chunk shows as wrapped plain text.
+type previewDoc struct {
+	markdown string
+	chunks   []string             // Markdown of each chunk, split at blank lines outside code fences
+	rendered map[int][]string     // Width -> glamour output of each chunk, "" until rendered
+	pending  map[int]map[int]bool // Width -> chunks being rendered
+	widths   []int                // Widths in rendered, least recently used first
+	starts   []int                // Line each chunk starts on as last laid out
+	// Shown below the markdown styled as is, for text glamour can't style,
+	// such as a diff with the words that changed highlighted. nil for none.
+	trailer func(width int) string
+}
+
+// previewChunksMsg carries chunks of a long preview rendered off the UI loop
+type previewChunksMsg struct {
+	doc      *previewDoc
+	width    int
+	rendered map[int]string // Chunk -> glamour output
+}
+
+func newPreviewDoc(markdown string) *previewDoc {
+	return &previewDoc{
+		markdown: markdown,
+		chunks:   splitPreview(markdown, previewChunkLines),
+		rendered: make(map[int][]string),
+		pending:  make(map[int]map[int]bool),
+	}
+}
+
+// splitPreview cuts markdown into chunks of at least size lines, ending each
+// at a blank line outside a code fence so every chunk renders on its own
+// much as it would in place
+func splitPreview(markdown string, size int) []string {
+	lines := strings.SplitAfter(markdown, "\n")
+	var chunks []string
+	start, fenced := 0, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		if !fenced && trimmed == "" && i+1-start >= size {
+			chunks = append(chunks, strings.Join(lines[start:i+1], ""))
+			start = i + 1
+		}
+	}
+	if rest := strings.Join(lines[start:], ""); strings.TrimSpace(rest) != "" || len(chunks) == 0 {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
+
+// output returns the rendered chunks for width, evicting the least recently
+// used width when the cache is full
+func (d *previewDoc) output(width int) []string {
+	if i := slices.Index(d.widths, width); i >= 0 {
+		d.widths = append(slices.Delete(d.widths, i, i+1), width)
+		return d.rendered[width]
+	}
+	if len(d.widths) == previewCacheWidths {
+		delete(d.rendered, d.widths[0])
+		delete(d.pending, d.widths[0])
+		d.widths = d.widths[1:]
+	}
+	out := make([]string, len(d.chunks))
+	d.rendered[width] = out
+	d.pending[width] = make(map[int]bool)
+	d.widths = append(d.widths, width)
+	return out
+}
+
+// compose joins the chunks for width, rendered where they are and plain
+// otherwise, and records the line each chunk starts on
+func (d *previewDoc) compose(width int) string {
+	out := d.rendered[width]
+	if len(d.chunks) == 1 {
+		d.starts = []int{0}
+		return out[0]
+	}
+
+	// Glamour frames each chunk in blank lines; keep one between chunks
+	var b strings.Builder
+	b.WriteString("\n")
+	d.starts = make([]int, len(d.chunks))
+	line := 1
+	for i, chunk := range d.chunks {
+		text := out[i]
+		if text == "" {
+			text = plainPreview(chunk, width)
+		}
+		text = strings.Trim(text, "\n")
+		d.starts[i] = line
+		line += strings.Count(text, "\n") + 2
+		b.WriteString(text)
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// locate returns the chunk holding line and how far into it the line is, or
+// -1 before the preview has been laid out
+func (d *previewDoc) locate(line int) (int, int) {
+	for i := len(d.starts) - 1; i >= 0; i-- {
+		if line >= d.starts[i] {
+			return i, line - d.starts[i]
+		}
+	}
+	return -1, 0
+}
+
+// plainPreview is the fallback shown for a chunk until glamour renders it
+func plainPreview(markdown string, width int) string {
+	return lipgloss.NewStyle().Width(width).Padding(0, 2).Render(strings.Trim(markdown, "\n"))
+}
+
+// setPreview shows markdown in the detail view, followed by trailer when it
+// isn't nil, reusing what was rendered of the markdown when it is the
+// markdown already shown
+func (m *Model) setPreview(markdown string, trailer func(width int) string) {
+	if m.preview == nil || m.preview.markdown != markdown {
+		m.preview = newPreviewDoc(markdown)
+	}
+	m.preview.trailer = trailer
+	m.layoutPreview()
+}
+
+// layoutPreview puts the preview at the current width in the viewport. A
+// short preview renders in place; a long one shows what is rendered so far,
+// keeping the line at the top of the viewport in place as chunks above it
+// change length.
+func (m *Model) layoutPreview() {
+	doc, width := m.preview, m.glamourWidth
+	out := doc.output(width)
+	if len(doc.chunks) == 1 && out[0] == "" {
+		formatted, err := m.glamourRenderer.Render(doc.chunks[0])
+		if err != nil {
+			formatted = doc.chunks[0]
+		}
+		out[0] = formatted
+	}
+
+	anchor, within := doc.locate(m.viewport.YOffset)
+	content := doc.compose(width)
+	if doc.trailer != nil {
+		content = strings.TrimRight(content, "\n") + "\n\n" + doc.trailer(width) + "\n"
+	}
+	m.viewport.SetContent(content)
+	if anchor >= 0 && len(doc.chunks) > 1 {
+		m.viewport.SetYOffset(doc.starts[anchor] + within)
+	}
+}
+
+// applyPreviewChunks stores chunks rendered off the UI loop, dropping them
+// when the preview has changed since
+func (m *Model) applyPreviewChunks(msg previewChunksMsg) {
+	doc := m.preview
+	if doc == nil || msg.doc != doc {
+		return
+	}
+	out, ok := doc.rendered[msg.width]
+	if !ok {
+		return
+	}
+	for i, rendered := range msg.rendered {
+		out[i] = rendered
+		delete(doc.pending[msg.width], i)
+	}
+	if msg.width == m.glamourWidth && m.viewMode == ViewPromptDetail {
+		m.layoutPreview()
+	}
+}
+
+// previewRenderCmd renders the chunks of a long preview from a screen above
+// the viewport to a screen below it that are not rendered at the current
+// width, or returns nil when there are none
+func (m *Model) previewRenderCmd() tea.Cmd {
+	doc, width := m.preview, m.glamourWidth
+	if m.viewMode != ViewPromptDetail || doc == nil || len(doc.chunks) < 2 || len(doc.starts) != len(doc.chunks) {
+		return nil
+	}
+	out, ok := doc.rendered[width]
+	if !ok {
+		return nil
+	}
+	pending := doc.pending[width]
+	top := m.viewport.YOffset - m.viewport.Height
+	bottom := m.viewport.YOffset + 2*m.viewport.Height
+	var wanted []int
+	for i, start := range doc.starts {
+		end := m.viewport.TotalLineCount()
+		if i+1 < len(doc.starts) {
+			end = doc.starts[i+1]
+		}
+		if out[i] == "" && !pending[i] && start < bottom && end > top {
+			wanted = append(wanted, i)
+			pending[i] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		msg := previewChunksMsg{doc: doc, width: width, rendered: make(map[int]string, len(wanted))}
+		renderer, err := createGlamourRenderer(width)
+		for _, i := range wanted {
+			formatted := ""
+			if err == nil {
+				formatted, _ = renderer.Render(doc.chunks[i])
+			}
+			if strings.TrimSpace(formatted) == "" {
+				// Keep the plain text rather than render the chunk again
+				formatted = plainPreview(doc.chunks[i], width)
+			}
+			msg.rendered[i] = formatted
+		}
+		return msg
+	}
+}
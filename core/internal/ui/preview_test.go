@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestSplitPreviewKeepsCodeFencesWhole(t *testing.T) {
+	markdown := "one\n\ntwo\n```\nfirst\n\nsecond\n```\n\nthree\n"
+	chunks := splitPreview(markdown, 2)
+	if strings.Join(chunks, "") != markdown {
+		t.Fatalf("chunks %q do not add up to the markdown", chunks)
+	}
+	for _, chunk := range chunks {
+		if strings.Count(chunk, "```")%2 != 0 {
+			t.Errorf("chunk %q splits a code fence", chunk)
+		}
+	}
+	if len(chunks) != 3 {
+		t.Errorf("chunks = %q, want 3", chunks)
+	}
+}
+
+func TestLongPreviewRendersLazily(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	var content strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&content, "Paragraph %d of a long prompt.\n\n", i)
+	}
+	if err := svc.CreatePrompt(&models.Prompt{ID: "long", Name: "Long", Content: content.String()}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	if err := model.OpenPrompt("long"); err != nil {
+		t.Fatalf("OpenPrompt: %v", err)
+	}
+	m, cmd := resizeWindow(*model, 80, 40)
+	width := m.glamourWidth
+
+	// The plain text shows at once and the first chunk renders off the UI loop
+	if len(m.preview.chunks) < 2 || m.preview.rendered[width][0] != "" {
+		t.Fatalf("Expected a long preview in unrendered chunks, got %d chunks", len(m.preview.chunks))
+	}
+	if !strings.Contains(m.viewport.View(), "Paragraph 0 of a long prompt.") {
+		t.Error("Expected the plain-text fallback in the viewport")
+	}
+	msg, ok := findPreviewChunks(cmd)
+	if !ok {
+		t.Fatal("Expected the visible chunk to be rendered")
+	}
+	if m.previewRenderCmd() != nil {
+		t.Error("Expected a chunk being rendered not to be requested again")
+	}
+	if _, ok := msg.rendered[0]; !ok || len(msg.rendered) == len(m.preview.chunks) {
+		t.Errorf("Expected only the chunks near the viewport, got %d of %d", len(msg.rendered), len(m.preview.chunks))
+	}
+
+	// A result for a preview no longer shown is dropped
+	m.applyPreviewChunks(previewChunksMsg{doc: newPreviewDoc("other"), width: width, rendered: map[int]string{1: "stale"}})
+	if m.preview.rendered[width][1] == "stale" {
+		t.Error("Expected a stale chunk to be dropped")
+	}
+
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	first := m.preview.rendered[width][0]
+	if first == "" {
+		t.Fatal("Expected the first chunk to be rendered")
+	}
+
+	// Resizing back to a width already rendered reuses the output
+	m, _ = resizeWindow(m, 120, 40)
+	m, _ = resizeWindow(m, 80, 40)
+	if m.glamourWidth != width || m.preview.rendered[width][0] != first {
+		t.Error("Expected the rendered chunk to be kept for its width")
+	}
+	if m.previewRenderCmd() != nil {
+		t.Error("Expected nothing to render at a width already rendered")
+	}
+}
+
+// findPreviewChunks runs cmd and the commands it batches for rendered chunks
+func findPreviewChunks(cmd tea.Cmd) (previewChunksMsg, bool) {
+	if cmd == nil {
+		return previewChunksMsg{}, false
+	}
+	switch msg := cmd().(type) {
+	case previewChunksMsg:
+		return msg, true
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if found, ok := findPreviewChunks(c); ok {
+				return found, true
+			}
+		}
+	}
+	return previewChunksMsg{}, false
+}
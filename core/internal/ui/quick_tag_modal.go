@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// maxTagMatches is how many matching tags the modal lists under the input
+const maxTagMatches = 6
+
+// QuickTagModal asks for one tag to add to or remove from the prompt
+// highlighted in the library, without opening the edit form
+type QuickTagModal struct {
+	input     textinput.Model
+	prompt    *models.Prompt
+	removing  bool
+	choices   []string // Tags to suggest: the library's for adding, the prompt's own for removing
+	isActive  bool
+	submitted bool
+}
+
+// NewQuickTagModal creates a quick tag modal
+func NewQuickTagModal() *QuickTagModal {
+	input := textinput.New()
+	input.CharLimit = 50
+	input.Width = 40
+	input.ShowSuggestions = true
+
+	keyMap := textinput.DefaultKeyMap
+	keyMap.AcceptSuggestion = key.NewBinding(key.WithKeys("tab", "ctrl+space", "right"))
+	input.KeyMap = keyMap
+
+	return &QuickTagModal{input: input}
+}
+
+// Show opens the modal for prompt. Adding suggests libraryTags the prompt
+// doesn't have yet; removing suggests the tags stored in the prompt's file.
+func (m *QuickTagModal) Show(prompt *models.Prompt, removing bool, libraryTags []string) {
+	m.prompt = prompt
+	m.removing = removing
+	m.submitted = false
+	m.isActive = true
+
+	if removing {
+		m.choices = prompt.StoredTags()
+		m.input.Placeholder = "Tag to remove"
+	} else {
+		m.choices = nil
+		for _, tag := range libraryTags {
+			if !slices.Contains(prompt.Tags, tag) {
+				m.choices = append(m.choices, tag)
+			}
+		}
+		m.input.Placeholder = "Tag to add"
+	}
+	m.input.SetSuggestions(m.choices)
+	m.input.SetValue("")
+	m.input.Focus()
+}
+
+// Hide closes the modal
+func (m *QuickTagModal) Hide() {
+	m.isActive = false
+	m.input.Blur()
+}
+
+// Update handles input for the modal
+func (m *QuickTagModal) Update(msg tea.Msg) tea.Cmd {
+	if !m.isActive {
+		return nil
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.Hide()
+			return nil
+		case "enter":
+			if m.Tag() != "" {
+				m.submitted = true
+			}
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
+
+// Tag returns the tag to add or remove. When removing, a prefix of one of
+// the prompt's tags stands for the suggested tag.
+func (m *QuickTagModal) Tag() string {
+	tag := strings.TrimSpace(m.input.Value())
+	if m.removing && tag != "" && !slices.Contains(m.choices, tag) {
+		if suggestion := m.input.CurrentSuggestion(); suggestion != "" {
+			return suggestion
+		}
+	}
+	return tag
+}
+
+// matches lists the suggested tags starting with what has been typed
+func (m *QuickTagModal) matches() []string {
+	typed := strings.ToLower(strings.TrimSpace(m.input.Value()))
+	var matches []string
+	for _, tag := range m.choices {
+		if strings.HasPrefix(strings.ToLower(tag), typed) {
+			matches = append(matches, tag)
+		}
+	}
+	return matches
+}
+
+// View renders the modal
+func (m *QuickTagModal) View() string {
+	if !m.isActive {
+		return ""
+	}
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(1, 2).
+		Width(60)
+	titleStyle := lipgloss.NewStyle().Bold(true).MarginBottom(1)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	helpStyle := lipgloss.NewStyle().Italic(true).MarginTop(1)
+
+	title := fmt.Sprintf("Add tag to %s", m.prompt.Title())
+	if m.removing {
+		title = fmt.Sprintf("Remove tag from %s", m.prompt.Title())
+	}
+	content := []string{titleStyle.Render(title), m.input.View(), ""}
+
+	switch matches := m.matches(); {
+	case m.removing && len(m.choices) == 0:
+		content = append(content, hintStyle.Render("This prompt has no tags to remove"))
+	case len(matches) > maxTagMatches:
+		content = append(content, hintStyle.Render(strings.Join(matches[:maxTagMatches], "  ")+fmt.Sprintf("  +%d more", len(matches)-maxTagMatches)))
+	case len(matches) > 0:
+		content = append(content, hintStyle.Render(strings.Join(matches, "  ")))
+	case m.removing:
+		content = append(content, hintStyle.Render("No tag of this prompt matches"))
+	default:
+		content = append(content, hintStyle.Render("New tag"))
+	}
+
+	content = append(content, helpStyle.Render("Enter: save • Tab/→: complete • ↑/↓: cycle • Esc: cancel"))
+	return modalStyle.Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+}
+
+// IsActive reports whether the modal is open
+func (m *QuickTagModal) IsActive() bool {
+	return m.isActive
+}
+
+// IsSubmitted reports whether a tag was entered
+func (m *QuickTagModal) IsSubmitted() bool {
+	return m.submitted
+}
+
+// Prompt returns the prompt being tagged
+func (m *QuickTagModal) Prompt() *models.Prompt {
+	return m.prompt
+}
+
+// Removing reports whether the tag is to be removed rather than added
+func (m *QuickTagModal) Removing() bool {
+	return m.removing
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// quotaCheckedMsg carries the library's soft limit warnings
+type quotaCheckedMsg struct {
+	warnings []string
+	err      error
+}
+
+// quotaCheckCmd measures the library against its soft limits without
+// blocking the TUI
+func quotaCheckCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		warnings, err := svc.QuotaWarnings()
+		return quotaCheckedMsg{warnings: warnings, err: err}
+	}
+}
+
+// handleQuotaChecked keeps the first warning in the status bar until a later
+// check finds the library within its limits
+func (m Model) handleQuotaChecked(msg quotaCheckedMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.err != nil:
+		m.quotaStatus = ""
+		m.statusMsg = i18n.T("status.warning", msg.err)
+		m.statusTimeout = 5
+		return m, clearStatusCmd()
+	case len(msg.warnings) == 0:
+		m.quotaStatus = ""
+	case len(msg.warnings) == 1:
+		m.quotaStatus = i18n.T("status.quota", msg.warnings[0])
+	default:
+		m.quotaStatus = i18n.T("status.quota_more", msg.warnings[0], len(msg.warnings)-1)
+	}
+	return m, nil
+}
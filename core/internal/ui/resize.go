@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+)
+
+const (
+	// resizeDebounce is how long the window size must hold before the
+	// preview is re-rendered, so dragging a window edge renders once
+	resizeDebounce = 100 * time.Millisecond
+	// maxGlamourRenderers is how many widths of glamour renderer are kept
+	maxGlamourRenderers = 8
+)
+
+// resizeSettledMsg fires resizeDebounce after a window resize; only the one
+// for the latest resize re-renders
+type resizeSettledMsg struct {
+	seq int
+}
+
+// scheduleResize starts the wait for the window size to settle, superseding
+// any earlier wait
+func (m *Model) scheduleResize() tea.Cmd {
+	m.resizeSeq++
+	seq := m.resizeSeq
+	return tea.Tick(resizeDebounce, func(time.Time) tea.Msg {
+		return resizeSettledMsg{seq: seq}
+	})
+}
+
+// settleResize re-renders the preview at the viewport's width once the
+// window size has stopped changing
+func (m *Model) settleResize(msg resizeSettledMsg) {
+	if msg.seq != m.resizeSeq || m.viewMode != ViewPromptDetail {
+		return
+	}
+	if width := m.viewport.Width; width > 0 && width != m.glamourWidth {
+		if renderer, err := m.glamourRendererFor(width); err == nil {
+			m.glamourRenderer = renderer
+			m.glamourWidth = width
+		}
+	}
+	if m.selectedPrompt != nil {
+		m.renderPreview()
+	}
+}
+
+// glamourRendererFor returns a renderer wrapping at width, made once per width
+func (m *Model) glamourRendererFor(width int) (*glamour.TermRenderer, error) {
+	if renderer, ok := m.renderers[width]; ok {
+		return renderer, nil
+	}
+	renderer, err := createGlamourRenderer(width)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.renderers) >= maxGlamourRenderers {
+		clear(m.renderers)
+	}
+	m.renderers[width] = renderer
+	return renderer, nil
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dpshade/pocket-prompt/internal/models"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+func TestResizeStormRendersOnce(t *testing.T) {
+	svc, err := service.OpenLibrary(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open library: %v", err)
+	}
+	content := "A paragraph long enough to wrap differently at every width the window passes through while it is dragged."
+	if err := svc.CreatePrompt(&models.Prompt{ID: "wrap", Name: "Wrap", Content: content}); err != nil {
+		t.Fatalf("Failed to create prompt: %v", err)
+	}
+	model, err := NewModel(svc)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	if err := model.OpenPrompt("wrap"); err != nil {
+		t.Fatalf("OpenPrompt: %v", err)
+	}
+	m, _ := resizeWindow(*model, 80, 40)
+
+	// Dragging the window edge resizes the viewport but renders nothing
+	var stale []resizeSettledMsg
+	for width := 81; width <= 120; width++ {
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: 40})
+		m = updated.(Model)
+		stale = append(stale, resizeSettledMsg{seq: m.resizeSeq})
+	}
+	if m.viewport.Width != 100 || m.glamourWidth != 60 || len(m.preview.widths) != 1 {
+		t.Errorf("Expected the preview to wait for the size to settle, wrapped at %d", m.glamourWidth)
+	}
+	for _, msg := range stale[:len(stale)-1] {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	if m.glamourWidth != 60 {
+		t.Errorf("Expected superseded resizes not to re-render, wrapped at %d", m.glamourWidth)
+	}
+
+	updated, _ := m.Update(stale[len(stale)-1])
+	m = updated.(Model)
+	if m.glamourWidth != 100 || !strings.Contains(m.viewport.View(), "A paragraph") {
+		t.Errorf("Expected the preview re-rendered at 100 once settled, wrapped at %d", m.glamourWidth)
+	}
+
+	// Returning to a width reuses its renderer
+	renderer := m.renderers[60]
+	m, _ = resizeWindow(m, 80, 40)
+	if m.glamourRenderer != renderer || len(m.renderers) != 2 {
+		t.Errorf("Expected the renderer for width 60 to be reused, have %d renderers", len(m.renderers))
+	}
+}
+
+// resizeWindow resizes m and lets the size settle, returning the commands
+// of both steps
+func resizeWindow(m Model, width, height int) (Model, tea.Cmd) {
+	updated, resized := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	m = updated.(Model)
+	updated, settled := m.Update(resizeSettledMsg{seq: m.resizeSeq})
+	return updated.(Model), tea.Batch(resized, settled)
+}
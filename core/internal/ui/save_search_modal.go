@@ -95,49 +95,10 @@ func (m *SaveSearchModal) SetAvailableTags(tags []string) {
 	}
 }
 
-// parseQuery parses a simple boolean query string into an expression
+// parseQuery parses a boolean query string into an expression, as every
+// other interface does
 func (m *SaveSearchModal) parseQuery(query string) (*models.BooleanExpression, error) {
-	// Import parseQuery logic from boolean_modal.go
-	query = strings.TrimSpace(query)
-	
-	// Handle NOT operations first
-	if strings.HasPrefix(strings.ToUpper(query), "NOT ") {
-		inner := strings.TrimSpace(query[4:])
-		innerExpr, err := m.parseQuery(inner)
-		if err != nil {
-			return nil, err
-		}
-		return models.NewNotExpression(innerExpr), nil
-	}
-	
-	// Split by OR (lower precedence)
-	if orParts := strings.Split(query, " OR "); len(orParts) > 1 {
-		var expressions []*models.BooleanExpression
-		for _, part := range orParts {
-			expr, err := m.parseQuery(strings.TrimSpace(part))
-			if err != nil {
-				return nil, err
-			}
-			expressions = append(expressions, expr)
-		}
-		return models.NewOrExpression(expressions...), nil
-	}
-	
-	// Split by AND (higher precedence)
-	if andParts := strings.Split(query, " AND "); len(andParts) > 1 {
-		var expressions []*models.BooleanExpression
-		for _, part := range andParts {
-			expr, err := m.parseQuery(strings.TrimSpace(part))
-			if err != nil {
-				return nil, err
-			}
-			expressions = append(expressions, expr)
-		}
-		return models.NewAndExpression(expressions...), nil
-	}
-	
-	// Single tag
-	return models.NewTagExpression(query), nil
+	return models.ParseBooleanExpression(query)
 }
 
 // Update handles input for the modal
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/renderer"
+)
+
+// selectedSection returns the section of the selected prompt that copies
+// take, or false when they take the whole prompt
+func (m *Model) selectedSection() (renderer.Section, int, bool) {
+	if m.section == 0 {
+		return renderer.Section{}, 0, false
+	}
+	sections := renderer.Sections(m.renderedContent)
+	if m.section > len(sections) {
+		return renderer.Section{}, 0, false
+	}
+	return sections[m.section-1], len(sections), true
+}
+
+// moveSection selects the section delta away from the current one, wrapping
+// around, and scrolls the content tab to its heading. From the whole prompt,
+// the next section is the first and the previous one the last.
+func (m *Model) moveSection(delta int) {
+	sections := renderer.Sections(m.renderedContent)
+	n := len(sections)
+	if n == 0 {
+		m.statusMsg = i18n.T("status.no_sections")
+		m.statusTimeout = 2
+		return
+	}
+	if m.section == 0 || m.section > n {
+		if delta > 0 {
+			m.section = 1
+		} else {
+			m.section = n
+		}
+	} else {
+		m.section = ((m.section-1+delta)%n+n)%n + 1
+	}
+	m.showDetailTab(tabContent)
+	m.scrollToSection(sections)
+}
+
+// scrollToSection puts the heading of the selected section at the top of the
+// viewport, finding each heading in turn in the preview so repeated titles
+// land on the right one. Headings glamour wrapped are not found and leave
+// the viewport where it is.
+func (m *Model) scrollToSection(sections []renderer.Section) {
+	if m.preview == nil {
+		return
+	}
+	lines := strings.Split(ansi.Strip(m.preview.compose(m.glamourWidth)), "\n")
+	line := 0
+	for i := 0; i < m.section; i++ {
+		if i > 0 {
+			line++
+		}
+		for line < len(lines) && !strings.Contains(lines[line], sections[i].Title) {
+			line++
+		}
+		if line == len(lines) {
+			return
+		}
+	}
+	m.viewport.SetYOffset(line)
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/lint"
+	"github.com/dpshade/pocket-prompt/internal/spellcheck"
+)
+
+const (
+	// editorCheckDebounce is how long typing in the prompt editor must pause
+	// before its content is checked again
+	editorCheckDebounce = 400 * time.Millisecond
+	// maxEditorHints is how many misspellings or style hints are listed
+	maxEditorHints = 6
+)
+
+// editorCheckMsg fires editorCheckDebounce after the editor's content
+// changed; only the one for the latest change checks it
+type editorCheckMsg struct {
+	seq int
+}
+
+// editorCheckedMsg carries what checking the editor's content found
+type editorCheckedMsg struct {
+	content      string
+	misspellings []spellcheck.Misspelling
+	styleIssues  []lint.Issue
+}
+
+// afterEditorUpdate starts the wait to check the prompt editor's content
+// when it has changed, or when the editor has just opened. A change also
+// takes back a "save anyway".
+func (m *Model) afterEditorUpdate(before string) tea.Cmd {
+	f := m.createForm
+	if f == nil || (m.spellChecker == nil && m.linter == nil) {
+		return nil
+	}
+	if f.textarea.Value() != before {
+		if f.saveAnyway {
+			f.saveAnyway = false
+			m.statusMsg = ""
+		}
+	} else if f.checkScheduled {
+		return nil
+	}
+	f.checkScheduled = true
+	m.editorCheckSeq++
+	seq := m.editorCheckSeq
+	return tea.Tick(editorCheckDebounce, func(time.Time) tea.Msg {
+		return editorCheckMsg{seq: seq}
+	})
+}
+
+// checkEditorCmd checks the editor's content off the UI loop, since aspell
+// and hunspell run as commands
+func (m *Model) checkEditorCmd(msg editorCheckMsg) tea.Cmd {
+	if msg.seq != m.editorCheckSeq || m.createForm == nil {
+		return nil
+	}
+	content := m.createForm.textarea.Value()
+	checker, linter := m.spellChecker, m.linter
+	return func() tea.Msg {
+		checked := editorCheckedMsg{content: content}
+		if checker != nil {
+			checked.misspellings, _ = checker.Check(content)
+		}
+		if linter != nil {
+			checked.styleIssues = linter.Check(content)
+		}
+		return checked
+	}
+}
+
+// applyEditorCheck shows what a check found, unless the content has
+// changed since
+func (m *Model) applyEditorCheck(msg editorCheckedMsg) {
+	if m.createForm == nil || m.createForm.textarea.Value() != msg.content {
+		return
+	}
+	m.createForm.misspellings = msg.misspellings
+	m.createForm.styleIssues = msg.styleIssues
+}
+
+// confirmSpelling reports whether the editor's content may be saved: it has
+// no misspellings, or Ctrl+s was pressed again to save it anyway. Otherwise
+// the status line lists them and asks.
+func (m *Model) confirmSpelling() bool {
+	f := m.createForm
+	if f == nil || m.spellChecker == nil || f.saveAnyway {
+		return true
+	}
+	misspellings, err := m.spellChecker.Check(f.textarea.Value())
+	if err != nil || len(misspellings) == 0 {
+		return true
+	}
+	f.misspellings = misspellings
+	f.saveAnyway = true
+	words := make([]string, 0, len(misspellings))
+	for _, misspelling := range misspellings {
+		words = append(words, misspelling.Word)
+	}
+	m.statusMsg = i18n.T("status.misspellings", len(misspellings), truncateList(words, maxEditorHints))
+	m.statusTimeout = 100 // Keep showing until the next save or edit clears it
+	return false
+}
+
+// hintsView lists the misspellings and style hints found in the editor's
+// content, or is empty when there are none
+func (f *CreateForm) hintsView() string {
+	var lines []string
+	if len(f.misspellings) > 0 {
+		misspelled := lipgloss.NewStyle().Foreground(ColorError).Underline(true)
+		words := make([]string, 0, len(f.misspellings))
+		for _, misspelling := range f.misspellings {
+			word := misspelled.Render(misspelling.Word)
+			if misspelling.Suggestion != "" {
+				word += StyleTextDim.Render(" → " + misspelling.Suggestion)
+			}
+			words = append(words, word)
+		}
+		lines = append(lines, StyleFormLabel.Render(i18n.T("prompt.spelling"))+" "+truncateList(words, maxEditorHints))
+	}
+	for i, issue := range f.styleIssues {
+		if i == maxEditorHints {
+			lines = append(lines, StyleTextDim.Render(fmt.Sprintf("  … %d more", len(f.styleIssues)-i)))
+			break
+		}
+		hint := issue.Message
+		if issue.Line > 0 {
+			hint = fmt.Sprintf("line %d: %s", issue.Line, hint)
+		}
+		lines = append(lines, StyleTextDim.Render(i18n.T("prompt.style_hint", hint)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncateList joins items with commas, naming how many more there are past
+// the first max
+func truncateList(items []string, max int) string {
+	if len(items) <= max {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s, … (%d more)", strings.Join(items[:max], ", "), len(items)-max)
+}
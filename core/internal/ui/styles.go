@@ -6,6 +6,8 @@ import (
 	"strings"
 	
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dpshade/pocket-prompt/internal/diff"
 )
 
 // Design System Colors - Adaptive based on terminal background
@@ -31,15 +33,31 @@ var (
 	ColorOverlay    lipgloss.Color
 )
 
-// initializeColors sets up adaptive colors based on terminal background
-func initializeColors() {
-	// Check for environment variable override
-	if os.Getenv("GLAMOUR_STYLE") == "light" {
+// theme is the configured ui.theme: "light", "dark", or "auto"/"" to follow
+// GLAMOUR_STYLE or the terminal background
+var theme string
+
+// forcedStyle returns the style set by the configured theme or GLAMOUR_STYLE,
+// or "" to detect it
+func forcedStyle() string {
+	if theme == "light" || theme == "dark" {
+		return theme
+	}
+	return os.Getenv("GLAMOUR_STYLE")
+}
+
+// initializeColors sets up adaptive colors based on the configured theme
+// or the terminal background
+func initializeColors(configured string) {
+	theme = configured
+
+	// Check for a theme or environment variable override
+	if forcedStyle() == "light" {
 		// Force light theme
 		setLightThemeColors()
 		return
 	}
-	if os.Getenv("GLAMOUR_STYLE") == "dark" {
+	if forcedStyle() == "dark" {
 		// Force dark theme  
 		setDarkThemeColors()
 		return
@@ -409,11 +427,66 @@ func CreateOption(label, description string, isSelected bool) []string {
 	return lines
 }
 
+// CreateTabs shows a row of numbered tabs with the active one highlighted
+func CreateTabs(labels []string, active int) string {
+	tabs := make([]string, len(labels))
+	for i, label := range labels {
+		text := fmt.Sprintf("%d %s", i+1, label)
+		if i == active {
+			tabs[i] = StyleSelected.Render(text)
+		} else {
+			tabs[i] = StyleUnselected.Render(text)
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
+
 // Git status styling
 func CreateGitStatus(status string) string {
 	return StyleMetadata.Render("Git: " + status)
 }
 
+// CreateServerStatus shows the state of the URL server running alongside the TUI
+func CreateServerStatus(status string) string {
+	return StyleMetadata.Render("Server: " + status)
+}
+
+// CreateQuotaStatus warns that the library is over or close to a soft limit
+func CreateQuotaStatus(status string) string {
+	return StyleWarning.Render("⚠ " + status)
+}
+
+// CreateSubscriptionStatus is the badge counting new matches of subscribed
+// saved searches
+func CreateSubscriptionStatus(status string) string {
+	return StyleInfo.Render("● " + status)
+}
+
+// CreateReadOnlyStatus shows that the library refuses changes, as with
+// --read-only or --demo
+func CreateReadOnlyStatus(status string) string {
+	return StyleWarning.Render(status)
+}
+
+// DiffStyle colors diffs: added lines green, removed lines red, unchanged
+// lines dim and notes bold, with the words that changed within a line reversed
+func DiffStyle() diff.Style {
+	render := func(style lipgloss.Style) func(string) string {
+		return func(text string) string { return style.Render(text) }
+	}
+	added := lipgloss.NewStyle().Foreground(ColorSuccess)
+	removed := lipgloss.NewStyle().Foreground(ColorError)
+	return diff.Style{
+		Added:       render(added),
+		Removed:     render(removed),
+		Kept:        render(StyleTextDim),
+		Note:        render(StyleText.Bold(true)),
+		AddedWord:   render(added.Reverse(true)),
+		RemovedWord: render(removed.Reverse(true)),
+		Gap:         func(int) string { return StyleTextDim.Render("  ...") },
+	}
+}
+
 // Search indicator styling
 func CreateSearchIndicator(expression string, count int) string {
 	text := lipgloss.JoinHorizontal(
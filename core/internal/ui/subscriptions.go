@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// subscriptionsCheckedMsg carries the new matches of subscribed searches
+type subscriptionsCheckedMsg struct {
+	searches []service.SearchMatches
+	err      error
+}
+
+// subscriptionsCheckCmd looks up the new matches of subscribed searches
+// without blocking the TUI
+func subscriptionsCheckCmd(svc *service.Service) tea.Cmd {
+	return func() tea.Msg {
+		searches, err := svc.AllNewMatches()
+		return subscriptionsCheckedMsg{searches: searches, err: err}
+	}
+}
+
+// handleSubscriptionsChecked keeps a badge with the new matches above the
+// library until they are acknowledged
+func (m Model) handleSubscriptionsChecked(msg subscriptionsCheckedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.subscriptionStatus = ""
+		m.statusMsg = i18n.T("status.warning", msg.err)
+		m.statusTimeout = 5
+		return m, clearStatusCmd()
+	}
+
+	m.newMatches = map[string]int{}
+	total := 0
+	for _, search := range msg.searches {
+		m.newMatches[search.Search] = len(search.Matches)
+		total += len(search.Matches)
+	}
+	switch len(msg.searches) {
+	case 0:
+		m.subscriptionStatus = ""
+	case 1:
+		m.subscriptionStatus = i18n.T("status.new_matches", total, msg.searches[0].Search)
+	default:
+		m.subscriptionStatus = i18n.T("status.new_matches_more", total, len(msg.searches))
+	}
+	return m, nil
+}
+
+// subscriptionLabel marks a subscribed search in the saved searches view,
+// with its new matches
+func (m Model) subscriptionLabel(name string) string {
+	if !m.service.Subscribed(name) {
+		return ""
+	}
+	if n := m.newMatches[name]; n > 0 {
+		return fmt.Sprintf(" (subscribed, %d new)", n)
+	}
+	return " (subscribed)"
+}
+
+// toggleSubscription subscribes to the search or, when subscribed already,
+// unsubscribes, keeping the cursor on it in the saved searches view
+func (m Model) toggleSubscription(name string) (tea.Model, tea.Cmd) {
+	var err error
+	if m.service.Subscribed(name) {
+		if err = m.service.Unsubscribe(name); err == nil {
+			m.statusMsg = i18n.T("status.search_unsubscribed", name)
+		}
+	} else if err = m.service.Subscribe(name); err == nil {
+		m.statusMsg = i18n.T("status.search_subscribed", name)
+	}
+	if err != nil {
+		m.statusMsg = i18n.T("status.subscribe_failed", err)
+	}
+	m.statusTimeout = 3
+
+	cursor := m.selectForm.selected
+	m.selectForm = NewSelectForm(m.savedSearchOptions(m.savedSearches))
+	m.selectForm.selected = cursor
+	return m, tea.Batch(clearStatusCmd(), subscriptionsCheckCmd(m.service))
+}
+
+// acknowledgeMatches clears the new matches of a search once its results are
+// shown, and refreshes the badge
+func (m *Model) acknowledgeMatches(name string) tea.Cmd {
+	if m.newMatches[name] == 0 {
+		return nil
+	}
+	if err := m.service.AcknowledgeMatches(name); err != nil {
+		m.statusMsg = i18n.T("status.warning", err)
+		return nil
+	}
+	return subscriptionsCheckCmd(m.service)
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/dpshade/pocket-prompt/internal/service"
+)
+
+// TemplateGallery shows the templates one at a time: each one's
+// description, slots with the sample values they are shown with, an example
+// rendered from those values and how many prompts are built from it
+type TemplateGallery struct {
+	viewport viewport.Model
+	entries  []service.GalleryTemplate
+	index    int
+}
+
+// NewTemplateGallery creates a gallery of entries, sized for a terminal of
+// width by height
+func NewTemplateGallery(entries []service.GalleryTemplate, width, height int) *TemplateGallery {
+	vp := viewport.New(60, 20)
+	vp.Style = lipgloss.NewStyle()
+	g := &TemplateGallery{viewport: vp, entries: entries}
+	g.Resize(width, height)
+	return g
+}
+
+// Selected returns the template shown, or nil in an empty gallery
+func (g *TemplateGallery) Selected() *service.GalleryTemplate {
+	if len(g.entries) == 0 {
+		return nil
+	}
+	return &g.entries[g.index]
+}
+
+// Resize fits the gallery to a terminal of width by height, leaving room for
+// the header and help
+func (g *TemplateGallery) Resize(width, height int) {
+	g.viewport.Width = max(20, width-4)
+	g.viewport.Height = max(3, height-8)
+	g.viewport.SetContent(g.content())
+}
+
+// Update moves between templates with left and right and scrolls the one
+// shown otherwise
+func (g *TemplateGallery) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok && len(g.entries) > 0 {
+		switch msg.String() {
+		case "left", "h":
+			g.show((g.index + len(g.entries) - 1) % len(g.entries))
+			return nil
+		case "right", "l":
+			g.show((g.index + 1) % len(g.entries))
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	g.viewport, cmd = g.viewport.Update(msg)
+	return cmd
+}
+
+func (g *TemplateGallery) show(index int) {
+	g.index = index
+	g.viewport.SetContent(g.content())
+	g.viewport.GotoTop()
+}
+
+// content renders the template shown as a card
+func (g *TemplateGallery) content() string {
+	entry := g.Selected()
+	if entry == nil {
+		return ""
+	}
+	template := entry.Template
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	slotStyle := lipgloss.NewStyle().Foreground(ColorPrimary)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	width := g.viewport.Width
+
+	var lines []string
+	if template.Description != "" {
+		lines = append(lines, lipgloss.NewStyle().Width(width).Render(template.Description), "")
+	}
+	meta := fmt.Sprintf("ID: %s", template.ID)
+	if template.Version != "" {
+		meta += " • Version: " + template.Version
+	}
+	lines = append(lines, hintStyle.Render(meta+" • Used by "+entry.Usage.Summary()), "")
+
+	lines = append(lines, titleStyle.Render("Slots"))
+	if len(template.Slots) == 0 {
+		lines = append(lines, hintStyle.Render("  None; the template only wraps the prompt's content"))
+	}
+	for _, slot := range template.Slots {
+		line := "  " + slotStyle.Render(slot.Name)
+		if slot.Required {
+			line += " (required)"
+		}
+		if slot.Description != "" {
+			line += " – " + slot.Description
+		}
+		lines = append(lines, line, hintStyle.Render("    e.g. "+entry.Samples[slot.Name]))
+	}
+
+	lines = append(lines, "")
+	heading := "Example"
+	if entry.Source != "" {
+		heading += " with the content of " + entry.Source
+	}
+	lines = append(lines, titleStyle.Render(heading))
+	if entry.Error != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ColorError).Render("  "+entry.Error))
+	} else {
+		example := lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(ColorPrimary).
+			PaddingLeft(1).
+			Width(max(10, width-4)).
+			Render(strings.TrimRight(entry.Example, "\n"))
+		lines = append(lines, example)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// View renders the gallery below a header naming the template shown
+func (g *TemplateGallery) View(showExpandedHelp bool, width int) string {
+	entry := g.Selected()
+	if entry == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, CreateSubPageHeader("Template Gallery"), "", "No templates available")
+	}
+	name := entry.Template.Name
+	if name == "" {
+		name = entry.Template.ID
+	}
+	header := CreateSubPageHeader(fmt.Sprintf("Template Gallery • %s (%d/%d)", name, g.index+1, len(g.entries)))
+
+	essential := []string{"←/→ browse • ↑/↓ scroll • c copy example"}
+	additional := []string{"Esc back"}
+	help := CreateContextualHelp(essential, additional, showExpandedHelp, width)
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", g.viewport.View(), help)
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/dpshade/pocket-prompt/internal/commands"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/models"
+)
+
+// runTemplateCommand runs a template command through the unified executor,
+// so the TUI changes templates exactly as the CLI and API do
+func (m *Model) runTemplateCommand(name string, params map[string]interface{}) (*commands.CommandResult, error) {
+	result, err := m.executor.Execute(context.Background(), name, params)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		if result.Error != nil {
+			return nil, fmt.Errorf("%s", result.Error.Message)
+		}
+		return nil, fmt.Errorf("%s failed", name)
+	}
+	return result, nil
+}
+
+// saveTemplate creates the template, or updates it when editing, in which
+// case the replaced version is archived
+func (m *Model) saveTemplate(template *models.Template) error {
+	name := "create-template"
+	if m.editMode {
+		name = "update-template"
+	}
+	_, err := m.runTemplateCommand(name, map[string]interface{}{"template": template})
+	return err
+}
+
+// reloadTemplates refreshes the template list after a change
+func (m *Model) reloadTemplates() {
+	if result, err := m.runTemplateCommand("list-templates", nil); err == nil {
+		m.templates = result.Data.([]*models.Template)
+	}
+}
+
+// openTemplateManagement shows the template management menu: a blank
+// template followed by every template with how much it is used
+func (m *Model) openTemplateManagement() {
+	options := []SelectOption{
+		{
+			Label:       "Create new template",
+			Description: "Start with a blank template",
+			Value:       "new",
+		},
+	}
+	m.templateStats, _ = m.service.TemplateStats()
+	for _, template := range m.templates {
+		description := template.Description
+		if st, ok := m.templateStats[template.ID]; ok {
+			description = strings.TrimPrefix(description+" • "+st.Summary(), " • ")
+		}
+		options = append(options, SelectOption{
+			Label:       template.Name,
+			Description: description,
+			Value:       template,
+		})
+	}
+	m.selectForm = NewSelectForm(options)
+	m.viewMode = ViewTemplateManagement
+}
+
+// openTemplateGallery shows every template with its slots, an example
+// rendered from sample values and how many prompts are built from it
+func (m *Model) openTemplateGallery() tea.Cmd {
+	gallery, err := m.service.TemplateGallery()
+	if err != nil {
+		m.statusMsg = i18n.T("status.templates_failed", err)
+		m.statusTimeout = 3
+		return clearStatusCmd()
+	}
+	if len(gallery) == 0 {
+		m.statusMsg = i18n.T("status.no_templates")
+		m.statusTimeout = 2
+		return clearStatusCmd()
+	}
+	m.templateGallery = NewTemplateGallery(gallery, m.width, m.height)
+	m.viewMode = ViewTemplateGallery
+	return nil
+}
+
+// deleteSelectedTemplate deletes the selected template on the second Ctrl+D.
+// The first press asks for confirmation, naming how many prompts use it.
+func (m *Model) deleteSelectedTemplate() {
+	template := m.selectedTemplate
+	if !m.deleteConfirm {
+		m.deleteConfirm = true
+		m.statusMsg = i18n.T("status.confirm_delete_template", template.ID)
+		if st, ok := m.templateStats[template.ID]; ok && len(st.Prompts) > 0 {
+			m.statusMsg = i18n.T("status.confirm_delete_used_template", template.ID, len(st.Prompts), st.Summary())
+		}
+		m.statusTimeout = 100 // Keep showing until next action
+		return
+	}
+
+	m.deleteConfirm = false
+	if _, err := m.runTemplateCommand("delete-template", map[string]interface{}{"id": template.ID}); err != nil {
+		m.statusMsg = i18n.T("status.delete_failed", err)
+		m.statusTimeout = 3
+		return
+	}
+	m.statusMsg = i18n.T("status.template_deleted", template.ID)
+	m.statusTimeout = 2
+	m.reloadTemplates()
+	m.selectedTemplate = nil
+	m.templateForm = nil
+	m.editMode = false
+	m.openTemplateManagement()
+}
@@ -0,0 +1,161 @@
+package urlscheme
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	linuxDesktopFile = "pocket-prompt-url.desktop"
+	darwinAppName    = "Pocket Prompt Links.app"
+)
+
+// Register installs executable as the handler for pocket-prompt:// links for
+// the current user and returns a description of what was installed
+func Register(executable string) (string, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return registerLinux(executable)
+	case "darwin":
+		return registerDarwin(executable)
+	case "windows":
+		return registerWindows(executable)
+	default:
+		return "", fmt.Errorf("URL scheme registration is not supported on %s", runtime.GOOS)
+	}
+}
+
+// Unregister removes the handler installed by Register
+func Unregister() error {
+	switch runtime.GOOS {
+	case "linux":
+		path, err := linuxDesktopPath()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	case "darwin":
+		path, err := darwinAppPath()
+		if err != nil {
+			return err
+		}
+		return os.RemoveAll(path)
+	case "windows":
+		return exec.Command("reg", "delete", `HKCU\Software\Classes\`+Scheme, "/f").Run()
+	default:
+		return fmt.Errorf("URL scheme registration is not supported on %s", runtime.GOOS)
+	}
+}
+
+func linuxDesktopPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "applications", linuxDesktopFile), nil
+}
+
+// registerLinux writes a desktop entry claiming the scheme and makes it the default via xdg-mime
+func registerLinux(executable string) (string, error) {
+	path, err := linuxDesktopPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	entry := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Pocket Prompt
+Comment=Open pocket-prompt:// links
+Exec="%s" open %%u
+Terminal=true
+NoDisplay=true
+MimeType=x-scheme-handler/%s;
+`, executable, Scheme)
+	if err := os.WriteFile(path, []byte(entry), 0644); err != nil {
+		return "", fmt.Errorf("failed to write desktop entry: %w", err)
+	}
+
+	if out, err := exec.Command("xdg-mime", "default", linuxDesktopFile, "x-scheme-handler/"+Scheme).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("wrote %s but xdg-mime failed: %v %s", path, err, strings.TrimSpace(string(out)))
+	}
+	// Refreshing the desktop database is best effort; not every desktop ships it
+	exec.Command("update-desktop-database", filepath.Dir(path)).Run()
+
+	return fmt.Sprintf("Registered %s:// via %s", Scheme, path), nil
+}
+
+func darwinAppPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Applications", darwinAppName), nil
+}
+
+// registerDarwin builds a small AppleScript applet that receives the link and
+// runs pocket-prompt in Terminal; macOS only delivers URLs to app bundles
+func registerDarwin(executable string) (string, error) {
+	path, err := darwinAppPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	script := fmt.Sprintf(`on open location theURL
+	tell application "Terminal"
+		activate
+		do script quoted form of %q & " open " & quoted form of theURL
+	end tell
+end open location`, executable)
+
+	if out, err := exec.Command("osacompile", "-o", path, "-e", script).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("osacompile failed: %v %s", err, strings.TrimSpace(string(out)))
+	}
+
+	plist := filepath.Join(path, "Contents", "Info.plist")
+	urlTypes := fmt.Sprintf(`[{"CFBundleURLName":"Pocket Prompt","CFBundleURLSchemes":["%s"]}]`, Scheme)
+	if out, err := exec.Command("plutil", "-replace", "CFBundleURLTypes", "-json", urlTypes, plist).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to declare URL scheme: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	exec.Command("plutil", "-replace", "CFBundleIdentifier", "-string", "com.pocket-prompt.links", plist).Run()
+
+	lsregister := "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+	if out, err := exec.Command(lsregister, "-f", path).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to register %s with Launch Services: %v %s", path, err, strings.TrimSpace(string(out)))
+	}
+
+	return fmt.Sprintf("Registered %s:// via %s", Scheme, path), nil
+}
+
+// registerWindows writes the per-user protocol handler keys under HKCU\Software\Classes
+func registerWindows(executable string) (string, error) {
+	key := `HKCU\Software\Classes\` + Scheme
+	commands := [][]string{
+		{"reg", "add", key, "/ve", "/d", "URL:Pocket Prompt", "/f"},
+		{"reg", "add", key, "/v", "URL Protocol", "/d", "", "/f"},
+		{"reg", "add", key + `\shell\open\command`, "/ve", "/d", fmt.Sprintf(`"%s" open "%%1"`, executable), "/f"},
+	}
+	for _, args := range commands {
+		if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("%s failed: %v %s", strings.Join(args[:3], " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	return fmt.Sprintf("Registered %s:// under %s", Scheme, key), nil
+}
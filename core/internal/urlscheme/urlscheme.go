@@ -0,0 +1,63 @@
+// Package urlscheme parses pocket-prompt:// deep links and registers the
+// binary as the handler for the scheme with the operating system.
+package urlscheme
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Scheme is the custom URL scheme handled by pocket-prompt
+const Scheme = "pocket-prompt"
+
+// Link actions
+const (
+	ActionPrompt = "prompt" // Open the TUI at the prompt
+	ActionCopy   = "copy"   // Copy the rendered prompt to the clipboard
+)
+
+// Link is a parsed deep link such as pocket-prompt://prompt/my-id
+type Link struct {
+	Action string
+	ID     string
+}
+
+// Parse validates a deep link and extracts its action and prompt ID
+func Parse(raw string) (*Link, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid link %q: %w", raw, err)
+	}
+	if u.Scheme != Scheme {
+		return nil, fmt.Errorf("unsupported link %q: expected %s://", raw, Scheme)
+	}
+
+	// pocket-prompt://prompt/id puts the action in the host; pocket-prompt:prompt/id in the opaque part
+	path := u.Host + u.Path
+	if u.Opaque != "" {
+		path = u.Opaque
+	}
+	action, id, _ := strings.Cut(strings.Trim(path, "/"), "/")
+
+	id, err = url.PathUnescape(strings.Trim(id, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt ID in %q: %w", raw, err)
+	}
+
+	switch action {
+	case ActionPrompt, ActionCopy:
+	default:
+		return nil, fmt.Errorf("unknown link action %q (expected %s or %s)", action, ActionPrompt, ActionCopy)
+	}
+	if id == "" {
+		return nil, fmt.Errorf("link %q is missing a prompt ID", raw)
+	}
+
+	return &Link{Action: action, ID: id}, nil
+}
+
+// String formats the link back into its URL form
+func (l Link) String() string {
+	return fmt.Sprintf("%s://%s/%s", Scheme, l.Action, url.PathEscape(l.ID))
+}
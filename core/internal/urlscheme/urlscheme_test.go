@@ -0,0 +1,32 @@
+package urlscheme
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw    string
+		action string
+		id     string
+	}{
+		{"pocket-prompt://prompt/my-id", ActionPrompt, "my-id"},
+		{"pocket-prompt://copy/code-review/", ActionCopy, "code-review"},
+		{"pocket-prompt:prompt/my-id", ActionPrompt, "my-id"},
+		{"pocket-prompt://prompt/with%20space", ActionPrompt, "with space"},
+	}
+
+	for _, tt := range tests {
+		link, err := Parse(tt.raw)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.raw, err)
+		}
+		if link.Action != tt.action || link.ID != tt.id {
+			t.Errorf("Parse(%q) = %+v, want action %q id %q", tt.raw, link, tt.action, tt.id)
+		}
+	}
+
+	for _, raw := range []string{"https://prompt/my-id", "pocket-prompt://prompt/", "pocket-prompt://delete/my-id"} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) should fail", raw)
+		}
+	}
+}
@@ -254,6 +254,10 @@ func ValidateQueryParams(values url.Values) map[string]interface{} {
 		params["pack"] = pack
 	}
 	
+	if lang := values.Get("lang"); lang != "" {
+		params["lang"] = lang
+	}
+	
 	if packs := values.Get("packs"); packs != "" {
 		params["packs"] = strings.Split(packs, ",")
 	}
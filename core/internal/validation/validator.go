@@ -55,6 +55,7 @@ import (
 	"strings"
 
 	"github.com/dpshade/pocket-prompt/internal/errors"
+	"github.com/dpshade/pocket-prompt/internal/models"
 )
 
 // FieldValidator provides validation rules for individual fields
@@ -353,10 +354,21 @@ func (v *Validator) registerBuiltinSchemas() {
 				MaxLength: 100,
 				Pattern: regexp.MustCompile(`^[a-zA-Z0-9_-]+$`),
 			},
+			"lang": {
+				Name: "lang",
+				Type: "string",
+				MaxLength: 35,
+				Pattern: regexp.MustCompile(`^[a-zA-Z0-9_-]+$`),
+			},
 			"archived": {
 				Name: "archived",
 				Type: "bool",
 			},
+			"sort": {
+				Name: "sort",
+				Type: "string",
+				Options: []string{"frecency"},
+			},
 			"format": {
 				Name: "format",
 				Type: "string",
@@ -396,22 +408,9 @@ func (v *Validator) registerBuiltinSchemas() {
 					if !ok {
 						return fmt.Errorf("expression must be a string")
 					}
-					// Basic validation - check for balanced parentheses
-					count := 0
-					for _, char := range expr {
-						if char == '(' {
-							count++
-						} else if char == ')' {
-							count--
-							if count < 0 {
-								return fmt.Errorf("unbalanced parentheses in expression")
-							}
-						}
-					}
-					if count != 0 {
-						return fmt.Errorf("unbalanced parentheses in expression")
-					}
-					return nil
+					// Report syntax errors, with their column, before the search runs
+					_, err := models.ParseBooleanExpression(expr)
+					return err
 				},
 			},
 			"packs": {
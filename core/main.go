@@ -1,45 +1,53 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
 	"os"
-	"os/exec"
-	"strconv"
-	"strings"
-	"syscall"
+	"os/signal"
+	"path/filepath"
+	"runtime/trace"
 	"time"
 
 	"github.com/dpshade/pocket-prompt/internal/api"
+	"github.com/dpshade/pocket-prompt/internal/bot"
 	"github.com/dpshade/pocket-prompt/internal/cli"
+	"github.com/dpshade/pocket-prompt/internal/client"
+	"github.com/dpshade/pocket-prompt/internal/clipboard"
+	"github.com/dpshade/pocket-prompt/internal/config"
+	"github.com/dpshade/pocket-prompt/internal/demo"
+	"github.com/dpshade/pocket-prompt/internal/editor"
+	"github.com/dpshade/pocket-prompt/internal/i18n"
+	"github.com/dpshade/pocket-prompt/internal/platform"
+	"github.com/dpshade/pocket-prompt/internal/rpc"
 	"github.com/dpshade/pocket-prompt/internal/service"
+	"github.com/dpshade/pocket-prompt/internal/startup"
+	"github.com/dpshade/pocket-prompt/internal/tracing"
 	"github.com/dpshade/pocket-prompt/internal/ui"
+	"github.com/dpshade/pocket-prompt/internal/urlscheme"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 var version = "0.1.0"
 
-// killExistingServers finds and kills any running pocket-prompt URL server processes
+// killExistingServers finds and kills any running pocket-prompt URL server
+// processes. Where processes cannot be listed, as on Windows, it leaves them.
 func killExistingServers() error {
-	// Find processes running pkt with --url-server
-	cmd := exec.Command("pgrep", "-f", "pkt.*--url-server")
-	output, err := cmd.Output()
+	pids, err := platform.FindProcesses("pkt.*--url-server")
 	if err != nil {
-		// No processes found or pgrep failed
+		// Processes cannot be listed here
 		return nil
 	}
 
-	// Parse PIDs and kill them
-	pids := strings.Fields(string(output))
 	currentPID := os.Getpid()
-
-	for _, pidStr := range pids {
-		pid, err := strconv.Atoi(pidStr)
-		if err != nil {
-			continue
-		}
-
+	for _, pid := range pids {
 		// Don't kill ourselves
 		if pid == currentPID {
 			continue
@@ -47,11 +55,8 @@ func killExistingServers() error {
 
 		fmt.Printf("Killing existing server process (PID %d)...\n", pid)
 
-		// Send SIGTERM first for graceful shutdown
-		if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
-			// If SIGTERM fails, try SIGKILL
-			syscall.Kill(pid, syscall.SIGKILL)
-		}
+		// Asks for a graceful shutdown first, killing it if that fails
+		platform.Terminate(pid)
 	}
 
 	// Give processes time to shut down
@@ -59,63 +64,107 @@ func killExistingServers() error {
 	return nil
 }
 
+// prepareHeadless readies an unattended server, as in a container: logs go to
+// stdout and an empty library volume is set up
+func prepareHeadless(svc *service.Service) error {
+	log.SetOutput(os.Stdout)
+	if os.Geteuid() == 0 {
+		log.Printf("Warning: running as root; the official image runs as an unprivileged user")
+	}
+	// A read-only library is served as mounted, without setting anything up
+	if !svc.ReadOnly() {
+		if err := svc.InitLibrary(); err != nil {
+			return fmt.Errorf("cannot set up library %s (is the volume writable?): %w", svc.GetBaseDir(), err)
+		}
+	}
+	log.Printf("Serving library %s", svc.GetBaseDir())
+	return nil
+}
+
+// serveUntilSignal runs the server until interrupted or terminated, then lets
+// in-flight requests finish before returning
+func serveUntilSignal(apiSrv *api.APIServer) error {
+	ctx, stop := signal.NotifyContext(context.Background(), platform.ShutdownSignals...)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() { errc <- apiSrv.Start() }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return apiSrv.Stop(shutdownCtx)
+}
+
+// startServerWithTUI serves the URL server from the TUI's own service in the
+// background and tells the TUI if it stops. Its log goes to
+// .pocket-prompt/server.log rather than over the TUI. The returned function
+// shuts the server down.
+func startServerWithTUI(apiSrv *api.APIServer, svc *service.Service, p *tea.Program) (stop func()) {
+	var logFile io.WriteCloser
+	logPath := filepath.Join(svc.GetBaseDir(), ".pocket-prompt", "server.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err == nil {
+		logFile, _ = os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	}
+	if logFile != nil {
+		log.SetOutput(logFile)
+	} else {
+		log.SetOutput(io.Discard) // A read-only library, such as the demo
+	}
+
+	apiSrv.OnListen(func(addr string) { p.Send(ui.ServerListeningMsg{Addr: addr}) })
+	go func() {
+		if err := apiSrv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.Send(ui.ServerStoppedMsg{Err: err})
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := apiSrv.Stop(ctx); err != nil {
+			log.Printf("Warning: server did not stop cleanly: %v", err)
+		}
+		log.SetOutput(os.Stderr)
+		if logFile != nil {
+			logFile.Close()
+		}
+	}
+}
+
+// startProfile times startup for --profile-startup, and writes an execution
+// trace to tracePath if one is given. The returned function prints the
+// timings to stderr and finishes the trace.
+func startProfile(tracePath string) (finish func(), err error) {
+	startup.Enable()
+	var traceFile *os.File
+	if tracePath != "" {
+		if traceFile, err = os.Create(tracePath); err != nil {
+			return nil, fmt.Errorf("failed to create trace file: %w", err)
+		}
+		if err := trace.Start(traceFile); err != nil {
+			traceFile.Close()
+			return nil, fmt.Errorf("failed to start trace: %w", err)
+		}
+	}
+
+	return func() {
+		startup.Report(os.Stderr)
+		if traceFile != nil {
+			trace.Stop()
+			traceFile.Close()
+			fmt.Fprintf(os.Stderr, "Trace written to %s (view it with 'go tool trace %s')\n", tracePath, tracePath)
+		}
+	}, nil
+}
+
 func printHelp() {
-	fmt.Printf(`pocket-prompt - Terminal-based AI prompt management
-
-USAGE:
-    pocket-prompt [OPTIONS] [COMMAND]
-
-OPTIONS:
-    --help          Show this help information
-    --version       Print version information  
-    --init          Initialize a new prompt library
-    --url-server    Start HTTP API server for integrations
-    --restart       Kill any running URL server instances and restart
-    --port          Port for URL server (default: 8080)
-    --no-git-sync   Disable smart background git synchronization
-
-COMMANDS:
-    (no command)       Start interactive TUI mode
-    list, ls           List all prompts
-    search <query>     Search prompts
-    get, show <id>     Show a specific prompt
-    create, new <id>   Create a new prompt
-    edit <id>          Edit an existing prompt
-    delete, rm <id>    Delete a prompt
-    copy <id>          Copy prompt to clipboard
-    templates          List templates
-    template           Template management (create, edit, delete, show)
-    tags               List all tags
-    archive            Manage archived prompts
-    search-saved       Manage saved searches
-    boolean-search     Boolean search operations (create, edit, delete, list, run)
-    export             Export prompts and templates
-    import             Import prompts and templates
-    git                Git synchronization commands
-    help               Show CLI command help
-
-EXAMPLES:
-    pocket-prompt                                    # Start interactive mode
-    pocket-prompt --init                             # Initialize new library
-    pocket-prompt --url-server                       # Start HTTP API server
-    pocket-prompt --url-server --restart            # Kill existing servers and restart
-    pocket-prompt --url-server --port 9000          # Start server on port 9000
-    pocket-prompt --url-server --no-git-sync        # Disable git sync
-    pocket-prompt list --format table               # List prompts in table format
-    pocket-prompt search "machine learning"         # Search prompts
-    pocket-prompt create my-prompt --title "Test"   # Create new prompt
-    pocket-prompt template create my-template        # Create template
-    pocket-prompt boolean-search run "(ai OR ml)"   # Boolean search
-    pocket-prompt export all --output backup.json   # Export everything
-    pocket-prompt git setup <repo-url>              # Setup git sync
-    pocket-prompt help <command>                     # Get detailed help
-
-STORAGE:
-    Default directory: ~/.pocket-prompt
-    Override with: POCKET_PROMPT_DIR=<path>
-
-For more information, visit: https://github.com/dpshade/pocket-prompt
-`)
+	fmt.Print(i18n.T("main.help"))
 }
 
 func main() {
@@ -125,16 +174,70 @@ func main() {
 	var urlServer bool
 	var restartServer bool
 	var port int
+	var portFallback bool
 	var noGitSync bool
+	var grpcPort int
+	var listen string
+	var remoteAddr string
+	var botPlatform string
+	var headless bool
+	var demoMode bool
+	var readOnly bool
+	var withServer bool
+	var profileStartup bool
+	var profileTrace string
+	var traceCommand bool
+	var editorProtocol bool
+	var noColor bool
 
 	flag.BoolVar(&showVersion, "version", false, "Print version information")
 	flag.BoolVar(&initLib, "init", false, "Initialize a new prompt library")
 	flag.BoolVar(&showHelp, "help", false, "Show help information")
 	flag.BoolVar(&urlServer, "url-server", false, "Start HTTP API server for integrations")
 	flag.BoolVar(&restartServer, "restart", false, "Kill any running URL server instances and restart")
-	flag.IntVar(&port, "port", 8080, "Port for URL server")
+	flag.IntVar(&port, "port", config.DefaultPort, "Port for URL server")
+	flag.BoolVar(&portFallback, "port-fallback", false, "Use the next free port when the URL server's port is taken")
 	flag.BoolVar(&noGitSync, "no-git-sync", false, "Disable smart background git synchronization")
+	flag.IntVar(&grpcPort, "grpc-port", 0, "Also serve the gRPC interface on this port (0 disables)")
+	flag.StringVar(&listen, "listen", "", "Listen address for URL server: unix:/path/to/socket, host:port or tailscale")
+	flag.StringVar(&remoteAddr, "remote", os.Getenv(client.RemoteEnv), "Send CLI commands to a running server at this address")
+	flag.StringVar(&botPlatform, "bot", "", "Serve search/get/copy in chat: discord or telegram")
+	flag.BoolVar(&editorProtocol, "editor-protocol", false, "Answer editor plugins' search/get/render requests on stdin and stdout")
+	flag.BoolVar(&headless, "headless", false, "Run the URL server unattended, as in a container")
+	flag.BoolVar(&demoMode, "demo", false, "Use a read-only sample library instead of your own")
+	flag.BoolVar(&readOnly, "read-only", false, "Open the library read-only, refusing every change to it")
+	flag.BoolVar(&withServer, "with-server", false, "Run the URL server inside the TUI, sharing its library")
+	flag.BoolVar(&profileStartup, "profile-startup", false, "Print how long each phase of startup took")
+	flag.StringVar(&profileTrace, "profile-trace", "", "With --profile-startup, also write an execution trace to this file")
+	flag.BoolVar(&traceCommand, "trace", false, "Print how long each phase of a command took and the files it touched")
+	flag.BoolVar(&noColor, "no-color", false, "Print CLI output without colors")
 	flag.Parse()
+	if noColor {
+		cli.DisableColor()
+	}
+
+	// Profiling starts first so that opening the library is timed too
+	finishProfile := func() {}
+	if profileStartup || profileTrace != "" {
+		finish, err := startProfile(profileTrace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		finishProfile = finish
+		defer finishProfile()
+	}
+	// --trace reports once the command is done, with paths relative to the
+	// library when one was opened
+	var libraryDir string
+	finishTrace := func() { tracing.Report(os.Stderr, libraryDir) }
+	if traceCommand {
+		tracing.Enable()
+		defer finishTrace()
+	}
+
+	// Messages follow LANG until the library's settings are loaded
+	i18n.SetLocale(i18n.Detect(""))
 
 	if showHelp {
 		printHelp()
@@ -152,12 +255,100 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize service with file storage
-	svc, err := service.NewService()
+	if withServer && (urlServer || restartServer || headless) {
+		fmt.Fprintf(os.Stderr, "Error: --with-server starts the TUI; use --url-server alone for a server without it\n")
+		os.Exit(1)
+	}
+
+	if demoMode && (initLib || restartServer) {
+		fmt.Fprintf(os.Stderr, "Error: --demo cannot be used with --init or --restart\n")
+		os.Exit(1)
+	}
+
+	if readOnly && initLib {
+		fmt.Fprintf(os.Stderr, "Error: --read-only cannot be used with --init\n")
+		os.Exit(1)
+	}
+
+	// Remote mode talks to a running server and never opens the local library
+	if remoteAddr != "" && !demoMode && !urlServer && !restartServer && len(flag.Args()) > 0 {
+		if readOnly {
+			fmt.Fprintf(os.Stderr, "Error: --read-only applies to a local library; start the server with --read-only instead\n")
+			os.Exit(1)
+		}
+		remoteClient, err := client.New(remoteAddr, os.Getenv(client.APIKeyEnv))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cli.NewRemoteCLI(remoteClient).ExecuteCommand(flag.Args()); err != nil {
+			cli.PrintError(err)
+			finishTrace()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Initialize service with file storage. Demo mode opens a read-only copy
+	// of the embedded sample library instead and never touches the user's;
+	// read-only mode opens the user's library but refuses every change.
+	var svc *service.Service
+	cleanup := func() {}
+	var err error
+	endOpen := tracing.Begin("open library")
+	if demoMode {
+		svc, cleanup, err = demo.Open()
+	} else if readOnly {
+		svc, err = service.NewReadOnlyService()
+	} else {
+		svc, err = service.NewService()
+	}
+	endOpen()
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+	defer cleanup()
+	// os.Exit skips deferred calls, so exits from here on remove the demo
+	// library first
+	exit := func(code int) {
+		cleanup()
+		os.Exit(code)
+	}
+	libraryDir = svc.GetBaseDir()
+	// Copies from both the CLI and the TUI use the configured clipboard command
+	clipboard.SetCommand(svc.Settings().CLI.Clipboard)
+	clipboard.SetPasteCommand(svc.Settings().Clipboard.PasteCommand)
+	clipboard.SetBackend(svc.Settings().Platform.ClipboardBackend())
+
+	// Flags take precedence over settings, which the config file and
+	// POCKET_PROMPT_* environment variables provide
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	settings := svc.Settings()
+	if !explicit["port"] {
+		port = settings.Server.ListenPort()
+	}
+	if !explicit["listen"] {
+		listen = settings.Server.Listen
+	}
+	if !explicit["grpc-port"] {
+		grpcPort = settings.Server.GRPCPort
+	}
+	if !explicit["port-fallback"] {
+		portFallback = settings.Server.PortFallback
+	}
+	i18n.SetLocale(i18n.Detect(settings.UI.Locale))
+	if !explicit["no-git-sync"] {
+		noGitSync = settings.Git.NoSync
+	}
+	if demoMode {
+		noGitSync = true
+		fmt.Fprintf(os.Stderr, "Demo mode: sample library, read-only\n")
+	} else if readOnly {
+		noGitSync = true
+		fmt.Fprintf(os.Stderr, "Read-only mode: changes to %s are refused\n", svc.GetBaseDir())
+	}
 
 	if initLib {
 		if err := svc.InitLibrary(); err != nil {
@@ -168,7 +359,36 @@ func main() {
 		return
 	}
 
-	if urlServer || restartServer {
+	if editorProtocol {
+		// Responses own stdout; anything else printed goes to stderr so it
+		// cannot corrupt the protocol
+		out := os.Stdout
+		os.Stdout = os.Stderr
+		if err := editor.Serve(svc, os.Stdin, out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		return
+	}
+
+	if botPlatform != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), platform.ShutdownSignals...)
+		defer stop()
+		if err := bot.Run(ctx, botPlatform, svc); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		return
+	}
+
+	if urlServer || restartServer || headless {
+		if headless {
+			if err := prepareHeadless(svc); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(1)
+			}
+		}
+
 		// Handle restart flag - kill existing servers first
 		if restartServer {
 			fmt.Printf("Restarting URL server...\n")
@@ -178,7 +398,12 @@ func main() {
 		}
 
 		fmt.Printf("Starting HTTP API server for integrations...\n")
+		svc.SetInterface("api")
 		apiSrv := api.NewAPIServer(svc, port)
+		if listen != "" {
+			apiSrv.SetListen(listen)
+		}
+		apiSrv.SetPortFallback(portFallback)
 
 		// Configure git sync - simplified to just enable/disable
 		if noGitSync {
@@ -189,35 +414,122 @@ func main() {
 			fmt.Printf("Git sync enabled with smart background polling\n")
 		}
 
-		if err := apiSrv.Start(); err != nil {
+		if grpcPort > 0 {
+			rpcSrv, err := rpc.NewServer(svc)
+			if err != nil {
+				fmt.Printf("Error creating gRPC server: %v\n", err)
+				exit(1)
+			}
+			lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
+			if err != nil {
+				fmt.Printf("Error starting gRPC server: %v\n", err)
+				exit(1)
+			}
+			fmt.Printf("gRPC server listening on :%d\n", grpcPort)
+			go func() {
+				if err := rpcSrv.Serve(lis); err != nil {
+					fmt.Printf("gRPC server stopped: %v\n", err)
+				}
+			}()
+		}
+
+		// Demo servers stop on a signal too, so the sample library is cleaned up
+		serve := apiSrv.Start
+		if headless || demoMode {
+			serve = func() error { return serveUntilSignal(apiSrv) }
+		}
+		if err := serve(); err != nil {
 			fmt.Printf("Error starting API server: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		return
 	}
 
+	// A demo stopped by a signal removes its library too. The TUI reads
+	// Ctrl+C as a key, so this stops CLI commands such as watch-clipboard.
+	if demoMode {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, platform.ShutdownSignals...)
+		go func() {
+			<-signals
+			exit(1)
+		}()
+	}
+
 	// Check if we have command line arguments for CLI mode
 	args := flag.Args()
+
+	// Deep links: prompt links open the TUI, everything else is a CLI command
+	var openPromptID string
+	if len(args) > 0 && args[0] == "open" {
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: open requires a %s:// link\n", urlscheme.Scheme)
+			exit(1)
+		}
+		link, err := urlscheme.Parse(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		switch link.Action {
+		case urlscheme.ActionPrompt:
+			openPromptID = link.ID
+			args = nil
+		case urlscheme.ActionCopy:
+			args = []string{"copy", link.ID}
+		}
+	}
+
 	if len(args) > 0 {
 		// CLI mode - execute command and exit
+		svc.SetInterface("cli")
 		cliHandler := cli.NewCLI(svc)
 		if err := cliHandler.ExecuteCommand(args); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			cli.PrintError(err)
+			finishProfile()
+			finishTrace()
+			exit(cli.ExitCode(err))
 		}
 		return
 	}
 
 	// No arguments provided - start TUI mode
 	// Initialize TUI
+	endTUIInit := startup.Begin("TUI init")
+	svc.SetInterface("tui")
 	model, err := ui.NewModel(svc)
+	endTUIInit()
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+	if openPromptID != "" {
+		if err := model.OpenPrompt(openPromptID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(1)
+		}
+	}
 
-	// Start TUI program
+	// Start TUI program, with the URL server on the same service if asked
+	var apiSrv *api.APIServer
+	if withServer {
+		apiSrv = api.NewAPIServer(svc, port)
+		if listen != "" {
+			apiSrv.SetListen(listen)
+		}
+		apiSrv.SetPortFallback(portFallback)
+		apiSrv.SetGitSync(!noGitSync)
+		model.SetServer(apiSrv.Address())
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen())
+	if apiSrv != nil {
+		stopServer := startServerWithTUI(apiSrv, svc, p)
+		defer stopServer()
+	} else if svc.Settings().Git.TUISyncInterval() > 0 {
+		// Warnings from scheduled pulls would be drawn over the TUI
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stderr)
+	}
 	if _, err := p.Run(); err != nil {
 		fmt.Println(err)
 		return
@@ -0,0 +1,105 @@
+;;; pocket-prompt.el --- Insert prompts from Pocket Prompt at point  -*- lexical-binding: t; -*-
+
+;; Package-Requires: ((emacs "27.1"))
+
+;;; Commentary:
+
+;; M-x pocket-prompt-insert searches your Pocket Prompt library, asks for the
+;; chosen prompt's variables and inserts the rendered text at point.  It
+;; talks to one long-running `pkt --editor-protocol' process started on
+;; first use.
+
+;;; Code:
+
+(require 'json)
+(require 'subr-x)
+
+(defgroup pocket-prompt nil
+  "Insert prompts from Pocket Prompt."
+  :group 'tools)
+
+(defcustom pocket-prompt-command '("pkt" "--editor-protocol")
+  "Command that serves the Pocket Prompt editor protocol."
+  :type '(repeat string))
+
+(defcustom pocket-prompt-limit 50
+  "Number of search results to choose from."
+  :type 'integer)
+
+(defvar pocket-prompt--process nil)
+(defvar pocket-prompt--next-id 0)
+(defvar pocket-prompt--partial "")
+(defvar pocket-prompt--responses (make-hash-table))
+
+(defun pocket-prompt--filter (_process output)
+  "Collect complete response lines from OUTPUT by request id."
+  (let ((lines (split-string (concat pocket-prompt--partial output) "\n")))
+    (setq pocket-prompt--partial (car (last lines)))
+    (dolist (line (butlast lines))
+      (unless (string-empty-p line)
+        (let ((response (json-parse-string line :object-type 'alist :array-type 'list :null-object nil)))
+          (puthash (alist-get 'id response) response pocket-prompt--responses))))))
+
+(defun pocket-prompt--process ()
+  "Return the running pkt process, starting it if needed."
+  (unless (process-live-p pocket-prompt--process)
+    (setq pocket-prompt--partial "")
+    (setq pocket-prompt--process
+          (make-process :name "pocket-prompt"
+                        :command pocket-prompt-command
+                        :connection-type 'pipe
+                        :noquery t
+                        :stderr (get-buffer-create " *pocket-prompt stderr*")
+                        :filter #'pocket-prompt--filter)))
+  pocket-prompt--process)
+
+(defun pocket-prompt--request (method params)
+  "Send METHOD with PARAMS to pkt and wait for the result."
+  (let* ((process (pocket-prompt--process))
+         (id (setq pocket-prompt--next-id (1+ pocket-prompt--next-id))))
+    (process-send-string process (concat (json-encode `((id . ,id) (method . ,method) (params . ,params))) "\n"))
+    (while (and (not (gethash id pocket-prompt--responses)) (process-live-p process))
+      (accept-process-output process 1))
+    (let ((response (gethash id pocket-prompt--responses)))
+      (remhash id pocket-prompt--responses)
+      (cond ((null response) (error "pocket-prompt: pkt exited"))
+            ((alist-get 'error response) (error "pocket-prompt: %s" (alist-get 'error response)))
+            (t (alist-get 'result response))))))
+
+(defun pocket-prompt--ask (variable)
+  "Read a value for VARIABLE, without echo when it is sensitive."
+  (let ((label (concat (alist-get 'name variable)
+                       (if-let ((description (alist-get 'description variable)))
+                           (format " (%s)" description)
+                         "")
+                       ": ")))
+    (if (alist-get 'sensitive variable)
+        (read-passwd label)
+      (read-string label nil nil (alist-get 'default variable)))))
+
+;;;###autoload
+(defun pocket-prompt-insert (query)
+  "Search prompts matching QUERY and insert the chosen one at point."
+  (interactive "sSearch prompts: ")
+  (let* ((prompts (pocket-prompt--request "search" `((query . ,query) (limit . ,pocket-prompt-limit))))
+         (choices (mapcar (lambda (p)
+                            (cons (format "%s [%s]" (alist-get 'title p) (alist-get 'id p))
+                                  (alist-get 'id p)))
+                          prompts)))
+    (unless choices
+      (user-error "No prompts match %s" query))
+    (let* ((id (cdr (assoc (completing-read "Prompt: " choices nil t) choices)))
+           (prompt (pocket-prompt--request "get" `((id . ,id))))
+           (values (delq nil (mapcar (lambda (variable)
+                                       (let ((value (pocket-prompt--ask variable)))
+                                         (unless (string-empty-p value)
+                                           (cons (intern (alist-get 'name variable)) value))))
+                                     (alist-get 'variables prompt)))))
+      (insert (alist-get 'text (pocket-prompt--request
+                                "render" `((id . ,id)
+                                           (variables . ,values)
+                                           (dir . ,(expand-file-name default-directory)))))))))
+
+(provide 'pocket-prompt)
+
+;;; pocket-prompt.el ends here
@@ -0,0 +1,144 @@
+-- Pocket Prompt for Neovim: pick a prompt, fill in its variables and insert
+-- the rendered text at the cursor. Talks to one long-running
+-- `pkt --editor-protocol` process started on first use.
+--
+--   require("pocket-prompt").setup()      -- adds :PocketPrompt [query]
+
+local M = {}
+
+local config = {
+  cmd = { "pkt", "--editor-protocol" },
+  limit = 50,
+}
+
+local job = nil
+local next_id = 0
+local pending = {}
+local partial = ""
+
+local function on_stdout(_, data)
+  -- data is a list of lines; the first continues the last partial line
+  data[1] = partial .. data[1]
+  partial = table.remove(data)
+  for _, line in ipairs(data) do
+    if line ~= "" then
+      local ok, resp = pcall(vim.json.decode, line)
+      if ok and pending[resp.id] then
+        local callback = pending[resp.id]
+        pending[resp.id] = nil
+        callback(resp.error, resp.result)
+      end
+    end
+  end
+end
+
+local function start()
+  if job then
+    return
+  end
+  job = vim.fn.jobstart(config.cmd, {
+    on_stdout = on_stdout,
+    on_exit = function()
+      job, partial = nil, ""
+      for id, callback in pairs(pending) do
+        pending[id] = nil
+        callback("pkt exited", nil)
+      end
+    end,
+  })
+  if job <= 0 then
+    job = nil
+    error("pocket-prompt: could not start " .. config.cmd[1])
+  end
+end
+
+local function request(method, params, callback)
+  start()
+  next_id = next_id + 1
+  pending[next_id] = vim.schedule_wrap(callback)
+  vim.fn.chansend(job, vim.json.encode({ id = next_id, method = method, params = params }) .. "\n")
+end
+
+local function report(err)
+  vim.notify("pocket-prompt: " .. err, vim.log.levels.ERROR)
+end
+
+-- ask prompts for one variable, without echo for sensitive ones
+local function ask(variable, callback)
+  local label = variable.name
+  if variable.description then
+    label = label .. " (" .. variable.description .. ")"
+  end
+  label = label .. ": "
+  if variable.sensitive then
+    callback(vim.fn.inputsecret(label))
+  else
+    vim.ui.input({ prompt = label, default = variable.default }, callback)
+  end
+end
+
+-- fill asks for each variable in turn, then calls done with the values
+local function fill(variables, values, i, done)
+  local variable = variables[i]
+  if not variable then
+    return done(values)
+  end
+  ask(variable, function(value)
+    if value == nil then
+      return -- Cancelled
+    end
+    if value ~= "" then
+      values[variable.name] = value
+    end
+    fill(variables, values, i + 1, done)
+  end)
+end
+
+-- insert renders the prompt with this ID and puts it after the cursor
+function M.insert(id)
+  local dir = vim.fn.expand("%:p:h")
+  request("get", { id = id }, function(err, prompt)
+    if err then
+      return report(err)
+    end
+    fill(prompt.variables or {}, vim.empty_dict(), 1, function(values)
+      request("render", { id = id, variables = values, dir = dir }, function(err, result)
+        if err then
+          return report(err)
+        end
+        vim.api.nvim_put(vim.split(result.text, "\n", { plain = true }), "c", true, true)
+      end)
+    end)
+  end)
+end
+
+-- pick searches the library and inserts the chosen prompt
+function M.pick(query)
+  request("search", { query = query or "", limit = config.limit }, function(err, prompts)
+    if err then
+      return report(err)
+    end
+    if #prompts == 0 then
+      return vim.notify("pocket-prompt: no prompts match")
+    end
+    vim.ui.select(prompts, {
+      prompt = "Insert prompt",
+      format_item = function(p)
+        return p.title .. "  [" .. p.id .. "]"
+      end,
+    }, function(choice)
+      if choice then
+        M.insert(choice.id)
+      end
+    end)
+  end)
+end
+
+function M.setup(opts)
+  config = vim.tbl_extend("force", config, opts or {})
+  vim.api.nvim_create_user_command("PocketPrompt", function(args)
+    M.pick(args.args)
+  end, { nargs = "?", desc = "Insert a prompt from Pocket Prompt" })
+end
+
+return M
@@ -0,0 +1,92 @@
+" Pocket Prompt for Vim 8.2+: pick a prompt, fill in its variables and
+" insert the rendered text at the cursor. Talks to one long-running
+" `pkt --editor-protocol` job started on first use.
+"
+"   :PocketPrompt [query]
+"   let g:pocket_prompt_cmd = ['pkt', '--editor-protocol']
+
+if exists('g:loaded_pocket_prompt') || !has('job') || !has('channel')
+  finish
+endif
+let g:loaded_pocket_prompt = 1
+
+let g:pocket_prompt_cmd = get(g:, 'pocket_prompt_cmd', ['pkt', '--editor-protocol'])
+let g:pocket_prompt_limit = get(g:, 'pocket_prompt_limit', 30)
+
+let s:job = v:null
+let s:id = 0
+
+" s:Request sends one request and waits for its response line
+function! s:Request(method, params) abort
+  if type(s:job) != v:t_job || job_status(s:job) !=# 'run'
+    let s:job = job_start(g:pocket_prompt_cmd, {'mode': 'nl', 'err_io': 'null'})
+    if job_status(s:job) !=# 'run'
+      throw 'pocket-prompt: could not start ' . g:pocket_prompt_cmd[0]
+    endif
+  endif
+  let s:id += 1
+  let l:request = json_encode({'id': s:id, 'method': a:method, 'params': a:params})
+  let l:line = ch_evalraw(job_getchannel(s:job), l:request . "\n", {'timeout': 10000})
+  if l:line ==# ''
+    throw 'pocket-prompt: no response from pkt'
+  endif
+  let l:response = json_decode(l:line)
+  if has_key(l:response, 'error')
+    throw 'pocket-prompt: ' . l:response.error
+  endif
+  return get(l:response, 'result', v:null)
+endfunction
+
+" s:Ask reads one variable, without echo for sensitive ones
+function! s:Ask(variable) abort
+  let l:label = a:variable.name
+  if has_key(a:variable, 'description')
+    let l:label .= ' (' . a:variable.description . ')'
+  endif
+  let l:label .= ': '
+  if get(a:variable, 'sensitive', v:false)
+    return inputsecret(l:label)
+  endif
+  return input(l:label, get(a:variable, 'default', ''))
+endfunction
+
+" s:Put inserts text after the cursor, leaving the unnamed register alone
+function! s:Put(text) abort
+  let l:saved = getreginfo('"')
+  call setreg('"', split(a:text, "\n", 1), 'c')
+  normal! ""p
+  call setreg('"', l:saved)
+endfunction
+
+function! s:Pick(query) abort
+  try
+    let l:prompts = s:Request('search', {'query': a:query, 'limit': g:pocket_prompt_limit})
+    if empty(l:prompts)
+      echo 'pocket-prompt: no prompts match'
+      return
+    endif
+    let l:choices = ['Insert prompt:']
+    for l:i in range(len(l:prompts))
+      call add(l:choices, printf('%d. %s [%s]', l:i + 1, l:prompts[l:i].title, l:prompts[l:i].id))
+    endfor
+    let l:n = inputlist(l:choices)
+    if l:n < 1 || l:n > len(l:prompts)
+      return
+    endif
+
+    let l:id = l:prompts[l:n - 1].id
+    let l:prompt = s:Request('get', {'id': l:id})
+    let l:values = {}
+    for l:variable in get(l:prompt, 'variables', [])
+      let l:value = s:Ask(l:variable)
+      if l:value !=# ''
+        let l:values[l:variable.name] = l:value
+      endif
+    endfor
+    call s:Put(s:Request('render', {'id': l:id, 'variables': l:values, 'dir': expand('%:p:h')}).text)
+  catch /^pocket-prompt:/
+    echohl ErrorMsg | echomsg v:exception | echohl None
+  endtry
+endfunction
+
+command! -nargs=? PocketPrompt call s:Pick(<q-args>)